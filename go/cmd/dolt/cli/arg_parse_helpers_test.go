@@ -86,3 +86,38 @@ func TestParseAuthor(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCommitMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected map[string]string
+		expErr   bool
+	}{
+		{"no metadata", []string{"-m", "msg"}, nil, false},
+		{"one pair", []string{"--metadata", "ticket=ABC-1"}, map[string]string{"ticket": "ABC-1"}, false},
+		{"many pairs", []string{"--metadata", "ticket=ABC-1", "run.id=42", "-m", "msg"}, map[string]string{"ticket": "ABC-1", "run.id": "42"}, false},
+		{"empty value", []string{"--metadata", "reviewed="}, map[string]string{"reviewed": ""}, false},
+		{"value with equals", []string{"--metadata", "query=a=b"}, map[string]string{"query": "a=b"}, false},
+		{"value with commas", []string{"--metadata", "tickets=ABC-1,ABC-2"}, map[string]string{"tickets": "ABC-1,ABC-2"}, false},
+		{"missing equals", []string{"--metadata", "ticket"}, nil, true},
+		{"invalid key", []string{"--metadata", "ticket id=ABC-1"}, nil, true},
+		{"empty key", []string{"--metadata", "=ABC-1"}, nil, true},
+		{"duplicate key", []string{"--metadata", "ticket=ABC-1", "ticket=ABC-2"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			apr, err := CreateCommitArgParser().Parse(test.args)
+			require.NoError(t, err)
+			metadata, err := ParseCommitMetadata(apr)
+
+			if test.expErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, metadata)
+			}
+		})
+	}
+}
@@ -46,6 +46,39 @@ func ParseAuthor(authorStr string) (string, string, error) {
 	return name, email, nil
 }
 
+var commitMetadataKeyRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ParseCommitMetadata parses the key=value pairs given to the --metadata option of the commit method. The values of
+// list options are split on commas, so a pair without an '=' is the continuation of the value before it.
+func ParseCommitMetadata(apr *argparser.ArgParseResults) (map[string]string, error) {
+	pairs, ok := apr.GetValueList(MetadataParam)
+	if !ok {
+		return nil, nil
+	}
+
+	metadata := make(map[string]string)
+	var lastKey string
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			if lastKey == "" {
+				return nil, fmt.Errorf("error: invalid commit metadata '%s', expected key=value", pair)
+			}
+			metadata[lastKey] += "," + pair
+			continue
+		}
+		if !commitMetadataKeyRegex.MatchString(k) {
+			return nil, fmt.Errorf("error: invalid commit metadata key '%s', keys may only contain letters, digits, '_', '.' and '-'", k)
+		}
+		if _, exists := metadata[k]; exists {
+			return nil, fmt.Errorf("error: commit metadata key '%s' given more than once", k)
+		}
+		metadata[k] = v
+		lastKey = k
+	}
+	return metadata, nil
+}
+
 const (
 	SyncBackupId        = "sync"
 	SyncBackupUrlId     = "sync-url"
@@ -64,11 +97,14 @@ func CreateCommitArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(AllowEmptyFlag, "", "Allow recording a commit that has the exact same data as its sole parent. This is usually a mistake, so it is disabled by default. This option bypasses that safety. Cannot be used with --skip-empty.")
 	ap.SupportsFlag(SkipEmptyFlag, "", "Only create a commit if there are staged changes. If no changes are staged, the call to commit is a no-op. Cannot be used with --allow-empty.")
 	ap.SupportsString(DateParam, "", "date", "Specify the date used in the commit. If not specified the current system time is used.")
-	ap.SupportsFlag(ForceFlag, "f", "Ignores any foreign key warnings and proceeds with the commit.")
+	ap.SupportsFlag(ForceFlag, "f", "Ignores any foreign key warnings and violated assertions and proceeds with the commit.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
 	ap.SupportsFlag(AllFlag, "a", "Adds all existing, changed tables (but not new tables) in the working set to the staged set.")
 	ap.SupportsFlag(UpperCaseAllFlag, "A", "Adds all tables and databases (including new tables) in the working set to the staged set.")
 	ap.SupportsFlag(AmendFlag, "", "Amend previous commit")
+	ap.SupportsFlag(GpgSignFlag, "S", "Sign the commit with the key in the {{.EmphasisLeft}}user.signingkey{{.EmphasisRight}} config, using GPG or, if {{.EmphasisLeft}}gpg.format{{.EmphasisRight}} is {{.EmphasisLeft}}ssh{{.EmphasisRight}}, ssh-keygen.")
+	ap.SupportsFlag(NoGpgSignFlag, "", "Don't sign the commit, overriding the {{.EmphasisLeft}}commit.gpgsign{{.EmphasisRight}} config.")
+	ap.SupportsStringList(MetadataParam, "", "key=value", "Attach the given {{.LessThan}}key=value{{.GreaterThan}} pairs to the commit as metadata, which is shown in the {{.EmphasisLeft}}metadata{{.EmphasisRight}} column of {{.EmphasisLeft}}dolt_commits{{.EmphasisRight}}.")
 	return ap
 }
 
@@ -96,6 +132,14 @@ func CreateMergeArgParser() *argparser.ArgParser {
 	return ap
 }
 
+func CreateMergeQueueArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("merge_queue", 2)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"subcommand", "Either {{.EmphasisLeft}}add{{.EmphasisRight}}, followed by the branch to merge, or {{.EmphasisLeft}}cancel{{.EmphasisRight}}, followed by the id of a queued merge."})
+	ap.SupportsString(IntoParam, "", "branch", "The branch to merge into. Defaults to the current branch.")
+	ap.SupportsFlag(RunTestsFlag, "", "Only commit the merge if the tests in {{.EmphasisLeft}}dolt_tests{{.EmphasisRight}} pass on its result.")
+	return ap
+}
+
 func CreateRebaseArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("rebase", 1)
 	ap.TooManyArgsErrorFunc = func(receivedArgs []string) error {
@@ -114,6 +158,7 @@ func CreatePushArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(SetUpstreamFlag, "u", "For every branch that is up to date or successfully pushed, add upstream (tracking) reference, used by argument-less {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} and other commands.")
 	ap.SupportsFlag(ForceFlag, "f", "Update the remote with local history, overwriting any conflicting history in the remote.")
 	ap.SupportsFlag(AllFlag, "", "Push all branches.")
+	ap.SupportsFlag(TagsFlag, "", "Push all tags, in addition to any refspecs given.")
 	ap.SupportsFlag(SilentFlag, "", "Suppress progress information.")
 	return ap
 }
@@ -186,6 +231,8 @@ func CreateFetchArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("fetch")
 	ap.SupportsString(UserFlag, "", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
 	ap.SupportsFlag(PruneFlag, "p", "After fetching, remove any remote-tracking references that don't exist on the remote.")
+	ap.SupportsFlag(TagsFlag, "t", "Fetch all tags from the remote, along with the commits they point to.")
+	ap.SupportsFlag(NotesFlag, "", "Fetch all notes refs from the remote. Local notes refs are fast-forwarded to them, or created if they don't exist.")
 	ap.SupportsFlag(SilentFlag, "", "Suppress progress information.")
 	return ap
 }
@@ -209,6 +256,8 @@ func CreatePullArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(CommitFlag, "", "Perform the merge and commit the result. This is the default option, but can be overridden with the --no-commit flag. Note that this option does not affect fast-forward merges, which don't create a new merge commit, and if any merge conflicts or constraint violations are detected, no commit will be attempted.")
 	ap.SupportsFlag(NoCommitFlag, "", "Perform the merge and stop just before creating a merge commit. Note this will not prevent a fast-forward merge; use the --no-ff arg together with the --no-commit arg to prevent both fast-forwards and merge commits.")
 	ap.SupportsFlag(NoEditFlag, "", "Use an auto-generated commit message when creating a merge commit. The default for interactive CLI sessions is to open an editor.")
+	ap.SupportsFlag(RebaseFlag, "r", "Rebase the commits on the current branch which aren't on the remote branch on top of it, instead of merging it. This is the default when the {{.EmphasisLeft}}pull.rebase{{.EmphasisRight}} config is {{.EmphasisLeft}}true{{.EmphasisRight}}.")
+	ap.SupportsFlag(NoRebaseFlag, "", "Merge the remote branch, overriding the {{.EmphasisLeft}}pull.rebase{{.EmphasisRight}} config.")
 	ap.SupportsString(UserFlag, "", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
 	ap.SupportsFlag(SilentFlag, "", "Suppress progress information.")
 	return ap
@@ -221,6 +270,10 @@ func createTracklessBranchArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(MoveFlag, "m", "Move/rename a branch")
 	ap.SupportsFlag(DeleteFlag, "d", "Delete a branch. The branch must be fully merged in its upstream branch.")
 	ap.SupportsFlag(DeleteForceFlag, "", "Shortcut for {{.EmphasisLeft}}--delete --force{{.EmphasisRight}}.")
+	ap.SupportsOptionalString(MergedParam, "", "commit", "Only branches merged into {{.LessThan}}commit{{.GreaterThan}}, or {{.EmphasisLeft}}HEAD{{.EmphasisRight}} if it's not given. Lists them, or with {{.EmphasisLeft}}-d{{.EmphasisRight}} deletes them.")
+	ap.SupportsOptionalString(NoMergedParam, "", "commit", "Only branches not merged into {{.LessThan}}commit{{.GreaterThan}}, or {{.EmphasisLeft}}HEAD{{.EmphasisRight}} if it's not given. Lists them, or with {{.EmphasisLeft}}-D{{.EmphasisRight}} deletes them.")
+	ap.SupportsFlag(DryRunFlag, "", "With {{.EmphasisLeft}}--merged{{.EmphasisRight}} or {{.EmphasisLeft}}--no-merged{{.EmphasisRight}}, report the branches which would be deleted without deleting them.")
+	ap.SupportsString(SetUpstreamToParam, "u", "upstream", "Set the upstream of {{.LessThan}}branchname{{.GreaterThan}}, or of the current branch if it's not given, to the remote tracking branch {{.LessThan}}upstream{{.GreaterThan}}.")
 
 	return ap
 }
@@ -246,13 +299,34 @@ func CreateTagArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(VerboseFlag, "v", "list tags along with their metadata.")
 	ap.SupportsFlag(DeleteFlag, "d", "Delete a tag.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsString(DateParam, "", "date", "Specify the date used in the tag. If not specified the current system time is used.")
+	return ap
+}
+
+func CreateNotesArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("notes")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"subcommand", "One of {{.EmphasisLeft}}list{{.EmphasisRight}}, {{.EmphasisLeft}}show{{.EmphasisRight}}, {{.EmphasisLeft}}add{{.EmphasisRight}}, {{.EmphasisLeft}}append{{.EmphasisRight}} or {{.EmphasisLeft}}remove{{.EmphasisRight}}."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "The commit to show, add, append to or remove the note of. Defaults to HEAD."})
+	ap.SupportsString(NotesRefParam, "", "ref", "Use the notes ref {{.LessThan}}ref{{.GreaterThan}}, e.g. {{.EmphasisLeft}}reviews{{.EmphasisRight}} or {{.EmphasisLeft}}refs/notes/reviews{{.EmphasisRight}}. Defaults to {{.EmphasisLeft}}refs/notes/commits{{.EmphasisRight}}.")
+	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the note.")
+	ap.SupportsFlag(ForceFlag, "f", "When adding a note to a commit which already has one, replace it.")
+	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author of the change to the notes using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	return ap
+}
+
+func CreateChangeCursorArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("dolt_change_cursor")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"subcommand", "One of {{.EmphasisLeft}}create{{.EmphasisRight}}, {{.EmphasisLeft}}advance{{.EmphasisRight}} or {{.EmphasisLeft}}delete{{.EmphasisRight}}."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"name", "The name of the change cursor."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "The commit to create the cursor at or advance it to. Defaults to the head of the cursor's branch."})
+	ap.SupportsString(BranchParam, "", "branch", "The branch whose changes a new cursor reads. Defaults to the current branch.")
 	return ap
 }
 
 func CreateBackupArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("backup")
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"region", "cloud provider region associated with this backup."})
-	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"creds-type", "credential type.  Valid options are role, env, and file.  See the help section for additional details."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"creds-type", "credential type.  Valid options are role, env, file, and helper.  See the help section for additional details."})
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"profile", "AWS profile to use."})
 	ap.SupportsFlag(VerboseFlag, "v", "When printing the list of backups adds additional details.")
 	ap.SupportsFlag(ForceFlag, "f", "When restoring a backup, overwrite the contents of the existing database with the same name.")
@@ -281,6 +355,8 @@ func CreateLogArgParser(isTableFunction bool) *argparser.ArgParser {
 	ap.SupportsStringList(NotFlag, "", "revision", "Excludes commits from revision.")
 	if isTableFunction {
 		ap.SupportsStringList(TablesFlag, "t", "table", "Restricts the log to commits that modified the specified tables.")
+		ap.SupportsString(FromKeyFlag, "", "key", "Restricts the log to commits that modified rows with a primary key greater than or equal to {{.LessThan}}key{{.GreaterThan}}. Requires a single table in --tables. Keys of more than one column are given as a JSON array, like [\"a\", 1], of a prefix of the primary key.")
+		ap.SupportsString(ToKeyFlag, "", "key", "Restricts the log to commits that modified rows with a primary key less than or equal to {{.LessThan}}key{{.GreaterThan}}. Requires a single table in --tables. Keys of more than one column are given as a JSON array, like [\"a\", 1], of a prefix of the primary key.")
 	} else {
 		ap.SupportsFlag(OneLineFlag, "", "Shows logs in a compact format.")
 		ap.SupportsFlag(StatFlag, "", "Shows the diffstat for each commit.")
@@ -292,6 +368,7 @@ func CreateLogArgParser(isTableFunction bool) *argparser.ArgParser {
 func CreateGCArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("gc", 0)
 	ap.SupportsFlag(ShallowFlag, "s", "perform a fast, but incomplete garbage collection pass")
+	ap.SupportsInt(PruneOlderThanFlag, "", "days", "keep commits made in the last {{.LessThan}}days{{.GreaterThan}} which were the head of a branch, even if they are no longer referenced. 0 disables retention")
 	return ap
 }
 
@@ -405,6 +482,9 @@ func VerifyCommitArgs(apr *argparser.ArgParseResults) error {
 	if apr.Contains(AllowEmptyFlag) && apr.Contains(SkipEmptyFlag) {
 		return errors.New("error: cannot use both --allow-empty and --skip-empty")
 	}
+	if apr.Contains(GpgSignFlag) && apr.Contains(NoGpgSignFlag) {
+		return errors.New("error: cannot use both --gpg-sign and --no-gpg-sign")
+	}
 
 	return nil
 }
@@ -21,6 +21,7 @@ const (
 	AllFlag              = "all"
 	AllowEmptyFlag       = "allow-empty"
 	AmendFlag            = "amend"
+	AsyncFlag            = "async"
 	AuthorParam          = "author"
 	BranchParam          = "branch"
 	CachedFlag           = "cached"
@@ -37,22 +38,33 @@ const (
 	DryRunFlag           = "dry-run"
 	EmptyParam           = "empty"
 	ForceFlag            = "force"
+	FromKeyFlag          = "from-key"
+	GpgSignFlag          = "gpg-sign"
 	GraphFlag            = "graph"
 	HardResetParam       = "hard"
 	HostFlag             = "host"
 	InteractiveFlag      = "interactive"
+	IntoParam            = "into"
 	ListFlag             = "list"
+	MergedParam          = "merged"
 	MergesFlag           = "merges"
 	MessageArg           = "message"
+	MetadataParam        = "metadata"
 	MinParentsFlag       = "min-parents"
 	MoveFlag             = "move"
 	NoCommitFlag         = "no-commit"
 	NoEditFlag           = "no-edit"
 	NoFFParam            = "no-ff"
+	NoGpgSignFlag        = "no-gpg-sign"
 	NoPrettyFlag         = "no-pretty"
+	NoRebaseFlag         = "no-rebase"
 	NoTLSFlag            = "no-tls"
 	NoJsonMergeFlag      = "dont-merge-json"
+	NoMergedParam        = "no-merged"
+	NoVerifyFlag         = "no-verify"
 	NotFlag              = "not"
+	NotesFlag            = "notes"
+	NotesRefParam        = "ref"
 	NumberFlag           = "number"
 	OneLineFlag          = "oneline"
 	OursFlag             = "ours"
@@ -61,8 +73,12 @@ const (
 	PasswordFlag         = "password"
 	PortFlag             = "port"
 	PruneFlag            = "prune"
+	PruneOlderThanFlag   = "prune-older-than"
+	RebaseFlag           = "rebase"
 	RemoteParam          = "remote"
+	RunTestsFlag         = "run-tests"
 	SetUpstreamFlag      = "set-upstream"
+	SetUpstreamToParam   = "set-upstream-to"
 	ShallowFlag          = "shallow"
 	ShowIgnoredFlag      = "ignored"
 	SilentFlag           = "silent"
@@ -73,8 +89,11 @@ const (
 	StatFlag             = "stat"
 	SystemFlag           = "system"
 	TablesFlag           = "tables"
+	TagsFlag             = "tags"
 	TheirsFlag           = "theirs"
+	ToKeyFlag            = "to-key"
 	TrackFlag            = "track"
+	TTLParam             = "ttl"
 	UpperCaseAllFlag     = "ALL"
 	UserFlag             = "user"
 )
@@ -0,0 +1,236 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const noCommitFlag = "no-commit"
+
+var applyDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Apply SQL patch files created by format-patch",
+	LongDesc: `Applies each of the given patch files, in order, to the current branch. Patch files are created with {{.EmphasisLeft}}dolt format-patch{{.EmphasisRight}}.
+
+After the statements in a patch are executed, all changed tables are committed using the author, date and message recorded in the patch. The working set must be clean before patches are committed. With {{.EmphasisLeft}}--no-commit{{.EmphasisRight}}, the changes from all patches are left in the working set instead.
+
+If a statement fails, no further patches are applied. Changes made by the statements of the failing patch that ran before the error remain in the working set, and can be discarded with {{.EmphasisLeft}}dolt reset --hard{{.EmphasisRight}}.
+`,
+	Synopsis: []string{
+		`[--no-commit] {{.LessThan}}patch{{.GreaterThan}}...`,
+	},
+}
+
+type ApplyCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd ApplyCmd) Name() string {
+	return "apply"
+}
+
+// Description returns a description of the command
+func (cmd ApplyCmd) Description() string {
+	return "Apply SQL patch files created by format-patch."
+}
+
+// EventType returns the type of the event to log
+func (cmd ApplyCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+func (cmd ApplyCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(applyDocs, ap)
+}
+
+func (cmd ApplyCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs(cmd.Name())
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"patch", "The patch files to apply."})
+	ap.SupportsFlag(noCommitFlag, "", "Apply the patches to the working set without committing them.")
+	return ap
+}
+
+func (cmd ApplyCmd) RequiresRepo() bool {
+	return false
+}
+
+// Exec executes the command
+func (cmd ApplyCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, applyDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() == 0 {
+		usage()
+		return 1
+	}
+
+	// Read all the patches up front so that a malformed patch late in the series doesn't leave earlier ones applied
+	patches := make([]*sqlPatch, apr.NArg())
+	for i, path := range apr.Args {
+		p, err := readPatchFile(dEnv, path)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: unable to read patch '%s'", path).AddCause(err).Build(), usage)
+		}
+		patches[i] = p
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	commit := !apr.Contains(noCommitFlag)
+	if commit {
+		staged, unstaged, err := GetDoltStatus(queryist, sqlCtx)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		if len(staged) > 0 || len(unstaged) > 0 {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: your local changes would be committed along with the patches. Commit or stash them, or use --%s", noCommitFlag).Build(), usage)
+		}
+	}
+
+	for i, p := range patches {
+		path := apr.Arg(i)
+		for _, stmt := range p.statements {
+			if _, err := GetRowsForSql(queryist, sqlCtx, stmt); err != nil {
+				return HandleVErrAndExitCode(errhand.BuildDError("error: failed to apply patch '%s'", path).AddCause(err).AddDetails("statement: %s", stmt).Build(), usage)
+			}
+		}
+
+		if commit {
+			_, err := InterpolateAndRunQuery(queryist, sqlCtx, "call dolt_commit('-A', '--allow-empty', '-m', ?, '--author', ?, '--date', ?)",
+				p.message, fmt.Sprintf("%s <%s>", p.name, p.email), p.date.Format(time.RFC3339))
+			if err != nil {
+				return HandleVErrAndExitCode(errhand.BuildDError("error: failed to commit patch '%s'", path).AddCause(err).Build(), usage)
+			}
+		}
+		cli.Printf("Applied %s: %s\n", path, strings.SplitN(p.message, "\n", 2)[0])
+	}
+
+	return 0
+}
+
+func readPatchFile(dEnv *env.DoltEnv, path string) (*sqlPatch, error) {
+	rd, err := dEnv.FS.OpenForRead(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	return readPatch(rd)
+}
+
+// readPatch parses a patch in the format written by sqlPatch.write.
+func readPatch(rd io.Reader) (*sqlPatch, error) {
+	br := bufio.NewReader(rd)
+	line, err := br.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if strings.TrimRight(line, "\r\n") != patchHeaderLine {
+		return nil, fmt.Errorf("not a dolt patch file")
+	}
+
+	p := &sqlPatch{}
+	inMessage := false
+	var messageLines []string
+	var body io.Reader = br
+	for {
+		line, err = br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if !strings.HasPrefix(line, "--") {
+			// the first line after the header belongs to the statements
+			body = io.MultiReader(strings.NewReader(line), br)
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if inMessage {
+			messageLines = append(messageLines, strings.TrimPrefix(strings.TrimPrefix(line, "--"), " "))
+		} else if line == patchMessageLine {
+			inMessage = true
+		} else if err := p.parseHeaderLine(line); err != nil {
+			return nil, err
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+	p.message = strings.Join(messageLines, "\n")
+
+	if p.commitHash == "" || p.name == "" || p.date.IsZero() {
+		return nil, fmt.Errorf("patch header is missing commit information")
+	}
+
+	scanner := NewSqlStatementScanner(body)
+	for scanner.Scan() {
+		stmt := strings.TrimSpace(scanner.Text())
+		if stmt == "" {
+			continue
+		}
+		p.statements = append(p.statements, stmt)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *sqlPatch) parseHeaderLine(line string) error {
+	key, val, ok := strings.Cut(strings.TrimPrefix(line, "-- "), ": ")
+	if !ok {
+		return fmt.Errorf("invalid patch header line: %s", line)
+	}
+
+	switch key {
+	case patchCommitKey:
+		p.commitHash = val
+	case patchParentKey:
+		p.parentHash = val
+	case patchAuthorKey:
+		name, email, ok := strings.Cut(val, " <")
+		if !ok || !strings.HasSuffix(email, ">") {
+			return fmt.Errorf("invalid patch author: %s", val)
+		}
+		p.name, p.email = name, strings.TrimSuffix(email, ">")
+	case patchDateKey:
+		t, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return fmt.Errorf("invalid patch date: %s", val)
+		}
+		p.date = t
+	}
+
+	return nil
+}
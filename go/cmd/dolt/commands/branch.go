@@ -51,13 +51,19 @@ With a {{.EmphasisLeft}}-m{{.EmphasisRight}}, {{.LessThan}}oldbranch{{.GreaterTh
 
 The {{.EmphasisLeft}}-c{{.EmphasisRight}} options have the exact same semantics as {{.EmphasisLeft}}-m{{.EmphasisRight}}, except instead of the branch being renamed it will be copied to a new name.
 
-With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}branchname{{.GreaterThan}} will be deleted. You may specify more than one branch for deletion.`,
+With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}branchname{{.GreaterThan}} will be deleted. You may specify more than one branch for deletion.
+
+With {{.EmphasisLeft}}--merged{{.EmphasisRight}}, only branches merged into {{.LessThan}}commit{{.GreaterThan}}, or {{.EmphasisLeft}}HEAD{{.EmphasisRight}} if it's not given, are listed, and with {{.EmphasisLeft}}--no-merged{{.EmphasisRight}} only branches which aren't. Given with {{.EmphasisLeft}}-d{{.EmphasisRight}} instead of branch names, {{.EmphasisLeft}}--merged{{.EmphasisRight}} deletes every branch merged into {{.LessThan}}commit{{.GreaterThan}}, and given with {{.EmphasisLeft}}-D{{.EmphasisRight}}, {{.EmphasisLeft}}--no-merged{{.EmphasisRight}} deletes every branch which isn't. The current branch and {{.LessThan}}commit{{.GreaterThan}} itself are never deleted this way, nor are branches checked out by other sql-server sessions unless forced. Use {{.EmphasisLeft}}--dry-run{{.EmphasisRight}} to see which branches would be deleted first.
+
+With {{.EmphasisLeft}}--set-upstream-to{{.EmphasisRight}}, the upstream of {{.LessThan}}branchname{{.GreaterThan}}, or of the current branch if it's not given, is set to the remote tracking branch {{.LessThan}}upstream{{.GreaterThan}}, such as {{.EmphasisLeft}}origin/main{{.EmphasisRight}}. How far each branch is ahead of and behind its upstream is shown in the {{.EmphasisLeft}}ahead{{.EmphasisRight}} and {{.EmphasisLeft}}behind{{.EmphasisRight}} columns of the {{.EmphasisLeft}}dolt_branches{{.EmphasisRight}} system table.`,
 	Synopsis: []string{
-		`[--list] [-v] [-a] [-r]`,
+		`[--list] [-v] [-a] [-r] [--merged [{{.LessThan}}commit{{.GreaterThan}}] | --no-merged [{{.LessThan}}commit{{.GreaterThan}}]]`,
 		`[-f] {{.LessThan}}branchname{{.GreaterThan}} [{{.LessThan}}start-point{{.GreaterThan}}]`,
 		`-m [-f] [{{.LessThan}}oldbranch{{.GreaterThan}}] {{.LessThan}}newbranch{{.GreaterThan}}`,
 		`-c [-f] [{{.LessThan}}oldbranch{{.GreaterThan}}] {{.LessThan}}newbranch{{.GreaterThan}}`,
 		`-d [-f] [-r] {{.LessThan}}branchname{{.GreaterThan}}...`,
+		`(-d --merged | -D --no-merged) [{{.LessThan}}commit{{.GreaterThan}}] [--dry-run]`,
+		`(--set-upstream-to={{.LessThan}}upstream{{.GreaterThan}} | -u {{.LessThan}}upstream{{.GreaterThan}}) [{{.LessThan}}branchname{{.GreaterThan}}]`,
 	},
 }
 
@@ -94,6 +100,7 @@ func (cmd BranchCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(datasetsFlag, "", "List all datasets in the database")
 	ap.SupportsFlag(cli.RemoteParam, "r", "When in list mode, show only remote tracked branches. When with -d, delete a remote tracking branch.")
 	ap.SupportsFlag(showCurrentFlag, "", "Print the name of the current branch")
+	addResultFormatFlag(ap, "")
 	return ap
 }
 
@@ -119,12 +126,26 @@ func (cmd BranchCmd) Exec(ctx context.Context, commandStr string, args []string,
 		defer closeFunc()
 	}
 
-	if len(apr.ContainsMany(cli.MoveFlag, cli.CopyFlag, cli.DeleteFlag, cli.DeleteForceFlag, cli.ListFlag, showCurrentFlag)) > 1 {
-		cli.PrintErrln("Must specify exactly one of --move/-m, --copy/-c, --delete/-d, -D, --show-current, or --list.")
+	if len(apr.ContainsMany(cli.MoveFlag, cli.CopyFlag, cli.DeleteFlag, cli.DeleteForceFlag, cli.ListFlag, showCurrentFlag, cli.SetUpstreamToParam)) > 1 {
+		cli.PrintErrln("Must specify exactly one of --move/-m, --copy/-c, --delete/-d, -D, --show-current, --set-upstream-to/-u, or --list.")
 		return 1
 	}
 
+	if _, err := getResultFormat(apr); err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if isJsonResultFormat(apr) {
+		isListing := apr.Contains(cli.ListFlag) || apr.Contains(showCurrentFlag) ||
+			(apr.NArg() == 0 && len(apr.ContainsMany(cli.MoveFlag, cli.CopyFlag, cli.DeleteFlag, cli.DeleteForceFlag, datasetsFlag, cli.SetUpstreamToParam)) == 0)
+		if !isListing {
+			err = fmt.Errorf("error: --%s %s is only supported when listing branches", FormatFlag, jsonResultFormat)
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+	}
+
 	switch {
+	case apr.Contains(cli.SetUpstreamToParam):
+		return setUpstream(sqlCtx, queryEngine, apr, args, usage)
 	case apr.Contains(cli.MoveFlag):
 		return moveBranch(sqlCtx, queryEngine, apr, args, usage)
 	case apr.Contains(cli.CopyFlag):
@@ -136,7 +157,7 @@ func (cmd BranchCmd) Exec(ctx context.Context, commandStr string, args []string,
 	case apr.Contains(cli.ListFlag):
 		return printBranches(sqlCtx, queryEngine, apr, usage)
 	case apr.Contains(showCurrentFlag):
-		return printCurrentBranch(sqlCtx, queryEngine)
+		return printCurrentBranch(sqlCtx, queryEngine, apr)
 	case apr.Contains(datasetsFlag):
 		return printAllDatasets(ctx, dEnv)
 	case apr.NArg() > 0:
@@ -152,13 +173,24 @@ type branchMeta struct {
 	remote bool
 }
 
-func getBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, remote bool) ([]branchMeta, error) {
+// getBranches returns the local or remote branches, only those merged or not merged into a commit if
+// |apr| has --merged or --no-merged.
+func getBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser.ArgParseResults, remote bool) ([]branchMeta, error) {
 	var command string
 	if remote {
 		command = "SELECT name, hash from dolt_remote_branches"
 	} else {
 		command = "SELECT name, hash from dolt_branches"
 	}
+	var err error
+	if target, ok := apr.GetValue(cli.MergedParam); ok {
+		command, err = dbr.InterpolateForDialect(command+" WHERE has_ancestor(?, hash)", []interface{}{mergedTarget(target)}, dialect.MySQL)
+	} else if target, ok := apr.GetValue(cli.NoMergedParam); ok {
+		command, err = dbr.InterpolateForDialect(command+" WHERE NOT has_ancestor(?, hash)", []interface{}{mergedTarget(target)}, dialect.MySQL)
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	schema, rowIter, _, err := queryEngine.Query(sqlCtx, command)
 	if err != nil {
@@ -188,6 +220,14 @@ func getBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, remote bool) ([]
 	}
 }
 
+// mergedTarget returns the commit given with --merged or --no-merged, which is HEAD if none was given.
+func mergedTarget(target string) string {
+	if target == "" {
+		return "HEAD"
+	}
+	return target
+}
+
 func printBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser.ArgParseResults, _ cli.UsagePrinter) int {
 	branchSet := set.NewStrSet(apr.Args)
 
@@ -197,7 +237,7 @@ func printBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser
 
 	var branches []branchMeta
 	if printAll || printRemote {
-		remoteBranches, err := getBranches(sqlCtx, queryEngine, true)
+		remoteBranches, err := getBranches(sqlCtx, queryEngine, apr, true)
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.BuildDError("error: failed to read remote branches from db").AddCause(err).Build(), nil)
 		}
@@ -205,7 +245,7 @@ func printBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser
 	}
 
 	if printAll || !printRemote {
-		localBranches, err := getBranches(sqlCtx, queryEngine, false)
+		localBranches, err := getBranches(sqlCtx, queryEngine, apr, false)
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.BuildDError("error: failed to read local branches from db").AddCause(err).Build(), nil)
 		}
@@ -221,6 +261,10 @@ func printBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser
 		return branches[i].name < branches[j].name
 	})
 
+	if isJsonResultFormat(apr) {
+		return printBranchesJson(branches, branchSet, currentBranch)
+	}
+
 	for _, branch := range branches {
 		if branchSet.Size() > 0 && !branchSet.Contains(branch.name) {
 			continue
@@ -249,11 +293,45 @@ func printBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser
 	return 0
 }
 
-func printCurrentBranch(sqlCtx *sql.Context, queryEngine cli.Queryist) int {
+// branchJson is the machine-readable representation of a branch in dolt branch output.
+type branchJson struct {
+	Name    string `json:"name"`
+	Hash    string `json:"hash"`
+	Current bool   `json:"current"`
+	Remote  bool   `json:"remote"`
+}
+
+func printBranchesJson(branches []branchMeta, branchSet *set.StrSet, currentBranch string) int {
+	res := make([]branchJson, 0, len(branches))
+	for _, branch := range branches {
+		if branchSet.Size() > 0 && !branchSet.Contains(branch.name) {
+			continue
+		}
+		res = append(res, branchJson{
+			Name:    branch.name,
+			Hash:    branch.hash,
+			Current: branch.name == currentBranch && !branch.remote,
+			Remote:  branch.remote,
+		})
+	}
+
+	err := printJsonResult(struct {
+		Branches []branchJson `json:"branches"`
+	}{res})
+	return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), nil)
+}
+
+func printCurrentBranch(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser.ArgParseResults) int {
 	currentBranchName, err := getActiveBranchName(sqlCtx, queryEngine)
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.BuildDError("error: failed to read current branch from db").AddCause(err).Build(), nil)
 	}
+	if isJsonResultFormat(apr) {
+		err = printJsonResult(struct {
+			Branch string `json:"branch"`
+		}{currentBranchName})
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), nil)
+	}
 	cli.Println(currentBranchName)
 	return 0
 }
@@ -384,6 +462,30 @@ func createBranch(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser.
 	return 0
 }
 
+func setUpstream(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser.ArgParseResults, args []string, usage cli.UsagePrinter) int {
+	if apr.NArg() > 1 {
+		usage()
+		return 1
+	}
+
+	result := callStoredProcedure(sqlCtx, queryEngine, args)
+	if result != 0 {
+		return result
+	}
+
+	branchName := apr.Arg(0)
+	if branchName == "" {
+		var err error
+		branchName, err = getActiveBranchName(sqlCtx, queryEngine)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: failed to read current branch from db").AddCause(err).Build(), nil)
+		}
+	}
+	upstream, _ := apr.GetValue(cli.SetUpstreamToParam)
+	cli.Printf("branch '%s' set up to track '%s'.\n", branchName, upstream)
+	return 0
+}
+
 func moveBranch(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser.ArgParseResults, args []string, usage cli.UsagePrinter) int {
 	if apr.NArg() != 1 && apr.NArg() != 2 {
 		usage()
@@ -451,7 +553,8 @@ func copyBranch(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser.Ar
 }
 
 func deleteBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser.ArgParseResults, args []string, usage cli.UsagePrinter) int {
-	if apr.NArg() == 0 {
+	bulk := apr.Contains(cli.MergedParam) || apr.Contains(cli.NoMergedParam)
+	if apr.NArg() == 0 && !bulk {
 		usage()
 		return 1
 	}
@@ -466,7 +569,23 @@ func deleteBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparse
 		return 1
 	}
 
-	return callStoredProcedure(sqlCtx, queryEngine, args)
+	if !bulk {
+		return callStoredProcedure(sqlCtx, queryEngine, args)
+	}
+
+	result := callStoredProcedure(sqlCtx, queryEngine, args)
+	if result != 0 {
+		return result
+	}
+	// the branches deleted, or which would be deleted with --dry-run, are reported as notes
+	rows, err := GetRowsForSql(queryEngine, sqlCtx, "SHOW WARNINGS")
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), nil)
+	}
+	for _, row := range rows {
+		cli.Println(fmt.Sprint(row[2]))
+	}
+	return 0
 }
 
 func generateForceDeleteMessage(args []string) string {
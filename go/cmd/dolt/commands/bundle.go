@@ -0,0 +1,420 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/earl"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	createBundleId    = "create"
+	extractBundleId   = "extract"
+	listHeadsBundleId = "list-heads"
+
+	// bundleManifestName is the name of the entry at the start of a bundle describing its refs
+	bundleManifestName = "bundle.json"
+	// bundleStoreDir is the directory within a bundle holding the chunk store files
+	bundleStoreDir       = "noms"
+	bundleFormatVersion  = 1
+	bundleStoreLockFile  = "LOCK"
+	bundleExtractDirPerm = 0755
+)
+
+var bundleDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Package refs and their history into a single file",
+	LongDesc: `Creates and unpacks bundles, which are single files containing a set of refs along with all the data reachable from them. Bundles allow a database, or part of one, to be shipped as one artifact to a machine that cannot reach the source repository.
+
+{{.EmphasisLeft}}create{{.EmphasisRight}}
+Writes the given branches and tags, and everything reachable from them, to the bundle {{.LessThan}}file{{.GreaterThan}}. If no refs are given, all branches and tags are included. Working sets and remote tracking branches are never included.
+
+{{.EmphasisLeft}}extract{{.EmphasisRight}}
+Unpacks the bundle {{.LessThan}}file{{.GreaterThan}} into {{.LessThan}}dir{{.GreaterThan}}, which must not already exist. The extracted directory is a file remote, and can be used as the source of {{.EmphasisLeft}}dolt clone file://{{.LessThan}}dir{{.GreaterThan}}{{.EmphasisRight}}, or added with {{.EmphasisLeft}}dolt remote add{{.EmphasisRight}} and fetched from.
+
+{{.EmphasisLeft}}list-heads{{.EmphasisRight}}
+Lists the refs contained in the bundle {{.LessThan}}file{{.GreaterThan}} along with the commits they reference.
+`,
+	Synopsis: []string{
+		"create {{.LessThan}}file{{.GreaterThan}} [{{.LessThan}}ref{{.GreaterThan}}...]",
+		"extract {{.LessThan}}file{{.GreaterThan}} {{.LessThan}}dir{{.GreaterThan}}",
+		"list-heads {{.LessThan}}file{{.GreaterThan}}",
+	},
+}
+
+type BundleCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd BundleCmd) Name() string {
+	return "bundle"
+}
+
+// Description returns a description of the command
+func (cmd BundleCmd) Description() string {
+	return "Package refs and their history into a single file."
+}
+
+func (cmd BundleCmd) RequiresRepo() bool {
+	return false
+}
+
+func (cmd BundleCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(bundleDocs, ap)
+}
+
+func (cmd BundleCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs(cmd.Name())
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"file", "The bundle file."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"ref", "A branch or tag to include in the bundle."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"dir", "The directory to extract the bundle to."})
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd BundleCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd BundleCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, bundleDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	var verr errhand.VerboseError
+	switch {
+	case apr.NArg() >= 2 && apr.Arg(0) == createBundleId:
+		if !cli.CheckEnvIsValid(dEnv) {
+			return 2
+		}
+		verr = createBundle(ctx, dEnv, apr.Arg(1), apr.Args[2:])
+	case apr.NArg() == 3 && apr.Arg(0) == extractBundleId:
+		verr = extractBundle(dEnv.FS, apr.Arg(1), apr.Arg(2))
+	case apr.NArg() == 2 && apr.Arg(0) == listHeadsBundleId:
+		verr = listBundleHeads(dEnv.FS, apr.Arg(1))
+	default:
+		verr = errhand.BuildDError("").SetPrintUsage().Build()
+	}
+
+	return HandleVErrAndExitCode(verr, usage)
+}
+
+// bundleManifest is the first entry in a bundle, and lists the refs it contains.
+type bundleManifest struct {
+	FormatVersion int                 `json:"format_version"`
+	NomsFormat    string              `json:"noms_format"`
+	Refs          []bundleManifestRef `json:"refs"`
+}
+
+type bundleManifestRef struct {
+	Ref  string `json:"ref"`
+	Hash string `json:"hash"`
+}
+
+func createBundle(ctx context.Context, dEnv *env.DoltEnv, bundlePath string, refNames []string) errhand.VerboseError {
+	metadata, err := env.GetMultiEnvStorageMetadata(dEnv.FS)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if metadata.ArchiveFilesPresent() {
+		return errhand.BuildDError("error: archive files present. Please revert them with the --revert flag before running this command.").Build()
+	}
+
+	refs, err := getRefsForBundle(ctx, dEnv.DoltDB, refNames)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	storeDir, err := os.MkdirTemp(tmpDir, "bundle")
+	if err != nil {
+		return errhand.BuildDError("error: unable to create temporary directory for bundle").AddCause(err).Build()
+	}
+	defer os.RemoveAll(storeDir)
+
+	nbf := dEnv.DoltDB.Format()
+	manifest := bundleManifest{
+		FormatVersion: bundleFormatVersion,
+		NomsFormat:    nbf.VersionString(),
+	}
+
+	storeUrl := earl.FileUrlFromPath(storeDir, os.PathSeparator)
+	destDB, err := doltdb.LoadDoltDBWithParams(ctx, nbf, storeUrl, filesys.LocalFS, nil)
+	if err != nil {
+		return errhand.BuildDError("error: unable to create bundle store").AddCause(err).Build()
+	}
+	for _, r := range refs {
+		err = pullRefIntoBundle(ctx, tmpDir, dEnv.DoltDB, destDB, r)
+		if err != nil {
+			destDB.Close()
+			return errhand.BuildDError("error: unable to add '%s' to bundle", r.Ref.String()).AddCause(err).Build()
+		}
+		manifest.Refs = append(manifest.Refs, bundleManifestRef{Ref: r.Ref.String(), Hash: r.Hash.String()})
+	}
+	err = destDB.Close()
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if u, err := earl.Parse(storeUrl); err == nil {
+		_ = dbfactory.DeleteFromSingletonCache(u.Path)
+	}
+
+	err = writeBundle(dEnv.FS, bundlePath, manifest, storeDir)
+	if err != nil {
+		return errhand.BuildDError("error: unable to write bundle '%s'", bundlePath).AddCause(err).Build()
+	}
+
+	cli.Printf("Bundled %d refs into %s\n", len(manifest.Refs), bundlePath)
+	return nil
+}
+
+// getRefsForBundle returns the branches and tags named by |refNames|, or every branch and tag if none are named.
+func getRefsForBundle(ctx context.Context, ddb *doltdb.DoltDB, refNames []string) ([]doltdb.RefWithHash, error) {
+	var all []doltdb.RefWithHash
+	err := ddb.VisitRefsOfType(ctx, map[ref.RefType]struct{}{ref.BranchRefType: {}, ref.TagRefType: {}}, func(r ref.DoltRef, addr hash.Hash) error {
+		all = append(all, doltdb.RefWithHash{Ref: r, Hash: addr})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(refNames) == 0 {
+		if len(all) == 0 {
+			return nil, errors.New("error: no branches or tags to bundle")
+		}
+		return all, nil
+	}
+
+	var selected []doltdb.RefWithHash
+	for _, name := range refNames {
+		var found *doltdb.RefWithHash
+		for i, r := range all {
+			// branches take precedence over tags with the same name, as they do elsewhere
+			if r.Ref.String() == name || (r.Ref.GetPath() == name && (found == nil || r.Ref.GetType() == ref.BranchRefType)) {
+				found = &all[i]
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("error: '%s' is not a branch or tag", name)
+		}
+		selected = append(selected, *found)
+	}
+	return selected, nil
+}
+
+func pullRefIntoBundle(ctx context.Context, tmpDir string, srcDB, destDB *doltdb.DoltDB, r doltdb.RefWithHash) error {
+	newCtx, cancelFunc := context.WithCancel(ctx)
+	wg, statsCh := buildProgStarter(defaultLanguage)(newCtx)
+	err := destDB.PullChunks(ctx, tmpDir, srcDB, []hash.Hash{r.Hash}, statsCh, nil)
+	stopProgFuncs(cancelFunc, wg, statsCh)
+	if err != nil {
+		return err
+	}
+	return destDB.SetHead(ctx, r.Ref, r.Hash)
+}
+
+// writeBundle writes a bundle to |bundlePath| as a tar file. The manifest is always the first entry, followed by the
+// files of the chunk store in |storeDir|.
+func writeBundle(fs filesys.Filesys, bundlePath string, manifest bundleManifest, storeDir string) (err error) {
+	wr, err := fs.OpenForWrite(bundlePath, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := wr.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	tw := tar.NewWriter(wr)
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = tw.WriteHeader(&tar.Header{Name: bundleManifestName, Mode: 0644, Size: int64(len(manifestBytes))})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(manifestBytes)
+	if err != nil {
+		return err
+	}
+
+	err = filepath.Walk(storeDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() == bundleStoreLockFile {
+			return nil
+		}
+		rel, err := filepath.Rel(storeDir, p)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(bundleStoreDir, filepath.ToSlash(rel))
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// readBundleManifest reads the manifest from the start of the bundle in |rd|, leaving |tr| positioned at the entry
+// following it.
+func readBundleManifest(rd io.Reader) (*tar.Reader, bundleManifest, error) {
+	var manifest bundleManifest
+	tr := tar.NewReader(rd)
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != bundleManifestName {
+		return nil, manifest, errors.New("not a dolt bundle")
+	}
+	err = json.NewDecoder(tr).Decode(&manifest)
+	if err != nil {
+		return nil, manifest, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if manifest.FormatVersion != bundleFormatVersion {
+		return nil, manifest, fmt.Errorf("unsupported bundle format version %d", manifest.FormatVersion)
+	}
+	return tr, manifest, nil
+}
+
+func listBundleHeads(fs filesys.Filesys, bundlePath string) errhand.VerboseError {
+	rd, err := fs.OpenForRead(bundlePath)
+	if err != nil {
+		return errhand.BuildDError("error: unable to open bundle '%s'", bundlePath).AddCause(err).Build()
+	}
+	defer rd.Close()
+
+	_, manifest, err := readBundleManifest(rd)
+	if err != nil {
+		return errhand.BuildDError("error: unable to read bundle '%s'", bundlePath).AddCause(err).Build()
+	}
+	for _, r := range manifest.Refs {
+		cli.Printf("%s %s\n", r.Hash, r.Ref)
+	}
+	return nil
+}
+
+func extractBundle(fs filesys.Filesys, bundlePath, dir string) errhand.VerboseError {
+	if exists, _ := fs.Exists(dir); exists {
+		return errhand.BuildDError("error: '%s' already exists", dir).Build()
+	}
+
+	rd, err := fs.OpenForRead(bundlePath)
+	if err != nil {
+		return errhand.BuildDError("error: unable to open bundle '%s'", bundlePath).AddCause(err).Build()
+	}
+	defer rd.Close()
+
+	tr, manifest, err := readBundleManifest(rd)
+	if err != nil {
+		return errhand.BuildDError("error: unable to read bundle '%s'", bundlePath).AddCause(err).Build()
+	}
+
+	absDir, err := fs.Abs(dir)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	err = extractBundleStore(tr, absDir)
+	if err != nil {
+		_ = os.RemoveAll(absDir)
+		return errhand.BuildDError("error: unable to extract bundle '%s'", bundlePath).AddCause(err).Build()
+	}
+
+	cli.Printf("Extracted %d refs to %s\n", len(manifest.Refs), dir)
+	cli.Printf("Clone it with: dolt clone %s\n", earl.FileUrlFromPath(absDir, os.PathSeparator))
+	return nil
+}
+
+func extractBundleStore(tr *tar.Reader, dir string) error {
+	// the oldgen directory must exist for the extracted store to be opened as a file remote, even if it's empty
+	if err := os.MkdirAll(filepath.Join(dir, "oldgen"), bundleExtractDirPerm); err != nil {
+		return err
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		name := path.Clean(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || !strings.HasPrefix(name, bundleStoreDir+"/") {
+			return fmt.Errorf("unexpected bundle entry '%s'", hdr.Name)
+		}
+		// path.Clean has already resolved any ".." elements, so |rel| cannot escape |dir|
+		rel := strings.TrimPrefix(name, bundleStoreDir+"/")
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), bundleExtractDirPerm); err != nil {
+			return err
+		}
+		if err := extractBundleFile(tr, p); err != nil {
+			return err
+		}
+	}
+}
+
+func extractBundleFile(rd io.Reader, p string) error {
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, rd)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	storeDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "manifest"), []byte("manifest contents"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "LOCK"), nil, 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(storeDir, "oldgen"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "oldgen", "tablefile"), []byte("table file"), 0644))
+
+	manifest := bundleManifest{
+		FormatVersion: bundleFormatVersion,
+		NomsFormat:    "__DOLT__",
+		Refs:          []bundleManifestRef{{Ref: "refs/heads/main", Hash: "2qmkn5bsnakp0depjp71e9hbl3qu6mld"}},
+	}
+	bundlePath := filepath.Join(t.TempDir(), "test.bundle")
+	require.NoError(t, writeBundle(filesys.LocalFS, bundlePath, manifest, storeDir))
+
+	f, err := os.Open(bundlePath)
+	require.NoError(t, err)
+	defer f.Close()
+	tr, readManifest, err := readBundleManifest(f)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, readManifest)
+
+	outDir := filepath.Join(t.TempDir(), "out")
+	require.NoError(t, extractBundleStore(tr, outDir))
+
+	contents, err := os.ReadFile(filepath.Join(outDir, "manifest"))
+	require.NoError(t, err)
+	assert.Equal(t, "manifest contents", string(contents))
+	contents, err = os.ReadFile(filepath.Join(outDir, "oldgen", "tablefile"))
+	require.NoError(t, err)
+	assert.Equal(t, "table file", string(contents))
+	_, err = os.Stat(filepath.Join(outDir, "LOCK"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExtractBundleRejectsEscapingEntries(t *testing.T) {
+	for _, name := range []string{"noms/../../escaped", "../escaped", "other/file"} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 1, Typeflag: tar.TypeReg}))
+			_, err := tw.Write([]byte("x"))
+			require.NoError(t, err)
+			require.NoError(t, tw.Close())
+
+			dir := t.TempDir()
+			err = extractBundleStore(tar.NewReader(&buf), filepath.Join(dir, "out"))
+			assert.Error(t, err)
+			_, err = os.Stat(filepath.Join(dir, "escaped"))
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestReadBundleManifestErrors(t *testing.T) {
+	_, _, err := readBundleManifest(bytes.NewReader([]byte("not a tar file")))
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	body := []byte(`{"format_version": 99}`)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: bundleManifestName, Mode: 0644, Size: int64(len(body))}))
+	_, err = tw.Write(body)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	_, _, err = readBundleManifest(&buf)
+	assert.ErrorContains(t, err, "unsupported bundle format version")
+}
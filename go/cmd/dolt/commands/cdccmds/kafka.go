@@ -0,0 +1,159 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdccmds
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc/kafka"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	brokersParam      = "brokers"
+	cursorParam       = "cursor"
+	topicPrefixParam  = "topic-prefix"
+	batchSizeParam    = "batch-size"
+	pollIntervalParam = "poll-interval-millis"
+	timeoutParam      = "timeout-millis"
+	onceFlag          = "once"
+)
+
+var kafkaDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Publish committed row changes to Kafka.",
+	LongDesc: `Publishes the row changes committed to a branch to Kafka topics as they're committed, like a Debezium source connector, so that Dolt can feed existing streaming pipelines.
+
+Changes are read with a change cursor, which is created with the {{.EmphasisLeft}}dolt_change_cursor(){{.EmphasisRight}} stored procedure, and are produced to a topic per table named {{.EmphasisLeft}}<topic-prefix>.<database>.<table>{{.EmphasisRight}}. The value of each message is a Debezium change event in JSON, without a schema, with the commit which made the change in its {{.EmphasisLeft}}source.commit{{.EmphasisRight}} field and in the {{.EmphasisLeft}}dolt.commit{{.EmphasisRight}} header, and its key is the primary key of the changed row as JSON. Avro isn't supported.
+
+The commit hash of the cursor is the offset of the connector: the cursor is moved past each commit once every one of its changes has been acknowledged, so changes are produced at least once, and publishing resumes where it stopped when the command is run again. The cursor can be moved with {{.EmphasisLeft}}dolt_change_cursor('advance', ...){{.EmphasisRight}} to skip or replay changes.
+
+The command runs until it's interrupted, checking the cursor's branch for new commits every {{.EmphasisLeft}}--poll-interval-millis{{.EmphasisRight}}, unless {{.EmphasisLeft}}--once{{.EmphasisRight}} is given. To publish the changes of a database served by {{.EmphasisLeft}}dolt sql-server{{.EmphasisRight}}, configure {{.EmphasisLeft}}change_data_capture.kafka{{.EmphasisRight}} in the server's config instead.`,
+	Synopsis: []string{
+		"--brokers {{.LessThan}}host:port[,host:port...]{{.GreaterThan}} --cursor {{.LessThan}}name{{.GreaterThan}} [--topic-prefix {{.LessThan}}prefix{{.GreaterThan}}] [--once]",
+	},
+}
+
+type KafkaCmd struct{}
+
+// Name implements cli.Command.
+func (cmd KafkaCmd) Name() string {
+	return "kafka"
+}
+
+// Description implements cli.Command.
+func (cmd KafkaCmd) Description() string {
+	return kafkaDocs.ShortDesc
+}
+
+// Docs implements cli.Command.
+func (cmd KafkaCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(kafkaDocs, ap)
+}
+
+// ArgParser implements cli.Command.
+func (cmd KafkaCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+	ap.SupportsString(brokersParam, "", "host:port", "Comma separated addresses of the Kafka brokers to connect to.")
+	ap.SupportsString(cursorParam, "", "name", "The change cursor to read changes with.")
+	ap.SupportsString(topicPrefixParam, "", "prefix", "The first part of the name of each table's topic. Defaults to dolt.")
+	ap.SupportsInt(batchSizeParam, "", "n", "The most change events to produce at a time. Defaults to 1000.")
+	ap.SupportsInt(pollIntervalParam, "", "millis", "How often to check for new commits. Defaults to 1000.")
+	ap.SupportsInt(timeoutParam, "", "millis", "How long to wait for brokers to respond. Defaults to 10000.")
+	ap.SupportsFlag(onceFlag, "", "Publish the changes committed so far, and exit.")
+	return ap
+}
+
+// EventType implements cli.Command.
+func (cmd KafkaCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec implements cli.Command.
+func (cmd KafkaCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, kafkaDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	brokers, ok := apr.GetValue(brokersParam)
+	if !ok {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: --%s must be given", brokersParam).Build(), usage)
+	}
+	cursor, ok := apr.GetValue(cursorParam)
+	if !ok {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: --%s must be given", cursorParam).Build(), usage)
+	}
+	if !dEnv.DoltDB.Format().UsesFlatbuffers() {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(doltdb.ErrChangeCursorsUnsupported), usage)
+	}
+	path, err := dEnv.FS.Abs("")
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	producer, err := kafka.NewProducer(kafka.ProducerArgs{
+		Brokers: strings.Split(brokers, ","),
+		Timeout: time.Duration(apr.GetIntOrDefault(timeoutParam, 0)) * time.Millisecond,
+	})
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	defer producer.Close()
+
+	sink := cdc.NewKafkaSink(cdc.KafkaSinkArgs{
+		Logger:   logrus.NewEntry(logrus.StandardLogger()),
+		Database: dbfactory.DirToDBName(filepath.Base(path)),
+		DoltDB: func(ctx context.Context) (*doltdb.DoltDB, error) {
+			// pick up the commits other processes have made since the last poll
+			if err := dEnv.DoltDB.Rebase(ctx); err != nil {
+				return nil, err
+			}
+			return dEnv.DoltDB, nil
+		},
+		Cursor:       cursor,
+		Producer:     producer,
+		TopicPrefix:  apr.GetValueOrDefault(topicPrefixParam, ""),
+		BatchSize:    apr.GetIntOrDefault(batchSizeParam, 0),
+		PollInterval: time.Duration(apr.GetIntOrDefault(pollIntervalParam, 0)) * time.Millisecond,
+	})
+
+	if apr.Contains(onceFlag) {
+		if err := sink.Poll(ctx); err != nil {
+			return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		return 0
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	sink.Run(ctx)
+	return 0
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cicmds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+const (
+	// assertExpectedRows compares the number of rows returned by a test query to the assertion value.
+	assertExpectedRows = "expected_rows"
+	// assertExpectedColumns compares the number of columns returned by a test query to the assertion value.
+	assertExpectedColumns = "expected_columns"
+	// assertExpectedSingleValue compares the only value returned by a test query to the assertion value.
+	assertExpectedSingleValue = "expected_single_value"
+)
+
+// checkAssertion checks the results of a test query, |sch| and |rows|, against an assertion. It returns an empty string
+// if the assertion holds, and a description of the failure otherwise. An error is returned if the assertion itself is
+// invalid.
+func checkAssertion(sch sql.Schema, rows []sql.Row, assertionType, comparator string, expected *string) (string, error) {
+	switch strings.ToLower(assertionType) {
+	case assertExpectedRows:
+		return checkCount("row count", len(rows), comparator, expected)
+	case assertExpectedColumns:
+		return checkCount("column count", len(sch), comparator, expected)
+	case assertExpectedSingleValue:
+		if len(rows) != 1 || len(rows[0]) != 1 {
+			return fmt.Sprintf("expected a single value, got %d rows and %d columns", len(rows), len(sch)), nil
+		}
+		return checkValue(rows[0][0], comparator, expected)
+	default:
+		return "", fmt.Errorf("unknown assertion type '%s'", assertionType)
+	}
+}
+
+func checkCount(what string, actual int, comparator string, expected *string) (string, error) {
+	if expected == nil {
+		return "", fmt.Errorf("%s assertion requires an assertion value", what)
+	}
+	exp, err := strconv.Atoi(strings.TrimSpace(*expected))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s assertion value '%s'", what, *expected)
+	}
+	ok, err := compare(float64(actual-exp), comparator)
+	if err != nil || ok {
+		return "", err
+	}
+	return fmt.Sprintf("expected %s %s %d, got %d", what, comparator, exp, actual), nil
+}
+
+func checkValue(actual interface{}, comparator string, expected *string) (string, error) {
+	if actual == nil || expected == nil {
+		var ok bool
+		switch comparator {
+		case "==":
+			ok = actual == nil && expected == nil
+		case "!=":
+			ok = (actual == nil) != (expected == nil)
+		default:
+			if _, err := compare(0, comparator); err != nil {
+				return "", err
+			}
+		}
+		if ok {
+			return "", nil
+		}
+		return fmt.Sprintf("expected value %s %s, got %s", comparator, formatValue(expected), formatValue(actual)), nil
+	}
+
+	act := valueString(actual)
+	var cmp float64
+	actNum, actErr := strconv.ParseFloat(act, 64)
+	expNum, expErr := strconv.ParseFloat(strings.TrimSpace(*expected), 64)
+	if actErr == nil && expErr == nil {
+		cmp = actNum - expNum
+	} else {
+		cmp = float64(strings.Compare(act, *expected))
+	}
+
+	ok, err := compare(cmp, comparator)
+	if err != nil || ok {
+		return "", err
+	}
+	return fmt.Sprintf("expected value %s %s, got %s", comparator, *expected, act), nil
+}
+
+// compare returns the result of applying |comparator| to two values whose difference is |cmp|.
+func compare(cmp float64, comparator string) (bool, error) {
+	switch comparator {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	default:
+		return false, fmt.Errorf("unknown assertion comparator '%s'", comparator)
+	}
+}
+
+func valueString(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case *string:
+		if v == nil {
+			return "NULL"
+		}
+		return *v
+	default:
+		return valueString(v)
+	}
+}
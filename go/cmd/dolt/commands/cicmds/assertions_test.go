@@ -0,0 +1,75 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cicmds
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAssertion(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	twoCols := sql.Schema{{Name: "a", Type: types.Int64}, {Name: "b", Type: types.Text}}
+	oneCol := sql.Schema{{Name: "a", Type: types.Int64}}
+
+	tests := []struct {
+		name       string
+		sch        sql.Schema
+		rows       []sql.Row
+		typ        string
+		comparator string
+		expected   *string
+		pass       bool
+		err        bool
+	}{
+		{"rows equal", twoCols, []sql.Row{{1, "a"}, {2, "b"}}, assertExpectedRows, "==", ptr("2"), true, false},
+		{"rows not equal", twoCols, []sql.Row{{1, "a"}}, assertExpectedRows, "==", ptr("2"), false, false},
+		{"rows less than", twoCols, []sql.Row{}, assertExpectedRows, "<", ptr("1"), true, false},
+		{"columns", twoCols, nil, assertExpectedColumns, ">=", ptr("2"), true, false},
+		{"columns fail", twoCols, nil, assertExpectedColumns, ">", ptr("2"), false, false},
+		{"numeric value", oneCol, []sql.Row{{int64(10)}}, assertExpectedSingleValue, ">", ptr("9.5"), true, false},
+		{"decimal string value", oneCol, []sql.Row{{"10.00"}}, assertExpectedSingleValue, "==", ptr("10"), true, false},
+		{"string value", oneCol, []sql.Row{{"abc"}}, assertExpectedSingleValue, "==", ptr("abc"), true, false},
+		{"string value fail", oneCol, []sql.Row{{"abc"}}, assertExpectedSingleValue, "!=", ptr("abc"), false, false},
+		{"null value", oneCol, []sql.Row{{nil}}, assertExpectedSingleValue, "==", nil, true, false},
+		{"null value not equal", oneCol, []sql.Row{{nil}}, assertExpectedSingleValue, "!=", ptr("1"), true, false},
+		{"null value compared", oneCol, []sql.Row{{nil}}, assertExpectedSingleValue, "<", ptr("1"), false, false},
+		{"not a single value", twoCols, []sql.Row{{1, "a"}}, assertExpectedSingleValue, "==", ptr("1"), false, false},
+		{"unknown type", oneCol, nil, "expected_magic", "==", ptr("1"), false, true},
+		{"unknown comparator", oneCol, nil, assertExpectedRows, "=~", ptr("1"), false, true},
+		{"invalid count", oneCol, nil, assertExpectedRows, "==", ptr("one"), false, true},
+		{"missing count", oneCol, nil, assertExpectedRows, "==", nil, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg, err := checkAssertion(test.sch, test.rows, test.typ, test.comparator, test.expected)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if test.pass {
+				assert.Empty(t, msg)
+			} else {
+				assert.NotEmpty(t, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cicmds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+)
+
+var runDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Run the data tests stored in the dolt_tests table",
+	LongDesc: `Runs the tests defined in the {{.EmphasisLeft}}dolt_tests{{.EmphasisRight}} system table and reports which of them passed.
+
+Each row of {{.EmphasisLeft}}dolt_tests{{.EmphasisRight}} defines a test: a {{.EmphasisLeft}}test_query{{.EmphasisRight}} and an assertion about its results. The {{.EmphasisLeft}}assertion_type{{.EmphasisRight}} is one of {{.EmphasisLeft}}expected_rows{{.EmphasisRight}}, {{.EmphasisLeft}}expected_columns{{.EmphasisRight}} or {{.EmphasisLeft}}expected_single_value{{.EmphasisRight}}, the {{.EmphasisLeft}}assertion_comparator{{.EmphasisRight}} is one of {{.EmphasisLeft}}==, !=, <, <=, >, >={{.EmphasisRight}}, and the {{.EmphasisLeft}}assertion_value{{.EmphasisRight}} is the value the results are compared to. Single values are compared as numbers if both sides are numeric, and as strings otherwise.
+
+Tests are run against the working set by default. Use {{.EmphasisLeft}}--ref{{.EmphasisRight}} to run the tests stored at a branch, tag or commit against that revision instead. If test names are given only those tests are run, and {{.EmphasisLeft}}--group{{.EmphasisRight}} limits the run to the tests of a {{.EmphasisLeft}}test_group{{.EmphasisRight}}.
+
+The command exits with a non-zero status if any test fails. Use {{.EmphasisLeft}}--result-format json{{.EmphasisRight}} for machine-readable results.`,
+	Synopsis: []string{
+		"[--ref {{.LessThan}}ref{{.GreaterThan}}] [--group {{.LessThan}}group{{.GreaterThan}}] [{{.LessThan}}test{{.GreaterThan}}...]",
+	},
+}
+
+const (
+	refParam   = "ref"
+	groupParam = "group"
+
+	testPassed = "PASS"
+	testFailed = "FAIL"
+	testError  = "ERROR"
+)
+
+type RunCmd struct{}
+
+// Name implements cli.Command.
+func (cmd RunCmd) Name() string {
+	return "run"
+}
+
+// Description implements cli.Command.
+func (cmd RunCmd) Description() string {
+	return runDocs.ShortDesc
+}
+
+// RequiresRepo implements cli.Command.
+func (cmd RunCmd) RequiresRepo() bool {
+	return false
+}
+
+// Docs implements cli.Command.
+func (cmd RunCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(runDocs, ap)
+}
+
+// ArgParser implements cli.Command.
+func (cmd RunCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs(cmd.Name())
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"test", "The name of a test to run. All tests are run if none are given."})
+	ap.SupportsString(refParam, "", "ref", "The branch, tag or commit to run the tests of. Defaults to the working set.")
+	ap.SupportsString(groupParam, "g", "group", "Only run the tests in this group.")
+	ap.SupportsString(commands.FormatFlag, "r", "result output format", "How to format the test results. Valid values are default and json. Defaults to default.")
+	return ap
+}
+
+// testResult is the result of running a single test.
+type testResult struct {
+	Name    string `json:"test_name"`
+	Group   string `json:"test_group,omitempty"`
+	Query   string `json:"test_query"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// runResults is the machine-readable output of `dolt ci run`.
+type runResults struct {
+	Passed int          `json:"passed"`
+	Failed int          `json:"failed"`
+	Tests  []testResult `json:"tests"`
+}
+
+// Exec implements cli.Command.
+func (cmd RunCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, runDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	format := apr.GetValueOrDefault(commands.FormatFlag, "default")
+	if format != "default" && format != "json" {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: invalid --%s: %s. Valid values are default and json", commands.FormatFlag, format).Build(), usage)
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	if ref, ok := apr.GetValue(refParam); ok {
+		restore, err := useRevision(queryist, sqlCtx, ref)
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		defer restore()
+	}
+
+	group, hasGroup := apr.GetValue(groupParam)
+	results, err := runTests(queryist, sqlCtx, set.NewStrSet(apr.Args), group, hasGroup)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	if format == "json" {
+		out, err := json.Marshal(results)
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		cli.Println(string(out))
+	} else {
+		printResults(results)
+	}
+
+	if results.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// useRevision changes the current database of the session to the revision database for the commit |ref| resolves to,
+// and returns a function which changes it back.
+func useRevision(queryist cli.Queryist, sqlCtx *sql.Context, ref string) (func(), error) {
+	rows, err := commands.GetRowsForSql(queryist, sqlCtx, "select database()")
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) != 1 || rows[0][0] == nil {
+		return nil, fmt.Errorf("no database selected")
+	}
+	dbName := fmt.Sprint(rows[0][0])
+
+	// A revision database named after a branch uses the branch's working set, so resolve |ref| to a commit first.
+	q, err := dbr.InterpolateForDialect("select hashof(?)", []interface{}{ref}, dialect.MySQL)
+	if err != nil {
+		return nil, err
+	}
+	rows, err = commands.GetRowsForSql(queryist, sqlCtx, q)
+	if err != nil {
+		return nil, err
+	}
+	commitHash := fmt.Sprint(rows[0][0])
+
+	if _, err = commands.GetRowsForSql(queryist, sqlCtx, "use "+quoteIdentifier(dbName+"/"+commitHash)); err != nil {
+		return nil, err
+	}
+	return func() {
+		_, _ = commands.GetRowsForSql(queryist, sqlCtx, "use "+quoteIdentifier(dbName))
+	}, nil
+}
+
+func quoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// RunAllTests runs every test in dolt_tests against the current database of |sqlCtx|. It returns an error naming the
+// tests which didn't pass, if any.
+func RunAllTests(queryist cli.Queryist, sqlCtx *sql.Context) error {
+	results, err := runTests(queryist, sqlCtx, set.NewStrSet(nil), "", false)
+	if err != nil {
+		return err
+	} else if results.Failed == 0 {
+		return nil
+	}
+
+	var failed []string
+	for _, res := range results.Tests {
+		if res.Status != testPassed {
+			failed = append(failed, res.Name)
+		}
+	}
+	return fmt.Errorf("%d of %d tests failed: %s", results.Failed, len(results.Tests), strings.Join(failed, ", "))
+}
+
+// runTests runs the tests in dolt_tests, or only those in |names| if it is not empty, and only those in |group| if
+// |hasGroup| is true.
+func runTests(queryist cli.Queryist, sqlCtx *sql.Context, names *set.StrSet, group string, hasGroup bool) (runResults, error) {
+	query := fmt.Sprintf("select %s, %s, %s, %s, %s, %s from %s order by %s",
+		doltdb.TestsNameCol, doltdb.TestsGroupCol, doltdb.TestsQueryCol, doltdb.TestsAssertionTypeCol,
+		doltdb.TestsAssertionComparatorCol, doltdb.TestsAssertionValueCol, doltdb.TestsTableName, doltdb.TestsNameCol)
+	tests, err := commands.GetRowsForSql(queryist, sqlCtx, query)
+	if err != nil {
+		return runResults{}, err
+	}
+
+	results := runResults{Tests: []testResult{}}
+	for _, test := range tests {
+		res := testResult{
+			Name:  fmt.Sprint(test[0]),
+			Group: optionalString(test[1]),
+			Query: fmt.Sprint(test[2]),
+		}
+		if names.Size() > 0 && !names.Contains(res.Name) {
+			continue
+		}
+		if hasGroup && res.Group != group {
+			continue
+		}
+
+		var expected *string
+		if test[5] != nil {
+			s := valueString(test[5])
+			expected = &s
+		}
+		res.Status, res.Message = runTest(queryist, sqlCtx, res.Query, fmt.Sprint(test[3]), fmt.Sprint(test[4]), expected)
+		if res.Status == testPassed {
+			results.Passed++
+		} else {
+			results.Failed++
+		}
+		results.Tests = append(results.Tests, res)
+	}
+
+	for _, name := range names.AsSortedSlice() {
+		found := false
+		for _, res := range results.Tests {
+			found = found || res.Name == name
+		}
+		if !found {
+			return runResults{}, fmt.Errorf("test '%s' not found", name)
+		}
+	}
+	return results, nil
+}
+
+// runTest runs a single test query and checks its assertion, returning the test's status and a description of any
+// failure.
+func runTest(queryist cli.Queryist, sqlCtx *sql.Context, query, assertionType, comparator string, expected *string) (string, string) {
+	sch, rowIter, _, err := queryist.Query(sqlCtx, query)
+	if err != nil {
+		return testError, err.Error()
+	}
+	rows, err := sql.RowIterToRows(sqlCtx, rowIter)
+	if err != nil {
+		return testError, err.Error()
+	}
+
+	msg, err := checkAssertion(sch, rows, assertionType, comparator, expected)
+	if err != nil {
+		return testError, err.Error()
+	}
+	if msg != "" {
+		return testFailed, msg
+	}
+	return testPassed, ""
+}
+
+func printResults(results runResults) {
+	if len(results.Tests) == 0 {
+		cli.Println("No tests found")
+		return
+	}
+	for _, res := range results.Tests {
+		name := res.Name
+		if res.Group != "" {
+			name = res.Group + "/" + res.Name
+		}
+		if res.Message != "" {
+			cli.Printf("%-5s %s: %s\n", res.Status, name, res.Message)
+		} else {
+			cli.Printf("%-5s %s\n", res.Status, name)
+		}
+	}
+	cli.Printf("\n%d passed, %d failed\n", results.Passed, results.Failed)
+}
+
+func optionalString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return valueString(v)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/events"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/libraries/utils/earl"
 )
 
@@ -104,11 +105,6 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 		return verr
 	}
 
-	dEnv.UserPassConfig, verr = getRemoteUserAndPassConfig(apr)
-	if verr != nil {
-		return verr
-	}
-
 	userDirExists, _ := dEnv.FS.Exists(dir)
 
 	// Check for a valid dolthub url and replace the urlStr with the parsed repoName.
@@ -122,6 +118,12 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 	if err != nil {
 		return errhand.BuildDError("error: '%s' is not valid.", urlStr).Build()
 	}
+
+	dEnv.UserPassConfig, verr = getRemoteUserAndPassConfig(ctx, apr, dEnv.Config, remoteUrl)
+	if verr != nil {
+		return verr
+	}
+
 	var params map[string]string
 	params, verr = parseRemoteArgs(apr, scheme, remoteUrl)
 	if verr != nil {
@@ -243,16 +245,36 @@ func validateAndParseDolthubUrl(urlStr string) (string, bool) {
 	return "", false
 }
 
-func getRemoteUserAndPassConfig(apr *argparser.ArgParseResults) (*creds.DoltCredsForPass, errhand.VerboseError) {
+func getRemoteUserAndPassConfig(ctx context.Context, apr *argparser.ArgParseResults, cfg config.ReadableConfig, remoteUrl string) (*creds.DoltCredsForPass, errhand.VerboseError) {
 	if !apr.Contains(cli.UserFlag) {
 		return nil, nil
 	}
+	user := apr.GetValueOrDefault(cli.UserFlag, "")
 	pass, found := os.LookupEnv(dconfig.EnvDoltRemotePassword)
-	if !found {
-		return nil, errhand.BuildDError("error: must set DOLT_REMOTE_PASSWORD environment variable to use --user param").Build()
+	if found {
+		return &creds.DoltCredsForPass{
+			Username: user,
+			Password: pass,
+		}, nil
+	}
+
+	helper := creds.CredentialHelper(cfg.GetStringOrDefault(config.CredentialHelperKey, ""))
+	if helper == "" {
+		return nil, errhand.BuildDError("error: must set DOLT_REMOTE_PASSWORD environment variable or credential.helper config to use --user param").Build()
+	}
+	u, err := earl.Parse(remoteUrl)
+	if err != nil {
+		return nil, errhand.VerboseErrorFromError(err)
+	}
+	hc, err := helper.Get(ctx, creds.CredentialRequest{Protocol: u.Scheme, Host: u.Hostname(), Path: strings.TrimPrefix(u.Path, "/"), Username: user})
+	if err != nil {
+		return nil, errhand.VerboseErrorFromError(err)
+	}
+	if hc.Username != user || hc.Password == "" {
+		return nil, errhand.BuildDError("error: credential helper did not return a password for user '%s'", user).Build()
 	}
 	return &creds.DoltCredsForPass{
-		Username: apr.GetValueOrDefault(cli.UserFlag, ""),
-		Password: pass,
+		Username: hc.Username,
+		Password: hc.Password,
 	}, nil
 }
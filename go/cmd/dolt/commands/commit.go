@@ -54,7 +54,13 @@ The content to be added can be specified by using dolt add to incrementally \"ad
 
 The log message can be added with the parameter {{.EmphasisLeft}}-m <msg>{{.EmphasisRight}}.  If the {{.LessThan}}-m{{.GreaterThan}} parameter is not provided an editor will be opened where you can review the commit and provide a log message.
 
-The commit timestamp can be modified using the --date parameter.  Dates can be specified in the formats {{.LessThan}}YYYY-MM-DD{{.GreaterThan}}, {{.LessThan}}YYYY-MM-DDTHH:MM:SS{{.GreaterThan}}, or {{.LessThan}}YYYY-MM-DDTHH:MM:SSZ07:00{{.GreaterThan}} (where {{.LessThan}}07:00{{.GreaterThan}} is the time zone offset)."`,
+The commit timestamp can be modified using the --date parameter.  Dates can be specified in the formats {{.LessThan}}YYYY-MM-DD{{.GreaterThan}}, {{.LessThan}}YYYY-MM-DDTHH:MM:SS{{.GreaterThan}}, or {{.LessThan}}YYYY-MM-DDTHH:MM:SSZ07:00{{.GreaterThan}} (where {{.LessThan}}07:00{{.GreaterThan}} is the time zone offset)."
+
+If the repository has an executable {{.EmphasisLeft}}.dolt/hooks/pre-commit{{.EmphasisRight}} hook it is run before the commit is made, and the commit is aborted if it exits with a non-zero status. The hook receives a summary of the changes being committed on stdin, one line per table containing the tab separated table name, diff type, and whether the table's data and schema changed. Use {{.EmphasisLeft}}--no-verify{{.EmphasisRight}} to skip the hook.
+
+Commits are signed with {{.EmphasisLeft}}-S{{.EmphasisRight}}, or by default when the {{.EmphasisLeft}}commit.gpgsign{{.EmphasisRight}} config is {{.EmphasisLeft}}true{{.EmphasisRight}}. The signature is made with GPG using the key in {{.EmphasisLeft}}user.signingkey{{.EmphasisRight}}, or gpg's default key if it's not set. When {{.EmphasisLeft}}gpg.format{{.EmphasisRight}} is {{.EmphasisLeft}}ssh{{.EmphasisRight}}, it's made with ssh-keygen using the SSH key file in {{.EmphasisLeft}}user.signingkey{{.EmphasisRight}}. Signatures are checked with {{.EmphasisLeft}}dolt verify-commit{{.EmphasisRight}}.
+
+Structured metadata, such as ticket or pipeline run ids, is attached to a commit with {{.EmphasisLeft}}--metadata key=value...{{.EmphasisRight}}. It's shown as a JSON object in the {{.EmphasisLeft}}metadata{{.EmphasisRight}} column of {{.EmphasisLeft}}dolt_commits{{.EmphasisRight}}, so that commits can be filtered on it, e.g. {{.EmphasisLeft}}select * from dolt_commits where json_unquote(json_extract(metadata, '$.ticket')) = 'ABC-1'{{.EmphasisRight}}.`,
 	Synopsis: []string{
 		"[options]",
 	},
@@ -73,12 +79,14 @@ func (cmd CommitCmd) Description() string {
 }
 
 func (cmd CommitCmd) Docs() *cli.CommandDocumentation {
-	ap := cli.CreateCommitArgParser()
+	ap := cmd.ArgParser()
 	return cli.NewCommandDocumentation(commitDocs, ap)
 }
 
 func (cmd CommitCmd) ArgParser() *argparser.ArgParser {
-	return cli.CreateCommitArgParser()
+	ap := cli.CreateCommitArgParser()
+	ap.SupportsFlag(cli.NoVerifyFlag, "n", "Do not run the {{.EmphasisLeft}}pre-commit{{.EmphasisRight}} hook.")
+	return ap
 }
 
 func (cmd CommitCmd) RequiresRepo() bool {
@@ -103,7 +111,7 @@ func (cmd CommitCmd) Exec(ctx context.Context, commandStr string, args []string,
 // status code indicating success or failure, as well as a boolean that indicates if the commit was skipped
 // (e.g. because --skip-empty was specified as an argument).
 func performCommit(ctx context.Context, commandStr string, args []string, cliCtx cli.CliContext, temporaryDEnv *env.DoltEnv) (int, bool) {
-	ap := cli.CreateCommitArgParser()
+	ap := CommitCmd{}.ArgParser()
 	apr, usage, terminate, status := ParseArgsOrPrintHelp(ap, commandStr, args, commitDocs)
 	if terminate {
 		return status, false
@@ -129,6 +137,24 @@ func performCommit(ctx context.Context, commandStr string, args []string, cliCtx
 		return 1, false
 	}
 
+	if !apr.Contains(cli.NoVerifyFlag) {
+		if _, ok := clientHookPath(temporaryDEnv, preCommitHook); ok {
+			toRef := "STAGED"
+			if apr.Contains(cli.AllFlag) || apr.Contains(cli.UpperCaseAllFlag) {
+				toRef = "WORKING"
+			}
+			input, err := preCommitHookInput(queryist, sqlCtx, toRef)
+			if err != nil {
+				cli.Println(err.Error())
+				return 1, false
+			}
+			if err = runClientHook(ctx, temporaryDEnv, preCommitHook, nil, input); err != nil {
+				cli.PrintErrln(err.Error())
+				return 1, false
+			}
+		}
+	}
+
 	msg, msgOk := apr.GetValue(cli.MessageArg)
 	if !msgOk {
 		amendStr := ""
@@ -268,6 +294,23 @@ func constructParametrizedDoltCommitQuery(msg string, apr *argparser.ArgParseRes
 		writeToBuffer("--skip-empty")
 	}
 
+	if apr.Contains(cli.GpgSignFlag) {
+		writeToBuffer("--gpg-sign")
+	}
+
+	if apr.Contains(cli.NoGpgSignFlag) {
+		writeToBuffer("--no-gpg-sign")
+	}
+
+	if pairs, ok := apr.GetValueList(cli.MetadataParam); ok {
+		writeToBuffer("--metadata")
+		for _, pair := range pairs {
+			param = true
+			writeToBuffer("?")
+			params = append(params, pair)
+		}
+	}
+
 	buffer.WriteString(")")
 	return buffer.String(), params, nil
 }
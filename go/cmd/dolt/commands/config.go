@@ -51,6 +51,8 @@ Valid configuration variables:
 
 	- creds.add_url - sets the endpoint used to authenticate a client for 'dolt login'.
 
+	- credential.helper - sets an external program which provides credentials for remotes, instead of storing them in the configuration. Dolt runs the helper with the argument 'get' and the protocol, host and username of the remote as key=value lines on stdin, and reads username and password, token, or aws_access_key_id, aws_secret_access_key and aws_session_token lines from its stdout. A helper 'foo' runs 'dolt-credential-foo', a path runs that program, and a helper starting with '!' is run by the shell.
+
 	- doltlab.insecure - boolean flag used to authenticate a client against DoltLab.
 
 	- init.defaultbranch - allows overriding the default branch name e.g. when initializing a new repository.
@@ -63,11 +65,31 @@ Valid configuration variables:
 
 	- user.name - sets email used in the author and committer field of commit objects.
 
+	- user.signingkey - sets the key used to sign commits. For GPG, a key id or user id, which defaults to gpg's default key. For SSH, the path to a private key, or to a public key whose private key is in the ssh-agent.
+
+	- commit.gpgsign - boolean flag which signs every commit made with 'dolt commit' or DOLT_COMMIT() when true.
+
+	- gpg.format - sets the kind of signature made when signing commits, either 'openpgp' (the default) or 'ssh'.
+
+	- gpg.program - sets the program used to make and check GPG signatures instead of 'gpg'.
+
+	- gpg.ssh.allowedsignersfile - sets the file of trusted SSH keys, in the allowed signers format of ssh-keygen, used to check SSH signatures of commits.
+
 	- remotes.default_host - sets default host for authenticating with doltremoteapi.
 
 	- remotes.default_port - sets default port for authenticating with doltremoteapi.
 
+	- remotes.proxy - sets the http, https or socks5 proxy URL, which may include credentials, used to connect to remotesapi remotes such as DoltHub instead of the HTTPS_PROXY environment variable. Hosts in NO_PROXY still bypass the proxy. Set to 'none' to connect directly.
+
+	- remotes.max_concurrent_downloads - limits the number of table file chunk downloads made in parallel when fetching from remotesapi remotes.
+
+	- remotes.max_concurrent_uploads - limits the number of table files uploaded in parallel when pushing to remotesapi remotes.
+
+	- remotes.max_bytes_per_second - limits the bandwidth used for fetch, pull, clone and push to remotesapi remotes. Accepts sizes such as '512KB' or '10MB'.
+
 	- push.autoSetupRemote - if set to "true" assume --set-upstream on default push when no upstream tracking exists for the current branch.
+
+	- pull.rebase - if set to "true" rebase local commits on top of the remote branch with 'dolt pull' or DOLT_PULL() instead of merging it, unless --no-rebase is given.
 `,
 
 	Synopsis: []string{
@@ -97,7 +97,9 @@ The diffs displayed can be limited to show the first N by providing the paramete
 
 To filter which data rows are displayed, use {{.EmphasisLeft}}--where <SQL expression>{{.EmphasisRight}}. Table column names in the filter expression must be prefixed with {{.EmphasisLeft}}from_{{.EmphasisRight}} or {{.EmphasisLeft}}to_{{.EmphasisRight}}, e.g. {{.EmphasisLeft}}to_COLUMN_NAME > 100{{.EmphasisRight}} or {{.EmphasisLeft}}from_COLUMN_NAME + to_COLUMN_NAME = 0{{.EmphasisRight}}.
 
-The {{.EmphasisLeft}}--diff-mode{{.EmphasisRight}} argument controls how modified rows are presented when the format output is set to {{.EmphasisLeft}}tabular{{.EmphasisRight}}. When set to {{.EmphasisLeft}}row{{.EmphasisRight}}, modified rows are presented as old and new rows. When set to {{.EmphasisLeft}}line{{.EmphasisRight}}, modified rows are presented as a single row, and changes are presented using "+" and "-" within the column. When set to {{.EmphasisLeft}}in-place{{.EmphasisRight}}, modified rows are presented as a single row, and changes are presented side-by-side with a color distinction (requires a color-enabled terminal). When set to {{.EmphasisLeft}}context{{.EmphasisRight}}, rows that contain at least one column that spans multiple lines uses {{.EmphasisLeft}}line{{.EmphasisRight}}, while all other rows use {{.EmphasisLeft}}row{{.EmphasisRight}}. The default value is {{.EmphasisLeft}}context{{.EmphasisRight}}.
+To see how many rows were added, deleted and modified in each table instead of the rows themselves, use {{.EmphasisLeft}}--stat{{.EmphasisRight}}.
+
+The {{.EmphasisLeft}}--diff-mode{{.EmphasisRight}} argument controls how modified rows are presented when the format output is set to {{.EmphasisLeft}}tabular{{.EmphasisRight}}. When set to {{.EmphasisLeft}}row{{.EmphasisRight}}, modified rows are presented as old and new rows. When set to {{.EmphasisLeft}}line{{.EmphasisRight}}, modified rows are presented as a single row, and changes are presented using "+" and "-" within the column. When set to {{.EmphasisLeft}}in-place{{.EmphasisRight}}, modified rows are presented as a single row, and changes are presented side-by-side with a color distinction (requires a color-enabled terminal). When set to {{.EmphasisLeft}}word{{.EmphasisRight}}, modified rows are presented as a single row, and changes within each column are computed word by word, with removed words shown as {{.EmphasisLeft}}[-removed-]{{.EmphasisRight}} and added words shown as {{.EmphasisLeft}}{+added+}{{.EmphasisRight}}. This is useful for reviewing edits to long text or json values. When set to {{.EmphasisLeft}}context{{.EmphasisRight}}, rows that contain at least one column that spans multiple lines uses {{.EmphasisLeft}}line{{.EmphasisRight}}, while all other rows use {{.EmphasisLeft}}row{{.EmphasisRight}}. The default value is {{.EmphasisLeft}}context{{.EmphasisRight}}.
 `,
 	Synopsis: []string{
 		`[options] [{{.LessThan}}commit{{.GreaterThan}}] [{{.LessThan}}tables{{.GreaterThan}}...]`,
@@ -174,7 +176,7 @@ func (cmd DiffCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(cli.CachedFlag, "c", "Show only the staged data changes.")
 	ap.SupportsFlag(SkinnyFlag, "sk", "Shows only primary key columns and any columns with data changes.")
 	ap.SupportsFlag(MergeBase, "", "Uses merge base of the first commit and second commit (or HEAD if not supplied) as the first commit")
-	ap.SupportsString(DiffMode, "", "diff mode", "Determines how to display modified rows with tabular output. Valid values are row, line, in-place, context. Defaults to context.")
+	ap.SupportsString(DiffMode, "", "diff mode", "Determines how to display modified rows with tabular output. Valid values are row, line, in-place, word, context. Defaults to context.")
 	ap.SupportsFlag(ReverseFlag, "R", "Reverses the direction of the diff.")
 	ap.SupportsFlag(NameOnlyFlag, "", "Only shows table names.")
 	return ap
@@ -266,6 +268,8 @@ func parseDiffDisplaySettings(apr *argparser.ArgParseResults) *diffDisplaySettin
 			displaySettings.diffMode = diff.ModeLine
 		case "in-place":
 			displaySettings.diffMode = diff.ModeInPlace
+		case "word":
+			displaySettings.diffMode = diff.ModeWord
 		case "context":
 			displaySettings.diffMode = diff.ModeContext
 		}
@@ -579,7 +583,21 @@ var diffSummarySchema = sql.Schema{
 	&sql.Column{Name: "Schema change", Type: types.Boolean, Nullable: false},
 }
 
+// diffSummaryJson is the machine-readable representation of a table in dolt diff --summary output.
+type diffSummaryJson struct {
+	TableName     string `json:"table_name"`
+	FromTableName string `json:"from_table_name"`
+	ToTableName   string `json:"to_table_name"`
+	DiffType      string `json:"diff_type"`
+	DataChange    bool   `json:"data_change"`
+	SchemaChange  bool   `json:"schema_change"`
+}
+
 func printDiffSummary(ctx context.Context, diffSummaries []diff.TableDeltaSummary, dArgs *diffArgs) errhand.VerboseError {
+	if dArgs.diffOutput == JsonDiffOutput {
+		return printDiffSummaryJson(diffSummaries, dArgs)
+	}
+
 	cliWR := iohelp.NopWrCloser(cli.OutStream)
 	wr := tabular.NewFixedWidthTableWriter(diffSummarySchema, cliWR, 100)
 	defer wr.Close(ctx)
@@ -589,7 +607,7 @@ func printDiffSummary(ctx context.Context, diffSummaries []diff.TableDeltaSummar
 		// TODO: schema name
 		shouldPrintTables := dArgs.tableSet.Contains(diffSummary.FromTableName.Name) || dArgs.tableSet.Contains(diffSummary.ToTableName.Name)
 		if !shouldPrintTables {
-			return nil
+			continue
 		}
 
 		tableName := diffSummary.TableName.Name
@@ -606,6 +624,32 @@ func printDiffSummary(ctx context.Context, diffSummaries []diff.TableDeltaSummar
 	return nil
 }
 
+func printDiffSummaryJson(diffSummaries []diff.TableDeltaSummary, dArgs *diffArgs) errhand.VerboseError {
+	res := make([]diffSummaryJson, 0, len(diffSummaries))
+	for _, diffSummary := range diffSummaries {
+		// TODO: schema name
+		if !dArgs.tableSet.Contains(diffSummary.FromTableName.Name) && !dArgs.tableSet.Contains(diffSummary.ToTableName.Name) {
+			continue
+		}
+		res = append(res, diffSummaryJson{
+			TableName:     diffSummary.TableName.Name,
+			FromTableName: diffSummary.FromTableName.Name,
+			ToTableName:   diffSummary.ToTableName.Name,
+			DiffType:      diffSummary.DiffType,
+			DataChange:    diffSummary.DataChange,
+			SchemaChange:  diffSummary.SchemaChange,
+		})
+	}
+
+	err := printJsonResult(struct {
+		Tables []diffSummaryJson `json:"tables"`
+	}{res})
+	if err != nil {
+		return errhand.BuildDError("could not write table delta summary").AddCause(err).Build()
+	}
+	return nil
+}
+
 func getDeltasBetweenRefs(queryist cli.Queryist, sqlCtx *sql.Context, fromRef, toRef string) ([]diff.TableDeltaSummary, error) {
 	diffSummaries, err := getDiffSummariesBetweenRefs(queryist, sqlCtx, fromRef, toRef)
 	if err != nil {
@@ -16,6 +16,7 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -26,6 +27,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/plan"
 	"github.com/dolthub/go-mysql-server/sql/planbuilder"
 	"github.com/fatih/color"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
@@ -52,6 +54,7 @@ const (
 	noAutocommitFlag = "no-autocommit"
 	schemaOnlyFlag   = "schema-only"
 	noCreateDbFlag   = "no-create-db"
+	parallelFlag     = "parallel"
 
 	sqlFileExt     = "sql"
 	csvFileExt     = "csv"
@@ -59,6 +62,12 @@ const (
 	parquetFileExt = "parquet"
 	emptyFileExt   = ""
 	emptyStr       = ""
+
+	// dumpManifestFile is the file listing the files of a --parallel dump, written to its directory once it's complete
+	dumpManifestFile = "manifest.json"
+	// dumpSchemaElementsFile is the file of a --parallel sql dump holding views, triggers and procedures. System
+	// tables aren't dumped, so no table's file can have this name.
+	dumpSchemaElementsFile = "dolt_schema_elements.sql"
 )
 
 var dumpDocs = cli.CommandDocumentationContent{
@@ -68,10 +77,18 @@ If a dump file already exists then the operation will fail, unless the {{.Emphas
 is provided. The force flag forces the existing dump file to be overwritten. The {{.EmphasisLeft}}-r{{.EmphasisRight}} flag 
 is used to support different file formats of the dump. In the case of non .sql files each table is written to a separate
 csv,json or parquet file. 
+
+With {{.EmphasisLeft}}--parallel{{.EmphasisRight}}, each table is written to its own file in the dump directory in any format, 
+including sql, and that many tables are dumped at once. All the tables are read from a single snapshot of the working set, 
+so the dump is consistent even when a running sql-server commits changes while it's taken. Views, triggers and procedures 
+of sql dumps are written to {{.EmphasisLeft}}dolt_schema_elements.sql{{.EmphasisRight}}, to be loaded after the tables. Once 
+every file is written, a {{.EmphasisLeft}}manifest.json{{.EmphasisRight}} listing the database, the hash of the snapshot 
+and each table's file and number of rows is written to the directory.
 `,
 
 	Synopsis: []string{
 		"[-f] [-r {{.LessThan}}result-format{{.GreaterThan}}] [-fn {{.LessThan}}file_name{{.GreaterThan}}]  [-d {{.LessThan}}directory{{.GreaterThan}}] [--batch] [--no-batch] [--no-autocommit] [--no-create-db] ",
+		"--parallel {{.LessThan}}n{{.GreaterThan}} [-f] [-r {{.LessThan}}result-format{{.GreaterThan}}] [-d {{.LessThan}}directory{{.GreaterThan}}] [--no-create-db] [--schema-only]",
 	},
 }
 
@@ -104,6 +121,7 @@ func (cmd DumpCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(noAutocommitFlag, "na", "Turn off autocommit for each dumped table. Useful for speeding up loading of output SQL file.")
 	ap.SupportsFlag(schemaOnlyFlag, "", "Dump a table's schema, without including any data, to the output SQL file.")
 	ap.SupportsFlag(noCreateDbFlag, "", "Do not write `CREATE DATABASE` statements in SQL files.")
+	ap.SupportsInt(parallelFlag, "", "n", "Dump each table to its own file in the dump directory, `n` tables at a time, from a single snapshot of the database.")
 	return ap
 }
 
@@ -142,6 +160,16 @@ func (cmd DumpCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		return HandleVErrAndExitCode(vErr, usage)
 	}
 
+	if apr.Contains(parallelFlag) {
+		workers, _ := apr.GetInt(parallelFlag)
+		vErr = dumpSnapshot(ctx, dEnv, root, apr, tblNames, resFormat, outputFileOrDirName, workers)
+		if vErr != nil {
+			return HandleVErrAndExitCode(vErr, usage)
+		}
+		cli.PrintErrln(color.CyanString("Successfully exported data."))
+		return 0
+	}
+
 	switch resFormat {
 	case emptyFileExt, sqlFileExt:
 		var defaultName string
@@ -189,7 +217,7 @@ func (cmd DumpCmd) Exec(ctx context.Context, commandStr string, args []string, d
 			}
 		}
 
-		err = dumpSchemaElements(ctx, dEnv, fPath)
+		err = dumpSchemaElements(ctx, dEnv, root, fPath)
 		if err != nil {
 			return HandleVErrAndExitCode(err, usage)
 		}
@@ -207,8 +235,8 @@ func (cmd DumpCmd) Exec(ctx context.Context, commandStr string, args []string, d
 	return 0
 }
 
-// dumpSchemaElements writes the non-table schema elements (views, triggers, procedures) to the file path given
-func dumpSchemaElements(ctx context.Context, dEnv *env.DoltEnv, path string) errhand.VerboseError {
+// dumpSchemaElements writes the non-table schema elements (views, triggers, procedures) in |root| to the file path given
+func dumpSchemaElements(ctx context.Context, dEnv *env.DoltEnv, root doltdb.RootValue, path string) errhand.VerboseError {
 	writer, err := dEnv.FS.OpenForWriteAppend(path, os.ModePerm)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
@@ -225,22 +253,17 @@ func dumpSchemaElements(ctx context.Context, dEnv *env.DoltEnv, path string) err
 	}
 	sqlCtx.SetCurrentDatabase(dbName)
 
-	root, err := dEnv.WorkingRoot(ctx)
-	if err != nil {
-		return errhand.VerboseErrorFromError(err)
-	}
-
 	err = dumpViews(sqlCtx, engine, root, writer)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
 
-	err = dumpTriggers(sqlCtx, engine, root, writer)
+	err = dumpTriggers(sqlCtx, root, writer)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
 
-	err = dumpProcedures(sqlCtx, engine, root, writer)
+	err = dumpProcedures(sqlCtx, root, writer)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
@@ -253,8 +276,8 @@ func dumpSchemaElements(ctx context.Context, dEnv *env.DoltEnv, path string) err
 	return nil
 }
 
-func dumpProcedures(sqlCtx *sql.Context, engine *engine.SqlEngine, root doltdb.RootValue, writer io.WriteCloser) (rerr error) {
-	_, _, ok, err := doltdb.GetTableInsensitive(sqlCtx, root, doltdb.TableName{Name: doltdb.ProceduresTableName})
+func dumpProcedures(sqlCtx *sql.Context, root doltdb.RootValue, writer io.WriteCloser) (rerr error) {
+	_, tblName, ok, err := doltdb.GetTableInsensitive(sqlCtx, root, doltdb.TableName{Name: doltdb.ProceduresTableName})
 	if err != nil {
 		return err
 	}
@@ -263,24 +286,25 @@ func dumpProcedures(sqlCtx *sql.Context, engine *engine.SqlEngine, root doltdb.R
 		return nil
 	}
 
-	sch, iter, _, err := engine.Query(sqlCtx, "select * from "+doltdb.ProceduresTableName)
+	rd, err := mvdata.NewRootTableReader(sqlCtx, root, tblName)
 	if err != nil {
 		return err
 	}
 
-	stmtColIdx := sch.IndexOfColName(doltdb.ProceduresTableCreateStmtCol)
+	cols := rd.GetSchema().GetAllCols()
+	stmtColIdx := cols.IndexOf(doltdb.ProceduresTableCreateStmtCol)
 	// Note: 'sql_mode' column of `dolt_procedures` table is not present in databases that were created before this column got added
-	sqlModeIdx := sch.IndexOfColName(doltdb.ProceduresTableSqlModeCol)
+	sqlModeIdx := cols.IndexOf(doltdb.ProceduresTableSqlModeCol)
 
-	defer func(iter sql.RowIter, context *sql.Context) {
-		err := iter.Close(context)
+	defer func() {
+		err := rd.Close(sqlCtx)
 		if rerr == nil && err != nil {
 			rerr = err
 		}
-	}(iter, sqlCtx)
+	}()
 
 	for {
-		row, err := iter.Next(sqlCtx)
+		row, err := rd.ReadSqlRow(sqlCtx)
 		if err == io.EOF {
 			break
 		} else if err != nil {
@@ -324,8 +348,8 @@ func dumpProcedures(sqlCtx *sql.Context, engine *engine.SqlEngine, root doltdb.R
 	return nil
 }
 
-func dumpTriggers(sqlCtx *sql.Context, engine *engine.SqlEngine, root doltdb.RootValue, writer io.WriteCloser) (rerr error) {
-	_, _, ok, err := doltdb.GetTableInsensitive(sqlCtx, root, doltdb.TableName{Name: doltdb.SchemasTableName})
+func dumpTriggers(sqlCtx *sql.Context, root doltdb.RootValue, writer io.WriteCloser) (rerr error) {
+	_, tblName, ok, err := doltdb.GetTableInsensitive(sqlCtx, root, doltdb.TableName{Name: doltdb.SchemasTableName})
 	if err != nil {
 		return err
 	}
@@ -334,25 +358,26 @@ func dumpTriggers(sqlCtx *sql.Context, engine *engine.SqlEngine, root doltdb.Roo
 		return nil
 	}
 
-	sch, iter, _, err := engine.Query(sqlCtx, "select * from "+doltdb.SchemasTableName)
+	rd, err := mvdata.NewRootTableReader(sqlCtx, root, tblName)
 	if err != nil {
 		return err
 	}
 
-	typeColIdx := sch.IndexOfColName(doltdb.SchemasTablesTypeCol)
-	fragColIdx := sch.IndexOfColName(doltdb.SchemasTablesFragmentCol)
+	cols := rd.GetSchema().GetAllCols()
+	typeColIdx := cols.IndexOf(doltdb.SchemasTablesTypeCol)
+	fragColIdx := cols.IndexOf(doltdb.SchemasTablesFragmentCol)
 	// Note: some columns of `dolt_schemas` table are not present in databases that were created before those columns got added
-	sqlModeIdx := sch.IndexOfColName(doltdb.SchemasTablesSqlModeCol)
+	sqlModeIdx := cols.IndexOf(doltdb.SchemasTablesSqlModeCol)
 
-	defer func(iter sql.RowIter, context *sql.Context) {
-		err := iter.Close(context)
+	defer func() {
+		err := rd.Close(sqlCtx)
 		if rerr == nil && err != nil {
 			rerr = err
 		}
-	}(iter, sqlCtx)
+	}()
 
 	for {
-		row, err := iter.Next(sqlCtx)
+		row, err := rd.ReadSqlRow(sqlCtx)
 		if err == io.EOF {
 			break
 		} else if err != nil {
@@ -391,7 +416,7 @@ func dumpTriggers(sqlCtx *sql.Context, engine *engine.SqlEngine, root doltdb.Roo
 }
 
 func dumpViews(ctx *sql.Context, engine *engine.SqlEngine, root doltdb.RootValue, writer io.WriteCloser) (rerr error) {
-	_, _, ok, err := doltdb.GetTableInsensitive(ctx, root, doltdb.TableName{Name: doltdb.SchemasTableName})
+	_, tblName, ok, err := doltdb.GetTableInsensitive(ctx, root, doltdb.TableName{Name: doltdb.SchemasTableName})
 	if err != nil {
 		return err
 	}
@@ -400,26 +425,27 @@ func dumpViews(ctx *sql.Context, engine *engine.SqlEngine, root doltdb.RootValue
 		return nil
 	}
 
-	sch, iter, _, err := engine.Query(ctx, "select * from "+doltdb.SchemasTableName)
+	rd, err := mvdata.NewRootTableReader(ctx, root, tblName)
 	if err != nil {
 		return err
 	}
 
-	typeColIdx := sch.IndexOfColName(doltdb.SchemasTablesTypeCol)
-	fragColIdx := sch.IndexOfColName(doltdb.SchemasTablesFragmentCol)
-	nameColIdx := sch.IndexOfColName(doltdb.SchemasTablesNameCol)
+	cols := rd.GetSchema().GetAllCols()
+	typeColIdx := cols.IndexOf(doltdb.SchemasTablesTypeCol)
+	fragColIdx := cols.IndexOf(doltdb.SchemasTablesFragmentCol)
+	nameColIdx := cols.IndexOf(doltdb.SchemasTablesNameCol)
 	// Note: some columns of `dolt_schemas` table are not present in databases that were created before those columns got added
-	sqlModeIdx := sch.IndexOfColName(doltdb.SchemasTablesSqlModeCol)
+	sqlModeIdx := cols.IndexOf(doltdb.SchemasTablesSqlModeCol)
 
-	defer func(iter sql.RowIter, context *sql.Context) {
-		err := iter.Close(context)
+	defer func() {
+		err := rd.Close(ctx)
 		if rerr == nil && err != nil {
 			rerr = err
 		}
-	}(iter, ctx)
+	}()
 
 	for {
-		row, err := iter.Next(ctx)
+		row, err := rd.ReadSqlRow(ctx)
 		if err == io.EOF {
 			break
 		} else if err != nil {
@@ -566,11 +592,22 @@ func dumpTable(ctx context.Context, dEnv *env.DoltEnv, tblOpts *tableOptions, fi
 		return errhand.BuildDError("Error creating reader for %s.", tblOpts.SrcName()).AddCause(err).Build()
 	}
 
-	wr, err := getTableWriter(ctx, dEnv, tblOpts, rd.GetSchema(), filePath)
+	root, err := dEnv.WorkingRoot(ctx)
 	if err != nil {
-		return errhand.BuildDError("Error creating writer for %s.", tblOpts.SrcName()).AddCause(err).Build()
+		return errhand.BuildDError("Could not create table writer for %s", tblOpts.tableName).AddCause(err).Build()
+	}
+
+	return dumpTableRows(ctx, dEnv, root, rd, tblOpts, filePath)
+}
+
+// dumpTableRows dumps the rows read from |rd| to the file given, writing the table's schema as it is in |root|
+func dumpTableRows(ctx context.Context, dEnv *env.DoltEnv, root doltdb.RootValue, rd table.SqlRowReader, tblOpts *tableOptions, filePath string) errhand.VerboseError {
+	wr, verr := getTableWriter(ctx, dEnv, root, tblOpts, rd.GetSchema(), filePath)
+	if verr != nil {
+		return errhand.BuildDError("Error creating writer for %s.", tblOpts.SrcName()).AddCause(verr).Build()
 	}
 
+	var err error
 	if tblOpts.schemaOnly {
 		// table schema can be exported to only sql file.
 		if sqlExpWr, ok := wr.(*sqlexport.SqlExportWriter); ok {
@@ -578,6 +615,7 @@ func dumpTable(ctx context.Context, dEnv *env.DoltEnv, tblOpts *tableOptions, fi
 		} else {
 			err = errhand.BuildDError("Cannot export table schemas to non-sql output file").Build()
 		}
+		_ = rd.Close(ctx)
 	} else {
 		pipeline := mvdata.NewDataMoverPipeline(ctx, rd, wr)
 		err = pipeline.Execute()
@@ -590,7 +628,7 @@ func dumpTable(ctx context.Context, dEnv *env.DoltEnv, tblOpts *tableOptions, fi
 	return nil
 }
 
-func getTableWriter(ctx context.Context, dEnv *env.DoltEnv, tblOpts *tableOptions, outSch schema.Schema, filePath string) (table.SqlRowWriter, errhand.VerboseError) {
+func getTableWriter(ctx context.Context, dEnv *env.DoltEnv, root doltdb.RootValue, tblOpts *tableOptions, outSch schema.Schema, filePath string) (table.SqlRowWriter, errhand.VerboseError) {
 	tmpDir, err := dEnv.TempTableFilesDir()
 	if err != nil {
 		return nil, errhand.BuildDError("error: ").AddCause(err).Build()
@@ -602,11 +640,6 @@ func getTableWriter(ctx context.Context, dEnv *env.DoltEnv, tblOpts *tableOption
 		return nil, errhand.BuildDError("Error opening writer for %s.", tblOpts.DestName()).AddCause(err).Build()
 	}
 
-	root, err := dEnv.WorkingRoot(ctx)
-	if err != nil {
-		return nil, errhand.BuildDError("Could not create table writer for %s", tblOpts.tableName).AddCause(err).Build()
-	}
-
 	wr, err := tblOpts.dest.NewCreatingWriter(ctx, tblOpts, root, outSch, opts, writer)
 	if err != nil {
 		return nil, errhand.BuildDError("Could not create table writer for %s", tblOpts.tableName).AddCause(err).Build()
@@ -687,14 +720,26 @@ func validateDumpArgs(apr *argparser.ArgParseResults) (string, errhand.VerboseEr
 	fn, fnOk := apr.GetValue(filenameFlag)
 	dn, dnOk := apr.GetValue(directoryFlag)
 	snOk := apr.Contains(schemaOnlyFlag)
+	parallel := apr.Contains(parallelFlag)
 
 	if fnOk && dnOk {
 		return emptyStr, errhand.BuildDError("cannot pass both directory and file names").SetPrintUsage().Build()
 	}
+	if parallel {
+		if n, _ := apr.GetInt(parallelFlag); n < 1 {
+			return emptyStr, errhand.BuildDError("%s must be at least 1", parallelFlag).SetPrintUsage().Build()
+		}
+		if fnOk {
+			return emptyStr, errhand.BuildDError("%s is not supported with %s, each table is dumped to a file in the dump directory", filenameFlag, parallelFlag).SetPrintUsage().Build()
+		}
+	}
 	switch rf {
 	case emptyFileExt, sqlFileExt:
+		if parallel {
+			return dn, nil
+		}
 		if dnOk {
-			return emptyStr, errhand.BuildDError("%s is not supported for %s exports", directoryFlag, sqlFileExt).SetPrintUsage().Build()
+			return emptyStr, errhand.BuildDError("%s is not supported for %s exports without %s", directoryFlag, sqlFileExt, parallelFlag).SetPrintUsage().Build()
 		}
 		return fn, nil
 	case csvFileExt, jsonFileExt, parquetFileExt:
@@ -767,6 +812,138 @@ func dumpNonSqlTables(ctx context.Context, root doltdb.RootValue, dEnv *env.Dolt
 	return nil
 }
 
+// dumpManifest is the manifest.json of a --parallel dump
+type dumpManifest struct {
+	Database string `json:"database"`
+	// RootHash is the hash of the root value the tables were dumped from
+	RootHash string              `json:"root_hash"`
+	Format   string              `json:"format"`
+	Tables   []dumpManifestTable `json:"tables"`
+	// SchemaElements is the file of the views, triggers and procedures of sql dumps
+	SchemaElements string `json:"schema_elements,omitempty"`
+}
+
+type dumpManifestTable struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Rows uint64 `json:"rows"`
+}
+
+// dumpSnapshot dumps each table in |root| to its own file in |dirName|, |workers| tables at a time, followed by the
+// schema elements of sql dumps and the manifest. Every table is read from |root| rather than the working set, so the
+// files are consistent with each other however long the dump takes.
+func dumpSnapshot(ctx context.Context, dEnv *env.DoltEnv, root doltdb.RootValue, apr *argparser.ArgParseResults, tblNames []string, rf string, dirName string, workers int) errhand.VerboseError {
+	if rf == emptyFileExt {
+		rf = sqlFileExt
+	}
+	if dirName == emptyStr {
+		dirName = "doltdump"
+	}
+	force := apr.Contains(forceParam)
+	schemaOnly := apr.Contains(schemaOnlyFlag)
+
+	rootHash, err := root.HashOf()
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	dbName, verr := getActiveDatabaseName(ctx, dEnv)
+	if verr != nil {
+		return verr
+	}
+
+	// every destination is checked before anything is written, so that a failed dump doesn't leave some files replaced
+	manifest := dumpManifest{Database: dbName, RootHash: rootHash.String(), Format: rf}
+	tblOpts := make([]*tableOptions, len(tblNames))
+	for i, tbl := range tblNames {
+		fName := fmt.Sprintf("%s.%s", tbl, rf)
+		dumpOpts := getDumpOptions(filepath.Join(dirName, fName), rf, schemaOnly)
+		if dumpOpts.dest == nil {
+			return errhand.BuildDError("invalid result format").SetPrintUsage().Build()
+		}
+		ow, err := checkOverwrite(ctx, root, dEnv.FS, force, dumpOpts.dest)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		if ow {
+			return errhand.BuildDError("%s already exists. Use -f to overwrite.", dumpOpts.DumpDestName()).Build()
+		}
+		tblOpts[i] = newTableArgs(tbl, dumpOpts.dest, !apr.Contains(noBatchFlag), apr.Contains(noAutocommitFlag), schemaOnly)
+		manifest.Tables = append(manifest.Tables, dumpManifestTable{Name: tbl, File: fName})
+	}
+	if rf == sqlFileExt {
+		manifest.SchemaElements = dumpSchemaElementsFile
+	}
+	for _, fName := range []string{manifest.SchemaElements, dumpManifestFile} {
+		if fName == emptyStr || force {
+			continue
+		}
+		if exists, _ := dEnv.FS.Exists(filepath.Join(dirName, fName)); exists {
+			return errhand.BuildDError("%s already exists. Use -f to overwrite.", filepath.Join(dirName, fName)).Build()
+		}
+	}
+
+	// creates an empty file for |fName|, starting it with the statements of sql dumps which set up the session
+	createFile := func(fName string) (string, errhand.VerboseError) {
+		fPath, verr := checkAndCreateOpenDestFile(ctx, root, dEnv, true, getDumpOptions(filepath.Join(dirName, fName), rf, schemaOnly), filepath.Join(dirName, fName))
+		if verr != nil || rf != sqlFileExt {
+			return fPath, verr
+		}
+		if !apr.Contains(noCreateDbFlag) {
+			if verr = addCreateDatabaseHeader(dEnv, fPath, dbName); verr != nil {
+				return emptyStr, verr
+			}
+		}
+		if verr = addBulkLoadingParadigms(dEnv, fPath); verr != nil {
+			return emptyStr, verr
+		}
+		return fPath, nil
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(workers)
+	for i := range tblOpts {
+		eg.Go(func() error {
+			fPath, verr := createFile(manifest.Tables[i].File)
+			if verr != nil {
+				return verr
+			}
+			rd, err := mvdata.NewRootTableReader(egCtx, root, tblOpts[i].tableName)
+			if err != nil {
+				return errhand.BuildDError("Error creating reader for %s.", tblOpts[i].SrcName()).AddCause(err).Build()
+			}
+			if verr = dumpTableRows(egCtx, dEnv, root, rd, tblOpts[i], fPath); verr != nil {
+				return verr
+			}
+			manifest.Tables[i].Rows = rd.RowsRead()
+			return nil
+		})
+	}
+	if err = eg.Wait(); err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	if manifest.SchemaElements != emptyStr {
+		fPath, verr := createFile(manifest.SchemaElements)
+		if verr != nil {
+			return verr
+		}
+		if verr = dumpSchemaElements(ctx, dEnv, root, fPath); verr != nil {
+			return verr
+		}
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	err = dEnv.FS.WriteFile(filepath.Join(dirName, dumpManifestFile), manifestBytes, os.ModePerm)
+	if err != nil {
+		return errhand.BuildDError("error: failed to write %s", dumpManifestFile).AddCause(err).Build()
+	}
+
+	return nil
+}
+
 // addBulkLoadingParadigms adds statements that are used to expedite dump file ingestion.
 // cc. https://dev.mysql.com/doc/refman/8.0/en/optimizing-innodb-bulk-data-loading.html
 // This includes turning off FOREIGN_KEY_CHECKS and UNIQUE_CHECKS off at the beginning of the file.
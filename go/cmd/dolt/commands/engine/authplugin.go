@@ -0,0 +1,130 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+)
+
+var registeredAuthPlugins = struct {
+	mu      sync.Mutex
+	plugins map[string]mysql_db.PlaintextAuthPlugin
+}{plugins: make(map[string]mysql_db.PlaintextAuthPlugin)}
+
+// RegisterAuthPlugin makes |plugin| available to authenticate users created WITH |name| in every SqlEngine created
+// afterward. It is intended to be called from the init function of packages compiled into custom builds of Dolt.
+// Users authenticated by plaintext plugins send their password in cleartext, so their connections should use TLS.
+func RegisterAuthPlugin(name string, plugin mysql_db.PlaintextAuthPlugin) {
+	registeredAuthPlugins.mu.Lock()
+	defer registeredAuthPlugins.mu.Unlock()
+	registeredAuthPlugins.plugins[name] = plugin
+}
+
+// authPlugins returns the authentication plugins available to an engine created with |config|: the builtin plugins,
+// the plugins registered with RegisterAuthPlugin, and the external plugins configured in |config|, in increasing
+// order of precedence.
+func authPlugins(config *SqlEngineConfig) map[string]mysql_db.PlaintextAuthPlugin {
+	plugins := map[string]mysql_db.PlaintextAuthPlugin{
+		"authentication_dolt_jwt":  NewAuthenticateDoltJWTPlugin(config.JwksConfig),
+		"authentication_dolt_ldap": NewAuthenticateDoltLDAPPlugin(config.LDAPConfig),
+		"authentication_dolt_oidc": NewAuthenticateDoltOIDCPlugin(config.OIDCConfig),
+	}
+	registeredAuthPlugins.mu.Lock()
+	for name, plugin := range registeredAuthPlugins.plugins {
+		plugins[name] = plugin
+	}
+	registeredAuthPlugins.mu.Unlock()
+	for _, pluginConfig := range config.AuthPlugins {
+		plugins[pluginConfig.Name] = NewExternalAuthPlugin(pluginConfig)
+	}
+	return plugins
+}
+
+// ExternalAuthRequest is the JSON request written to the stdin of an external authentication plugin.
+type ExternalAuthRequest struct {
+	Plugin   string `json:"plugin"`
+	User     string `json:"user"`
+	Host     string `json:"host"`
+	Identity string `json:"identity,omitempty"`
+	Password string `json:"password"`
+}
+
+// ExternalAuthResponse is the JSON response an external authentication plugin writes to its stdout. A plugin which
+// exits with a non-zero status fails the authentication, along with its stderr.
+type ExternalAuthResponse struct {
+	Authenticated bool   `json:"authenticated"`
+	Message       string `json:"message,omitempty"`
+}
+
+// externalAuthPlugin authenticates users by running an external command.
+type externalAuthPlugin struct {
+	config servercfg.AuthPluginConfig
+}
+
+func NewExternalAuthPlugin(config servercfg.AuthPluginConfig) mysql_db.PlaintextAuthPlugin {
+	return &externalAuthPlugin{config: config}
+}
+
+func (p *externalAuthPlugin) Authenticate(db *mysql_db.MySQLDb, user string, userEntry *mysql_db.User, pass string) (bool, error) {
+	req, err := json.Marshal(ExternalAuthRequest{
+		Plugin:   p.config.Name,
+		User:     user,
+		Host:     userEntry.Host,
+		Identity: userEntry.Identity,
+		Password: pass,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	timeout := time.Duration(servercfg.DefaultAuthPluginTimeoutMillis) * time.Millisecond
+	if p.config.TimeoutMillis != nil {
+		timeout = time.Duration(*p.config.TimeoutMillis) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.config.Command, p.config.Args...)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return false, fmt.Errorf("%s: timed out after %s", p.config.Name, timeout)
+		}
+		return false, fmt.Errorf("%s: %w: %s", p.config.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp ExternalAuthResponse
+	if err = json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return false, fmt.Errorf("%s: invalid response: %w", p.config.Name, err)
+	}
+	if !resp.Authenticated && resp.Message != "" {
+		return false, fmt.Errorf("%s: %s", p.config.Name, resp.Message)
+	}
+	return resp.Authenticated, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+)
+
+// TestExternalAuthPluginHelper is run as the command of the external plugins in TestExternalAuthPlugin.
+func TestExternalAuthPluginHelper(t *testing.T) {
+	mode := os.Getenv("DOLT_TEST_AUTH_PLUGIN")
+	if mode == "" {
+		return
+	}
+	var req ExternalAuthRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	switch mode {
+	case "check":
+		resp := ExternalAuthResponse{Authenticated: req.User == "alice" && req.Host == "%" && req.Password == "secret"}
+		if !resp.Authenticated && req.User == "bob" {
+			resp.Message = "bob is locked out"
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(resp)
+	case "fail":
+		fmt.Fprintln(os.Stderr, "validator unavailable")
+		os.Exit(1)
+	case "garbage":
+		fmt.Println("not json")
+	case "hang":
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+func externalPluginForTest(t *testing.T, mode string, timeoutMillis uint64) mysql_db.PlaintextAuthPlugin {
+	t.Setenv("DOLT_TEST_AUTH_PLUGIN", mode)
+	return NewExternalAuthPlugin(servercfg.AuthPluginConfig{
+		Name:          "authentication_test",
+		Command:       os.Args[0],
+		Args:          []string{"-test.run=^TestExternalAuthPluginHelper$"},
+		TimeoutMillis: &timeoutMillis,
+	})
+}
+
+func TestExternalAuthPlugin(t *testing.T) {
+	userEntry := func(user string) *mysql_db.User {
+		return &mysql_db.User{User: user, Host: "%"}
+	}
+
+	t.Run("check", func(t *testing.T) {
+		plugin := externalPluginForTest(t, "check", 10_000)
+		authed, err := plugin.Authenticate(nil, "alice", userEntry("alice"), "secret")
+		require.NoError(t, err)
+		assert.True(t, authed)
+
+		authed, err = plugin.Authenticate(nil, "alice", userEntry("alice"), "wrong")
+		require.NoError(t, err)
+		assert.False(t, authed)
+
+		authed, err = plugin.Authenticate(nil, "bob", userEntry("bob"), "secret")
+		assert.ErrorContains(t, err, "bob is locked out")
+		assert.False(t, authed)
+	})
+	t.Run("command fails", func(t *testing.T) {
+		authed, err := externalPluginForTest(t, "fail", 10_000).Authenticate(nil, "alice", userEntry("alice"), "secret")
+		assert.ErrorContains(t, err, "validator unavailable")
+		assert.False(t, authed)
+	})
+	t.Run("invalid response", func(t *testing.T) {
+		authed, err := externalPluginForTest(t, "garbage", 10_000).Authenticate(nil, "alice", userEntry("alice"), "secret")
+		assert.ErrorContains(t, err, "invalid response")
+		assert.False(t, authed)
+	})
+	t.Run("timeout", func(t *testing.T) {
+		authed, err := externalPluginForTest(t, "hang", 100).Authenticate(nil, "alice", userEntry("alice"), "secret")
+		assert.ErrorContains(t, err, "timed out")
+		assert.False(t, authed)
+	})
+}
+
+type constAuthPlugin bool
+
+func (p constAuthPlugin) Authenticate(*mysql_db.MySQLDb, string, *mysql_db.User, string) (bool, error) {
+	return bool(p), nil
+}
+
+func TestAuthPlugins(t *testing.T) {
+	RegisterAuthPlugin("authentication_compiled_in", constAuthPlugin(true))
+	RegisterAuthPlugin("authentication_overridden", constAuthPlugin(true))
+	plugins := authPlugins(&SqlEngineConfig{
+		AuthPlugins: []servercfg.AuthPluginConfig{{Name: "authentication_overridden", Command: "false"}},
+	})
+	assert.Contains(t, plugins, "authentication_dolt_jwt")
+	assert.Contains(t, plugins, "authentication_dolt_ldap")
+	assert.Contains(t, plugins, "authentication_dolt_oidc")
+	assert.Equal(t, constAuthPlugin(true), plugins["authentication_compiled_in"])
+	assert.IsType(t, &externalAuthPlugin{}, plugins["authentication_overridden"])
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+)
+
+// authenticateDoltLDAPPlugin authenticates users by binding to an LDAP server with their password, and maps the
+// groups they are members of to roles.
+type authenticateDoltLDAPPlugin struct {
+	config *servercfg.LDAPConfig
+}
+
+func NewAuthenticateDoltLDAPPlugin(config *servercfg.LDAPConfig) mysql_db.PlaintextAuthPlugin {
+	return &authenticateDoltLDAPPlugin{config: config}
+}
+
+func (p *authenticateDoltLDAPPlugin) Authenticate(db *mysql_db.MySQLDb, user string, userEntry *mysql_db.User, pass string) (bool, error) {
+	groups, err := authenticateLDAP(p.config, user, pass)
+	if err != nil {
+		return false, err
+	}
+	syncMappedRoles(userEntry, p.config.RoleMappings, groups)
+	return true, nil
+}
+
+// authenticateLDAP binds as |user| and returns the names of the groups it is a member of.
+func authenticateLDAP(config *servercfg.LDAPConfig, user, pass string) ([]string, error) {
+	if config == nil {
+		return nil, errors.New("authentication_dolt_ldap: ldap server config not found")
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "ldap" && !config.StartTLS && !config.AllowUnencrypted {
+		return nil, errors.New("authentication_dolt_ldap: refusing to send password over an unencrypted connection")
+	}
+
+	timeout := time.Duration(servercfg.DefaultLDAPTimeoutMillis) * time.Millisecond
+	if config.TimeoutMillis != nil {
+		timeout = time.Duration(*config.TimeoutMillis) * time.Millisecond
+	}
+	tlsConfig := &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: config.InsecureSkipVerify}
+	conn, err := ldap.DialURL(config.URL, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetTimeout(timeout)
+	if config.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	dn := strings.ReplaceAll(config.UserDNTemplate, "{user}", ldap.EscapeDN(user))
+	if err = conn.Bind(dn, pass); err != nil {
+		return nil, err
+	}
+	logrus.Infof("Authenticated with LDAP: %s", dn)
+
+	if config.GroupBaseDN == "" {
+		return nil, nil
+	}
+	memberAttr := config.GroupMemberAttribute
+	if memberAttr == "" {
+		memberAttr = servercfg.DefaultLDAPGroupMemberAttribute
+	}
+	nameAttr := config.GroupNameAttribute
+	if nameAttr == "" {
+		nameAttr = servercfg.DefaultLDAPGroupNameAttribute
+	}
+	filter := fmt.Sprintf("(%s=%s)", ldap.EscapeFilter(memberAttr), ldap.EscapeFilter(dn))
+	req := ldap.NewSearchRequest(config.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, filter, []string{nameAttr}, nil)
+	res, err := conn.Search(req)
+	if err != nil {
+		return nil, err
+	}
+	var groups []string
+	for _, entry := range res.Entries {
+		groups = append(groups, entry.GetAttributeValues(nameAttr)...)
+	}
+	return groups, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+)
+
+func TestLDAPAuthRequiresEncryption(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			conn.Close()
+		}
+	}()
+
+	timeout := uint64(1000)
+	config := &servercfg.LDAPConfig{
+		URL:            "ldap://" + l.Addr().String(),
+		UserDNTemplate: "uid={user},dc=example,dc=com",
+		TimeoutMillis:  &timeout,
+	}
+	_, err = authenticateLDAP(config, "alice", "secret")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unencrypted")
+	assert.Equal(t, int32(0), accepted.Load())
+
+	// the password is only sent unencrypted when that's explicitly allowed
+	config.AllowUnencrypted = true
+	_, err = authenticateLDAP(config, "alice", "secret")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "unencrypted")
+}
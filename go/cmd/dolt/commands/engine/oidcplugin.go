@@ -0,0 +1,147 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/sirupsen/logrus"
+	josejwt "gopkg.in/go-jose/go-jose.v2/jwt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+	"github.com/dolthub/dolt/go/libraries/utils/jwtauth"
+)
+
+// authenticateDoltOIDCPlugin authenticates users with an OIDC ID token given as their password, and maps the groups
+// listed in the token to roles.
+type authenticateDoltOIDCPlugin struct {
+	config *servercfg.OIDCConfig
+
+	mu   sync.Mutex
+	keys jwtauth.KeyProvider
+}
+
+func NewAuthenticateDoltOIDCPlugin(config *servercfg.OIDCConfig) mysql_db.PlaintextAuthPlugin {
+	return &authenticateDoltOIDCPlugin{config: config}
+}
+
+func (p *authenticateDoltOIDCPlugin) Authenticate(db *mysql_db.MySQLDb, user string, userEntry *mysql_db.User, pass string) (bool, error) {
+	if p.config == nil {
+		return false, errors.New("authentication_dolt_oidc: oidc config not found")
+	}
+	keys, err := p.keyProvider()
+	if err != nil {
+		return false, err
+	}
+	groups, err := validateOIDCToken(p.config, keys, user, pass, time.Now())
+	if err != nil {
+		return false, err
+	}
+	syncMappedRoles(userEntry, p.config.RoleMappings, groups)
+	return true, nil
+}
+
+// keyProvider returns the issuer's signing keys, discovering the location of its JWKS the first time it is called if
+// it was not configured.
+func (p *authenticateDoltOIDCPlugin) keyProvider() (jwtauth.KeyProvider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keys != nil {
+		return p.keys, nil
+	}
+	jwksURL := p.config.JwksURL
+	if jwksURL == "" {
+		var err error
+		jwksURL, err = discoverJwksURL(p.config.Issuer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	keys, err := jwtauth.NewFetchedJWKS(jwksURL)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	return keys, nil
+}
+
+// discoverJwksURL reads the jwks_uri from the OpenID provider configuration of |issuer|.
+func discoverJwksURL(issuer string) (string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("authentication_dolt_oidc: fetching openid configuration of %s: %s", issuer, resp.Status)
+	}
+	var providerConfig struct {
+		JwksURI string `json:"jwks_uri"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&providerConfig); err != nil {
+		return "", err
+	}
+	if providerConfig.JwksURI == "" {
+		return "", fmt.Errorf("authentication_dolt_oidc: openid configuration of %s has no jwks_uri", issuer)
+	}
+	return providerConfig.JwksURI, nil
+}
+
+// validateOIDCToken validates that |token| was issued to the configured audience for |user| and returns the groups it
+// lists.
+func validateOIDCToken(config *servercfg.OIDCConfig, keys jwtauth.KeyProvider, user, token string, reqTime time.Time) ([]string, error) {
+	if config.Audience == "" {
+		return nil, errors.New("authentication_dolt_oidc: audience is not configured")
+	}
+	expected := josejwt.Expected{Issuer: config.Issuer, Audience: josejwt.Audience{config.Audience}}
+	var custom map[string]interface{}
+	if _, err := jwtauth.ValidateJWT(token, reqTime, keys, expected, &custom); err != nil {
+		return nil, err
+	}
+
+	usernameClaim := config.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = servercfg.DefaultOIDCUsernameClaim
+	}
+	if username, _ := custom[usernameClaim].(string); username != user {
+		return nil, fmt.Errorf("authentication_dolt_oidc: token claim %s does not match user", usernameClaim)
+	}
+	logrus.Infof("Authenticated with OIDC: %s", user)
+
+	groupsClaim := config.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = servercfg.DefaultOIDCGroupsClaim
+	}
+	var groups []string
+	switch claim := custom[groupsClaim].(type) {
+	case string:
+		groups = append(groups, claim)
+	case []interface{}:
+		for _, g := range claim {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+	return groups, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/go-jose/go-jose.v2"
+	josejwt "gopkg.in/go-jose/go-jose.v2/jwt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+)
+
+func TestOIDCAuth(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwk := jose.JSONWebKey{Key: key.Public(), KeyID: "oidc-key", Algorithm: "RS256", Use: "sig"}
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": srv.URL, "jwks_uri": srv.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{jwk}})
+	})
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, (&jose.SignerOptions{}).WithHeader("kid", "oidc-key"))
+	require.NoError(t, err)
+	now := time.Now()
+	sign := func(claims map[string]interface{}) string {
+		tok, err := josejwt.Signed(signer).Claims(claims).CompactSerialize()
+		require.NoError(t, err)
+		return tok
+	}
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		res := map[string]interface{}{
+			"iss":                srv.URL,
+			"aud":                "dolt",
+			"exp":                now.Add(time.Hour).Unix(),
+			"sub":                "1234",
+			"preferred_username": "alice",
+			"groups":             []string{"dbas", "readers"},
+		}
+		for k, v := range overrides {
+			res[k] = v
+		}
+		return res
+	}
+
+	config := &servercfg.OIDCConfig{
+		Issuer:        srv.URL,
+		Audience:      "dolt",
+		UsernameClaim: "preferred_username",
+	}
+	plugin := NewAuthenticateDoltOIDCPlugin(config).(*authenticateDoltOIDCPlugin)
+	keys, err := plugin.keyProvider()
+	require.NoError(t, err)
+
+	t.Run("valid token", func(t *testing.T) {
+		groups, err := validateOIDCToken(config, keys, "alice", sign(claims(nil)), now)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dbas", "readers"}, groups)
+	})
+	t.Run("single group", func(t *testing.T) {
+		groups, err := validateOIDCToken(config, keys, "alice", sign(claims(map[string]interface{}{"groups": "dbas"})), now)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dbas"}, groups)
+	})
+	t.Run("wrong user", func(t *testing.T) {
+		_, err := validateOIDCToken(config, keys, "bob", sign(claims(nil)), now)
+		assert.Error(t, err)
+	})
+	t.Run("wrong issuer", func(t *testing.T) {
+		_, err := validateOIDCToken(config, keys, "alice", sign(claims(map[string]interface{}{"iss": "https://evil.example"})), now)
+		assert.Error(t, err)
+	})
+	t.Run("wrong audience", func(t *testing.T) {
+		_, err := validateOIDCToken(config, keys, "alice", sign(claims(map[string]interface{}{"aud": "other"})), now)
+		assert.Error(t, err)
+	})
+	t.Run("no audience", func(t *testing.T) {
+		c := claims(nil)
+		delete(c, "aud")
+		_, err := validateOIDCToken(config, keys, "alice", sign(c), now)
+		assert.Error(t, err)
+	})
+	t.Run("audience not configured", func(t *testing.T) {
+		noAudience := *config
+		noAudience.Audience = ""
+		_, err := validateOIDCToken(&noAudience, keys, "alice", sign(claims(nil)), now)
+		assert.Error(t, err)
+	})
+	t.Run("expired", func(t *testing.T) {
+		_, err := validateOIDCToken(config, keys, "alice", sign(claims(nil)), now.Add(2*time.Hour))
+		assert.Error(t, err)
+	})
+	t.Run("not a token", func(t *testing.T) {
+		_, err := validateOIDCToken(config, keys, "alice", "password", now)
+		assert.Error(t, err)
+	})
+	t.Run("unsigned by issuer", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		otherSigner, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: other}, (&jose.SignerOptions{}).WithHeader("kid", "oidc-key"))
+		require.NoError(t, err)
+		tok, err := josejwt.Signed(otherSigner).Claims(claims(nil)).CompactSerialize()
+		require.NoError(t, err)
+		_, err = validateOIDCToken(config, keys, "alice", tok, now)
+		assert.Error(t, err)
+	})
+}
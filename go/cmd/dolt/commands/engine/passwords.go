@@ -0,0 +1,181 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+)
+
+const (
+	nativePasswordPlugin      = "mysql_native_password"
+	cachingSha2PasswordPlugin = "caching_sha2_password"
+)
+
+// ValidatePassword returns whether |password| is the password of |userEntry|, the entry of |user|, checked the way
+// the authentication plugin the user was created with checks it. It's for the clients of protocols which send
+// passwords in cleartext, rather than the scrambles of the MySQL protocol. The roles mapped to the user's groups by
+// the plugin are granted and revoked before it returns.
+func (se *SqlEngine) ValidatePassword(user string, userEntry *mysql_db.User, password string) (bool, error) {
+	ok, err := validatePassword(se.engine.Analyzer.Catalog.MySQLDb, se.authPlugins, user, userEntry, password)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err = se.ApplyMappedRoles(user); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func validatePassword(db *mysql_db.MySQLDb, plugins map[string]mysql_db.PlaintextAuthPlugin, user string, userEntry *mysql_db.User, password string) (bool, error) {
+	switch userEntry.Plugin {
+	case "", nativePasswordPlugin:
+		return subtle.ConstantTimeCompare([]byte(nativePasswordHash(password)), []byte(strings.ToUpper(userEntry.AuthString))) == 1, nil
+	case cachingSha2PasswordPlugin:
+		return validateCachingSha2Password(userEntry.AuthString, password)
+	}
+	plugin, ok := plugins[userEntry.Plugin]
+	if !ok {
+		return false, fmt.Errorf("authentication plugin %s is not supported", userEntry.Plugin)
+	}
+	return plugin.Authenticate(db, user, userEntry, password)
+}
+
+// nativePasswordHash returns the authentication string mysql_native_password stores for |password|.
+func nativePasswordHash(password string) string {
+	if password == "" {
+		return ""
+	}
+	hash := sha1.Sum([]byte(password))
+	hash = sha1.Sum(hash[:])
+	return "*" + strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+const (
+	cachingSha2Prefix     = "$A$"
+	cachingSha2SaltLen    = 20
+	cachingSha2DigestLen  = 43
+	cachingSha2RoundsUnit = 1000
+)
+
+// validateCachingSha2Password returns whether |password| is the password of the caching_sha2_password
+// authentication string |authString|, which is "$A$", the number of thousands of rounds as three hex digits, "$",
+// then a 20 byte salt and the SHA-256 crypt digest of the password.
+func validateCachingSha2Password(authString, password string) (bool, error) {
+	if authString == "" {
+		return password == "", nil
+	}
+	if !strings.HasPrefix(authString, cachingSha2Prefix) || len(authString) != len(cachingSha2Prefix)+4+cachingSha2SaltLen+cachingSha2DigestLen {
+		return false, fmt.Errorf("invalid %s authentication string", cachingSha2PasswordPlugin)
+	}
+	rest := authString[len(cachingSha2Prefix):]
+	count, err := strconv.ParseUint(rest[:3], 16, 16)
+	if err != nil || rest[3] != '$' || count == 0 {
+		return false, fmt.Errorf("invalid %s authentication string", cachingSha2PasswordPlugin)
+	}
+	salt, digest := rest[4:4+cachingSha2SaltLen], rest[4+cachingSha2SaltLen:]
+	computed := sha256Crypt([]byte(password), []byte(salt), int(count)*cachingSha2RoundsUnit)
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(digest)) == 1, nil
+}
+
+// sha256Crypt returns the encoded digest of the SHA-256 crypt algorithm for |password|, |salt| and |rounds|.
+func sha256Crypt(password, salt []byte, rounds int) string {
+	alternate := sha256.New()
+	alternate.Write(password)
+	alternate.Write(salt)
+	alternate.Write(password)
+	altSum := alternate.Sum(nil)
+
+	h := sha256.New()
+	h.Write(password)
+	h.Write(salt)
+	i := len(password)
+	for ; i > sha256.Size; i -= sha256.Size {
+		h.Write(altSum)
+	}
+	h.Write(altSum[:i])
+	for i = len(password); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			h.Write(altSum)
+		} else {
+			h.Write(password)
+		}
+	}
+	sum := h.Sum(nil)
+
+	h = sha256.New()
+	for i = 0; i < len(password); i++ {
+		h.Write(password)
+	}
+	pBytes := repeatToLength(h.Sum(nil), len(password))
+
+	h = sha256.New()
+	for i = 0; i < 16+int(sum[0]); i++ {
+		h.Write(salt)
+	}
+	sBytes := repeatToLength(h.Sum(nil), len(salt))
+
+	for i = 0; i < rounds; i++ {
+		h = sha256.New()
+		if i&1 != 0 {
+			h.Write(pBytes)
+		} else {
+			h.Write(sum)
+		}
+		if i%3 != 0 {
+			h.Write(sBytes)
+		}
+		if i%7 != 0 {
+			h.Write(pBytes)
+		}
+		if i&1 != 0 {
+			h.Write(sum)
+		} else {
+			h.Write(pBytes)
+		}
+		sum = h.Sum(nil)
+	}
+
+	var sb strings.Builder
+	for _, g := range [][3]int{{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14}, {15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29}} {
+		encodeCrypt64(&sb, uint(sum[g[0]])<<16|uint(sum[g[1]])<<8|uint(sum[g[2]]), 4)
+	}
+	encodeCrypt64(&sb, uint(sum[31])<<8|uint(sum[30]), 3)
+	return sb.String()
+}
+
+func repeatToLength(b []byte, n int) []byte {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		out = append(out, b[:min(len(b), n-len(out))]...)
+	}
+	return out
+}
+
+const crypt64Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+func encodeCrypt64(sb *strings.Builder, v uint, n int) {
+	for ; n > 0; n-- {
+		sb.WriteByte(crypt64Alphabet[v&0x3f])
+		v >>= 6
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type passwordPluginForTest struct{}
+
+func (passwordPluginForTest) Authenticate(db *mysql_db.MySQLDb, user string, userEntry *mysql_db.User, pass string) (bool, error) {
+	return user == "alice" && pass == "from plugin", nil
+}
+
+func TestSha256Crypt(t *testing.T) {
+	// digests computed with glibc's crypt()
+	assert.Equal(t, "5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5", sha256Crypt([]byte("Hello world!"), []byte("saltstring"), 5000))
+	assert.Equal(t, "AgVq/ZYRckWGGNyWbbvHY6VZbeMsdlqkuGXPj3rAJKC", sha256Crypt([]byte("a much longer password which is more than thirty two bytes"), []byte("0123456789abcdef"), 5000))
+	assert.Equal(t, "sP9FmVrTEqPcRDE7OxGDY0efugGF1dtCtqYcUsX9wmD", sha256Crypt(nil, []byte("abc"), 1000))
+}
+
+func TestValidatePassword(t *testing.T) {
+	plugins := map[string]mysql_db.PlaintextAuthPlugin{"test_plugin": passwordPluginForTest{}}
+	validate := func(plugin, authString, password string) (bool, error) {
+		return validatePassword(nil, plugins, "alice", &mysql_db.User{User: "alice", Host: "%", Plugin: plugin, AuthString: authString}, password)
+	}
+
+	t.Run("mysql_native_password", func(t *testing.T) {
+		ok, err := validate(nativePasswordPlugin, "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19", "password")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = validate("", "*2470c0c06dee42fd1618bb99005adca2ec9d1e19", "password")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = validate(nativePasswordPlugin, "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19", "wrong")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		ok, err = validate(nativePasswordPlugin, "", "")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = validate(nativePasswordPlugin, "", "password")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("caching_sha2_password", func(t *testing.T) {
+		salt := "01234567890123456789"
+		authString := "$A$005$" + salt + sha256Crypt([]byte("password"), []byte(salt), 5000)
+		ok, err := validate(cachingSha2PasswordPlugin, authString, "password")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = validate(cachingSha2PasswordPlugin, authString, "wrong")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		_, err = validate(cachingSha2PasswordPlugin, "$A$005$tooshort", "password")
+		assert.Error(t, err)
+	})
+
+	t.Run("plugins", func(t *testing.T) {
+		ok, err := validate("test_plugin", "", "from plugin")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = validate("test_plugin", "", "password")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		_, err = validate("no_such_plugin", "", "password")
+		assert.Error(t, err)
+	})
+}
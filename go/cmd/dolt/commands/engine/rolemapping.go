@@ -0,0 +1,145 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+)
+
+// mappedRoles returns the roles of |mappings| which should be granted to a member of |groups|, and those which should
+// be revoked.
+func mappedRoles(mappings []servercfg.RoleMapping, groups []string) (grant, revoke map[string]struct{}) {
+	member := make(map[string]struct{}, len(groups))
+	for _, g := range groups {
+		member[g] = struct{}{}
+	}
+	grant = make(map[string]struct{})
+	revoke = make(map[string]struct{})
+	for _, m := range mappings {
+		if _, ok := member[m.Group]; ok {
+			grant[m.Role] = struct{}{}
+		}
+	}
+	for _, m := range mappings {
+		if _, ok := grant[m.Role]; !ok {
+			revoke[m.Role] = struct{}{}
+		}
+	}
+	return grant, revoke
+}
+
+// pendingRoleSync is a change to the mapped roles of a user who has authenticated, which must be made before a
+// session is created for them.
+type pendingRoleSync struct {
+	host          string
+	grant, revoke map[string]struct{}
+}
+
+// pendingRoleSyncs holds the changes to the mapped roles of users who have authenticated, keyed by user name.
+var pendingRoleSyncs = struct {
+	mu    sync.Mutex
+	syncs map[string]pendingRoleSync
+}{syncs: make(map[string]pendingRoleSync)}
+
+// syncMappedRoles records that |userEntry| must be granted the roles which |mappings| map its |groups| to, and have
+// the mapped roles of groups it is no longer a member of revoked. Roles granted by other means are left alone.
+//
+// Plugins authenticate while holding a read lock on the MySQLDb, under which roles can't be granted, so the changes
+// are made by ApplyMappedRoles, after authentication and before the user's session is created.
+func syncMappedRoles(userEntry *mysql_db.User, mappings []servercfg.RoleMapping, groups []string) {
+	if len(mappings) == 0 {
+		return
+	}
+	grant, revoke := mappedRoles(mappings, groups)
+	pendingRoleSyncs.mu.Lock()
+	defer pendingRoleSyncs.mu.Unlock()
+	pendingRoleSyncs.syncs[userEntry.User] = pendingRoleSync{host: userEntry.Host, grant: grant, revoke: revoke}
+}
+
+// ApplyMappedRoles grants and revokes the mapped roles of |user| found when they last authenticated with a plugin
+// which maps groups to roles. It must be called once a user has authenticated, before a session is created for them,
+// so that none of their statements run with roles of groups they are no longer a member of. The login must fail if
+// it returns an error.
+func (se *SqlEngine) ApplyMappedRoles(user string) error {
+	return applyMappedRoles(se.engine.Analyzer.Catalog.MySQLDb, user)
+}
+
+func applyMappedRoles(db *mysql_db.MySQLDb, user string) error {
+	pendingRoleSyncs.mu.Lock()
+	pending, ok := pendingRoleSyncs.syncs[user]
+	delete(pendingRoleSyncs.syncs, user)
+	pendingRoleSyncs.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	if err := updateRoleEdges(db, user, pending.host, pending.grant, pending.revoke); err != nil {
+		return fmt.Errorf("error updating mapped roles of user '%s'@'%s': %w", user, pending.host, err)
+	}
+	return nil
+}
+
+func updateRoleEdges(db *mysql_db.MySQLDb, user, host string, grant, revoke map[string]struct{}) error {
+	ed := db.Editor()
+	defer ed.Close()
+
+	current := make(map[string]*mysql_db.RoleEdge)
+	for _, edge := range ed.GetToUserRoleEdges(mysql_db.RoleEdgesToKey{ToHost: host, ToUser: user}) {
+		current[edge.FromUser] = edge
+	}
+
+	changed := false
+	for role := range grant {
+		if _, ok := current[role]; ok {
+			continue
+		}
+		roles := ed.GetUsersByUsername(role)
+		if len(roles) != 1 || !roles[0].IsRole {
+			logrus.Warnf("cannot grant mapped role '%s' to user '%s'@'%s': role does not exist", role, user, host)
+			continue
+		}
+		ed.PutRoleEdge(&mysql_db.RoleEdge{
+			FromHost: roles[0].Host,
+			FromUser: roles[0].User,
+			ToHost:   host,
+			ToUser:   user,
+		})
+		changed = true
+	}
+	for role := range revoke {
+		edge, ok := current[role]
+		if !ok {
+			continue
+		}
+		ed.RemoveRoleEdge(mysql_db.RoleEdgesPrimaryKey{
+			FromHost: edge.FromHost,
+			FromUser: edge.FromUser,
+			ToHost:   edge.ToHost,
+			ToUser:   edge.ToUser,
+		})
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return db.Persist(sql.NewEmptyContext(), ed)
+}
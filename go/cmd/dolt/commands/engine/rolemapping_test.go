@@ -0,0 +1,115 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+)
+
+func TestMappedRoles(t *testing.T) {
+	mappings := []servercfg.RoleMapping{
+		{Group: "dbas", Role: "admin"},
+		{Group: "analysts", Role: "reader"},
+		{Group: "dbas", Role: "reader"},
+		{Group: "interns", Role: "intern"},
+	}
+	grant, revoke := mappedRoles(mappings, []string{"dbas", "sales"})
+	assert.Equal(t, map[string]struct{}{"admin": {}, "reader": {}}, grant)
+	assert.Equal(t, map[string]struct{}{"intern": {}}, revoke)
+
+	grant, revoke = mappedRoles(mappings, nil)
+	assert.Empty(t, grant)
+	assert.Equal(t, map[string]struct{}{"admin": {}, "reader": {}, "intern": {}}, revoke)
+}
+
+func TestUpdateRoleEdges(t *testing.T) {
+	db := mysql_db.CreateEmptyMySQLDb()
+	db.SetPersister(&mysql_db.NoopPersister{})
+	ed := db.Editor()
+	ed.PutUser(&mysql_db.User{User: "alice", Host: "%", PrivilegeSet: mysql_db.NewPrivilegeSet()})
+	for _, role := range []string{"admin", "reader", "other"} {
+		ed.PutUser(&mysql_db.User{User: role, Host: "%", IsRole: true, PrivilegeSet: mysql_db.NewPrivilegeSet()})
+	}
+	ed.PutRoleEdge(&mysql_db.RoleEdge{FromHost: "%", FromUser: "admin", ToHost: "%", ToUser: "alice"})
+	ed.PutRoleEdge(&mysql_db.RoleEdge{FromHost: "%", FromUser: "other", ToHost: "%", ToUser: "alice"})
+	ed.Close()
+
+	roles := func() []string {
+		rd := db.Reader()
+		defer rd.Close()
+		var res []string
+		for _, edge := range rd.GetToUserRoleEdges(mysql_db.RoleEdgesToKey{ToHost: "%", ToUser: "alice"}) {
+			res = append(res, edge.FromUser)
+		}
+		sort.Strings(res)
+		return res
+	}
+
+	err := updateRoleEdges(db, "alice", "%", map[string]struct{}{"reader": {}, "missing": {}}, map[string]struct{}{"admin": {}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other", "reader"}, roles())
+
+	err = updateRoleEdges(db, "alice", "%", nil, map[string]struct{}{"reader": {}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, roles())
+}
+
+func TestApplyMappedRoles(t *testing.T) {
+	db := mysql_db.CreateEmptyMySQLDb()
+	db.SetPersister(&mysql_db.NoopPersister{})
+	ed := db.Editor()
+	alice := &mysql_db.User{User: "alice", Host: "%", PrivilegeSet: mysql_db.NewPrivilegeSet()}
+	ed.PutUser(alice)
+	for _, role := range []string{"admin", "reader"} {
+		ed.PutUser(&mysql_db.User{User: role, Host: "%", IsRole: true, PrivilegeSet: mysql_db.NewPrivilegeSet()})
+	}
+	ed.PutRoleEdge(&mysql_db.RoleEdge{FromHost: "%", FromUser: "admin", ToHost: "%", ToUser: "alice"})
+	ed.Close()
+
+	roles := func() []string {
+		rd := db.Reader()
+		defer rd.Close()
+		var res []string
+		for _, edge := range rd.GetToUserRoleEdges(mysql_db.RoleEdgesToKey{ToHost: "%", ToUser: "alice"}) {
+			res = append(res, edge.FromUser)
+		}
+		sort.Strings(res)
+		return res
+	}
+	mappings := []servercfg.RoleMapping{{Group: "dbas", Role: "admin"}, {Group: "analysts", Role: "reader"}}
+
+	// the roles aren't changed until they're applied
+	syncMappedRoles(alice, mappings, []string{"analysts"})
+	assert.Equal(t, []string{"admin"}, roles())
+	require.NoError(t, applyMappedRoles(db, "alice"))
+	assert.Equal(t, []string{"reader"}, roles())
+
+	// they're applied once
+	ed = db.Editor()
+	ed.PutRoleEdge(&mysql_db.RoleEdge{FromHost: "%", FromUser: "admin", ToHost: "%", ToUser: "alice"})
+	ed.Close()
+	require.NoError(t, applyMappedRoles(db, "alice"))
+	assert.Equal(t, []string{"admin", "reader"}, roles())
+
+	// users who didn't authenticate with a plugin which maps roles have nothing to apply
+	require.NoError(t, applyMappedRoles(db, "bob"))
+}
@@ -38,11 +38,19 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/assertions"
 	dblr "github.com/dolthub/dolt/go/libraries/doltcore/sqle/binlogreplication"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cte"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/explainanalyze"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/kvexec"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/memstats"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/mysql_file_handler"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/optimizertrace"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/perfschema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/querycache"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/statsnoms"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/statspro"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
@@ -56,6 +64,10 @@ type SqlEngine struct {
 	contextFactory contextFactory
 	dsessFactory   sessionFactory
 	engine         *gms.Engine
+	digests        *perfschema.StatementDigests
+	bThreads       *sql.BackgroundThreads
+	// authPlugins are the plaintext authentication plugins users can be created with
+	authPlugins map[string]mysql_db.PlaintextAuthPlugin
 }
 
 type sessionFactory func(mysqlSess *sql.BaseSession, pro sql.DatabaseProvider) (*dsess.DoltSession, error)
@@ -76,6 +88,9 @@ type SqlEngineConfig struct {
 	DoltTransactionCommit   bool
 	Bulk                    bool
 	JwksConfig              []servercfg.JwksConfig
+	LDAPConfig              *servercfg.LDAPConfig
+	OIDCConfig              *servercfg.OIDCConfig
+	AuthPlugins             []servercfg.AuthPluginConfig
 	SystemVariables         SystemVariables
 	ClusterController       *cluster.Controller
 	BinlogReplicaController binlogreplication.BinlogReplicaController
@@ -115,6 +130,11 @@ func NewSqlEngine(
 		return nil, err
 	}
 
+	// Monitoring tools only query the performance schema if this is set
+	if err = sql.SystemVariables.AssignValues(map[string]interface{}{"performance_schema": int8(1)}); err != nil {
+		return nil, err
+	}
+
 	err = applySystemVariables(sql.SystemVariables, config.SystemVariables)
 	if err != nil {
 		return nil, err
@@ -128,6 +148,10 @@ func NewSqlEngine(
 		locations = append(locations, nil)
 	}
 
+	statementDigests := perfschema.NewStatementDigests()
+	all = append(all, perfschema.NewDatabase(statementDigests).(dsess.SqlDatabase))
+	locations = append(locations, nil)
+
 	b := env.GetDefaultInitBranch(mrEnv.Config())
 	pro, err := dsqle.NewDoltDatabaseProviderWithDatabases(b, mrEnv.FileSystem(), all, locations)
 	if err != nil {
@@ -149,6 +173,11 @@ func NewSqlEngine(
 		IsReadOnly:     config.IsReadOnly,
 		IsServerLocked: config.IsServerLocked,
 	}).WithBackgroundThreads(bThreads)
+	engine.Parser = dsqle.NewParser(engine.Parser)
+	pro.RegisterFunctions(dfunctions.UserLockFunctions(engine.LS)...)
+	pro.SetPrivilegeChecker(engine.Analyzer.Catalog.MySQLDb)
+	engine.Analyzer.Catalog.InfoSchema = dsqle.NewInformationSchemaDatabase(engine.Analyzer.Catalog.InfoSchema)
+	engine.ProcessList = perfschema.NewProcessList(engine.ProcessList, statementDigests)
 
 	if err := configureBinlogPrimaryController(engine); err != nil {
 		return nil, err
@@ -185,19 +214,26 @@ func NewSqlEngine(
 	// Setup the engine.
 	engine.Analyzer.Catalog.MySQLDb.SetPersister(persister)
 
-	engine.Analyzer.Catalog.MySQLDb.SetPlugins(map[string]mysql_db.PlaintextAuthPlugin{
-		"authentication_dolt_jwt": NewAuthenticateDoltJWTPlugin(config.JwksConfig),
-	})
+	sqlEngine.authPlugins = authPlugins(config)
+	engine.Analyzer.Catalog.MySQLDb.SetPlugins(sqlEngine.authPlugins)
 
 	statsPro := statspro.NewProvider(pro, statsnoms.NewNomsStatsFactory(mrEnv.RemoteDialProvider()))
 	engine.Analyzer.Catalog.StatsProvider = statsPro
 
-	engine.Analyzer.ExecBuilder = rowexec.NewOverrideBuilder(kvexec.Builder{})
+	queryCache := querycache.NewCache()
+	engine.Analyzer.ExecBuilder = rowexec.NewOverrideBuilder(querycache.NewBuilder(cte.NewBuilder(explainanalyze.NewBuilder(kvexec.Builder{})), queryCache))
+	memstats.Register(queryCache.MemorySource())
+	pro.Register(optimizertrace.NewProcedure(engine))
+	pro.Register(memstats.NewProcedure())
+	pro.Register(assertions.NewProcedure(engine))
+	pro.SetAssertionVerifier(assertions.NewVerifier(engine))
 	sessFactory := doltSessionFactory(pro, statsPro, mrEnv.Config(), bcController, config.Autocommit)
 	sqlEngine.provider = pro
 	sqlEngine.contextFactory = sqlContextFactory()
 	sqlEngine.dsessFactory = sessFactory
 	sqlEngine.engine = engine
+	sqlEngine.digests = statementDigests
+	sqlEngine.bThreads = bThreads
 
 	// configuring stats depends on sessionBuilder
 	// sessionBuilder needs ref to statsProv
@@ -205,11 +241,29 @@ func NewSqlEngine(
 		fmt.Fprintln(cli.CliErr, err)
 	}
 
+	if err = pro.StartStorageScrubber(sqlEngine.NewDefaultContext, bThreads); err != nil {
+		return nil, err
+	}
+
+	if err = pro.StartDroppedDatabasePurger(sqlEngine.NewDefaultContext, bThreads); err != nil {
+		return nil, err
+	}
+
 	// Load MySQL Db information
 	if err = engine.Analyzer.Catalog.MySQLDb.LoadData(sql.NewEmptyContext(), data); err != nil {
 		return nil, err
 	}
 
+	// Finish or roll back the commits of any transactions writing several branches which were interrupted, since
+	// those branches can't be committed to until that's done
+	recoverCtx, err := sqlEngine.NewDefaultContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = dsess.RecoverInterruptedCommits(recoverCtx, pro); err != nil {
+		return nil, err
+	}
+
 	if dbg, ok := os.LookupEnv(dconfig.EnvSqlDebugLog); ok && strings.ToLower(dbg) == "true" {
 		engine.Analyzer.Debug = true
 		if verbose, ok := os.LookupEnv(dconfig.EnvSqlDebugLogVerbose); ok && strings.ToLower(verbose) == "true" {
@@ -300,6 +354,36 @@ func (se *SqlEngine) NewLocalContext(ctx context.Context) (*sql.Context, error)
 	return sqlCtx, nil
 }
 
+// NewConnectionContext returns a new |sql.Context| for a single client connection, such as one opened by an embedded
+// database/sql driver. Every connection gets its own DoltSession, so concurrent writers on separate connections get
+// their own transactions and are serialized at commit time exactly as sql-server clients are. If |database| is
+// non-empty it becomes the session's current database, qualified by |branch| if one is given, as if the client had run
+// `USE database/branch`.
+func (se *SqlEngine) NewConnectionContext(ctx context.Context, user, database, branch string) (*sql.Context, error) {
+	sqlCtx, err := se.NewDefaultContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sqlCtx.Session.SetClient(sql.Client{User: user, Address: "%", Capabilities: 0})
+
+	if database == "" {
+		if branch != "" {
+			return nil, fmt.Errorf("cannot select branch '%s' without a database", branch)
+		}
+		return sqlCtx, nil
+	}
+
+	dbName := database
+	if branch != "" {
+		dbName = dsess.RevisionDbName(database, branch)
+	}
+	if _, err = se.provider.Database(sqlCtx, dbName); err != nil {
+		return nil, err
+	}
+	sqlCtx.SetCurrentDatabase(dbName)
+	return sqlCtx, nil
+}
+
 // NewDoltSession creates a new DoltSession from a BaseSession
 func (se *SqlEngine) NewDoltSession(_ context.Context, mysqlSess *sql.BaseSession) (*dsess.DoltSession, error) {
 	return se.dsessFactory(mysqlSess, se.provider)
@@ -319,6 +403,29 @@ func (se *SqlEngine) GetUnderlyingEngine() *gms.Engine {
 	return se.engine
 }
 
+// StartMergeQueue starts doing the merges added to the merge queue of this engine's databases with |merger|.
+func (se *SqlEngine) StartMergeQueue(merger dsqle.MergeQueueMerger) error {
+	pro, ok := se.provider.(*dsqle.DoltDatabaseProvider)
+	if !ok {
+		return nil
+	}
+	return pro.StartMergeQueue(merger, se.bThreads)
+}
+
+// StartEphemeralBranchCleanup starts deleting the ephemeral branches of this engine's databases once they expire.
+func (se *SqlEngine) StartEphemeralBranchCleanup() error {
+	pro, ok := se.provider.(*dsqle.DoltDatabaseProvider)
+	if !ok {
+		return nil
+	}
+	return pro.StartEphemeralBranchCleanup(se.NewDefaultContext, se.bThreads)
+}
+
+// StatementDigests returns the summaries of the statements run by this engine, reported by performance_schema.
+func (se *SqlEngine) StatementDigests() *perfschema.StatementDigests {
+	return se.digests
+}
+
 func (se *SqlEngine) Close() error {
 	if se.engine != nil {
 		return se.engine.Close()
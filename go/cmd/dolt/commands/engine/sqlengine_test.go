@@ -0,0 +1,118 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestNewConnectionContext(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	se, dbName, err := NewSqlEngineForEnv(ctx, dEnv)
+	require.NoError(t, err)
+	defer se.Close()
+
+	setup, err := se.NewConnectionContext(ctx, "root", dbName, "")
+	require.NoError(t, err)
+	runQueries(t, se, setup,
+		"create table t (pk int primary key, conn int)",
+		"call dolt_commit('-Am', 'create t')",
+		"call dolt_branch('feature')",
+	)
+
+	t.Run("branch selection", func(t *testing.T) {
+		feature, err := se.NewConnectionContext(ctx, "root", dbName, "feature")
+		require.NoError(t, err)
+		runQueries(t, se, feature, "insert into t values (-1, -1)", "commit")
+
+		rows := runQuery(t, se, feature, "select active_branch()")
+		assert.Equal(t, []sql.Row{{"feature"}}, rows)
+
+		main, err := se.NewConnectionContext(ctx, "root", dbName, "main")
+		require.NoError(t, err)
+		rows = runQuery(t, se, main, "select count(*) from t")
+		assert.Equal(t, []sql.Row{{int64(0)}}, rows)
+
+		_, err = se.NewConnectionContext(ctx, "root", dbName, "missing")
+		assert.Error(t, err)
+		_, err = se.NewConnectionContext(ctx, "root", "", "feature")
+		assert.Error(t, err)
+	})
+
+	t.Run("concurrent writers", func(t *testing.T) {
+		const conns, rowsPerConn = 8, 25
+		eg, egCtx := errgroup.WithContext(ctx)
+		for i := 0; i < conns; i++ {
+			i := i
+			eg.Go(func() error {
+				sqlCtx, err := se.NewConnectionContext(egCtx, "root", dbName, "main")
+				if err != nil {
+					return err
+				}
+				for j := 0; j < rowsPerConn; j++ {
+					q := fmt.Sprintf("insert into t values (%d, %d)", i*rowsPerConn+j, i)
+					if err = execQuery(se, sqlCtx, q); err != nil {
+						return err
+					}
+					if err = execQuery(se, sqlCtx, "commit"); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+		require.NoError(t, eg.Wait())
+
+		sqlCtx, err := se.NewConnectionContext(ctx, "root", dbName, "main")
+		require.NoError(t, err)
+		rows := runQuery(t, se, sqlCtx, "select count(*), count(distinct conn) from t")
+		assert.Equal(t, []sql.Row{{int64(conns * rowsPerConn), int64(conns)}}, rows)
+	})
+}
+
+func runQueries(t *testing.T, se *SqlEngine, sqlCtx *sql.Context, queries ...string) {
+	for _, q := range queries {
+		require.NoError(t, execQuery(se, sqlCtx, q), q)
+	}
+}
+
+func runQuery(t *testing.T, se *SqlEngine, sqlCtx *sql.Context, query string) []sql.Row {
+	_, iter, _, err := se.Query(sqlCtx, query)
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(sqlCtx, iter)
+	require.NoError(t, err)
+	return rows
+}
+
+func execQuery(se *SqlEngine, sqlCtx *sql.Context, query string) error {
+	_, iter, _, err := se.Query(sqlCtx, query)
+	if err != nil {
+		return err
+	}
+	_, err = sql.RowIterToRows(sqlCtx, iter)
+	return err
+}
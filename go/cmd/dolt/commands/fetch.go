@@ -37,6 +37,10 @@ var fetchDocs = cli.CommandDocumentationContent{
 By default dolt will attempt to fetch from a remote named {{.EmphasisLeft}}origin{{.EmphasisRight}}.  The {{.LessThan}}remote{{.GreaterThan}} parameter allows you to specify the name of a different remote you wish to pull from by the remote's name.
 
 When no refspec(s) are specified on the command line, the fetch_specs for the default remote are used.
+
+Tags which point to fetched commits are fetched along with them. With {{.EmphasisLeft}}--tags{{.EmphasisRight}}, every tag in the remote is fetched, along with the commits it points to.
+
+With {{.EmphasisLeft}}--notes{{.EmphasisRight}}, every notes ref in the remote is fetched, and the local notes ref with the same name is fast-forwarded to it. The fetch fails if a local notes ref has diverged from the remote one. See {{.EmphasisLeft}}dolt notes{{.EmphasisRight}}.
 `,
 
 	Synopsis: []string{
@@ -145,6 +149,12 @@ func constructInterpolatedDoltFetchQuery(apr *argparser.ArgParseResults) (string
 	if apr.Contains(cli.PruneFlag) {
 		args = append(args, "'--prune'")
 	}
+	if apr.Contains(cli.TagsFlag) {
+		args = append(args, "'--tags'")
+	}
+	if apr.Contains(cli.NotesFlag) {
+		args = append(args, "'--notes'")
+	}
 	if user, hasUser := apr.GetValue(cli.UserFlag); hasUser {
 		args = append(args, "'--user'")
 		args = append(args, "?")
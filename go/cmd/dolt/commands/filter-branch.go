@@ -16,8 +16,10 @@ package commands
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
 	"io"
+	"os"
 	"runtime"
 	"strings"
 
@@ -44,6 +46,7 @@ const (
 	filterDbName    = "filterDB"
 	branchesFlag    = "branches"
 	uncommittedFlag = "apply-to-uncommitted"
+	commitMapFlag   = "commit-map"
 )
 
 var filterBranchDocs = cli.CommandDocumentationContent{
@@ -55,10 +58,12 @@ If a {{.LessThan}}commit-spec{{.GreaterThan}} is provided, the traversal will st
 If the {{.EmphasisLeft}}--branches{{.EmphasisRight}} flag is supplied, filter-branch traverses and rewrites commits for all branches.
 
 If the {{.EmphasisLeft}}--all{{.EmphasisRight}} flag is supplied, filter-branch traverses and rewrites commits for all branches and tags.
+
+Each branch or tag which was rewritten is listed once filter-branch completes. If {{.EmphasisLeft}}--commit-map{{.EmphasisRight}} is supplied, a CSV file mapping the hash of every rewritten commit to the hash of the commit which replaced it is written to the given path, with parents before their children. Commits which the queries didn't change are left out.
 `,
 
 	Synopsis: []string{
-		"[--all] -q {{.LessThan}}queries{{.GreaterThan}} [{{.LessThan}}commit{{.GreaterThan}}]",
+		"[--all] [--commit-map {{.LessThan}}file{{.GreaterThan}}] -q {{.LessThan}}queries{{.GreaterThan}} [{{.LessThan}}commit{{.GreaterThan}}]",
 	},
 }
 
@@ -87,6 +92,7 @@ func (cmd FilterBranchCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(cli.AllFlag, "a", "filter all branches and tags")
 	ap.SupportsFlag(continueFlag, "c", "log a warning and continue if any errors occur executing statements")
 	ap.SupportsString(QueryFlag, "q", "queries", "Queries to run, separated by semicolons. If not provided, queries are read from STDIN.")
+	ap.SupportsString(commitMapFlag, "", "file", "Write a CSV file mapping the hash of each rewritten commit to the hash of its replacement.")
 	return ap
 }
 
@@ -140,21 +146,60 @@ func (cmd FilterBranchCmd) Exec(ctx context.Context, commandStr string, args []s
 	}
 
 	applyUncommitted := apr.Contains(uncommittedFlag)
+	var result *rebase.FilterBranchResult
 	switch {
 	case apr.Contains(branchesFlag):
-		err = rebase.AllBranches(ctx, dEnv, applyUncommitted, commitReplayer, rootReplayer, nerf)
+		result, err = rebase.AllBranches(ctx, dEnv, applyUncommitted, commitReplayer, rootReplayer, nerf)
 	case apr.Contains(cli.AllFlag):
-		err = rebase.AllBranchesAndTags(ctx, dEnv, applyUncommitted, commitReplayer, rootReplayer, nerf)
+		result, err = rebase.AllBranchesAndTags(ctx, dEnv, applyUncommitted, commitReplayer, rootReplayer, nerf)
 	default:
-		err = rebase.CurrentBranch(ctx, dEnv, applyUncommitted, commitReplayer, rootReplayer, nerf)
+		result, err = rebase.CurrentBranch(ctx, dEnv, applyUncommitted, commitReplayer, rootReplayer, nerf)
 	}
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
+	for _, r := range result.RewrittenRefs {
+		cli.Printf("Ref '%s' was rewritten\n", r.String())
+	}
+
+	if commitMapPath, ok := apr.GetValue(commitMapFlag); ok {
+		err = writeCommitMap(dEnv, commitMapPath, result.RewrittenCommits)
+		if err != nil {
+			verr := errhand.BuildDError("error: failed to write commit map to %s", commitMapPath).AddCause(err).Build()
+			return HandleVErrAndExitCode(verr, usage)
+		}
+	}
+
 	return 0
 }
 
+// writeCommitMap writes |rewritten| to a CSV file at |path|, with a row for each rewritten commit.
+func writeCommitMap(dEnv *env.DoltEnv, path string, rewritten []rebase.RewrittenCommit) (err error) {
+	wr, err := dEnv.FS.OpenForWrite(path, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := wr.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	csvWr := csv.NewWriter(wr)
+	if err = csvWr.Write([]string{"original_commit", "rewritten_commit"}); err != nil {
+		return err
+	}
+	for _, rc := range rewritten {
+		if err = csvWr.Write([]string{rc.Original.String(), rc.Rewritten.String()}); err != nil {
+			return err
+		}
+	}
+	csvWr.Flush()
+	return csvWr.Error()
+}
+
 // workingSetReplayer replays working set root values, rebasing them with a specific query, and returns the updated root value
 type workingSetReplayer struct {
 	dEnv          *env.DoltEnv
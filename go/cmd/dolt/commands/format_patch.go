@@ -0,0 +1,263 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	outputDirectoryParam = "output-directory"
+	stdoutFlag           = "stdout"
+)
+
+var formatPatchDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Export commits as SQL patch files",
+	LongDesc: `Writes each commit in the given revision range to its own patch file, which can be applied to another repository with {{.EmphasisLeft}}dolt apply{{.EmphasisRight}}. This allows data changes to be exchanged between repositories that cannot reach each other, such as when one of them is air-gapped.
+
+If a single {{.LessThan}}since{{.GreaterThan}} revision is given, a patch is written for every commit reachable from HEAD that is not reachable from {{.LessThan}}since{{.GreaterThan}}. A range of the form {{.LessThan}}since{{.GreaterThan}}..{{.LessThan}}until{{.GreaterThan}} writes the patches for the commits reachable from {{.LessThan}}until{{.GreaterThan}} instead.
+
+Each patch contains the SQL statements needed to transform the first parent of the commit into the commit, preceded by a comment header holding the original commit's hash, author, date and message. Because the header is made of SQL comments, a patch can also be applied with {{.EmphasisLeft}}dolt sql < patch.sql{{.EmphasisRight}}. Merge commits are written as the difference from their first parent.
+
+Patch files are named {{.EmphasisLeft}}NNNN-subject.sql{{.EmphasisRight}}, numbered in the order they should be applied, and are written to the current directory unless {{.EmphasisLeft}}--output-directory{{.EmphasisRight}} is given. With {{.EmphasisLeft}}--stdout{{.EmphasisRight}}, all patches are printed to standard output instead.
+`,
+	Synopsis: []string{
+		`[-o {{.LessThan}}dir{{.GreaterThan}} | --stdout] {{.LessThan}}since{{.GreaterThan}}`,
+		`[-o {{.LessThan}}dir{{.GreaterThan}} | --stdout] {{.LessThan}}since{{.GreaterThan}}..{{.LessThan}}until{{.GreaterThan}}`,
+	},
+}
+
+type FormatPatchCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd FormatPatchCmd) Name() string {
+	return "format-patch"
+}
+
+// Description returns a description of the command
+func (cmd FormatPatchCmd) Description() string {
+	return "Export commits as SQL patch files."
+}
+
+// EventType returns the type of the event to log
+func (cmd FormatPatchCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+func (cmd FormatPatchCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(formatPatchDocs, ap)
+}
+
+func (cmd FormatPatchCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"revision range", "The commits to export, given as {{.LessThan}}since{{.GreaterThan}} or {{.LessThan}}since{{.GreaterThan}}..{{.LessThan}}until{{.GreaterThan}}."})
+	ap.SupportsString(outputDirectoryParam, "o", "dir", "The directory to write patch files to. Defaults to the current directory.")
+	ap.SupportsFlag(stdoutFlag, "", "Print all patches to standard output instead of writing them to files.")
+	return ap
+}
+
+func (cmd FormatPatchCmd) RequiresRepo() bool {
+	return false
+}
+
+// Exec executes the command
+func (cmd FormatPatchCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, formatPatchDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 1 {
+		usage()
+		return 1
+	}
+	if apr.Contains(stdoutFlag) && apr.Contains(outputDirectoryParam) {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: --%s and --%s cannot be used together", stdoutFlag, outputDirectoryParam).Build(), usage)
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	revRange := apr.Arg(0)
+	if !strings.Contains(revRange, "..") {
+		revRange = revRange + "..HEAD"
+	}
+	if strings.Contains(revRange, "...") {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: symmetric revision ranges are not supported by format-patch").Build(), usage)
+	}
+
+	patches, err := getPatchesForRange(queryist, sqlCtx, revRange)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	if apr.Contains(stdoutFlag) {
+		for _, p := range patches {
+			if err := p.write(cli.CliOut); err != nil {
+				return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+			}
+		}
+		return 0
+	}
+
+	dir := apr.GetValueOrDefault(outputDirectoryParam, ".")
+	if err := dEnv.FS.MkDirs(dir); err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: unable to create directory '%s'", dir).AddCause(err).Build(), usage)
+	}
+	for i, p := range patches {
+		path := filepath.Join(dir, patchFileName(i+1, p.message))
+		if err := writePatchFile(dEnv, path, p); err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: unable to write patch '%s'", path).AddCause(err).Build(), usage)
+		}
+		cli.Println(path)
+	}
+
+	return 0
+}
+
+// sqlPatch is a single commit exported as a series of SQL statements, along with the metadata needed to recreate the
+// commit when the patch is applied.
+type sqlPatch struct {
+	commitHash string
+	parentHash string
+	name       string
+	email      string
+	date       time.Time
+	message    string
+	statements []string
+}
+
+// getPatchesForRange returns a patch for every commit in |revRange|, ordered from oldest to newest.
+func getPatchesForRange(queryist cli.Queryist, sqlCtx *sql.Context, revRange string) ([]*sqlPatch, error) {
+	rows, err := InterpolateAndRunQuery(queryist, sqlCtx, "select commit_hash, committer, email, date, message, parents from dolt_log(?, '--parents')", revRange)
+	if err != nil {
+		return nil, fmt.Errorf("error getting commits for '%s': %w", revRange, err)
+	}
+
+	patches := make([]*sqlPatch, len(rows))
+	for i, row := range rows {
+		timestamp, err := getTimestampColAsUint64(row[3])
+		if err != nil {
+			return nil, err
+		}
+		p := &sqlPatch{
+			commitHash: row[0].(string),
+			name:       row[1].(string),
+			email:      row[2].(string),
+			date:       time.UnixMilli(int64(timestamp)).UTC(),
+			message:    row[4].(string),
+		}
+		parents := row[5].(string)
+		if parents == "" {
+			return nil, fmt.Errorf("error: commit %s has no parent and cannot be exported as a patch", p.commitHash)
+		}
+		p.parentHash = strings.Split(parents, ", ")[0]
+
+		stmtRows, err := InterpolateAndRunQuery(queryist, sqlCtx, "select statement from dolt_patch(?, ?) order by statement_order", p.parentHash, p.commitHash)
+		if err != nil {
+			return nil, fmt.Errorf("error getting patch for commit %s: %w", p.commitHash, err)
+		}
+		for _, stmtRow := range stmtRows {
+			p.statements = append(p.statements, stmtRow[0].(string))
+		}
+
+		// dolt_log returns the newest commit first, but patches must be applied oldest first
+		patches[len(rows)-1-i] = p
+	}
+
+	return patches, nil
+}
+
+const (
+	patchHeaderLine  = "-- dolt format-patch"
+	patchCommitKey   = "commit"
+	patchParentKey   = "parent"
+	patchAuthorKey   = "author"
+	patchDateKey     = "date"
+	patchMessageLine = "--"
+)
+
+// write writes |p| to |wr| in the patch file format. The header holds the commit metadata as SQL comments, with the
+// commit message following a bare "--" line, and is followed by the patch statements.
+func (p *sqlPatch) write(wr io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString(patchHeaderLine + "\n")
+	sb.WriteString(fmt.Sprintf("-- %s: %s\n", patchCommitKey, p.commitHash))
+	sb.WriteString(fmt.Sprintf("-- %s: %s\n", patchParentKey, p.parentHash))
+	sb.WriteString(fmt.Sprintf("-- %s: %s <%s>\n", patchAuthorKey, p.name, p.email))
+	sb.WriteString(fmt.Sprintf("-- %s: %s\n", patchDateKey, p.date.Format(time.RFC3339)))
+	sb.WriteString(patchMessageLine + "\n")
+	for _, line := range strings.Split(strings.TrimRight(p.message, "\n"), "\n") {
+		sb.WriteString(strings.TrimRight("-- "+line, " ") + "\n")
+	}
+	sb.WriteString("\n")
+	for _, stmt := range p.statements {
+		sb.WriteString(stmt)
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(wr, sb.String())
+	return err
+}
+
+func writePatchFile(dEnv *env.DoltEnv, path string, p *sqlPatch) (err error) {
+	wr, err := dEnv.FS.OpenForWrite(path, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := wr.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	return p.write(wr)
+}
+
+var patchFileNameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// patchFileName returns the file name for the |n|th patch in a series, derived from the subject line of its commit
+// message.
+func patchFileName(n int, message string) string {
+	subject := strings.SplitN(message, "\n", 2)[0]
+	subject = strings.Trim(patchFileNameSanitizer.ReplaceAllString(subject, "-"), "-")
+	if len(subject) > 52 {
+		subject = strings.TrimRight(subject[:52], "-")
+	}
+	if subject == "" {
+		return fmt.Sprintf("%04d.sql", n)
+	}
+	return fmt.Sprintf("%04d-%s.sql", n, subject)
+}
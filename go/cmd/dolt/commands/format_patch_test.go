@@ -0,0 +1,74 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchRoundTrip(t *testing.T) {
+	p := &sqlPatch{
+		commitHash: "3dfbmod5mj0sfg4rjfem4k0n36ja0b4r",
+		parentHash: "gg358a9h0ijlk83k8d3c3u3hsps9vhpe",
+		name:       "Bill Billerson",
+		email:      "bill@billerson.com",
+		date:       time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+		message:    "add rows\n\nwith a body\n-- that looks like a comment",
+		statements: []string{
+			"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  PRIMARY KEY (`pk`)\n);",
+			"INSERT INTO `t` (`pk`,`c`) VALUES (1,'a; -- b');",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, p.write(&buf))
+
+	read, err := readPatch(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, p.commitHash, read.commitHash)
+	assert.Equal(t, p.parentHash, read.parentHash)
+	assert.Equal(t, p.name, read.name)
+	assert.Equal(t, p.email, read.email)
+	assert.True(t, p.date.Equal(read.date))
+	assert.Equal(t, p.message, read.message)
+	require.Len(t, read.statements, 2)
+	for i := range p.statements {
+		assert.Equal(t, strings.TrimSuffix(p.statements[i], ";"), strings.TrimSuffix(read.statements[i], ";"))
+	}
+}
+
+func TestReadPatchErrors(t *testing.T) {
+	_, err := readPatch(strings.NewReader("insert into t values (1);\n"))
+	assert.Error(t, err)
+
+	_, err = readPatch(strings.NewReader("-- dolt format-patch\n--\n-- message\n\ninsert into t values (1);\n"))
+	assert.Error(t, err)
+
+	_, err = readPatch(strings.NewReader("-- dolt format-patch\n-- author: nobody\n"))
+	assert.Error(t, err)
+}
+
+func TestPatchFileName(t *testing.T) {
+	assert.Equal(t, "0001-add-rows.sql", patchFileName(1, "add rows\n\nbody"))
+	assert.Equal(t, "0012-Fix-the-thing-for-v1-2.sql", patchFileName(12, "  Fix: the thing (for v1.2)!"))
+	assert.Equal(t, "0003.sql", patchFileName(3, "!!!"))
+	assert.Equal(t, 4+1+52+4, len(patchFileName(1, strings.Repeat("a", 100))))
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const quarantineFlag = "quarantine"
+
+var fsckDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Verifies the integrity of the database",
+	LongDesc: `Checks the database for corruption, such as after a disk or filesystem incident.
+
+Every table file listed in the manifest is checked to be present with the expected number of chunks. Every chunk in the database's table files, archives and chunk journal is then read and its data checked against its address, and the chunk journal is checked for data which can't be read. Finally, every chunk reachable from the database's branches, tags, working sets and other refs is visited, and any references to chunks which are missing are reported.
+
+Each problem found is printed, and the command exits with a non-zero status if there were any.
+
+With {{.EmphasisLeft}}--quarantine{{.EmphasisRight}}, table files and archives which contain corrupt chunks are removed from the database and moved to a {{.EmphasisLeft}}quarantine{{.EmphasisRight}} directory next to them, such as {{.EmphasisLeft}}.dolt/noms/oldgen/quarantine{{.EmphasisRight}}. The intact chunks in them are first copied to a new table file. Data which was held only in a corrupt chunk is lost, so the check of reachable chunks reports what is now missing. Problems with the chunk journal are reported but not repaired.
+
+No other process should be using the database while this command runs.
+`,
+	Synopsis: []string{
+		"[--quarantine]",
+	},
+}
+
+type FsckCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd FsckCmd) Name() string {
+	return "fsck"
+}
+
+// Description returns a description of the command
+func (cmd FsckCmd) Description() string {
+	return "Verifies the integrity of the database."
+}
+
+func (cmd FsckCmd) RequiresRepo() bool {
+	return true
+}
+
+func (cmd FsckCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(fsckDocs, ap)
+}
+
+func (cmd FsckCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+	ap.SupportsFlag(quarantineFlag, "", "Move table files containing corrupt chunks out of the database, after salvaging their intact chunks.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd FsckCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd FsckCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, fsckDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if !cli.CheckEnvIsValid(dEnv) {
+		return 2
+	}
+
+	report, err := dEnv.DoltDB.Fsck(ctx, apr.Contains(quarantineFlag), func(problem string) error {
+		cli.Println(problem)
+		return nil
+	})
+	if err != nil {
+		verr := errhand.BuildDError("error: failed to check the database").AddCause(err).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	cli.Printf("checked %d chunks, %d reachable\n", report.ChunksChecked, report.ChunksReachable)
+	if len(report.Quarantined) > 0 {
+		cli.Printf("quarantined table files: %s\n", strings.Join(report.Quarantined, ", "))
+		cli.Printf("%d corrupt chunks could not be salvaged\n", report.ChunksLost)
+	}
+	if report.Problems > 0 {
+		cli.Printf("found %d problems\n", report.Problems)
+		return 1
+	}
+	cli.Println("no problems found")
+	return 0
+}
@@ -16,6 +16,8 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
@@ -33,9 +35,14 @@ var gcDocs = cli.CommandDocumentationContent{
 	ShortDesc: "Cleans up unreferenced data from the repository.",
 	LongDesc: `Searches the repository for data that is no longer referenced and no longer needed.
 
-If the {{.EmphasisLeft}}--shallow{{.EmphasisRight}} flag is supplied, a faster but less thorough garbage collection will be performed.`,
+If the {{.EmphasisLeft}}--shallow{{.EmphasisRight}} flag is supplied, a faster but less thorough garbage collection will be performed.
+
+If {{.EmphasisLeft}}--prune-older-than{{.EmphasisRight}} is supplied, commits made within the given number of days which were the head of a branch, remote tracking branch or workspace are kept even if they are no longer referenced, for example because the branch was deleted or reset. They remain available through their commit hash until a later garbage collection runs after they have aged out of the window. When the flag is not supplied, the number of days is read from the {{.EmphasisLeft}}dolt_gc_retention_days{{.EmphasisRight}} system variable, which defaults to 0. A value of 0 disables retention.
+
+Dropped databases which were dropped more than {{.EmphasisLeft}}dolt_dropped_database_retention_days{{.EmphasisRight}} days ago are permanently deleted, and can no longer be restored with {{.EmphasisLeft}}dolt_undrop(){{.EmphasisRight}}. Dropped databases are kept until they're purged with {{.EmphasisLeft}}dolt_purge_dropped_databases(){{.EmphasisRight}} while it's 0, which is the default.`,
 	Synopsis: []string{
 		"[--shallow]",
+		"[--prune-older-than {{.LessThan}}days{{.GreaterThan}}]",
 	},
 }
 
@@ -107,9 +114,14 @@ func (cmd GarbageCollectionCmd) Exec(ctx context.Context, commandStr string, arg
 // constructDoltGCQuery generates the sql query necessary to call DOLT_GC()
 func constructDoltGCQuery(apr *argparser.ArgParseResults) (string, error) {
 	query := "call DOLT_GC("
+	var params []string
 	if apr.Contains(cli.ShallowFlag) {
-		query += "'--shallow'"
+		params = append(params, "'--shallow'")
+	}
+	if days, ok := apr.GetInt(cli.PruneOlderThanFlag); ok {
+		params = append(params, fmt.Sprintf("'--%s', '%d'", cli.PruneOlderThanFlag, days))
 	}
+	query += strings.Join(params, ", ")
 	query += ")"
 	return query, nil
 }
@@ -0,0 +1,157 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+)
+
+// Client side hooks are executables in the .dolt/hooks directory of a repository, named after the operation they
+// guard. A hook which exits with a non-zero status aborts the operation. Hooks are run by the CLI only; they are not
+// run for the equivalent stored procedures or by sql-server.
+const (
+	hooksDir      = "hooks"
+	preCommitHook = "pre-commit"
+	prePushHook   = "pre-push"
+)
+
+// runClientHook runs the hook |name| of the repository in |dEnv|, if it exists, with |args| and |stdin|. The hook's
+// stdout and stderr are passed through to the user. An error is returned if the hook could not be run or exited with
+// a non-zero status.
+func runClientHook(ctx context.Context, dEnv *env.DoltEnv, name string, args []string, stdin []byte) error {
+	path, ok := clientHookPath(dEnv, name)
+	if !ok {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = cli.CliOut
+	cmd.Stderr = cli.CliErr
+	if root, err := dEnv.FS.Abs(""); err == nil {
+		cmd.Dir = root
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s hook failed: %w", name, err)
+	}
+	return nil
+}
+
+// clientHookPath returns the path of the hook |name| in |dEnv| and whether it exists and is executable.
+func clientHookPath(dEnv *env.DoltEnv, name string) (string, bool) {
+	if dEnv == nil || dEnv.FS == nil {
+		return "", false
+	}
+	path, err := dEnv.FS.Abs(filepath.Join(dbfactory.DoltDir, hooksDir, name))
+	if err != nil {
+		return "", false
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return "", false
+	}
+	return path, true
+}
+
+// preCommitHookInput returns the summary of the changes about to be committed which is given to the pre-commit hook on
+// stdin. There is one tab separated line per changed table: the table name, the type of change, and whether the data
+// and the schema of the table changed.
+func preCommitHookInput(queryist cli.Queryist, sqlCtx *sql.Context, toRef string) ([]byte, error) {
+	q, err := dbr.InterpolateForDialect("select to_table_name, from_table_name, diff_type, data_change, schema_change from dolt_diff_summary('HEAD', ?)", []interface{}{toRef}, dialect.MySQL)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := GetRowsForSql(queryist, sqlCtx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, row := range rows {
+		tableName := fmt.Sprint(row[0])
+		if tableName == "" {
+			tableName = fmt.Sprint(row[1])
+		}
+		dataChange, err := GetTinyIntColAsBool(row[3])
+		if err != nil {
+			return nil, err
+		}
+		schemaChange, err := GetTinyIntColAsBool(row[4])
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "%s\t%s\t%t\t%t\n", tableName, row[2], dataChange, schemaChange)
+	}
+	return buf.Bytes(), nil
+}
+
+// prePushHookInput returns the refs about to be pushed, which are given to the pre-push hook on stdin. There is one
+// line per ref: the ref as given on the command line and the commit hash it resolves to.
+func prePushHookInput(queryist cli.Queryist, sqlCtx *sql.Context, refSpecs []string, all bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if all {
+		rows, err := GetRowsForSql(queryist, sqlCtx, "select name, hash from dolt_branches order by name")
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			fmt.Fprintf(&buf, "%s %s\n", row[0], row[1])
+		}
+		return buf.Bytes(), nil
+	}
+
+	var srcs []string
+	for _, refSpec := range refSpecs {
+		src, _, _ := strings.Cut(strings.TrimPrefix(refSpec, "+"), ":")
+		if src != "" {
+			srcs = append(srcs, src)
+		}
+	}
+	if len(refSpecs) == 0 {
+		rows, err := GetRowsForSql(queryist, sqlCtx, "select active_branch()")
+		if err != nil {
+			return nil, err
+		}
+		srcs = append(srcs, fmt.Sprint(rows[0][0]))
+	}
+
+	for _, src := range srcs {
+		q, err := dbr.InterpolateForDialect("select hashof(?)", []interface{}{src}, dialect.MySQL)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := GetRowsForSql(queryist, sqlCtx, q)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "%s %s\n", src, rows[0][0])
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importcmds
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// statementKind is how a statement of a dump is applied.
+type statementKind int
+
+const (
+	// skipStatement statements aren't run, like LOCK TABLES
+	skipStatement statementKind = iota
+	// setStatement statements set session variables, and are run in every session
+	setStatement
+	// useStatement statements select the database the dump was taken from
+	useStatement
+	// schemaStatement statements are run in order, before the rows of the tables they create are imported
+	schemaStatement
+	// dataStatement statements write the rows of a table, and are run in parallel with those of other tables
+	dataStatement
+	// postStatement statements are run once the rows of every table are imported, so triggers don't fire on them
+	postStatement
+)
+
+// dumpStatement is a statement of a dump, and how it's applied.
+type dumpStatement struct {
+	kind  statementKind
+	query string
+	// name is the table a data statement writes to, or the database a use statement selects
+	name string
+}
+
+var (
+	identifier = "(?:`(?:[^`]|``)+`|[\\w$]+)"
+
+	lockTablesRegex     = regexp.MustCompile(`(?i)^(?:LOCK|UNLOCK)\s+TABLES?\b`)
+	tableKeysRegex      = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+` + identifier + `(?:\.` + identifier + `)?\s+(?:DISABLE|ENABLE)\s+KEYS$`)
+	createDatabaseRegex = regexp.MustCompile(`(?i)^CREATE\s+(?:DATABASE|SCHEMA)\b`)
+	useRegex            = regexp.MustCompile(`(?i)^USE\s+(` + identifier + `)$`)
+	setRegex            = regexp.MustCompile(`(?i)^SET\b`)
+	// unsupportedSetRegex matches the variables a dump sets which only matter to MySQL's replication, or which can't
+	// be set by a session
+	unsupportedSetRegex = regexp.MustCompile(`(?i)\bGLOBAL\b|\bSQL_LOG_BIN\b|\bGTID_PURGED\b`)
+	// gtidPurgedRegex matches the GTIDs a dump taken with --set-gtid-purged records the source had executed
+	gtidPurgedRegex = regexp.MustCompile(`(?i)\bGTID_PURGED\s*=\s*(?:'\+'\s*)?'([^']*)'`)
+	insertRegex     = regexp.MustCompile(`(?is)^(?:INSERT|REPLACE)\s+(?:(?:LOW_PRIORITY|DELAYED|HIGH_PRIORITY|IGNORE)\s+)*(?:INTO\s+)?(?:` + identifier + `\.)?(` + identifier + `)`)
+	postRegex       = regexp.MustCompile(`(?is)^(?:CREATE|DROP)\s+(?:(?:OR\s+REPLACE|ALGORITHM\s*=\s*\w+|SQL\s+SECURITY\s+\w+|AGGREGATE)\s+)*(?:VIEW|TRIGGER|PROCEDURE|FUNCTION|EVENT)\b`)
+
+	account      = "(?:`[^`]*`|'[^']*'|\"[^\"]*\"|[\\w.%-]+)"
+	definerRegex = regexp.MustCompile(`(?i)\bDEFINER\s*=\s*(?:CURRENT_USER(?:\s*\(\s*\))?|` + account + `(?:\s*@\s*` + account + `)?)\s*`)
+)
+
+// parseStatement returns how |stmt|, a statement of a dump, is applied. Versioned comments are unwrapped, since MySQL
+// runs the statements in them, and DEFINER clauses are removed, since the accounts they name usually don't exist.
+func parseStatement(stmt string) dumpStatement {
+	query := strings.TrimSpace(stripLeadingComments(stmt))
+	if strings.Contains(query, "/*!") {
+		query = strings.TrimSpace(unwrapVersionedComments(query))
+	}
+
+	switch {
+	case query == "", lockTablesRegex.MatchString(query), tableKeysRegex.MatchString(query), createDatabaseRegex.MatchString(query):
+		return dumpStatement{kind: skipStatement, query: query}
+	case setRegex.MatchString(query):
+		if unsupportedSetRegex.MatchString(query) {
+			return dumpStatement{kind: skipStatement, query: query}
+		}
+		return dumpStatement{kind: setStatement, query: query}
+	}
+	if m := useRegex.FindStringSubmatch(query); m != nil {
+		return dumpStatement{kind: useStatement, query: query, name: unquoteIdentifier(m[1])}
+	}
+	if m := insertRegex.FindStringSubmatch(query); m != nil {
+		return dumpStatement{kind: dataStatement, query: query, name: unquoteIdentifier(m[1])}
+	}
+
+	query = definerRegex.ReplaceAllString(query, "")
+	if postRegex.MatchString(query) {
+		return dumpStatement{kind: postStatement, query: query}
+	}
+	return dumpStatement{kind: schemaStatement, query: query}
+}
+
+// stripLeadingComments returns |stmt| without the comments before it, other than versioned comments.
+func stripLeadingComments(stmt string) string {
+	for {
+		stmt = strings.TrimLeftFunc(stmt, unicode.IsSpace)
+		switch {
+		case strings.HasPrefix(stmt, "--"), strings.HasPrefix(stmt, "#"):
+			i := strings.IndexByte(stmt, '\n')
+			if i < 0 {
+				return ""
+			}
+			stmt = stmt[i+1:]
+		case strings.HasPrefix(stmt, "/*") && !strings.HasPrefix(stmt, "/*!"):
+			i := strings.Index(stmt, "*/")
+			if i < 0 {
+				return ""
+			}
+			stmt = stmt[i+2:]
+		default:
+			return stmt
+		}
+	}
+}
+
+// unwrapVersionedComments returns |stmt| with the contents of its versioned comments, like /*!40101 SET NAMES utf8 */,
+// in place of the comments.
+func unwrapVersionedComments(stmt string) string {
+	var sb strings.Builder
+	sb.Grow(len(stmt))
+	var quote byte
+	inComment := false
+	for i := 0; i < len(stmt); i++ {
+		c := stmt[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' && i+1 < len(stmt) {
+				sb.WriteByte(c)
+				i++
+				c = stmt[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case strings.HasPrefix(stmt[i:], "/*!"):
+			i += 3
+			for i < len(stmt) && stmt[i] >= '0' && stmt[i] <= '9' {
+				i++
+			}
+			i--
+			inComment = true
+			continue
+		case inComment && strings.HasPrefix(stmt[i:], "*/"):
+			i++
+			inComment = false
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+func unquoteIdentifier(id string) string {
+	if len(id) >= 2 && id[0] == '`' && id[len(id)-1] == '`' {
+		return strings.ReplaceAll(id[1:len(id)-1], "``", "`")
+	}
+	return id
+}
+
+// dumpFile is a file of a dump.
+type dumpFile struct {
+	path string
+	// post is set for the files of views, triggers and routines, whose statements are run once every table's rows are
+	// imported
+	post bool
+}
+
+// dumpFiles returns the files of the dump at |path|, in the order they're read. A file is a dump written by mysqldump,
+// and a directory is a dump written by mydumper, whose table schemas are read before the rows of the tables, and
+// whose views, triggers and routines are read last.
+func dumpFiles(fs filesys.Filesys, path string) ([]dumpFile, error) {
+	exists, isDir := fs.Exists(path)
+	if !exists {
+		return nil, fmt.Errorf("%s does not exist", path)
+	}
+	if !isDir {
+		return []dumpFile{{path: path}}, nil
+	}
+
+	var schemaFiles, dataFiles, postFiles []dumpFile
+	err := fs.Iter(path, false, func(p string, _ int64, isDir bool) (stop bool) {
+		name := strings.TrimSuffix(filepath.Base(p), ".gz")
+		switch {
+		case isDir, !strings.HasSuffix(name, ".sql"), strings.HasSuffix(name, "-schema-create.sql"):
+			// the database is created by mydumper's -schema-create.sql file, and we import into the current database
+		case strings.HasSuffix(name, "-schema-view.sql"), strings.HasSuffix(name, "-schema-triggers.sql"), strings.HasSuffix(name, "-schema-post.sql"):
+			postFiles = append(postFiles, dumpFile{path: p, post: true})
+		case strings.HasSuffix(name, "-schema.sql"):
+			schemaFiles = append(schemaFiles, dumpFile{path: p})
+		default:
+			dataFiles = append(dataFiles, dumpFile{path: p})
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var files []dumpFile
+	for _, group := range [][]dumpFile{schemaFiles, dataFiles, postFiles} {
+		sort.Slice(group, func(i, j int) bool { return group[i].path < group[j].path })
+		files = append(files, group...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s holds no .sql files", path)
+	}
+	return files, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importcmds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		stmt     string
+		expected dumpStatement
+	}{
+		{
+			stmt:     "-- MySQL dump 10.13\n--\n-- Host: localhost\n\n/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */",
+			expected: dumpStatement{kind: setStatement, query: "SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT"},
+		},
+		{
+			stmt:     "/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */",
+			expected: dumpStatement{kind: setStatement, query: "SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0"},
+		},
+		{
+			stmt:     "SET @@GLOBAL.GTID_PURGED=/*!80000 '+'*/ '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'",
+			expected: dumpStatement{kind: skipStatement, query: "SET @@GLOBAL.GTID_PURGED= '+' '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5'"},
+		},
+		{
+			stmt:     "SET @MYSQLDUMP_TEMP_LOG_BIN = @@SESSION.SQL_LOG_BIN",
+			expected: dumpStatement{kind: skipStatement, query: "SET @MYSQLDUMP_TEMP_LOG_BIN = @@SESSION.SQL_LOG_BIN"},
+		},
+		{
+			stmt:     "\nLOCK TABLES `t1` WRITE",
+			expected: dumpStatement{kind: skipStatement, query: "LOCK TABLES `t1` WRITE"},
+		},
+		{
+			stmt:     "UNLOCK TABLES",
+			expected: dumpStatement{kind: skipStatement, query: "UNLOCK TABLES"},
+		},
+		{
+			stmt:     "/*!40000 ALTER TABLE `t1` DISABLE KEYS */",
+			expected: dumpStatement{kind: skipStatement, query: "ALTER TABLE `t1` DISABLE KEYS"},
+		},
+		{
+			stmt:     "CREATE DATABASE /*!32312 IF NOT EXISTS*/ `db` /*!40100 DEFAULT CHARACTER SET utf8mb4 */",
+			expected: dumpStatement{kind: skipStatement, query: "CREATE DATABASE  IF NOT EXISTS `db`  DEFAULT CHARACTER SET utf8mb4"},
+		},
+		{
+			stmt:     "USE `my db`",
+			expected: dumpStatement{kind: useStatement, query: "USE `my db`", name: "my db"},
+		},
+		{
+			stmt:     "--\n-- Table structure for table `t1`\n--\n\nDROP TABLE IF EXISTS `t1`",
+			expected: dumpStatement{kind: schemaStatement, query: "DROP TABLE IF EXISTS `t1`"},
+		},
+		{
+			stmt:     "CREATE TABLE `t1` (\n  `pk` int NOT NULL,\n  `view` varchar(10),\n  PRIMARY KEY (`pk`)\n)",
+			expected: dumpStatement{kind: schemaStatement, query: "CREATE TABLE `t1` (\n  `pk` int NOT NULL,\n  `view` varchar(10),\n  PRIMARY KEY (`pk`)\n)"},
+		},
+		{
+			stmt:     "INSERT INTO `t1` VALUES (1,'/*!50001 not a comment */'),(2,'it''s')",
+			expected: dumpStatement{kind: dataStatement, query: "INSERT INTO `t1` VALUES (1,'/*!50001 not a comment */'),(2,'it''s')", name: "t1"},
+		},
+		{
+			stmt:     "insert ignore into db.`odd``name` (a) values (1)",
+			expected: dumpStatement{kind: dataStatement, query: "insert ignore into db.`odd``name` (a) values (1)", name: "odd`name"},
+		},
+		{
+			stmt:     "REPLACE INTO t2 VALUES (1)",
+			expected: dumpStatement{kind: dataStatement, query: "REPLACE INTO t2 VALUES (1)", name: "t2"},
+		},
+		{
+			stmt:     "/*!50001 CREATE ALGORITHM=UNDEFINED */\n/*!50013 DEFINER=`root`@`localhost` SQL SECURITY DEFINER */\n/*!50001 VIEW `v1` AS select `t1`.`pk` AS `pk` from `t1` */",
+			expected: dumpStatement{kind: postStatement, query: "CREATE ALGORITHM=UNDEFINED \n SQL SECURITY DEFINER \n VIEW `v1` AS select `t1`.`pk` AS `pk` from `t1`"},
+		},
+		{
+			stmt:     "/*!50003 CREATE*/ /*!50017 DEFINER=`admin`@`%`*/ /*!50003 TRIGGER `trg` BEFORE INSERT ON `t1` FOR EACH ROW SET NEW.pk = NEW.pk + 1 */",
+			expected: dumpStatement{kind: postStatement, query: "CREATE  TRIGGER `trg` BEFORE INSERT ON `t1` FOR EACH ROW SET NEW.pk = NEW.pk + 1"},
+		},
+		{
+			stmt:     "CREATE DEFINER='app'@'10.0.0.%' PROCEDURE `p`() BEGIN SELECT 1; END",
+			expected: dumpStatement{kind: postStatement, query: "CREATE PROCEDURE `p`() BEGIN SELECT 1; END"},
+		},
+		{
+			stmt:     "/*!50001 DROP VIEW IF EXISTS `v1`*/",
+			expected: dumpStatement{kind: postStatement, query: "DROP VIEW IF EXISTS `v1`"},
+		},
+		{
+			stmt:     "\n-- Dump completed on 2024-01-01 12:00:00\n",
+			expected: dumpStatement{kind: skipStatement},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.stmt, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseStatement(test.stmt))
+		})
+	}
+}
+
+func TestDumpFiles(t *testing.T) {
+	fs := filesys.NewInMemFS(nil, map[string][]byte{
+		"/dump.sql":                      {},
+		"/out/metadata":                  {},
+		"/out/db-schema-create.sql":      {},
+		"/out/db.t2-schema.sql":          {},
+		"/out/db.t1-schema.sql":          {},
+		"/out/db.t1.00001.sql":           {},
+		"/out/db.t1.00000.sql":           {},
+		"/out/db.t2.sql.gz":              {},
+		"/out/db.v1-schema-view.sql":     {},
+		"/out/db.t1-schema-triggers.sql": {},
+		"/out/db-schema-post.sql":        {},
+		"/empty/notes.txt":               {},
+	}, "/")
+
+	files, err := dumpFiles(fs, "/dump.sql")
+	require.NoError(t, err)
+	assert.Equal(t, []dumpFile{{path: "/dump.sql"}}, files)
+
+	files, err = dumpFiles(fs, "/out")
+	require.NoError(t, err)
+	assert.Equal(t, []dumpFile{
+		{path: "/out/db.t1-schema.sql"},
+		{path: "/out/db.t2-schema.sql"},
+		{path: "/out/db.t1.00000.sql"},
+		{path: "/out/db.t1.00001.sql"},
+		{path: "/out/db.t2.sql.gz"},
+		{path: "/out/db-schema-post.sql", post: true},
+		{path: "/out/db.t1-schema-triggers.sql", post: true},
+		{path: "/out/db.v1-schema-view.sql", post: true},
+	}, files)
+
+	_, err = dumpFiles(fs, "/empty")
+	assert.Error(t, err)
+	_, err = dumpFiles(fs, "/missing.sql")
+	assert.Error(t, err)
+}
@@ -0,0 +1,441 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importcmds
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const (
+	parallelParam      = "parallel"
+	commitPerTableFlag = "commit-per-table"
+	messageParam       = "message"
+
+	defaultParallelism = 4
+	// workerQueueSize is how many statements are read ahead of each worker
+	workerQueueSize = 16
+)
+
+var mysqldumpDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Import a database dumped by mysqldump or mydumper.",
+	LongDesc: `Imports a database dumped by {{.EmphasisLeft}}mysqldump{{.EmphasisRight}}, or the directory of a database dumped by {{.EmphasisLeft}}mydumper{{.EmphasisRight}}, into the current database, to migrate a database from MySQL. Files ending in .gz are decompressed.
+
+The statements of the dump are run as MySQL runs them, except that:
+
+{{.EmphasisLeft}}LOCK TABLES{{.EmphasisRight}}, {{.EmphasisLeft}}UNLOCK TABLES{{.EmphasisRight}}, {{.EmphasisLeft}}ALTER TABLE ... DISABLE KEYS{{.EmphasisRight}}, {{.EmphasisLeft}}CREATE DATABASE{{.EmphasisRight}} and {{.EmphasisLeft}}USE{{.EmphasisRight}} statements are skipped. A dump of more than one database can't be imported.
+
+{{.EmphasisLeft}}SET{{.EmphasisRight}} statements of global variables and of replication variables, like {{.EmphasisLeft}}GTID_PURGED{{.EmphasisRight}}, are skipped. Other variables are set for every table's rows, and a warning is printed for those which can't be set.
+
+{{.EmphasisLeft}}DEFINER{{.EmphasisRight}} clauses are removed from views, triggers and routines, so they're defined by the current user.
+
+The rows of up to {{.EmphasisLeft}}--parallel{{.EmphasisRight}} tables are imported at once. Views, triggers, routines and events are created once the rows of every table are imported, so triggers don't fire on imported rows.
+
+The import is committed in a single commit, or with {{.EmphasisLeft}}--commit-per-table{{.EmphasisRight}}, in a commit for each table once its rows are imported, followed by a commit of the tables without rows, views, triggers and routines. The working set must be clean. If the import fails, the changes made so far are left in the working set, and can be discarded with {{.EmphasisLeft}}dolt reset --hard{{.EmphasisRight}}.`,
+	Synopsis: []string{
+		"[--parallel {{.LessThan}}n{{.GreaterThan}}] [--commit-per-table] [-m {{.LessThan}}msg{{.GreaterThan}}] {{.LessThan}}file|directory{{.GreaterThan}}",
+	},
+}
+
+type MysqldumpCmd struct{}
+
+// Name implements cli.Command.
+func (cmd MysqldumpCmd) Name() string {
+	return "mysqldump"
+}
+
+// Description implements cli.Command.
+func (cmd MysqldumpCmd) Description() string {
+	return mysqldumpDocs.ShortDesc
+}
+
+// Docs implements cli.Command.
+func (cmd MysqldumpCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(mysqldumpDocs, ap)
+}
+
+// ArgParser implements cli.Command.
+func (cmd MysqldumpCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"file|directory", "The file written by mysqldump, or the directory written by mydumper."})
+	ap.SupportsInt(parallelParam, "", "n", fmt.Sprintf("The most tables whose rows are imported at once. Defaults to %d.", defaultParallelism))
+	ap.SupportsFlag(commitPerTableFlag, "", "Commit each table once its rows are imported, rather than committing the whole dump at once.")
+	ap.SupportsString(messageParam, "m", "msg", "The message of the commit of the dump. Defaults to Import followed by the name of the dump.")
+	return ap
+}
+
+// EventType implements cli.Command.
+func (cmd MysqldumpCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec implements cli.Command.
+func (cmd MysqldumpCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, mysqldumpDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 1 {
+		usage()
+		return 1
+	}
+	path := apr.Arg(0)
+	parallel := apr.GetIntOrDefault(parallelParam, defaultParallelism)
+	if parallel <= 0 {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: --%s must be positive", parallelParam).Build(), usage)
+	}
+	message, ok := apr.GetValue(messageParam)
+	if ok && message == "" {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: --%s must not be empty", messageParam).Build(), usage)
+	} else if !ok {
+		message = "Import " + filepath.Base(path)
+	}
+
+	files, err := dumpFiles(dEnv.FS, path)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	se, dbName, err := engine.NewSqlEngineForEnv(ctx, dEnv)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to build sql engine").AddCause(err).Build(), usage)
+	}
+	defer se.Close()
+
+	imp := &dumpImporter{
+		se:             se,
+		database:       dbName,
+		fs:             dEnv.FS,
+		parallel:       parallel,
+		commitPerTable: apr.Contains(commitPerTableFlag),
+		message:        message,
+	}
+	if err = imp.run(ctx, files); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to import %s", path).AddCause(err).Build(), usage)
+	}
+
+	cli.Println("Import completed successfully.")
+	return 0
+}
+
+// dumpImporter applies the statements of a dump. Schema statements are run in order in one session, and the rows of
+// each table are written by one of |parallel| workers, each with its own session, which commits its transaction once
+// it's written the rows of a table. Data statements are only sent to workers once the schema session's transaction
+// is committed, so the tables they write are visible to the workers.
+type dumpImporter struct {
+	se             *engine.SqlEngine
+	database       string
+	fs             filesys.ReadableFS
+	parallel       int
+	commitPerTable bool
+	message        string
+
+	workers  []chan dumpStatement
+	workerOf map[string]chan dumpStatement
+	// post holds the statements which are run once every table's rows are imported
+	post         []dumpStatement
+	dumpDatabase string
+	schemaDirty  bool
+	// gtidPurged is the set of GTIDs the source had executed when the dump was taken, if the dump records it
+	gtidPurged string
+
+	// mu serializes the commits of tables, and their output
+	mu sync.Mutex
+}
+
+func (imp *dumpImporter) run(ctx context.Context, files []dumpFile) error {
+	sqlCtx, err := imp.newContext(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := imp.count(sqlCtx, "SELECT COUNT(*) FROM dolt_status"); err != nil {
+		return err
+	} else if n > 0 {
+		return fmt.Errorf("the working set has uncommitted changes; commit or stash them before importing a dump")
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	imp.workers = make([]chan dumpStatement, imp.parallel)
+	imp.workerOf = make(map[string]chan dumpStatement)
+	for i := range imp.workers {
+		stmts := make(chan dumpStatement, workerQueueSize)
+		imp.workers[i] = stmts
+		eg.Go(func() error {
+			return imp.applyRows(egCtx, stmts)
+		})
+	}
+	eg.Go(func() error {
+		defer func() {
+			for _, stmts := range imp.workers {
+				close(stmts)
+			}
+		}()
+		for _, f := range files {
+			if err := imp.readFile(egCtx, sqlCtx, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err = eg.Wait(); err != nil {
+		return err
+	}
+
+	for _, stmt := range imp.post {
+		if err = imp.exec(sqlCtx, stmt.query); err != nil && stmt.kind != setStatement {
+			return fmt.Errorf("%s: %w", abbreviate(stmt.query), err)
+		}
+	}
+	if err = imp.exec(sqlCtx, "COMMIT"); err != nil {
+		return err
+	}
+
+	// with --commit-per-table, this commits the tables without rows, views, triggers and routines
+	if n, err := imp.count(sqlCtx, "SELECT COUNT(*) FROM dolt_status"); err != nil || n == 0 {
+		return err
+	}
+	return imp.exec(sqlCtx, "CALL DOLT_COMMIT('-Am', ?)", imp.message)
+}
+
+// readFile reads the statements of |f|, running its schema statements and sending its data statements to workers.
+func (imp *dumpImporter) readFile(ctx context.Context, sqlCtx *sql.Context, f dumpFile) error {
+	rd, err := imp.fs.OpenForRead(f.path)
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+
+	var r io.Reader = rd
+	if strings.HasSuffix(f.path, ".gz") {
+		gz, err := gzip.NewReader(rd)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	scanner := commands.NewSqlStatementScanner(r)
+	for scanner.Scan() {
+		stmt := parseStatement(scanner.Text())
+		if err := imp.apply(ctx, sqlCtx, f, stmt); err != nil {
+			return fmt.Errorf("%s: %s: %w", f.path, abbreviate(stmt.query), err)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", f.path, err)
+	}
+	return nil
+}
+
+func (imp *dumpImporter) apply(ctx context.Context, sqlCtx *sql.Context, f dumpFile, stmt dumpStatement) error {
+	switch stmt.kind {
+	case skipStatement:
+		if m := gtidPurgedRegex.FindStringSubmatch(stmt.query); m != nil {
+			imp.gtidPurged = strings.Join(strings.Fields(m[1]), "")
+		}
+		return nil
+
+	case useStatement:
+		if imp.dumpDatabase != "" && !strings.EqualFold(imp.dumpDatabase, stmt.name) {
+			return fmt.Errorf("the dump holds more than one database, %s and %s; dump each database separately", imp.dumpDatabase, stmt.name)
+		}
+		imp.dumpDatabase = stmt.name
+		return nil
+
+	case setStatement:
+		imp.post = append(imp.post, stmt)
+		if f.post {
+			return nil
+		}
+		if err := imp.exec(sqlCtx, stmt.query); err != nil {
+			cli.PrintErrf("warning: skipped %s: %s\n", stmt.query, err.Error())
+			return nil
+		}
+		// the sessions of the workers follow the variables the dump sets
+		for _, w := range imp.workers {
+			if err := send(ctx, w, stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case dataStatement:
+		if imp.schemaDirty {
+			if err := imp.exec(sqlCtx, "COMMIT"); err != nil {
+				return err
+			}
+			imp.schemaDirty = false
+		}
+		key := strings.ToLower(stmt.name)
+		w, ok := imp.workerOf[key]
+		if !ok {
+			w = imp.workers[len(imp.workerOf)%len(imp.workers)]
+			imp.workerOf[key] = w
+		}
+		return send(ctx, w, stmt)
+	}
+
+	if stmt.kind == postStatement || f.post {
+		imp.post = append(imp.post, stmt)
+		return nil
+	}
+	if err := imp.exec(sqlCtx, stmt.query); err != nil {
+		return err
+	}
+	imp.schemaDirty = true
+	return nil
+}
+
+// applyRows runs the statements sent to a worker, committing its transaction once it's written the rows of a table.
+func (imp *dumpImporter) applyRows(ctx context.Context, stmts <-chan dumpStatement) error {
+	sqlCtx, err := imp.newContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var table string
+	for stmt := range stmts {
+		if stmt.kind == setStatement {
+			// a warning was printed by the schema session if the variable can't be set
+			_ = imp.exec(sqlCtx, stmt.query)
+			continue
+		}
+		if !strings.EqualFold(stmt.name, table) {
+			if err = imp.finishTable(sqlCtx, table); err != nil {
+				return err
+			}
+			table = stmt.name
+		}
+		if err = imp.exec(sqlCtx, stmt.query); err != nil {
+			return fmt.Errorf("table %s: %w", table, err)
+		}
+	}
+	return imp.finishTable(sqlCtx, table)
+}
+
+// finishTable commits the transaction which wrote the rows of |table|, and with --commit-per-table, commits the table.
+func (imp *dumpImporter) finishTable(sqlCtx *sql.Context, table string) error {
+	if table == "" {
+		return nil
+	}
+	if err := imp.exec(sqlCtx, "COMMIT"); err != nil {
+		return fmt.Errorf("table %s: %w", table, err)
+	}
+
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	if imp.commitPerTable {
+		if err := imp.exec(sqlCtx, "CALL DOLT_ADD(?)", table); err != nil {
+			return fmt.Errorf("table %s: %w", table, err)
+		}
+		if err := imp.exec(sqlCtx, "CALL DOLT_COMMIT('-m', ?)", fmt.Sprintf("Import table %s", table)); err != nil {
+			return fmt.Errorf("table %s: %w", table, err)
+		}
+	}
+	cli.Printf("Imported the rows of %s\n", table)
+	return nil
+}
+
+func (imp *dumpImporter) newContext(ctx context.Context) (*sql.Context, error) {
+	sqlCtx, err := imp.se.NewLocalContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sqlCtx.SetCurrentDatabase(imp.database)
+	return sqlCtx, nil
+}
+
+func (imp *dumpImporter) exec(sqlCtx *sql.Context, query string, params ...interface{}) error {
+	return execQuery(imp.se, sqlCtx, query, params...)
+}
+
+// execQuery runs |query| with |params| interpolated into it, and drains its results.
+func execQuery(se *engine.SqlEngine, sqlCtx *sql.Context, query string, params ...interface{}) error {
+	if len(params) > 0 {
+		var err error
+		query, err = dbr.InterpolateForDialect(query, params, dialect.MySQL)
+		if err != nil {
+			return err
+		}
+	}
+	_, iter, _, err := se.Query(sqlCtx, query)
+	if err != nil {
+		return err
+	}
+	_, err = sql.RowIterToRows(sqlCtx, iter)
+	return err
+}
+
+// count returns the count selected by |query|.
+func (imp *dumpImporter) count(sqlCtx *sql.Context, query string) (int64, error) {
+	_, iter, _, err := imp.se.Query(sqlCtx, query)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := sql.RowIterToRows(sqlCtx, iter)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return 0, fmt.Errorf("unexpected result of %s", query)
+	}
+	n, ok := rows[0][0].(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected result of %s", query)
+	}
+	return n, nil
+}
+
+func send(ctx context.Context, stmts chan<- dumpStatement, stmt dumpStatement) error {
+	select {
+	case stmts <- stmt:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// abbreviate returns the start of |query|, for error messages.
+func abbreviate(query string) string {
+	const maxLen = 80
+	if i := strings.IndexByte(query, '\n'); i >= 0 {
+		query = query[:i] + " ..."
+	}
+	if len(query) > maxLen {
+		query = query[:maxLen] + " ..."
+	}
+	return query
+}
@@ -0,0 +1,261 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importcmds
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/binlogreplication"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	hostParam      = "host"
+	portParam      = "port"
+	userParam      = "user"
+	passwordParam  = "password"
+	serverIdParam  = "server-id"
+	mysqldumpParam = "mysqldump"
+
+	defaultSourcePort = 3306
+	defaultServerId   = 2
+)
+
+var replicateFromMysqlDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Migrate a MySQL database to Dolt, and replicate the writes made to it since.",
+	LongDesc: `Migrates a MySQL database to the current Dolt database with near-zero downtime. A consistent snapshot of the MySQL database is taken with {{.EmphasisLeft}}mysqldump --single-transaction{{.EmphasisRight}}, which must be installed, and is imported and committed as {{.EmphasisLeft}}dolt import mysqldump{{.EmphasisRight}} imports it. Then this database is configured as a binlog replica of the MySQL server, starting from the GTIDs the server had executed when the snapshot was taken, so that no write is missed or applied twice.
+
+Replication starts when {{.EmphasisLeft}}dolt sql-server{{.EmphasisRight}} is next started in this directory, and can be monitored with {{.EmphasisLeft}}SHOW REPLICA STATUS{{.EmphasisRight}}. To cut over to Dolt, stop writes to the MySQL server, wait until the replica has executed every GTID of the server's {{.EmphasisLeft}}@@gtid_executed{{.EmphasisRight}}, run {{.EmphasisLeft}}STOP REPLICA{{.EmphasisRight}} and {{.EmphasisLeft}}RESET REPLICA ALL{{.EmphasisRight}}, and point clients at Dolt.
+
+The MySQL server must have GTIDs enabled, the user must be able to dump the database and must have the {{.EmphasisLeft}}REPLICATION SLAVE{{.EmphasisRight}} privilege, and the Dolt database must have the same name as the MySQL database, since replicated writes are applied to the database they're made to. The password is read from {{.EmphasisLeft}}--password{{.EmphasisRight}}, or from the {{.EmphasisLeft}}MYSQL_PWD{{.EmphasisRight}} environment variable, and is stored in this server's privileges file, as {{.EmphasisLeft}}CHANGE REPLICATION SOURCE TO{{.EmphasisRight}} stores it.`,
+	Synopsis: []string{
+		"--host {{.LessThan}}host{{.GreaterThan}} [--port {{.LessThan}}port{{.GreaterThan}}] --user {{.LessThan}}user{{.GreaterThan}} [--password {{.LessThan}}password{{.GreaterThan}}] [--server-id {{.LessThan}}id{{.GreaterThan}}] [{{.LessThan}}database{{.GreaterThan}}]",
+	},
+}
+
+type ReplicateFromMysqlCmd struct{}
+
+// Name implements cli.Command.
+func (cmd ReplicateFromMysqlCmd) Name() string {
+	return "replicate-from-mysql"
+}
+
+// Description implements cli.Command.
+func (cmd ReplicateFromMysqlCmd) Description() string {
+	return replicateFromMysqlDocs.ShortDesc
+}
+
+// Docs implements cli.Command.
+func (cmd ReplicateFromMysqlCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(replicateFromMysqlDocs, ap)
+}
+
+// ArgParser implements cli.Command.
+func (cmd ReplicateFromMysqlCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"database", "The MySQL database to migrate. Defaults to the name of the current database, which it must match."})
+	ap.SupportsString(hostParam, "", "host", "The host of the MySQL server.")
+	ap.SupportsInt(portParam, "", "port", fmt.Sprintf("The port of the MySQL server. Defaults to %d.", defaultSourcePort))
+	ap.SupportsString(userParam, "u", "user", "The MySQL user to dump and replicate the database as.")
+	ap.SupportsString(passwordParam, "p", "password", "The password of the MySQL user. Defaults to the value of MYSQL_PWD.")
+	ap.SupportsInt(serverIdParam, "", "id", fmt.Sprintf("The @@server_id of this replica, which must differ from that of the MySQL server and of its other replicas. Defaults to %d.", defaultServerId))
+	ap.SupportsString(mysqldumpParam, "", "path", "The mysqldump executable. Defaults to the mysqldump on the PATH.")
+	ap.SupportsInt(parallelParam, "", "n", fmt.Sprintf("The most tables whose rows are imported at once. Defaults to %d.", defaultParallelism))
+	return ap
+}
+
+// EventType implements cli.Command.
+func (cmd ReplicateFromMysqlCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec implements cli.Command.
+func (cmd ReplicateFromMysqlCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, replicateFromMysqlDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	host, ok := apr.GetValue(hostParam)
+	if !ok {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: --%s must be given", hostParam).Build(), usage)
+	}
+	user, ok := apr.GetValue(userParam)
+	if !ok {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: --%s must be given", userParam).Build(), usage)
+	}
+	password, ok := apr.GetValue(passwordParam)
+	if !ok {
+		password = os.Getenv("MYSQL_PWD")
+	}
+	port := apr.GetIntOrDefault(portParam, defaultSourcePort)
+	serverId := apr.GetIntOrDefault(serverIdParam, defaultServerId)
+	if serverId <= 0 {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: --%s must be positive", serverIdParam).Build(), usage)
+	}
+	parallel := apr.GetIntOrDefault(parallelParam, defaultParallelism)
+	if parallel <= 0 {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: --%s must be positive", parallelParam).Build(), usage)
+	}
+
+	se, dbName, err := engine.NewSqlEngineForEnv(ctx, dEnv)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to build sql engine").AddCause(err).Build(), usage)
+	}
+	// the engine is closed before the replica is configured, since configuring it opens the databases again
+	closeEngine := func() {
+		if se != nil {
+			se.Close()
+			se = nil
+		}
+	}
+	defer closeEngine()
+
+	database := dbName
+	if apr.NArg() == 1 {
+		database = apr.Arg(0)
+	}
+	if !strings.EqualFold(database, dbName) {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: the current database, %s, must have the same name as the MySQL database %s to replicate it", dbName, database).Build(), usage)
+	}
+	if exists, _ := dEnv.FS.Exists(filepath.Join(commands.DefaultCfgDirName, "binlog-position")); exists {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: this server has already replicated from a source; run RESET REPLICA ALL on it first").Build(), usage)
+	}
+
+	source := net.JoinHostPort(host, strconv.Itoa(port))
+	cli.Printf("Taking a snapshot of %s on %s\n", database, source)
+	dumpPath, err := takeSnapshot(ctx, dEnv, apr.GetValueOrDefault(mysqldumpParam, "mysqldump"), host, port, user, password, database)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to take a snapshot of %s", database).AddCause(err).Build(), usage)
+	}
+	defer os.Remove(dumpPath)
+
+	imp := &dumpImporter{
+		se:       se,
+		database: dbName,
+		fs:       dEnv.FS,
+		parallel: parallel,
+		message:  fmt.Sprintf("Import %s from %s", database, source),
+	}
+	if err = imp.run(ctx, []dumpFile{{path: dumpPath}}); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: failed to import the snapshot of %s", database).AddCause(err).Build(), usage)
+	}
+	if imp.gtidPurged == "" {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: the snapshot was imported, but it doesn't record the GTIDs %s had executed, so it can't be replicated from; GTIDs must be enabled on the MySQL server", source).Build(), usage)
+	}
+	closeEngine()
+
+	if err = configureReplica(ctx, dEnv, dbName, serverId, host, port, user, password, imp.gtidPurged); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: the snapshot was imported, but replication couldn't be configured").AddCause(err).Build(), usage)
+	}
+
+	cli.Printf("Imported %s as of GTIDs %s\n", database, imp.gtidPurged)
+	cli.Printf("Replication from %s starts when dolt sql-server is next started in this directory.\n", source)
+	return 0
+}
+
+// takeSnapshot dumps |database| with mysqldump in a single transaction, recording the GTIDs the server had executed
+// when the transaction started, and returns the path of the dump.
+func takeSnapshot(ctx context.Context, dEnv *env.DoltEnv, mysqldump, host string, port int, user, password, database string) (string, error) {
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(tmpDir, "mysqldump-*.sql")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	cmd := exec.CommandContext(ctx, mysqldump,
+		"--single-transaction",
+		"--set-gtid-purged=ON",
+		"--routines",
+		"--triggers",
+		"--events",
+		"--host="+host,
+		"--port="+strconv.Itoa(port),
+		"--user="+user,
+		database)
+	cmd.Env = os.Environ()
+	if password != "" {
+		cmd.Env = append(cmd.Env, "MYSQL_PWD="+password)
+	}
+	cmd.Stdout = f
+	cmd.Stderr = cli.CliErr
+	if err = cmd.Run(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// configureReplica configures this server as a replica of the MySQL server at |host|:|port|, which starts
+// replicating from the GTIDs after |gtidPurged| when the server next starts. The replication source is stored in
+// the privileges file sql-server reads by default.
+func configureReplica(ctx context.Context, dEnv *env.DoltEnv, dbName string, serverId int, host string, port int, user, password, gtidPurged string) error {
+	cfgDirPath, err := dEnv.FS.Abs(commands.DefaultCfgDirName)
+	if err != nil {
+		return err
+	}
+	mrEnv, err := env.MultiEnvForDirectory(ctx, dEnv.Config.WriteableConfig(), dEnv.FS, dEnv.Version, dEnv)
+	if err != nil {
+		return err
+	}
+	se, err := engine.NewSqlEngine(ctx, mrEnv, &engine.SqlEngineConfig{
+		DoltCfgDirPath:          cfgDirPath,
+		PrivFilePath:            filepath.Join(cfgDirPath, commands.DefaultPrivsName),
+		ServerUser:              "root",
+		ServerHost:              "localhost",
+		Autocommit:              true,
+		BinlogReplicaController: binlogreplication.DoltBinlogReplicaController,
+	})
+	if err != nil {
+		return err
+	}
+	defer se.Close()
+
+	sqlCtx, err := se.NewLocalContext(ctx)
+	if err != nil {
+		return err
+	}
+	sqlCtx.SetCurrentDatabase(dbName)
+
+	// the replica starts from @@gtid_purged when it hasn't replicated before, as it does once a dump is loaded into it
+	if err = execQuery(se, sqlCtx, "SET @@PERSIST.server_id = ?", serverId); err != nil {
+		return err
+	}
+	if err = execQuery(se, sqlCtx, "SET @@PERSIST.gtid_purged = ?", gtidPurged); err != nil {
+		return err
+	}
+	err = execQuery(se, sqlCtx, "CHANGE REPLICATION SOURCE TO SOURCE_HOST = ?, SOURCE_PORT = ?, SOURCE_USER = ?, SOURCE_PASSWORD = ?", host, port, user, password)
+	if err != nil {
+		return err
+	}
+	return binlogreplication.PersistReplicaAutoStart(sqlCtx)
+}
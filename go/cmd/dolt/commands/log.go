@@ -18,9 +18,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/fatih/color"
@@ -57,7 +59,10 @@ The command takes options to control what is shown and how.
 	
 {{.EmphasisLeft}}dolt log <revisionB>...<revisionA>{{.EmphasisRight}}
 {{.EmphasisLeft}}dolt log <revisionA> <revisionB> --not $(dolt merge-base <revisionA> <revisionB>){{.EmphasisRight}}
-  Different ways to list three dot logs. These will list commit logs reachable by revisionA OR revisionB, while excluding commits reachable by BOTH revisionA AND revisionB.`,
+  Different ways to list three dot logs. These will list commit logs reachable by revisionA OR revisionB, while excluding commits reachable by BOTH revisionA AND revisionB.
+
+{{.EmphasisLeft}}dolt log --result-format json{{.EmphasisRight}}
+  Lists commit logs as a JSON document containing the hash, parents, author, date, message, and refs of each commit, suitable for consumption by scripts.`,
 	Synopsis: []string{
 		`[-n {{.LessThan}}num_commits{{.GreaterThan}}] [{{.LessThan}}revision-range{{.GreaterThan}}] [[--] {{.LessThan}}table{{.GreaterThan}}]`,
 	},
@@ -86,7 +91,9 @@ func (cmd LogCmd) Docs() *cli.CommandDocumentation {
 }
 
 func (cmd LogCmd) ArgParser() *argparser.ArgParser {
-	return cli.CreateLogArgParser(false)
+	ap := cli.CreateLogArgParser(false)
+	addResultFormatFlag(ap, "r")
+	return ap
 }
 
 func (cmd LogCmd) RequiresRepo() bool {
@@ -105,6 +112,10 @@ func (cmd LogCmd) logWithLoggerFunc(ctx context.Context, commandStr string, args
 		return status
 	}
 
+	if err := validateLogFormat(apr); err != nil {
+		return handleErrAndExit(err)
+	}
+
 	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
 	if err != nil {
 		return handleErrAndExit(err)
@@ -125,6 +136,15 @@ func (cmd LogCmd) logWithLoggerFunc(ctx context.Context, commandStr string, args
 	return handleErrAndExit(logCommits(apr, logRows, queryist, sqlCtx))
 }
 
+// validateLogFormat checks the --result-format argument, and that it isn't combined with options that only affect the
+// human-readable output.
+func validateLogFormat(apr *argparser.ArgParseResults) error {
+	if _, err := getResultFormat(apr); err != nil {
+		return err
+	}
+	return validateJsonResultFormatFlags(apr, cli.GraphFlag, cli.OneLineFlag, cli.StatFlag)
+}
+
 // constructInterpolatedDoltLogQuery generates the sql query necessary to call the DOLT_LOG() function.
 // Also interpolates this query to prevent sql injection.
 func constructInterpolatedDoltLogQuery(apr *argparser.ArgParseResults, queryist cli.Queryist, sqlCtx *sql.Context) (string, error) {
@@ -360,6 +380,11 @@ func logToStdOut(apr *argparser.ArgParseResults, commits []CommitInfo, sqlCtx *s
 		return nil
 	}
 	cli.ExecuteWithStdioRestored(func() {
+		if isJsonResultFormat(apr) {
+			err = logJson(cli.CliOut, commits)
+			return
+		}
+
 		pager := outputpager.Start()
 		defer pager.Stop()
 		if apr.Contains(cli.GraphFlag) {
@@ -374,6 +399,56 @@ func logToStdOut(apr *argparser.ArgParseResults, commits []CommitInfo, sqlCtx *s
 	return
 }
 
+// logJsonCommit is the machine-readable representation of a single commit in json log output.
+type logJsonCommit struct {
+	Hash    string   `json:"hash"`
+	Parents []string `json:"parents"`
+	Author  string   `json:"author"`
+	Email   string   `json:"email"`
+	Date    string   `json:"date"`
+	Message string   `json:"message"`
+	Refs    []string `json:"refs"`
+}
+
+// logJson writes the given commits to |wr| as a json document. Refs are always written in their fully qualified form.
+func logJson(wr io.Writer, commits []CommitInfo) error {
+	jsonCommits := make([]logJsonCommit, len(commits))
+	for i, comm := range commits {
+		refs := make([]string, 0, len(comm.localBranchNames)+len(comm.remoteBranchNames)+len(comm.tagNames)+1)
+		if comm.isHead {
+			refs = append(refs, "HEAD")
+		}
+		for _, b := range comm.localBranchNames {
+			refs = append(refs, "refs/heads/"+b)
+		}
+		for _, b := range comm.remoteBranchNames {
+			refs = append(refs, "refs/remotes/"+b)
+		}
+		for _, t := range comm.tagNames {
+			refs = append(refs, "refs/tags/"+t)
+		}
+
+		parents := comm.parentHashes
+		if parents == nil {
+			parents = []string{}
+		}
+
+		jsonCommits[i] = logJsonCommit{
+			Hash:    comm.commitHash,
+			Parents: parents,
+			Author:  comm.commitMeta.Name,
+			Email:   comm.commitMeta.Email,
+			Date:    comm.commitMeta.Time().UTC().Format(time.RFC3339),
+			Message: comm.commitMeta.Description,
+			Refs:    refs,
+		}
+	}
+
+	return writeJsonResult(wr, struct {
+		Commits []logJsonCommit `json:"commits"`
+	}{jsonCommits})
+}
+
 // printDiffStats prints the diff stats for a commit to a pager
 func printDiffStats(diffStats map[string]*merge.MergeStats, pager *outputpager.Pager) {
 	maxNameLen := 0
@@ -15,7 +15,9 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -27,6 +29,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/utils/osutil"
+	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/types"
 	"github.com/dolthub/dolt/go/store/util/outputpager"
 )
@@ -92,3 +95,46 @@ func TestLogSigterm(t *testing.T) {
 	err = process.Signal(syscall.SIGTERM)
 	require.NoError(t, err)
 }
+
+func TestLogJson(t *testing.T) {
+	commits := []CommitInfo{
+		{
+			commitMeta: &datas.CommitMeta{
+				Name:          "Bill Billerson",
+				Email:         "bigbillieb@fake.horse",
+				Description:   "merge",
+				UserTimestamp: 1000,
+			},
+			commitHash:        "c2",
+			isHead:            true,
+			parentHashes:      []string{"c1", "c0"},
+			localBranchNames:  []string{"main"},
+			remoteBranchNames: []string{"origin/main"},
+			tagNames:          []string{"v1"},
+		},
+		{
+			commitMeta: &datas.CommitMeta{Name: "Bill Billerson", Email: "bigbillieb@fake.horse", Description: "init"},
+			commitHash: "c0",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, logJson(&buf, commits))
+
+	var res struct {
+		Commits []logJsonCommit `json:"commits"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &res))
+	require.Len(t, res.Commits, 2)
+	require.Equal(t, logJsonCommit{
+		Hash:    "c2",
+		Parents: []string{"c1", "c0"},
+		Author:  "Bill Billerson",
+		Email:   "bigbillieb@fake.horse",
+		Date:    "1970-01-01T00:00:01Z",
+		Message: "merge",
+		Refs:    []string{"HEAD", "refs/heads/main", "refs/remotes/origin/main", "refs/tags/v1"},
+	}, res.Commits[0])
+	require.Equal(t, []string{}, res.Commits[1].Parents)
+	require.Equal(t, []string{}, res.Commits[1].Refs)
+}
@@ -73,12 +73,14 @@ func (cmd MergeCmd) Description() string {
 }
 
 func (cmd MergeCmd) Docs() *cli.CommandDocumentation {
-	ap := cli.CreateMergeArgParser()
+	ap := cmd.ArgParser()
 	return cli.NewCommandDocumentation(mergeDocs, ap)
 }
 
 func (cmd MergeCmd) ArgParser() *argparser.ArgParser {
-	return cli.CreateMergeArgParser()
+	ap := cli.CreateMergeArgParser()
+	addResultFormatFlag(ap, "r")
+	return ap
 }
 
 // EventType returns the type of the event to log
@@ -92,7 +94,7 @@ func (cmd MergeCmd) RequiresRepo() bool {
 
 // Exec executes the command
 func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
-	ap := cli.CreateMergeArgParser()
+	ap := cmd.ArgParser()
 	ap.SupportsFlag(cli.NoJsonMergeFlag, "", "Do not attempt to automatically resolve multiple changes to the same JSON value, report a conflict instead.")
 	apr, usage, terminate, status := ParseArgsOrPrintHelp(ap, commandStr, args, mergeDocs)
 	if terminate {
@@ -119,6 +121,13 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return 1
 	}
 
+	if _, err = getResultFormat(apr); err == nil {
+		err = validateJsonResultFormatFlags(apr, cli.AbortParam)
+	}
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
 	// allows merges that create conflicts to stick
 	_, _, _, err = queryist.Query(sqlCtx, "set @@dolt_force_transaction_commit = 1")
 	if err != nil {
@@ -161,6 +170,9 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return 1
 	}
 	if upToDate {
+		if isJsonResultFormat(apr) {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(printJsonResult(mergeJson{UpToDate: true, Tables: []mergeJsonTable{}})), usage)
+		}
 		// dolt uses "Everything up-to-date" message, but Git CLI uses "Already up to date".
 		cli.Println(doltdb.ErrUpToDate.Error())
 		return 0
@@ -188,6 +200,13 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 		fastFwd := getFastforward(mergeResultRow, dprocedures.MergeProcFFIndex)
 
+		if isJsonResultFormat(apr) {
+			if apr.Contains(cli.NoCommitFlag) {
+				return printMergeJson(fastFwd, queryist, sqlCtx, usage, headHash, mergeHash, "HEAD", "STAGED")
+			}
+			return printMergeJson(fastFwd, queryist, sqlCtx, usage, headHash, mergeHash, "HEAD^1", "HEAD")
+		}
+
 		if apr.Contains(cli.NoCommitFlag) {
 			return printMergeStats(fastFwd, apr, queryist, sqlCtx, usage, headHash, mergeHash, "HEAD", "STAGED")
 		}
@@ -407,6 +426,99 @@ func printMergeStats(fastForward bool,
 	return handleMergeErr(sqlCtx, queryist, nil, hasConflicts, hasConstraintViolations, usage)
 }
 
+// mergeJson is the machine-readable representation of dolt merge output.
+type mergeJson struct {
+	UpToDate             bool             `json:"up_to_date"`
+	FastForward          bool             `json:"fast_forward"`
+	Head                 string           `json:"head"`
+	Merged               string           `json:"merged"`
+	Conflicts            bool             `json:"conflicts"`
+	ConstraintViolations bool             `json:"constraint_violations"`
+	Tables               []mergeJsonTable `json:"tables"`
+}
+
+type mergeJsonTable struct {
+	Table                string `json:"table"`
+	Operation            string `json:"operation"`
+	RowsAdded            int    `json:"rows_added"`
+	RowsModified         int    `json:"rows_modified"`
+	RowsDeleted          int    `json:"rows_deleted"`
+	DataConflicts        int    `json:"data_conflicts"`
+	SchemaConflicts      int    `json:"schema_conflicts"`
+	ConstraintViolations int    `json:"constraint_violations"`
+}
+
+var mergeJsonOperations = map[merge.TableMergeOp]string{
+	merge.TableUnmodified: "unmodified",
+	merge.TableAdded:      "added",
+	merge.TableRemoved:    "removed",
+	merge.TableModified:   "modified",
+}
+
+// printMergeJson calculates the same merge stats as printMergeStats, and prints them as a json document. As with
+// printMergeStats, a non-zero exit code is returned if the merge left conflicts or constraint violations behind.
+func printMergeJson(fastForward bool,
+	queryist cli.Queryist,
+	sqlCtx *sql.Context,
+	usage cli.UsagePrinter,
+	headHash string,
+	mergeHash string,
+	fromRef string,
+	toRef string) int {
+
+	mergeStats := make(map[string]*merge.MergeStats)
+	mergeStats, noConflicts, err := calculateMergeConflicts(queryist, sqlCtx, mergeStats)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("merge finished, but could not calculate conflicts").AddCause(err).Build(), usage)
+	}
+
+	res := mergeJson{
+		FastForward: fastForward,
+		Head:        headHash,
+		Merged:      mergeHash,
+		Tables:      []mergeJsonTable{},
+	}
+
+	if noConflicts {
+		upToDate := false
+		mergeStats, upToDate, err = calculateMergeStats(queryist, sqlCtx, mergeStats, fromRef, toRef)
+		if err != nil {
+			if err.Error() == "error: unable to get diff summary from HEAD^1 to HEAD: invalid ancestor spec" {
+				upToDate = true
+			} else {
+				return HandleVErrAndExitCode(errhand.BuildDError("merge successful, but could not calculate stats").AddCause(err).Build(), usage)
+			}
+		}
+		res.UpToDate = upToDate
+	}
+
+	for tblName, stats := range mergeStats {
+		res.Conflicts = res.Conflicts || stats.HasConflicts()
+		res.ConstraintViolations = res.ConstraintViolations || stats.HasConstraintViolations()
+		res.Tables = append(res.Tables, mergeJsonTable{
+			Table:                tblName,
+			Operation:            mergeJsonOperations[stats.Operation],
+			RowsAdded:            stats.Adds,
+			RowsModified:         stats.Modifications,
+			RowsDeleted:          stats.Deletes,
+			DataConflicts:        stats.DataConflicts,
+			SchemaConflicts:      stats.SchemaConflicts,
+			ConstraintViolations: stats.ConstraintViolations,
+		})
+	}
+	sort.Slice(res.Tables, func(i, j int) bool {
+		return res.Tables[i].Table < res.Tables[j].Table
+	})
+
+	if err = printJsonResult(res); err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if res.Conflicts || res.ConstraintViolations {
+		return 1
+	}
+	return 0
+}
+
 // calculateMergeConflicts calculates the count of conflicts that occurred during the merge. Returns a map of table name to MergeStats,
 // a bool indicating whether there were any conflicts, and a bool indicating whether calculation was successful.
 func calculateMergeConflicts(queryist cli.Queryist, sqlCtx *sql.Context, mergeStats map[string]*merge.MergeStats) (map[string]*merge.MergeStats, bool, error) {
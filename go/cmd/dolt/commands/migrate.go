@@ -16,6 +16,7 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/dolthub/dolt/go/store/types"
@@ -40,10 +41,20 @@ var migrateDocs = cli.CommandDocumentationContent{
 	LongDesc: `Migrate is a multi-purpose command to update the data format of a Dolt database. Over time, development 
 on Dolt requires changes to the on-disk data format. These changes are necessary to improve Database performance and 
 correctness. Migrating to the latest format is therefore necessary for compatibility with the latest Dolt clients, and
-to take advantage of the newly released Dolt features.`,
+to take advantage of the newly released Dolt features.
+
+The database is migrated into the {{.EmphasisLeft}}.dolt/migration{{.EmphasisRight}} directory, and only replaced once 
+every commit has been migrated and verified. Progress is logged for each commit and table. If a migration is interrupted, 
+running {{.EmphasisLeft}}dolt migrate{{.EmphasisRight}} again resumes it, without migrating the commits already migrated.
+
+Once all the commits are migrated, the tables at the head of each branch are verified to have the same number of rows 
+and the same checksum as before the migration.
+
+If branches are given, only those branches are migrated, along with the tags and remote refs pointing to commits on 
+them. The other branches aren't kept. The checked out branch must be one of the branches migrated.`,
 
 	Synopsis: []string{
-		"[ --push ] [ --pull ]",
+		"[--drop-conflicts] [{{.LessThan}}branch{{.GreaterThan}}...]",
 	},
 }
 
@@ -64,7 +75,8 @@ func (cmd MigrateCmd) Docs() *cli.CommandDocumentation {
 }
 
 func (cmd MigrateCmd) ArgParser() *argparser.ArgParser {
-	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+	ap := argparser.NewArgParserWithVariableArgs(cmd.Name())
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"branch", "The branches to migrate. Defaults to all branches."})
 	ap.SupportsFlag(migrateDropConflictsFlag, "", "Drop any conflicts visited during the migration")
 	return ap
 }
@@ -81,7 +93,7 @@ func (cmd MigrateCmd) Exec(ctx context.Context, commandStr string, args []string
 	apr := cli.ParseArgsOrDie(ap, args, help)
 
 	dropConflicts := apr.Contains(migrateDropConflictsFlag)
-	if err := MigrateDatabase(ctx, dEnv, dropConflicts); err != nil {
+	if err := MigrateDatabase(ctx, dEnv, dropConflicts, apr.Args); err != nil {
 		verr := errhand.BuildDError("migration failed").AddCause(err).Build()
 		return HandleVErrAndExitCode(verr, usage)
 	}
@@ -89,18 +101,34 @@ func (cmd MigrateCmd) Exec(ctx context.Context, commandStr string, args []string
 	return 0 // unreachable
 }
 
-// MigrateDatabase migrates the NomsBinFormat of |dEnv.DoltDB|.
-func MigrateDatabase(ctx context.Context, dEnv *env.DoltEnv, dropConflicts bool) error {
+// MigrateDatabase migrates the NomsBinFormat of |dEnv.DoltDB|. If |branches| isn't empty, only those branches are
+// migrated.
+func MigrateDatabase(ctx context.Context, dEnv *env.DoltEnv, dropConflicts bool, branches []string) error {
+	if curr := dEnv.DoltDB.Format(); types.IsFormat_DOLT(curr) {
+		cli.Println("database is already migrated")
+		return nil
+	}
+
+	if len(branches) > 0 {
+		headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, b := range branches {
+			found = found || b == headRef.GetPath()
+		}
+		if !found {
+			return fmt.Errorf("the checked out branch %s must be migrated, add it to the branches to migrate", headRef.GetPath())
+		}
+	}
+
 	menv, err := migrate.NewEnvironment(ctx, dEnv)
 	if err != nil {
 		return err
 	}
 	menv.DropConflicts = dropConflicts
-
-	if curr := menv.Existing.DoltDB.Format(); types.IsFormat_DOLT(curr) {
-		cli.Println("database is already migrated")
-		return nil
-	}
+	menv.Branches = branches
 
 	p, err := menv.Migration.FS.Abs(".")
 	if err != nil {
@@ -113,5 +141,8 @@ func MigrateDatabase(ctx context.Context, dEnv *env.DoltEnv, dropConflicts bool)
 		return err
 	}
 
-	return migrate.SwapChunkStores(ctx, menv)
+	if err = migrate.SwapChunkStores(ctx, menv); err != nil {
+		return err
+	}
+	return migrate.RemoveMigrationDir(dEnv.FS)
 }
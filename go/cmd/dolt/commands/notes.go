@@ -0,0 +1,189 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var notesDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Add or inspect notes attached to commits`,
+	LongDesc: `Adds, shows, lists or removes notes attached to commits, without changing the commits themselves.
+
+Notes are kept in notes refs, such as {{.EmphasisLeft}}refs/notes/commits{{.EmphasisRight}}, which is used unless another is given with {{.EmphasisLeft}}--ref{{.EmphasisRight}}. Different notes refs can be used for different kinds of data, like review metadata or quality scores. Each notes ref points to a commit holding its notes, and every change to them is a new commit in its history.
+
+{{.EmphasisLeft}}list{{.EmphasisRight}} lists the commits with notes, or only {{.LessThan}}commit{{.GreaterThan}} if it has a note. {{.EmphasisLeft}}show{{.EmphasisRight}} prints the note of a commit. {{.EmphasisLeft}}add{{.EmphasisRight}} attaches the note given with {{.EmphasisLeft}}-m{{.EmphasisRight}} to a commit, and replaces an existing note only with {{.EmphasisLeft}}-f{{.EmphasisRight}}. {{.EmphasisLeft}}append{{.EmphasisRight}} adds to the existing note of a commit, separated by a blank line. {{.EmphasisLeft}}remove{{.EmphasisRight}} removes the note of a commit. The commit defaults to {{.EmphasisLeft}}HEAD{{.EmphasisRight}}.
+
+Notes refs are pushed by giving them as refspecs, e.g. ` + "`dolt push origin refs/notes/commits`" + `, and fetched with ` + "`dolt fetch --notes`" + `.
+
+Notes can also be added, appended to and removed with the {{.EmphasisLeft}}dolt_notes(){{.EmphasisRight}} stored procedure, and read from the {{.EmphasisLeft}}dolt_notes{{.EmphasisRight}} system table.`,
+	Synopsis: []string{
+		`[--ref {{.LessThan}}ref{{.GreaterThan}}] list [{{.LessThan}}commit{{.GreaterThan}}]`,
+		`[--ref {{.LessThan}}ref{{.GreaterThan}}] show [{{.LessThan}}commit{{.GreaterThan}}]`,
+		`[--ref {{.LessThan}}ref{{.GreaterThan}}] add [-f] -m {{.LessThan}}msg{{.GreaterThan}} [{{.LessThan}}commit{{.GreaterThan}}]`,
+		`[--ref {{.LessThan}}ref{{.GreaterThan}}] append -m {{.LessThan}}msg{{.GreaterThan}} [{{.LessThan}}commit{{.GreaterThan}}]`,
+		`[--ref {{.LessThan}}ref{{.GreaterThan}}] remove [{{.LessThan}}commit{{.GreaterThan}}]`,
+	},
+}
+
+type NotesCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd NotesCmd) Name() string {
+	return "notes"
+}
+
+// Description returns a description of the command
+func (cmd NotesCmd) Description() string {
+	return "Add or inspect notes attached to commits."
+}
+
+func (cmd NotesCmd) Docs() *cli.CommandDocumentation {
+	ap := cli.CreateNotesArgParser()
+	return cli.NewCommandDocumentation(notesDocs, ap)
+}
+
+func (cmd NotesCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateNotesArgParser()
+}
+
+// EventType returns the type of the event to log
+func (cmd NotesCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd NotesCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cli.CreateNotesArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, notesDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return handleStatusVErr(err)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	subcommand := "list"
+	if apr.NArg() > 0 {
+		subcommand = apr.Arg(0)
+	}
+	switch subcommand {
+	case "list", "show":
+		err = showNotes(queryist, sqlCtx, apr, subcommand)
+	case "add", "append", "remove":
+		err = changeNotes(queryist, sqlCtx, apr)
+	default:
+		err = fmt.Errorf("error: unknown subcommand '%s'", subcommand)
+	}
+	return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+}
+
+// showNotes prints the note of a commit for the show subcommand, or the commits with notes for the list subcommand.
+func showNotes(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults, subcommand string) error {
+	if apr.NArg() > 2 {
+		return errors.New("error: too many arguments")
+	} else if apr.Contains(cli.MessageArg) || apr.Contains(cli.ForceFlag) || apr.Contains(cli.AuthorParam) {
+		return fmt.Errorf("error: invalid option for %s", subcommand)
+	}
+	list := subcommand == "list"
+
+	refName, _ := apr.GetValue(cli.NotesRefParam)
+	nr, err := actions.ParseNotesRef(refName)
+	if err != nil {
+		return err
+	}
+
+	query := "select commit_hash, note from dolt_notes where notes_ref = ?"
+	params := []interface{}{nr.GetPath()}
+	if apr.NArg() > 1 || !list {
+		commitSpec := "HEAD"
+		if apr.NArg() > 1 {
+			commitSpec = apr.Arg(1)
+		}
+		commitHash, err := resolveNotesCommit(queryist, sqlCtx, commitSpec)
+		if err != nil {
+			return err
+		}
+		query += " and commit_hash = ?"
+		params = append(params, commitHash)
+	}
+	query += " order by commit_hash"
+
+	rows, err := InterpolateAndRunQuery(queryist, sqlCtx, query, params...)
+	if err != nil {
+		return err
+	}
+
+	if !list {
+		if len(rows) == 0 {
+			return fmt.Errorf("error: no note found for commit %s", params[1])
+		}
+		cli.Println(rows[0][1])
+		return nil
+	}
+	for _, row := range rows {
+		cli.Println(row[0])
+	}
+	return nil
+}
+
+// changeNotes adds, appends to or removes the note of a commit with the dolt_notes stored procedure.
+func changeNotes(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults) error {
+	query := "call dolt_notes(?"
+	params := []interface{}{apr.Arg(0)}
+	if apr.Contains(cli.ForceFlag) {
+		query += ", '--force'"
+	}
+	for _, opt := range []string{cli.NotesRefParam, cli.MessageArg, cli.AuthorParam} {
+		if val, ok := apr.GetValue(opt); ok {
+			query += fmt.Sprintf(", '--%s', ?", opt)
+			params = append(params, val)
+		}
+	}
+	for _, arg := range apr.Args[1:] {
+		query += ", ?"
+		params = append(params, arg)
+	}
+	query += ")"
+
+	_, err := InterpolateAndRunQuery(queryist, sqlCtx, query, params...)
+	if err != nil {
+		return fmt.Errorf("error: failed to %s note: %w", apr.Arg(0), err)
+	}
+	return nil
+}
+
+// resolveNotesCommit returns the hash of the commit |commitSpec| resolves to.
+func resolveNotesCommit(queryist cli.Queryist, sqlCtx *sql.Context, commitSpec string) (string, error) {
+	rows, err := InterpolateAndRunQuery(queryist, sqlCtx, "select hashof(?)", commitSpec)
+	if err != nil {
+		return "", fmt.Errorf("error: unable to resolve commit '%s': %w", commitSpec, err)
+	}
+	return fmt.Sprint(rows[0][0]), nil
+}
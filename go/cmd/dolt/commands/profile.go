@@ -44,17 +44,27 @@ var profileDocs = cli.CommandDocumentationContent{
 Adds a profile named {{.LessThan}}name{{.GreaterThan}}. Returns an error if the profile already exists.
 
 {{.EmphasisLeft}}remove{{.EmphasisRight}}, {{.EmphasisLeft}}rm{{.EmphasisRight}}
-Remove the profile named {{.LessThan}}name{{.GreaterThan}}.`,
+Remove the profile named {{.LessThan}}name{{.GreaterThan}}.
+
+Profiles are saved in the global config, or in the config of the current repository with {{.EmphasisLeft}}--local{{.EmphasisRight}}. A repository's profiles take precedence over global profiles with the same name when dolt is run in the repository.
+
+A profile can include other profiles with {{.EmphasisLeft}}--include{{.EmphasisRight}}, and inherits the values it doesn't set itself from them. Earlier includes take precedence over later ones.
+
+A profile can also save the arguments of a command with {{.EmphasisLeft}}--args{{.EmphasisRight}}, as the command name followed by its options, e.g. {{.EmphasisLeft}}--args "sql-server --port=3307 --loglevel=debug"{{.EmphasisRight}}. The saved options are applied when the command is run with the profile, unless they're given on the command line.
+
+Environment variables in profile values, such as {{.EmphasisLeft}}$PROD_PASSWORD{{.EmphasisRight}}, are expanded when the profile is used, so secrets don't need to be saved in the profile.`,
 	Synopsis: []string{
 		"[-v | --verbose]",
-		"add [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [--host {{.LessThan}}host{{.GreaterThan}}] [--port {{.LessThan}}port{{.GreaterThan}}] [--no-tls] [--data-dir {{.LessThan}}directory{{.GreaterThan}}] [--doltcfg-dir {{.LessThan}}directory{{.GreaterThan}}] [--privilege-file {{.LessThan}}privilege file{{.GreaterThan}}] [--branch-control-file {{.LessThan}}branch control file{{.GreaterThan}}] [--use-db {{.LessThan}}database{{.GreaterThan}}] {{.LessThan}}name{{.GreaterThan}}",
-		"remove {{.LessThan}}name{{.GreaterThan}}",
+		"add [--local] [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [--host {{.LessThan}}host{{.GreaterThan}}] [--port {{.LessThan}}port{{.GreaterThan}}] [--no-tls] [--data-dir {{.LessThan}}directory{{.GreaterThan}}] [--doltcfg-dir {{.LessThan}}directory{{.GreaterThan}}] [--privilege-file {{.LessThan}}privilege file{{.GreaterThan}}] [--branch-control-file {{.LessThan}}branch control file{{.GreaterThan}}] [--use-db {{.LessThan}}database{{.GreaterThan}}] [--include {{.LessThan}}profile{{.GreaterThan}}...] [--args {{.LessThan}}command args{{.GreaterThan}}...] {{.LessThan}}name{{.GreaterThan}}",
+		"remove [--local] {{.LessThan}}name{{.GreaterThan}}",
 	},
 }
 
 const (
 	addProfileId          = "add"
 	removeProfileId       = "remove"
+	includeProfileFlag    = "include"
+	profileArgsFlag       = "args"
 	GlobalCfgProfileKey   = "profile"
 	DefaultProfileName    = "default"
 	defaultProfileWarning = "Default profile has been added. All dolt commands taking global arguments will use this default profile until it is removed.\nWARNING: This will alter the behavior of commands which specify no `--profile`.\nIf you are using dolt in contexts where you expect a `.dolt` directory to be accessed, the default profile will be used instead."
@@ -81,6 +91,9 @@ func (cmd ProfileCmd) ArgParser() *argparser.ArgParser {
 	ap := cli.CreateGlobalArgParser("profile")
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"name", "Defines the name of the profile to add or remove."})
 	ap.SupportsFlag(cli.VerboseFlag, "v", "Includes full details when printing list of profiles.")
+	ap.SupportsFlag(localParamName, "", "Adds or removes a profile of the current repository instead of a global profile.")
+	ap.SupportsStringList(includeProfileFlag, "", "profile", "Profiles whose values are used for the values this profile doesn't set.")
+	ap.SupportsStringList(profileArgsFlag, "", "command args", "A command name followed by the options to run it with when using this profile.")
 	return ap
 }
 
@@ -122,12 +135,20 @@ func addProfile(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Verbo
 
 	profileName := strings.TrimSpace(apr.Arg(1))
 
-	p := newProfile(apr)
+	p, err := newProfile(apr)
+	if err != nil {
+		return errhand.BuildDError("error: %s", err).Build()
+	}
+	for _, include := range p.Include {
+		if include == profileName {
+			return errhand.BuildDError("error: profile %s cannot include itself", profileName).Build()
+		}
+	}
 	profStr := p.String()
 
-	cfg, ok := dEnv.Config.GetConfig(env.GlobalConfig)
-	if !ok {
-		return errhand.BuildDError("error: failed to get global config").Build()
+	cfg, verr := getProfileConfig(dEnv, apr)
+	if verr != nil {
+		return verr
 	}
 	//TODO: enable config to retrieve json objects instead of just strings
 	encodedProfiles, err := cfg.GetString(GlobalCfgProfileKey)
@@ -159,9 +180,11 @@ func addProfile(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Verbo
 		cli.Println(color.YellowString(defaultProfileWarning))
 	}
 
-	err = setGlobalConfigPermissions(dEnv)
-	if err != nil {
-		return errhand.BuildDError("error: failed to set permissions, %s", err).Build()
+	if !apr.Contains(localParamName) {
+		err = setGlobalConfigPermissions(dEnv)
+		if err != nil {
+			return errhand.BuildDError("error: failed to set permissions, %s", err).Build()
+		}
 	}
 
 	return nil
@@ -174,9 +197,9 @@ func removeProfile(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Ve
 
 	profileName := strings.TrimSpace(apr.Arg(1))
 
-	cfg, ok := dEnv.Config.GetConfig(env.GlobalConfig)
-	if !ok {
-		return errhand.BuildDError("error: failed to get global config").Build()
+	cfg, verr := getProfileConfig(dEnv, apr)
+	if verr != nil {
+		return verr
 	}
 	encodedProfiles, err := cfg.GetString(GlobalCfgProfileKey)
 	if err != nil {
@@ -206,30 +229,48 @@ func removeProfile(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Ve
 		}
 	}
 
-	err = setGlobalConfigPermissions(dEnv)
-	if err != nil {
-		return errhand.BuildDError("error: failed to set permissions, %s", err).Build()
+	if !apr.Contains(localParamName) {
+		err = setGlobalConfigPermissions(dEnv)
+		if err != nil {
+			return errhand.BuildDError("error: failed to set permissions, %s", err).Build()
+		}
 	}
 
 	return nil
 }
 
+// getProfileConfig returns the config profiles are added to or removed from, which is the local config of the current
+// repository if --local is given, and the global config otherwise.
+func getProfileConfig(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (config.ReadWriteConfig, errhand.VerboseError) {
+	if apr.Contains(localParamName) {
+		cfg, ok := dEnv.Config.GetConfig(env.LocalConfig)
+		if !ok {
+			return nil, errhand.BuildDError("error: --%s profiles can only be used in a dolt repository", localParamName).Build()
+		}
+		return cfg, nil
+	}
+	cfg, ok := dEnv.Config.GetConfig(env.GlobalConfig)
+	if !ok {
+		return nil, errhand.BuildDError("error: failed to get global config").Build()
+	}
+	return cfg, nil
+}
+
 func printProfiles(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	var configs []config.ReadableConfig
+	if cfg, ok := dEnv.Config.GetConfig(env.LocalConfig); ok {
+		configs = append(configs, cfg)
+	}
 	cfg, ok := dEnv.Config.GetConfig(env.GlobalConfig)
 	if !ok {
 		return errhand.BuildDError("error: failed to get global config").Build()
 	}
-	encodedProfiles, err := cfg.GetString(GlobalCfgProfileKey)
+	configs = append(configs, cfg)
+
+	profilesJSON, err := LoadProfiles(configs...)
 	if err != nil {
-		if err == config.ErrConfigParamNotFound {
-			return nil
-		}
 		return errhand.BuildDError("error: failed to get profiles, %s", err).Build()
 	}
-	profilesJSON, err := DecodeProfile(encodedProfiles)
-	if err != nil {
-		return errhand.BuildDError("error: failed to decode profiles, %s", err).Build()
-	}
 
 	profileMap := gjson.Parse(profilesJSON)
 	if !profileMap.Exists() {
@@ -259,6 +300,12 @@ func prettyPrintProfile(profileName string, profile Profile, verbose bool) {
 			cli.Println(fmt.Sprintf("\tuser: %s\n\thost: %s\n\tport: %s\n\tno-tls: %t\n\tdata-dir: %s\n\tdoltcfg-dir: %s\n\tprivilege-file: %s\n\tbranch-control-file: %s\n\tuse-db: %s\n",
 				profile.User, profile.Host, profile.Port, profile.NoTLS, profile.DataDir, profile.DoltCfgDir, profile.PrivilegeFile, profile.BranchControl, profile.UseDB))
 		}
+		if len(profile.Include) > 0 {
+			cli.Println(fmt.Sprintf("\tinclude: %s", strings.Join(profile.Include, ", ")))
+		}
+		for command, args := range profile.Args {
+			cli.Println(fmt.Sprintf("\targs: %s %s", command, strings.Join(args, " ")))
+		}
 	}
 }
 
@@ -316,17 +363,19 @@ func decodeProfileAndCheckExists(profileName, encodedProfiles string) (string, b
 }
 
 type Profile struct {
-	User          string `json:"user"`
-	Password      string `json:"password"`
-	HasPassword   bool   `json:"has-password"`
-	Host          string `json:"host"`
-	Port          string `json:"port"`
-	NoTLS         bool   `json:"no-tls"`
-	DataDir       string `json:"data-dir"`
-	DoltCfgDir    string `json:"doltcfg-dir"`
-	PrivilegeFile string `json:"privilege-file"`
-	BranchControl string `json:"branch-control-file"`
-	UseDB         string `json:"use-db"`
+	User          string              `json:"user"`
+	Password      string              `json:"password"`
+	HasPassword   bool                `json:"has-password"`
+	Host          string              `json:"host"`
+	Port          string              `json:"port"`
+	NoTLS         bool                `json:"no-tls"`
+	DataDir       string              `json:"data-dir"`
+	DoltCfgDir    string              `json:"doltcfg-dir"`
+	PrivilegeFile string              `json:"privilege-file"`
+	BranchControl string              `json:"branch-control-file"`
+	UseDB         string              `json:"use-db"`
+	Include       []string            `json:"include,omitempty"`
+	Args          map[string][]string `json:"args,omitempty"`
 }
 
 func (p Profile) String() string {
@@ -337,8 +386,60 @@ func (p Profile) String() string {
 	return string(b)
 }
 
-func newProfile(apr *argparser.ArgParseResults) Profile {
-	return Profile{
+// inherit returns |p| with the values it doesn't set taken from |base|.
+func (p Profile) inherit(base Profile) Profile {
+	inheritString := func(s *string, baseVal string) {
+		if *s == "" {
+			*s = baseVal
+		}
+	}
+	inheritString(&p.User, base.User)
+	inheritString(&p.Host, base.Host)
+	inheritString(&p.Port, base.Port)
+	inheritString(&p.DataDir, base.DataDir)
+	inheritString(&p.DoltCfgDir, base.DoltCfgDir)
+	inheritString(&p.PrivilegeFile, base.PrivilegeFile)
+	inheritString(&p.BranchControl, base.BranchControl)
+	inheritString(&p.UseDB, base.UseDB)
+	if !p.HasPassword {
+		p.Password, p.HasPassword = base.Password, base.HasPassword
+	}
+	p.NoTLS = p.NoTLS || base.NoTLS
+
+	if len(base.Args) > 0 {
+		args := make(map[string][]string, len(base.Args)+len(p.Args))
+		for command, a := range base.Args {
+			args[command] = a
+		}
+		for command, a := range p.Args {
+			args[command] = a
+		}
+		p.Args = args
+	}
+	return p
+}
+
+// ExpandEnv returns |p| with the environment variables in its values replaced by their values.
+func (p Profile) ExpandEnv() Profile {
+	for _, s := range []*string{&p.User, &p.Password, &p.Host, &p.Port, &p.DataDir, &p.DoltCfgDir, &p.PrivilegeFile, &p.BranchControl, &p.UseDB} {
+		*s = os.ExpandEnv(*s)
+	}
+	if len(p.Args) > 0 {
+		args := make(map[string][]string, len(p.Args))
+		for command, a := range p.Args {
+			expanded := make([]string, len(a))
+			for i := range a {
+				expanded[i] = os.ExpandEnv(a[i])
+			}
+			args[command] = expanded
+		}
+		p.Args = args
+	}
+	return p
+}
+
+func newProfile(apr *argparser.ArgParseResults) (Profile, error) {
+	p := Profile{
 		User:          apr.GetValueOrDefault(cli.UserFlag, ""),
 		Password:      apr.GetValueOrDefault(cli.PasswordFlag, ""),
 		HasPassword:   apr.Contains(cli.PasswordFlag),
@@ -351,4 +452,86 @@ func newProfile(apr *argparser.ArgParseResults) Profile {
 		BranchControl: apr.GetValueOrDefault(BranchCtrlPathFlag, ""),
 		UseDB:         apr.GetValueOrDefault(UseDbFlag, ""),
 	}
+	if includes, ok := apr.GetValueList(includeProfileFlag); ok {
+		for _, include := range includes {
+			if include = strings.TrimSpace(include); include != "" {
+				p.Include = append(p.Include, include)
+			}
+		}
+	}
+	if commandArgs, ok := apr.GetValueList(profileArgsFlag); ok {
+		p.Args = make(map[string][]string)
+		for _, ca := range commandArgs {
+			fields := strings.Fields(ca)
+			if len(fields) == 0 {
+				continue
+			}
+			if _, ok := p.Args[fields[0]]; ok {
+				return Profile{}, fmt.Errorf("multiple --%s given for command %s", profileArgsFlag, fields[0])
+			}
+			p.Args[fields[0]] = fields[1:]
+		}
+	}
+	return p, nil
+}
+
+// LoadProfiles returns the profiles saved in |configs| as a JSON object of profiles by name. A profile in an earlier
+// config takes precedence over a profile with the same name in a later config. Returns an empty string if there are
+// no profiles.
+func LoadProfiles(configs ...config.ReadableConfig) (string, error) {
+	profilesJSON := ""
+	for i := len(configs) - 1; i >= 0; i-- {
+		encodedProfiles, err := configs[i].GetString(GlobalCfgProfileKey)
+		if err == config.ErrConfigParamNotFound {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		decoded, err := DecodeProfile(encodedProfiles)
+		if err != nil {
+			return "", err
+		}
+		if profilesJSON == "" {
+			profilesJSON = decoded
+			continue
+		}
+		for name, profile := range gjson.Parse(decoded).Map() {
+			profilesJSON, err = sjson.SetRaw(profilesJSON, name, profile.Raw)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	return profilesJSON, nil
+}
+
+// ResolveProfile returns the profile named |profileName| in |profiles|, with the values it doesn't set taken from the
+// profiles it includes.
+func ResolveProfile(profiles, profileName string) (Profile, error) {
+	return resolveProfile(profiles, profileName, make(map[string]bool))
+}
+
+func resolveProfile(profiles, profileName string, resolving map[string]bool) (Profile, error) {
+	prof := gjson.Get(profiles, profileName)
+	if !prof.Exists() {
+		return Profile{}, fmt.Errorf("profile %s not found", profileName)
+	}
+	if resolving[profileName] {
+		return Profile{}, fmt.Errorf("profile %s includes itself", profileName)
+	}
+	resolving[profileName] = true
+	defer delete(resolving, profileName)
+
+	var p Profile
+	if err := json.Unmarshal([]byte(prof.Raw), &p); err != nil {
+		return Profile{}, err
+	}
+	for _, include := range p.Include {
+		base, err := resolveProfile(profiles, include, resolving)
+		if err != nil {
+			return Profile{}, err
+		}
+		p = p.inherit(base)
+	}
+	return p, nil
 }
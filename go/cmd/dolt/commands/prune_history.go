@@ -0,0 +1,166 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rebase"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/chunks"
+)
+
+const (
+	beforeFlag = "before"
+	noGCFlag   = "no-gc"
+)
+
+var pruneHistoryDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Permanently removes old commits from the history",
+	LongDesc: `Rewrites the history of every branch and tag to drop the commits before {{.LessThan}}commit{{.GreaterThan}}, or the commits made before the date given with {{.EmphasisLeft}}--before{{.EmphasisRight}}, and then garbage collects the data which is no longer referenced.
+
+The oldest commits which are kept become root commits of the rewritten history, and keep all of the data they had. The commit at the head of a branch or tag is always kept. Every commit after the pruned history is rewritten, so commit hashes change, and the rewritten branches can't be pushed to remotes which still have the old history without {{.EmphasisLeft}}--force{{.EmphasisRight}}.
+
+The pruned commits are still kept by garbage collection while other refs, such as remote tracking branches, workspaces or stashes, reference them. Remove those refs first to delete the pruned data from the repository entirely. If {{.EmphasisLeft}}--no-gc{{.EmphasisRight}} is supplied, the history is rewritten but the pruned data isn't removed until the next {{.EmphasisLeft}}dolt gc{{.EmphasisRight}}.
+`,
+	Synopsis: []string{
+		"[--no-gc] {{.LessThan}}commit{{.GreaterThan}}",
+		"[--no-gc] --before {{.LessThan}}date{{.GreaterThan}}",
+	},
+}
+
+type PruneHistoryCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd PruneHistoryCmd) Name() string {
+	return "prune-history"
+}
+
+// Description returns a description of the command
+func (cmd PruneHistoryCmd) Description() string {
+	return fmt.Sprintf("%s.", pruneHistoryDocs.ShortDesc)
+}
+
+func (cmd PruneHistoryCmd) RequiresRepo() bool {
+	return true
+}
+
+func (cmd PruneHistoryCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(pruneHistoryDocs, ap)
+}
+
+func (cmd PruneHistoryCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "The oldest commit to keep. Every commit before it is pruned."})
+	ap.SupportsString(beforeFlag, "", "date", "Prune the commits made before the given date.")
+	ap.SupportsFlag(noGCFlag, "", "Rewrite the history without garbage collecting the pruned data.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd PruneHistoryCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd PruneHistoryCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, pruneHistoryDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if !cli.CheckEnvIsValid(dEnv) {
+		return 2
+	}
+
+	before, hasBefore := apr.GetValue(beforeFlag)
+	if hasBefore == (apr.NArg() == 1) {
+		verr := errhand.BuildDError("error: exactly one of a commit or --%s must be given", beforeFlag).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	var prune rebase.PruneFn
+	if hasBefore {
+		t, err := dconfig.ParseDate(before)
+		if err != nil {
+			verr := errhand.BuildDError("error: invalid date '%s'", before).AddCause(err).Build()
+			return HandleVErrAndExitCode(verr, usage)
+		}
+		prune = rebase.PruneOlderThan(t)
+	} else {
+		graft, err := resolvePruneGraft(ctx, dEnv, apr.Arg(0))
+		if err != nil {
+			verr := errhand.BuildDError("error: unable to resolve commit '%s'", apr.Arg(0)).AddCause(err).Build()
+			return HandleVErrAndExitCode(verr, usage)
+		}
+		prune, err = rebase.PruneAncestorsOf(ctx, dEnv.DoltDB, graft)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+	}
+
+	rewritten, err := rebase.PruneHistory(ctx, dEnv.DoltDB, prune)
+	if err != nil {
+		verr := errhand.BuildDError("error: failed to prune history").AddCause(err).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+	if len(rewritten) == 0 {
+		cli.Println("Nothing to prune.")
+		return 0
+	}
+	for _, r := range rewritten {
+		cli.Printf("Pruned the history of %s\n", r.String())
+	}
+
+	if apr.Contains(noGCFlag) {
+		return 0
+	}
+	// the pruned commits may have been the head of a branch recently, so retention must be disabled to collect them
+	err = dEnv.DoltDB.GC(ctx, time.Time{}, nil)
+	if err != nil && !errors.Is(err, chunks.ErrNothingToCollect) {
+		verr := errhand.BuildDError("error: failed to garbage collect the pruned history").AddCause(err).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+	return 0
+}
+
+func resolvePruneGraft(ctx context.Context, dEnv *env.DoltEnv, spec string) (*doltdb.Commit, error) {
+	cs, err := doltdb.NewCommitSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		return nil, err
+	}
+	optCmt, err := dEnv.DoltDB.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	return cm, nil
+}
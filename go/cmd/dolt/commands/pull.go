@@ -41,6 +41,8 @@ var pullDocs = cli.CommandDocumentationContent{
 	LongDesc: `Incorporates changes from a remote repository into the current branch. In its default mode, {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} is shorthand for {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} followed by {{.EmphasisLeft}}dolt merge <remote>/<branch>{{.EmphasisRight}}.
 
 More precisely, dolt pull runs {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} with the given parameters and calls {{.EmphasisLeft}}dolt merge{{.EmphasisRight}} to merge the retrieved branch {{.EmphasisLeft}}HEAD{{.EmphasisRight}} into the current branch.
+
+With {{.EmphasisLeft}}--rebase{{.EmphasisRight}}, or when the {{.EmphasisLeft}}pull.rebase{{.EmphasisRight}} config is {{.EmphasisLeft}}true{{.EmphasisRight}}, the commits on the current branch which aren't on the retrieved branch are rebased on top of it instead, keeping history linear. If a commit being rebased conflicts with the retrieved branch, the rebase stops on the {{.EmphasisLeft}}dolt_rebase_{{.LessThan}}branch{{.GreaterThan}}{{.EmphasisRight}} branch so that the conflicts can be resolved and the resolved tables staged. Then {{.EmphasisLeft}}dolt rebase --continue{{.EmphasisRight}} finishes the rebase, or {{.EmphasisLeft}}dolt rebase --abort{{.EmphasisRight}} returns the current branch to how it was before the pull.
 `,
 	Synopsis: []string{
 		`[{{.LessThan}}remote{{.GreaterThan}}, [{{.LessThan}}remoteBranch{{.GreaterThan}}]]`,
@@ -91,6 +93,10 @@ func (cmd PullCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		verr := errhand.VerboseErrorFromError(errors.New(fmt.Sprintf(ErrConflictingFlags, cli.SquashParam, cli.NoFFParam)))
 		return HandleVErrAndExitCode(verr, usage)
 	}
+	if apr.ContainsAll(cli.RebaseFlag, cli.NoRebaseFlag) {
+		verr := errhand.VerboseErrorFromError(errors.New(fmt.Sprintf(ErrConflictingFlags, cli.RebaseFlag, cli.NoRebaseFlag)))
+		return HandleVErrAndExitCode(verr, usage)
+	}
 	// This command may create a commit, so we need user identity
 	if !cli.CheckUserNameAndEmail(cliCtx.Config()) {
 		bdr := errhand.BuildDError("Could not determine name and/or email.")
@@ -129,6 +135,11 @@ func (cmd PullCmd) Exec(ctx context.Context, commandStr string, args []string, d
 			cli.Println("failed to get hash of HEAD, pull not started")
 			errChan <- err
 		}
+		branchName, err := getActiveBranchName(sqlCtx, queryist)
+		if err != nil {
+			errChan <- err
+			return
+		}
 
 		_, rowIter, _, err := queryist.Query(sqlCtx, query)
 		if err != nil {
@@ -153,6 +164,23 @@ func (cmd PullCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		}
 		row := rows[0]
 
+		// A pull which rebases reports how it went in the message, and a conflict leaves the session on the branch
+		// used for rebasing, which later commands need to run on to resolve the conflicts
+		if message, ok := row[2].(string); ok && strings.HasPrefix(message, dprocedures.SuccessfulRebaseMessage) {
+			cli.Println(message)
+			return
+		}
+		activeBranch, err := syncHeadBranch(sqlCtx, queryist, dEnv, branchName)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		if activeBranch != branchName {
+			message, _ := row[2].(string)
+			errChan <- errors.New(message)
+			return
+		}
+
 		remoteHash, remoteRef, err := getRemoteHashForPull(apr, sqlCtx, queryist)
 		if err != nil {
 			cli.Println("pull finished, but failed to get hash of remote ref")
@@ -254,6 +282,12 @@ func constructInterpolatedDoltPullQuery(apr *argparser.ArgParseResults) (string,
 	if apr.Contains(cli.NoEditFlag) {
 		args = append(args, "'--no-edit'")
 	}
+	if apr.Contains(cli.RebaseFlag) {
+		args = append(args, "'--rebase'")
+	}
+	if apr.Contains(cli.NoRebaseFlag) {
+		args = append(args, "'--no-rebase'")
+	}
 	if user, hasUser := apr.GetValue(cli.UserFlag); hasUser {
 		args = append(args, "'--user'")
 		args = append(args, "?")
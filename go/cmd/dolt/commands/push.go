@@ -32,6 +32,7 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
@@ -49,11 +50,18 @@ When the command line does not specify what to push with {{.LessThan}}refspec{{.
 
 A remote's branch can be deleted by pushing an empty source ref: ` + "`dolt push origin :branch`" + `
 
+With {{.EmphasisLeft}}--tags{{.EmphasisRight}}, every local tag is pushed along with the refspecs given, and the current branch is only pushed if it's given as a refspec.
+
+Notes refs are pushed by giving them as refspecs, e.g. ` + "`dolt push origin refs/notes/commits`" + `. A notes ref is only updated in the remote if it's a fast-forward, unless {{.EmphasisLeft}}--force{{.EmphasisRight}} is given.
+
 When neither the command-line does not specify what to push, the default behavior is used, which corresponds to the current branch being pushed to the corresponding upstream branch, but as a safety measure, the push is aborted if the upstream branch does not have the same name as the local one.
+
+If the repository has an executable {{.EmphasisLeft}}.dolt/hooks/pre-push{{.EmphasisRight}} hook it is run before anything is pushed, with the remote and refspecs given on the command line as its arguments, and the push is aborted if it exits with a non-zero status. The hook receives one line per ref being pushed on stdin, containing the ref and the commit hash it resolves to. Use {{.EmphasisLeft}}--no-verify{{.EmphasisRight}} to skip the hook.
 `,
 
 	Synopsis: []string{
 		"[-u | --set-upstream] [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}}]",
+		"--tags [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}}...]",
 	},
 }
 
@@ -75,7 +83,10 @@ func (cmd PushCmd) Docs() *cli.CommandDocumentation {
 }
 
 func (cmd PushCmd) ArgParser() *argparser.ArgParser {
-	return cli.CreatePushArgParser()
+	ap := cli.CreatePushArgParser()
+	ap.SupportsFlag(cli.NoVerifyFlag, "", "Do not run the {{.EmphasisLeft}}pre-push{{.EmphasisRight}} hook.")
+	addResultFormatFlag(ap, "r")
+	return ap
 }
 
 // EventType returns the type of the event to log
@@ -89,6 +100,11 @@ func (cmd PushCmd) Exec(ctx context.Context, commandStr string, args []string, d
 	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, pushDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
 
+	if _, err := getResultFormat(apr); err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	jsonOutput := isJsonResultFormat(apr)
+
 	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
@@ -97,6 +113,18 @@ func (cmd PushCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		defer closeFunc()
 	}
 
+	if !apr.Contains(cli.NoVerifyFlag) {
+		if _, ok := clientHookPath(dEnv, prePushHook); ok {
+			input, err := prePushHookInput(queryist, sqlCtx, apr.Args[min(1, apr.NArg()):], apr.Contains(cli.AllFlag))
+			if err != nil {
+				return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+			}
+			if err = runClientHook(ctx, dEnv, prePushHook, apr.Args, input); err != nil {
+				return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+			}
+		}
+	}
+
 	query, err := constructInterpolatedDoltPushQuery(apr)
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
@@ -116,11 +144,16 @@ func (cmd PushCmd) Exec(ctx context.Context, commandStr string, args []string, d
 			errChan <- err
 			return
 		}
+		if jsonOutput {
+			errChan <- printPushResultJson(sqlRows)
+			return
+		}
 		printPushResult(sqlRows)
 	}()
 
+	silent := apr.Contains(cli.SilentFlag) || jsonOutput
 	spinner := TextSpinner{}
-	if !apr.Contains(cli.SilentFlag) {
+	if !silent {
 		cli.Print(spinner.next() + " Uploading...")
 		defer func() {
 			cli.DeleteAndPrint(len(" Uploading...")+1, "")
@@ -144,7 +177,7 @@ func (cmd PushCmd) Exec(ctx context.Context, commandStr string, args []string, d
 			}
 			return HandleVErrAndExitCode(nil, usage)
 		case <-time.After(time.Millisecond * 50):
-			if !apr.Contains(cli.SilentFlag) {
+			if !silent {
 				cli.DeleteAndPrint(len(" Uploading...")+1, spinner.next()+" Uploading...")
 			}
 		}
@@ -172,6 +205,9 @@ func constructInterpolatedDoltPushQuery(apr *argparser.ArgParseResults) (string,
 	if all := apr.Contains(cli.AllFlag); all {
 		args = append(args, fmt.Sprintf("'--%s'", cli.AllFlag))
 	}
+	if apr.Contains(cli.TagsFlag) {
+		args = append(args, fmt.Sprintf("'--%s'", cli.TagsFlag))
+	}
 	for _, arg := range apr.Args {
 		args = append(args, "?")
 		params = append(params, arg)
@@ -194,6 +230,24 @@ func printPushResult(rows []sql.Row) {
 	}
 }
 
+// pushJson is the machine-readable representation of dolt push output.
+type pushJson struct {
+	UpToDate bool   `json:"up_to_date"`
+	Message  string `json:"message"`
+}
+
+// printPushResultJson prints the result of a successful push as a json document.
+func printPushResultJson(rows []sql.Row) error {
+	var res pushJson
+	if len(rows[0]) > 1 {
+		if msg, ok := rows[0][1].(string); ok {
+			res.Message = msg
+		}
+	}
+	res.UpToDate = res.Message == doltdb.ErrUpToDate.Error()
+	return printJsonResult(res)
+}
+
 // handlePushError prints the appropriate error message and returns the exit code
 func handlePushError(err error, usage cli.UsagePrinter) int {
 	if err == nil {
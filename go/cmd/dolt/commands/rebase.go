@@ -46,6 +46,11 @@ the same as Git and Dolt's "two dot log" syntax, or |upstreamBranch|..|currentBr
 Rebasing is useful to clean and organize your commit history, especially before merging a feature branch back to a shared 
 branch. For example, you can drop commits that contain debugging or test changes, or squash or fixup small commits into a 
 single commit, or reorder commits so that related changes are adjacent in the new commit history.
+
+A rebase started by {{.EmphasisLeft}}dolt pull --rebase{{.EmphasisRight}} stops when a commit conflicts, leaving the conflicts to be resolved on 
+the {{.EmphasisLeft}}dolt_rebase_{{.LessThan}}branch{{.GreaterThan}}{{.EmphasisRight}} branch. Once the resolved tables are staged with {{.EmphasisLeft}}dolt add{{.EmphasisRight}}, 
+{{.EmphasisLeft}}--continue{{.EmphasisRight}} commits them and rebases the remaining commits, and {{.EmphasisLeft}}--abort{{.EmphasisRight}} returns the branch to 
+how it was before the rebase.
 `,
 	Synopsis: []string{
 		`(-i | --interactive) {{.LessThan}}upstream{{.GreaterThan}}`,
@@ -105,6 +110,12 @@ func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
+	// allows a rebase paused by conflicts to stick
+	_, err = GetRowsForSql(queryist, sqlCtx, "set @@dolt_force_transaction_commit = 1")
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
 	rows, err := GetRowsForSql(queryist, sqlCtx, query)
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
@@ -120,8 +131,17 @@ func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	message := rows[0][1].(string)
 	if strings.Contains(message, dprocedures.SuccessfulRebaseMessage) {
+		// continuing a rebase paused by conflicts moves the session back to the rebased branch
+		branchName, err = syncHeadBranch(sqlCtx, queryist, dEnv, branchName)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
 		cli.Println(dprocedures.SuccessfulRebaseMessage + branchName)
 	} else if strings.Contains(message, dprocedures.RebaseAbortedMessage) {
+		_, err = syncHeadBranch(sqlCtx, queryist, dEnv, branchName)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
 		cli.Println(dprocedures.RebaseAbortedMessage)
 	} else {
 		rebasePlan, err := getRebasePlan(cliCtx, sqlCtx, queryist, apr.Arg(0), branchName)
@@ -179,6 +199,27 @@ func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string,
 	return HandleVErrAndExitCode(nil, usage)
 }
 
+// syncHeadBranch saves the branch checked out in the session to the repo state if it's no longer |prevBranch|, which
+// happens when a rebase stops on the branch used for rebasing, or finishes or is aborted there, so that later commands
+// run on the same branch. Returns the branch checked out in the session.
+func syncHeadBranch(sqlCtx *sql.Context, queryist cli.Queryist, dEnv *env.DoltEnv, prevBranch string) (string, error) {
+	branchName, err := getActiveBranchName(sqlCtx, queryist)
+	if err != nil {
+		return "", err
+	}
+	if branchName != prevBranch && dEnv != nil {
+		err = saveHeadBranch(dEnv.FS, branchName)
+		if err != nil {
+			return "", err
+		}
+		err = dEnv.ReloadRepoState()
+		if err != nil {
+			return "", err
+		}
+	}
+	return branchName, nil
+}
+
 // getRebasePlan opens an editor for users to edit the rebase plan and returns the parsed rebase plan from the editor.
 func getRebasePlan(cliCtx cli.CliContext, sqlCtx *sql.Context, queryist cli.Queryist, rebaseBranch, currentBranch string) (*rebase.RebasePlan, error) {
 	if cli.ExecuteWithStdioRestored == nil {
@@ -17,6 +17,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -38,11 +39,16 @@ or tag changed over time to reference different commits, particularly for inform
 The data from Dolt's reflog comes from [Dolt's journaling chunk store](https://www.dolthub.com/blog/2023-03-08-dolt-chunk-journal/). 
 This data is local to a Dolt database and never included when pushing, pulling, or cloning a Dolt database. This means when you clone a Dolt database, it will not have any reflog data until you perform operations that change what commit branches or tags reference.
 
+Reflog entries are kept in the chunk journal until the next garbage collection, which moves them to a reflog archive stored alongside it, so the history of a ref survives {{.EmphasisLeft}}dolt gc{{.EmphasisRight}}. Commits which are only referenced by the reflog are still removed by garbage collection, unless they are kept by the retention window set with {{.EmphasisLeft}}dolt gc --prune-older-than{{.EmphasisRight}} or the {{.EmphasisLeft}}dolt_gc_retention_days{{.EmphasisRight}} system variable.
+
+{{.EmphasisLeft}}dolt reflog restore {{.LessThan}}ref{{.GreaterThan}}@{n}{{.EmphasisRight}} resurrects the commit that {{.LessThan}}ref{{.GreaterThan}} referenced {{.LessThan}}n{{.GreaterThan}} changes before its most recent reflog entry. {{.EmphasisLeft}}@{0}{{.EmphasisRight}}, or no suffix at all, is the most recent entry, which for a deleted branch is the commit it referenced when it was deleted. The ref is recreated as the branch or tag it was recorded for, unless {{.EmphasisLeft}}--branch{{.EmphasisRight}} is given, in which case a branch with that name is created at the commit instead. Existing branches and tags are only reset with {{.EmphasisLeft}}--force{{.EmphasisRight}} ({{.EmphasisLeft}}-f{{.EmphasisRight}}).
+
 Dolt's reflog is similar to [Git's reflog](https://git-scm.com/docs/git-reflog), but there are a few differences:
 - The Dolt reflog currently only supports named references, such as branches and tags, and not any of Git's special refs (e.g. {{.EmphasisLeft}}HEAD{{.EmphasisRight}}, {{.EmphasisLeft}}FETCH-HEAD{{.EmphasisRight}}, {{.EmphasisLeft}}MERGE-HEAD{{.EmphasisRight}}).
 - The Dolt reflog can be queried for the log of references, even after a reference has been deleted. In Git, once a branch or tag is deleted, the reflog for that ref is also deleted and to find the last commit a branch or tag pointed to you have to use Git's special {{.EmphasisLeft}}HEAD{{.EmphasisRight}} reflog to find the commit, which can sometimes be challenging. Dolt makes this much easier by allowing you to see the history for a deleted ref so you can easily see the last commit a branch or tag pointed to before it was deleted.`,
 	Synopsis: []string{
 		`[--all] {{.LessThan}}ref{{.GreaterThan}}`,
+		`restore [-f] [-b {{.LessThan}}branch{{.GreaterThan}}] {{.LessThan}}ref{{.GreaterThan}}[@{n}]`,
 	},
 }
 
@@ -78,6 +84,10 @@ func (cmd ReflogCmd) RequiresRepo() bool {
 
 // Exec executes the command
 func (cmd ReflogCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	if len(args) > 0 && args[0] == reflogRestoreSubcommand {
+		return restoreReflogEntry(ctx, commandStr, args[1:], cliCtx)
+	}
+
 	ap := cmd.ArgParser()
 	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, reflogDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
@@ -126,6 +136,137 @@ func constructInterpolatedDoltReflogQuery(apr *argparser.ArgParseResults) (strin
 	return interpolatedQuery, nil
 }
 
+const reflogRestoreSubcommand = "restore"
+
+func createReflogRestoreArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(reflogRestoreSubcommand, 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"ref", "The reflog entry to restore, given as {{.LessThan}}ref{{.GreaterThan}}@{n}."})
+	ap.SupportsString(cli.BranchParam, "b", "branch", "Create or reset {{.LessThan}}branch{{.GreaterThan}} instead of the ref the entry was recorded for.")
+	ap.SupportsFlag(cli.ForceFlag, "f", "Reset the branch or tag if it already exists.")
+	return ap
+}
+
+// restoreReflogEntry executes the restore subcommand, which recreates the branch or tag recorded by a reflog entry.
+func restoreReflogEntry(ctx context.Context, commandStr string, args []string, cliCtx cli.CliContext) int {
+	ap := createReflogRestoreArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, reflogDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 1 {
+		usage()
+		return 1
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	spec := apr.Arg(0)
+	refName, n, err := parseReflogSpec(spec)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	rows, err := InterpolateAndRunQuery(queryist, sqlCtx, "SELECT ref, commit_hash FROM DOLT_REFLOG(?, '--all')", refName)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if len(rows) == 0 {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: no reflog entries found for '%s'", refName).Build(), usage)
+	}
+	if n >= len(rows) {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: '%s' only has %d reflog entries", refName, len(rows)).Build(), usage)
+	}
+	fullRef := rows[n][0].(string)
+	commitHash := rows[n][1].(string)
+
+	if _, err = InterpolateAndRunQuery(queryist, sqlCtx, "SELECT hashof(?)", commitHash); err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: commit %s for %s no longer exists; it may have been removed by garbage collection", commitHash, spec).Build(), usage)
+	}
+
+	force := apr.Contains(cli.ForceFlag)
+	switch {
+	case apr.Contains(cli.BranchParam):
+		err = restoreBranch(queryist, sqlCtx, apr.MustGetValue(cli.BranchParam), commitHash, force)
+	case strings.HasPrefix(fullRef, "refs/heads/"):
+		err = restoreBranch(queryist, sqlCtx, strings.TrimPrefix(fullRef, "refs/heads/"), commitHash, force)
+	case strings.HasPrefix(fullRef, "refs/tags/"):
+		err = restoreTag(queryist, sqlCtx, strings.TrimPrefix(fullRef, "refs/tags/"), commitHash, force)
+	default:
+		err = fmt.Errorf("error: %s is not a branch or tag; use --%s to restore it as a branch", fullRef, cli.BranchParam)
+	}
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	return 0
+}
+
+// parseReflogSpec parses a reflog entry of the form <ref>@{<n>}, where <n> counts back from the most recent entry
+// for <ref>. A ref without a suffix refers to its most recent entry.
+func parseReflogSpec(spec string) (string, int, error) {
+	i := strings.LastIndex(spec, "@{")
+	if i < 0 {
+		return spec, 0, nil
+	}
+
+	refName, suffix := spec[:i], spec[i+2:]
+	if refName == "" || !strings.HasSuffix(suffix, "}") {
+		return "", 0, fmt.Errorf("error: invalid reflog entry '%s'", spec)
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(suffix, "}"))
+	if err != nil || n < 0 {
+		return "", 0, fmt.Errorf("error: invalid reflog entry '%s'", spec)
+	}
+	return refName, n, nil
+}
+
+func restoreBranch(queryist cli.Queryist, sqlCtx *sql.Context, name, commitHash string, force bool) error {
+	existing, err := InterpolateAndRunQuery(queryist, sqlCtx, "SELECT name FROM dolt_branches WHERE name = ?", name)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		if !force {
+			return fmt.Errorf("error: a branch named '%s' already exists; use --%s to reset it", name, cli.ForceFlag)
+		}
+		_, err = InterpolateAndRunQuery(queryist, sqlCtx, "CALL DOLT_BRANCH('-f', ?, ?)", name, commitHash)
+	} else {
+		_, err = InterpolateAndRunQuery(queryist, sqlCtx, "CALL DOLT_BRANCH(?, ?)", name, commitHash)
+	}
+	if err != nil {
+		return err
+	}
+
+	cli.Printf("Restored branch '%s' to %s\n", name, commitHash)
+	return nil
+}
+
+func restoreTag(queryist cli.Queryist, sqlCtx *sql.Context, name, commitHash string, force bool) error {
+	existing, err := InterpolateAndRunQuery(queryist, sqlCtx, "SELECT tag_name FROM dolt_tags WHERE tag_name = ?", name)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		if !force {
+			return fmt.Errorf("error: a tag named '%s' already exists; use --%s to reset it", name, cli.ForceFlag)
+		}
+		if _, err = InterpolateAndRunQuery(queryist, sqlCtx, "CALL DOLT_TAG('-d', ?)", name); err != nil {
+			return err
+		}
+	}
+	if _, err = InterpolateAndRunQuery(queryist, sqlCtx, "CALL DOLT_TAG(?, ?)", name, commitHash); err != nil {
+		return err
+	}
+
+	cli.Printf("Restored tag '%s' to %s\n", name, commitHash)
+	return nil
+}
+
 type ReflogInfo struct {
 	ref           string
 	commitHash    string
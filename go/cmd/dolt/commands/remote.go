@@ -16,6 +16,7 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -47,11 +48,12 @@ The {{.LessThan}}url{{.GreaterThan}} parameter supports url schemes of http, htt
 
 AWS cloud remote urls should be of the form {{.EmphasisLeft}}aws://[dynamo-table:s3-bucket]/database{{.EmphasisRight}}.  You may configure your aws cloud remote using the optional parameters {{.EmphasisLeft}}aws-region{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-type{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-file{{.EmphasisRight}}.
 
-aws-creds-type specifies the means by which credentials should be retrieved in order to access the specified cloud resources (specifically the dynamo table, and the s3 bucket). Valid values are 'role', 'env', or 'file'.
+aws-creds-type specifies the means by which credentials should be retrieved in order to access the specified cloud resources (specifically the dynamo table, and the s3 bucket). Valid values are 'role', 'env', 'file', or 'helper'.
 
 	role: Use the credentials installed for the current user
 	env: Looks for environment variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
 	file: Uses the credentials file specified by the parameter aws-creds-file
+	helper: Uses the AWS access key returned by the program set in the credential.helper config
 	
 GCP remote urls should be of the form gs://gcs-bucket/database and will use the credentials setup using the gcloud command line available from Google.
 
@@ -95,12 +97,13 @@ func (cmd RemoteCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(cli.VerboseFlag, "v", "When printing the list of remotes adds additional details.")
 
 	ap.SupportsString(dbfactory.AWSRegionParam, "", "region", "Cloud provider region associated with this remote.")
-	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "Credential type. Valid options are role, env, and file. See the help section for additional details.", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
+	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "Credential type. Valid options are role, env, file, and helper. See the help section for additional details.", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
 	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file")
 	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use")
 
 	ap.SupportsString(dbfactory.OSSCredsFileParam, "", "file", "OSS credentials file")
 	ap.SupportsString(dbfactory.OSSCredsProfile, "", "profile", "OSS profile to use")
+	addResultFormatFlag(ap, "r")
 	return ap
 }
 
@@ -123,6 +126,14 @@ func (cmd RemoteCmd) Exec(ctx context.Context, commandStr string, args []string,
 		defer closeFunc()
 	}
 
+	if _, err := getResultFormat(apr); err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if isJsonResultFormat(apr) && apr.NArg() != 0 {
+		err = fmt.Errorf("error: --%s %s is only supported when listing remotes", FormatFlag, jsonResultFormat)
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
 	var verr errhand.VerboseError
 	switch {
 	case apr.NArg() == 0:
@@ -314,6 +325,10 @@ func printRemotes(sqlCtx *sql.Context, queryist cli.Queryist, apr *argparser.Arg
 		return errhand.BuildDError("Unable to get remotes from the local directory").AddCause(err).Build()
 	}
 
+	if isJsonResultFormat(apr) {
+		return printRemotesJson(remotes)
+	}
+
 	for _, r := range remotes {
 		if apr.Contains(cli.VerboseFlag) {
 			cli.Printf("%s %s %s\n", r.Name, r.Url, r.Params)
@@ -324,3 +339,26 @@ func printRemotes(sqlCtx *sql.Context, queryist cli.Queryist, apr *argparser.Arg
 
 	return nil
 }
+
+// remoteJson is the machine-readable representation of a remote in dolt remote output.
+type remoteJson struct {
+	Name   string          `json:"name"`
+	Url    string          `json:"url"`
+	Params json.RawMessage `json:"params"`
+}
+
+func printRemotesJson(remotes []remote) errhand.VerboseError {
+	res := make([]remoteJson, len(remotes))
+	for i, r := range remotes {
+		params := json.RawMessage("{}")
+		if r.Params != "" {
+			params = json.RawMessage(r.Params)
+		}
+		res[i] = remoteJson{Name: r.Name, Url: r.Url, Params: params}
+	}
+
+	err := printJsonResult(struct {
+		Remotes []remoteJson `json:"remotes"`
+	}{res})
+	return errhand.VerboseErrorFromError(err)
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+// Output formats supported by porcelain commands which can emit machine-readable results. Commands that print
+// query results, such as sql and diff, support additional formats and do their own validation.
+const (
+	defaultResultFormat = "default"
+	jsonResultFormat    = "json"
+)
+
+// addResultFormatFlag adds the --result-format flag shared by porcelain commands to |ap|. |abbrev| is the short
+// name of the flag, which may be empty for commands where -r already means something else.
+func addResultFormatFlag(ap *argparser.ArgParser, abbrev string) {
+	ap.SupportsString(FormatFlag, abbrev, "result output format", "How to format command output. Valid values are default and json. Defaults to default.")
+}
+
+// getResultFormat returns the validated --result-format argument, or defaultResultFormat if none was given.
+func getResultFormat(apr *argparser.ArgParseResults) (string, error) {
+	f := apr.GetValueOrDefault(FormatFlag, defaultResultFormat)
+	switch f {
+	case defaultResultFormat, jsonResultFormat:
+		return f, nil
+	default:
+		return "", fmt.Errorf("error: invalid --%s: %s. Valid values are %s and %s", FormatFlag, f, defaultResultFormat, jsonResultFormat)
+	}
+}
+
+// isJsonResultFormat returns whether json output was requested. The argument must already have been validated with
+// getResultFormat.
+func isJsonResultFormat(apr *argparser.ArgParseResults) bool {
+	return apr.GetValueOrDefault(FormatFlag, defaultResultFormat) == jsonResultFormat
+}
+
+// validateJsonResultFormatFlags returns an error if json output was requested along with any of the given flags,
+// which only apply to human-readable output.
+func validateJsonResultFormatFlags(apr *argparser.ArgParseResults, flags ...string) error {
+	if !isJsonResultFormat(apr) {
+		return nil
+	}
+	for _, flag := range flags {
+		if apr.Contains(flag) {
+			return fmt.Errorf("error: --%s is not supported with --%s %s", flag, FormatFlag, jsonResultFormat)
+		}
+	}
+	return nil
+}
+
+// printJsonResult writes |v| to stdout as an indented json document.
+func printJsonResult(v interface{}) error {
+	return writeJsonResult(cli.CliOut, v)
+}
+
+// writeJsonResult writes |v| to |wr| as an indented json document.
+func writeJsonResult(wr io.Writer, v interface{}) error {
+	enc := json.NewEncoder(wr)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
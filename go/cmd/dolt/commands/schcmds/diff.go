@@ -0,0 +1,190 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schcmds
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	textdiff "github.com/andreyvit/diff"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const migrationFlag = "migration"
+
+var schDiffDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Shows the changes to table schemas between two revisions.",
+	LongDesc: `{{.EmphasisLeft}}dolt schema diff{{.EmphasisRight}} shows how the schemas of tables differ between two revisions. With no revisions, the working set is compared to HEAD. With one, the working set is compared to {{.LessThan}}from{{.GreaterThan}}. A revision can be a commit, a branch or tag, or one of {{.EmphasisLeft}}WORKING{{.EmphasisRight}} or {{.EmphasisLeft}}STAGED{{.EmphasisRight}}.
+
+If {{.EmphasisLeft}}--migration{{.EmphasisRight}} is given, an executable script of DDL statements which transforms the schemas at {{.LessThan}}from{{.GreaterThan}} into those at {{.LessThan}}to{{.GreaterThan}} is printed instead, to apply the same changes to another MySQL database. The statements are ordered so that foreign keys never reference missing tables or columns: changed foreign keys are dropped first, then dropped tables are dropped, existing tables are altered, new tables are created, and finally the new foreign keys are added. Data, views, triggers and stored procedures are not included.`,
+	Synopsis: []string{
+		"[--migration] [{{.LessThan}}from{{.GreaterThan}} [{{.LessThan}}to{{.GreaterThan}}]]",
+	},
+}
+
+type DiffCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd DiffCmd) Name() string {
+	return "diff"
+}
+
+// Description returns a description of the command
+func (cmd DiffCmd) Description() string {
+	return "Shows the changes to table schemas between two revisions."
+}
+
+func (cmd DiffCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(schDiffDocs, ap)
+}
+
+func (cmd DiffCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 2)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"from", "the revision to compare from. Defaults to HEAD."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"to", "the revision to compare to. Defaults to the working set."})
+	ap.SupportsFlag(migrationFlag, "", "Print an ordered DDL script which migrates the schemas at {{.LessThan}}from{{.GreaterThan}} to those at {{.LessThan}}to{{.GreaterThan}}.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd DiffCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_SCHEMA
+}
+
+// Exec executes the command
+func (cmd DiffCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, schDiffDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	fromSpec, toSpec := "HEAD", "WORKING"
+	if apr.NArg() > 0 {
+		fromSpec = apr.Arg(0)
+	}
+	if apr.NArg() > 1 {
+		toSpec = apr.Arg(1)
+	}
+
+	fromRoot, verr := resolveRoot(ctx, dEnv, fromSpec)
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+	toRoot, verr := resolveRoot(ctx, dEnv, toSpec)
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	if apr.Contains(migrationFlag) {
+		verr = printMigration(ctx, fromRoot, toRoot)
+	} else {
+		verr = printSchemaDiffs(ctx, fromRoot, toRoot)
+	}
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+// resolveRoot returns the root value of the revision |spec|, which is either WORKING, STAGED or a commit.
+func resolveRoot(ctx context.Context, dEnv *env.DoltEnv, spec string) (doltdb.RootValue, errhand.VerboseError) {
+	switch strings.ToUpper(spec) {
+	case "WORKING":
+		return commands.GetWorkingWithVErr(dEnv)
+	case "STAGED":
+		return commands.GetStagedWithVErr(dEnv)
+	}
+
+	cm, verr := commands.MaybeGetCommitWithVErr(dEnv, spec)
+	if verr != nil {
+		return nil, verr
+	}
+	if cm == nil {
+		return nil, errhand.BuildDError("error: '%s' is not a valid revision", spec).Build()
+	}
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, errhand.BuildDError("unable to get root value").AddCause(err).Build()
+	}
+	return root, nil
+}
+
+func printMigration(ctx context.Context, fromRoot, toRoot doltdb.RootValue) errhand.VerboseError {
+	stmts, err := sqlfmt.GenerateSchemaMigration(ctx, fromRoot, toRoot)
+	if err != nil {
+		return errhand.BuildDError("error: unable to generate the migration").AddCause(err).Build()
+	}
+	for _, stmt := range stmts {
+		cli.Println(stmt)
+	}
+	return nil
+}
+
+func printSchemaDiffs(ctx context.Context, fromRoot, toRoot doltdb.RootValue) errhand.VerboseError {
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return errhand.BuildDError("error: unable to diff tables").AddCause(err).Build()
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].CurName() < deltas[j].CurName()
+	})
+
+	fromSchemas, err := doltdb.GetAllSchemas(ctx, fromRoot)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	toSchemas, err := doltdb.GetAllSchemas(ctx, toRoot)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	for _, td := range deltas {
+		if doltdb.HasDoltPrefix(td.CurName()) {
+			continue
+		}
+		fromStmt, err := createTableStmt(td.FromName.Name, td.FromSch, td.FromFks, fromSchemas)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		toStmt, err := createTableStmt(td.ToName.Name, td.ToSch, td.ToFks, toSchemas)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		if fromStmt == toStmt {
+			continue
+		}
+
+		cli.Println(bold.Sprint(td.CurName()))
+		cli.Println(textdiff.LineDiff(fromStmt, toStmt))
+		cli.Println()
+	}
+	return nil
+}
+
+// createTableStmt returns the CREATE TABLE statement of a table, or the empty string if it doesn't exist
+func createTableStmt(name string, sch schema.Schema, fks []doltdb.ForeignKey, parentSchemas map[string]schema.Schema) (string, error) {
+	if sch == nil {
+		return "", nil
+	}
+	return sqlfmt.GenerateCreateTableStatement(name, sch, fks, parentSchemas)
+}
@@ -21,6 +21,7 @@ import (
 )
 
 var Commands = cli.NewSubCommandHandler("schema", "Commands for showing and importing table schemas.", []cli.Command{
+	DiffCmd{},
 	ExportCmd{},
 	ImportCmd{},
 	ShowCmd{},
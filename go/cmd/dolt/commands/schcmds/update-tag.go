@@ -132,10 +132,10 @@ func (cmd UpdateTagCmd) Exec(ctx context.Context, commandStr string, args []stri
 
 func updateColumnTag(sch schema.Schema, name string, tag uint64) (schema.Schema, error) {
 	var found bool
-	columns := sch.GetAllCols().GetColumns()
+	columns := sch.GetAllCols().GetColumnsWithDropped()
 	// Find column and update its tag
 	for i, col := range columns {
-		if col.Name == name {
+		if !col.Dropped && col.Name == name {
 			col.Tag = tag
 			columns[i] = col
 			found = true
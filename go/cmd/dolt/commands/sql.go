@@ -40,6 +40,7 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
@@ -55,13 +56,18 @@ var sqlDocs = cli.CommandDocumentationContent{
 
 Multiple SQL statements must be separated by semicolons. Use {{.EmphasisLeft}}-b{{.EmphasisRight}} to enable batch mode to speed up large batches of INSERT / UPDATE statements. Pipe SQL files to dolt sql (no {{.EmphasisLeft}}-q{{.EmphasisRight}}) to execute a SQL import or update script. 
 
+When running a script, such as the output of mysqldump, each statement is committed as it's run, and the first statement to fail stops the script. Use {{.EmphasisLeft}}--transaction-size{{.EmphasisRight}} to commit every N statements instead, and {{.EmphasisLeft}}--on-error{{.EmphasisRight}} to continue past failed statements, or to roll back the current transaction when one fails. With {{.EmphasisLeft}}--file{{.EmphasisRight}}, the progress through the file and the number of statements run are reported as it runs.
+
 By default this command uses the dolt database in the current working directory. If you would prefer to use a different directory, user the {{.EmphasisLeft}}--data-dir <directory>{{.EmphasisRight}} argument before the sql subcommand.
 
+In the interactive shell, commands starting with a backslash, like {{.EmphasisLeft}}\d{{.EmphasisRight}} to describe a table or {{.EmphasisLeft}}\timing{{.EmphasisRight}} to time statements, are run by the shell rather than as SQL. Run {{.EmphasisLeft}}\help{{.EmphasisRight}} to list them. Results too large to fit on the screen are shown in a pager, and the shell's history is saved in the .dolt directory of your home directory, where ctrl-r searches it.
+
 If a server is running for the database in question, then the query will go through the server automatically. If connecting to a remote server is preferred, used the {{.EmphasisLeft}}--host <host>{{.EmphasisRight}} and {{.EmphasisLeft}}--port <port>{{.EmphasisRight}} global arguments. See 'dolt --help' for more information about global arguments.`,
 
 	Synopsis: []string{
 		"",
 		"< script.sql",
+		"-f {{.LessThan}}script.sql{{.GreaterThan}} [--transaction-size {{.LessThan}}n{{.GreaterThan}}] [--on-error abort|continue|rollback]",
 		"-q {{.LessThan}}query{{.GreaterThan}} [-r {{.LessThan}}result format{{.GreaterThan}}] [-s {{.LessThan}}name{{.GreaterThan}} -m {{.LessThan}}message{{.GreaterThan}}] [-b]",
 		"-x {{.LessThan}}name{{.GreaterThan}}",
 		"--list-saved",
@@ -87,6 +93,8 @@ const (
 	DefaultPrivsName      = "privileges.db"
 	DefaultBranchCtrlName = "branch_control.db"
 	continueFlag          = "continue"
+	onErrorFlag           = "on-error"
+	transactionSizeFlag   = "transaction-size"
 	fileInputFlag         = "file"
 	UserFlag              = "user"
 	DefaultUser           = "root"
@@ -140,7 +148,9 @@ func (cmd SqlCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(listSavedFlag, "l", "List all saved queries.")
 	ap.SupportsString(messageFlag, "m", "saved query description", "Used with --query and --save, saves the query with the descriptive message given. See also `--name`.")
 	ap.SupportsFlag(BatchFlag, "b", "Use to enable more efficient batch processing for large SQL import scripts. This mode is no longer supported and this flag is a no-op. To speed up your SQL imports, use either LOAD DATA, or structure your SQL import script to insert many rows per statement.")
-	ap.SupportsFlag(continueFlag, "c", "Continue running queries on an error. Used for batch mode only.")
+	ap.SupportsFlag(continueFlag, "c", "Continue running queries on an error. Used for batch mode only. Same as --on-error continue.")
+	ap.SupportsString(onErrorFlag, "", "policy", "What to do when a statement fails while running a script. abort stops running it, committing the statements before the error. continue prints the error and runs the rest of the script. rollback rolls back the current transaction and stops running it. Defaults to abort.")
+	ap.SupportsInt(transactionSizeFlag, "", "statements", "Run the statements of a script in transactions of this many statements each, rather than committing each statement separately. With --on-error rollback and no --transaction-size, the whole script is run in one transaction.")
 	ap.SupportsString(fileInputFlag, "f", "input file", "Execute statements from the file given.")
 	return ap
 }
@@ -238,7 +248,10 @@ func (cmd SqlCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 			isTty = fi.Mode()&os.ModeCharDevice != 0
 		}
 
-		_, continueOnError := apr.GetValue(continueFlag)
+		batchOpts, err := getBatchOptions(apr)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
 
 		var input io.Reader = os.Stdin
 		if fileInput, ok := apr.GetValue(fileInputFlag); ok {
@@ -266,7 +279,7 @@ func (cmd SqlCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 			}
 		} else {
 			input = transform.NewReader(input, textunicode.BOMOverride(transform.Nop))
-			err := execBatchMode(sqlCtx, queryist, input, continueOnError, format)
+			err := execBatchMode(sqlCtx, queryist, input, batchOpts, format)
 			if err != nil {
 				return sqlHandleVErrAndExitCode(queryist, errhand.VerboseErrorFromError(err), usage)
 			}
@@ -388,10 +401,13 @@ func queryMode(
 	usage cli.UsagePrinter,
 ) int {
 
-	_, continueOnError := apr.GetValue(continueFlag)
+	batchOpts, err := getBatchOptions(apr)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
 
 	input := strings.NewReader(query)
-	err := execBatchMode(ctx, qryist, input, continueOnError, format)
+	err = execBatchMode(ctx, qryist, input, batchOpts, format)
 	if err != nil {
 		return sqlHandleVErrAndExitCode(qryist, errhand.VerboseErrorFromError(err), usage)
 	}
@@ -610,8 +626,102 @@ func saveQuery(ctx *sql.Context, root doltdb.RootValue, query string, name strin
 	return newRoot, nil
 }
 
+// sqlParser parses statements before they are sent to the engine, and understands the Dolt specific statements which
+// the engine's parser does.
+var sqlParser = dsqle.NewParser(sql.NewMysqlParser())
+
+const (
+	onErrorAbort    = "abort"
+	onErrorContinue = "continue"
+	onErrorRollback = "rollback"
+)
+
+// batchOptions configure how execBatchMode runs a script.
+type batchOptions struct {
+	// onError is what to do when a statement fails: onErrorAbort, onErrorContinue or onErrorRollback
+	onError string
+	// transactionSize is the number of statements run in each transaction, or 0 if statements are committed as they
+	// are run. Transactions started and committed by the script itself take precedence.
+	transactionSize int
+}
+
+// useTransactions returns whether the script is run in transactions started by execBatchMode.
+func (o batchOptions) useTransactions() bool {
+	return o.transactionSize > 0 || o.onError == onErrorRollback
+}
+
+// getBatchOptions returns the batchOptions given by the --continue, --on-error and --transaction-size arguments.
+func getBatchOptions(apr *argparser.ArgParseResults) (batchOptions, error) {
+	opts := batchOptions{onError: onErrorAbort}
+	if apr.Contains(continueFlag) {
+		opts.onError = onErrorContinue
+	}
+	if onError, ok := apr.GetValue(onErrorFlag); ok {
+		onError = strings.ToLower(onError)
+		switch onError {
+		case onErrorAbort, onErrorContinue, onErrorRollback:
+		default:
+			return batchOptions{}, fmt.Errorf("invalid value for --%s: %s, expected one of %s, %s or %s", onErrorFlag, onError, onErrorAbort, onErrorContinue, onErrorRollback)
+		}
+		if apr.Contains(continueFlag) && onError != onErrorContinue {
+			return batchOptions{}, fmt.Errorf("--%s is not compatible with --%s %s", continueFlag, onErrorFlag, onError)
+		}
+		opts.onError = onError
+	}
+	if size, ok := apr.GetInt(transactionSizeFlag); ok {
+		if size < 1 {
+			return batchOptions{}, fmt.Errorf("--%s must be at least 1", transactionSizeFlag)
+		}
+		opts.transactionSize = size
+	}
+	return opts, nil
+}
+
 // execBatchMode runs all the queries in the input reader
-func execBatchMode(ctx *sql.Context, qryist cli.Queryist, input io.Reader, continueOnErr bool, format engine.PrintResultFormat) error {
+func execBatchMode(ctx *sql.Context, qryist cli.Queryist, input io.Reader, opts batchOptions, format engine.PrintResultFormat) error {
+	inTx := false
+	txStatements := 0
+	beginTx := func() error {
+		if _, err := GetRowsForSql(qryist, ctx, "START TRANSACTION"); err != nil {
+			return err
+		}
+		inTx, txStatements = true, 0
+		return nil
+	}
+	// endTx ends the transaction begun by beginTx, if there is one, with |query|: COMMIT or ROLLBACK
+	endTx := func(query string) error {
+		if !inTx {
+			return nil
+		}
+		inTx = false
+		_, err := GetRowsForSql(qryist, ctx, query)
+		return err
+	}
+
+	// handleErr applies the error policy to the failure of a statement. Returns the error to stop running the script
+	// with, or nil to run the rest of it.
+	handleErr := func(err error) error {
+		switch opts.onError {
+		case onErrorContinue:
+			if fileReadProg != nil {
+				fileReadProg.errors++
+				fileReadProg.printNewLineIfNeeded()
+			}
+			cli.PrintErrln(err.Error())
+			return nil
+		case onErrorRollback:
+			if txErr := endTx("ROLLBACK"); txErr != nil {
+				return fmt.Errorf("%w; rolling back also failed: %s", err, txErr.Error())
+			}
+			return err
+		default:
+			if txErr := endTx("COMMIT"); txErr != nil {
+				return fmt.Errorf("%w; committing the statements before it also failed: %s", err, txErr.Error())
+			}
+			return err
+		}
+	}
+
 	scanner := NewSqlStatementScanner(input)
 	var query string
 	for scanner.Scan() {
@@ -626,31 +736,27 @@ func execBatchMode(ctx *sql.Context, qryist cli.Queryist, input io.Reader, conti
 
 		sqlMode := sql.LoadSqlMode(ctx)
 
-		sqlStatement, err := sqlparser.ParseWithOptions(ctx, query, sqlMode.ParserOptions())
+		sqlStatement, _, _, err := sqlParser.ParseWithOptions(ctx, query, ';', false, sqlMode.ParserOptions())
 		if err == sqlparser.ErrEmpty {
 			continue
 		} else if err != nil {
-			err = buildBatchSqlErr(scanner.statementStartLine, query, err)
-			if !continueOnErr {
+			if err = handleErr(buildBatchSqlErr(scanner.statementStartLine, query, err)); err != nil {
 				return err
-			} else {
-				cli.PrintErrln(err.Error())
 			}
+			query = ""
+			continue
 		}
 
-		// store start time for query
-		ctx.SetQueryTime(time.Now())
-		sqlSch, rowIter, _, err := processParsedQuery(ctx, query, qryist, sqlStatement)
-		if err != nil {
-			err = buildBatchSqlErr(scanner.statementStartLine, query, err)
-			if !continueOnErr {
-				return err
-			} else {
-				cli.PrintErrln(err.Error())
+		if opts.useTransactions() && !inTx {
+			if err = beginTx(); err != nil {
+				return buildBatchSqlErr(scanner.statementStartLine, "START TRANSACTION", err)
 			}
 		}
 
-		if rowIter != nil {
+		// store start time for query
+		ctx.SetQueryTime(time.Now())
+		sqlSch, rowIter, _, err := processParsedQuery(ctx, query, qryist, sqlStatement)
+		if err == nil && rowIter != nil {
 			switch sqlStatement.(type) {
 			case *sqlparser.Select, *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete,
 				*sqlparser.OtherRead, *sqlparser.Show, *sqlparser.Explain, *sqlparser.SetOp:
@@ -660,22 +766,35 @@ func execBatchMode(ctx *sql.Context, qryist cli.Queryist, input io.Reader, conti
 				}
 			}
 			err = engine.PrettyPrintResults(ctx, format, sqlSch, rowIter)
-			if err != nil {
-				err = buildBatchSqlErr(scanner.statementStartLine, query, err)
-				if !continueOnErr {
-					return err
-				} else {
-					cli.PrintErrln(err.Error())
-				}
+		}
+		if fileReadProg != nil {
+			fileReadProg.statements++
+		}
+		if err != nil {
+			if err = handleErr(buildBatchSqlErr(scanner.statementStartLine, query, err)); err != nil {
+				return err
 			}
 		}
 		query = ""
+
+		if inTx && opts.transactionSize > 0 {
+			txStatements++
+			if txStatements >= opts.transactionSize {
+				if err = endTx("COMMIT"); err != nil {
+					return buildBatchSqlErr(scanner.statementStartLine, "COMMIT", err)
+				}
+			}
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
+		_ = endTx("ROLLBACK")
 		return buildBatchSqlErr(scanner.statementStartLine, query, err)
 	}
 
+	if err := endTx("COMMIT"); err != nil {
+		return buildBatchSqlErr(scanner.statementStartLine, "COMMIT", err)
+	}
 	return nil
 }
 
@@ -687,7 +806,8 @@ func buildBatchSqlErr(stmtStartLine int, query string, err error) error {
 // be updated by any queries which were processed.
 func execShell(sqlCtx *sql.Context, qryist cli.Queryist, format engine.PrintResultFormat, cliCtx cli.CliContext) error {
 	_ = iohelp.WriteLine(cli.CliOut, welcomeMsg)
-	historyFile := filepath.Join(".sqlhistory") // history file written to working dir
+	historyFile := sqlHistoryFile()
+	initShellSettings()
 
 	db, branch, _ := getDBBranchFromSession(sqlCtx, qryist)
 	dirty := false
@@ -702,7 +822,7 @@ func execShell(sqlCtx *sql.Context, qryist cli.Queryist, format engine.PrintResu
 		Stdout:                 cli.CliOut,
 		Stderr:                 cli.CliOut,
 		HistoryFile:            historyFile,
-		HistoryLimit:           500,
+		HistoryLimit:           sqlHistoryLimit,
 		HistorySearchFold:      true,
 		DisableAutoSaveHistory: true,
 	}
@@ -786,6 +906,7 @@ func execShell(sqlCtx *sql.Context, qryist cli.Queryist, format engine.PrintResu
 
 			sqlCtx := sql.NewContext(subCtx, sql.WithSession(sqlCtx.Session))
 
+			start := time.Now()
 			subCmd, foundCmd := isSlashQuery(query)
 			if foundCmd {
 				err := handleSlashCommand(sqlCtx, subCmd, query, cliCtx)
@@ -799,18 +920,15 @@ func execShell(sqlCtx *sql.Context, qryist cli.Queryist, format engine.PrintResu
 					verr := formatQueryError("", err)
 					shell.Println(verr.Verbose())
 				} else if rowIter != nil {
-					switch closureFormat {
-					case engine.FormatTabular, engine.FormatVertical:
-						err = engine.PrettyPrintResultsExtended(sqlCtx, closureFormat, sqlSch, rowIter)
-					default:
-						err = engine.PrettyPrintResults(sqlCtx, closureFormat, sqlSch, rowIter)
-					}
-
+					err = printShellResults(sqlCtx, closureFormat, sqlSch, rowIter)
 					if err != nil {
 						shell.Println(color.RedString(err.Error()))
 					}
 				}
 			}
+			if _, isTiming := subCmd.(SlashTiming); shellSettings.timing && !isTiming {
+				shell.Println(fmt.Sprintf("Time: %.3f ms", float64(time.Since(start).Microseconds())/1000))
+			}
 
 			nextPrompt, multiPrompt = postCommandUpdate(sqlCtx, qryist)
 
@@ -831,6 +949,23 @@ func execShell(sqlCtx *sql.Context, qryist cli.Queryist, format engine.PrintResu
 	return nil
 }
 
+// sqlHistoryLimit is the number of statements kept in the shell's history.
+const sqlHistoryLimit = 10_000
+
+// sqlHistoryFile returns the file the shell's history is saved to, .sqlhistory in the user's global dolt directory, so
+// it's kept across shells started in different directories. Falls back to the working directory if there's no home
+// directory.
+func sqlHistoryFile() string {
+	homeDir, err := env.GetCurrentUserHomeDir()
+	if err == nil {
+		dir := filepath.Join(homeDir, dbfactory.DoltDir)
+		if err = os.MkdirAll(dir, os.ModePerm); err == nil {
+			return filepath.Join(dir, ".sqlhistory")
+		}
+	}
+	return ".sqlhistory"
+}
+
 func isSlashQuery(query string) (cli.Command, bool) {
 	// strip leading whitespace
 	query = strings.TrimLeft(query, " \t\n\r\v\f")
@@ -1070,7 +1205,7 @@ func prepend(s string, ss []string) []string {
 // processQuery processes a single query. The Root of the sqlEngine will be updated if necessary.
 // Returns the schema and the row iterator for the results, which may be nil, and an error if one occurs.
 func processQuery(ctx *sql.Context, query string, qryist cli.Queryist) (sql.Schema, sql.RowIter, *sql.QueryFlags, error) {
-	sqlStatement, err := sqlparser.Parse(query)
+	sqlStatement, err := sqlParser.ParseSimple(query)
 	if err == sqlparser.ErrEmpty {
 		// silently skip empty statements
 		return nil, nil, nil, nil
@@ -1140,6 +1275,9 @@ type fileReadProgress struct {
 	totalBytes    int64
 	printed       int64
 	displayStrLen int
+	// statements and errors count the statements run, and the ones which failed and were skipped
+	statements int
+	errors     int
 }
 
 var batchEditStats = &stats{}
@@ -1200,6 +1338,10 @@ func updateFileReadProgressOutput() {
 	batchEditStats.printNewLineIfNeeded()
 	percent := float64(fileReadProg.bytesRead) / float64(fileReadProg.totalBytes) * 100
 	fileReadProg.printed = fileReadProg.bytesRead
-	displayStr := fmt.Sprintf("Processed %.1f%% of the file", percent)
+	displayStr := fmt.Sprintf("Processed %.1f%% of the file (%d statements", percent, fileReadProg.statements)
+	if fileReadProg.errors > 0 {
+		displayStr += fmt.Sprintf(", %d errors", fileReadProg.errors)
+	}
+	displayStr += ")"
 	fileReadProg.displayStrLen = cli.DeleteAndPrint(fileReadProg.displayStrLen, displayStr)
 }
@@ -0,0 +1,112 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/store/util/outputpager"
+)
+
+// defaultShellPager is the pager used when neither DOLT_SQL_PAGER nor PAGER is set. With -F, less exits immediately
+// when the results fit on one screen, so only large result sets are paged.
+const defaultShellPager = "less -FSRX"
+
+// shellSettings are the settings of the SQL shell changed by its \timing and \pager commands.
+var shellSettings = struct {
+	// timing is whether the time each statement takes is printed after its results
+	timing bool
+	// pager is whether query results are printed through a pager
+	pager bool
+}{}
+
+// initShellSettings sets the shell's settings to their defaults: results are paged when the shell is run in a terminal.
+func initShellSettings() {
+	shellSettings.timing = false
+	shellSettings.pager = false
+	if cli.ExecuteWithStdioRestored != nil {
+		cli.ExecuteWithStdioRestored(func() {
+			shellSettings.pager = outputpager.IsStdoutTty()
+		})
+	}
+}
+
+// shellPagerCommand returns the command line of the pager: DOLT_SQL_PAGER if it's set, then PAGER, then less.
+func shellPagerCommand() []string {
+	for _, name := range []string{dconfig.EnvSqlPager, "PAGER"} {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return strings.Fields(v)
+		}
+	}
+	return strings.Fields(defaultShellPager)
+}
+
+// printShellResults prints the results of a query run in the shell, through the pager if paging is enabled and
+// |format| is one meant to be read in a terminal.
+func printShellResults(sqlCtx *sql.Context, format engine.PrintResultFormat, sqlSch sql.Schema, rowIter sql.RowIter) error {
+	printResults := func() error {
+		switch format {
+		case engine.FormatTabular, engine.FormatVertical:
+			return engine.PrettyPrintResultsExtended(sqlCtx, format, sqlSch, rowIter)
+		default:
+			return engine.PrettyPrintResults(sqlCtx, format, sqlSch, rowIter)
+		}
+	}
+
+	pageable := format == engine.FormatTabular || format == engine.FormatVertical
+	if !shellSettings.pager || !pageable || cli.ExecuteWithStdioRestored == nil {
+		return printResults()
+	}
+
+	var err error
+	cli.ExecuteWithStdioRestored(func() {
+		args := shellPagerCommand()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		var in io.WriteCloser
+		if in, err = cmd.StdinPipe(); err == nil {
+			err = cmd.Start()
+		}
+		if err != nil {
+			// the pager isn't installed, print directly instead
+			err = printResults()
+			return
+		}
+
+		out := cli.CliOut
+		cli.CliOut = in
+		err = printResults()
+		cli.CliOut = out
+
+		_ = in.Close()
+		_ = cmd.Wait()
+		if errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed) {
+			// the pager was quit before all the results were written to it
+			err = nil
+		}
+	})
+	return err
+}
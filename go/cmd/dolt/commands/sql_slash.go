@@ -21,8 +21,10 @@ import (
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/fatih/color"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 )
@@ -38,6 +40,10 @@ var slashCmds = []cli.Command{
 	ResetCmd{},
 	BranchCmd{},
 	MergeCmd{},
+	SlashDescribe{},
+	SlashListDatabases{},
+	SlashTiming{},
+	SlashPager{},
 	SlashHelp{},
 }
 
@@ -134,6 +140,7 @@ func (s SlashHelp) Exec(ctx context.Context, _ string, args []string, _ *env.Dol
 	for _, cmdInst := range slashCmds {
 		cli.Println(fmt.Sprintf("  %10s - %s", cmdInst.Name(), cmdInst.Description()))
 	}
+	cli.Println("\nSearch the history of statements with ctrl-r.")
 	cli.Printf("\nFor more information on a specific command, type '\\help <command>' (e.g. '%s\\help status')\n", prompt)
 
 	moreWords := `
@@ -161,6 +168,196 @@ func (s SlashHelp) ArgParser() *argparser.ArgParser {
 	return &argparser.ArgParser{}
 }
 
+// slashQuery runs |query| in the shell's session, printing its results like a query entered in the shell.
+func slashQuery(ctx context.Context, cliCtx cli.CliContext, query string) int {
+	qryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+	if err != nil {
+		cli.Println(fmt.Sprintf("error getting query engine: %s", err))
+		return 1
+	}
+
+	sqlSch, rowIter, _, err := qryist.Query(sqlCtx, query)
+	if err == nil {
+		err = printShellResults(sqlCtx, engine.FormatTabular, sqlSch, rowIter)
+	}
+	if err != nil {
+		cli.PrintErrln(color.RedString(err.Error()))
+		return 1
+	}
+	return 0
+}
+
+// quoteQualifiedIdentifier quotes each part of a possibly database-qualified name, eg. db.table.
+func quoteQualifiedIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = "`" + strings.ReplaceAll(strings.Trim(part, "`"), "`", "``") + "`"
+	}
+	return strings.Join(parts, ".")
+}
+
+type SlashDescribe struct{}
+
+func (s SlashDescribe) Name() string {
+	return "d"
+}
+
+func (s SlashDescribe) Description() string {
+	return "List tables, or describe the columns of a table."
+}
+
+func (s SlashDescribe) Docs() *cli.CommandDocumentation {
+	return &cli.CommandDocumentation{
+		CommandStr: "\\d",
+		ShortDesc:  "List tables, or describe the columns of a table.",
+		LongDesc:   "Without arguments, lists the tables of the current database, like SHOW TABLES. Given a table name, optionally qualified with its database, describes its columns, like DESCRIBE.",
+		Synopsis:   []string{"[{{.LessThan}}table{{.GreaterThan}}]"},
+		ArgParser:  s.ArgParser(),
+	}
+}
+
+func (s SlashDescribe) Exec(ctx context.Context, _ string, args []string, _ *env.DoltEnv, cliCtx cli.CliContext) int {
+	switch len(args) {
+	case 0:
+		return slashQuery(ctx, cliCtx, "show tables")
+	case 1:
+		return slashQuery(ctx, cliCtx, "describe "+quoteQualifiedIdentifier(args[0]))
+	default:
+		cli.PrintErrln("\\d takes at most one table name")
+		return 1
+	}
+}
+
+func (s SlashDescribe) ArgParser() *argparser.ArgParser {
+	return &argparser.ArgParser{}
+}
+
+type SlashListDatabases struct{}
+
+func (s SlashListDatabases) Name() string {
+	return "l"
+}
+
+func (s SlashListDatabases) Description() string {
+	return "List databases."
+}
+
+func (s SlashListDatabases) Docs() *cli.CommandDocumentation {
+	return &cli.CommandDocumentation{
+		CommandStr: "\\l",
+		ShortDesc:  "List databases.",
+		LongDesc:   "Lists the databases the shell can use, like SHOW DATABASES.",
+		Synopsis:   []string{},
+		ArgParser:  s.ArgParser(),
+	}
+}
+
+func (s SlashListDatabases) Exec(ctx context.Context, _ string, _ []string, _ *env.DoltEnv, cliCtx cli.CliContext) int {
+	return slashQuery(ctx, cliCtx, "show databases")
+}
+
+func (s SlashListDatabases) ArgParser() *argparser.ArgParser {
+	return &argparser.ArgParser{}
+}
+
+// parseOnOff parses the optional on / off argument of a command which toggles a setting, returning the new value.
+func parseOnOff(cmd string, args []string, current bool) (bool, error) {
+	if len(args) == 0 {
+		return !current, nil
+	}
+	if len(args) == 1 {
+		switch strings.ToLower(args[0]) {
+		case "on":
+			return true, nil
+		case "off":
+			return false, nil
+		}
+	}
+	return current, fmt.Errorf("usage: \\%s [on|off]", cmd)
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+type SlashTiming struct{}
+
+func (s SlashTiming) Name() string {
+	return "timing"
+}
+
+func (s SlashTiming) Description() string {
+	return "Toggle printing how long each statement takes."
+}
+
+func (s SlashTiming) Docs() *cli.CommandDocumentation {
+	return &cli.CommandDocumentation{
+		CommandStr: "\\timing",
+		ShortDesc:  "Toggle printing how long each statement takes.",
+		LongDesc:   "Without arguments, toggles whether the time each statement takes to run and print its results is printed after it. With on or off, sets it.",
+		Synopsis:   []string{"[on|off]"},
+		ArgParser:  s.ArgParser(),
+	}
+}
+
+func (s SlashTiming) Exec(_ context.Context, _ string, args []string, _ *env.DoltEnv, _ cli.CliContext) int {
+	timing, err := parseOnOff(s.Name(), args, shellSettings.timing)
+	if err != nil {
+		cli.PrintErrln(err.Error())
+		return 1
+	}
+	shellSettings.timing = timing
+	cli.Printf("Timing is %s.\n", onOff(timing))
+	return 0
+}
+
+func (s SlashTiming) ArgParser() *argparser.ArgParser {
+	return &argparser.ArgParser{}
+}
+
+type SlashPager struct{}
+
+func (s SlashPager) Name() string {
+	return "pager"
+}
+
+func (s SlashPager) Description() string {
+	return "Toggle printing query results through a pager."
+}
+
+func (s SlashPager) Docs() *cli.CommandDocumentation {
+	return &cli.CommandDocumentation{
+		CommandStr: "\\pager",
+		ShortDesc:  "Toggle printing query results through a pager.",
+		LongDesc: `Without arguments, toggles whether query results are printed through a pager. With on or off, sets it. Paging is on by default when the shell is run in a terminal.
+
+The pager is the command in the DOLT_SQL_PAGER environment variable, or else PAGER, or else {{.EmphasisLeft}}less -FSRX{{.EmphasisRight}}, which only pages results too large to fit on the screen.`,
+		Synopsis:  []string{"[on|off]"},
+		ArgParser: s.ArgParser(),
+	}
+}
+
+func (s SlashPager) Exec(_ context.Context, _ string, args []string, _ *env.DoltEnv, _ cli.CliContext) int {
+	pager, err := parseOnOff(s.Name(), args, shellSettings.pager)
+	if err != nil {
+		cli.PrintErrln(err.Error())
+		return 1
+	}
+	shellSettings.pager = pager
+	cli.Printf("Pager is %s.\n", onOff(pager))
+	return 0
+}
+
+func (s SlashPager) ArgParser() *argparser.ArgParser {
+	return &argparser.ArgParser{}
+}
+
 // findSlashCmd finds a command by name in the list of slash commands. This function is meant to be flexible and can
 // take just command names or a command with arguments and a "\" prefix.
 func findSlashCmd(cmd string) (cli.Command, bool) {
@@ -88,6 +88,96 @@ func TestSqlBatchMode(t *testing.T) {
 	}
 }
 
+func TestSqlBatchErrorPolicy(t *testing.T) {
+	const script = "insert into t values (1);" +
+		"insert into t values (1);" +
+		"insert into t values (2);"
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedRes int
+		expectedIds []int32
+	}{
+		{
+			name:        "abort",
+			expectedRes: 1,
+			expectedIds: []int32{1},
+		},
+		{
+			name:        "continue",
+			args:        []string{"--on-error", "continue"},
+			expectedIds: []int32{1, 2},
+		},
+		{
+			name:        "continue flag",
+			args:        []string{"-c"},
+			expectedIds: []int32{1, 2},
+		},
+		{
+			name:        "rollback script",
+			args:        []string{"--on-error", "rollback"},
+			expectedRes: 1,
+		},
+		{
+			name:        "rollback transaction",
+			args:        []string{"--on-error", "rollback", "--transaction-size", "1"},
+			expectedRes: 1,
+			expectedIds: []int32{1},
+		},
+		{
+			name:        "continue in transactions",
+			args:        []string{"--on-error", "continue", "--transaction-size", "2"},
+			expectedIds: []int32{1, 2},
+		},
+		{
+			name:        "invalid policy",
+			args:        []string{"--on-error", "ignore"},
+			expectedRes: 1,
+		},
+		{
+			name:        "incompatible with continue flag",
+			args:        []string{"-c", "--on-error", "abort"},
+			expectedRes: 1,
+		},
+		{
+			name:        "invalid transaction size",
+			args:        []string{"--transaction-size", "0"},
+			expectedRes: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			dEnv, err := sqle.CreateEnvWithSeedData()
+			require.NoError(t, err)
+			defer dEnv.DoltDB.Close()
+
+			cliCtx, err := NewArgFreeCliContext(ctx, dEnv)
+			require.NoError(t, err)
+			result := SqlCmd{}.Exec(ctx, "dolt sql", []string{"-q", "create table t (a int primary key);"}, dEnv, cliCtx)
+			require.Equal(t, 0, result)
+
+			cliCtx, err = NewArgFreeCliContext(ctx, dEnv)
+			require.NoError(t, err)
+			args := append([]string{"-q", script}, test.args...)
+			result = SqlCmd{}.Exec(ctx, "dolt sql", args, dEnv, cliCtx)
+			assert.Equal(t, test.expectedRes, result)
+
+			root, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+			rows, err := sqle.ExecuteSelect(dEnv, root, "select a from t order by a")
+			require.NoError(t, err)
+			var ids []int32
+			for _, row := range rows {
+				ids = append(ids, row[0].(int32))
+			}
+			assert.Equal(t, test.expectedIds, ids)
+		})
+	}
+}
+
 // Smoke tests, values are printed to console
 func TestSqlSelect(t *testing.T) {
 	tests := []struct {
@@ -54,6 +54,9 @@ type commandLineServerConfig struct {
 	socket                  string
 	remotesapiPort          *int
 	remotesapiReadOnly      *bool
+	xPort                   *int
+	postgresPort            *int
+	flightSQLPort           *int
 	goldenMysqlConn         string
 	eventSchedulerStatus    string
 	valuesSet               map[string]struct{}
@@ -119,6 +122,15 @@ func NewCommandLineConfig(creds *cli.UserPassword, apr *argparser.ArgParseResult
 	if port, ok := apr.GetInt(remotesapiPortFlag); ok {
 		config.WithRemotesapiPort(&port)
 	}
+	if port, ok := apr.GetInt(xPortFlag); ok {
+		config.WithXPort(&port)
+	}
+	if port, ok := apr.GetInt(postgresPortFlag); ok {
+		config.WithPostgresPort(&port)
+	}
+	if port, ok := apr.GetInt(flightSQLPortFlag); ok {
+		config.WithFlightSQLPort(&port)
+	}
 	if apr.Contains(remotesapiReadOnlyFlag) {
 		val := true
 		config.WithRemotesapiReadOnly(&val)
@@ -303,6 +315,16 @@ func (cfg *commandLineServerConfig) RemotesapiReadOnly() *bool {
 	return cfg.remotesapiReadOnly
 }
 
+// RemotesapiPreReceiveHooks returns nil. Receive hooks can only be configured in a config file.
+func (cfg *commandLineServerConfig) RemotesapiPreReceiveHooks() []servercfg.ReceiveHookConfig {
+	return nil
+}
+
+// RemotesapiPostReceiveHooks returns nil. Receive hooks can only be configured in a config file.
+func (cfg *commandLineServerConfig) RemotesapiPostReceiveHooks() []servercfg.ReceiveHookConfig {
+	return nil
+}
+
 func (cfg *commandLineServerConfig) ClusterConfig() servercfg.ClusterConfig {
 	return nil
 }
@@ -331,6 +353,26 @@ func (cfg *commandLineServerConfig) JwksConfig() []servercfg.JwksConfig {
 	return nil
 }
 
+func (cfg *commandLineServerConfig) LDAPConfig() *servercfg.LDAPConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) OIDCConfig() *servercfg.OIDCConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) AuthPlugins() []servercfg.AuthPluginConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) HTTPAPIConfig() *servercfg.HTTPAPIConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) ChangeDataCaptureConfig() *servercfg.ChangeDataCaptureConfig {
+	return nil
+}
+
 func (cfg *commandLineServerConfig) AllowCleartextPasswords() bool {
 	return cfg.allowCleartextPasswords
 }
@@ -350,6 +392,21 @@ func (cfg *commandLineServerConfig) Socket() string {
 	return cfg.socket
 }
 
+// XPort is the port to serve the MySQL X Protocol on, or nil if it shouldn't be served.
+func (cfg *commandLineServerConfig) XPort() *int {
+	return cfg.xPort
+}
+
+// PostgresPort is the port to serve the PostgreSQL protocol on, or nil if it shouldn't be served.
+func (cfg *commandLineServerConfig) PostgresPort() *int {
+	return cfg.postgresPort
+}
+
+// FlightSQLPort is the port to serve Arrow Flight SQL on, or nil if it shouldn't be served.
+func (cfg *commandLineServerConfig) FlightSQLPort() *int {
+	return cfg.flightSQLPort
+}
+
 // WithHost updates the host and returns the called `*commandLineServerConfig`, which is useful for chaining calls.
 func (cfg *commandLineServerConfig) WithHost(host string) *commandLineServerConfig {
 	cfg.host = host
@@ -455,6 +512,24 @@ func (cfg *commandLineServerConfig) WithRemotesapiPort(port *int) *commandLineSe
 	return cfg
 }
 
+// WithXPort sets the port to serve the MySQL X Protocol on.
+func (cfg *commandLineServerConfig) WithXPort(port *int) *commandLineServerConfig {
+	cfg.xPort = port
+	return cfg
+}
+
+// WithPostgresPort sets the port to serve the PostgreSQL protocol on.
+func (cfg *commandLineServerConfig) WithPostgresPort(port *int) *commandLineServerConfig {
+	cfg.postgresPort = port
+	return cfg
+}
+
+// WithFlightSQLPort sets the port to serve Arrow Flight SQL on.
+func (cfg *commandLineServerConfig) WithFlightSQLPort(port *int) *commandLineServerConfig {
+	cfg.flightSQLPort = port
+	return cfg
+}
+
 func (cfg *commandLineServerConfig) WithRemotesapiReadOnly(readonly *bool) *commandLineServerConfig {
 	cfg.remotesapiReadOnly = readonly
 	return cfg
@@ -0,0 +1,216 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/mysql_file_handler"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/libraries/utils/svcs"
+)
+
+// ReloadConfigProcedureName is the name of the stored procedure which reloads the config of a running server.
+const ReloadConfigProcedureName = "dolt_reload_config"
+
+// ConfigReloader reads the config of a running server again.
+type ConfigReloader func() (servercfg.ServerConfig, error)
+
+// newConfigReloader returns a ConfigReloader which parses |args|, the arguments the server was started with, again,
+// and reads the config file they name from |cwdFS|.
+func newConfigReloader(ap *argparser.ArgParser, args []string, cwdFS filesys.Filesys) ConfigReloader {
+	return func() (servercfg.ServerConfig, error) {
+		apr, err := ap.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		return getServerConfig(cwdFS, apr, DoltServerConfigReader{})
+	}
+}
+
+// configReloader applies the settings of a reloaded config to a running server. Only the log level, the connection
+// limit, the system variables, which include the replication settings, and the users loaded from the privilege file
+// are reloaded. Every other setting, such as the listener and cluster config, only changes when the server restarts.
+type configReloader struct {
+	mu           sync.Mutex
+	reload       ConfigReloader
+	serverConfig servercfg.ServerConfig
+	sqlEngine    *engine.SqlEngine
+	localCreds   *LocalCreds
+	// reloadUsers is false when the users aren't loaded from the privilege file, such as when they are replicated
+	// from the primary of a cluster
+	reloadUsers bool
+}
+
+// reloadConfig reads the server's config again and applies the settings which can change while it runs.
+func (r *configReloader) reloadConfig(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := r.reload()
+	if err != nil {
+		return err
+	}
+	if err = servercfg.ValidateConfig(cfg); err != nil {
+		return err
+	}
+
+	level, err := logrus.ParseLevel(cfg.LogLevel().String())
+	if err != nil {
+		return err
+	}
+	// @@dolt_log_level reports the level of the logger, so they are changed together
+	if err = sql.SystemVariables.SetGlobal(dsess.DoltLogLevel, level.String()); err != nil {
+		return err
+	}
+	logrus.SetLevel(level)
+
+	if cfg.PersistenceBehavior() != servercfg.LoadPerisistentGlobals {
+		if err = sql.SystemVariables.SetGlobal("max_connections", cfg.MaxConnections()); err != nil {
+			return err
+		}
+	}
+	if err = servercfg.ApplySystemVariables(cfg, sql.SystemVariables); err != nil {
+		return err
+	}
+
+	if !r.reloadUsers {
+		return nil
+	}
+	if cfg.PrivilegeFilePath() != r.serverConfig.PrivilegeFilePath() {
+		logrus.Warnf("privilege_file changed to %s; restart the server to load users from it", cfg.PrivilegeFilePath())
+	}
+	return r.reloadPrivileges(ctx, cfg)
+}
+
+// reloadPrivileges loads the users and grants from the server's privilege file again, and adds back the users which
+// the server creates when it starts, which aren't stored in it.
+func (r *configReloader) reloadPrivileges(ctx context.Context, cfg servercfg.ServerConfig) error {
+	persister := mysql_file_handler.NewPersister(r.serverConfig.PrivilegeFilePath(), r.serverConfig.CfgDir())
+	data, err := persister.LoadData(ctx)
+	if err != nil {
+		return err
+	}
+
+	sqlCtx, err := r.sqlEngine.NewDefaultContext(ctx)
+	if err != nil {
+		return err
+	}
+	mysqlDb := r.sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb
+	if err = mysqlDb.LoadData(sqlCtx, data); err != nil {
+		return err
+	}
+
+	addServerSuperUser(mysqlDb, cfg.User(), cfg.Password())
+	addLocalConnectionUser(mysqlDb, r.localCreds)
+	return nil
+}
+
+// newReloadConfigProcedure returns the DOLT_RELOAD_CONFIG() stored procedure, which reloads the config of the running
+// server the same way it is reloaded when the server receives SIGHUP.
+func newReloadConfigProcedure(r *configReloader) sql.ExternalStoredProcedureDetails {
+	return sql.ExternalStoredProcedureDetails{
+		Name:   ReloadConfigProcedureName,
+		Schema: sql.Schema{&sql.Column{Name: "status", Type: types.Int64, Nullable: false}},
+		Function: func(ctx *sql.Context) (sql.RowIter, error) {
+			if err := r.reloadConfig(ctx); err != nil {
+				return nil, err
+			}
+			return sql.RowsToRowIter(sql.Row{int64(0)}), nil
+		},
+		ReadOnly:  true,
+		AdminOnly: true,
+	}
+}
+
+// configReloadService reloads the server's config whenever the process receives SIGHUP.
+type configReloadService struct {
+	reloader *configReloader
+	sigCh    chan os.Signal
+	stopCh   chan struct{}
+}
+
+func (s *configReloadService) Init(context.Context) error {
+	s.sigCh = make(chan os.Signal, 1)
+	s.stopCh = make(chan struct{})
+	signal.Notify(s.sigCh, syscall.SIGHUP)
+	return nil
+}
+
+func (s *configReloadService) Stop() error {
+	signal.Stop(s.sigCh)
+	close(s.stopCh)
+	return nil
+}
+
+func (s *configReloadService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-s.sigCh:
+			if err := s.reloader.reloadConfig(ctx); err != nil {
+				logrus.Errorf("failed to reload server config: %v", err)
+			} else {
+				logrus.Info("reloaded server config")
+			}
+		}
+	}
+}
+
+var _ svcs.Service = &configReloadService{}
+
+// addServerSuperUser adds |user| as a superuser if it doesn't exist yet. If no user is given, the default root user is
+// added when there are no users at all.
+func addServerSuperUser(mysqlDb *mysql_db.MySQLDb, user, pass string) {
+	ed := mysqlDb.Editor()
+	defer ed.Close()
+
+	if user != "" {
+		if mysqlDb.GetUser(ed, user, "%", false) == nil {
+			mysqlDb.AddSuperUser(ed, user, "%", pass)
+		}
+		return
+	}
+
+	var numUsers int
+	ed.VisitUsers(func(*mysql_db.User) { numUsers += 1 })
+	if numUsers == 0 {
+		mysqlDb.AddSuperUser(ed, servercfg.DefaultUser, "%", servercfg.DefaultPass)
+	}
+}
+
+// addLocalConnectionUser adds the user which local dolt commands connect to the server as, with the credentials in
+// |creds|.
+func addLocalConnectionUser(mysqlDb *mysql_db.MySQLDb, creds *LocalCreds) {
+	ed := mysqlDb.Editor()
+	defer ed.Close()
+	mysqlDb.AddSuperUser(ed, LocalConnectionUser, "localhost", creds.Secret)
+}
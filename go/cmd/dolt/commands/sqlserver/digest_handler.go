@@ -0,0 +1,108 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/perfschema"
+)
+
+// digestHandler is a mysql.Handler which reports the outcome of each statement it runs to the statement digests
+// shown in performance_schema.
+type digestHandler struct {
+	mysql.Handler
+	digests *perfschema.StatementDigests
+}
+
+var _ mysql.Handler = digestHandler{}
+var _ mysql.BinlogReplicaHandler = digestHandler{}
+
+func newDigestHandler(h mysql.Handler, digests *perfschema.StatementDigests) mysql.Handler {
+	if digests == nil {
+		return h
+	}
+	return digestHandler{Handler: h, digests: digests}
+}
+
+func (h digestHandler) ComQuery(ctx context.Context, c *mysql.Conn, query string, callback mysql.ResultSpoolFn) error {
+	var counts resultCounts
+	err := h.Handler.ComQuery(ctx, c, query, func(res *sqltypes.Result, more bool) error {
+		counts.add(res)
+		return callback(res, more)
+	})
+	h.statementDone(c, err, counts)
+	return err
+}
+
+func (h digestHandler) ComMultiQuery(ctx context.Context, c *mysql.Conn, query string, callback mysql.ResultSpoolFn) (string, error) {
+	var counts resultCounts
+	remainder, err := h.Handler.ComMultiQuery(ctx, c, query, func(res *sqltypes.Result, more bool) error {
+		counts.add(res)
+		return callback(res, more)
+	})
+	h.statementDone(c, err, counts)
+	return remainder, err
+}
+
+func (h digestHandler) ComStmtExecute(ctx context.Context, c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	var counts resultCounts
+	err := h.Handler.ComStmtExecute(ctx, c, prepare, func(res *sqltypes.Result) error {
+		counts.add(res)
+		return callback(res)
+	})
+	h.statementDone(c, err, counts)
+	return err
+}
+
+func (h digestHandler) statementDone(c *mysql.Conn, err error, counts resultCounts) {
+	warnings := uint64(h.Handler.WarningCount(c))
+	h.digests.StatementDone(c.ConnectionID, err != nil, counts.rowsSent, counts.rowsAffected, warnings)
+}
+
+func (h digestHandler) ComRegisterReplica(c *mysql.Conn, replicaHost string, replicaPort uint16, replicaUser string, replicaPassword string) error {
+	if brh, ok := h.Handler.(mysql.BinlogReplicaHandler); ok {
+		return brh.ComRegisterReplica(c, replicaHost, replicaPort, replicaUser, replicaPassword)
+	}
+	return fmt.Errorf("binlog replication is not supported")
+}
+
+func (h digestHandler) ComBinlogDumpGTID(c *mysql.Conn, logFile string, logPos uint64, gtidSet mysql.GTIDSet) error {
+	if brh, ok := h.Handler.(mysql.BinlogReplicaHandler); ok {
+		return brh.ComBinlogDumpGTID(c, logFile, logPos, gtidSet)
+	}
+	return fmt.Errorf("binlog replication is not supported")
+}
+
+// resultCounts counts the rows in the results of a statement.
+type resultCounts struct {
+	rowsSent     uint64
+	rowsAffected uint64
+}
+
+func (rc *resultCounts) add(res *sqltypes.Result) {
+	if res == nil {
+		return
+	}
+	if len(res.Fields) > 0 {
+		rc.rowsSent += uint64(len(res.Rows))
+	} else {
+		rc.rowsAffected += res.RowsAffected
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/cicmds"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// newMergeQueueMerger returns the sqle.MergeQueueMerger for the merge queue of |se|. Each merge is done in a
+// transaction on its target branch, which is only committed once the merge is done without conflicts and, if the merge
+// was queued with --run-tests, every test in dolt_tests passes against the merged tables. Merges are done as the user
+// who queued them.
+func newMergeQueueMerger(se *engine.SqlEngine) sqle.MergeQueueMerger {
+	return func(ctx context.Context, entry dsess.MergeQueueEntry) (string, error) {
+		sqlCtx, err := se.NewDefaultContext(ctx)
+		if err != nil {
+			return "", err
+		}
+		// The merge is done as the user who queued it, so that it's subject to their privileges and branch permissions
+		sqlCtx.Session.SetClient(sql.Client{User: entry.User, Address: entry.Host, Capabilities: 0})
+		sqlCtx.SetCurrentDatabase(dsess.RevisionDbName(entry.Database, entry.Target))
+
+		if _, err = commands.GetRowsForSql(se, sqlCtx, "start transaction"); err != nil {
+			return "", err
+		}
+		committed := false
+		defer func() {
+			if !committed {
+				commands.GetRowsForSql(se, sqlCtx, "rollback")
+			}
+		}()
+
+		rows, err := commands.InterpolateAndRunQuery(se, sqlCtx, "call dolt_merge('--no-ff', '--no-commit', ?)", entry.Source)
+		if err != nil {
+			return "", err
+		}
+		if len(rows) == 1 && len(rows[0]) > 2 && fmt.Sprint(rows[0][2]) != "0" {
+			return "", fmt.Errorf("merging %s into %s produced conflicts", entry.Source, entry.Target)
+		}
+
+		rows, err = commands.GetRowsForSql(se, sqlCtx, "select is_merging from dolt_merge_status")
+		if err != nil {
+			return "", err
+		}
+		merging, err := commands.GetTinyIntColAsBool(rows[0][0])
+		if err != nil {
+			return "", err
+		}
+		if !merging {
+			// the source branch was already merged into the target, so there's nothing to commit
+			rows, err = commands.GetRowsForSql(se, sqlCtx, "select hashof('HEAD')")
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprint(rows[0][0]), nil
+		}
+
+		if entry.RunTests {
+			if err = cicmds.RunAllTests(se, sqlCtx); err != nil {
+				return "", err
+			}
+		}
+
+		msg := fmt.Sprintf("Merge branch '%s' into %s", entry.Source, entry.Target)
+		rows, err = commands.InterpolateAndRunQuery(se, sqlCtx, "call dolt_commit('-m', ?)", msg)
+		if err != nil {
+			return "", err
+		}
+		if _, err = commands.GetRowsForSql(se, sqlCtx, "commit"); err != nil {
+			return "", err
+		}
+		committed = true
+		return fmt.Sprint(rows[0][0]), nil
+	}
+}
@@ -25,18 +25,26 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
 	"github.com/dolthub/dolt/go/libraries/utils/version"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
 )
 
 const (
 	clusterUpdateInterval = time.Second * 5
 
-	dbLabel     = "database"
-	roleLabel   = "role"
-	remoteLabel = "remote"
+	dbLabel        = "database"
+	roleLabel      = "role"
+	remoteLabel    = "remote"
+	partitionLabel = "partition"
+	tierLabel      = "tier"
 )
 
 var _ server.ServerEventListener = (*metricsListener)(nil)
 
+// journalStatsProvider returns the chunk journal stats of each database which has a chunk journal, keyed by database
+// name.
+type journalStatsProvider func() map[string]nbs.JournalStats
+
 type metricsListener struct {
 	labels prometheus.Labels
 
@@ -51,14 +59,27 @@ type metricsListener struct {
 	isReplicaGauges      *prometheus.GaugeVec
 	replicationLagGauges *prometheus.GaugeVec
 
+	// chunk journal metrics
+	journalSizeGauges        *prometheus.GaugeVec
+	journalCompactionLag     *prometheus.GaugeVec
+	journalCompactionsGauges *prometheus.GaugeVec
+
+	// node cache metrics
+	nodeCacheHitsGauges      *prometheus.GaugeVec
+	nodeCacheMissesGauges    *prometheus.GaugeVec
+	nodeCacheEvictionsGauges *prometheus.GaugeVec
+	nodeCacheSizeGauges      *prometheus.GaugeVec
+
 	// used in updating cluster metrics
 	clusterStatus  clusterdb.ClusterStatusProvider
 	mu             *sync.Mutex
 	done           bool
 	clusterSeenDbs map[string]struct{}
+	journalStats   journalStatsProvider
+	journalSeenDbs map[string]struct{}
 }
 
-func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStatus clusterdb.ClusterStatusProvider) (*metricsListener, error) {
+func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStatus clusterdb.ClusterStatusProvider, journalStats journalStatsProvider) (*metricsListener, error) {
 	ml := &metricsListener{
 		labels: labels,
 		cntConnections: prometheus.NewCounter(prometheus.CounterOpts{
@@ -102,9 +123,46 @@ func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStat
 			Help:        "one if the server is currently in this role, zero otherwise",
 			ConstLabels: labels,
 		}, []string{dbLabel}),
+		journalSizeGauges: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_journal_size",
+			Help:        "The size in bytes of the chunk journal of the database.",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+		journalCompactionLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_journal_compaction_lag",
+			Help:        "The number of bytes by which the chunk journal of the database exceeds its maximum size and is yet to be compacted.",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+		journalCompactionsGauges: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_journal_compactions",
+			Help:        "The number of times the chunk journal of the database has been compacted since the server started.",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+		nodeCacheHitsGauges: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_node_cache_hits",
+			Help:        "The number of chunks read from the tier of the node cache since the server started.",
+			ConstLabels: labels,
+		}, []string{partitionLabel, tierLabel}),
+		nodeCacheMissesGauges: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_node_cache_misses",
+			Help:        "The number of chunks looked up in the tier of the node cache and not found since the server started.",
+			ConstLabels: labels,
+		}, []string{partitionLabel, tierLabel}),
+		nodeCacheEvictionsGauges: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_node_cache_evictions",
+			Help:        "The number of chunks evicted from the tier of the node cache since the server started.",
+			ConstLabels: labels,
+		}, []string{partitionLabel, tierLabel}),
+		nodeCacheSizeGauges: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_node_cache_size",
+			Help:        "The size in bytes of the chunks held by the tier of the node cache.",
+			ConstLabels: labels,
+		}, []string{partitionLabel, tierLabel}),
 		clusterStatus:  clusterStatus,
 		mu:             &sync.Mutex{},
 		clusterSeenDbs: make(map[string]struct{}),
+		journalStats:   journalStats,
+		journalSeenDbs: make(map[string]struct{}),
 	}
 
 	u32Version, err := version.Encode(versionStr)
@@ -126,9 +184,16 @@ func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStat
 	prometheus.MustRegister(ml.histQueryDur)
 	prometheus.MustRegister(ml.replicationLagGauges)
 	prometheus.MustRegister(ml.isReplicaGauges)
+	prometheus.MustRegister(ml.journalSizeGauges)
+	prometheus.MustRegister(ml.journalCompactionLag)
+	prometheus.MustRegister(ml.journalCompactionsGauges)
+	prometheus.MustRegister(ml.nodeCacheHitsGauges)
+	prometheus.MustRegister(ml.nodeCacheMissesGauges)
+	prometheus.MustRegister(ml.nodeCacheEvictionsGauges)
+	prometheus.MustRegister(ml.nodeCacheSizeGauges)
 
 	go func() {
-		for ml.updateReplMetrics() {
+		for ml.updateReplMetrics() && ml.updateJournalMetrics() && ml.updateNodeCacheMetrics() {
 			time.Sleep(clusterUpdateInterval)
 		}
 	}()
@@ -181,6 +246,61 @@ func (ml *metricsListener) updateReplMetrics() bool {
 	return true
 }
 
+func (ml *metricsListener) updateJournalMetrics() bool {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	if ml.done {
+		return false
+	}
+	if ml.journalStats == nil {
+		return true
+	}
+
+	perDbStats := ml.journalStats()
+	dbNames := make(map[string]struct{})
+	for db, stats := range perDbStats {
+		dbNames[db] = struct{}{}
+		ml.journalSizeGauges.WithLabelValues(db).Set(float64(stats.Size))
+		ml.journalCompactionLag.WithLabelValues(db).Set(float64(stats.CompactionLag))
+		ml.journalCompactionsGauges.WithLabelValues(db).Set(float64(stats.Compactions))
+	}
+
+	// deregister metrics for deleted databases
+	for db := range ml.journalSeenDbs {
+		if _, ok := dbNames[db]; !ok {
+			ml.journalSizeGauges.DeleteLabelValues(db)
+			ml.journalCompactionLag.DeleteLabelValues(db)
+			ml.journalCompactionsGauges.DeleteLabelValues(db)
+		}
+	}
+	ml.journalSeenDbs = dbNames
+
+	return true
+}
+
+func (ml *metricsListener) updateNodeCacheMetrics() bool {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	if ml.done {
+		return false
+	}
+
+	// partitions are never removed, so neither are their metrics
+	for _, stats := range tree.NodeCacheStats() {
+		partition := stats.Partition
+		if partition == "" {
+			partition = "shared"
+		}
+		ml.nodeCacheHitsGauges.WithLabelValues(partition, stats.Tier).Set(float64(stats.Hits))
+		ml.nodeCacheMissesGauges.WithLabelValues(partition, stats.Tier).Set(float64(stats.Misses))
+		ml.nodeCacheEvictionsGauges.WithLabelValues(partition, stats.Tier).Set(float64(stats.Evictions))
+		ml.nodeCacheSizeGauges.WithLabelValues(partition, stats.Tier).Set(float64(stats.Size))
+	}
+	return true
+}
+
 func (ml *metricsListener) ClientConnected() {
 	ml.gaugeConcurrentConn.Add(1.0)
 	ml.cntConnections.Add(1.0)
@@ -217,6 +337,13 @@ func (ml *metricsListener) closeReplicationMetrics() {
 
 	prometheus.Unregister(ml.replicationLagGauges)
 	prometheus.Unregister(ml.isReplicaGauges)
+	prometheus.Unregister(ml.journalSizeGauges)
+	prometheus.Unregister(ml.journalCompactionLag)
+	prometheus.Unregister(ml.journalCompactionsGauges)
+	prometheus.Unregister(ml.nodeCacheHitsGauges)
+	prometheus.Unregister(ml.nodeCacheMissesGauges)
+	prometheus.Unregister(ml.nodeCacheEvictionsGauges)
+	prometheus.Unregister(ml.nodeCacheSizeGauges)
 
 	ml.done = true
 }
@@ -0,0 +1,197 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"sync/atomic"
+
+	gms "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/memstats"
+)
+
+// preparedStmtCacheSize is the number of prepared statements whose analysis is shared between sessions.
+const preparedStmtCacheSize = 1024
+
+// schemaChangeRegex matches the statements which may change the schema of a database, invalidating the statements
+// prepared before them. Stored procedures are included, since the Dolt procedures can change the schema of the
+// working set by merging, resetting or checking out branches.
+var schemaChangeRegex = regexp.MustCompile(`(?is)^\s*(?:create|alter|drop|rename|truncate|call)\b`)
+
+// connSessions are the sessions of the server's connections, keyed by connection ID.
+type connSessions struct {
+	sessions sync.Map
+}
+
+func (cs *connSessions) add(connID uint32, sess *dsess.DoltSession) {
+	cs.sessions.Store(connID, sess)
+}
+
+func (cs *connSessions) get(connID uint32) (*dsess.DoltSession, bool) {
+	sess, ok := cs.sessions.Load(connID)
+	if !ok {
+		return nil, false
+	}
+	return sess.(*dsess.DoltSession), true
+}
+
+func (cs *connSessions) remove(connID uint32) {
+	cs.sessions.Delete(connID)
+}
+
+// preparedStmtCache is a server-wide cache of the result fields of prepared statements, which preparing a statement
+// analyzes it to find. Entries are keyed by the schema generation they were prepared in, which is advanced by every
+// statement which may change a schema.
+type preparedStmtCache struct {
+	fields     *lru.Cache[string, []*querypb.Field]
+	generation atomic.Uint64
+}
+
+func newPreparedStmtCache() *preparedStmtCache {
+	// only errors for a non-positive size
+	fields, _ := lru.New[string, []*querypb.Field](preparedStmtCacheSize)
+	return &preparedStmtCache{fields: fields}
+}
+
+// memorySource returns the memstats.Source reporting the statements held by the cache. Flushing it only drops the
+// shared analysis; statements prepared by clients stay prepared.
+func (pc *preparedStmtCache) memorySource() memstats.Source {
+	return memstats.Source{
+		Name: "prepared_statements",
+		Usage: func(*sql.Context) []memstats.Usage {
+			return []memstats.Usage{{
+				Component: "shared analysis",
+				Bytes:     memstats.Unknown,
+				Capacity:  memstats.Unknown,
+				Entries:   int64(pc.fields.Len()),
+			}}
+		},
+		Flush: func(*sql.Context) { pc.fields.Purge() },
+	}
+}
+
+// key returns the key the statement |query|, prepared in the session |ctx|, is cached with. The current database
+// and branch determine which tables the statement reads, and the SQL mode how it's parsed.
+func (pc *preparedStmtCache) key(ctx *sql.Context, query string) (string, error) {
+	branch, err := dsess.DSessFromSess(ctx.Session).GetBranch()
+	if err != nil {
+		return "", err
+	}
+	sqlMode, err := ctx.GetSessionVariable(ctx, "sql_mode")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d\x00%s\x00%s\x00%v\x00%s", pc.generation.Load(), ctx.GetCurrentDatabase(), branch, sqlMode, query), nil
+}
+
+// statementDone invalidates the cache if |query| may have changed a schema.
+func (pc *preparedStmtCache) statementDone(query string) {
+	if schemaChangeRegex.MatchString(query) {
+		pc.generation.Add(1)
+	}
+}
+
+// preparedStmtHandler is a mysql.Handler which shares the analysis of prepared statements between sessions, so
+// applications which prepare the same statements on each of their connections only pay for it once.
+type preparedStmtHandler struct {
+	mysql.Handler
+	engine   *gms.Engine
+	sessions *connSessions
+	cache    *preparedStmtCache
+}
+
+var _ mysql.Handler = preparedStmtHandler{}
+var _ mysql.BinlogReplicaHandler = preparedStmtHandler{}
+
+func newPreparedStmtHandler(h mysql.Handler, engine *gms.Engine, sessions *connSessions) mysql.Handler {
+	cache := newPreparedStmtCache()
+	memstats.Register(cache.memorySource())
+	return preparedStmtHandler{Handler: h, engine: engine, sessions: sessions, cache: cache}
+}
+
+func (h preparedStmtHandler) ComPrepare(ctx context.Context, c *mysql.Conn, query string, prepare *mysql.PrepareData) ([]*querypb.Field, error) {
+	sess, ok := h.sessions.get(c.ConnectionID)
+	if !ok {
+		return h.Handler.ComPrepare(ctx, c, query, prepare)
+	}
+	sqlCtx := sql.NewContext(ctx, sql.WithSession(sess))
+	key, err := h.cache.key(sqlCtx, query)
+	if err != nil {
+		return h.Handler.ComPrepare(ctx, c, query, prepare)
+	}
+
+	if fields, ok := h.cache.fields.Get(key); ok {
+		// The session still needs the statement to execute it, which only takes parsing it
+		stmt, _, _, err := h.engine.Parser.ParseWithOptions(ctx, query, ';', false, sql.LoadSqlMode(sqlCtx).ParserOptions())
+		if err == nil {
+			h.engine.PreparedDataCache.CacheStmt(sess.ID(), query, stmt)
+			return fields, nil
+		}
+	}
+
+	fields, err := h.Handler.ComPrepare(ctx, c, query, prepare)
+	if err != nil {
+		return nil, err
+	}
+	h.cache.fields.Add(key, fields)
+	return fields, nil
+}
+
+func (h preparedStmtHandler) ComQuery(ctx context.Context, c *mysql.Conn, query string, callback mysql.ResultSpoolFn) error {
+	defer h.cache.statementDone(query)
+	return h.Handler.ComQuery(ctx, c, query, callback)
+}
+
+func (h preparedStmtHandler) ComMultiQuery(ctx context.Context, c *mysql.Conn, query string, callback mysql.ResultSpoolFn) (string, error) {
+	defer h.cache.statementDone(query)
+	return h.Handler.ComMultiQuery(ctx, c, query, callback)
+}
+
+func (h preparedStmtHandler) ComStmtExecute(ctx context.Context, c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	defer h.cache.statementDone(prepare.PrepareStmt)
+	return h.Handler.ComStmtExecute(ctx, c, prepare, callback)
+}
+
+func (h preparedStmtHandler) ConnectionClosed(c *mysql.Conn) {
+	if sess, ok := h.sessions.get(c.ConnectionID); ok {
+		sess.EndSession(sql.NewContext(context.Background(), sql.WithSession(sess)))
+	}
+	h.sessions.remove(c.ConnectionID)
+	h.Handler.ConnectionClosed(c)
+}
+
+func (h preparedStmtHandler) ComRegisterReplica(c *mysql.Conn, replicaHost string, replicaPort uint16, replicaUser string, replicaPassword string) error {
+	if brh, ok := h.Handler.(mysql.BinlogReplicaHandler); ok {
+		return brh.ComRegisterReplica(c, replicaHost, replicaPort, replicaUser, replicaPassword)
+	}
+	return fmt.Errorf("binlog replication is not supported")
+}
+
+func (h preparedStmtHandler) ComBinlogDumpGTID(c *mysql.Conn, logFile string, logPos uint64, gtidSet mysql.GTIDSet) error {
+	if brh, ok := h.Handler.(mysql.BinlogReplicaHandler); ok {
+		return brh.ComBinlogDumpGTID(c, logFile, logPos, gtidSet)
+	}
+	return fmt.Errorf("binlog replication is not supported")
+}
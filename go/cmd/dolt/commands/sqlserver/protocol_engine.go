@@ -0,0 +1,143 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/flightsql"
+	"github.com/dolthub/dolt/go/libraries/doltcore/httpapi"
+	"github.com/dolthub/dolt/go/libraries/doltcore/mysqlx"
+	"github.com/dolthub/dolt/go/libraries/doltcore/pgwire"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// protocolEngine runs the statements of the clients of the X Protocol, PostgreSQL protocol and Arrow Flight SQL
+// listeners, and of the HTTP API, on a SqlEngine, authenticating them against the same users as MySQL protocol clients.
+type protocolEngine struct {
+	sqlEngine *engine.SqlEngine
+	mysqlDb   *mysql_db.MySQLDb
+}
+
+var _ mysqlx.Engine = (*protocolEngine)(nil)
+var _ pgwire.Engine = (*protocolEngine)(nil)
+var _ httpapi.Engine = (*protocolEngine)(nil)
+var _ flightsql.Engine = (*protocolEngine)(nil)
+
+func newProtocolEngine(sqlEngine *engine.SqlEngine) *protocolEngine {
+	return &protocolEngine{
+		sqlEngine: sqlEngine,
+		mysqlDb:   sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb,
+	}
+}
+
+func (e *protocolEngine) ValidateScramble(user string, salt, scramble []byte, addr net.Addr) error {
+	authenticated, err := e.mysqlDb.ValidateHash(salt, user, scramble, addr)
+	if err != nil {
+		return err
+	}
+	if authenticated == nil {
+		return fmt.Errorf("unable to authenticate user %s", user)
+	}
+	return nil
+}
+
+// ValidatePassword checks |password| with the authentication plugin of the user it authenticates, so that users
+// created WITH any plugin can log in to protocols which receive passwords in cleartext.
+func (e *protocolEngine) ValidatePassword(user, password string, addr net.Addr) error {
+	rd := e.mysqlDb.Reader()
+	userEntry := e.mysqlDb.GetUser(rd, user, hostOf(addr), false)
+	rd.Close()
+	if userEntry == nil || userEntry.Locked {
+		return fmt.Errorf("unable to authenticate user %s", user)
+	}
+	authenticated, err := e.sqlEngine.ValidatePassword(user, userEntry, password)
+	if err != nil {
+		return err
+	}
+	if !authenticated {
+		return fmt.Errorf("unable to authenticate user %s", user)
+	}
+	return nil
+}
+
+func (e *protocolEngine) NewSession(ctx context.Context, user string, addr net.Addr, database string) (sql.Session, error) {
+	sqlCtx, err := e.sqlEngine.NewConnectionContext(ctx, user, database, "")
+	if err != nil {
+		return nil, err
+	}
+	sqlCtx.Session.SetClient(sql.Client{User: user, Address: hostOf(addr), Capabilities: 0})
+	return sqlCtx.Session, nil
+}
+
+// NewReadOnlySession returns a new session like NewSession, whose transactions are all READ ONLY. Its first
+// transaction is started explicitly, so that the engine checks the first statement run in it as well.
+func (e *protocolEngine) NewReadOnlySession(ctx context.Context, user string, addr net.Addr, database string) (sql.Session, error) {
+	sess, err := e.NewSession(ctx, user, addr, database)
+	if err != nil {
+		return nil, err
+	}
+	dSess := dsess.DSessFromSess(sess)
+	dSess.SetReadOnly()
+	sqlCtx, err := e.NewContext(ctx, sess)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := dSess.StartTransaction(sqlCtx, sql.ReadOnly)
+	if err != nil {
+		return nil, err
+	}
+	sqlCtx.SetTransaction(tx)
+	sqlCtx.SetIgnoreAutoCommit(true)
+	return sess, nil
+}
+
+// CloseSession ends |sess| once its client has disconnected, the way the MySQL listener ends the session of a closed
+// connection: its transaction is rolled back, its user locks and row locks are released, and its temporary tables and
+// ephemeral branches are deleted.
+func (e *protocolEngine) CloseSession(ctx context.Context, sess sql.Session) {
+	sqlCtx, err := e.NewContext(ctx, sess)
+	if err != nil {
+		return
+	}
+	if _, err = e.sqlEngine.GetUnderlyingEngine().LS.ReleaseAll(sqlCtx); err != nil {
+		sqlCtx.GetLogger().Warnf("failed to release the user locks of closed session %d: %s", sess.ID(), err.Error())
+	}
+	dsess.DSessFromSess(sess).EndSession(sqlCtx)
+}
+
+func (e *protocolEngine) NewContext(ctx context.Context, sess sql.Session) (*sql.Context, error) {
+	return e.sqlEngine.NewContext(ctx, sess)
+}
+
+func (e *protocolEngine) Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error) {
+	sch, iter, _, err := e.sqlEngine.Query(ctx, query)
+	return sch, iter, err
+}
+
+// hostOf returns the host of |addr|, without its port.
+func hostOf(addr net.Addr) string {
+	host := addr.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return host
+}
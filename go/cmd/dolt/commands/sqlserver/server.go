@@ -24,6 +24,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dolthub/go-mysql-server/eventscheduler"
@@ -44,8 +45,14 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc/kafka"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/flightsql"
+	"github.com/dolthub/dolt/go/libraries/doltcore/httpapi"
+	"github.com/dolthub/dolt/go/libraries/doltcore/mysqlx"
+	"github.com/dolthub/dolt/go/libraries/doltcore/pgwire"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/servercfg"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
@@ -57,6 +64,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/events"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/libraries/utils/svcs"
+	"github.com/dolthub/dolt/go/store/nbs"
 )
 
 const (
@@ -73,11 +81,13 @@ var ExternalDisableUsers bool = false
 
 var ErrCouldNotLockDatabase = goerrors.NewKind("database \"%s\" is locked by another dolt process; either clone the database to run a second server, or stop the dolt process which currently holds an exclusive write lock on the database")
 
-// Serve starts a MySQL-compatible server. Returns any errors that were encountered.
+// Serve starts a MySQL-compatible server. |reloader| reads the server's config again when it is reloaded, and may be
+// nil. Returns any errors that were encountered.
 func Serve(
 	ctx context.Context,
 	version string,
 	serverConfig servercfg.ServerConfig,
+	reloader ConfigReloader,
 	controller *svcs.Controller,
 	dEnv *env.DoltEnv,
 ) (startError error, closeError error) {
@@ -86,7 +96,7 @@ func Serve(
 		controller = svcs.NewController()
 	}
 
-	ConfigureServices(serverConfig, controller, version, dEnv)
+	ConfigureServices(serverConfig, reloader, controller, version, dEnv)
 
 	go controller.Start(ctx)
 	err := controller.WaitForStart()
@@ -96,8 +106,11 @@ func Serve(
 	return nil, controller.WaitForStop()
 }
 
+// ConfigureServices registers the services which run the server with |controller|. |reloader| reads the server's
+// config again when it is reloaded; if it is nil, reloading applies |serverConfig| again.
 func ConfigureServices(
 	serverConfig servercfg.ServerConfig,
+	reloader ConfigReloader,
 	controller *svcs.Controller,
 	version string,
 	dEnv *env.DoltEnv,
@@ -246,6 +259,9 @@ func ConfigureServices(
 				Autocommit:              serverConfig.AutoCommit(),
 				DoltTransactionCommit:   serverConfig.DoltTransactionCommit(),
 				JwksConfig:              serverConfig.JwksConfig(),
+				LDAPConfig:              serverConfig.LDAPConfig(),
+				OIDCConfig:              serverConfig.OIDCConfig(),
+				AuthPlugins:             serverConfig.AuthPlugins(),
 				SystemVariables:         serverConfig.SystemVars(),
 				ClusterController:       clusterController,
 				BinlogReplicaController: binlogreplication.DoltBinlogReplicaController,
@@ -289,6 +305,20 @@ func ConfigureServices(
 	}
 	controller.Register(InitSqlEngine)
 
+	InitMergeQueue := &svcs.AnonService{
+		InitF: func(context.Context) error {
+			return sqlEngine.StartMergeQueue(newMergeQueueMerger(sqlEngine))
+		},
+	}
+	controller.Register(InitMergeQueue)
+
+	InitEphemeralBranchCleanup := &svcs.AnonService{
+		InitF: func(context.Context) error {
+			return sqlEngine.StartEphemeralBranchCleanup()
+		},
+	}
+	controller.Register(InitEphemeralBranchCleanup)
+
 	// Persist any system variables that have a non-deterministic default value (i.e. @@server_uuid)
 	// We only do this on sql-server startup initially since we want to keep the persisted server_uuid
 	// in the configuration files for a sql-server, and not global for the whole host.
@@ -375,23 +405,7 @@ func ConfigureServices(
 	// Add superuser if specified user exists; add root superuser if no user specified and no existing privileges
 	InitSuperUser := &svcs.AnonService{
 		InitF: func(context.Context) error {
-			userSpecified := config.ServerUser != ""
-
-			mysqlDb := sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb
-			ed := mysqlDb.Editor()
-			var numUsers int
-			ed.VisitUsers(func(*mysql_db.User) { numUsers += 1 })
-			privsExist := numUsers != 0
-			if userSpecified {
-				superuser := mysqlDb.GetUser(ed, config.ServerUser, "%", false)
-				if userSpecified && superuser == nil {
-					mysqlDb.AddSuperUser(ed, config.ServerUser, "%", config.ServerPass)
-				}
-			} else if !privsExist {
-				mysqlDb.AddSuperUser(ed, servercfg.DefaultUser, "%", servercfg.DefaultPass)
-			}
-			ed.Close()
-
+			addServerSuperUser(sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb, config.ServerUser, config.ServerPass)
 			return nil
 		},
 	}
@@ -401,7 +415,9 @@ func ConfigureServices(
 	InitMetricsListener := &svcs.AnonService{
 		InitF: func(context.Context) (err error) {
 			labels := serverConfig.MetricsLabels()
-			metListener, err = newMetricsListener(labels, version, clusterController)
+			metListener, err = newMetricsListener(labels, version, clusterController, func() map[string]nbs.JournalStats {
+				return journalStatsByDatabase(sqlEngine)
+			})
 			return err
 		},
 		StopF: func() error {
@@ -413,10 +429,7 @@ func ConfigureServices(
 
 	InitLockSuperUser := &svcs.AnonService{
 		InitF: func(context.Context) error {
-			mysqlDb := sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb
-			ed := mysqlDb.Editor()
-			mysqlDb.AddSuperUser(ed, LocalConnectionUser, "localhost", localCreds.Secret)
-			ed.Close()
+			addLocalConnectionUser(sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb, localCreds)
 			return nil
 		},
 	}
@@ -433,6 +446,27 @@ func ConfigureServices(
 	}
 	controller.Register(DisableMySQLDbIfRequired)
 
+	// The log level, connection limit, system variables and users can be reloaded from the config without restarting
+	// the server, on SIGHUP or with CALL DOLT_RELOAD_CONFIG()
+	if reloader == nil {
+		reloader = func() (servercfg.ServerConfig, error) { return serverConfig, nil }
+	}
+	cfgReloader := &configReloader{reload: reloader, serverConfig: serverConfig}
+	InitConfigReload := &svcs.AnonService{
+		InitF: func(context.Context) error {
+			cfgReloader.sqlEngine = sqlEngine
+			cfgReloader.localCreds = localCreds
+			cfgReloader.reloadUsers = clusterController == nil && !ExternalDisableUsers
+			provider := sqlEngine.GetUnderlyingEngine().Analyzer.Catalog.DbProvider
+			if doltProvider, ok := provider.(*sqle.DoltDatabaseProvider); ok {
+				doltProvider.Register(newReloadConfigProcedure(cfgReloader))
+			}
+			return nil
+		},
+	}
+	controller.Register(InitConfigReload)
+	controller.Register(&configReloadService{reloader: cfgReloader})
+
 	type SQLMetricsService struct {
 		state svcs.ServiceState
 		lis   net.Listener
@@ -506,6 +540,15 @@ func ConfigureServices(
 				HttpListenAddr:     listenaddr,
 				GrpcListenAddr:     listenaddr,
 				ConcurrencyControl: remotesapi.PushConcurrencyControl_PUSH_CONCURRENCY_CONTROL_ASSERT_WORKING_SET,
+				PreReceiveHooks:    []remotesrv.PreReceiveHook{sqle.BranchProtectionPreReceiveHook(sqlEngine.NewDefaultContext)},
+			}
+			for _, h := range serverConfig.RemotesapiPreReceiveHooks() {
+				timeout := time.Duration(h.TimeoutMillis()) * time.Millisecond
+				args.PreReceiveHooks = append(args.PreReceiveHooks, remotesrv.NewWebhookPreReceiveHook(h.URL(), timeout))
+			}
+			for _, h := range serverConfig.RemotesapiPostReceiveHooks() {
+				timeout := time.Duration(h.TimeoutMillis()) * time.Millisecond
+				args.PostReceiveHooks = append(args.PostReceiveHooks, remotesrv.NewWebhookPostReceiveHook(args.Logger, h.URL(), timeout))
 			}
 			var err error
 			args.FS, args.DBCache, err = sqle.RemoteSrvFSAndDBCache(sqlEngine.NewDefaultContext, sqle.DoNotCreateUnknownDatabases)
@@ -614,24 +657,21 @@ func ConfigureServices(
 	InitSQLServer := &svcs.AnonService{
 		InitF: func(context.Context) (err error) {
 			v, ok := serverConfig.(servercfg.ValidatingServerConfig)
-			if ok && v.GoldenMysqlConnectionString() != "" {
-				mySQLServer, err = server.NewServerWithHandler(
-					serverConf,
-					sqlEngine.GetUnderlyingEngine(),
-					newSessionBuilder(sqlEngine, serverConfig),
-					metListener,
-					func(h mysql.Handler) (mysql.Handler, error) {
+			sessions := &connSessions{}
+			mySQLServer, err = server.NewServerWithHandler(
+				serverConf,
+				sqlEngine.GetUnderlyingEngine(),
+				newSessionBuilder(sqlEngine, serverConfig, sessions),
+				metListener,
+				func(h mysql.Handler) (mysql.Handler, error) {
+					h = newPreparedStmtHandler(h, sqlEngine.GetUnderlyingEngine(), sessions)
+					h = newDigestHandler(h, sqlEngine.StatementDigests())
+					if ok && v.GoldenMysqlConnectionString() != "" {
 						return golden.NewValidatingHandler(h, v.GoldenMysqlConnectionString(), logrus.StandardLogger())
-					},
-				)
-			} else {
-				mySQLServer, err = server.NewServer(
-					serverConf,
-					sqlEngine.GetUnderlyingEngine(),
-					newSessionBuilder(sqlEngine, serverConfig),
-					metListener,
-				)
-			}
+					}
+					return h, nil
+				},
+			)
 			if errors.Is(err, server.UnixSocketInUseError) {
 				lgr.Warn("unix socket set up failed: file already in use: ", serverConf.Socket)
 				err = nil
@@ -688,6 +728,286 @@ func ConfigureServices(
 	}
 	controller.Register(RunClusterController)
 
+	type XProtocolService struct {
+		state svcs.ServiceState
+		lis   net.Listener
+		srv   *mysqlx.Server
+	}
+	var xSrv XProtocolService
+	RunXProtocolServer := &svcs.AnonService{
+		InitF: func(context.Context) error {
+			if serverConfig.XPort() == nil {
+				return nil
+			}
+			xSrv.state.Swap(svcs.ServiceState_Init)
+
+			port := *serverConfig.XPort()
+			var err error
+			xSrv.lis, err = net.Listen("tcp", net.JoinHostPort(serverConfig.Host(), strconv.Itoa(port)))
+			if err != nil {
+				lgr.Errorf("error starting X Protocol server on port %d: %v", port, err)
+				return err
+			}
+			xSrv.srv = mysqlx.NewServer(mysqlx.ServerArgs{
+				Logger:                  logrus.NewEntry(lgr),
+				Engine:                  newProtocolEngine(sqlEngine),
+				AllowCleartextPasswords: serverConfig.AllowCleartextPasswords(),
+			})
+			return nil
+		},
+		RunF: func(context.Context) {
+			if xSrv.state.CompareAndSwap(svcs.ServiceState_Init, svcs.ServiceState_Run) {
+				if err := xSrv.srv.Serve(xSrv.lis); !errors.Is(err, net.ErrClosed) {
+					lgr.Errorf("error serving X Protocol: %v", err)
+				}
+			}
+		},
+		StopF: func() error {
+			state := xSrv.state.Swap(svcs.ServiceState_Stopped)
+			if state == svcs.ServiceState_Run {
+				xSrv.lis.Close()
+				return xSrv.srv.Close()
+			} else if state == svcs.ServiceState_Init {
+				xSrv.lis.Close()
+			}
+			return nil
+		},
+	}
+	controller.Register(RunXProtocolServer)
+
+	type PostgresService struct {
+		state svcs.ServiceState
+		lis   net.Listener
+		srv   *pgwire.Server
+	}
+	var pgSrv PostgresService
+	RunPostgresServer := &svcs.AnonService{
+		InitF: func(context.Context) error {
+			if serverConfig.PostgresPort() == nil {
+				return nil
+			}
+			tlsConfig, err := servercfg.LoadTLSConfig(serverConfig)
+			if err != nil {
+				return err
+			}
+			pgSrv.state.Swap(svcs.ServiceState_Init)
+
+			port := *serverConfig.PostgresPort()
+			pgSrv.lis, err = net.Listen("tcp", net.JoinHostPort(serverConfig.Host(), strconv.Itoa(port)))
+			if err != nil {
+				lgr.Errorf("error starting PostgreSQL protocol server on port %d: %v", port, err)
+				return err
+			}
+			pgSrv.srv = pgwire.NewServer(pgwire.ServerArgs{
+				Logger:                  logrus.NewEntry(lgr),
+				Engine:                  newProtocolEngine(sqlEngine),
+				TLSConfig:               tlsConfig,
+				AllowCleartextPasswords: serverConfig.AllowCleartextPasswords(),
+			})
+			return nil
+		},
+		RunF: func(context.Context) {
+			if pgSrv.state.CompareAndSwap(svcs.ServiceState_Init, svcs.ServiceState_Run) {
+				if err := pgSrv.srv.Serve(pgSrv.lis); !errors.Is(err, net.ErrClosed) {
+					lgr.Errorf("error serving PostgreSQL protocol: %v", err)
+				}
+			}
+		},
+		StopF: func() error {
+			state := pgSrv.state.Swap(svcs.ServiceState_Stopped)
+			if state == svcs.ServiceState_Run {
+				pgSrv.lis.Close()
+				return pgSrv.srv.Close()
+			} else if state == svcs.ServiceState_Init {
+				pgSrv.lis.Close()
+			}
+			return nil
+		},
+	}
+	controller.Register(RunPostgresServer)
+
+	type FlightSQLService struct {
+		state svcs.ServiceState
+		lis   net.Listener
+		srv   *flightsql.Server
+	}
+	var flightSrv FlightSQLService
+	RunFlightSQLServer := &svcs.AnonService{
+		InitF: func(context.Context) error {
+			if serverConfig.FlightSQLPort() == nil {
+				return nil
+			}
+			tlsConfig, err := servercfg.LoadTLSConfig(serverConfig)
+			if err != nil {
+				return err
+			}
+			flightSrv.state.Swap(svcs.ServiceState_Init)
+
+			port := *serverConfig.FlightSQLPort()
+			flightSrv.lis, err = net.Listen("tcp", net.JoinHostPort(serverConfig.Host(), strconv.Itoa(port)))
+			if err != nil {
+				lgr.Errorf("error starting Arrow Flight SQL server on port %d: %v", port, err)
+				return err
+			}
+			flightSrv.srv = flightsql.NewServer(flightsql.ServerArgs{
+				Logger:    logrus.NewEntry(lgr),
+				Engine:    newProtocolEngine(sqlEngine),
+				TLSConfig: tlsConfig,
+			})
+			return nil
+		},
+		RunF: func(context.Context) {
+			if flightSrv.state.CompareAndSwap(svcs.ServiceState_Init, svcs.ServiceState_Run) {
+				if err := flightSrv.srv.Serve(flightSrv.lis); err != nil {
+					lgr.Errorf("error serving Arrow Flight SQL: %v", err)
+				}
+			}
+		},
+		StopF: func() error {
+			state := flightSrv.state.Swap(svcs.ServiceState_Stopped)
+			if state == svcs.ServiceState_Run {
+				return flightSrv.srv.Close()
+			} else if state == svcs.ServiceState_Init {
+				flightSrv.lis.Close()
+			}
+			return nil
+		},
+	}
+	controller.Register(RunFlightSQLServer)
+
+	type HTTPAPIService struct {
+		state svcs.ServiceState
+		lis   net.Listener
+		srv   *http.Server
+	}
+	var httpSrv HTTPAPIService
+	RunHTTPAPIServer := &svcs.AnonService{
+		InitF: func(context.Context) error {
+			cfg := serverConfig.HTTPAPIConfig()
+			if cfg == nil {
+				return nil
+			}
+			httpSrv.state.Swap(svcs.ServiceState_Init)
+
+			host := cfg.Host
+			if host == "" {
+				host = serverConfig.Host()
+			}
+			addr := net.JoinHostPort(host, strconv.Itoa(cfg.Port))
+			var err error
+			httpSrv.lis, err = net.Listen("tcp", addr)
+			if err != nil {
+				lgr.Errorf("error starting HTTP API server on %s: %v", addr, err)
+				return err
+			}
+			mux := http.NewServeMux()
+			mux.Handle(httpapi.Path, httpapi.NewHandler(httpapi.HandlerArgs{
+				Logger:            logrus.NewEntry(lgr),
+				Engine:            newProtocolEngine(sqlEngine),
+				RequestsPerSecond: cfg.RequestsPerSecond,
+				Burst:             cfg.Burst,
+			}))
+			httpSrv.srv = &http.Server{
+				Addr:    addr,
+				Handler: mux,
+			}
+			return nil
+		},
+		RunF: func(context.Context) {
+			if httpSrv.state.CompareAndSwap(svcs.ServiceState_Init, svcs.ServiceState_Run) {
+				cfg := serverConfig.HTTPAPIConfig()
+				var err error
+				if cfg.TLSCert != "" {
+					err = httpSrv.srv.ServeTLS(httpSrv.lis, cfg.TLSCert, cfg.TLSKey)
+				} else {
+					err = httpSrv.srv.Serve(httpSrv.lis)
+				}
+				if !errors.Is(err, http.ErrServerClosed) {
+					lgr.Errorf("error serving HTTP API: %v", err)
+				}
+			}
+		},
+		StopF: func() error {
+			state := httpSrv.state.Swap(svcs.ServiceState_Stopped)
+			if state == svcs.ServiceState_Run {
+				return httpSrv.srv.Close()
+			} else if state == svcs.ServiceState_Init {
+				httpSrv.lis.Close()
+			}
+			return nil
+		},
+	}
+	controller.Register(RunHTTPAPIServer)
+
+	cdcCtx, cdcStop := context.WithCancel(context.Background())
+	RunChangeDataCapture := &svcs.AnonService{
+		RunF: func(context.Context) {
+			cfg := serverConfig.ChangeDataCaptureConfig()
+			if cfg == nil {
+				return
+			}
+			var wg sync.WaitGroup
+			for _, w := range cfg.Webhooks {
+				args := cdc.WebhookSinkArgs{
+					Logger:    logrus.NewEntry(lgr),
+					Database:  w.Database,
+					DoltDB:    databaseDoltDB(sqlEngine, w.Database),
+					Cursor:    w.Cursor,
+					URL:       w.URL,
+					BatchSize: w.BatchSize,
+				}
+				if w.PollIntervalMillis != nil {
+					args.PollInterval = time.Duration(*w.PollIntervalMillis) * time.Millisecond
+				}
+				if w.TimeoutMillis != nil {
+					args.Timeout = time.Duration(*w.TimeoutMillis) * time.Millisecond
+				}
+				sink := cdc.NewWebhookSink(args)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					sink.Run(cdcCtx)
+				}()
+			}
+			for _, k := range cfg.Kafka {
+				producerArgs := kafka.ProducerArgs{Brokers: k.Brokers}
+				if k.TimeoutMillis != nil {
+					producerArgs.Timeout = time.Duration(*k.TimeoutMillis) * time.Millisecond
+				}
+				producer, err := kafka.NewProducer(producerArgs)
+				if err != nil {
+					lgr.WithError(err).Errorf("error configuring kafka producer for cursor %s in database %s", k.Cursor, k.Database)
+					continue
+				}
+				args := cdc.KafkaSinkArgs{
+					Logger:      logrus.NewEntry(lgr),
+					Database:    k.Database,
+					DoltDB:      databaseDoltDB(sqlEngine, k.Database),
+					Cursor:      k.Cursor,
+					Producer:    producer,
+					TopicPrefix: k.TopicPrefix,
+					BatchSize:   k.BatchSize,
+				}
+				if k.PollIntervalMillis != nil {
+					args.PollInterval = time.Duration(*k.PollIntervalMillis) * time.Millisecond
+				}
+				sink := cdc.NewKafkaSink(args)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer producer.Close()
+					sink.Run(cdcCtx)
+				}()
+			}
+			wg.Wait()
+		},
+		StopF: func() error {
+			cdcStop()
+			return nil
+		},
+	}
+	controller.Register(RunChangeDataCapture)
+
 	RunSQLServer := &svcs.AnonService{
 		RunF: func(context.Context) {
 			sqlserver.SetRunningServer(mySQLServer)
@@ -893,7 +1213,57 @@ func portInUse(hostPort string) bool {
 	return false
 }
 
-func newSessionBuilder(se *engine.SqlEngine, config servercfg.ServerConfig) server.SessionBuilder {
+// journalStatsByDatabase returns the chunk journal stats of each database in |se| which has a chunk journal.
+func journalStatsByDatabase(se *engine.SqlEngine) map[string]nbs.JournalStats {
+	provider, ok := se.GetUnderlyingEngine().Analyzer.Catalog.DbProvider.(*sqle.DoltDatabaseProvider)
+	if !ok {
+		return nil
+	}
+	sqlCtx, err := se.NewDefaultContext(context.Background())
+	if err != nil {
+		return nil
+	}
+
+	stats := make(map[string]nbs.JournalStats)
+	for _, db := range provider.AllDatabases(sqlCtx) {
+		sqlDb, ok := db.(dsess.SqlDatabase)
+		if !ok || sqlDb.DbData().Ddb == nil {
+			continue
+		}
+		if s, ok := sqlDb.DbData().Ddb.JournalStats(); ok {
+			stats[sqlDb.Name()] = s
+		}
+	}
+	return stats
+}
+
+// databaseDoltDB returns a function which looks up the DoltDB of the database |name| in |se|, so that databases
+// which are created, or dropped and recreated, while the server runs are found.
+func databaseDoltDB(se *engine.SqlEngine, name string) func(ctx context.Context) (*doltdb.DoltDB, error) {
+	return func(ctx context.Context) (*doltdb.DoltDB, error) {
+		provider, ok := se.GetUnderlyingEngine().Analyzer.Catalog.DbProvider.(*sqle.DoltDatabaseProvider)
+		if !ok {
+			return nil, fmt.Errorf("unexpected database provider: %T", se.GetUnderlyingEngine().Analyzer.Catalog.DbProvider)
+		}
+		sqlCtx, err := se.NewLocalContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		db, err := provider.Database(sqlCtx, name)
+		if err != nil {
+			return nil, err
+		}
+		sqlDb, ok := db.(dsess.SqlDatabase)
+		if !ok || sqlDb.DbData().Ddb == nil {
+			return nil, fmt.Errorf("database %s is not a Dolt database", name)
+		}
+		return sqlDb.DbData().Ddb, nil
+	}
+}
+
+// newSessionBuilder returns a server.SessionBuilder which creates Dolt sessions for new connections, and adds them to
+// |sessions|.
+func newSessionBuilder(se *engine.SqlEngine, config servercfg.ServerConfig, sessions *connSessions) server.SessionBuilder {
 	userToSessionVars := make(map[string]map[string]string)
 	userVars := config.UserVars()
 	for _, curr := range userVars {
@@ -901,6 +1271,12 @@ func newSessionBuilder(se *engine.SqlEngine, config servercfg.ServerConfig) serv
 	}
 
 	return func(ctx context.Context, conn *mysql.Conn, addr string) (sql.Session, error) {
+		// users authenticated by plugins which map groups to roles must have their roles brought up to date before
+		// their session can be used
+		if err := se.ApplyMappedRoles(conn.User); err != nil {
+			return nil, err
+		}
+
 		baseSession, err := sql.BaseSessionFromConnection(ctx, conn, addr)
 		if err != nil {
 			return nil, err
@@ -926,6 +1302,7 @@ func newSessionBuilder(se *engine.SqlEngine, config servercfg.ServerConfig) serv
 			}
 		}
 
+		sessions.add(conn.ConnectionID, dsess)
 		return dsess, nil
 	}
 }
@@ -15,6 +15,7 @@
 package sqlserver
 
 import (
+	gosql "database/sql"
 	"net/http"
 	"os"
 	"strings"
@@ -187,7 +188,7 @@ func TestServerGoodParams(t *testing.T) {
 		t.Run(servercfg.ConfigInfo(test), func(t *testing.T) {
 			sc := svcs.NewController()
 			go func(config servercfg.ServerConfig, sc *svcs.Controller) {
-				_, _ = Serve(context.Background(), "0.0.0", config, sc, env)
+				_, _ = Serve(context.Background(), "0.0.0", config, nil, sc, env)
 			}(test, sc)
 			err := sc.WaitForStart()
 			require.NoError(t, err)
@@ -214,7 +215,7 @@ func TestServerSelect(t *testing.T) {
 	sc := svcs.NewController()
 	defer sc.Stop()
 	go func() {
-		_, _ = Serve(context.Background(), "0.0.0", serverConfig, sc, env)
+		_, _ = Serve(context.Background(), "0.0.0", serverConfig, nil, sc, env)
 	}()
 	err = sc.WaitForStart()
 	require.NoError(t, err)
@@ -258,6 +259,59 @@ func TestServerSelect(t *testing.T) {
 	}
 }
 
+func TestServerPreparedStatementsAcrossConnections(t *testing.T) {
+	env, err := sqle.CreateEnvWithSeedData()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, env.DoltDB.Close())
+	}()
+
+	serverConfig := DefaultCommandLineServerConfig().withLogLevel(servercfg.LogLevel_Fatal).WithPort(15301)
+
+	sc := svcs.NewController()
+	defer sc.Stop()
+	go func() {
+		_, _ = Serve(context.Background(), "0.0.0", serverConfig, nil, sc, env)
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	const dbName = "dolt"
+	conn, err := dbr.Open("mysql", servercfg.ConnectionString(serverConfig, dbName), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx := context.Background()
+	const query = "select * from people where age = ?"
+	columnsOf := func(c interface {
+		QueryContext(context.Context, string, ...interface{}) (*gosql.Rows, error)
+	}) []string {
+		// go-sql-driver prepares statements with arguments on the server
+		rows, err := c.QueryContext(ctx, query, 32)
+		require.NoError(t, err)
+		defer rows.Close()
+		cols, err := rows.Columns()
+		require.NoError(t, err)
+		require.True(t, rows.Next())
+		return cols
+	}
+
+	conn1, err := conn.DB.Conn(ctx)
+	require.NoError(t, err)
+	defer conn1.Close()
+	conn2, err := conn.DB.Conn(ctx)
+	require.NoError(t, err)
+	defer conn2.Close()
+
+	cols := columnsOf(conn1)
+	assert.Equal(t, cols, columnsOf(conn2))
+
+	// statements prepared after a schema change see the new schema
+	_, err = conn1.ExecContext(ctx, "alter table people add column nickname varchar(20)")
+	require.NoError(t, err)
+	assert.Equal(t, append(cols, "nickname"), columnsOf(conn2))
+}
+
 // If a port is already in use, throw error "Port XXXX already in use."
 func TestServerFailsIfPortInUse(t *testing.T) {
 	controller := svcs.NewController()
@@ -313,7 +367,7 @@ func TestServerSetDefaultBranch(t *testing.T) {
 	sc := svcs.NewController()
 	defer sc.Stop()
 	go func() {
-		_, _ = Serve(context.Background(), "0.0.0", serverConfig, sc, dEnv)
+		_, _ = Serve(context.Background(), "0.0.0", serverConfig, nil, sc, dEnv)
 	}()
 	err = sc.WaitForStart()
 	require.NoError(t, err)
@@ -477,7 +531,7 @@ func TestReadReplica(t *testing.T) {
 
 	os.Chdir(multiSetup.DbPaths[readReplicaDbName])
 	go func() {
-		err, _ = Serve(context.Background(), "0.0.0", serverConfig, sc, multiSetup.GetEnv(readReplicaDbName))
+		err, _ = Serve(context.Background(), "0.0.0", serverConfig, nil, sc, multiSetup.GetEnv(readReplicaDbName))
 		require.NoError(t, err)
 	}()
 	require.NoError(t, sc.WaitForStart())
@@ -49,6 +49,9 @@ const (
 	socketFlag                  = "socket"
 	remotesapiPortFlag          = "remotesapi-port"
 	remotesapiReadOnlyFlag      = "remotesapi-readonly"
+	xPortFlag                   = "x-port"
+	postgresPortFlag            = "postgres-port"
+	flightSQLPortFlag           = "flight-sql-port"
 	goldenMysqlConn             = "golden"
 	eventSchedulerStatus        = "event-scheduler"
 )
@@ -108,17 +111,31 @@ SUPPORTED CONFIG FILE FIELDS:
 
 {{.EmphasisLeft}}listener.write_timeout_millis{{.EmphasisRight}}: The number of milliseconds that the server will wait for a write operation
 
+{{.EmphasisLeft}}listener.x_port{{.EmphasisRight}}: A port to serve the MySQL X Protocol on, conventionally 33060. If set, clients using the X DevAPI, such as MySQL Shell, can connect to run SQL statements and to create, read, update and delete rows of tables. Document collections, TLS and compression are not supported over the X Protocol.
+
+{{.EmphasisLeft}}listener.postgres_port{{.EmphasisRight}}: A port to serve the PostgreSQL wire protocol on, conventionally 5432. If set, tools which only have PostgreSQL connectors can connect with the user names and passwords of this server, which are sent unencrypted. Queries are translated to MySQL's syntax: double quoted identifiers, PostgreSQL string literals and $1 parameters are supported, and statements which set or show PostgreSQL's run time parameters are accepted. Other PostgreSQL syntax, such as :: casts, and the pg_catalog tables are not supported.
+
+{{.EmphasisLeft}}listener.flight_sql_port{{.EmphasisRight}}: A port to serve Apache Arrow Flight SQL on, so that analytics clients such as pandas, DuckDB and Spark can fetch large results in Arrow's columnar format. Clients authenticate with the user names and passwords of this server over basic authentication, and are given bearer tokens for later calls. A {{.EmphasisLeft}}database{{.EmphasisRight}} header chooses the database statements run in, which may name a branch as {{.EmphasisLeft}}db/branch{{.EmphasisRight}}. Only read only statements can be run, including prepared statements without parameters. If {{.EmphasisLeft}}listener.tls_key{{.EmphasisRight}} and {{.EmphasisLeft}}listener.tls_cert{{.EmphasisRight}} are set, Flight SQL is served over TLS.
+
 {{.EmphasisLeft}}remotesapi.port{{.EmphasisRight}}: A port to listen for remote API operations on. If set to a positive integer, this server will accept connections from clients to clone, pull, etc. databases being served.
 
 {{.EmphasisLeft}}remotesapi.read_only{{.EmphasisRight}}: Boolean flag which disables the ability to perform pushes against the server.
 
+{{.EmphasisLeft}}http_api{{.EmphasisRight}}: Settings for an HTTP endpoint which runs read only queries, for integrations which don't have a MySQL driver. Requests to {{.EmphasisLeft}}/api/sql{{.EmphasisRight}} authenticate as a user of this server with HTTP basic authentication, and give the SELECT, SHOW, DESCRIBE or EXPLAIN statement to run in the {{.EmphasisLeft}}query{{.EmphasisRight}} parameter. The {{.EmphasisLeft}}database{{.EmphasisRight}} and {{.EmphasisLeft}}ref{{.EmphasisRight}} parameters choose the database and the branch, tag or commit to query, and the {{.EmphasisLeft}}format{{.EmphasisRight}} parameter streams the rows as {{.EmphasisLeft}}json{{.EmphasisRight}} or {{.EmphasisLeft}}csv{{.EmphasisRight}}. The endpoint is served on {{.EmphasisLeft}}http_api.port{{.EmphasisRight}} of {{.EmphasisLeft}}http_api.host{{.EmphasisRight}}, which defaults to {{.EmphasisLeft}}listener.host{{.EmphasisRight}}. {{.EmphasisLeft}}http_api.requests_per_second{{.EmphasisRight}} and {{.EmphasisLeft}}http_api.burst{{.EmphasisRight}} limit how many requests each client address and each user can make, counting failed logins against the address, and {{.EmphasisLeft}}http_api.tls_key{{.EmphasisRight}} and {{.EmphasisLeft}}http_api.tls_cert{{.EmphasisRight}} serve it over HTTPS.
+
+{{.EmphasisLeft}}change_data_capture.webhooks{{.EmphasisRight}}: A list of webhooks which the row level changes committed to a branch are POSTed to as JSON, read with the change cursor {{.EmphasisLeft}}cursor{{.EmphasisRight}} of the database {{.EmphasisLeft}}database{{.EmphasisRight}}. Change cursors are created with the {{.EmphasisLeft}}dolt_change_cursor(){{.EmphasisRight}} stored procedure. Changes are sent to {{.EmphasisLeft}}url{{.EmphasisRight}} in batches of at most {{.EmphasisLeft}}batch_size{{.EmphasisRight}} changes, and the cursor is moved past each commit once all of its changes are delivered, so a change may be delivered more than once. The branch is checked for new commits every {{.EmphasisLeft}}poll_interval_millis{{.EmphasisRight}}, and requests time out after {{.EmphasisLeft}}timeout_millis{{.EmphasisRight}}.
+
+{{.EmphasisLeft}}change_data_capture.kafka{{.EmphasisRight}}: A list of Kafka source connectors, like {{.EmphasisLeft}}dolt cdc kafka{{.EmphasisRight}}, which produce the row level changes read with the change cursor {{.EmphasisLeft}}cursor{{.EmphasisRight}} of the database {{.EmphasisLeft}}database{{.EmphasisRight}} to the Kafka cluster at {{.EmphasisLeft}}brokers{{.EmphasisRight}}, as Debezium change events in JSON in a topic per table named {{.EmphasisLeft}}<topic_prefix>.<database>.<table>{{.EmphasisRight}}. The cursor is moved past each commit once all of its changes are acknowledged. {{.EmphasisLeft}}batch_size{{.EmphasisRight}}, {{.EmphasisLeft}}poll_interval_millis{{.EmphasisRight}} and {{.EmphasisLeft}}timeout_millis{{.EmphasisRight}} work as they do for webhooks.
+
 {{.EmphasisLeft}}system_variables{{.EmphasisRight}}: A map of system variable name to desired value for all system variable values to override.
 
 {{.EmphasisLeft}}user_session_vars{{.EmphasisRight}}: A map of user name to a map of session variables to set on connection for each session.
 
 {{.EmphasisLeft}}cluster{{.EmphasisRight}}: Settings related to running this server in a replicated cluster. For information on setting these values, see https://docs.dolthub.com/sql-reference/server/replication
 
-If a config file is not provided many of these settings may be configured on the command line.`,
+If a config file is not provided many of these settings may be configured on the command line.
+
+Some settings can be reloaded from the config file while the server is running, without breaking its connections, by sending the server process {{.EmphasisLeft}}SIGHUP{{.EmphasisRight}} or running {{.EmphasisLeft}}CALL DOLT_RELOAD_CONFIG(){{.EmphasisRight}}: {{.EmphasisLeft}}log_level{{.EmphasisRight}}, {{.EmphasisLeft}}listener.max_connections{{.EmphasisRight}}, {{.EmphasisLeft}}system_variables{{.EmphasisRight}}, which include the replication settings, and the users and grants in {{.EmphasisLeft}}privilege_file{{.EmphasisRight}}. Every other setting requires a restart.`,
 	Synopsis: []string{
 		"--config {{.LessThan}}file{{.GreaterThan}}",
 		"[-H {{.LessThan}}host{{.GreaterThan}}] [-P {{.LessThan}}port{{.GreaterThan}}] [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [-t {{.LessThan}}timeout{{.GreaterThan}}] [-l {{.LessThan}}loglevel{{.GreaterThan}}] [--data-dir {{.LessThan}}directory{{.GreaterThan}}] [-r]",
@@ -173,6 +190,9 @@ func (cmd SqlServerCmd) ArgParserWithName(name string) *argparser.ArgParser {
 	ap.SupportsOptionalString(socketFlag, "", "socket file", "Path for the unix socket file. Defaults to '/tmp/mysql.sock'.")
 	ap.SupportsUint(remotesapiPortFlag, "", "remotesapi port", "Sets the port for a server which can expose the databases in this sql-server over remotesapi, so that clients can clone or pull from this server.")
 	ap.SupportsFlag(remotesapiReadOnlyFlag, "", "Disable writes to the sql-server via the push operations. SQL writes are unaffected by this setting.")
+	ap.SupportsUint(xPortFlag, "", "x protocol port", "Sets the port for serving the MySQL X Protocol, so that clients using the X DevAPI, such as MySQL Shell, can connect to this server.")
+	ap.SupportsUint(postgresPortFlag, "", "postgres port", "Sets the port for serving the PostgreSQL protocol, so that tools which only have PostgreSQL connectors can connect to this server.")
+	ap.SupportsUint(flightSQLPortFlag, "", "flight sql port", "Sets the port for serving Arrow Flight SQL, so that analytics clients can fetch results from this server in Arrow's columnar format.")
 	ap.SupportsString(goldenMysqlConn, "", "mysql connection string", "Provides a connection string to a MySQL instance to be used to validate query results")
 	ap.SupportsString(eventSchedulerStatus, "", "status", "Determines whether the Event Scheduler is enabled and running on the server. It has one of the following values: 'ON', 'OFF' or 'DISABLED'.")
 	return ap
@@ -246,7 +266,8 @@ func StartServer(ctx context.Context, versionStr, commandStr string, args []stri
 
 	cli.PrintErrf("Starting server with Config %v\n", servercfg.ConfigInfo(serverConfig))
 
-	startError, closeError := Serve(ctx, versionStr, serverConfig, controller, dEnv)
+	reloader := newConfigReloader(ap, args, dEnv.FS)
+	startError, closeError := Serve(ctx, versionStr, serverConfig, reloader, controller, dEnv)
 	if startError != nil {
 		return startError
 	}
@@ -17,6 +17,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -93,6 +94,7 @@ func (cmd StatusCmd) Docs() *cli.CommandDocumentation {
 func (cmd StatusCmd) ArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
 	ap.SupportsFlag(cli.ShowIgnoredFlag, "", "Show tables that are ignored (according to dolt_ignore)")
+	addResultFormatFlag(ap, "r")
 	return ap
 }
 
@@ -109,6 +111,10 @@ func (cmd StatusCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	showIgnoredTables := apr.Contains(cli.ShowIgnoredFlag)
 
+	if _, err := getResultFormat(apr); err != nil {
+		return handleStatusVErr(err)
+	}
+
 	// configure SQL engine
 	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
 	if err != nil {
@@ -124,7 +130,11 @@ func (cmd StatusCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return handleStatusVErr(err)
 	}
 
-	err = printEverything(pd)
+	if isJsonResultFormat(apr) {
+		err = printStatusJson(pd)
+	} else {
+		err = printEverything(pd)
+	}
 	if err != nil {
 		return handleStatusVErr(err)
 	}
@@ -660,6 +670,87 @@ and have %v and %v different commits each, respectively.
 	return nil
 }
 
+// statusJson is the machine-readable representation of dolt status output.
+type statusJson struct {
+	Branch               string            `json:"branch"`
+	Upstream             string            `json:"upstream"`
+	Ahead                int64             `json:"ahead"`
+	Behind               int64             `json:"behind"`
+	MergeActive          bool              `json:"merge_active"`
+	Staged               []statusJsonTable `json:"staged"`
+	Unstaged             []statusJsonTable `json:"unstaged"`
+	Untracked            []statusJsonTable `json:"untracked"`
+	Conflicts            []statusJsonTable `json:"conflicts"`
+	ConstraintViolations []string          `json:"constraint_violations"`
+	Ignored              []string          `json:"ignored,omitempty"`
+}
+
+type statusJsonTable struct {
+	Table  string `json:"table"`
+	Status string `json:"status"`
+}
+
+// printStatusJson prints the status data as a json document. Tables are categorized the same way as they are by
+// printEverything, and each category is sorted by table name.
+func printStatusJson(data *printData) error {
+	res := statusJson{
+		Branch:               data.branchName,
+		MergeActive:          data.mergeActive,
+		Staged:               []statusJsonTable{},
+		Unstaged:             []statusJsonTable{},
+		Untracked:            []statusJsonTable{},
+		Conflicts:            []statusJsonTable{},
+		ConstraintViolations: []string{},
+	}
+	if data.remoteName != "" {
+		res.Upstream = fmt.Sprintf("%s/%s", data.remoteName, data.remoteBranchName)
+		res.Ahead = data.ahead
+		res.Behind = data.behind
+	}
+
+	for tableName, status := range data.stagedTables {
+		if !doltdb.IsReadOnlySystemTable(tableName) {
+			res.Staged = append(res.Staged, statusJsonTable{Table: tableName, Status: status})
+		}
+	}
+	for tableName, status := range data.unstagedTables {
+		hasConflicts := data.dataConflictTables[tableName] || data.schemaConflictTables[tableName]
+		if !hasConflicts && !data.constraintViolationTables[tableName] {
+			res.Unstaged = append(res.Unstaged, statusJsonTable{Table: tableName, Status: status})
+		}
+	}
+	for tableName, status := range data.filteredUntrackedTables {
+		res.Untracked = append(res.Untracked, statusJsonTable{Table: tableName, Status: status})
+	}
+	if data.conflictsPresent {
+		for tableName := range data.schemaConflictTables {
+			res.Conflicts = append(res.Conflicts, statusJsonTable{Table: tableName, Status: strings.TrimSuffix(schemaConflictLabel, ":")})
+		}
+		for tableName := range data.dataConflictTables {
+			res.Conflicts = append(res.Conflicts, statusJsonTable{Table: tableName, Status: strings.TrimSuffix(bothModifiedLabel, ":")})
+		}
+	}
+	for tableName := range data.constraintViolationTables {
+		res.ConstraintViolations = append(res.ConstraintViolations, tableName)
+	}
+	if data.showIgnoredTables {
+		res.Ignored = []string{}
+		for _, tableName := range data.ignoredTables.Ignore {
+			res.Ignored = append(res.Ignored, tableName.String())
+		}
+		sort.Strings(res.Ignored)
+	}
+
+	for _, tables := range [][]statusJsonTable{res.Staged, res.Unstaged, res.Untracked, res.Conflicts} {
+		sort.Slice(tables, func(i, j int) bool {
+			return tables[i].Table < tables[j].Table
+		})
+	}
+	sort.Strings(res.ConstraintViolations)
+
+	return printJsonResult(res)
+}
+
 func handleStatusVErr(err error) int {
 	if err != argparser.ErrHelp {
 		cli.PrintErrln(errhand.VerboseErrorFromError(err).Verbose())
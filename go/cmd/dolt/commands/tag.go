@@ -45,12 +45,14 @@ var tagDocs = cli.CommandDocumentationContent{
 	ShortDesc: `Create, list, delete tags.`,
 	LongDesc: `If there are no non-option arguments, existing tags are listed.
 
-The command's second form creates a new tag named {{.LessThan}}tagname{{.GreaterThan}} which points to the current {{.EmphasisLeft}}HEAD{{.EmphasisRight}}, or {{.LessThan}}ref{{.GreaterThan}} if given. Optionally, a tag message can be passed using the {{.EmphasisLeft}}-m{{.EmphasisRight}} option. 
+The command's second form creates a new tag named {{.LessThan}}tagname{{.GreaterThan}} which points to the current {{.EmphasisLeft}}HEAD{{.EmphasisRight}}, or {{.LessThan}}ref{{.GreaterThan}} if given. Optionally, a tag message can be passed using the {{.EmphasisLeft}}-m{{.EmphasisRight}} option. The tagger and date of the tag default to the configured user and the current time, and can be given with the {{.EmphasisLeft}}--author{{.EmphasisRight}} and {{.EmphasisLeft}}--date{{.EmphasisRight}} options.
+
+Tags can also be created, changed and deleted by writing to the {{.EmphasisLeft}}dolt_tags{{.EmphasisRight}} system table.
 
 With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}tagname{{.GreaterThan}} will be deleted.`,
 	Synopsis: []string{
 		`[-v]`,
-		`[-m {{.LessThan}}message{{.GreaterThan}}] {{.LessThan}}tagname{{.GreaterThan}} [{{.LessThan}}ref{{.GreaterThan}}]`,
+		`[-m {{.LessThan}}message{{.GreaterThan}}] [--author {{.LessThan}}author{{.GreaterThan}}] [--date {{.LessThan}}date{{.GreaterThan}}] {{.LessThan}}tagname{{.GreaterThan}} [{{.LessThan}}ref{{.GreaterThan}}]`,
 		`-d {{.LessThan}}tagname{{.GreaterThan}}`,
 	},
 }
@@ -124,28 +126,16 @@ func createTag(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgPar
 	if len(apr.Args) > 1 {
 		startPoint = apr.Arg(1)
 	}
-	message, _ := apr.GetValue(cli.MessageArg)
-	author, _ := apr.GetValue(cli.AuthorParam)
-
-	var query string
-	var params []interface{}
-	if len(message) == 0 {
-		if len(author) == 0 {
-			query = "call dolt_tag(?, ?)"
-			params = []interface{}{tagName, startPoint}
-		} else {
-			query = "call dolt_tag(?, ?, '--author', ?)"
-			params = []interface{}{tagName, startPoint, author}
-		}
-	} else {
-		if len(author) == 0 {
-			query = "call dolt_tag('-m', ?, ?, ?)"
-			params = []interface{}{message, tagName, startPoint}
-		} else {
-			query = "call dolt_tag('-m', ?, ?, ?, '--author', ?)"
-			params = []interface{}{message, tagName, startPoint, author}
+
+	query := "call dolt_tag(?, ?"
+	params := []interface{}{tagName, startPoint}
+	for _, opt := range []string{cli.MessageArg, cli.AuthorParam, cli.DateParam} {
+		if val, ok := apr.GetValue(opt); ok && len(val) > 0 {
+			query += fmt.Sprintf(", '--%s', ?", opt)
+			params = append(params, val)
 		}
 	}
+	query += ")"
 
 	_, err := InterpolateAndRunQuery(queryist, sqlCtx, query, params...)
 	if err != nil {
@@ -157,6 +147,8 @@ func createTag(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgPar
 func deleteTags(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults) error {
 	if apr.Contains(cli.MessageArg) {
 		return errors.New("delete and tag message options are incompatible")
+	} else if apr.Contains(cli.DateParam) {
+		return errors.New("delete and tag date options are incompatible")
 	} else if apr.Contains(cli.VerboseFlag) {
 		return errors.New("delete and verbose options are incompatible")
 	} else {
@@ -0,0 +1,154 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/commitsign"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var verifyCommitDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Check the signatures of commits",
+	LongDesc: `Checks the signature of each commit given, which defaults to HEAD, and prints whether it's good, bad, unknown or unsigned.
+
+GPG signatures are checked against the keys in the user's gpg keyring. SSH signatures are checked against the keys in the allowed signers file named by the {{.EmphasisLeft}}gpg.ssh.allowedsignersfile{{.EmphasisRight}} config, in the format described in ssh-keygen(1). A signature is unknown when it can't be checked, such as when the key which made it isn't trusted.
+
+The command exits with a non-zero status unless every commit has a good signature.
+`,
+	Synopsis: []string{
+		"[{{.LessThan}}commit{{.GreaterThan}}...]",
+	},
+}
+
+type VerifyCommitCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd VerifyCommitCmd) Name() string {
+	return "verify-commit"
+}
+
+// Description returns a description of the command
+func (cmd VerifyCommitCmd) Description() string {
+	return "Check the signatures of commits."
+}
+
+func (cmd VerifyCommitCmd) RequiresRepo() bool {
+	return true
+}
+
+func (cmd VerifyCommitCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(verifyCommitDocs, ap)
+}
+
+func (cmd VerifyCommitCmd) ArgParser() *argparser.ArgParser {
+	return argparser.NewArgParserWithVariableArgs(cmd.Name())
+}
+
+// EventType returns the type of the event to log
+func (cmd VerifyCommitCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd VerifyCommitCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, verifyCommitDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if !cli.CheckEnvIsValid(dEnv) {
+		return 2
+	}
+
+	specs := apr.Args
+	if len(specs) == 0 {
+		specs = []string{"HEAD"}
+	}
+
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	signCfg := commitsign.NewConfig(dEnv.Config)
+	allGood := true
+	for _, spec := range specs {
+		cm, err := resolveCommitToVerify(ctx, dEnv, spec, headRef)
+		if err != nil {
+			verr := errhand.BuildDError("error: unable to resolve commit '%s'", spec).AddCause(err).Build()
+			return HandleVErrAndExitCode(verr, usage)
+		}
+		h, err := cm.HashOf()
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+
+		ver, err := signCfg.VerifyCommit(ctx, cm)
+		if err != nil {
+			verr := errhand.BuildDError("error: failed to verify commit %s", h.String()).AddCause(err).Build()
+			return HandleVErrAndExitCode(verr, usage)
+		}
+
+		cli.Println(formatVerification(h.String(), ver))
+		if ver.Status != commitsign.StatusGood {
+			allGood = false
+		}
+	}
+
+	if !allGood {
+		return 1
+	}
+	return 0
+}
+
+func resolveCommitToVerify(ctx context.Context, dEnv *env.DoltEnv, spec string, headRef ref.DoltRef) (*doltdb.Commit, error) {
+	cs, err := doltdb.NewCommitSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	optCmt, err := dEnv.DoltDB.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	return cm, nil
+}
+
+// formatVerification returns the line printed for the verification of the commit |h|
+func formatVerification(h string, ver commitsign.Verification) string {
+	line := fmt.Sprintf("commit %s: %s signature", h, ver.Status)
+	if ver.Status == commitsign.StatusUnsigned {
+		line = fmt.Sprintf("commit %s: unsigned", h)
+	}
+	if ver.Signer != "" {
+		line += fmt.Sprintf(" from %s", ver.Signer)
+	}
+	if ver.Detail != "" {
+		line += fmt.Sprintf(" (%s)", ver.Detail)
+	}
+	return line
+}
@@ -43,10 +43,13 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/admin"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/cdccmds"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/cicmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/cnfcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/credcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/cvcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/docscmds"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/importcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/indexcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/schcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/sqlserver"
@@ -102,8 +105,12 @@ var doltSubCommands = []cli.Command{
 	schcmds.Commands,
 	tblcmds.Commands,
 	commands.TagCmd{},
+	commands.NotesCmd{},
 	commands.BlameCmd{},
 	cvcmds.Commands,
+	cdccmds.Commands,
+	importcmds.Commands,
+	importcmds.ReplicateFromMysqlCmd{},
 	commands.SendMetricsCmd{},
 	commands.MigrateCmd{},
 	indexcmds.Commands,
@@ -119,12 +126,19 @@ var doltSubCommands = []cli.Command{
 	dumpZshCommand,
 	docscmds.Commands,
 	stashcmds.StashCommands,
+	cicmds.Commands,
 	&commands.Assist{},
 	commands.ProfileCmd{},
 	commands.QueryDiff{},
 	commands.ReflogCmd{},
 	commands.RebaseCmd{},
+	commands.FormatPatchCmd{},
+	commands.ApplyCmd{},
+	commands.BundleCmd{},
 	commands.ArchiveCmd{},
+	commands.FsckCmd{},
+	commands.VerifyCommitCmd{},
+	commands.PruneHistoryCmd{},
 }
 
 var commandsWithoutCliCtx = []cli.Command{
@@ -136,6 +150,9 @@ var commandsWithoutCliCtx = []cli.Command{
 	credcmds.Commands,
 	schcmds.Commands,
 	cvcmds.Commands,
+	cdccmds.Commands,
+	importcmds.Commands,
+	importcmds.ReplicateFromMysqlCmd{},
 	commands.SendMetricsCmd{},
 	commands.MigrateCmd{},
 	indexcmds.Commands,
@@ -151,6 +168,10 @@ var commandsWithoutCliCtx = []cli.Command{
 	&commands.Assist{},
 	commands.ProfileCmd{},
 	commands.ArchiveCmd{},
+	commands.BundleCmd{},
+	commands.FsckCmd{},
+	commands.VerifyCommitCmd{},
+	commands.PruneHistoryCmd{},
 }
 
 var commandsWithoutGlobalArgSupport = []cli.Command{
@@ -551,7 +572,7 @@ func runMain() int {
 		})
 	}
 
-	apr, remainingArgs, subcommandName, err := parseGlobalArgsAndSubCommandName(globalConfig, args)
+	apr, remainingArgs, subcommandName, err := parseGlobalArgsAndSubCommandName(globalConfig, localConfig, args)
 	if err == argparser.ErrHelp {
 		doltCommand.PrintUsage("dolt")
 		cli.Println(globalSpecialMsg)
@@ -875,7 +896,8 @@ func interceptSendMetrics(ctx context.Context, args []string) (bool, int) {
 }
 
 // parseGlobalArgsAndSubCommandName parses the global arguments, including a profile if given or a default profile if exists. Also returns the subcommand name.
-func parseGlobalArgsAndSubCommandName(globalConfig config.ReadWriteConfig, args []string) (apr *argparser.ArgParseResults, remaining []string, subcommandName string, err error) {
+// Profiles saved in |localConfig|, which is nil outside a repository, take precedence over the profiles in |globalConfig|.
+func parseGlobalArgsAndSubCommandName(globalConfig, localConfig config.ReadableConfig, args []string) (apr *argparser.ArgParseResults, remaining []string, subcommandName string, err error) {
 	apr, remaining, err = globalArgParser.ParseGlobalArgs(args)
 	if err != nil {
 		return nil, nil, "", err
@@ -885,22 +907,20 @@ func parseGlobalArgsAndSubCommandName(globalConfig config.ReadWriteConfig, args
 
 	useDefaultProfile := false
 	profileName, hasProfile := apr.GetValue(commands.ProfileFlag)
-	encodedProfiles, err := globalConfig.GetString(commands.GlobalCfgProfileKey)
-	if err != nil {
-		if err == config.ErrConfigParamNotFound {
-			if hasProfile {
-				return nil, nil, "", fmt.Errorf("no profiles found")
-			} else {
-				return apr, remaining, subcommandName, nil
-			}
-		} else {
-			return nil, nil, "", err
-		}
+	configs := []config.ReadableConfig{globalConfig}
+	if localConfig != nil {
+		configs = []config.ReadableConfig{localConfig, globalConfig}
 	}
-	profiles, err := commands.DecodeProfile(encodedProfiles)
+	profiles, err := commands.LoadProfiles(configs...)
 	if err != nil {
 		return nil, nil, "", err
 	}
+	if profiles == "" {
+		if hasProfile {
+			return nil, nil, "", fmt.Errorf("no profiles found")
+		}
+		return apr, remaining, subcommandName, nil
+	}
 
 	if !hasProfile && supportsGlobalArgs(subcommandName) {
 		defaultProfile := gjson.Get(profiles, commands.DefaultProfileName)
@@ -916,50 +936,100 @@ func parseGlobalArgsAndSubCommandName(globalConfig config.ReadWriteConfig, args
 	}
 
 	if hasProfile || useDefaultProfile {
-		profileArgs, err := getProfile(apr, profileName, profiles)
+		prof, err := commands.ResolveProfile(profiles, profileName)
 		if err != nil {
 			return nil, nil, "", err
 		}
-		args = append(profileArgs, args...)
+		prof = prof.ExpandEnv()
+		args = append(getProfileArgs(apr, prof), args...)
 		apr, remaining, err = globalArgParser.ParseGlobalArgs(args)
 		if err != nil {
 			return nil, nil, "", err
 		}
+		remaining, err = applyProfileCommandArgs(prof, remaining)
+		if err != nil {
+			return nil, nil, "", err
+		}
 	}
 
 	return
 }
 
-// getProfile retrieves the given profile from the provided list of profiles and returns the args (as flags) and values
-// for that profile in a []string. If the profile is not found, an error is returned.
-func getProfile(apr *argparser.ArgParseResults, profileName, profiles string) (result []string, err error) {
-	prof := gjson.Get(profiles, profileName)
-	if prof.Exists() {
-		hasPassword := false
-		password := ""
-		for flag, value := range prof.Map() {
-			if !apr.Contains(flag) {
-				if flag == cli.PasswordFlag {
-					password = value.Str
-				} else if flag == "has-password" {
-					hasPassword = value.Bool()
-				} else if flag == cli.NoTLSFlag {
-					if value.Bool() {
-						result = append(result, "--"+flag)
-						continue
-					}
-				} else {
-					if value.Str != "" {
-						result = append(result, "--"+flag, value.Str)
-					}
-				}
-			}
+// getProfileArgs returns the global args (as flags) and values saved in the given profile, skipping the args which
+// are given in |apr|.
+func getProfileArgs(apr *argparser.ArgParseResults, prof commands.Profile) (result []string) {
+	addArg := func(flag, value string) {
+		if value != "" && !apr.Contains(flag) {
+			result = append(result, "--"+flag, value)
 		}
-		if !apr.Contains(cli.PasswordFlag) && hasPassword {
-			result = append(result, "--"+cli.PasswordFlag, password)
+	}
+	addArg(cli.UserFlag, prof.User)
+	addArg(cli.HostFlag, prof.Host)
+	addArg(cli.PortFlag, prof.Port)
+	addArg(commands.DataDirFlag, prof.DataDir)
+	addArg(commands.CfgDirFlag, prof.DoltCfgDir)
+	addArg(commands.PrivsFilePathFlag, prof.PrivilegeFile)
+	addArg(commands.BranchCtrlPathFlag, prof.BranchControl)
+	addArg(commands.UseDbFlag, prof.UseDB)
+	if prof.NoTLS && !apr.Contains(cli.NoTLSFlag) {
+		result = append(result, "--"+cli.NoTLSFlag)
+	}
+	if prof.HasPassword && !apr.Contains(cli.PasswordFlag) {
+		result = append(result, "--"+cli.PasswordFlag, prof.Password)
+	}
+	return result
+}
+
+// applyProfileCommandArgs inserts the options the given profile saves for the subcommand in |remaining| after the
+// subcommand's name. Options given on the command line take precedence over the profile's.
+func applyProfileCommandArgs(prof commands.Profile, remaining []string) ([]string, error) {
+	profArgs := prof.Args[remaining[0]]
+	if len(profArgs) == 0 {
+		return remaining, nil
+	}
+
+	var ap *argparser.ArgParser
+	for _, cmd := range doltCommand.Subcommands {
+		if cmd.Name() == remaining[0] {
+			ap = cmd.ArgParser()
+			break
 		}
-		return result, nil
-	} else {
-		return nil, fmt.Errorf("profile %s not found", profileName)
 	}
+	if ap == nil {
+		return nil, fmt.Errorf("profile arguments are not supported for command %s", remaining[0])
+	}
+
+	profApr, err := ap.Parse(profArgs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile arguments for %s: %w", remaining[0], err)
+	}
+	if profApr.NArg() > 0 {
+		return nil, fmt.Errorf("profile arguments for %s must be options, found: %s", remaining[0], strings.Join(profApr.Args, " "))
+	}
+	// if the command line doesn't parse, the command reports the error
+	cmdApr, err := ap.Parse(remaining[1:])
+	if err != nil {
+		cmdApr = nil
+	}
+
+	var applied []string
+	for _, opt := range ap.Supported {
+		if !profApr.Contains(opt.Name) || (cmdApr != nil && cmdApr.Contains(opt.Name)) {
+			continue
+		}
+		switch value := profApr.MustGetValue(opt.Name); {
+		case opt.OptType == argparser.OptionalFlag:
+			applied = append(applied, "--"+opt.Name)
+		case value == "":
+			applied = append(applied, "--"+opt.Name, "")
+		default:
+			// values are attached to their options, so lists don't take the command's own arguments as values
+			applied = append(applied, "--"+opt.Name+"="+value)
+		}
+	}
+
+	result := make([]string, 0, len(remaining)+len(applied))
+	result = append(result, remaining[0])
+	result = append(result, applied...)
+	return append(result, remaining[1:]...), nil
 }
@@ -55,6 +55,10 @@ type ChunkStoreServiceClient interface {
 	ListTableFiles(ctx context.Context, in *ListTableFilesRequest, opts ...grpc.CallOption) (*ListTableFilesResponse, error)
 	RefreshTableFileUrl(ctx context.Context, in *RefreshTableFileUrlRequest, opts ...grpc.CallOption) (*RefreshTableFileUrlResponse, error)
 	AddTableFiles(ctx context.Context, in *AddTableFilesRequest, opts ...grpc.CallOption) (*AddTableFilesResponse, error)
+	// Get a list of chunks encoded as deltas against base chunks which the
+	// client already has. Only supported by servers which advertise
+	// `supports_chunk_deltas` in their GetRepoMetadataResponse.
+	GetChunkDeltas(ctx context.Context, in *GetChunkDeltasRequest, opts ...grpc.CallOption) (*GetChunkDeltasResponse, error)
 }
 
 type chunkStoreServiceClient struct {
@@ -186,6 +190,15 @@ func (c *chunkStoreServiceClient) AddTableFiles(ctx context.Context, in *AddTabl
 	return out, nil
 }
 
+func (c *chunkStoreServiceClient) GetChunkDeltas(ctx context.Context, in *GetChunkDeltasRequest, opts ...grpc.CallOption) (*GetChunkDeltasResponse, error) {
+	out := new(GetChunkDeltasResponse)
+	err := c.cc.Invoke(ctx, "/dolt.services.remotesapi.v1alpha1.ChunkStoreService/GetChunkDeltas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChunkStoreServiceServer is the server API for ChunkStoreService service.
 // All implementations must embed UnimplementedChunkStoreServiceServer
 // for forward compatibility
@@ -208,6 +221,10 @@ type ChunkStoreServiceServer interface {
 	ListTableFiles(context.Context, *ListTableFilesRequest) (*ListTableFilesResponse, error)
 	RefreshTableFileUrl(context.Context, *RefreshTableFileUrlRequest) (*RefreshTableFileUrlResponse, error)
 	AddTableFiles(context.Context, *AddTableFilesRequest) (*AddTableFilesResponse, error)
+	// Get a list of chunks encoded as deltas against base chunks which the
+	// client already has. Only supported by servers which advertise
+	// `supports_chunk_deltas` in their GetRepoMetadataResponse.
+	GetChunkDeltas(context.Context, *GetChunkDeltasRequest) (*GetChunkDeltasResponse, error)
 	mustEmbedUnimplementedChunkStoreServiceServer()
 }
 
@@ -248,6 +265,9 @@ func (UnimplementedChunkStoreServiceServer) RefreshTableFileUrl(context.Context,
 func (UnimplementedChunkStoreServiceServer) AddTableFiles(context.Context, *AddTableFilesRequest) (*AddTableFilesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddTableFiles not implemented")
 }
+func (UnimplementedChunkStoreServiceServer) GetChunkDeltas(context.Context, *GetChunkDeltasRequest) (*GetChunkDeltasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChunkDeltas not implemented")
+}
 func (UnimplementedChunkStoreServiceServer) mustEmbedUnimplementedChunkStoreServiceServer() {}
 
 // UnsafeChunkStoreServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -467,6 +487,24 @@ func _ChunkStoreService_AddTableFiles_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChunkStoreService_GetChunkDeltas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChunkDeltasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChunkStoreServiceServer).GetChunkDeltas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dolt.services.remotesapi.v1alpha1.ChunkStoreService/GetChunkDeltas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChunkStoreServiceServer).GetChunkDeltas(ctx, req.(*GetChunkDeltasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChunkStoreService_ServiceDesc is the grpc.ServiceDesc for ChunkStoreService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -514,6 +552,10 @@ var ChunkStoreService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddTableFiles",
 			Handler:    _ChunkStoreService_AddTableFiles_Handler,
 		},
+		{
+			MethodName: "GetChunkDeltas",
+			Handler:    _ChunkStoreService_GetChunkDeltas_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -241,13 +241,38 @@ func CheckAccess(ctx context.Context, flags Permissions) error {
 	controller.Access.RWMutex.RLock()
 	defer controller.Access.RWMutex.RUnlock()
 
-	user := branchAwareSession.GetUser()
-	host := branchAwareSession.GetHost()
-	database := branchAwareSession.GetCurrentDatabase()
 	branch, err := branchAwareSession.GetBranch()
 	if err != nil {
 		return err
 	}
+	return checkBranchAccess(controller, branchAwareSession, branch, flags)
+}
+
+// CheckBranchAccess is like CheckAccess, but checks the permissions of the given context on |branchName| rather than on
+// the branch of the context's current database. It's used by operations which change a branch other than the current
+// one, such as merges added to the merge queue.
+func CheckBranchAccess(ctx context.Context, branchName string, flags Permissions) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, so we allow all operations
+	if branchAwareSession == nil {
+		return nil
+	}
+	controller := branchAwareSession.GetController()
+	// Any context that has a non-nil session should always have a non-nil controller, so this is an error
+	if controller == nil {
+		return ErrMissingController.New()
+	}
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+	return checkBranchAccess(controller, branchAwareSession, branchName, flags)
+}
+
+// checkBranchAccess checks the permissions of |branchAwareSession| on |branch|. The read lock on the controller's access
+// table must be held by the caller.
+func checkBranchAccess(controller *Controller, branchAwareSession Context, branch string, flags Permissions) error {
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	database := branchAwareSession.GetCurrentDatabase()
 	// Get the permissions for the branch, user, and host combination
 	_, perms := controller.Access.Match(database, branch, user, host)
 	// If either the flags match or the user is an admin for this branch, then we allow access
@@ -0,0 +1,267 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cdc reads the row level changes made by the commits on a branch since the position of a change cursor, so
+// that they can be consumed by other systems.
+package cdc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ErrCursorNotAncestor is returned when the commit of a change cursor isn't one of the first parent ancestors of the
+// head of its branch, eg. because the branch was reset.
+var ErrCursorNotAncestor = errors.New("the commit of the change cursor is not an ancestor of the head of its branch")
+
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// Change is a row added, removed or modified by a commit. Rows are keyed by column name.
+type Change struct {
+	Commit     string                 `json:"commit_hash"`
+	CommitDate time.Time              `json:"commit_date"`
+	Table      string                 `json:"table_name"`
+	Type       string                 `json:"diff_type"`
+	Key        map[string]interface{} `json:"primary_key,omitempty"`
+	From       map[string]interface{} `json:"from_row,omitempty"`
+	To         map[string]interface{} `json:"to_row,omitempty"`
+}
+
+// ChangeIter iterates over the row changes made by each commit after a change cursor up to the head of its branch,
+// oldest commit first. The changes of each commit are relative to its first parent, so merged commits are seen as
+// the changes of their merge commit.
+type ChangeIter struct {
+	ddb     *doltdb.DoltDB
+	head    hash.Hash
+	commits []*doltdb.Commit
+
+	commit     string
+	commitDate time.Time
+	deltas     []diff.ThreeWayTableDelta
+	delta      diff.ThreeWayTableDelta
+	rows       *diff.ThreeWayRowDiffIter
+}
+
+// NewChangeIter returns a ChangeIter over the changes made to the branch of |c| since its commit.
+func NewChangeIter(ctx context.Context, ddb *doltdb.DoltDB, c doltdb.ChangeCursor) (*ChangeIter, error) {
+	head, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef(c.Branch))
+	if err != nil {
+		return nil, err
+	}
+	headHash, err := head.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	optCmt, err := ddb.ReadCommit(ctx, c.Commit)
+	if err != nil {
+		return nil, err
+	}
+	from, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	fromHeight, err := from.Height()
+	if err != nil {
+		return nil, err
+	}
+
+	// walk the first parents of the head back to the cursor's commit. Commits are never lower than their parents, so
+	// the walk stops once it passes the height of the cursor's commit.
+	var commits []*doltdb.Commit
+	for cm := head; ; {
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if h == c.Commit {
+			break
+		}
+		height, err := cm.Height()
+		if err != nil {
+			return nil, err
+		}
+		if height <= fromHeight || cm.NumParents() == 0 {
+			return nil, fmt.Errorf("%w: cursor %s is at %s, which isn't in the history of %s", ErrCursorNotAncestor, c.Name, c.Commit.String(), c.Branch)
+		}
+		commits = append(commits, cm)
+		optCmt, err := cm.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		if cm, ok = optCmt.ToCommit(); !ok {
+			return nil, doltdb.ErrGhostCommitEncountered
+		}
+	}
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+
+	return &ChangeIter{ddb: ddb, head: headHash, commits: commits}, nil
+}
+
+// Head returns the commit at the head of the branch when the iterator was created. Once every change has been read,
+// the cursor can be moved to it.
+func (it *ChangeIter) Head() hash.Hash {
+	return it.head
+}
+
+// Next returns the next Change, or io.EOF once the changes of every commit have been returned.
+func (it *ChangeIter) Next(ctx context.Context) (Change, error) {
+	for {
+		if it.rows != nil {
+			d, err := it.rows.Next(ctx)
+			if err == nil {
+				return it.change(d), nil
+			} else if err != io.EOF {
+				return Change{}, err
+			}
+			it.rows = nil
+		}
+
+		if len(it.deltas) > 0 {
+			it.delta, it.deltas = it.deltas[0], it.deltas[1:]
+			rows, err := it.delta.RowDiffs(ctx)
+			if errors.Is(err, diff.ErrThreeWayPrimaryKeyChange) {
+				return Change{}, fmt.Errorf("the primary key of table %s was changed in commit %s, so its row changes can't be read", it.delta.Name.Name, it.commit)
+			} else if err != nil {
+				return Change{}, err
+			}
+			it.rows = rows
+			continue
+		}
+
+		if len(it.commits) == 0 {
+			return Change{}, io.EOF
+		}
+		if err := it.nextCommit(ctx); err != nil {
+			return Change{}, err
+		}
+	}
+}
+
+// nextCommit starts reading the table changes of the next commit.
+func (it *ChangeIter) nextCommit(ctx context.Context) error {
+	cm := it.commits[0]
+	it.commits = it.commits[1:]
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return err
+	}
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	optCmt, err := cm.GetParent(ctx, 0)
+	if err != nil {
+		return err
+	}
+	parent, ok := optCmt.ToCommit()
+	if !ok {
+		return doltdb.ErrGhostCommitEncountered
+	}
+	parentRoot, err := parent.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+
+	// with the parent as both the base and the right side of a three-way diff, only the left side has changes
+	deltas, err := diff.GetThreeWayTableDeltas(ctx, parentRoot, root, parentRoot)
+	if err != nil {
+		return err
+	}
+	it.deltas = it.deltas[:0]
+	for _, td := range deltas {
+		if !doltdb.HasDoltPrefix(td.Name.Name) {
+			it.deltas = append(it.deltas, td)
+		}
+	}
+	it.commit = h.String()
+	it.commitDate = meta.Time()
+	return nil
+}
+
+func (it *ChangeIter) change(d diff.ThreeWayRowDiff) Change {
+	c := Change{
+		Commit:     it.commit,
+		CommitDate: it.commitDate,
+		Table:      it.delta.Name.Name,
+		From:       rowMap(it.delta.BaseSch, d.Base),
+		To:         rowMap(it.delta.LeftSch, d.Left),
+	}
+	switch d.LeftChange {
+	case diff.RowAdded:
+		c.Type = ChangeAdded
+	case diff.RowRemoved:
+		c.Type = ChangeRemoved
+	default:
+		c.Type = ChangeModified
+	}
+	if d.Key != nil {
+		sch := it.delta.LeftSch
+		if sch == nil {
+			sch = it.delta.BaseSch
+		}
+		c.Key = make(map[string]interface{}, len(d.Key))
+		for i, col := range sch.GetPKCols().GetColumns() {
+			c.Key[col.Name] = jsonValue(d.Key[i])
+		}
+	}
+	return c
+}
+
+// rowMap returns |row| keyed by the names of the columns of |sch|, or nil if the row doesn't exist. Virtual columns
+// aren't stored, so they're left out.
+func rowMap(sch schema.Schema, row sql.Row) map[string]interface{} {
+	if row == nil {
+		return nil
+	}
+	m := make(map[string]interface{}, len(row))
+	for i, col := range sch.GetAllCols().GetColumns() {
+		if !col.Virtual {
+			m[col.Name] = jsonValue(row[i])
+		}
+	}
+	return m
+}
+
+// jsonValue returns |v| as a value which is encoded to JSON as its SQL value.
+func jsonValue(v interface{}) interface{} {
+	if w, ok := v.(sql.JSONWrapper); ok {
+		if i, err := w.ToInterface(); err == nil {
+			return i
+		}
+	}
+	return v
+}
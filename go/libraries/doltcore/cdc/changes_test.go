@@ -0,0 +1,124 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// commitSql runs |query| against the head of main, and commits the result to main.
+func commitSql(t *testing.T, dEnv *env.DoltEnv, query string) hash.Hash {
+	ctx := context.Background()
+	head, err := dEnv.DoltDB.ResolveCommitRef(ctx, ref.NewBranchRef("main"))
+	require.NoError(t, err)
+	root, err := head.GetRootValue(ctx)
+	require.NoError(t, err)
+	// ExecuteSql runs against the working set, so reset it to |root| first
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, root))
+	root, err = sqle.ExecuteSql(dEnv, root, query)
+	require.NoError(t, err)
+
+	_, valHash, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", query)
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Commit(ctx, valHash, ref.NewBranchRef("main"), meta)
+	require.NoError(t, err)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	return h
+}
+
+func headHash(t *testing.T, ddb *doltdb.DoltDB, branch string) hash.Hash {
+	head, err := ddb.ResolveCommitRef(context.Background(), ref.NewBranchRef(branch))
+	require.NoError(t, err)
+	h, err := head.HashOf()
+	require.NoError(t, err)
+	return h
+}
+
+func readChanges(t *testing.T, it *cdc.ChangeIter) []cdc.Change {
+	var changes []cdc.Change
+	for {
+		c, err := it.Next(context.Background())
+		if err == io.EOF {
+			return changes
+		}
+		require.NoError(t, err)
+		c.CommitDate = time.Time{}
+		changes = append(changes, c)
+	}
+}
+
+func TestChangeIter(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	cursor := doltdb.ChangeCursor{Name: "c", Branch: "main", Commit: headHash(t, dEnv.DoltDB, "main")}
+	first := commitSql(t, dEnv, `
+		create table t (pk int primary key, c1 varchar(10));
+		insert into t values (1, 'one'), (2, 'two');`)
+	second := commitSql(t, dEnv, `
+		update t set c1 = 'uno' where pk = 1;
+		delete from t where pk = 2;
+		insert into t values (3, 'three');
+		create table k (c1 int);
+		insert into k values (7);`)
+
+	it, err := cdc.NewChangeIter(ctx, dEnv.DoltDB, cursor)
+	require.NoError(t, err)
+	assert.Equal(t, second, it.Head())
+	expected := []cdc.Change{
+		{Commit: first.String(), Table: "t", Type: cdc.ChangeAdded, Key: map[string]interface{}{"pk": int32(1)}, To: map[string]interface{}{"pk": int32(1), "c1": "one"}},
+		{Commit: first.String(), Table: "t", Type: cdc.ChangeAdded, Key: map[string]interface{}{"pk": int32(2)}, To: map[string]interface{}{"pk": int32(2), "c1": "two"}},
+		{Commit: second.String(), Table: "k", Type: cdc.ChangeAdded, To: map[string]interface{}{"c1": int32(7)}},
+		{Commit: second.String(), Table: "t", Type: cdc.ChangeModified, Key: map[string]interface{}{"pk": int32(1)}, From: map[string]interface{}{"pk": int32(1), "c1": "one"}, To: map[string]interface{}{"pk": int32(1), "c1": "uno"}},
+		{Commit: second.String(), Table: "t", Type: cdc.ChangeRemoved, Key: map[string]interface{}{"pk": int32(2)}, From: map[string]interface{}{"pk": int32(2), "c1": "two"}},
+		{Commit: second.String(), Table: "t", Type: cdc.ChangeAdded, Key: map[string]interface{}{"pk": int32(3)}, To: map[string]interface{}{"pk": int32(3), "c1": "three"}},
+	}
+	assert.Equal(t, expected, readChanges(t, it))
+
+	// a cursor at the head has nothing to read
+	cursor.Commit = second
+	it, err = cdc.NewChangeIter(ctx, dEnv.DoltDB, cursor)
+	require.NoError(t, err)
+	assert.Empty(t, readChanges(t, it))
+
+	// a cursor which is ahead of its branch can't read it
+	headCm, err := dEnv.DoltDB.ResolveCommitRef(ctx, ref.NewBranchRef("main"))
+	require.NoError(t, err)
+	optCmt, err := headCm.GetParent(ctx, 0)
+	require.NoError(t, err)
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("other"), optCmt.Commit, nil))
+	cursor.Branch = "other"
+	_, err = cdc.NewChangeIter(ctx, dEnv.DoltDB, cursor)
+	assert.ErrorIs(t, err, cdc.ErrCursorNotAncestor)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrCursorExists = errors.New("change cursor already exists")
+var ErrCursorNotFound = errors.New("change cursor not found")
+
+// CreateCursor creates the change cursor |name| for |branch|, at |commit|, which must be one of the first parent
+// ancestors of the head of the branch, or the head itself.
+func CreateCursor(ctx context.Context, ddb *doltdb.DoltDB, name, branch string, commit hash.Hash, meta *datas.CommitMeta) error {
+	if _, ok, err := ddb.GetChangeCursor(ctx, name); err != nil {
+		return err
+	} else if ok {
+		return fmt.Errorf("%w: %s", ErrCursorExists, name)
+	}
+	c := doltdb.ChangeCursor{Name: name, Branch: branch, Commit: commit, UpdatedAt: time.Now()}
+	if _, err := NewChangeIter(ctx, ddb, c); err != nil {
+		return err
+	}
+	return ddb.SetChangeCursor(ctx, c, meta)
+}
+
+// MoveCursor moves the change cursor |name| to |commit|, which must be one of the first parent ancestors of the head
+// of its branch, or the head itself. Cursors are usually moved forward once changes have been read, but can be moved
+// back to read changes again.
+func MoveCursor(ctx context.Context, ddb *doltdb.DoltDB, name string, commit hash.Hash, meta *datas.CommitMeta) error {
+	c, ok, err := ddb.GetChangeCursor(ctx, name)
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("%w: %s", ErrCursorNotFound, name)
+	}
+	c.Commit = commit
+	c.UpdatedAt = time.Now()
+	if _, err := NewChangeIter(ctx, ddb, c); err != nil {
+		return err
+	}
+	return ddb.SetChangeCursor(ctx, c, meta)
+}
+
+// DeleteCursor deletes the change cursor |name|.
+func DeleteCursor(ctx context.Context, ddb *doltdb.DoltDB, name string, meta *datas.CommitMeta) error {
+	if _, ok, err := ddb.GetChangeCursor(ctx, name); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("%w: %s", ErrCursorNotFound, name)
+	}
+	return ddb.RemoveChangeCursor(ctx, name, meta)
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc/kafka"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+const (
+	DefaultKafkaTopicPrefix  = "dolt"
+	DefaultKafkaBatchSize    = 1000
+	DefaultKafkaPollInterval = time.Second
+)
+
+// The operations of change events, as Debezium names them.
+const (
+	debeziumCreate = "c"
+	debeziumUpdate = "u"
+	debeziumDelete = "d"
+)
+
+// KafkaCommitHeader is the header of each change event holding the hash of the commit which made the change.
+const KafkaCommitHeader = "dolt.commit"
+
+// ChangeEvent is the value of each message a KafkaSink produces, in the format of the change events of Debezium's
+// connectors when the JSON converter doesn't include schemas. The key of each message is the primary key of the
+// changed row, or null for keyless tables.
+type ChangeEvent struct {
+	Before map[string]interface{} `json:"before"`
+	After  map[string]interface{} `json:"after"`
+	Source ChangeEventSource      `json:"source"`
+	Op     string                 `json:"op"`
+	TsMs   int64                  `json:"ts_ms"`
+}
+
+// ChangeEventSource describes where the change of a ChangeEvent was made. Commit is the offset of the change, since
+// the changes of a branch are produced commit by commit.
+type ChangeEventSource struct {
+	Connector string `json:"connector"`
+	Name      string `json:"name"`
+	TsMs      int64  `json:"ts_ms"`
+	DB        string `json:"db"`
+	Table     string `json:"table"`
+	Branch    string `json:"branch"`
+	Commit    string `json:"commit"`
+}
+
+// KafkaProducer produces messages to Kafka. It's implemented by *kafka.Producer.
+type KafkaProducer interface {
+	Produce(ctx context.Context, msgs []kafka.Message) error
+}
+
+var _ KafkaProducer = (*kafka.Producer)(nil)
+
+// KafkaSinkArgs configures a KafkaSink.
+type KafkaSinkArgs struct {
+	Logger *logrus.Entry
+	// Database is the name of the database the cursor is in
+	Database string
+	// DoltDB returns the DoltDB of the database
+	DoltDB func(ctx context.Context) (*doltdb.DoltDB, error)
+	// Cursor is the name of the change cursor to read changes with
+	Cursor string
+	// Producer produces the change events
+	Producer KafkaProducer
+	// TopicPrefix is the first part of the name of the topic of each table, <prefix>.<database>.<table>
+	TopicPrefix string
+	// BatchSize is the most change events produced at a time
+	BatchSize int
+	// PollInterval is how often the branch of the cursor is checked for new commits
+	PollInterval time.Duration
+}
+
+// KafkaSink produces the changes read with a change cursor to a Kafka topic per table as they're committed, like a
+// Debezium source connector. Changes are delivered at least once, as described by deliverChanges, and the changes of
+// each row are produced to the same partition in the order they were committed.
+type KafkaSink struct {
+	args KafkaSinkArgs
+}
+
+// NewKafkaSink returns a KafkaSink for |args|.
+func NewKafkaSink(args KafkaSinkArgs) *KafkaSink {
+	if args.TopicPrefix == "" {
+		args.TopicPrefix = DefaultKafkaTopicPrefix
+	}
+	if args.BatchSize <= 0 {
+		args.BatchSize = DefaultKafkaBatchSize
+	}
+	if args.PollInterval <= 0 {
+		args.PollInterval = DefaultKafkaPollInterval
+	}
+	return &KafkaSink{args: args}
+}
+
+// Run produces changes until |ctx| is done. Failures are logged, and retried on the next poll.
+func (s *KafkaSink) Run(ctx context.Context) {
+	pollUntilDone(ctx, s.args.PollInterval, s.Poll, func(err error) {
+		s.args.Logger.WithError(err).Warnf("error producing changes of cursor %s in database %s to kafka", s.args.Cursor, s.args.Database)
+	})
+}
+
+// Poll produces every change committed since the cursor's commit, moving the cursor as each commit is produced.
+func (s *KafkaSink) Poll(ctx context.Context) error {
+	ddb, err := s.args.DoltDB(ctx)
+	if err != nil {
+		return err
+	}
+	return deliverChanges(ctx, ddb, s.args.Cursor, s.args.BatchSize, func(ctx context.Context, c doltdb.ChangeCursor, changes []Change) error {
+		msgs := make([]kafka.Message, len(changes))
+		for i, change := range changes {
+			msg, err := s.message(c.Branch, change)
+			if err != nil {
+				return err
+			}
+			msgs[i] = msg
+		}
+		return s.args.Producer.Produce(ctx, msgs)
+	})
+}
+
+// message returns the message holding the change event of |change|.
+func (s *KafkaSink) message(branch string, change Change) (kafka.Message, error) {
+	now := time.Now()
+	event := ChangeEvent{
+		Before: change.From,
+		After:  change.To,
+		Source: ChangeEventSource{
+			Connector: "dolt",
+			Name:      s.args.TopicPrefix,
+			TsMs:      change.CommitDate.UnixMilli(),
+			DB:        s.args.Database,
+			Table:     change.Table,
+			Branch:    branch,
+			Commit:    change.Commit,
+		},
+		TsMs: now.UnixMilli(),
+	}
+	switch change.Type {
+	case ChangeAdded:
+		event.Op = debeziumCreate
+	case ChangeRemoved:
+		event.Op = debeziumDelete
+	default:
+		event.Op = debeziumUpdate
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	var key []byte
+	if change.Key != nil {
+		if key, err = json.Marshal(change.Key); err != nil {
+			return kafka.Message{}, err
+		}
+	}
+	return kafka.Message{
+		Topic:     KafkaTopic(s.args.TopicPrefix, s.args.Database, change.Table),
+		Key:       key,
+		Value:     value,
+		Headers:   []kafka.Header{{Key: KafkaCommitHeader, Value: []byte(change.Commit)}},
+		Timestamp: now.UnixMilli(),
+	}, nil
+}
+
+// KafkaTopic returns the topic the changes of |table| are produced to, <prefix>.<database>.<table>. Characters which
+// can't be in topic names are replaced with underscores.
+func KafkaTopic(prefix, database, table string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, prefix+"."+database+"."+table)
+}
@@ -0,0 +1,356 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultClientID = "dolt"
+	DefaultTimeout  = 10 * time.Second
+
+	// maxAttempts is how many times messages are produced before giving up, refreshing metadata between attempts
+	maxAttempts  = 5
+	retryBackoff = 250 * time.Millisecond
+
+	// acksAll waits for every in sync replica to acknowledge the messages
+	acksAll = -1
+)
+
+// ProducerArgs configures a Producer.
+type ProducerArgs struct {
+	// Brokers are the host:port addresses of the brokers the cluster's metadata is first read from
+	Brokers  []string
+	ClientID string
+	// Timeout is how long to wait to connect to a broker, and for a broker to respond to a request
+	Timeout time.Duration
+}
+
+// Producer produces messages to the topics of a Kafka cluster. Messages are acknowledged by every in sync replica of
+// their partition before Produce returns, and each partition only has one request in flight, so the messages of a
+// partition are appended in the order they're produced.
+type Producer struct {
+	args ProducerArgs
+
+	mu            sync.Mutex
+	conns         map[int32]*brokerConn
+	brokers       map[int32]broker
+	leaders       map[string][]int32
+	correlationID int32
+	next          int
+}
+
+type brokerConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewProducer returns a Producer for |args|. Brokers aren't connected to until messages are produced.
+func NewProducer(args ProducerArgs) (*Producer, error) {
+	if len(args.Brokers) == 0 {
+		return nil, errors.New("kafka: no brokers given")
+	}
+	if args.ClientID == "" {
+		args.ClientID = DefaultClientID
+	}
+	if args.Timeout <= 0 {
+		args.Timeout = DefaultTimeout
+	}
+	return &Producer{
+		args:    args,
+		conns:   make(map[int32]*brokerConn),
+		brokers: make(map[int32]broker),
+		leaders: make(map[string][]int32),
+	}, nil
+}
+
+// Produce produces |msgs|, returning once every message has been acknowledged. Failures which can be fixed by
+// reading the cluster's metadata again, like a partition's leader moving, are retried. If an error is returned, some
+// of the messages may have been produced.
+func (p *Producer) Produce(ctx context.Context, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pending := msgs
+	for attempt := 1; ; attempt++ {
+		err := p.refreshMetadata(ctx, pending, attempt > 1)
+		if err == nil {
+			pending, err = p.produce(ctx, pending)
+			if err == nil {
+				return nil
+			}
+		}
+		var kerr Error
+		if attempt == maxAttempts || ctx.Err() != nil || (errors.As(err, &kerr) && !kerr.retriable()) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryBackoff * time.Duration(attempt)):
+		}
+	}
+}
+
+// Close closes the connections to the brokers.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var err error
+	for id, c := range p.conns {
+		if cerr := c.conn.Close(); err == nil {
+			err = cerr
+		}
+		delete(p.conns, id)
+	}
+	return err
+}
+
+// refreshMetadata reads the partition leaders of the topics of |msgs| which aren't known, or of every topic of |msgs|
+// if |force| is set.
+func (p *Producer) refreshMetadata(ctx context.Context, msgs []Message, force bool) error {
+	var topics []string
+	seen := make(map[string]bool)
+	for _, m := range msgs {
+		if _, ok := p.leaders[m.Topic]; (force || !ok) && !seen[m.Topic] {
+			seen[m.Topic] = true
+			topics = append(topics, m.Topic)
+		}
+	}
+	if len(topics) == 0 {
+		return nil
+	}
+
+	body, err := p.requestAny(ctx, apiMetadata, metadataVersion, encodeMetadataRequest(topics))
+	if err != nil {
+		return err
+	}
+	brokers, topicMeta, err := decodeMetadataResponse(body)
+	if err != nil {
+		return err
+	}
+	p.brokers = make(map[int32]broker, len(brokers))
+	for _, b := range brokers {
+		p.brokers[b.id] = b
+	}
+	for id, c := range p.conns {
+		if _, ok := p.brokers[id]; !ok {
+			c.conn.Close()
+			delete(p.conns, id)
+		}
+	}
+
+	var topicErr error
+	for _, t := range topicMeta {
+		if t.err != errNone || len(t.leaders) == 0 {
+			delete(p.leaders, t.name)
+			if topicErr == nil {
+				topicErr = fmt.Errorf("topic %s: %w", t.name, t.err)
+			}
+			continue
+		}
+		p.leaders[t.name] = t.leaders
+	}
+	return topicErr
+}
+
+// produce produces |msgs| to the leaders of their partitions, returning the messages which weren't acknowledged, and
+// the first error which stopped them from being acknowledged.
+func (p *Producer) produce(ctx context.Context, msgs []Message) ([]Message, error) {
+	type partition struct {
+		topic string
+		index int32
+	}
+	var order []partition
+	byPartition := make(map[partition][]Message)
+	for _, m := range msgs {
+		leaders, ok := p.leaders[m.Topic]
+		if !ok {
+			return msgs, fmt.Errorf("topic %s: %w", m.Topic, Error(errUnknownTopicOrPartition))
+		}
+		var idx int32
+		if m.Key != nil {
+			idx = int32(int(murmur2(m.Key)&0x7fffffff) % len(leaders))
+		} else {
+			idx = int32(p.next % len(leaders))
+			p.next++
+		}
+		pt := partition{topic: m.Topic, index: idx}
+		if _, ok := byPartition[pt]; !ok {
+			order = append(order, pt)
+		}
+		byPartition[pt] = append(byPartition[pt], m)
+	}
+
+	var failed []Message
+	var firstErr error
+	fail := func(pt partition, err error) {
+		failed = append(failed, byPartition[pt]...)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	byLeader := make(map[int32][]partitionRecords)
+	var leaderIDs []int32
+	for _, pt := range order {
+		leader := p.leaders[pt.topic][pt.index]
+		if leader < 0 {
+			fail(pt, fmt.Errorf("topic %s partition %d: %w", pt.topic, pt.index, Error(errLeaderNotAvailable)))
+			continue
+		}
+		if _, ok := byLeader[leader]; !ok {
+			leaderIDs = append(leaderIDs, leader)
+		}
+		byLeader[leader] = append(byLeader[leader], partitionRecords{
+			topic:     pt.topic,
+			partition: pt.index,
+			records:   encodeRecordBatch(byPartition[pt]),
+		})
+	}
+	sort.Slice(leaderIDs, func(i, j int) bool { return leaderIDs[i] < leaderIDs[j] })
+
+	timeoutMs := int32(p.args.Timeout / time.Millisecond)
+	for _, id := range leaderIDs {
+		batches := byLeader[id]
+		body, err := p.request(ctx, id, apiProduce, produceVersion, encodeProduceRequest(acksAll, timeoutMs, batches))
+		var results []partitionResult
+		if err == nil {
+			results, err = decodeProduceResponse(body)
+		}
+		if err != nil {
+			for _, b := range batches {
+				fail(partition{topic: b.topic, index: b.partition}, err)
+			}
+			continue
+		}
+		acked := make(map[partition]bool)
+		for _, r := range results {
+			pt := partition{topic: r.topic, index: r.partition}
+			if r.err != errNone {
+				fail(pt, fmt.Errorf("topic %s partition %d: %w", r.topic, r.partition, r.err))
+			}
+			acked[pt] = true
+		}
+		for _, b := range batches {
+			if pt := (partition{topic: b.topic, index: b.partition}); !acked[pt] {
+				fail(pt, fmt.Errorf("topic %s partition %d: %w", b.topic, b.partition, errMalformedResponse))
+			}
+		}
+	}
+	return failed, firstErr
+}
+
+// requestAny sends a request to any broker which can be reached, trying the known brokers before the bootstrap
+// brokers, and returns the body of its response.
+func (p *Producer) requestAny(ctx context.Context, api, version int16, body []byte) ([]byte, error) {
+	var ids []int32
+	for id := range p.conns {
+		ids = append(ids, id)
+	}
+	for id := range p.brokers {
+		if _, ok := p.conns[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	var lastErr error
+	for _, id := range ids {
+		resp, err := p.request(ctx, id, api, version, body)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+	}
+	for _, addr := range p.args.Brokers {
+		c, err := p.dial(ctx, addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp, err := p.roundTrip(ctx, c, api, version, body)
+		c.conn.Close()
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("kafka: no broker could be reached: %w", lastErr)
+}
+
+// request sends a request to the broker |id|, connecting to it if needed, and returns the body of its response.
+func (p *Producer) request(ctx context.Context, id int32, api, version int16, body []byte) ([]byte, error) {
+	c, ok := p.conns[id]
+	if !ok {
+		b, ok := p.brokers[id]
+		if !ok {
+			return nil, fmt.Errorf("kafka: unknown broker %d", id)
+		}
+		var err error
+		c, err = p.dial(ctx, net.JoinHostPort(b.host, strconv.Itoa(int(b.port))))
+		if err != nil {
+			return nil, err
+		}
+		p.conns[id] = c
+	}
+	resp, err := p.roundTrip(ctx, c, api, version, body)
+	if err != nil {
+		// the connection can't be reused once a request fails part way through
+		c.conn.Close()
+		delete(p.conns, id)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *Producer) dial(ctx context.Context, addr string) (*brokerConn, error) {
+	d := net.Dialer{Timeout: p.args.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &brokerConn{conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+func (p *Producer) roundTrip(ctx context.Context, c *brokerConn, api, version int16, body []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// brokers wait up to the timeout for replicas to acknowledge produced messages before responding, so responses
+	// are given twice as long
+	deadline := time.Now().Add(2 * p.args.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	p.correlationID++
+	if err := writeRequest(c.conn, api, version, p.correlationID, p.args.ClientID, body); err != nil {
+		return nil, err
+	}
+	return readResponse(c.r, p.correlationID)
+}
@@ -0,0 +1,252 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMurmur2(t *testing.T) {
+	// the hashes the Java client computes for these keys
+	cases := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	for key, expected := range cases {
+		assert.Equal(t, expected, murmur2([]byte(key)), key)
+	}
+}
+
+func TestRecordBatch(t *testing.T) {
+	msgs := []Message{
+		{Key: []byte(`{"pk":1}`), Value: []byte(`{"op":"c"}`), Timestamp: 1700000000000, Headers: []Header{{Key: "dolt.commit", Value: []byte("abc")}}},
+		{Value: []byte(`{"op":"d"}`), Timestamp: 1700000000500},
+	}
+	decoded, err := decodeRecordBatch(encodeRecordBatch(msgs))
+	require.NoError(t, err)
+	assert.Equal(t, msgs, decoded)
+
+	b := encodeRecordBatch(msgs)
+	b[len(b)-1] ^= 0xff
+	_, err = decodeRecordBatch(b)
+	assert.Error(t, err)
+}
+
+// fakeBroker is a single broker cluster, which serves the Metadata and Produce requests of a Producer. Every topic has
+// two partitions. The first metadata request for each topic in |unavailable| fails with LEADER_NOT_AVAILABLE, as it
+// does while a broker creates a topic.
+type fakeBroker struct {
+	lis         net.Listener
+	unavailable map[string]bool
+
+	mu       sync.Mutex
+	produced map[string][][]Message
+}
+
+func newFakeBroker(t *testing.T, unavailable ...string) *fakeBroker {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := &fakeBroker{lis: lis, unavailable: make(map[string]bool), produced: make(map[string][][]Message)}
+	for _, topic := range unavailable {
+		b.unavailable[topic] = true
+	}
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { lis.Close() })
+	return b
+}
+
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(r, req); err != nil {
+			return
+		}
+		d := decoder{buf: req}
+		api := d.int16()
+		d.int16() // version
+		correlationID := d.int32()
+		d.nullableString() // client id
+
+		e := encoder{buf: make([]byte, 4)}
+		e.int32(correlationID)
+		switch api {
+		case apiMetadata:
+			b.metadata(&d, &e)
+		case apiProduce:
+			b.produce(&d, &e)
+		default:
+			return
+		}
+		binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+		if _, err := conn.Write(e.buf); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) metadata(d *decoder, e *encoder) {
+	host, portStr, _ := net.SplitHostPort(b.lis.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	e.int32(1)
+	e.int32(7) // node id
+	e.string(host)
+	e.int32(int32(port))
+	e.nullableString(nil)
+	e.int32(7) // controller id
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := d.arrayLen()
+	e.int32(int32(n))
+	for i := 0; i < n; i++ {
+		topic := d.string()
+		if b.unavailable[topic] {
+			delete(b.unavailable, topic)
+			e.int16(errLeaderNotAvailable)
+			e.string(topic)
+			e.int8(0)
+			e.int32(0)
+			continue
+		}
+		e.int16(errNone)
+		e.string(topic)
+		e.int8(0)
+		e.int32(2)
+		for p := int32(0); p < 2; p++ {
+			e.int16(errNone)
+			e.int32(p)
+			e.int32(7) // leader
+			e.int32(1) // replicas
+			e.int32(7)
+			e.int32(1) // in sync replicas
+			e.int32(7)
+		}
+	}
+}
+
+func (b *fakeBroker) produce(d *decoder, e *encoder) {
+	d.nullableString() // transactional id
+	d.int16()          // acks
+	d.int32()          // timeout
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	topics := d.arrayLen()
+	e.int32(int32(topics))
+	for i := 0; i < topics; i++ {
+		topic := d.string()
+		if b.produced[topic] == nil {
+			b.produced[topic] = make([][]Message, 2)
+		}
+		e.string(topic)
+		partitions := d.arrayLen()
+		e.int32(int32(partitions))
+		for j := 0; j < partitions; j++ {
+			p := d.int32()
+			msgs, err := decodeRecordBatch(d.bytes())
+			e.int32(p)
+			if err != nil {
+				e.int16(2) // CORRUPT_MESSAGE
+			} else {
+				b.produced[topic][p] = append(b.produced[topic][p], msgs...)
+				e.int16(errNone)
+			}
+			e.int64(0)
+			e.int64(-1)
+		}
+	}
+	e.int32(0) // throttle time
+}
+
+func TestProducer(t *testing.T) {
+	ctx := context.Background()
+	b := newFakeBroker(t, "created")
+	p, err := NewProducer(ProducerArgs{Brokers: []string{b.lis.Addr().String()}})
+	require.NoError(t, err)
+	defer p.Close()
+
+	var msgs []Message
+	for i := 0; i < 10; i++ {
+		key := []byte(strconv.Itoa(i % 3))
+		msgs = append(msgs, Message{Topic: "t1", Key: key, Value: []byte(strconv.Itoa(i))})
+	}
+	msgs = append(msgs, Message{Topic: "created", Key: []byte("k"), Value: []byte("v")})
+	require.NoError(t, p.Produce(ctx, msgs))
+	require.NoError(t, p.Produce(ctx, []Message{{Topic: "t1", Key: []byte("0"), Value: []byte("10")}}))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// every message with the same key is in the same partition, in the order it was produced
+	values := make(map[string][]string)
+	total := 0
+	for _, partition := range b.produced["t1"] {
+		for _, m := range partition {
+			values[string(m.Key)] = append(values[string(m.Key)], string(m.Value))
+			total++
+		}
+	}
+	assert.Equal(t, 11, total)
+	assert.Equal(t, []string{"0", "3", "6", "9", "10"}, values["0"])
+	assert.Equal(t, []string{"1", "4", "7"}, values["1"])
+	assert.Equal(t, []string{"2", "5", "8"}, values["2"])
+
+	partition := (murmur2([]byte("k")) & 0x7fffffff) % 2
+	require.Len(t, b.produced["created"][partition], 1)
+	assert.Equal(t, "v", string(b.produced["created"][partition][0].Value))
+}
+
+func TestProducerUnreachable(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	lis.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p, err := NewProducer(ProducerArgs{Brokers: []string{addr}})
+	require.NoError(t, err)
+	assert.Error(t, p.Produce(ctx, []Message{{Topic: "t1", Value: []byte("v")}}))
+
+	_, err = NewProducer(ProducerArgs{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,538 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kafka is a minimal Kafka producer, which publishes messages with the Metadata and Produce requests of the
+// Kafka protocol. Only the request versions supported by every broker since Kafka 0.11 are used, so it doesn't
+// negotiate versions, and it doesn't support compression, transactions or SASL.
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Every request and response is framed by its length as a big endian int32. Requests start with a header holding the
+// API key and version of the request, a correlation id and the client id, and responses start with the correlation id
+// of their request.
+
+// maxResponseSize is the size of the largest response read from a broker.
+const maxResponseSize = 64 << 20
+
+// API keys and versions of the requests which are sent.
+const (
+	apiProduce  = 0
+	apiMetadata = 3
+
+	produceVersion  = 3
+	metadataVersion = 1
+)
+
+// Error codes of responses which are handled.
+const (
+	errNone                    = 0
+	errUnknownTopicOrPartition = 3
+	errLeaderNotAvailable      = 5
+	errNotLeaderForPartition   = 6
+)
+
+// Error is an error code returned by a broker.
+type Error int16
+
+func (e Error) Error() string {
+	switch e {
+	case errUnknownTopicOrPartition:
+		return "kafka: unknown topic or partition"
+	case errLeaderNotAvailable:
+		return "kafka: leader not available"
+	case errNotLeaderForPartition:
+		return "kafka: not leader for partition"
+	}
+	return fmt.Sprintf("kafka: error code %d", int16(e))
+}
+
+// retriable returns whether a request which failed with |e| can succeed once metadata is refreshed.
+func (e Error) retriable() bool {
+	return e == errUnknownTopicOrPartition || e == errLeaderNotAvailable || e == errNotLeaderForPartition
+}
+
+var errMalformedResponse = errors.New("kafka: malformed response")
+
+// encoder appends the primitive types of the Kafka protocol to a buffer.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) int8(v int8) {
+	e.buf = append(e.buf, byte(v))
+}
+
+func (e *encoder) int16(v int16) {
+	e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v))
+}
+
+func (e *encoder) int32(v int32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v))
+}
+
+func (e *encoder) int64(v int64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v))
+}
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) nullableString(s *string) {
+	if s == nil {
+		e.int16(-1)
+		return
+	}
+	e.string(*s)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.int32(int32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// varint appends |v| zigzag encoded, as the fields of records are.
+func (e *encoder) varint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+// varBytes appends |b| prefixed by its length as a varint, or a length of -1 if it's nil.
+func (e *encoder) varBytes(b []byte) {
+	if b == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// decoder reads the primitive types of the Kafka protocol from a response. Once a read fails, every later read
+// returns zero values, and err is set.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.buf) < n {
+		d.err = errMalformedResponse
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) int8() int8 {
+	if b := d.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *decoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *decoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *decoder) string() string {
+	n := d.int16()
+	return string(d.next(int(n)))
+}
+
+func (d *decoder) nullableString() *string {
+	n := d.int16()
+	if n < 0 {
+		return nil
+	}
+	s := string(d.next(int(n)))
+	return &s
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.int32()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+// arrayLen returns the length of the array which follows. Null arrays have no elements.
+func (d *decoder) arrayLen() int {
+	n := d.int32()
+	if n < 0 {
+		return 0
+	}
+	if int(n) > len(d.buf) {
+		// every element is at least a byte long
+		d.err = errMalformedResponse
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errMalformedResponse
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) varBytes() []byte {
+	n := d.varint()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+// writeRequest writes the request |body| with the API key and version |api| and |version| to |w|.
+func writeRequest(w io.Writer, api, version int16, correlationID int32, clientID string, body []byte) error {
+	e := encoder{buf: make([]byte, 4, 4+10+len(clientID)+len(body))}
+	e.int16(api)
+	e.int16(version)
+	e.int32(correlationID)
+	e.nullableString(&clientID)
+	e.buf = append(e.buf, body...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+	_, err := w.Write(e.buf)
+	return err
+}
+
+// readResponse reads the response to the request with |correlationID| from |r|, returning its body.
+func readResponse(r io.Reader, correlationID int32) ([]byte, error) {
+	var hdr [8]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	size := int32(binary.BigEndian.Uint32(hdr[:4]))
+	if size < 4 || size > maxResponseSize {
+		return nil, errMalformedResponse
+	}
+	if id := int32(binary.BigEndian.Uint32(hdr[4:])); id != correlationID {
+		return nil, fmt.Errorf("kafka: response has correlation id %d, expected %d", id, correlationID)
+	}
+	body := make([]byte, size-4)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// broker is a broker of a cluster, from a Metadata response.
+type broker struct {
+	id   int32
+	host string
+	port int32
+}
+
+// topicMetadata holds the leaders of the partitions of a topic, from a Metadata response.
+type topicMetadata struct {
+	name    string
+	err     Error
+	leaders []int32
+}
+
+func encodeMetadataRequest(topics []string) []byte {
+	var e encoder
+	e.int32(int32(len(topics)))
+	for _, t := range topics {
+		e.string(t)
+	}
+	return e.buf
+}
+
+func decodeMetadataResponse(body []byte) ([]broker, []topicMetadata, error) {
+	d := decoder{buf: body}
+	brokers := make([]broker, d.arrayLen())
+	for i := range brokers {
+		brokers[i].id = d.int32()
+		brokers[i].host = d.string()
+		brokers[i].port = d.int32()
+		d.nullableString() // rack
+	}
+	d.int32() // controller id
+	topics := make([]topicMetadata, d.arrayLen())
+	for i := range topics {
+		topics[i].err = Error(d.int16())
+		topics[i].name = d.string()
+		d.int8() // is internal
+		n := d.arrayLen()
+		leaders := make([]int32, n)
+		for j := 0; j < n; j++ {
+			perr := d.int16()
+			idx := d.int32()
+			leader := d.int32()
+			for k := d.arrayLen(); k > 0; k-- {
+				d.int32() // replica
+			}
+			for k := d.arrayLen(); k > 0; k-- {
+				d.int32() // in sync replica
+			}
+			if idx < 0 || int(idx) >= n {
+				return nil, nil, errMalformedResponse
+			}
+			if perr != errNone {
+				leader = -1
+			}
+			leaders[idx] = leader
+		}
+		topics[i].leaders = leaders
+	}
+	if d.err != nil {
+		return nil, nil, d.err
+	}
+	return brokers, topics, nil
+}
+
+// partitionRecords are the records produced to a partition of a topic.
+type partitionRecords struct {
+	topic     string
+	partition int32
+	records   []byte
+}
+
+// partitionResult is the result of producing records to a partition, from a Produce response.
+type partitionResult struct {
+	topic     string
+	partition int32
+	err       Error
+}
+
+// encodeProduceRequest returns a Produce request for |batches|, which waits for |acks| replicas to acknowledge the
+// records, for at most |timeoutMs|.
+func encodeProduceRequest(acks int16, timeoutMs int32, batches []partitionRecords) []byte {
+	var e encoder
+	e.nullableString(nil) // transactional id
+	e.int16(acks)
+	e.int32(timeoutMs)
+
+	// batches are grouped by topic, in the order their topics are first seen
+	var topics []string
+	byTopic := make(map[string][]partitionRecords)
+	for _, b := range batches {
+		if _, ok := byTopic[b.topic]; !ok {
+			topics = append(topics, b.topic)
+		}
+		byTopic[b.topic] = append(byTopic[b.topic], b)
+	}
+	e.int32(int32(len(topics)))
+	for _, t := range topics {
+		e.string(t)
+		e.int32(int32(len(byTopic[t])))
+		for _, b := range byTopic[t] {
+			e.int32(b.partition)
+			e.bytes(b.records)
+		}
+	}
+	return e.buf
+}
+
+func decodeProduceResponse(body []byte) ([]partitionResult, error) {
+	d := decoder{buf: body}
+	var results []partitionResult
+	for i := d.arrayLen(); i > 0; i-- {
+		topic := d.string()
+		for j := d.arrayLen(); j > 0; j-- {
+			r := partitionResult{topic: topic}
+			r.partition = d.int32()
+			r.err = Error(d.int16())
+			d.int64() // base offset
+			d.int64() // log append time
+			results = append(results, r)
+		}
+	}
+	d.int32() // throttle time
+	if d.err != nil {
+		return nil, d.err
+	}
+	return results, nil
+}
+
+// Header is a header of a Message.
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message is a message produced to a topic. Messages with the same key are produced to the same partition of their
+// topic, using the same partitioner as the Java client, so they're consumed in the order they're produced.
+type Message struct {
+	Topic string
+	// Key is the key of the message, or nil if it has none. Messages without keys are spread across partitions.
+	Key     []byte
+	Value   []byte
+	Headers []Header
+	// Timestamp is the creation time of the message, in milliseconds since the epoch.
+	Timestamp int64
+}
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// encodeRecordBatch returns |msgs| as a v2 record batch, the format of the records of Produce requests since
+// Kafka 0.11.
+func encodeRecordBatch(msgs []Message) []byte {
+	baseTs, maxTs := msgs[0].Timestamp, msgs[0].Timestamp
+	for _, m := range msgs {
+		if m.Timestamp > maxTs {
+			maxTs = m.Timestamp
+		}
+	}
+
+	var e encoder
+	e.int64(0)  // base offset, which is assigned by the broker
+	e.int32(0)  // length, which is set below
+	e.int32(-1) // partition leader epoch
+	e.int8(2)   // magic
+	e.int32(0)  // crc, which is set below
+	crcStart := len(e.buf)
+	e.int16(0) // attributes: no compression, create time
+	e.int32(int32(len(msgs) - 1))
+	e.int64(baseTs)
+	e.int64(maxTs)
+	e.int64(-1) // producer id
+	e.int16(-1) // producer epoch
+	e.int32(-1) // base sequence
+	e.int32(int32(len(msgs)))
+
+	var rec encoder
+	for i, m := range msgs {
+		rec.buf = rec.buf[:0]
+		rec.int8(0) // attributes
+		rec.varint(m.Timestamp - baseTs)
+		rec.varint(int64(i))
+		rec.varBytes(m.Key)
+		rec.varBytes(m.Value)
+		rec.varint(int64(len(m.Headers)))
+		for _, h := range m.Headers {
+			rec.varBytes([]byte(h.Key))
+			rec.varBytes(h.Value)
+		}
+		e.varint(int64(len(rec.buf)))
+		e.buf = append(e.buf, rec.buf...)
+	}
+
+	binary.BigEndian.PutUint32(e.buf[8:], uint32(len(e.buf)-12))
+	binary.BigEndian.PutUint32(e.buf[crcStart-4:], crc32.Checksum(e.buf[crcStart:], crc32c))
+	return e.buf
+}
+
+// decodeRecordBatch returns the messages of the v2 record batch |b|, without their topics.
+func decodeRecordBatch(b []byte) ([]Message, error) {
+	d := decoder{buf: b}
+	d.int64() // base offset
+	length := d.int32()
+	d.int32() // partition leader epoch
+	if magic := d.int8(); d.err == nil && magic != 2 {
+		return nil, fmt.Errorf("kafka: unsupported record batch version %d", magic)
+	}
+	crc := uint32(d.int32())
+	if d.err != nil || int(length) != len(b)-12 {
+		return nil, errMalformedResponse
+	}
+	if crc32.Checksum(d.buf, crc32c) != crc {
+		return nil, errors.New("kafka: record batch checksum mismatch")
+	}
+	d.int16() // attributes
+	d.int32() // last offset delta
+	baseTs := d.int64()
+	d.int64() // max timestamp
+	d.int64() // producer id
+	d.int16() // producer epoch
+	d.int32() // base sequence
+	msgs := make([]Message, d.arrayLen())
+	for i := range msgs {
+		d.varint() // length
+		d.int8()   // attributes
+		msgs[i].Timestamp = baseTs + d.varint()
+		d.varint() // offset delta
+		msgs[i].Key = d.varBytes()
+		msgs[i].Value = d.varBytes()
+		for j := d.varint(); j > 0; j-- {
+			key := string(d.varBytes())
+			msgs[i].Headers = append(msgs[i].Headers, Header{Key: key, Value: d.varBytes()})
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return msgs, nil
+}
+
+// murmur2 is the hash the Java client's default partitioner uses to choose the partition of a message from its key.
+func murmur2(data []byte) int32 {
+	const seed uint32 = 0x9747b28c
+	const m uint32 = 0x5bd1e995
+	const r = 24
+
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
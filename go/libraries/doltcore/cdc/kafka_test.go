@@ -0,0 +1,129 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc/kafka"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+type fakeProducer struct {
+	msgs []kafka.Message
+	err  error
+}
+
+func (p *fakeProducer) Produce(_ context.Context, msgs []kafka.Message) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.msgs = append(p.msgs, msgs...)
+	return nil
+}
+
+func TestKafkaSink(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "create cursor")
+	require.NoError(t, err)
+	require.NoError(t, dEnv.DoltDB.SetChangeCursor(ctx, doltdb.ChangeCursor{Name: "c", Branch: "main", Commit: headHash(t, dEnv.DoltDB, "main")}, meta))
+	first := commitSql(t, dEnv, `
+		create table t (pk int primary key, c1 varchar(10));
+		insert into t values (1, 'one');`)
+	second := commitSql(t, dEnv, `
+		update t set c1 = 'uno' where pk = 1;
+		create table `+"`keyless t`"+` (c1 int);
+		insert into `+"`keyless t`"+` values (7);`)
+
+	producer := &fakeProducer{err: errors.New("broker unavailable")}
+	sink := cdc.NewKafkaSink(cdc.KafkaSinkArgs{
+		Logger:   logrus.NewEntry(logrus.StandardLogger()),
+		Database: "db",
+		DoltDB: func(context.Context) (*doltdb.DoltDB, error) {
+			return dEnv.DoltDB, nil
+		},
+		Cursor:   "c",
+		Producer: producer,
+	})
+
+	// the cursor isn't moved if the changes can't be produced
+	require.Error(t, sink.Poll(ctx))
+	c, _, err := dEnv.DoltDB.GetChangeCursor(ctx, "c")
+	require.NoError(t, err)
+	assert.NotEqual(t, second, c.Commit)
+
+	producer.err = nil
+	require.NoError(t, sink.Poll(ctx))
+	c, _, err = dEnv.DoltDB.GetChangeCursor(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, second, c.Commit)
+
+	require.Len(t, producer.msgs, 3)
+	type expected struct {
+		topic, key, op, commit string
+		before, after          map[string]interface{}
+	}
+	for i, e := range []expected{
+		{topic: "dolt.db.t", key: `{"pk":1}`, op: "c", commit: first.String(), after: map[string]interface{}{"pk": 1.0, "c1": "one"}},
+		{topic: "dolt.db.keyless_t", op: "c", commit: second.String(), after: map[string]interface{}{"c1": 7.0}},
+		{topic: "dolt.db.t", key: `{"pk":1}`, op: "u", commit: second.String(), before: map[string]interface{}{"pk": 1.0, "c1": "one"}, after: map[string]interface{}{"pk": 1.0, "c1": "uno"}},
+	} {
+		msg := producer.msgs[i]
+		assert.Equal(t, e.topic, msg.Topic)
+		if e.key == "" {
+			assert.Nil(t, msg.Key)
+		} else {
+			assert.Equal(t, e.key, string(msg.Key))
+		}
+		assert.Equal(t, []kafka.Header{{Key: cdc.KafkaCommitHeader, Value: []byte(e.commit)}}, msg.Headers)
+
+		var event struct {
+			Before map[string]interface{} `json:"before"`
+			After  map[string]interface{} `json:"after"`
+			Source cdc.ChangeEventSource  `json:"source"`
+			Op     string                 `json:"op"`
+		}
+		require.NoError(t, json.Unmarshal(msg.Value, &event))
+		assert.Equal(t, e.op, event.Op)
+		assert.Equal(t, e.before, event.Before)
+		assert.Equal(t, e.after, event.After)
+		assert.Equal(t, "dolt", event.Source.Connector)
+		assert.Equal(t, "db", event.Source.DB)
+		assert.Equal(t, "main", event.Source.Branch)
+		assert.Equal(t, e.commit, event.Source.Commit)
+	}
+
+	// nothing is produced once the cursor is at the head of its branch
+	require.NoError(t, sink.Poll(ctx))
+	assert.Len(t, producer.msgs, 3)
+}
+
+func TestKafkaTopic(t *testing.T) {
+	assert.Equal(t, "dolt.mydb.t1", cdc.KafkaTopic("dolt", "mydb", "t1"))
+	assert.Equal(t, "cdc.my_db.my_table", cdc.KafkaTopic("cdc", "my db", "my$table"))
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// pollUntilDone calls |poll| every |interval| until |ctx| is done, passing its failures to |onErr|.
+func pollUntilDone(ctx context.Context, interval time.Duration, poll func(context.Context) error, onErr func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := poll(ctx); err != nil && ctx.Err() == nil {
+			onErr(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverChanges passes every change committed since the commit of the change cursor |name| to |send|, in batches of
+// at most |batchSize| changes which never span commits. The cursor is moved past each commit once all of its changes
+// are sent, so changes are delivered at least once: if a batch fails, the changes of its commit are sent again,
+// starting with the commit's first batch, the next time changes are delivered.
+func deliverChanges(ctx context.Context, ddb *doltdb.DoltDB, name string, batchSize int, send func(context.Context, doltdb.ChangeCursor, []Change) error) error {
+	c, ok, err := ddb.GetChangeCursor(ctx, name)
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("%w: %s", ErrCursorNotFound, name)
+	}
+	it, err := NewChangeIter(ctx, ddb, c)
+	if err != nil {
+		return err
+	}
+
+	advance := func(commit hash.Hash) error {
+		meta, err := datas.NewCommitMeta(env.DefaultName, env.DefaultEmail, "advance change cursor")
+		if err != nil {
+			return err
+		}
+		c.Commit = commit
+		c.UpdatedAt = time.Now()
+		return ddb.SetChangeCursor(ctx, c, meta)
+	}
+	flush := func(batch []Change) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		return send(ctx, c, batch)
+	}
+
+	var batch []Change
+	var last string
+	for {
+		change, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if last != "" && change.Commit != last {
+			// every change of the last commit has been read
+			if err := flush(batch); err != nil {
+				return err
+			}
+			batch = nil
+			if err := advance(hash.Parse(last)); err != nil {
+				return err
+			}
+		}
+		last = change.Commit
+		batch = append(batch, change)
+		if len(batch) >= batchSize {
+			if err := flush(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+	}
+	if err := flush(batch); err != nil {
+		return err
+	}
+	if it.Head() != c.Commit {
+		return advance(it.Head())
+	}
+	return nil
+}
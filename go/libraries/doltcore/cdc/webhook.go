@@ -0,0 +1,127 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+const (
+	DefaultWebhookBatchSize    = 1000
+	DefaultWebhookPollInterval = time.Second
+	DefaultWebhookTimeout      = 10 * time.Second
+)
+
+// WebhookBatch is the JSON body POSTed to a webhook, holding the next changes read with a change cursor.
+type WebhookBatch struct {
+	Database string   `json:"database"`
+	Cursor   string   `json:"cursor"`
+	Branch   string   `json:"branch"`
+	Changes  []Change `json:"changes"`
+}
+
+// WebhookSinkArgs configures a WebhookSink.
+type WebhookSinkArgs struct {
+	Logger *logrus.Entry
+	// Database is the name of the database the cursor is in
+	Database string
+	// DoltDB returns the DoltDB of the database
+	DoltDB func(ctx context.Context) (*doltdb.DoltDB, error)
+	// Cursor is the name of the change cursor to read changes with
+	Cursor string
+	// URL is the http or https URL batches of changes are POSTed to
+	URL string
+	// BatchSize is the most changes POSTed at a time
+	BatchSize int
+	// PollInterval is how often the branch of the cursor is checked for new commits
+	PollInterval time.Duration
+	// Timeout is how long to wait for the webhook to respond
+	Timeout time.Duration
+}
+
+// WebhookSink POSTs the changes read with a change cursor to a webhook as they're committed, in batches of at most
+// BatchSize changes. Changes are delivered at least once, as described by deliverChanges.
+type WebhookSink struct {
+	args   WebhookSinkArgs
+	client *http.Client
+}
+
+// NewWebhookSink returns a WebhookSink for |args|.
+func NewWebhookSink(args WebhookSinkArgs) *WebhookSink {
+	if args.BatchSize <= 0 {
+		args.BatchSize = DefaultWebhookBatchSize
+	}
+	if args.PollInterval <= 0 {
+		args.PollInterval = DefaultWebhookPollInterval
+	}
+	if args.Timeout <= 0 {
+		args.Timeout = DefaultWebhookTimeout
+	}
+	return &WebhookSink{args: args, client: &http.Client{Timeout: args.Timeout}}
+}
+
+// Run delivers changes until |ctx| is done. Failures are logged, and retried on the next poll.
+func (s *WebhookSink) Run(ctx context.Context) {
+	pollUntilDone(ctx, s.args.PollInterval, s.Poll, func(err error) {
+		s.args.Logger.WithError(err).Warnf("error delivering changes of cursor %s in database %s to %s", s.args.Cursor, s.args.Database, s.args.URL)
+	})
+}
+
+// Poll delivers every change committed since the cursor's commit, moving the cursor as each commit is delivered.
+func (s *WebhookSink) Poll(ctx context.Context) error {
+	ddb, err := s.args.DoltDB(ctx)
+	if err != nil {
+		return err
+	}
+	return deliverChanges(ctx, ddb, s.args.Cursor, s.args.BatchSize, func(ctx context.Context, c doltdb.ChangeCursor, changes []Change) error {
+		return s.post(ctx, c.Branch, changes)
+	})
+}
+
+// post POSTs |changes| to the webhook.
+func (s *WebhookSink) post(ctx context.Context, branch string, changes []Change) error {
+	body, err := json.Marshal(WebhookBatch{
+		Database: s.args.Database,
+		Cursor:   s.args.Cursor,
+		Branch:   branch,
+		Changes:  changes,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.args.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+func TestWebhookSink(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "create cursor")
+	require.NoError(t, err)
+	require.NoError(t, dEnv.DoltDB.SetChangeCursor(ctx, doltdb.ChangeCursor{Name: "c", Branch: "main", Commit: headHash(t, dEnv.DoltDB, "main")}, meta))
+	first := commitSql(t, dEnv, `
+		create table t (pk int primary key);
+		insert into t values (1), (2), (3);`)
+	second := commitSql(t, dEnv, `delete from t where pk = 2;`)
+
+	var mu sync.Mutex
+	var batches []cdc.WebhookBatch
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var batch cdc.WebhookBatch
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		batches = append(batches, batch)
+	}))
+	defer srv.Close()
+
+	sink := cdc.NewWebhookSink(cdc.WebhookSinkArgs{
+		Logger:   logrus.NewEntry(logrus.StandardLogger()),
+		Database: "db",
+		DoltDB: func(context.Context) (*doltdb.DoltDB, error) {
+			return dEnv.DoltDB, nil
+		},
+		Cursor:    "c",
+		URL:       srv.URL,
+		BatchSize: 2,
+	})
+	require.NoError(t, sink.Poll(ctx))
+
+	// the first commit's changes are split into two batches, and aren't batched with the second's
+	require.Len(t, batches, 3)
+	for _, b := range batches {
+		assert.Equal(t, "db", b.Database)
+		assert.Equal(t, "c", b.Cursor)
+		assert.Equal(t, "main", b.Branch)
+	}
+	assert.Len(t, batches[0].Changes, 2)
+	assert.Len(t, batches[1].Changes, 1)
+	assert.Equal(t, first.String(), batches[1].Changes[0].Commit)
+	require.Len(t, batches[2].Changes, 1)
+	assert.Equal(t, second.String(), batches[2].Changes[0].Commit)
+	assert.Equal(t, cdc.ChangeRemoved, batches[2].Changes[0].Type)
+	assert.Equal(t, map[string]interface{}{"pk": float64(2)}, batches[2].Changes[0].Key)
+
+	c, ok, err := dEnv.DoltDB.GetChangeCursor(ctx, "c")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, second, c.Commit)
+
+	// nothing is delivered until there are new commits
+	batches = nil
+	require.NoError(t, sink.Poll(ctx))
+	assert.Empty(t, batches)
+
+	// the cursor isn't moved past commits which weren't delivered
+	third := commitSql(t, dEnv, `insert into t values (4);`)
+	mu.Lock()
+	fail = true
+	mu.Unlock()
+	require.Error(t, sink.Poll(ctx))
+	c, _, err = dEnv.DoltDB.GetChangeCursor(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, second, c.Commit)
+
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	require.NoError(t, sink.Poll(ctx))
+	require.Len(t, batches, 1)
+	assert.Equal(t, third.String(), batches[0].Changes[0].Commit)
+}
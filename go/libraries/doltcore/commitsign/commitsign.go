@@ -0,0 +1,171 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package commitsign signs dolt commits and verifies their signatures, with GPG or with SSH keys, using the same
+// programs and configuration as git does.
+package commitsign
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+const (
+	// FormatOpenPGP signs commits with GPG, and is the default format
+	FormatOpenPGP = "openpgp"
+	// FormatSSH signs commits with SSH keys, using ssh-keygen
+	FormatSSH = "ssh"
+)
+
+// sshNamespace is the namespace of SSH signatures of dolt commits, so they can't be used as signatures of anything else
+const sshNamespace = "dolt"
+
+// Status is the result of verifying the signature of a commit.
+type Status string
+
+const (
+	// StatusUnsigned is the status of a commit without a signature
+	StatusUnsigned Status = "unsigned"
+	// StatusGood is the status of a commit with a valid signature by a trusted key
+	StatusGood Status = "good"
+	// StatusBad is the status of a commit whose signature doesn't match its contents
+	StatusBad Status = "bad"
+	// StatusUnknown is the status of a commit whose signature can't be checked, such as when the key which made it
+	// isn't known
+	StatusUnknown Status = "unknown"
+)
+
+// Verification is the result of verifying the signature of a commit.
+type Verification struct {
+	Status Status
+	// Signer identifies who made the signature, when it's known
+	Signer string
+	// Detail describes why a signature couldn't be checked
+	Detail string
+}
+
+// Config is the configuration for signing and verifying commits, which is read from the user.signingkey, gpg.format,
+// gpg.program, gpg.ssh.allowedsignersfile and commit.gpgsign dolt config.
+type Config struct {
+	Format             string
+	Program            string
+	SigningKey         string
+	AllowedSignersFile string
+	// SignByDefault is whether commits are signed without being asked to
+	SignByDefault bool
+}
+
+// NewConfig returns the signing configuration in |cfg|.
+func NewConfig(cfg config.ReadableConfig) Config {
+	return Config{
+		Format:             strings.ToLower(cfg.GetStringOrDefault(config.GpgFormat, FormatOpenPGP)),
+		Program:            cfg.GetStringOrDefault(config.GpgProgram, ""),
+		SigningKey:         cfg.GetStringOrDefault(config.UserSigningKey, ""),
+		AllowedSignersFile: cfg.GetStringOrDefault(config.GpgSshAllowedSignersFile, ""),
+		SignByDefault:      strings.ToLower(cfg.GetStringOrDefault(config.CommitGpgSign, "false")) == "true",
+	}
+}
+
+// LoadConfig loads the signing configuration from the dolt config on disk, for use where there's no DoltEnv, such as
+// in a sql-server.
+func LoadConfig() (Config, error) {
+	cfg, err := env.LoadDoltCliConfig(env.GetCurrentUserHomeDir, filesys.LocalFS)
+	if err != nil {
+		return Config{}, err
+	}
+	return NewConfig(cfg), nil
+}
+
+// Signer returns a datas.CommitSigner which signs commits with the configured key.
+func (c Config) Signer(ctx context.Context) (datas.CommitSigner, error) {
+	switch c.Format {
+	case FormatOpenPGP:
+		return func(payload []byte) (string, error) {
+			return c.gpgSign(ctx, payload)
+		}, nil
+	case FormatSSH:
+		if c.SigningKey == "" {
+			return nil, errors.New("error: signing commits with SSH keys requires the path to a key in user.signingkey")
+		}
+		return func(payload []byte) (string, error) {
+			return c.sshSign(ctx, payload)
+		}, nil
+	default:
+		return nil, fmt.Errorf("error: unsupported value for gpg.format: %s", c.Format)
+	}
+}
+
+// VerifyCommit verifies the signature of |cm|.
+func (c Config) VerifyCommit(ctx context.Context, cm *doltdb.Commit) (Verification, error) {
+	signature, payload, err := datas.GetCommitSignature(cm.Value())
+	if err != nil {
+		return Verification{}, err
+	}
+	return c.Verify(ctx, payload, signature)
+}
+
+// Verify verifies that |signature| is a signature of |payload|. The kind of signature is detected from its armor, so
+// SSH signatures are verified even when gpg.format is openpgp. Signatures which can't be checked because the program
+// to check them can't be run have the status StatusUnknown, rather than being an error.
+func (c Config) Verify(ctx context.Context, payload []byte, signature string) (Verification, error) {
+	if signature == "" {
+		return Verification{Status: StatusUnsigned}, nil
+	}
+
+	sigFile, err := os.CreateTemp("", "dolt-signature-*")
+	if err != nil {
+		return Verification{}, err
+	}
+	defer os.Remove(sigFile.Name())
+	_, err = sigFile.WriteString(signature)
+	if cerr := sigFile.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return Verification{}, err
+	}
+
+	if strings.HasPrefix(signature, sshSignatureArmor) {
+		return c.sshVerify(ctx, payload, sigFile.Name()), nil
+	}
+	return c.gpgVerify(ctx, payload, sigFile.Name()), nil
+}
+
+// run runs |program| with |args|, writing |stdin| to it, and returns what it wrote to stdout and stderr.
+func run(ctx context.Context, stdin []byte, program string, args ...string) (stdout, stderr []byte, err error) {
+	var outBuf, errBuf bytes.Buffer
+	cmd := exec.CommandContext(ctx, program, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+	err = cmd.Run()
+	return outBuf.Bytes(), errBuf.Bytes(), err
+}
+
+// notRun returns whether |err| means a program couldn't be started at all, rather than that it failed.
+func notRun(err error) bool {
+	var exitErr *exec.ExitError
+	return err != nil && !errors.As(err, &exitErr)
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitsign
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const defaultGpgProgram = "gpg"
+
+// gpgStatusPrefix begins the machine-readable status lines gpg writes to the file descriptor given by --status-fd
+const gpgStatusPrefix = "[GNUPG:] "
+
+func (c Config) gpgProgram() string {
+	if c.Program != "" {
+		return c.Program
+	}
+	return defaultGpgProgram
+}
+
+// gpgSign returns a detached, armored signature of |payload| made by the signing key, or gpg's default key when no
+// key is configured.
+func (c Config) gpgSign(ctx context.Context, payload []byte) (string, error) {
+	args := []string{"--status-fd=2", "-bsa"}
+	if c.SigningKey != "" {
+		args = append(args, "-u", c.SigningKey)
+	}
+	stdout, stderr, err := run(ctx, payload, c.gpgProgram(), args...)
+	// gpg may exit successfully without having signed anything, so its status is checked instead
+	if err != nil || !bytes.Contains(stderr, []byte("\n"+gpgStatusPrefix+"SIG_CREATED ")) {
+		return "", fmt.Errorf("error: gpg failed to sign the commit: %s", strings.TrimSpace(string(stderr)))
+	}
+	return string(stdout), nil
+}
+
+// gpgVerify verifies the signature in |sigFile| of |payload| against the keys in the user's keyring.
+func (c Config) gpgVerify(ctx context.Context, payload []byte, sigFile string) Verification {
+	stdout, stderr, err := run(ctx, payload, c.gpgProgram(), "--status-fd=1", "--keyid-format=long", "--verify", sigFile, "-")
+	if notRun(err) {
+		return Verification{Status: StatusUnknown, Detail: err.Error()}
+	}
+
+	// gpg exits with an error for bad signatures, so the result is read from its status lines
+	ver := Verification{Status: StatusUnknown, Detail: strings.TrimSpace(string(stderr))}
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		line, ok := strings.CutPrefix(scanner.Text(), gpgStatusPrefix)
+		if !ok {
+			continue
+		}
+		keyword, rest, _ := strings.Cut(line, " ")
+		switch keyword {
+		case "GOODSIG":
+			ver.Status = StatusGood
+			ver.Signer = gpgSigner(rest)
+		case "BADSIG":
+			ver.Status = StatusBad
+			ver.Signer = gpgSigner(rest)
+		case "EXPKEYSIG", "REVKEYSIG":
+			// made by a key which is known, but can't be trusted anymore
+			ver.Signer = gpgSigner(rest)
+		}
+	}
+	if ver.Status != StatusUnknown {
+		ver.Detail = ""
+	}
+	return ver
+}
+
+// gpgSigner returns the user id from the arguments of a GOODSIG or BADSIG status line, which are the long key id
+// followed by the user id
+func gpgSigner(args string) string {
+	keyID, uid, ok := strings.Cut(args, " ")
+	if !ok {
+		return keyID
+	}
+	return uid
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitsign
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const sshKeygenProgram = "ssh-keygen"
+
+// sshSignatureArmor begins every armored SSH signature
+const sshSignatureArmor = "-----BEGIN SSH SIGNATURE-----"
+
+// sshSign returns an armored signature of |payload| made with the SSH key in the signing key file. If the file is a
+// public key, the private key is taken from the ssh-agent.
+func (c Config) sshSign(ctx context.Context, payload []byte) (string, error) {
+	stdout, stderr, err := run(ctx, payload, sshKeygenProgram, "-Y", "sign", "-n", sshNamespace, "-f", c.SigningKey)
+	if err != nil || !strings.HasPrefix(string(stdout), sshSignatureArmor) {
+		msg := strings.TrimSpace(string(stderr))
+		if msg == "" && err != nil {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("error: ssh-keygen failed to sign the commit: %s", msg)
+	}
+	return string(stdout), nil
+}
+
+// sshVerify verifies the SSH signature in |sigFile| of |payload|. Signatures are only good when they're made by a key
+// listed in the allowed signers file. Signatures by other keys, or when there's no allowed signers file, are unknown.
+func (c Config) sshVerify(ctx context.Context, payload []byte, sigFile string) Verification {
+	if c.AllowedSignersFile != "" {
+		stdout, _, err := run(ctx, nil, sshKeygenProgram, "-Y", "find-principals", "-f", c.AllowedSignersFile, "-s", sigFile)
+		if notRun(err) {
+			return Verification{Status: StatusUnknown, Detail: err.Error()}
+		}
+		if err == nil {
+			principal, _, _ := strings.Cut(strings.TrimSpace(string(stdout)), "\n")
+			_, stderr, err := run(ctx, payload, sshKeygenProgram, "-Y", "verify", "-f", c.AllowedSignersFile, "-I", principal, "-n", sshNamespace, "-s", sigFile)
+			if err != nil {
+				return Verification{Status: StatusBad, Signer: principal, Detail: strings.TrimSpace(string(stderr))}
+			}
+			return Verification{Status: StatusGood, Signer: principal}
+		}
+	}
+
+	// the signer isn't trusted, but the signature can still be checked against the commit
+	_, stderr, err := run(ctx, payload, sshKeygenProgram, "-Y", "check-novalidate", "-n", sshNamespace, "-s", sigFile)
+	if notRun(err) {
+		return Verification{Status: StatusUnknown, Detail: err.Error()}
+	} else if err != nil {
+		return Verification{Status: StatusBad, Detail: strings.TrimSpace(string(stderr))}
+	}
+	detail := "no allowed signers file is configured in gpg.ssh.allowedsignersfile"
+	if c.AllowedSignersFile != "" {
+		detail = "the key which made the signature is not in the allowed signers file"
+	}
+	return Verification{Status: StatusUnknown, Detail: detail}
+}
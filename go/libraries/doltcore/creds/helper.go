@@ -0,0 +1,178 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creds
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// CredentialHelperTimeout bounds how long a credential helper may run.
+var CredentialHelperTimeout = time.Minute
+
+// ErrNoHelperCredentials is returned by CredentialHelper.Get when the helper has no credentials for a request.
+var ErrNoHelperCredentials = errors.New("credential helper returned no credentials")
+
+// CredentialHelper is an external program which provides credentials for remotes, configured with the
+// credential.helper config key. It follows the protocol of git credential helpers: the helper is run with the
+// argument "get", is given the attributes of a CredentialRequest as key=value lines on stdin, and prints the attributes
+// of the HelperCredentials on stdout in the same format.
+//
+// Like git, a helper starting with "!" is run by the shell, a helper which is a path is run directly, and any other
+// helper "foo" runs the program "dolt-credential-foo" from the PATH. Any words following the helper's name are passed
+// to it as arguments before "get".
+type CredentialHelper string
+
+// CredentialRequest describes the remote which credentials are requested for.
+type CredentialRequest struct {
+	// Protocol is "https" or "http" for remotesapi servers such as DoltHub, and "aws" for AWS remotes.
+	Protocol string
+	Host     string
+	Path     string
+	// Username is set when the user asked for a specific user, e.g. with --user.
+	Username string
+}
+
+// HelperCredentials are the credentials returned by a credential helper. Helpers for remotesapi servers return either
+// a username and password or a bearer token, and helpers for AWS remotes return an AWS access key.
+type HelperCredentials struct {
+	Username           string
+	Password           string
+	Token              string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+}
+
+// Get runs the helper to get credentials for |req|.
+func (h CredentialHelper) Get(ctx context.Context, req CredentialRequest) (HelperCredentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, CredentialHelperTimeout)
+	defer cancel()
+	cmd, err := h.command(ctx, "get")
+	if err != nil {
+		return HelperCredentials{}, err
+	}
+
+	var stdin bytes.Buffer
+	for _, kv := range [][2]string{{"protocol", req.Protocol}, {"host", req.Host}, {"path", req.Path}, {"username", req.Username}} {
+		if kv[1] != "" {
+			fmt.Fprintf(&stdin, "%s=%s\n", kv[0], kv[1])
+		}
+	}
+	stdin.WriteString("\n")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = &stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return HelperCredentials{}, fmt.Errorf("credential helper '%s' failed: %w: %s", string(h), err, strings.TrimSpace(stderr.String()))
+	}
+
+	var res HelperCredentials
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return HelperCredentials{}, fmt.Errorf("credential helper '%s' returned an invalid line: %q", string(h), line)
+		}
+		switch key {
+		case "username":
+			res.Username = val
+		case "password":
+			res.Password = val
+		case "token":
+			res.Token = val
+		case "aws_access_key_id":
+			res.AWSAccessKeyID = val
+		case "aws_secret_access_key":
+			res.AWSSecretAccessKey = val
+		case "aws_session_token":
+			res.AWSSessionToken = val
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return HelperCredentials{}, err
+	}
+	if res.Password == "" && res.Token == "" && res.AWSSecretAccessKey == "" {
+		return HelperCredentials{}, ErrNoHelperCredentials
+	}
+	if res.Username == "" {
+		res.Username = req.Username
+	}
+	return res, nil
+}
+
+func (h CredentialHelper) command(ctx context.Context, action string) (*exec.Cmd, error) {
+	helper := strings.TrimSpace(string(h))
+	if helper == "" {
+		return nil, errors.New("credential helper is empty")
+	}
+	if strings.HasPrefix(helper, "!") {
+		script := helper[1:] + " " + action
+		if runtime.GOOS == "windows" {
+			return exec.CommandContext(ctx, "cmd", "/C", script), nil
+		}
+		return exec.CommandContext(ctx, "sh", "-c", script), nil
+	}
+	fields := strings.Fields(helper)
+	name := fields[0]
+	if !filepath.IsAbs(name) && !strings.ContainsAny(name, `/\`) {
+		name = "dolt-credential-" + name
+	}
+	return exec.CommandContext(ctx, name, append(fields[1:], action)...), nil
+}
+
+// RPCCredsForToken authenticates requests to a remotesapi server with a bearer token. Tokens are only sent over TLS
+// if RequireTLS is set.
+type RPCCredsForToken struct {
+	RequireTLS bool
+	Token      string
+}
+
+func (c *RPCCredsForToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + c.Token,
+	}, nil
+}
+
+func (c *RPCCredsForToken) RequireTransportSecurity() bool {
+	return c.RequireTLS
+}
+
+// RPCCreds returns the per-RPC credentials for the credentials returned by a helper.
+func (hc HelperCredentials) RPCCreds() (credentials.PerRPCCredentials, error) {
+	if hc.Token != "" {
+		return &RPCCredsForToken{Token: hc.Token, RequireTLS: true}, nil
+	}
+	if hc.Username == "" || hc.Password == "" {
+		return nil, errors.New("credential helper must return a token, or a username and password")
+	}
+	return DoltCredsForPass{Username: hc.Username, Password: hc.Password}.RPCCreds(), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creds
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helpers in this test are shell scripts")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "dolt-credential-test")
+	// echoes the request back as the username, and its arguments as the password
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"username=$(grep -v '^$' | tr '\\n' ' ')\"\necho \"password=$*\"\n"), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	req := CredentialRequest{Protocol: "https", Host: "doltremoteapi.dolthub.com", Username: "alice"}
+	expectedUser := "protocol=https host=doltremoteapi.dolthub.com username=alice "
+
+	t.Run("name", func(t *testing.T) {
+		hc, err := CredentialHelper("test --flag").Get(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, HelperCredentials{Username: expectedUser, Password: "--flag get"}, hc)
+	})
+	t.Run("path", func(t *testing.T) {
+		hc, err := CredentialHelper(script).Get(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, HelperCredentials{Username: expectedUser, Password: "get"}, hc)
+	})
+	t.Run("shell", func(t *testing.T) {
+		hc, err := CredentialHelper("!f() { echo token=abc; }; f").Get(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, HelperCredentials{Username: "alice", Token: "abc"}, hc)
+		rpcCreds, err := hc.RPCCreds()
+		require.NoError(t, err)
+		md, err := rpcCreds.GetRequestMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer abc", md["authorization"])
+		assert.True(t, rpcCreds.RequireTransportSecurity())
+	})
+	t.Run("aws", func(t *testing.T) {
+		hc, err := CredentialHelper("!printf 'aws_access_key_id=id\\naws_secret_access_key=secret\\naws_session_token=tok\\n'; true").Get(ctx, CredentialRequest{Protocol: "aws", Host: "table:bucket"})
+		require.NoError(t, err)
+		assert.Equal(t, HelperCredentials{AWSAccessKeyID: "id", AWSSecretAccessKey: "secret", AWSSessionToken: "tok"}, hc)
+	})
+	t.Run("no credentials", func(t *testing.T) {
+		_, err := CredentialHelper("!true").Get(ctx, req)
+		assert.ErrorIs(t, err, ErrNoHelperCredentials)
+	})
+	t.Run("invalid output", func(t *testing.T) {
+		_, err := CredentialHelper("!echo garbage; true").Get(ctx, req)
+		assert.ErrorContains(t, err, "invalid line")
+	})
+	t.Run("failure", func(t *testing.T) {
+		_, err := CredentialHelper("!echo oops >&2; exit 1; true").Get(ctx, req)
+		assert.ErrorContains(t, err, "oops")
+	})
+	t.Run("missing", func(t *testing.T) {
+		_, err := CredentialHelper("does-not-exist").Get(ctx, req)
+		assert.Error(t, err)
+	})
+}
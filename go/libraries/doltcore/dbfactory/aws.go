@@ -28,6 +28,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/s3"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
 	"github.com/dolthub/dolt/go/libraries/utils/awsrefreshcreds"
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/datas"
@@ -53,7 +54,7 @@ const (
 
 var AWSFileCredsRefreshDuration = time.Minute
 
-var AWSCredTypes = []string{RoleCS.String(), EnvCS.String(), FileCS.String()}
+var AWSCredTypes = []string{RoleCS.String(), EnvCS.String(), FileCS.String(), HelperCS.String()}
 
 // AWSCredentialSource is an enum type representing the different credential sources (auto, role, env, file, or invalid)
 type AWSCredentialSource int
@@ -72,6 +73,9 @@ const (
 
 	// Uses credentials stored in a file
 	FileCS
+
+	// Uses credentials returned by the credential helper configured with credential.helper
+	HelperCS
 )
 
 // String returns the string representation of the of an AWSCredentialSource
@@ -85,6 +89,8 @@ func (ct AWSCredentialSource) String() string {
 		return "auto"
 	case FileCS:
 		return "file"
+	case HelperCS:
+		return "helper"
 	default:
 		return "invalid"
 	}
@@ -102,6 +108,8 @@ func AWSCredentialSourceFromStr(str string) AWSCredentialSource {
 		return EnvCS
 	case "file":
 		return FileCS
+	case "helper":
+		return HelperCS
 	default:
 		return InvalidCS
 	}
@@ -126,7 +134,7 @@ func (fact AWSFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, u
 	}
 
 	vrw := types.NewValueStore(cs)
-	ns := tree.NewNodeStore(cs)
+	ns := tree.NewNodeStoreForDatabase(cs, urlObj.String(), true)
 	db = datas.NewTypesDatabase(vrw, ns)
 
 	return db, vrw, ns, nil
@@ -138,7 +146,7 @@ func (fact AWSFactory) newChunkStore(ctx context.Context, nbf *types.NomsBinForm
 		return nil, errors.New("aws url has an invalid format")
 	}
 
-	opts, err := awsConfigFromParams(params)
+	opts, err := awsConfigFromParams(ctx, urlObj, params)
 
 	if err != nil {
 		return nil, err
@@ -178,7 +186,7 @@ func validatePath(path string) (string, error) {
 	return path, nil
 }
 
-func awsConfigFromParams(params map[string]interface{}) (session.Options, error) {
+func awsConfigFromParams(ctx context.Context, urlObj *url.URL, params map[string]interface{}) (session.Options, error) {
 	awsConfig := aws.NewConfig()
 	if val, ok := params[AWSRegionParam]; ok {
 		awsConfig = awsConfig.WithRegion(val.(string))
@@ -237,6 +245,17 @@ func awsConfigFromParams(params map[string]interface{}) (session.Options, error)
 
 			// if file and env do not return valid credentials use the default credentials of the box (same as role)
 		}
+	case HelperCS:
+		helperProvider, ok := params[GRPCDialProviderParam].(CredentialHelperProvider)
+		if !ok || helperProvider.CredentialHelper() == "" {
+			return opts, errors.New("aws-creds-type helper requires the credential.helper config to be set")
+		}
+		provider := &helperCredentialsProvider{
+			ctx:    ctx,
+			helper: helperProvider.CredentialHelper(),
+			req:    creds.CredentialRequest{Protocol: "aws", Host: urlObj.Host, Path: strings.TrimPrefix(urlObj.Path, "/")},
+		}
+		awsConfig = awsConfig.WithCredentials(credentials.NewCredentials(awsrefreshcreds.NewRefreshingCredentialsProvider(provider, AWSFileCredsRefreshDuration)))
 	case RoleCS:
 	default:
 	}
@@ -245,3 +264,38 @@ func awsConfigFromParams(params map[string]interface{}) (session.Options, error)
 
 	return opts, nil
 }
+
+// CredentialHelperProvider is implemented by GRPCDialProviders which can provide the credential helper configured
+// by the user, for use by remotes which are not accessed through gRPC.
+type CredentialHelperProvider interface {
+	CredentialHelper() creds.CredentialHelper
+}
+
+// helperCredentialsProvider is an AWS credentials.Provider for the AWS access key returned by a credential helper.
+type helperCredentialsProvider struct {
+	ctx    context.Context
+	helper creds.CredentialHelper
+	req    creds.CredentialRequest
+}
+
+var _ credentials.Provider = (*helperCredentialsProvider)(nil)
+
+func (p *helperCredentialsProvider) Retrieve() (credentials.Value, error) {
+	hc, err := p.helper.Get(p.ctx, p.req)
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	if hc.AWSAccessKeyID == "" || hc.AWSSecretAccessKey == "" {
+		return credentials.Value{}, errors.New("credential helper must return aws_access_key_id and aws_secret_access_key for aws remotes")
+	}
+	return credentials.Value{
+		AccessKeyID:     hc.AWSAccessKeyID,
+		SecretAccessKey: hc.AWSSecretAccessKey,
+		SessionToken:    hc.AWSSessionToken,
+		ProviderName:    "DoltCredentialHelper",
+	}, nil
+}
+
+func (p *helperCredentialsProvider) IsExpired() bool {
+	return false
+}
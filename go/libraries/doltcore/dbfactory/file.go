@@ -180,7 +180,7 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 	// metrics?
 
 	vrw := types.NewValueStore(st)
-	ns := tree.NewNodeStore(st)
+	ns := tree.NewNodeStoreForDatabase(st, urlObj.Path, false)
 	ddb := datas.NewTypesDatabase(vrw, ns)
 
 	singletons[urlObj.Path] = singletonDB{
@@ -39,6 +39,11 @@ type GRPCRemoteConfig struct {
 	Endpoint    string
 	DialOptions []grpc.DialOption
 	HTTPFetcher grpcendpoint.HTTPFetcher
+
+	// MaxConcurrentDownloads and MaxConcurrentUploads limit the number of table file transfers made at once, if they
+	// are non-zero.
+	MaxConcurrentDownloads int
+	MaxConcurrentUploads   int
 }
 
 // GRPCDialProvider is an interface for getting a concrete Endpoint,
@@ -132,6 +137,15 @@ func (fact DoltRemoteFactory) newChunkStore(ctx context.Context, nbf *types.Noms
 		return nil, fmt.Errorf("could not access dolt url '%s': %w", urlObj.String(), err)
 	}
 	cs = cs.WithHTTPFetcher(cfg.HTTPFetcher)
+	if cfg.MaxConcurrentDownloads > 0 || cfg.MaxConcurrentUploads > 0 {
+		reqParams := cs.NetworkRequestParams()
+		if cfg.MaxConcurrentDownloads > 0 {
+			reqParams.MaximumConcurrentDownloads = cfg.MaxConcurrentDownloads
+			reqParams.StartingConcurrentDownloads = min(reqParams.StartingConcurrentDownloads, cfg.MaxConcurrentDownloads)
+		}
+		reqParams.MaximumConcurrentUploads = cfg.MaxConcurrentUploads
+		cs = cs.WithNetworkRequestParams(reqParams)
+	}
 	cs.SetFinalizer(conn.Close)
 
 	if _, ok := params[NoCachingParameter]; ok {
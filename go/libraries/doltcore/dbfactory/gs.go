@@ -55,7 +55,7 @@ func (fact GSFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, ur
 	}
 
 	vrw := types.NewValueStore(gcsStore)
-	ns := tree.NewNodeStore(gcsStore)
+	ns := tree.NewNodeStoreForDatabase(gcsStore, urlObj.String(), true)
 	db = datas.NewTypesDatabase(vrw, ns)
 
 	return db, vrw, ns, nil
@@ -60,7 +60,7 @@ func (fact OCIFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, u
 	}
 
 	vrw := types.NewValueStore(ociStore)
-	ns := tree.NewNodeStore(ociStore)
+	ns := tree.NewNodeStoreForDatabase(ociStore, urlObj.String(), true)
 	db = datas.NewTypesDatabase(vrw, ns)
 
 	return db, vrw, ns, nil
@@ -74,7 +74,7 @@ func (fact OSSFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, u
 	}
 
 	vrw := types.NewValueStore(ossStore)
-	ns := tree.NewNodeStore(ossStore)
+	ns := tree.NewNodeStoreForDatabase(ossStore, urlObj.String(), true)
 	db := datas.NewTypesDatabase(vrw, ns)
 
 	return db, vrw, ns, nil
@@ -32,6 +32,7 @@ const (
 	EnvDisableChunkJournal           = "DOLT_DISABLE_CHUNK_JOURNAL"
 	EnvDisableReflog                 = "DOLT_DISABLE_REFLOG"
 	EnvReflogRecordLimit             = "DOLT_REFLOG_RECORD_LIMIT"
+	EnvJournalMaxSize                = "DOLT_JOURNAL_MAX_SIZE"
 	EnvOssEndpoint                   = "OSS_ENDPOINT"
 	EnvOssAccessKeyID                = "OSS_ACCESS_KEY_ID"
 	EnvOssAccessKeySecret            = "OSS_ACCESS_KEY_SECRET"
@@ -43,4 +44,11 @@ const (
 	EnvDoltAuthorDate                = "DOLT_AUTHOR_DATE"
 	EnvDoltCommitterDate             = "DOLT_COMMITTER_DATE"
 	EnvDbNameReplace                 = "DOLT_DBNAME_REPLACE"
+	EnvNodeCacheSize                 = "DOLT_NODE_CACHE_SIZE"
+	EnvNodeCachePartitionSize        = "DOLT_NODE_CACHE_PARTITION_SIZE"
+	EnvNodeCacheDiskDir              = "DOLT_NODE_CACHE_DISK_DIR"
+	EnvNodeCacheDiskSize             = "DOLT_NODE_CACHE_DISK_SIZE"
+	EnvSqlPager                      = "DOLT_SQL_PAGER"
+	EnvIndexKeyPrefixCompression     = "DOLT_INDEX_KEY_PREFIX_COMPRESSION"
+	EnvExternalConnectionPrefix      = "DOLT_EXTERNAL_CONNECTION_"
 )
@@ -48,6 +48,7 @@ const (
 	ModeLine    Mode = 1
 	ModeInPlace Mode = 2
 	ModeContext Mode = 3
+	ModeWord    Mode = 4
 )
 
 type RowDiffer interface {
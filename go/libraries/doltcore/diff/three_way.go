@@ -0,0 +1,449 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// ErrThreeWayPrimaryKeyChange is returned when computing the row diffs of a table whose primary key differs between
+// the base, left and right sides of a three-way diff.
+var ErrThreeWayPrimaryKeyChange = errors.New("cannot diff rows of a table whose primary key differs between sides of a three-way diff")
+
+// ThreeWayTableDelta represents the changes made to a single table on two sides of a three-way diff, relative to
+// their common base, eg. the two branches of a merge and their merge base. Tables are matched across the three roots
+// by name. The Table and Schema of a side are nil if the table does not exist on that side.
+type ThreeWayTableDelta struct {
+	Name                       doltdb.TableName
+	BaseTable, LeftTable       *doltdb.Table
+	RightTable                 *doltdb.Table
+	BaseSch, LeftSch, RightSch schema.Schema
+}
+
+// GetThreeWayTableDeltas returns a ThreeWayTableDelta for each table that was added, dropped or changed on either
+// the |left| or |right| roots relative to the |base| root, ordered by table name.
+func GetThreeWayTableDeltas(ctx context.Context, base, left, right doltdb.RootValue) ([]ThreeWayTableDelta, error) {
+	names := make(map[string]struct{})
+	for _, root := range []doltdb.RootValue{base, left, right} {
+		tableNames, err := root.GetTableNames(ctx, doltdb.DefaultSchemaName)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range tableNames {
+			names[name] = struct{}{}
+		}
+	}
+
+	var deltas []ThreeWayTableDelta
+	for name := range names {
+		td := ThreeWayTableDelta{Name: doltdb.TableName{Name: name}}
+		var hashes [3]string
+		for i, root := range []doltdb.RootValue{base, left, right} {
+			tbl, ok, err := root.GetTable(ctx, td.Name)
+			if err != nil {
+				return nil, err
+			} else if !ok {
+				continue
+			}
+			sch, err := tbl.GetSchema(ctx)
+			if err != nil {
+				return nil, err
+			}
+			h, err := tbl.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			hashes[i] = h.String()
+
+			switch i {
+			case 0:
+				td.BaseTable, td.BaseSch = tbl, sch
+			case 1:
+				td.LeftTable, td.LeftSch = tbl, sch
+			case 2:
+				td.RightTable, td.RightSch = tbl, sch
+			}
+		}
+		if hashes[0] == hashes[1] && hashes[0] == hashes[2] {
+			continue
+		}
+		deltas = append(deltas, td)
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Name.Name < deltas[j].Name.Name
+	})
+	return deltas, nil
+}
+
+// ThreeWayColumnDiff is the result of comparing a column on two sides of a three-way diff with the column in their
+// base. Columns are matched by tag.
+type ThreeWayColumnDiff struct {
+	Tag                     uint64
+	Base, Left, Right       *schema.Column
+	LeftChange, RightChange SchemaChangeType
+}
+
+// IsConflict returns true if the column was changed on both sides, and the changes are not the same.
+func (d ThreeWayColumnDiff) IsConflict() bool {
+	if d.LeftChange == SchDiffNone || d.RightChange == SchDiffNone {
+		return false
+	}
+	if d.Left == nil || d.Right == nil {
+		return d.Left != d.Right
+	}
+	return !d.Left.Equals(*d.Right)
+}
+
+// SchemaDiffs returns a ThreeWayColumnDiff for each column in any of the schemas of this table, ordered by the
+// base, then left, then right schemas.
+func (td ThreeWayTableDelta) SchemaDiffs() []ThreeWayColumnDiff {
+	base, left, right := orEmptySchema(td.BaseSch), orEmptySchema(td.LeftSch), orEmptySchema(td.RightSch)
+	leftDiffs, leftTags := DiffSchColumns(base, left)
+	rightDiffs, rightTags := DiffSchColumns(base, right)
+
+	var diffs []ThreeWayColumnDiff
+	seen := make(map[uint64]struct{})
+	for _, tag := range append(leftTags, rightTags...) {
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+
+		d := ThreeWayColumnDiff{Tag: tag}
+		if ld, ok := leftDiffs[tag]; ok {
+			d.Base, d.Left, d.LeftChange = ld.Old, ld.New, ld.DiffType
+		}
+		if rd, ok := rightDiffs[tag]; ok {
+			d.Base, d.Right, d.RightChange = rd.Old, rd.New, rd.DiffType
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
+
+func orEmptySchema(sch schema.Schema) schema.Schema {
+	if sch == nil {
+		return schema.EmptySchema
+	}
+	return sch
+}
+
+// RowChangeType describes how a row changed on one side of a three-way diff.
+type RowChangeType int
+
+const (
+	// RowUnchanged is the RowChangeType of a row which is the same as the base
+	RowUnchanged RowChangeType = iota
+	// RowAdded is the RowChangeType of a row which is not in the base
+	RowAdded
+	// RowRemoved is the RowChangeType of a row which is in the base, but was removed
+	RowRemoved
+	// RowModified is the RowChangeType of a row which is in the base, but has changed
+	RowModified
+)
+
+// ThreeWayRowDiff describes a row that was changed on at least one side of a three-way diff. Rows are in the
+// column order of the schema of their side, and are nil if the row does not exist on that side.
+type ThreeWayRowDiff struct {
+	// Key is the primary key of the row, or nil for keyless tables
+	Key                     sql.Row
+	Base, Left, Right       sql.Row
+	LeftChange, RightChange RowChangeType
+	// Conflict is true if the row was changed on both sides, and the changes are not the same
+	Conflict bool
+}
+
+// ThreeWayRowDiffIter iterates over the ThreeWayRowDiffs of a table, in primary key order.
+type ThreeWayRowDiffIter struct {
+	left, right        tree.Differ[val.Tuple, val.TupleDesc]
+	lDiff, rDiff       tree.Diff
+	lDone, rDone       bool
+	started            bool
+	keyDesc            val.TupleDesc
+	base, lSide, rSide rowSide
+	sameValues         bool
+	keyless            bool
+	ns                 tree.NodeStore
+}
+
+type rowSide struct {
+	sch schema.Schema
+	vd  val.TupleDesc
+}
+
+// RowDiffs returns an iterator over the rows of this table that were changed on either side of the three-way diff.
+// An error is returned if the table's primary key differs between sides, or if the table is not stored in the
+// __DOLT__ format.
+func (td ThreeWayTableDelta) RowDiffs(ctx context.Context) (*ThreeWayRowDiffIter, error) {
+	var keyDesc val.TupleDesc
+	var keyless bool
+	var ns tree.NodeStore
+	for _, side := range []struct {
+		tbl *doltdb.Table
+		sch schema.Schema
+	}{{td.BaseTable, td.BaseSch}, {td.LeftTable, td.LeftSch}, {td.RightTable, td.RightSch}} {
+		if side.tbl == nil {
+			continue
+		}
+		if !types.IsFormat_DOLT(side.tbl.Format()) {
+			return nil, fmt.Errorf("three-way row diffs are not supported for format %s", side.tbl.Format().VersionString())
+		}
+		kd := side.sch.GetKeyDescriptor()
+		if ns == nil {
+			keyDesc, keyless, ns = kd, schema.IsKeyless(side.sch), side.tbl.NodeStore()
+		} else if !keyDesc.Equals(kd) || keyless != schema.IsKeyless(side.sch) {
+			return nil, ErrThreeWayPrimaryKeyChange
+		}
+	}
+
+	base, baseMap, err := rowDataForSide(ctx, td.BaseTable, td.BaseSch, keyDesc, ns)
+	if err != nil {
+		return nil, err
+	}
+	left, leftMap, err := rowDataForSide(ctx, td.LeftTable, td.LeftSch, keyDesc, ns)
+	if err != nil {
+		return nil, err
+	}
+	right, rightMap, err := rowDataForSide(ctx, td.RightTable, td.RightSch, keyDesc, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	// if the values of a side are encoded differently from the base, every row on that side is compared as modified
+	ld, err := tree.DifferFromRoots[val.Tuple](ctx, ns, ns, baseMap.Node(), leftMap.Node(), keyDesc, !base.vd.Equals(left.vd))
+	if err != nil {
+		return nil, err
+	}
+	rd, err := tree.DifferFromRoots[val.Tuple](ctx, ns, ns, baseMap.Node(), rightMap.Node(), keyDesc, !base.vd.Equals(right.vd))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ThreeWayRowDiffIter{
+		left:       ld,
+		right:      rd,
+		keyDesc:    keyDesc,
+		base:       base,
+		lSide:      left,
+		rSide:      right,
+		sameValues: left.vd.Equals(right.vd),
+		keyless:    keyless,
+		ns:         ns,
+	}, nil
+}
+
+func rowDataForSide(ctx context.Context, tbl *doltdb.Table, sch schema.Schema, kd val.TupleDesc, ns tree.NodeStore) (rowSide, prolly.Map, error) {
+	if tbl == nil {
+		m, err := prolly.NewMapFromTuples(ctx, ns, kd, val.TupleDesc{})
+		return rowSide{}, m, err
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return rowSide{}, prolly.Map{}, err
+	}
+	return rowSide{sch: sch, vd: sch.GetValueDescriptor()}, durable.ProllyMapFromIndex(idx), nil
+}
+
+// Next returns the next ThreeWayRowDiff, or io.EOF once every changed row has been returned.
+func (it *ThreeWayRowDiffIter) Next(ctx context.Context) (ThreeWayRowDiff, error) {
+	if err := ctx.Err(); err != nil {
+		return ThreeWayRowDiff{}, err
+	}
+
+	var err error
+	if !it.started {
+		it.started = true
+		if it.lDiff, it.lDone, err = nextDiff(ctx, it.left); err != nil {
+			return ThreeWayRowDiff{}, err
+		}
+		if it.rDiff, it.rDone, err = nextDiff(ctx, it.right); err != nil {
+			return ThreeWayRowDiff{}, err
+		}
+	}
+
+	var l, r *tree.Diff
+	switch {
+	case it.lDone && it.rDone:
+		return ThreeWayRowDiff{}, io.EOF
+	case it.rDone:
+		l = &it.lDiff
+	case it.lDone:
+		r = &it.rDiff
+	default:
+		cmp := it.keyDesc.Compare(val.Tuple(it.lDiff.Key), val.Tuple(it.rDiff.Key))
+		if cmp <= 0 {
+			l = &it.lDiff
+		}
+		if cmp >= 0 {
+			r = &it.rDiff
+		}
+	}
+
+	res, err := it.makeRowDiff(ctx, l, r)
+	if err != nil {
+		return ThreeWayRowDiff{}, err
+	}
+
+	if l != nil {
+		if it.lDiff, it.lDone, err = nextDiff(ctx, it.left); err != nil {
+			return ThreeWayRowDiff{}, err
+		}
+	}
+	if r != nil {
+		if it.rDiff, it.rDone, err = nextDiff(ctx, it.right); err != nil {
+			return ThreeWayRowDiff{}, err
+		}
+	}
+	return res, nil
+}
+
+// Close releases the resources held by this iterator.
+func (it *ThreeWayRowDiffIter) Close(ctx context.Context) error {
+	return nil
+}
+
+func nextDiff(ctx context.Context, d tree.Differ[val.Tuple, val.TupleDesc]) (tree.Diff, bool, error) {
+	diff, err := d.Next(ctx)
+	if errors.Is(err, io.EOF) {
+		return tree.Diff{}, true, nil
+	} else if err != nil {
+		return tree.Diff{}, false, err
+	}
+	return diff, false, nil
+}
+
+// makeRowDiff builds the ThreeWayRowDiff for a key changed on the left side (|l|), the right side (|r|), or both.
+func (it *ThreeWayRowDiffIter) makeRowDiff(ctx context.Context, l, r *tree.Diff) (res ThreeWayRowDiff, err error) {
+	var key, base, left, right val.Tuple
+	if l != nil {
+		key, base = val.Tuple(l.Key), val.Tuple(l.From)
+		left = val.Tuple(l.To)
+		res.LeftChange = rowChangeType(l.Type)
+	}
+	if r != nil {
+		key, base = val.Tuple(r.Key), val.Tuple(r.From)
+		right = val.Tuple(r.To)
+		res.RightChange = rowChangeType(r.Type)
+	}
+	// a side without a diff is unchanged from the base
+	if l == nil {
+		left = base
+	}
+	if r == nil {
+		right = base
+	}
+
+	if l != nil && r != nil {
+		res.Conflict = !(l.Type == r.Type && it.sameValues && bytes.Equal(left, right))
+	}
+
+	if !it.keyless {
+		if res.Key, err = it.keyToRow(ctx, key); err != nil {
+			return ThreeWayRowDiff{}, err
+		}
+	}
+	if res.Base, err = it.tupleToRow(ctx, it.base, key, base); err != nil {
+		return ThreeWayRowDiff{}, err
+	}
+	if res.Left, err = it.tupleToRow(ctx, it.lSide, key, left); err != nil {
+		return ThreeWayRowDiff{}, err
+	}
+	if res.Right, err = it.tupleToRow(ctx, it.rSide, key, right); err != nil {
+		return ThreeWayRowDiff{}, err
+	}
+	return res, nil
+}
+
+// keyToRow converts a primary |key| tuple to a sql.Row in primary key order.
+func (it *ThreeWayRowDiffIter) keyToRow(ctx context.Context, key val.Tuple) (sql.Row, error) {
+	row := make(sql.Row, it.keyDesc.Count())
+	for i := range row {
+		v, err := tree.GetField(ctx, it.keyDesc, i, key, it.ns)
+		if err != nil {
+			return nil, err
+		}
+		row[i] = v
+	}
+	return row, nil
+}
+
+func rowChangeType(t tree.DiffType) RowChangeType {
+	switch t {
+	case tree.AddedDiff:
+		return RowAdded
+	case tree.RemovedDiff:
+		return RowRemoved
+	case tree.ModifiedDiff:
+		return RowModified
+	default:
+		return RowUnchanged
+	}
+}
+
+// tupleToRow converts the |key| and |value| tuples of a row on |side| to a sql.Row in the column order of its schema.
+// It returns nil if the row does not exist on that side. Virtual columns are not stored, and are returned as nil.
+func (it *ThreeWayRowDiffIter) tupleToRow(ctx context.Context, side rowSide, key, value val.Tuple) (sql.Row, error) {
+	if side.sch == nil || value == nil {
+		return nil, nil
+	}
+
+	allCols := side.sch.GetAllCols()
+	row := make(sql.Row, allCols.Size())
+	if !it.keyless {
+		for i, col := range side.sch.GetPKCols().GetColumns() {
+			v, err := tree.GetField(ctx, it.keyDesc, i, key, it.ns)
+			if err != nil {
+				return nil, err
+			}
+			row[allCols.TagToIdx[col.Tag]] = v
+		}
+	}
+
+	// the first field of a keyless value tuple is the row's cardinality
+	offset := 0
+	if it.keyless {
+		offset = 1
+	}
+	nonPKCols := side.sch.GetNonPKCols()
+	for _, col := range nonPKCols.GetColumns() {
+		// virtual columns aren't stored, and stored dropped columns aren't part of rows
+		pos, ok := nonPKCols.StoredIndexByTag(col.Tag)
+		if !ok || col.Virtual {
+			continue
+		}
+		v, err := tree.GetField(ctx, side.vd, offset+pos, value, it.ns)
+		if err != nil {
+			return nil, err
+		}
+		row[allCols.TagToIdx[col.Tag]] = v
+	}
+	return row, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+func TestThreeWayDiff(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	base := execSql(t, dEnv, nil, `
+		create table t (pk int primary key, a int, b varchar(10));
+		create table unchanged (pk int primary key);
+		insert into t values (1, 1, 'a'), (2, 2, 'b'), (3, 3, 'c'), (4, 4, 'd');`)
+	left := execSql(t, dEnv, base, `
+		update t set a = 10 where pk = 1;
+		delete from t where pk = 2;
+		update t set a = 40 where pk = 4;
+		insert into t values (5, 5, 'e');
+		create table added (pk int primary key);`)
+	right := execSql(t, dEnv, base, `
+		update t set b = 'x' where pk = 3;
+		update t set a = 41 where pk = 4;
+		insert into t values (5, 5, 'e');`)
+
+	deltas, err := diff.GetThreeWayTableDeltas(ctx, base, left, right)
+	require.NoError(t, err)
+	require.Len(t, deltas, 2)
+	assert.Equal(t, "added", deltas[0].Name.Name)
+	assert.Nil(t, deltas[0].BaseTable)
+	assert.NotNil(t, deltas[0].LeftTable)
+	assert.Nil(t, deltas[0].RightTable)
+	assert.Equal(t, "t", deltas[1].Name.Name)
+
+	t.Run("row diffs", func(t *testing.T) {
+		iter, err := deltas[1].RowDiffs(ctx)
+		require.NoError(t, err)
+		defer iter.Close(ctx)
+
+		expected := []diff.ThreeWayRowDiff{
+			{
+				Key:        sql.Row{int32(1)},
+				Base:       sql.Row{int32(1), int32(1), "a"},
+				Left:       sql.Row{int32(1), int32(10), "a"},
+				Right:      sql.Row{int32(1), int32(1), "a"},
+				LeftChange: diff.RowModified,
+			},
+			{
+				Key:        sql.Row{int32(2)},
+				Base:       sql.Row{int32(2), int32(2), "b"},
+				Right:      sql.Row{int32(2), int32(2), "b"},
+				LeftChange: diff.RowRemoved,
+			},
+			{
+				Key:         sql.Row{int32(3)},
+				Base:        sql.Row{int32(3), int32(3), "c"},
+				Left:        sql.Row{int32(3), int32(3), "c"},
+				Right:       sql.Row{int32(3), int32(3), "x"},
+				RightChange: diff.RowModified,
+			},
+			{
+				Key:         sql.Row{int32(4)},
+				Base:        sql.Row{int32(4), int32(4), "d"},
+				Left:        sql.Row{int32(4), int32(40), "d"},
+				Right:       sql.Row{int32(4), int32(41), "d"},
+				LeftChange:  diff.RowModified,
+				RightChange: diff.RowModified,
+				Conflict:    true,
+			},
+			{
+				Key:         sql.Row{int32(5)},
+				Left:        sql.Row{int32(5), int32(5), "e"},
+				Right:       sql.Row{int32(5), int32(5), "e"},
+				LeftChange:  diff.RowAdded,
+				RightChange: diff.RowAdded,
+			},
+		}
+
+		var actual []diff.ThreeWayRowDiff
+		for {
+			d, err := iter.Next(ctx)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			actual = append(actual, d)
+		}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		iter, err := deltas[1].RowDiffs(ctx)
+		require.NoError(t, err)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = iter.Next(cancelled)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("schema diffs", func(t *testing.T) {
+		left := execSql(t, dEnv, base, "alter table t add column c int;")
+		right := execSql(t, dEnv, base, "alter table t modify column b varchar(20);\nalter table t drop column a;")
+		deltas, err := diff.GetThreeWayTableDeltas(ctx, base, left, right)
+		require.NoError(t, err)
+		require.Len(t, deltas, 1)
+
+		changes := make(map[string][2]diff.SchemaChangeType)
+		var conflicts int
+		for _, d := range deltas[0].SchemaDiffs() {
+			col := d.Base
+			if col == nil {
+				col = d.Left
+			}
+			changes[col.Name] = [2]diff.SchemaChangeType{d.LeftChange, d.RightChange}
+			if d.IsConflict() {
+				conflicts++
+			}
+		}
+		assert.Equal(t, map[string][2]diff.SchemaChangeType{
+			"pk": {diff.SchDiffNone, diff.SchDiffNone},
+			"a":  {diff.SchDiffNone, diff.SchDiffRemoved},
+			"b":  {diff.SchDiffNone, diff.SchDiffModified},
+			"c":  {diff.SchDiffAdded, diff.SchDiffNone},
+		}, changes)
+		assert.Zero(t, conflicts)
+	})
+
+	t.Run("primary key changes", func(t *testing.T) {
+		right := execSql(t, dEnv, base, "alter table t drop primary key;\nalter table t add primary key (pk, a);")
+		deltas, err := diff.GetThreeWayTableDeltas(ctx, base, left, right)
+		require.NoError(t, err)
+		_, err = deltas[1].RowDiffs(ctx)
+		assert.ErrorIs(t, err, diff.ErrThreeWayPrimaryKeyChange)
+	})
+}
+
+func execSql(t *testing.T, dEnv *env.DoltEnv, root doltdb.RootValue, query string) doltdb.RootValue {
+	ctx := context.Background()
+	var err error
+	if root == nil {
+		root, err = dEnv.WorkingRoot(ctx)
+		require.NoError(t, err)
+	}
+	// ExecuteSql runs against the working set, so reset it to |root| first
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, root))
+	root, err = sqle.ExecuteSql(dEnv, root, query)
+	require.NoError(t, err)
+	return root
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// assertionQueryRegex matches the start of the queries an assertion may have. Assertions are checked by running their
+// query, so they can't modify the database.
+var assertionQueryRegex = regexp.MustCompile(`(?is)^\s*(?:select|with|table|\()`)
+
+// Assertion is a row of the dolt_assertions table. An assertion holds when its query returns no rows. Otherwise, each
+// row returned by the query starts with the primary key of a row of the table |TableName| which violates it.
+type Assertion struct {
+	Name      string
+	TableName string
+	Query     string
+}
+
+// Validate returns an error if the definition of the assertion is invalid.
+func (a Assertion) Validate() error {
+	if !assertionQueryRegex.MatchString(a.Query) {
+		return fmt.Errorf("invalid assertion '%s': %s must be a SELECT, WITH or TABLE statement", a.Name, AssertionsQueryCol)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrBranchProtected is returned when a change to a protected branch is rejected.
+var ErrBranchProtected = errors.New("branch is protected")
+
+// BranchProtection is a row of the dolt_branch_protection table. Branches whose names match |BranchPattern| only accept
+// merges, and only from the users in |AllowedUsers|. Both branch patterns and users use the same wildcards as
+// dolt_ignore patterns.
+type BranchProtection struct {
+	BranchPattern string
+	AllowedUsers  []string
+}
+
+type BranchProtections []BranchProtection
+
+// GetBranchProtections reads the branch protection rules from the dolt_branch_protection table of |root|.
+func GetBranchProtections(ctx context.Context, root RootValue) (BranchProtections, error) {
+	table, found, err := root.GetTable(ctx, TableName{Name: BranchProtectionTableName})
+	if err != nil {
+		return nil, err
+	}
+	if !found || table.Format() == types.Format_LD_1 {
+		return nil, nil
+	}
+	index, err := table.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyDesc, valueDesc := sch.GetMapDescriptors()
+	if keyDesc.Count() != 1 || valueDesc.Count() != 1 {
+		return nil, fmt.Errorf("%s had unexpected schema, this should never happen", BranchProtectionTableName)
+	}
+
+	m := durable.ProllyMapFromIndex(index)
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var protections BranchProtections
+	for {
+		keyTuple, valueTuple, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pattern, ok := keyDesc.GetString(0, keyTuple)
+		if !ok {
+			return nil, fmt.Errorf("could not read branch pattern")
+		}
+		users, err := tree.GetField(ctx, valueDesc, 0, valueTuple, m.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+		protection := BranchProtection{BranchPattern: pattern}
+		if s, ok := users.(string); ok {
+			for _, user := range strings.Split(s, ",") {
+				if user = strings.TrimSpace(user); user != "" {
+					protection.AllowedUsers = append(protection.AllowedUsers, user)
+				}
+			}
+		}
+		protections = append(protections, protection)
+	}
+	return protections, nil
+}
+
+// IsProtected returns whether |branch| matches any of the branch protection rules.
+func (bp BranchProtections) IsProtected(branch string) (bool, error) {
+	for _, protection := range bp {
+		matches, err := matchesPattern(protection.BranchPattern, branch)
+		if err != nil || matches {
+			return matches, err
+		}
+	}
+	return false, nil
+}
+
+// CanMerge returns whether |user| may merge into |branch|. This is true for branches which are not protected, and for
+// users allowed by any of the rules matching a protected branch.
+func (bp BranchProtections) CanMerge(branch, user string) (bool, error) {
+	protected := false
+	for _, protection := range bp {
+		matches, err := matchesPattern(protection.BranchPattern, branch)
+		if err != nil {
+			return false, err
+		}
+		if !matches {
+			continue
+		}
+		protected = true
+		for _, allowed := range protection.AllowedUsers {
+			matches, err = matchesPattern(allowed, user)
+			if err != nil || matches {
+				return matches, err
+			}
+		}
+	}
+	return !protected, nil
+}
+
+// CheckBranchProtection returns an error wrapping ErrBranchProtected if |user| may not change |branch|. |isMerge| is
+// whether the change is a merge into the branch, since protected branches only accept merges.
+func (bp BranchProtections) CheckBranchProtection(branch, user string, isMerge bool) error {
+	canMerge, err := bp.CanMerge(branch, user)
+	if err != nil {
+		return err
+	}
+	if !canMerge {
+		return fmt.Errorf("%w: user '%s' is not allowed to change branch '%s'", ErrBranchProtected, user, branch)
+	}
+	if isMerge {
+		return nil
+	}
+	protected, err := bp.IsProtected(branch)
+	if err != nil {
+		return err
+	}
+	if protected {
+		return fmt.Errorf("%w: branch '%s' only accepts merges", ErrBranchProtected, branch)
+	}
+	return nil
+}
+
+func matchesPattern(pattern, s string) (bool, error) {
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(s), nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchProtections(t *testing.T) {
+	protections := BranchProtections{
+		{BranchPattern: "main", AllowedUsers: []string{"alice", "bob"}},
+		{BranchPattern: "release/%", AllowedUsers: []string{"release_*"}},
+		{BranchPattern: "frozen"},
+	}
+
+	tests := []struct {
+		branch    string
+		user      string
+		isMerge   bool
+		protected bool
+		canMerge  bool
+	}{
+		{"main", "alice", true, true, true},
+		{"main", "alice", false, true, true},
+		{"main", "eve", true, true, false},
+		{"release/1.0", "release_bot", true, true, true},
+		{"release/1.0", "alice", true, true, false},
+		{"frozen", "alice", true, true, false},
+		{"feature", "eve", false, false, true},
+		{"mainline", "eve", false, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.branch+"/"+test.user, func(t *testing.T) {
+			protected, err := protections.IsProtected(test.branch)
+			require.NoError(t, err)
+			assert.Equal(t, test.protected, protected)
+
+			canMerge, err := protections.CanMerge(test.branch, test.user)
+			require.NoError(t, err)
+			assert.Equal(t, test.canMerge, canMerge)
+
+			err = protections.CheckBranchProtection(test.branch, test.user, test.isMerge)
+			if test.canMerge && (test.isMerge || !test.protected) {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrBranchProtected))
+			}
+		})
+	}
+}
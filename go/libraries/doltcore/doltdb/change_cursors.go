@@ -0,0 +1,146 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/pool"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// ErrChangeCursorsUnsupported is returned when writing change cursors to a database in the old storage format.
+var ErrChangeCursorsUnsupported = errors.New("change cursors are not supported for the old storage format")
+
+// changeCursorsRef is the ref of the commit holding the change data capture cursors of the database.
+var changeCursorsRef = ref.NewInternalRef("change-cursors")
+
+// changeCursorsTableName is the name of the table holding change cursors in the root value of the change cursors
+// commit
+const changeCursorsTableName = "change_cursors"
+
+// changeCursorsSchema is the schema of the table holding change cursors
+var changeCursorsSchema = schema.MustSchemaFromCols(schema.NewColCollection(
+	schema.NewColumn("name", schema.DoltChangeCursorNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn("branch", schema.DoltChangeCursorBranchTag, types.StringKind, false, schema.NotNullConstraint{}),
+	schema.NewColumn("commit_hash", schema.DoltChangeCursorCommitTag, types.StringKind, false, schema.NotNullConstraint{}),
+	schema.NewColumn("updated_at", schema.DoltChangeCursorUpdatedAtTag, types.IntKind, false, schema.NotNullConstraint{}),
+))
+
+// ChangeCursor is the position of a consumer of the row changes made to a branch. Consumers read the changes made by
+// the commits after Commit up to the head of Branch, and then advance the cursor to the last commit they read. Like
+// branch metadata, cursors are local to a database, and aren't pushed to or fetched from remotes.
+type ChangeCursor struct {
+	// Name is the name of the cursor
+	Name string
+	// Branch is the branch whose changes are read
+	Branch string
+	// Commit is the last commit whose changes were read
+	Commit hash.Hash
+	// UpdatedAt is when the cursor was last moved
+	UpdatedAt time.Time
+}
+
+// GetChangeCursors returns every change cursor of the database, ordered by name.
+func (ddb *DoltDB) GetChangeCursors(ctx context.Context) ([]ChangeCursor, error) {
+	var ret []ChangeCursor
+	m, ok, err := ddb.refTableMap(ctx, changeCursorsRef, changeCursorsTableName)
+	if err != nil || !ok {
+		return ret, err
+	}
+
+	itr, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	kd, vd := changeCursorsSchema.GetMapDescriptors()
+	for {
+		k, v, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		var c ChangeCursor
+		c.Name, _ = kd.GetString(0, k)
+		c.Branch, _ = vd.GetString(0, v)
+		commit, _ := vd.GetString(1, v)
+		updatedAt, _ := vd.GetInt64(2, v)
+		c.Commit = hash.Parse(commit)
+		c.UpdatedAt = time.UnixMilli(updatedAt)
+		ret = append(ret, c)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret, nil
+}
+
+// GetChangeCursor returns the change cursor named |name|, and whether it exists.
+func (ddb *DoltDB) GetChangeCursor(ctx context.Context, name string) (ChangeCursor, bool, error) {
+	cursors, err := ddb.GetChangeCursors(ctx)
+	if err != nil {
+		return ChangeCursor{}, false, err
+	}
+	for _, c := range cursors {
+		if c.Name == name {
+			return c, true, nil
+		}
+	}
+	return ChangeCursor{}, false, nil
+}
+
+// SetChangeCursor records |c|, replacing any cursor with the same name. The change is committed with |meta|.
+func (ddb *DoltDB) SetChangeCursor(ctx context.Context, c ChangeCursor, meta *datas.CommitMeta) error {
+	return ddb.updateChangeCursors(ctx, meta, func(mut *prolly.MutableMap, p pool.BuffPool) error {
+		_, vd := changeCursorsSchema.GetMapDescriptors()
+		vb := val.NewTupleBuilder(vd)
+		vb.PutString(0, c.Branch)
+		vb.PutString(1, c.Commit.String())
+		vb.PutInt64(2, c.UpdatedAt.UnixMilli())
+		return mut.Put(ctx, changeCursorKey(p, c.Name), vb.Build(p))
+	})
+}
+
+// RemoveChangeCursor removes the change cursor named |name|, if it exists. The change is committed with |meta|.
+func (ddb *DoltDB) RemoveChangeCursor(ctx context.Context, name string, meta *datas.CommitMeta) error {
+	return ddb.updateChangeCursors(ctx, meta, func(mut *prolly.MutableMap, p pool.BuffPool) error {
+		return mut.Delete(ctx, changeCursorKey(p, name))
+	})
+}
+
+func changeCursorKey(p pool.BuffPool, name string) val.Tuple {
+	kd, _ := changeCursorsSchema.GetMapDescriptors()
+	kb := val.NewTupleBuilder(kd)
+	kb.PutString(0, name)
+	return kb.Build(p)
+}
+
+func (ddb *DoltDB) updateChangeCursors(ctx context.Context, meta *datas.CommitMeta, edit func(mut *prolly.MutableMap, p pool.BuffPool) error) error {
+	if !types.IsFormat_DOLT(ddb.Format()) {
+		return ErrChangeCursorsUnsupported
+	}
+	return ddb.updateRefTable(ctx, changeCursorsRef, changeCursorsTableName, changeCursorsSchema, meta, edit)
+}
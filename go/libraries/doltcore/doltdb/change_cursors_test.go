@@ -0,0 +1,74 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestChangeCursors(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse"))
+
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "change cursors")
+	require.NoError(t, err)
+
+	all, err := ddb.GetChangeCursors(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	now := time.UnixMilli(time.Now().UnixMilli())
+	warehouse := ChangeCursor{Name: "warehouse", Branch: "main", Commit: hash.Of([]byte("one")), UpdatedAt: now}
+	search := ChangeCursor{Name: "search", Branch: "feature", Commit: hash.Of([]byte("two")), UpdatedAt: now}
+	require.NoError(t, ddb.SetChangeCursor(ctx, warehouse, meta))
+	require.NoError(t, ddb.SetChangeCursor(ctx, search, meta))
+
+	warehouse.Commit = hash.Of([]byte("three"))
+	warehouse.UpdatedAt = now.Add(time.Minute)
+	require.NoError(t, ddb.SetChangeCursor(ctx, warehouse, meta))
+
+	all, err = ddb.GetChangeCursors(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ChangeCursor{search, warehouse}, all)
+
+	c, ok, err := ddb.GetChangeCursor(ctx, "warehouse")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, warehouse, c)
+
+	require.NoError(t, ddb.RemoveChangeCursor(ctx, "warehouse", meta))
+	require.NoError(t, ddb.RemoveChangeCursor(ctx, "missing", meta))
+	_, ok, err = ddb.GetChangeCursor(ctx, "warehouse")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// change cursors aren't branches
+	branches, err := ddb.GetBranches(ctx)
+	require.NoError(t, err)
+	assert.Len(t, branches, 1)
+}
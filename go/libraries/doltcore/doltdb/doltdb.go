@@ -624,8 +624,8 @@ func (ddb *DoltDB) workingSetFromDataset(ctx context.Context, workingSetRef ref.
 // value will not be associated with a commit and can be committed by hash at a
 // later time.  Returns an updated root value and the hash of the value
 // written.  This method is the primary place in doltcore that handles setting
-// the FeatureVersion of root values to the current value, so all writes of
-// RootValues should happen here.
+// the FeatureVersion of root values to the version their contents require, so
+// all writes of RootValues should happen here.
 func (ddb *DoltDB) WriteRootValue(ctx context.Context, rv RootValue) (RootValue, hash.Hash, error) {
 	nrv, ref, err := ddb.writeRootValue(ctx, rv)
 	if err != nil {
@@ -635,7 +635,11 @@ func (ddb *DoltDB) WriteRootValue(ctx context.Context, rv RootValue) (RootValue,
 }
 
 func (ddb *DoltDB) writeRootValue(ctx context.Context, rv RootValue) (RootValue, types.Ref, error) {
-	rv, err := rv.SetFeatureVersion(DoltFeatureVersion)
+	ver, err := featureVersionFor(ctx, rv)
+	if err != nil {
+		return nil, types.Ref{}, err
+	}
+	rv, err = rv.SetFeatureVersion(ver)
 	if err != nil {
 		return nil, types.Ref{}, err
 	}
@@ -1577,6 +1581,25 @@ func (ddb *DoltDB) DeleteWorkingSet(ctx context.Context, workingSetRef ref.Worki
 	return err
 }
 
+// GetWorkingSetRefsWithPrefix returns the refs of the working sets whose names begin with |prefix|, such as the
+// working sets which aren't the working sets of heads, but are stored like them.
+func (ddb *DoltDB) GetWorkingSetRefsWithPrefix(ctx context.Context, prefix string) ([]ref.WorkingSetRef, error) {
+	dss, err := ddb.db.Datasets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dsPrefix := ref.NewWorkingSetRef(prefix).String()
+	var refs []ref.WorkingSetRef
+	err = dss.IterAll(ctx, func(key string, _ hash.Hash) error {
+		if strings.HasPrefix(key, dsPrefix) {
+			refs = append(refs, ref.NewWorkingSetRef(key))
+		}
+		return nil
+	})
+	return refs, err
+}
+
 func (ddb *DoltDB) DeleteTag(ctx context.Context, tag ref.DoltRef) error {
 	err := ddb.deleteRef(ctx, tag, nil, "")
 
@@ -1630,7 +1653,12 @@ func (ddb *DoltDB) Rebase(ctx context.Context) error {
 // until no possibly-stale ChunkStore state is retained in memory, or failing
 // certain in-progress operations which cannot be finalized in a timely manner,
 // etc.
-func (ddb *DoltDB) GC(ctx context.Context, safepointF func() error) error {
+//
+// Commits which have been the head of a branch and were made at or after
+// |retainSince| are kept, even if they are no longer referenced. A zero
+// |retainSince| disables retention, and releases any commits kept by
+// previous runs.
+func (ddb *DoltDB) GC(ctx context.Context, retainSince time.Time, safepointF func() error) error {
 	collector, ok := ddb.db.Database.(datas.GarbageCollector)
 	if !ok {
 		return fmt.Errorf("this database does not support garbage collection")
@@ -1641,6 +1669,17 @@ func (ddb *DoltDB) GC(ctx context.Context, safepointF func() error) error {
 		return err
 	}
 
+	// The chunk journal, and the reflog held in it, are rewritten by the collection
+	err = ddb.archiveReflog(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = ddb.updateGCRetainedCommits(ctx, retainSince)
+	if err != nil {
+		return err
+	}
+
 	datasets, err := ddb.db.Datasets(ctx)
 	if err != nil {
 		return err
@@ -1674,7 +1713,10 @@ func (ddb *DoltDB) GC(ctx context.Context, safepointF func() error) error {
 		return err
 	}
 
-	return collector.GC(ctx, oldGen, newGen, safepointF)
+	err = collector.GC(ctx, oldGen, newGen, safepointF)
+	// cached diffs may hold trees whose chunks were collected
+	tree.PurgeDiffCache()
+	return err
 }
 
 func (ddb *DoltDB) ShallowGC(ctx context.Context) error {
@@ -1726,13 +1768,30 @@ func (ddb *DoltDB) PullChunks(
 	statsCh chan pull.Stats,
 	skipHashes hash.HashSet,
 ) error {
-	return pullHash(ctx, ddb.db, srcDB.db, targetHashes, tempDir, statsCh, skipHashes)
+	return pullHash(ctx, ddb.db, srcDB.db, targetHashes, nil, tempDir, statsCh, skipHashes)
+}
+
+// PullChunksWithDeltaBases is like PullChunks, but |deltaBases| maps target
+// hashes to earlier versions of them which this database already has, for
+// example the current values of the remote tracking branches being fetched.
+// Remotes which support it will send changed chunks as deltas against them.
+func (ddb *DoltDB) PullChunksWithDeltaBases(
+	ctx context.Context,
+	tempDir string,
+	srcDB *DoltDB,
+	targetHashes []hash.Hash,
+	deltaBases map[hash.Hash]hash.Hash,
+	statsCh chan pull.Stats,
+	skipHashes hash.HashSet,
+) error {
+	return pullHash(ctx, ddb.db, srcDB.db, targetHashes, deltaBases, tempDir, statsCh, skipHashes)
 }
 
 func pullHash(
 	ctx context.Context,
 	destDB, srcDB datas.Database,
 	targetHashes []hash.Hash,
+	deltaBases map[hash.Hash]hash.Hash,
 	tempDir string,
 	statsCh chan pull.Stats,
 	skipHashes hash.HashSet,
@@ -1749,6 +1808,7 @@ func pullHash(
 			return err
 		}
 
+		puller.SetDeltaBases(deltaBases)
 		return puller.Pull(ctx)
 	} else {
 		return errors.New("Puller not supported")
@@ -1786,6 +1846,19 @@ func (ddb *DoltDB) ChunkJournal() *nbs.ChunkJournal {
 	return nbs.ChunkJournal()
 }
 
+// JournalStats returns the JournalStats of the ChunkJournal for this DoltDB, if one is in use.
+func (ddb *DoltDB) JournalStats() (nbs.JournalStats, bool) {
+	generationalNbs, ok := datas.ChunkStoreFromDatabase(ddb.db).(*nbs.GenerationalNBS)
+	if !ok {
+		return nbs.JournalStats{}, false
+	}
+	newGen, ok := generationalNbs.NewGen().(*nbs.NomsBlockStore)
+	if !ok {
+		return nbs.JournalStats{}, false
+	}
+	return newGen.JournalStats()
+}
+
 func (ddb *DoltDB) TableFileStoreHasJournal(ctx context.Context) (bool, error) {
 	tableFileStore, ok := datas.ChunkStoreFromDatabase(ddb.db).(chunks.TableFileStore)
 	if !ok {
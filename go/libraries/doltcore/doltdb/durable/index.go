@@ -274,20 +274,20 @@ func (i prollyIndex) bytes() ([]byte, error) {
 var _ Index = prollyIndex{}
 
 func (i prollyIndex) AddColumnToRows(ctx context.Context, newCol string, newSchema schema.Schema) (Index, error) {
-	var last bool
-	colIdx, iCol := 0, 0
-	newSchema.GetNonPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-		last = false
-		if strings.ToLower(col.Name) == strings.ToLower(newCol) {
-			last = true
-			colIdx = iCol
-		}
-		iCol++
-		return false, nil
-	})
+	nonPKCols := newSchema.GetNonPKCols()
+	col, ok := nonPKCols.GetByNameCaseInsensitive(newCol)
+	if !ok {
+		return nil, fmt.Errorf("column not found: %s", newCol)
+	}
+	colIdx, ok := nonPKCols.StoredIndexByTag(col.Tag)
+	if !ok {
+		// virtual columns are not stored
+		return i, nil
+	}
 
-	// If the column we added was last among non-primary key columns we can skip this step
-	if last {
+	// If the column we added is stored last, rows written before it was added are just missing its field, which reads
+	// as NULL, so we can skip this step
+	if colIdx == nonPKCols.StoredSize()-1 {
 		return i, nil
 	}
 
@@ -301,8 +301,7 @@ func (i prollyIndex) AddColumnToRows(ctx context.Context, newCol string, newSche
 	}
 
 	// Re-write all the rows, inserting a zero-byte field in every value tuple
-	_, valDesc := rowMap.Descriptors()
-	b := val.NewTupleBuilder(valDesc)
+	b := val.NewTupleBuilder(newSchema.GetValueDescriptor())
 	for {
 		k, v, err := iter.Next(ctx)
 		if err == io.EOF {
@@ -0,0 +1,107 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ExternalTable is a row of the dolt_external_tables table. An external table has no rows of its own: reads of it, and
+// writes to it if it's writable, are made to the table |RemoteTable| on the MySQL or Postgres server of the connection
+// named |Connection|. Connections are defined by the server's environment rather than by the database, so that their
+// credentials aren't versioned, and so that only the servers they name can be reached.
+type ExternalTable struct {
+	Name        string
+	Connection  string
+	RemoteTable string
+	Writable    bool
+}
+
+var externalConnectionNameRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Validate returns an error if the definition of the external table is invalid.
+func (t ExternalTable) Validate() error {
+	if HasDoltPrefix(t.Name) {
+		return fmt.Errorf("invalid external table '%s': names beginning with dolt_ are reserved", t.Name)
+	}
+	if !externalConnectionNameRegex.MatchString(t.Connection) {
+		return fmt.Errorf("invalid external table '%s': %s must be the name of a connection, made of letters, digits and underscores", t.Name, ExternalTablesConnectionCol)
+	}
+	if strings.TrimSpace(t.RemoteTable) == "" {
+		return fmt.Errorf("invalid external table '%s': %s must not be empty", t.Name, ExternalTablesRemoteTableCol)
+	}
+	return nil
+}
+
+// GetExternalTable returns the external table named |name| from the dolt_external_tables table of |root|. Table names
+// are case-insensitive.
+func GetExternalTable(ctx context.Context, root RootValue, name string) (ExternalTable, bool, error) {
+	table, found, err := root.GetTable(ctx, TableName{Name: ExternalTablesTableName})
+	if err != nil {
+		return ExternalTable{}, false, err
+	}
+	if !found || table.Format() == types.Format_LD_1 {
+		return ExternalTable{}, false, nil
+	}
+	index, err := table.GetRowData(ctx)
+	if err != nil {
+		return ExternalTable{}, false, err
+	}
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return ExternalTable{}, false, err
+	}
+	keyDesc, valueDesc := sch.GetMapDescriptors()
+	if keyDesc.Count() != 1 || valueDesc.Count() != 3 {
+		return ExternalTable{}, false, fmt.Errorf("%s had unexpected schema, this should never happen", ExternalTablesTableName)
+	}
+
+	m := durable.ProllyMapFromIndex(index)
+	iter, err := m.IterAll(ctx)
+	if err != nil {
+		return ExternalTable{}, false, err
+	}
+	for {
+		keyTuple, valueTuple, err := iter.Next(ctx)
+		if err == io.EOF {
+			return ExternalTable{}, false, nil
+		}
+		if err != nil {
+			return ExternalTable{}, false, err
+		}
+
+		tblName, ok := keyDesc.GetString(0, keyTuple)
+		if !ok {
+			return ExternalTable{}, false, fmt.Errorf("could not read external table name")
+		}
+		if !strings.EqualFold(tblName, name) {
+			continue
+		}
+
+		ext := ExternalTable{Name: tblName}
+		ext.Connection, _ = valueDesc.GetString(0, valueTuple)
+		ext.RemoteTable, _ = valueDesc.GetString(1, valueTuple)
+		writable, _ := valueDesc.GetInt8(2, valueTuple)
+		ext.Writable = writable != 0
+		return ext, true, nil
+	}
+}
@@ -70,6 +70,7 @@ type fvUser struct {
 
 var NewClient = fvUser{vers: newVersion}
 var OldClient = fvUser{vers: oldVersion}
+var CurrentClient = fvUser{vers: DoltFeatureVersionCopy}
 
 func TestFeatureVersion(t *testing.T) {
 
@@ -146,6 +147,33 @@ func TestFeatureVersion(t *testing.T) {
 			},
 			expVer: newVersion,
 		},
+		{
+			name: "roots without dropped columns are readable by older clients",
+			setup: []fvCommand{
+				{CurrentClient, commands.SqlCmd{}, args{"-q", "CREATE TABLE test (pk int PRIMARY KEY, c0 int, c1 int);"}},
+				{CurrentClient, commands.SqlCmd{}, args{"-q", "INSERT INTO test VALUES (0, 0, 0);"}},
+			},
+			expVer: doltdb.DroppedColumnsFeatureVersion - 1,
+		},
+		{
+			name: "dropping a column without rewriting rows writes its feature version",
+			setup: []fvCommand{
+				{CurrentClient, commands.SqlCmd{}, args{"-q", "CREATE TABLE test (pk int PRIMARY KEY, c0 int, c1 int);"}},
+				{CurrentClient, commands.SqlCmd{}, args{"-q", "INSERT INTO test VALUES (0, 0, 0);"}},
+				{CurrentClient, commands.SqlCmd{}, args{"-q", "ALTER TABLE test DROP COLUMN c0;"}},
+			},
+			expVer: doltdb.DroppedColumnsFeatureVersion,
+		},
+		{
+			name: "rewriting the table with dropped columns keeps its feature version",
+			setup: []fvCommand{
+				{CurrentClient, commands.SqlCmd{}, args{"-q", "CREATE TABLE test (pk int PRIMARY KEY, c0 int, c1 int);"}},
+				{CurrentClient, commands.SqlCmd{}, args{"-q", "INSERT INTO test VALUES (0, 0, 0);"}},
+				{CurrentClient, commands.SqlCmd{}, args{"-q", "ALTER TABLE test DROP COLUMN c0;"}},
+				{CurrentClient, commands.SqlCmd{}, args{"-q", "ALTER TABLE test MODIFY COLUMN c1 varchar(20);"}},
+			},
+			expVer: doltdb.DroppedColumnsFeatureVersion,
+		},
 	}
 
 	ctx := context.Background()
@@ -0,0 +1,186 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// fsckBatchSize is the number of chunks requested at a time when walking the chunk graph.
+const fsckBatchSize = 4096
+
+// FsckReport summarizes the result of a call to DoltDB.Fsck.
+type FsckReport struct {
+	// ChunksChecked is the number of chunks whose data was read and verified
+	ChunksChecked int
+	// ChunksReachable is the number of chunks reachable from the root of the database
+	ChunksReachable int
+	// Problems is the number of problems found
+	Problems int
+	// Quarantined is the names of the table files which were quarantined
+	Quarantined []string
+	// ChunksLost is the number of chunks in the quarantined table files which could not be salvaged
+	ChunksLost int
+}
+
+// Fsck checks the integrity of this database. Every chunk in the table files, archives and chunk journal of the
+// database's stores is read and verified against its address, and then the chunk graph is walked from the root of
+// the database to find references to chunks which are missing. |cb| is called with a description of each problem.
+//
+// If |quarantine| is true, table files and archives which contain corrupt chunks are moved out of the database after
+// their intact chunks have been salvaged, before the chunk graph is walked. Problems with the chunk journal are only
+// reported.
+func (ddb *DoltDB) Fsck(ctx context.Context, quarantine bool, cb func(problem string) error) (FsckReport, error) {
+	var report FsckReport
+	problem := func(msg string) error {
+		report.Problems++
+		return cb(msg)
+	}
+
+	cs := datas.ChunkStoreFromDatabase(ddb.db)
+	gen, ok := cs.(*nbs.GenerationalNBS)
+	if !ok {
+		return report, errors.New("fsck is only supported for local databases")
+	}
+
+	stores := []struct {
+		name  string
+		store chunks.ChunkStore
+	}{{"oldgen", gen.OldGen()}, {"newgen", gen.NewGen()}}
+	for _, s := range stores {
+		store, ok := s.store.(*nbs.NomsBlockStore)
+		if !ok {
+			continue
+		}
+
+		corrupt := hash.NewHashSet()
+		n, err := store.Fsck(ctx, func(p nbs.FsckProblem) error {
+			if !p.IsJournal() {
+				corrupt.Insert(p.TableFile)
+			}
+			return problem(fmt.Sprintf("%s: %s", s.name, p.String()))
+		})
+		if err != nil {
+			return report, err
+		}
+		report.ChunksChecked += n
+
+		if quarantine && corrupt.Size() > 0 {
+			lost, err := store.QuarantineTableFiles(ctx, corrupt)
+			if err != nil {
+				return report, err
+			}
+			report.ChunksLost += lost
+			for h := range corrupt {
+				report.Quarantined = append(report.Quarantined, h.String())
+			}
+		}
+	}
+
+	reachable, err := walkChunkGraph(ctx, cs, ddb.Format(), problem)
+	if err != nil && report.Problems > 0 {
+		// corrupt chunks which were not quarantined can stop the walk, and have already been reported
+		return report, problem(fmt.Sprintf("unable to walk chunk graph: %s", err.Error()))
+	} else if err != nil {
+		return report, err
+	}
+	report.ChunksReachable = reachable
+
+	return report, nil
+}
+
+// walkChunkGraph visits every chunk reachable from the root of |cs|, calling |problem| for each referenced chunk which
+// is missing. Ghost chunks, which a shallow clone knows exist but doesn't have, are not walked. The number of chunks
+// visited is returned.
+func walkChunkGraph(ctx context.Context, cs chunks.ChunkStore, nbf *types.NomsBinFormat, problem func(string) error) (int, error) {
+	root, err := cs.Root(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if root.IsEmpty() {
+		return 0, nil
+	}
+
+	walked := 0
+	visited := hash.NewHashSet(root)
+	// pending maps each chunk still to be visited to a chunk which references it
+	pending := map[hash.Hash]hash.Hash{root: {}}
+	for len(pending) > 0 {
+		batch := make(hash.HashSet, fsckBatchSize)
+		for h := range pending {
+			batch.Insert(h)
+			if batch.Size() == fsckBatchSize {
+				break
+			}
+		}
+
+		var mu sync.Mutex
+		var walkErr error
+		found := hash.NewHashSet()
+		err = cs.GetMany(ctx, batch, func(ctx context.Context, c *chunks.Chunk) {
+			mu.Lock()
+			defer mu.Unlock()
+			found.Insert(c.Hash())
+			if c.IsGhost() || walkErr != nil {
+				return
+			}
+			walked++
+			addrs := hash.NewHashSet()
+			if err := types.AddrsFromNomsValue(*c, nbf, addrs); err != nil {
+				walkErr = fmt.Errorf("unable to read references of chunk %s: %w", c.Hash().String(), err)
+				return
+			}
+			for a := range addrs {
+				if !visited.Has(a) {
+					visited.Insert(a)
+					pending[a] = c.Hash()
+				}
+			}
+		})
+		if err != nil {
+			return 0, err
+		} else if walkErr != nil {
+			return 0, walkErr
+		}
+
+		for h := range batch {
+			parent := pending[h]
+			delete(pending, h)
+			if found.Has(h) {
+				continue
+			}
+			var msg string
+			if parent.IsEmpty() {
+				msg = fmt.Sprintf("root chunk %s is missing", h.String())
+			} else {
+				msg = fmt.Sprintf("chunk %s referenced by chunk %s is missing", h.String(), parent.String())
+			}
+			if err = problem(msg); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return walked, nil
+}
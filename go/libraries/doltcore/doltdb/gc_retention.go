@@ -0,0 +1,139 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// gcRetainRefPrefix is the path prefix of the internal refs which keep commits alive through garbage collection after
+// they are no longer referenced by a branch. Each such ref is named for the commit it references, and is removed by
+// the first garbage collection run after the commit falls outside of the retention window.
+const gcRetainRefPrefix = "gc-retain/"
+
+// retainableRefTypes are the types of refs whose previous values are retained by garbage collection. All of them
+// reference commits.
+var retainableRefTypes = map[ref.RefType]struct{}{
+	ref.BranchRefType:    {},
+	ref.RemoteRefType:    {},
+	ref.WorkspaceRefType: {},
+}
+
+var gcRetainRefFilter = map[ref.RefType]struct{}{ref.InternalRefType: {}}
+
+// IsGCRetainRef returns whether |r| is one of the internal refs garbage collection uses to retain commits.
+func IsGCRetainRef(r ref.DoltRef) bool {
+	return r.GetType() == ref.InternalRefType && strings.HasPrefix(r.GetPath(), gcRetainRefPrefix)
+}
+
+// updateGCRetainedCommits brings the set of retained commits up to date before a garbage collection. Every commit
+// which is, or according to the reflog has been, the head of a branch, remote tracking branch or workspace is
+// retained if its commit timestamp is not before |retainSince|. Retained commits which have fallen outside of the
+// window are released. If |retainSince| is the zero time, retention is disabled and all retained commits are
+// released.
+//
+// The commits in the reflog are not otherwise referenced, so they must be recorded here for them to outlive the
+// next collection.
+func (ddb *DoltDB) updateGCRetainedCommits(ctx context.Context, retainSince time.Time) error {
+	retained := make(map[hash.Hash]ref.DoltRef)
+	err := ddb.VisitRefsOfType(ctx, gcRetainRefFilter, func(r ref.DoltRef, addr hash.Hash) error {
+		if IsGCRetainRef(r) {
+			retained[addr] = r
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	candidates := make(hash.HashSet)
+	for h := range retained {
+		candidates.Insert(h)
+	}
+	if !retainSince.IsZero() {
+		err = ddb.VisitRefsOfType(ctx, retainableRefTypes, func(_ ref.DoltRef, addr hash.Hash) error {
+			candidates.Insert(addr)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		err = ddb.visitReflogCommits(ctx, func(addr hash.Hash) {
+			candidates.Insert(addr)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for h := range candidates {
+		keep := false
+		if !retainSince.IsZero() {
+			keep, err = ddb.isCommitNewerThan(ctx, h, retainSince)
+			if err != nil {
+				return err
+			}
+		}
+
+		r, isRetained := retained[h]
+		if keep && !isRetained {
+			err = ddb.SetHead(ctx, ref.NewInternalRef(gcRetainRefPrefix+h.String()), h)
+		} else if !keep && isRetained {
+			err = ddb.deleteRef(ctx, r, nil, "")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// visitReflogCommits calls |cb| with every commit which a retainable ref has referenced according to the reflog.
+func (ddb *DoltDB) visitReflogCommits(ctx context.Context, cb func(addr hash.Hash)) error {
+	return ddb.IterateReflog(ctx, func(e ReflogEntry) error {
+		if _, ok := retainableRefTypes[e.Ref.GetType()]; ok {
+			cb(e.CommitHash)
+		}
+		return nil
+	})
+}
+
+// isCommitNewerThan returns whether the commit |h| was made at or after |t|. Ghost commits, and commits from the
+// reflog archive which have already been collected, are never retained.
+func (ddb *DoltDB) isCommitNewerThan(ctx context.Context, h hash.Hash, t time.Time) (bool, error) {
+	optCmt, err := ddb.ReadCommit(ctx, h)
+	if errors.Is(err, datas.ErrCommitNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return false, nil
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !meta.Time().Before(t), nil
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/stretchr/testify/assert"
@@ -50,6 +51,7 @@ func TestGarbageCollection(t *testing.T) {
 	}
 
 	t.Run("HasCacheDataCorruption", testGarbageCollectionHasCacheDataCorruptionBugFix)
+	t.Run("RetainRecentCommits", testGarbageCollectionRetainRecentCommits)
 }
 
 type stage struct {
@@ -139,7 +141,7 @@ func testGarbageCollection(t *testing.T, test gcTest) {
 		}
 	}
 
-	err := dEnv.DoltDB.GC(ctx, nil)
+	err := dEnv.DoltDB.GC(ctx, time.Time{}, nil)
 	require.NoError(t, err)
 	test.postGCFunc(ctx, t, dEnv.DoltDB, res)
 
@@ -151,6 +153,58 @@ func testGarbageCollection(t *testing.T, test gcTest) {
 	assert.Equal(t, test.expected, actual)
 }
 
+func testGarbageCollectionRetainRecentCommits(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	cliCtx, verr := commands.NewArgFreeCliContext(ctx, dEnv)
+	require.NoError(t, verr)
+
+	runCommands := func(cmds []testCommand) {
+		for _, c := range cmds {
+			exitCode := c.cmd.Exec(ctx, c.cmd.Name(), c.args, dEnv, cliCtx)
+			require.Equal(t, 0, exitCode)
+		}
+	}
+
+	runCommands(gcSetupCommon)
+	runCommands([]testCommand{
+		{commands.CheckoutCmd{}, []string{"-b", "temp"}},
+		{commands.SqlCmd{}, []string{"-q", "INSERT INTO test VALUES (0),(1),(2);"}},
+		{commands.AddCmd{}, []string{"."}},
+		{commands.CommitCmd{}, []string{"-m", "commit"}},
+	})
+
+	cm, err := dEnv.DoltDB.ResolveCommitRef(ctx, ref.NewBranchRef("temp"))
+	require.NoError(t, err)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	cs, err := doltdb.NewCommitSpec(h.String())
+	require.NoError(t, err)
+
+	retainSince := time.Now().Add(-time.Hour)
+	err = dEnv.DoltDB.GC(ctx, retainSince, nil)
+	require.NoError(t, err)
+
+	runCommands([]testCommand{
+		{commands.CheckoutCmd{}, []string{env.DefaultInitBranch}},
+		{commands.BranchCmd{}, []string{"-D", "temp"}},
+	})
+
+	// the commit was the head of a branch within the retention window, so it survives
+	err = dEnv.DoltDB.GC(ctx, retainSince, nil)
+	require.NoError(t, err)
+	_, err = dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+
+	// the commit is older than the retention window, so it's released
+	err = dEnv.DoltDB.GC(ctx, time.Now().Add(time.Hour), nil)
+	require.NoError(t, err)
+	_, err = dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.Error(t, err)
+}
+
 // In September 2023, we found a failure to handle the `hasCache` in
 // `*NomsBlockStore` appropriately while cleaning up a memtable into which
 // dangling references had been written could result in writing chunks to a
@@ -208,7 +262,7 @@ func testGarbageCollectionHasCacheDataCorruptionBugFix(t *testing.T) {
 	_, err = ns.Write(ctx, c1.Node())
 	require.NoError(t, err)
 
-	err = ddb.GC(ctx, nil)
+	err = ddb.GC(ctx, time.Time{}, nil)
 	require.NoError(t, err)
 
 	c2 := newIntMap(t, ctx, ns, 2, 2)
@@ -0,0 +1,154 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/pool"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// ErrNoteNotFound is returned when there's no note on a commit in a notes ref.
+var ErrNoteNotFound = errors.New("no note found")
+
+// ErrNotesUnsupported is returned when writing notes to a database in the old storage format.
+var ErrNotesUnsupported = errors.New("notes are not supported for the old storage format")
+
+const (
+	// NotesCommitCol is the name of the column holding the hash of the commit a note is attached to
+	NotesCommitCol = "commit_hash"
+	// NotesNoteCol is the name of the column holding the contents of a note
+	NotesNoteCol = "note"
+)
+
+// notesSchema is the schema of the table holding the notes in the root value of a notes commit
+var notesSchema = schema.MustSchemaFromCols(schema.NewColCollection(
+	schema.NewColumn(NotesCommitCol, schema.DoltNotesCommitHashTag, types.StringKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn(NotesNoteCol, schema.DoltNotesNoteTag, types.StringKind, false, schema.NotNullConstraint{}),
+))
+
+// Note is a note attached to a commit in a notes ref.
+type Note struct {
+	// Commit is the hash of the commit the note is attached to
+	Commit hash.Hash
+	// Text is the contents of the note
+	Text string
+}
+
+// GetNotesRefs returns a list of all notes refs in the database.
+func (ddb *DoltDB) GetNotesRefs(ctx context.Context) ([]ref.DoltRef, error) {
+	return ddb.GetRefsOfType(ctx, ref.NotesRefTypes)
+}
+
+// GetNotes returns the notes in the notes ref given, in order of the hashes of the commits they're attached to. There
+// are no notes in a notes ref which doesn't exist.
+func (ddb *DoltDB) GetNotes(ctx context.Context, nr ref.NotesRef) ([]Note, error) {
+	m, ok, err := ddb.notesMap(ctx, nr)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	itr, err := m.IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	kd, vd := notesSchema.GetMapDescriptors()
+	var notes []Note
+	for {
+		k, v, err := itr.Next(ctx)
+		if err == io.EOF {
+			return notes, nil
+		} else if err != nil {
+			return nil, err
+		}
+		cm, _ := kd.GetString(0, k)
+		text, _ := vd.GetString(0, v)
+		notes = append(notes, Note{Commit: hash.Parse(cm), Text: text})
+	}
+}
+
+// GetNote returns the note attached to the commit |cm| in the notes ref given, and whether there is one.
+func (ddb *DoltDB) GetNote(ctx context.Context, nr ref.NotesRef, cm hash.Hash) (string, bool, error) {
+	m, ok, err := ddb.notesMap(ctx, nr)
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	var text string
+	var found bool
+	err = m.Get(ctx, notesKey(m.Pool(), cm), func(_, v val.Tuple) error {
+		if v != nil {
+			_, vd := notesSchema.GetMapDescriptors()
+			text, found = vd.GetString(0, v)
+		}
+		return nil
+	})
+	return text, found, err
+}
+
+// SetNote attaches |text| to the commit |cm| in the notes ref given, replacing any note already attached to it. The
+// notes ref is updated with a new commit described by |meta|, and is created if it doesn't exist.
+func (ddb *DoltDB) SetNote(ctx context.Context, nr ref.NotesRef, cm hash.Hash, text string, meta *datas.CommitMeta) error {
+	return ddb.updateNotes(ctx, nr, meta, func(mut *prolly.MutableMap, p pool.BuffPool) error {
+		_, vd := notesSchema.GetMapDescriptors()
+		vb := val.NewTupleBuilder(vd)
+		vb.PutString(0, text)
+		return mut.Put(ctx, notesKey(p, cm), vb.Build(p))
+	})
+}
+
+// RemoveNote removes the note attached to the commit |cm| in the notes ref given, updating the notes ref with a new
+// commit described by |meta|. It returns ErrNoteNotFound if there's no note on the commit.
+func (ddb *DoltDB) RemoveNote(ctx context.Context, nr ref.NotesRef, cm hash.Hash, meta *datas.CommitMeta) error {
+	_, found, err := ddb.GetNote(ctx, nr, cm)
+	if err != nil {
+		return err
+	} else if !found {
+		return ErrNoteNotFound
+	}
+
+	return ddb.updateNotes(ctx, nr, meta, func(mut *prolly.MutableMap, p pool.BuffPool) error {
+		return mut.Delete(ctx, notesKey(p, cm))
+	})
+}
+
+func notesKey(p pool.BuffPool, cm hash.Hash) val.Tuple {
+	kd, _ := notesSchema.GetMapDescriptors()
+	kb := val.NewTupleBuilder(kd)
+	kb.PutString(0, cm.String())
+	return kb.Build(p)
+}
+
+// notesMap returns the notes in the notes ref given, and whether the notes ref exists.
+func (ddb *DoltDB) notesMap(ctx context.Context, nr ref.NotesRef) (prolly.Map, bool, error) {
+	return ddb.refTableMap(ctx, nr, NotesTableName)
+}
+
+// updateNotes applies |edit| to the notes in the notes ref given, and commits the result to the notes ref.
+func (ddb *DoltDB) updateNotes(ctx context.Context, nr ref.NotesRef, meta *datas.CommitMeta, edit func(mut *prolly.MutableMap, p pool.BuffPool) error) error {
+	if !types.IsFormat_DOLT(ddb.Format()) {
+		return ErrNotesUnsupported
+	}
+	return ddb.updateRefTable(ctx, nr, NotesTableName, notesSchema, meta, edit)
+}
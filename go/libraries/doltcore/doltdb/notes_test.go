@@ -0,0 +1,84 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestNotes(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse"))
+
+	cs, _ := NewCommitSpec("main")
+	optCmt, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	cm, ok := optCmt.ToCommit()
+	require.True(t, ok)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+
+	nr := ref.NewNotesRef(ref.DefaultNotesRefName)
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "notes")
+	require.NoError(t, err)
+
+	notes, err := ddb.GetNotes(ctx, nr)
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+	err = ddb.RemoveNote(ctx, nr, h, meta)
+	assert.ErrorIs(t, err, ErrNoteNotFound)
+
+	require.NoError(t, ddb.SetNote(ctx, nr, h, "first", meta))
+	require.NoError(t, ddb.SetNote(ctx, nr, h, "second", meta))
+	text, found, err := ddb.GetNote(ctx, nr, h)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "second", text)
+
+	notes, err = ddb.GetNotes(ctx, nr)
+	require.NoError(t, err)
+	assert.Equal(t, []Note{{Commit: h, Text: "second"}}, notes)
+
+	refs, err := ddb.GetNotesRefs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ref.DoltRef{nr}, refs)
+
+	// each change to the notes is a commit in the history of the notes ref
+	notesCm, err := ddb.ResolveCommitRef(ctx, nr)
+	require.NoError(t, err)
+	assert.Equal(t, 1, notesCm.NumParents())
+
+	require.NoError(t, ddb.RemoveNote(ctx, nr, h, meta))
+	_, found, err = ddb.GetNote(ctx, nr, h)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// other notes refs are unaffected
+	notes, err = ddb.GetNotes(ctx, ref.NewNotesRef("reviews"))
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+}
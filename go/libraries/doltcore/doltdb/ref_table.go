@@ -0,0 +1,128 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/pool"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// refTableMap returns the rows of the table |tName| in the commit at the ref |r|, and whether the ref and the table
+// exist.
+func (ddb *DoltDB) refTableMap(ctx context.Context, r ref.DoltRef, tName string) (prolly.Map, bool, error) {
+	if !types.IsFormat_DOLT(ddb.Format()) {
+		return prolly.Map{}, false, nil
+	}
+
+	ok, err := ddb.HasRef(ctx, r)
+	if err != nil || !ok {
+		return prolly.Map{}, false, err
+	}
+
+	cm, err := ddb.ResolveCommitRef(ctx, r)
+	if err != nil {
+		return prolly.Map{}, false, err
+	}
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return prolly.Map{}, false, err
+	}
+	tbl, ok, err := root.GetTable(ctx, TableName{Name: tName})
+	if err != nil || !ok {
+		return prolly.Map{}, false, err
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return prolly.Map{}, false, err
+	}
+	return durable.ProllyMapFromIndex(idx), true, nil
+}
+
+// updateRefTable applies |edit| to the rows of the table |tName| in the commit at the ref |r|, and commits the result to
+// the ref with |meta|. The table is created with the schema |sch| if it doesn't exist, and the ref if it doesn't exist.
+func (ddb *DoltDB) updateRefTable(ctx context.Context, r ref.DoltRef, tName string, sch schema.Schema, meta *datas.CommitMeta, edit func(mut *prolly.MutableMap, p pool.BuffPool) error) error {
+	var root RootValue
+	ok, err := ddb.HasRef(ctx, r)
+	if err != nil {
+		return err
+	}
+	if ok {
+		cm, err := ddb.ResolveCommitRef(ctx, r)
+		if err != nil {
+			return err
+		}
+		root, err = cm.GetRootValue(ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		root, err = EmptyRootValue(ctx, ddb.vrw, ddb.ns)
+		if err != nil {
+			return err
+		}
+	}
+
+	tn := TableName{Name: tName}
+	tbl, ok, err := root.GetTable(ctx, tn)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		root, err = CreateEmptyTable(ctx, root, tn, sch)
+		if err != nil {
+			return err
+		}
+		tbl, _, err = root.GetTable(ctx, tn)
+		if err != nil {
+			return err
+		}
+	}
+
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	m := durable.ProllyMapFromIndex(idx)
+	mut := m.Mutate()
+	if err = edit(mut, m.Pool()); err != nil {
+		return err
+	}
+	m, err = mut.Map(ctx)
+	if err != nil {
+		return err
+	}
+	tbl, err = tbl.UpdateRows(ctx, durable.IndexFromProllyMap(m))
+	if err != nil {
+		return err
+	}
+	root, err = root.PutTable(ctx, tn, tbl)
+	if err != nil {
+		return err
+	}
+
+	_, valHash, err := ddb.WriteRootValue(ctx, root)
+	if err != nil {
+		return err
+	}
+	_, err = ddb.CommitWithParentCommits(ctx, valHash, r, nil, meta)
+	return err
+}
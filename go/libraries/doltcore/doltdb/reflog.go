@@ -0,0 +1,244 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+// ReflogEntry is a single change to the value of a ref, as recorded by the reflog.
+type ReflogEntry struct {
+	// Ref is the fully qualified ref which changed, e.g. refs/heads/main
+	Ref ref.DoltRef
+	// Timestamp is the time the change was made, or nil if it was recorded by a version of Dolt which didn't
+	// record timestamps
+	Timestamp *time.Time
+	// Address is the address the ref was changed to. For most refs this is the same as CommitHash, but for tags it
+	// is the address of the tag.
+	Address hash.Hash
+	// CommitHash is the commit the ref referenced after the change
+	CommitHash hash.Hash
+	// CommitMessage is the description of the commit, or the empty string for a ghost commit
+	CommitMessage string
+}
+
+// reflogArchiveRecord is the serialized form of a ReflogEntry in the reflog archive. Each batch of archived entries
+// is followed by a record holding only the Root of the last journal root they were read from.
+type reflogArchiveRecord struct {
+	Root          string     `json:"root,omitempty"`
+	Ref           string     `json:"ref,omitempty"`
+	Timestamp     *time.Time `json:"timestamp,omitempty"`
+	Address       string     `json:"address,omitempty"`
+	CommitHash    string     `json:"commit_hash,omitempty"`
+	CommitMessage string     `json:"commit_message,omitempty"`
+}
+
+// IterateReflog calls |cb| with every change to a ref recorded by the reflog of this database, from oldest to newest.
+// The reflog is made up of the entries archived by previous garbage collections, followed by the entries read from
+// the roots in the chunk journal. Internal refs and working sets are not included, nor are entries which don't change
+// the value of their ref. Databases without a chunk journal have no reflog.
+func (ddb *DoltDB) IterateReflog(ctx context.Context, cb func(ReflogEntry) error) error {
+	journal := ddb.ChunkJournal()
+	if journal == nil {
+		return nil
+	}
+
+	previous, lastRoot, err := iterateArchivedReflog(journal, cb)
+	if err != nil {
+		return err
+	}
+	_, err = ddb.iterateJournalReflog(ctx, journal, previous, lastRoot, cb)
+	return err
+}
+
+// archiveReflog moves the entries of the reflog which are only held in the chunk journal to the reflog archive, so
+// that they are not lost when garbage collection clears the journal.
+func (ddb *DoltDB) archiveReflog(ctx context.Context) error {
+	journal := ddb.ChunkJournal()
+	if journal == nil {
+		return nil
+	}
+
+	previous, lastRoot, err := iterateArchivedReflog(journal, func(ReflogEntry) error { return nil })
+	if err != nil {
+		return err
+	}
+
+	var records [][]byte
+	lastRoot, err = ddb.iterateJournalReflog(ctx, journal, previous, lastRoot, func(e ReflogEntry) error {
+		record, err := json.Marshal(reflogArchiveRecord{
+			Ref:           e.Ref.String(),
+			Timestamp:     e.Timestamp,
+			Address:       e.Address.String(),
+			CommitHash:    e.CommitHash.String(),
+			CommitMessage: e.CommitMessage,
+		})
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	marker, err := json.Marshal(reflogArchiveRecord{Root: lastRoot.String()})
+	if err != nil {
+		return err
+	}
+	return journal.ArchiveReflog(append(records, marker))
+}
+
+// iterateArchivedReflog calls |cb| with every entry in the reflog archive of |journal|. It returns the address each
+// archived ref was last changed to, and the last journal root which has been archived. Records which can't be
+// decoded, such as those left behind by an interrupted write, are skipped.
+func iterateArchivedReflog(journal *nbs.ChunkJournal, cb func(ReflogEntry) error) (map[string]hash.Hash, hash.Hash, error) {
+	previous := make(map[string]hash.Hash)
+	var lastRoot hash.Hash
+	err := journal.IterateReflogArchive(func(record []byte) error {
+		var r reflogArchiveRecord
+		if err := json.Unmarshal(record, &r); err != nil {
+			return nil
+		}
+		if r.Root != "" {
+			if root, ok := hash.MaybeParse(r.Root); ok {
+				lastRoot = root
+			}
+			return nil
+		}
+		doltRef, err := ref.Parse(r.Ref)
+		if err != nil {
+			return nil
+		}
+		addr, ok := hash.MaybeParse(r.Address)
+		if !ok {
+			return nil
+		}
+		commitHash, ok := hash.MaybeParse(r.CommitHash)
+		if !ok {
+			return nil
+		}
+
+		previous[r.Ref] = addr
+		return cb(ReflogEntry{
+			Ref:           doltRef,
+			Timestamp:     r.Timestamp,
+			Address:       addr,
+			CommitHash:    commitHash,
+			CommitMessage: r.CommitMessage,
+		})
+	})
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+	return previous, lastRoot, nil
+}
+
+// iterateJournalReflog calls |cb| with every change to a ref found in the roots held in the chunk journal, and
+// returns the last root it read. |previous| holds the last known address of each ref, and is updated as changes are
+// found. If the journal still holds |archivedRoot|, because a garbage collection which archived the reflog failed,
+// it and the roots before it have already been archived and are skipped.
+func (ddb *DoltDB) iterateJournalReflog(ctx context.Context, journal *nbs.ChunkJournal, previous map[string]hash.Hash, archivedRoot hash.Hash, cb func(ReflogEntry) error) (hash.Hash, error) {
+	type journalRoot struct {
+		root      hash.Hash
+		timestamp *time.Time
+	}
+	var roots []journalRoot
+	err := journal.IterateRoots(func(root string, timestamp *time.Time) error {
+		h := hash.Parse(root)
+		if h == archivedRoot {
+			roots = roots[:0]
+			return nil
+		}
+		roots = append(roots, journalRoot{root: h, timestamp: timestamp})
+		return nil
+	})
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	var lastRoot hash.Hash
+	for _, jr := range roots {
+		lastRoot = jr.root
+		if err = ddb.iterateRootReflog(ctx, jr.root, jr.timestamp, previous, cb); err != nil {
+			return hash.Hash{}, err
+		}
+	}
+	return lastRoot, nil
+}
+
+// iterateRootReflog calls |cb| with every ref in the root |rootHash| whose address differs from |previous|.
+func (ddb *DoltDB) iterateRootReflog(ctx context.Context, rootHash hash.Hash, timestamp *time.Time, previous map[string]hash.Hash, cb func(ReflogEntry) error) error {
+	datasets, err := ddb.DatasetsByRootHash(ctx, rootHash)
+	if err != nil {
+		return fmt.Errorf("unable to look up references for root hash %s: %s", rootHash.String(), err.Error())
+	}
+
+	return datasets.IterAll(ctx, func(id string, addr hash.Hash) error {
+		// Skip working set references (WorkingSetRefs can't always be resolved to commits)
+		if ref.IsWorkingSet(id) {
+			return nil
+		}
+
+		doltRef, err := ref.Parse(id)
+		if err != nil {
+			return err
+		}
+		if doltRef.GetType() == ref.InternalRefType {
+			return nil
+		}
+
+		if prev, ok := previous[id]; ok && prev == addr {
+			return nil
+		}
+
+		commitHash, err := ddb.GetHashForRefStrByNomsRoot(ctx, id, rootHash)
+		if err != nil {
+			return err
+		}
+		optCmt, err := ddb.ReadCommit(ctx, *commitHash)
+		if err != nil {
+			return err
+		}
+		message := ""
+		if cm, ok := optCmt.ToCommit(); ok {
+			meta, err := cm.GetCommitMeta(ctx)
+			if err != nil {
+				return err
+			}
+			message = meta.Description
+		}
+
+		previous[id] = addr
+		return cb(ReflogEntry{
+			Ref:           doltRef,
+			Timestamp:     timestamp,
+			Address:       addr,
+			CommitHash:    *commitHash,
+			CommitMessage: message,
+		})
+	})
+}
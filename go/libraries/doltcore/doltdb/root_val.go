@@ -52,7 +52,16 @@ type FeatureVersion int64
 
 // DoltFeatureVersion is described in feature_version.md.
 // only variable for testing.
-var DoltFeatureVersion FeatureVersion = 7 // last bumped when fixing bug related to GeomAddrs not getting pushed
+var DoltFeatureVersion FeatureVersion = 9 // last bumped when prefix compressing the keys of index leaf nodes
+
+// DroppedColumnsFeatureVersion is the feature version of roots with tables whose schemas store dropped columns. Roots
+// without them are written with the previous version, so older clients can still read them.
+const DroppedColumnsFeatureVersion FeatureVersion = 8
+
+// KeyPrefixCompressionFeatureVersion is the feature version of roots with tables whose index maps may have prefix
+// compressed keys, see message.ProllyMapSerializer.WithKeyPrefixCompression. Like DroppedColumnsFeatureVersion, it's
+// only written with roots which need it.
+const KeyPrefixCompressionFeatureVersion FeatureVersion = 9
 
 // RootValue is the value of the Database and is the committed value in every Dolt or Doltgres commit.
 type RootValue interface {
@@ -174,6 +183,33 @@ var NewRootValue = func(ctx context.Context, vrw types.ValueReadWriter, ns tree.
 	return &rootValue{vrw, ns, storage, nil, hash.Hash{}, 0, nil}, nil
 }
 
+// baseFeatureVersion returns the feature version written with roots that don't use any feature which requires a later
+// one.
+func baseFeatureVersion() FeatureVersion {
+	if DoltFeatureVersion == KeyPrefixCompressionFeatureVersion {
+		return DroppedColumnsFeatureVersion - 1
+	}
+	return DoltFeatureVersion
+}
+
+// featureVersionFor returns the feature version written with |root|. A root's feature version is raised to the
+// version the tables put in it need as they're put, so roots which need a later version already have it.
+func featureVersionFor(ctx context.Context, root RootValue) (FeatureVersion, error) {
+	ver := baseFeatureVersion()
+	if ver == DoltFeatureVersion {
+		return ver, nil
+	}
+
+	stored, ok, err := root.GetFeatureVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if ok && stored > ver {
+		ver = stored
+	}
+	return ver, nil
+}
+
 // EmptyRootValue returns an empty RootValue. This is a variable as it's changed in Doltgres.
 var EmptyRootValue = func(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeStore) (RootValue, error) {
 	if vrw.Format().UsesFlatbuffers() {
@@ -189,7 +225,7 @@ var EmptyRootValue = func(ctx context.Context, vrw types.ValueReadWriter, ns tre
 		var empty hash.Hash
 		fkoff := builder.CreateByteVector(empty[:])
 		serial.RootValueStart(builder)
-		serial.RootValueAddFeatureVersion(builder, int64(DoltFeatureVersion))
+		serial.RootValueAddFeatureVersion(builder, int64(baseFeatureVersion()))
 		serial.RootValueAddCollation(builder, serial.Collationutf8mb4_0900_bin)
 		serial.RootValueAddTables(builder, tablesoff)
 		serial.RootValueAddForeignKeyAddr(builder, fkoff)
@@ -206,7 +242,7 @@ var EmptyRootValue = func(ctx context.Context, vrw types.ValueReadWriter, ns tre
 		tablesKey:       empty,
 		superSchemasKey: empty,
 		foreignKeyKey:   empty,
-		featureVersKey:  types.Int(DoltFeatureVersion),
+		featureVersKey:  types.Int(baseFeatureVersion()),
 	}
 
 	st, err := types.NewStruct(vrw.Format(), ddbRootStructName, sd)
@@ -501,7 +537,16 @@ func (root *rootValue) SetTableHash(ctx context.Context, tName string, h hash.Ha
 		return nil, err
 	}
 
-	return root.putTable(ctx, TableName{Name: tName}, ref, hash.Hash{})
+	newRoot, err := root.putTable(ctx, TableName{Name: tName}, ref, hash.Hash{})
+	if err != nil {
+		return nil, err
+	}
+
+	table, _, err := newRoot.GetTable(ctx, TableName{Name: tName})
+	if err != nil {
+		return nil, err
+	}
+	return newRoot.trackFeatureVersion(ctx, table)
 }
 
 // ResolveTableName resolves a case-insensitive name to the exact name as stored in Dolt. Returns false if no matching
@@ -811,14 +856,98 @@ func (root *rootValue) PutTable(ctx context.Context, tName TableName, table *Tab
 		return nil, err
 	}
 
-	return root.putTable(ctx, tName, tableRef, schHash)
+	newRoot, err := root.putTable(ctx, tName, tableRef, schHash)
+	if err != nil {
+		return nil, err
+	}
+	return newRoot.trackFeatureVersion(ctx, table)
+}
+
+// trackFeatureVersion returns |root|, in which |table| was just put, with its feature version raised to the version
+// |table| needs: DroppedColumnsFeatureVersion if its schema stores a dropped column, and
+// KeyPrefixCompressionFeatureVersion if any of its maps may have prefix compressed keys. Only the table put is read, so
+// that writing a root doesn't cost more as it has more tables. A root keeps its version once it has it, even after the
+// tables which needed it are rewritten or dropped.
+func (root *rootValue) trackFeatureVersion(ctx context.Context, table *Table) (RootValue, error) {
+	if baseFeatureVersion() == DoltFeatureVersion {
+		return root, nil
+	}
+
+	ver, ok, err := root.GetFeatureVersion(ctx)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		ver = baseFeatureVersion()
+	}
+	if ver >= KeyPrefixCompressionFeatureVersion {
+		return root, nil
+	}
+
+	required := ver
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	} else if sch.GetAllCols().HasDroppedColumns() {
+		required = DroppedColumnsFeatureVersion
+	}
+	if compressed, err := hasKeyPrefixCompressedMaps(ctx, table, sch); err != nil {
+		return nil, err
+	} else if compressed {
+		required = KeyPrefixCompressionFeatureVersion
+	}
+	if required <= ver {
+		return root, nil
+	}
+
+	newRoot, err := root.withFeatureVersion(required)
+	if err != nil {
+		return nil, err
+	}
+	return newRoot, nil
+}
+
+// hasKeyPrefixCompressedMaps returns true if the primary index or any secondary index of |table| may have prefix
+// compressed keys. Only the root node of each map is read, since a map's root node is marked once any of its nodes
+// may be compressed.
+func hasKeyPrefixCompressedMaps(ctx context.Context, table *Table, sch schema.Schema) (bool, error) {
+	if !types.IsFormat_DOLT(table.Format()) {
+		return false, nil
+	}
+
+	rows, err := table.GetRowData(ctx)
+	if err != nil {
+		return false, err
+	} else if durable.ProllyMapFromIndex(rows).KeyPrefixCompressed() {
+		return true, nil
+	}
+	for _, idx := range sch.Indexes().AllIndexes() {
+		rows, err = table.GetIndexRowData(ctx, idx.Name())
+		if err != nil {
+			return false, err
+		} else if durable.ProllyMapFromIndex(rows).KeyPrefixCompressed() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// withFeatureVersion returns |root| with its feature version set to |v|, keeping its cached table hashes.
+func (root *rootValue) withFeatureVersion(v FeatureVersion) (*rootValue, error) {
+	st, err := root.st.SetFeatureVersion(v)
+	if err != nil {
+		return nil, err
+	}
+	ret := root.withStorage(st)
+	ret.tablesHash = root.tablesHash
+	ret.schemaHashes = root.schemaHashes
+	return ret, nil
 }
 
 func RefFromNomsTable(ctx context.Context, table *Table) (types.Ref, error) {
 	return durable.RefFromNomsTable(ctx, table.table)
 }
 
-func (root *rootValue) putTable(ctx context.Context, tName TableName, ref types.Ref, schHash hash.Hash) (RootValue, error) {
+func (root *rootValue) putTable(ctx context.Context, tName TableName, ref types.Ref, schHash hash.Hash) (*rootValue, error) {
 	if !IsValidTableName(tName.Name) {
 		panic("Don't attempt to put a table with a name that fails the IsValidTableName check")
 	}
@@ -1100,9 +1229,10 @@ func GetAllTagsForRoots(ctx context.Context, roots ...RootValue) (tags schema.Ta
 			continue
 		}
 		err = root.IterTables(ctx, func(tblName TableName, _ *Table, sch schema.Schema) (stop bool, err error) {
-			for _, t := range sch.GetAllCols().Tags {
+			// dropped columns still occupy their tags in stored rows
+			for _, col := range sch.GetAllCols().GetColumnsWithDropped() {
 				// TODO: schema names
-				tags.Add(t, tblName.Name)
+				tags.Add(col.Tag, tblName.Name)
 			}
 			return
 		})
@@ -0,0 +1,203 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+// ErrScrubNotSupported is returned by DoltDB.Scrub for databases which aren't stored locally.
+var ErrScrubNotSupported = errors.New("scrubbing is only supported for local databases")
+
+// ScrubProblem is a problem with a table file found by DoltDB.Scrub.
+type ScrubProblem struct {
+	// Store is the generation of the database's storage the table file is in, either "oldgen" or "newgen"
+	Store string
+	// TableFile is the name of the table file or archive
+	TableFile hash.Hash
+	// Chunk is the address of the corrupt chunk, or the zero hash if the problem is not with a single chunk
+	Chunk hash.Hash
+	// Msg describes the problem
+	Msg string
+	// Repaired is whether the table file was replaced with intact copies of its chunks
+	Repaired bool
+}
+
+// ScrubReport summarizes the result of a call to DoltDB.Scrub.
+type ScrubReport struct {
+	// ChunksChecked is the number of chunks whose data was read and verified
+	ChunksChecked int
+	// Problems are the problems found
+	Problems []ScrubProblem
+}
+
+// Scrub reads every chunk in the table files and archives of this database and verifies it against its address. Unlike
+// Fsck, it can be run while the database is in use, but it doesn't check the chunk journal or walk the chunk graph.
+//
+// When corrupt chunks are found, intact copies of them are fetched from the databases returned by |sources|, which is
+// only called if there are corrupt chunks and may be nil. A table file is repaired when every corrupt chunk in it can
+// be fetched: its intact chunks are salvaged and it's quarantined, as with Fsck, and the fetched chunks are written to
+// the database in its place. Table files which can't be repaired are left alone, and their problems only reported.
+func (ddb *DoltDB) Scrub(ctx context.Context, sources func(context.Context) ([]*DoltDB, error)) (ScrubReport, error) {
+	var report ScrubReport
+
+	gen, ok := datas.ChunkStoreFromDatabase(ddb.db).(*nbs.GenerationalNBS)
+	if !ok {
+		return report, ErrScrubNotSupported
+	}
+
+	var srcDBs []*DoltDB
+	srcsLoaded := false
+	stores := []struct {
+		name  string
+		store chunks.ChunkStore
+	}{{"oldgen", gen.OldGen()}, {"newgen", gen.NewGen()}}
+	for _, s := range stores {
+		store, ok := s.store.(*nbs.NomsBlockStore)
+		if !ok {
+			continue
+		}
+
+		var problems []nbs.FsckProblem
+		n, err := store.Scrub(ctx, func(p nbs.FsckProblem) error {
+			problems = append(problems, p)
+			return nil
+		})
+		if err != nil {
+			return report, err
+		}
+		report.ChunksChecked += n
+
+		repaired := hash.NewHashSet()
+		if len(problems) > 0 && sources != nil {
+			if !srcsLoaded {
+				if srcDBs, err = sources(ctx); err != nil {
+					return report, err
+				}
+				srcsLoaded = true
+			}
+			if repaired, err = repairTableFiles(ctx, store, problems, srcDBs); err != nil {
+				return report, err
+			}
+		}
+
+		for _, p := range problems {
+			report.Problems = append(report.Problems, ScrubProblem{
+				Store:     s.name,
+				TableFile: p.TableFile,
+				Chunk:     p.Chunk,
+				Msg:       p.Msg,
+				Repaired:  repaired.Has(p.TableFile),
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// repairTableFiles replaces the table files in |store| with |problems| whose corrupt chunks can all be fetched from
+// |sources|. It returns the names of the table files which were replaced.
+func repairTableFiles(ctx context.Context, store *nbs.NomsBlockStore, problems []nbs.FsckProblem, sources []*DoltDB) (hash.HashSet, error) {
+	repaired := hash.NewHashSet()
+	if len(sources) == 0 {
+		return repaired, nil
+	}
+
+	corrupt := make(map[hash.Hash]hash.HashSet)
+	unrepairable := hash.NewHashSet()
+	for _, p := range problems {
+		if p.Chunk.IsEmpty() {
+			// the chunks lost to a problem with the whole table file aren't known
+			unrepairable.Insert(p.TableFile)
+			continue
+		}
+		if corrupt[p.TableFile] == nil {
+			corrupt[p.TableFile] = hash.NewHashSet()
+		}
+		corrupt[p.TableFile].Insert(p.Chunk)
+	}
+
+	var fetched []chunks.Chunk
+	for name, addrs := range corrupt {
+		if unrepairable.Has(name) {
+			continue
+		}
+		cs, err := fetchVerifiedChunks(ctx, addrs, sources)
+		if err != nil {
+			return nil, err
+		}
+		if len(cs) < addrs.Size() {
+			continue
+		}
+		repaired.Insert(name)
+		fetched = append(fetched, cs...)
+	}
+	if repaired.Size() == 0 {
+		return repaired, nil
+	}
+
+	// the corrupt table files must be removed first, since chunks the store already has aren't written again
+	if _, err := store.QuarantineTableFiles(ctx, repaired); err != nil {
+		return nil, err
+	}
+	for _, c := range fetched {
+		err := store.Put(ctx, c, func(c chunks.Chunk) chunks.GetAddrsCb {
+			return func(ctx context.Context, addrs hash.HashSet, _ chunks.PendingRefExists) error { return nil }
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// persist the fetched chunks without changing the root of the store, retrying if it's moved by another writer
+	for {
+		root, err := store.Root(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := store.Commit(ctx, root, root)
+		if err != nil {
+			return nil, err
+		} else if ok {
+			return repaired, nil
+		}
+	}
+}
+
+// fetchVerifiedChunks returns the chunks |addrs| which can be read from any of |sources| with data matching their
+// addresses. Chunks which can't be found in any of them are left out. A source which can't be read from, such as a
+// remote which can't be reached, is passed over rather than failing the repair.
+func fetchVerifiedChunks(ctx context.Context, addrs hash.HashSet, sources []*DoltDB) ([]chunks.Chunk, error) {
+	var found []chunks.Chunk
+	for addr := range addrs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, src := range sources {
+			c, err := datas.ChunkStoreFromDatabase(src.db).Get(ctx, addr)
+			if err == nil && !c.IsEmpty() && hash.Of(c.Data()) == addr {
+				found = append(found, c)
+				break
+			}
+		}
+	}
+	return found, nil
+}
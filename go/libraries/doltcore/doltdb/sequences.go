@@ -0,0 +1,181 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// SequenceMaxValue is the largest value a sequence may have. It's one less than the largest BIGINT, like in MariaDB, so
+// that the next value of a sequence which has run out can always be stored.
+const SequenceMaxValue = math.MaxInt64 - 1
+
+// ErrSequenceRunOut is returned when a sequence which doesn't cycle has no values left.
+var ErrSequenceRunOut = errors.New("sequence has run out")
+
+// Sequence is a row of the dolt_sequences table.
+type Sequence struct {
+	Name       string
+	StartValue int64
+	Increment  int64
+	MinValue   int64
+	MaxValue   int64
+	Cycle      bool
+	// NextValue is the next value of the sequence, or nil if the sequence hasn't been used yet
+	NextValue *int64
+}
+
+// Validate returns an error if the definition of the sequence is invalid.
+func (s Sequence) Validate() error {
+	switch {
+	case s.Increment <= 0:
+		return fmt.Errorf("invalid sequence '%s': %s must be positive", s.Name, SequencesIncrementCol)
+	case s.MaxValue > SequenceMaxValue:
+		return fmt.Errorf("invalid sequence '%s': %s must be at most %d", s.Name, SequencesMaxValueCol, int64(SequenceMaxValue))
+	case s.MinValue > s.MaxValue:
+		return fmt.Errorf("invalid sequence '%s': %s must not be greater than %s", s.Name, SequencesMinValueCol, SequencesMaxValueCol)
+	case s.StartValue < s.MinValue || s.StartValue > s.MaxValue:
+		return fmt.Errorf("invalid sequence '%s': %s must be between %s and %s", s.Name, SequencesStartValueCol, SequencesMinValueCol, SequencesMaxValueCol)
+	}
+	return nil
+}
+
+// Next returns the next value of the sequence, given that values before |atLeast| must not be returned, along with the
+// value which follows it. Returns ErrSequenceRunOut if the sequence doesn't cycle and has no values left.
+func (s Sequence) Next(atLeast int64) (value, next int64, err error) {
+	value = s.StartValue
+	if s.NextValue != nil {
+		value = *s.NextValue
+	}
+	if atLeast > value {
+		value = atLeast
+	}
+	if value > s.MaxValue {
+		if !s.Cycle {
+			return 0, 0, fmt.Errorf("%w: '%s'", ErrSequenceRunOut, s.Name)
+		}
+		value = s.MinValue
+	}
+	if value > math.MaxInt64-s.Increment {
+		// any value past the max value marks the sequence as used up
+		return value, s.MaxValue + 1, nil
+	}
+	return value, value + s.Increment, nil
+}
+
+// GetSequence returns the sequence named |name| from the dolt_sequences table of |root|. Like the names of tables,
+// sequence names are case-insensitive, since the table's key is collated case-insensitively. The name of the sequence
+// returned is the one it was created with.
+func GetSequence(ctx context.Context, root RootValue, name string) (Sequence, bool, error) {
+	table, found, err := root.GetTable(ctx, TableName{Name: SequencesTableName})
+	if err != nil {
+		return Sequence{}, false, err
+	}
+	if !found || table.Format() == types.Format_LD_1 {
+		return Sequence{}, false, nil
+	}
+	index, err := table.GetRowData(ctx)
+	if err != nil {
+		return Sequence{}, false, err
+	}
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return Sequence{}, false, err
+	}
+	keyDesc, valueDesc := sch.GetMapDescriptors()
+	if keyDesc.Count() != 1 || valueDesc.Count() != 6 {
+		return Sequence{}, false, fmt.Errorf("%s had unexpected schema, this should never happen", SequencesTableName)
+	}
+
+	m := durable.ProllyMapFromIndex(index)
+	kb := val.NewTupleBuilder(keyDesc)
+	kb.PutString(0, name)
+
+	var seq Sequence
+	err = m.Get(ctx, kb.Build(m.Pool()), func(keyTuple, valueTuple val.Tuple) error {
+		if valueTuple == nil {
+			return nil
+		}
+		found = true
+		seq.Name, _ = keyDesc.GetString(0, keyTuple)
+		seq.StartValue, _ = valueDesc.GetInt64(0, valueTuple)
+		seq.Increment, _ = valueDesc.GetInt64(1, valueTuple)
+		seq.MinValue, _ = valueDesc.GetInt64(2, valueTuple)
+		seq.MaxValue, _ = valueDesc.GetInt64(3, valueTuple)
+		cycle, _ := valueDesc.GetInt8(4, valueTuple)
+		seq.Cycle = cycle != 0
+		if next, ok := valueDesc.GetInt64(5, valueTuple); ok {
+			seq.NextValue = &next
+		}
+		return nil
+	})
+	if err != nil || !found {
+		return Sequence{}, false, err
+	}
+	return seq, true, nil
+}
+
+// GetSequenceHighWater returns the largest next value of the sequence |name| on any branch of |ddb|, read from the
+// working set of each branch, or from its head if it has no working set. Returns false if no branch has used the
+// sequence.
+func (ddb *DoltDB) GetSequenceHighWater(ctx context.Context, name string) (int64, bool, error) {
+	branches, err := ddb.GetBranches(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	highWater, found := int64(math.MinInt64), false
+	for _, b := range branches {
+		var rootish Rootish
+		wsRef, err := ref.WorkingSetRefForHead(b)
+		if err != nil {
+			return 0, false, err
+		}
+		ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+		if err == ErrWorkingSetNotFound {
+			// use the branch head if there isn't a working set for it
+			cm, err := ddb.ResolveCommitRef(ctx, b)
+			if err != nil {
+				return 0, false, err
+			}
+			rootish = cm
+		} else if err != nil {
+			return 0, false, err
+		} else {
+			rootish = ws
+		}
+
+		root, err := rootish.ResolveRootValue(ctx)
+		if err != nil {
+			return 0, false, err
+		}
+		seq, ok, err := GetSequence(ctx, root, name)
+		if err != nil {
+			return 0, false, err
+		}
+		if ok && seq.NextValue != nil && *seq.NextValue > highWater {
+			highWater, found = *seq.NextValue, true
+		}
+	}
+	return highWater, found, nil
+}
@@ -18,8 +18,13 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
@@ -52,8 +57,94 @@ func init() {
 		docTextCol,
 	)
 	DocsSchema = schema.MustSchemaFromCols(doltDocsColumns)
+
+	testQueryCol, err := schema.NewColumnWithTypeInfo(TestsQueryCol, schema.DoltTestsQueryTag, typeinfo.LongTextType, false, "", false, "", schema.NotNullConstraint{})
+	if err != nil {
+		panic(err)
+	}
+	testValueCol, err := schema.NewColumnWithTypeInfo(TestsAssertionValueCol, schema.DoltTestsAssertionValueTag, typeinfo.LongTextType, false, "", false, "")
+	if err != nil {
+		panic(err)
+	}
+	TestsSchema = schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn(TestsNameCol, schema.DoltTestsNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(TestsGroupCol, schema.DoltTestsGroupTag, types.StringKind, false),
+		testQueryCol,
+		schema.NewColumn(TestsAssertionTypeCol, schema.DoltTestsAssertionTypeTag, types.StringKind, false, schema.NotNullConstraint{}),
+		schema.NewColumn(TestsAssertionComparatorCol, schema.DoltTestsAssertionComparatorTag, types.StringKind, false, schema.NotNullConstraint{}),
+		testValueCol,
+	))
+
+	allowedUsersCol, err := schema.NewColumnWithTypeInfo(BranchProtectionAllowedUsersCol, schema.DoltBranchProtectionAllowedUsersTag, typeinfo.LongTextType, false, "", false, "")
+	if err != nil {
+		panic(err)
+	}
+	BranchProtectionSchema = schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn(BranchProtectionPatternCol, schema.DoltBranchProtectionPatternTag, types.StringKind, true, schema.NotNullConstraint{}),
+		allowedUsersCol,
+	))
+
+	// like the names of tables, the names of sequences are case-insensitive
+	sequenceNameType := typeinfo.CreateVarStringTypeFromSqlType(gmstypes.MustCreateString(sqltypes.VarChar, typeinfo.MaxVarcharLength/16, sql.Collation_utf8mb4_0900_ai_ci))
+	sequenceNameCol, err := schema.NewColumnWithTypeInfo(SequencesNameCol, schema.DoltSequencesNameTag, sequenceNameType, true, "", false, "", schema.NotNullConstraint{})
+	if err != nil {
+		panic(err)
+	}
+	SequencesSchema = schema.MustSchemaFromCols(schema.NewColCollection(
+		sequenceNameCol,
+		mustNewSequenceColumn(SequencesStartValueCol, schema.DoltSequencesStartValueTag, typeinfo.Int64Type, "1"),
+		mustNewSequenceColumn(SequencesIncrementCol, schema.DoltSequencesIncrementTag, typeinfo.Int64Type, "1"),
+		mustNewSequenceColumn(SequencesMinValueCol, schema.DoltSequencesMinValueTag, typeinfo.Int64Type, "1"),
+		mustNewSequenceColumn(SequencesMaxValueCol, schema.DoltSequencesMaxValueTag, typeinfo.Int64Type, strconv.FormatInt(SequenceMaxValue, 10)),
+		mustNewSequenceColumn(SequencesCycleCol, schema.DoltSequencesCycleTag, typeinfo.Int8Type, "0"),
+		schema.NewColumn(SequencesNextValueCol, schema.DoltSequencesNextValueTag, types.IntKind, false),
+	))
+
+	assertionQueryCol, err := schema.NewColumnWithTypeInfo(AssertionsQueryCol, schema.DoltAssertionsQueryTag, typeinfo.LongTextType, false, "", false, "", schema.NotNullConstraint{})
+	if err != nil {
+		panic(err)
+	}
+	AssertionsSchema = schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn(AssertionsNameCol, schema.DoltAssertionsNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(AssertionsTableNameCol, schema.DoltAssertionsTableNameTag, types.StringKind, false, schema.NotNullConstraint{}),
+		assertionQueryCol,
+	))
+
+	writableCol, err := schema.NewColumnWithTypeInfo(ExternalTablesWritableCol, schema.DoltExternalTablesWritableTag, typeinfo.Int8Type, false, "0", false, "", schema.NotNullConstraint{})
+	if err != nil {
+		panic(err)
+	}
+	ExternalTablesSchema = schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn(ExternalTablesNameCol, schema.DoltExternalTablesNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(ExternalTablesConnectionCol, schema.DoltExternalTablesConnectionTag, types.StringKind, false, schema.NotNullConstraint{}),
+		schema.NewColumn(ExternalTablesRemoteTableCol, schema.DoltExternalTablesRemoteTableTag, types.StringKind, false, schema.NotNullConstraint{}),
+		writableCol,
+	))
 }
 
+func mustNewSequenceColumn(name string, tag uint64, ti typeinfo.TypeInfo, defaultVal string) schema.Column {
+	col, err := schema.NewColumnWithTypeInfo(name, tag, ti, false, defaultVal, false, "", schema.NotNullConstraint{})
+	if err != nil {
+		panic(err)
+	}
+	return col
+}
+
+// TestsSchema is the schema of the dolt_tests table.
+var TestsSchema schema.Schema
+
+// BranchProtectionSchema is the schema of the dolt_branch_protection table.
+var BranchProtectionSchema schema.Schema
+
+// SequencesSchema is the schema of the dolt_sequences table.
+var SequencesSchema schema.Schema
+
+// AssertionsSchema is the schema of the dolt_assertions table.
+var AssertionsSchema schema.Schema
+
+// ExternalTablesSchema is the schema of the dolt_external_tables table.
+var ExternalTablesSchema schema.Schema
+
 // HasDoltPrefix returns a boolean whether or not the provided string is prefixed with the DoltNamespace. Users should
 // not be able to create tables in this reserved namespace.
 func HasDoltPrefix(s string) bool {
@@ -124,11 +215,12 @@ func GetPersistedSystemTables(ctx context.Context, root RootValue) ([]string, er
 	return funcitr.FilterStrings(tn, HasDoltPrefix), nil
 }
 
-// GetGeneratedSystemTables returns table names of all generated system tables.
+// GetGeneratedSystemTables returns table names of all generated system tables. Per-table system tables, such as
+// dolt_diff_$tablename, are only generated for user tables, not for system tables like dolt_docs.
 func GetGeneratedSystemTables(ctx context.Context, root RootValue) ([]string, error) {
 	s := set.NewStrSet(generatedSystemTables)
 
-	tn, err := root.GetTableNames(ctx, DefaultSchemaName)
+	tn, err := GetNonSystemTableNames(ctx, root)
 	if err != nil {
 		return nil, err
 	}
@@ -163,6 +255,11 @@ var writeableSystemTables = []string{
 	ProceduresTableName,
 	IgnoreTableName,
 	RebaseTableName,
+	TestsTableName,
+	BranchProtectionTableName,
+	SequencesTableName,
+	AssertionsTableName,
+	ExternalTablesTableName,
 }
 
 var persistedSystemTables = []string{
@@ -171,6 +268,11 @@ var persistedSystemTables = []string{
 	SchemasTableName,
 	ProceduresTableName,
 	IgnoreTableName,
+	TestsTableName,
+	BranchProtectionTableName,
+	SequencesTableName,
+	AssertionsTableName,
+	ExternalTablesTableName,
 }
 
 var generatedSystemTables = []string{
@@ -210,6 +312,82 @@ const (
 	DocTextColumnName = "doc_text"
 )
 
+const (
+	// TestsTableName is the name of the dolt table containing the data tests run by `dolt ci run`
+	TestsTableName = "dolt_tests"
+	// TestsNameCol is the name of the pk column of the tests table
+	TestsNameCol = "test_name"
+	// TestsGroupCol is the column containing an optional group name, used to run a subset of the tests
+	TestsGroupCol = "test_group"
+	// TestsQueryCol is the column containing the query whose results are checked by the test
+	TestsQueryCol = "test_query"
+	// TestsAssertionTypeCol is the column containing what is checked about the query results, e.g. expected_rows
+	TestsAssertionTypeCol = "assertion_type"
+	// TestsAssertionComparatorCol is the column containing the comparison operator of the assertion, e.g. ==
+	TestsAssertionComparatorCol = "assertion_comparator"
+	// TestsAssertionValueCol is the column containing the value the query results are compared to
+	TestsAssertionValueCol = "assertion_value"
+)
+
+const (
+	// BranchProtectionTableName is the name of the dolt table containing the rules for protected branches
+	BranchProtectionTableName = "dolt_branch_protection"
+	// BranchProtectionPatternCol is the pk column of the branch protection table, a pattern matching branch names
+	BranchProtectionPatternCol = "branch_pattern"
+	// BranchProtectionAllowedUsersCol is the column containing a comma separated list of the users allowed to merge
+	// into the matching branches
+	BranchProtectionAllowedUsersCol = "allowed_users"
+)
+
+const (
+	// SequencesTableName is the name of the dolt table containing the sequences of a database
+	SequencesTableName = "dolt_sequences"
+	// SequencesNameCol is the pk column of the sequences table, the name of the sequence
+	SequencesNameCol = "name"
+	// SequencesStartValueCol is the column containing the first value of the sequence
+	SequencesStartValueCol = "start_value"
+	// SequencesIncrementCol is the column containing the difference between consecutive values of the sequence
+	SequencesIncrementCol = "increment"
+	// SequencesMinValueCol is the column containing the smallest value of the sequence, which it restarts from if it cycles
+	SequencesMinValueCol = "min_value"
+	// SequencesMaxValueCol is the column containing the largest value of the sequence
+	SequencesMaxValueCol = "max_value"
+	// SequencesCycleCol is the column containing whether the sequence restarts from its min value after its max value
+	SequencesCycleCol = "cycle"
+	// SequencesNextValueCol is the column containing the next value of the sequence, which is NULL until the sequence
+	// is first used
+	SequencesNextValueCol = "next_value"
+)
+
+const (
+	// AssertionsTableName is the name of the dolt table containing the assertions checked before commits are made
+	AssertionsTableName = "dolt_assertions"
+	// AssertionsNameCol is the pk column of the assertions table, the name of the assertion
+	AssertionsNameCol = "name"
+	// AssertionsTableNameCol is the column containing the name of the table whose rows the assertion checks
+	AssertionsTableNameCol = "table_name"
+	// AssertionsQueryCol is the column containing the query which returns the primary keys of the rows violating the
+	// assertion
+	AssertionsQueryCol = "query"
+)
+
+const (
+	// ExternalTablesTableName is the name of the dolt table containing the definitions of the tables of a database which
+	// proxy to tables on other servers
+	ExternalTablesTableName = "dolt_external_tables"
+	// ExternalTablesNameCol is the pk column of the external tables table, the name of the table in this database
+	ExternalTablesNameCol = "name"
+	// ExternalTablesConnectionCol is the column containing the name of the connection to the server the table is on,
+	// whose URL is given by the server's DOLT_EXTERNAL_CONNECTION_<NAME> environment variable
+	ExternalTablesConnectionCol = "connection"
+	// ExternalTablesRemoteTableCol is the column containing the name of the table on the server, which may be qualified
+	// by its database or schema
+	ExternalTablesRemoteTableCol = "remote_table"
+	// ExternalTablesWritableCol is the column containing whether writes to the table are made to the table on the
+	// server, rather than rejected
+	ExternalTablesWritableCol = "writable"
+)
+
 const (
 	// DoltQueryCatalogTableName is the name of the query catalog table
 	DoltQueryCatalogTableName = "dolt_query_catalog"
@@ -315,6 +493,24 @@ const (
 
 	// StatisticsTableName is the statistics system table name
 	StatisticsTableName = "dolt_statistics"
+
+	// CloneStatusTableName is the clone status system table name
+	CloneStatusTableName = "dolt_clone_status"
+
+	// StorageHealthTableName is the storage health system table name
+	StorageHealthTableName = "dolt_storage_health"
+
+	// DroppedDatabasesTableName is the dropped databases system table name
+	DroppedDatabasesTableName = "dolt_dropped_databases"
+
+	// NotesTableName is the notes system table name, and the name of the table holding the notes in a notes commit
+	NotesTableName = "dolt_notes"
+
+	// MergeQueueTableName is the merge queue system table name
+	MergeQueueTableName = "dolt_merge_queue"
+
+	// ChangeCursorsTableName is the change cursors system table name
+	ChangeCursorsTableName = "dolt_change_cursors"
 )
 
 const (
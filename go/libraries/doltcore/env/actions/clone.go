@@ -157,11 +157,21 @@ func sortedKeys(m map[string]iohelp.ReadStats) []string {
 	return keys
 }
 
+// CloneProgressFunc consumes the table file events generated while a full clone downloads table files from the
+// remote. It must read from |eventCh| until it is closed.
+type CloneProgressFunc func(eventCh <-chan pull.TableFileEvent)
+
 // CloneRemote - common entry point for both dolt_clone() and `dolt clone`
 // The database must be initialized with a remote before calling this function.
 //
 // The `branch` parameter is the branch to clone. If it is empty, the default branch is used.
 func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, singleBranch bool, depth int, dEnv *env.DoltEnv) error {
+	return CloneRemoteWithProgress(ctx, srcDB, remoteName, branch, singleBranch, depth, dEnv, clonePrint)
+}
+
+// CloneRemoteWithProgress is CloneRemote, but reports the progress of downloading table files to |progress| instead
+// of printing it to the terminal.
+func CloneRemoteWithProgress(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, singleBranch bool, depth int, dEnv *env.DoltEnv, progress CloneProgressFunc) error {
 	// We support two forms of cloning: full and shallow. These two approaches have little in common, with the exception
 	// of the first and last steps. Determining the branch to check out and setting the working set to the checked out commit.
 
@@ -177,7 +187,7 @@ func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 
 	// Step 1) Pull the remote information we care about to a local disk.
 	if depth <= 0 {
-		checkedOutCommit, err = fullClone(ctx, srcDB, dEnv, srcRefHashes, branch, remoteName, singleBranch)
+		checkedOutCommit, err = fullClone(ctx, srcDB, dEnv, srcRefHashes, branch, remoteName, singleBranch, progress)
 	} else {
 		checkedOutCommit, err = shallowCloneDataPull(ctx, dEnv.DbData(), srcDB, remoteName, branch, depth)
 	}
@@ -247,13 +257,13 @@ func getSrcRefs(ctx context.Context, branch string, srcDB *doltdb.DoltDB, dEnv *
 	return srcRefHashes, branch, nil
 }
 
-func fullClone(ctx context.Context, srcDB *doltdb.DoltDB, dEnv *env.DoltEnv, srcRefHashes []doltdb.RefWithHash, branch, remoteName string, singleBranch bool) (*doltdb.Commit, error) {
+func fullClone(ctx context.Context, srcDB *doltdb.DoltDB, dEnv *env.DoltEnv, srcRefHashes []doltdb.RefWithHash, branch, remoteName string, singleBranch bool, progress CloneProgressFunc) (*doltdb.Commit, error) {
 	eventCh := make(chan pull.TableFileEvent, 128)
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		clonePrint(eventCh)
+		progress(eventCh)
 	}()
 
 	err := srcDB.Clone(ctx, dEnv.DoltDB, eventCh)
@@ -32,6 +32,8 @@ type CommitStagedProps struct {
 	Force      bool
 	Name       string
 	Email      string
+	// Metadata is arbitrary key/value metadata to attach to the commit
+	Metadata map[string]string
 }
 
 // GetCommitStaged returns a new pending commit with the roots and commit properties given.
@@ -106,6 +108,7 @@ func GetCommitStaged(
 	if err != nil {
 		return nil, err
 	}
+	meta.Metadata = props.Metadata
 
 	return db.NewPendingCommit(ctx, roots, mergeParents, meta)
 }
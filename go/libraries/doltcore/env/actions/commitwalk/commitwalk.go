@@ -17,6 +17,7 @@ package commitwalk
 import (
 	"container/heap"
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -406,3 +407,67 @@ func (i *dotDotCommiterator) Reset(ctx context.Context) error {
 	}
 	return nil
 }
+
+// CountAheadBehind returns the number of commits |from| is ahead of and behind |to|, counting back from each of them to
+// their common ancestor. It returns doltdb.ErrNoCommonAncestor if they have none.
+func CountAheadBehind(ctx context.Context, ddb *doltdb.DoltDB, from, to *doltdb.Commit) (ahead uint64, behind uint64, err error) {
+	fromHash, err := from.HashOf()
+	if err != nil {
+		return 0, 0, err
+	}
+	toHash, err := to.HashOf()
+	if err != nil {
+		return 0, 0, err
+	}
+	if fromHash == toHash {
+		return 0, 0, nil
+	}
+
+	optCmt, err := doltdb.GetCommitAncestor(ctx, from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+	ancestor, ok := optCmt.ToCommit()
+	if !ok {
+		return 0, 0, doltdb.ErrGhostCommitEncountered
+	}
+	ancestorHash, err := ancestor.HashOf()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	behind, err = countCommitsInRange(ctx, ddb, toHash, ancestorHash)
+	if err != nil {
+		return 0, 0, err
+	}
+	ahead, err = countCommitsInRange(ctx, ddb, fromHash, ancestorHash)
+	if err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// countCommitsInRange returns the number of commits between the given starting point to trace back to the given target point.
+// The starting commit must be a descendant of the target commit. Target commit must be a common ancestor commit.
+func countCommitsInRange(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash, targetCommitHash hash.Hash) (uint64, error) {
+	itr, iErr := GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{startCommitHash}, nil)
+	if iErr != nil {
+		return 0, iErr
+	}
+	count := 0
+	for {
+		nextHash, _, err := itr.Next(ctx)
+		if err == io.EOF {
+			return 0, fmt.Errorf("no match found to ancestor commit")
+		} else if err != nil {
+			return 0, err
+		}
+
+		if nextHash == targetCommitHash {
+			break
+		}
+		count += 1
+	}
+
+	return uint64(count), nil
+}
@@ -18,12 +18,16 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
 	"github.com/google/uuid"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
@@ -40,6 +44,11 @@ type typeInfoSet map[typeinfo.TypeInfo]struct{}
 const (
 	maxUint24 = 1<<24 - 1
 	minInt24  = -1 << 23
+
+	// float32Digits and float64Digits are the most significant digits a decimal number can have and still be stored
+	// exactly by a float and a double. Numbers with more digits are inferred as a wider type.
+	float32Digits = 6
+	float64Digits = 15
 )
 
 // InferenceArgs are arguments that can be passed to the schema inferrer to modify it's inference behavior.
@@ -53,39 +62,91 @@ type InferenceArgs interface {
 	// a fractional component greater than or equal to 0.001 will be treated as a float (1.0 would be an int, 1.0009 would
 	// be an int, 1.001 would be a float, 1.1 would be a float, etc)
 	FloatThreshold() float64
+	// SampleRows is the number of rows read from the start of the file to infer types from. If it's 0, rows are sampled
+	// from the whole file, more sparsely as the file goes on.
+	SampleRows() int
+	// ColTypeOverrides are the types of the columns, by their names in the inferred schema, which are given instead of
+	// being inferred.
+	ColTypeOverrides() map[string]typeinfo.TypeInfo
+}
+
+// InferenceReport describes how the types of the columns of a table reader were inferred.
+type InferenceReport struct {
+	// RowsRead is the number of rows read, and RowsSampled the number of them whose values types were inferred from.
+	RowsRead    int
+	RowsSampled int
+	Columns     []ColumnInference
+}
+
+// ColumnInference describes how the type of a column was inferred.
+type ColumnInference struct {
+	Name string
+	Type typeinfo.TypeInfo
+	// SampledTypes are the types of the sampled values, if there was more than one, which Type is common to.
+	SampledTypes []typeinfo.TypeInfo
+	// Nullable is set if empty values were sampled.
+	Nullable bool
+	// Overridden is set if Type was given by the InferenceArgs rather than inferred.
+	Overridden bool
 }
 
 // InferColumnTypesFromTableReader will infer a data types from a table reader.
 func InferColumnTypesFromTableReader(ctx context.Context, rd table.ReadCloser, args InferenceArgs) (*schema.ColCollection, error) {
+	cols, _, err := InferColumnTypesWithReport(ctx, rd, args)
+	return cols, err
+}
+
+// InferColumnTypesWithReport infers the types of the columns of a table reader like InferColumnTypesFromTableReader,
+// and also returns a report of how each type was inferred.
+func InferColumnTypesWithReport(ctx context.Context, rd table.ReadCloser, args InferenceArgs) (*schema.ColCollection, *InferenceReport, error) {
+	i := newInferrer(rd.GetSchema(), args)
+
+	if args.SampleRows() > 0 {
+		for i.rowsRead < args.SampleRows() {
+			r, err := rd.ReadRow(ctx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, nil, err
+			}
+			i.rowsRead++
+			if err = i.processRow(r); err != nil {
+				return nil, nil, err
+			}
+		}
+		return i.inferColumnTypes()
+	}
+
 	// for large imports, we want to sample a subset of the rows.
 	// skip through the file in an exponential manner
 	const exp = 1.02
 
-	var curr, prev row.Row
-	i := newInferrer(rd.GetSchema(), args)
+	var last row.Row
+	lastProcessed := false
 OUTER:
 	for j := 0; true; j++ {
-		var err error
-
+		var curr row.Row
 		next := int(math.Pow(exp, float64(j)))
 		for n := 0; n < next; n++ {
-			curr, err = rd.ReadRow(ctx)
+			r, err := rd.ReadRow(ctx)
 			if err == io.EOF {
 				break OUTER
 			} else if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			prev = curr
+			i.rowsRead++
+			curr, last, lastProcessed = r, r, false
 		}
-		if err = i.processRow(curr); err != nil {
-			return nil, err
+		if err := i.processRow(curr); err != nil {
+			return nil, nil, err
 		}
+		lastProcessed = true
 	}
 
 	// always process last row
-	if prev != nil {
-		if err := i.processRow(prev); err != nil {
-			return nil, err
+	if last != nil && !lastProcessed {
+		if err := i.processRow(last); err != nil {
+			return nil, nil, err
 		}
 	}
 
@@ -95,9 +156,23 @@ OUTER:
 type inferrer struct {
 	readerSch      schema.Schema
 	inferSets      map[uint64]typeInfoSet
+	digits         map[uint64]*numericDigits
 	nullable       *set.Uint64Set
 	mapper         rowconv.NameMapper
 	floatThreshold float64
+	overrides      map[string]typeinfo.TypeInfo
+
+	rowsRead    int
+	rowsSampled int
+}
+
+// numericDigits tracks the widest numbers sampled from a column, so that a decimal type which holds all of them can be
+// inferred.
+type numericDigits struct {
+	intDigits int
+	scale     int
+	// exponent is set if a number in scientific notation was sampled, which a decimal type can't be inferred from
+	exponent bool
 }
 
 func newInferrer(readerSch schema.Schema, args InferenceArgs) *inferrer {
@@ -110,38 +185,69 @@ func newInferrer(readerSch schema.Schema, args InferenceArgs) *inferrer {
 	return &inferrer{
 		readerSch:      readerSch,
 		inferSets:      inferSets,
+		digits:         make(map[uint64]*numericDigits),
 		nullable:       set.NewUint64Set(nil),
 		mapper:         args.ColNameMapper(),
 		floatThreshold: args.FloatThreshold(),
+		overrides:      args.ColTypeOverrides(),
 	}
 }
 
 // inferColumnTypes returns TableReader's columns with updated TypeInfo and columns names
-func (inf *inferrer) inferColumnTypes() (*schema.ColCollection, error) {
-
-	inferredTypes := make(map[uint64]typeinfo.TypeInfo)
-	for tag, ts := range inf.inferSets {
-		inferredTypes[tag] = findCommonType(ts)
-	}
+func (inf *inferrer) inferColumnTypes() (*schema.ColCollection, *InferenceReport, error) {
+	report := &InferenceReport{RowsRead: inf.rowsRead, RowsSampled: inf.rowsSampled}
+	overridden := set.NewStrSet(nil)
 
 	var cols []schema.Column
 	_ = inf.readerSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		col.Name = inf.mapper.Map(col.Name)
-		col.Kind = inferredTypes[tag].NomsKind()
-		col.TypeInfo = inferredTypes[tag]
+		colInf := ColumnInference{Name: col.Name, Nullable: inf.nullable.Contains(tag)}
+		for ti := range inf.inferSets[tag] {
+			if ti != typeinfo.UnknownType {
+				colInf.SampledTypes = append(colInf.SampledTypes, ti)
+			}
+		}
+		if len(colInf.SampledTypes) < 2 {
+			colInf.SampledTypes = nil
+		}
+		sort.Slice(colInf.SampledTypes, func(i, j int) bool {
+			return colInf.SampledTypes[i].String() < colInf.SampledTypes[j].String()
+		})
+
+		if ti, ok := inf.overrides[col.Name]; ok {
+			colInf.Type = ti
+			colInf.Overridden = true
+			overridden.Add(col.Name)
+		} else {
+			colInf.Type = findCommonType(inf.inferSets[tag])
+			if isDecimalType(colInf.Type) {
+				colInf.Type = inf.digits[tag].decimalType()
+			}
+		}
+
+		col.Kind = colInf.Type.NomsKind()
+		col.TypeInfo = colInf.Type
 		col.Tag = schema.ReservedTagMin + tag
 
 		// for large imports, it is possible to miss all the null values, so we cannot accurately add not null constraint
 		col.Constraints = []schema.ColConstraint(nil)
 
 		cols = append(cols, col)
+		report.Columns = append(report.Columns, colInf)
 		return false, nil
 	})
 
-	return schema.NewColCollection(cols...), nil
+	for name := range inf.overrides {
+		if !overridden.Contains(name) {
+			return nil, nil, fmt.Errorf("a type was given for column %s, which isn't in the file", name)
+		}
+	}
+
+	return schema.NewColCollection(cols...), report, nil
 }
 
 func (inf *inferrer) processRow(r row.Row) error {
+	inf.rowsSampled++
 	_, err := r.IterSchema(inf.readerSch, func(tag uint64, val types.Value) (stop bool, err error) {
 		if val == nil {
 			inf.nullable.Add(tag)
@@ -150,12 +256,81 @@ func (inf *inferrer) processRow(r row.Row) error {
 		strVal := string(val.(types.String))
 		typeInfo := leastPermissiveType(strVal, inf.floatThreshold)
 		inf.inferSets[tag][typeInfo] = struct{}{}
+		if isNumericType(typeInfo) {
+			d, ok := inf.digits[tag]
+			if !ok {
+				d = &numericDigits{}
+				inf.digits[tag] = d
+			}
+			d.add(strings.TrimSpace(strVal))
+		}
 		return false, nil
 	})
 
 	return err
 }
 
+// add widens |d| to hold |strVal|, a number.
+func (d *numericDigits) add(strVal string) {
+	if strings.ContainsAny(strVal, "eE") {
+		d.exponent = true
+		return
+	}
+	intDigits, scale := decimalDigits(strVal)
+	d.intDigits = max(d.intDigits, intDigits)
+	d.scale = max(d.scale, scale)
+}
+
+// decimalType returns the narrowest decimal type which holds every number |d| was widened to hold, or a double or a
+// string if no decimal type can.
+func (d *numericDigits) decimalType() typeinfo.TypeInfo {
+	if d == nil || d.exponent {
+		return typeinfo.Float64Type
+	}
+	if ti, ok := newDecimalType(d.intDigits+d.scale, d.scale); ok {
+		return ti
+	}
+	return typeinfo.StringDefaultType
+}
+
+// decimalDigits returns the number of digits of |strVal|, a number without an exponent, before and after its decimal
+// point, ignoring its sign and the leading zeros of its integer part.
+func decimalDigits(strVal string) (intDigits, scale int) {
+	intPart, fracPart, _ := strings.Cut(strings.TrimLeft(strVal, "+-"), ".")
+	return len(strings.TrimLeft(intPart, "0")), len(fracPart)
+}
+
+// newDecimalType returns a decimal type with |precision| and |scale|, if they're within the limits of decimal types.
+func newDecimalType(precision, scale int) (typeinfo.TypeInfo, bool) {
+	if precision == 0 {
+		precision = 1
+	}
+	if precision > gmstypes.DecimalTypeMaxPrecision || scale > gmstypes.DecimalTypeMaxScale {
+		return nil, false
+	}
+	ti, err := typeinfo.FromSqlType(gmstypes.MustCreateDecimalType(uint8(precision), uint8(scale)))
+	if err != nil {
+		return nil, false
+	}
+	return ti, true
+}
+
+func isDecimalType(ti typeinfo.TypeInfo) bool {
+	return ti.GetTypeIdentifier() == typeinfo.DecimalTypeIdentifier
+}
+
+func isNumericType(ti typeinfo.TypeInfo) bool {
+	if isDecimalType(ti) || ti == typeinfo.Uint64Type {
+		return true
+	}
+	for _, nt := range numericTypes() {
+		if ti == nt {
+			return true
+		}
+	}
+	return false
+}
+
 func leastPermissiveType(strVal string, floatThreshold float64) typeinfo.TypeInfo {
 	if len(strVal) == 0 {
 		return typeinfo.UnknownType
@@ -204,8 +379,20 @@ func leastPermissiveNumericType(strVal string, floatThreshold float64) (ti typei
 			return typeinfo.UnknownType
 		}
 
-		if math.Abs(f) < math.MaxFloat32 {
+		// numbers in scientific notation are floats, others are the narrowest type which stores all of their digits
+		intDigits, scale := decimalDigits(strVal)
+		if strings.ContainsAny(strVal, "eE") {
+			if math.Abs(f) < math.MaxFloat32 {
+				ti = typeinfo.Float32Type
+			} else {
+				ti = typeinfo.Float64Type
+			}
+		} else if intDigits+scale <= float32Digits {
 			ti = typeinfo.Float32Type
+		} else if intDigits+scale <= float64Digits {
+			ti = typeinfo.Float64Type
+		} else if dec, ok := newDecimalType(intDigits+scale, scale); ok {
+			ti = dec
 		} else {
 			ti = typeinfo.Float64Type
 		}
@@ -221,8 +408,10 @@ func leastPermissiveNumericType(strVal string, floatThreshold float64) (ti typei
 			if decimalPart < floatThreshold {
 				if ti == typeinfo.Float32Type {
 					ti = typeinfo.Int32Type
-				} else {
+				} else if intDigits <= 18 {
 					ti = typeinfo.Int64Type
+				} else if dec, ok := newDecimalType(intDigits, 0); ok {
+					ti = dec
 				}
 			}
 		}
@@ -232,8 +421,17 @@ func leastPermissiveNumericType(strVal string, floatThreshold float64) (ti typei
 	// always parse as signed int
 	i, err := strconv.ParseInt(strVal, 10, 64)
 
-	// use string for out of range
+	// use an unsigned int or a decimal for out of range
 	if errors.Is(err, strconv.ErrRange) {
+		if strings.TrimLeft(strVal, "+-")[0] == '0' {
+			return typeinfo.StringDefaultType
+		}
+		if _, err := strconv.ParseUint(strVal, 10, 64); err == nil {
+			return typeinfo.Uint64Type
+		}
+		if dec, ok := newDecimalType(len(strings.TrimLeft(strVal, "+-")), 0); ok {
+			return dec
+		}
 		return typeinfo.StringDefaultType
 	}
 
@@ -260,8 +458,9 @@ func leastPermissiveChronoType(strVal string) typeinfo.TypeInfo {
 
 	dt, err := typeinfo.StringDefaultType.ConvertToType(context.Background(), nil, typeinfo.DatetimeType, types.String(strVal))
 	if err == nil {
+		// a datetime at midnight is still a datetime, only values without a time are dates
 		t := time.Time(dt.(types.Timestamp))
-		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 && !strings.Contains(strVal, ":") {
 			return typeinfo.DateType
 		}
 
@@ -345,8 +544,8 @@ func findCommonType(ts typeInfoSet) typeinfo.TypeInfo {
 	}
 
 	hasNumeric := false
-	for _, nt := range numericTypes() {
-		if setHasType(ts, nt) {
+	for ti := range ts {
+		if isNumericType(ti) {
 			hasNumeric = true
 			break
 		}
@@ -391,6 +590,20 @@ func findCommonType(ts typeInfoSet) typeinfo.TypeInfo {
 }
 
 func findCommonNumericType(nums typeInfoSet) typeinfo.TypeInfo {
+	// decimals only have decimals in common with other numbers, and so do unsigned ints with signed ints, since the
+	// signed ints may be negative. The decimal returned holds every decimal and int in |nums|.
+	hasFloat := setHasType(nums, typeinfo.Float32Type) || setHasType(nums, typeinfo.Float64Type)
+	hasDecimal := false
+	for ti := range nums {
+		hasDecimal = hasDecimal || isDecimalType(ti)
+	}
+	if hasDecimal || (setHasType(nums, typeinfo.Uint64Type) && !hasFloat && len(nums) > 1) {
+		return commonDecimalType(nums)
+	}
+	if setHasType(nums, typeinfo.Uint64Type) && hasFloat {
+		return typeinfo.Float64Type
+	}
+
 	// find a common numeric type
 	// iterate through types from most to least permissive
 	// return the most permissive type found
@@ -407,6 +620,7 @@ func findCommonNumericType(nums typeInfoSet) typeinfo.TypeInfo {
 		typeinfo.Int24Type,
 		typeinfo.Int16Type,
 		typeinfo.Int8Type,
+		typeinfo.Uint64Type,
 	}
 	for _, numType := range mostToLeast {
 		if setHasType(nums, numType) {
@@ -417,6 +631,32 @@ func findCommonNumericType(nums typeInfoSet) typeinfo.TypeInfo {
 	panic("unreachable")
 }
 
+// commonDecimalType returns a decimal type which holds the values of the decimal and int types in |nums|.
+func commonDecimalType(nums typeInfoSet) typeinfo.TypeInfo {
+	intDigits := map[typeinfo.TypeInfo]int{
+		typeinfo.Int8Type:   3,
+		typeinfo.Int16Type:  5,
+		typeinfo.Int24Type:  7,
+		typeinfo.Int32Type:  10,
+		typeinfo.Int64Type:  19,
+		typeinfo.Uint64Type: 20,
+	}
+	var maxIntDigits, maxScale int
+	for ti := range nums {
+		if isDecimalType(ti) {
+			dt := ti.ToSqlType().(sql.DecimalType)
+			maxIntDigits = max(maxIntDigits, int(dt.Precision())-int(dt.Scale()))
+			maxScale = max(maxScale, int(dt.Scale()))
+		} else {
+			maxIntDigits = max(maxIntDigits, intDigits[ti])
+		}
+	}
+	if ti, ok := newDecimalType(maxIntDigits+maxScale, maxScale); ok {
+		return ti
+	}
+	return typeinfo.StringDefaultType
+}
+
 func findCommonChronoType(chronos typeInfoSet) typeinfo.TypeInfo {
 	if len(chronos) == 1 {
 		for ct := range chronos {
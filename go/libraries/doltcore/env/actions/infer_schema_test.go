@@ -20,6 +20,7 @@ import (
 	"math"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,7 +58,9 @@ func TestLeastPermissiveType(t *testing.T) {
 		{"negative one point 999 with FT of 1.0", "-1.999", 1.0, typeinfo.Int32Type},
 		{"zero point zero zero zero zero", "0.0000", 0.0, typeinfo.Float32Type},
 		{"max int", strconv.FormatUint(math.MaxInt64, 10), 0.0, typeinfo.Int64Type},
-		{"bigger than max int", strconv.FormatUint(math.MaxUint64, 10) + "0", 0.0, typeinfo.StringDefaultType},
+		{"bigger than max int", strconv.FormatUint(math.MaxUint64, 10) + "0", 0.0, mustDecimalType(21, 0)},
+		{"money", "19.99", 0.0, typeinfo.Float32Type},
+		{"more digits than a double", "12345678901234.567", 0.0, mustDecimalType(17, 3)},
 	}
 
 	for _, test := range tests {
@@ -78,15 +81,20 @@ func TestLeastPermissiveNumericType(t *testing.T) {
 		{"zero", "0", 0.0, typeinfo.Int32Type},
 		{"zero float", "0.0", 0.0, typeinfo.Float32Type},
 		{"zero float with floatThreshold of 0.1", "0.0", 0.1, typeinfo.Int32Type},
-		{"negative float", "-1.3451234", 0.0, typeinfo.Float32Type},
+		{"negative float", "-1.3451", 0.0, typeinfo.Float32Type},
+		{"more digits than a float", "-1.3451234", 0.0, typeinfo.Float64Type},
+		{"more digits than a double", "0.1234567890123456789", 0.0, mustDecimalType(19, 19)},
+		{"scientific notation", "1.5e10", 0.0, typeinfo.Float32Type},
+		{"more digits than a decimal", "0." + strings.Repeat("1", 31), 0.0, typeinfo.Float64Type},
 		{"double decimal point", "0.00.0", 0.0, typeinfo.UnknownType},
 		{"leading zero floats", "05.78", 0.0, typeinfo.Float32Type},
 		{"zero float with high precision", "0.0000", 0.0, typeinfo.Float32Type},
 		{"all zeroes", "0000", 0.0, typeinfo.StringDefaultType},
 		{"leading zeroes", "01", 0.0, typeinfo.StringDefaultType},
 		{"negative int", "-1234", 0.0, typeinfo.Int32Type},
-		{"fits in uint64 but not int64", strconv.FormatUint(math.MaxUint64, 10), 0.0, typeinfo.StringDefaultType},
-		{"negative less than math.MinInt64", "-" + strconv.FormatUint(math.MaxUint64, 10), 0.0, typeinfo.StringDefaultType},
+		{"fits in uint64 but not int64", strconv.FormatUint(math.MaxUint64, 10), 0.0, typeinfo.Uint64Type},
+		{"negative less than math.MinInt64", "-" + strconv.FormatUint(math.MaxUint64, 10), 0.0, mustDecimalType(20, 0)},
+		{"more digits than a decimal", strings.Repeat("1", 66), 0.0, typeinfo.StringDefaultType},
 		{"math.MinInt64", strconv.FormatInt(math.MinInt64, 10), 0.0, typeinfo.Int64Type},
 	}
 
@@ -108,7 +116,7 @@ func TestLeasPermissiveChronoType(t *testing.T) {
 		{"random string", "asdf", typeinfo.UnknownType},
 		{"time", "9:27:10.485214", typeinfo.TimeType},
 		{"date", "2020-02-02", typeinfo.DateType},
-		{"also date", "2020-02-02 00:00:00.0", typeinfo.DateType},
+		{"datetime at midnight", "2020-02-02 00:00:00.0", typeinfo.DatetimeType},
 		{"datetime", "2030-01-02 04:06:03.472382", typeinfo.DatetimeType},
 	}
 
@@ -180,6 +188,39 @@ func testFindCommonType(t *testing.T) {
 			},
 			expType: typeinfo.Float64Type,
 		},
+		{
+			name: "uints and ints",
+			inferSet: typeInfoSet{
+				typeinfo.Uint64Type: {},
+				typeinfo.Int32Type:  {},
+			},
+			expType: mustDecimalType(20, 0),
+		},
+		{
+			name: "uints and floats",
+			inferSet: typeInfoSet{
+				typeinfo.Uint64Type:  {},
+				typeinfo.Float32Type: {},
+			},
+			expType: typeinfo.Float64Type,
+		},
+		{
+			name: "decimals and ints",
+			inferSet: typeInfoSet{
+				mustDecimalType(20, 4): {},
+				mustDecimalType(18, 8): {},
+				typeinfo.Int64Type:     {},
+			},
+			expType: mustDecimalType(27, 8),
+		},
+		{
+			name: "decimals and bools",
+			inferSet: typeInfoSet{
+				mustDecimalType(20, 4): {},
+				typeinfo.BoolType:      {},
+			},
+			expType: typeinfo.StringDefaultType,
+		},
 		{
 			name: "ints and bools",
 			inferSet: typeInfoSet{
@@ -223,6 +264,8 @@ func testFindCommonTypeFromSingleType(t *testing.T) {
 		typeinfo.Int64Type,
 		typeinfo.Float32Type,
 		typeinfo.Float64Type,
+		typeinfo.Uint64Type,
+		mustDecimalType(20, 2),
 		typeinfo.BoolType,
 		typeinfo.UuidType,
 		typeinfo.YearType,
@@ -339,11 +382,22 @@ da95f30d-4cde-4e53-a786-e00624ff2cbe,0.0001
 6fb474ca-8bec-4e21-9af2-a1ba22f39f1d,-1.0005
 aee125d4-e055-42e9-af3d-0bc676436ccd,1.0001`
 
+var decimalsAndInts = `uuid,dec
+1e0e1d3c-1ed5-4bd4-9c3a-7e3a62e8a0c1,1
+7f3b2d4e-29c5-4c44-8a53-1d33d0e1c2b9,0.12345678901234567
+3c0f4a79-5a11-4d6f-9f0e-0d7b6e3d5b22,-12345.5`
+
+var midnightDatetimes = `uuid,dt
+a3d5c6f2-8b1e-4f0a-9d2c-5e7f1a3b4c6d,2020-02-02 00:00:00
+b4e6d7a3-9c2f-4a1b-8e3d-6f8a2b4c5d7e,2020-02-03 00:00:00`
+
 var identityMapper = make(rowconv.NameMapper)
 
 type testInferenceArgs struct {
 	ColMapper      rowconv.NameMapper
 	floatThreshold float64
+	sampleRows     int
+	overrides      map[string]typeinfo.TypeInfo
 }
 
 func (tia testInferenceArgs) ColNameMapper() rowconv.NameMapper {
@@ -354,6 +408,22 @@ func (tia testInferenceArgs) FloatThreshold() float64 {
 	return tia.floatThreshold
 }
 
+func (tia testInferenceArgs) SampleRows() int {
+	return tia.sampleRows
+}
+
+func (tia testInferenceArgs) ColTypeOverrides() map[string]typeinfo.TypeInfo {
+	return tia.overrides
+}
+
+func mustDecimalType(precision, scale int) typeinfo.TypeInfo {
+	ti, ok := newDecimalType(precision, scale)
+	if !ok {
+		panic("invalid decimal type")
+	}
+	return ti
+}
+
 func TestInferSchema(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -371,7 +441,7 @@ func TestInferSchema(t *testing.T) {
 			},
 			map[string]typeinfo.TypeInfo{
 				"int":    typeinfo.Int32Type,
-				"uint":   typeinfo.StringDefaultType,
+				"uint":   typeinfo.Uint64Type,
 				"uuid":   typeinfo.UuidType,
 				"float":  typeinfo.Float32Type,
 				"bool":   typeinfo.BoolType,
@@ -387,11 +457,76 @@ func TestInferSchema(t *testing.T) {
 				floatThreshold: 0,
 			},
 			map[string]typeinfo.TypeInfo{
-				"mix":  typeinfo.StringDefaultType,
+				"mix":  mustDecimalType(19, 0),
 				"uuid": typeinfo.UuidType,
 			},
 			nil,
 		},
+		{
+			"decimals and ints",
+			decimalsAndInts,
+			testInferenceArgs{
+				ColMapper:      identityMapper,
+				floatThreshold: 0,
+			},
+			map[string]typeinfo.TypeInfo{
+				"dec":  mustDecimalType(22, 17),
+				"uuid": typeinfo.UuidType,
+			},
+			nil,
+		},
+		{
+			"datetimes at midnight",
+			midnightDatetimes,
+			testInferenceArgs{
+				ColMapper:      identityMapper,
+				floatThreshold: 0,
+			},
+			map[string]typeinfo.TypeInfo{
+				"dt":   typeinfo.DatetimeType,
+				"uuid": typeinfo.UuidType,
+			},
+			nil,
+		},
+		{
+			"sampled rows",
+			oneOfEachKindWithSomeNilsCSVStr,
+			testInferenceArgs{
+				ColMapper:      identityMapper,
+				floatThreshold: 0,
+				sampleRows:     2,
+			},
+			map[string]typeinfo.TypeInfo{
+				"int":    typeinfo.Int32Type,
+				"uint":   typeinfo.Uint64Type,
+				"uuid":   typeinfo.UuidType,
+				"float":  typeinfo.Float32Type,
+				"bool":   typeinfo.BoolType,
+				"string": typeinfo.StringDefaultType,
+			},
+			nil,
+		},
+		{
+			"overridden types",
+			oneOfEachKindCSVStr,
+			testInferenceArgs{
+				ColMapper:      identityMapper,
+				floatThreshold: 0,
+				overrides: map[string]typeinfo.TypeInfo{
+					"int":   typeinfo.Int64Type,
+					"float": mustDecimalType(10, 2),
+				},
+			},
+			map[string]typeinfo.TypeInfo{
+				"int":    typeinfo.Int64Type,
+				"uint":   typeinfo.Uint64Type,
+				"uuid":   typeinfo.UuidType,
+				"float":  mustDecimalType(10, 2),
+				"bool":   typeinfo.BoolType,
+				"string": typeinfo.StringDefaultType,
+			},
+			nil,
+		},
 		{
 			"floats with zero fractional and float threshold of 0",
 			floatsWithZeroForFractionalPortion,
@@ -491,3 +626,76 @@ func TestInferSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestInferenceReport(t *testing.T) {
+	const importFilePath = "/Users/home/datasets/test/import_file.csv"
+
+	infer := func(t *testing.T, csvContents string, args testInferenceArgs) (*schema.ColCollection, *InferenceReport, error) {
+		dEnv := dtestutils.CreateTestEnv()
+		t.Cleanup(func() { dEnv.DoltDB.Close() })
+
+		wrCl, err := dEnv.FS.OpenForWrite(importFilePath, os.ModePerm)
+		require.NoError(t, err)
+		_, err = wrCl.Write([]byte(csvContents))
+		require.NoError(t, err)
+		require.NoError(t, wrCl.Close())
+
+		rdCl, err := dEnv.FS.OpenForRead(importFilePath)
+		require.NoError(t, err)
+		csvRd, err := csv.NewCSVReader(types.Format_Default, rdCl, csv.NewCSVInfo())
+		require.NoError(t, err)
+		defer csvRd.Close(context.Background())
+
+		return InferColumnTypesWithReport(context.Background(), csvRd, args)
+	}
+
+	t.Run("report", func(t *testing.T) {
+		_, report, err := infer(t, oneOfEachKindWithSomeNilsCSVStr, testInferenceArgs{
+			ColMapper: identityMapper,
+			overrides: map[string]typeinfo.TypeInfo{"string": typeinfo.TextType},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 9, report.RowsRead)
+		assert.Equal(t, 9, report.RowsSampled)
+
+		byName := make(map[string]ColumnInference)
+		for _, c := range report.Columns {
+			byName[c.Name] = c
+		}
+		assert.Equal(t, ColumnInference{Name: "int", Type: typeinfo.Int32Type, Nullable: true}, byName["int"])
+		assert.Equal(t, ColumnInference{Name: "string", Type: typeinfo.TextType, Overridden: true}, byName["string"])
+		assert.Equal(t, typeinfo.Float32Type, byName["float"].Type)
+		assert.False(t, byName["float"].Nullable)
+	})
+
+	t.Run("sampled rows", func(t *testing.T) {
+		_, report, err := infer(t, oneOfEachKindWithSomeNilsCSVStr, testInferenceArgs{ColMapper: identityMapper, sampleRows: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.RowsRead)
+		assert.Equal(t, 2, report.RowsSampled)
+		for _, c := range report.Columns {
+			// the first empty value is in the third row
+			assert.False(t, c.Nullable, c.Name)
+		}
+	})
+
+	t.Run("sampled types", func(t *testing.T) {
+		_, report, err := infer(t, decimalsAndInts, testInferenceArgs{ColMapper: identityMapper})
+		require.NoError(t, err)
+		for _, c := range report.Columns {
+			if c.Name == "dec" {
+				assert.Len(t, c.SampledTypes, 3)
+			} else {
+				assert.Empty(t, c.SampledTypes)
+			}
+		}
+	})
+
+	t.Run("override of a column which isn't in the file", func(t *testing.T) {
+		_, _, err := infer(t, oneOfEachKindCSVStr, testInferenceArgs{
+			ColMapper: identityMapper,
+			overrides: map[string]typeinfo.TypeInfo{"missing": typeinfo.TextType},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ErrInvalidNotesRef is returned for notes ref names which aren't valid
+var ErrInvalidNotesRef = errors.New("invalid notes ref")
+
+type NotesProps struct {
+	AuthorName  string
+	AuthorEmail string
+}
+
+func (p NotesProps) commitMeta(desc string) (*datas.CommitMeta, error) {
+	return datas.NewCommitMeta(p.AuthorName, p.AuthorEmail, desc)
+}
+
+// ParseNotesRef returns the notes ref named by |name|, which may be a name such as "reviews" or a full ref such as
+// "refs/notes/reviews". An empty name is the default notes ref, refs/notes/commits.
+func ParseNotesRef(name string) (ref.NotesRef, error) {
+	if name == "" {
+		return ref.NewNotesRef(ref.DefaultNotesRefName), nil
+	}
+	if ref.IsRef(name) && !strings.HasPrefix(name, ref.PrefixForType(ref.NotesRefType)) {
+		return ref.NotesRef{}, fmt.Errorf("%w: '%s' is not in refs/notes/", ErrInvalidNotesRef, name)
+	}
+
+	nr := ref.NewNotesRef(name)
+	if !ref.IsValidNotesRefName(nr.GetPath()) {
+		return ref.NotesRef{}, fmt.Errorf("%w: '%s'", ErrInvalidNotesRef, name)
+	}
+	return nr, nil
+}
+
+// AddNoteOnDB attaches the note |text| to the commit |commitSpec| in the notes ref given. If the commit already has a
+// note, it's replaced when |force| is true, and otherwise it's an error.
+func AddNoteOnDB(ctx context.Context, ddb *doltdb.DoltDB, nr ref.NotesRef, commitSpec, text string, force bool, props NotesProps, headRef ref.DoltRef) error {
+	h, err := resolveNoteCommit(ctx, ddb, commitSpec, headRef)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		_, found, err := ddb.GetNote(ctx, nr, h)
+		if err != nil {
+			return err
+		} else if found {
+			return fmt.Errorf("cannot add notes: found existing notes for commit %s; use '-f' to overwrite existing notes", h.String())
+		}
+	}
+
+	meta, err := props.commitMeta("Notes added by 'dolt notes add'")
+	if err != nil {
+		return err
+	}
+	return ddb.SetNote(ctx, nr, h, text, meta)
+}
+
+// AppendNoteOnDB appends |text| to the note attached to the commit |commitSpec| in the notes ref given, separated from
+// it by a blank line. The note is added if the commit doesn't have one.
+func AppendNoteOnDB(ctx context.Context, ddb *doltdb.DoltDB, nr ref.NotesRef, commitSpec, text string, props NotesProps, headRef ref.DoltRef) error {
+	h, err := resolveNoteCommit(ctx, ddb, commitSpec, headRef)
+	if err != nil {
+		return err
+	}
+
+	existing, found, err := ddb.GetNote(ctx, nr, h)
+	if err != nil {
+		return err
+	}
+	if found && existing != "" {
+		text = existing + "\n\n" + text
+	}
+
+	meta, err := props.commitMeta("Notes added by 'dolt notes append'")
+	if err != nil {
+		return err
+	}
+	return ddb.SetNote(ctx, nr, h, text, meta)
+}
+
+// RemoveNoteOnDB removes the note attached to the commit |commitSpec| in the notes ref given.
+func RemoveNoteOnDB(ctx context.Context, ddb *doltdb.DoltDB, nr ref.NotesRef, commitSpec string, props NotesProps, headRef ref.DoltRef) error {
+	h, err := resolveNoteCommit(ctx, ddb, commitSpec, headRef)
+	if err != nil {
+		return err
+	}
+
+	meta, err := props.commitMeta("Notes removed by 'dolt notes remove'")
+	if err != nil {
+		return err
+	}
+	err = ddb.RemoveNote(ctx, nr, h, meta)
+	if errors.Is(err, doltdb.ErrNoteNotFound) {
+		return fmt.Errorf("%w for commit %s", err, h.String())
+	}
+	return err
+}
+
+func resolveNoteCommit(ctx context.Context, ddb *doltdb.DoltDB, commitSpec string, headRef ref.DoltRef) (hash.Hash, error) {
+	cm, err := resolveTagStartPoint(ctx, ddb, commitSpec, headRef)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	return cm.HashOf()
+}
@@ -113,6 +113,10 @@ func DoPush(ctx context.Context, pushMeta *env.PushOptions, progStarter ProgStar
 			// response is not sufficient, as there are many "success" cases that are not errors.
 			if targets.SrcRef == ref.EmptyBranchRef {
 				successPush = append(successPush, fmt.Sprintf(" - [deleted]             %s", targets.DestRef.GetPath()))
+			} else if targets.SrcRef.GetType() == ref.TagRefType {
+				successPush = append(successPush, fmt.Sprintf(" * [new tag]             %s -> %s", targets.SrcRef.GetPath(), targets.DestRef.GetPath()))
+			} else if targets.SrcRef.GetType() == ref.NotesRefType {
+				successPush = append(successPush, fmt.Sprintf(" * [notes]               %s -> %s", targets.SrcRef.String(), targets.DestRef.String()))
 			} else {
 				successPush = append(successPush, fmt.Sprintf(" * [new branch]          %s -> %s", targets.SrcRef.GetPath(), targets.DestRef.GetPath()))
 			}
@@ -153,6 +157,8 @@ func push(ctx context.Context, rsr env.RepoStateReader, tmpDir string, src, dest
 		}
 	case ref.TagRefType:
 		return pushTagToRemote(ctx, tmpDir, opts.SrcRef, opts.DestRef, src, dest, progStarter, progStopper)
+	case ref.NotesRefType:
+		return pushNotesToRemote(ctx, tmpDir, opts.Mode, opts.SrcRef, opts.DestRef, src, dest, progStarter, progStopper)
 	default:
 		return fmt.Errorf("%w: %s of type %s", ErrCannotPushRef, opts.SrcRef.String(), opts.SrcRef.GetType())
 	}
@@ -274,6 +280,48 @@ func pushTagToRemote(ctx context.Context, tempTableDir string, srcRef, destRef r
 	return nil
 }
 
+// pushNotesToRemote pushes the notes ref |srcRef| and the history of its notes to |destRef| in the remote database.
+// Unless the push is forced, the remote notes ref must be an ancestor of the local one.
+func pushNotesToRemote(ctx context.Context, tempTableDir string, mode ref.UpdateMode, srcRef, destRef ref.DoltRef, localDB, remoteDB *doltdb.DoltDB, progStarter ProgStarter, progStopper ProgStopper) error {
+	cm, err := localDB.ResolveCommitRef(ctx, srcRef)
+	if err != nil {
+		return fmt.Errorf("%w; refspec not found: '%s'; %s", ref.ErrInvalidRefSpec, srcRef.String(), err.Error())
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+
+	newCtx, cancelFunc := context.WithCancel(ctx)
+	wg, statsCh := progStarter(newCtx)
+	err = remoteDB.PullChunks(ctx, tempTableDir, localDB, []hash.Hash{h}, statsCh, nil)
+	progStopper(cancelFunc, wg, statsCh)
+	if err != nil {
+		return err
+	}
+	cli.Println()
+
+	return updateNotesRef(ctx, remoteDB, destRef, cm, mode.Force)
+}
+
+// updateNotesRef points the notes ref |nr| in |ddb| at |cm|, which must be a descendant of its current commit unless
+// |force| is true.
+func updateNotesRef(ctx context.Context, ddb *doltdb.DoltDB, nr ref.DoltRef, cm *doltdb.Commit, force bool) error {
+	if force {
+		return ddb.SetHeadToCommit(ctx, nr, cm)
+	}
+
+	ok, err := ddb.HasRef(ctx, nr)
+	if err != nil {
+		return err
+	} else if !ok {
+		return ddb.SetHeadToCommit(ctx, nr, cm)
+	}
+
+	return ddb.FastForward(ctx, nr, cm)
+}
+
 // DeleteRemoteBranch validates targetRef is a branch on the remote database, and then deletes it, then deletes the
 // remote tracking branch from the local database.
 func DeleteRemoteBranch(ctx context.Context, targetRef ref.BranchRef, remoteRef ref.RemoteRef, localDB, remoteDB *doltdb.DoltDB, force bool) error {
@@ -376,32 +424,115 @@ func FetchFollowTags(ctx context.Context, tempTableDir string, srcDB, destDB *do
 			return false, nil
 		}
 
-		newCtx, cancelFunc := context.WithCancel(ctx)
-		wg, statsCh := progStarter(newCtx)
-		err = FetchTag(ctx, tempTableDir, srcDB, destDB, tag, statsCh)
-		progStopper(cancelFunc, wg, statsCh)
-		if err == nil {
-			cli.Println()
-		} else if err == pull.ErrDBUpToDate {
-			err = nil
-		}
+		err = fetchTagAndSetHead(ctx, tempTableDir, srcDB, destDB, tag, tagHash, progStarter, progStopper)
+		return err != nil, err
+	})
 
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchAllTags fetches every tag from the source DB into the destination DB, along with the commits they point to,
+// whether or not those commits are on any branch which has been fetched.
+func FetchAllTags(ctx context.Context, tempTableDir string, srcDB, destDB *doltdb.DoltDB, progStarter ProgStarter, progStopper ProgStopper) error {
+	return IterResolvedTags(ctx, srcDB, func(tag *doltdb.Tag) (stop bool, err error) {
+		tagHash, err := tag.GetAddr()
 		if err != nil {
 			return true, err
 		}
 
-		err = destDB.SetHead(ctx, tag.GetDoltRef(), tagHash)
+		has, err := destDB.Has(ctx, tagHash)
+		if err != nil {
+			return true, err
+		}
+		if has {
+			// the tag is already fetched, but may have been deleted or moved locally since
+			err = destDB.SetHead(ctx, tag.GetDoltRef(), tagHash)
+			return err != nil, err
+		}
 
-		return false, err
+		err = fetchTagAndSetHead(ctx, tempTableDir, srcDB, destDB, tag, tagHash, progStarter, progStopper)
+		return err != nil, err
 	})
+}
 
+// FetchAllNotes fetches every notes ref in |srcDB| and the history of its notes, fast-forwarding the notes ref with the
+// same name in |destDB| to it. Notes refs which don't exist in |destDB| are created, and it's an error for one which
+// has diverged from the remote.
+func FetchAllNotes(ctx context.Context, tempTableDir string, srcDB, destDB *doltdb.DoltDB, progStarter ProgStarter, progStopper ProgStopper) error {
+	notesRefs, err := srcDB.GetNotesRefs(ctx)
 	if err != nil {
 		return err
 	}
 
+	for _, nr := range notesRefs {
+		cm, err := srcDB.ResolveCommitRef(ctx, nr)
+		if err != nil {
+			return err
+		}
+
+		newCtx, cancelFunc := context.WithCancel(ctx)
+		wg, statsCh := progStarter(newCtx)
+		err = FetchCommit(ctx, tempTableDir, srcDB, destDB, cm, statsCh)
+		progStopper(cancelFunc, wg, statsCh)
+		if err != nil {
+			return err
+		}
+
+		err = updateNotesRef(ctx, destDB, nr, cm, false)
+		if errors.Is(err, datas.ErrMergeNeeded) {
+			return fmt.Errorf("%w: notes ref %s has diverged from the remote", ErrCantFF, nr.String())
+		} else if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// fetchTagAndSetHead fetches |tag| from |srcDB| and points the tag with the same name in |destDB| at it.
+func fetchTagAndSetHead(ctx context.Context, tempTableDir string, srcDB, destDB *doltdb.DoltDB, tag *doltdb.Tag, tagHash hash.Hash, progStarter ProgStarter, progStopper ProgStopper) error {
+	newCtx, cancelFunc := context.WithCancel(ctx)
+	wg, statsCh := progStarter(newCtx)
+	err := FetchTag(ctx, tempTableDir, srcDB, destDB, tag, statsCh)
+	progStopper(cancelFunc, wg, statsCh)
+	if err == nil {
+		cli.Println()
+	} else if err == pull.ErrDBUpToDate {
+		err = nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return destDB.SetHead(ctx, tag.GetDoltRef(), tagHash)
+}
+
+// remoteTrackingDeltaBases maps each new head being fetched to the current
+// value of the remote tracking ref it will update, if there is one. Most of
+// the chunks of the new head are likely to be unchanged or only slightly
+// changed from it, so remotes which support it can send deltas against it.
+func remoteTrackingDeltaBases(ctx context.Context, ddb *doltdb.DoltDB, newHeads []doltdb.RefWithHash) (map[hash.Hash]hash.Hash, error) {
+	bases := make(map[hash.Hash]hash.Hash)
+	for _, newHead := range newHeads {
+		ok, err := ddb.HasRef(ctx, newHead.Ref)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		h, err := ddb.GetHashForRefStr(ctx, newHead.Ref.String())
+		if err != nil {
+			return nil, err
+		}
+		bases[newHead.Hash] = *h
+	}
+	return bases, nil
+}
+
 // FetchRemoteBranch fetches and returns the |Commit| corresponding to the remote ref given. Returns an error if the
 // remote reference doesn't exist or can't be fetched. Blocks until the fetch is complete.
 func FetchRemoteBranch(
@@ -606,6 +737,11 @@ func fetchRefSpecsWithDepth(
 		}
 	}
 
+	deltaBases, err := remoteTrackingDeltaBases(ctx, dbData.Ddb, newHeads)
+	if err != nil {
+		return err
+	}
+
 	err = func() error {
 		newCtx := ctx
 		var statsCh chan pull.Stats
@@ -618,7 +754,7 @@ func fetchRefSpecsWithDepth(
 			defer progStopper(cancelFunc, wg, statsCh)
 		}
 
-		err = dbData.Ddb.PullChunks(ctx, tmpDir, srcDB, toFetch, statsCh, skipCmts)
+		err = dbData.Ddb.PullChunksWithDeltaBases(ctx, tmpDir, srcDB, toFetch, deltaBases, statsCh, skipCmts)
 		if err == pull.ErrDBUpToDate {
 			err = nil
 		}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
@@ -29,6 +30,15 @@ type TagProps struct {
 	TaggerName  string
 	TaggerEmail string
 	Description string
+	// Date is the date of the tag. If it's zero, the current time is used.
+	Date time.Time
+}
+
+func (p TagProps) tagMeta() *datas.TagMeta {
+	if p.Date.IsZero() {
+		return datas.NewTagMeta(p.TaggerName, p.TaggerEmail, p.Description)
+	}
+	return datas.NewTagMetaWithUserTS(p.TaggerName, p.TaggerEmail, p.Description, p.Date)
 }
 
 func CreateTag(ctx context.Context, dEnv *env.DoltEnv, tagName, startPoint string, props TagProps) error {
@@ -56,23 +66,67 @@ func CreateTagOnDB(ctx context.Context, ddb *doltdb.DoltDB, tagName, startPoint
 		return doltdb.ErrInvTagName
 	}
 
-	cs, err := doltdb.NewCommitSpec(startPoint)
+	cm, err := resolveTagStartPoint(ctx, ddb, startPoint, headRef)
 	if err != nil {
 		return err
 	}
 
-	optCmt, err := ddb.Resolve(ctx, cs, headRef)
+	return ddb.NewTagAtCommit(ctx, tagRef, cm, props.tagMeta())
+}
+
+// UpdateTagOnDB replaces the tag |oldName| with a tag named |newName| at |startPoint|, which may be the same name. The
+// new tag is checked before the old one is deleted, so the old tag is left alone if the new one can't be created.
+func UpdateTagOnDB(ctx context.Context, ddb *doltdb.DoltDB, oldName, newName, startPoint string, props TagProps, headRef ref.DoltRef) error {
+	oldRef := ref.NewTagRef(oldName)
+	hasRef, err := ddb.HasRef(ctx, oldRef)
 	if err != nil {
 		return err
 	}
-	cm, ok := optCmt.ToCommit()
-	if !ok {
-		return doltdb.ErrGhostCommitEncountered
+	if !hasRef {
+		return doltdb.ErrTagNotFound
 	}
 
-	meta := datas.NewTagMeta(props.TaggerName, props.TaggerEmail, props.Description)
+	newRef := ref.NewTagRef(newName)
+	if newName != oldName {
+		hasRef, err = ddb.HasRef(ctx, newRef)
+		if err != nil {
+			return err
+		}
+		if hasRef {
+			return ErrAlreadyExists
+		}
+	}
 
-	return ddb.NewTagAtCommit(ctx, tagRef, cm, meta)
+	if !ref.IsValidTagName(newName) {
+		return doltdb.ErrInvTagName
+	}
+
+	cm, err := resolveTagStartPoint(ctx, ddb, startPoint, headRef)
+	if err != nil {
+		return err
+	}
+
+	if err = ddb.DeleteTag(ctx, oldRef); err != nil {
+		return err
+	}
+	return ddb.NewTagAtCommit(ctx, newRef, cm, props.tagMeta())
+}
+
+func resolveTagStartPoint(ctx context.Context, ddb *doltdb.DoltDB, startPoint string, headRef ref.DoltRef) (*doltdb.Commit, error) {
+	cs, err := doltdb.NewCommitSpec(startPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	optCmt, err := ddb.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	return cm, nil
 }
 
 func DeleteTagsOnDB(ctx context.Context, ddb *doltdb.DoltDB, tagNames ...string) error {
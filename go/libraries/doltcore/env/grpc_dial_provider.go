@@ -15,15 +15,21 @@
 package env
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 
+	"github.com/dustin/go-humanize"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
@@ -31,6 +37,8 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
 	"github.com/dolthub/dolt/go/libraries/doltcore/grpcendpoint"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
 )
 
 // GRPCDialProvider implements dbfactory.GRPCDialProvider. By default, it is not able to use custom user credentials, but
@@ -66,6 +74,11 @@ func (p GRPCDialProvider) GetGRPCDialParams(config grpcendpoint.Config) (dbfacto
 		}
 	}
 
+	proxyFunc, hasProxyOverride, err := p.getProxyFunc()
+	if err != nil {
+		return dbfactory.GRPCRemoteConfig{}, err
+	}
+
 	var httpfetcher grpcendpoint.HTTPFetcher = http.DefaultClient
 
 	var opts []grpc.DialOption
@@ -75,32 +88,41 @@ func (p GRPCDialProvider) GetGRPCDialParams(config grpcendpoint.Config) (dbfacto
 
 		httpfetcher = &http.Client{
 			Transport: &http.Transport{
+				Proxy:             proxyFunc,
 				TLSClientConfig:   config.TLSConfig,
 				ForceAttemptHTTP2: true,
 			},
 		}
-	} else if config.Insecure {
-		opts = append(opts, grpc.WithInsecure())
 	} else {
-		tc := credentials.NewTLS(&tls.Config{})
-		opts = append(opts, grpc.WithTransportCredentials(tc))
+		if config.Insecure {
+			opts = append(opts, grpc.WithInsecure())
+		} else {
+			tc := credentials.NewTLS(&tls.Config{})
+			opts = append(opts, grpc.WithTransportCredentials(tc))
+		}
+		if hasProxyOverride {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.Proxy = proxyFunc
+			httpfetcher = &http.Client{Transport: transport}
+		}
 	}
 
+	userAgent := p.getUserAgentString()
 	opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(128*1024*1024)))
-	opts = append(opts, grpc.WithUserAgent(p.getUserAgentString()))
+	opts = append(opts, grpc.WithUserAgent(userAgent))
+	opts = append(opts, grpc.WithContextDialer(grpcendpoint.NewProxyDialer(proxyFunc, config.Insecure, userAgent)))
 
 	if config.Creds != nil {
 		opts = append(opts, grpc.WithPerRPCCredentials(config.Creds))
 	} else if config.WithEnvCreds {
 		var rpcCreds credentials.PerRPCCredentials
-		var err error
 		if config.UserIdForOsEnvAuth != "" {
-			rpcCreds, err = p.getRPCCredsFromOSEnv(config.UserIdForOsEnvAuth)
+			rpcCreds, err = p.getRPCCredsFromOSEnv(config.UserIdForOsEnvAuth, endpoint, config.Insecure)
 			if err != nil {
 				return dbfactory.GRPCRemoteConfig{}, err
 			}
 		} else {
-			rpcCreds, err = p.getRPCCreds(endpoint)
+			rpcCreds, err = p.getRPCCreds(endpoint, config.Insecure)
 			if err != nil {
 				return dbfactory.GRPCRemoteConfig{}, err
 			}
@@ -109,22 +131,97 @@ func (p GRPCDialProvider) GetGRPCDialParams(config grpcendpoint.Config) (dbfacto
 			opts = append(opts, grpc.WithPerRPCCredentials(rpcCreds))
 		}
 	}
+	limits, err := p.getTransferLimits()
+	if err != nil {
+		return dbfactory.GRPCRemoteConfig{}, err
+	}
+	if limits.bytesPerSecond > 0 {
+		httpfetcher = remotestorage.NewThrottledHTTPFetcher(httpfetcher, bandwidthLimiter(limits.bytesPerSecond))
+	}
+
 	return dbfactory.GRPCRemoteConfig{
-		Endpoint:    endpoint,
-		DialOptions: opts,
-		HTTPFetcher: httpfetcher,
+		Endpoint:               endpoint,
+		DialOptions:            opts,
+		HTTPFetcher:            httpfetcher,
+		MaxConcurrentDownloads: limits.maxDownloads,
+		MaxConcurrentUploads:   limits.maxUploads,
 	}, nil
 }
 
-// getRPCCredsFromOSEnv returns RPC Credentials for the specified username, using the DOLT_REMOTE_PASSWORD
-func (p GRPCDialProvider) getRPCCredsFromOSEnv(username string) (credentials.PerRPCCredentials, error) {
+type transferLimits struct {
+	maxDownloads   int
+	maxUploads     int
+	bytesPerSecond uint64
+}
+
+// getTransferLimits returns the limits on table file transfers configured by remotes.max_concurrent_downloads,
+// remotes.max_concurrent_uploads and remotes.max_bytes_per_second.
+func (p GRPCDialProvider) getTransferLimits() (transferLimits, error) {
+	var limits transferLimits
+	if p.dEnv == nil || p.dEnv.Config == nil {
+		return limits, nil
+	}
+	for _, kv := range []struct {
+		key string
+		val *int
+	}{{config.RemotesMaxConcurrentDownloadsKey, &limits.maxDownloads}, {config.RemotesMaxConcurrentUploadsKey, &limits.maxUploads}} {
+		if str := p.dEnv.Config.GetStringOrDefault(kv.key, ""); str != "" {
+			n, err := strconv.Atoi(str)
+			if err != nil || n < 0 {
+				return limits, fmt.Errorf("invalid %s: '%s' is not a non-negative integer", kv.key, str)
+			}
+			*kv.val = n
+		}
+	}
+	if str := p.dEnv.Config.GetStringOrDefault(config.RemotesMaxBytesPerSecondKey, ""); str != "" {
+		n, err := humanize.ParseBytes(str)
+		if err != nil {
+			return limits, fmt.Errorf("invalid %s: %w", config.RemotesMaxBytesPerSecondKey, err)
+		}
+		limits.bytesPerSecond = n
+	}
+	return limits, nil
+}
+
+var bandwidthLimiters = struct {
+	mu       sync.Mutex
+	limiters map[uint64]*rate.Limiter
+}{limiters: make(map[uint64]*rate.Limiter)}
+
+// bandwidthLimiter returns the limiter for |bytesPerSecond|, which is shared by all remotes in the process with the
+// same limit so that it limits their combined bandwidth.
+func bandwidthLimiter(bytesPerSecond uint64) *rate.Limiter {
+	bandwidthLimiters.mu.Lock()
+	defer bandwidthLimiters.mu.Unlock()
+	l, ok := bandwidthLimiters.limiters[bytesPerSecond]
+	if !ok {
+		l = remotestorage.NewBandwidthLimiter(bytesPerSecond)
+		bandwidthLimiters.limiters[bytesPerSecond] = l
+	}
+	return l
+}
+
+// getRPCCredsFromOSEnv returns RPC Credentials for the specified username, using the DOLT_REMOTE_PASSWORD, or the
+// password returned by the configured credential helper if it is not set.
+func (p GRPCDialProvider) getRPCCredsFromOSEnv(username, endpoint string, insecure bool) (credentials.PerRPCCredentials, error) {
 	if username == "" {
 		return nil, errors.New("Runtime error: username must be provided to getRPCCredsFromOSEnv")
 	}
 
 	pass, found := os.LookupEnv(dconfig.EnvDoltRemotePassword)
 	if !found {
-		return nil, errors.New("error: must set DOLT_REMOTE_PASSWORD environment variable to use --user param")
+		helper := p.CredentialHelper()
+		if helper == "" {
+			return nil, errors.New("error: must set DOLT_REMOTE_PASSWORD environment variable or credential.helper config to use --user param")
+		}
+		hc, err := helper.Get(context.Background(), helperRequest(endpoint, insecure, username))
+		if err != nil {
+			return nil, err
+		}
+		if hc.Username != username {
+			return nil, fmt.Errorf("error: credential helper returned credentials for user '%s' instead of '%s'", hc.Username, username)
+		}
+		return hc.RPCCreds()
 	}
 	c := creds.DoltCredsForPass{
 		Username: username,
@@ -136,7 +233,7 @@ func (p GRPCDialProvider) getRPCCredsFromOSEnv(username string) (credentials.Per
 
 // getRPCCreds returns any RPC credentials available to this dial provider. If a DoltEnv has been configured
 // in this dial provider, it will be used to load custom user credentials, otherwise nil will be returned.
-func (p GRPCDialProvider) getRPCCreds(endpoint string) (credentials.PerRPCCredentials, error) {
+func (p GRPCDialProvider) getRPCCreds(endpoint string, insecure bool) (credentials.PerRPCCredentials, error) {
 	if p.dEnv == nil {
 		return nil, nil
 	}
@@ -145,6 +242,15 @@ func (p GRPCDialProvider) getRPCCreds(endpoint string) (credentials.PerRPCCreden
 		return p.dEnv.UserPassConfig.RPCCreds(), nil
 	}
 
+	if helper := p.CredentialHelper(); helper != "" {
+		hc, err := helper.Get(context.Background(), helperRequest(endpoint, insecure, ""))
+		if err == nil {
+			return hc.RPCCreds()
+		} else if !errors.Is(err, creds.ErrNoHelperCredentials) {
+			return nil, err
+		}
+	}
+
 	dCreds, valid, err := p.dEnv.UserDoltCreds()
 	if err != nil {
 		return nil, ErrInvalidCredsFile
@@ -156,6 +262,40 @@ func (p GRPCDialProvider) getRPCCreds(endpoint string) (credentials.PerRPCCreden
 	return dCreds.RPCCreds(getHostFromEndpoint(endpoint)), nil
 }
 
+// getProxyFunc returns the proxies to use for remotes, and whether they were configured with remotes.proxy instead of
+// the environment.
+func (p GRPCDialProvider) getProxyFunc() (grpcendpoint.ProxyFunc, bool, error) {
+	override := ""
+	if p.dEnv != nil && p.dEnv.Config != nil {
+		override = p.dEnv.Config.GetStringOrDefault(config.RemotesProxyKey, "")
+	}
+	proxyFunc, err := grpcendpoint.NewProxyFunc(override)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid %s: %w", config.RemotesProxyKey, err)
+	}
+	return proxyFunc, override != "", nil
+}
+
+// CredentialHelper returns the credential helper configured by credential.helper, if any.
+func (p GRPCDialProvider) CredentialHelper() creds.CredentialHelper {
+	if p.dEnv == nil || p.dEnv.Config == nil {
+		return ""
+	}
+	return creds.CredentialHelper(p.dEnv.Config.GetStringOrDefault(config.CredentialHelperKey, ""))
+}
+
+func helperRequest(endpoint string, insecure bool, username string) creds.CredentialRequest {
+	protocol := "https"
+	if insecure {
+		protocol = "http"
+	}
+	return creds.CredentialRequest{
+		Protocol: protocol,
+		Host:     getHostFromEndpoint(endpoint),
+		Username: username,
+	}
+}
+
 func getHostFromEndpoint(endpoint string) string {
 	host, _, err := net.SplitHostPort(endpoint)
 	if err != nil {
@@ -0,0 +1,61 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/grpcendpoint"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
+)
+
+func dialProviderWithConfig(props map[string]string) GRPCDialProvider {
+	ch := config.NewConfigHierarchy()
+	ch.AddConfig(globalConfigName, config.NewMapConfig(props))
+	return GRPCDialProvider{dEnv: &DoltEnv{Config: &DoltCliConfig{ReadableConfig: ch, ch: ch}}}
+}
+
+func TestGRPCDialProviderTransferLimits(t *testing.T) {
+	t.Run("unset", func(t *testing.T) {
+		cfg, err := dialProviderWithConfig(map[string]string{}).GetGRPCDialParams(grpcendpoint.Config{Endpoint: "localhost:50051", Insecure: true})
+		require.NoError(t, err)
+		assert.Zero(t, cfg.MaxConcurrentDownloads)
+		assert.Zero(t, cfg.MaxConcurrentUploads)
+	})
+	t.Run("set", func(t *testing.T) {
+		p := dialProviderWithConfig(map[string]string{
+			config.RemotesMaxConcurrentDownloadsKey: "8",
+			config.RemotesMaxConcurrentUploadsKey:   "2",
+			config.RemotesMaxBytesPerSecondKey:      "10 MB",
+		})
+		cfg, err := p.GetGRPCDialParams(grpcendpoint.Config{Endpoint: "localhost:50051", Insecure: true})
+		require.NoError(t, err)
+		assert.Equal(t, 8, cfg.MaxConcurrentDownloads)
+		assert.Equal(t, 2, cfg.MaxConcurrentUploads)
+		limits, err := p.getTransferLimits()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10_000_000), limits.bytesPerSecond)
+		assert.Same(t, bandwidthLimiter(10_000_000), bandwidthLimiter(10_000_000))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := dialProviderWithConfig(map[string]string{config.RemotesMaxConcurrentUploadsKey: "-1"}).GetGRPCDialParams(grpcendpoint.Config{Endpoint: "localhost:50051", Insecure: true})
+		assert.ErrorContains(t, err, config.RemotesMaxConcurrentUploadsKey)
+		_, err = dialProviderWithConfig(map[string]string{config.RemotesMaxBytesPerSecondKey: "fast"}).GetGRPCDialParams(grpcendpoint.Config{Endpoint: "localhost:50051", Insecure: true})
+		assert.ErrorContains(t, err, config.RemotesMaxBytesPerSecondKey)
+	})
+}
@@ -43,6 +43,7 @@ var ErrBranchDoesNotMatchUpstream = errors.New("the upstream branch of your curr
 var ErrFailedToReadDb = errors.New("failed to read from the db")
 var ErrUnknownBranch = errors.New("unknown branch")
 var ErrCannotSetUpstreamForTag = errors.New("cannot set upstream for tag")
+var ErrCannotSetUpstreamForNotes = errors.New("cannot set upstream for notes")
 var ErrCannotPushRef = errors.New("cannot push ref")
 var ErrNoRefSpecForRemote = errors.New("no refspec for remote")
 var ErrInvalidFetchSpec = errors.New("invalid fetch spec")
@@ -57,6 +58,7 @@ var ErrCurrentBranchHasNoUpstream = goerrors.NewKind("fatal: The current branch
 var ErrInvalidRepository = goerrors.NewKind("fatal: remote '%s' not found.\n" +
 	"Please make sure the remote exists.")
 var ErrAllFlagCannotBeUsedWithRefSpec = goerrors.NewKind("fatal: --all can't be combined with refspecs")
+var ErrAllFlagCannotBeUsedWithTags = goerrors.NewKind("fatal: --all and --tags are incompatible")
 var ErrNoPushDestination = goerrors.NewKind("fatal: No configured push destination.\n" +
 	"Either specify the URL from the command-line or configure a remote repository using\n\n" +
 	"\tdolt remote add <name> <url>\n\n" +
@@ -164,7 +166,14 @@ type PushTarget struct {
 	HasUpstream bool
 }
 
-func NewPushOpts(ctx context.Context, apr *argparser.ArgParseResults, rsr RepoStateReader, ddb *doltdb.DoltDB, force, setUpstream, pushAutoSetupRemote, all bool) ([]*PushTarget, *Remote, error) {
+func NewPushOpts(ctx context.Context, apr *argparser.ArgParseResults, rsr RepoStateReader, ddb *doltdb.DoltDB, force, setUpstream, pushAutoSetupRemote, all, tags bool) ([]*PushTarget, *Remote, error) {
+	if tags {
+		if all {
+			return nil, nil, ErrAllFlagCannotBeUsedWithTags.New()
+		}
+		return getPushTargetsAndRemoteForTags(ctx, apr, rsr, ddb, force, setUpstream)
+	}
+
 	if apr.NArg() == 0 {
 		return getPushTargetsAndRemoteFromNoArg(ctx, rsr, ddb, force, setUpstream, pushAutoSetupRemote, all)
 	}
@@ -227,6 +236,47 @@ func NewPushOpts(ctx context.Context, apr *argparser.ArgParseResults, rsr RepoSt
 	}
 }
 
+// getPushTargetsAndRemoteForTags pushes every local tag, along with any refspecs given after the remote name, to the
+// remote given or the default remote if there isn't one.
+func getPushTargetsAndRemoteForTags(ctx context.Context, apr *argparser.ArgParseResults, rsr RepoStateReader, ddb *doltdb.DoltDB, force, setUpstream bool) ([]*PushTarget, *Remote, error) {
+	var remote Remote
+	var err error
+	if apr.NArg() == 0 {
+		remote, err = GetDefaultRemote(rsr)
+		if err == ErrNoRemote {
+			err = ErrNoPushDestination.New()
+		}
+	} else {
+		remote, err = getRemote(rsr, apr.Arg(0))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rsrBranches, err := rsr.GetBranches()
+	if err != nil {
+		return nil, nil, err
+	}
+	currentBranch, err := rsr.CWBHeadRef()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var refSpecNames []string
+	if apr.NArg() > 1 {
+		refSpecNames = append(refSpecNames, apr.Args[1:]...)
+	}
+	tagRefs, err := ddb.GetTags(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, tr := range tagRefs {
+		refSpecNames = append(refSpecNames, tr.String())
+	}
+
+	return getPushTargetsAndRemoteForBranchRefs(ctx, rsrBranches, refSpecNames, currentBranch, &remote, ddb, force, setUpstream)
+}
+
 func getRemote(rsr RepoStateReader, name string) (Remote, error) {
 	remotes, err := rsr.GetRemotes()
 	if err != nil {
@@ -330,6 +380,10 @@ func getPushTargetFromRefSpec(refSpec ref.RefSpec, currentBranch ref.DoltRef, re
 		if setUpstream {
 			err = ErrCannotSetUpstreamForTag
 		}
+	case ref.NotesRefType:
+		if setUpstream {
+			err = ErrCannotSetUpstreamForNotes
+		}
 	default:
 		err = fmt.Errorf("%w: '%s' of type '%s'", ErrCannotPushRef, src.String(), src.GetType())
 	}
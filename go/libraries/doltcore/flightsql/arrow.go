@@ -0,0 +1,347 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightsql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/shopspring/decimal"
+)
+
+// maxDecimalPrecision is the largest precision of Arrow's 128 bit decimals. Larger MySQL decimals are sent as strings.
+const maxDecimalPrecision = 38
+
+// Record batches are sent once they have this many rows, or their values take up this many bytes, so that they fit
+// in the default maximum gRPC message size of clients.
+const (
+	maxBatchRows  = 64 * 1024
+	maxBatchBytes = 2 * 1024 * 1024
+)
+
+// arrowSchema returns the Arrow schema of results with |sch|.
+func arrowSchema(sch sql.Schema) *arrow.Schema {
+	fields := make([]arrow.Field, len(sch))
+	for i, col := range sch {
+		fields[i] = arrow.Field{Name: col.Name, Type: arrowType(col.Type), Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// arrowType returns the Arrow type values of |t| are sent as.
+func arrowType(t sql.Type) arrow.DataType {
+	switch t.Type() {
+	case sqltypes.Int8:
+		return arrow.PrimitiveTypes.Int8
+	case sqltypes.Uint8:
+		return arrow.PrimitiveTypes.Uint8
+	case sqltypes.Int16, sqltypes.Year:
+		return arrow.PrimitiveTypes.Int16
+	case sqltypes.Uint16:
+		return arrow.PrimitiveTypes.Uint16
+	case sqltypes.Int24, sqltypes.Int32:
+		return arrow.PrimitiveTypes.Int32
+	case sqltypes.Uint24, sqltypes.Uint32:
+		return arrow.PrimitiveTypes.Uint32
+	case sqltypes.Int64:
+		return arrow.PrimitiveTypes.Int64
+	case sqltypes.Uint64:
+		return arrow.PrimitiveTypes.Uint64
+	case sqltypes.Float32:
+		return arrow.PrimitiveTypes.Float32
+	case sqltypes.Float64:
+		return arrow.PrimitiveTypes.Float64
+	case sqltypes.Decimal:
+		if dt, ok := t.(sql.DecimalType); ok && dt.Precision() <= maxDecimalPrecision {
+			return &arrow.Decimal128Type{Precision: int32(dt.Precision()), Scale: int32(dt.Scale())}
+		}
+		return arrow.BinaryTypes.String
+	case sqltypes.Date:
+		return arrow.FixedWidthTypes.Date32
+	case sqltypes.Datetime, sqltypes.Timestamp:
+		return &arrow.TimestampType{Unit: arrow.Microsecond}
+	case sqltypes.Time:
+		return &arrow.DurationType{Unit: arrow.Microsecond}
+	case sqltypes.Blob, sqltypes.Binary, sqltypes.VarBinary, sqltypes.Bit, sqltypes.Geometry:
+		return arrow.BinaryTypes.Binary
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// appendValue appends |v|, a value of |t|, to |b|, the builder of the Arrow type of |t|. It returns the number of
+// bytes the value takes up in the record batch.
+func appendValue(ctx *sql.Context, b array.Builder, t sql.Type, v interface{}) (int, error) {
+	if v == nil {
+		b.AppendNull()
+		return 0, nil
+	}
+
+	switch b := b.(type) {
+	case *array.Int8Builder:
+		i, err := toInt64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(int8(i))
+		return 1, nil
+	case *array.Int16Builder:
+		i, err := toInt64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(int16(i))
+		return 2, nil
+	case *array.Int32Builder:
+		i, err := toInt64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(int32(i))
+		return 4, nil
+	case *array.Int64Builder:
+		i, err := toInt64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(i)
+		return 8, nil
+	case *array.Uint8Builder:
+		u, err := toUint64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(uint8(u))
+		return 1, nil
+	case *array.Uint16Builder:
+		u, err := toUint64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(uint16(u))
+		return 2, nil
+	case *array.Uint32Builder:
+		u, err := toUint64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(uint32(u))
+		return 4, nil
+	case *array.Uint64Builder:
+		u, err := toUint64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(u)
+		return 8, nil
+	case *array.Float32Builder:
+		f, err := toFloat64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(float32(f))
+		return 4, nil
+	case *array.Float64Builder:
+		f, err := toFloat64(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(f)
+		return 8, nil
+	case *array.Decimal128Builder:
+		d, err := toDecimal(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		scale := b.Type().(*arrow.Decimal128Type).Scale
+		b.Append(decimal128.FromBigInt(d.Shift(scale).BigInt()))
+		return 16, nil
+	case *array.Date32Builder:
+		tm, err := toTime(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(arrow.Date32FromTime(tm))
+		return 4, nil
+	case *array.TimestampBuilder:
+		tm, err := toTime(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(arrow.Timestamp(tm.UnixMicro()))
+		return 8, nil
+	case *array.DurationBuilder:
+		d, err := toDuration(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(arrow.Duration(d.Microseconds()))
+		return 8, nil
+	case *array.BinaryBuilder:
+		raw, err := rawValue(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(raw)
+		return len(raw), nil
+	case *array.StringBuilder:
+		raw, err := rawValue(ctx, t, v)
+		if err != nil {
+			return 0, err
+		}
+		b.Append(string(raw))
+		return len(raw), nil
+	default:
+		return 0, fmt.Errorf("unexpected Arrow builder %T", b)
+	}
+}
+
+// rawValue returns the MySQL text or binary representation of |v|, a value of |t|.
+func rawValue(ctx *sql.Context, t sql.Type, v interface{}) ([]byte, error) {
+	res, err := t.SQL(ctx, nil, v)
+	if err != nil {
+		return nil, err
+	}
+	return res.Raw(), nil
+}
+
+// The following functions convert values to the Go types they are sent as. Values usually already have that type,
+// and others are converted from their MySQL text representation.
+
+func toInt64(ctx *sql.Context, t sql.Type, v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	}
+	raw, err := rawValue(ctx, t, v)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(raw), 10, 64)
+}
+
+func toUint64(ctx *sql.Context, t sql.Type, v interface{}) (uint64, error) {
+	switch v := v.(type) {
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case uint:
+		return uint64(v), nil
+	}
+	raw, err := rawValue(ctx, t, v)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(string(raw), 10, 64)
+}
+
+func toFloat64(ctx *sql.Context, t sql.Type, v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	raw, err := rawValue(ctx, t, v)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(string(raw), 64)
+}
+
+func toDecimal(ctx *sql.Context, t sql.Type, v interface{}) (decimal.Decimal, error) {
+	if d, ok := v.(decimal.Decimal); ok {
+		return d, nil
+	}
+	raw, err := rawValue(ctx, t, v)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return decimal.NewFromString(string(raw))
+}
+
+// toTime returns |v| as a time in UTC with the same wall clock, because Arrow timestamps without a time zone are
+// relative to the Unix epoch in UTC.
+func toTime(ctx *sql.Context, t sql.Type, v interface{}) (time.Time, error) {
+	tm, ok := v.(time.Time)
+	if !ok {
+		raw, err := rawValue(ctx, t, v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		s := string(raw)
+		layout := "2006-01-02"
+		if len(s) > len(layout) {
+			layout = "2006-01-02 15:04:05.999999"
+		}
+		if tm, err = time.Parse(layout, s); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.Date(tm.Year(), tm.Month(), tm.Day(), tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond(), time.UTC), nil
+}
+
+// toDuration returns the TIME value |v|, which is formatted as [-]hhh:mm:ss[.ffffff], as a duration.
+func toDuration(ctx *sql.Context, t sql.Type, v interface{}) (time.Duration, error) {
+	raw, err := rawValue(ctx, t, v)
+	if err != nil {
+		return 0, err
+	}
+	s := string(raw)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", raw)
+	}
+	h, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	sec, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, err
+	}
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(math.Round(sec*1e6))*time.Microsecond
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightsql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrowSchema(t *testing.T) {
+	sch := arrowSchema(sql.Schema{
+		{Name: "pk", Type: types.Int64},
+		{Name: "name", Type: types.Text},
+		{Name: "price", Type: types.MustCreateDecimalType(10, 2)},
+		{Name: "big", Type: types.MustCreateDecimalType(65, 30)},
+		{Name: "data", Type: types.Blob},
+		{Name: "at", Type: types.DatetimeMaxPrecision},
+		{Name: "day", Type: types.Date},
+		{Name: "u", Type: types.Uint8},
+	})
+
+	expected := []arrow.Field{
+		{Name: "pk", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "price", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}, Nullable: true},
+		{Name: "big", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "data", Type: arrow.BinaryTypes.Binary, Nullable: true},
+		{Name: "at", Type: &arrow.TimestampType{Unit: arrow.Microsecond}, Nullable: true},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "u", Type: arrow.PrimitiveTypes.Uint8, Nullable: true},
+	}
+	assert.True(t, arrow.NewSchema(expected, nil).Equal(sch), sch.String())
+}
+
+func TestAppendValue(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	sch := sql.Schema{
+		{Name: "pk", Type: types.Int64},
+		{Name: "name", Type: types.Text},
+		{Name: "price", Type: types.MustCreateDecimalType(10, 2)},
+		{Name: "day", Type: types.Date},
+		{Name: "at", Type: types.DatetimeMaxPrecision},
+	}
+	rows := []sql.Row{
+		{int64(1), "one", decimal.RequireFromString("1.50"), time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC)},
+		{int64(-2), nil, decimal.RequireFromString("-0.01"), nil, nil},
+		{int64(3), "three", nil, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), nil},
+	}
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, arrowSchema(sch))
+	defer b.Release()
+	for _, row := range rows {
+		for i, col := range sch {
+			_, err := appendValue(ctx, b.Field(i), col.Type, row[i])
+			require.NoError(t, err)
+		}
+	}
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	require.Equal(t, int64(len(rows)), rec.NumRows())
+	assert.Equal(t, []int64{1, -2, 3}, rec.Column(0).(*array.Int64).Int64Values())
+	names := rec.Column(1).(*array.String)
+	assert.Equal(t, "one", names.Value(0))
+	assert.True(t, names.IsNull(1))
+	assert.Equal(t, "three", names.Value(2))
+	prices := rec.Column(2).(*array.Decimal128)
+	assert.Equal(t, uint64(150), prices.Value(0).LowBits())
+	assert.Equal(t, int64(-1), prices.Value(1).HighBits())
+	assert.True(t, prices.IsNull(2))
+	days := rec.Column(3).(*array.Date32)
+	assert.Equal(t, arrow.Date32(1), days.Value(0))
+	assert.Equal(t, arrow.Date32(-1), days.Value(2))
+	assert.Equal(t, arrow.Timestamp(1000000), rec.Column(4).(*array.Timestamp).Value(0))
+	assert.Equal(t, 2, rec.Column(4).NullN())
+}
+
+func TestToDuration(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	d, err := toDuration(ctx, types.Text, "838:59:59")
+	require.NoError(t, err)
+	assert.Equal(t, 838*time.Hour+59*time.Minute+59*time.Second, d)
+	d, err = toDuration(ctx, types.Text, "-01:02:03.500000")
+	require.NoError(t, err)
+	assert.Equal(t, -(time.Hour + 2*time.Minute + 3500*time.Millisecond), d)
+	_, err = toDuration(ctx, types.Text, "12")
+	require.Error(t, err)
+}
@@ -0,0 +1,513 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightsql
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	fsql "github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	pb "github.com/apache/arrow-go/v18/arrow/flight/gen/flight"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// tokenTTL is how long the bearer tokens given to clients remain valid after they were last used.
+const tokenTTL = time.Hour
+
+// databaseHeader is the gRPC header clients set to choose the database their statements run in.
+const databaseHeader = "database"
+
+// handshakeMethod is the full name of Flight's Handshake method, which clients call to log in.
+const handshakeMethod = "/arrow.flight.protocol.FlightService/Handshake"
+
+// catalogsQuery lists the databases, which are Flight SQL's catalogs.
+const catalogsQuery = "SELECT schema_name AS catalog_name FROM information_schema.schemata ORDER BY catalog_name"
+
+// Engine authenticates the clients of a Server and runs their statements.
+type Engine interface {
+	// ValidatePassword returns an error unless |password| is the password of |user|.
+	ValidatePassword(user, password string, addr net.Addr) error
+	// NewReadOnlySession returns a new session of |user| connected from |addr|, in which no statement can change any
+	// data. If |database| isn't empty, it is the session's current database.
+	NewReadOnlySession(ctx context.Context, user string, addr net.Addr, database string) (sql.Session, error)
+	// CloseSession ends |sess| once its statement is done, rolling back its transaction and releasing its locks.
+	CloseSession(ctx context.Context, sess sql.Session)
+	// NewContext returns a context for running a statement in |sess|.
+	NewContext(ctx context.Context, sess sql.Session) (*sql.Context, error)
+	// Query runs |query|.
+	Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error)
+}
+
+// ServerArgs configure a Server.
+type ServerArgs struct {
+	Logger *logrus.Entry
+	Engine Engine
+	// TLSConfig encrypts connections if it isn't nil.
+	TLSConfig *tls.Config
+}
+
+// Server serves Arrow Flight SQL, so that analytics clients can fetch large results in Arrow's columnar format.
+// Clients authenticate with the user name and password of a MySQL user in a basic authorization header, and are
+// given a bearer token for their later calls. Statements run in the database named by the database header, either
+// directly or as prepared statements without parameters. They run in read-only sessions, so they can't change any
+// data.
+type Server struct {
+	fsql.BaseServer
+	args    ServerArgs
+	grpcSrv *grpc.Server
+
+	mu     sync.Mutex
+	tokens map[string]token
+}
+
+var _ fsql.Server = (*Server)(nil)
+
+type token struct {
+	user     string
+	lastUsed time.Time
+}
+
+// userKey is the key of the authenticated user in the contexts of calls.
+type userKey struct{}
+
+// NewServer returns a new Server for |args|.
+func NewServer(args ServerArgs) *Server {
+	if args.Logger == nil {
+		args.Logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+	s := &Server{args: args, tokens: make(map[string]token)}
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.authenticateUnary),
+		grpc.StreamInterceptor(s.authenticateStream),
+	}
+	if args.TLSConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(args.TLSConfig)))
+	}
+	s.grpcSrv = grpc.NewServer(opts...)
+	flight.RegisterFlightServiceServer(s.grpcSrv, fsql.NewFlightServer(s))
+	return s
+}
+
+// Serve accepts connections on |lis| until the server is closed. It returns nil if the server was closed.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpcSrv.Serve(lis)
+}
+
+// Close closes every open connection and listener of the server.
+func (s *Server) Close() error {
+	s.grpcSrv.Stop()
+	return nil
+}
+
+func (s *Server) authenticateUnary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	user, err := s.authenticate(ctx, func(md metadata.MD) error {
+		return grpc.SetHeader(ctx, md)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return handler(context.WithValue(ctx, userKey{}, user), req)
+}
+
+func (s *Server) authenticateStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if info.FullMethod == handshakeMethod {
+		return s.handshake(stream)
+	}
+	user, err := s.authenticate(stream.Context(), stream.SetHeader)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: context.WithValue(stream.Context(), userKey{}, user)})
+}
+
+// authenticatedStream is a stream whose context holds its authenticated user.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// handshake authenticates clients. Clients either send an authorization header, or send their user name and
+// password in the payload of a request, as a BasicAuth message. They are given a bearer token in the response's
+// authorization header, and in its payload if they used BasicAuth.
+func (s *Server) handshake(stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	var tok string
+	if md, _ := metadata.FromIncomingContext(ctx); len(md.Get("authorization")) > 0 {
+		if _, err := s.authenticate(ctx, stream.SetHeader); err != nil {
+			return err
+		}
+	} else {
+		var req pb.HandshakeRequest
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+		var auth pb.BasicAuth
+		if err := proto.Unmarshal(req.Payload, &auth); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if err := s.args.Engine.ValidatePassword(auth.Username, auth.Password, peerAddr(ctx)); err != nil {
+			return status.Errorf(codes.Unauthenticated, "access denied for user %q", auth.Username)
+		}
+		tok = s.newToken(auth.Username)
+		if err := stream.SetHeader(metadata.Pairs("authorization", "Bearer "+tok)); err != nil {
+			return err
+		}
+	}
+	if err := stream.SendMsg(&pb.HandshakeResponse{Payload: []byte(tok)}); err != nil {
+		return err
+	}
+	for {
+		var req pb.HandshakeRequest
+		if err := stream.RecvMsg(&req); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// authenticate returns the user who made the call in |ctx|, from its authorization header. Users who authenticate
+// with their password are given a bearer token in a header set with |setHeader|.
+func (s *Server) authenticate(ctx context.Context, setHeader func(metadata.MD) error) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	auth := md.Get("authorization")
+	if len(auth) == 0 {
+		return "", status.Error(codes.Unauthenticated, "authentication is required")
+	}
+	scheme, credential, _ := strings.Cut(auth[0], " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		decoded, err := base64.StdEncoding.DecodeString(credential)
+		if err != nil {
+			return "", status.Error(codes.Unauthenticated, "invalid basic authorization header")
+		}
+		user, password, _ := strings.Cut(string(decoded), ":")
+		if err := s.args.Engine.ValidatePassword(user, password, peerAddr(ctx)); err != nil {
+			return "", status.Errorf(codes.Unauthenticated, "access denied for user %q", user)
+		}
+		if err := setHeader(metadata.Pairs("authorization", "Bearer "+s.newToken(user))); err != nil {
+			return "", err
+		}
+		return user, nil
+	case "bearer":
+		if user, ok := s.tokenUser(credential); ok {
+			return user, nil
+		}
+		return "", status.Error(codes.Unauthenticated, "invalid or expired bearer token")
+	default:
+		return "", status.Errorf(codes.Unauthenticated, "unsupported authorization scheme %q", scheme)
+	}
+}
+
+func (s *Server) newToken(user string) string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	tok := hex.EncodeToString(b[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for t, info := range s.tokens {
+		if now.Sub(info.lastUsed) > tokenTTL {
+			delete(s.tokens, t)
+		}
+	}
+	s.tokens[tok] = token{user: user, lastUsed: now}
+	return tok
+}
+
+func (s *Server) tokenUser(tok string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, ok := s.tokens[tok]
+	if !ok || time.Since(info.lastUsed) > tokenTTL {
+		return "", false
+	}
+	info.lastUsed = time.Now()
+	s.tokens[tok] = info
+	return info.user, true
+}
+
+// statementHandle identifies a statement in tickets and prepared statement handles, so that it runs in the
+// database it was planned in.
+type statementHandle struct {
+	Query    string `json:"query"`
+	Database string `json:"database,omitempty"`
+}
+
+func newStatementHandle(ctx context.Context, query string) statementHandle {
+	return statementHandle{Query: query, Database: requestDatabase(ctx)}
+}
+
+func (h statementHandle) encode() []byte {
+	b, err := json.Marshal(h)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func decodeStatementHandle(b []byte) (statementHandle, error) {
+	var h statementHandle
+	if err := json.Unmarshal(b, &h); err != nil {
+		return statementHandle{}, status.Errorf(codes.InvalidArgument, "invalid statement handle: %v", err)
+	}
+	return h, nil
+}
+
+// query runs the statement of |h| as the user who made the call in |ctx|, in a read-only session. The session must be
+// closed with closeQuery once the results have been read.
+func (s *Server) query(ctx context.Context, h statementHandle) (*sql.Context, sql.Schema, sql.RowIter, error) {
+	user, _ := ctx.Value(userKey{}).(string)
+	sess, err := s.args.Engine.NewReadOnlySession(ctx, user, peerAddr(ctx), h.Database)
+	if err != nil {
+		return nil, nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sqlCtx, err := s.args.Engine.NewContext(ctx, sess)
+	if err != nil {
+		s.args.Engine.CloseSession(context.Background(), sess)
+		return nil, nil, nil, status.Error(codes.Internal, err.Error())
+	}
+	sch, iter, err := s.args.Engine.Query(sqlCtx, h.Query)
+	if err != nil {
+		s.args.Engine.CloseSession(context.Background(), sess)
+		return nil, nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return sqlCtx, sch, iter, nil
+}
+
+// closeQuery closes the results of a statement run by query, and its session.
+func (s *Server) closeQuery(sqlCtx *sql.Context, iter sql.RowIter) error {
+	defer s.args.Engine.CloseSession(context.Background(), sqlCtx.Session)
+	return iter.Close(sqlCtx)
+}
+
+// schema returns the Arrow schema of the results of |h|. Statements are run to find their schema, but their results
+// are only fetched by DoGet.
+func (s *Server) schema(ctx context.Context, h statementHandle) (*arrow.Schema, error) {
+	sqlCtx, sch, iter, err := s.query(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.closeQuery(sqlCtx, iter); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return arrowSchema(sch), nil
+}
+
+// flightInfo returns the FlightInfo of the results of |h|, which are fetched with |ticket|.
+func (s *Server) flightInfo(ctx context.Context, h statementHandle, desc *flight.FlightDescriptor, ticket []byte) (*flight.FlightInfo, error) {
+	sch, err := s.schema(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	return &flight.FlightInfo{
+		Schema:           flight.SerializeSchema(sch, memory.DefaultAllocator),
+		FlightDescriptor: desc,
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: ticket}}},
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+// schemaResult returns the SchemaResult of the results of |h|.
+func (s *Server) schemaResult(ctx context.Context, h statementHandle) (*flight.SchemaResult, error) {
+	sch, err := s.schema(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	return &flight.SchemaResult{Schema: flight.SerializeSchema(sch, memory.DefaultAllocator)}, nil
+}
+
+// records runs |h| and streams its results as Arrow record batches.
+func (s *Server) records(ctx context.Context, h statementHandle) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	sqlCtx, sch, iter, err := s.query(ctx, h)
+	if err != nil {
+		return nil, nil, err
+	}
+	arrowSch := arrowSchema(sch)
+	ch := make(chan flight.StreamChunk)
+	go func() {
+		defer close(ch)
+		defer func() {
+			if err := s.closeQuery(sqlCtx, iter); err != nil {
+				s.args.Logger.Debugf("error closing Flight SQL results: %v", err)
+			}
+		}()
+		b := array.NewRecordBuilder(memory.DefaultAllocator, arrowSch)
+		defer b.Release()
+
+		send := func(chunk flight.StreamChunk) bool {
+			select {
+			case ch <- chunk:
+				return true
+			case <-ctx.Done():
+				if chunk.Data != nil {
+					chunk.Data.Release()
+				}
+				return false
+			}
+		}
+		rows, size := 0, 0
+		for {
+			row, err := iter.Next(sqlCtx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				send(flight.StreamChunk{Err: status.Error(codes.Internal, err.Error())})
+				return
+			}
+			for i, col := range sch {
+				n, err := appendValue(sqlCtx, b.Field(i), col.Type, row[i])
+				if err != nil {
+					send(flight.StreamChunk{Err: status.Errorf(codes.Internal, "error converting column %s: %v", col.Name, err)})
+					return
+				}
+				size += n
+			}
+			rows++
+			if rows >= maxBatchRows || size >= maxBatchBytes {
+				if !send(flight.StreamChunk{Data: b.NewRecord()}) {
+					return
+				}
+				rows, size = 0, 0
+			}
+		}
+		if rows > 0 {
+			send(flight.StreamChunk{Data: b.NewRecord()})
+		}
+	}()
+	return arrowSch, ch, nil
+}
+
+// GetFlightInfoStatement implements fsql.Server.
+func (s *Server) GetFlightInfoStatement(ctx context.Context, cmd fsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	h := newStatementHandle(ctx, cmd.GetQuery())
+	ticket, err := fsql.CreateStatementQueryTicket(h.encode())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s.flightInfo(ctx, h, desc, ticket)
+}
+
+// GetSchemaStatement implements fsql.Server.
+func (s *Server) GetSchemaStatement(ctx context.Context, cmd fsql.StatementQuery, _ *flight.FlightDescriptor) (*flight.SchemaResult, error) {
+	return s.schemaResult(ctx, newStatementHandle(ctx, cmd.GetQuery()))
+}
+
+// DoGetStatement implements fsql.Server.
+func (s *Server) DoGetStatement(ctx context.Context, ticket fsql.StatementQueryTicket) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	h, err := decodeStatementHandle(ticket.GetStatementHandle())
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.records(ctx, h)
+}
+
+// CreatePreparedStatement implements fsql.Server. Prepared statements can't have parameters, and their handles
+// hold their statement, so closing them does nothing.
+func (s *Server) CreatePreparedStatement(ctx context.Context, req fsql.ActionCreatePreparedStatementRequest) (fsql.ActionCreatePreparedStatementResult, error) {
+	h := newStatementHandle(ctx, req.GetQuery())
+	sch, err := s.schema(ctx, h)
+	if err != nil {
+		return fsql.ActionCreatePreparedStatementResult{}, err
+	}
+	return fsql.ActionCreatePreparedStatementResult{Handle: h.encode(), DatasetSchema: sch}, nil
+}
+
+// ClosePreparedStatement implements fsql.Server.
+func (s *Server) ClosePreparedStatement(context.Context, fsql.ActionClosePreparedStatementRequest) error {
+	return nil
+}
+
+// GetFlightInfoPreparedStatement implements fsql.Server.
+func (s *Server) GetFlightInfoPreparedStatement(ctx context.Context, cmd fsql.PreparedStatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	h, err := decodeStatementHandle(cmd.GetPreparedStatementHandle())
+	if err != nil {
+		return nil, err
+	}
+	return s.flightInfo(ctx, h, desc, desc.Cmd)
+}
+
+// GetSchemaPreparedStatement implements fsql.Server.
+func (s *Server) GetSchemaPreparedStatement(ctx context.Context, cmd fsql.PreparedStatementQuery, _ *flight.FlightDescriptor) (*flight.SchemaResult, error) {
+	h, err := decodeStatementHandle(cmd.GetPreparedStatementHandle())
+	if err != nil {
+		return nil, err
+	}
+	return s.schemaResult(ctx, h)
+}
+
+// DoGetPreparedStatement implements fsql.Server.
+func (s *Server) DoGetPreparedStatement(ctx context.Context, cmd fsql.PreparedStatementQuery) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	h, err := decodeStatementHandle(cmd.GetPreparedStatementHandle())
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.records(ctx, h)
+}
+
+// GetFlightInfoCatalogs implements fsql.Server.
+func (s *Server) GetFlightInfoCatalogs(ctx context.Context, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	return s.flightInfo(ctx, statementHandle{Query: catalogsQuery}, desc, desc.Cmd)
+}
+
+// DoGetCatalogs implements fsql.Server.
+func (s *Server) DoGetCatalogs(ctx context.Context) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	return s.records(ctx, statementHandle{Query: catalogsQuery})
+}
+
+// requestDatabase returns the database named by the database header of the call in |ctx|.
+func requestDatabase(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(databaseHeader); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func peerAddr(ctx context.Context) net.Addr {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr
+	}
+	return &net.TCPAddr{}
+}
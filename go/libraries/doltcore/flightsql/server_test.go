@@ -0,0 +1,185 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightsql
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	fsql "github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testEngine struct {
+	databases []string
+}
+
+func (e *testEngine) ValidatePassword(user, password string, addr net.Addr) error {
+	if user != "root" || password != "pass" {
+		return errors.New("access denied")
+	}
+	return nil
+}
+
+func (e *testEngine) NewReadOnlySession(ctx context.Context, user string, addr net.Addr, database string) (sql.Session, error) {
+	e.databases = append(e.databases, database)
+	return sql.NewBaseSession(), nil
+}
+
+func (e *testEngine) CloseSession(ctx context.Context, sess sql.Session) {}
+
+func (e *testEngine) NewContext(ctx context.Context, sess sql.Session) (*sql.Context, error) {
+	return sql.NewContext(ctx, sql.WithSession(sess)), nil
+}
+
+func (e *testEngine) Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error) {
+	sch := sql.Schema{{Name: "pk", Type: types.Int64}, {Name: "c1", Type: types.Text}}
+	return sch, sql.RowsToRowIter(sql.Row{int64(1), "one"}, sql.Row{int64(2), nil}), nil
+}
+
+func startTestServer(t *testing.T) (*fsql.Client, *testEngine) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	e := &testEngine{}
+	srv := NewServer(ServerArgs{Engine: e})
+	go srv.Serve(lis)
+	t.Cleanup(func() { srv.Close() })
+
+	client, err := fsql.NewClient(lis.Addr().String(), nil, nil, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client, e
+}
+
+func login(t *testing.T, client *fsql.Client) context.Context {
+	ctx, err := client.Client.AuthenticateBasicToken(context.Background(), "root", "pass")
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(ctx, databaseHeader, "db/main")
+}
+
+// fetch returns the rows of the results of |info|.
+func fetch(t *testing.T, ctx context.Context, client *fsql.Client, info *flight.FlightInfo) [][2]interface{} {
+	require.Len(t, info.Endpoint, 1)
+	rdr, err := client.DoGet(ctx, info.Endpoint[0].Ticket)
+	require.NoError(t, err)
+	defer rdr.Release()
+
+	var rows [][2]interface{}
+	for rdr.Next() {
+		rec := rdr.Record()
+		pks := rec.Column(0).(*array.Int64)
+		names := rec.Column(1).(*array.String)
+		for i := 0; i < int(rec.NumRows()); i++ {
+			row := [2]interface{}{pks.Value(i), nil}
+			if names.IsValid(i) {
+				row[1] = names.Value(i)
+			}
+			rows = append(rows, row)
+		}
+	}
+	require.NoError(t, rdr.Err())
+	return rows
+}
+
+func TestAuthentication(t *testing.T) {
+	client, _ := startTestServer(t)
+	ctx := context.Background()
+
+	_, err := client.Execute(ctx, "select * from t")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.Client.AuthenticateBasicToken(ctx, "root", "wrong")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	var header metadata.MD
+	_, err = client.Client.AuthenticateBasicToken(ctx, "root", "pass", grpc.Header(&header))
+	require.NoError(t, err)
+	require.Len(t, header.Get("authorization"), 1)
+	assert.Regexp(t, "^Bearer [0-9a-f]{32}$", header.Get("authorization")[0])
+
+	badToken := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer 0123")
+	_, err = client.Execute(badToken, "select * from t")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestExecuteAndDoGet(t *testing.T) {
+	client, e := startTestServer(t)
+	ctx := login(t, client)
+
+	info, err := client.Execute(ctx, "select * from t")
+	require.NoError(t, err)
+	sch, err := flight.DeserializeSchema(info.Schema, nil)
+	require.NoError(t, err)
+	assert.True(t, arrowSchema(sql.Schema{{Name: "pk", Type: types.Int64}, {Name: "c1", Type: types.Text}}).Equal(sch))
+
+	assert.Equal(t, [][2]interface{}{{int64(1), "one"}, {int64(2), nil}}, fetch(t, ctx, client, info))
+
+	// The ticket runs the statement in the database it was planned in, and every statement runs in a read-only session.
+	assert.Equal(t, []string{"db/main", "db/main"}, e.databases)
+}
+
+func TestUnsupportedStatements(t *testing.T) {
+	client, _ := startTestServer(t)
+	ctx := login(t, client)
+
+	_, err := client.ExecuteUpdate(ctx, "delete from t")
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, err = client.GetTables(ctx, &fsql.GetTablesOpts{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestPreparedStatement(t *testing.T) {
+	client, _ := startTestServer(t)
+	ctx := login(t, client)
+
+	stmt, err := client.Prepare(ctx, "select * from t")
+	require.NoError(t, err)
+	defer stmt.Close(ctx)
+	assert.Equal(t, []string{"pk", "c1"}, fieldNames(stmt.DatasetSchema()))
+
+	info, err := stmt.Execute(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, [][2]interface{}{{int64(1), "one"}, {int64(2), nil}}, fetch(t, ctx, client, info))
+}
+
+func TestGetCatalogs(t *testing.T) {
+	client, _ := startTestServer(t)
+	ctx := login(t, client)
+
+	info, err := client.GetCatalogs(ctx)
+	require.NoError(t, err)
+	assert.Len(t, fetch(t, ctx, client, info), 2)
+}
+
+func fieldNames(sch *arrow.Schema) []string {
+	names := make([]string, sch.NumFields())
+	for i, f := range sch.Fields() {
+		names[i] = f.Name
+	}
+	return names
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcendpoint
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// ProxyFunc returns the URL of the proxy to use for a request, or nil if the request should not use a proxy. It has
+// the signature of http.Transport.Proxy.
+type ProxyFunc func(*http.Request) (*url.URL, error)
+
+// NoProxy is the |override| given to NewProxyFunc to disable proxies.
+const NoProxy = "none"
+
+// NewProxyFunc returns the ProxyFunc for the proxy URL |override|. If |override| is empty, the proxies are read from
+// the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables, and if it is NoProxy, no proxy is used. Otherwise,
+// it is the URL of an http, https or socks5 proxy, with optional credentials, which is used for all hosts other than
+// those in NO_PROXY.
+func NewProxyFunc(override string) (ProxyFunc, error) {
+	switch override {
+	case "":
+		return http.ProxyFromEnvironment, nil
+	case NoProxy:
+		return func(*http.Request) (*url.URL, error) { return nil, nil }, nil
+	}
+	u, err := url.Parse(override)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy url scheme '%s'; must be http, https or socks5", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy url '%s' has no host", override)
+	}
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	cfg := &httpproxy.Config{HTTPProxy: override, HTTPSProxy: override, NoProxy: noProxy}
+	proxyForURL := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyForURL(req.URL)
+	}, nil
+}
+
+// NewProxyDialer returns a dialer for gRPC connections which tunnels through the proxy returned by |proxyFunc|. HTTP
+// and HTTPS proxies are tunneled through with CONNECT, and SOCKS5 proxies are supported. Credentials in the proxy URL
+// are used to authenticate with the proxy. |insecure| is whether the connections are made to plaintext endpoints.
+func NewProxyDialer(proxyFunc ProxyFunc, insecure bool, userAgent string) func(ctx context.Context, addr string) (net.Conn, error) {
+	scheme := "https"
+	if insecure {
+		scheme = "http"
+	}
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		proxyURL, err := proxyFunc(&http.Request{URL: &url.URL{Scheme: scheme, Host: addr}})
+		if err != nil {
+			return nil, err
+		}
+		dialer := &net.Dialer{}
+		if proxyURL == nil {
+			return dialer.DialContext(ctx, "tcp", addr)
+		}
+
+		switch proxyURL.Scheme {
+		case "socks5", "socks5h":
+			var auth *proxy.Auth
+			if proxyURL.User != nil {
+				pass, _ := proxyURL.User.Password()
+				auth = &proxy.Auth{User: proxyURL.User.Username(), Password: pass}
+			}
+			socks, err := proxy.SOCKS5("tcp", proxyHostPort(proxyURL), auth, dialer)
+			if err != nil {
+				return nil, err
+			}
+			return socks.(proxy.ContextDialer).DialContext(ctx, "tcp", addr)
+		case "http", "https":
+			conn, err := dialer.DialContext(ctx, "tcp", proxyHostPort(proxyURL))
+			if err != nil {
+				return nil, err
+			}
+			if proxyURL.Scheme == "https" {
+				tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+				if err = tlsConn.HandshakeContext(ctx); err != nil {
+					conn.Close()
+					return nil, err
+				}
+				conn = tlsConn
+			}
+			return httpConnect(ctx, conn, addr, proxyURL, userAgent)
+		default:
+			return nil, fmt.Errorf("unsupported proxy url scheme '%s'", proxyURL.Scheme)
+		}
+	}
+}
+
+func proxyHostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	switch u.Scheme {
+	case "https":
+		return net.JoinHostPort(u.Hostname(), "443")
+	case "socks5", "socks5h":
+		return net.JoinHostPort(u.Hostname(), "1080")
+	default:
+		return net.JoinHostPort(u.Hostname(), "80")
+	}
+}
+
+// httpConnect asks the HTTP proxy connected to by |conn| to open a tunnel to |addr|.
+func httpConnect(ctx context.Context, conn net.Conn, addr string, proxyURL *url.URL, userAgent string) (net.Conn, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: addr},
+		Host:   addr,
+		Header: http.Header{"User-Agent": {userAgent}},
+	}
+	if proxyURL.User != nil {
+		pass, _ := proxyURL.User.Password()
+		creds := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + pass))
+		req.Header.Set("Proxy-Authorization", "Basic "+creds)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", proxyURL.Redacted(), addr, strings.TrimSpace(resp.Status))
+	}
+	return &bufferedConn{Conn: conn, r: r}, nil
+}
+
+// bufferedConn is a net.Conn which first reads any bytes buffered in |r| after the proxy's response.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,211 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcendpoint
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listen(t *testing.T, handle func(net.Conn)) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func echoServer(t *testing.T) string {
+	return listen(t, func(conn net.Conn) {
+		_, _ = io.Copy(conn, conn)
+	})
+}
+
+func tunnel(conn net.Conn, addr string) {
+	backend, err := net.Dial("tcp", addr)
+	if err != nil {
+		return
+	}
+	defer backend.Close()
+	go func() { _, _ = io.Copy(backend, conn) }()
+	_, _ = io.Copy(conn, backend)
+}
+
+// connectProxy is an HTTP proxy which supports CONNECT and requires the credentials alice:secret.
+func connectProxy(t *testing.T, requests *int32) string {
+	return listen(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		req, err := http.ReadRequest(r)
+		if err != nil {
+			return
+		}
+		atomic.AddInt32(requests, 1)
+		if req.Method != http.MethodConnect || req.Header.Get("Proxy-Authorization") != "Basic YWxpY2U6c2VjcmV0" {
+			_, _ = io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+			return
+		}
+		_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		tunnel(conn, req.Host)
+	})
+}
+
+// socks5Proxy is a SOCKS5 proxy which requires the credentials alice:secret.
+func socks5Proxy(t *testing.T, requests *int32) string {
+	return listen(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		readN := func(n int) []byte {
+			b := make([]byte, n)
+			if _, err := io.ReadFull(r, b); err != nil {
+				return nil
+			}
+			return b
+		}
+		hdr := readN(2)
+		if hdr == nil || readN(int(hdr[1])) == nil {
+			return
+		}
+		_, _ = conn.Write([]byte{5, 2})
+		auth := readN(2)
+		user := string(readN(int(auth[1])))
+		passLen := readN(1)
+		pass := string(readN(int(passLen[0])))
+		if user != "alice" || pass != "secret" {
+			_, _ = conn.Write([]byte{1, 1})
+			return
+		}
+		_, _ = conn.Write([]byte{1, 0})
+
+		req := readN(4)
+		var host string
+		switch req[3] {
+		case 1:
+			host = net.IP(readN(4)).String()
+		case 3:
+			host = string(readN(int(readN(1)[0])))
+		default:
+			return
+		}
+		port := binary.BigEndian.Uint16(readN(2))
+		atomic.AddInt32(requests, 1)
+		_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		tunnel(conn, net.JoinHostPort(host, strconv.Itoa(int(port))))
+	})
+}
+
+func assertEchoes(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	_, err := io.WriteString(conn, "hello")
+	require.NoError(t, err)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+}
+
+// fixedProxy returns a ProxyFunc which always uses |proxyURL|, since NewProxyFunc never proxies loopback addresses.
+func fixedProxy(t *testing.T, proxyURL string) ProxyFunc {
+	u, err := url.Parse(proxyURL)
+	require.NoError(t, err)
+	return http.ProxyURL(u)
+}
+
+func TestProxyDialer(t *testing.T) {
+	ctx := context.Background()
+	target := echoServer(t)
+	var connects, socksConnects int32
+	httpProxy := connectProxy(t, &connects)
+	socksProxy := socks5Proxy(t, &socksConnects)
+
+	t.Run("direct", func(t *testing.T) {
+		proxyFunc, err := NewProxyFunc(NoProxy)
+		require.NoError(t, err)
+		conn, err := NewProxyDialer(proxyFunc, false, "test")(ctx, target)
+		require.NoError(t, err)
+		assertEchoes(t, conn)
+	})
+	t.Run("http connect", func(t *testing.T) {
+		proxyFunc := fixedProxy(t, "http://alice:secret@"+httpProxy)
+		conn, err := NewProxyDialer(proxyFunc, true, "test")(ctx, target)
+		require.NoError(t, err)
+		assertEchoes(t, conn)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&connects))
+	})
+	t.Run("http connect bad credentials", func(t *testing.T) {
+		proxyFunc := fixedProxy(t, "http://alice:wrong@"+httpProxy)
+		_, err := NewProxyDialer(proxyFunc, false, "test")(ctx, target)
+		assert.ErrorContains(t, err, "407")
+	})
+	t.Run("socks5", func(t *testing.T) {
+		proxyFunc := fixedProxy(t, "socks5://alice:secret@"+socksProxy)
+		conn, err := NewProxyDialer(proxyFunc, false, "test")(ctx, target)
+		require.NoError(t, err)
+		assertEchoes(t, conn)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&socksConnects))
+	})
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv("HTTPS_PROXY", "http://alice:secret@"+httpProxy)
+		t.Setenv("NO_PROXY", "")
+		proxyFunc, err := NewProxyFunc("")
+		require.NoError(t, err)
+		before := atomic.LoadInt32(&connects)
+		// loopback addresses are never proxied
+		conn, err := NewProxyDialer(proxyFunc, false, "test")(ctx, target)
+		require.NoError(t, err)
+		assertEchoes(t, conn)
+		assert.Equal(t, before, atomic.LoadInt32(&connects))
+	})
+}
+
+func TestNewProxyFunc(t *testing.T) {
+	t.Setenv("NO_PROXY", "internal.example.com")
+	proxyFunc, err := NewProxyFunc("http://proxy.example.com:3128")
+	require.NoError(t, err)
+
+	u, err := proxyFunc(&http.Request{URL: &url.URL{Scheme: "https", Host: "doltremoteapi.dolthub.com:443"}})
+	require.NoError(t, err)
+	require.NotNil(t, u)
+	assert.Equal(t, "proxy.example.com:3128", u.Host)
+
+	u, err = proxyFunc(&http.Request{URL: &url.URL{Scheme: "https", Host: "internal.example.com:443"}})
+	require.NoError(t, err)
+	assert.Nil(t, u)
+
+	_, err = NewProxyFunc("ftp://proxy.example.com")
+	assert.Error(t, err)
+	_, err = NewProxyFunc("http://")
+	assert.Error(t, err)
+}
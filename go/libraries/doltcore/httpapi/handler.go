@@ -0,0 +1,290 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	jsontable "github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+)
+
+// Path is the path Handler serves queries on.
+const Path = "/api/sql"
+
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+// emptyJSONResult is the JSON result of a query which returns no rows.
+const emptyJSONResult = `{"rows": []}`
+
+// Engine authenticates the clients of a Handler and runs their queries.
+type Engine interface {
+	// ValidatePassword returns an error unless |password| is the password of |user|.
+	ValidatePassword(user, password string, addr net.Addr) error
+	// NewReadOnlySession returns a new session of |user| connected from |addr|, in which no statement can change any
+	// data. If |database| isn't empty, it is the session's current database.
+	NewReadOnlySession(ctx context.Context, user string, addr net.Addr, database string) (sql.Session, error)
+	// CloseSession ends |sess| once its request is done, rolling back its transaction and releasing its locks.
+	CloseSession(ctx context.Context, sess sql.Session)
+	// NewContext returns a context for running a statement in |sess|.
+	NewContext(ctx context.Context, sess sql.Session) (*sql.Context, error)
+	// Query runs |query|.
+	Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error)
+}
+
+// HandlerArgs configure a Handler.
+type HandlerArgs struct {
+	Logger *logrus.Entry
+	Engine Engine
+	// RequestsPerSecond is how many requests each client address, and each authenticated user, can make per second.
+	// Requests that fail to authenticate count against their address's limit. Nothing is limited if it is zero.
+	RequestsPerSecond float64
+	// Burst is how many requests each address or user can make at once, before they are limited by RequestsPerSecond.
+	Burst int
+}
+
+// Handler runs read only queries for clients which don't have a MySQL driver. Each request runs the statement in
+// its query parameter as the user given by HTTP basic authentication, in a read-only session, and streams the rows
+// it returns as JSON or CSV, according to its format parameter. The statement runs against the database in the database parameter, at
+// the branch, tag or commit in the ref parameter if one is given.
+type Handler struct {
+	args HandlerArgs
+
+	mu sync.Mutex
+	// limiters holds the rate limiter of each client address and each authenticated user, see allow
+	limiters map[limiterKey]*rate.Limiter
+}
+
+// limiterKey identifies a rate limiter. Exactly one of its fields is set.
+type limiterKey struct {
+	host string
+	user string
+}
+
+// limiterSweepSize is the number of rate limiters a Handler holds before it evicts the idle ones.
+const limiterSweepSize = 1024
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler returns a new Handler for |args|.
+func NewHandler(args HandlerArgs) *Handler {
+	if args.Logger == nil {
+		args.Logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+	if args.Burst < 1 {
+		args.Burst = 1
+	}
+	return &Handler{args: args, limiters: make(map[limiterKey]*rate.Limiter)}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "method %s is not allowed", r.Method)
+		return
+	}
+
+	// Clients are limited before they're authenticated, so that failed logins count against their limit too.
+	addr := remoteAddr(r)
+	if !h.allow(limiterKey{host: addr.IP.String()}) {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, "too many requests from %s", addr.IP)
+		return
+	}
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="dolt"`)
+		writeError(w, http.StatusUnauthorized, "authentication is required")
+		return
+	}
+	if err := h.args.Engine.ValidatePassword(user, password, addr); err != nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="dolt"`)
+		writeError(w, http.StatusUnauthorized, "access denied for user %q", user)
+		return
+	}
+	if !h.allow(limiterKey{user: user}) {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, "too many requests for user %q", user)
+		return
+	}
+
+	query := r.FormValue("query")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "the query parameter is required")
+		return
+	}
+	format := r.FormValue("format")
+	if format == "" {
+		format = formatJSON
+	} else if format != formatJSON && format != formatCSV {
+		writeError(w, http.StatusBadRequest, "format %q is not supported, it must be %s or %s", format, formatJSON, formatCSV)
+		return
+	}
+	database := r.FormValue("database")
+	if ref := r.FormValue("ref"); ref != "" {
+		if database == "" {
+			writeError(w, http.StatusBadRequest, "the database parameter is required with the ref parameter")
+			return
+		}
+		database += "/" + ref
+	}
+	sess, err := h.args.Engine.NewReadOnlySession(r.Context(), user, addr, database)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err.Error())
+		return
+	}
+	defer h.args.Engine.CloseSession(context.Background(), sess)
+	ctx, err := h.args.Engine.NewContext(r.Context(), sess)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%s", err.Error())
+		return
+	}
+	sch, iter, err := h.args.Engine.Query(ctx, query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err.Error())
+		return
+	}
+	h.writeResult(ctx, w, format, sch, iter)
+}
+
+// allow returns whether the client address or authenticated user identified by |key| may make another request now.
+// Once there are limiterSweepSize limiters, the limiters whose whole burst is available again are evicted, since
+// they're no different from new ones.
+func (h *Handler) allow(key limiterKey) bool {
+	if h.args.RequestsPerSecond <= 0 {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	limiter, ok := h.limiters[key]
+	if !ok {
+		if len(h.limiters) >= limiterSweepSize {
+			h.evictIdleLimiters()
+		}
+		limiter = rate.NewLimiter(rate.Limit(h.args.RequestsPerSecond), h.args.Burst)
+		h.limiters[key] = limiter
+	}
+	return limiter.Allow()
+}
+
+// evictIdleLimiters deletes the limiters which would allow a full burst of requests. Must be called with |mu| held.
+func (h *Handler) evictIdleLimiters() {
+	for key, limiter := range h.limiters {
+		if limiter.Tokens() >= float64(h.args.Burst) {
+			delete(h.limiters, key)
+		}
+	}
+}
+
+// writeResult streams the rows of |iter| to |w| in |format|. Errors are sent to the client as long as none of the
+// result has been sent yet. After that, the response is aborted so that the client can't mistake it for a whole
+// result.
+func (h *Handler) writeResult(ctx *sql.Context, w http.ResponseWriter, format string, sch sql.Schema, iter sql.RowIter) {
+	defer func() {
+		if err := iter.Close(ctx); err != nil {
+			h.args.Logger.Debugf("error closing HTTP API query results: %v", err)
+		}
+	}()
+
+	out := &trackingWriter{w: w}
+	var wr table.SqlRowWriter
+	var err error
+	switch format {
+	case formatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		wr, err = csv.NewCSVSqlWriter(iohelp.NopWrCloser(out), sch, csv.NewCSVInfo())
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		wr, err = jsontable.NewJSONSqlWriter(iohelp.NopWrCloser(out), sch)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%s", err.Error())
+		return
+	}
+
+	rows := 0
+	for {
+		var row sql.Row
+		row, err = iter.Next(ctx)
+		if err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			break
+		}
+		if err = wr.WriteSqlRow(ctx, row); err != nil {
+			break
+		}
+		rows++
+	}
+	if err == nil {
+		err = wr.Close(ctx)
+	}
+	if err == nil && format == formatJSON && rows == 0 {
+		_, err = io.WriteString(out, emptyJSONResult)
+	}
+	if err != nil {
+		if !out.written {
+			writeError(w, http.StatusBadRequest, "%s", err.Error())
+			return
+		}
+		h.args.Logger.Debugf("error streaming HTTP API query results: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// trackingWriter is an io.Writer which records whether anything has been written to it.
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = t.written || len(p) > 0
+	return t.w.Write(p)
+}
+
+func remoteAddr(r *http.Request) *net.TCPAddr {
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return &net.TCPAddr{}
+	}
+	p, _ := strconv.Atoi(port)
+	return &net.TCPAddr{IP: net.ParseIP(host), Port: p}
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{fmt.Sprintf(format, args...)})
+}
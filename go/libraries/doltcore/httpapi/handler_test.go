@@ -0,0 +1,234 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+type testEngine struct {
+	passwords map[string]string
+	databases []string
+	queries   []string
+	open      int
+}
+
+var errTestReadOnly = errors.New("cannot execute statement in a READ ONLY transaction")
+
+func (e *testEngine) ValidatePassword(user, password string, addr net.Addr) error {
+	if p, ok := e.passwords[user]; !ok || p != password {
+		return errors.New("access denied")
+	}
+	return nil
+}
+
+func (e *testEngine) NewReadOnlySession(ctx context.Context, user string, addr net.Addr, database string) (sql.Session, error) {
+	e.databases = append(e.databases, database)
+	e.open++
+	return sql.NewBaseSession(), nil
+}
+
+func (e *testEngine) CloseSession(ctx context.Context, sess sql.Session) {
+	e.open--
+}
+
+func (e *testEngine) NewContext(ctx context.Context, sess sql.Session) (*sql.Context, error) {
+	return sql.NewContext(ctx, sql.WithSession(sess)), nil
+}
+
+func (e *testEngine) Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error) {
+	e.queries = append(e.queries, query)
+	switch query {
+	case "select from":
+		return nil, nil, errors.New("syntax error")
+	case "insert into t values (1, 'one')", "select nextval('s')":
+		return nil, nil, errTestReadOnly
+	}
+	sch := sql.Schema{
+		{Name: "pk", Type: types.Int64},
+		{Name: "c1", Type: types.Text},
+	}
+	if query == "select * from empty" {
+		return sch, sql.RowsToRowIter(), nil
+	}
+	return sch, sql.RowsToRowIter(sql.Row{int64(1), "one"}, sql.Row{int64(2), "two, too"}), nil
+}
+
+func newTestHandler(args HandlerArgs) (*Handler, *testEngine) {
+	e := &testEngine{passwords: map[string]string{"root": "pass"}}
+	args.Engine = e
+	return NewHandler(args), e
+}
+
+func doRequest(h http.Handler, user, password string, params url.Values) *httptest.ResponseRecorder {
+	return doRequestFrom(h, "", user, password, params)
+}
+
+// doRequestFrom makes a request from |remoteAddr|, or httptest's default address if it's empty.
+func doRequestFrom(h http.Handler, remoteAddr, user, password string, params url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, Path+"?"+params.Encode(), nil)
+	if remoteAddr != "" {
+		req.RemoteAddr = remoteAddr
+	}
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerResults(t *testing.T) {
+	h, e := newTestHandler(HandlerArgs{})
+
+	rec := doRequest(h, "root", "pass", url.Values{"query": {"select * from t"}, "database": {"db"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var res struct {
+		Rows []map[string]interface{} `json:"rows"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, []map[string]interface{}{
+		{"pk": float64(1), "c1": "one"},
+		{"pk": float64(2), "c1": "two, too"},
+	}, res.Rows)
+
+	rec = doRequest(h, "root", "pass", url.Values{"query": {"select * from t"}, "database": {"db"}, "ref": {"main"}, "format": {"csv"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "pk,c1\n1,one\n2,\"two, too\"\n", rec.Body.String())
+
+	rec = doRequest(h, "root", "pass", url.Values{"query": {"select * from empty"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, emptyJSONResult, rec.Body.String())
+
+	assert.Equal(t, []string{"db", "db/main", ""}, e.databases)
+	assert.Zero(t, e.open)
+}
+
+func TestHandlerErrors(t *testing.T) {
+	h, e := newTestHandler(HandlerArgs{})
+
+	rec := doRequest(h, "", "", url.Values{"query": {"select 1"}})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+
+	rec = doRequest(h, "root", "wrong", url.Values{"query": {"select 1"}})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doRequest(h, "root", "pass", url.Values{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doRequest(h, "root", "pass", url.Values{"query": {"select 1"}, "format": {"xml"}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doRequest(h, "root", "pass", url.Values{"query": {"select 1"}, "ref": {"main"}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req := httptest.NewRequest(http.MethodDelete, Path, nil)
+	req.SetBasicAuth("root", "pass")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	assert.Empty(t, e.queries)
+
+	// statements are checked by the engine, which runs them in a read-only session
+	for _, query := range []string{"insert into t values (1, 'one')", "select nextval('s')", "select from"} {
+		rec = doRequest(h, "root", "pass", url.Values{"query": {query}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		var res struct {
+			Error string `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.NotEmpty(t, res.Error)
+	}
+	rec = doRequest(h, "root", "pass", url.Values{"query": {"insert into t values (1, 'one')"}})
+	assert.Contains(t, rec.Body.String(), errTestReadOnly.Error())
+}
+
+func TestHandlerAllowsReads(t *testing.T) {
+	h, e := newTestHandler(HandlerArgs{})
+	queries := []string{
+		"select * from t",
+		"select 1 union select 2",
+		"show tables",
+		"describe t",
+		"explain select * from t",
+	}
+	for _, query := range queries {
+		rec := doRequest(h, "root", "pass", url.Values{"query": {query}})
+		assert.Equal(t, http.StatusOK, rec.Code, query)
+	}
+	assert.Equal(t, queries, e.queries)
+}
+
+func TestHandlerRateLimit(t *testing.T) {
+	h, _ := newTestHandler(HandlerArgs{RequestsPerSecond: 0.001, Burst: 2})
+	params := url.Values{"query": {"select * from t"}}
+
+	// A user is limited across the addresses they connect from.
+	assert.Equal(t, http.StatusOK, doRequestFrom(h, "192.0.2.1:1234", "root", "pass", params).Code)
+	assert.Equal(t, http.StatusOK, doRequestFrom(h, "192.0.2.2:1234", "root", "pass", params).Code)
+	rec := doRequestFrom(h, "192.0.2.3:1234", "root", "pass", params)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	// Each user has their own limit.
+	h.args.Engine.(*testEngine).passwords["other"] = "pass"
+	assert.Equal(t, http.StatusOK, doRequestFrom(h, "192.0.2.3:1234", "other", "pass", params).Code)
+	assert.Len(t, h.limiters, 5)
+}
+
+func TestHandlerRateLimitsFailedLogins(t *testing.T) {
+	h, e := newTestHandler(HandlerArgs{RequestsPerSecond: 0.001, Burst: 2})
+	params := url.Values{"query": {"select * from t"}}
+
+	// Failed logins count against the limit of the address they come from, so once it's used up, even the right
+	// password is refused without being checked.
+	assert.Equal(t, http.StatusUnauthorized, doRequestFrom(h, "192.0.2.1:1234", "root", "wrong", params).Code)
+	assert.Equal(t, http.StatusUnauthorized, doRequestFrom(h, "192.0.2.1:5678", "unknown", "pass", params).Code)
+	rec := doRequestFrom(h, "192.0.2.1:1234", "root", "pass", params)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, doRequestFrom(h, "192.0.2.1:1234", "", "", params).Code)
+	assert.Empty(t, e.queries)
+
+	// Other addresses aren't affected.
+	assert.Equal(t, http.StatusOK, doRequestFrom(h, "192.0.2.2:1234", "root", "pass", params).Code)
+}
+
+func TestHandlerEvictsIdleLimiters(t *testing.T) {
+	h, _ := newTestHandler(HandlerArgs{RequestsPerSecond: 1000, Burst: 1})
+	for i := 0; i < limiterSweepSize; i++ {
+		h.limiters[limiterKey{user: strconv.Itoa(i)}] = rate.NewLimiter(rate.Limit(h.args.RequestsPerSecond), h.args.Burst)
+	}
+	assert.Equal(t, http.StatusOK, doRequest(h, "root", "pass", url.Values{"query": {"select * from t"}}).Code)
+	assert.Len(t, h.limiters, 2)
+}
@@ -0,0 +1,347 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor/creation"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// maxCascadeDepth is the number of times referential actions are applied to the rows changed by the previous round
+// of referential actions, which is the same as the limit MySQL places on nested cascades.
+const maxCascadeDepth = 15
+
+// cascadeEdit is a change to a child row made by a referential action. |to| is nil when the row is deleted.
+type cascadeEdit struct {
+	key, from, to val.Tuple
+}
+
+// cascadeForeignKeyActions applies the ON DELETE and ON UPDATE referential actions of the foreign keys in |mergedRoot|
+// to the child rows referencing parent rows which were deleted or updated since |ancRoot|, the same as if the parent
+// rows had been changed by a statement. Child rows which are unchanged since |ancRoot| are left alone, since the side
+// of the merge which changed the parent left them dangling with foreign key checks disabled. Nothing is done to the
+// children of parents whose schema changed either, or when the foreign key columns are part of the child's primary
+// key, so that those dangling child rows are recorded as constraint violations instead.
+func cascadeForeignKeyActions(ctx *sql.Context, mergedRoot, ancRoot doltdb.RootValue, tables *set.StrSet) (doltdb.RootValue, error) {
+	if !types.IsFormat_DOLT(mergedRoot.VRW().Format()) {
+		return mergedRoot, nil
+	}
+
+	// after the first round, only the changes made by the previous round of referential actions are cascaded
+	baseRoot, changedSince := ancRoot, ancRoot
+	for i := 0; i < maxCascadeDepth; i++ {
+		roundRoot := mergedRoot
+		fkColl, err := mergedRoot.GetForeignKeyCollection(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		changed := false
+		for _, foreignKey := range fkColl.AllKeys() {
+			if !foreignKey.IsResolved() || (tables.Size() != 0 && !tables.Contains(foreignKey.TableName)) {
+				continue
+			}
+			if !isCascadingAction(foreignKey.OnDelete) && !isCascadingAction(foreignKey.OnUpdate) {
+				continue
+			}
+
+			postChild, edits, err := foreignKeyCascadeEdits(ctx, foreignKey, mergedRoot, baseRoot, changedSince)
+			if err != nil {
+				return nil, err
+			}
+			if len(edits) == 0 {
+				continue
+			}
+
+			tbl, err := applyCascadeEdits(ctx, postChild, edits)
+			if err != nil {
+				return nil, err
+			}
+			mergedRoot, err = mergedRoot.PutTable(ctx, doltdb.TableName{Name: postChild.TableName}, tbl)
+			if err != nil {
+				return nil, err
+			}
+			changed = true
+		}
+		if !changed {
+			break
+		}
+		baseRoot, changedSince = roundRoot, nil
+	}
+	return mergedRoot, nil
+}
+
+func isCascadingAction(action doltdb.ForeignKeyReferentialAction) bool {
+	return action == doltdb.ForeignKeyReferentialAction_Cascade || action == doltdb.ForeignKeyReferentialAction_SetNull
+}
+
+// foreignKeyCascadeEdits returns the child table of |foreignKey| and the changes its referential actions make to the
+// child rows in |root| whose parent rows were deleted or updated since |baseRoot|. If |changedSince| is non-nil, the
+// child rows which are the same in |changedSince| are left alone.
+func foreignKeyCascadeEdits(ctx context.Context, foreignKey doltdb.ForeignKey, root, baseRoot, changedSince doltdb.RootValue) (*constraintViolationsLoadedTable, []cascadeEdit, error) {
+	postParent, ok, err := newConstraintViolationsLoadedTable(ctx, foreignKey.ReferencedTableName, foreignKey.ReferencedTableIndex, root)
+	if err != nil || !ok {
+		return nil, nil, err
+	}
+	postChild, ok, err := newConstraintViolationsLoadedTable(ctx, foreignKey.TableName, foreignKey.TableIndex, root)
+	if err != nil || !ok {
+		return nil, nil, err
+	}
+	preParent, _, err := newConstraintViolationsLoadedTable(ctx, foreignKey.ReferencedTableName, foreignKey.ReferencedTableIndex, baseRoot)
+	if err == doltdb.ErrTableNotFound {
+		// every parent row was added, so no child can reference a changed parent
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	if !schema.SchemasAreEqual(preParent.Schema, postParent.Schema) || schema.IsKeyless(postChild.Schema) {
+		return nil, nil, nil
+	}
+
+	// the referential actions change the non-primary key columns of the child
+	childVals := make([]int, len(foreignKey.TableColumns))
+	for i, tag := range foreignKey.TableColumns {
+		j, ok := postChild.Schema.GetNonPKCols().StoredIndexByTag(tag)
+		if !ok {
+			return nil, nil, nil
+		}
+		childVals[i] = j
+	}
+
+	var preChildRowData *prolly.Map
+	if changedSince != nil {
+		preChild, _, err := newConstraintViolationsLoadedTable(ctx, foreignKey.TableName, foreignKey.TableIndex, changedSince)
+		if err == nil {
+			m := durable.ProllyMapFromIndex(preChild.RowData)
+			preChildRowData = &m
+		} else if err != doltdb.ErrTableNotFound {
+			return nil, nil, err
+		}
+	}
+
+	preParentRowData := durable.ProllyMapFromIndex(preParent.RowData)
+	postParentRowData := durable.ProllyMapFromIndex(postParent.RowData)
+	postParentIndexData := durable.ProllyMapFromIndex(postParent.IndexData)
+	childPriIdx := durable.ProllyMapFromIndex(postChild.RowData)
+	childScndryIdx := durable.ProllyMapFromIndex(postChild.IndexData)
+	primaryKD, childVD := childPriIdx.Descriptors()
+
+	idxDesc, _ := postParentIndexData.Descriptors()
+	partialDesc := idxDesc.PrefixDesc(len(foreignKey.TableColumns))
+	partialKB := val.NewTupleBuilder(partialDesc)
+	childVB := val.NewTupleBuilder(childVD)
+
+	var edits []cascadeEdit
+	edited := make(map[string]struct{})
+	err = prolly.DiffMaps(ctx, preParentRowData, postParentRowData, false, func(ctx context.Context, diff tree.Diff) error {
+		var action doltdb.ForeignKeyReferentialAction
+		switch diff.Type {
+		case tree.RemovedDiff:
+			action = foreignKey.OnDelete
+		case tree.ModifiedDiff:
+			action = foreignKey.OnUpdate
+		default:
+			return nil
+		}
+		if !isCascadingAction(action) {
+			return nil
+		}
+
+		partialKey, hadNulls := makePartialKey(partialKB, foreignKey.ReferencedTableColumns, postParent.Index, postParent.Schema, val.Tuple(diff.Key), val.Tuple(diff.From), preParentRowData.Pool())
+		if hadNulls {
+			return nil
+		}
+
+		itr, err := postParentIndexData.IterRange(ctx, prolly.PrefixRange(partialKey, partialDesc))
+		if err != nil {
+			return err
+		}
+		if _, _, err = itr.Next(ctx); err == nil {
+			// some other equivalent parents exist
+			return nil
+		} else if err != io.EOF {
+			return err
+		}
+
+		// the values the child's foreign key columns are set to, which are NULL unless updates are cascaded
+		newVals := make([][]byte, len(childVals))
+		if diff.Type == tree.ModifiedDiff && action == doltdb.ForeignKeyReferentialAction_Cascade {
+			newKey, hadNulls := makePartialKey(partialKB, foreignKey.ReferencedTableColumns, postParent.Index, postParent.Schema, val.Tuple(diff.Key), val.Tuple(diff.To), preParentRowData.Pool())
+			if hadNulls {
+				return nil
+			}
+			for i, j := range childVals {
+				if partialDesc.Types[i].Enc != childVD.Types[j].Enc {
+					return nil
+				}
+				newVals[i] = newKey.GetField(i)
+			}
+		} else if action == doltdb.ForeignKeyReferentialAction_SetNull {
+			for _, j := range childVals {
+				if !childVD.Types[j].Nullable {
+					return nil
+				}
+			}
+		}
+
+		return cascadeToChildren(ctx, partialKey, partialDesc, primaryKD, childPriIdx, childScndryIdx, func(key, value val.Tuple) error {
+			if _, ok := edited[string(key)]; ok {
+				return nil
+			}
+			if preChildRowData != nil {
+				var preValue val.Tuple
+				err := preChildRowData.Get(ctx, key, func(_, v val.Tuple) error {
+					preValue = v
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				if preValue != nil && bytes.Equal(preValue, value) {
+					return nil
+				}
+			}
+			edited[string(key)] = struct{}{}
+
+			if diff.Type == tree.RemovedDiff && action == doltdb.ForeignKeyReferentialAction_Cascade {
+				edits = append(edits, cascadeEdit{key: key, from: value})
+				return nil
+			}
+			for i := 0; i < childVD.Count(); i++ {
+				childVB.PutRaw(i, value.GetField(i))
+			}
+			for i, j := range childVals {
+				childVB.PutRaw(j, newVals[i])
+			}
+			edits = append(edits, cascadeEdit{key: key, from: value, to: childVB.BuildPermissive(childPriIdx.Pool())})
+			return nil
+		})
+	})
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	return postChild, edits, nil
+}
+
+// cascadeToChildren calls |cb| with the primary key and value of each row of the child table whose foreign key columns
+// match |partialKey|.
+func cascadeToChildren(
+	ctx context.Context,
+	partialKey val.Tuple,
+	partialKeyDesc val.TupleDesc,
+	primaryKD val.TupleDesc,
+	primaryIdx prolly.Map,
+	secondaryIdx prolly.Map,
+	cb func(key, value val.Tuple) error,
+) error {
+	itr, err := creation.NewPrefixItr(ctx, partialKey, partialKeyDesc, secondaryIdx)
+	if err != nil {
+		return err
+	}
+
+	kb := val.NewTupleBuilder(primaryKD)
+	for k, _, err := itr.Next(ctx); err == nil; k, _, err = itr.Next(ctx) {
+		// the pks of the table are the last keys of the index
+		o := k.Count() - primaryKD.Count()
+		for i := 0; i < primaryKD.Count(); i++ {
+			kb.PutRaw(i, k.GetField(o+i))
+		}
+		primaryIdxKey := kb.Build(primaryIdx.Pool())
+
+		var value val.Tuple
+		err = primaryIdx.Get(ctx, primaryIdxKey, func(k, v val.Tuple) error {
+			value = v
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err = cb(primaryIdxKey, value); err != nil {
+			return err
+		}
+	}
+	if err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// applyCascadeEdits applies |edits| to the rows and secondary indexes of |child|.
+func applyCascadeEdits(ctx *sql.Context, child *constraintViolationsLoadedTable, edits []cascadeEdit) (*doltdb.Table, error) {
+	mutMap := durable.ProllyMapFromIndex(child.RowData).Mutate()
+	idxSet, err := child.Table.GetIndexSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mutIdxs, err := GetMutableSecondaryIdxs(ctx, child.Schema, child.Schema, child.TableName, idxSet)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, edit := range edits {
+		if edit.to == nil {
+			err = mutMap.Delete(ctx, edit.key)
+		} else {
+			err = mutMap.Put(ctx, edit.key, edit.to)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mutIdx := range mutIdxs {
+			if edit.to == nil {
+				err = mutIdx.DeleteEntry(ctx, edit.key, edit.from)
+			} else {
+				err = mutIdx.UpdateEntry(ctx, edit.key, edit.from, edit.to)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	newMap, err := mutMap.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := child.Table.UpdateRows(ctx, durable.IndexFromProllyMap(newMap))
+	if err != nil {
+		return nil, err
+	}
+	for _, mutIdx := range mutIdxs {
+		m, err := mutIdx.Map(ctx)
+		if err != nil {
+			return nil, err
+		}
+		idxSet, err = idxSet.PutIndex(ctx, mutIdx.Name, durable.IndexFromProllyMap(m))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tbl.SetIndexSet(ctx, idxSet)
+}
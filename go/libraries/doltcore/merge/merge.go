@@ -354,6 +354,11 @@ func MergeRoots(
 		}
 	}
 
+	mergedRoot, err = cascadeForeignKeyActions(ctx, mergedRoot, ancRoot, tableSet)
+	if err != nil {
+		return nil, err
+	}
+
 	mergedRoot, _, err = AddForeignKeyViolations(ctx, mergedRoot, ancRoot, tableSet, h)
 	if err != nil {
 		return nil, err
@@ -88,6 +88,14 @@ func mergeProllySecondaryIndexes(
 
 		mergedIndex, err := func() (durable.Index, error) {
 			if forceIndexRebuild || rebuildRequired {
+				if !forceIndexRebuild && leftIndexDefinition == nil {
+					// the index was added on the right, such as by ALTER TABLE ... ADD INDEX in a
+					// transaction which is being merged with concurrent writes
+					idx, ok, err := catchUpIndex(ctx, tm, rightSet, finalSch, index, mergedM)
+					if err != nil || ok {
+						return idx, err
+					}
+				}
 				return buildIndex(ctx, tm.vrw, tm.ns, finalSch, index, mergedM, artifacts, tm.rightSrc, tm.name)
 			}
 			return durable.IndexFromProllyMap(left), nil
@@ -157,6 +165,51 @@ func buildIndex(
 	return mergedIndex, nil
 }
 
+// catchUpIndex returns |index| from the right side of the merge, updated with the differences between the right
+// side's rows and the merged rows |merged|. When an index is added on one side of a merge, this avoids rebuilding it
+// from every row in the table: only the rows changed by the other side are applied to it. If an online index build
+// already caught the index up with some of those rows, only the rest are applied. The index can only be
+// caught up when the right side of the merge has the merged schema, and false is returned if it can't be. Unique
+// indexes which might gain duplicate entries also can't be caught up, since the violations must be recorded by a
+// full rebuild.
+func catchUpIndex(
+	ctx *sql.Context,
+	tm *TableMerger,
+	rightSet durable.IndexSet,
+	finalSch schema.Schema,
+	index schema.Index,
+	merged prolly.Map,
+) (durable.Index, bool, error) {
+	if schema.IsKeyless(finalSch) || !schema.SchemasAreEqual(tm.rightSch, finalSch) {
+		return nil, false, nil
+	}
+	rightDef := tm.rightSch.Indexes().GetByName(index.Name())
+	if rightDef == nil || !rightDef.Equals(index) {
+		return nil, false, nil
+	}
+
+	idx, err := rightSet.GetIndex(ctx, tm.rightSch, nil, index.Name())
+	if err != nil {
+		return nil, false, err
+	}
+	rows, err := tm.rightTbl.GetRowData(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	from, secondary := durable.ProllyMapFromIndex(rows), durable.ProllyMapFromIndex(idx)
+	// an online index build may have already caught the index up with rows written since it was built
+	if caughtUpRows, caughtUp, ok := takeCaughtUpIndex(tm.name, finalSch, index, secondary); ok {
+		from, secondary = caughtUpRows, caughtUp
+	}
+
+	m, ok, err := CatchUpSecondaryIndex(ctx, tm.name, finalSch, index, secondary, from, merged)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return durable.IndexFromProllyMap(m), true, nil
+}
+
 // applyEdit applies |edit| to |idx|. If |len(edit.To)| == 0, then action is
 // a delete, if |len(edit.From)| == 0 then it is an insert, otherwise it is an
 // update.
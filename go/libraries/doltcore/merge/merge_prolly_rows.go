@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
@@ -69,12 +70,13 @@ func mergeProllyTable(ctx context.Context, tm *TableMerger, mergedSch schema.Sch
 	}
 	leftRows := durable.ProllyMapFromIndex(lr)
 	valueMerger := newValueMerger(mergedSch, tm.leftSch, tm.rightSch, tm.ancSch, leftRows.Pool(), tm.ns)
+	valueMerger.sequences = strings.EqualFold(tm.name, doltdb.SequencesTableName)
 
-	if !valueMerger.leftMapping.IsIdentityMapping() {
+	if !isStoredIdentityMapping(valueMerger.leftMapping, mergedSch, tm.leftSch) {
 		mergeInfo.LeftNeedsRewrite = true
 	}
 
-	if !valueMerger.rightMapping.IsIdentityMapping() {
+	if !isStoredIdentityMapping(valueMerger.rightMapping, mergedSch, tm.rightSch) {
 		mergeInfo.RightNeedsRewrite = true
 	}
 
@@ -870,7 +872,7 @@ func (nv nullValidator) validateDiff(ctx context.Context, diff tree.ThreeWayDiff
 	case tree.DiffOpRightAdd, tree.DiffOpRightModify:
 		var violations []string
 		for to, from := range nv.rightMap {
-			col := nv.final.GetNonPKCols().GetByIndex(to)
+			col := nv.final.GetNonPKCols().GetByStoredIndex(to)
 			if col.IsNullable() {
 				continue
 			}
@@ -905,7 +907,7 @@ func (nv nullValidator) validateDiff(ctx context.Context, diff tree.ThreeWayDiff
 	case tree.DiffOpLeftAdd, tree.DiffOpLeftModify:
 		var violations []string
 		for to, from := range nv.leftMap {
-			col := nv.final.GetNonPKCols().GetByIndex(to)
+			col := nv.final.GetNonPKCols().GetByStoredIndex(to)
 			if col.IsNullable() {
 				continue
 			}
@@ -947,7 +949,7 @@ func (nv nullValidator) validateDiff(ctx context.Context, diff tree.ThreeWayDiff
 	case tree.DiffOpDivergentModifyResolved:
 		var violations []string
 		for to, _ := range nv.leftMap {
-			col := nv.final.GetNonPKCols().GetByIndex(to)
+			col := nv.final.GetNonPKCols().GetByStoredIndex(to)
 			if !col.IsNullable() && diff.Merged.FieldIsNull(to) {
 				violations = append(violations, col.Name)
 			}
@@ -1127,23 +1129,7 @@ func (m *primaryMerger) merge(ctx *sql.Context, diff tree.ThreeWayDiff, sourceSc
 		// the merge
 		merged := diff.Merged
 		if hasStoredGeneratedColumns(m.finalSch) {
-			defaults, err := resolveDefaults(ctx, m.tableMerger.name, m.finalSch, m.tableMerger.rightSch)
-			if err != nil {
-				return err
-			}
-
-			tempTupleValue, err := remapTupleWithColumnDefaults(
-				ctx,
-				diff.Key,
-				merged,
-				m.finalSch.GetValueDescriptor(),
-				m.valueMerger.rightMapping,
-				m.tableMerger,
-				m.tableMerger.rightSch,
-				m.finalSch,
-				defaults,
-				m.valueMerger.syncPool,
-				true)
+			tempTupleValue, err := regenerateStoredGeneratedColumns(ctx, m.tableMerger, m.finalSch, diff.Key, merged, m.valueMerger.syncPool)
 			if err != nil {
 				return err
 			}
@@ -1182,12 +1168,12 @@ func (m *primaryMerger) merge(ctx *sql.Context, diff tree.ThreeWayDiff, sourceSc
 
 func resolveDefaults(ctx *sql.Context, tableName string, mergedSchema schema.Schema, sourceSchema schema.Schema) ([]sql.Expression, error) {
 	var exprs []sql.Expression
-	i := 0
 
 	// We want a slice of expressions in the order of the merged schema, but with column indexes from the source schema,
 	// against which they will be evaluated
 	err := mergedSchema.GetNonPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-		if col.Virtual {
+		i, ok := mergedSchema.GetNonPKCols().StoredIndexByTag(tag)
+		if !ok || col.Virtual {
 			return false, nil
 		}
 
@@ -1202,7 +1188,6 @@ func resolveDefaults(ctx *sql.Context, tableName string, mergedSchema schema.Sch
 			exprs[i] = expr
 		}
 
-		i++
 		return false, nil
 	})
 	if err != nil {
@@ -1240,6 +1225,33 @@ func indexOf(col string, cols []string) int {
 	return -1
 }
 
+// regenerateStoredGeneratedColumns recomputes the stored generated columns of |value|, a value tuple of the merged
+// schema |sch|. A cell-wise merge can change the columns that a generated column is computed from, so the merged
+// generated values can't be taken from either side of the merge.
+func regenerateStoredGeneratedColumns(ctx *sql.Context, tm *TableMerger, sch schema.Schema, key, value val.Tuple, pool pool.BuffPool) (val.Tuple, error) {
+	exprs, err := resolveDefaults(ctx, tm.name, sch, sch)
+	if err != nil {
+		return nil, err
+	}
+
+	vd := sch.GetValueDescriptor()
+	tb := val.NewTupleBuilder(vd)
+	for i := 0; i < vd.Count(); i++ {
+		tb.PutRaw(i, value.GetField(i))
+	}
+	for i := 0; i < sch.GetNonPKCols().StoredSize(); i++ {
+		col := sch.GetNonPKCols().GetByStoredIndex(i)
+		if col.Generated == "" || len(exprs) == 0 {
+			continue
+		}
+		err = writeTupleExpression(ctx, key, value, exprs[i], col, sch, tm, tb, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tb.Build(pool), nil
+}
+
 func hasStoredGeneratedColumns(sch schema.Schema) bool {
 	hasGenerated := false
 	sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
@@ -1544,9 +1556,9 @@ func writeTupleExpression(
 func convertValueToNewType(value interface{}, newTypeInfo typeinfo.TypeInfo, tm *TableMerger, from int, rightSide bool) (interface{}, error) {
 	var previousTypeInfo typeinfo.TypeInfo
 	if rightSide {
-		previousTypeInfo = tm.rightSch.GetNonPKCols().GetByIndex(from).TypeInfo
+		previousTypeInfo = tm.rightSch.GetNonPKCols().GetByStoredIndex(from).TypeInfo
 	} else {
-		previousTypeInfo = tm.leftSch.GetNonPKCols().GetByIndex(from).TypeInfo
+		previousTypeInfo = tm.leftSch.GetNonPKCols().GetByStoredIndex(from).TypeInfo
 	}
 
 	if newTypeInfo.Equals(previousTypeInfo) {
@@ -1619,6 +1631,9 @@ type valueMerger struct {
 	syncPool                               pool.BuffPool
 	keyless                                bool
 	ns                                     tree.NodeStore
+	// sequences is set when merging the dolt_sequences table, where a sequence used on both sides of the merge
+	// continues after the larger of the values used, rather than conflicting
+	sequences bool
 }
 
 func newValueMerger(merged, leftSch, rightSch, baseSch schema.Schema, syncPool pool.BuffPool, ns tree.NodeStore) *valueMerger {
@@ -1650,36 +1665,58 @@ func newValueMerger(merged, leftSch, rightSch, baseSch schema.Schema, syncPool p
 // generateSchemaMappings returns three schema mappings: 1) mapping the |leftSch| to |mergedSch|,
 // 2) mapping |rightSch| to |mergedSch|, and 3) mapping |baseSch| to |mergedSch|. Columns are
 // mapped from the source schema to destination schema by finding an identical tag, or if no
-// identical tag is found, then falling back to a match on column name and type.
+// identical tag is found, then falling back to a match on column name and type. Dropped columns of |mergedSch| aren't
+// mapped, so their values are always NULL.
 func generateSchemaMappings(mergedSch, leftSch, rightSch, baseSch schema.Schema) (leftMapping, rightMapping, baseMapping val.OrdinalMapping) {
-	n := mergedSch.GetNonPKCols().StoredSize()
+	nonPKCols := mergedSch.GetNonPKCols()
+	n := nonPKCols.StoredSize()
 	leftMapping = make(val.OrdinalMapping, n)
 	rightMapping = make(val.OrdinalMapping, n)
 	baseMapping = make(val.OrdinalMapping, n)
 
-	i := 0
-	for _, col := range mergedSch.GetNonPKCols().GetColumns() {
-		if col.Virtual {
+	for i := 0; i < n; i++ {
+		col := nonPKCols.GetByStoredIndex(i)
+		if col.Dropped {
+			leftMapping[i], rightMapping[i], baseMapping[i] = -1, -1, -1
 			continue
 		}
 		leftMapping[i] = findNonPKColumnMappingByTagOrName(leftSch, col)
 		rightMapping[i] = findNonPKColumnMappingByTagOrName(rightSch, col)
 		baseMapping[i] = findNonPKColumnMappingByTagOrName(baseSch, col)
-		i++
 	}
 
 	return leftMapping, rightMapping, baseMapping
 }
 
+// isStoredIdentityMapping returns whether |mapping|, from |sch| to |mergedSch|, maps every column to itself, except
+// for the dropped columns of |mergedSch|, which |sch| must store in the same places. The rows of |sch| don't need to
+// be rewritten for |mergedSch| if this is the case, since the values of dropped columns are never read.
+func isStoredIdentityMapping(mapping val.OrdinalMapping, mergedSch, sch schema.Schema) bool {
+	if mergedSch.GetNonPKCols().StoredSize() != sch.GetNonPKCols().StoredSize() {
+		return false
+	}
+	for i, j := range mapping {
+		if i == j {
+			continue
+		}
+		merged, stored := mergedSch.GetNonPKCols().GetByStoredIndex(i), sch.GetNonPKCols().GetByStoredIndex(i)
+		if !merged.Dropped || !stored.Dropped || merged.Tag != stored.Tag {
+			return false
+		}
+	}
+	return true
+}
+
 // findNonPKColumnMappingByName returns the index of the column with the given name in the given schema, or -1 if it
 // doesn't exist.
 func findNonPKColumnMappingByName(sch schema.Schema, name string) int {
-	leftNonPKCols := sch.GetNonPKCols()
-	if leftNonPKCols.Contains(name) {
-		return leftNonPKCols.IndexOf(name)
-	} else {
-		return -1
+	nonPKCols := sch.GetNonPKCols()
+	if col, ok := nonPKCols.GetByName(name); ok {
+		if idx, ok := nonPKCols.StoredIndexByTag(col.Tag); ok {
+			return idx
+		}
 	}
+	return -1
 }
 
 // findNonPKColumnMappingByTagOrName returns the index of the column with the given tag in the given schema. If a
@@ -1687,6 +1724,9 @@ func findNonPKColumnMappingByName(sch schema.Schema, name string) int {
 // matching column is found, then this function returns -1.
 func findNonPKColumnMappingByTagOrName(sch schema.Schema, col schema.Column) int {
 	if idx, ok := sch.GetNonPKCols().StoredIndexByTag(col.Tag); ok {
+		if sch.GetNonPKCols().GetByStoredIndex(idx).Dropped {
+			return -1
+		}
 		return idx
 	} else {
 		return findNonPKColumnMappingByName(sch, col.Name)
@@ -1843,10 +1883,10 @@ func (m *valueMerger) processColumn(ctx *sql.Context, i int, left, right, base v
 	leftCol, leftColIdx, leftColExists := getColumn(&left, &m.leftMapping, i)
 	rightCol, rightColIdx, rightColExists := getColumn(&right, &m.rightMapping, i)
 	resultType := m.resultVD.Types[i]
-	resultColumn := m.resultSchema.GetNonPKCols().GetByIndex(i)
+	resultColumn := m.resultSchema.GetNonPKCols().GetByStoredIndex(i)
 	generatedColumn := resultColumn.Generated != ""
 
-	sqlType := m.resultSchema.GetNonPKCols().GetByIndex(i).TypeInfo.ToSqlType()
+	sqlType := resultColumn.TypeInfo.ToSqlType()
 
 	// We previously asserted that left and right are not nil.
 	// But base can be nil in the event of convergent inserts.
@@ -1954,6 +1994,12 @@ func (m *valueMerger) processColumn(ctx *sql.Context, i int, left, right, base v
 		if generatedColumn {
 			return leftCol, false, nil
 		}
+		if m.sequences && resultColumn.Tag == schema.DoltSequencesNextValueTag {
+			if m.resultVD.Comparator().CompareValues(i, leftCol, rightCol, resultType) > 0 {
+				return leftCol, false, nil
+			}
+			return rightCol, false, nil
+		}
 		// concurrent modification
 		// if the result type is JSON, we can attempt to merge the JSON changes.
 		dontMergeJsonVar, err := ctx.Session.GetSessionVariable(ctx, "dolt_dont_merge_json")
@@ -2154,7 +2200,7 @@ func convert(ctx context.Context, fromDesc, toDesc val.TupleDesc, toSchema schem
 	if err != nil {
 		return nil, err
 	}
-	sqlType := toSchema.GetNonPKCols().GetByIndex(toIndex).TypeInfo.ToSqlType()
+	sqlType := toSchema.GetNonPKCols().GetByStoredIndex(toIndex).TypeInfo.ToSqlType()
 	convertedCell, _, err := sqlType.Convert(parsedCell)
 	if err != nil {
 		return nil, err
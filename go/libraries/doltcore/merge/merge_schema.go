@@ -187,6 +187,7 @@ func SchemaMerge(ctx context.Context, format *storetypes.NomsBinFormat, ourSch,
 	if len(sc.ColConflicts) > 0 {
 		return nil, sc, mergeInfo, diffInfo, nil
 	}
+	mergedCC = withDroppedColumns(mergedCC, ourSch.GetAllCols())
 
 	var mergedIdxs schema.IndexCollection
 	mergedIdxs, sc.IdxConflicts = mergeIndexes(mergedCC, ourSch, theirSch, ancSch)
@@ -373,6 +374,42 @@ func checkUnmergeableNewColumns(tblName string, columnMappings columnMappings) e
 	return nil
 }
 
+// withDroppedColumns returns |mergedCC| with the dropped columns of |ourCC| which it doesn't have, each after the
+// column it follows in |ourCC|. Our rows store values for our dropped columns, so they don't have to be rewritten for
+// the merged schema if it stores them in the same places.
+func withDroppedColumns(mergedCC, ourCC *schema.ColCollection) *schema.ColCollection {
+	if !ourCC.HasDroppedColumns() {
+		return mergedCC
+	}
+
+	// the dropped columns after each of our columns which were merged, by its tag
+	var first []schema.Column
+	after := make(map[uint64][]schema.Column)
+	prev, hasPrev := uint64(0), false
+	for _, col := range ourCC.GetColumnsWithDropped() {
+		if !col.Dropped {
+			if _, ok := mergedCC.GetByTag(col.Tag); ok {
+				prev, hasPrev = col.Tag, true
+			}
+			continue
+		} else if _, ok := mergedCC.GetByTag(col.Tag); ok {
+			continue
+		}
+		if hasPrev {
+			after[prev] = append(after[prev], col)
+		} else {
+			first = append(first, col)
+		}
+	}
+
+	cols := first
+	for _, col := range mergedCC.GetColumns() {
+		cols = append(cols, col)
+		cols = append(cols, after[col.Tag]...)
+	}
+	return schema.NewColCollection(cols...)
+}
+
 type MergeInfo struct {
 	LeftNeedsRewrite           bool
 	RightNeedsRewrite          bool
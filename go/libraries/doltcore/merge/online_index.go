@@ -0,0 +1,146 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// maxCaughtUpIndexes is the number of caught up indexes kept by CacheCaughtUpIndex.
+const maxCaughtUpIndexes = 64
+
+// caughtUpIndex is the data of a secondary index caught up with the rows |rows| of a table with schema |sch|. |built|
+// is the data the index was built with, before it was caught up.
+type caughtUpIndex struct {
+	sch       schema.Schema
+	index     schema.Index
+	built     prolly.Map
+	rows      prolly.Map
+	secondary prolly.Map
+}
+
+// errIndexCatchUpFailed is returned from a diff callback when an index can't be caught up, and must be rebuilt.
+var errIndexCatchUpFailed = errors.New("index must be rebuilt")
+
+var caughtUpIndexes = struct {
+	mu sync.Mutex
+	m  map[string]caughtUpIndex
+}{m: make(map[string]caughtUpIndex)}
+
+// CatchUpSecondaryIndex returns the secondary index data |secondary|, which was built for the rows |from| of the table
+// |tableName| with schema |sch|, updated with the differences between |from| and |to|. Only the changed rows are
+// applied, so this is much cheaper than building the index from |to| when few rows have changed. Returns false if
+// the index can't be caught up, because it's a unique index and the changes would add a duplicate entry to it: the
+// violation must be found by building the index from every row instead.
+func CatchUpSecondaryIndex(
+	ctx *sql.Context,
+	tableName string,
+	sch schema.Schema,
+	index schema.Index,
+	secondary, from, to prolly.Map,
+) (prolly.Map, bool, error) {
+	mut, err := NewMutableSecondaryIdx(ctx, secondary, sch, sch, tableName, index)
+	if err != nil {
+		return prolly.Map{}, false, err
+	}
+	prefixDesc := secondary.KeyDesc().PrefixDesc(index.Count())
+
+	err = prolly.DiffMaps(ctx, from, to, false, func(ctx context.Context, diff tree.Diff) error {
+		if index.IsUnique() && diff.Type != tree.RemovedDiff {
+			newKey, err := mut.mergedBuilder.SecondaryKeyFromRow(ctx, val.Tuple(diff.Key), val.Tuple(diff.To))
+			if err != nil {
+				return err
+			}
+			if !prefixDesc.HasNulls(newKey) {
+				var collision bool
+				err = mut.mut.GetPrefix(ctx, newKey, prefixDesc, func(existingKey, _ val.Tuple) error {
+					collision = existingKey != nil && secondary.KeyDesc().Compare(existingKey, newKey) != 0
+					return nil
+				})
+				if err != nil {
+					return err
+				} else if collision {
+					return errIndexCatchUpFailed
+				}
+			}
+		}
+		return applyEdit(ctx, mut, val.Tuple(diff.Key), val.Tuple(diff.From), val.Tuple(diff.To))
+	})
+	if errors.Is(err, errIndexCatchUpFailed) {
+		return prolly.Map{}, false, nil
+	} else if err != nil && err != io.EOF {
+		return prolly.Map{}, false, err
+	}
+
+	m, err := mut.Map(ctx)
+	if err != nil {
+		return prolly.Map{}, false, err
+	}
+	return m, true, nil
+}
+
+// CacheCaughtUpIndex records that |secondary| is the data of |index| for the rows |rows| of the table |tableName|
+// with schema |sch|, caught up from the data |built| was built with. An online index build, which builds an index
+// from a snapshot of a table and then catches it up with the rows written since, records the index it caught up.
+// When its transaction is merged with the transactions committed since the snapshot, the merge catches the index up
+// from there instead of from the snapshot, which is only the rows written after the build finished.
+func CacheCaughtUpIndex(tableName string, sch schema.Schema, index schema.Index, built, rows, secondary prolly.Map) {
+	caughtUpIndexes.mu.Lock()
+	defer caughtUpIndexes.mu.Unlock()
+	key := caughtUpIndexKey(tableName, index)
+	if _, ok := caughtUpIndexes.m[key]; !ok && len(caughtUpIndexes.m) >= maxCaughtUpIndexes {
+		for k := range caughtUpIndexes.m {
+			delete(caughtUpIndexes.m, k)
+			break
+		}
+	}
+	caughtUpIndexes.m[key] = caughtUpIndex{
+		sch:       sch,
+		index:     index,
+		built:     built,
+		rows:      rows,
+		secondary: secondary,
+	}
+}
+
+// takeCaughtUpIndex returns the rows and data of the index recorded by CacheCaughtUpIndex for |index| of the table
+// |tableName| with schema |sch|, if it was caught up from |built|. The recorded index is removed, so that it's only
+// used by the merge of the transaction which built it.
+func takeCaughtUpIndex(tableName string, sch schema.Schema, index schema.Index, built prolly.Map) (prolly.Map, prolly.Map, bool) {
+	caughtUpIndexes.mu.Lock()
+	defer caughtUpIndexes.mu.Unlock()
+	key := caughtUpIndexKey(tableName, index)
+	c, ok := caughtUpIndexes.m[key]
+	if !ok || c.built.NodeStore() != built.NodeStore() || c.built.HashOf() != built.HashOf() ||
+		!c.index.Equals(index) || !schema.SchemasAreEqual(c.sch, sch) {
+		return prolly.Map{}, prolly.Map{}, false
+	}
+	delete(caughtUpIndexes.m, key)
+	return c.rows, c.secondary, true
+}
+
+func caughtUpIndexKey(tableName string, index schema.Index) string {
+	return tableName + "\x00" + index.Name()
+}
@@ -364,7 +364,7 @@ func makePartialKey(kb *val.TupleBuilder, tags []uint64, idxSch schema.Index, tb
 			continue
 		}
 
-		j, _ := tblSch.GetNonPKCols().TagToIdx[tag]
+		j, _ := tblSch.GetNonPKCols().StoredIndexByTag(tag)
 		if v.FieldIsNull(j) {
 			return nil, true
 		}
@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -38,6 +37,10 @@ const (
 
 	manifestFile = "manifest"
 	migrationRef = "migration"
+
+	// migrationDir is the directory in the .dolt directory the database is migrated into. It's kept until the
+	// migration completes, so that an interrupted migration can be resumed.
+	migrationDir = "migration"
 )
 
 var (
@@ -50,17 +53,23 @@ type Environment struct {
 	Migration     *env.DoltEnv
 	Existing      *env.DoltEnv
 	DropConflicts bool
+	// Branches are the names of the branches to migrate, or empty to migrate every branch. When only some branches are
+	// migrated, tags and remote refs are only migrated if they point to a commit on one of them.
+	Branches []string
 }
 
-// NewEnvironment creates a migration Environment for |existing|.
+// NewEnvironment creates a migration Environment for |existing|. If an earlier migration of |existing| was
+// interrupted, the Environment resumes it.
 func NewEnvironment(ctx context.Context, existing *env.DoltEnv) (Environment, error) {
-	mfs, err := getMigrateFS(existing.FS)
+	mfs, resume, err := getMigrateFS(existing.FS)
 	if err != nil {
 		return Environment{}, err
 	}
 
-	if err = initMigrationDB(ctx, existing, existing.FS, mfs); err != nil {
-		return Environment{}, err
+	if !resume {
+		if err = initMigrationDB(ctx, existing, existing.FS, mfs); err != nil {
+			return Environment{}, err
+		}
 	}
 
 	mdb, err := doltdb.LoadDoltDB(ctx, targetFormat, doltdb.LocalDirDoltDB, mfs)
@@ -95,6 +104,8 @@ func initMigrationDB(ctx context.Context, existing *env.DoltEnv, src, dest files
 		return err
 	}
 
+	// the migration directory is in |src|'s .dolt directory, but isn't part of the database
+	skip := filepath.Join(doltDir, migrationDir)
 	ierr := src.Iter(doltDir, true, func(path string, size int64, isDir bool) (stop bool) {
 		path, err = filepath.Rel(base, path)
 		if err != nil {
@@ -102,6 +113,9 @@ func initMigrationDB(ctx context.Context, existing *env.DoltEnv, src, dest files
 			return
 		}
 
+		if path == skip || strings.HasPrefix(path, skip+string(filepath.Separator)) {
+			return
+		}
 		if isDir {
 			err = dest.MkDirs(path)
 			stop = err != nil
@@ -165,7 +179,16 @@ func initMigrationDB(ctx context.Context, existing *env.DoltEnv, src, dest files
 	}
 
 	_, err = db.Commit(ctx, ds, rv.NomsValue(), datas.CommitOptions{Meta: meta})
-	return nil
+	if err != nil {
+		return err
+	}
+
+	// the journal is created last, its presence marks a migration database which is ready to be resumed
+	j, _, err := openJournal(dest)
+	if err != nil {
+		return err
+	}
+	return j.Close()
 }
 
 // SwapChunkStores atomically swaps the ChunkStores of |menv.Migration| and |menv.Existing|.
@@ -248,20 +271,50 @@ func swapManifests(ctx context.Context, src, dest filesys.Filesys) (err error) {
 	// exit immediately!
 }
 
-func getMigrateFS(existing filesys.Filesys) (filesys.Filesys, error) {
-	uniq := fmt.Sprintf("dolt_migration_%d", time.Now().UnixNano())
-	tmpPath := filepath.Join(existing.TempDir(), uniq)
-	if err := existing.MkDirs(tmpPath); err != nil {
-		return nil, err
+// getMigrateFS returns the filesystem of the directory the database of |existing| is migrated into, and whether it
+// holds an interrupted migration to resume. Anything else left in the directory is removed.
+func getMigrateFS(existing filesys.Filesys) (mfs filesys.Filesys, resume bool, err error) {
+	path, err := existing.Abs(filepath.Join(doltDir, migrationDir))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if exists, _ := existing.Exists(path); exists {
+		mfs, err = filesys.LocalFilesysWithWorkingDir(path)
+		if err != nil {
+			return nil, false, err
+		}
+		if hasJournal(mfs) {
+			return mfs, true, nil
+		}
+		// the migration was interrupted before its database was initialized
+		if err = existing.Delete(path, true); err != nil {
+			return nil, false, err
+		}
 	}
 
-	mfs, err := filesys.LocalFilesysWithWorkingDir(tmpPath)
+	if err = existing.MkDirs(path); err != nil {
+		return nil, false, err
+	}
+	mfs, err = filesys.LocalFilesysWithWorkingDir(path)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if err = mfs.MkDirs(doltDir); err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	return mfs, false, nil
+}
+
+// RemoveMigrationDir removes the directory |existing| was migrated into, once the migration is complete.
+func RemoveMigrationDir(existing filesys.Filesys) error {
+	path, err := existing.Abs(filepath.Join(doltDir, migrationDir))
+	if err != nil {
+		return err
+	}
+	if exists, _ := existing.Exists(path); !exists {
+		return nil
 	}
-	return mfs, nil
+	return existing.Delete(path, true)
 }
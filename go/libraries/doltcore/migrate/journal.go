@@ -0,0 +1,111 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// journalFile is the file in the migration directory recording the commits migrated so far
+const journalFile = "migrated_commits.log"
+
+// journal records each commit as it's migrated, as a line of its old and new hashes, so that a migration which is
+// interrupted can be resumed without migrating those commits again. A commit is only recorded once it's been flushed
+// to the migrated database.
+type journal struct {
+	mu sync.Mutex
+	wr io.WriteCloser
+}
+
+// hasJournal returns whether |fs| has the journal of an earlier migration.
+func hasJournal(fs filesys.Filesys) bool {
+	exists, isDir := fs.Exists(journalFile)
+	return exists && !isDir
+}
+
+// openJournal opens the journal in |fs|, creating it if it doesn't exist, and returns the commits it records, as a
+// map from their old hashes to their new ones.
+func openJournal(fs filesys.Filesys) (*journal, map[hash.Hash]hash.Hash, error) {
+	migrated := make(map[hash.Hash]hash.Hash)
+	var data []byte
+	if hasJournal(fs) {
+		rd, err := fs.OpenForRead(journalFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err = io.ReadAll(rd)
+		if cerr := rd.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			// the last line is incomplete if the migration was interrupted while writing it
+			continue
+		}
+		old, ok := hash.MaybeParse(fields[0])
+		if !ok {
+			continue
+		}
+		new, ok := hash.MaybeParse(fields[1])
+		if !ok {
+			continue
+		}
+		migrated[old] = new
+	}
+
+	wr, err := fs.OpenForWriteAppend(journalFile, os.ModePerm)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		// end the incomplete line, so it isn't continued by the next commit recorded
+		if _, err = wr.Write([]byte{'\n'}); err != nil {
+			_ = wr.Close()
+			return nil, nil, err
+		}
+	}
+	return &journal{wr: wr}, migrated, nil
+}
+
+// record records that the commit |old| was migrated to |new|.
+func (j *journal) record(old, new hash.Hash) error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err := fmt.Fprintf(j.wr, "%s %s\n", old.String(), new.String())
+	return err
+}
+
+func (j *journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	return j.wr.Close()
+}
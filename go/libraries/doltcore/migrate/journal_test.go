@@ -0,0 +1,66 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestJournal(t *testing.T) {
+	fs := filesys.NewInMemFS([]string{"/migration"}, nil, "/migration")
+	assert.False(t, hasJournal(fs))
+
+	j, migrated, err := openJournal(fs)
+	require.NoError(t, err)
+	assert.Empty(t, migrated)
+	assert.True(t, hasJournal(fs))
+
+	old1, new1 := hash.Of([]byte("old1")), hash.Of([]byte("new1"))
+	old2, new2 := hash.Of([]byte("old2")), hash.Of([]byte("new2"))
+	require.NoError(t, j.record(old1, new1))
+	require.NoError(t, j.record(old2, new2))
+	require.NoError(t, j.Close())
+
+	// simulate a migration interrupted while recording a commit
+	wr, err := fs.OpenForWriteAppend(journalFile, os.ModePerm)
+	require.NoError(t, err)
+	_, err = wr.Write([]byte(hash.Of([]byte("old3")).String()))
+	require.NoError(t, err)
+	require.NoError(t, wr.Close())
+
+	j, migrated, err = openJournal(fs)
+	require.NoError(t, err)
+	assert.Equal(t, map[hash.Hash]hash.Hash{old1: new1, old2: new2}, migrated)
+
+	// commits recorded after resuming aren't lost to the incomplete line
+	old4, new4 := hash.Of([]byte("old4")), hash.Of([]byte("new4"))
+	require.NoError(t, j.record(old4, new4))
+	require.NoError(t, j.Close())
+	j, migrated, err = openJournal(fs)
+	require.NoError(t, err)
+	defer j.Close()
+	assert.Equal(t, map[hash.Hash]hash.Hash{old1: new1, old2: new2, old4: new4}, migrated)
+
+	var nilJournal *journal
+	assert.NoError(t, nilJournal.record(old1, new1))
+	assert.NoError(t, nilJournal.Close())
+}
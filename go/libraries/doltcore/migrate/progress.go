@@ -62,9 +62,15 @@ type progress struct {
 
 	vs *types.ValueStore
 	cs chunks.ChunkStore
+
+	// journal records migrated commits so an interrupted migration can be resumed, it's nil if it can't be
+	journal *journal
+	// migrated counts the commits migrated, including those migrated before the migration was resumed
+	migrated int
 }
 
-func newProgress(ctx context.Context, cs chunks.ChunkStore) (*progress, error) {
+// newProgress returns the progress of a migration into |cs|, which has already migrated the commits in |resumed|.
+func newProgress(ctx context.Context, cs chunks.ChunkStore, j *journal, resumed map[hash.Hash]hash.Hash) (*progress, error) {
 	kd := val.NewTupleDescriptor(val.Type{
 		Enc:      val.ByteStringEnc,
 		Nullable: false,
@@ -86,7 +92,7 @@ func newProgress(ctx context.Context, cs chunks.ChunkStore) (*progress, error) {
 	kb := val.NewTupleBuilder(kd)
 	vb := val.NewTupleBuilder(vd)
 
-	return &progress{
+	p := &progress{
 		stack:    make([]*doltdb.Commit, 0, 128),
 		mapping:  mut,
 		kb:       kb,
@@ -94,7 +100,14 @@ func newProgress(ctx context.Context, cs chunks.ChunkStore) (*progress, error) {
 		buffPool: ns.Pool(),
 		vs:       vs,
 		cs:       cs,
-	}, nil
+		journal:  j,
+	}
+	for old, new := range resumed {
+		if err = p.put(ctx, old, new); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
 }
 
 func (p *progress) Has(ctx context.Context, addr hash.Hash) (ok bool, err error) {
@@ -119,7 +132,17 @@ func (p *progress) Get(ctx context.Context, old hash.Hash) (new hash.Hash, err e
 	return
 }
 
+// Put records that the commit |old| was migrated to |new|. |new| must have been flushed to the migrated database,
+// since it's recorded in the journal used to resume the migration.
 func (p *progress) Put(ctx context.Context, old, new hash.Hash) (err error) {
+	if err = p.put(ctx, old, new); err != nil {
+		return err
+	}
+	return p.journal.record(old, new)
+}
+
+func (p *progress) put(ctx context.Context, old, new hash.Hash) (err error) {
+	p.migrated++
 	p.kb.PutByteString(0, old[:])
 	k := p.kb.Build(p.buffPool)
 	p.vb.PutByteString(0, new[:])
@@ -182,6 +205,14 @@ func persistMigratedCommitMapping(ctx context.Context, ddb *doltdb.DoltDB, mappi
 	}
 
 	br := ref.NewBranchRef(MigratedCommitsBranch)
+	// a resumed migration may have persisted a mapping of fewer commits already
+	if ok, err := ddb.HasRef(ctx, br); err != nil {
+		return err
+	} else if ok {
+		if err = ddb.DeleteBranch(ctx, br, nil); err != nil {
+			return err
+		}
+	}
 	err = ddb.NewBranchAtCommit(ctx, br, init, nil)
 	if err != nil {
 		return err
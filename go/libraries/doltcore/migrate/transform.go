@@ -35,6 +35,7 @@ import (
 	"github.com/dolthub/dolt/go/store/prolly"
 	"github.com/dolthub/dolt/go/store/prolly/tree"
 	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
 	"github.com/dolthub/dolt/go/store/val"
 )
 
@@ -42,7 +43,7 @@ var (
 	flushRef = ref.NewInternalRef("migration-flush")
 )
 
-func migrateWorkingSet(ctx context.Context, menv Environment, brRef ref.BranchRef, wsRef ref.WorkingSetRef, old, new *doltdb.DoltDB) error {
+func migrateWorkingSet(ctx context.Context, menv Environment, brRef ref.BranchRef, wsRef ref.WorkingSetRef, old, new *doltdb.DoltDB, prog *progress) error {
 	oldHead, err := old.ResolveCommitRef(ctx, brRef)
 	if err != nil {
 		return err
@@ -71,12 +72,13 @@ func migrateWorkingSet(ctx context.Context, menv Environment, brRef ref.BranchRe
 		return err
 	}
 
-	wr, err := migrateRoot(ctx, menv, oldHeadRoot, oldWs.WorkingRoot(), newHeadRoot)
+	prog.Log(ctx, "migrating working set of branch %s", brRef.GetPath())
+	wr, err := migrateRoot(ctx, menv, oldHeadRoot, oldWs.WorkingRoot(), newHeadRoot, prog)
 	if err != nil {
 		return err
 	}
 
-	sr, err := migrateRoot(ctx, menv, oldHeadRoot, oldWs.StagedRoot(), newHeadRoot)
+	sr, err := migrateRoot(ctx, menv, oldHeadRoot, oldWs.StagedRoot(), newHeadRoot, prog)
 	if err != nil {
 		return err
 	}
@@ -93,7 +95,17 @@ func migrateWorkingSet(ctx context.Context, menv Environment, brRef ref.BranchRe
 
 	newWs := doltdb.EmptyWorkingSet(wsRef).WithWorkingRoot(wr).WithStagedRoot(sr)
 
-	return new.UpdateWorkingSet(ctx, wsRef, newWs, hash.Hash{}, oldWs.Meta(), nil)
+	// a resumed migration may have migrated the working set already
+	var prev hash.Hash
+	if ws, err := new.ResolveWorkingSet(ctx, wsRef); err == nil {
+		if prev, err = ws.HashOf(); err != nil {
+			return err
+		}
+	} else if err != doltdb.ErrWorkingSetNotFound {
+		return err
+	}
+
+	return new.UpdateWorkingSet(ctx, wsRef, newWs, prev, oldWs.Meta(), nil)
 }
 
 func migrateCommit(ctx context.Context, menv Environment, oldCm *doltdb.Commit, new *doltdb.DoltDB, prog *progress) error {
@@ -114,7 +126,7 @@ func migrateCommit(ctx context.Context, menv Environment, oldCm *doltdb.Commit,
 	}
 
 	hs := oldHash.String()
-	prog.Log(ctx, "migrating commit %s", hs)
+	prog.Log(ctx, "migrating commit %s (%d commits migrated)", hs, prog.migrated)
 
 	oldRoot, err := oldCm.GetRootValue(ctx)
 	if err != nil {
@@ -164,7 +176,7 @@ func migrateCommit(ctx context.Context, menv Environment, oldCm *doltdb.Commit,
 		return err
 	}
 
-	mRoot, err := migrateRoot(ctx, menv, oldParentRoot, oldRoot, newParentRoot)
+	mRoot, err := migrateRoot(ctx, menv, oldParentRoot, oldRoot, newParentRoot, prog)
 	if err != nil {
 		return err
 	}
@@ -187,14 +199,10 @@ func migrateCommit(ctx context.Context, menv Environment, oldCm *doltdb.Commit,
 		return err
 	}
 
-	// update progress
 	newHash, err := migratedCm.HashOf()
 	if err != nil {
 		return err
 	}
-	if err = prog.Put(ctx, oldHash, newHash); err != nil {
-		return err
-	}
 
 	// flush ChunkStore
 	if err = new.SetHead(ctx, flushRef, newHash); err != nil {
@@ -205,6 +213,11 @@ func migrateCommit(ctx context.Context, menv Environment, oldCm *doltdb.Commit,
 		return err
 	}
 
+	// update progress, now that the migrated commit is persisted
+	if err = prog.Put(ctx, oldHash, newHash); err != nil {
+		return err
+	}
+
 	// validate root after we flush the ChunkStore to facilitate
 	// investigating failed migrations
 	if err = validateRootValue(ctx, oldParentRoot, oldRoot, mRoot); err != nil {
@@ -281,7 +294,7 @@ func migrateCommitOptions(ctx context.Context, oldCm *doltdb.Commit, prog *progr
 	}, nil
 }
 
-func migrateRoot(ctx context.Context, menv Environment, oldParent, oldRoot, newParent doltdb.RootValue) (doltdb.RootValue, error) {
+func migrateRoot(ctx context.Context, menv Environment, oldParent, oldRoot, newParent doltdb.RootValue, prog *progress) (doltdb.RootValue, error) {
 	migrated := newParent
 
 	fkc, err := oldRoot.GetForeignKeyCollection(ctx)
@@ -359,6 +372,9 @@ func migrateRoot(ctx context.Context, menv Environment, oldParent, oldRoot, newP
 		if err != nil {
 			return true, err
 		}
+		if err = logMigratedTable(ctx, prog, name.Name, oldParentTbl, oldTbl, mtbl); err != nil {
+			return true, err
+		}
 
 		migrated, err = migrated.PutTable(ctx, name, mtbl)
 		if err != nil {
@@ -373,6 +389,32 @@ func migrateRoot(ctx context.Context, menv Environment, oldParent, oldRoot, newP
 	return migrated, nil
 }
 
+// logMigratedTable logs the migration of |oldTbl| to |newTbl|, if it changed from |oldParentTbl|.
+func logMigratedTable(ctx context.Context, prog *progress, name string, oldParentTbl, oldTbl, newTbl *doltdb.Table) error {
+	oldParentHash, err := oldParentTbl.HashOf()
+	if err != nil {
+		return err
+	}
+	oldHash, err := oldTbl.HashOf()
+	if err != nil {
+		return err
+	}
+	if oldHash.Equal(oldParentHash) {
+		return nil
+	}
+
+	idx, err := newTbl.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := idx.Count()
+	if err != nil {
+		return err
+	}
+	prog.Log(ctx, "  migrated table %s (%d rows)", name, rows)
+	return nil
+}
+
 // renames also get returned here
 func getRemovedTableNames(ctx context.Context, prev, curr doltdb.RootValue) ([]string, error) {
 	prevNames, err := prev.GetTableNames(ctx, doltdb.DefaultSchemaName)
@@ -694,6 +736,19 @@ func writeProllyMap(ctx context.Context, prev prolly.Map, writer <-chan val.Tupl
 		ok   bool
 	)
 
+	if cnt, err := prev.Count(); err != nil {
+		return prolly.Map{}, err
+	} else if cnt == 0 {
+		// every change is an addition, so the map can be bulk loaded
+		iter := &tupleChanIter{ch: writer}
+		kd, vd := prev.Descriptors()
+		m, err = prolly.BulkLoadMap(ctx, prev.NodeStore(), kd, vd, iter, tempfiles.MovableTempFileProvider)
+		if err == nil {
+			err = ctx.Err()
+		}
+		return m, err
+	}
+
 	mut := prev.Mutate()
 	for {
 		select {
@@ -717,3 +772,35 @@ func writeProllyMap(ctx context.Context, prev prolly.Map, writer <-chan val.Tupl
 		}
 	}
 }
+
+// tupleChanIter is a prolly.TupleIter over the key-value pairs sent on |ch|.
+// Removals, which are sent with a nil value, are skipped.
+type tupleChanIter struct {
+	ch <-chan val.Tuple
+}
+
+var _ prolly.TupleIter = (*tupleChanIter)(nil)
+
+func (it *tupleChanIter) Next(ctx context.Context) (k, v val.Tuple) {
+	for {
+		var ok bool
+		select {
+		case k, ok = <-it.ch:
+			if !ok {
+				return nil, nil
+			}
+		case <-ctx.Done():
+			return nil, nil
+		}
+
+		select {
+		case v, ok = <-it.ch:
+			assertTrue(ok)
+		case <-ctx.Done():
+			return nil, nil
+		}
+		if v != nil {
+			return k, v
+		}
+	}
+}
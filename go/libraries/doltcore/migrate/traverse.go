@@ -25,12 +25,13 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 )
 
-// TraverseDAG traverses |old|, migrating values to |new|.
+// TraverseDAG traverses |old|, migrating values to |new|. Commits already migrated by an earlier, interrupted
+// migration in |menv| aren't migrated again.
 func TraverseDAG(ctx context.Context, menv Environment, old, new *doltdb.DoltDB) (err error) {
-	var heads []ref.DoltRef
+	var branches, others []ref.DoltRef
 	var prog *progress
 
-	heads, err = old.GetHeadRefs(ctx)
+	branches, others, err = selectRefs(ctx, menv, old)
 	if err != nil {
 		return err
 	}
@@ -38,18 +39,51 @@ func TraverseDAG(ctx context.Context, menv Environment, old, new *doltdb.DoltDB)
 	datasdb := doltdb.HackDatasDatabaseFromDoltDB(new)
 	cs := datas.ChunkStoreFromDatabase(datasdb)
 
-	prog, err = newProgress(ctx, cs)
+	var j *journal
+	var resumed map[hash.Hash]hash.Hash
+	if menv.Migration != nil {
+		if j, resumed, err = openJournal(menv.Migration.FS); err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := j.Close(); err == nil {
+				err = cerr
+			}
+		}()
+	}
+
+	prog, err = newProgress(ctx, cs, j, resumed)
 	if err != nil {
 		return err
 	}
+	if len(resumed) > 0 {
+		prog.Log(ctx, "resuming migration, %d commits were already migrated", len(resumed))
+	}
 
-	for i := range heads {
-		if err = traverseRefHistory(ctx, menv, heads[i], old, new, prog); err != nil {
+	for i := range branches {
+		if err = traverseRefHistory(ctx, menv, branches[i], old, new, prog); err != nil {
+			return err
+		}
+	}
+	for i := range others {
+		// when only some branches are migrated, tags and remote refs are only kept if they're on one of them
+		ok, err := isRefMigrated(ctx, others[i], old, prog)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			prog.Log(ctx, "skipping %s, which isn't on a migrated branch", others[i].String())
+			continue
+		}
+		if err = traverseRefHistory(ctx, menv, others[i], old, new, prog); err != nil {
 			return err
 		}
 	}
 
-	if err = validateBranchMapping(ctx, old, new); err != nil {
+	if err = validateBranchMapping(ctx, branches, new); err != nil {
+		return err
+	}
+	if err = verifyBranches(ctx, branches, old, new, prog); err != nil {
 		return err
 	}
 
@@ -71,6 +105,67 @@ func TraverseDAG(ctx context.Context, menv Environment, old, new *doltdb.DoltDB)
 	return
 }
 
+// selectRefs returns the branches of |old| to migrate, and its other refs. When |menv| doesn't name the branches to
+// migrate, all the refs of |old| are returned as branches to migrate.
+func selectRefs(ctx context.Context, menv Environment, old *doltdb.DoltDB) (branches, others []ref.DoltRef, err error) {
+	heads, err := old.GetHeadRefs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(menv.Branches) == 0 {
+		return heads, nil, nil
+	}
+
+	selected := make(map[string]bool, len(menv.Branches))
+	for _, name := range menv.Branches {
+		_, ok, err := old.HasBranch(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("branch not found: %s", name)
+		}
+		selected[name] = true
+	}
+
+	for _, r := range heads {
+		if r.GetType() == ref.BranchRefType {
+			if selected[r.GetPath()] {
+				branches = append(branches, r)
+			}
+			continue
+		}
+		others = append(others, r)
+	}
+	return branches, others, nil
+}
+
+// isRefMigrated returns whether the commit |r| points to has been migrated.
+func isRefMigrated(ctx context.Context, r ref.DoltRef, old *doltdb.DoltDB, prog *progress) (bool, error) {
+	var cm *doltdb.Commit
+	switch r.GetType() {
+	case ref.TagRefType:
+		t, err := old.ResolveTag(ctx, r.(ref.TagRef))
+		if err != nil {
+			return false, err
+		}
+		cm = t.Commit
+	case ref.RemoteRefType:
+		var err error
+		if cm, err = old.ResolveCommitRef(ctx, r); err != nil {
+			return false, err
+		}
+	default:
+		return true, nil
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return prog.Has(ctx, h)
+}
+
 func traverseRefHistory(ctx context.Context, menv Environment, r ref.DoltRef, old, new *doltdb.DoltDB, prog *progress) error {
 	switch r.GetType() {
 	case ref.BranchRefType:
@@ -81,7 +176,7 @@ func traverseRefHistory(ctx context.Context, menv Environment, r ref.DoltRef, ol
 		if err != nil {
 			return err
 		}
-		return migrateWorkingSet(ctx, menv, r.(ref.BranchRef), wsRef, old, new)
+		return migrateWorkingSet(ctx, menv, r.(ref.BranchRef), wsRef, old, new, prog)
 
 	case ref.TagRefType:
 		return traverseTagHistory(ctx, menv, r.(ref.TagRef), old, new, prog)
@@ -145,6 +240,10 @@ func traverseTagHistory(ctx context.Context, menv Environment, r ref.TagRef, old
 		return doltdb.ErrGhostCommitEncountered
 	}
 
+	// a resumed migration may have migrated the tag already
+	if ok, err = new.HasRef(ctx, r); err != nil || ok {
+		return err
+	}
 	return new.NewTagAtCommit(ctx, r, cm, t.Meta)
 }
 
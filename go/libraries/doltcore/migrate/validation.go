@@ -29,25 +29,23 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
-func validateBranchMapping(ctx context.Context, old, new *doltdb.DoltDB) error {
-	branches, err := old.GetBranches(ctx)
-	if err != nil {
-		return err
-	}
-
-	var ok bool
-	for _, bref := range branches {
-		_, ok, err = new.HasBranch(ctx, bref.GetPath())
+func validateBranchMapping(ctx context.Context, refs []ref.DoltRef, new *doltdb.DoltDB) error {
+	for _, r := range refs {
+		if r.GetType() != ref.BranchRefType {
+			continue
+		}
+		_, ok, err := new.HasBranch(ctx, r.GetPath())
 		if err != nil {
 			return err
 		}
 		if !ok {
-			return fmt.Errorf("failed to map branch %s", bref.GetPath())
+			return fmt.Errorf("failed to map branch %s", r.GetPath())
 		}
 	}
 	return nil
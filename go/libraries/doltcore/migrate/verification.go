@@ -0,0 +1,188 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// tableChecksum summarizes the rows of a table. The checksum is a sum of hashes of each row's values, so it doesn't
+// depend on the order rows are stored in, which can differ between storage formats.
+type tableChecksum struct {
+	rows     uint64
+	checksum uint64
+}
+
+// verifyBranches checks that every table at the head of each branch in |refs| has the same rows in |new| as in |old|.
+// Where validateRootValue compares the tables changed by each commit as it's migrated, this compares whole tables once
+// the migration is done, by their number of rows and checksums.
+func verifyBranches(ctx context.Context, refs []ref.DoltRef, old, new *doltdb.DoltDB, prog *progress) error {
+	for _, r := range refs {
+		if r.GetType() != ref.BranchRefType {
+			continue
+		}
+
+		oldCm, err := old.ResolveCommitRef(ctx, r)
+		if err != nil {
+			return err
+		}
+		oldRoot, err := oldCm.GetRootValue(ctx)
+		if err != nil {
+			return err
+		}
+		newCm, err := new.ResolveCommitRef(ctx, r)
+		if err != nil {
+			return err
+		}
+		newRoot, err := newCm.GetRootValue(ctx)
+		if err != nil {
+			return err
+		}
+
+		names, err := oldRoot.GetTableNames(ctx, doltdb.DefaultSchemaName)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err = verifyTable(ctx, r.GetPath(), name, oldRoot, newRoot, prog); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func verifyTable(ctx context.Context, branch, name string, oldRoot, newRoot doltdb.RootValue, prog *progress) error {
+	oldTbl, ok, err := oldRoot.GetTable(ctx, doltdb.TableName{Name: name})
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("expected to find table %s on branch %s", name, branch)
+	}
+	newTbl, ok, err := newRoot.GetTable(ctx, doltdb.TableName{Name: name})
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("verification failed: table %s is missing from branch %s after migrating", name, branch)
+	}
+
+	var before, after tableChecksum
+	eg, ectx := errgroup.WithContext(ctx)
+	eg.Go(func() (err error) {
+		before, err = checksumTable(ectx, name, oldTbl)
+		return err
+	})
+	eg.Go(func() (err error) {
+		after, err = checksumTable(ectx, name, newTbl)
+		return err
+	})
+	if err = eg.Wait(); err != nil {
+		return err
+	}
+
+	if before != after {
+		return fmt.Errorf("verification failed for table %s on branch %s: "+
+			"%d rows with checksum %016x before migrating, %d rows with checksum %016x after",
+			name, branch, before.rows, before.checksum, after.rows, after.checksum)
+	}
+	prog.Log(ctx, "verified table %s on branch %s (%d rows, checksum %016x)", name, branch, after.rows, after.checksum)
+	return nil
+}
+
+func checksumTable(ctx context.Context, name string, tbl *doltdb.Table) (tableChecksum, error) {
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return tableChecksum{}, err
+	}
+	c, err := idx.Count()
+	if err != nil || c == 0 {
+		return tableChecksum{}, err
+	}
+
+	sctx := sql.NewContext(ctx)
+	sch, iter, err := sqle.DoltTablePartitionToRowIter(sctx, name, tbl, 0, c)
+	if err != nil {
+		return tableChecksum{}, err
+	}
+	defer iter.Close(sctx)
+
+	var sum tableChecksum
+	var buf bytes.Buffer
+	for {
+		r, err := iter.Next(sctx)
+		if err == io.EOF {
+			return sum, nil
+		} else if err != nil {
+			return tableChecksum{}, err
+		}
+
+		buf.Reset()
+		if err = writeRowValues(&buf, r, sch); err != nil {
+			return tableChecksum{}, err
+		}
+		h := hash.Of(buf.Bytes())
+		sum.rows++
+		sum.checksum += binary.BigEndian.Uint64(h[:8])
+	}
+}
+
+// writeRowValues writes the values of |r| to |buf| as they're displayed by SQL, making the same allowances for changes
+// between formats that equalRows does.
+func writeRowValues(buf *bytes.Buffer, r sql.Row, sch sql.Schema) error {
+	for i, v := range r {
+		var s string
+		switch tv := v.(type) {
+		case nil:
+			buf.WriteByte(0)
+			continue
+		case string:
+			s = strings.TrimRightFunc(tv, unicode.IsSpace)
+		case time.Time:
+			secs, _, err := gmstypes.Int64.Convert(tv)
+			if err != nil {
+				return err
+			}
+			s = strconv.FormatInt(secs.(int64), 10)
+		default:
+			var err error
+			if s, err = sqlutil.SqlColToStr(sch[i].Type, v); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(1)
+		buf.WriteString(strconv.Itoa(len(s)))
+		buf.WriteByte(':')
+		buf.WriteString(s)
+	}
+	return nil
+}
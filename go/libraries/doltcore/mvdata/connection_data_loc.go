@@ -0,0 +1,66 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/sqlconn"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// ConnectionDataLocation is the result of a query run over a connection to a MySQL or Postgres server, which can be
+// imported from.
+type ConnectionDataLocation struct {
+	// URL is the mysql:// or postgres:// URL of the server
+	URL string
+
+	// Query is the query whose rows are imported
+	Query string
+}
+
+// String returns a string representation of the data location, without the password of its URL.
+func (dl ConnectionDataLocation) String() string {
+	if u, err := url.Parse(dl.URL); err == nil {
+		return u.Redacted()
+	}
+	return "connection"
+}
+
+// Exists returns true if the DataLocation already exists
+func (dl ConnectionDataLocation) Exists(ctx context.Context, root doltdb.RootValue, fs filesys.ReadableFS) (bool, error) {
+	return true, nil
+}
+
+// NewReader creates a TableReadCloser for the DataLocation, which runs its query
+func (dl ConnectionDataLocation) NewReader(ctx context.Context, dEnv *env.DoltEnv, opts interface{}) (rdCl table.SqlRowReader, sorted bool, err error) {
+	connOpts, _ := opts.(ConnectionOptions)
+	rd, err := sqlconn.OpenConnReader(ctx, dl.URL, dl.Query, connOpts.PrimaryKeys, connOpts.ColTypes)
+	return rd, false, err
+}
+
+// NewCreatingWriter will create a TableWriteCloser for a DataLocation that will create a new table, or overwrite
+// an existing table.
+func (dl ConnectionDataLocation) NewCreatingWriter(ctx context.Context, mvOpts DataMoverOptions, root doltdb.RootValue, outSch schema.Schema, opts editor.Options, wr io.WriteCloser) (table.SqlRowWriter, error) {
+	return nil, errors.New("a connection can't be exported to")
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
@@ -48,12 +49,21 @@ type ParquetOptions struct {
 	SchFile   string
 }
 
+type ConnectionOptions struct {
+	PrimaryKeys []string
+	ColTypes    map[string]typeinfo.TypeInfo
+}
+
 type MoverOptions struct {
 	ContinueOnErr  bool
 	Force          bool
 	TableToWriteTo string
 	Operation      TableImportOp
 	DisableFks     bool
+	// Sync deletes the rows of the table whose keys aren't imported by an UpdateOp
+	Sync bool
+	// CommitMessage commits the import with the message given, if it's set
+	CommitMessage string
 }
 
 type DataMoverOptions interface {
@@ -124,12 +134,14 @@ func SchAndTableNameFromFile(ctx context.Context, path string, dEnv *env.DoltEnv
 	}
 }
 
-func InferSchema(ctx context.Context, root doltdb.RootValue, rd table.ReadCloser, tableName string, pks []string, args actions.InferenceArgs) (schema.Schema, error) {
+// InferSchema infers the schema of |tableName| from the rows of |rd|, returning a report of how the type of each
+// column was inferred.
+func InferSchema(ctx context.Context, root doltdb.RootValue, rd table.ReadCloser, tableName string, pks []string, args actions.InferenceArgs) (schema.Schema, *actions.InferenceReport, error) {
 	var err error
 
-	infCols, err := actions.InferColumnTypesFromTableReader(ctx, rd, args)
+	infCols, report, err := actions.InferColumnTypesWithReport(ctx, rd, args)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	pkSet := set.NewStrSet(pks)
@@ -154,21 +166,25 @@ func InferSchema(ctx context.Context, root doltdb.RootValue, rd table.ReadCloser
 	for _, pk := range pks {
 		col, ok := newCols.GetByName(pk)
 		if !col.IsPartOfPK || !ok {
-			return nil, ErrProvidedPkNotFound
+			return nil, nil, ErrProvidedPkNotFound
 		}
 	}
 
 	newCols, err = doltdb.GenerateTagsForNewColColl(ctx, root, tableName, newCols)
 	if err != nil {
-		return nil, errhand.BuildDError("failed to generate new schema").AddCause(err).Build()
+		return nil, nil, errhand.BuildDError("failed to generate new schema").AddCause(err).Build()
 	}
 
 	err = schema.ValidateForInsert(newCols)
 	if err != nil {
-		return nil, errhand.BuildDError("invalid schema").AddCause(err).Build()
+		return nil, nil, errhand.BuildDError("invalid schema").AddCause(err).Build()
 	}
 
-	return schema.SchemaFromCols(newCols)
+	sch, err := schema.SchemaFromCols(newCols)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sch, report, nil
 }
 
 type TableImportOp string
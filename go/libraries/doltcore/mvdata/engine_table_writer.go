@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"sync/atomic"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -27,10 +28,13 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/planbuilder"
 	"github.com/dolthub/go-mysql-server/sql/rowexec"
 	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
 	"github.com/dolthub/dolt/go/store/types"
@@ -59,6 +63,11 @@ type SqlEngineTableWriter struct {
 	importOption       TableImportOp
 	tableSchema        sql.PrimaryKeySchema
 	rowOperationSchema sql.PrimaryKeySchema
+	doltTableSchema    schema.Schema
+
+	sync          bool
+	importedKeys  map[string]struct{}
+	commitMessage string
 }
 
 func NewSqlEngineTableWriter(ctx context.Context, dEnv *env.DoltEnv, createTableSchema, rowOperationSchema schema.Schema, options *MoverOptions, statsCB noms.StatsCB) (*SqlEngineTableWriter, error) {
@@ -108,6 +117,10 @@ func NewSqlEngineTableWriter(ctx context.Context, dEnv *env.DoltEnv, createTable
 		return nil, err
 	}
 
+	if options.Sync && len(doltCreateTableSchema.PkOrdinals) == 0 {
+		return nil, fmt.Errorf("table %s must have a primary key to sync its rows with an import", options.TableToWriteTo)
+	}
+
 	return &SqlEngineTableWriter{
 		se:         se,
 		sqlCtx:     sqlCtx,
@@ -123,6 +136,11 @@ func NewSqlEngineTableWriter(ctx context.Context, dEnv *env.DoltEnv, createTable
 		importOption:       options.Operation,
 		tableSchema:        doltCreateTableSchema,
 		rowOperationSchema: doltRowOperationSchema,
+		doltTableSchema:    createTableSchema,
+
+		sync:          options.Sync,
+		importedKeys:  make(map[string]struct{}),
+		commitMessage: options.CommitMessage,
 	}, nil
 }
 
@@ -149,15 +167,16 @@ func (s *SqlEngineTableWriter) WriteRows(ctx context.Context, inputChannel chan
 		return err
 	}
 
-	updateStats := func(row sql.Row) {
+	updateStats := func(row sql.Row) error {
 		if row == nil {
-			return
+			return nil
 		}
 
+		newRow := row
 		// If the length of the row does not match the schema then we have an update operation.
 		if len(row) != len(s.tableSchema.Schema) {
 			oldRow := row[:len(row)/2]
-			newRow := row[len(row)/2:]
+			newRow = row[len(row)/2:]
 
 			if ok, err := oldRow.Equals(newRow, s.tableSchema.Schema); err == nil {
 				if ok {
@@ -169,6 +188,15 @@ func (s *SqlEngineTableWriter) WriteRows(ctx context.Context, inputChannel chan
 		} else {
 			s.stats.Additions++
 		}
+
+		if s.sync {
+			key, err := s.rowKey(newRow)
+			if err != nil {
+				return err
+			}
+			s.importedKeys[key] = struct{}{}
+		}
+		return nil
 	}
 
 	insertOrUpdateOperation, err := s.getInsertNode(inputChannel, false)
@@ -202,7 +230,9 @@ func (s *SqlEngineTableWriter) WriteRows(ctx context.Context, inputChannel chan
 		// All other errors are handled by the errorHandler
 		if err == nil {
 			_ = atomic.AddInt32(&s.statOps, 1)
-			updateStats(row)
+			if err = updateStats(row); err != nil {
+				return err
+			}
 		} else if err == io.EOF {
 			atomic.LoadInt32(&s.statOps)
 			atomic.StoreInt32(&s.statOps, 0)
@@ -229,7 +259,96 @@ func (s *SqlEngineTableWriter) WriteRows(ctx context.Context, inputChannel chan
 }
 
 func (s *SqlEngineTableWriter) Commit(ctx context.Context) error {
+	if s.sync {
+		if err := s.deleteUnimportedRows(); err != nil {
+			return err
+		}
+	}
+
 	_, _, _, err := s.se.Query(s.sqlCtx, "COMMIT")
+	if err != nil || s.commitMessage == "" {
+		return err
+	}
+
+	if err = s.exec("CALL DOLT_ADD(?)", s.tableName); err != nil {
+		return err
+	}
+	return s.exec("CALL DOLT_COMMIT('-m', ?)", s.commitMessage)
+}
+
+// deleteUnimportedRows deletes the rows of the table whose keys weren't imported, so the table holds exactly the rows
+// of the import. The rows are deleted through the engine, so that triggers and foreign key actions run.
+func (s *SqlEngineTableWriter) deleteUnimportedRows() error {
+	_, iter, _, err := s.se.Query(s.sqlCtx, fmt.Sprintf("SELECT * FROM %s", sql.QuoteIdentifier(s.tableName)))
+	if err != nil {
+		return err
+	}
+	var unimported []sql.Row
+	for {
+		row, err := iter.Next(s.sqlCtx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			iter.Close(s.sqlCtx)
+			return err
+		}
+		key, err := s.rowKey(row)
+		if err != nil {
+			iter.Close(s.sqlCtx)
+			return err
+		}
+		if _, ok := s.importedKeys[key]; !ok {
+			unimported = append(unimported, row)
+		}
+	}
+	if err = iter.Close(s.sqlCtx); err != nil {
+		return err
+	}
+
+	for _, row := range unimported {
+		del, err := sqlfmt.SqlRowAsDeleteStmt(row, s.tableName, s.doltTableSchema, 0)
+		if err != nil {
+			return err
+		}
+		if err = s.exec(del); err != nil {
+			return err
+		}
+		s.stats.Deletions++
+	}
+	if s.statsCB != nil {
+		s.statsCB(s.stats)
+	}
+	return nil
+}
+
+// rowKey returns a string which identifies the primary key of |row|, a row of the table.
+func (s *SqlEngineTableWriter) rowKey(row sql.Row) (string, error) {
+	var b strings.Builder
+	for _, i := range s.tableSchema.PkOrdinals {
+		str, err := sqlutil.SqlColToStr(s.tableSchema.Schema[i].Type, row[i])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(str)
+		b.WriteByte(0)
+	}
+	return b.String(), nil
+}
+
+// exec runs |query|, with |params| interpolated, to completion.
+func (s *SqlEngineTableWriter) exec(query string, params ...interface{}) error {
+	if len(params) > 0 {
+		var err error
+		query, err = dbr.InterpolateForDialect(query, params, dialect.MySQL)
+		if err != nil {
+			return err
+		}
+	}
+	_, iter, _, err := s.se.Query(s.sqlCtx, query)
+	if err != nil {
+		return err
+	}
+	_, err = sql.RowIterToRows(s.sqlCtx, iter)
 	return err
 }
 
@@ -0,0 +1,91 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+)
+
+// RootTableReader reads the rows of a table in a root value directly, without a SQL engine.
+type RootTableReader struct {
+	sch  schema.Schema
+	iter table.RowIter
+	rows atomic.Uint64
+}
+
+var _ table.SqlRowReader = (*RootTableReader)(nil)
+
+// NewRootTableReader returns a reader of the rows of |tableName| in |root|. Unlike the readers created by
+// NewSqlEngineReader, which each read the working set as of when they're created, all the readers created from the
+// same root read the same version of the database, so they can be used to read several tables from one snapshot.
+func NewRootTableReader(ctx context.Context, root doltdb.RootValue, tableName string) (*RootTableReader, error) {
+	tbl, ok, err := root.GetTable(ctx, doltdb.TableName{Name: tableName})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("table %s not found", tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := table.NewTableIterator(ctx, sch, idx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RootTableReader{sch: sch, iter: iter}, nil
+}
+
+func (r *RootTableReader) GetSchema() schema.Schema {
+	return r.sch
+}
+
+func (r *RootTableReader) ReadRow(ctx context.Context) (row.Row, error) {
+	panic("deprecated")
+}
+
+func (r *RootTableReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	next, err := r.iter.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.rows.Add(1)
+	return next, nil
+}
+
+// RowsRead returns the number of rows read so far.
+func (r *RootTableReader) RowsRead() uint64 {
+	return r.rows.Load()
+}
+
+func (r *RootTableReader) Close(ctx context.Context) error {
+	return r.iter.Close(ctx)
+}
@@ -0,0 +1,687 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	mechMySQL41 = "MYSQL41"
+	mechPlain   = "PLAIN"
+)
+
+// Types of Mysqlx.Resultset.ColumnMetaData columns.
+const (
+	fieldSint   = 1
+	fieldUint   = 2
+	fieldDouble = 5
+	fieldFloat  = 6
+	fieldBytes  = 7
+)
+
+// Content types of Mysqlx.Resultset.ColumnMetaData BYTES columns.
+const (
+	contentGeometry = 1
+	contentJSON     = 2
+)
+
+// Flags of Mysqlx.Resultset.ColumnMetaData columns.
+const (
+	flagNotNull    = 0x0010
+	flagPrimaryKey = 0x0020
+)
+
+// binaryCollation is the collation of BYTES columns which don't hold text.
+const binaryCollation = 63
+
+// Parameters of Mysqlx.Notice.SessionStateChanged notices.
+const (
+	stateGeneratedInsertID = 3
+	stateRowsAffected      = 4
+)
+
+const (
+	noticeSessionStateChanged = 3
+	noticeScopeLocal          = 2
+)
+
+// expectNoError is the Mysqlx.Expect.Open.Condition which fails the rest of an expectation block after an error.
+const expectNoError = 1
+
+// expectOpUnset is the Mysqlx.Expect.Open.Condition.ConditionOperation which unsets a condition.
+const expectOpUnset = 1
+
+// expectBlock is an open Mysqlx.Expect block.
+type expectBlock struct {
+	noError bool
+	failed  bool
+}
+
+// conn is a client connection. Messages are handled one at a time, and every response is buffered until the
+// message is handled.
+type conn struct {
+	srv *Server
+	nc  net.Conn
+	r   *bufio.Reader
+	w   *bufio.Writer
+	lgr *logrus.Entry
+	// writeErr is the first error writing a response, which closes the connection
+	writeErr error
+
+	// salt is sent to the client while MYSQL41 authentication is in progress
+	salt []byte
+	user string
+	// sess is nil until the client authenticates
+	sess sql.Session
+
+	expects []expectBlock
+}
+
+func (c *conn) send(typ byte, payload []byte) {
+	if c.writeErr == nil {
+		c.writeErr = writeMessage(c.w, typ, payload)
+	}
+}
+
+func (c *conn) sendError(err error) {
+	c.send(serverError, toError(err).encode())
+}
+
+func (c *conn) expectFailed() bool {
+	return len(c.expects) > 0 && c.expects[len(c.expects)-1].failed
+}
+
+// dispatch handles a message of type |typ|. Returns true if the connection should be closed.
+func (c *conn) dispatch(ctx context.Context, typ byte, msg []byte) (bool, error) {
+	if c.expectFailed() && typ != clientExpectClose {
+		if typ == clientExpectOpen {
+			// the nested block still has to be closed by its own Expect.Close
+			c.expects = append(c.expects, expectBlock{noError: true, failed: true})
+		}
+		c.sendError(&Error{Code: erXExpectFailed, State: "HY000", Msg: "Expectation failed: no_error"})
+		return false, c.writeErr
+	}
+
+	var err error
+	switch typ {
+	case clientConClose:
+		c.send(serverOk, nil)
+		return true, c.writeErr
+	case clientConCapabilitiesGet:
+		err = c.capabilitiesGet()
+	case clientConCapabilitiesSet:
+		err = c.capabilitiesSet(msg)
+	case clientSessAuthenticateStart:
+		err = c.authenticateStart(ctx, msg)
+	case clientSessAuthenticateContinue:
+		err = c.authenticateContinue(ctx, msg)
+	case clientExpectOpen:
+		err = c.expectOpen(msg)
+	case clientExpectClose:
+		err = c.expectClose()
+	default:
+		if c.sess == nil {
+			err = newBadMessageError("Invalid message %d before authentication", typ)
+		} else {
+			err = c.dispatchSession(ctx, typ, msg)
+		}
+	}
+
+	if err != nil {
+		c.sendError(err)
+		if n := len(c.expects); n > 0 && c.expects[n-1].noError {
+			c.expects[n-1].failed = true
+		}
+	}
+	return false, c.writeErr
+}
+
+// dispatchSession handles a message of type |typ| from an authenticated client.
+func (c *conn) dispatchSession(ctx context.Context, typ byte, msg []byte) error {
+	var query string
+	var err error
+	switch typ {
+	case clientSessReset:
+		return c.sessionReset(ctx, msg)
+	case clientSessClose:
+		c.closeSession()
+		c.send(serverOk, nil)
+		return nil
+	case clientSqlStmtExecute:
+		return c.stmtExecute(ctx, msg)
+	case clientCrudFind:
+		query, err = translateFind(msg)
+	case clientCrudInsert:
+		query, err = translateInsert(msg)
+	case clientCrudUpdate:
+		query, err = translateUpdate(msg)
+	case clientCrudDelete:
+		query, err = translateDelete(msg)
+	default:
+		return newBadMessageError("Unexpected message received: %d", typ)
+	}
+	if err != nil {
+		return err
+	}
+	return c.execute(ctx, query)
+}
+
+func (c *conn) capabilitiesGet() error {
+	mechs := [][]byte{encodeAnyScalar(encodeStringScalar(mechMySQL41))}
+	if c.srv.args.AllowCleartextPasswords {
+		mechs = append(mechs, encodeAnyScalar(encodeStringScalar(mechPlain)))
+	}
+
+	var b []byte
+	b = appendCapability(b, "authentication.mechanisms", encodeAnyArray(mechs...))
+	b = appendCapability(b, "doc.formats", encodeAnyScalar(encodeStringScalar("text")))
+	b = appendCapability(b, "node_type", encodeAnyScalar(encodeStringScalar("mysql")))
+	b = appendCapability(b, "client.pwd_expire_ok", encodeAnyScalar(encodeBoolScalar(false)))
+	c.send(serverConnCapabilities, b)
+	return nil
+}
+
+func appendCapability(b []byte, name string, value []byte) []byte {
+	capability := appendStringField(nil, 1, name)
+	capability = appendBytesField(capability, 2, value)
+	return appendBytesField(b, 1, capability)
+}
+
+// capabilitiesSet accepts the capabilities which only inform the server about the client. TLS and compression
+// aren't supported, so clients fall back to unencrypted, uncompressed connections unless they require them.
+func (c *conn) capabilitiesSet(msg []byte) error {
+	var names []string
+	err := decodeFields(msg, func(f field) error {
+		if f.num != 1 {
+			return nil
+		}
+		return decodeFields(f.b, func(f field) error {
+			if f.num != 1 {
+				return nil
+			}
+			return decodeFields(f.b, func(f field) error {
+				if f.num == 1 {
+					names = append(names, string(f.b))
+				}
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		switch name {
+		case "client.pwd_expire_ok", "client.interactive", "session_connect_attrs":
+		case "tls", "compression":
+			return &Error{Code: erXCapabilitiesPrepFailed, State: "HY000", Msg: fmt.Sprintf("Capability prepare failed for '%s'", name)}
+		default:
+			return &Error{Code: erXCapabilityNotFound, State: "HY000", Msg: fmt.Sprintf("Capability '%s' doesn't exist", name)}
+		}
+	}
+	c.send(serverOk, nil)
+	return nil
+}
+
+func (c *conn) authenticateStart(ctx context.Context, msg []byte) error {
+	var mech string
+	var authData []byte
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			mech = string(f.b)
+		case 2:
+			authData = f.b
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case mech == mechMySQL41:
+		if c.salt, err = newSalt(); err != nil {
+			return err
+		}
+		c.send(serverSessAuthenticateContinue, appendBytesField(nil, 1, c.salt))
+		return nil
+	case mech == mechPlain && c.srv.args.AllowCleartextPasswords:
+		schema, user, password, err := splitAuthData(authData)
+		if err != nil {
+			return err
+		}
+		if err = c.srv.args.Engine.ValidatePassword(user, password, c.nc.RemoteAddr()); err != nil {
+			return c.accessDenied(user, password != "", err)
+		}
+		return c.startSession(ctx, user, schema)
+	default:
+		return &Error{Code: erNotSupportedAuthMode, State: "08004", Msg: fmt.Sprintf("Invalid authentication method %s", mech)}
+	}
+}
+
+func (c *conn) authenticateContinue(ctx context.Context, msg []byte) error {
+	salt := c.salt
+	c.salt = nil
+	if salt == nil {
+		return newBadMessageError("Unexpected authentication continue message")
+	}
+
+	var authData []byte
+	err := decodeFields(msg, func(f field) error {
+		if f.num == 1 {
+			authData = f.b
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	schema, user, hexScramble, err := splitAuthData(authData)
+	if err != nil {
+		return err
+	}
+	scramble, err := hex.DecodeString(strings.TrimPrefix(hexScramble, "*"))
+	if err != nil {
+		return c.accessDenied(user, true, err)
+	}
+	if err = c.srv.args.Engine.ValidateScramble(user, salt, scramble, c.nc.RemoteAddr()); err != nil {
+		return c.accessDenied(user, len(scramble) > 0, err)
+	}
+	return c.startSession(ctx, user, schema)
+}
+
+// splitAuthData splits the authentication data of the MYSQL41 and PLAIN mechanisms, which hold the schema, user and
+// password or scramble separated by NUL bytes.
+func splitAuthData(data []byte) (schema, user, secret string, err error) {
+	parts := strings.SplitN(string(data), "\x00", 3)
+	if len(parts) != 3 {
+		return "", "", "", newBadMessageError("Invalid authentication data")
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func (c *conn) accessDenied(user string, usingPassword bool, cause error) error {
+	c.lgr.Debugf("X Protocol authentication failed for %s: %v", user, cause)
+	host, _, err := net.SplitHostPort(c.nc.RemoteAddr().String())
+	if err != nil {
+		host = c.nc.RemoteAddr().String()
+	}
+	using := "NO"
+	if usingPassword {
+		using = "YES"
+	}
+	return &Error{Code: erAccessDenied, State: "28000", Msg: fmt.Sprintf("Access denied for user '%s'@'%s' (using password: %s)", user, host, using)}
+}
+
+func (c *conn) startSession(ctx context.Context, user, schema string) error {
+	sess, err := c.srv.args.Engine.NewSession(ctx, user, c.nc.RemoteAddr(), schema)
+	if err != nil {
+		return err
+	}
+	c.closeSession()
+	c.user, c.sess = user, sess
+	c.send(serverSessAuthenticateOk, nil)
+	return nil
+}
+
+// closeSession ends the client's session, if it has one.
+func (c *conn) closeSession() {
+	if c.sess != nil {
+		c.srv.args.Engine.CloseSession(context.Background(), c.sess)
+		c.sess = nil
+	}
+}
+
+// newSalt returns a salt for the MYSQL41 mechanism, which like the salt of the MySQL protocol has no NUL bytes.
+func newSalt() ([]byte, error) {
+	salt := make([]byte, 20)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	for i := range salt {
+		salt[i] &= 0x7f
+		if salt[i] == 0 || salt[i] == '$' {
+			salt[i]++
+		}
+	}
+	return salt, nil
+}
+
+// sessionReset starts a new session for the client's user, or ends its session unless it is kept open.
+func (c *conn) sessionReset(ctx context.Context, msg []byte) error {
+	var keepOpen bool
+	err := decodeFields(msg, func(f field) error {
+		if f.num == 1 {
+			keepOpen = f.v != 0
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.closeSession()
+	if !keepOpen {
+		c.send(serverOk, nil)
+		return nil
+	}
+	sess, err := c.srv.args.Engine.NewSession(ctx, c.user, c.nc.RemoteAddr(), "")
+	if err != nil {
+		return err
+	}
+	c.sess = sess
+	c.send(serverOk, nil)
+	return nil
+}
+
+func (c *conn) expectOpen(msg []byte) error {
+	var copyPrev = true
+	var conds [][]byte
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			// EXPECT_CTX_COPY_PREV is the default, EXPECT_CTX_EMPTY starts without conditions
+			copyPrev = f.v == 0
+		case 2:
+			conds = append(conds, f.b)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var block expectBlock
+	if copyPrev && len(c.expects) > 0 {
+		block.noError = c.expects[len(c.expects)-1].noError
+	}
+	for _, cond := range conds {
+		var key, op uint64
+		err = decodeFields(cond, func(f field) error {
+			switch f.num {
+			case 1:
+				key = f.v
+			case 3:
+				op = f.v
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if key != expectNoError {
+			return newUnsupportedError(fmt.Sprintf("expectation condition %d", key))
+		}
+		block.noError = op != expectOpUnset
+	}
+	c.expects = append(c.expects, block)
+	c.send(serverOk, nil)
+	return nil
+}
+
+func (c *conn) expectClose() error {
+	n := len(c.expects)
+	if n == 0 {
+		return newBadMessageError("Expect block currently not open")
+	}
+	block := c.expects[n-1]
+	c.expects = c.expects[:n-1]
+	if block.failed {
+		return &Error{Code: erXExpectFailed, State: "HY000", Msg: "Expectation failed: no_error"}
+	}
+	c.send(serverOk, nil)
+	return nil
+}
+
+// stmtExecute handles a Mysqlx.Sql.StmtExecute message, which runs a SQL statement or an admin command.
+func (c *conn) stmtExecute(ctx context.Context, msg []byte) error {
+	var stmt string
+	namespace := "sql"
+	var args []scalar
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			stmt = string(f.b)
+		case 2:
+			arg, err := decodeAnyScalar(f.b)
+			if err != nil {
+				return err
+			}
+			args = append(args, arg)
+		case 3:
+			namespace = string(f.b)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch namespace {
+	case "sql":
+		query, err := bindPlaceholders(stmt, args)
+		if err != nil {
+			return err
+		}
+		return c.execute(ctx, query)
+	case "mysqlx", "xplugin", "admin":
+		// the admin commands manage document collections, except for ping
+		if stmt == "ping" {
+			c.send(serverSqlStmtExecuteOk, nil)
+			return nil
+		}
+		return newUnsupportedError("admin command " + stmt)
+	default:
+		return &Error{Code: erXInvalidNamespace, State: "HY000", Msg: fmt.Sprintf("Unknown namespace %s", namespace)}
+	}
+}
+
+// execute runs |query| in the client's session and sends its results.
+func (c *conn) execute(ctx context.Context, query string) error {
+	sqlCtx, err := c.srv.args.Engine.NewContext(ctx, c.sess)
+	if err != nil {
+		return err
+	}
+	sch, iter, err := c.srv.args.Engine.Query(sqlCtx, query)
+	if err != nil {
+		return err
+	}
+	return c.sendResults(sqlCtx, sch, iter)
+}
+
+// sendResults sends the rows of |iter|, or the rows affected by a statement which changes data.
+func (c *conn) sendResults(ctx *sql.Context, sch sql.Schema, iter sql.RowIter) (err error) {
+	defer func() {
+		if cerr := iter.Close(ctx); err == nil {
+			err = cerr
+		}
+	}()
+
+	isOk := types.IsOkResultSchema(sch)
+	if !isOk {
+		for _, col := range sch {
+			c.send(serverResultsetColumnMetaData, encodeColumnMetaData(col))
+		}
+	}
+
+	var rowsAffected, insertID uint64
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if isOk {
+			if res, ok := row[0].(types.OkResult); ok {
+				rowsAffected += res.RowsAffected
+				insertID = res.InsertID
+			}
+			continue
+		}
+		b, err := encodeRow(ctx, sch, row)
+		if err != nil {
+			return err
+		}
+		c.send(serverResultsetRow, b)
+	}
+
+	if isOk {
+		if insertID != 0 {
+			c.send(serverNotice, encodeStateChangedNotice(stateGeneratedInsertID, encodeUintScalar(insertID)))
+		}
+		c.send(serverNotice, encodeStateChangedNotice(stateRowsAffected, encodeUintScalar(rowsAffected)))
+	} else if len(sch) > 0 {
+		c.send(serverResultsetFetchDone, nil)
+	}
+	c.send(serverSqlStmtExecuteOk, nil)
+	return nil
+}
+
+func encodeStateChangedNotice(param uint64, value []byte) []byte {
+	state := appendVarintField(nil, 1, param)
+	state = appendBytesField(state, 2, value)
+	b := appendVarintField(nil, 1, noticeSessionStateChanged)
+	b = appendVarintField(b, 2, noticeScopeLocal)
+	return appendBytesField(b, 3, state)
+}
+
+// fieldType returns the X Protocol type and content type of values of |t|. Values of types without an X Protocol
+// equivalent, such as decimals and times, are sent as text.
+func fieldType(t sql.Type) (uint64, uint64) {
+	switch qt := t.Type(); {
+	case qt == sqltypes.Float32:
+		return fieldFloat, 0
+	case qt == sqltypes.Float64:
+		return fieldDouble, 0
+	case sqltypes.IsSigned(qt):
+		return fieldSint, 0
+	case sqltypes.IsUnsigned(qt):
+		return fieldUint, 0
+	case qt == sqltypes.TypeJSON:
+		return fieldBytes, contentJSON
+	case qt == sqltypes.Geometry:
+		return fieldBytes, contentGeometry
+	default:
+		return fieldBytes, 0
+	}
+}
+
+func encodeColumnMetaData(col *sql.Column) []byte {
+	typ, contentType := fieldType(col.Type)
+	b := appendVarintField(nil, 1, typ)
+	b = appendStringField(b, 2, col.Name)
+	b = appendStringField(b, 3, col.Name)
+	b = appendStringField(b, 4, col.Source)
+	b = appendStringField(b, 5, col.Source)
+	b = appendStringField(b, 6, col.DatabaseSource)
+	b = appendStringField(b, 7, "def")
+	if tc, ok := col.Type.(sql.TypeWithCollation); ok {
+		b = appendVarintField(b, 8, uint64(tc.Collation()))
+	} else if typ == fieldBytes {
+		b = appendVarintField(b, 8, binaryCollation)
+	}
+
+	var flags uint64
+	if !col.Nullable {
+		flags |= flagNotNull
+	}
+	if col.PrimaryKey {
+		flags |= flagPrimaryKey
+	}
+	b = appendVarintField(b, 11, flags)
+	if contentType != 0 {
+		b = appendVarintField(b, 12, contentType)
+	}
+	return b
+}
+
+// encodeRow returns the Mysqlx.Resultset.Row message for |row|.
+func encodeRow(ctx *sql.Context, sch sql.Schema, row sql.Row) ([]byte, error) {
+	var b []byte
+	for i, col := range sch {
+		v, err := encodeValue(ctx, col.Type, row[i])
+		if err != nil {
+			return nil, err
+		}
+		b = appendBytesField(b, 1, v)
+	}
+	return b, nil
+}
+
+// encodeValue encodes |v| for a row of a result set. NULL is sent as an empty field, and every other value of a
+// BYTES column ends with a NUL byte so that empty strings can be told apart from it.
+func encodeValue(ctx *sql.Context, t sql.Type, v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	typ, _ := fieldType(t)
+	switch typ {
+	case fieldSint:
+		i, _, err := types.Int64.Convert(v)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendVarint(nil, protowire.EncodeZigZag(i.(int64))), nil
+	case fieldUint:
+		u, _, err := types.Uint64.Convert(v)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendVarint(nil, u.(uint64)), nil
+	case fieldDouble:
+		f, _, err := types.Float64.Convert(v)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint64(nil, math.Float64bits(f.(float64))), nil
+	case fieldFloat:
+		f, _, err := types.Float32.Convert(v)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint32(nil, math.Float32bits(f.(float32))), nil
+	default:
+		res, err := t.SQL(ctx, nil, v)
+		if err != nil {
+			return nil, err
+		}
+		raw := res.Raw()
+		b := make([]byte, len(raw)+1)
+		copy(b, raw)
+		return b, nil
+	}
+}
@@ -0,0 +1,806 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The Mysqlx.Crud messages act on either a document collection or a relational table. Only tables are supported,
+// and each message is translated into the equivalent SQL statement.
+
+// dataModelTable is the Mysqlx.Crud.DataModel of relational tables.
+const dataModelTable = 2
+
+// Types of Mysqlx.Expr.Expr expressions.
+const (
+	exprIdent       = 1
+	exprLiteral     = 2
+	exprVariable    = 3
+	exprFuncCall    = 4
+	exprOperator    = 5
+	exprPlaceholder = 6
+	exprObject      = 7
+	exprArray       = 8
+)
+
+// updateSet is the Mysqlx.Crud.UpdateOperation that sets a column. The other operations change documents.
+const updateSet = 1
+
+// orderDesc is the Mysqlx.Crud.Order.Direction of descending orders.
+const orderDesc = 2
+
+// binaryOperators are the operators of Mysqlx.Expr.Operator which take two operands, and their SQL equivalents.
+var binaryOperators = map[string]string{
+	"==":         "=",
+	"!=":         "!=",
+	"<":          "<",
+	"<=":         "<=",
+	">":          ">",
+	">=":         ">=",
+	"&&":         "AND",
+	"||":         "OR",
+	"xor":        "XOR",
+	"+":          "+",
+	"-":          "-",
+	"*":          "*",
+	"/":          "/",
+	"div":        "DIV",
+	"%":          "%",
+	"&":          "&",
+	"|":          "|",
+	"^":          "^",
+	"<<":         "<<",
+	">>":         ">>",
+	"is":         "IS",
+	"is_not":     "IS NOT",
+	"regexp":     "REGEXP",
+	"not_regexp": "NOT REGEXP",
+}
+
+// unaryOperators are the operators of Mysqlx.Expr.Operator which take one operand, and their SQL equivalents.
+var unaryOperators = map[string]string{
+	"not":        "NOT ",
+	"!":          "!",
+	"~":          "~",
+	"sign_plus":  "+",
+	"sign_minus": "-",
+}
+
+// crudTranslator builds the SQL statement for a CRUD message.
+type crudTranslator struct {
+	sb   strings.Builder
+	args []scalar
+}
+
+// decodeArgs decodes the Mysqlx.Datatypes.Scalar arguments of a CRUD message.
+func decodeArgs(encoded [][]byte) ([]scalar, error) {
+	args := make([]scalar, len(encoded))
+	for i, b := range encoded {
+		var err error
+		if args[i], err = decodeScalar(b); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// collection writes the table named by a Mysqlx.Crud.Collection.
+func (t *crudTranslator) collection(msg []byte) error {
+	var name, schema string
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			name = string(f.b)
+		case 2:
+			schema = string(f.b)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return newBadMessageError("invalid empty collection name")
+	}
+	if schema != "" {
+		t.sb.WriteString(quoteIdentifier(schema))
+		t.sb.WriteByte('.')
+	}
+	t.sb.WriteString(quoteIdentifier(name))
+	return nil
+}
+
+// expr writes a Mysqlx.Expr.Expr.
+func (t *crudTranslator) expr(msg []byte) error {
+	var typ, position uint64
+	var body []byte
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			typ = f.v
+		case 2, 4, 5, 6:
+			body = f.b
+		case 7:
+			position = f.v
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch typ {
+	case exprIdent:
+		return t.columnIdentifier(body)
+	case exprLiteral:
+		s, err := decodeScalar(body)
+		if err != nil {
+			return err
+		}
+		return t.literal(s)
+	case exprFuncCall:
+		return t.functionCall(body)
+	case exprOperator:
+		return t.operator(body)
+	case exprPlaceholder:
+		if position >= uint64(len(t.args)) {
+			return newArgumentsError()
+		}
+		return t.literal(t.args[position])
+	case exprVariable:
+		return newUnsupportedError("variables")
+	case exprObject, exprArray:
+		return newUnsupportedError("object and array expressions")
+	default:
+		return newBadMessageError("invalid expression type %d", typ)
+	}
+}
+
+func (t *crudTranslator) literal(s scalar) error {
+	lit, err := scalarLiteral(s)
+	if err != nil {
+		return err
+	}
+	t.sb.WriteString(lit)
+	return nil
+}
+
+// columnIdentifier writes the column named by a Mysqlx.Expr.ColumnIdentifier.
+func (t *crudTranslator) columnIdentifier(msg []byte) error {
+	var name, table, schema string
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			return newUnsupportedError("document paths")
+		case 2:
+			name = string(f.b)
+		case 3:
+			table = string(f.b)
+		case 4:
+			schema = string(f.b)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if schema != "" {
+		t.sb.WriteString(quoteIdentifier(schema))
+		t.sb.WriteByte('.')
+	}
+	if table != "" {
+		t.sb.WriteString(quoteIdentifier(table))
+		t.sb.WriteByte('.')
+	}
+	t.sb.WriteString(quoteIdentifier(name))
+	return nil
+}
+
+// functionCall writes a Mysqlx.Expr.FunctionCall.
+func (t *crudTranslator) functionCall(msg []byte) error {
+	var name, schema string
+	var params [][]byte
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			return decodeFields(f.b, func(f field) error {
+				switch f.num {
+				case 1:
+					name = string(f.b)
+				case 2:
+					schema = string(f.b)
+				}
+				return nil
+			})
+		case 2:
+			params = append(params, f.b)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if schema != "" {
+		t.sb.WriteString(quoteIdentifier(schema))
+		t.sb.WriteByte('.')
+		t.sb.WriteString(quoteIdentifier(name))
+	} else if isFunctionName(name) {
+		// built in functions can't be quoted
+		t.sb.WriteString(name)
+	} else {
+		return newBadMessageError("invalid function name %s", name)
+	}
+	t.sb.WriteByte('(')
+	if err = t.exprList(params); err != nil {
+		return err
+	}
+	t.sb.WriteByte(')')
+	return nil
+}
+
+func isFunctionName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// operator writes a Mysqlx.Expr.Operator.
+func (t *crudTranslator) operator(msg []byte) error {
+	var name string
+	var params [][]byte
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			name = string(f.b)
+		case 2:
+			params = append(params, f.b)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if op, ok := binaryOperators[name]; ok && len(params) == 2 {
+		return t.binary(params[0], op, params[1])
+	}
+	if op, ok := unaryOperators[name]; ok && len(params) == 1 {
+		t.sb.WriteByte('(')
+		t.sb.WriteString(op)
+		if err = t.expr(params[0]); err != nil {
+			return err
+		}
+		t.sb.WriteByte(')')
+		return nil
+	}
+
+	switch {
+	case name == "*" && len(params) == 0:
+		t.sb.WriteByte('*')
+		return nil
+	case name == "default" && len(params) == 0:
+		t.sb.WriteString("DEFAULT")
+		return nil
+	case (name == "like" || name == "not_like") && (len(params) == 2 || len(params) == 3):
+		op := "LIKE"
+		if name == "not_like" {
+			op = "NOT LIKE"
+		}
+		t.sb.WriteByte('(')
+		if err = t.expr(params[0]); err != nil {
+			return err
+		}
+		t.sb.WriteString(" " + op + " ")
+		if err = t.expr(params[1]); err != nil {
+			return err
+		}
+		if len(params) == 3 {
+			t.sb.WriteString(" ESCAPE ")
+			if err = t.expr(params[2]); err != nil {
+				return err
+			}
+		}
+		t.sb.WriteByte(')')
+		return nil
+	case (name == "in" || name == "not_in") && len(params) >= 2:
+		op := " IN ("
+		if name == "not_in" {
+			op = " NOT IN ("
+		}
+		t.sb.WriteByte('(')
+		if err = t.expr(params[0]); err != nil {
+			return err
+		}
+		t.sb.WriteString(op)
+		if err = t.exprList(params[1:]); err != nil {
+			return err
+		}
+		t.sb.WriteString("))")
+		return nil
+	case (name == "between" || name == "not_between") && len(params) == 3:
+		op := " BETWEEN "
+		if name == "not_between" {
+			op = " NOT BETWEEN "
+		}
+		t.sb.WriteByte('(')
+		if err = t.expr(params[0]); err != nil {
+			return err
+		}
+		t.sb.WriteString(op)
+		if err = t.expr(params[1]); err != nil {
+			return err
+		}
+		t.sb.WriteString(" AND ")
+		if err = t.expr(params[2]); err != nil {
+			return err
+		}
+		t.sb.WriteByte(')')
+		return nil
+	}
+
+	return newUnsupportedError("operator " + name + " with " + strconv.Itoa(len(params)) + " operands")
+}
+
+func (t *crudTranslator) binary(left []byte, op string, right []byte) error {
+	t.sb.WriteByte('(')
+	if err := t.expr(left); err != nil {
+		return err
+	}
+	t.sb.WriteString(" " + op + " ")
+	if err := t.expr(right); err != nil {
+		return err
+	}
+	t.sb.WriteByte(')')
+	return nil
+}
+
+func (t *crudTranslator) exprList(exprs [][]byte) error {
+	for i, e := range exprs {
+		if i > 0 {
+			t.sb.WriteString(", ")
+		}
+		if err := t.expr(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// where writes the WHERE clause for |criteria|, if there is one.
+func (t *crudTranslator) where(criteria []byte) error {
+	if criteria == nil {
+		return nil
+	}
+	t.sb.WriteString(" WHERE ")
+	return t.expr(criteria)
+}
+
+// orderBy writes the ORDER BY clause for the Mysqlx.Crud.Order messages in |orders|.
+func (t *crudTranslator) orderBy(orders [][]byte) error {
+	for i, o := range orders {
+		if i == 0 {
+			t.sb.WriteString(" ORDER BY ")
+		} else {
+			t.sb.WriteString(", ")
+		}
+		var e []byte
+		var direction uint64
+		err := decodeFields(o, func(f field) error {
+			switch f.num {
+			case 1:
+				e = f.b
+			case 2:
+				direction = f.v
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err = t.expr(e); err != nil {
+			return err
+		}
+		if direction == orderDesc {
+			t.sb.WriteString(" DESC")
+		}
+	}
+	return nil
+}
+
+// limit writes the LIMIT clause for a Mysqlx.Crud.Limit. Updates and deletes can't skip rows.
+func (t *crudTranslator) limit(msg []byte, allowOffset bool) error {
+	if msg == nil {
+		return nil
+	}
+	var rowCount, offset uint64
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			rowCount = f.v
+		case 2:
+			offset = f.v
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	t.sb.WriteString(" LIMIT ")
+	if offset != 0 {
+		if !allowOffset {
+			return newBadMessageError("invalid limit offset for update or delete")
+		}
+		t.sb.WriteString(strconv.FormatUint(offset, 10))
+		t.sb.WriteString(", ")
+	}
+	t.sb.WriteString(strconv.FormatUint(rowCount, 10))
+	return nil
+}
+
+func checkDataModel(dataModel uint64) error {
+	if dataModel != dataModelTable {
+		return newUnsupportedError("document collections")
+	}
+	return nil
+}
+
+// translateFind returns the SELECT statement for a Mysqlx.Crud.Find message.
+func translateFind(msg []byte) (string, error) {
+	var coll, criteria, limit, havingCriteria []byte
+	var dataModel, locking uint64
+	var projections, orders, grouping, args [][]byte
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 2:
+			coll = f.b
+		case 3:
+			dataModel = f.v
+		case 4:
+			projections = append(projections, f.b)
+		case 5:
+			criteria = f.b
+		case 6:
+			limit = f.b
+		case 7:
+			orders = append(orders, f.b)
+		case 8:
+			grouping = append(grouping, f.b)
+		case 9:
+			havingCriteria = f.b
+		case 11:
+			args = append(args, f.b)
+		case 12:
+			locking = f.v
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = checkDataModel(dataModel); err != nil {
+		return "", err
+	}
+	if locking != 0 {
+		return "", newUnsupportedError("row locks")
+	}
+
+	t := &crudTranslator{}
+	if t.args, err = decodeArgs(args); err != nil {
+		return "", err
+	}
+
+	t.sb.WriteString("SELECT ")
+	if len(projections) == 0 {
+		t.sb.WriteByte('*')
+	}
+	for i, p := range projections {
+		if i > 0 {
+			t.sb.WriteString(", ")
+		}
+		var source []byte
+		var alias string
+		err = decodeFields(p, func(f field) error {
+			switch f.num {
+			case 1:
+				source = f.b
+			case 2:
+				alias = string(f.b)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		if err = t.expr(source); err != nil {
+			return "", err
+		}
+		if alias != "" {
+			t.sb.WriteString(" AS ")
+			t.sb.WriteString(quoteIdentifier(alias))
+		}
+	}
+
+	t.sb.WriteString(" FROM ")
+	if err = t.collection(coll); err != nil {
+		return "", err
+	}
+	if err = t.where(criteria); err != nil {
+		return "", err
+	}
+	if len(grouping) > 0 {
+		t.sb.WriteString(" GROUP BY ")
+		if err = t.exprList(grouping); err != nil {
+			return "", err
+		}
+	}
+	if havingCriteria != nil {
+		t.sb.WriteString(" HAVING ")
+		if err = t.expr(havingCriteria); err != nil {
+			return "", err
+		}
+	}
+	if err = t.orderBy(orders); err != nil {
+		return "", err
+	}
+	if err = t.limit(limit, true); err != nil {
+		return "", err
+	}
+	return t.sb.String(), nil
+}
+
+// translateInsert returns the INSERT statement for a Mysqlx.Crud.Insert message.
+func translateInsert(msg []byte) (string, error) {
+	var coll []byte
+	var dataModel uint64
+	var upsert bool
+	var columns, rows, args [][]byte
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			coll = f.b
+		case 2:
+			dataModel = f.v
+		case 3:
+			columns = append(columns, f.b)
+		case 4:
+			rows = append(rows, f.b)
+		case 5:
+			args = append(args, f.b)
+		case 6:
+			upsert = f.v != 0
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = checkDataModel(dataModel); err != nil {
+		return "", err
+	}
+	if upsert {
+		return "", newUnsupportedError("upserts into tables")
+	}
+	if len(rows) == 0 {
+		return "", newBadMessageError("missing row data for insert")
+	}
+
+	t := &crudTranslator{}
+	if t.args, err = decodeArgs(args); err != nil {
+		return "", err
+	}
+
+	t.sb.WriteString("INSERT INTO ")
+	if err = t.collection(coll); err != nil {
+		return "", err
+	}
+	if len(columns) > 0 {
+		t.sb.WriteString(" (")
+		for i, c := range columns {
+			if i > 0 {
+				t.sb.WriteString(", ")
+			}
+			var name string
+			err = decodeFields(c, func(f field) error {
+				switch f.num {
+				case 1:
+					name = string(f.b)
+				case 3:
+					return newUnsupportedError("document paths")
+				}
+				return nil
+			})
+			if err != nil {
+				return "", err
+			}
+			t.sb.WriteString(quoteIdentifier(name))
+		}
+		t.sb.WriteByte(')')
+	}
+
+	t.sb.WriteString(" VALUES ")
+	for i, r := range rows {
+		if i > 0 {
+			t.sb.WriteString(", ")
+		}
+		var fields [][]byte
+		err = decodeFields(r, func(f field) error {
+			if f.num == 1 {
+				fields = append(fields, f.b)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(columns) > 0 && len(fields) != len(columns) {
+			return "", newBadMessageError("wrong number of fields in row being inserted")
+		}
+		t.sb.WriteByte('(')
+		if err = t.exprList(fields); err != nil {
+			return "", err
+		}
+		t.sb.WriteByte(')')
+	}
+	return t.sb.String(), nil
+}
+
+// translateUpdate returns the UPDATE statement for a Mysqlx.Crud.Update message.
+func translateUpdate(msg []byte) (string, error) {
+	var coll, criteria, limit []byte
+	var dataModel uint64
+	var orders, operations, args [][]byte
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 2:
+			coll = f.b
+		case 3:
+			dataModel = f.v
+		case 4:
+			criteria = f.b
+		case 5:
+			limit = f.b
+		case 6:
+			orders = append(orders, f.b)
+		case 7:
+			operations = append(operations, f.b)
+		case 8:
+			args = append(args, f.b)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = checkDataModel(dataModel); err != nil {
+		return "", err
+	}
+	if len(operations) == 0 {
+		return "", newBadMessageError("invalid update expression list")
+	}
+
+	t := &crudTranslator{}
+	if t.args, err = decodeArgs(args); err != nil {
+		return "", err
+	}
+
+	t.sb.WriteString("UPDATE ")
+	if err = t.collection(coll); err != nil {
+		return "", err
+	}
+	t.sb.WriteString(" SET ")
+	for i, op := range operations {
+		if i > 0 {
+			t.sb.WriteString(", ")
+		}
+		var source, value []byte
+		var typ uint64
+		err = decodeFields(op, func(f field) error {
+			switch f.num {
+			case 1:
+				source = f.b
+			case 2:
+				typ = f.v
+			case 3:
+				value = f.b
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		if typ != updateSet {
+			return "", newUnsupportedError("document update operations")
+		}
+		if value == nil {
+			return "", newBadMessageError("missing value for update")
+		}
+		if err = t.columnIdentifier(source); err != nil {
+			return "", err
+		}
+		t.sb.WriteString(" = ")
+		if err = t.expr(value); err != nil {
+			return "", err
+		}
+	}
+	if err = t.where(criteria); err != nil {
+		return "", err
+	}
+	if err = t.orderBy(orders); err != nil {
+		return "", err
+	}
+	if err = t.limit(limit, false); err != nil {
+		return "", err
+	}
+	return t.sb.String(), nil
+}
+
+// translateDelete returns the DELETE statement for a Mysqlx.Crud.Delete message.
+func translateDelete(msg []byte) (string, error) {
+	var coll, criteria, limit []byte
+	var dataModel uint64
+	var orders, args [][]byte
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			coll = f.b
+		case 2:
+			dataModel = f.v
+		case 3:
+			criteria = f.b
+		case 4:
+			limit = f.b
+		case 5:
+			orders = append(orders, f.b)
+		case 6:
+			args = append(args, f.b)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = checkDataModel(dataModel); err != nil {
+		return "", err
+	}
+
+	t := &crudTranslator{}
+	if t.args, err = decodeArgs(args); err != nil {
+		return "", err
+	}
+
+	t.sb.WriteString("DELETE FROM ")
+	if err = t.collection(coll); err != nil {
+		return "", err
+	}
+	if err = t.where(criteria); err != nil {
+		return "", err
+	}
+	if err = t.orderBy(orders); err != nil {
+		return "", err
+	}
+	if err = t.limit(limit, false); err != nil {
+		return "", err
+	}
+	return t.sb.String(), nil
+}
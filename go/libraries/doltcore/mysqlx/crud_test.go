@@ -0,0 +1,243 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func collectionMsg(schema, name string) []byte {
+	b := appendStringField(nil, 1, name)
+	if schema != "" {
+		b = appendStringField(b, 2, schema)
+	}
+	return b
+}
+
+func identExpr(name string) []byte {
+	ident := appendStringField(nil, 2, name)
+	b := appendVarintField(nil, 1, exprIdent)
+	return appendBytesField(b, 2, ident)
+}
+
+func literalExpr(s []byte) []byte {
+	b := appendVarintField(nil, 1, exprLiteral)
+	return appendBytesField(b, 4, s)
+}
+
+func placeholderExpr(pos uint64) []byte {
+	b := appendVarintField(nil, 1, exprPlaceholder)
+	return appendVarintField(b, 7, pos)
+}
+
+func operatorExpr(name string, params ...[]byte) []byte {
+	op := appendStringField(nil, 1, name)
+	for _, p := range params {
+		op = appendBytesField(op, 2, p)
+	}
+	b := appendVarintField(nil, 1, exprOperator)
+	return appendBytesField(b, 6, op)
+}
+
+func functionExpr(name string, params ...[]byte) []byte {
+	fn := appendBytesField(nil, 1, appendStringField(nil, 1, name))
+	for _, p := range params {
+		fn = appendBytesField(fn, 2, p)
+	}
+	b := appendVarintField(nil, 1, exprFuncCall)
+	return appendBytesField(b, 5, fn)
+}
+
+func sintScalar(v int64) []byte {
+	b := appendVarintField(nil, 1, scalarSint)
+	return appendVarintField(b, 2, protowire.EncodeZigZag(v))
+}
+
+func TestTranslateFind(t *testing.T) {
+	projection := func(e []byte, alias string) []byte {
+		b := appendBytesField(nil, 1, e)
+		if alias != "" {
+			b = appendStringField(b, 2, alias)
+		}
+		return b
+	}
+	order := func(e []byte, direction uint64) []byte {
+		b := appendBytesField(nil, 1, e)
+		return appendVarintField(b, 2, direction)
+	}
+
+	tests := []struct {
+		name     string
+		msg      []byte
+		expected string
+		err      bool
+	}{
+		{
+			name: "select all",
+			msg: func() []byte {
+				b := appendBytesField(nil, 2, collectionMsg("db", "t"))
+				return appendVarintField(b, 3, dataModelTable)
+			}(),
+			expected: "SELECT * FROM `db`.`t`",
+		},
+		{
+			name: "projection, criteria, order and limit",
+			msg: func() []byte {
+				b := appendBytesField(nil, 2, collectionMsg("", "t"))
+				b = appendVarintField(b, 3, dataModelTable)
+				b = appendBytesField(b, 4, projection(identExpr("a"), ""))
+				b = appendBytesField(b, 4, projection(functionExpr("upper", identExpr("b")), "B"))
+				b = appendBytesField(b, 5, operatorExpr("&&",
+					operatorExpr(">", identExpr("a"), placeholderExpr(0)),
+					operatorExpr("like", identExpr("b"), literalExpr(encodeStringScalar("x'%")))))
+				limit := appendVarintField(nil, 1, 10)
+				limit = appendVarintField(limit, 2, 5)
+				b = appendBytesField(b, 6, limit)
+				b = appendBytesField(b, 7, order(identExpr("a"), orderDesc))
+				return appendBytesField(b, 11, sintScalar(-3))
+			}(),
+			expected: "SELECT `a`, upper(`b`) AS `B` FROM `t` WHERE ((`a` > -3) AND (`b` LIKE 'x\\'%')) ORDER BY `a` DESC LIMIT 5, 10",
+		},
+		{
+			name: "group by and having",
+			msg: func() []byte {
+				b := appendBytesField(nil, 2, collectionMsg("", "t"))
+				b = appendVarintField(b, 3, dataModelTable)
+				b = appendBytesField(b, 4, projection(identExpr("a"), ""))
+				b = appendBytesField(b, 4, projection(functionExpr("count", operatorExpr("*")), "n"))
+				b = appendBytesField(b, 8, identExpr("a"))
+				return appendBytesField(b, 9, operatorExpr("in", identExpr("a"), literalExpr(sintScalar(1)), literalExpr(sintScalar(2))))
+			}(),
+			expected: "SELECT `a`, count(*) AS `n` FROM `t` GROUP BY `a` HAVING (`a` IN (1, 2))",
+		},
+		{
+			name: "document collection",
+			msg:  appendBytesField(nil, 2, collectionMsg("", "t")),
+			err:  true,
+		},
+		{
+			name: "missing placeholder argument",
+			msg: func() []byte {
+				b := appendBytesField(nil, 2, collectionMsg("", "t"))
+				b = appendVarintField(b, 3, dataModelTable)
+				return appendBytesField(b, 5, operatorExpr("==", identExpr("a"), placeholderExpr(0)))
+			}(),
+			err: true,
+		},
+		{
+			name: "invalid function name",
+			msg: func() []byte {
+				b := appendBytesField(nil, 2, collectionMsg("", "t"))
+				b = appendVarintField(b, 3, dataModelTable)
+				return appendBytesField(b, 4, projection(functionExpr("sleep(1); drop", identExpr("a")), ""))
+			}(),
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := translateFind(tt.msg)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestTranslateInsert(t *testing.T) {
+	row := func(fields ...[]byte) []byte {
+		var b []byte
+		for _, f := range fields {
+			b = appendBytesField(b, 1, f)
+		}
+		return b
+	}
+
+	b := appendBytesField(nil, 1, collectionMsg("", "t"))
+	b = appendVarintField(b, 2, dataModelTable)
+	b = appendBytesField(b, 3, appendStringField(nil, 1, "a"))
+	b = appendBytesField(b, 3, appendStringField(nil, 1, "b"))
+	b = appendBytesField(b, 4, row(literalExpr(sintScalar(1)), literalExpr(encodeStringScalar("one"))))
+	b = appendBytesField(b, 4, row(placeholderExpr(0), operatorExpr("default")))
+	b = appendBytesField(b, 5, encodeUintScalar(2))
+
+	query, err := translateInsert(b)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `t` (`a`, `b`) VALUES (1, 'one'), (2, DEFAULT)", query)
+
+	// rows must match the columns inserted into
+	b = appendBytesField(nil, 1, collectionMsg("", "t"))
+	b = appendVarintField(b, 2, dataModelTable)
+	b = appendBytesField(b, 3, appendStringField(nil, 1, "a"))
+	b = appendBytesField(b, 4, row(literalExpr(sintScalar(1)), literalExpr(sintScalar(2))))
+	_, err = translateInsert(b)
+	require.Error(t, err)
+}
+
+func TestTranslateUpdateAndDelete(t *testing.T) {
+	set := func(column string, value []byte) []byte {
+		b := appendBytesField(nil, 1, appendStringField(nil, 2, column))
+		b = appendVarintField(b, 2, updateSet)
+		return appendBytesField(b, 3, value)
+	}
+
+	b := appendBytesField(nil, 2, collectionMsg("", "t"))
+	b = appendVarintField(b, 3, dataModelTable)
+	b = appendBytesField(b, 4, operatorExpr("==", identExpr("a"), placeholderExpr(0)))
+	b = appendBytesField(b, 5, appendVarintField(nil, 1, 1))
+	b = appendBytesField(b, 7, set("b", operatorExpr("+", identExpr("b"), literalExpr(sintScalar(1)))))
+	b = appendBytesField(b, 8, encodeStringScalar("k"))
+
+	query, err := translateUpdate(b)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE `t` SET `b` = (`b` + 1) WHERE (`a` = 'k') LIMIT 1", query)
+
+	b = appendBytesField(nil, 1, collectionMsg("", "t"))
+	b = appendVarintField(b, 2, dataModelTable)
+	b = appendBytesField(b, 3, operatorExpr("not", operatorExpr("is", identExpr("a"), literalExpr(appendVarintField(nil, 1, scalarNull)))))
+
+	query, err = translateDelete(b)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `t` WHERE (NOT (`a` IS NULL))", query)
+}
+
+func TestBindPlaceholders(t *testing.T) {
+	args := []scalar{
+		{typ: scalarSint, sint: -1},
+		{typ: scalarString, bytes: []byte("it's")},
+		{typ: scalarBool, bool: true},
+	}
+
+	query, err := bindPlaceholders("select ?, '?', `?`, ? /* ? */, ? -- ?", args)
+	require.NoError(t, err)
+	assert.Equal(t, "select -1, '?', `?`, 'it\\'s' /* ? */, TRUE -- ?", query)
+
+	query, err = bindPlaceholders("select 'a\\'?', ?", args[:1])
+	require.NoError(t, err)
+	assert.Equal(t, "select 'a\\'?', -1", query)
+
+	_, err = bindPlaceholders("select ?, ?", args[:1])
+	require.Error(t, err)
+	_, err = bindPlaceholders("select ?", args)
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Error codes of the MySQL server sent to X Protocol clients.
+const (
+	erAccessDenied            = 1045
+	erNotSupportedYet         = 1235
+	erNotSupportedAuthMode    = 1251
+	erXBadMessage             = 5000
+	erXCapabilitiesPrepFailed = 5001
+	erXCapabilityNotFound     = 5002
+	erXCmdNumArguments        = 5015
+	erXExpectFailed           = 5159
+	erXInvalidNamespace       = 5162
+)
+
+// Error is an error which is sent to the client, with its MySQL error code and SQLSTATE.
+type Error struct {
+	Code  uint32
+	State string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+func newBadMessageError(format string, args ...any) error {
+	return &Error{Code: erXBadMessage, State: "HY000", Msg: fmt.Sprintf(format, args...)}
+}
+
+func newArgumentsError() error {
+	return &Error{Code: erXCmdNumArguments, State: "HY000", Msg: "Invalid number of arguments"}
+}
+
+func newUnsupportedError(what string) error {
+	return &Error{Code: erNotSupportedYet, State: "42000", Msg: fmt.Sprintf("The X Protocol server does not support %s", what)}
+}
+
+// toError returns |err| as an *Error, with the code and SQLSTATE the engine reports for it.
+func toError(err error) *Error {
+	var xErr *Error
+	if errors.As(err, &xErr) {
+		return xErr
+	}
+	sqlErr := sql.CastSQLError(err)
+	return &Error{Code: uint32(sqlErr.Num), State: sqlErr.State, Msg: sqlErr.Message}
+}
+
+// encode returns the Mysqlx.Error message for |e|.
+func (e *Error) encode() []byte {
+	b := appendVarintField(nil, 2, uint64(e.Code))
+	b = appendStringField(b, 3, e.Msg)
+	return appendStringField(b, 4, e.State)
+}
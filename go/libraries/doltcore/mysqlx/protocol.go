@@ -0,0 +1,268 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The X Protocol frames every message with its length and type, and encodes its payload as a protobuf message
+// defined in the mysqlx*.proto files of the MySQL server. The messages are small and only a few of them are
+// supported, so they are encoded and decoded by hand here rather than generated.
+
+// maxMessageSize is the largest message a client may send, the default of @@mysqlx_max_allowed_packet.
+const maxMessageSize = 64 * 1024 * 1024
+
+// Types of the messages sent by clients, from Mysqlx.ClientMessages.
+const (
+	clientConCapabilitiesGet       = 1
+	clientConCapabilitiesSet       = 2
+	clientConClose                 = 3
+	clientSessAuthenticateStart    = 4
+	clientSessAuthenticateContinue = 5
+	clientSessReset                = 6
+	clientSessClose                = 7
+	clientSqlStmtExecute           = 12
+	clientCrudFind                 = 17
+	clientCrudInsert               = 18
+	clientCrudUpdate               = 19
+	clientCrudDelete               = 20
+	clientExpectOpen               = 24
+	clientExpectClose              = 25
+)
+
+// Types of the messages sent by the server, from Mysqlx.ServerMessages.
+const (
+	serverOk                       = 0
+	serverError                    = 1
+	serverConnCapabilities         = 2
+	serverSessAuthenticateContinue = 3
+	serverSessAuthenticateOk       = 4
+	serverNotice                   = 11
+	serverResultsetColumnMetaData  = 12
+	serverResultsetRow             = 13
+	serverResultsetFetchDone       = 14
+	serverSqlStmtExecuteOk         = 17
+)
+
+// Types of Mysqlx.Datatypes.Scalar values.
+const (
+	scalarSint   = 1
+	scalarUint   = 2
+	scalarNull   = 3
+	scalarOctets = 4
+	scalarDouble = 5
+	scalarFloat  = 6
+	scalarBool   = 7
+	scalarString = 8
+)
+
+// Types of Mysqlx.Datatypes.Any values.
+const (
+	anyScalar = 1
+	anyObject = 2
+	anyArray  = 3
+)
+
+// readMessage reads the next message from |r|, returning its type and payload.
+func readMessage(r io.Reader) (byte, []byte, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r, hdr[:4]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.LittleEndian.Uint32(hdr[:4])
+	if size == 0 {
+		return 0, nil, fmt.Errorf("invalid message of length 0")
+	} else if size > maxMessageSize {
+		return 0, nil, fmt.Errorf("message of %d bytes is larger than the maximum of %d", size, maxMessageSize)
+	}
+	if _, err := io.ReadFull(r, hdr[4:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, size-1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return hdr[4], payload, nil
+}
+
+// writeMessage writes a message of type |typ| with |payload| to |w|.
+func writeMessage(w io.Writer, typ byte, payload []byte) error {
+	var hdr [5]byte
+	binary.LittleEndian.PutUint32(hdr[:4], uint32(len(payload)+1))
+	hdr[4] = typ
+	if _, err := w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// field is a field of a decoded protobuf message. Varint and fixed width values are held in |v|, length delimited
+// values in |b|.
+type field struct {
+	num protowire.Number
+	v   uint64
+	b   []byte
+}
+
+// decodeFields calls |cb| with each field of the protobuf message |msg|, in the order they are encoded.
+func decodeFields(msg []byte, cb func(f field) error) error {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		f := field{num: num}
+		switch typ {
+		case protowire.VarintType:
+			f.v, n = protowire.ConsumeVarint(msg)
+		case protowire.Fixed64Type:
+			f.v, n = protowire.ConsumeFixed64(msg)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(msg)
+			f.v = uint64(v)
+		case protowire.BytesType:
+			f.b, n = protowire.ConsumeBytes(msg)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, msg)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		if err := cb(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendVarintField(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendBytesField(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendStringField(b []byte, num protowire.Number, v string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// scalar is a decoded Mysqlx.Datatypes.Scalar.
+type scalar struct {
+	typ   uint64
+	sint  int64
+	uint  uint64
+	bytes []byte
+	float float64
+	bool  bool
+}
+
+func decodeScalar(msg []byte) (scalar, error) {
+	var s scalar
+	err := decodeFields(msg, func(f field) error {
+		switch f.num {
+		case 1:
+			s.typ = f.v
+		case 2:
+			s.sint = protowire.DecodeZigZag(f.v)
+		case 3:
+			s.uint = f.v
+		case 5, 9:
+			// Octets and String both hold their value in field 1
+			return decodeFields(f.b, func(f field) error {
+				if f.num == 1 {
+					s.bytes = f.b
+				}
+				return nil
+			})
+		case 6:
+			s.float = math.Float64frombits(f.v)
+		case 7:
+			s.float = float64(math.Float32frombits(uint32(f.v)))
+		case 8:
+			s.bool = f.v != 0
+		}
+		return nil
+	})
+	return s, err
+}
+
+// decodeAnyScalar decodes a Mysqlx.Datatypes.Any holding a scalar. Objects and arrays are only used by document
+// collections, which aren't supported.
+func decodeAnyScalar(msg []byte) (scalar, error) {
+	var typ uint64
+	var s scalar
+	err := decodeFields(msg, func(f field) (err error) {
+		switch f.num {
+		case 1:
+			typ = f.v
+		case 2:
+			s, err = decodeScalar(f.b)
+		}
+		return err
+	})
+	if err != nil {
+		return scalar{}, err
+	}
+	if typ != anyScalar {
+		return scalar{}, newUnsupportedError("object and array arguments")
+	}
+	return s, nil
+}
+
+func encodeStringScalar(v string) []byte {
+	str := appendStringField(nil, 1, v)
+	b := appendVarintField(nil, 1, scalarString)
+	return appendBytesField(b, 9, str)
+}
+
+func encodeUintScalar(v uint64) []byte {
+	b := appendVarintField(nil, 1, scalarUint)
+	return appendVarintField(b, 3, v)
+}
+
+func encodeBoolScalar(v bool) []byte {
+	b := appendVarintField(nil, 1, scalarBool)
+	return appendVarintField(b, 8, protowire.EncodeBool(v))
+}
+
+func encodeAnyScalar(scalar []byte) []byte {
+	b := appendVarintField(nil, 1, anyScalar)
+	return appendBytesField(b, 2, scalar)
+}
+
+func encodeAnyArray(values ...[]byte) []byte {
+	var arr []byte
+	for _, v := range values {
+		arr = appendBytesField(arr, 1, v)
+	}
+	b := appendVarintField(nil, 1, anyArray)
+	return appendBytesField(b, 4, arr)
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPort is the port MySQL servers serve the X Protocol on.
+const DefaultPort = 33060
+
+// Engine authenticates the clients of a Server and runs their statements.
+type Engine interface {
+	// ValidateScramble returns an error unless |scramble| is the mysql_native_password response of |user|'s password
+	// to |salt|.
+	ValidateScramble(user string, salt, scramble []byte, addr net.Addr) error
+	// ValidatePassword returns an error unless |password| is the password of |user|.
+	ValidatePassword(user, password string, addr net.Addr) error
+	// NewSession returns a new session of |user| connected from |addr|. If |schema| isn't empty, it is the session's
+	// current database.
+	NewSession(ctx context.Context, user string, addr net.Addr, schema string) (sql.Session, error)
+	// CloseSession ends |sess| once its client has closed it or disconnected, rolling back its transaction and
+	// releasing its locks.
+	CloseSession(ctx context.Context, sess sql.Session)
+	// NewContext returns a context for running a statement in |sess|.
+	NewContext(ctx context.Context, sess sql.Session) (*sql.Context, error)
+	// Query runs |query|.
+	Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error)
+}
+
+// ServerArgs configure a Server.
+type ServerArgs struct {
+	Logger *logrus.Entry
+	Engine Engine
+	// AllowCleartextPasswords enables the PLAIN authentication mechanism, which sends the client's password
+	// unencrypted. Connections aren't encrypted, so it is disabled by default.
+	AllowCleartextPasswords bool
+}
+
+// Server serves the MySQL X Protocol, so that clients using the X DevAPI, such as MySQL Shell, can connect. Clients
+// can run SQL statements, and use the CRUD messages on relational tables. Document collections, TLS, compression and
+// prepared statements aren't supported.
+type Server struct {
+	args ServerArgs
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewServer returns a new Server for |args|.
+func NewServer(args ServerArgs) *Server {
+	if args.Logger == nil {
+		args.Logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+	return &Server{args: args, conns: make(map[net.Conn]struct{})}
+}
+
+// Serve accepts connections on |lis| until the server is closed. It always returns a non-nil error, which is
+// net.ErrClosed if the server was closed.
+func (s *Server) Serve(lis net.Listener) error {
+	defer lis.Close()
+	for {
+		nc, err := lis.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return net.ErrClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			nc.Close()
+			return net.ErrClosed
+		}
+		s.conns[nc] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			s.handle(nc)
+		}()
+	}
+}
+
+// Close closes every open connection and waits for them to finish. Listeners passed to Serve must be closed by the
+// caller.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for nc := range s.conns {
+		nc.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Server) handle(nc net.Conn) {
+	c := &conn{
+		srv: s,
+		nc:  nc,
+		r:   bufio.NewReader(nc),
+		w:   bufio.NewWriter(nc),
+		lgr: s.args.Logger.WithField("remote_addr", nc.RemoteAddr().String()),
+	}
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, nc)
+		s.mu.Unlock()
+		c.closeSession()
+		nc.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for {
+		typ, msg, err := readMessage(c.r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !s.isClosed() {
+				c.lgr.Debugf("error reading X Protocol message: %v", err)
+			}
+			return
+		}
+		closeConn, err := c.dispatch(ctx, typ, msg)
+		if err == nil {
+			err = c.w.Flush()
+		}
+		if err != nil {
+			c.lgr.Debugf("error writing X Protocol message: %v", err)
+			return
+		}
+		if closeConn {
+			return
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Like the X Plugin of the MySQL server, statement arguments and the CRUD messages are turned into SQL text, with
+// the arguments quoted as literals, before they are run.
+
+var stringLiteralEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+)
+
+// quoteString returns |s| as a SQL string literal.
+func quoteString(s string) string {
+	return "'" + stringLiteralEscaper.Replace(s) + "'"
+}
+
+// quoteIdentifier returns |s| as a quoted SQL identifier.
+func quoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// scalarLiteral returns |s| as a SQL literal.
+func scalarLiteral(s scalar) (string, error) {
+	switch s.typ {
+	case scalarSint:
+		return strconv.FormatInt(s.sint, 10), nil
+	case scalarUint:
+		return strconv.FormatUint(s.uint, 10), nil
+	case scalarNull:
+		return "NULL", nil
+	case scalarOctets, scalarString:
+		return quoteString(string(s.bytes)), nil
+	case scalarDouble:
+		return strconv.FormatFloat(s.float, 'g', -1, 64), nil
+	case scalarFloat:
+		return strconv.FormatFloat(s.float, 'g', -1, 32), nil
+	case scalarBool:
+		if s.bool {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	default:
+		return "", newBadMessageError("invalid scalar type %d", s.typ)
+	}
+}
+
+// bindPlaceholders replaces each ? placeholder in |query| with the next of |args|. Question marks in strings, quoted
+// identifiers and comments are left alone.
+func bindPlaceholders(query string, args []scalar) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+
+	var sb strings.Builder
+	next := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(query) && query[end] != c {
+				if query[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			if end >= len(query) {
+				end = len(query) - 1
+			}
+			sb.WriteString(query[i : end+1])
+			i = end
+		case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "-- ")):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i - 1
+			}
+			sb.WriteString(query[i : i+end+1])
+			i += end
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i - 1
+			} else {
+				end += 3
+			}
+			sb.WriteString(query[i : i+end+1])
+			i += end
+		case c == '?':
+			if next >= len(args) {
+				return "", newArgumentsError()
+			}
+			lit, err := scalarLiteral(args[next])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(lit)
+			next++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	if next != len(args) {
+		return "", newArgumentsError()
+	}
+	return sb.String(), nil
+}
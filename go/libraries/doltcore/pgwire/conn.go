@@ -0,0 +1,748 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgwire
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/sirupsen/logrus"
+)
+
+// preparedStatement is a statement prepared by a Parse message.
+type preparedStatement struct {
+	// stmt is the statement in PostgreSQL's syntax, which is empty for an empty query
+	stmt      string
+	paramOIDs []int32
+}
+
+// portal is a prepared statement bound to its arguments by a Bind message. It starts running when it is first
+// described or executed, and its rows may be fetched by several Execute messages.
+type portal struct {
+	stmt string
+	args [][]byte
+	res  *result
+	done bool
+}
+
+// result is the result of a running statement.
+type result struct {
+	ctx  *sql.Context
+	sch  sql.Schema
+	iter sql.RowIter
+	// tag is the start of the CommandComplete tag of the statement
+	tag string
+	// rows counts the rows sent, or the rows affected by a statement which changes data
+	rows uint64
+}
+
+func (r *result) returnsRows() bool {
+	return len(r.sch) > 0 && !types.IsOkResultSchema(r.sch)
+}
+
+func (r *result) close() error {
+	if r.iter == nil {
+		return nil
+	}
+	iter := r.iter
+	r.iter = nil
+	return iter.Close(r.ctx)
+}
+
+// commandTag returns the CommandComplete tag for the result.
+func (r *result) commandTag() string {
+	switch r.tag {
+	case "SELECT", "INSERT 0", "UPDATE", "DELETE":
+		return r.tag + " " + strconv.FormatUint(r.rows, 10)
+	default:
+		return r.tag
+	}
+}
+
+// conn is a client connection. Responses are buffered until a message is handled.
+type conn struct {
+	srv *Server
+	nc  net.Conn
+	id  int32
+	r   *bufio.Reader
+	w   *bufio.Writer
+	lgr *logrus.Entry
+	// writeErr is the first error writing a response, which closes the connection
+	writeErr error
+
+	// encrypted is set once the client has negotiated SSL
+	encrypted bool
+
+	sess   sql.Session
+	params parameterValues
+	inTxn  bool
+
+	statements map[string]*preparedStatement
+	portals    map[string]*portal
+	// skipToSync is set after an error handling an extended query message, until the client sends Sync
+	skipToSync bool
+}
+
+func (c *conn) send(typ byte, payload []byte) {
+	if c.writeErr == nil {
+		c.writeErr = writeMessage(c.w, typ, payload)
+	}
+}
+
+func (c *conn) sendError(err error) {
+	c.send(serverErrorResponse, toError(err).encode())
+}
+
+func (c *conn) sendReadyForQuery() {
+	status := byte(txnIdle)
+	if c.inTxn {
+		status = txnBlock
+	}
+	c.send(serverReadyForQuery, []byte{status})
+}
+
+func (c *conn) sendParameterStatus(name, value string) {
+	c.send(serverParameterStatus, (&messageBuilder{}).string(name).string(value).b)
+}
+
+// fail sends |err| to the client before the connection is closed, and returns it.
+func (c *conn) fail(err error) error {
+	c.sendError(err)
+	if c.writeErr == nil {
+		c.writeErr = c.w.Flush()
+	}
+	return err
+}
+
+// startup handles the startup messages of the connection and authenticates the client.
+func (c *conn) startup(ctx context.Context) error {
+	code, msg, err := readStartupMessage(c.r)
+	for err == nil && (code == sslRequestCode || code == gssEncRequestCode) {
+		if code == sslRequestCode && c.srv.args.TLSConfig != nil && !c.encrypted {
+			err = c.startTLS(ctx)
+		} else if err = c.w.WriteByte('N'); err == nil {
+			// the client may continue without encryption
+			err = c.w.Flush()
+		}
+		if err == nil {
+			code, msg, err = readStartupMessage(c.r)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if code == cancelRequestCode {
+		// running queries can't be cancelled
+		return io.EOF
+	}
+	if code>>16 != protocolVersion3>>16 {
+		return c.fail(newUnsupportedError("unsupported frontend protocol %d.%d: server supports 3.0", code>>16, code&0xffff))
+	}
+
+	r := &messageReader{b: msg}
+	startParams := make(map[string]string)
+	for {
+		name := r.string()
+		if name == "" || r.err != nil {
+			break
+		}
+		startParams[name] = r.string()
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	user := startParams["user"]
+	if user == "" {
+		return c.fail(&Error{State: stateInvalidAuthorization, Msg: "no PostgreSQL user name specified in startup packet"})
+	}
+	for name, value := range startParams {
+		if _, _, ok := c.params.get(name); ok {
+			if _, err := c.params.set(name, value); err != nil {
+				return c.fail(err)
+			}
+		}
+	}
+
+	if !c.encrypted && !c.srv.args.AllowCleartextPasswords {
+		// the client's password would be sent unencrypted
+		return c.fail(&Error{State: stateInvalidAuthorization, Msg: "connection requires SSL: set allow_cleartext_passwords to authenticate without it"})
+	}
+	c.send(serverAuthentication, (&messageBuilder{}).int32(authCleartextPassword).b)
+	if c.writeErr == nil {
+		c.writeErr = c.w.Flush()
+	}
+	if c.writeErr != nil {
+		return c.writeErr
+	}
+	typ, msg, err := readMessage(c.r)
+	if err != nil {
+		return err
+	}
+	if typ != clientPassword {
+		return c.fail(newProtocolViolationError("expected password response, got message type %d", typ))
+	}
+	r = &messageReader{b: msg}
+	password := r.string()
+	if r.err != nil {
+		return r.err
+	}
+	if err = c.srv.args.Engine.ValidatePassword(user, password, c.nc.RemoteAddr()); err != nil {
+		c.lgr.Debugf("PostgreSQL protocol authentication failed for %s: %v", user, err)
+		return c.fail(&Error{State: stateInvalidPassword, Msg: fmt.Sprintf("password authentication failed for user %q", user)})
+	}
+	c.sess, err = c.srv.args.Engine.NewSession(ctx, user, c.nc.RemoteAddr(), startParams["database"])
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.send(serverAuthentication, (&messageBuilder{}).int32(authOk).b)
+	for _, p := range c.params.reported() {
+		c.sendParameterStatus(p[0], p[1])
+	}
+	var secret [4]byte
+	if _, err = rand.Read(secret[:]); err != nil {
+		return err
+	}
+	c.send(serverBackendKeyData, (&messageBuilder{}).int32(c.id).int32(int32(binary.BigEndian.Uint32(secret[:]))).b)
+	c.sendReadyForQuery()
+	if c.writeErr == nil {
+		c.writeErr = c.w.Flush()
+	}
+	return c.writeErr
+}
+
+// startTLS accepts the client's SSL request, and encrypts the rest of the connection.
+func (c *conn) startTLS(ctx context.Context) error {
+	if err := c.w.WriteByte('S'); err != nil {
+		return err
+	}
+	if err := c.w.Flush(); err != nil {
+		return err
+	}
+	if c.r.Buffered() > 0 {
+		// the client must wait for the response before starting the handshake
+		return newProtocolViolationError("received unencrypted data after SSL request")
+	}
+	tlsConn := tls.Server(c.nc, c.srv.args.TLSConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return err
+	}
+	c.nc = tlsConn
+	c.r = bufio.NewReader(tlsConn)
+	c.w = bufio.NewWriter(tlsConn)
+	c.encrypted = true
+	return nil
+}
+
+// dispatch handles a message of type |typ|.
+func (c *conn) dispatch(ctx context.Context, typ byte, msg []byte) {
+	if typ == clientQuery {
+		c.simpleQuery(ctx, msg)
+		return
+	}
+	if typ == clientSync {
+		c.skipToSync = false
+		c.closePortal("")
+		c.sendReadyForQuery()
+		return
+	}
+	if c.skipToSync {
+		return
+	}
+
+	var err error
+	switch typ {
+	case clientParse:
+		err = c.parse(msg)
+	case clientBind:
+		err = c.bind(msg)
+	case clientDescribe:
+		err = c.describe(ctx, msg)
+	case clientExecute:
+		err = c.execute(ctx, msg)
+	case clientClose:
+		err = c.close(msg)
+	case clientFlush:
+	default:
+		err = newProtocolViolationError("invalid frontend message type %d", typ)
+	}
+	if err != nil {
+		c.sendError(err)
+		c.skipToSync = true
+	}
+}
+
+// simpleQuery handles a Query message, which runs each of the statements of a query.
+func (c *conn) simpleQuery(ctx context.Context, msg []byte) {
+	defer c.sendReadyForQuery()
+	r := &messageReader{b: msg}
+	query := r.string()
+	if r.err != nil {
+		c.sendError(r.err)
+		return
+	}
+	stmts, err := splitStatements(query)
+	if err != nil {
+		c.sendError(err)
+		return
+	}
+	if len(stmts) == 0 {
+		c.send(serverEmptyQueryResponse, nil)
+		return
+	}
+
+	for _, stmt := range stmts {
+		res, err := c.start(ctx, stmt, nil)
+		if err == nil {
+			if res.returnsRows() {
+				c.sendRowDescription(res.sch)
+			}
+			_, err = c.sendRows(res, 0)
+		}
+		if err != nil {
+			c.sendError(err)
+			return
+		}
+	}
+}
+
+func (c *conn) parse(msg []byte) error {
+	r := &messageReader{b: msg}
+	name := r.string()
+	query := r.string()
+	paramOIDs := make([]int32, r.int16())
+	for i := range paramOIDs {
+		paramOIDs[i] = r.int32()
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if _, ok := c.statements[name]; ok && name != "" {
+		return &Error{State: stateDuplicatePreparedStmt, Msg: fmt.Sprintf("prepared statement %q already exists", name)}
+	}
+
+	stmts, err := splitStatements(query)
+	if err != nil {
+		return err
+	}
+	if len(stmts) > 1 {
+		return &Error{State: stateSyntaxError, Msg: "cannot insert multiple commands into a prepared statement"}
+	}
+	ps := &preparedStatement{paramOIDs: paramOIDs}
+	if len(stmts) == 1 {
+		ps.stmt = stmts[0]
+	}
+	n, err := countParameters(ps.stmt)
+	if err != nil {
+		return err
+	}
+	for len(ps.paramOIDs) < n {
+		ps.paramOIDs = append(ps.paramOIDs, 0)
+	}
+	c.statements[name] = ps
+	c.send(serverParseComplete, nil)
+	return nil
+}
+
+func (c *conn) bind(msg []byte) error {
+	r := &messageReader{b: msg}
+	portalName := r.string()
+	stmtName := r.string()
+	formats := make([]int16, r.int16())
+	for i := range formats {
+		formats[i] = r.int16()
+	}
+	args := make([][]byte, r.int16())
+	for i := range args {
+		args[i] = r.value()
+	}
+	resultFormats := make([]int16, r.int16())
+	for i := range resultFormats {
+		resultFormats[i] = r.int16()
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	ps, ok := c.statements[stmtName]
+	if !ok {
+		return &Error{State: stateUndefinedPreparedStmt, Msg: fmt.Sprintf("prepared statement %q does not exist", stmtName)}
+	}
+	for _, f := range formats {
+		if f != formatText {
+			return newUnsupportedError("binary format parameter values are not supported")
+		}
+	}
+	for _, f := range resultFormats {
+		if f != formatText {
+			return newUnsupportedError("binary format results are not supported")
+		}
+	}
+	if len(args) != len(ps.paramOIDs) {
+		return newProtocolViolationError("bind message supplies %d parameters, but prepared statement %q requires %d", len(args), stmtName, len(ps.paramOIDs))
+	}
+	if _, ok := c.portals[portalName]; ok {
+		if portalName != "" {
+			return &Error{State: stateDuplicateCursor, Msg: fmt.Sprintf("portal %q already exists", portalName)}
+		}
+		c.closePortal(portalName)
+	}
+
+	c.portals[portalName] = &portal{stmt: ps.stmt, args: args}
+	c.send(serverBindComplete, nil)
+	return nil
+}
+
+func (c *conn) describe(ctx context.Context, msg []byte) error {
+	r := &messageReader{b: msg}
+	kind := r.byte()
+	name := r.string()
+	if r.err != nil {
+		return r.err
+	}
+
+	switch kind {
+	case 'S':
+		ps, ok := c.statements[name]
+		if !ok {
+			return &Error{State: stateUndefinedPreparedStmt, Msg: fmt.Sprintf("prepared statement %q does not exist", name)}
+		}
+		m := (&messageBuilder{}).int16(int16(len(ps.paramOIDs)))
+		for _, oid := range ps.paramOIDs {
+			if oid == 0 {
+				oid = oidText
+			}
+			m.int32(oid)
+		}
+		c.send(serverParameterDescription, m.b)
+		// the columns of a statement's result aren't known until it runs, which happens when a portal is described
+		c.send(serverNoData, nil)
+		return nil
+	case 'P':
+		p, err := c.startPortal(ctx, name)
+		if err != nil {
+			return err
+		}
+		if p.res != nil && p.res.returnsRows() {
+			c.sendRowDescription(p.res.sch)
+		} else {
+			c.send(serverNoData, nil)
+		}
+		return nil
+	default:
+		return newProtocolViolationError("invalid DESCRIBE message subtype %d", kind)
+	}
+}
+
+func (c *conn) execute(ctx context.Context, msg []byte) error {
+	r := &messageReader{b: msg}
+	name := r.string()
+	maxRows := r.int32()
+	if r.err != nil {
+		return r.err
+	}
+
+	p, err := c.startPortal(ctx, name)
+	if err != nil {
+		return err
+	}
+	if p.res == nil {
+		c.send(serverEmptyQueryResponse, nil)
+		return nil
+	}
+	if p.done {
+		c.send(serverCommandComplete, (&messageBuilder{}).string(p.res.commandTag()).b)
+		return nil
+	}
+	suspended, err := c.sendRows(p.res, maxRows)
+	if err != nil {
+		return err
+	}
+	if suspended {
+		c.send(serverPortalSuspended, nil)
+	} else {
+		p.done = true
+	}
+	return nil
+}
+
+func (c *conn) close(msg []byte) error {
+	r := &messageReader{b: msg}
+	kind := r.byte()
+	name := r.string()
+	if r.err != nil {
+		return r.err
+	}
+	switch kind {
+	case 'S':
+		delete(c.statements, name)
+	case 'P':
+		c.closePortal(name)
+	default:
+		return newProtocolViolationError("invalid CLOSE message subtype %d", kind)
+	}
+	c.send(serverCloseComplete, nil)
+	return nil
+}
+
+// startPortal starts running the portal named |name|, unless it has already started.
+func (c *conn) startPortal(ctx context.Context, name string) (*portal, error) {
+	p, ok := c.portals[name]
+	if !ok {
+		return nil, &Error{State: stateUndefinedCursor, Msg: fmt.Sprintf("portal %q does not exist", name)}
+	}
+	if p.res != nil || p.stmt == "" {
+		return p, nil
+	}
+	res, err := c.start(ctx, p.stmt, p.args)
+	if err != nil {
+		return nil, err
+	}
+	p.res = res
+	return p, nil
+}
+
+func (c *conn) closePortal(name string) {
+	p, ok := c.portals[name]
+	if !ok {
+		return
+	}
+	delete(c.portals, name)
+	if p.res != nil {
+		if err := p.res.close(); err != nil {
+			c.lgr.Debugf("error closing PostgreSQL protocol portal: %v", err)
+		}
+	}
+}
+
+func (c *conn) closePortals() {
+	for name := range c.portals {
+		c.closePortal(name)
+	}
+}
+
+// start starts running |stmt|, a statement in PostgreSQL's syntax with |args| for its parameters. Statements which
+// set and show run time parameters are handled by the connection, and the rest are run by the engine.
+func (c *conn) start(ctx context.Context, stmt string, args [][]byte) (*result, error) {
+	words := keywords(stmt, 3)
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	var query string
+	var begins, ends bool
+	switch words[0] {
+	case "set":
+		if name, value, ok := parseSet(stmt); ok {
+			if _, _, known := c.params.get(name); known {
+				p, err := c.params.set(name, value)
+				if err != nil {
+					return nil, err
+				}
+				if p.reported {
+					v, _, _ := c.params.get(name)
+					c.sendParameterStatus(p.name, v)
+				}
+				return &result{tag: "SET"}, nil
+			}
+		}
+	case "show":
+		if name, ok := parseShow(stmt); ok {
+			if v, _, known := c.params.get(name); known {
+				return c.localResult(ctx, "SHOW", name, v)
+			}
+		}
+	case "reset":
+		if len(words) == 2 {
+			if words[1] == "all" {
+				c.params = newParameterValues()
+			} else if _, err := c.params.set(words[1], "default"); err != nil {
+				return nil, err
+			}
+			return &result{tag: "RESET"}, nil
+		}
+	case "discard":
+		if len(words) == 2 && words[1] == "all" {
+			c.params = newParameterValues()
+			return &result{tag: "DISCARD ALL"}, nil
+		}
+	case "deallocate":
+		// statement names are case sensitive, unlike the keywords
+		fields := strings.Fields(stmt)
+		name := strings.Trim(fields[len(fields)-1], `"`)
+		if strings.EqualFold(name, "all") {
+			for n := range c.statements {
+				if n != "" {
+					delete(c.statements, n)
+				}
+			}
+		} else {
+			delete(c.statements, name)
+		}
+		return &result{tag: "DEALLOCATE"}, nil
+	case "listen", "unlisten", "notify":
+		return nil, newUnsupportedError("%s is not supported", strings.ToUpper(words[0]))
+	case "begin", "start":
+		query, begins = "START TRANSACTION", true
+		if strings.Contains(normalize(stmt), "read only") {
+			query += " READ ONLY"
+		}
+	case "commit", "end":
+		query, ends = "COMMIT", true
+	case "rollback", "abort":
+		if len(words) < 2 || words[1] != "to" {
+			query, ends = "ROLLBACK", true
+		}
+	case "select":
+		switch normalize(stmt) {
+		case "select version()", "select pg_catalog.version()":
+			return c.localResult(ctx, "SELECT", "version", versionString)
+		case "select current_schema()", "select pg_catalog.current_schema()":
+			query = "SELECT database() AS current_schema"
+		}
+	}
+
+	sqlCtx, err := c.srv.args.Engine.NewContext(ctx, c.sess)
+	if err != nil {
+		return nil, err
+	}
+	if query == "" {
+		noBackslashEscapes := sql.LoadSqlMode(sqlCtx).ModeEnabled("NO_BACKSLASH_ESCAPES")
+		if query, err = rewriteStatement(stmt, args, noBackslashEscapes); err != nil {
+			return nil, err
+		}
+	}
+	sch, iter, err := c.srv.args.Engine.Query(sqlCtx, query)
+	if err != nil {
+		return nil, err
+	}
+	if begins {
+		c.inTxn = true
+	} else if ends {
+		c.inTxn = false
+	}
+	return &result{ctx: sqlCtx, sch: sch, iter: iter, tag: commandTag(words)}, nil
+}
+
+// localResult returns a result with a single text column named |column|, holding |value|.
+func (c *conn) localResult(ctx context.Context, tag, column, value string) (*result, error) {
+	sqlCtx, err := c.srv.args.Engine.NewContext(ctx, c.sess)
+	if err != nil {
+		return nil, err
+	}
+	sch := sql.Schema{{Name: column, Type: types.LongText, Nullable: true}}
+	return &result{ctx: sqlCtx, sch: sch, iter: sql.RowsToRowIter(sql.Row{value}), tag: tag}, nil
+}
+
+// commandTag returns the start of the CommandComplete tag of a statement starting with |words|.
+func commandTag(words []string) string {
+	switch words[0] {
+	case "select", "with", "values", "table", "describe", "desc", "explain":
+		return "SELECT"
+	case "show":
+		return "SHOW"
+	case "insert", "replace":
+		return "INSERT 0"
+	case "update":
+		return "UPDATE"
+	case "delete":
+		return "DELETE"
+	case "begin", "start":
+		return "BEGIN"
+	case "commit", "end":
+		return "COMMIT"
+	case "rollback", "abort":
+		return "ROLLBACK"
+	case "create", "drop", "alter":
+		if len(words) > 1 {
+			return strings.ToUpper(words[0] + " " + words[1])
+		}
+	}
+	return strings.ToUpper(words[0])
+}
+
+func (c *conn) sendRowDescription(sch sql.Schema) {
+	m := (&messageBuilder{}).int16(int16(len(sch)))
+	for _, col := range sch {
+		oid, size := typeOID(col.Type)
+		m.string(col.Name)
+		// the table and column numbers, which are only known for PostgreSQL's own tables
+		m.int32(0).int16(0)
+		m.int32(oid).int16(size)
+		// the type modifier, and the text format code
+		m.int32(-1).int16(formatText)
+	}
+	c.send(serverRowDescription, m.b)
+}
+
+// sendRows sends the rows of |res|, up to |maxRows| if it is positive, followed by CommandComplete once they have
+// all been sent. Returns true if rows remain. The result is closed unless rows remain, or there is an error.
+func (c *conn) sendRows(res *result, maxRows int32) (suspended bool, err error) {
+	if res.iter == nil {
+		c.send(serverCommandComplete, (&messageBuilder{}).string(res.commandTag()).b)
+		return false, nil
+	}
+	defer func() {
+		if !suspended {
+			if cerr := res.close(); err == nil {
+				err = cerr
+			}
+		}
+	}()
+
+	returnsRows := res.returnsRows()
+	var sent int32
+	for {
+		if returnsRows && maxRows > 0 && sent == maxRows {
+			return true, nil
+		}
+		row, err := res.iter.Next(res.ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return false, err
+		}
+
+		if !returnsRows {
+			if ok, isOk := row[0].(types.OkResult); isOk {
+				res.rows += ok.RowsAffected
+			}
+			continue
+		}
+		m := (&messageBuilder{}).int16(int16(len(res.sch)))
+		for i, col := range res.sch {
+			v, err := encodeValue(res.ctx, col.Type, row[i])
+			if err != nil {
+				return false, err
+			}
+			m.value(v, v == nil)
+		}
+		c.send(serverDataRow, m.b)
+		res.rows++
+		sent++
+	}
+	c.send(serverCommandComplete, (&messageBuilder{}).string(res.commandTag()).b)
+	return false, nil
+}
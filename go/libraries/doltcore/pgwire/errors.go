@@ -0,0 +1,129 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgwire
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// SQLSTATE codes of PostgreSQL errors.
+const (
+	stateCantChangeParameter     = "55P02"
+	stateCheckViolation          = "23514"
+	stateDivisionByZero          = "22012"
+	stateDuplicateCursor         = "42P03"
+	stateDuplicateDatabase       = "42P04"
+	stateDuplicatePreparedStmt   = "42P05"
+	stateDuplicateTable          = "42P07"
+	stateFeatureNotSupported     = "0A000"
+	stateForeignKeyViolation     = "23503"
+	stateInsufficientPrivilege   = "42501"
+	stateInternalError           = "XX000"
+	stateInvalidAuthorization    = "28000"
+	stateInvalidCatalogName      = "3D000"
+	stateInvalidParameterValue   = "22023"
+	stateInvalidPassword         = "28P01"
+	stateLockNotAvailable        = "55P03"
+	stateNotNullViolation        = "23502"
+	stateNumericValueOutOfRange  = "22003"
+	stateProtocolViolation       = "08P01"
+	stateReadOnlySQLTransaction  = "25006"
+	stateSerializationFailure    = "40001"
+	stateStringDataRightTruncate = "22001"
+	stateSyntaxError             = "42601"
+	stateUndefinedColumn         = "42703"
+	stateUndefinedCursor         = "34000"
+	stateUndefinedFunction       = "42883"
+	stateUndefinedObject         = "42704"
+	stateUndefinedParameter      = "42P02"
+	stateUndefinedPreparedStmt   = "26000"
+	stateUndefinedTable          = "42P01"
+	stateUniqueViolation         = "23505"
+)
+
+// mysqlErrorStates are the SQLSTATE codes of PostgreSQL errors equivalent to MySQL errors, by MySQL error number.
+// Other errors are sent with the SQLSTATE of the MySQL error, which is often the same as PostgreSQL's.
+var mysqlErrorStates = map[int]string{
+	1007: stateDuplicateDatabase,
+	1044: stateInsufficientPrivilege,
+	1045: stateInvalidAuthorization,
+	1048: stateNotNullViolation,
+	1049: stateInvalidCatalogName,
+	1050: stateDuplicateTable,
+	1054: stateUndefinedColumn,
+	1062: stateUniqueViolation,
+	1064: stateSyntaxError,
+	1142: stateInsufficientPrivilege,
+	1146: stateUndefinedTable,
+	1205: stateLockNotAvailable,
+	1213: stateSerializationFailure,
+	1235: stateFeatureNotSupported,
+	1264: stateNumericValueOutOfRange,
+	1305: stateUndefinedFunction,
+	1365: stateDivisionByZero,
+	1406: stateStringDataRightTruncate,
+	1451: stateForeignKeyViolation,
+	1452: stateForeignKeyViolation,
+	1792: stateReadOnlySQLTransaction,
+	3819: stateCheckViolation,
+}
+
+// Error is an error which is sent to the client in an ErrorResponse message, with its SQLSTATE.
+type Error struct {
+	State string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+func newProtocolViolationError(format string, args ...any) error {
+	return &Error{State: stateProtocolViolation, Msg: fmt.Sprintf(format, args...)}
+}
+
+func newUnsupportedError(format string, args ...any) error {
+	return &Error{State: stateFeatureNotSupported, Msg: fmt.Sprintf(format, args...)}
+}
+
+// toError returns |err| as an *Error, with the SQLSTATE of the equivalent PostgreSQL error.
+func toError(err error) *Error {
+	var pgErr *Error
+	if errors.As(err, &pgErr) {
+		return pgErr
+	}
+	sqlErr := sql.CastSQLError(err)
+	state, ok := mysqlErrorStates[sqlErr.Num]
+	if !ok {
+		state = sqlErr.State
+		if state == "" || state == "HY000" {
+			state = stateInternalError
+		}
+	}
+	return &Error{State: state, Msg: sqlErr.Message}
+}
+
+// encode returns the ErrorResponse message for |e|.
+func (e *Error) encode() []byte {
+	m := &messageBuilder{}
+	m.byte('S').string("ERROR")
+	m.byte('V').string("ERROR")
+	m.byte('C').string(e.State)
+	m.byte('M').string(e.Msg)
+	return m.byte(0).b
+}
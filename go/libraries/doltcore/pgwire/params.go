@@ -0,0 +1,123 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgwire
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// serverVersion is the PostgreSQL version reported to clients, which use it to decide which features they can use.
+const serverVersion = "15.0"
+
+// versionString is the result of version(), which some clients parse for the server's version.
+const versionString = "PostgreSQL " + serverVersion + " (Dolt)"
+
+// parameter is a PostgreSQL run time parameter. Clients set and show these, but they don't change how the engine
+// runs statements, so each connection only keeps their values.
+type parameter struct {
+	// name is the name reported to clients, which may have upper case letters
+	name string
+	def  string
+	// reported parameters are sent to the client when they change
+	reported bool
+	readOnly bool
+	// values are the only values the parameter may have, in lower case, if they are restricted
+	values []string
+}
+
+// parameters are the run time parameters each connection keeps, by their names in lower case.
+var parameters = map[string]parameter{
+	"application_name":              {name: "application_name", reported: true},
+	"bytea_output":                  {name: "bytea_output", def: "hex", values: []string{"hex"}},
+	"client_encoding":               {name: "client_encoding", def: "UTF8", reported: true, values: []string{"utf8", "utf-8", "unicode"}},
+	"client_min_messages":           {name: "client_min_messages", def: "notice"},
+	"datestyle":                     {name: "DateStyle", def: "ISO, MDY", reported: true},
+	"default_transaction_isolation": {name: "default_transaction_isolation", def: "repeatable read", readOnly: true},
+	"extra_float_digits":            {name: "extra_float_digits", def: "1"},
+	"integer_datetimes":             {name: "integer_datetimes", def: "on", reported: true, readOnly: true},
+	"intervalstyle":                 {name: "IntervalStyle", def: "postgres", reported: true},
+	"is_superuser":                  {name: "is_superuser", def: "off", reported: true, readOnly: true},
+	"search_path":                   {name: "search_path", def: `"$user", public`},
+	"server_encoding":               {name: "server_encoding", def: "UTF8", reported: true, readOnly: true},
+	"server_version":                {name: "server_version", def: serverVersion, reported: true, readOnly: true},
+	"standard_conforming_strings":   {name: "standard_conforming_strings", def: "on", reported: true, values: []string{"on"}},
+	"statement_timeout":             {name: "statement_timeout", def: "0"},
+	"timezone":                      {name: "TimeZone", def: "UTC", reported: true},
+	"transaction_isolation":         {name: "transaction_isolation", def: "repeatable read", readOnly: true},
+}
+
+// parameterValues are the values of the run time parameters of a connection.
+type parameterValues map[string]string
+
+func newParameterValues() parameterValues {
+	values := make(parameterValues, len(parameters))
+	for key, p := range parameters {
+		values[key] = p.def
+	}
+	return values
+}
+
+// set sets the parameter named |name| to |value|, returning the parameter. The value DEFAULT resets it.
+func (v parameterValues) set(name, value string) (parameter, error) {
+	key := strings.ToLower(name)
+	p, ok := parameters[key]
+	if !ok {
+		return parameter{}, &Error{State: stateUndefinedObject, Msg: fmt.Sprintf("unrecognized configuration parameter %q", name)}
+	}
+	if strings.EqualFold(value, "default") {
+		value = p.def
+	}
+	if p.readOnly {
+		if value == p.def {
+			return p, nil
+		}
+		return parameter{}, &Error{State: stateCantChangeParameter, Msg: fmt.Sprintf("parameter %q cannot be changed", p.name)}
+	}
+	if p.values != nil {
+		allowed := false
+		for _, allowedValue := range p.values {
+			allowed = allowed || strings.EqualFold(value, allowedValue)
+		}
+		if !allowed {
+			return parameter{}, newUnsupportedError("%s %q is not supported", p.name, value)
+		}
+		value = p.def
+	}
+	v[key] = value
+	return p, nil
+}
+
+// get returns the value of the parameter named |name|, and whether there is such a parameter.
+func (v parameterValues) get(name string) (string, parameter, bool) {
+	key := strings.ToLower(name)
+	p, ok := parameters[key]
+	return v[key], p, ok
+}
+
+// reported returns the names and values of the parameters which are reported to clients, in order of their names.
+func (v parameterValues) reported() [][2]string {
+	var res [][2]string
+	for key, p := range parameters {
+		if p.reported {
+			res = append(res, [2]string{p.name, v[key]})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][0] < res[j][0]
+	})
+	return res
+}
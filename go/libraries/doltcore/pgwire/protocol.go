@@ -0,0 +1,241 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Every message after the startup message is a type byte followed by the message's length, including the length
+// itself, as a big endian int32. The startup message has no type byte.
+
+// maxMessageSize is the size of the largest message a client may send.
+const maxMessageSize = 64 << 20
+
+// Codes of the startup message, which are in place of the protocol version of the other startup requests.
+const (
+	protocolVersion3  = 3 << 16
+	cancelRequestCode = 1234<<16 | 5678
+	sslRequestCode    = 1234<<16 | 5679
+	gssEncRequestCode = 1234<<16 | 5680
+)
+
+// Types of the messages sent by clients.
+const (
+	clientBind      = 'B'
+	clientClose     = 'C'
+	clientDescribe  = 'D'
+	clientExecute   = 'E'
+	clientFlush     = 'H'
+	clientParse     = 'P'
+	clientQuery     = 'Q'
+	clientSync      = 'S'
+	clientTerminate = 'X'
+	clientPassword  = 'p'
+)
+
+// Types of the messages sent by the server.
+const (
+	serverAuthentication       = 'R'
+	serverBackendKeyData       = 'K'
+	serverBindComplete         = '2'
+	serverCloseComplete        = '3'
+	serverCommandComplete      = 'C'
+	serverDataRow              = 'D'
+	serverEmptyQueryResponse   = 'I'
+	serverErrorResponse        = 'E'
+	serverNoData               = 'n'
+	serverParameterDescription = 't'
+	serverParameterStatus      = 'S'
+	serverParseComplete        = '1'
+	serverPortalSuspended      = 's'
+	serverReadyForQuery        = 'Z'
+	serverRowDescription       = 'T'
+)
+
+// Authentication requests of AuthenticationRequest messages.
+const (
+	authOk                = 0
+	authCleartextPassword = 3
+)
+
+// Transaction statuses of ReadyForQuery messages.
+const (
+	txnIdle  = 'I'
+	txnBlock = 'T'
+)
+
+// formatText is the format code of values sent as text. Values sent in the binary format aren't supported.
+const formatText = 0
+
+// readStartupMessage reads a startup message, returning its code and the rest of the message.
+func readStartupMessage(r io.Reader) (uint32, []byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:4])
+	if size < 8 || size > maxMessageSize {
+		return 0, nil, fmt.Errorf("invalid startup message length %d", size)
+	}
+	msg := make([]byte, size-8)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return 0, nil, err
+	}
+	return binary.BigEndian.Uint32(header[4:]), msg, nil
+}
+
+// readMessage reads a message, returning its type and contents.
+func readMessage(r io.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size < 4 || size > maxMessageSize {
+		return 0, nil, fmt.Errorf("invalid message length %d", size)
+	}
+	msg := make([]byte, size-4)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return 0, nil, err
+	}
+	return header[0], msg, nil
+}
+
+// writeMessage writes a message of type |typ|.
+func writeMessage(w io.Writer, typ byte, payload []byte) error {
+	var header [5]byte
+	header[0] = typ
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)+4))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// messageBuilder builds the contents of a message.
+type messageBuilder struct {
+	b []byte
+}
+
+func (m *messageBuilder) int16(v int16) *messageBuilder {
+	m.b = binary.BigEndian.AppendUint16(m.b, uint16(v))
+	return m
+}
+
+func (m *messageBuilder) int32(v int32) *messageBuilder {
+	m.b = binary.BigEndian.AppendUint32(m.b, uint32(v))
+	return m
+}
+
+func (m *messageBuilder) byte(v byte) *messageBuilder {
+	m.b = append(m.b, v)
+	return m
+}
+
+// string appends |v| as a NUL terminated string.
+func (m *messageBuilder) string(v string) *messageBuilder {
+	m.b = append(m.b, v...)
+	m.b = append(m.b, 0)
+	return m
+}
+
+// value appends |v| preceded by its length, or a length of -1 if |v| is NULL.
+func (m *messageBuilder) value(v []byte, null bool) *messageBuilder {
+	if null {
+		return m.int32(-1)
+	}
+	m.int32(int32(len(v)))
+	m.b = append(m.b, v...)
+	return m
+}
+
+// messageReader reads the contents of a message. Reading past the end of the message sets err, after which every
+// read returns a zero value.
+type messageReader struct {
+	b   []byte
+	err error
+}
+
+func (m *messageReader) fail() {
+	if m.err == nil {
+		m.err = newProtocolViolationError("invalid message format")
+	}
+	m.b = nil
+}
+
+func (m *messageReader) int16() int16 {
+	if len(m.b) < 2 {
+		m.fail()
+		return 0
+	}
+	v := int16(binary.BigEndian.Uint16(m.b))
+	m.b = m.b[2:]
+	return v
+}
+
+func (m *messageReader) int32() int32 {
+	if len(m.b) < 4 {
+		m.fail()
+		return 0
+	}
+	v := int32(binary.BigEndian.Uint32(m.b))
+	m.b = m.b[4:]
+	return v
+}
+
+func (m *messageReader) byte() byte {
+	if len(m.b) < 1 {
+		m.fail()
+		return 0
+	}
+	v := m.b[0]
+	m.b = m.b[1:]
+	return v
+}
+
+// string reads a NUL terminated string.
+func (m *messageReader) string() string {
+	i := bytes.IndexByte(m.b, 0)
+	if i < 0 {
+		m.fail()
+		return ""
+	}
+	v := string(m.b[:i])
+	m.b = m.b[i+1:]
+	return v
+}
+
+// value reads a value preceded by its length. NULL values are returned as nil.
+func (m *messageReader) value() []byte {
+	n := m.int32()
+	if n < 0 {
+		return nil
+	}
+	if len(m.b) < int(n) {
+		m.fail()
+		return nil
+	}
+	v := m.b[:n:n]
+	if v == nil {
+		v = []byte{}
+	}
+	m.b = m.b[n:]
+	return v
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgwire
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPort is the port PostgreSQL servers listen on.
+const DefaultPort = 5432
+
+// Engine authenticates the clients of a Server and runs their statements.
+type Engine interface {
+	// ValidatePassword returns an error unless |password| is the password of |user|.
+	ValidatePassword(user, password string, addr net.Addr) error
+	// NewSession returns a new session of |user| connected from |addr|. If |database| isn't empty, it is the
+	// session's current database.
+	NewSession(ctx context.Context, user string, addr net.Addr, database string) (sql.Session, error)
+	// CloseSession ends |sess| once its client has disconnected, rolling back its transaction and releasing its locks.
+	CloseSession(ctx context.Context, sess sql.Session)
+	// NewContext returns a context for running a statement in |sess|.
+	NewContext(ctx context.Context, sess sql.Session) (*sql.Context, error)
+	// Query runs |query|.
+	Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error)
+}
+
+// ServerArgs configure a Server.
+type ServerArgs struct {
+	Logger *logrus.Entry
+	Engine Engine
+	// TLSConfig encrypts the connections of clients which request SSL, if it's set.
+	TLSConfig *tls.Config
+	// AllowCleartextPasswords allows clients to authenticate on unencrypted connections. Clients always send their
+	// password in cleartext, so it is disabled by default, and clients must connect with SSL.
+	AllowCleartextPasswords bool
+}
+
+// Server serves the PostgreSQL frontend/backend protocol, so that tools which only have PostgreSQL connectors can
+// read and write the databases of a MySQL engine. Clients authenticate with their MySQL user's password, which
+// they send in cleartext, so they must connect with SSL unless cleartext passwords are allowed. Queries are
+// translated to MySQL lexically, so only the subset of PostgreSQL's syntax which MySQL shares is understood. Values
+// are always sent as text.
+type Server struct {
+	args ServerArgs
+
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+	lastConn int32
+}
+
+// NewServer returns a new Server for |args|.
+func NewServer(args ServerArgs) *Server {
+	if args.Logger == nil {
+		args.Logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+	return &Server{args: args, conns: make(map[net.Conn]struct{})}
+}
+
+// Serve accepts connections on |lis| until the server is closed. It always returns a non-nil error, which is
+// net.ErrClosed if the server was closed.
+func (s *Server) Serve(lis net.Listener) error {
+	defer lis.Close()
+	for {
+		nc, err := lis.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return net.ErrClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			nc.Close()
+			return net.ErrClosed
+		}
+		s.conns[nc] = struct{}{}
+		s.lastConn++
+		id := s.lastConn
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			s.handle(nc, id)
+		}()
+	}
+}
+
+// Close closes every open connection and waits for them to finish. Listeners passed to Serve must be closed by the
+// caller.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for nc := range s.conns {
+		nc.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Server) handle(nc net.Conn, id int32) {
+	c := &conn{
+		srv:        s,
+		nc:         nc,
+		id:         id,
+		r:          bufio.NewReader(nc),
+		w:          bufio.NewWriter(nc),
+		lgr:        s.args.Logger.WithField("remote_addr", nc.RemoteAddr().String()),
+		params:     newParameterValues(),
+		statements: make(map[string]*preparedStatement),
+		portals:    make(map[string]*portal),
+	}
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, nc)
+		s.mu.Unlock()
+		c.closePortals()
+		if c.sess != nil {
+			s.args.Engine.CloseSession(context.Background(), c.sess)
+		}
+		nc.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.startup(ctx); err != nil {
+		if !errors.Is(err, io.EOF) && !s.isClosed() {
+			c.lgr.Debugf("error starting PostgreSQL protocol connection: %v", err)
+		}
+		return
+	}
+	for {
+		typ, msg, err := readMessage(c.r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !s.isClosed() {
+				c.lgr.Debugf("error reading PostgreSQL protocol message: %v", err)
+			}
+			return
+		}
+		if typ == clientTerminate {
+			return
+		}
+		c.dispatch(ctx, typ, msg)
+		if c.writeErr == nil {
+			c.writeErr = c.w.Flush()
+		}
+		if c.writeErr != nil {
+			c.lgr.Debugf("error writing PostgreSQL protocol message: %v", c.writeErr)
+			return
+		}
+	}
+}
@@ -0,0 +1,428 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgwire
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Queries are translated from PostgreSQL to MySQL lexically: quoted identifiers, string literals and parameters are
+// rewritten in MySQL's syntax, and everything else is passed to the engine as it is. PostgreSQL syntax without a
+// MySQL equivalent, such as :: casts, is rejected with an error instead of being run with a different meaning.
+
+var stringLiteralEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `''`,
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+)
+
+// quoteString returns |s| as a MySQL string literal. Quotes are always doubled, and backslashes are only escaped
+// when |noBackslashEscapes| is false, so that the literal has the value |s| whether or not the session's sql_mode
+// includes NO_BACKSLASH_ESCAPES.
+func quoteString(s string, noBackslashEscapes bool) string {
+	if noBackslashEscapes {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	return "'" + stringLiteralEscaper.Replace(s) + "'"
+}
+
+// unescapeString returns the value of the contents of a PostgreSQL escape string, such as E'a\tb'.
+func unescapeString(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 == len(s) {
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		switch c = s[i]; c {
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'x':
+			// one or two hex digits
+			end := i + 1
+			for end < len(s) && end < i+3 && isHexDigit(s[end]) {
+				end++
+			}
+			if end == i+1 {
+				sb.WriteByte(c)
+				continue
+			}
+			n, _ := strconv.ParseUint(s[i+1:end], 16, 8)
+			sb.WriteByte(byte(n))
+			i = end - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			// one to three octal digits
+			end := i + 1
+			for end < len(s) && end < i+3 && s[end] >= '0' && s[end] <= '7' {
+				end++
+			}
+			n, _ := strconv.ParseUint(s[i:end], 8, 16)
+			sb.WriteByte(byte(n))
+			i = end - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// quoteIdentifier returns |s| as a MySQL quoted identifier.
+func quoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// tokenKind is the kind of a token of a query which isn't passed to the engine as it is.
+type tokenKind int
+
+const (
+	tokenOther tokenKind = iota
+	tokenString
+	tokenEscapeString
+	tokenDollarString
+	tokenIdentifier
+	tokenParameter
+	tokenComment
+	tokenCast
+	tokenSemicolon
+)
+
+// token is a token of a query, from query[start:end].
+type token struct {
+	kind       tokenKind
+	start, end int
+	// value is the contents of strings and quoted identifiers, and the number of parameters
+	value string
+}
+
+// scanQuery calls |cb| with each token of |query|. Characters which aren't part of a string, quoted identifier,
+// parameter, comment, cast or semicolon are passed one at a time as tokenOther.
+func scanQuery(query string, cb func(t token) error) error {
+	for i := 0; i < len(query); {
+		t, err := nextToken(query, i)
+		if err != nil {
+			return err
+		}
+		if err = cb(t); err != nil {
+			return err
+		}
+		i = t.end
+	}
+	return nil
+}
+
+func nextToken(query string, i int) (token, error) {
+	c := query[i]
+	prevIsIdentifier := i > 0 && isIdentifierChar(query[i-1])
+	switch {
+	case c == '\'':
+		end, value, err := scanQuoted(query, i+1, '\'', false)
+		return token{kind: tokenString, start: i, end: end, value: value}, err
+	case (c == 'E' || c == 'e') && !prevIsIdentifier && i+1 < len(query) && query[i+1] == '\'':
+		end, value, err := scanQuoted(query, i+2, '\'', true)
+		return token{kind: tokenEscapeString, start: i, end: end, value: value}, err
+	case c == '"':
+		end, value, err := scanQuoted(query, i+1, '"', false)
+		if err == nil && value == "" {
+			err = &Error{State: stateSyntaxError, Msg: "zero-length delimited identifier"}
+		}
+		return token{kind: tokenIdentifier, start: i, end: end, value: value}, err
+	case c == '$' && !prevIsIdentifier && i+1 < len(query) && isDigit(query[i+1]):
+		end := i + 1
+		for end < len(query) && isDigit(query[end]) {
+			end++
+		}
+		return token{kind: tokenParameter, start: i, end: end, value: query[i+1 : end]}, nil
+	case c == '$' && !prevIsIdentifier:
+		// a dollar quoted string, such as $$text$$ or $tag$text$tag$
+		end := i + 1
+		for end < len(query) && query[end] != '$' && isIdentifierChar(query[end]) {
+			end++
+		}
+		if end >= len(query) || query[end] != '$' {
+			return token{kind: tokenOther, start: i, end: i + 1}, nil
+		}
+		delim := query[i : end+1]
+		closing := strings.Index(query[end+1:], delim)
+		if closing < 0 {
+			return token{}, &Error{State: stateSyntaxError, Msg: "unterminated dollar-quoted string"}
+		}
+		value := query[end+1 : end+1+closing]
+		return token{kind: tokenDollarString, start: i, end: end + 1 + closing + len(delim), value: value}, nil
+	case c == '-' && strings.HasPrefix(query[i:], "--"):
+		end := strings.IndexByte(query[i:], '\n')
+		if end < 0 {
+			end = len(query)
+		} else {
+			end += i + 1
+		}
+		return token{kind: tokenComment, start: i, end: end}, nil
+	case c == '/' && strings.HasPrefix(query[i:], "/*"):
+		end := strings.Index(query[i+2:], "*/")
+		if end < 0 {
+			return token{}, &Error{State: stateSyntaxError, Msg: "unterminated /* comment"}
+		}
+		return token{kind: tokenComment, start: i, end: i + 2 + end + 2}, nil
+	case c == ':' && strings.HasPrefix(query[i:], "::"):
+		return token{kind: tokenCast, start: i, end: i + 2}, nil
+	case c == ';':
+		return token{kind: tokenSemicolon, start: i, end: i + 1}, nil
+	default:
+		return token{kind: tokenOther, start: i, end: i + 1}, nil
+	}
+}
+
+// scanQuoted scans the rest of a string or quoted identifier starting at |i|, which is ended by |quote|. A doubled
+// quote stands for itself. If |backslashEscapes| is true, backslashes escape the next character, and are kept in the
+// returned value. Returns the end of the token and its value.
+func scanQuoted(query string, i int, quote byte, backslashEscapes bool) (int, string, error) {
+	var sb strings.Builder
+	for i < len(query) {
+		c := query[i]
+		switch {
+		case c == '\\' && backslashEscapes && i+1 < len(query):
+			sb.WriteByte(c)
+			sb.WriteByte(query[i+1])
+			i += 2
+			continue
+		case c == quote && i+1 < len(query) && query[i+1] == quote:
+			sb.WriteByte(quote)
+			i += 2
+			continue
+		case c == quote:
+			return i + 1, sb.String(), nil
+		}
+		sb.WriteByte(c)
+		i++
+	}
+	if quote == '"' {
+		return 0, "", &Error{State: stateSyntaxError, Msg: "unterminated quoted identifier"}
+	}
+	return 0, "", &Error{State: stateSyntaxError, Msg: "unterminated quoted string"}
+}
+
+// splitStatements splits |query| into the statements separated by its semicolons. Statements which are empty, or
+// only hold comments, are dropped.
+func splitStatements(query string) ([]string, error) {
+	var stmts []string
+	start := 0
+	empty := true
+	err := scanQuery(query, func(t token) error {
+		switch t.kind {
+		case tokenSemicolon:
+			if !empty {
+				stmts = append(stmts, strings.TrimSpace(query[start:t.start]))
+			}
+			start, empty = t.end, true
+		case tokenComment:
+		case tokenOther:
+			if c := query[t.start]; c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				empty = false
+			}
+		default:
+			empty = false
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !empty {
+		stmts = append(stmts, strings.TrimSpace(query[start:]))
+	}
+	return stmts, nil
+}
+
+// countParameters returns the number of parameters of |query|, which is the largest $n it refers to.
+func countParameters(query string) (int, error) {
+	n := 0
+	err := scanQuery(query, func(t token) error {
+		if t.kind == tokenParameter {
+			pos, err := strconv.Atoi(t.value)
+			if err != nil || pos < 1 {
+				return &Error{State: stateSyntaxError, Msg: fmt.Sprintf("invalid parameter $%s", t.value)}
+			}
+			if pos > n {
+				n = pos
+			}
+		}
+		return nil
+	})
+	return n, err
+}
+
+// rewriteStatement returns |stmt| in MySQL's syntax, with its parameters replaced by |args|. NULL arguments are nil.
+// |noBackslashEscapes| is whether the session's sql_mode includes NO_BACKSLASH_ESCAPES, which changes how strings
+// are quoted.
+func rewriteStatement(stmt string, args [][]byte, noBackslashEscapes bool) (string, error) {
+	var sb strings.Builder
+	err := scanQuery(stmt, func(t token) error {
+		switch t.kind {
+		case tokenString, tokenDollarString:
+			sb.WriteString(quoteString(t.value, noBackslashEscapes))
+		case tokenEscapeString:
+			sb.WriteString(quoteString(unescapeString(t.value), noBackslashEscapes))
+		case tokenIdentifier:
+			sb.WriteString(quoteIdentifier(t.value))
+		case tokenParameter:
+			pos, err := strconv.Atoi(t.value)
+			if err != nil || pos < 1 || pos > len(args) {
+				return &Error{State: stateUndefinedParameter, Msg: fmt.Sprintf("there is no parameter $%s", t.value)}
+			}
+			if arg := args[pos-1]; arg == nil {
+				sb.WriteString("NULL")
+			} else {
+				sb.WriteString(quoteString(string(arg), noBackslashEscapes))
+			}
+		case tokenCast:
+			return newUnsupportedError("type casts with :: are not supported, use CAST(value AS type) instead")
+		default:
+			sb.WriteString(stmt[t.start:t.end])
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// keywords returns the first |n| words of |stmt|, in lower case, skipping comments and opening parentheses.
+func keywords(stmt string, n int) []string {
+	var words []string
+	for i := 0; i < len(stmt) && len(words) < n; {
+		c := stmt[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '(':
+			i++
+		case strings.HasPrefix(stmt[i:], "--") || strings.HasPrefix(stmt[i:], "/*"):
+			t, err := nextToken(stmt, i)
+			if err != nil {
+				return words
+			}
+			i = t.end
+		case isIdentifierChar(c) && !isDigit(c):
+			end := i
+			for end < len(stmt) && isIdentifierChar(stmt[end]) {
+				end++
+			}
+			words = append(words, strings.ToLower(stmt[i:end]))
+			i = end
+		default:
+			return words
+		}
+	}
+	return words
+}
+
+// normalize returns |stmt| in lower case with its runs of whitespace collapsed, for comparing it to the fixed
+// queries clients send.
+func normalize(stmt string) string {
+	return strings.Join(strings.Fields(strings.ToLower(stmt)), " ")
+}
+
+// parseSet parses a SET statement of a parameter, returning the parameter's name, in lower case, and its value.
+// Returns false if |stmt| doesn't set a single parameter with PostgreSQL's syntax.
+func parseSet(stmt string) (string, string, bool) {
+	rest := strings.TrimSpace(stmt)
+	if len(rest) < 4 || !strings.EqualFold(rest[:4], "set ") {
+		return "", "", false
+	}
+	rest = strings.TrimSpace(rest[4:])
+	for _, scope := range []string{"session ", "local "} {
+		if len(rest) > len(scope) && strings.EqualFold(rest[:len(scope)], scope) {
+			rest = strings.TrimSpace(rest[len(scope):])
+		}
+	}
+	if len(rest) > 10 && strings.EqualFold(rest[:10], "time zone ") {
+		return "timezone", parseSetValue(rest[10:]), true
+	}
+
+	end := 0
+	for end < len(rest) && (isIdentifierChar(rest[end]) || rest[end] == '.') {
+		end++
+	}
+	if end == 0 {
+		return "", "", false
+	}
+	name := strings.ToLower(rest[:end])
+	rest = strings.TrimSpace(rest[end:])
+	switch {
+	case strings.HasPrefix(rest, "="):
+		rest = rest[1:]
+	case len(rest) > 3 && strings.EqualFold(rest[:3], "to "):
+		rest = rest[3:]
+	default:
+		return "", "", false
+	}
+	return name, parseSetValue(rest), true
+}
+
+// parseSetValue returns the value of a SET statement, without the quotes of a single string.
+func parseSetValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && value[0] == '\'' {
+		end, s, err := scanQuoted(value, 1, '\'', false)
+		if err == nil && end == len(value) {
+			return s
+		}
+	}
+	return value
+}
+
+// parseShow returns the name of the parameter shown by a SHOW statement, in lower case, or false if |stmt| isn't
+// a SHOW statement of a single parameter.
+func parseShow(stmt string) (string, bool) {
+	words := strings.Fields(strings.ToLower(stmt))
+	if len(words) < 2 || words[0] != "show" {
+		return "", false
+	}
+	if len(words) == 4 && words[1] == "transaction" && words[2] == "isolation" && words[3] == "level" {
+		return "transaction_isolation", true
+	}
+	if len(words) == 3 && words[1] == "time" && words[2] == "zone" {
+		return "timezone", true
+	}
+	if len(words) != 2 {
+		return "", false
+	}
+	return words[1], true
+}
@@ -0,0 +1,207 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgwire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", nil},
+		{" ; -- comment\n;", nil},
+		{"select 1", []string{"select 1"}},
+		{"select 1; select 2;", []string{"select 1", "select 2"}},
+		{"select ';'; select \";\"", []string{"select ';'", "select \";\""}},
+		{"select $$a;b$$; select 'it''s;'", []string{"select $$a;b$$", "select 'it''s;'"}},
+		{"select 1 /* ; */; select E'\\';'", []string{"select 1 /* ; */", "select E'\\';'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			stmts, err := splitStatements(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stmts)
+		})
+	}
+
+	_, err := splitStatements("select 'unterminated")
+	require.Error(t, err)
+	_, err = splitStatements("select 1 /* unterminated")
+	require.Error(t, err)
+}
+
+func TestRewriteStatement(t *testing.T) {
+	tests := []struct {
+		stmt               string
+		args               [][]byte
+		noBackslashEscapes bool
+		expected           string
+		err                bool
+	}{
+		{
+			stmt:     `select "Col", "a""b" from "My Table"`,
+			expected: "select `Col`, `a\"b` from `My Table`",
+		},
+		{
+			stmt:     `select 'C:\dir', 'it''s', E'tab\tit\'s', $$dollar 'quoted'$$, $tag$x$tag$`,
+			expected: "select 'C:\\\\dir', 'it''s', 'tab\tit''s', 'dollar ''quoted''', 'x'",
+		},
+		{
+			stmt:               `select 'C:\dir', 'it''s', E'tab\tit\'s\x41\101', $$dollar 'quoted'$$`,
+			noBackslashEscapes: true,
+			expected:           "select 'C:\\dir', 'it''s', 'tab\tit''sAA', 'dollar ''quoted'''",
+		},
+		{
+			stmt:     "select * from t where a = $1 and b = $2 and c = '$1' -- $2",
+			args:     [][]byte{[]byte("it's"), nil},
+			expected: "select * from t where a = 'it''s' and b = NULL and c = '$1' -- $2",
+		},
+		{
+			// a parameter can't end its string early, whether or not backslashes are escape characters
+			stmt:     "select * from t where a = $1",
+			args:     [][]byte{[]byte(`\' or 1=1 -- `)},
+			expected: `select * from t where a = '\\'' or 1=1 -- '`,
+		},
+		{
+			stmt:               "select * from t where a = $1",
+			args:               [][]byte{[]byte(`\' or 1=1 -- `)},
+			noBackslashEscapes: true,
+			expected:           `select * from t where a = '\'' or 1=1 -- '`,
+		},
+		{
+			stmt:     "select price$1 from t",
+			expected: "select price$1 from t",
+		},
+		{
+			stmt: "select $2",
+			args: [][]byte{[]byte("1")},
+			err:  true,
+		},
+		{
+			stmt: "select '1'::int",
+			err:  true,
+		},
+		{
+			stmt: `select "" from t`,
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stmt, func(t *testing.T) {
+			query, err := rewriteStatement(tt.stmt, tt.args, tt.noBackslashEscapes)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestCountParameters(t *testing.T) {
+	n, err := countParameters("select $1, $3, '$4', $2")
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	n, err = countParameters("select 1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	_, err = countParameters("select $0")
+	require.Error(t, err)
+}
+
+func TestKeywords(t *testing.T) {
+	assert.Equal(t, []string{"select", "a"}, keywords("  /* hint */ (SELECT a", 2))
+	assert.Equal(t, []string{"create", "table", "t"}, keywords("-- comment\nCreate Table t (a int)", 3))
+	assert.Empty(t, keywords("", 3))
+	assert.Equal(t, "CREATE TABLE", commandTag(keywords("CREATE TABLE t (a int)", 3)))
+	assert.Equal(t, "INSERT 0", commandTag(keywords("insert into t values (1)", 3)))
+}
+
+func TestParseSetAndShow(t *testing.T) {
+	tests := []struct {
+		stmt  string
+		name  string
+		value string
+		ok    bool
+	}{
+		{"SET extra_float_digits = 3", "extra_float_digits", "3", true},
+		{"set application_name to 'my app'", "application_name", "my app", true},
+		{"SET SESSION DateStyle TO 'ISO'", "datestyle", "ISO", true},
+		{"SET LOCAL search_path = public, \"$user\"", "search_path", "public, \"$user\"", true},
+		{"SET TIME ZONE 'UTC'", "timezone", "UTC", true},
+		{"SET @x = 1", "", "", false},
+		{"SET NAMES utf8mb4", "", "", false},
+		{"SELECT 1", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stmt, func(t *testing.T) {
+			name, value, ok := parseSet(tt.stmt)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+
+	name, ok := parseShow("SHOW TRANSACTION ISOLATION LEVEL")
+	assert.True(t, ok)
+	assert.Equal(t, "transaction_isolation", name)
+	name, ok = parseShow("show server_version")
+	assert.True(t, ok)
+	assert.Equal(t, "server_version", name)
+	_, ok = parseShow("show full tables")
+	assert.False(t, ok)
+}
+
+func TestParameterValues(t *testing.T) {
+	values := newParameterValues()
+
+	_, err := values.set("Application_Name", "psql")
+	require.NoError(t, err)
+	v, p, ok := values.get("application_name")
+	assert.True(t, ok)
+	assert.True(t, p.reported)
+	assert.Equal(t, "psql", v)
+
+	_, err = values.set("client_encoding", "utf-8")
+	require.NoError(t, err)
+	v, _, _ = values.get("client_encoding")
+	assert.Equal(t, "UTF8", v)
+
+	_, err = values.set("client_encoding", "LATIN1")
+	require.Error(t, err)
+	_, err = values.set("server_version", "16.0")
+	require.Error(t, err)
+	_, err = values.set("no_such_parameter", "1")
+	require.Error(t, err)
+
+	_, err = values.set("application_name", "DEFAULT")
+	require.NoError(t, err)
+	v, _, _ = values.get("application_name")
+	assert.Equal(t, "", v)
+
+	reported := values.reported()
+	assert.Contains(t, reported, [2]string{"server_version", serverVersion})
+	assert.Contains(t, reported, [2]string{"TimeZone", "UTC"})
+	assert.NotContains(t, reported, [2]string{"search_path", `"$user", public`})
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgwire
+
+import (
+	"encoding/hex"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+)
+
+// OIDs of the PostgreSQL types which values are sent as.
+const (
+	oidBytea     = 17
+	oidInt8      = 20
+	oidInt2      = 21
+	oidInt4      = 23
+	oidText      = 25
+	oidJSON      = 114
+	oidFloat4    = 700
+	oidFloat8    = 701
+	oidVarchar   = 1043
+	oidDate      = 1082
+	oidTime      = 1083
+	oidTimestamp = 1114
+	oidNumeric   = 1700
+)
+
+// typeOID returns the OID of the PostgreSQL type that values of |t| are sent as, and the size of the type, which
+// is -1 for types with values of varying size.
+func typeOID(t sql.Type) (int32, int16) {
+	switch t.Type() {
+	case sqltypes.Int8, sqltypes.Uint8, sqltypes.Int16, sqltypes.Year:
+		return oidInt2, 2
+	case sqltypes.Uint16, sqltypes.Int24, sqltypes.Uint24, sqltypes.Int32:
+		return oidInt4, 4
+	case sqltypes.Uint32, sqltypes.Int64:
+		return oidInt8, 8
+	case sqltypes.Uint64, sqltypes.Decimal:
+		return oidNumeric, -1
+	case sqltypes.Float32:
+		return oidFloat4, 4
+	case sqltypes.Float64:
+		return oidFloat8, 8
+	case sqltypes.Date:
+		return oidDate, 4
+	case sqltypes.Time:
+		return oidTime, 8
+	case sqltypes.Datetime, sqltypes.Timestamp:
+		return oidTimestamp, 8
+	case sqltypes.TypeJSON:
+		return oidJSON, -1
+	case sqltypes.VarChar, sqltypes.Char:
+		return oidVarchar, -1
+	case sqltypes.Blob, sqltypes.Binary, sqltypes.VarBinary, sqltypes.Bit, sqltypes.Geometry:
+		return oidBytea, -1
+	default:
+		return oidText, -1
+	}
+}
+
+// encodeValue returns the text format of |v|, a value of |t|, or nil if it is NULL. Binary values are encoded in
+// bytea's hex format.
+func encodeValue(ctx *sql.Context, t sql.Type, v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := t.SQL(ctx, nil, v)
+	if err != nil {
+		return nil, err
+	}
+	raw := res.Raw()
+	if oid, _ := typeOID(t); oid == oidBytea {
+		b := make([]byte, 2+hex.EncodedLen(len(raw)))
+		b[0], b[1] = '\\', 'x'
+		hex.Encode(b[2:], raw)
+		return b, nil
+	}
+	if raw == nil {
+		raw = []byte{}
+	}
+	return raw, nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porcelain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+// MergeOptions control the behavior of Merge and Pull.
+type MergeOptions struct {
+	Author
+	// Message is the message of the merge commit. A default message is used if it is empty.
+	Message string
+	// NoFF creates a merge commit even when the merge could be resolved as a fast-forward.
+	NoFF bool
+	// TempDir is used for any temporary files needed by the merge.
+	TempDir string
+}
+
+// MergeResult describes the outcome of a merge.
+type MergeResult struct {
+	// Commit is the new HEAD of the branch, unless the merge stopped on conflicts.
+	Commit *doltdb.Commit
+	// UpToDate is true if the branch already contained the merged commit, in which case nothing was changed.
+	UpToDate bool
+	// FastForward is true if the branch was fast-forwarded to the merged commit.
+	FastForward bool
+	// HasConflicts is true if the merge produced conflicts or constraint violations. The merge is left in progress
+	// in the branch's working set, where the conflicts can be resolved and the merge concluded with Commit.
+	HasConflicts bool
+	// Stats holds the per table statistics of a three-way merge.
+	Stats map[string]*merge.MergeStats
+}
+
+// Merge merges |commitSpec|, which may be any commit spec such as a branch name, into |branch|. The working set of
+// |branch| must not have uncommitted changes.
+func Merge(ctx context.Context, ddb *doltdb.DoltDB, branch, commitSpec string, opts MergeOptions) (MergeResult, error) {
+	b, err := resolveBranch(ctx, ddb, branch)
+	if err != nil {
+		return MergeResult{}, err
+	}
+
+	cs, err := doltdb.NewCommitSpec(commitSpec)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	optCmt, err := ddb.Resolve(ctx, cs, b.ref)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return MergeResult{}, doltdb.ErrGhostCommitEncountered
+	}
+
+	return mergeCommit(ctx, ddb, b, cm, commitSpec, opts)
+}
+
+func mergeCommit(ctx context.Context, ddb *doltdb.DoltDB, b branchState, cm *doltdb.Commit, commitSpec string, opts MergeOptions) (MergeResult, error) {
+	if b.ws.MergeActive() {
+		return MergeResult{}, doltdb.ErrMergeActive
+	}
+	if clean, err := b.isClean(); err != nil {
+		return MergeResult{}, err
+	} else if !clean {
+		return MergeResult{}, ErrUncommittedChanges
+	}
+
+	headHash, err := b.head.HashOf()
+	if err != nil {
+		return MergeResult{}, err
+	}
+	mergeHash, err := cm.HashOf()
+	if err != nil {
+		return MergeResult{}, err
+	}
+	optAnc, err := doltdb.GetCommitAncestor(ctx, b.head, cm)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	anc, ok := optAnc.ToCommit()
+	if !ok {
+		return MergeResult{}, doltdb.ErrGhostCommitEncountered
+	}
+	ancHash, err := anc.HashOf()
+	if err != nil {
+		return MergeResult{}, err
+	}
+
+	if ancHash == mergeHash {
+		return MergeResult{Commit: b.head, UpToDate: true}, nil
+	}
+
+	mergeRoot, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return MergeResult{}, err
+	}
+
+	canFF := ancHash == headHash
+	if canFF && !opts.NoFF {
+		if err = ddb.FastForward(ctx, b.ref, cm); err != nil {
+			return MergeResult{}, err
+		}
+		ws := b.ws.WithWorkingRoot(mergeRoot).WithStagedRoot(mergeRoot)
+		err = ddb.UpdateWorkingSet(ctx, b.wsRef, ws, b.wsHash, opts.workingSetMeta("fast-forward merge"), nil)
+		if err != nil {
+			return MergeResult{}, err
+		}
+		return MergeResult{Commit: cm, FastForward: true}, nil
+	}
+
+	var result *merge.Result
+	if canFF {
+		result = &merge.Result{Root: mergeRoot, Stats: make(map[string]*merge.MergeStats)}
+	} else {
+		result, err = merge.MergeCommits(sql.NewContext(ctx), b.head, cm, editor.Options{Tempdir: opts.TempDir})
+		if err != nil {
+			return MergeResult{}, err
+		}
+	}
+
+	if result.HasMergeArtifacts() {
+		ws := b.ws.StartMerge(cm, commitSpec)
+		ws = ws.WithUnmergableTables(merge.SchemaConflictTableNames(result.SchemaConflicts))
+		ws = ws.WithWorkingRoot(result.Root)
+		err = ddb.UpdateWorkingSet(ctx, b.wsRef, ws, b.wsHash, opts.workingSetMeta("merge with conflicts"), nil)
+		if err != nil {
+			return MergeResult{}, err
+		}
+		return MergeResult{HasConflicts: true, Stats: result.Stats}, nil
+	}
+
+	msg := opts.Message
+	if msg == "" {
+		msg = fmt.Sprintf("Merge %s into %s", commitSpec, b.ref.GetPath())
+	}
+	meta, err := opts.commitMeta(msg)
+	if err != nil {
+		return MergeResult{}, err
+	}
+
+	roots := doltdb.Roots{Head: b.roots.Head, Staged: result.Root, Working: result.Root}
+	pending, err := ddb.NewPendingCommit(ctx, roots, []*doltdb.Commit{cm}, meta)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	ws := b.ws.WithStagedRoot(pending.Roots.Staged).WithWorkingRoot(pending.Roots.Working)
+	commit, err := ddb.CommitWithWorkingSet(ctx, b.ref, b.wsRef, pending, ws, b.wsHash, opts.workingSetMeta("merge"), nil)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	return MergeResult{Commit: commit, Stats: result.Stats}, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package porcelain is a high level Go API for versioning a Dolt database without going through the CLI or a SQL
+// engine. Every function operates directly on a |doltdb.DoltDB| handle and names the branch it acts on, so there is
+// no notion of a current branch: each branch has its own working set, exactly as it does for sql-server sessions.
+//
+// Updates to a branch's working set are made with an optimistic lock on the working set read at the start of the
+// operation. Concurrent writers to the same branch get datas.ErrOptimisticLockFailed rather than silently losing
+// changes.
+package porcelain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ErrUncommittedChanges is returned by operations that require a branch's working set to match its HEAD.
+var ErrUncommittedChanges = errors.New("branch has uncommitted changes")
+
+// Author identifies the person or process making a change.
+type Author struct {
+	Name  string
+	Email string
+	// Date is the author date of any commit created. The current time is used if it is zero.
+	Date time.Time
+}
+
+func (a Author) commitMeta(message string) (*datas.CommitMeta, error) {
+	date := a.Date
+	if date.IsZero() {
+		date = datas.CommitterDate()
+	}
+	return datas.NewCommitMetaWithUserTS(a.Name, a.Email, message, date)
+}
+
+func (a Author) workingSetMeta(description string) *datas.WorkingSetMeta {
+	return &datas.WorkingSetMeta{
+		Name:        a.Name,
+		Email:       a.Email,
+		Timestamp:   uint64(time.Now().Unix()),
+		Description: description,
+	}
+}
+
+// CommitOptions control the behavior of Commit.
+type CommitOptions struct {
+	Author
+	Message string
+	// All stages every modified, added and deleted table before committing.
+	All bool
+	// AllowEmpty permits a commit with no staged changes.
+	AllowEmpty bool
+}
+
+// Branch creates a new branch named |name| at |startPoint|, which may be any commit spec, eg. a branch name or commit
+// hash. If |force| is true an existing branch of the same name is moved to |startPoint|.
+func Branch(ctx context.Context, ddb *doltdb.DoltDB, name, startPoint string, force bool) error {
+	return actions.CreateBranchOnDB(ctx, ddb, name, startPoint, force, nil, nil)
+}
+
+// Checkout returns the HEAD, STAGED and WORKING roots of |branch|. Changes made to the returned roots are not visible
+// to anyone else until they are written with SetRoots.
+func Checkout(ctx context.Context, ddb *doltdb.DoltDB, branch string) (doltdb.Roots, error) {
+	b, err := resolveBranch(ctx, ddb, branch)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	return b.roots, nil
+}
+
+// SetRoots writes the STAGED and WORKING roots of |roots| to the working set of |branch|. The HEAD root is ignored.
+func SetRoots(ctx context.Context, ddb *doltdb.DoltDB, branch string, roots doltdb.Roots, author Author) error {
+	b, err := resolveBranch(ctx, ddb, branch)
+	if err != nil {
+		return err
+	}
+	ws := b.ws.WithWorkingRoot(roots.Working).WithStagedRoot(roots.Staged)
+	return ddb.UpdateWorkingSet(ctx, b.wsRef, ws, b.wsHash, author.workingSetMeta("updated working set"), nil)
+}
+
+// Commit commits the staged changes of |branch| and returns the new commit. If a merge is in progress on the branch,
+// the commit concludes it.
+func Commit(ctx context.Context, ddb *doltdb.DoltDB, branch string, opts CommitOptions) (*doltdb.Commit, error) {
+	b, err := resolveBranch(ctx, ddb, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := b.roots
+	if opts.All {
+		roots, err = actions.StageAllTables(ctx, roots, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var mergeParents []*doltdb.Commit
+	if b.ws.MergeActive() {
+		mergeParents = []*doltdb.Commit{b.ws.MergeState().Commit()}
+	}
+
+	date := opts.Date
+	if date.IsZero() {
+		date = datas.CommitterDate()
+	}
+	pending, err := actions.GetCommitStaged(ctx, roots, b.ws, mergeParents, ddb, actions.CommitStagedProps{
+		Message:    opts.Message,
+		Date:       date,
+		AllowEmpty: opts.AllowEmpty,
+		Name:       opts.Name,
+		Email:      opts.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ws := b.ws.WithStagedRoot(pending.Roots.Staged).WithWorkingRoot(pending.Roots.Working).ClearMerge()
+	return ddb.CommitWithWorkingSet(ctx, b.ref, b.wsRef, pending, ws, b.wsHash, opts.workingSetMeta("commit"), nil)
+}
+
+// branchState is the state of a branch read at the start of an operation.
+type branchState struct {
+	ref    ref.BranchRef
+	head   *doltdb.Commit
+	wsRef  ref.WorkingSetRef
+	ws     *doltdb.WorkingSet
+	wsHash hash.Hash
+	roots  doltdb.Roots
+}
+
+func resolveBranch(ctx context.Context, ddb *doltdb.DoltDB, branch string) (branchState, error) {
+	br := ref.NewBranchRef(branch)
+	head, err := ddb.ResolveCommitRef(ctx, br)
+	if err != nil {
+		return branchState{}, err
+	}
+	headRoot, err := head.GetRootValue(ctx)
+	if err != nil {
+		return branchState{}, err
+	}
+
+	wsRef, err := ref.WorkingSetRefForHead(br)
+	if err != nil {
+		return branchState{}, err
+	}
+
+	var wsHash hash.Hash
+	ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+	if errors.Is(err, doltdb.ErrWorkingSetNotFound) {
+		ws = doltdb.EmptyWorkingSet(wsRef).WithWorkingRoot(headRoot).WithStagedRoot(headRoot)
+	} else if err != nil {
+		return branchState{}, err
+	} else {
+		wsHash, err = ws.HashOf()
+		if err != nil {
+			return branchState{}, err
+		}
+	}
+
+	return branchState{
+		ref:    br,
+		head:   head,
+		wsRef:  wsRef,
+		ws:     ws,
+		wsHash: wsHash,
+		roots: doltdb.Roots{
+			Head:    headRoot,
+			Staged:  ws.StagedRoot(),
+			Working: ws.WorkingRoot(),
+		},
+	}, nil
+}
+
+// isClean returns whether the working set of the branch matches its HEAD.
+func (b branchState) isClean() (bool, error) {
+	dirty, _, _, err := actions.RootHasUncommittedChanges(b.roots)
+	return !dirty, err
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porcelain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/porcelain"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+var author = porcelain.Author{Name: "Bill Billerson", Email: "bill@billerson.com"}
+
+func TestPorcelain(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+	ddb := dEnv.DoltDB
+
+	writeBranch(t, dEnv, "main", "create table t (pk int primary key, v int);\ninsert into t values (1, 1);")
+
+	t.Run("commit", func(t *testing.T) {
+		_, err := porcelain.Commit(ctx, ddb, "main", porcelain.CommitOptions{Author: author, Message: "nothing staged"})
+		assert.True(t, actions.IsNothingStaged(err))
+
+		cm := commit(t, ddb, "main", "create t")
+		head, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef("main"))
+		require.NoError(t, err)
+		assert.Equal(t, mustHash(t, cm), mustHash(t, head))
+		assert.Equal(t, 1, rowCount(t, ddb, "main"))
+	})
+
+	require.NoError(t, porcelain.Branch(ctx, ddb, "feature", "main", false))
+	assert.ErrorIs(t, porcelain.Branch(ctx, ddb, "feature", "main", false), actions.ErrAlreadyExists)
+
+	t.Run("fast-forward merge", func(t *testing.T) {
+		writeBranch(t, dEnv, "feature", "insert into t values (2, 2);")
+		cm := commit(t, ddb, "feature", "insert 2")
+
+		res, err := porcelain.Merge(ctx, ddb, "main", "feature", porcelain.MergeOptions{Author: author})
+		require.NoError(t, err)
+		assert.True(t, res.FastForward)
+		assert.Equal(t, mustHash(t, cm), mustHash(t, res.Commit))
+		assert.Equal(t, 2, rowCount(t, ddb, "main"))
+
+		res, err = porcelain.Merge(ctx, ddb, "main", "feature", porcelain.MergeOptions{Author: author})
+		require.NoError(t, err)
+		assert.True(t, res.UpToDate)
+	})
+
+	t.Run("three-way merge", func(t *testing.T) {
+		writeBranch(t, dEnv, "main", "insert into t values (3, 3);")
+		commit(t, ddb, "main", "insert 3")
+		writeBranch(t, dEnv, "feature", "insert into t values (4, 4);")
+		commit(t, ddb, "feature", "insert 4")
+
+		res, err := porcelain.Merge(ctx, ddb, "main", "feature", porcelain.MergeOptions{Author: author})
+		require.NoError(t, err)
+		assert.False(t, res.FastForward)
+		assert.False(t, res.HasConflicts)
+		assert.Equal(t, 2, res.Commit.NumParents())
+		assert.Equal(t, 4, rowCount(t, ddb, "main"))
+	})
+
+	t.Run("uncommitted changes", func(t *testing.T) {
+		writeBranch(t, dEnv, "feature", "insert into t values (5, 5);")
+		_, err := porcelain.Merge(ctx, ddb, "feature", "main", porcelain.MergeOptions{Author: author})
+		assert.ErrorIs(t, err, porcelain.ErrUncommittedChanges)
+		commit(t, ddb, "feature", "insert 5")
+	})
+
+	t.Run("merge conflicts", func(t *testing.T) {
+		writeBranch(t, dEnv, "main", "update t set v = 10 where pk = 1;")
+		commit(t, ddb, "main", "update 1 on main")
+		writeBranch(t, dEnv, "feature", "update t set v = 20 where pk = 1;")
+		commit(t, ddb, "feature", "update 1 on feature")
+
+		res, err := porcelain.Merge(ctx, ddb, "main", "feature", porcelain.MergeOptions{Author: author})
+		require.NoError(t, err)
+		assert.True(t, res.HasConflicts)
+		assert.Nil(t, res.Commit)
+
+		wsRef, err := ref.WorkingSetRefForHead(ref.NewBranchRef("main"))
+		require.NoError(t, err)
+		ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+		require.NoError(t, err)
+		assert.True(t, ws.MergeActive())
+
+		_, err = porcelain.Commit(ctx, ddb, "main", porcelain.CommitOptions{Author: author, Message: "conclude", All: true})
+		assert.True(t, actions.IsTblInConflict(err))
+	})
+
+	t.Run("push and pull", func(t *testing.T) {
+		remoteEnv := dtestutils.CreateTestEnv()
+		defer remoteEnv.DoltDB.Close()
+		remote := porcelain.Remote{Name: "origin", DB: remoteEnv.DoltDB}
+		tmpDir := t.TempDir()
+
+		err := porcelain.Push(ctx, ddb, remote, "feature", porcelain.PushOptions{TempDir: tmpDir})
+		require.NoError(t, err)
+		local, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef("feature"))
+		require.NoError(t, err)
+		pushed, err := remote.DB.ResolveCommitRef(ctx, ref.NewBranchRef("feature"))
+		require.NoError(t, err)
+		assert.Equal(t, mustHash(t, local), mustHash(t, pushed))
+		tracking, err := ddb.ResolveCommitRef(ctx, ref.NewRemoteRef("origin", "feature"))
+		require.NoError(t, err)
+		assert.Equal(t, mustHash(t, local), mustHash(t, tracking))
+
+		_, err = porcelain.Commit(ctx, remote.DB, "feature", porcelain.CommitOptions{
+			Author:     author,
+			Message:    "remote commit",
+			AllowEmpty: true,
+		})
+		require.NoError(t, err)
+
+		res, err := porcelain.Pull(ctx, ddb, remote, "feature", porcelain.MergeOptions{Author: author, TempDir: tmpDir})
+		require.NoError(t, err)
+		assert.True(t, res.FastForward)
+		remoteHead, err := remote.DB.ResolveCommitRef(ctx, ref.NewBranchRef("feature"))
+		require.NoError(t, err)
+		assert.Equal(t, mustHash(t, remoteHead), mustHash(t, res.Commit))
+	})
+}
+
+// writeBranch runs |query| against the working set of |branch|. ExecuteSql only runs against the working set of the
+// checked out branch, so the statements are run there and their results moved to |branch|.
+func writeBranch(t *testing.T, dEnv *env.DoltEnv, branch, query string) {
+	ctx := context.Background()
+	ddb := dEnv.DoltDB
+
+	mainRoots, err := porcelain.Checkout(ctx, ddb, "main")
+	require.NoError(t, err)
+	roots, err := porcelain.Checkout(ctx, ddb, branch)
+	require.NoError(t, err)
+
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, roots.Working))
+	roots.Working, err = sqle.ExecuteSql(dEnv, roots.Working, query)
+	require.NoError(t, err)
+
+	if branch != "main" {
+		require.NoError(t, porcelain.SetRoots(ctx, ddb, "main", mainRoots, author))
+	}
+	require.NoError(t, porcelain.SetRoots(ctx, ddb, branch, roots, author))
+}
+
+func commit(t *testing.T, ddb *doltdb.DoltDB, branch, msg string) *doltdb.Commit {
+	cm, err := porcelain.Commit(context.Background(), ddb, branch, porcelain.CommitOptions{
+		Author:  author,
+		Message: msg,
+		All:     true,
+	})
+	require.NoError(t, err)
+	return cm
+}
+
+func rowCount(t *testing.T, ddb *doltdb.DoltDB, branch string) int {
+	ctx := context.Background()
+	roots, err := porcelain.Checkout(ctx, ddb, branch)
+	require.NoError(t, err)
+	tbl, ok, err := roots.Working.GetTable(ctx, doltdb.TableName{Name: "t"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	rows, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+	cnt, err := rows.Count()
+	require.NoError(t, err)
+	return int(cnt)
+}
+
+func mustHash(t *testing.T, cm *doltdb.Commit) string {
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	return h.String()
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package porcelain
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas/pull"
+)
+
+// Remote is a remote database and the name its branches are tracked under locally. The database is usually loaded
+// with env.Remote.GetRemoteDB.
+type Remote struct {
+	Name string
+	DB   *doltdb.DoltDB
+}
+
+// PushOptions control the behavior of Push.
+type PushOptions struct {
+	// Force overwrites the remote branch even if the push is not a fast-forward.
+	Force bool
+	// TempDir is used for table files that are written while pushing.
+	TempDir string
+}
+
+// Push pushes |branch| to the branch of the same name on |remote| and updates the local remote tracking branch.
+func Push(ctx context.Context, ddb *doltdb.DoltDB, remote Remote, branch string, opts PushOptions) error {
+	br := ref.NewBranchRef(branch)
+	cm, err := ddb.ResolveCommitRef(ctx, br)
+	if err != nil {
+		return err
+	}
+
+	mode := ref.FastForwardOnly
+	if opts.Force {
+		mode = ref.ForceUpdate
+	}
+	return actions.Push(ctx, opts.TempDir, mode, br, ref.NewRemoteRef(remote.Name, branch), ddb, remote.DB, cm, nil)
+}
+
+// Pull fetches the branch of the same name as |branch| from |remote|, updates the local remote tracking branch, and
+// merges it into |branch|.
+func Pull(ctx context.Context, ddb *doltdb.DoltDB, remote Remote, branch string, opts MergeOptions) (MergeResult, error) {
+	cm, err := remote.DB.ResolveCommitRef(ctx, ref.NewBranchRef(branch))
+	if err != nil {
+		return MergeResult{}, err
+	}
+
+	err = actions.FetchCommit(ctx, opts.TempDir, remote.DB, ddb, cm, nil)
+	if err != nil && err != pull.ErrDBUpToDate {
+		return MergeResult{}, err
+	}
+
+	remoteRef := ref.NewRemoteRef(remote.Name, branch)
+	if err = ddb.SetHeadToCommit(ctx, remoteRef, cm); err != nil {
+		return MergeResult{}, err
+	}
+
+	b, err := resolveBranch(ctx, ddb, branch)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	return mergeCommit(ctx, ddb, b, cm, remoteRef.GetPath(), opts)
+}
@@ -61,6 +61,21 @@ func StopAtCommit(stopCommit *doltdb.Commit) NeedsRebaseFn {
 	}
 }
 
+// RewrittenCommit is a commit rewritten by filter-branch, and the commit which replaced it.
+type RewrittenCommit struct {
+	Original  hash.Hash
+	Rewritten hash.Hash
+}
+
+// FilterBranchResult describes the history rewritten by filter-branch.
+type FilterBranchResult struct {
+	// RewrittenRefs are the refs whose heads were rewritten.
+	RewrittenRefs []ref.DoltRef
+	// RewrittenCommits are the commits which were rewritten, with parents before their children. Commits which were
+	// replayed without changes are left out.
+	RewrittenCommits []RewrittenCommit
+}
+
 // RootReplayer is something that takes a root value and rebases it with changes.
 type RootReplayer interface {
 	ReplayRoot(ctx context.Context, root, parentRoot, rebasedParentRoot doltdb.RootValue) (rebaseRoot doltdb.RootValue, err error)
@@ -72,44 +87,44 @@ type CommitReplayer interface {
 }
 
 // AllBranchesAndTags rewrites the history of all branches and tags in the repo using the |replay| function.
-func AllBranchesAndTags(ctx context.Context, dEnv *env.DoltEnv, applyUncommitted bool, commitReplayer CommitReplayer, rootReplayer RootReplayer, nerf NeedsRebaseFn) error {
+func AllBranchesAndTags(ctx context.Context, dEnv *env.DoltEnv, applyUncommitted bool, commitReplayer CommitReplayer, rootReplayer RootReplayer, nerf NeedsRebaseFn) (*FilterBranchResult, error) {
 	branches, err := dEnv.DoltDB.GetBranches(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	tags, err := dEnv.DoltDB.GetTags(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	return rebaseRefs(ctx, dEnv.DbData(), applyUncommitted, commitReplayer, rootReplayer, nerf, append(branches, tags...)...)
 }
 
 // AllBranches rewrites the history of all branches in the repo using the |replay| function.
-func AllBranches(ctx context.Context, dEnv *env.DoltEnv, applyUncommitted bool, commitReplayer CommitReplayer, rootReplayer RootReplayer, nerf NeedsRebaseFn) error {
+func AllBranches(ctx context.Context, dEnv *env.DoltEnv, applyUncommitted bool, commitReplayer CommitReplayer, rootReplayer RootReplayer, nerf NeedsRebaseFn) (*FilterBranchResult, error) {
 	branches, err := dEnv.DoltDB.GetBranches(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	return rebaseRefs(ctx, dEnv.DbData(), applyUncommitted, commitReplayer, rootReplayer, nerf, branches...)
 }
 
 // CurrentBranch rewrites the history of the current branch using the |replay| function.
-func CurrentBranch(ctx context.Context, dEnv *env.DoltEnv, applyUncommitted bool, commitReplayer CommitReplayer, rootReplayer RootReplayer, nerf NeedsRebaseFn) error {
+func CurrentBranch(ctx context.Context, dEnv *env.DoltEnv, applyUncommitted bool, commitReplayer CommitReplayer, rootReplayer RootReplayer, nerf NeedsRebaseFn) (*FilterBranchResult, error) {
 	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
 	if err != nil {
-		return nil
+		return nil, err
 	}
 	return rebaseRefs(ctx, dEnv.DbData(), applyUncommitted, commitReplayer, rootReplayer, nerf, headRef)
 }
 
-func rebaseRefs(ctx context.Context, dbData env.DbData, applyUncommitted bool, commitReplayer CommitReplayer, rootReplayer RootReplayer, nerf NeedsRebaseFn, refs ...ref.DoltRef) error {
+func rebaseRefs(ctx context.Context, dbData env.DbData, applyUncommitted bool, commitReplayer CommitReplayer, rootReplayer RootReplayer, nerf NeedsRebaseFn, refs ...ref.DoltRef) (*FilterBranchResult, error) {
 	ddb := dbData.Ddb
 	heads := make([]*doltdb.Commit, len(refs))
 	for i, dRef := range refs {
 		var err error
 		heads[i], err = ddb.ResolveCommitRef(ctx, dRef)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -119,38 +134,38 @@ func rebaseRefs(ctx context.Context, dbData env.DbData, applyUncommitted bool, c
 		case ref.BranchRef:
 			hRootVal, err := heads[i].GetRootValue(ctx)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			hHash, err := hRootVal.HashOf()
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			wsRef, err := ref.WorkingSetRefForHead(dRef)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			wHash, err := ws.WorkingRoot().HashOf()
 			if err != nil {
-				return err
+				return nil, err
 			}
 			sHash, err := ws.StagedRoot().HashOf()
 			if err != nil {
-				return err
+				return nil, err
 			}
 			if !applyUncommitted && (!hHash.Equal(wHash) || !hHash.Equal(sHash)) {
-				return fmt.Errorf("local changes detected on branch %s, clear uncommitted changes (dolt stash dolt commit) before using filter-branch, or use --apply-to-uncommitted", dRef.String())
+				return nil, fmt.Errorf("local changes detected on branch %s, clear uncommitted changes (dolt stash dolt commit) before using filter-branch, or use --apply-to-uncommitted", dRef.String())
 			}
 
 			if !hHash.Equal(wHash) {
 				var newWRoot doltdb.RootValue
 				newWRoot, err = rootReplayer.ReplayRoot(ctx, ws.WorkingRoot(), nil, nil)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				ws = ws.WithWorkingRoot(newWRoot)
 			} else {
@@ -160,7 +175,7 @@ func rebaseRefs(ctx context.Context, dbData env.DbData, applyUncommitted bool, c
 				var newSRoot doltdb.RootValue
 				newSRoot, err = rootReplayer.ReplayRoot(ctx, ws.StagedRoot(), nil, nil)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				ws = ws.WithStagedRoot(newSRoot)
 			} else {
@@ -172,18 +187,20 @@ func rebaseRefs(ctx context.Context, dbData env.DbData, applyUncommitted bool, c
 		}
 	}
 
-	newHeads, err := rebase(ctx, ddb, commitReplayer, nerf, heads...)
+	newHeads, rewrittenCommits, err := rebase(ctx, ddb, commitReplayer, nerf, heads...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	result := &FilterBranchResult{RewrittenCommits: rewrittenCommits}
+
 	for i, r := range refs {
 		switch dRef := r.(type) {
 		case ref.BranchRef:
 			newHead := newHeads[i]
 			err = ddb.NewBranchAtCommit(ctx, dRef, newHead, nil)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			newWorkingSet := newWorkingSets[i]
@@ -194,13 +211,13 @@ func rebaseRefs(ctx context.Context, dbData env.DbData, applyUncommitted bool, c
 			var wsRef ref.WorkingSetRef
 			wsRef, err = ref.WorkingSetRefForHead(dRef)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			var ws *doltdb.WorkingSet
 			ws, err = ddb.ResolveWorkingSet(ctx, wsRef)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			if newWorkingSet.WorkingRoot() != nil {
@@ -213,7 +230,7 @@ func rebaseRefs(ctx context.Context, dbData env.DbData, applyUncommitted bool, c
 			var currWsHash hash.Hash
 			currWsHash, err = ws.HashOf()
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			err = ddb.UpdateWorkingSet(ctx, wsRef, ws, currWsHash, ws.Meta(), nil)
@@ -221,39 +238,51 @@ func rebaseRefs(ctx context.Context, dbData env.DbData, applyUncommitted bool, c
 			// rewrite tag with new commit
 			var tag *doltdb.Tag
 			if tag, err = ddb.ResolveTag(ctx, dRef); err != nil {
-				return err
+				return nil, err
 			}
 			if err = ddb.DeleteTag(ctx, dRef); err != nil {
-				return err
+				return nil, err
 			}
 			err = ddb.NewTagAtCommit(ctx, dRef, newHeads[i], tag.Meta)
 		default:
-			return fmt.Errorf("cannot rebase ref: %s", ref.String(dRef))
+			return nil, fmt.Errorf("cannot rebase ref: %s", ref.String(dRef))
 		}
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		var oldHeadHash, newHeadHash hash.Hash
+		if oldHeadHash, err = heads[i].HashOf(); err != nil {
+			return nil, err
+		}
+		if newHeadHash, err = newHeads[i].HashOf(); err != nil {
+			return nil, err
+		}
+		if oldHeadHash != newHeadHash {
+			result.RewrittenRefs = append(result.RewrittenRefs, r)
 		}
 	}
-	return nil
+	return result, nil
 }
 
-func rebase(ctx context.Context, ddb *doltdb.DoltDB, commitReplayer CommitReplayer, nerf NeedsRebaseFn, origins ...*doltdb.Commit) ([]*doltdb.Commit, error) {
+func rebase(ctx context.Context, ddb *doltdb.DoltDB, commitReplayer CommitReplayer, nerf NeedsRebaseFn, origins ...*doltdb.Commit) ([]*doltdb.Commit, []RewrittenCommit, error) {
 	var rebasedCommits []*doltdb.Commit
+	var rewritten []RewrittenCommit
 	vs := make(visitedSet)
 	for _, cm := range origins {
-		rc, err := rebaseRecursive(ctx, ddb, commitReplayer, nerf, vs, cm)
+		rc, err := rebaseRecursive(ctx, ddb, commitReplayer, nerf, vs, &rewritten, cm)
 
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		rebasedCommits = append(rebasedCommits, rc)
 	}
 
-	return rebasedCommits, nil
+	return rebasedCommits, rewritten, nil
 }
 
-func rebaseRecursive(ctx context.Context, ddb *doltdb.DoltDB, commitReplayer CommitReplayer, nerf NeedsRebaseFn, vs visitedSet, commit *doltdb.Commit) (*doltdb.Commit, error) {
+func rebaseRecursive(ctx context.Context, ddb *doltdb.DoltDB, commitReplayer CommitReplayer, nerf NeedsRebaseFn, vs visitedSet, rewritten *[]RewrittenCommit, commit *doltdb.Commit) (*doltdb.Commit, error) {
 	commitHash, err := commit.HashOf()
 	if err != nil {
 		return nil, err
@@ -294,7 +323,7 @@ func rebaseRecursive(ctx context.Context, ddb *doltdb.DoltDB, commitReplayer Com
 
 	var allRebasedParents []*doltdb.Commit
 	for _, p := range allParents {
-		rp, err := rebaseRecursive(ctx, ddb, commitReplayer, nerf, vs, p)
+		rp, err := rebaseRecursive(ctx, ddb, commitReplayer, nerf, vs, rewritten, p)
 
 		if err != nil {
 			return nil, err
@@ -324,6 +353,14 @@ func rebaseRecursive(ctx context.Context, ddb *doltdb.DoltDB, commitReplayer Com
 		return nil, err
 	}
 
+	rebasedHash, err := rebasedCommit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if rebasedHash != commitHash {
+		*rewritten = append(*rewritten, RewrittenCommit{Original: commitHash, Rewritten: rebasedHash})
+	}
+
 	vs[commitHash] = rebasedCommit
 	return rebasedCommit, nil
 }
@@ -0,0 +1,207 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// PruneFn returns whether |cm| is pruned from the commit history. The ancestors of a pruned commit are pruned along
+// with it.
+type PruneFn func(ctx context.Context, cm *doltdb.Commit) (bool, error)
+
+// PruneOlderThan returns a |PruneFn| that prunes commits made before |t|.
+func PruneOlderThan(t time.Time) PruneFn {
+	return func(ctx context.Context, cm *doltdb.Commit) (bool, error) {
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return false, err
+		}
+		return meta.Time().Before(t), nil
+	}
+}
+
+// PruneAncestorsOf returns a |PruneFn| that prunes the ancestors of |graft|, which becomes a root commit of the
+// rewritten history.
+func PruneAncestorsOf(ctx context.Context, ddb *doltdb.DoltDB, graft *doltdb.Commit) (PruneFn, error) {
+	ancestors := make(hash.HashSet)
+	toVisit := []*doltdb.Commit{graft}
+	for len(toVisit) > 0 {
+		cm := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+
+		optParents, err := ddb.ResolveAllParents(ctx, cm)
+		if err != nil {
+			return nil, err
+		}
+		for _, optParent := range optParents {
+			parent, ok := optParent.ToCommit()
+			if !ok {
+				// the history of a shallow clone ends at ghost commits
+				continue
+			}
+			h, err := parent.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			if ancestors.Has(h) {
+				continue
+			}
+			ancestors.Insert(h)
+			toVisit = append(toVisit, parent)
+		}
+	}
+
+	return func(_ context.Context, cm *doltdb.Commit) (bool, error) {
+		h, err := cm.HashOf()
+		if err != nil {
+			return false, err
+		}
+		return ancestors.Has(h), nil
+	}, nil
+}
+
+// PruneHistory rewrites the history of every branch and tag in |ddb| to drop the commits pruned by |prune|. Commits
+// whose parents are all pruned become root commits of the rewritten history, and keep the data they had. The commit
+// at the head of a branch or tag is never pruned. Uncommitted changes are left alone. Returns the refs which were
+// rewritten.
+//
+// The pruned commits are not deleted from storage until they are garbage collected, and are kept by garbage
+// collection while any other ref, such as a remote tracking branch or a stash, still references them.
+func PruneHistory(ctx context.Context, ddb *doltdb.DoltDB, prune PruneFn) ([]ref.DoltRef, error) {
+	branches, err := ddb.GetBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := ddb.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vs := make(visitedSet)
+	var rewritten []ref.DoltRef
+	for _, r := range append(branches, tags...) {
+		head, err := ddb.ResolveCommitRef(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		newHead, err := pruneRecursive(ctx, ddb, prune, vs, head)
+		if err != nil {
+			return nil, err
+		}
+		if newHead == head {
+			continue
+		}
+
+		switch dRef := r.(type) {
+		case ref.BranchRef:
+			// the rewritten head has the same root value, so the working set doesn't need to change
+			err = ddb.SetHeadToCommit(ctx, dRef, newHead)
+		case ref.TagRef:
+			var tag *doltdb.Tag
+			if tag, err = ddb.ResolveTag(ctx, dRef); err != nil {
+				return nil, err
+			}
+			if err = ddb.DeleteTag(ctx, dRef); err != nil {
+				return nil, err
+			}
+			err = ddb.NewTagAtCommit(ctx, dRef, newHead, tag.Meta)
+		default:
+			return nil, fmt.Errorf("cannot prune the history of ref: %s", ref.String(dRef))
+		}
+		if err != nil {
+			return nil, err
+		}
+		rewritten = append(rewritten, r)
+	}
+	return rewritten, nil
+}
+
+// pruneRecursive returns |commit| with the pruned commits dropped from its history. |commit| itself is returned if
+// nothing in its history was pruned.
+func pruneRecursive(ctx context.Context, ddb *doltdb.DoltDB, prune PruneFn, vs visitedSet, commit *doltdb.Commit) (*doltdb.Commit, error) {
+	commitHash, err := commit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if visitedCommit, found := vs[commitHash]; found {
+		return visitedCommit, nil
+	}
+
+	optParents, err := ddb.ResolveAllParents(ctx, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	var newParents []*doltdb.Commit
+	for _, optParent := range optParents {
+		parent, ok := optParent.ToCommit()
+		if !ok {
+			// ghost commits, which a shallow clone doesn't have, are pruned along with everything else before them
+			changed = true
+			continue
+		}
+
+		pruned, err := prune(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		if pruned {
+			changed = true
+			continue
+		}
+
+		newParent, err := pruneRecursive(ctx, ddb, prune, vs, parent)
+		if err != nil {
+			return nil, err
+		}
+		if newParent != parent {
+			changed = true
+		}
+		newParents = append(newParents, newParent)
+	}
+
+	if !changed {
+		vs[commitHash] = commit
+		return commit, nil
+	}
+
+	root, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	valueHash, err := root.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	meta, err := commit.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newCommit, err := ddb.CommitDanglingWithParentCommits(ctx, valueHash, newParents, meta)
+	if err != nil {
+		return nil, err
+	}
+	vs[commitHash] = newCommit
+	return newCommit, nil
+}
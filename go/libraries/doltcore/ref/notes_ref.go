@@ -0,0 +1,67 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ref
+
+import "strings"
+
+// DefaultNotesRefName is the name of the notes ref used when none is given, as in git
+const DefaultNotesRefName = "commits"
+
+// IsValidNotesRefName validates that a notes ref name passes naming constraints, which are the same as for tags.
+func IsValidNotesRefName(name string) bool {
+	return IsValidTagName(name)
+}
+
+// NotesRef is a reference to a namespace of notes attached to commits, e.g. refs/notes/commits. It points to a commit
+// whose root value holds the notes, and whose history is the history of changes to them.
+type NotesRef struct {
+	notes string
+}
+
+var _ DoltRef = NotesRef{}
+
+// NewNotesRef creates a reference to a notes namespace from a name or a notes ref e.g. reviews, or refs/notes/reviews
+func NewNotesRef(name string) NotesRef {
+	if IsRef(name) {
+		prefix := PrefixForType(NotesRefType)
+		if strings.HasPrefix(name, prefix) {
+			name = name[len(prefix):]
+		} else {
+			panic(name + " is a ref that is not of type " + prefix)
+		}
+	}
+
+	return NotesRef{name}
+}
+
+// GetType will return NotesRefType
+func (nr NotesRef) GetType() RefType {
+	return NotesRefType
+}
+
+// GetPath returns the name of the notes namespace
+func (nr NotesRef) GetPath() string {
+	return nr.notes
+}
+
+// String returns the fully qualified reference name e.g. refs/notes/commits
+func (nr NotesRef) String() string {
+	return String(nr)
+}
+
+// MarshalJSON serializes a NotesRef to JSON.
+func (nr NotesRef) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(nr)
+}
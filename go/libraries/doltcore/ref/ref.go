@@ -57,6 +57,9 @@ const (
 
 	// StatsRefType is a reference to a statistics table
 	StatsRefType RefType = "statistics"
+
+	// NotesRefType is a reference to a namespace of notes attached to commits
+	NotesRefType RefType = "notes"
 )
 
 // HeadRefTypes are the ref types that point to a HEAD and contain a Commit struct. These are the types that are
@@ -79,6 +82,12 @@ var StatsRefTypes = map[RefType]struct{}{
 	StatsRefType: {},
 }
 
+// NotesRefTypes point to commits holding notes. They aren't HeadRefTypes since those commits aren't part of the
+// history of the database, and have no relationship to its branches.
+var NotesRefTypes = map[RefType]struct{}{
+	NotesRefType: {},
+}
+
 // PrefixForType returns what a reference string for a given type should start with
 func PrefixForType(refType RefType) string {
 	return refPrefix + string(refType) + "/"
@@ -205,5 +214,9 @@ func Parse(str string) (DoltRef, error) {
 		return NewStatsRef(str[len(prefix):]), nil
 	}
 
+	if prefix := PrefixForType(NotesRefType); strings.HasPrefix(str, prefix) {
+		return NewNotesRef(str[len(prefix):]), nil
+	}
+
 	return nil, ErrUnknownRefType
 }
@@ -106,6 +106,8 @@ func ParseRefSpecForRemote(remote, refSpecStr string) (RefSpec, error) {
 		return NewBranchToBranchRefSpec(fromRef.(BranchRef), toRef.(BranchRef))
 	} else if fromRef.GetType() == TagRefType && toRef.GetType() == TagRefType {
 		return NewTagToTagRefSpec(fromRef.(TagRef), toRef.(TagRef))
+	} else if fromRef.GetType() == NotesRefType && toRef.GetType() == NotesRefType {
+		return NewNotesToNotesRefSpec(fromRef.(NotesRef), toRef.(NotesRef))
 	}
 
 	return nil, ErrUnsupportedMapping
@@ -197,6 +199,34 @@ func (rs TagToTagRefSpec) DestRef(r DoltRef) DoltRef {
 	return nil
 }
 
+// NotesToNotesRefSpec maps one notes ref to another.
+type NotesToNotesRefSpec struct {
+	srcRef  DoltRef
+	destRef DoltRef
+}
+
+// NewNotesToNotesRefSpec takes a source and destination NotesRef and returns a RefSpec that maps source to dest.
+func NewNotesToNotesRefSpec(srcRef, destRef NotesRef) (RefSpec, error) {
+	return NotesToNotesRefSpec{
+		srcRef:  srcRef,
+		destRef: destRef,
+	}, nil
+}
+
+// SrcRef will always determine the DoltRef specified as the source ref regardless to the cwbRef
+func (rs NotesToNotesRefSpec) SrcRef(_ DoltRef) DoltRef {
+	return rs.srcRef
+}
+
+// DestRef returns the destination notes ref if the ref given is the source notes ref, and nil otherwise.
+func (rs NotesToNotesRefSpec) DestRef(r DoltRef) DoltRef {
+	if Equals(r, rs.srcRef) {
+		return rs.destRef
+	}
+
+	return nil
+}
+
 // BranchToTrackingBranchRefSpec maps a branch to the branch that should be tracking it
 type BranchToTrackingBranchRefSpec struct {
 	localPattern  pattern
@@ -80,6 +80,22 @@ func TestRefSpec(t *testing.T) {
 		}, {
 			remote:     "origin",
 			refSpecStr: "refs/heads/*/*:refs/remotes/origin/*/*",
+		}, {
+			refSpecStr: "refs/notes/commits",
+			isValid:    true,
+			inToExpOut: map[string]string{
+				"refs/notes/commits": "refs/notes/commits",
+				"refs/notes/reviews": "refs/nil/",
+			},
+		}, {
+			refSpecStr: "refs/notes/reviews:refs/notes/commits",
+			isValid:    true,
+			inToExpOut: map[string]string{
+				"refs/notes/reviews": "refs/notes/commits",
+				"refs/notes/commits": "refs/nil/",
+			},
+		}, {
+			refSpecStr: "refs/notes/commits:refs/tags/v1",
 		}, {
 			refSpecStr: "refs/tags/*:refs/tags/*",
 			isValid:    true,
@@ -46,6 +46,10 @@ func TestJsonMarshalAndUnmarshal(t *testing.T) {
 			NewWorkspaceRef("newworkspace"),
 			`{"test":"refs/workspaces/newworkspace"}`,
 		},
+		{
+			NewNotesRef("reviews"),
+			`{"test":"refs/notes/reviews"}`,
+		},
 	}
 
 	for _, test := range tests {
@@ -24,8 +24,10 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 
+	"github.com/golang/snappy"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -54,6 +56,10 @@ type RemoteChunkStore struct {
 	fs      filesys.Filesys
 	lgr     *logrus.Entry
 	sealer  Sealer
+
+	preReceiveHooks  []PreReceiveHook
+	postReceiveHooks []PostReceiveHook
+
 	remotesapi.UnimplementedChunkStoreServiceServer
 }
 
@@ -221,6 +227,72 @@ func (rs *RemoteChunkStore) GetDownloadLocations(ctx context.Context, req *remot
 	return &remotesapi.GetDownloadLocsResponse{Locs: locs}, nil
 }
 
+func (rs *RemoteChunkStore) GetChunkDeltas(ctx context.Context, req *remotesapi.GetChunkDeltasRequest) (*remotesapi.GetChunkDeltasResponse, error) {
+	logger := getReqLogger(rs.lgr, "GetChunkDeltas")
+	if err := ValidateGetChunkDeltasRequest(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	repoPath := getRepoPath(req)
+	logger = logger.WithField(RepoPathField, repoPath)
+	defer func() { logger.Info("finished") }()
+
+	cs, err := rs.getStore(ctx, logger, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	toGet := make(hash.HashSet, 2*len(req.Chunks))
+	for _, c := range req.Chunks {
+		toGet.Insert(hash.New(c.Hash))
+		toGet.Insert(hash.New(c.BaseHash))
+	}
+	var mu sync.Mutex
+	found := make(map[hash.Hash][]byte, len(toGet))
+	err = cs.GetMany(ctx, toGet, func(_ context.Context, c *chunks.Chunk) {
+		mu.Lock()
+		defer mu.Unlock()
+		found[c.Hash()] = c.Data()
+	})
+	if err != nil {
+		logger.WithError(err).Error("error calling GetMany")
+		return nil, status.Error(codes.Internal, "GetMany failure:"+err.Error())
+	}
+
+	var deltas []*remotesapi.ChunkDelta
+	var deltaBytes, chunkBytes int
+	for _, c := range req.Chunks {
+		data, ok := found[hash.New(c.Hash)]
+		if !ok {
+			continue
+		}
+		base, ok := found[hash.New(c.BaseHash)]
+		if !ok {
+			continue
+		}
+		delta, err := remotestorage.EncodeChunkDelta(base, data)
+		if err != nil {
+			logger.WithError(err).Error("error encoding chunk delta")
+			return nil, status.Error(codes.Internal, "error encoding chunk delta: "+err.Error())
+		}
+		// The client would otherwise download the snappy compressed chunk.
+		if len(delta) >= len(snappy.Encode(nil, data)) {
+			continue
+		}
+		deltaBytes += len(delta)
+		chunkBytes += len(data)
+		deltas = append(deltas, &remotesapi.ChunkDelta{Hash: c.Hash, BaseHash: c.BaseHash, Delta: delta})
+	}
+
+	logger = logger.WithFields(logrus.Fields{
+		"num_requested": len(req.Chunks),
+		"num_deltas":    len(deltas),
+		"delta_bytes":   deltaBytes,
+		"chunk_bytes":   chunkBytes,
+	})
+
+	return &remotesapi.GetChunkDeltasResponse{Deltas: deltas}, nil
+}
+
 func (rs *RemoteChunkStore) StreamDownloadLocations(stream remotesapi.ChunkStoreService_StreamDownloadLocationsServer) error {
 	ologger := getReqLogger(rs.lgr, "StreamDownloadLocations")
 	numMessages := 0
@@ -501,6 +573,22 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 	currHash := hash.New(req.Current)
 	lastHash := hash.New(req.Last)
 
+	var event ReceiveEvent
+	runHooks := currHash != lastHash && (len(rs.preReceiveHooks) > 0 || len(rs.postReceiveHooks) > 0)
+	if runHooks {
+		event, err = newReceiveEvent(ctx, cs, repoPath, lastHash, currHash)
+		if err != nil {
+			logger.WithError(err).Error("error reading refs for receive hooks")
+			return nil, status.Errorf(codes.Internal, "failed to read pushed refs: %v", err)
+		}
+		for _, hook := range rs.preReceiveHooks {
+			if err = hook(ctx, event); err != nil {
+				logger.WithError(err).Info("push rejected by pre-receive hook")
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
+		}
+	}
+
 	var ok bool
 	ok, err = cs.Commit(ctx, currHash, lastHash)
 	if err != nil {
@@ -511,6 +599,12 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 		return nil, status.Errorf(codes.Internal, "failed to commit: %v", err)
 	}
 
+	if runHooks && ok {
+		for _, hook := range rs.postReceiveHooks {
+			hook(ctx, event)
+		}
+	}
+
 	logger.Tracef("Commit success; moved from %s -> %s", lastHash.String(), currHash.String())
 	return &remotesapi.CommitResponse{Success: ok}, nil
 }
@@ -541,6 +635,7 @@ func (rs *RemoteChunkStore) GetRepoMetadata(ctx context.Context, req *remotesapi
 		NbsVersion:             req.ClientRepoFormat.NbsVersion,
 		StorageSize:            size,
 		PushConcurrencyControl: rs.concurrencyControl,
+		SupportsChunkDeltas:    true,
 	}, nil
 }
 
@@ -0,0 +1,191 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ReceiveEvent describes a push received by the server, which moves the root of
+// a database from |OldRoot| to |NewRoot|. |User| is the user who authenticated
+// the push, if any.
+type ReceiveEvent struct {
+	Database string      `json:"database"`
+	User     string      `json:"user,omitempty"`
+	OldRoot  string      `json:"old_root"`
+	NewRoot  string      `json:"new_root"`
+	Refs     []RefUpdate `json:"refs"`
+}
+
+// RefUpdate is a ref changed by a push. |Old| is empty for a created ref and
+// |New| is empty for a deleted one.
+type RefUpdate struct {
+	Ref string `json:"ref"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// PreReceiveHook is called before a push is applied to a database. Returning
+// an error rejects the push, and the error is returned to the client.
+type PreReceiveHook func(ctx context.Context, event ReceiveEvent) error
+
+// PostReceiveHook is called after a push has been applied to a database.
+type PostReceiveHook func(ctx context.Context, event ReceiveEvent)
+
+// ErrPushRejected is returned by webhook pre-receive hooks which reject a
+// push.
+var ErrPushRejected = errors.New("push rejected by pre-receive hook")
+
+// maxWebhookMessageLen bounds how much of a webhook's response body is
+// returned to the client when it rejects a push.
+const maxWebhookMessageLen = 1024
+
+// NewWebhookPreReceiveHook returns a PreReceiveHook which POSTs each
+// ReceiveEvent as JSON to |url|. The push is accepted if the webhook responds
+// with a 2xx status and rejected otherwise, with the response body as the
+// reason. The push is also rejected if the webhook cannot be reached.
+func NewWebhookPreReceiveHook(url string, timeout time.Duration) PreReceiveHook {
+	client := &http.Client{Timeout: timeout}
+	return func(ctx context.Context, event ReceiveEvent) error {
+		resp, err := postReceiveEvent(ctx, client, url, event)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrPushRejected, err.Error())
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 == 2 {
+			return nil
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookMessageLen))
+		if s := strings.TrimSpace(string(msg)); s != "" {
+			return fmt.Errorf("%w: %s", ErrPushRejected, s)
+		}
+		return fmt.Errorf("%w: %s", ErrPushRejected, resp.Status)
+	}
+}
+
+// NewWebhookPostReceiveHook returns a PostReceiveHook which POSTs each
+// ReceiveEvent as JSON to |url|. Delivery is asynchronous and best effort;
+// failures are logged to |lgr|.
+func NewWebhookPostReceiveHook(lgr *logrus.Entry, url string, timeout time.Duration) PostReceiveHook {
+	client := &http.Client{Timeout: timeout}
+	return func(_ context.Context, event ReceiveEvent) {
+		go func() {
+			resp, err := postReceiveEvent(context.Background(), client, url, event)
+			if err != nil {
+				lgr.WithError(err).Warnf("post-receive webhook %s failed", url)
+				return
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode/100 != 2 {
+				lgr.Warnf("post-receive webhook %s returned %s", url, resp.Status)
+			}
+		}()
+	}
+}
+
+func postReceiveEvent(ctx context.Context, client *http.Client, url string, event ReceiveEvent) (*http.Response, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return client.Do(req)
+}
+
+// newReceiveEvent builds the ReceiveEvent for moving the root of |cs| from
+// |last| to |curr|. The chunks of |curr| must already be present in |cs|.
+func newReceiveEvent(ctx context.Context, cs chunks.ChunkStore, database string, last, curr hash.Hash) (ReceiveEvent, error) {
+	db := datas.NewTypesDatabase(types.NewValueStore(cs), tree.NewNodeStore(cs))
+	oldRefs, err := refsAtRoot(ctx, db, last)
+	if err != nil {
+		return ReceiveEvent{}, err
+	}
+	newRefs, err := refsAtRoot(ctx, db, curr)
+	if err != nil {
+		return ReceiveEvent{}, err
+	}
+
+	var updates []RefUpdate
+	for ref, newAddr := range newRefs {
+		if oldAddr, ok := oldRefs[ref]; !ok {
+			updates = append(updates, RefUpdate{Ref: ref, New: newAddr})
+		} else if oldAddr != newAddr {
+			updates = append(updates, RefUpdate{Ref: ref, Old: oldAddr, New: newAddr})
+		}
+	}
+	for ref, oldAddr := range oldRefs {
+		if _, ok := newRefs[ref]; !ok {
+			updates = append(updates, RefUpdate{Ref: ref, Old: oldAddr})
+		}
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Ref < updates[j].Ref
+	})
+
+	event := ReceiveEvent{
+		Database: database,
+		OldRoot:  last.String(),
+		NewRoot:  curr.String(),
+		Refs:     updates,
+	}
+	if creds, err := ExtractBasicAuthCreds(ctx); err == nil {
+		event.User = creds.Username
+	}
+	return event, nil
+}
+
+// refsAtRoot returns the address of every ref in the datasets map at |root|.
+// Working sets and other datasets outside of refs/ are skipped.
+func refsAtRoot(ctx context.Context, db datas.Database, root hash.Hash) (map[string]string, error) {
+	refs := make(map[string]string)
+	if root.IsEmpty() {
+		return refs, nil
+	}
+	dss, err := db.DatasetsByRootHash(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	err = dss.IterAll(ctx, func(id string, addr hash.Hash) error {
+		if strings.HasPrefix(id, "refs/") {
+			refs[id] = addr.String()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
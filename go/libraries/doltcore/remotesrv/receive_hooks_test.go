@@ -0,0 +1,136 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestNewReceiveEvent(t *testing.T) {
+	ctx := context.Background()
+	storage := &chunks.MemoryStorage{}
+	cs := storage.NewViewWithDefaultFormat()
+	db := datas.NewTypesDatabase(types.NewValueStore(cs), tree.NewNodeStore(cs))
+
+	commit := func(id string, v int) hash.Hash {
+		ds, err := db.GetDataset(ctx, id)
+		require.NoError(t, err)
+		ds, err = datas.CommitValue(ctx, db, ds, types.Int(v))
+		require.NoError(t, err)
+		addr, ok := ds.MaybeHeadAddr()
+		require.True(t, ok)
+		return addr
+	}
+	root := func() hash.Hash {
+		h, err := cs.Root(ctx)
+		require.NoError(t, err)
+		return h
+	}
+
+	main1 := commit("refs/heads/main", 1)
+	feature := commit("refs/heads/feature", 1)
+	commit("workingSets/heads/main", 1)
+	first := root()
+
+	event, err := newReceiveEvent(ctx, cs, "db", hash.Hash{}, first)
+	require.NoError(t, err)
+	assert.Equal(t, "db", event.Database)
+	assert.Equal(t, first.String(), event.NewRoot)
+	assert.Equal(t, []RefUpdate{
+		{Ref: "refs/heads/feature", New: feature.String()},
+		{Ref: "refs/heads/main", New: main1.String()},
+	}, event.Refs)
+
+	main2 := commit("refs/heads/main", 2)
+	ds, err := db.GetDataset(ctx, "refs/heads/feature")
+	require.NoError(t, err)
+	_, err = db.Delete(ctx, ds, "")
+	require.NoError(t, err)
+	second := root()
+
+	event, err = newReceiveEvent(ctx, cs, "db", first, second)
+	require.NoError(t, err)
+	assert.Equal(t, []RefUpdate{
+		{Ref: "refs/heads/feature", Old: feature.String()},
+		{Ref: "refs/heads/main", Old: main1.String(), New: main2.String()},
+	}, event.Refs)
+}
+
+func TestWebhookPreReceiveHook(t *testing.T) {
+	ctx := context.Background()
+	event := ReceiveEvent{
+		Database: "db",
+		Refs:     []RefUpdate{{Ref: "refs/heads/main", New: "abc"}},
+	}
+
+	var received ReceiveEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.Refs[0].Ref == "refs/heads/main" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("pushes to main are not allowed\n"))
+		}
+	}))
+	defer srv.Close()
+
+	hook := NewWebhookPreReceiveHook(srv.URL, time.Second)
+	err := hook(ctx, event)
+	assert.ErrorIs(t, err, ErrPushRejected)
+	assert.Contains(t, err.Error(), "pushes to main are not allowed")
+	assert.Equal(t, event, received)
+
+	event.Refs[0].Ref = "refs/heads/feature"
+	assert.NoError(t, hook(ctx, event))
+
+	srv.Close()
+	assert.ErrorIs(t, hook(ctx, event), ErrPushRejected)
+}
+
+func TestWebhookPostReceiveHook(t *testing.T) {
+	received := make(chan ReceiveEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event ReceiveEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer srv.Close()
+
+	event := ReceiveEvent{Database: "db", OldRoot: "old", NewRoot: "new"}
+	hook := NewWebhookPostReceiveHook(logrus.NewEntry(logrus.StandardLogger()), srv.URL, time.Second)
+	hook(context.Background(), event)
+
+	select {
+	case got := <-received:
+		assert.Equal(t, event, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for post-receive webhook")
+	}
+}
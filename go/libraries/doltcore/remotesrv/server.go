@@ -73,6 +73,13 @@ type ServerArgs struct {
 	// listeners. The scheme used in the URLs returned from the gRPC server
 	// will be https.
 	TLSConfig *tls.Config
+
+	// PreReceiveHooks are run before a push updates the root of a
+	// database. Any hook returning an error rejects the push.
+	PreReceiveHooks []PreReceiveHook
+	// PostReceiveHooks are run after a push has updated the root of a
+	// database.
+	PostReceiveHooks []PostReceiveHook
 }
 
 func NewServer(args ServerArgs) (*Server, error) {
@@ -105,7 +112,10 @@ func NewServer(args ServerArgs) (*Server, error) {
 	s.wg.Add(2)
 	s.grpcListenAddr = args.GrpcListenAddr
 	s.grpcSrv = grpc.NewServer(append([]grpc.ServerOption{grpc.MaxRecvMsgSize(128 * 1024 * 1024)}, args.Options...)...)
-	var chnkSt remotesapi.ChunkStoreServiceServer = NewHttpFSBackedChunkStore(args.Logger, args.HttpHost, args.DBCache, args.FS, scheme, args.ConcurrencyControl, sealer)
+	rcs := NewHttpFSBackedChunkStore(args.Logger, args.HttpHost, args.DBCache, args.FS, scheme, args.ConcurrencyControl, sealer)
+	rcs.preReceiveHooks = args.PreReceiveHooks
+	rcs.postReceiveHooks = args.PostReceiveHooks
+	var chnkSt remotesapi.ChunkStoreServiceServer = rcs
 
 	if args.ReadOnly {
 		chnkSt = ReadOnlyChunkStore{chnkSt}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -92,6 +93,24 @@ func ValidateGetDownloadLocsRequest(req *remotesapi.GetDownloadLocsRequest) erro
 	return validateHashes("chunk_hashes", req.ChunkHashes)
 }
 
+func ValidateGetChunkDeltasRequest(req *remotesapi.GetChunkDeltasRequest) error {
+	if err := validateRepoRequest(req); err != nil {
+		return err
+	}
+	if len(req.Chunks) > remotestorage.MaxChunkDeltasPerRequest {
+		return fmt.Errorf("expected at most %d chunks, got %d", remotestorage.MaxChunkDeltasPerRequest, len(req.Chunks))
+	}
+	for i, c := range req.Chunks {
+		if err := validateHash(fmt.Sprintf("chunks[%d].hash", i), c.GetHash()); err != nil {
+			return err
+		}
+		if err := validateHash(fmt.Sprintf("chunks[%d].base_hash", i), c.GetBaseHash()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func ValidateGetUploadLocsRequest(req *remotesapi.GetUploadLocsRequest) error {
 	if err := validateRepoRequest(req); err != nil {
 		return err
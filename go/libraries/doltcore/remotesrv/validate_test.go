@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
@@ -229,6 +230,51 @@ func TestValidateGetDownloadLocsRequest(t *testing.T) {
 	}
 }
 
+func TestValidateGetChunkDeltasRequest(t *testing.T) {
+	for i, errMsg := range []*remotesapi.GetChunkDeltasRequest{
+		{},
+		{
+			RepoId: &remotesapi.RepoId{
+				Org: "dolthub",
+			},
+			Chunks: []*remotesapi.ChunkDeltaRequest{{Hash: GoodHash, BaseHash: GoodHash}},
+		},
+		{
+			RepoPath: GoodRepoPath,
+			Chunks:   []*remotesapi.ChunkDeltaRequest{{Hash: ShortHash, BaseHash: GoodHash}},
+		},
+		{
+			RepoPath: GoodRepoPath,
+			Chunks:   []*remotesapi.ChunkDeltaRequest{{Hash: GoodHash, BaseHash: LongHash}},
+		},
+		{
+			RepoPath: GoodRepoPath,
+			Chunks:   []*remotesapi.ChunkDeltaRequest{{Hash: GoodHash, BaseHash: GoodHash}, {Hash: GoodHash}},
+		},
+		{
+			RepoPath: GoodRepoPath,
+			Chunks:   make([]*remotesapi.ChunkDeltaRequest, remotestorage.MaxChunkDeltasPerRequest+1),
+		},
+	} {
+		t.Run(fmt.Sprintf("Error #%02d", i), func(t *testing.T) {
+			assert.Error(t, ValidateGetChunkDeltasRequest(errMsg), "%v should not validate", errMsg)
+		})
+	}
+	for i, msg := range []*remotesapi.GetChunkDeltasRequest{
+		{
+			RepoPath: GoodRepoPath,
+		},
+		{
+			RepoId: GoodRepoId,
+			Chunks: []*remotesapi.ChunkDeltaRequest{{Hash: GoodHash, BaseHash: GoodHash}},
+		},
+	} {
+		t.Run(fmt.Sprintf("NoError #%02d", i), func(t *testing.T) {
+			assert.NoError(t, ValidateGetChunkDeltasRequest(msg), "%v should validate", msg)
+		})
+	}
+}
+
 func TestValidateGetUploadLocsRequest(t *testing.T) {
 	for i, errMsg := range []*remotesapi.GetUploadLocsRequest{
 		{},
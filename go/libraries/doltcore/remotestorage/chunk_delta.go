@@ -0,0 +1,221 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/gozstd"
+	"golang.org/x/sync/errgroup"
+
+	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+// MaxChunkDeltasPerRequest is the largest number of chunks a client will
+// request, and a server will accept, in a single GetChunkDeltas call.
+const MaxChunkDeltasPerRequest = 1024
+
+// EncodeChunkDelta encodes |data| as a delta against |base|. The delta is the
+// zstd compression of |data| using |base| as a raw content dictionary, so any
+// runs of bytes shared between the two chunks are encoded as back references
+// into |base|.
+func EncodeChunkDelta(base, data []byte) ([]byte, error) {
+	cd, err := gozstd.NewCDict(base)
+	if err != nil {
+		return nil, err
+	}
+	defer cd.Release()
+	return gozstd.CompressDict(nil, data, cd), nil
+}
+
+// DecodeChunkDelta returns the contents of a chunk which was encoded against
+// |base| by EncodeChunkDelta.
+func DecodeChunkDelta(base, delta []byte) ([]byte, error) {
+	dd, err := gozstd.NewDDict(base)
+	if err != nil {
+		return nil, err
+	}
+	defer dd.Release()
+	return gozstd.DecompressDict(nil, delta, dd)
+}
+
+// deltaChunkFetcher is an nbs.ChunkFetcher which fetches chunks that have a
+// delta base through GetChunkDeltas, and fetches everything else, including
+// any chunks the server declined to encode as deltas, through |inner|.
+type deltaChunkFetcher struct {
+	eg    *errgroup.Group
+	egCtx context.Context
+
+	dcs   *DoltChunkStore
+	bases nbs.DeltaBases
+	inner nbs.ChunkFetcher
+
+	resCh   chan nbs.CompressedChunk
+	abortCh chan struct{}
+}
+
+var _ nbs.ChunkFetcher = (*deltaChunkFetcher)(nil)
+
+func newDeltaChunkFetcher(ctx context.Context, dcs *DoltChunkStore, bases nbs.DeltaBases) *deltaChunkFetcher {
+	eg, ctx := errgroup.WithContext(ctx)
+	ret := &deltaChunkFetcher{
+		eg:      eg,
+		egCtx:   ctx,
+		dcs:     dcs,
+		bases:   bases,
+		inner:   NewChunkFetcher(ctx, dcs),
+		resCh:   make(chan nbs.CompressedChunk),
+		abortCh: make(chan struct{}),
+	}
+	eg.Go(func() error {
+		// Every chunk fetched as a delta is delivered to |resCh| from
+		// |Get|, which returns before |CloseSend| is called. So once
+		// |inner| is drained, nothing else will be sent to |resCh|.
+		defer close(ret.resCh)
+		for {
+			cc, err := ret.inner.Recv(ctx)
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			select {
+			case ret.resCh <- cc:
+			case <-ret.abortCh:
+				return nil
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		}
+	})
+	return ret
+}
+
+func (f *deltaChunkFetcher) Get(ctx context.Context, hashes hash.HashSet) error {
+	var reqs []*remotesapi.ChunkDeltaRequest
+	rest := make(hash.HashSet, len(hashes))
+	for h := range hashes {
+		if base, ok := f.bases.DeltaBase(h); ok {
+			hCpy := h
+			reqs = append(reqs, &remotesapi.ChunkDeltaRequest{Hash: hCpy[:], BaseHash: base[:]})
+		} else {
+			rest.Insert(h)
+		}
+	}
+
+	for len(reqs) > 0 {
+		n := min(len(reqs), MaxChunkDeltasPerRequest)
+		missing, err := f.getDeltas(ctx, reqs[:n])
+		if err != nil {
+			return err
+		}
+		rest.InsertAll(missing)
+		reqs = reqs[n:]
+	}
+
+	if len(rest) == 0 {
+		return nil
+	}
+	return f.inner.Get(ctx, rest)
+}
+
+// getDeltas fetches the chunks in |reqs| as deltas and delivers them to
+// |resCh|. Returns the addresses of any chunks which could not be fetched as
+// deltas.
+func (f *deltaChunkFetcher) getDeltas(ctx context.Context, reqs []*remotesapi.ChunkDeltaRequest) (hash.HashSet, error) {
+	id, token := f.dcs.getRepoId()
+	req := &remotesapi.GetChunkDeltasRequest{RepoId: id, RepoToken: token, RepoPath: f.dcs.repoPath, Chunks: reqs}
+	resp, err := f.dcs.csClient.GetChunkDeltas(ctx, req)
+	if err != nil {
+		return nil, NewRpcError(err, "GetChunkDeltas", f.dcs.host, req)
+	}
+	if resp.RepoToken != "" {
+		f.dcs.repoToken.Store(resp.RepoToken)
+	}
+
+	missing := make(hash.HashSet, len(reqs))
+	for _, r := range reqs {
+		missing.Insert(hash.New(r.Hash))
+	}
+	for _, d := range resp.Deltas {
+		h, base := hash.New(d.Hash), hash.New(d.BaseHash)
+		if !missing.Has(h) {
+			continue
+		}
+		cc, err := f.applyDelta(ctx, h, base, d.Delta)
+		if err != nil {
+			return nil, err
+		} else if cc.IsEmpty() {
+			// We no longer have the base, fetch the whole chunk instead.
+			continue
+		}
+		select {
+		case f.resCh <- cc:
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		case <-f.egCtx.Done():
+			return nil, context.Cause(f.egCtx)
+		}
+		missing.Remove(h)
+	}
+	return missing, nil
+}
+
+func (f *deltaChunkFetcher) applyDelta(ctx context.Context, h, base hash.Hash, delta []byte) (nbs.CompressedChunk, error) {
+	baseChunk, err := f.bases.GetDeltaBase(ctx, base)
+	if err != nil {
+		return nbs.CompressedChunk{}, err
+	} else if baseChunk.IsEmpty() {
+		return nbs.CompressedChunk{}, nil
+	}
+	data, err := DecodeChunkDelta(baseChunk.Data(), delta)
+	if err != nil {
+		return nbs.CompressedChunk{}, fmt.Errorf("could not decode delta for chunk %s: %w", h.String(), err)
+	}
+	chnk := chunks.NewChunk(data)
+	if chnk.Hash() != h {
+		return nbs.CompressedChunk{}, fmt.Errorf("delta for chunk %s against %s decoded to a chunk with address %s", h.String(), base.String(), chnk.Hash().String())
+	}
+	return nbs.ChunkToCompressedChunk(chnk), nil
+}
+
+func (f *deltaChunkFetcher) CloseSend() error {
+	return f.inner.CloseSend()
+}
+
+func (f *deltaChunkFetcher) Recv(ctx context.Context) (nbs.CompressedChunk, error) {
+	select {
+	case <-ctx.Done():
+		return nbs.CompressedChunk{}, context.Cause(ctx)
+	case <-f.egCtx.Done():
+		return nbs.CompressedChunk{}, context.Cause(f.egCtx)
+	case cc, ok := <-f.resCh:
+		if !ok {
+			return nbs.CompressedChunk{}, io.EOF
+		}
+		return cc, nil
+	}
+}
+
+func (f *deltaChunkFetcher) Close() error {
+	close(f.abortCh)
+	return errors.Join(f.inner.Close(), f.eg.Wait())
+}
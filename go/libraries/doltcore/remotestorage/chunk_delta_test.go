@@ -0,0 +1,43 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkDelta(t *testing.T) {
+	base := make([]byte, 4096)
+	rand.New(rand.NewSource(0)).Read(base)
+	data := bytes.Clone(base)
+	copy(data[1000:], "a few changed bytes")
+	data = append(data, "and a few more"...)
+
+	delta, err := EncodeChunkDelta(base, data)
+	require.NoError(t, err)
+	assert.Less(t, len(delta), 128)
+
+	decoded, err := DecodeChunkDelta(base, delta)
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+
+	_, err = DecodeChunkDelta(data[:2048], delta)
+	assert.Error(t, err)
+}
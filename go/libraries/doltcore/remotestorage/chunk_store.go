@@ -35,6 +35,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage/internal/reliable"
@@ -83,6 +84,8 @@ type NetworkRequestParams struct {
 	ThroughputMinimumBytesPerCheck int
 	ThroughputMinimumNumIntervals  int
 	RespHeadersTimeout             time.Duration
+	// MaximumConcurrentUploads limits the number of table files uploaded at once, or is 0 for no limit.
+	MaximumConcurrentUploads int
 }
 
 var defaultRequestParams = NetworkRequestParams{
@@ -112,6 +115,7 @@ type DoltChunkStore struct {
 	stats       cacheStats
 	logger      chunks.DebugLogger
 	wsValidate  bool
+	uploads     *semaphore.Weighted
 }
 
 func NewDoltChunkStoreFromPath(ctx context.Context, nbf *types.NomsBinFormat, path, host string, wsval bool, csClient remotesapi.ChunkStoreServiceClient) (*DoltChunkStore, error) {
@@ -181,6 +185,7 @@ func (dcs *DoltChunkStore) WithHTTPFetcher(fetcher HTTPFetcher) *DoltChunkStore
 		httpFetcher: fetcher,
 		params:      dcs.params,
 		stats:       dcs.stats,
+		uploads:     dcs.uploads,
 	}
 }
 
@@ -200,6 +205,7 @@ func (dcs *DoltChunkStore) WithNoopChunkCache() *DoltChunkStore {
 		params:      dcs.params,
 		stats:       dcs.stats,
 		logger:      dcs.logger,
+		uploads:     dcs.uploads,
 	}
 }
 
@@ -219,9 +225,22 @@ func (dcs *DoltChunkStore) WithChunkCache(cache ChunkCache) *DoltChunkStore {
 		params:      dcs.params,
 		stats:       dcs.stats,
 		logger:      dcs.logger,
+		uploads:     dcs.uploads,
 	}
 }
 
+// NetworkRequestParams returns the parameters of this chunk store's requests.
+func (dcs *DoltChunkStore) NetworkRequestParams() NetworkRequestParams {
+	return dcs.params
+}
+
+func newUploadSemaphore(params NetworkRequestParams) *semaphore.Weighted {
+	if params.MaximumConcurrentUploads <= 0 {
+		return nil
+	}
+	return semaphore.NewWeighted(int64(params.MaximumConcurrentUploads))
+}
+
 func (dcs *DoltChunkStore) WithNetworkRequestParams(params NetworkRequestParams) *DoltChunkStore {
 	return &DoltChunkStore{
 		repoId:      dcs.repoId,
@@ -238,6 +257,7 @@ func (dcs *DoltChunkStore) WithNetworkRequestParams(params NetworkRequestParams)
 		params:      params,
 		stats:       dcs.stats,
 		logger:      dcs.logger,
+		uploads:     newUploadSemaphore(params),
 	}
 }
 
@@ -280,6 +300,15 @@ func (dcs *DoltChunkStore) ChunkFetcher(ctx context.Context) nbs.ChunkFetcher {
 	return NewChunkFetcher(ctx, dcs)
 }
 
+// DeltaChunkFetcher returns a ChunkFetcher which fetches chunks as deltas
+// against the chunks in |bases| when the remote supports it.
+func (dcs *DoltChunkStore) DeltaChunkFetcher(ctx context.Context, bases nbs.DeltaBases) nbs.ChunkFetcher {
+	if !dcs.metadata.SupportsChunkDeltas {
+		return NewChunkFetcher(ctx, dcs)
+	}
+	return newDeltaChunkFetcher(ctx, dcs, bases)
+}
+
 // Get the Chunk for the value of the hash in the store. If the hash is absent from the store EmptyChunk is returned.
 func (dcs *DoltChunkStore) Get(ctx context.Context, h hash.Hash) (chunks.Chunk, error) {
 	hashes := hash.HashSet{h: struct{}{}}
@@ -917,6 +946,13 @@ func (dcs *DoltChunkStore) uploadChunks(ctx context.Context) (map[hash.Hash]int,
 }
 
 func (dcs *DoltChunkStore) uploadTableFileWithRetries(ctx context.Context, tableFileId hash.Hash, numChunks uint64, tableFileContentHash []byte, getContent func() (io.ReadCloser, uint64, error)) error {
+	if dcs.uploads != nil {
+		if err := dcs.uploads.Acquire(ctx, 1); err != nil {
+			return err
+		}
+		defer dcs.uploads.Release(1)
+	}
+
 	op := func() error {
 		body, contentLength, err := getContent()
 		if err != nil {
@@ -0,0 +1,84 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// maxThrottleBurst bounds how many bytes a throttled transfer can send or receive at once.
+const maxThrottleBurst = 64 * 1024
+
+// NewBandwidthLimiter returns a limiter for |bytesPerSecond|. A single limiter can be shared by multiple
+// HTTPFetchers to limit their combined bandwidth.
+func NewBandwidthLimiter(bytesPerSecond uint64) *rate.Limiter {
+	burst := maxThrottleBurst
+	if bytesPerSecond < maxThrottleBurst {
+		burst = int(bytesPerSecond)
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
+
+// throttledHTTPFetcher is an HTTPFetcher whose request and response bodies are limited by |limiter|.
+type throttledHTTPFetcher struct {
+	fetcher HTTPFetcher
+	limiter *rate.Limiter
+}
+
+// NewThrottledHTTPFetcher returns an HTTPFetcher which uploads and downloads table files through |fetcher| no faster
+// than |limiter| allows.
+func NewThrottledHTTPFetcher(fetcher HTTPFetcher, limiter *rate.Limiter) HTTPFetcher {
+	return throttledHTTPFetcher{fetcher: fetcher, limiter: limiter}
+}
+
+func (f throttledHTTPFetcher) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = &throttledReader{ctx: ctx, rd: req.Body, limiter: f.limiter}
+	}
+	resp, err := f.fetcher.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &throttledReader{ctx: ctx, rd: resp.Body, limiter: f.limiter}
+	return resp, nil
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	rd      io.ReadCloser
+	limiter *rate.Limiter
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if burst := r.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := r.rd.Read(p)
+	if n > 0 {
+		if werr := r.limiter.WaitN(r.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
+
+func (r *throttledReader) Close() error {
+	return r.rd.Close()
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type echoFetcher struct{}
+
+func (echoFetcher) Do(req *http.Request) (*http.Response, error) {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func TestThrottledHTTPFetcher(t *testing.T) {
+	const bytesPerSecond = 20 * 1024
+	fetcher := NewThrottledHTTPFetcher(echoFetcher{}, NewBandwidthLimiter(bytesPerSecond))
+	data := bytes.Repeat([]byte{1}, 25*1024)
+
+	start := time.Now()
+	req, err := http.NewRequest(http.MethodPut, "http://localhost/file", bytes.NewReader(data))
+	require.NoError(t, err)
+	resp, err := fetcher.Do(req)
+	require.NoError(t, err)
+	got, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, data, got)
+
+	// 50KiB are sent and received with a burst of 20KiB, so this takes at least 1.5s.
+	assert.GreaterOrEqual(t, time.Since(start), 1400*time.Millisecond)
+}
+
+func TestThrottledHTTPFetcherCanceled(t *testing.T) {
+	fetcher := NewThrottledHTTPFetcher(echoFetcher{}, NewBandwidthLimiter(1024))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, "http://localhost/file", bytes.NewReader(make([]byte, 1<<20)))
+	require.NoError(t, err)
+	_, err = fetcher.Do(req)
+	assert.Error(t, err)
+}
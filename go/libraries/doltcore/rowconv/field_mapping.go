@@ -202,8 +202,8 @@ func TagMappingByTagAndName(srcSch, destSch schema.Schema) (*FieldMapping, error
 		if j == -1 {
 			continue
 		}
-		srcTag := srcSch.GetNonPKCols().GetByIndex(i).Tag
-		dstTag := destSch.GetNonPKCols().GetByIndex(j).Tag
+		srcTag := srcSch.GetNonPKCols().GetByStoredIndex(i).Tag
+		dstTag := destSch.GetNonPKCols().GetByStoredIndex(j).Tag
 		srcToDest[srcTag] = dstTag
 		successes++
 	}
@@ -53,8 +53,10 @@ type ColCollection struct {
 	cols []Column
 	// virtualColumns stores the indexes of any virtual columns in the collection
 	virtualColumns []int
-	// storedIndexes stores the indexes of the stored columns in the collection
-	storedIndexes []int
+	// storedCols stores the stored columns in the collection in storage order, including dropped columns
+	storedCols []Column
+	// withDropped is the columns the collection was created with, including dropped columns
+	withDropped []Column
 	// Tags is a list of all the tags in the ColCollection in their original order.
 	Tags []uint64
 	// SortedTags is a list of all the tags in the ColCollection in sorted order.
@@ -88,31 +90,38 @@ func NewColCollection(cols ...Column) *ColCollection {
 	var virtualColumns []int
 
 	var columns []Column
-	var storedIndexes []int
-	storageIdx := 0
-	for i, col := range cols {
+	var storedCols []Column
+	for _, col := range cols {
+		// Dropped columns are only stored. They're not part of the collection otherwise, so their tags and names
+		// don't collide with the columns which are.
+		if col.Dropped {
+			tagToStorageIndex[col.Tag] = len(storedCols)
+			storedCols = append(storedCols, col)
+			continue
+		}
+
 		// If multiple columns have the same tag, the last one is used for tag lookups.
 		// Columns must have unique tags to pass schema.ValidateForInsert.
+		i := len(columns)
 		columns = append(columns, col)
 		tagToCol[col.Tag] = col
 		tagToIdx[col.Tag] = i
 		tags = append(tags, col.Tag)
 		sortedTags = append(sortedTags, col.Tag)
-		nameToCol[col.Name] = cols[i]
+		nameToCol[col.Name] = col
 
 		// If multiple columns have the same lower case name, the first one is used for case-insensitive matching.
 		// Column names must all be case-insensitive different to pass schema.ValidateForInsert.
 		lowerCaseName := strings.ToLower(col.Name)
 		if _, ok := lowerNameToCol[lowerCaseName]; !ok {
-			lowerNameToCol[lowerCaseName] = cols[i]
+			lowerNameToCol[lowerCaseName] = col
 		}
 
 		if col.Virtual {
 			virtualColumns = append(virtualColumns, i)
 		} else {
-			storedIndexes = append(storedIndexes, i)
-			tagToStorageIndex[col.Tag] = storageIdx
-			storageIdx++
+			tagToStorageIndex[col.Tag] = len(storedCols)
+			storedCols = append(storedCols, col)
 		}
 	}
 
@@ -121,7 +130,8 @@ func NewColCollection(cols ...Column) *ColCollection {
 	return &ColCollection{
 		cols:              columns,
 		virtualColumns:    virtualColumns,
-		storedIndexes:     storedIndexes,
+		storedCols:        storedCols,
+		withDropped:       append([]Column(nil), cols...),
 		tagToStorageIndex: tagToStorageIndex,
 		Tags:              tags,
 		SortedTags:        sortedTags,
@@ -139,6 +149,20 @@ func (cc *ColCollection) GetColumns() []Column {
 	return colsCopy
 }
 
+// GetColumnsWithDropped returns the columns of the collection, including its dropped columns. NewColCollection
+// returns a collection which stores its columns the same way as this one from the list returned, or from the list
+// with columns added to its end. The list returned is a copy.
+func (cc *ColCollection) GetColumnsWithDropped() []Column {
+	colsCopy := make([]Column, len(cc.withDropped))
+	copy(colsCopy, cc.withDropped)
+	return colsCopy
+}
+
+// HasDroppedColumns returns whether the collection stores any dropped columns.
+func (cc *ColCollection) HasDroppedColumns() bool {
+	return len(cc.withDropped) > len(cc.cols)
+}
+
 // GetColumnNames returns a list of names of the columns.
 func (cc *ColCollection) GetColumnNames() []string {
 	names := make([]string, len(cc.cols))
@@ -246,12 +270,14 @@ func (cc *ColCollection) GetByIndex(idx int) Column {
 	return cc.cols[idx]
 }
 
-// GetByStoredIndex returns the Nth stored column (omitting virtual columns from index calculation)
+// GetByStoredIndex returns the Nth stored column (omitting virtual columns from index calculation). The column may be a
+// dropped column, which is stored but isn't otherwise part of the collection.
 func (cc *ColCollection) GetByStoredIndex(idx int) Column {
-	return cc.cols[cc.storedIndexes[idx]]
+	return cc.storedCols[idx]
 }
 
-// StoredIndexByTag returns the storage index of the column with the given tag, ignoring virtual columns
+// StoredIndexByTag returns the storage index of the column with the given tag, ignoring virtual columns. Dropped
+// columns have a storage index.
 func (cc *ColCollection) StoredIndexByTag(tag uint64) (int, bool) {
 	idx, ok := cc.tagToStorageIndex[tag]
 	return idx, ok
@@ -262,9 +288,9 @@ func (cc *ColCollection) Size() int {
 	return len(cc.cols)
 }
 
-// StoredSize returns the number of non-virtual columns in the collection
+// StoredSize returns the number of non-virtual columns in the collection, including dropped columns
 func (cc *ColCollection) StoredSize() int {
-	return len(cc.storedIndexes)
+	return len(cc.storedCols)
 }
 
 // Contains returns whether this column collection contains a column with the name given, case insensitive
@@ -286,6 +312,15 @@ func ColCollsAreEqual(cc1, cc2 *ColCollection) bool {
 			return false
 		}
 	}
+	// Dropped columns must be stored in the same places.
+	if cc1.StoredSize() != cc2.StoredSize() {
+		return false
+	}
+	for i := range cc1.storedCols {
+		if cc1.storedCols[i].Dropped != cc2.storedCols[i].Dropped {
+			return false
+		}
+	}
 	return true
 }
 
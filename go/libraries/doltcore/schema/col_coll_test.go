@@ -138,3 +138,25 @@ func validateIter(numCols int, colColl *ColCollection, t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestDroppedColumns(t *testing.T) {
+	dropped := Column{Name: "first", Tag: 2, Kind: types.StringKind, TypeInfo: typeinfo.StringDefaultType, Dropped: true}
+	colColl := NewColCollection(firstNameCol, dropped, lastNameCol)
+
+	assert.Equal(t, 2, colColl.Size())
+	assert.Equal(t, 3, colColl.StoredSize())
+	assert.True(t, colColl.HasDroppedColumns())
+	assert.Equal(t, []uint64{0, 1}, colColl.Tags)
+
+	_, ok := colColl.GetByTag(dropped.Tag)
+	assert.False(t, ok)
+	col, ok := colColl.GetByName("first")
+	assert.True(t, ok)
+	assert.Equal(t, firstNameCol, col)
+
+	idx, ok := colColl.StoredIndexByTag(lastNameCol.Tag)
+	assert.True(t, ok)
+	assert.Equal(t, 2, idx)
+	assert.Equal(t, dropped, colColl.GetByStoredIndex(1))
+	assert.Equal(t, []Column{firstNameCol, dropped, lastNameCol}, colColl.GetColumnsWithDropped())
+}
@@ -80,6 +80,10 @@ type Column struct {
 	// Virtual is true if this is a virtual column.
 	Virtual bool
 
+	// Dropped is true if this column was dropped from its table without rewriting the table's rows, which still store
+	// a value for it. A ColCollection stores a dropped column, but it isn't otherwise one of the collection's columns.
+	Dropped bool
+
 	// AutoIncrement says whether this column auto increments.
 	AutoIncrement bool
 
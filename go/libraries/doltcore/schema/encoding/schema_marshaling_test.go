@@ -279,6 +279,28 @@ func TestSchemaMarshalling(t *testing.T) {
 	}
 }
 
+func TestDroppedColumnMarshalling(t *testing.T) {
+	ctx := context.Background()
+	nbf := types.Format_Default
+	vrw := getTestVRW(nbf)
+	cols := getColumns(t)[:5]
+	cols[0].IsPartOfPK = true
+	cols[0].Constraints = []schema.ColConstraint{schema.NotNullConstraint{}}
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(cols...))
+	require.NoError(t, err)
+	sch, err = sch.DropColumn(cols[2].Name)
+	require.NoError(t, err)
+
+	v, err := MarshalSchema(ctx, vrw, sch)
+	require.NoError(t, err)
+	s, err := UnmarshalSchema(ctx, nbf, v)
+	require.NoError(t, err)
+	assert.Equal(t, sch, s)
+	assert.Equal(t, 4, s.GetAllCols().Size())
+	assert.Equal(t, 4, s.GetNonPKCols().StoredSize())
+	assert.True(t, s.GetNonPKCols().GetByStoredIndex(1).Dropped)
+}
+
 func getTypeinfo(t *testing.T) (ti []typeinfo.TypeInfo) {
 	st := getSqlTypes()
 	ti = make([]typeinfo.TypeInfo, len(st))
@@ -158,8 +158,13 @@ func serializeClusteredIndex(b *fb.Builder, sch schema.Schema) fb.UOffsetT {
 		ko = b.EndVector(len(pkMap))
 	}
 
-	// serialize value columns
-	nonPk := sch.GetNonPKCols().GetColumns()
+	// serialize value columns, including dropped columns, which are serialized after the other columns
+	nonPk := sch.GetNonPKCols().GetColumnsWithDropped()
+	cols, _ := serializedColumns(sch)
+	positions := make(map[uint64]int, len(cols))
+	for i, col := range cols {
+		positions[col.Tag] = i
+	}
 	length := len(nonPk)
 	if keyless {
 		length++
@@ -167,7 +172,7 @@ func serializeClusteredIndex(b *fb.Builder, sch schema.Schema) fb.UOffsetT {
 	serial.IndexStartValueColumnsVector(b, length)
 	for i := len(nonPk) - 1; i >= 0; i-- {
 		col := nonPk[i]
-		pos := sch.GetAllCols().TagToIdx[col.Tag]
+		pos := positions[col.Tag]
 		b.PrependUint16(uint16(pos))
 	}
 	if keyless {
@@ -213,8 +218,26 @@ func deserializeClusteredIndex(s *serial.TableSchema) ([]int, error) {
 	return pkOrdinals, nil
 }
 
+// serializedColumns returns the columns of |sch| in the order they're serialized in the columns vector: its columns,
+// followed by its dropped columns, which are hidden. The display order of a dropped column is its position among
+// every column, which places it in the right place in the rows of the table when it's deserialized.
+func serializedColumns(sch schema.Schema) (cols []schema.Column, displayOrder []int) {
+	cols = sch.GetAllCols().GetColumns()
+	displayOrder = make([]int, len(cols))
+	for i := range cols {
+		displayOrder[i] = i
+	}
+	for i, col := range sch.GetAllCols().GetColumnsWithDropped() {
+		if col.Dropped {
+			cols = append(cols, col)
+			displayOrder = append(displayOrder, i)
+		}
+	}
+	return cols, displayOrder
+}
+
 func serializeSchemaColumns(b *fb.Builder, sch schema.Schema) fb.UOffsetT {
-	cols := sch.GetAllCols().GetColumns()
+	cols, displayOrder := serializedColumns(sch)
 	offs := make([]fb.UOffsetT, len(cols))
 
 	if schema.IsKeyless(sch) {
@@ -257,7 +280,7 @@ func serializeSchemaColumns(b *fb.Builder, sch schema.Schema) fb.UOffsetT {
 		serial.ColumnAddDefaultValue(b, do)
 		serial.ColumnAddComment(b, co)
 		// schema.Schema determines display order
-		serial.ColumnAddDisplayOrder(b, int16(i))
+		serial.ColumnAddDisplayOrder(b, int16(displayOrder[i]))
 		serial.ColumnAddTag(b, col.Tag)
 		serial.ColumnAddEncoding(b, encodingFromTypeinfo(col.TypeInfo))
 		serial.ColumnAddPrimaryKey(b, col.IsPartOfPK)
@@ -268,7 +291,7 @@ func serializeSchemaColumns(b *fb.Builder, sch schema.Schema) fb.UOffsetT {
 		if onUpdateVal != "" {
 			serial.ColumnAddOnUpdateValue(b, ou)
 		}
-		serial.ColumnAddHidden(b, false)
+		serial.ColumnAddHidden(b, col.Dropped)
 		offs[i] = serial.ColumnEnd(b)
 	}
 
@@ -335,6 +358,7 @@ func deserializeColumns(ctx context.Context, s *serial.TableSchema) ([]schema.Co
 
 	cols := make([]schema.Column, length)
 	c := serial.Column{}
+	var droppedPositions []int
 	for i := range cols {
 		_, err := s.TryColumns(&c, i)
 		if err != nil {
@@ -371,9 +395,27 @@ func deserializeColumns(ctx context.Context, s *serial.TableSchema) ([]schema.Co
 			AutoIncrement: c.AutoIncrement(),
 			Comment:       string(c.Comment()),
 			Constraints:   constraintsFromSerialColumn(&c),
+			Dropped:       c.Hidden(),
+		}
+		if c.Hidden() {
+			droppedPositions = append(droppedPositions, int(c.DisplayOrder()))
+		}
+	}
+	if len(droppedPositions) == 0 {
+		return cols, nil
+	}
+
+	// dropped columns are serialized after the other columns, and go back in their place among them
+	visible, dropped := cols[:len(cols)-len(droppedPositions)], cols[len(cols)-len(droppedPositions):]
+	ordered := make([]schema.Column, 0, len(cols))
+	for i, col := range dropped {
+		for len(ordered) < droppedPositions[i] && len(visible) > 0 {
+			ordered = append(ordered, visible[0])
+			visible = visible[1:]
 		}
+		ordered = append(ordered, col)
 	}
-	return cols, nil
+	return append(ordered, visible...), nil
 }
 
 func serializeSecondaryIndexes(b *fb.Builder, sch schema.Schema, indexes []schema.Index) fb.UOffsetT {
@@ -115,3 +115,59 @@ const (
 	DoltIgnorePatternTag = iota + SystemTableReservedMin + uint64(8000)
 	DoltIgnoreIgnoredTag
 )
+
+// Tags for the dolt_tests table
+const (
+	DoltTestsNameTag = iota + SystemTableReservedMin + uint64(9000)
+	DoltTestsGroupTag
+	DoltTestsQueryTag
+	DoltTestsAssertionTypeTag
+	DoltTestsAssertionComparatorTag
+	DoltTestsAssertionValueTag
+)
+
+// Tags for the dolt_branch_protection table
+const (
+	DoltBranchProtectionPatternTag = iota + SystemTableReservedMin + uint64(10000)
+	DoltBranchProtectionAllowedUsersTag
+)
+
+// Tags for the table holding the notes in the root value of a notes commit
+const (
+	DoltNotesCommitHashTag = iota + SystemTableReservedMin + uint64(11000)
+	DoltNotesNoteTag
+)
+
+// Tags for the dolt_sequences table
+const (
+	DoltSequencesNameTag = iota + SystemTableReservedMin + uint64(13000)
+	DoltSequencesStartValueTag
+	DoltSequencesIncrementTag
+	DoltSequencesMinValueTag
+	DoltSequencesMaxValueTag
+	DoltSequencesCycleTag
+	DoltSequencesNextValueTag
+)
+
+// Tags for the dolt_assertions table
+const (
+	DoltAssertionsNameTag = iota + SystemTableReservedMin + uint64(14000)
+	DoltAssertionsTableNameTag
+	DoltAssertionsQueryTag
+)
+
+// Tags for the table holding change data capture cursors in the root value of the change cursors commit
+const (
+	DoltChangeCursorNameTag = iota + SystemTableReservedMin + uint64(15000)
+	DoltChangeCursorBranchTag
+	DoltChangeCursorCommitTag
+	DoltChangeCursorUpdatedAtTag
+)
+
+// Tags for the dolt_external_tables table
+const (
+	DoltExternalTablesNameTag = iota + SystemTableReservedMin + uint64(16000)
+	DoltExternalTablesConnectionTag
+	DoltExternalTablesRemoteTableTag
+	DoltExternalTablesWritableTag
+)
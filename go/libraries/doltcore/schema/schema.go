@@ -73,6 +73,10 @@ type Schema interface {
 	// The new column cannot be a primary key. To alter primary keys, create a new schema with those keys.
 	AddColumn(column Column, order *ColumnOrder) (Schema, error)
 
+	// DropColumn removes the column named from this schema and returns the resulting Schema. The column cannot be a
+	// primary key, or be part of an index.
+	DropColumn(name string) (Schema, error)
+
 	// GetMapDescriptors returns the key and value tuple descriptors for this schema.
 	GetMapDescriptors() (keyDesc, valueDesc val.TupleDesc)
 
@@ -307,9 +311,11 @@ func ArePrimaryKeySetsDiffable(format *types.NomsBinFormat, fromSch, toSch Schem
 // use to map key, value val.Tuple's of schema |inSch| to |outSch|. The first
 // ordinal map is for keys, and the second is for values. If a column of |inSch|
 // is missing in |outSch| then that column's index in the ordinal map holds -1.
+// Virtual columns aren't stored in value tuples, so the value mapping is
+// between the stored indexes of the non-primary key columns.
 func MapSchemaBasedOnTagAndName(inSch, outSch Schema) ([]int, []int, error) {
 	keyMapping := make([]int, inSch.GetPKCols().Size())
-	valMapping := make([]int, inSch.GetNonPKCols().Size())
+	valMapping := make([]int, inSch.GetNonPKCols().StoredSize())
 
 	// if inSch or outSch is empty schema. This can be from added or dropped table.
 	if len(inSch.GetAllCols().cols) == 0 || len(outSch.GetAllCols().cols) == 0 {
@@ -330,13 +336,20 @@ func MapSchemaBasedOnTagAndName(inSch, outSch Schema) ([]int, []int, error) {
 		return nil, nil, err
 	}
 
+	// dropped columns aren't mapped
+	for i := range valMapping {
+		valMapping[i] = -1
+	}
 	err = inSch.GetNonPKCols().Iter(func(tag uint64, col Column) (stop bool, err error) {
-		i := inSch.GetNonPKCols().TagToIdx[col.Tag]
+		i, ok := inSch.GetNonPKCols().StoredIndexByTag(col.Tag)
+		if !ok {
+			return false, nil
+		}
+		valMapping[i] = -1
 		if col, ok := outSch.GetNonPKCols().GetByName(col.Name); ok {
-			j := outSch.GetNonPKCols().TagToIdx[col.Tag]
-			valMapping[i] = j
-		} else {
-			valMapping[i] = -1
+			if j, ok := outSch.GetNonPKCols().StoredIndexByTag(col.Tag); ok {
+				valMapping[i] = j
+			}
 		}
 		return false, nil
 	})
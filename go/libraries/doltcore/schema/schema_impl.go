@@ -106,13 +106,20 @@ func SchemaFromCols(allCols *ColCollection) (Schema, error) {
 	var nonPKCols []Column
 
 	defaultPkOrds := make([]int, 0)
-	for i, c := range allCols.cols {
+	i := 0
+	for _, c := range allCols.withDropped {
+		if c.Dropped {
+			// dropped columns are stored with the non-primary key columns
+			nonPKCols = append(nonPKCols, c)
+			continue
+		}
 		if c.IsPartOfPK {
 			pkCols = append(pkCols, c)
 			defaultPkOrds = append(defaultPkOrds, i)
 		} else {
 			nonPKCols = append(nonPKCols, c)
 		}
+		i++
 	}
 
 	if len(pkCols) == 0 && !FeatureFlagKeylessSchema {
@@ -291,25 +298,22 @@ func UnkeyedSchemaFromCols(allCols *ColCollection) Schema {
 //
 // Deprecated: Use NewSchema instead.
 func SchemaFromPKAndNonPKCols(pkCols, nonPKCols *ColCollection) (Schema, error) {
-	allCols := make([]Column, pkCols.Size()+nonPKCols.Size())
+	allCols := make([]Column, 0, pkCols.Size()+len(nonPKCols.withDropped))
 
-	i := 0
 	for _, c := range pkCols.cols {
 		if !c.IsPartOfPK {
 			panic("bug: attempting to add a column to the pk that isn't part of the pk")
 		}
 
-		allCols[i] = c
-		i++
+		allCols = append(allCols, c)
 	}
 
-	for _, c := range nonPKCols.cols {
+	for _, c := range nonPKCols.withDropped {
 		if c.IsPartOfPK {
 			panic("bug: attempting to add a column that is part of the pk to the non-pk columns")
 		}
 
-		allCols[i] = c
-		i++
+		allCols = append(allCols, c)
 	}
 
 	allColColl := NewColCollection(allCols...)
@@ -426,9 +430,12 @@ func (si schemaImpl) AddColumn(newCol Column, order *ColumnOrder) (Schema, error
 		nonPkCols = append(nonPkCols, newCol)
 	}
 
-	for _, col := range si.GetAllCols().GetColumns() {
+	for _, col := range si.GetAllCols().GetColumnsWithDropped() {
 		newCols = append(newCols, col)
-		if col.IsPartOfPK {
+		if col.Dropped {
+			nonPkCols = append(nonPkCols, col)
+			continue
+		} else if col.IsPartOfPK {
 			pkCols = append(pkCols, col)
 		} else {
 			nonPkCols = append(nonPkCols, col)
@@ -461,6 +468,54 @@ func (si schemaImpl) AddColumn(newCol Column, order *ColumnOrder) (Schema, error
 	return &si, nil
 }
 
+// DropColumn implements the Schema interface. The column is kept as a dropped column, which is stored in the
+// same place, so that the table's rows don't have to be rewritten.
+func (si schemaImpl) DropColumn(name string) (Schema, error) {
+	col, ok := si.allCols.GetByNameCaseInsensitive(name)
+	if !ok {
+		return nil, fmt.Errorf("column not found: %s", name)
+	} else if col.IsPartOfPK {
+		return nil, fmt.Errorf("cannot drop a column that is a primary key: %s", col.Name)
+	} else if len(si.indexCollection.IndexesWithTag(col.Tag)) > 0 {
+		return nil, fmt.Errorf("cannot drop a column that is part of an index: %s", col.Name)
+	}
+
+	// preserve the primary key column names in their original order, which we'll need at the end
+	keyCols := make([]string, len(si.pkOrdinals))
+	for i, ordinal := range si.pkOrdinals {
+		keyCols[i] = si.allCols.GetByIndex(ordinal).Name
+	}
+
+	var newCols []Column
+	var nonPkCols []Column
+	for _, c := range si.GetAllCols().GetColumnsWithDropped() {
+		if c.Tag == col.Tag && !c.Dropped {
+			if c.Virtual {
+				// virtual columns aren't stored
+				continue
+			}
+			c.Dropped = true
+			c.AutoIncrement = false
+			c.Constraints = nil
+			c.Default = ""
+			c.Generated = ""
+			c.OnUpdate = ""
+		}
+		newCols = append(newCols, c)
+		if c.Dropped || !c.IsPartOfPK {
+			nonPkCols = append(nonPkCols, c)
+		}
+	}
+
+	si.allCols = NewColCollection(newCols...)
+	si.nonPKCols = NewColCollection(nonPkCols...)
+	si.pkOrdinals = primaryKeyOrdinals(&si, keyCols)
+	si.indexCollection = si.indexCollection.Copy()
+	si.checkCollection = si.checkCollection.Copy()
+
+	return &si, nil
+}
+
 // GetMapDescriptors implements the Schema interface.
 func (si *schemaImpl) GetMapDescriptors() (keyDesc, valueDesc val.TupleDesc) {
 	keyDesc = si.GetKeyDescriptor()
@@ -562,16 +617,15 @@ func (si *schemaImpl) GetValueDescriptor() val.TupleDesc {
 	}
 
 	useCollations := false // We only use collations if a string exists
-	_ = si.GetNonPKCols().Iter(func(tag uint64, col Column) (stop bool, err error) {
-		if col.Virtual {
-			return
-		}
-
+	nonPKCols := si.GetNonPKCols()
+	for i := 0; i < nonPKCols.StoredSize(); i++ {
+		col := nonPKCols.GetByStoredIndex(i)
 		sqlType := col.TypeInfo.ToSqlType()
 		queryType := sqlType.Type()
 		tt = append(tt, val.Type{
-			Enc:      val.Encoding(EncodingFromSqlType(sqlType)),
-			Nullable: col.IsNullable(),
+			Enc: val.Encoding(EncodingFromSqlType(sqlType)),
+			// rows written after a column is dropped store NULL for it
+			Nullable: col.IsNullable() || col.Dropped,
 		})
 		if queryType == query.Type_CHAR || queryType == query.Type_VARCHAR {
 			useCollations = true
@@ -585,8 +639,7 @@ func (si *schemaImpl) GetValueDescriptor() val.TupleDesc {
 		} else {
 			handlers = append(handlers, nil)
 		}
-		return
-	})
+	}
 
 	if useCollations {
 		if len(collations) != len(tt) {
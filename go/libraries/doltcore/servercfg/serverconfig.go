@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -37,28 +38,29 @@ const (
 )
 
 const (
-	DefaultHost                    = "localhost"
-	DefaultPort                    = 3306
-	DefaultUser                    = "root"
-	DefaultPass                    = ""
-	DefaultTimeout                 = 8 * 60 * 60 * 1000 // 8 hours, same as MySQL
-	DefaultReadOnly                = false
-	DefaultLogLevel                = LogLevel_Info
-	DefaultAutoCommit              = true
-	DefaultDoltTransactionCommit   = false
-	DefaultMaxConnections          = 100
-	DefaultQueryParallelism        = 0
-	DefaultPersistenceBahavior     = LoadPerisistentGlobals
-	DefaultDataDir                 = "."
-	DefaultCfgDir                  = ".doltcfg"
-	DefaultPrivilegeFilePath       = "privileges.db"
-	DefaultBranchControlFilePath   = "branch_control.db"
-	DefaultMetricsHost             = ""
-	DefaultMetricsPort             = -1
-	DefaultAllowCleartextPasswords = false
-	DefaultUnixSocketFilePath      = "/tmp/mysql.sock"
-	DefaultMaxLoggedQueryLen       = 0
-	DefaultEncodeLoggedQuery       = false
+	DefaultHost                     = "localhost"
+	DefaultPort                     = 3306
+	DefaultUser                     = "root"
+	DefaultPass                     = ""
+	DefaultTimeout                  = 8 * 60 * 60 * 1000 // 8 hours, same as MySQL
+	DefaultReadOnly                 = false
+	DefaultLogLevel                 = LogLevel_Info
+	DefaultAutoCommit               = true
+	DefaultDoltTransactionCommit    = false
+	DefaultMaxConnections           = 100
+	DefaultQueryParallelism         = 0
+	DefaultPersistenceBahavior      = LoadPerisistentGlobals
+	DefaultDataDir                  = "."
+	DefaultCfgDir                   = ".doltcfg"
+	DefaultPrivilegeFilePath        = "privileges.db"
+	DefaultBranchControlFilePath    = "branch_control.db"
+	DefaultMetricsHost              = ""
+	DefaultMetricsPort              = -1
+	DefaultAllowCleartextPasswords  = false
+	DefaultUnixSocketFilePath       = "/tmp/mysql.sock"
+	DefaultMaxLoggedQueryLen        = 0
+	DefaultEncodeLoggedQuery        = false
+	DefaultReceiveHookTimeoutMillis = 10_000
 )
 
 const (
@@ -97,6 +99,14 @@ type ClusterConfig interface {
 	RemotesAPIConfig() ClusterRemotesAPIConfig
 }
 
+// ReceiveHookConfig is the configuration of a webhook called for pushes received by the remotesapi interface.
+type ReceiveHookConfig interface {
+	// URL is the http or https URL each push is POSTed to as JSON.
+	URL() string
+	// TimeoutMillis is how long to wait for the webhook to respond.
+	TimeoutMillis() uint64
+}
+
 type ClusterRemotesAPIConfig interface {
 	Address() string
 	Port() int
@@ -119,6 +129,107 @@ type JwksConfig struct {
 	FieldsToLog []string          `yaml:"fields_to_log"`
 }
 
+// LDAPConfig configures authenticating users whose accounts use the authentication_dolt_ldap plugin against an LDAP
+// server. Users are authenticated by binding as the DN produced by replacing {user} in |UserDNTemplate| with their
+// user name, and their groups are the entries under |GroupBaseDN| whose |GroupMemberAttribute| is that DN.
+//
+// Binding sends the user's password to the server, so connections must be encrypted: the URL must be an ldaps URL,
+// or |StartTLS| must be set for an ldap URL. Unencrypted connections are only made if |AllowUnencrypted| is set.
+type LDAPConfig struct {
+	URL                  string        `yaml:"url"`
+	UserDNTemplate       string        `yaml:"user_dn_template"`
+	GroupBaseDN          string        `yaml:"group_base_dn,omitempty"`
+	GroupMemberAttribute string        `yaml:"group_member_attribute,omitempty"`
+	GroupNameAttribute   string        `yaml:"group_name_attribute,omitempty"`
+	StartTLS             bool          `yaml:"start_tls,omitempty"`
+	AllowUnencrypted     bool          `yaml:"allow_unencrypted,omitempty"`
+	InsecureSkipVerify   bool          `yaml:"insecure_skip_verify,omitempty"`
+	TimeoutMillis        *uint64       `yaml:"timeout_millis,omitempty"`
+	RoleMappings         []RoleMapping `yaml:"role_mappings,omitempty"`
+}
+
+// OIDCConfig configures authenticating users whose accounts use the authentication_dolt_oidc plugin with an OIDC ID
+// token, given as their password. Tokens must be signed by a key of the issuer for |Audience|, the client ID Dolt is
+// registered with, and their |UsernameClaim| must match the user name. The user's groups are read from the token's
+// |GroupsClaim|.
+type OIDCConfig struct {
+	Issuer        string        `yaml:"issuer"`
+	JwksURL       string        `yaml:"jwks_url,omitempty"`
+	Audience      string        `yaml:"audience"`
+	UsernameClaim string        `yaml:"username_claim,omitempty"`
+	GroupsClaim   string        `yaml:"groups_claim,omitempty"`
+	RoleMappings  []RoleMapping `yaml:"role_mappings,omitempty"`
+}
+
+// RoleMapping grants |Role| to the users authenticated by an LDAP or OIDC backend who are members of |Group|, and
+// revokes it from those who are not.
+type RoleMapping struct {
+	Group string `yaml:"group"`
+	Role  string `yaml:"role"`
+}
+
+// AuthPluginConfig configures an authentication plugin named |Name| which is implemented by an external |Command|.
+// Users created WITH the plugin are authenticated by running the command with |Args|, writing a JSON request with
+// their user name, host, identity and password to its stdin, and reading a JSON response from its stdout.
+type AuthPluginConfig struct {
+	Name          string   `yaml:"name"`
+	Command       string   `yaml:"command"`
+	Args          []string `yaml:"args,omitempty"`
+	TimeoutMillis *uint64  `yaml:"timeout_millis,omitempty"`
+}
+
+// HTTPAPIConfig configures serving read only queries over HTTP, for clients which don't have a MySQL driver. Queries
+// are served on |Port| of |Host|, which defaults to the listener's host. If |RequestsPerSecond| is set, each client
+// address and each user can only make that many requests per second, after a burst of |Burst| requests, and failed
+// logins count against the address's limit. Connections are encrypted with |TLSKey| and |TLSCert| if they are given.
+type HTTPAPIConfig struct {
+	Host              string  `yaml:"host,omitempty"`
+	Port              int     `yaml:"port"`
+	RequestsPerSecond float64 `yaml:"requests_per_second,omitempty"`
+	Burst             int     `yaml:"burst,omitempty"`
+	TLSKey            string  `yaml:"tls_key,omitempty"`
+	TLSCert           string  `yaml:"tls_cert,omitempty"`
+}
+
+// ChangeDataCaptureConfig configures the sinks which the row level changes read with change cursors are delivered to
+// as they're committed.
+type ChangeDataCaptureConfig struct {
+	Webhooks []ChangeWebhookConfig `yaml:"webhooks,omitempty"`
+	Kafka    []ChangeKafkaConfig   `yaml:"kafka,omitempty"`
+}
+
+// ChangeWebhookConfig configures POSTing the changes read with the change cursor |Cursor| of |Database| to |URL|, in
+// batches of at most |BatchSize| changes. The cursor's branch is checked for new commits every |PollIntervalMillis|.
+type ChangeWebhookConfig struct {
+	Database           string  `yaml:"database"`
+	Cursor             string  `yaml:"cursor"`
+	URL                string  `yaml:"url"`
+	BatchSize          int     `yaml:"batch_size,omitempty"`
+	PollIntervalMillis *uint64 `yaml:"poll_interval_millis,omitempty"`
+	TimeoutMillis      *uint64 `yaml:"timeout_millis,omitempty"`
+}
+
+// ChangeKafkaConfig configures producing the changes read with the change cursor |Cursor| of |Database| to Kafka, as
+// Debezium change events in a topic per table named <TopicPrefix>.<database>.<table>.
+type ChangeKafkaConfig struct {
+	Database           string   `yaml:"database"`
+	Cursor             string   `yaml:"cursor"`
+	Brokers            []string `yaml:"brokers"`
+	TopicPrefix        string   `yaml:"topic_prefix,omitempty"`
+	BatchSize          int      `yaml:"batch_size,omitempty"`
+	PollIntervalMillis *uint64  `yaml:"poll_interval_millis,omitempty"`
+	TimeoutMillis      *uint64  `yaml:"timeout_millis,omitempty"`
+}
+
+const (
+	DefaultAuthPluginTimeoutMillis  = 10_000
+	DefaultLDAPTimeoutMillis        = 10_000
+	DefaultLDAPGroupMemberAttribute = "member"
+	DefaultLDAPGroupNameAttribute   = "cn"
+	DefaultOIDCUsernameClaim        = "sub"
+	DefaultOIDCGroupsClaim          = "groups"
+)
+
 // ServerConfig contains all of the configurable options for the MySQL-compatible server.
 type ServerConfig interface {
 	// Host returns the domain that the server will run on. Accepts an IPv4 or IPv6 address, in addition to localhost.
@@ -185,10 +296,29 @@ type ServerConfig interface {
 	SystemVars() map[string]interface{}
 	// JwksConfig is an array containing jwks config
 	JwksConfig() []JwksConfig
+	// LDAPConfig is the configuration of the authentication_dolt_ldap plugin, or nil if it is not configured.
+	LDAPConfig() *LDAPConfig
+	// OIDCConfig is the configuration of the authentication_dolt_oidc plugin, or nil if it is not configured.
+	OIDCConfig() *OIDCConfig
+	// AuthPlugins are authentication plugins implemented by external commands.
+	AuthPlugins() []AuthPluginConfig
+	// HTTPAPIConfig is the configuration of the HTTP SQL API, or nil if it should not be served.
+	HTTPAPIConfig() *HTTPAPIConfig
+	// ChangeDataCaptureConfig is the configuration of the change data capture sinks, or nil if there are none.
+	ChangeDataCaptureConfig() *ChangeDataCaptureConfig
 	// AllowCleartextPasswords is true if the server should accept cleartext passwords.
 	AllowCleartextPasswords() bool
 	// Socket is a path to the unix socket file
 	Socket() string
+	// XPort is the port to serve the MySQL X Protocol on, for clients using the X DevAPI. The X Protocol isn't served
+	// if it is nil.
+	XPort() *int
+	// PostgresPort is the port to serve the PostgreSQL protocol on, for tools which only have PostgreSQL connectors.
+	// The PostgreSQL protocol isn't served if it is nil.
+	PostgresPort() *int
+	// FlightSQLPort is the port to serve Arrow Flight SQL on, for analytics clients which fetch results in columnar
+	// form. Arrow Flight SQL isn't served if it is nil.
+	FlightSQLPort() *int
 	// RemotesapiPort is the port to use for serving a remotesapi interface with this sql-server instance.
 	// A remotesapi interface will allow this sql-server process to be used
 	// as a dolt remote for things like `clone`, `fetch` and read
@@ -196,6 +326,10 @@ type ServerConfig interface {
 	RemotesapiPort() *int
 	// RemotesapiReadOnly is true if the remotesapi interface should be read only.
 	RemotesapiReadOnly() *bool
+	// RemotesapiPreReceiveHooks are webhooks which can accept or reject each push to the remotesapi interface.
+	RemotesapiPreReceiveHooks() []ReceiveHookConfig
+	// RemotesapiPostReceiveHooks are webhooks which are notified of each push to the remotesapi interface.
+	RemotesapiPostReceiveHooks() []ReceiveHookConfig
 	// ClusterConfig is the configuration for clustering in this sql-server.
 	ClusterConfig() ClusterConfig
 	// EventSchedulerStatus is the configuration for enabling or disabling the event scheduler in this server.
@@ -269,9 +403,154 @@ func ValidateConfig(config ServerConfig) error {
 	if config.RequireSecureTransport() && config.TLSCert() == "" && config.TLSKey() == "" {
 		return fmt.Errorf("require_secure_transport can only be `true` when a tls_key and tls_cert are provided.")
 	}
+	if err := validateReceiveHooks("pre_receive_hooks", config.RemotesapiPreReceiveHooks()); err != nil {
+		return err
+	}
+	if err := validateReceiveHooks("post_receive_hooks", config.RemotesapiPostReceiveHooks()); err != nil {
+		return err
+	}
+	if err := validateLDAPConfig(config.LDAPConfig()); err != nil {
+		return err
+	}
+	if err := validateOIDCConfig(config.OIDCConfig()); err != nil {
+		return err
+	}
+	if err := validateAuthPlugins(config.AuthPlugins()); err != nil {
+		return err
+	}
+	if err := validateHTTPAPIConfig(config.HTTPAPIConfig()); err != nil {
+		return err
+	}
+	if err := validateChangeDataCaptureConfig(config.ChangeDataCaptureConfig()); err != nil {
+		return err
+	}
 	return ValidateClusterConfig(config.ClusterConfig())
 }
 
+func validateReceiveHooks(name string, hooks []ReceiveHookConfig) error {
+	for _, h := range hooks {
+		u, err := url.Parse(h.URL())
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("remotesapi: %s: url: is not a valid http or https URL: %q", name, h.URL())
+		}
+	}
+	return nil
+}
+
+func validateLDAPConfig(cfg *LDAPConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+		return fmt.Errorf("ldap: url: is not a valid ldap or ldaps URL: %q", cfg.URL)
+	}
+	if u.Scheme == "ldaps" && cfg.StartTLS {
+		return fmt.Errorf("ldap: start_tls: cannot be used with an ldaps URL, which is already encrypted")
+	}
+	if u.Scheme == "ldap" && !cfg.StartTLS && !cfg.AllowUnencrypted {
+		return fmt.Errorf("ldap: url: would send passwords unencrypted: use an ldaps URL, or set start_tls or allow_unencrypted: %q", cfg.URL)
+	}
+	if !strings.Contains(cfg.UserDNTemplate, "{user}") {
+		return fmt.Errorf("ldap: user_dn_template: must contain {user}: %q", cfg.UserDNTemplate)
+	}
+	if len(cfg.RoleMappings) > 0 && cfg.GroupBaseDN == "" {
+		return fmt.Errorf("ldap: group_base_dn: is required when role_mappings are given")
+	}
+	return validateRoleMappings("ldap", cfg.RoleMappings)
+}
+
+func validateOIDCConfig(cfg *OIDCConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	u, err := url.Parse(cfg.Issuer)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("oidc: issuer: is not a valid http or https URL: %q", cfg.Issuer)
+	}
+	if cfg.Audience == "" {
+		return fmt.Errorf("oidc: audience: is required")
+	}
+	return validateRoleMappings("oidc", cfg.RoleMappings)
+}
+
+func validateAuthPlugins(plugins []AuthPluginConfig) error {
+	seen := make(map[string]struct{})
+	for _, p := range plugins {
+		if p.Name == "" || p.Command == "" {
+			return fmt.Errorf("auth_plugins: name and command are required: %+v", p)
+		}
+		if _, ok := seen[p.Name]; ok {
+			return fmt.Errorf("auth_plugins: name: %q is given more than once", p.Name)
+		}
+		seen[p.Name] = struct{}{}
+	}
+	return nil
+}
+
+func validateHTTPAPIConfig(cfg *HTTPAPIConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Host != "" && cfg.Host != "localhost" && net.ParseIP(cfg.Host) == nil {
+		return fmt.Errorf("http_api: host: is not a valid IP: %q", cfg.Host)
+	}
+	if cfg.Port < 1 || cfg.Port > 65535 {
+		return fmt.Errorf("http_api: port: is not in range 1-65535: %d", cfg.Port)
+	}
+	if cfg.RequestsPerSecond < 0 {
+		return fmt.Errorf("http_api: requests_per_second: must not be negative: %v", cfg.RequestsPerSecond)
+	}
+	if (cfg.TLSKey == "") != (cfg.TLSCert == "") {
+		return fmt.Errorf("http_api: tls_key and tls_cert must be given together")
+	}
+	return nil
+}
+
+func validateChangeDataCaptureConfig(cfg *ChangeDataCaptureConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	for _, w := range cfg.Webhooks {
+		if w.Database == "" || w.Cursor == "" {
+			return fmt.Errorf("change_data_capture: webhooks: database and cursor must be given")
+		}
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("change_data_capture: webhooks: url: is not a valid http or https URL: %q", w.URL)
+		}
+		if w.BatchSize < 0 {
+			return fmt.Errorf("change_data_capture: webhooks: batch_size: must not be negative: %d", w.BatchSize)
+		}
+	}
+	for _, k := range cfg.Kafka {
+		if k.Database == "" || k.Cursor == "" {
+			return fmt.Errorf("change_data_capture: kafka: database and cursor must be given")
+		}
+		if len(k.Brokers) == 0 {
+			return fmt.Errorf("change_data_capture: kafka: brokers: at least one broker must be given")
+		}
+		for _, b := range k.Brokers {
+			if _, _, err := net.SplitHostPort(b); err != nil {
+				return fmt.Errorf("change_data_capture: kafka: brokers: is not a valid host:port: %q", b)
+			}
+		}
+		if k.BatchSize < 0 {
+			return fmt.Errorf("change_data_capture: kafka: batch_size: must not be negative: %d", k.BatchSize)
+		}
+	}
+	return nil
+}
+
+func validateRoleMappings(name string, mappings []RoleMapping) error {
+	for _, m := range mappings {
+		if m.Group == "" || m.Role == "" {
+			return fmt.Errorf("%s: role_mappings: group and role are required: %+v", name, m)
+		}
+	}
+	return nil
+}
+
 const (
 	MaxConnectionsKey = "max_connections"
 	ReadTimeoutKey    = "net_read_timeout"
@@ -90,6 +90,12 @@ type ListenerYAMLConfig struct {
 	AllowCleartextPasswords *bool `yaml:"allow_cleartext_passwords"`
 	// Socket is unix socket file path
 	Socket *string `yaml:"socket,omitempty"`
+	// XPort is the port to serve the MySQL X Protocol on.
+	XPort *int `yaml:"x_port,omitempty" minver:"TBD"`
+	// PostgresPort is the port to serve the PostgreSQL protocol on.
+	PostgresPort *int `yaml:"postgres_port,omitempty" minver:"TBD"`
+	// FlightSQLPort is the port to serve Arrow Flight SQL on.
+	FlightSQLPort *int `yaml:"flight_sql_port,omitempty" minver:"TBD"`
 }
 
 // PerformanceYAMLConfig contains configuration parameters for performance tweaking
@@ -104,8 +110,10 @@ type MetricsYAMLConfig struct {
 }
 
 type RemotesapiYAMLConfig struct {
-	Port_     *int  `yaml:"port,omitempty"`
-	ReadOnly_ *bool `yaml:"read_only,omitempty" minver:"1.30.5"`
+	Port_             *int                    `yaml:"port,omitempty"`
+	ReadOnly_         *bool                   `yaml:"read_only,omitempty" minver:"1.30.5"`
+	PreReceiveHooks_  []ReceiveHookYAMLConfig `yaml:"pre_receive_hooks,omitempty" minver:"TBD"`
+	PostReceiveHooks_ []ReceiveHookYAMLConfig `yaml:"post_receive_hooks,omitempty" minver:"TBD"`
 }
 
 func (r RemotesapiYAMLConfig) Port() int {
@@ -116,6 +124,24 @@ func (r RemotesapiYAMLConfig) ReadOnly() bool {
 	return *r.ReadOnly_
 }
 
+// ReceiveHookYAMLConfig is a webhook which is called with a JSON description
+// of every push received by the remotesapi server.
+type ReceiveHookYAMLConfig struct {
+	URL_           string  `yaml:"url"`
+	TimeoutMillis_ *uint64 `yaml:"timeout_millis,omitempty"`
+}
+
+func (h ReceiveHookYAMLConfig) URL() string {
+	return h.URL_
+}
+
+func (h ReceiveHookYAMLConfig) TimeoutMillis() uint64 {
+	if h.TimeoutMillis_ == nil {
+		return DefaultReceiveHookTimeoutMillis
+	}
+	return *h.TimeoutMillis_
+}
+
 type UserSessionVars struct {
 	Name string            `yaml:"name"`
 	Vars map[string]string `yaml:"vars"`
@@ -138,10 +164,15 @@ type YAMLConfig struct {
 	PrivilegeFile     *string               `yaml:"privilege_file,omitempty"`
 	BranchControlFile *string               `yaml:"branch_control_file,omitempty"`
 	// TODO: Rename to UserVars_
-	Vars            []UserSessionVars      `yaml:"user_session_vars"`
-	SystemVars_     map[string]interface{} `yaml:"system_variables,omitempty" minver:"1.11.1"`
-	Jwks            []JwksConfig           `yaml:"jwks"`
-	GoldenMysqlConn *string                `yaml:"golden_mysql_conn,omitempty"`
+	Vars               []UserSessionVars        `yaml:"user_session_vars"`
+	SystemVars_        map[string]interface{}   `yaml:"system_variables,omitempty" minver:"1.11.1"`
+	Jwks               []JwksConfig             `yaml:"jwks"`
+	LDAP_              *LDAPConfig              `yaml:"ldap,omitempty" minver:"TBD"`
+	OIDC_              *OIDCConfig              `yaml:"oidc,omitempty" minver:"TBD"`
+	AuthPlugins_       []AuthPluginConfig       `yaml:"auth_plugins,omitempty" minver:"TBD"`
+	HTTPAPI_           *HTTPAPIConfig           `yaml:"http_api,omitempty" minver:"TBD"`
+	ChangeDataCapture_ *ChangeDataCaptureConfig `yaml:"change_data_capture,omitempty" minver:"TBD"`
+	GoldenMysqlConn    *string                  `yaml:"golden_mysql_conn,omitempty"`
 }
 
 var _ ServerConfig = YAMLConfig{}
@@ -202,6 +233,9 @@ func ServerConfigAsYAMLConfig(cfg ServerConfig) *YAMLConfig {
 			nillableBoolPtr(cfg.RequireSecureTransport()),
 			nillableBoolPtr(cfg.AllowCleartextPasswords()),
 			nillableStrPtr(cfg.Socket()),
+			cfg.XPort(),
+			cfg.PostgresPort(),
+			cfg.FlightSQLPort(),
 		},
 		PerformanceConfig: PerformanceYAMLConfig{
 			QueryParallelism: nillableIntPtr(cfg.QueryParallelism()),
@@ -214,15 +248,22 @@ func ServerConfigAsYAMLConfig(cfg ServerConfig) *YAMLConfig {
 			Port:   ptr(cfg.MetricsPort()),
 		},
 		RemotesapiConfig: RemotesapiYAMLConfig{
-			Port_:     cfg.RemotesapiPort(),
-			ReadOnly_: cfg.RemotesapiReadOnly(),
+			Port_:             cfg.RemotesapiPort(),
+			ReadOnly_:         cfg.RemotesapiReadOnly(),
+			PreReceiveHooks_:  receiveHooksAsYAMLConfig(cfg.RemotesapiPreReceiveHooks()),
+			PostReceiveHooks_: receiveHooksAsYAMLConfig(cfg.RemotesapiPostReceiveHooks()),
 		},
-		ClusterCfg:        clusterConfigAsYAMLConfig(cfg.ClusterConfig()),
-		PrivilegeFile:     ptr(cfg.PrivilegeFilePath()),
-		BranchControlFile: ptr(cfg.BranchControlFilePath()),
-		SystemVars_:       systemVars,
-		Vars:              cfg.UserVars(),
-		Jwks:              cfg.JwksConfig(),
+		ClusterCfg:         clusterConfigAsYAMLConfig(cfg.ClusterConfig()),
+		PrivilegeFile:      ptr(cfg.PrivilegeFilePath()),
+		BranchControlFile:  ptr(cfg.BranchControlFilePath()),
+		SystemVars_:        systemVars,
+		Vars:               cfg.UserVars(),
+		Jwks:               cfg.JwksConfig(),
+		LDAP_:              cfg.LDAPConfig(),
+		OIDC_:              cfg.OIDCConfig(),
+		AuthPlugins_:       cfg.AuthPlugins(),
+		HTTPAPI_:           cfg.HTTPAPIConfig(),
+		ChangeDataCapture_: cfg.ChangeDataCaptureConfig(),
 	}
 }
 
@@ -427,6 +468,36 @@ func (cfg YAMLConfig) RemotesapiReadOnly() *bool {
 	return cfg.RemotesapiConfig.ReadOnly_
 }
 
+func (cfg YAMLConfig) RemotesapiPreReceiveHooks() []ReceiveHookConfig {
+	return receiveHookConfigs(cfg.RemotesapiConfig.PreReceiveHooks_)
+}
+
+func (cfg YAMLConfig) RemotesapiPostReceiveHooks() []ReceiveHookConfig {
+	return receiveHookConfigs(cfg.RemotesapiConfig.PostReceiveHooks_)
+}
+
+func receiveHookConfigs(hooks []ReceiveHookYAMLConfig) []ReceiveHookConfig {
+	if hooks == nil {
+		return nil
+	}
+	ret := make([]ReceiveHookConfig, len(hooks))
+	for i := range hooks {
+		ret[i] = hooks[i]
+	}
+	return ret
+}
+
+func receiveHooksAsYAMLConfig(hooks []ReceiveHookConfig) []ReceiveHookYAMLConfig {
+	if hooks == nil {
+		return nil
+	}
+	ret := make([]ReceiveHookYAMLConfig, len(hooks))
+	for i, h := range hooks {
+		ret[i] = ReceiveHookYAMLConfig{URL_: h.URL(), TimeoutMillis_: ptr(h.TimeoutMillis())}
+	}
+	return ret
+}
+
 // PrivilegeFilePath returns the path to the file which contains all needed privilege information in the form of a
 // JSON string.
 func (cfg YAMLConfig) PrivilegeFilePath() string {
@@ -469,6 +540,26 @@ func (cfg YAMLConfig) JwksConfig() []JwksConfig {
 	return nil
 }
 
+func (cfg YAMLConfig) LDAPConfig() *LDAPConfig {
+	return cfg.LDAP_
+}
+
+func (cfg YAMLConfig) OIDCConfig() *OIDCConfig {
+	return cfg.OIDC_
+}
+
+func (cfg YAMLConfig) AuthPlugins() []AuthPluginConfig {
+	return cfg.AuthPlugins_
+}
+
+func (cfg YAMLConfig) HTTPAPIConfig() *HTTPAPIConfig {
+	return cfg.HTTPAPI_
+}
+
+func (cfg YAMLConfig) ChangeDataCaptureConfig() *ChangeDataCaptureConfig {
+	return cfg.ChangeDataCapture_
+}
+
 func (cfg YAMLConfig) AllowCleartextPasswords() bool {
 	if cfg.ListenerConfig.AllowCleartextPasswords == nil {
 		return DefaultAllowCleartextPasswords
@@ -564,6 +655,21 @@ func (cfg YAMLConfig) Socket() string {
 	return *cfg.ListenerConfig.Socket
 }
 
+// XPort is the port to serve the MySQL X Protocol on, or nil if it shouldn't be served.
+func (cfg YAMLConfig) XPort() *int {
+	return cfg.ListenerConfig.XPort
+}
+
+// PostgresPort is the port to serve the PostgreSQL protocol on, or nil if it shouldn't be served.
+func (cfg YAMLConfig) PostgresPort() *int {
+	return cfg.ListenerConfig.PostgresPort
+}
+
+// FlightSQLPort is the port to serve Arrow Flight SQL on, or nil if it shouldn't be served.
+func (cfg YAMLConfig) FlightSQLPort() *int {
+	return cfg.ListenerConfig.FlightSQLPort
+}
+
 func (cfg YAMLConfig) GoldenMysqlConnectionString() (s string) {
 	if cfg.GoldenMysqlConn != nil {
 		s = *cfg.GoldenMysqlConn
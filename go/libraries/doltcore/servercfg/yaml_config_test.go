@@ -158,6 +158,102 @@ remotesapi:
 	require.Equal(t, 8000, *config.RemotesapiPort())
 }
 
+func TestUnmarshallHTTPAPI(t *testing.T) {
+	testStr := `
+http_api:
+  port: 8080
+  requests_per_second: 2.5
+  burst: 5
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.HTTPAPIConfig())
+	require.Equal(t, HTTPAPIConfig{Port: 8080, RequestsPerSecond: 2.5, Burst: 5}, *config.HTTPAPIConfig())
+	require.NoError(t, ValidateConfig(config))
+
+	for _, testStr := range []string{
+		"http_api:\n  host: example.com\n  port: 8080\n",
+		"http_api:\n  port: 0\n",
+		"http_api:\n  port: 8080\n  requests_per_second: -1\n",
+		"http_api:\n  port: 8080\n  tls_key: key.pem\n",
+	} {
+		config, err = NewYamlConfig([]byte(testStr))
+		require.NoError(t, err)
+		assert.Error(t, ValidateConfig(config), testStr)
+	}
+}
+
+func TestUnmarshallChangeDataCapture(t *testing.T) {
+	testStr := `
+change_data_capture:
+  webhooks:
+  - database: mydb
+    cursor: warehouse
+    url: https://example.com/changes
+    batch_size: 100
+    poll_interval_millis: 500
+  kafka:
+  - database: mydb
+    cursor: stream
+    brokers: [kafka-1:9092, kafka-2:9092]
+    topic_prefix: cdc
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.ChangeDataCaptureConfig())
+	pollInterval := uint64(500)
+	require.Equal(t, []ChangeWebhookConfig{{
+		Database:           "mydb",
+		Cursor:             "warehouse",
+		URL:                "https://example.com/changes",
+		BatchSize:          100,
+		PollIntervalMillis: &pollInterval,
+	}}, config.ChangeDataCaptureConfig().Webhooks)
+	require.Equal(t, []ChangeKafkaConfig{{
+		Database:    "mydb",
+		Cursor:      "stream",
+		Brokers:     []string{"kafka-1:9092", "kafka-2:9092"},
+		TopicPrefix: "cdc",
+	}}, config.ChangeDataCaptureConfig().Kafka)
+	require.NoError(t, ValidateConfig(config))
+
+	for _, testStr := range []string{
+		"change_data_capture:\n  webhooks:\n  - cursor: warehouse\n    url: https://example.com\n",
+		"change_data_capture:\n  webhooks:\n  - database: mydb\n    cursor: warehouse\n    url: kafka://example.com\n",
+		"change_data_capture:\n  webhooks:\n  - database: mydb\n    cursor: warehouse\n    url: https://example.com\n    batch_size: -1\n",
+		"change_data_capture:\n  kafka:\n  - database: mydb\n    cursor: stream\n",
+		"change_data_capture:\n  kafka:\n  - database: mydb\n    cursor: stream\n    brokers: [kafka-1]\n",
+	} {
+		config, err = NewYamlConfig([]byte(testStr))
+		require.NoError(t, err)
+		assert.Error(t, ValidateConfig(config), testStr)
+	}
+}
+
+func TestValidateLDAPAndOIDC(t *testing.T) {
+	for _, testStr := range []string{
+		"ldap:\n  url: ldaps://ldap.example.com\n  user_dn_template: uid={user},dc=example,dc=com\n",
+		"ldap:\n  url: ldap://ldap.example.com\n  user_dn_template: uid={user},dc=example,dc=com\n  start_tls: true\n",
+		"ldap:\n  url: ldap://ldap.example.com\n  user_dn_template: uid={user},dc=example,dc=com\n  allow_unencrypted: true\n",
+		"oidc:\n  issuer: https://accounts.example.com\n  audience: dolt\n",
+	} {
+		config, err := NewYamlConfig([]byte(testStr))
+		require.NoError(t, err)
+		assert.NoError(t, ValidateConfig(config), testStr)
+	}
+
+	for _, testStr := range []string{
+		"ldap:\n  url: ldap://ldap.example.com\n  user_dn_template: uid={user},dc=example,dc=com\n",
+		"ldap:\n  url: ldaps://ldap.example.com\n  user_dn_template: uid={user},dc=example,dc=com\n  start_tls: true\n",
+		"ldap:\n  url: ldaps://ldap.example.com\n  user_dn_template: dc=example,dc=com\n",
+		"oidc:\n  issuer: https://accounts.example.com\n",
+	} {
+		config, err := NewYamlConfig([]byte(testStr))
+		require.NoError(t, err)
+		assert.Error(t, ValidateConfig(config), testStr)
+	}
+}
+
 func TestUnmarshallCluster(t *testing.T) {
 	testStr := `
 cluster:
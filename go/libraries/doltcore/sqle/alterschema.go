@@ -18,13 +18,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor/creation"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 // renameTable renames a table with in a RootValue and returns the updated root.
@@ -93,6 +100,23 @@ func addColumnToTable(
 	return newTable.AddColumnToRows(ctx, newColName, newSchema)
 }
 
+// dropColumnFromTable drops the column named from the table given and returns the new table value. Only the schema
+// is changed: rows keep the dropped column's values in place until the table is next rewritten. The column must not
+// be part of the primary key or any index, so the keys of rows and indexes are unaffected.
+func dropColumnFromTable(ctx context.Context, tbl *doltdb.Table, colName string) (*doltdb.Table, error) {
+	oldSchema, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newSchema, err := oldSchema.DropColumn(colName)
+	if err != nil {
+		return nil, err
+	}
+
+	return tbl.UpdateSchema(ctx, newSchema)
+}
+
 func orderToOrder(order *sql.ColumnOrder) *schema.ColumnOrder {
 	if order == nil {
 		return nil
@@ -233,12 +257,13 @@ func replaceColumnInSchema(sch schema.Schema, oldCol schema.Column, newCol schem
 		newCols = append(newCols, newCol)
 	}
 
-	for _, col := range sch.GetAllCols().GetColumns() {
-		if col.Name != oldCol.Name {
+	// dropped columns keep their place, since they're still stored in the table's rows
+	for _, col := range sch.GetAllCols().GetColumnsWithDropped() {
+		if col.Dropped || col.Name != oldCol.Name {
 			newCols = append(newCols, col)
 		}
 
-		if order.AfterColumn == col.Name {
+		if !col.Dropped && order.AfterColumn == col.Name {
 			newCols = append(newCols, newCol)
 		}
 	}
@@ -368,3 +393,194 @@ func backupFkcIndexesForPkDrop(ctx *sql.Context, tbl string, sch schema.Schema,
 	}
 	return fkUpdates, nil
 }
+
+// convertTableCollation converts every character string column of |tbl| to |collation|, which also becomes the
+// table's default collation, as ALTER TABLE ... CONVERT TO CHARACTER SET does. Rows are stored as UTF-8 whatever
+// their character set, so their values only need to be checked to be representable in the new character set, but
+// the primary and secondary indexes are ordered by their collations and are rebuilt. Keys are ordered by the sorter
+// go-mysql-server implements for each collation, such as utf8mb4_unicode_520_ci or the accent insensitive
+// utf8mb4_*_0900_ai_ci collations. It knows the names of some collations it has no sorter for, which are rejected
+// before the table is changed.
+func convertTableCollation(ctx *sql.Context, tbl *doltdb.Table, tableName string, collation sql.CollationID) (*doltdb.Table, error) {
+	if !types.IsFormat_DOLT(tbl.Format()) {
+		return nil, fmt.Errorf("converting the collations of columns is not supported for this storage format")
+	}
+	if collation.CharacterSet().Encoder() == nil {
+		return nil, sql.ErrCharSetNotYetImplementedTemp.New(collation.CharacterSet().Name())
+	} else if collation.Sorter() == nil {
+		return nil, sql.ErrCollationNotYetImplementedTemp.New(collation.Name())
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if sch.Indexes().ContainsFullTextIndex() {
+		return nil, fmt.Errorf("converting the collations of columns is not yet supported for tables with Full-Text indexes")
+	}
+
+	newSch := sch
+	converted := make(map[uint64]sql.StringType)
+	for _, col := range sch.GetAllCols().GetColumns() {
+		st, ok := col.TypeInfo.ToSqlType().(sql.StringType)
+		if !ok || st.Collation() == sql.Collation_binary || st.Collation() == collation {
+			continue
+		}
+		newType, err := gmstypes.CreateString(st.Type(), st.MaxCharacterLength(), collation)
+		if err != nil {
+			return nil, err
+		}
+		newCol := col
+		newCol.TypeInfo, err = typeinfo.FromSqlType(newType)
+		if err != nil {
+			return nil, err
+		}
+		newSch, err = replaceColumnInSchema(newSch, col, newCol, nil)
+		if err != nil {
+			return nil, err
+		}
+		converted[col.Tag] = newType
+	}
+	newSch.SetCollation(schema.Collation(collation))
+	newSch.SetComment(sch.GetComment())
+
+	tbl, err = tbl.UpdateSchema(ctx, newSch)
+	if err != nil {
+		return nil, err
+	}
+	if len(converted) == 0 {
+		return tbl, nil
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	primary := durable.ProllyMapFromIndex(rowData)
+	if err = checkConvertedValues(ctx, primary, sch, converted); err != nil {
+		return nil, err
+	}
+
+	// keyless tables are keyed by the hash of their rows, which doesn't depend on collations
+	if !schema.IsKeyless(newSch) {
+		primary, err = reorderPrimaryIndex(ctx, tbl.ValueReadWriter(), primary, newSch)
+		if err != nil {
+			return nil, err
+		}
+		tbl, err = tbl.UpdateRows(ctx, durable.IndexFromProllyMap(primary))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, idx := range newSch.Indexes().AllIndexes() {
+		idxData, err := creation.BuildSecondaryProllyIndex(ctx, tbl.ValueReadWriter(), tbl.NodeStore(), newSch, tableName, idx, primary)
+		if err != nil {
+			return nil, err
+		}
+		tbl, err = tbl.SetIndexRows(ctx, idx.Name(), idxData)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tbl, nil
+}
+
+// checkConvertedValues returns an error if any value of the columns in |converted| can't be represented in the
+// character set of its new type.
+func checkConvertedValues(ctx *sql.Context, primary prolly.Map, sch schema.Schema, converted map[uint64]sql.StringType) error {
+	kd, vd := primary.Descriptors()
+	keyless := schema.IsKeyless(sch)
+
+	type field struct {
+		inKey bool
+		idx   int
+		typ   sql.StringType
+	}
+	var fields []field
+	for i, col := range sch.GetPKCols().GetColumns() {
+		if typ, ok := converted[col.Tag]; ok {
+			fields = append(fields, field{inKey: true, idx: i, typ: typ})
+		}
+	}
+	offset := 0
+	if keyless {
+		// the first field of a keyless row is its cardinality
+		offset = 1
+	}
+	for _, col := range sch.GetNonPKCols().GetColumns() {
+		valIdx, ok := sch.GetNonPKCols().StoredIndexByTag(col.Tag)
+		if !ok || col.Virtual {
+			continue
+		}
+		if typ, ok := converted[col.Tag]; ok {
+			fields = append(fields, field{idx: offset + valIdx, typ: typ})
+		}
+	}
+
+	iter, err := primary.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			var fv interface{}
+			if f.inKey {
+				fv, err = tree.GetField(ctx, kd, f.idx, k, primary.NodeStore())
+			} else {
+				fv, err = tree.GetField(ctx, vd, f.idx, v, primary.NodeStore())
+			}
+			if err != nil {
+				return err
+			}
+			if fv == nil {
+				continue
+			}
+			if _, _, err = f.typ.Convert(fv); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// reorderPrimaryIndex returns the rows of |primary| in a new map ordered by the key collations of |newSch|. Keys which
+// differed under the old collations but are equal under the new ones are a duplicate primary key error.
+func reorderPrimaryIndex(ctx *sql.Context, vrw types.ValueReadWriter, primary prolly.Map, newSch schema.Schema) (prolly.Map, error) {
+	empty, err := durable.NewEmptyIndex(ctx, vrw, primary.NodeStore(), newSch)
+	if err != nil {
+		return prolly.Map{}, err
+	}
+	mut := durable.ProllyMapFromIndex(empty).Mutate()
+	kd, _ := mut.Descriptors()
+
+	iter, err := primary.IterAll(ctx)
+	if err != nil {
+		return prolly.Map{}, err
+	}
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return prolly.Map{}, err
+		}
+
+		ok, err := mut.Has(ctx, k)
+		if err != nil {
+			return prolly.Map{}, err
+		}
+		if ok {
+			keyStr := creation.FormatKeyForUniqKeyErr(k, kd)
+			return prolly.Map{}, sql.NewUniqueKeyErr(keyStr, true, nil)
+		}
+		if err = mut.Put(ctx, k, v); err != nil {
+			return prolly.Map{}, err
+		}
+	}
+	return mut.Map(ctx)
+}
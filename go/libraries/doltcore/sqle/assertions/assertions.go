@@ -0,0 +1,227 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gms "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// result is an assertion and the rows returned by its query, which violate it.
+type result struct {
+	doltdb.Assertion
+	Rows []sql.Row
+}
+
+// check runs the query of every assertion in the dolt_assertions table of the database |dbName| with |e|.
+func check(ctx *sql.Context, e *gms.Engine, dbName string) ([]result, error) {
+	query := fmt.Sprintf("SELECT %s, %s, %s FROM `%s`.%s ORDER BY %s",
+		doltdb.AssertionsNameCol, doltdb.AssertionsTableNameCol, doltdb.AssertionsQueryCol,
+		strings.ReplaceAll(dbName, "`", "``"), doltdb.AssertionsTableName, doltdb.AssertionsNameCol)
+	rows, err := runQuery(ctx, e, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]result, len(rows))
+	for i, row := range rows {
+		a := doltdb.Assertion{
+			Name:      row[0].(string),
+			TableName: row[1].(string),
+			Query:     row[2].(string),
+		}
+		if err = a.Validate(); err != nil {
+			return nil, err
+		}
+		violations, err := runQuery(ctx, e, a.Query)
+		if err != nil {
+			return nil, fmt.Errorf("error checking assertion '%s': %w", a.Name, err)
+		}
+		results[i] = result{Assertion: a, Rows: violations}
+	}
+	return results, nil
+}
+
+// runQuery runs |query| with |e| as part of the current statement, and returns its rows.
+func runQuery(ctx *sql.Context, e *gms.Engine, query string) ([]sql.Row, error) {
+	node, err := e.AnalyzeQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// The query mustn't end the current one in the process list, or commit its transaction
+	for unwrapped := false; !unwrapped; {
+		switch n := node.(type) {
+		case *plan.QueryProcess:
+			node = n.Child()
+		case *plan.TransactionCommittingNode:
+			node = n.Child()
+		default:
+			unwrapped = true
+		}
+	}
+
+	iter, err := e.Analyzer.ExecBuilder.Build(ctx, node, nil)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowIterToRows(ctx, iter)
+}
+
+// recordViolations records the rows violating each assertion in |results| as constraint violations in the working set
+// of the database |dbName|.
+func recordViolations(ctx *sql.Context, dbName string, results []result) error {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return err
+	}
+
+	root := roots.Working
+	changed := false
+	for _, r := range results {
+		if len(r.Rows) == 0 {
+			continue
+		}
+		root, err = recordAssertionViolations(ctx, root, headHash, r)
+		if err != nil {
+			return err
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return dSess.SetWorkingRoot(ctx, dbName, root)
+}
+
+// recordAssertionViolations records a check constraint violation named after the assertion of |r| for each row of its
+// table returned by its query, which start with the primary key of the violating row.
+func recordAssertionViolations(ctx *sql.Context, root doltdb.RootValue, srcHash hash.Hash, r result) (doltdb.RootValue, error) {
+	tbl, tblName, ok, err := doltdb.GetTableInsensitive(ctx, root, doltdb.TableName{Name: r.TableName})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrTableNotFound.New(r.TableName)
+	}
+	if tbl.Format() != types.Format_DOLT {
+		return nil, fmt.Errorf("cannot record the violations of assertion '%s': unsupported storage format", r.Name)
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schema.IsKeyless(sch) {
+		return nil, fmt.Errorf("cannot record the violations of assertion '%s': table %s has no primary key", r.Name, tblName)
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := durable.ProllyMapFromIndex(rowData)
+	kd, _ := m.Descriptors()
+	kb := val.NewTupleBuilder(kd)
+
+	arts, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	artEditor := durable.ProllyMapFromArtifactIndex(arts).Editor()
+	vinfo, err := json.Marshal(merge.CheckCVMeta{Name: r.Name, Expression: r.Query})
+	if err != nil {
+		return nil, err
+	}
+
+	pkCols := sch.GetPKCols().GetColumns()
+	for _, row := range r.Rows {
+		if len(row) < len(pkCols) {
+			return nil, fmt.Errorf("the query of assertion '%s' must return the primary key of the rows of table %s which violate it", r.Name, tblName)
+		}
+
+		hasNulls := false
+		for i, col := range pkCols {
+			if row[i] == nil {
+				hasNulls = true
+				break
+			}
+			v, _, err := col.TypeInfo.ToSqlType().Convert(row[i])
+			if err != nil {
+				return nil, err
+			}
+			if err = tree.PutField(ctx, m.NodeStore(), kb, i, v); err != nil {
+				return nil, err
+			}
+		}
+		if hasNulls {
+			kb.Recycle()
+			continue
+		}
+		key := kb.Build(m.Pool())
+
+		var value val.Tuple
+		err = m.Get(ctx, key, func(_, v val.Tuple) error {
+			value = v
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			// the query returned a key which isn't in the table
+			continue
+		}
+
+		meta := prolly.ConstraintViolationMeta{VInfo: vinfo, Value: value}
+		err = artEditor.ReplaceConstraintViolation(ctx, key, srcHash, prolly.ArtifactTypeChkConsViol, meta)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	artMap, err := artEditor.Flush(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err = tbl.SetArtifacts(ctx, durable.ArtifactIndexFromProllyMap(artMap))
+	if err != nil {
+		return nil, err
+	}
+	return root.PutTable(ctx, doltdb.TableName{Name: tblName}, tbl)
+}
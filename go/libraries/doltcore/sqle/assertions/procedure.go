@@ -0,0 +1,106 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gms "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// ProcedureName is the name of the stored procedure which checks the assertions of the current database.
+const ProcedureName = "dolt_verify_assertions"
+
+// ErrAssertionsViolated is returned when a commit is made from a working set which violates assertions.
+var ErrAssertionsViolated = errors.New("the working set violates assertions")
+
+var resultSchema = sql.Schema{
+	&sql.Column{Name: "name", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "table_name", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "violations", Type: types.Int64, Nullable: false},
+}
+
+// NewProcedure returns the DOLT_VERIFY_ASSERTIONS() stored procedure, which runs the query of every assertion in the
+// dolt_assertions table of the current database with |e|, and returns a row for each assertion with the number of rows
+// violating it. The violating rows are recorded in dolt_constraint_violations, as check constraint violations named
+// after the assertion.
+func NewProcedure(e *gms.Engine) sql.ExternalStoredProcedureDetails {
+	return sql.ExternalStoredProcedureDetails{
+		Name:     ProcedureName,
+		Schema:   resultSchema,
+		Function: verifyAssertions(e),
+	}
+}
+
+func verifyAssertions(e *gms.Engine) func(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	return func(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+		if len(args) != 0 {
+			return nil, fmt.Errorf("%s takes no arguments", ProcedureName)
+		}
+		if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+			return nil, err
+		}
+
+		dbName := ctx.GetCurrentDatabase()
+		results, err := check(ctx, e, dbName)
+		if err != nil {
+			return nil, err
+		}
+		if err = recordViolations(ctx, dbName, results); err != nil {
+			return nil, err
+		}
+
+		rows := make([]sql.Row, len(results))
+		for i, r := range results {
+			rows[i] = sql.Row{r.Name, r.TableName, int64(len(r.Rows))}
+		}
+		return sql.RowsToRowIter(rows...), nil
+	}
+}
+
+// NewVerifier returns the dsess.AssertionVerifier which checks assertions by running their queries with |e|.
+func NewVerifier(e *gms.Engine) dsess.AssertionVerifier {
+	return func(ctx *sql.Context, dbName string) error {
+		// the queries of assertions name the tables of the database they're in without qualifying them
+		if currDb := ctx.GetCurrentDatabase(); currDb != dbName {
+			ctx.SetCurrentDatabase(dbName)
+			defer ctx.SetCurrentDatabase(currDb)
+		}
+
+		results, err := check(ctx, e, dbName)
+		if err != nil {
+			return err
+		}
+
+		var violated []string
+		for _, r := range results {
+			if len(r.Rows) > 0 {
+				violated = append(violated, fmt.Sprintf("'%s' on table %s", r.Name, r.TableName))
+			}
+		}
+		if len(violated) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%w: %s. Call %s() to record the rows violating them in dolt_constraint_violations",
+			ErrAssertionsViolated, strings.Join(violated, ", "), ProcedureName)
+	}
+}
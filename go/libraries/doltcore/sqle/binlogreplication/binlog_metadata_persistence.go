@@ -102,6 +102,13 @@ func persistReplicaRunningState(ctx *sql.Context, state replicaRunningState) err
 	}
 }
 
+// PersistReplicaAutoStart records that replication is running without starting it, so that it starts when the server
+// next starts. It's used to replicate from a source once a snapshot of the source is loaded into a database, before
+// the database's server is started.
+func PersistReplicaAutoStart(ctx *sql.Context) error {
+	return persistReplicaRunningState(ctx, running)
+}
+
 // loadReplicationConfiguration loads the replication configuration for default channel ("") from
 // the "mysql" database, |mysqlDb|.
 func loadReplicationConfiguration(_ *sql.Context, mysqlDb *mysql_db.MySQLDb) (*mysql_db.ReplicaSourceInfo, error) {
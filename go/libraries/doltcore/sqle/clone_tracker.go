@@ -0,0 +1,113 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas/pull"
+)
+
+// cloneTracker records the progress of every database cloned by a DoltDatabaseProvider, so that it can be reported
+// by the dolt_clone_status system table while clones are running in the background.
+type cloneTracker struct {
+	mu     sync.Mutex
+	clones []*dsess.CloneStatus
+}
+
+func newCloneTracker() *cloneTracker {
+	return &cloneTracker{}
+}
+
+// start records a new running clone of |remoteUrl| into |dbName|. Any finished clone previously recorded for
+// |dbName| is forgotten. Returns an error if |dbName| is already being cloned.
+func (t *cloneTracker) start(dbName, remoteUrl string) (*cloneProgress, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clones := t.clones[:0]
+	for _, c := range t.clones {
+		if strings.EqualFold(c.Database, dbName) {
+			if c.State == dsess.CloneRunning {
+				return nil, fmt.Errorf("database %s is already being cloned from %s", dbName, c.RemoteUrl)
+			}
+			continue
+		}
+		clones = append(clones, c)
+	}
+
+	status := &dsess.CloneStatus{
+		Database:  dbName,
+		RemoteUrl: remoteUrl,
+		State:     dsess.CloneRunning,
+		StartedAt: time.Now(),
+	}
+	t.clones = append(clones, status)
+	return &cloneProgress{t: t, status: status}, nil
+}
+
+// statuses returns a copy of the status of every recorded clone.
+func (t *cloneTracker) statuses() []dsess.CloneStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ret := make([]dsess.CloneStatus, len(t.clones))
+	for i, c := range t.clones {
+		ret[i] = *c
+	}
+	return ret
+}
+
+// cloneProgress updates the status of a single clone recorded by a cloneTracker.
+type cloneProgress struct {
+	t      *cloneTracker
+	status *dsess.CloneStatus
+}
+
+// consume is an actions.CloneProgressFunc which counts the chunks listed and downloaded by the clone.
+func (p *cloneProgress) consume(eventCh <-chan pull.TableFileEvent) {
+	for evt := range eventCh {
+		var n int64
+		for _, tf := range evt.TableFiles {
+			n += int64(tf.NumChunks())
+		}
+		switch evt.EventType {
+		case pull.Listed:
+			p.t.mu.Lock()
+			p.status.ChunksTotal += n
+			p.t.mu.Unlock()
+		case pull.DownloadSuccess:
+			p.t.mu.Lock()
+			p.status.ChunksDownloaded += n
+			p.t.mu.Unlock()
+		}
+	}
+}
+
+// finish marks the clone as completed, or as failed if |err| is non-nil.
+func (p *cloneProgress) finish(err error) {
+	p.t.mu.Lock()
+	defer p.t.mu.Unlock()
+	p.status.FinishedAt = time.Now()
+	if err != nil {
+		p.status.State = dsess.CloneFailed
+		p.status.Error = err.Error()
+	} else {
+		p.status.State = dsess.CloneCompleted
+	}
+}
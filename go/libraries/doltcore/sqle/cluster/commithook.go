@@ -232,8 +232,12 @@ func (h *commithook) primaryNeedsInit() bool {
 }
 
 // Called by the replicate thread to periodically heartbeat liveness to a
-// standby if we are a primary. These heartbeats are best effort and currently
-// do not affect the data plane much.
+// standby if we are a primary. These heartbeats are best effort. If the
+// standby answers that it is no longer at the head we last pushed to it, for
+// example because it was restarted with an empty data directory, we forget
+// that we pushed it, so that the replicate thread pushes the current head to
+// it again. This is how a new standby is bootstrapped with the contents of
+// the primary without first being cloned by an operator.
 //
 // preconditions: h.mu is locked and shouldReplicate() returned false.
 func (h *commithook) attemptHeartbeat(ctx context.Context) {
@@ -258,8 +262,18 @@ func (h *commithook) attemptHeartbeat(ctx context.Context) {
 	h.mu.Unlock()
 	datasDB := doltdb.HackDatasDatabaseFromDoltDB(destDB)
 	cs := datas.ChunkStoreFromDatabase(datasDB)
-	cs.Commit(ctx, head, head)
+	// A Commit from |head| to |head| does not move the standby's root, but it
+	// does refresh our view of it.
+	_, err := cs.Commit(ctx, head, head)
+	var root hash.Hash
+	if err == nil {
+		root, err = cs.Root(ctx)
+	}
 	h.mu.Lock()
+	if err == nil && root != head && h.role == RolePrimary && h.lastPushedHead == head {
+		h.logger().Warnf("cluster/commithook: standby is at %v instead of the last pushed head %v; replicating to it again.", root.String(), head.String())
+		h.lastPushedHead = hash.Hash{}
+	}
 }
 
 // Called by the replicate thread to push the nextHead to the destDB and set
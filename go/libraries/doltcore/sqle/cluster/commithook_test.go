@@ -17,12 +17,15 @@ package cluster
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/store/datas"
 )
 
 func TestCommitHookStartsNotCaughtUp(t *testing.T) {
@@ -41,3 +44,51 @@ func TestCommitHookStartsNotCaughtUp(t *testing.T) {
 
 	require.False(t, hook.isCaughtUp())
 }
+
+func TestCommitHookReplicatesToStandbyThatLostItsHead(t *testing.T) {
+	srcEnv := dtestutils.CreateTestEnv()
+	t.Cleanup(func() {
+		srcEnv.DoltDB.Close()
+	})
+	destEnv := dtestutils.CreateTestEnv()
+	t.Cleanup(func() {
+		destEnv.DoltDB.Close()
+	})
+
+	hook := newCommitHook(logrus.StandardLogger(), "origin", "https://localhost:50051/mydb", "mydb", RolePrimary, func(context.Context) (*doltdb.DoltDB, error) {
+		return destEnv.DoltDB, nil
+	}, srcEnv.DoltDB, t.TempDir())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bt := sql.NewBackgroundThreads()
+	t.Cleanup(func() {
+		cancel()
+		bt.Shutdown()
+	})
+
+	srcCS := datas.ChunkStoreFromDatabase(doltdb.HackDatasDatabaseFromDoltDB(srcEnv.DoltDB))
+	destCS := datas.ChunkStoreFromDatabase(doltdb.HackDatasDatabaseFromDoltDB(destEnv.DoltDB))
+	srcRoot, err := srcCS.Root(ctx)
+	require.NoError(t, err)
+	origDestRoot, err := destCS.Root(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, srcRoot, origDestRoot)
+
+	require.NoError(t, hook.Run(bt))
+	destAtSrcRoot := func() bool {
+		require.NoError(t, destCS.Rebase(ctx))
+		root, err := destCS.Root(ctx)
+		require.NoError(t, err)
+		return root == srcRoot
+	}
+	require.Eventually(t, destAtSrcRoot, 5*time.Second, 10*time.Millisecond)
+
+	// The standby loses everything it was sent, as if it was restarted with an
+	// empty data directory. The primary's heartbeat notices and replicates to
+	// it again.
+	ok, err := destCS.Commit(ctx, origDestRoot, srcRoot)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.False(t, destAtSrcRoot())
+	require.Eventually(t, destAtSrcRoot, 10*time.Second, 50*time.Millisecond)
+}
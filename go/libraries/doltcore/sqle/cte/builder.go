@@ -0,0 +1,381 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cte
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/rowexec"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// MaxRecursionDepthVariable is the system variable which limits the number of iterations of a recursive common table
+// expression.
+const MaxRecursionDepthVariable = "cte_max_recursion_depth"
+
+// ErrRecursionDepthExceeded is returned when a recursive common table expression iterates more times than
+// cte_max_recursion_depth allows.
+var ErrRecursionDepthExceeded = errors.NewKind("Recursive query aborted after %d iterations. Try increasing @@cte_max_recursion_depth to a larger value.")
+
+// Builder is a sql.NodeExecBuilder which runs the common table expressions of queries like MySQL does:
+//
+//   - A recursive common table expression fails once it iterates more times than cte_max_recursion_depth.
+//   - A common table expression which a query reads more than once is materialized: its query runs once, and its
+//     rows are read from memory after that, for the rest of the query.
+//   - The MERGE(name) optimizer hint disables materializing the common table expression or derived table |name|, and
+//     NO_MERGE(name) or MATERIALIZE(name) materialize it even if it's only read once.
+//
+// The nodes it doesn't handle are built with the exec builder override the engine would otherwise use.
+type Builder struct {
+	// base builds the queries of the subquery aliases this builder materializes
+	base     sql.NodeExecBuilder
+	override sql.NodeExecBuilder
+
+	mu sync.Mutex
+	// queries is the state of the queries being run, by the session and process running them. A query run while
+	// another query of the same process is running, like a statement of a stored procedure, is on top of it.
+	queries map[queryKey][]*queryState
+}
+
+// queryKey identifies the process running a query
+type queryKey struct {
+	session uint32
+	pid     uint64
+}
+
+var _ sql.NodeExecBuilder = (*Builder)(nil)
+
+// NewBuilder returns a Builder which uses |override| like rowexec.NewOverrideBuilder.
+func NewBuilder(override sql.NodeExecBuilder) *Builder {
+	b := &Builder{override: override, queries: make(map[queryKey][]*queryState)}
+	b.base = rowexec.NewOverrideBuilder(b)
+	return b
+}
+
+// queryState is what a Builder tracks while running a query. It's created when the query's QueryProcess is built,
+// and removed once the iterator of the QueryProcess's child, the root of the query, is closed, or the query's context
+// is done, whichever is first.
+type queryState struct {
+	key queryKey
+	// root is the child of the query's QueryProcess, until it's built
+	root sql.Node
+	// stop stops removing the state once the query's context is done
+	stop func() bool
+
+	hints hints
+	// references is the number of times the plan reads each subquery alias, by its key
+	references map[string]int
+
+	mu sync.Mutex
+	// iterations is the iterations of each recursive common table expression
+	iterations map[*plan.RecursiveTable]*recursion
+	// materialized is the rows of each materialized subquery alias, by its key
+	materialized map[string]*materialization
+}
+
+// recursion counts the iterations of a recursive common table expression. Each iteration reads the rows of the
+// previous one from its RecursiveTable, which may be built more than once an iteration, like on the inner side of a
+// join.
+type recursion struct {
+	iterations uint64
+	working    *sql.Row
+}
+
+// materialization is the rows of a materialized subquery alias, which are read once, and released once every read
+// of them in the plan is closed.
+type materialization struct {
+	once   sync.Once
+	rows   []sql.Row
+	err    error
+	closed int
+}
+
+// Build implements sql.NodeExecBuilder
+func (b *Builder) Build(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	switch n := n.(type) {
+	case *plan.QueryProcess:
+		// Every query is run by a QueryProcess, whose child is built next
+		b.push(ctx, newQueryState(ctx, n))
+	case *plan.RecursiveTable:
+		if st := b.stateOf(ctx); st != nil {
+			if err := st.iterate(ctx, n); err != nil {
+				return nil, err
+			}
+		}
+	case *plan.SubqueryAlias:
+		if st := b.stateOf(ctx); st != nil && len(r) == 0 {
+			if key, ok := st.materializes(n); ok {
+				return &materializedIter{b: b, st: st, key: key, n: n}, nil
+			}
+		}
+	}
+	if st := b.stateOf(ctx); st != nil && b.takeRoot(st, n) {
+		iter, err := b.base.Build(ctx, n, r)
+		if err != nil {
+			b.remove(st)
+			return nil, err
+		}
+		return &rootIter{RowIter: iter, b: b, st: st}, nil
+	}
+	return b.buildOverride(ctx, n, r)
+}
+
+func (b *Builder) buildOverride(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	if b.override == nil {
+		return nil, nil
+	}
+	return b.override.Build(ctx, n, r)
+}
+
+// newQueryState returns the state of the query run by |qp|.
+func newQueryState(ctx *sql.Context, qp *plan.QueryProcess) *queryState {
+	st := &queryState{
+		key:          queryKey{session: ctx.Session.ID(), pid: ctx.Pid()},
+		root:         qp.Child(),
+		hints:        parseHints(ctx.Query()),
+		references:   make(map[string]int),
+		iterations:   make(map[*plan.RecursiveTable]*recursion),
+		materialized: make(map[string]*materialization),
+	}
+	countReferences(qp.Child(), st.references)
+	return st
+}
+
+// countReferences counts the subquery aliases read by |n|, including the ones in its subquery expressions.
+func countReferences(n sql.Node, references map[string]int) {
+	transform.Inspect(n, func(n sql.Node) bool {
+		if sqa, ok := n.(*plan.SubqueryAlias); ok {
+			references[subqueryKey(sqa)]++
+		}
+		if ne, ok := n.(sql.Expressioner); ok {
+			for _, e := range ne.Expressions() {
+				transform.InspectExpr(e, func(e sql.Expression) bool {
+					if sq, ok := e.(*plan.Subquery); ok {
+						countReferences(sq.Query, references)
+					}
+					return false
+				})
+			}
+		}
+		return true
+	})
+}
+
+// push adds |st| as the state of the query its process is now running. The state of a query whose root is never
+// built, like a query whose results are cached, is removed once its context is done.
+func (b *Builder) push(ctx context.Context, st *queryState) {
+	b.mu.Lock()
+	b.queries[st.key] = append(b.queries[st.key], st)
+	b.mu.Unlock()
+	st.stop = context.AfterFunc(ctx, func() {
+		b.remove(st)
+	})
+}
+
+// remove removes |st| once its query is done.
+func (b *Builder) remove(st *queryState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stack := b.queries[st.key]
+	for i := range stack {
+		if stack[i] == st {
+			stack = append(stack[:i:i], stack[i+1:]...)
+			break
+		}
+	}
+	if len(stack) == 0 {
+		delete(b.queries, st.key)
+	} else {
+		b.queries[st.key] = stack
+	}
+}
+
+// stateOf returns the state of the query being run by the process of |ctx|, if any.
+func (b *Builder) stateOf(ctx *sql.Context) *queryState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stack := b.queries[queryKey{session: ctx.Session.ID(), pid: ctx.Pid()}]
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
+
+// takeRoot returns whether |n| is the root of the query of |st|, which is now being built.
+func (b *Builder) takeRoot(st *queryState, n sql.Node) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if st.root == nil || st.root != n {
+		return false
+	}
+	st.root = nil
+	return true
+}
+
+// maxRecursionDepth returns the session's cte_max_recursion_depth.
+func maxRecursionDepth(ctx *sql.Context) (uint64, error) {
+	v, err := ctx.GetSessionVariable(ctx, MaxRecursionDepthVariable)
+	if err != nil {
+		return 0, err
+	}
+	depth, _, err := types.Uint64.Convert(v)
+	if err != nil {
+		return 0, err
+	}
+	return depth.(uint64), nil
+}
+
+// iterate counts an iteration of the recursive common table expression which reads |rt|, if its working rows have
+// changed since it was last built. Returns an error if that's more iterations than cte_max_recursion_depth.
+func (st *queryState) iterate(ctx *sql.Context, rt *plan.RecursiveTable) error {
+	if len(rt.Buf) == 0 {
+		return nil
+	}
+	st.mu.Lock()
+	rec, ok := st.iterations[rt]
+	if !ok {
+		rec = &recursion{}
+		st.iterations[rt] = rec
+	}
+	if rec.working == &rt.Buf[0] {
+		st.mu.Unlock()
+		return nil
+	}
+	rec.working = &rt.Buf[0]
+	rec.iterations++
+	iterations := rec.iterations
+	st.mu.Unlock()
+
+	depth, err := maxRecursionDepth(ctx)
+	if err != nil {
+		return err
+	}
+	if iterations > depth {
+		return ErrRecursionDepthExceeded.New(iterations)
+	}
+	return nil
+}
+
+// materializes returns the key |sqa|'s rows are materialized with, if they're materialized.
+func (st *queryState) materializes(sqa *plan.SubqueryAlias) (string, bool) {
+	key := subqueryKey(sqa)
+	switch st.hints.of(sqa.Name()) {
+	case hintMerge:
+		return "", false
+	case hintNoMerge:
+		return key, true
+	}
+	return key, st.references[key] > 1
+}
+
+// subqueryKey returns the key of the rows of |sqa|. Every read of a common table expression, under any alias, has
+// the same plan.
+func subqueryKey(sqa *plan.SubqueryAlias) string {
+	return sql.DebugString(sqa.Child)
+}
+
+// rootIter is the iterator of the root of a query, which removes the query's state once it's closed.
+type rootIter struct {
+	sql.RowIter
+	b  *Builder
+	st *queryState
+}
+
+func (it *rootIter) Close(ctx *sql.Context) error {
+	defer func() {
+		it.st.stop()
+		it.b.remove(it.st)
+	}()
+	return it.RowIter.Close(ctx)
+}
+
+// materializedIter returns the rows of a materialized subquery alias, which it reads when it's first read.
+type materializedIter struct {
+	b    *Builder
+	st   *queryState
+	key  string
+	n    *plan.SubqueryAlias
+	rows []sql.Row
+	pos  int
+	read bool
+}
+
+var _ sql.RowIter = (*materializedIter)(nil)
+
+func (it *materializedIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if !it.read {
+		it.read = true
+		rows, err := it.st.materialize(ctx, it.b, it.key, it.n)
+		if err != nil {
+			return nil, err
+		}
+		it.rows = rows
+	}
+	if it.pos >= len(it.rows) {
+		return nil, io.EOF
+	}
+	row := it.rows[it.pos].Copy()
+	it.pos++
+	return row, nil
+}
+
+func (it *materializedIter) Close(_ *sql.Context) error {
+	it.rows = nil
+	if it.read {
+		it.st.release(it.key)
+	}
+	return nil
+}
+
+// materialize returns the rows of the subquery alias |n| with |key|, running its query if it hasn't been already.
+func (st *queryState) materialize(ctx *sql.Context, b *Builder, key string, n *plan.SubqueryAlias) ([]sql.Row, error) {
+	st.mu.Lock()
+	m, ok := st.materialized[key]
+	if !ok {
+		m = &materialization{}
+		st.materialized[key] = m
+	}
+	st.mu.Unlock()
+
+	m.once.Do(func() {
+		var iter sql.RowIter
+		iter, m.err = b.base.Build(ctx, n.Child, nil)
+		if m.err != nil {
+			return
+		}
+		m.rows, m.err = sql.RowIterToRows(ctx, iter)
+	})
+	return m.rows, m.err
+}
+
+// release releases the rows of the subquery alias with |key| once every read of them in the plan is closed. A
+// subquery alias which is read again after that, like on the inner side of a join, runs its query again.
+func (st *queryState) release(key string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m, ok := st.materialized[key]
+	if !ok {
+		return
+	}
+	if m.closed++; m.closed >= st.references[key] {
+		delete(st.materialized, key)
+	}
+}
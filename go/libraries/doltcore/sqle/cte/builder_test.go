@@ -0,0 +1,129 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cte_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/rowexec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cte"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		depth    int64
+		expected []sql.Row
+		err      bool
+	}{
+		{
+			name:     "recursion within the default depth",
+			query:    "with recursive n (i) as (select 1 union all select i + 1 from n where i < 100) select count(*) from n",
+			depth:    1000,
+			expected: []sql.Row{{int64(100)}},
+		},
+		{
+			name:  "recursion past the depth",
+			query: "with recursive n (i) as (select 1 union all select i + 1 from n where i < 100) select count(*) from n",
+			depth: 10,
+			err:   true,
+		},
+		{
+			name:     "recursion to the depth",
+			query:    "with recursive n (i) as (select 1 union all select i + 1 from n where i < 11) select count(*) from n",
+			depth:    10,
+			expected: []sql.Row{{int64(11)}},
+		},
+		{
+			name:     "recursion joined to the working rows",
+			query:    "with recursive n (i) as (select 1 union all select n.i + 1 from n join xy on n.i = xy.x where n.i < 20) select count(*) from n",
+			depth:    30,
+			expected: []sql.Row{{int64(20)}},
+		},
+		{
+			name:     "materialized common table expression",
+			query:    "with t as (select x, y from xy where x <= 3) select t1.x, t2.x from t t1 join t t2 on t1.y < t2.y order by 1, 2",
+			depth:    1000,
+			expected: []sql.Row{{int64(1), int64(2)}, {int64(1), int64(3)}, {int64(2), int64(3)}},
+		},
+		{
+			name:     "merged common table expression",
+			query:    "with t as (select x, y from xy where x <= 3) select /*+ MERGE(t) */ t1.x, t2.x from t t1 join t t2 on t1.y < t2.y order by 1, 2",
+			depth:    1000,
+			expected: []sql.Row{{int64(1), int64(2)}, {int64(1), int64(3)}, {int64(2), int64(3)}},
+		},
+		{
+			name:     "hinted common table expression",
+			query:    "with recursive n (i) as (select 1 union all select i + 1 from n where i < 100) select /*+ NO_MERGE(n) */ count(*) from n",
+			depth:    1000,
+			expected: []sql.Row{{int64(100)}},
+		},
+	}
+
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := sqle.NewDatabase(context.Background(), "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+
+	engine, ctx, err := sqle.NewTestEngine(dEnv, context.Background(), db)
+	require.NoError(t, err)
+	engine.Analyzer.ExecBuilder = rowexec.NewOverrideBuilder(cte.NewBuilder(nil))
+	err = ctx.Session.SetSessionVariable(ctx, sql.AutoCommitSessionVar, false)
+	require.NoError(t, err)
+
+	setup := []string{
+		"create table xy (x int primary key, y int)",
+		"insert into xy with recursive r(i) as (select 1 union all select i+1 from r where i < 50) select i, i * 10 from r",
+	}
+	for _, q := range setup {
+		_, iter, _, err := engine.Query(ctx, q)
+		require.NoError(t, err)
+		_, err = sql.RowIterToRows(ctx, iter)
+		require.NoError(t, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ctx.Session.SetSessionVariable(ctx, cte.MaxRecursionDepthVariable, tt.depth)
+			require.NoError(t, err)
+
+			var rows []sql.Row
+			_, iter, _, err := engine.Query(ctx, tt.query)
+			if err == nil {
+				rows, err = sql.RowIterToRows(ctx, iter)
+			}
+			if tt.err {
+				require.Error(t, err)
+				require.True(t, cte.ErrRecursionDepthExceeded.Is(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, rows)
+		})
+	}
+}
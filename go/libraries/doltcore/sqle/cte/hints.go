@@ -0,0 +1,82 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cte
+
+import (
+	"regexp"
+	"strings"
+)
+
+// hint is whether an optimizer hint asks for a common table expression or derived table to be merged into the query
+// which reads it, or materialized.
+type hint int
+
+const (
+	hintNone hint = iota
+	hintMerge
+	hintNoMerge
+)
+
+// hints is the MERGE, NO_MERGE and MATERIALIZE optimizer hints of a query.
+type hints struct {
+	// all is the hint with no names, which applies to every common table expression
+	all hint
+	// named is the hints by the lower case names of the common table expressions they apply to
+	named map[string]hint
+}
+
+var hintCommentRegex = regexp.MustCompile(`/\*\+(.*?)\*/`)
+var hintRegex = regexp.MustCompile(`(?i)\b(NO_MERGE|MERGE|MATERIALIZE)\s*\(([^)]*)\)`)
+
+// parseHints returns the MERGE, NO_MERGE and MATERIALIZE optimizer hints in the hint comments of |query|. Later hints
+// override earlier ones.
+func parseHints(query string) hints {
+	var h hints
+	for _, comment := range hintCommentRegex.FindAllStringSubmatch(query, -1) {
+		for _, m := range hintRegex.FindAllStringSubmatch(comment[1], -1) {
+			kind := hintMerge
+			if !strings.EqualFold(m[1], "MERGE") {
+				kind = hintNoMerge
+			}
+
+			names := strings.FieldsFunc(m[2], func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t' || r == '\n'
+			})
+			if len(names) == 0 {
+				h.all = kind
+				continue
+			}
+			if h.named == nil {
+				h.named = make(map[string]hint)
+			}
+			for _, name := range names {
+				// a query block qualifier, like n@qb1, isn't part of the name
+				if i := strings.IndexByte(name, '@'); i >= 0 {
+					name = name[:i]
+				}
+				h.named[strings.ToLower(strings.Trim(name, "`"))] = kind
+			}
+		}
+	}
+	return h
+}
+
+// of returns the hint for the common table expression or derived table |name|.
+func (h hints) of(name string) hint {
+	if kind, ok := h.named[strings.ToLower(name)]; ok {
+		return kind
+	}
+	return h.all
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cte
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHints(t *testing.T) {
+	h := parseHints("with a as (select 1), b as (select 2) select /*+ NO_MERGE(a) merge(`B`) JOIN_ORDER(a, b) */ * from a, b")
+	assert.Equal(t, hintNoMerge, h.of("a"))
+	assert.Equal(t, hintMerge, h.of("b"))
+	assert.Equal(t, hintNone, h.of("c"))
+
+	h = parseHints("select /*+ MATERIALIZE(x@qb1, y) */ * from x, y")
+	assert.Equal(t, hintNoMerge, h.of("X"))
+	assert.Equal(t, hintNoMerge, h.of("y"))
+
+	h = parseHints("select /*+ MERGE() */ * from x /*+ NO_MERGE(y) */")
+	assert.Equal(t, hintMerge, h.of("x"))
+	assert.Equal(t, hintNoMerge, h.of("y"))
+
+	// hints are only read from hint comments
+	h = parseHints("select 'NO_MERGE(x)' from x /* NO_MERGE(x) */")
+	assert.Equal(t, hintNone, h.of("x"))
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cte
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryStates(t *testing.T) {
+	b := NewBuilder(nil)
+	ctx := sql.NewEmptyContext()
+
+	outer := newQueryState(ctx, plan.NewQueryProcess(plan.NewResolvedDualTable(), nil))
+	b.push(ctx, outer)
+	assert.Same(t, outer, b.stateOf(ctx))
+
+	// a query run by another query in the same process is on top of it until it's done
+	inner := newQueryState(ctx, plan.NewQueryProcess(plan.NewResolvedDualTable(), nil))
+	b.push(ctx, inner)
+	assert.Same(t, inner, b.stateOf(ctx))
+	b.remove(inner)
+	assert.Same(t, outer, b.stateOf(ctx))
+	b.remove(outer)
+	assert.Nil(t, b.stateOf(ctx))
+	assert.Empty(t, b.queries)
+
+	// the state of a query is removed once its context is done, even if its root is never built
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	qctx := ctx.WithContext(cancelCtx)
+	b.push(qctx, newQueryState(qctx, plan.NewQueryProcess(plan.NewResolvedDualTable(), nil)))
+	require.NotNil(t, b.stateOf(qctx))
+	cancel()
+	require.Eventually(t, func() bool {
+		return b.stateOf(qctx) == nil
+	}, time.Second, time.Millisecond)
+}
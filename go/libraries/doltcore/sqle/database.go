@@ -59,6 +59,7 @@ var ErrInvalidTableName = errors.NewKind("Invalid table name %s.")
 var ErrReservedTableName = errors.NewKind("Invalid table name %s. Table names beginning with `dolt_` are reserved for internal use")
 var ErrReservedDiffTableName = errors.NewKind("Invalid table name %s. Table names beginning with `__DATABASE__` are reserved for internal use")
 var ErrSystemTableAlter = errors.NewKind("Cannot alter table %s: system tables cannot be dropped or altered")
+var ErrInstantAlterNotSupported = errors.NewKind("ALGORITHM=INSTANT is not supported for this operation. Try ALGORITHM=COPY/INPLACE.")
 
 // Database implements sql.Database for a dolt DB.
 type Database struct {
@@ -309,6 +310,10 @@ func (db Database) GetTableInsensitiveAsOf(ctx *sql.Context, tableName string, a
 		// there's no need to lock it to a root.
 		return t, true, nil
 
+	case *dtables.ExternalTable:
+		// the definition of an external table is read from the root, but its rows are always read from its server
+		return t, true, nil
+
 	default:
 		return nil, false, fmt.Errorf("unexpected table type %T", table)
 	}
@@ -474,8 +479,20 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 		dt, found = dtables.NewStatusTable(ctx, db.ddb, ws, adapter), true
 	case doltdb.MergeStatusTableName:
 		dt, found = dtables.NewMergeStatusTable(db.RevisionQualifiedName()), true
+	case doltdb.CloneStatusTableName:
+		dt, found = dtables.NewCloneStatusTable(db.Name()), true
+	case doltdb.StorageHealthTableName:
+		dt, found = dtables.NewStorageHealthTable(db.Name()), true
+	case doltdb.DroppedDatabasesTableName:
+		dt, found = dtables.NewDroppedDatabasesTable(db.Name()), true
 	case doltdb.TagsTableName:
-		dt, found = dtables.NewTagsTable(ctx, db.ddb), true
+		dt, found = dtables.NewTagsTable(ctx, db), true
+	case doltdb.NotesTableName:
+		dt, found = dtables.NewNotesTable(db.Name(), db.ddb), true
+	case doltdb.MergeQueueTableName:
+		dt, found = dtables.NewMergeQueueTable(db.Name()), true
+	case doltdb.ChangeCursorsTableName:
+		dt, found = dtables.NewChangeCursorsTable(db.Name(), db.ddb), true
 	case dtables.AccessTableName:
 		basCtx := branch_control.GetBranchAwareSession(ctx)
 		if basCtx != nil {
@@ -512,6 +529,61 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 			versionableTable := backingTable.(dtables.VersionableTable)
 			dt, found = dtables.NewDocsTable(ctx, versionableTable), true
 		}
+	case doltdb.TestsTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.TestsTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		if backingTable == nil {
+			dt, found = dtables.NewEmptyTestsTable(ctx), true
+		} else {
+			versionableTable := backingTable.(dtables.VersionableTable)
+			dt, found = dtables.NewTestsTable(ctx, versionableTable), true
+		}
+	case doltdb.BranchProtectionTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.BranchProtectionTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		if backingTable == nil {
+			dt, found = dtables.NewEmptyBranchProtectionTable(ctx), true
+		} else {
+			versionableTable := backingTable.(dtables.VersionableTable)
+			dt, found = dtables.NewBranchProtectionTable(ctx, versionableTable), true
+		}
+	case doltdb.SequencesTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.SequencesTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		if backingTable == nil {
+			dt, found = dtables.NewEmptySequencesTable(ctx), true
+		} else {
+			versionableTable := backingTable.(dtables.VersionableTable)
+			dt, found = dtables.NewSequencesTable(ctx, versionableTable), true
+		}
+	case doltdb.ExternalTablesTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.ExternalTablesTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		if backingTable == nil {
+			dt, found = dtables.NewEmptyExternalTablesTable(ctx), true
+		} else {
+			versionableTable := backingTable.(dtables.VersionableTable)
+			dt, found = dtables.NewExternalTablesTable(ctx, versionableTable), true
+		}
+	case doltdb.AssertionsTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.AssertionsTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		if backingTable == nil {
+			dt, found = dtables.NewEmptyAssertionsTable(ctx), true
+		} else {
+			versionableTable := backingTable.(dtables.VersionableTable)
+			dt, found = dtables.NewAssertionsTable(ctx, versionableTable), true
+		}
 	case doltdb.StatisticsTableName:
 		dt, found = dtables.NewStatisticsTable(ctx, db.Name(), db.ddb, asOf), true
 	case doltdb.ProceduresTableName:
@@ -556,6 +628,19 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 		return table, found, err
 	}
 
+	// Tables defined in dolt_external_tables are proxies to tables on other servers
+	ext, found, err := doltdb.GetExternalTable(ctx, root, tblName)
+	if err != nil {
+		return nil, false, err
+	}
+	if found {
+		extTable, err := dtables.NewExternalTable(ctx, db.Name(), ext)
+		if err != nil {
+			return nil, false, err
+		}
+		return extTable, true, nil
+	}
+
 	// If the table wasn't found in the specified data root, check if there is an overridden
 	// schema commit that contains it and return an empty table if so.
 	return resolveOverriddenNonexistentTable(ctx, tblName, db)
@@ -1327,7 +1412,15 @@ func (db Database) CreateTemporaryTable(ctx *sql.Context, tableName string, pkSc
 		return ErrInvalidTableName.New(tableName)
 	}
 
-	tmp, err := NewTempTable(ctx, db.ddb, pkSch, tableName, db.Name(), db.editOpts, collation)
+	var tmpDir string
+	if db.rsw != nil {
+		var err error
+		if tmpDir, err = db.rsw.TempTableFilesDir(); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := NewTempTable(ctx, db.ddb, pkSch, tableName, db.Name(), db.editOpts, collation, tmpDir)
 	if err != nil {
 		return err
 	}
@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -36,6 +37,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/perfschema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/resolve"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
@@ -58,6 +60,12 @@ type DoltDatabaseProvider struct {
 	mu                 *sync.RWMutex
 
 	droppedDatabaseManager *droppedDatabaseManager
+	clones                 *cloneTracker
+	storageHealth          *storageHealthTracker
+	mergeQueue             *mergeQueue
+	ephemeralBranches      *ephemeralBranches
+	assertionVerifier      dsess.AssertionVerifier
+	privilegeChecker       sql.PrivilegedOperationChecker
 
 	defaultBranch string
 	fs            filesys.Filesys
@@ -150,6 +158,10 @@ func NewDoltDatabaseProviderWithDatabases(defaultBranch string, fs filesys.Files
 		InitDatabaseHooks:      []InitDatabaseHook{ConfigureReplicationDatabaseHook},
 		isStandby:              new(bool),
 		droppedDatabaseManager: newDroppedDatabaseManager(fs),
+		clones:                 newCloneTracker(),
+		storageHealth:          newStorageHealthTracker(),
+		mergeQueue:             newMergeQueue(),
+		ephemeralBranches:      newEphemeralBranches(),
 	}, nil
 }
 
@@ -164,6 +176,15 @@ func (p *DoltDatabaseProvider) WithFunctions(fns []sql.Function) *DoltDatabasePr
 	return &cp
 }
 
+// RegisterFunctions adds the functions given to this provider, replacing any functions of the same names. Since the
+// engine's catalog looks for functions in its provider first, this overrides built-in functions as well. It must be
+// called before the provider is used to execute queries.
+func (p *DoltDatabaseProvider) RegisterFunctions(fns ...sql.Function) {
+	for _, fn := range fns {
+		p.functions[strings.ToLower(fn.FunctionName())] = fn
+	}
+}
+
 // WithDbFactoryUrl returns a copy of this provider with the DbFactoryUrl set as provided.
 // The URL is used when creating new databases.
 // See doltdb.InMemDoltDB, doltdb.LocalDirDoltDB
@@ -327,7 +348,7 @@ func (p *DoltDatabaseProvider) AllDatabases(ctx *sql.Context) (all []sql.Databas
 	for _, db := range p.databases {
 		all = append(all, db)
 
-		if showBranches && db.Name() != clusterdb.DoltClusterDbName {
+		if showBranches && db.Name() != clusterdb.DoltClusterDbName && db.Name() != perfschema.DatabaseName {
 			revisionDbs, err := p.allRevisionDbs(ctx, db)
 			if err != nil {
 				// TODO: this interface is wrong, needs to return errors
@@ -561,31 +582,89 @@ func (p *DoltDatabaseProvider) CloneDatabaseFromRemote(
 	depth int,
 	remoteParams map[string]string,
 ) error {
+	progress, err := p.startClone(dbName, remoteUrl)
+	if err != nil {
+		return err
+	}
+
+	err = p.cleanUpFailedClone(dbName, p.cloneDatabaseFromRemote(ctx, dbName, remoteName, branch, remoteUrl, depth, remoteParams, progress))
+	progress.finish(err)
+	return err
+}
+
+// CloneDatabaseFromRemoteAsync implements DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) CloneDatabaseFromRemoteAsync(
+	ctx *sql.Context,
+	dbName, branch, remoteName, remoteUrl string,
+	depth int,
+	remoteParams map[string]string,
+) error {
+	progress, err := p.startClone(dbName, remoteUrl)
+	if err != nil {
+		return err
+	}
+
+	// The clone outlives the query which started it, so it can't use the query's context.
+	bgCtx := sql.NewContext(context.Background(), sql.WithSession(ctx.Session))
+	go func() {
+		err := p.cleanUpFailedClone(dbName, p.cloneDatabaseFromRemote(bgCtx, dbName, remoteName, branch, remoteUrl, depth, remoteParams, progress))
+		if err != nil {
+			bgCtx.GetLogger().Warnf("failed to clone database %s from %s: %s", dbName, remoteUrl, err.Error())
+		}
+		progress.finish(err)
+	}()
+
+	return nil
+}
+
+// CloneStatuses implements DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) CloneStatuses() []dsess.CloneStatus {
+	return p.clones.statuses()
+}
+
+// startClone records the start of a clone into |dbName| and creates its directory, so that no other database can be
+// created with that name while the clone is running. The provider's mutex is not held for the rest of the clone, which
+// may take a long time.
+func (p *DoltDatabaseProvider) startClone(dbName, remoteUrl string) (*cloneProgress, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	exists, isDir := p.fs.Exists(dbName)
 	if exists && isDir {
-		return sql.ErrDatabaseExists.New(dbName)
+		return nil, sql.ErrDatabaseExists.New(dbName)
 	} else if exists {
-		return fmt.Errorf("cannot create DB, file exists at %s", dbName)
+		return nil, fmt.Errorf("cannot create DB, file exists at %s", dbName)
 	}
 
-	err := p.cloneDatabaseFromRemote(ctx, dbName, remoteName, branch, remoteUrl, depth, remoteParams)
+	progress, err := p.clones.start(dbName, remoteUrl)
 	if err != nil {
-		// Make a best effort to clean up any artifacts on disk from a failed clone
-		// before we return the error
-		exists, _ := p.fs.Exists(dbName)
-		if exists {
-			deleteErr := p.fs.Delete(dbName, true)
-			if deleteErr != nil {
-				err = fmt.Errorf("%s: unable to clean up failed clone in directory '%s'", err.Error(), dbName)
-			}
-		}
-		return err
+		return nil, err
 	}
 
-	return nil
+	err = p.fs.MkDirs(dbName)
+	if err != nil {
+		progress.finish(err)
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+// cleanUpFailedClone makes a best effort to clean up any artifacts on disk from a clone which failed with |err|, and
+// returns the error to report for the clone.
+func (p *DoltDatabaseProvider) cleanUpFailedClone(dbName string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	exists, _ := p.fs.Exists(dbName)
+	if exists {
+		deleteErr := p.fs.Delete(dbName, true)
+		if deleteErr != nil {
+			err = fmt.Errorf("%s: unable to clean up failed clone in directory '%s'", err.Error(), dbName)
+		}
+	}
+	return err
 }
 
 // cloneDatabaseFromRemote encapsulates the inner logic for cloning a database so that if any error
@@ -597,6 +676,7 @@ func (p *DoltDatabaseProvider) cloneDatabaseFromRemote(
 	dbName, remoteName, branch, remoteUrl string,
 	depth int,
 	remoteParams map[string]string,
+	progress *cloneProgress,
 ) error {
 	if p.remoteDialer == nil {
 		return fmt.Errorf("unable to clone remote database; no remote dialer configured")
@@ -613,7 +693,7 @@ func (p *DoltDatabaseProvider) cloneDatabaseFromRemote(
 		return err
 	}
 
-	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, false, depth, dEnv)
+	err = actions.CloneRemoteWithProgress(ctx, srcDB, remoteName, branch, false, depth, dEnv, progress.consume)
 	if err != nil {
 		return err
 	}
@@ -623,6 +703,8 @@ func (p *DoltDatabaseProvider) cloneDatabaseFromRemote(
 		Remote: remoteName,
 	})
 
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	return p.registerNewDatabase(ctx, dbName, dEnv)
 }
 
@@ -730,6 +812,22 @@ func (p *DoltDatabaseProvider) PurgeDroppedDatabases(ctx *sql.Context) error {
 	return p.droppedDatabaseManager.PurgeAllDroppedDatabases(ctx)
 }
 
+// DroppedDatabases implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) DroppedDatabases(ctx *sql.Context) ([]dsess.DroppedDatabase, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.droppedDatabaseManager.DroppedDatabases(ctx)
+}
+
+// PurgeExpiredDroppedDatabases implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) PurgeExpiredDroppedDatabases(ctx *sql.Context, droppedBefore time.Time) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.droppedDatabaseManager.PurgeExpiredDroppedDatabases(ctx, droppedBefore)
+}
+
 // registerNewDatabase registers the specified DoltEnv, |newEnv|, as a new database named |name|. This
 // function is responsible for instantiating the new Database instance and updating the tracking metadata
 // in this provider. If any problems are encountered while registering the new database, an error is returned.
@@ -1301,6 +1399,33 @@ func (p *DoltDatabaseProvider) Register(d sql.ExternalStoredProcedureDetails) {
 	p.externalProcedures.Register(d)
 }
 
+// SetAssertionVerifier sets the function which checks the assertions of a database before commits are made.
+func (p *DoltDatabaseProvider) SetAssertionVerifier(verifier dsess.AssertionVerifier) {
+	p.assertionVerifier = verifier
+}
+
+// VerifyAssertions implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) VerifyAssertions(ctx *sql.Context, dbName string) error {
+	if p.assertionVerifier == nil {
+		return nil
+	}
+	return p.assertionVerifier(ctx, dbName)
+}
+
+// SetPrivilegeChecker sets the checker of the privileges which aren't checked by the engine, such as the privileges
+// needed by the statements implemented by stored procedures.
+func (p *DoltDatabaseProvider) SetPrivilegeChecker(checker sql.PrivilegedOperationChecker) {
+	p.privilegeChecker = checker
+}
+
+// UserHasPrivileges implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) UserHasPrivileges(ctx *sql.Context, operations ...sql.PrivilegedOperation) bool {
+	if p.privilegeChecker == nil {
+		return true
+	}
+	return p.privilegeChecker.UserHasPrivileges(ctx, operations...)
+}
+
 // ExternalStoredProcedure implements the sql.ExternalStoredProcedureProvider interface
 func (p *DoltDatabaseProvider) ExternalStoredProcedure(_ *sql.Context, name string, numOfParams int) (*sql.ExternalStoredProcedureDetails, error) {
 	return p.externalProcedures.LookupByNameAndParamCount(name, numOfParams)
@@ -1327,10 +1452,14 @@ func (p *DoltDatabaseProvider) TableFunction(_ *sql.Context, name string) (sql.T
 		return &PatchTableFunction{}, nil
 	case "dolt_schema_diff":
 		return &SchemaDiffTableFunction{}, nil
+	case "dolt_schema_object_diff":
+		return &SchemaObjectDiffTableFunction{}, nil
 	case "dolt_reflog":
 		return &ReflogTableFunction{}, nil
 	case "dolt_query_diff":
 		return &QueryDiffTableFunction{}, nil
+	case "dolt_changes":
+		return &ChangesTableFunction{}, nil
 	}
 
 	if fun, ok := p.tableFunctions[name]; ok {
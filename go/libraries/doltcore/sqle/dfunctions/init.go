@@ -26,6 +26,11 @@ var DoltFunctions = []sql.Function{
 	sql.Function2{Name: HasAncestorFuncName, Fn: NewHasAncestor},
 	sql.Function1{Name: HashOfTableFuncName, Fn: NewHashOfTable},
 	sql.FunctionN{Name: HashOfDatabaseFuncName, Fn: NewHashOfDatabase},
+	sql.Function1{Name: NextValFuncName, Fn: NewNextVal},
+	sql.Function2{Name: SetValFuncName, Fn: NewSetVal},
+	sql.Function0{Name: ULIDFuncName, Fn: NewULID},
+	sql.Function1{Name: ULIDToBinFuncName, Fn: NewULIDToBin},
+	sql.Function1{Name: BinToULIDFuncName, Fn: NewBinToULID},
 }
 
 // DolthubApiFunctions are the DoltFunctions that get exposed to Dolthub Api.
@@ -0,0 +1,337 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+)
+
+const (
+	NextValFuncName = "nextval"
+	SetValFuncName  = "setval"
+)
+
+// ErrSequenceNotFound is returned for sequences which aren't in the dolt_sequences table, like MariaDB's
+// ER_UNKNOWN_SEQUENCES.
+var ErrSequenceNotFound = errors.NewKind("Unknown SEQUENCE: '%s'")
+
+// maxCachedSequences is the number of sequences whose next values are kept in sequenceValues.
+const maxCachedSequences = 1024
+
+// sequenceValues holds the next value of the sequences used by this server, keyed by the lowercase names of the
+// database and the sequence. Sessions on other branches, or which haven't committed yet, may not have seen the values
+// handed out for a sequence, so every sequence continues from the larger of the value here and the one in the
+// session, keeping the values used on different branches distinct when they are merged. The first time a sequence is
+// used, its value here is read from the working sets of every branch, like the values of auto increment columns, so
+// the values used on any branch before the server started are skipped too. Values handed out in transactions which
+// were never committed may be handed out again after a restart.
+var sequenceValues = make(map[string]int64)
+var sequenceValuesMu = &sync.Mutex{}
+
+// updateSequenceFn returns the result of a sequence function given the sequence it's called on, and the value which the
+// next value of the sequence must not be less than. If the sequence changes, its new next value is returned as well.
+type updateSequenceFn func(seq doltdb.Sequence, atLeast int64) (result interface{}, next *int64, err error)
+
+// updateSequence calls |update| on the sequence named in the current database, and writes its new next value to the
+// dolt_sequences table. Sequence values are handed out as soon as they're generated, and aren't given back when the
+// transaction using them is rolled back.
+func updateSequence(ctx *sql.Context, name string, update updateSequenceFn) (interface{}, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if dbName == "" {
+		return nil, sql.ErrNoDatabaseSelected.New()
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	if err := checkSequenceAccess(ctx, dSess, dbName); err != nil {
+		return nil, err
+	}
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	if dbState.WorkingSet() == nil || dbState.WriteSession() == nil {
+		return nil, doltdb.ErrOperationNotSupportedInDetachedHead
+	}
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	seq, ok, err := doltdb.GetSequence(ctx, roots.Working, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrSequenceNotFound.New(name)
+	}
+
+	sequenceValuesMu.Lock()
+	defer sequenceValuesMu.Unlock()
+
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+	key := strings.ToLower(baseName + "." + seq.Name)
+	atLeast, ok := sequenceValues[key]
+	if !ok {
+		highWater, found, err := dbData.Ddb.GetSequenceHighWater(ctx, seq.Name)
+		if err != nil {
+			return nil, err
+		}
+		atLeast = math.MinInt64
+		if found {
+			atLeast = highWater
+		}
+	}
+	result, next, err := update(seq, atLeast)
+	if err != nil || next == nil {
+		return result, err
+	}
+
+	newSeq := seq
+	newSeq.NextValue = next
+	tableWriter, err := dbState.WriteSession().GetTableWriter(ctx, doltdb.TableName{Name: doltdb.SequencesTableName}, dbName, dSess.SetWorkingRoot)
+	if err != nil {
+		return nil, err
+	}
+	tableWriter.StatementBegin(ctx)
+	if err = tableWriter.Update(ctx, dtables.SequenceRow(seq), dtables.SequenceRow(newSeq)); err != nil {
+		return nil, err
+	}
+	if err = tableWriter.StatementComplete(ctx); err != nil {
+		return nil, err
+	}
+	if err = tableWriter.Close(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, ok := sequenceValues[key]; !ok && len(sequenceValues) >= maxCachedSequences {
+		// any sequence can be evicted, since its value is read from the working sets again when it's next used
+		for evicted := range sequenceValues {
+			delete(sequenceValues, evicted)
+			break
+		}
+	}
+	sequenceValues[key] = *next
+	return result, nil
+}
+
+// checkSequenceAccess returns an error if the current user may not change the sequences of the database |dbName|,
+// which requires the UPDATE privilege on its dolt_sequences table and write access to its branch, in a transaction
+// which isn't READ ONLY.
+func checkSequenceAccess(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) error {
+	if err := dsess.CheckWritableTransaction(ctx); err != nil {
+		return err
+	}
+	privs, counter := ctx.GetPrivilegeSet()
+	if counter == 0 {
+		return fmt.Errorf("unable to check user privileges for sequence functions")
+	}
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+	dbPrivs := privs.Database(baseName)
+	if !privs.Has(sql.PrivilegeType_Update) && !dbPrivs.Has(sql.PrivilegeType_Update) &&
+		!dbPrivs.Table(doltdb.SequencesTableName).Has(sql.PrivilegeType_Update) {
+		return sql.ErrPrivilegeCheckFailed.New(ctx.Session.Client().User)
+	}
+
+	db, err := dSess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	sqlDb, ok := db.(dsess.SqlDatabase)
+	if !ok {
+		return nil
+	}
+	return dsess.CheckAccessForDb(ctx, sqlDb, branch_control.Permissions_Write)
+}
+
+// sequenceName returns the name of the sequence a sequence function was called on, or false if it's NULL.
+func sequenceName(ctx *sql.Context, e sql.Expression, row sql.Row) (string, bool, error) {
+	val, err := e.Eval(ctx, row)
+	if err != nil || val == nil {
+		return "", false, err
+	}
+	name, ok := val.(string)
+	if !ok {
+		return "", false, fmt.Errorf("sequence name is not a string")
+	}
+	return name, true, nil
+}
+
+// NextVal implements NEXTVAL(name), which returns the next value of the sequence named in the dolt_sequences table.
+type NextVal struct {
+	expression.UnaryExpression
+}
+
+var _ sql.FunctionExpression = (*NextVal)(nil)
+
+// NewNextVal creates a new NextVal expression.
+func NewNextVal(e sql.Expression) sql.Expression {
+	return &NextVal{expression.UnaryExpression{Child: e}}
+}
+
+// Eval implements the Expression interface.
+func (n *NextVal) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	name, ok, err := sequenceName(ctx, n.Child, row)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return updateSequence(ctx, name, func(seq doltdb.Sequence, atLeast int64) (interface{}, *int64, error) {
+		value, next, err := seq.Next(atLeast)
+		if err != nil {
+			return nil, nil, err
+		}
+		return value, &next, nil
+	})
+}
+
+// String implements the Stringer interface.
+func (n *NextVal) String() string {
+	return fmt.Sprintf("%s(%s)", NextValFuncName, n.Child.String())
+}
+
+// FunctionName implements the FunctionExpression interface
+func (n *NextVal) FunctionName() string {
+	return NextValFuncName
+}
+
+// Description implements the FunctionExpression interface
+func (n *NextVal) Description() string {
+	return "returns the next value of a sequence"
+}
+
+// IsNonDeterministic implements sql.NonDeterministicExpression. Every call returns a different value.
+func (n *NextVal) IsNonDeterministic() bool {
+	return true
+}
+
+// IsNullable implements the Expression interface.
+func (n *NextVal) IsNullable() bool {
+	return n.Child.IsNullable()
+}
+
+// WithChildren implements the Expression interface.
+func (n *NextVal) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 1)
+	}
+	return NewNextVal(children[0]), nil
+}
+
+// Type implements the Expression interface.
+func (n *NextVal) Type() sql.Type {
+	return types.Int64
+}
+
+// SetVal implements SETVAL(name, value), which marks |value| as used by the sequence named, so that the sequence
+// continues after it. Like in MariaDB, values which the sequence is already past are ignored, and NULL is returned for
+// them. Otherwise |value| is returned.
+type SetVal struct {
+	expression.BinaryExpressionStub
+}
+
+var _ sql.FunctionExpression = (*SetVal)(nil)
+
+// NewSetVal creates a new SetVal expression.
+func NewSetVal(name, value sql.Expression) sql.Expression {
+	return &SetVal{expression.BinaryExpressionStub{LeftChild: name, RightChild: value}}
+}
+
+// Eval implements the Expression interface.
+func (s *SetVal) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	name, ok, err := sequenceName(ctx, s.LeftChild, row)
+	if err != nil || !ok {
+		return nil, err
+	}
+	val, err := s.RightChild.Eval(ctx, row)
+	if err != nil || val == nil {
+		return nil, err
+	}
+	converted, _, err := types.Int64.Convert(val)
+	if err != nil {
+		return nil, err
+	}
+	value := converted.(int64)
+
+	return updateSequence(ctx, name, func(seq doltdb.Sequence, atLeast int64) (interface{}, *int64, error) {
+		current := seq.StartValue
+		if seq.NextValue != nil {
+			current = *seq.NextValue
+		}
+		if value < max(current, atLeast) {
+			return nil, nil, nil
+		}
+		next := seq.MaxValue + 1
+		if value <= seq.MaxValue-seq.Increment {
+			next = value + seq.Increment
+		}
+		return value, &next, nil
+	})
+}
+
+// String implements the Stringer interface.
+func (s *SetVal) String() string {
+	return fmt.Sprintf("%s(%s, %s)", SetValFuncName, s.LeftChild.String(), s.RightChild.String())
+}
+
+// FunctionName implements the FunctionExpression interface
+func (s *SetVal) FunctionName() string {
+	return SetValFuncName
+}
+
+// Description implements the FunctionExpression interface
+func (s *SetVal) Description() string {
+	return "sets the last used value of a sequence"
+}
+
+// IsNonDeterministic implements sql.NonDeterministicExpression. Its result depends on the state of the sequence.
+func (s *SetVal) IsNonDeterministic() bool {
+	return true
+}
+
+// IsNullable implements the Expression interface.
+func (s *SetVal) IsNullable() bool {
+	return true
+}
+
+// WithChildren implements the Expression interface.
+func (s *SetVal) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 2 {
+		return nil, sql.ErrInvalidChildrenNumber.New(s, len(children), 2)
+	}
+	return NewSetVal(children[0], children[1]), nil
+}
+
+// Type implements the Expression interface.
+func (s *SetVal) Type() sql.Type {
+	return types.Int64
+}
@@ -0,0 +1,345 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+)
+
+const (
+	ULIDFuncName      = "ulid"
+	ULIDToBinFuncName = "ulid_to_bin"
+	BinToULIDFuncName = "bin_to_ulid"
+
+	// ulidLength is the length of the text form of a ULID
+	ulidLength = 26
+	// ulidBinaryLength is the length of the binary form of a ULID
+	ulidBinaryLength = 16
+	// crockfordAlphabet is the Crockford base32 alphabet used in the text form of ULIDs
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+var ulidType = types.MustCreateStringWithDefaults(sqltypes.Char, ulidLength)
+var ulidBinaryType = types.MustCreateBinary(sqltypes.Binary, ulidBinaryLength)
+
+// ulid is a Universally Unique Lexicographically Sortable Identifier: a 48 bit timestamp in milliseconds followed by
+// 80 random bits. Both its text and binary forms sort in the order they were generated, so they make good primary keys
+// for tables written on many branches, which unlike AUTO_INCREMENT values don't collide when the branches are merged.
+type ulid [ulidBinaryLength]byte
+
+// ulidGenerator generates monotonic ULIDs. ULIDs generated in the same millisecond increment the random bits of the
+// previous one, so that they still sort in the order they were generated.
+type ulidGenerator struct {
+	mu   sync.Mutex
+	last ulid
+}
+
+var ulids = &ulidGenerator{}
+
+func (g *ulidGenerator) next(now time.Time) (ulid, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(now.UnixMilli())
+	lastMs := binary.BigEndian.Uint64(append([]byte{0, 0}, g.last[:6]...))
+	var id ulid
+	if ms <= lastMs {
+		// the clock hasn't moved forward, so continue from the previous ULID
+		id = g.last
+		if incrementULIDRandom(&id) {
+			return id, nil
+		}
+		// the random bits overflowed, so borrow the next millisecond
+		ms = lastMs + 1
+	}
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(id[:6], ts[2:])
+	if _, err := rand.Read(id[6:]); err != nil {
+		return ulid{}, err
+	}
+	g.last = id
+	return id, nil
+}
+
+// incrementULIDRandom adds one to the random bits of |id|, returning false if they overflowed.
+func incrementULIDRandom(id *ulid) bool {
+	for i := len(id) - 1; i >= 6; i-- {
+		id[i]++
+		if id[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the text form of the ULID, 26 characters of Crockford base32.
+func (id ulid) String() string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var dst [ulidLength]byte
+	for i := ulidLength - 1; i >= 0; i-- {
+		dst[i] = crockfordAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(dst[:])
+}
+
+// parseULID parses the text form of a ULID. Letters are case-insensitive.
+func parseULID(s string) (ulid, error) {
+	if len(s) != ulidLength {
+		return ulid{}, fmt.Errorf("invalid ULID '%s': must be %d characters long", s, ulidLength)
+	}
+	if s[0] > '7' {
+		// the first character only holds the top 3 bits
+		return ulid{}, fmt.Errorf("invalid ULID '%s': value is too large", s)
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := crockfordValue(s[i])
+		if v < 0 {
+			return ulid{}, fmt.Errorf("invalid ULID '%s': invalid character '%c'", s, s[i])
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	var id ulid
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, nil
+}
+
+func crockfordValue(c byte) int {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	for i := 0; i < len(crockfordAlphabet); i++ {
+		if crockfordAlphabet[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// ULID implements ULID(), which returns a new ULID in its text form.
+type ULID struct{}
+
+var _ sql.FunctionExpression = (*ULID)(nil)
+
+// NewULID creates a new ULID expression.
+func NewULID() sql.Expression {
+	return &ULID{}
+}
+
+// Children implements the Expression interface.
+func (*ULID) Children() []sql.Expression {
+	return nil
+}
+
+// Eval implements the Expression interface.
+func (*ULID) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	id, err := ulids.next(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return id.String(), nil
+}
+
+// FunctionName implements the FunctionExpression interface
+func (*ULID) FunctionName() string {
+	return ULIDFuncName
+}
+
+// Description implements the FunctionExpression interface
+func (*ULID) Description() string {
+	return "returns a new Universally Unique Lexicographically Sortable Identifier"
+}
+
+// IsNonDeterministic implements sql.NonDeterministicExpression. Every call returns a different value.
+func (*ULID) IsNonDeterministic() bool {
+	return true
+}
+
+// IsNullable implements the Expression interface.
+func (*ULID) IsNullable() bool {
+	return false
+}
+
+// Resolved implements the Expression interface.
+func (*ULID) Resolved() bool {
+	return true
+}
+
+// String implements the Stringer interface.
+func (*ULID) String() string {
+	return "ULID()"
+}
+
+// Type implements the Expression interface.
+func (*ULID) Type() sql.Type {
+	return ulidType
+}
+
+// WithChildren implements the Expression interface.
+func (u *ULID) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(u, len(children), 0)
+	}
+	return NewULID(), nil
+}
+
+// ULIDToBin implements ULID_TO_BIN(ulid), which converts the text form of a ULID to its 16 byte binary form. The
+// binary form sorts in the same order as the text form.
+type ULIDToBin struct {
+	expression.UnaryExpression
+}
+
+var _ sql.FunctionExpression = (*ULIDToBin)(nil)
+
+// NewULIDToBin creates a new ULIDToBin expression.
+func NewULIDToBin(e sql.Expression) sql.Expression {
+	return &ULIDToBin{expression.UnaryExpression{Child: e}}
+}
+
+// Eval implements the Expression interface.
+func (u *ULIDToBin) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := u.Child.Eval(ctx, row)
+	if err != nil || val == nil {
+		return nil, err
+	}
+	s, _, err := types.LongText.Convert(val)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseULID(s.(string))
+	if err != nil {
+		return nil, err
+	}
+	return id[:], nil
+}
+
+// String implements the Stringer interface.
+func (u *ULIDToBin) String() string {
+	return fmt.Sprintf("%s(%s)", ULIDToBinFuncName, u.Child.String())
+}
+
+// FunctionName implements the FunctionExpression interface
+func (u *ULIDToBin) FunctionName() string {
+	return ULIDToBinFuncName
+}
+
+// Description implements the FunctionExpression interface
+func (u *ULIDToBin) Description() string {
+	return "converts a ULID to its binary form"
+}
+
+// IsNullable implements the Expression interface.
+func (u *ULIDToBin) IsNullable() bool {
+	return u.Child.IsNullable()
+}
+
+// WithChildren implements the Expression interface.
+func (u *ULIDToBin) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(u, len(children), 1)
+	}
+	return NewULIDToBin(children[0]), nil
+}
+
+// Type implements the Expression interface.
+func (u *ULIDToBin) Type() sql.Type {
+	return ulidBinaryType
+}
+
+// BinToULID implements BIN_TO_ULID(bin), which converts the binary form of a ULID back to its text form.
+type BinToULID struct {
+	expression.UnaryExpression
+}
+
+var _ sql.FunctionExpression = (*BinToULID)(nil)
+
+// NewBinToULID creates a new BinToULID expression.
+func NewBinToULID(e sql.Expression) sql.Expression {
+	return &BinToULID{expression.UnaryExpression{Child: e}}
+}
+
+// Eval implements the Expression interface.
+func (b *BinToULID) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := b.Child.Eval(ctx, row)
+	if err != nil || val == nil {
+		return nil, err
+	}
+	converted, _, err := types.LongBlob.Convert(val)
+	if err != nil {
+		return nil, err
+	}
+	var bin []byte
+	switch v := converted.(type) {
+	case []byte:
+		bin = v
+	case string:
+		bin = []byte(v)
+	}
+	if len(bin) != ulidBinaryLength {
+		return nil, fmt.Errorf("invalid binary ULID: must be %d bytes long", ulidBinaryLength)
+	}
+	var id ulid
+	copy(id[:], bin)
+	return id.String(), nil
+}
+
+// String implements the Stringer interface.
+func (b *BinToULID) String() string {
+	return fmt.Sprintf("%s(%s)", BinToULIDFuncName, b.Child.String())
+}
+
+// FunctionName implements the FunctionExpression interface
+func (b *BinToULID) FunctionName() string {
+	return BinToULIDFuncName
+}
+
+// Description implements the FunctionExpression interface
+func (b *BinToULID) Description() string {
+	return "converts the binary form of a ULID to its text form"
+}
+
+// IsNullable implements the Expression interface.
+func (b *BinToULID) IsNullable() bool {
+	return b.Child.IsNullable()
+}
+
+// WithChildren implements the Expression interface.
+func (b *BinToULID) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(b, len(children), 1)
+	}
+	return NewBinToULID(children[0]), nil
+}
+
+// Type implements the Expression interface.
+func (b *BinToULID) Type() sql.Type {
+	return ulidType
+}
@@ -0,0 +1,232 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const (
+	GetLockFuncName     = "get_lock"
+	ReleaseLockFuncName = "release_lock"
+	IsFreeLockFuncName  = "is_free_lock"
+	IsUsedLockFuncName  = "is_used_lock"
+
+	// MaxUserLockNameLength is the longest user-level lock name allowed, in characters.
+	MaxUserLockNameLength = 64
+
+	// getLockPollInterval is how often GET_LOCK checks whether its query was killed while waiting for a lock.
+	getLockPollInterval = 100 * time.Millisecond
+)
+
+// ErrUserLockWrongName is returned for user-level lock names which are empty or too long, like MySQL's
+// ER_USER_LOCK_WRONG_NAME.
+var ErrUserLockWrongName = errors.NewKind("Incorrect user-level lock name '%s'.")
+
+// UserLockFunctions returns the user-level lock functions GET_LOCK, RELEASE_LOCK, IS_FREE_LOCK and IS_USED_LOCK, which
+// take and inspect the named locks in |ls|. |ls| should be the engine's lock subsystem, which releases a session's
+// locks when its connection closes. They replace the go-mysql-server functions of the same names, which they differ
+// from in the same ways MySQL does: lock names are case-insensitive and limited to MaxUserLockNameLength characters,
+// and a GET_LOCK waiting for a lock stops waiting when its query is killed.
+func UserLockFunctions(ls *sql.LockSubsystem) []sql.Function {
+	return []sql.Function{
+		sql.Function2{Name: GetLockFuncName, Fn: NewGetLock(ls)},
+		sql.Function1{Name: ReleaseLockFuncName, Fn: func(name sql.Expression) sql.Expression {
+			return function.NewReleaseLock(ls)(newUserLockName(ReleaseLockFuncName, name))
+		}},
+		sql.Function1{Name: IsFreeLockFuncName, Fn: func(name sql.Expression) sql.Expression {
+			return function.NewIsFreeLock(ls)(newUserLockName(IsFreeLockFuncName, name))
+		}},
+		sql.Function1{Name: IsUsedLockFuncName, Fn: func(name sql.Expression) sql.Expression {
+			return function.NewIsUsedLock(ls)(newUserLockName(IsUsedLockFuncName, name))
+		}},
+	}
+}
+
+// userLockName normalizes the name of a user-level lock passed to one of the user-level lock functions, and returns
+// an error if it isn't a valid lock name.
+type userLockName struct {
+	expression.UnaryExpression
+	funcName string
+}
+
+var _ sql.Expression = (*userLockName)(nil)
+
+func newUserLockName(funcName string, name sql.Expression) sql.Expression {
+	return &userLockName{UnaryExpression: expression.UnaryExpression{Child: name}, funcName: funcName}
+}
+
+// Eval implements the Expression interface.
+func (n *userLockName) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := n.Child.Eval(ctx, row)
+	if err != nil || val == nil {
+		return nil, err
+	}
+	return lockNameFromValue(n.funcName, n.Child.Type(), val)
+}
+
+// lockNameFromValue returns the user-level lock name for |val|, the evaluated lock name argument of the function
+// named, which has the type given.
+func lockNameFromValue(funcName string, typ sql.Type, val interface{}) (string, error) {
+	s, ok := typ.(sql.StringType)
+	if !ok {
+		return "", function.ErrIllegalLockNameArgType.New(typ.String(), funcName)
+	}
+	name, err := types.ConvertToString(val, s)
+	if err != nil {
+		return "", fmt.Errorf("%w; %s", function.ErrIllegalLockNameArgType.New(typ.String(), funcName), err)
+	}
+	if name == "" || utf8.RuneCountInString(name) > MaxUserLockNameLength {
+		return "", ErrUserLockWrongName.New(name)
+	}
+	return strings.ToLower(name), nil
+}
+
+// Type implements the Expression interface.
+func (n *userLockName) Type() sql.Type {
+	return types.LongText
+}
+
+// IsNullable implements the Expression interface.
+func (n *userLockName) IsNullable() bool {
+	return n.Child.IsNullable()
+}
+
+// String implements the Expression interface. The lock name is displayed as it was given.
+func (n *userLockName) String() string {
+	return n.Child.String()
+}
+
+// WithChildren implements the Expression interface.
+func (n *userLockName) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 1)
+	}
+	return newUserLockName(n.funcName, children[0]), nil
+}
+
+// GetLock implements GET_LOCK(name, timeout), which waits up to |timeout| seconds to take the user-level lock named,
+// or forever if |timeout| is negative. It returns 1 if the lock was taken and 0 if the wait timed out.
+type GetLock struct {
+	expression.BinaryExpressionStub
+	ls *sql.LockSubsystem
+}
+
+var _ sql.FunctionExpression = (*GetLock)(nil)
+
+// NewGetLock returns a function creating GetLock expressions which take locks in |ls|.
+func NewGetLock(ls *sql.LockSubsystem) func(name, timeout sql.Expression) sql.Expression {
+	return func(name, timeout sql.Expression) sql.Expression {
+		return &GetLock{BinaryExpressionStub: expression.BinaryExpressionStub{LeftChild: name, RightChild: timeout}, ls: ls}
+	}
+}
+
+// FunctionName implements sql.FunctionExpression
+func (gl *GetLock) FunctionName() string {
+	return GetLockFuncName
+}
+
+// Description implements sql.FunctionExpression
+func (gl *GetLock) Description() string {
+	return "gets a named lock."
+}
+
+// Eval implements the Expression interface.
+func (gl *GetLock) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	nameVal, err := gl.LeftChild.Eval(ctx, row)
+	if err != nil || nameVal == nil {
+		return nil, err
+	}
+	timeoutVal, err := gl.RightChild.Eval(ctx, row)
+	if err != nil || timeoutVal == nil {
+		return nil, err
+	}
+
+	name, err := lockNameFromValue(gl.FunctionName(), gl.LeftChild.Type(), nameVal)
+	if err != nil {
+		return nil, err
+	}
+	seconds, _, err := types.Float64.Convert(timeoutVal)
+	if err != nil {
+		return nil, fmt.Errorf("illegal value for timeout %v", timeoutVal)
+	}
+
+	// A lock subsystem wait can't be interrupted, so wait in short intervals, checking whether the query was killed
+	// between them.
+	timeout := time.Duration(seconds.(float64) * float64(time.Second))
+	deadline := time.Now().Add(timeout)
+	dsess.SetQueryStage(ctx, dsess.QueryStageUserLockWait)
+	defer dsess.SetQueryStage(ctx, dsess.QueryStageExecuting)
+	for {
+		wait := getLockPollInterval
+		if timeout >= 0 {
+			wait = min(wait, max(time.Until(deadline), 0))
+		}
+
+		err = gl.ls.Lock(ctx, name, wait)
+		if err == nil {
+			return int8(1), nil
+		} else if !sql.ErrLockTimeout.Is(err) {
+			return nil, err
+		}
+
+		if timeout >= 0 && !time.Now().Before(deadline) {
+			return int8(0), nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (gl *GetLock) String() string {
+	return fmt.Sprintf("%s(%s, %s)", GetLockFuncName, gl.LeftChild.String(), gl.RightChild.String())
+}
+
+// IsNonDeterministic implements sql.NonDeterministicExpression. Its result depends on other sessions, so it can't be
+// used in a CHECK constraint.
+func (gl *GetLock) IsNonDeterministic() bool {
+	return true
+}
+
+// IsNullable implements the Expression interface.
+func (gl *GetLock) IsNullable() bool {
+	return true
+}
+
+// Type implements the Expression interface.
+func (gl *GetLock) Type() sql.Type {
+	return types.Int8
+}
+
+// WithChildren implements the Expression interface.
+func (gl *GetLock) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 2 {
+		return nil, sql.ErrInvalidChildrenNumber.New(gl, len(children), 2)
+	}
+	return NewGetLock(gl.ls)(children[0], children[1]), nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const changesDefaultRowCount = 100
+
+var _ sql.TableFunction = (*ChangesTableFunction)(nil)
+var _ sql.ExecSourceRel = (*ChangesTableFunction)(nil)
+
+// ChangesTableFunction is the dolt_changes table function, which returns the row level changes made by each commit on
+// the branch of a change cursor since the cursor's commit, oldest commit first. Reading changes doesn't move the
+// cursor, which is done with the dolt_change_cursor stored procedure.
+type ChangesTableFunction struct {
+	ctx *sql.Context
+
+	// dolt_changes('cursor_name')
+	cursorExpr sql.Expression
+
+	database sql.Database
+}
+
+var changesTableSchema = sql.Schema{
+	&sql.Column{Name: "commit_hash", Type: types.LongText, Nullable: false}, // 0
+	&sql.Column{Name: "commit_date", Type: types.Datetime, Nullable: false}, // 1
+	&sql.Column{Name: "table_name", Type: types.LongText, Nullable: false},  // 2
+	&sql.Column{Name: "diff_type", Type: types.LongText, Nullable: false},   // 3
+	&sql.Column{Name: "primary_key", Type: types.JSON, Nullable: true},      // 4
+	&sql.Column{Name: "from_row", Type: types.JSON, Nullable: true},         // 5
+	&sql.Column{Name: "to_row", Type: types.JSON, Nullable: true},           // 6
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (ct *ChangesTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &ChangesTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+func (ct *ChangesTableFunction) DataLength(ctx *sql.Context) (uint64, error) {
+	numBytesPerRow := schema.SchemaAvgLength(ct.Schema())
+	numRows, _, err := ct.RowCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return numBytesPerRow * numRows, nil
+}
+
+func (ct *ChangesTableFunction) RowCount(_ *sql.Context) (uint64, bool, error) {
+	return changesDefaultRowCount, false, nil
+}
+
+// Database implements the sql.Databaser interface
+func (ct *ChangesTableFunction) Database() sql.Database {
+	return ct.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (ct *ChangesTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nct := *ct
+	nct.database = database
+	return &nct, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (ct *ChangesTableFunction) Name() string {
+	return "dolt_changes"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (ct *ChangesTableFunction) Resolved() bool {
+	return ct.cursorExpr == nil || ct.cursorExpr.Resolved()
+}
+
+func (ct *ChangesTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (ct *ChangesTableFunction) String() string {
+	args := make([]string, len(ct.Expressions()))
+	for i, expr := range ct.Expressions() {
+		args[i] = expr.String()
+	}
+	return fmt.Sprintf("DOLT_CHANGES(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (ct *ChangesTableFunction) Schema() sql.Schema {
+	return changesTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (ct *ChangesTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (ct *ChangesTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return ct, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (ct *ChangesTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	// changes can be made to any table, so reading them requires access to the whole database
+	subject := sql.PrivilegeCheckSubject{Database: ct.database.Name()}
+	return opChecker.UserHasPrivileges(ctx, sql.NewPrivilegedOperation(subject, sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (ct *ChangesTableFunction) Expressions() []sql.Expression {
+	if ct.cursorExpr == nil {
+		return nil
+	}
+	return []sql.Expression{ct.cursorExpr}
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (ct *ChangesTableFunction) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New(ct.Name(), 1, len(exprs))
+	}
+
+	expr := exprs[0]
+	if !expr.Resolved() {
+		return nil, ErrInvalidNonLiteralArgument.New(ct.Name(), expr.String())
+	}
+	// prepared statements resolve functions beforehand, so above check fails
+	if _, ok := expr.(sql.FunctionExpression); ok {
+		return nil, ErrInvalidNonLiteralArgument.New(ct.Name(), expr.String())
+	}
+	if !types.IsText(expr.Type()) && !expression.IsBindVar(expr) {
+		return nil, sql.ErrInvalidArgumentDetails.New(ct.Name(), expr.String())
+	}
+
+	nct := *ct
+	nct.cursorExpr = expr
+	return &nct, nil
+}
+
+// RowIter implements the sql.Node interface
+func (ct *ChangesTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	cursorVal, err := ct.cursorExpr.Eval(ct.ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	cursorName, ok := cursorVal.(string)
+	if !ok {
+		return nil, sql.ErrInvalidArgumentDetails.New(ct.Name(), ct.cursorExpr.String())
+	}
+
+	sqledb, ok := ct.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", ct.database)
+	}
+	ddb := sqledb.DbData().Ddb
+
+	c, ok, err := ddb.GetChangeCursor(ctx, cursorName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("%w: %s", cdc.ErrCursorNotFound, cursorName)
+	}
+	it, err := cdc.NewChangeIter(ctx, ddb, c)
+	if err != nil {
+		return nil, err
+	}
+	return &changesRowIter{it: it}, nil
+}
+
+// changesRowIter streams the changes read by a cdc.ChangeIter as rows of the dolt_changes table function.
+type changesRowIter struct {
+	it *cdc.ChangeIter
+}
+
+var _ sql.RowIter = (*changesRowIter)(nil)
+
+func (itr *changesRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	c, err := itr.it.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := changesJSON(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	from, err := changesJSON(c.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := changesJSON(c.To)
+	if err != nil {
+		return nil, err
+	}
+	return sql.Row{
+		c.Commit,     // 0
+		c.CommitDate, // 1
+		c.Table,      // 2
+		c.Type,       // 3
+		key,          // 4
+		from,         // 5
+		to,           // 6
+	}, nil
+}
+
+func (itr *changesRowIter) Close(*sql.Context) error {
+	return nil
+}
+
+// changesJSON returns |m| as a JSON value, or nil if it's empty.
+func changesJSON(m map[string]interface{}) (interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+	v, _, err := types.JSON.Convert(m)
+	return v, err
+}
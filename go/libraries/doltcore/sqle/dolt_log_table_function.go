@@ -15,7 +15,10 @@
 package sqle
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -24,11 +27,15 @@ import (
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
 )
 
 const logTableDefaultRowCount = 10
@@ -43,6 +50,7 @@ type LogTableFunction struct {
 	notRevisionExprs []sql.Expression
 	notRevisionStrs  []string
 	tableNames       []string
+	keyRange         *logKeyRange
 
 	minParents  int
 	showParents bool
@@ -148,6 +156,15 @@ func (ltf *LogTableFunction) getOptionsString() string {
 		options = append(options, "--tables", strings.Join(ltf.tableNames, ","))
 	}
 
+	if ltf.keyRange != nil {
+		if len(ltf.keyRange.from) > 0 {
+			options = append(options, fmt.Sprintf("--%s %s", cli.FromKeyFlag, formatLogKey(ltf.keyRange.from)))
+		}
+		if len(ltf.keyRange.to) > 0 {
+			options = append(options, fmt.Sprintf("--%s %s", cli.ToKeyFlag, formatLogKey(ltf.keyRange.to)))
+		}
+	}
+
 	return strings.Join(options, ", ")
 }
 
@@ -246,6 +263,30 @@ func (ltf *LogTableFunction) addOptions(expression []sql.Expression) error {
 		ltf.tableNames = append(ltf.tableNames, tableNames...)
 	}
 
+	fromKey, hasFromKey := apr.GetValue(cli.FromKeyFlag)
+	toKey, hasToKey := apr.GetValue(cli.ToKeyFlag)
+	if hasFromKey || hasToKey {
+		if len(ltf.tableNames) != 1 {
+			return ltf.invalidArgDetailsErr(fmt.Sprintf("--%s and --%s require exactly one table in --%s", cli.FromKeyFlag, cli.ToKeyFlag, cli.TablesFlag))
+		}
+		kr := &logKeyRange{}
+		var err error
+		if hasFromKey {
+			if kr.from, err = parseLogKey(fromKey); err != nil {
+				return ltf.invalidArgDetailsErr(fmt.Sprintf("invalid --%s: %s", cli.FromKeyFlag, err.Error()))
+			}
+		}
+		if hasToKey {
+			if kr.to, err = parseLogKey(toKey); err != nil {
+				return ltf.invalidArgDetailsErr(fmt.Sprintf("invalid --%s: %s", cli.ToKeyFlag, err.Error()))
+			}
+		}
+		if hasFromKey && hasToKey && len(kr.from) != len(kr.to) {
+			return ltf.invalidArgDetailsErr(fmt.Sprintf("--%s and --%s must specify the same number of key columns", cli.FromKeyFlag, cli.ToKeyFlag))
+		}
+		ltf.keyRange = kr
+	}
+
 	minParents := apr.GetIntOrDefault(cli.MinParentsFlag, 0)
 	if apr.Contains(cli.MergesFlag) {
 		minParents = 2
@@ -600,6 +641,7 @@ type logTableFunctionRowIter struct {
 	headHash    hash.Hash
 
 	tableNames []string
+	keyRange   *logKeyRange
 }
 
 func (ltf *LogTableFunction) NewLogTableFunctionRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, commit *doltdb.Commit, matchFn func(*doltdb.OptionalCommit) (bool, error), cHashToRefs map[hash.Hash][]string, tableNames []string) (*logTableFunctionRowIter, error) {
@@ -620,6 +662,7 @@ func (ltf *LogTableFunction) NewLogTableFunctionRowIter(ctx *sql.Context, ddb *d
 		cHashToRefs: cHashToRefs,
 		headHash:    h,
 		tableNames:  tableNames,
+		keyRange:    ltf.keyRange,
 	}, nil
 }
 
@@ -660,6 +703,7 @@ func (ltf *LogTableFunction) NewDotDotLogTableFunctionRowIter(ctx *sql.Context,
 		cHashToRefs: cHashToRefs,
 		headHash:    headHash,
 		tableNames:  tableNames,
+		keyRange:    ltf.keyRange,
 	}, nil
 }
 
@@ -730,6 +774,12 @@ func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 				if err != nil {
 					return nil, err
 				}
+				if didChange && itr.keyRange != nil {
+					didChange, err = itr.keyRange.didRowsChangeBetweenRootValues(ctx, childRV, parent0RV, parent1RV, tableName)
+					if err != nil {
+						return nil, err
+					}
+				}
 				if didChange {
 					break
 				}
@@ -856,3 +906,183 @@ func didTableChangeBetweenRootValues(ctx *sql.Context, child, parent0, parent1 d
 		}
 	}
 }
+
+// logKeyRange restricts a table-filtered log to commits that modified rows whose primary key falls within an inclusive
+// key range. Each bound is a prefix of the table's primary key, and an empty bound leaves that end of the range open.
+type logKeyRange struct {
+	from []string
+	to   []string
+}
+
+// parseLogKey parses the value of --from-key or --to-key. A value is the first column of the primary key, unless it's a
+// JSON array, like ["a,b", 2], which gives a prefix of a composite primary key.
+func parseLogKey(key string) ([]string, error) {
+	if !strings.HasPrefix(strings.TrimSpace(key), "[") {
+		return []string{key}, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(key))
+	dec.UseNumber()
+	var vals []interface{}
+	if err := dec.Decode(&vals); err != nil {
+		return nil, fmt.Errorf("composite keys must be JSON arrays: %w", err)
+	}
+	if len(vals) == 0 {
+		return nil, fmt.Errorf("composite keys must have at least one column")
+	}
+
+	strs := make([]string, len(vals))
+	for i, v := range vals {
+		switch v := v.(type) {
+		case string:
+			strs[i] = v
+		case json.Number:
+			strs[i] = v.String()
+		default:
+			return nil, fmt.Errorf("key columns must be strings or numbers")
+		}
+	}
+	return strs, nil
+}
+
+// formatLogKey formats a key parsed by parseLogKey.
+func formatLogKey(vals []string) string {
+	if len(vals) == 1 && !strings.HasPrefix(strings.TrimSpace(vals[0]), "[") {
+		return vals[0]
+	}
+	b, _ := json.Marshal(vals)
+	return string(b)
+}
+
+// didRowsChangeBetweenRootValues checks if any row of the given table within the key range changed between the child
+// root value and either of its parents. Identical subtrees are skipped by the prolly tree differ, so only the chunks
+// that actually differ between the commits are visited.
+func (kr *logKeyRange) didRowsChangeBetweenRootValues(ctx *sql.Context, child, parent0, parent1 doltdb.RootValue, tableName string) (bool, error) {
+	changed, err := kr.didRowsChange(ctx, parent0, child, tableName)
+	if err != nil || changed || parent1 == nil {
+		return changed, err
+	}
+	return kr.didRowsChange(ctx, parent1, child, tableName)
+}
+
+func (kr *logKeyRange) didRowsChange(ctx *sql.Context, from, to doltdb.RootValue, tableName string) (bool, error) {
+	fromSch, fromRows, fromOk, err := getTableRowMap(ctx, from, tableName)
+	if err != nil {
+		return false, err
+	}
+	toSch, toRows, toOk, err := getTableRowMap(ctx, to, tableName)
+	if err != nil {
+		return false, err
+	}
+
+	sch := toSch
+	if !toOk {
+		if !fromOk {
+			return false, nil
+		}
+		sch = fromSch
+		toRows, err = emptyMapLike(ctx, fromRows)
+	} else if !fromOk {
+		fromRows, err = emptyMapLike(ctx, toRows)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	fromKd, _ := fromRows.Descriptors()
+	toKd, _ := toRows.Descriptors()
+	if !fromKd.Equals(toKd) {
+		// the primary key changed, so every row is considered modified
+		return true, nil
+	}
+
+	rng, err := kr.toRange(ctx, sch, toKd, toRows.NodeStore())
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	err = prolly.RangeDiffMaps(ctx, fromRows, toRows, rng, func(_ context.Context, _ tree.Diff) error {
+		changed = true
+		return io.EOF
+	})
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return changed, nil
+}
+
+// toRange converts the key range bounds into a prolly.Range over the primary key prefix they specify.
+func (kr *logKeyRange) toRange(ctx context.Context, sch schema.Schema, kd val.TupleDesc, ns tree.NodeStore) (prolly.Range, error) {
+	pkCols := sch.GetPKCols()
+	if schema.IsKeyless(sch) {
+		return prolly.Range{}, fmt.Errorf("--%s and --%s require a table with a primary key", cli.FromKeyFlag, cli.ToKeyFlag)
+	}
+
+	n := len(kr.from)
+	if len(kr.to) > n {
+		n = len(kr.to)
+	}
+	if n > pkCols.Size() {
+		return prolly.Range{}, fmt.Errorf("key range specifies %d columns, but the primary key has %d", n, pkCols.Size())
+	}
+
+	desc := kd.PrefixDesc(n)
+	buildKey := func(vals []string) (val.Tuple, error) {
+		tb := val.NewTupleBuilder(desc)
+		for i, s := range vals {
+			v, _, err := pkCols.GetByIndex(i).TypeInfo.ToSqlType().Convert(s)
+			if err != nil {
+				return nil, err
+			}
+			if err = tree.PutField(ctx, ns, tb, i, v); err != nil {
+				return nil, err
+			}
+		}
+		return tb.Build(ns.Pool()), nil
+	}
+
+	var start, stop val.Tuple
+	var err error
+	if len(kr.from) > 0 {
+		if start, err = buildKey(kr.from); err != nil {
+			return prolly.Range{}, err
+		}
+	}
+	if len(kr.to) > 0 {
+		if stop, err = buildKey(kr.to); err != nil {
+			return prolly.Range{}, err
+		}
+	}
+
+	switch {
+	case start != nil && stop != nil:
+		return prolly.ClosedRange(start, stop, desc), nil
+	case start != nil:
+		return prolly.GreaterOrEqualRange(start, desc), nil
+	default:
+		return prolly.LesserOrEqualRange(stop, desc), nil
+	}
+}
+
+// getTableRowMap returns the schema and primary row data of the given table, if it exists in |root|.
+func getTableRowMap(ctx *sql.Context, root doltdb.RootValue, tableName string) (schema.Schema, prolly.Map, bool, error) {
+	tbl, ok, err := root.GetTable(ctx, doltdb.TableName{Name: tableName})
+	if err != nil || !ok {
+		return nil, prolly.Map{}, false, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, prolly.Map{}, false, err
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, prolly.Map{}, false, err
+	}
+	return sch, durable.ProllyMapFromIndex(idx), true, nil
+}
+
+func emptyMapLike(ctx context.Context, m prolly.Map) (prolly.Map, error) {
+	kd, vd := m.Descriptors()
+	return prolly.NewMapFromTuples(ctx, m.NodeStore(), kd, vd)
+}
@@ -0,0 +1,396 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	textdiff "github.com/andreyvit/diff"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const schemaObjectDiffDefaultRowCount = 10
+
+var _ sql.TableFunction = (*SchemaObjectDiffTableFunction)(nil)
+var _ sql.ExecSourceRel = (*SchemaObjectDiffTableFunction)(nil)
+
+// SchemaObjectDiffTableFunction is the dolt_schema_object_diff table function, which returns the views, triggers,
+// events and stored procedures whose definitions differ between two refs, along with a line diff of each definition.
+type SchemaObjectDiffTableFunction struct {
+	ctx *sql.Context
+
+	// fromCommitExpr and toCommitExpr are the first two expressions, unless the first contains '..'
+	// dolt_schema_object_diff('from_commit', 'to_commit', 'object_name')
+	fromCommitExpr sql.Expression
+	toCommitExpr   sql.Expression
+
+	// dotCommitExpr is the first expression if it contains '..'
+	// dolt_schema_object_diff('from_commit..to_commit', 'object_name')
+	dotCommitExpr sql.Expression
+
+	// objectNameExpr is the optional expression that follows the commit expressions
+	objectNameExpr sql.Expression
+
+	database sql.Database
+}
+
+var schemaObjectDiffTableSchema = sql.Schema{
+	&sql.Column{Name: "object_type", Type: types.LongText, Nullable: false},    // 0
+	&sql.Column{Name: "name", Type: types.LongText, Nullable: false},           // 1
+	&sql.Column{Name: "diff_type", Type: types.LongText, Nullable: false},      // 2
+	&sql.Column{Name: "from_definition", Type: types.LongText, Nullable: true}, // 3
+	&sql.Column{Name: "to_definition", Type: types.LongText, Nullable: true},   // 4
+	&sql.Column{Name: "diff", Type: types.LongText, Nullable: false},           // 5
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (ds *SchemaObjectDiffTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &SchemaObjectDiffTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+func (ds *SchemaObjectDiffTableFunction) DataLength(ctx *sql.Context) (uint64, error) {
+	numBytesPerRow := schema.SchemaAvgLength(ds.Schema())
+	numRows, _, err := ds.RowCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return numBytesPerRow * numRows, nil
+}
+
+func (ds *SchemaObjectDiffTableFunction) RowCount(_ *sql.Context) (uint64, bool, error) {
+	return schemaObjectDiffDefaultRowCount, false, nil
+}
+
+// Database implements the sql.Databaser interface
+func (ds *SchemaObjectDiffTableFunction) Database() sql.Database {
+	return ds.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (ds *SchemaObjectDiffTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nds := *ds
+	nds.database = database
+	return &nds, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (ds *SchemaObjectDiffTableFunction) Name() string {
+	return "dolt_schema_object_diff"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (ds *SchemaObjectDiffTableFunction) Resolved() bool {
+	for _, expr := range ds.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+func (ds *SchemaObjectDiffTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (ds *SchemaObjectDiffTableFunction) String() string {
+	args := make([]string, len(ds.Expressions()))
+	for i, expr := range ds.Expressions() {
+		args[i] = expr.String()
+	}
+	return fmt.Sprintf("DOLT_SCHEMA_OBJECT_DIFF(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (ds *SchemaObjectDiffTableFunction) Schema() sql.Schema {
+	return schemaObjectDiffTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (ds *SchemaObjectDiffTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (ds *SchemaObjectDiffTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return ds, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (ds *SchemaObjectDiffTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	// views, triggers, events and procedures are stored in system tables, so reading their definitions requires
+	// access to the whole database
+	subject := sql.PrivilegeCheckSubject{Database: ds.database.Name()}
+	return opChecker.UserHasPrivileges(ctx, sql.NewPrivilegedOperation(subject, sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (ds *SchemaObjectDiffTableFunction) Expressions() []sql.Expression {
+	exprs := []sql.Expression{}
+	if ds.dotCommitExpr != nil {
+		exprs = append(exprs, ds.dotCommitExpr)
+	} else {
+		exprs = append(exprs, ds.fromCommitExpr, ds.toCommitExpr)
+	}
+	if ds.objectNameExpr != nil {
+		exprs = append(exprs, ds.objectNameExpr)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (ds *SchemaObjectDiffTableFunction) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) < 1 || len(exprs) > 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New(ds.Name(), "1 to 3", len(exprs))
+	}
+
+	for _, expr := range exprs {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(ds.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(ds.Name(), expr.String())
+		}
+	}
+
+	newDsf := *ds
+	if strings.Contains(exprs[0].String(), "..") {
+		if len(exprs) > 2 {
+			return nil, sql.ErrInvalidArgumentDetails.New(newDsf.Name(), "There are more than 2 arguments present, and the first contains '..'")
+		}
+		newDsf.dotCommitExpr = exprs[0]
+		if len(exprs) > 1 {
+			newDsf.objectNameExpr = exprs[1]
+		}
+	} else {
+		if len(exprs) < 2 {
+			return nil, sql.ErrInvalidArgumentDetails.New(newDsf.Name(), "There are less than 2 arguments present, and the first does not contain '..'")
+		}
+		newDsf.fromCommitExpr = exprs[0]
+		newDsf.toCommitExpr = exprs[1]
+		if len(exprs) > 2 {
+			newDsf.objectNameExpr = exprs[2]
+		}
+	}
+
+	for _, expr := range newDsf.Expressions() {
+		if !types.IsText(expr.Type()) && !expression.IsBindVar(expr) {
+			return nil, sql.ErrInvalidArgumentDetails.New(newDsf.Name(), expr.String())
+		}
+	}
+
+	return &newDsf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (ds *SchemaObjectDiffTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	fromCommitVal, toCommitVal, dotCommitVal, objectName, err := ds.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	sqledb, ok := ds.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", ds.database)
+	}
+
+	fromCommitStr, toCommitStr, err := loadCommitStrings(ctx, fromCommitVal, toCommitVal, dotCommitVal, sqledb)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	fromRoot, _, _, err := sess.ResolveRootForRef(ctx, sqledb.Name(), fromCommitStr)
+	if err != nil {
+		return nil, err
+	}
+	toRoot, _, _, err := sess.ResolveRootForRef(ctx, sqledb.Name(), toCommitStr)
+	if err != nil {
+		return nil, err
+	}
+
+	fromObjects, err := getSchemaObjects(ctx, fromRoot)
+	if err != nil {
+		return nil, err
+	}
+	toObjects, err := getSchemaObjects(ctx, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]schemaObjectKey, 0, len(fromObjects)+len(toObjects))
+	for key := range fromObjects {
+		keys = append(keys, key)
+	}
+	for key := range toObjects {
+		if _, ok := fromObjects[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].objectType != keys[j].objectType {
+			return keys[i].objectType < keys[j].objectType
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	var rows []sql.Row
+	for _, key := range keys {
+		if objectName != "" && !strings.EqualFold(objectName, key.name) {
+			continue
+		}
+
+		fromDefn, inFrom := fromObjects[key]
+		toDefn, inTo := toObjects[key]
+		var diffType string
+		var fromVal, toVal interface{}
+		switch {
+		case !inFrom:
+			diffType, toVal = "added", toDefn
+		case !inTo:
+			diffType, fromVal = "removed", fromDefn
+		case fromDefn != toDefn:
+			diffType, fromVal, toVal = "modified", fromDefn, toDefn
+		default:
+			continue
+		}
+
+		rows = append(rows, sql.Row{
+			key.objectType,                      // 0
+			key.name,                            // 1
+			diffType,                            // 2
+			fromVal,                             // 3
+			toVal,                               // 4
+			textdiff.LineDiff(fromDefn, toDefn), // 5
+		})
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// evaluateArguments returns fromCommitVal, toCommitVal, dotCommitVal, and objectName. Note that this method only evals
+// the expressions, and doesn't validate the values.
+func (ds *SchemaObjectDiffTableFunction) evaluateArguments() (interface{}, interface{}, interface{}, string, error) {
+	var objectName string
+	if ds.objectNameExpr != nil {
+		objectNameVal, err := ds.objectNameExpr.Eval(ds.ctx, nil)
+		if err != nil {
+			return nil, nil, nil, "", err
+		}
+		name, ok := objectNameVal.(string)
+		if !ok {
+			return nil, nil, nil, "", sql.ErrInvalidArgumentDetails.New(ds.Name(), ds.objectNameExpr.String())
+		}
+		objectName = name
+	}
+
+	if ds.dotCommitExpr != nil {
+		dotCommitVal, err := ds.dotCommitExpr.Eval(ds.ctx, nil)
+		if err != nil {
+			return nil, nil, nil, "", err
+		}
+		return nil, nil, dotCommitVal, objectName, nil
+	}
+
+	fromCommitVal, err := ds.fromCommitExpr.Eval(ds.ctx, nil)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+	toCommitVal, err := ds.toCommitExpr.Eval(ds.ctx, nil)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+	return fromCommitVal, toCommitVal, nil, objectName, nil
+}
+
+// schemaObjectKey identifies a view, trigger, event or stored procedure. Names are lowercased, since they're
+// case-insensitive.
+type schemaObjectKey struct {
+	objectType string
+	name       string
+}
+
+// getSchemaObjects returns the definitions of the views, triggers and events in the dolt_schemas table of |root|, and
+// of the stored procedures in its dolt_procedures table.
+func getSchemaObjects(ctx *sql.Context, root doltdb.RootValue) (map[schemaObjectKey]string, error) {
+	objects := make(map[schemaObjectKey]string)
+	err := readSchemaObjects(ctx, root, doltdb.SchemasTableName, func(sch sql.Schema, r sql.Row) {
+		typeIdx := sch.IndexOfColName(doltdb.SchemasTablesTypeCol)
+		nameIdx := sch.IndexOfColName(doltdb.SchemasTablesNameCol)
+		fragmentIdx := sch.IndexOfColName(doltdb.SchemasTablesFragmentCol)
+		objType, _ := r[typeIdx].(string)
+		name, _ := r[nameIdx].(string)
+		fragment, _ := r[fragmentIdx].(string)
+		objects[schemaObjectKey{objectType: objType, name: strings.ToLower(name)}] = fragment
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readSchemaObjects(ctx, root, doltdb.ProceduresTableName, func(sch sql.Schema, r sql.Row) {
+		nameIdx := sch.IndexOfColName(doltdb.ProceduresTableNameCol)
+		createStmtIdx := sch.IndexOfColName(doltdb.ProceduresTableCreateStmtCol)
+		name, _ := r[nameIdx].(string)
+		createStmt, _ := r[createStmtIdx].(string)
+		objects[schemaObjectKey{objectType: "procedure", name: strings.ToLower(name)}] = createStmt
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// readSchemaObjects calls |cb| with each row of the system table |tableName| in |root|, if it exists.
+func readSchemaObjects(ctx *sql.Context, root doltdb.RootValue, tableName string, cb func(sch sql.Schema, r sql.Row)) error {
+	tbl, name, ok, err := doltdb.GetTableInsensitive(ctx, root, doltdb.TableName{Name: tableName})
+	if err != nil || !ok {
+		return err
+	}
+	sch, iter, err := DoltTablePartitionToRowIter(ctx, name, tbl, 0, math.MaxUint64)
+	if err != nil {
+		return err
+	}
+	rows, err := sql.RowIterToRows(ctx, iter)
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		cb(sch, r)
+	}
+	return nil
+}
@@ -71,11 +71,23 @@ func doDoltBranch(ctx *sql.Context, args []string) (int, error) {
 
 	var rsc doltdb.ReplicationStatusController
 
+	bulk := apr.Contains(cli.MergedParam) || apr.Contains(cli.NoMergedParam)
+	if bulk && !apr.Contains(cli.DeleteFlag) && !apr.Contains(cli.DeleteForceFlag) {
+		return 1, fmt.Errorf("error: --merged and --no-merged can only be used to delete branches with -d or -D; " +
+			"use has_ancestor() with the dolt_branches system table to list them")
+	} else if apr.Contains(cli.DryRunFlag) && !bulk {
+		return 1, fmt.Errorf("error: --dry-run can only be used with --merged or --no-merged")
+	}
+
 	switch {
+	case apr.Contains(cli.SetUpstreamToParam):
+		err = setBranchUpstream(ctx, dbData, apr)
 	case apr.Contains(cli.CopyFlag):
 		err = copyBranch(ctx, dbData, apr, &rsc)
 	case apr.Contains(cli.MoveFlag):
 		err = renameBranch(ctx, dbData, apr, dSess, dbName, &rsc)
+	case bulk:
+		err = deleteMergedBranches(ctx, dbData, apr, dSess, dbName, &rsc)
 	case apr.Contains(cli.DeleteFlag), apr.Contains(cli.DeleteForceFlag):
 		err = deleteBranches(ctx, dbData, apr, dSess, dbName, &rsc)
 	default:
@@ -122,6 +134,9 @@ func renameBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseRe
 	if err := branch_control.CanDeleteBranch(ctx, oldBranchName); err != nil {
 		return err
 	}
+	if err := checkBranchRefProtection(ctx, dbData.Ddb, oldBranchName); err != nil {
+		return err
+	}
 	if err := branch_control.CanCreateBranch(ctx, newBranchName); err != nil {
 		return err
 	}
@@ -149,6 +164,8 @@ func renameBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseRe
 		// If force is enabled, we can overwrite the destination branch, so we require a permission check here, even if the
 		// destination branch doesn't exist. An unauthorized user could simply rerun the command without the force flag.
 		return err
+	} else if err := checkBranchRefProtection(ctx, dbData.Ddb, newBranchName); err != nil {
+		return err
 	}
 
 	headRef, err := dbData.Rsr.CWBHeadRef()
@@ -223,6 +240,9 @@ func deleteBranches(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParse
 		if err = branch_control.CanDeleteBranch(ctx, branchName); err != nil {
 			return err
 		}
+		if err = checkBranchRefProtection(ctx, dbData.Ddb, branchName); err != nil {
+			return err
+		}
 	}
 
 	dSess := dsess.DSessFromSess(ctx.Session)
@@ -278,6 +298,177 @@ func deleteBranches(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParse
 	return nil
 }
 
+// setBranchUpstream sets the upstream of the branch given, or of the current branch if none is, to the remote tracking
+// branch given with --set-upstream-to, which must exist. This requires the same permissions as deleting the branch.
+func setBranchUpstream(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseResults) error {
+	if apr.NArg() > 1 {
+		return InvalidArgErr
+	}
+	var branchName string
+	if apr.NArg() == 1 {
+		branchName = apr.Arg(0)
+	} else {
+		headRef, err := dbData.Rsr.CWBHeadRef()
+		if err != nil {
+			return err
+		}
+		branchName = headRef.GetPath()
+	}
+	existing, ok, err := dbData.Ddb.HasBranch(ctx, branchName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("fatal: branch '%s' does not exist", branchName)
+	}
+	if err = branch_control.CanDeleteBranch(ctx, existing); err != nil {
+		return err
+	}
+
+	upstream, _ := apr.GetValue(cli.SetUpstreamToParam)
+	remoteName, remoteBranch := actions.ParseRemoteBranchName(upstream)
+	if remoteName == "" {
+		return fmt.Errorf("fatal: the requested upstream branch '%s' does not exist", upstream)
+	}
+	ok, err = dbData.Ddb.HasRef(ctx, ref.NewRemoteRef(remoteName, remoteBranch))
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("fatal: the requested upstream branch '%s' does not exist", upstream)
+	}
+	refSpec, err := ref.ParseRefSpecForRemote(remoteName, remoteBranch)
+	if err != nil {
+		return err
+	}
+	return env.SetRemoteUpstreamForRefSpec(dbData.Rsw, refSpec, remoteName, ref.NewBranchRef(existing))
+}
+
+// deleteMergedBranches deletes every local branch which is merged into the commit given with --merged, or which isn't
+// merged into the commit given with --no-merged, which requires -D. The current branch, the branch given as the
+// commit, and the default branch of a running server are never deleted, and neither are branches checked out in other
+// sessions unless forced. Each branch deleted, or which would be deleted with --dry-run, is reported with a note.
+func deleteMergedBranches(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseResults, sess *dsess.DoltSession, dbName string, rsc *doltdb.ReplicationStatusController) error {
+	if apr.NArg() > 0 {
+		return fmt.Errorf("error: branch names can't be given with --merged or --no-merged")
+	}
+	target, merged := apr.GetValue(cli.MergedParam)
+	if !merged {
+		target, _ = apr.GetValue(cli.NoMergedParam)
+	} else if apr.Contains(cli.NoMergedParam) {
+		return fmt.Errorf("error: --merged and --no-merged can't be used together")
+	}
+	force := apr.Contains(cli.DeleteForceFlag) || apr.Contains(cli.ForceFlag)
+	if !merged && !force {
+		return fmt.Errorf("error: branches which aren't merged can only be deleted with -D")
+	}
+	if target == "" {
+		target = "HEAD"
+	}
+
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return err
+	}
+	cs, err := doltdb.NewCommitSpec(target)
+	if err != nil {
+		return err
+	}
+	optCmt, err := dbData.Ddb.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return err
+	}
+	targetCommit, ok := optCmt.ToCommit()
+	if !ok {
+		return doltdb.ErrGhostCommitEncountered
+	}
+
+	// The default branch of a running server is kept, as it is for deleteBranches.
+	var headOnCLI string
+	if fs, err := sess.Provider().FileSystemForDatabase(dbName); err == nil && sqlserver.RunningInServerMode() && !shouldAllowDefaultBranchDeletion(ctx) {
+		if repoState, err := env.LoadRepoState(fs); err == nil {
+			headOnCLI = repoState.Head.Ref.GetPath()
+		}
+	}
+
+	branches, err := dbData.Ddb.GetBranches(ctx)
+	if err != nil {
+		return err
+	}
+	var toDelete []string
+	for _, branchRef := range branches {
+		name := branchRef.GetPath()
+		if ref.Equals(branchRef, headRef) || strings.EqualFold(name, target) || name == headOnCLI {
+			continue
+		}
+		branchCommit, err := dbData.Ddb.ResolveCommitRef(ctx, branchRef)
+		if err != nil {
+			return err
+		}
+		isMerged, err := isMergedInto(ctx, branchCommit, targetCommit)
+		if err != nil {
+			return err
+		} else if isMerged != merged {
+			continue
+		}
+		if err = branch_control.CanDeleteBranch(ctx, name); err != nil {
+			return err
+		}
+		if err = checkBranchRefProtection(ctx, dbData.Ddb, name); err != nil {
+			return err
+		}
+		if !force {
+			if err = validateBranchNotActiveInAnySession(ctx, name); err != nil {
+				ctx.Session.Warn(&sql.Warning{
+					Level:   "Note",
+					Code:    1105,
+					Message: fmt.Sprintf("skipped branch '%s', which is checked out in another session", name),
+				})
+				continue
+			}
+		}
+		toDelete = append(toDelete, name)
+	}
+
+	if apr.Contains(cli.DryRunFlag) {
+		for _, name := range toDelete {
+			ctx.Session.Warn(&sql.Warning{Level: "Note", Code: 1105, Message: fmt.Sprintf("would delete branch '%s'", name)})
+		}
+		return nil
+	}
+
+	for _, name := range toDelete {
+		// whether the branch is merged was already checked against the target rather than its upstream
+		err = actions.DeleteBranch(ctx, dbData, name, actions.DeleteOptions{Force: true}, sess.Provider(), rsc)
+		if err != nil {
+			return err
+		}
+		ctx.Session.Warn(&sql.Warning{Level: "Note", Code: 1105, Message: fmt.Sprintf("deleted branch '%s'", name)})
+	}
+	return nil
+}
+
+// isMergedInto returns whether |branchCommit| is |target| or one of its ancestors.
+func isMergedInto(ctx *sql.Context, branchCommit, target *doltdb.Commit) (bool, error) {
+	optCmt, err := doltdb.GetCommitAncestor(ctx, branchCommit, target)
+	if errors.Is(err, doltdb.ErrNoCommonAncestor) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	ancestor, ok := optCmt.ToCommit()
+	if !ok {
+		return false, doltdb.ErrGhostCommitEncountered
+	}
+	ancestorHash, err := ancestor.HashOf()
+	if err != nil {
+		return false, err
+	}
+	branchHash, err := branchCommit.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return ancestorHash == branchHash, nil
+}
+
 // shouldAllowDefaultBranchDeletion returns true if the default branch deletion check should be
 // bypassed for testing. This should only ever be true for tests that need to invalidate a databases
 // default branch to test recovery from a bad state. We determine if the check should be bypassed by
@@ -340,6 +531,29 @@ func validateBranchNotActiveInAnySession(ctx *sql.Context, branchName string) er
 }
 
 // TODO: the config should be available via the context, it's unnecessary to do an env.Load here and this should be removed
+// checkBranchRefProtection returns an error wrapping doltdb.ErrBranchProtected if the dolt_branch_protection rules at
+// the head of |branchName| forbid the current user from moving, renaming or deleting it. Since none of these are merges,
+// this is an error for every protected branch. Branches which don't exist aren't protected.
+func checkBranchRefProtection(ctx *sql.Context, ddb *doltdb.DoltDB, branchName string) error {
+	branchName, ok, err := ddb.HasBranch(ctx, branchName)
+	if err != nil || !ok {
+		return err
+	}
+	head, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef(branchName))
+	if err != nil {
+		return err
+	}
+	root, err := head.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	protections, err := doltdb.GetBranchProtections(ctx, root)
+	if err != nil || len(protections) == 0 {
+		return err
+	}
+	return protections.CheckBranchProtection(branchName, ctx.Session.Client().User, false)
+}
+
 func loadConfig(ctx *sql.Context) *env.DoltCliConfig {
 	// When executing branch actions from SQL, we don't have access to a DoltEnv like we do from
 	// within the CLI. We can fake it here enough to get a DoltCliConfig, but we can't rely on the
@@ -405,6 +619,12 @@ func createNewBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgPars
 	if err != nil {
 		return err
 	}
+	if apr.Contains(cli.ForceFlag) {
+		// -f moves the branch if it already exists
+		if err = checkBranchRefProtection(ctx, dbData.Ddb, branchName); err != nil {
+			return err
+		}
+	}
 
 	err = actions.CreateBranchWithStartPt(ctx, dbData, branchName, startPt, apr.Contains(cli.ForceFlag), rsc)
 	if err != nil {
@@ -451,6 +671,9 @@ func copyABranch(ctx *sql.Context, dbData env.DbData, srcBr string, destBr strin
 		if err := branch_control.CanDeleteBranch(ctx, destBr); err != nil {
 			return err
 		}
+		if err := checkBranchRefProtection(ctx, dbData.Ddb, destBr); err != nil {
+			return err
+		}
 	}
 	err := actions.CopyBranchOnDB(ctx, dbData.Ddb, srcBr, destBr, force, rsc)
 	if err != nil {
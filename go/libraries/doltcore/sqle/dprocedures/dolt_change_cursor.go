@@ -0,0 +1,138 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/cdc"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// doltChangeCursor creates, advances and deletes the change cursors read with the dolt_changes table function. To
+// list change cursors, the dolt_change_cursors system table is used.
+func doltChangeCursor(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltChangeCursor(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltChangeCursor(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 1, err
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	apr, err := cli.CreateChangeCursorArgParser().Parse(args)
+	if err != nil {
+		return 1, err
+	}
+	if apr.NArg() < 2 {
+		return 1, fmt.Errorf("error: a subcommand and a change cursor name must be given")
+	} else if apr.NArg() > 3 {
+		return 1, fmt.Errorf("error: too many arguments")
+	}
+	name := apr.Arg(1)
+
+	meta, err := dSess.ChangeCursorCommitMeta()
+	if err != nil {
+		return 1, err
+	}
+
+	switch subcommand := apr.Arg(0); subcommand {
+	case "create":
+		branch, ok := apr.GetValue(cli.BranchParam)
+		if !ok {
+			headRef, err := dbData.Rsr.CWBHeadRef()
+			if err != nil {
+				return 1, err
+			}
+			branch = headRef.GetPath()
+		}
+		commit, err := resolveCursorCommit(ctx, dbData.Ddb, branch, apr.Args[2:])
+		if err != nil {
+			return 1, err
+		}
+		if err = cdc.CreateCursor(ctx, dbData.Ddb, name, branch, commit, meta); err != nil {
+			return 1, err
+		}
+	case "advance":
+		if apr.Contains(cli.BranchParam) {
+			return 1, fmt.Errorf("error: the branch of a change cursor can't be changed")
+		}
+		c, ok, err := dbData.Ddb.GetChangeCursor(ctx, name)
+		if err != nil {
+			return 1, err
+		} else if !ok {
+			return 1, fmt.Errorf("%w: %s", cdc.ErrCursorNotFound, name)
+		}
+		commit, err := resolveCursorCommit(ctx, dbData.Ddb, c.Branch, apr.Args[2:])
+		if err != nil {
+			return 1, err
+		}
+		if err = cdc.MoveCursor(ctx, dbData.Ddb, name, commit, meta); err != nil {
+			return 1, err
+		}
+	case "delete":
+		if apr.NArg() > 2 || apr.Contains(cli.BranchParam) {
+			return 1, fmt.Errorf("error: delete takes only the name of the change cursor")
+		}
+		if err = cdc.DeleteCursor(ctx, dbData.Ddb, name, meta); err != nil {
+			return 1, err
+		}
+	default:
+		return 1, fmt.Errorf("error: unknown subcommand '%s', expected one of create, advance or delete", subcommand)
+	}
+
+	return 0, nil
+}
+
+// resolveCursorCommit resolves the commit given in |args| relative to |branch|, or the head of |branch| if none is.
+func resolveCursorCommit(ctx *sql.Context, ddb *doltdb.DoltDB, branch string, args []string) (hash.Hash, error) {
+	spec := "HEAD"
+	if len(args) > 0 {
+		spec = args[0]
+	}
+	cs, err := doltdb.NewCommitSpec(spec)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	optCmt, err := ddb.Resolve(ctx, cs, ref.NewBranchRef(branch))
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return hash.Hash{}, doltdb.ErrGhostCommitEncountered
+	}
+	return cm.HashOf()
+}
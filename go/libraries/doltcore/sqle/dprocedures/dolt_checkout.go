@@ -17,6 +17,7 @@ package dprocedures
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -66,6 +67,8 @@ func doDoltCheckout(ctx *sql.Context, args []string) (statusCode int, successMes
 	// The --move flag is used internally by the `dolt checkout` CLI command. It is not intended for external use.
 	// It mimics the behavior of the `dolt checkout` command line, moving the working set into the new branch.
 	argParser.SupportsFlag(cli.MoveFlag, "m", "")
+	// The --ttl option makes the new branch ephemeral, so that a running sql-server deletes it once it expires.
+	argParser.SupportsString(cli.TTLParam, "", "duration", "")
 	apr, err := argParser.Parse(args)
 	if err != nil {
 		return 1, "", err
@@ -76,6 +79,11 @@ func doDoltCheckout(ctx *sql.Context, args []string) (statusCode int, successMes
 		return 1, "", err
 	}
 
+	ttl, err := parseEphemeralBranchTTL(ctx, apr, newBranch)
+	if err != nil {
+		return 1, "", err
+	}
+
 	branchOrTrack := newBranch != "" || apr.Contains(cli.TrackFlag)
 	if apr.Contains(cli.TrackFlag) && apr.NArg() > 0 {
 		return 1, "", errors.New("Improper usage. Too many arguments provided.")
@@ -108,9 +116,14 @@ func doDoltCheckout(ctx *sql.Context, args []string) (statusCode int, successMes
 		newBranch, upstream, err := checkoutNewBranch(ctx, currentDbName, dbData, apr, &rsc, updateHead)
 		if err != nil {
 			return 1, "", err
-		} else {
-			return 0, generateSuccessMessage(newBranch, upstream), nil
 		}
+		if ttl > 0 {
+			baseName, _ := dsess.SplitRevisionDbName(currentDbName)
+			if err = dSess.Provider().AddEphemeralBranch(ctx, baseName, newBranch, ttl); err != nil {
+				return 1, "", err
+			}
+		}
+		return 0, generateSuccessMessage(newBranch, upstream), nil
 	}
 
 	branchName := apr.Arg(0)
@@ -204,6 +217,26 @@ func doDoltCheckout(ctx *sql.Context, args []string) (statusCode int, successMes
 	return 0, successMessage, nil
 }
 
+// parseEphemeralBranchTTL returns the time to live given with --ttl for the ephemeral branch |newBranch|, or 0 if none
+// was given. It's an error to give one without creating a branch, or when ephemeral branches aren't available.
+func parseEphemeralBranchTTL(ctx *sql.Context, apr *argparser.ArgParseResults, newBranch string) (time.Duration, error) {
+	ttlStr, ok := apr.GetValue(cli.TTLParam)
+	if !ok {
+		return 0, nil
+	}
+	if newBranch == "" {
+		return 0, fmt.Errorf("error: --%s can only be used when creating a branch with -b or -B", cli.TTLParam)
+	}
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("error: invalid --%s '%s', expected a positive duration such as 30m or 1h", cli.TTLParam, ttlStr)
+	}
+	if !dsess.DSessFromSess(ctx.Session).Provider().EphemeralBranchesEnabled() {
+		return 0, dsess.ErrEphemeralBranchesNotRunning
+	}
+	return ttl, nil
+}
+
 // parseBranchArgs returns the name of the new branch and whether or not it should be created forcibly. This asserts
 // that the provided branch name may not be empty, so an empty string is returned where no -b or -B flag is provided.
 func parseBranchArgs(apr *argparser.ArgParseResults) (newBranch string, createBranchForcibly bool, err error) {
@@ -32,6 +32,7 @@ import (
 // doltClone is the stored procedure version for the CLI command `dolt clone`.
 func doltClone(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	ap := cli.CreateCloneArgParser()
+	ap.SupportsFlag(cli.AsyncFlag, "", "Return as soon as the clone has started and clone the database in the background. Progress is reported in the dolt_clone_status table.")
 	apr, err := ap.Parse(args)
 	if err != nil {
 		return nil, err
@@ -62,7 +63,11 @@ func doltClone(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 		depth = -1
 	}
 
-	err = sess.Provider().CloneDatabaseFromRemote(ctx, dir, branch, remoteName, remoteUrl, depth, remoteParms)
+	if apr.Contains(cli.AsyncFlag) {
+		err = sess.Provider().CloneDatabaseFromRemoteAsync(ctx, dir, branch, remoteName, remoteUrl, depth, remoteParms)
+	} else {
+		err = sess.Provider().CloneDatabaseFromRemote(ctx, dir, branch, remoteName, remoteUrl, depth, remoteParms)
+	}
 	if err != nil {
 		return nil, err
 	}
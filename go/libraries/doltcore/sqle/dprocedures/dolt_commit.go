@@ -23,9 +23,11 @@ import (
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/commitsign"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/store/datas"
 )
 
@@ -116,20 +118,30 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 
 	amend := apr.Contains(cli.AmendFlag)
 
+	metadata, err := cli.ParseCommitMetadata(apr)
+	if err != nil {
+		return "", false, err
+	}
+
 	msg, msgOk := apr.GetValue(cli.MessageArg)
-	if !msgOk {
-		if amend {
-			commit, err := dSess.GetHeadCommit(ctx, dbName)
-			if err != nil {
-				return "", false, err
-			}
-			commitMeta, err := commit.GetCommitMeta(ctx)
-			if err != nil {
-				return "", false, err
-			}
+	if !msgOk && !amend {
+		return "", false, fmt.Errorf("Must provide commit message.")
+	}
+	if amend && (!msgOk || metadata == nil) {
+		// the message and metadata of the commit being amended are kept unless new ones are given
+		commit, err := dSess.GetHeadCommit(ctx, dbName)
+		if err != nil {
+			return "", false, err
+		}
+		commitMeta, err := commit.GetCommitMeta(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		if !msgOk {
 			msg = commitMeta.Description
-		} else {
-			return "", false, fmt.Errorf("Must provide commit message.")
+		}
+		if metadata == nil {
+			metadata = commitMeta.Metadata
 		}
 	}
 
@@ -152,6 +164,12 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 		}
 	}
 
+	if !apr.Contains(cli.ForceFlag) {
+		if err = dSess.Provider().VerifyAssertions(ctx, dbName); err != nil {
+			return "", false, err
+		}
+	}
+
 	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, actions.CommitStagedProps{
 		Message:    msg,
 		Date:       t,
@@ -161,6 +179,7 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 		Force:      apr.Contains(cli.ForceFlag),
 		Name:       name,
 		Email:      email,
+		Metadata:   metadata,
 	})
 	if err != nil {
 		return "", false, err
@@ -173,6 +192,11 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 		return "", false, errors.New("nothing to commit")
 	}
 
+	pendingCommit.CommitOptions.Signer, err = commitSigner(ctx, apr)
+	if err != nil {
+		return "", false, err
+	}
+
 	newCommit, err := dSess.DoltCommit(ctx, dbName, dSess.GetTransaction(), pendingCommit)
 	if err != nil {
 		return "", false, err
@@ -186,6 +210,22 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 	return h.String(), false, nil
 }
 
+// commitSigner returns the signer for a commit, or nil if it shouldn't be signed. Commits are signed when asked to
+// with --gpg-sign, or when commit.gpgsign is set and they aren't made with --no-gpg-sign.
+func commitSigner(ctx *sql.Context, apr *argparser.ArgParseResults) (datas.CommitSigner, error) {
+	if apr.Contains(cli.NoGpgSignFlag) {
+		return nil, nil
+	}
+	cfg, err := commitsign.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !apr.Contains(cli.GpgSignFlag) && !cfg.SignByDefault {
+		return nil, nil
+	}
+	return cfg.Signer(ctx)
+}
+
 func getDoltArgs(ctx *sql.Context, row sql.Row, children []sql.Expression) ([]string, error) {
 	args := make([]string, len(children))
 	for i := range children {
@@ -15,9 +15,7 @@
 package dprocedures
 
 import (
-	"context"
 	"fmt"
-	"io"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -25,7 +23,6 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
-	"github.com/dolthub/dolt/go/store/hash"
 )
 
 func doltCountCommits(ctx *sql.Context, args ...string) (sql.RowIter, error) {
@@ -86,11 +83,6 @@ func countCommits(ctx *sql.Context, args ...string) (ahead uint64, behind uint64
 		return 0, 0, doltdb.ErrGhostCommitEncountered
 	}
 
-	fromHash, err := fromCommit.HashOf()
-	if err != nil {
-		return 0, 0, err
-	}
-
 	toSpec, err := doltdb.NewCommitSpec(toRef)
 	if err != nil {
 		return 0, 0, err
@@ -104,60 +96,5 @@ func countCommits(ctx *sql.Context, args ...string) (ahead uint64, behind uint64
 		return 0, 0, doltdb.ErrGhostCommitEncountered
 	}
 
-	toHash, err := toCommit.HashOf()
-	if err != nil {
-		return 0, 0, err
-	}
-
-	optCmt, err = doltdb.GetCommitAncestor(ctx, fromCommit, toCommit)
-	if err != nil {
-		return 0, 0, err
-	}
-	ancestor, ok := optCmt.ToCommit()
-	if !ok {
-		return 0, 0, doltdb.ErrGhostCommitEncountered
-	}
-
-	ancestorHash, err := ancestor.HashOf()
-	if err != nil {
-		return 0, 0, err
-	}
-
-	if fromHash != toHash {
-		behind, err = countCommitsInRange(ctx, ddb, toHash, ancestorHash)
-		if err != nil {
-			return 0, 0, err
-		}
-		ahead, err = countCommitsInRange(ctx, ddb, fromHash, ancestorHash)
-		if err != nil {
-			return 0, 0, err
-		}
-	}
-
-	return ahead, behind, nil
-}
-
-// countCommitsInRange returns the number of commits between the given starting point to trace back to the given target point.
-// The starting commit must be a descendant of the target commit. Target commit must be a common ancestor commit.
-func countCommitsInRange(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash, targetCommitHash hash.Hash) (uint64, error) {
-	itr, iErr := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{startCommitHash}, nil)
-	if iErr != nil {
-		return 0, iErr
-	}
-	count := 0
-	for {
-		nextHash, _, err := itr.Next(ctx)
-		if err == io.EOF {
-			return 0, fmt.Errorf("no match found to ancestor commit")
-		} else if err != nil {
-			return 0, err
-		}
-
-		if nextHash == targetCommitHash {
-			break
-		}
-		count += 1
-	}
-
-	return uint64(count), nil
+	return commitwalk.CountAheadBehind(ctx, ddb, fromCommit, toCommit)
 }
@@ -91,6 +91,28 @@ func doDoltFetch(ctx *sql.Context, args []string) (int, error) {
 	if err != nil {
 		return cmdFailure, fmt.Errorf("fetch failed: %w", err)
 	}
+
+	if apr.Contains(cli.TagsFlag) {
+		tmpDir, err := dbData.Rsw.TempTableFilesDir()
+		if err != nil {
+			return cmdFailure, err
+		}
+		err = actions.FetchAllTags(ctx, tmpDir, srcDB, dbData.Ddb, runProgFuncs, stopProgFuncs)
+		if err != nil {
+			return cmdFailure, fmt.Errorf("fetch failed: %w", err)
+		}
+	}
+
+	if apr.Contains(cli.NotesFlag) {
+		tmpDir, err := dbData.Rsw.TempTableFilesDir()
+		if err != nil {
+			return cmdFailure, err
+		}
+		err = actions.FetchAllNotes(ctx, tmpDir, srcDB, dbData.Ddb, runProgFuncs, stopProgFuncs)
+		if err != nil {
+			return cmdFailure, fmt.Errorf("fetch failed: %w", err)
+		}
+	}
 	return cmdSuccess, nil
 }
 
@@ -22,6 +22,7 @@ import (
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
@@ -81,6 +82,31 @@ func doDoltGC(ctx *sql.Context, args []string) (int, error) {
 		return cmdFailure, fmt.Errorf("Could not load database %s", dbName)
 	}
 
+	retentionDays, hasRetention := apr.GetInt(cli.PruneOlderThanFlag)
+	if hasRetention && apr.Contains(cli.ShallowFlag) {
+		return cmdFailure, fmt.Errorf("--%s cannot be used with --%s", cli.PruneOlderThanFlag, cli.ShallowFlag)
+	}
+	if !hasRetention {
+		if _, val, ok := sql.SystemVariables.GetGlobal(dsess.DoltGCRetentionDays); ok {
+			days, _, err := types.Int64.Convert(val)
+			if err != nil {
+				return cmdFailure, err
+			}
+			retentionDays = int(days.(int64))
+		}
+	}
+	if retentionDays < 0 {
+		return cmdFailure, fmt.Errorf("--%s must not be negative", cli.PruneOlderThanFlag)
+	}
+	var retainSince time.Time
+	if retentionDays > 0 {
+		retainSince = time.Now().Add(-time.Duration(retentionDays) * 24 * time.Hour)
+	}
+
+	if err = purgeExpiredDroppedDatabases(ctx); err != nil {
+		return cmdFailure, err
+	}
+
 	if apr.Contains(cli.ShallowFlag) {
 		err = ddb.ShallowGC(ctx)
 		if err != nil {
@@ -109,7 +135,7 @@ func doDoltGC(ctx *sql.Context, args []string) (int, error) {
 		// TODO: If we got a callback at the beginning and an
 		// (allowed-to-block) callback at the end, we could more
 		// gracefully tear things down.
-		err = ddb.GC(ctx, func() error {
+		err = ddb.GC(ctx, retainSince, func() error {
 			if origepoch != -1 {
 				// Here we need to sanity check role and epoch.
 				if _, role, ok := sql.SystemVariables.GetGlobal(dsess.DoltClusterRoleVariable); ok {
@@ -168,3 +194,23 @@ func doDoltGC(ctx *sql.Context, args []string) (int, error) {
 
 	return cmdSuccess, nil
 }
+
+// purgeExpiredDroppedDatabases permanently deletes the dropped databases which were dropped more than
+// dolt_dropped_database_retention_days days ago. Nothing is purged while it's 0.
+func purgeExpiredDroppedDatabases(ctx *sql.Context) error {
+	_, val, ok := sql.SystemVariables.GetGlobal(dsess.DoltDroppedDatabaseRetentionDays)
+	if !ok {
+		return nil
+	}
+	days, _, err := types.Int64.Convert(val)
+	if err != nil {
+		return err
+	}
+	if days.(int64) == 0 {
+		return nil
+	}
+
+	droppedBefore := time.Now().Add(-time.Duration(days.(int64)) * 24 * time.Hour)
+	_, err = dsess.DSessFromSess(ctx.Session).Provider().PurgeExpiredDroppedDatabases(ctx, droppedBefore)
+	return err
+}
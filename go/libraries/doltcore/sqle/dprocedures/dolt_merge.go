@@ -353,6 +353,10 @@ func executeFFMerge(ctx *sql.Context, dbName string, squash bool, ws *doltdb.Wor
 		if err != nil {
 			return nil, err
 		}
+		err = dsess.DSessFromSess(ctx.Session).CheckBranchProtection(ctx, dbName, true)
+		if err != nil {
+			return ws, err
+		}
 		err = dbData.Ddb.FastForward(ctx, headRef, cm2)
 		if err != nil {
 			return ws, err
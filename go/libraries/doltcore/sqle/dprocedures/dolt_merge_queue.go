@@ -0,0 +1,81 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltMergeQueue is the stored procedure which adds merges to the merge queue of a running sql-server and cancels
+// them. It returns the id of the merge. To see the state of the queue, the dolt_merge_queue system table is used.
+func doltMergeQueue(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	id, err := doDoltMergeQueue(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(id), nil
+}
+
+func doDoltMergeQueue(ctx *sql.Context, args []string) (int64, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 0, fmt.Errorf("Empty database name.")
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 0, err
+	}
+
+	apr, err := cli.CreateMergeQueueArgParser().Parse(args)
+	if err != nil {
+		return 0, err
+	}
+	if apr.NArg() != 2 {
+		return 0, fmt.Errorf("error: expected a subcommand and its argument, e.g. dolt_merge_queue('add', 'feature')")
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+
+	switch apr.Arg(0) {
+	case "add":
+		target, ok := apr.GetValue(cli.IntoParam)
+		if !ok {
+			headRef, err := dSess.CWBHeadRef(ctx, dbName)
+			if err != nil {
+				return 0, err
+			}
+			target = headRef.GetPath()
+		}
+		return dSess.Provider().EnqueueMerge(ctx, baseName, apr.Arg(1), target, apr.Contains(cli.RunTestsFlag))
+	case "cancel":
+		if apr.Contains(cli.IntoParam) || apr.Contains(cli.RunTestsFlag) {
+			return 0, fmt.Errorf("error: cancel takes no options")
+		}
+		id, err := strconv.ParseInt(apr.Arg(1), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error: invalid merge id '%s'", apr.Arg(1))
+		}
+		return id, dSess.Provider().CancelQueuedMerge(ctx, baseName, id)
+	default:
+		return 0, fmt.Errorf("error: unknown subcommand '%s', expected add or cancel", apr.Arg(0))
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltNotes is the stored procedure version for the CLI command `dolt notes`.
+func doltNotes(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltNotes(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+// doDoltNotes is used as sql dolt_notes command for only adding, appending to and removing notes, not listing them.
+// To read notes, the dolt_notes system table is used.
+func doDoltNotes(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 1, err
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	apr, err := cli.CreateNotesArgParser().Parse(args)
+	if err != nil {
+		return 1, err
+	}
+	if apr.NArg() == 0 {
+		return 1, fmt.Errorf("error: invalid argument, use 'dolt_notes' system table to list notes")
+	} else if apr.NArg() > 2 {
+		return 1, fmt.Errorf("error: too many arguments")
+	}
+
+	refName, _ := apr.GetValue(cli.NotesRefParam)
+	nr, err := actions.ParseNotesRef(refName)
+	if err != nil {
+		return 1, err
+	}
+
+	var name, email string
+	if authorStr, ok := apr.GetValue(cli.AuthorParam); ok {
+		name, email, err = cli.ParseAuthor(authorStr)
+		if err != nil {
+			return 1, err
+		}
+	} else {
+		name = dSess.Username()
+		email = dSess.Email()
+	}
+	props := actions.NotesProps{AuthorName: name, AuthorEmail: email}
+
+	commitSpec := "HEAD"
+	if apr.NArg() > 1 {
+		commitSpec = apr.Arg(1)
+	}
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return 1, err
+	}
+
+	msg, hasMsg := apr.GetValue(cli.MessageArg)
+	switch subcommand := apr.Arg(0); subcommand {
+	case "add", "append":
+		if !hasMsg {
+			return 1, fmt.Errorf("error: a note message must be given with -m")
+		}
+		if subcommand == "add" {
+			err = actions.AddNoteOnDB(ctx, dbData.Ddb, nr, commitSpec, msg, apr.Contains(cli.ForceFlag), props, headRef)
+		} else {
+			err = actions.AppendNoteOnDB(ctx, dbData.Ddb, nr, commitSpec, msg, props, headRef)
+		}
+	case "remove":
+		if hasMsg {
+			return 1, fmt.Errorf("error: remove and note message options are incompatible")
+		}
+		err = actions.RemoveNoteOnDB(ctx, dbData.Ddb, nr, commitSpec, props, headRef)
+	default:
+		return 1, fmt.Errorf("error: unknown subcommand '%s', expected one of add, append or remove", subcommand)
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
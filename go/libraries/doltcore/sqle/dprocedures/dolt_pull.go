@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -32,6 +33,8 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/store/datas/pull"
 )
 
@@ -96,6 +99,11 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, string, error) {
 		return noConflictsOrViolations, threeWayMerge, "", actions.ErrInvalidPullArgs
 	}
 
+	rebase, err := pullWithRebase(ctx, apr)
+	if err != nil {
+		return noConflictsOrViolations, threeWayMerge, "", err
+	}
+
 	var remoteName, remoteRefName string
 	if apr.NArg() == 1 {
 		remoteName = apr.Arg(0)
@@ -223,6 +231,21 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, string, error) {
 				return noConflictsOrViolations, threeWayMerge, "", ErrUncommittedChanges.New()
 			}
 
+			// Branches which haven't diverged are fast-forwarded or up to date, the same as with a merge
+			if rebase {
+				diverged, err := hasDiverged(ctx, sess, dbName, dbData.Ddb, remoteTrackRef)
+				if err != nil {
+					return noConflictsOrViolations, threeWayMerge, "", err
+				}
+				if diverged {
+					conflicts, message, err = rebaseOntoRemoteBranch(ctx, remoteTrackRef.String())
+					if err != nil {
+						return conflicts, threeWayMerge, "", err
+					}
+					continue
+				}
+			}
+
 			ws, _, conflicts, fastForward, message, err = performMerge(ctx, sess, ws, dbName, mergeSpec, apr.Contains(cli.NoCommitFlag), msg)
 			if err != nil && !errors.Is(doltdb.ErrUpToDate, err) {
 				return conflicts, fastForward, "", err
@@ -245,6 +268,90 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, string, error) {
 	return conflicts, fastForward, message, nil
 }
 
+// pullWithRebase returns whether to rebase the current branch onto the remote branch rather than merge it, which is
+// the case with --rebase, or with the pull.rebase config unless --no-rebase or an option for merges is given.
+func pullWithRebase(ctx *sql.Context, apr *argparser.ArgParseResults) (bool, error) {
+	mergeOnly := apr.ContainsAny(cli.SquashParam, cli.NoFFParam, cli.NoCommitFlag)
+	if apr.Contains(cli.RebaseFlag) {
+		if apr.Contains(cli.NoRebaseFlag) {
+			return false, fmt.Errorf("error: Flags '--%s' and '--%s' cannot be used together", cli.RebaseFlag, cli.NoRebaseFlag)
+		}
+		if mergeOnly {
+			return false, fmt.Errorf("error: --%s can't be used with --%s, --%s or --%s", cli.RebaseFlag, cli.SquashParam, cli.NoFFParam, cli.NoCommitFlag)
+		}
+		return true, nil
+	}
+	if apr.Contains(cli.NoRebaseFlag) || mergeOnly {
+		return false, nil
+	}
+
+	val := loadConfig(ctx).GetStringOrDefault(config.PullRebase, "false")
+	rebase, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %s", config.PullRebase, val)
+	}
+	return rebase, nil
+}
+
+// hasDiverged returns whether the current branch and the remote tracking branch |remoteTrackRef| each have commits
+// the other doesn't.
+func hasDiverged(ctx *sql.Context, sess *dsess.DoltSession, dbName string, ddb *doltdb.DoltDB, remoteTrackRef ref.DoltRef) (bool, error) {
+	headCommit, err := sess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return false, err
+	}
+	remoteCommit, err := ddb.ResolveCommitRef(ctx, remoteTrackRef)
+	if err != nil {
+		return false, err
+	}
+	optCmt, err := doltdb.GetCommitAncestor(ctx, headCommit, remoteCommit)
+	if errors.Is(err, doltdb.ErrNoCommonAncestor) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	ancestor, ok := optCmt.ToCommit()
+	if !ok {
+		return false, doltdb.ErrGhostCommitEncountered
+	}
+
+	ancestorHash, err := ancestor.HashOf()
+	if err != nil {
+		return false, err
+	}
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return false, err
+	}
+	remoteHash, err := remoteCommit.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return ancestorHash != headHash && ancestorHash != remoteHash, nil
+}
+
+// rebaseOntoRemoteBranch rebases the commits on the current branch which aren't on the remote tracking branch
+// |upstream| on top of it. Commits which become empty, because their changes are already on the remote branch, are
+// dropped. A merge conflict pauses the rebase, leaving the conflicts to be resolved on the branch used for rebasing
+// before the rebase is continued with dolt_rebase('--continue') or aborted with dolt_rebase('--abort').
+func rebaseOntoRemoteBranch(ctx *sql.Context, upstream string) (int, string, error) {
+	rebaseBranch, err := currentBranch(ctx)
+	if err != nil {
+		return noConflictsOrViolations, "", err
+	}
+	err = startRebase(ctx, upstream, doltdb.DropEmptyCommit, doltdb.KeepEmptyCommit)
+	if err != nil {
+		return noConflictsOrViolations, "", err
+	}
+	_, err = continueRebase(ctx, true)
+	if ErrRebasePaused.Is(err) {
+		return hasConflictsOrViolations, err.Error(), nil
+	} else if err != nil {
+		return noConflictsOrViolations, "", err
+	}
+	return noConflictsOrViolations, SuccessfulRebaseMessage + rebaseBranch, nil
+}
+
 // TODO: remove this as it does not do anything useful
 func pullerProgFunc(ctx context.Context, statsCh <-chan pull.Stats) {
 	for {
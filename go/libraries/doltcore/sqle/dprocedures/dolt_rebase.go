@@ -94,6 +94,12 @@ var ErrRebaseConflictWithAbortError = goerrors.NewKind(
 	"merge conflict detected while rebasing commit %s. " +
 		"attempted to abort rebase operation, but encountered error: %w")
 
+// ErrRebasePaused is used when a merge conflict is detected while rebasing a commit in a rebase which pauses on
+// conflicts, such as one started by dolt_pull('--rebase'), so that they can be resolved before continuing.
+var ErrRebasePaused = goerrors.NewKind(
+	"merge conflict detected while rebasing commit %s. " +
+		"resolve the conflicts and stage the resolved tables, then continue the rebase with --continue, or abort it with --abort")
+
 // SuccessfulRebaseMessage is used when a rebase finishes successfully. The branch that was rebased should be appended
 // to the end of the message.
 var SuccessfulRebaseMessage = "Successfully rebased and updated refs/heads/"
@@ -128,8 +134,10 @@ func doDoltRebase(ctx *sql.Context, args []string) (int, string, error) {
 		}
 
 	case apr.Contains(cli.ContinueFlag):
-		rebaseBranch, err := continueRebase(ctx)
-		if err != nil {
+		rebaseBranch, err := continueRebase(ctx, false)
+		if ErrRebasePaused.Is(err) {
+			return 1, err.Error(), nil
+		} else if err != nil {
 			return 1, "", err
 		} else {
 			return 0, SuccessfulRebaseMessage + rebaseBranch, nil
@@ -419,10 +427,11 @@ func abortRebase(ctx *sql.Context) error {
 	return doltSession.SwitchWorkingSet(ctx, ctx.GetCurrentDatabase(), wsRef)
 }
 
-func continueRebase(ctx *sql.Context) (string, error) {
-	// TODO: Eventually, when we allow interactive-rebases to be stopped and started (e.g. with the break action,
-	//       or for conflict resolution), we'll need to track what step we're at in the rebase plan.
-
+// continueRebase applies the steps of the rebase plan, and then updates the branch being rebased to the result. When
+// |pauseOnConflict| is true, a merge conflict while rebasing a commit pauses the rebase with ErrRebasePaused, leaving
+// the conflicts in the working set and the steps which haven't been applied yet in the rebase plan. Continuing a paused
+// rebase commits the staged resolution of the conflicts first, and pauses again at the next conflict.
+func continueRebase(ctx *sql.Context, pauseOnConflict bool) (string, error) {
 	// Validate that we are in an interactive rebase
 	doltSession := dsess.DSessFromSess(ctx.Session)
 	workingSet, err := doltSession.WorkingSet(ctx, ctx.GetCurrentDatabase())
@@ -447,16 +456,38 @@ func continueRebase(ctx *sql.Context) (string, error) {
 		return "", err
 	}
 
-	err = rebase.ValidateRebasePlan(ctx, rebasePlan)
-	if err != nil {
-		return "", err
+	steps := rebasePlan.Steps
+	if workingSet.MergeActive() {
+		// The rebase was paused by a conflict in the first step of the remaining plan, which may be a squash or fixup,
+		// so the plan isn't validated again
+		if len(steps) == 0 {
+			return "", fmt.Errorf("unable to continue rebase: no rebase plan step for the conflicts being resolved")
+		}
+		err = commitResolvedRebaseStep(ctx, steps[0], workingSet.RebaseState().CommitBecomesEmptyHandling())
+		if err != nil {
+			return "", err
+		}
+		steps = steps[1:]
+		pauseOnConflict = true
+	} else {
+		err = rebase.ValidateRebasePlan(ctx, rebasePlan)
+		if err != nil {
+			return "", err
+		}
 	}
 
-	for _, step := range rebasePlan.Steps {
+	for i, step := range steps {
 		err = processRebasePlanStep(ctx, &step,
 			workingSet.RebaseState().CommitBecomesEmptyHandling(),
-			workingSet.RebaseState().EmptyCommitHandling())
-		if err != nil {
+			workingSet.RebaseState().EmptyCommitHandling(),
+			pauseOnConflict)
+		if ErrRebasePaused.Is(err) {
+			saveErr := saveRemainingRebasePlan(ctx, rdb, steps[i:])
+			if saveErr != nil {
+				return "", saveErr
+			}
+			return "", err
+		} else if err != nil {
 			return "", err
 		}
 	}
@@ -512,7 +543,7 @@ func continueRebase(ctx *sql.Context) (string, error) {
 }
 
 func processRebasePlanStep(ctx *sql.Context, planStep *rebase.RebasePlanStep,
-	commitBecomesEmptyHandling doltdb.EmptyCommitHandling, emptyCommitHandling doltdb.EmptyCommitHandling) error {
+	commitBecomesEmptyHandling doltdb.EmptyCommitHandling, emptyCommitHandling doltdb.EmptyCommitHandling, pauseOnConflict bool) error {
 	// Make sure we have a transaction opened for the session
 	// NOTE: After our first call to cherry-pick, the tx is committed, so a new tx needs to be started
 	//       as we process additional rebase actions.
@@ -538,7 +569,7 @@ func processRebasePlanStep(ctx *sql.Context, planStep *rebase.RebasePlanStep,
 		if planStep.Action == rebase.RebaseActionReword {
 			options.CommitMessage = planStep.CommitMsg
 		}
-		return handleRebaseCherryPick(ctx, planStep.CommitHash, options)
+		return handleRebaseCherryPick(ctx, planStep.CommitHash, options, pauseOnConflict)
 
 	case rebase.RebaseActionSquash, rebase.RebaseActionFixup:
 		options.Amend = true
@@ -549,7 +580,7 @@ func processRebasePlanStep(ctx *sql.Context, planStep *rebase.RebasePlanStep,
 			}
 			options.CommitMessage = commitMessage
 		}
-		return handleRebaseCherryPick(ctx, planStep.CommitHash, options)
+		return handleRebaseCherryPick(ctx, planStep.CommitHash, options, pauseOnConflict)
 
 	default:
 		return fmt.Errorf("rebase action '%s' is not supported", planStep.Action)
@@ -557,19 +588,21 @@ func processRebasePlanStep(ctx *sql.Context, planStep *rebase.RebasePlanStep,
 }
 
 // handleRebaseCherryPick runs a cherry-pick for the specified |commitHash|, using the specified
-// cherry-pick |options| and checks the results for any errors or merge conflicts. The initial
-// version of rebase doesn't support conflict resolution, so if any conflicts are detected, the
-// rebase is aborted and an error is returned.
-func handleRebaseCherryPick(ctx *sql.Context, commitHash string, options cherry_pick.CherryPickOptions) error {
+// cherry-pick |options| and checks the results for any errors or merge conflicts. If data conflicts
+// or constraint violations are detected and |pauseOnConflict| is true, they're left in the working
+// set and ErrRebasePaused is returned. Otherwise, if any conflicts are detected, the rebase is
+// aborted and an error is returned.
+func handleRebaseCherryPick(ctx *sql.Context, commitHash string, options cherry_pick.CherryPickOptions, pauseOnConflict bool) error {
 	_, mergeResult, err := cherry_pick.CherryPick(ctx, commitHash, options)
 
 	var schemaConflict merge.SchemaConflict
 	isSchemaConflict := errors.As(err, &schemaConflict)
 
 	if (mergeResult != nil && mergeResult.HasMergeArtifacts()) || isSchemaConflict {
-		// TODO: rebase doesn't currently support conflict resolution, but ideally, when a conflict
-		//       is detected, the rebase would be paused and the user would resolve the conflict just
-		//       like any other conflict, and then call dolt_rebase --continue to keep going.
+		// Schema conflicts aren't recorded in the working set by cherry-pick, so they can't be resolved
+		if pauseOnConflict && !isSchemaConflict {
+			return ErrRebasePaused.New(commitHash)
+		}
 		abortErr := abortRebase(ctx)
 		if abortErr != nil {
 			return ErrRebaseConflictWithAbortError.New(commitHash, abortErr)
@@ -579,6 +612,107 @@ func handleRebaseCherryPick(ctx *sql.Context, commitHash string, options cherry_
 	return err
 }
 
+// saveRemainingRebasePlan replaces the rebase plan with |steps|, the steps of a paused rebase which haven't been
+// applied yet.
+func saveRemainingRebasePlan(ctx *sql.Context, rdb rebase.RebasePlanDatabase, steps []rebase.RebasePlanStep) error {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	roots, ok := doltSession.GetRoots(ctx, ctx.GetCurrentDatabase())
+	if !ok {
+		return fmt.Errorf("unable to get roots for database %s", ctx.GetCurrentDatabase())
+	}
+	newWorkingRoot, err := roots.Working.RemoveTables(ctx, true, false, doltdb.TableName{Name: doltdb.RebaseTableName})
+	if err != nil {
+		return err
+	}
+	err = doltSession.SetWorkingRoot(ctx, ctx.GetCurrentDatabase(), newWorkingRoot)
+	if err != nil {
+		return err
+	}
+	return rdb.SaveRebasePlan(ctx, &rebase.RebasePlan{Steps: steps})
+}
+
+// commitResolvedRebaseStep commits the staged resolution of the conflicts which paused a rebase while applying
+// |planStep|, the same way the step would have been committed without conflicts.
+func commitResolvedRebaseStep(ctx *sql.Context, planStep rebase.RebasePlanStep, commitBecomesEmptyHandling doltdb.EmptyCommitHandling) error {
+	doltSession := dsess.DSessFromSess(ctx.Session)
+	dbName := ctx.GetCurrentDatabase()
+	if doltSession.GetTransaction() == nil {
+		_, err := doltSession.StartTransaction(ctx, sql.ReadWrite)
+		if err != nil {
+			return err
+		}
+	}
+
+	roots, ok := doltSession.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("unable to get roots for database %s", dbName)
+	}
+	hasConflicts, err := doltdb.HasConflicts(ctx, roots.Working)
+	if err != nil {
+		return err
+	}
+	hasConstraintViolations, err := doltdb.HasConstraintViolations(ctx, roots.Working)
+	if err != nil {
+		return err
+	}
+	if hasConflicts || hasConstraintViolations {
+		return fmt.Errorf("unable to continue rebase: the conflicts from rebasing commit %s must be resolved first", planStep.CommitHash)
+	}
+
+	// Comparing the working root to the staged root finds any changes which haven't been staged
+	unstagedRoots := roots
+	unstagedRoots.Head = roots.Staged
+	onlyIgnoredTables, err := diff.WorkingSetContainsOnlyIgnoredTables(ctx, unstagedRoots)
+	if err != nil {
+		return err
+	}
+	if !onlyIgnoredTables {
+		return fmt.Errorf("unable to continue rebase: stage the resolved tables with dolt_add() first")
+	}
+
+	props := actions.CommitStagedProps{
+		Date:       ctx.QueryTime(),
+		Name:       ctx.Client().User,
+		Email:      fmt.Sprintf("%s@%s", ctx.Client().User, ctx.Client().Address),
+		Message:    planStep.CommitMsg,
+		AllowEmpty: commitBecomesEmptyHandling == doltdb.KeepEmptyCommit,
+	}
+	switch planStep.Action {
+	case rebase.RebaseActionSquash:
+		props.Amend = true
+		props.Message, err = squashCommitMessage(ctx, planStep.CommitHash)
+		if err != nil {
+			return err
+		}
+	case rebase.RebaseActionFixup:
+		props.Amend = true
+		headCommit, err := doltSession.GetHeadCommit(ctx, dbName)
+		if err != nil {
+			return err
+		}
+		headCommitMeta, err := headCommit.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		props.Message = headCommitMeta.Description
+	}
+
+	pendingCommit, err := doltSession.NewPendingCommit(ctx, dbName, roots, props)
+	if err != nil {
+		return err
+	}
+	if pendingCommit == nil {
+		// Resolving the conflicts left nothing to commit, so the commit is dropped
+		workingSet, err := doltSession.WorkingSet(ctx, dbName)
+		if err != nil {
+			return err
+		}
+		return doltSession.SetWorkingSet(ctx, dbName, workingSet.ClearMerge())
+	}
+	_, err = doltSession.DoltCommit(ctx, dbName, doltSession.GetTransaction(), pendingCommit)
+	return err
+}
+
 // squashCommitMessage looks up the commit at HEAD and the commit identified by |nextCommitHash| and squashes their two
 // commit messages together.
 func squashCommitMessage(ctx *sql.Context, nextCommitHash string) (string, error) {
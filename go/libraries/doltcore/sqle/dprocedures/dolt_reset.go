@@ -100,6 +100,9 @@ func doDoltReset(ctx *sql.Context, args []string) (int, error) {
 			if err != nil {
 				return 1, err
 			}
+			if err := checkResetBranchProtection(ctx, dSess, dbName, newHead); err != nil {
+				return 1, err
+			}
 			if err := dbData.Ddb.SetHeadToCommit(ctx, headRef, newHead); err != nil {
 				return 1, err
 			}
@@ -183,3 +186,26 @@ func doDoltReset(ctx *sql.Context, args []string) (int, error) {
 
 	return 0, nil
 }
+
+// checkResetBranchProtection returns an error if resetting the current branch of |dbName| to |newHead| is forbidden by
+// the dolt_branch_protection rules. Moving a protected branch to a different commit is never a merge.
+func checkResetBranchProtection(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, newHead *doltdb.Commit) error {
+	head, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	if head != nil {
+		headHash, err := head.HashOf()
+		if err != nil {
+			return err
+		}
+		newHash, err := newHead.HashOf()
+		if err != nil {
+			return err
+		}
+		if headHash == newHash {
+			return nil
+		}
+	}
+	return dSess.CheckBranchProtection(ctx, dbName, false)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
@@ -60,6 +61,8 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 	if apr.Contains(cli.DeleteFlag) {
 		if apr.Contains(cli.MessageArg) {
 			return 1, fmt.Errorf("delete and tag message options are incompatible")
+		} else if apr.Contains(cli.DateParam) {
+			return 1, fmt.Errorf("delete and tag date options are incompatible")
 		}
 		err = actions.DeleteTagsOnDB(ctx, dbData.Ddb, apr.Args...)
 		if err != nil {
@@ -91,6 +94,12 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 		TaggerEmail: email,
 		Description: msg,
 	}
+	if dateStr, ok := apr.GetValue(cli.DateParam); ok {
+		props.Date, err = dconfig.ParseDate(dateStr)
+		if err != nil {
+			return 1, err
+		}
+	}
 
 	tagName := apr.Arg(0)
 	startPoint := "head"
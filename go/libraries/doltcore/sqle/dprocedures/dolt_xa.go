@@ -0,0 +1,129 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const (
+	xaStart          = "start"
+	xaEnd            = "end"
+	xaPrepare        = "prepare"
+	xaCommit         = "commit"
+	xaCommitOnePhase = "commit one phase"
+	xaRollback       = "rollback"
+	xaRecover        = "recover"
+	xaRecoverConvert = "recover convert xid"
+)
+
+// doltXaSchema is the schema of the rows returned by XA RECOVER.
+var doltXaSchema = []*sql.Column{
+	{Name: "formatID", Type: types.Int64, Nullable: false},
+	{Name: "gtrid_length", Type: types.Int64, Nullable: false},
+	{Name: "bqual_length", Type: types.Int64, Nullable: false},
+	{Name: "data", Type: types.LongText, Nullable: false},
+}
+
+// doltXa is the stored procedure which implements the XA statements. It isn't meant to be called directly: the
+// statements XA START, XA END, XA PREPARE, XA COMMIT, XA ROLLBACK and XA RECOVER are rewritten as calls to it by the
+// parser. The first argument is the statement, and the remaining arguments are the parts of the XID.
+func doltXa(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("error: invalid number of arguments")
+	}
+	action := strings.ToLower(args[0])
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	switch action {
+	case xaRecover, xaRecoverConvert:
+		xids, err := sess.XaRecover(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return xaRecoverRows(xids, action == xaRecoverConvert), nil
+	}
+
+	xid, err := parseXid(args[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case xaStart:
+		err = sess.XaStart(ctx, xid)
+	case xaEnd:
+		err = sess.XaEnd(ctx, xid)
+	case xaPrepare:
+		err = sess.XaPrepare(ctx, xid)
+	case xaCommit:
+		err = sess.XaCommit(ctx, xid, false)
+	case xaCommitOnePhase:
+		err = sess.XaCommit(ctx, xid, true)
+	case xaRollback:
+		err = sess.XaRollback(ctx, xid)
+	default:
+		return nil, fmt.Errorf("error: unknown XA statement: %s", args[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), nil
+}
+
+// parseXid returns the XID made up of the gtrid, bqual and formatID given. Only the gtrid is required.
+func parseXid(args []string) (dsess.XID, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return dsess.XID{}, fmt.Errorf("error: an XID is made up of a gtrid and an optional bqual and formatID")
+	}
+
+	xid := dsess.XID{Gtrid: args[0], FormatID: 1}
+	if len(args) > 1 {
+		xid.Bqual = args[1]
+	}
+	if len(args) > 2 {
+		formatID, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return dsess.XID{}, fmt.Errorf("error: invalid XID formatID: %s", args[2])
+		}
+		xid.FormatID = formatID
+	}
+
+	if len(xid.Gtrid) == 0 || len(xid.Gtrid) > 64 || len(xid.Bqual) > 64 {
+		return dsess.XID{}, dsess.ErrXaNota
+	}
+	return xid, nil
+}
+
+func xaRecoverRows(xids []dsess.XID, convert bool) sql.RowIter {
+	rows := make([]sql.Row, len(xids))
+	for i, xid := range xids {
+		data := xid.Gtrid + xid.Bqual
+		if convert {
+			data = "0x" + hex.EncodeToString([]byte(data))
+		}
+		rows[i] = sql.NewRow(xid.FormatID, int64(len(xid.Gtrid)), int64(len(xid.Bqual)), data)
+	}
+	return sql.RowsToRowIter(rows...)
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const droppedDatabasePurgerThread = "dropped_database_purger"
+
+// droppedDatabasePurgeInterval is how often the dropped database purger looks for dropped databases which have
+// expired.
+const droppedDatabasePurgeInterval = time.Minute
+
+// StartDroppedDatabasePurger starts a background thread which permanently deletes the dropped databases that were
+// dropped more than dolt_dropped_database_retention_days days ago, so that they can no longer be restored with
+// dolt_undrop(). Dropped databases are kept until they're purged with dolt_purge_dropped_databases() while
+// dolt_dropped_database_retention_days is 0.
+func (p *DoltDatabaseProvider) StartDroppedDatabasePurger(ctxFactory func(context.Context) (*sql.Context, error), bThreads *sql.BackgroundThreads) error {
+	return bThreads.Add(droppedDatabasePurgerThread, func(ctx context.Context) {
+		ticker := time.NewTicker(droppedDatabasePurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			retention := droppedDatabaseRetention()
+			if retention == 0 {
+				continue
+			}
+
+			sqlCtx, err := ctxFactory(ctx)
+			if err != nil {
+				continue
+			}
+			purged, err := p.PurgeExpiredDroppedDatabases(sqlCtx, time.Now().Add(-retention))
+			for _, name := range purged {
+				sqlCtx.GetLogger().Infof("purged dropped database %s, which was dropped more than %s ago", name, retention)
+			}
+			if err != nil {
+				sqlCtx.GetLogger().Warnf("unable to purge expired dropped databases: %s", err.Error())
+			}
+		}
+	})
+}
+
+// droppedDatabaseRetention returns how long dropped databases are kept before they're purged, according to
+// dolt_dropped_database_retention_days. Returns 0 when dropped databases are kept until they're purged explicitly.
+func droppedDatabaseRetention() time.Duration {
+	_, val, ok := sql.SystemVariables.GetGlobal(dsess.DoltDroppedDatabaseRetentionDays)
+	if !ok {
+		return 0
+	}
+	days, _, err := types.Int64.Convert(val)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(days.(int64)) * 24 * time.Hour
+}
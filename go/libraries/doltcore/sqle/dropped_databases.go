@@ -16,13 +16,16 @@ package sqle
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/utils/errors"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 )
@@ -31,6 +34,10 @@ import (
 // dropped. The dolt_undrop() stored procedure is then able to restore them from this location.
 const droppedDatabaseDirectoryName = ".dolt_dropped_databases"
 
+// droppedAtFileName is the name of the file holding the time a database was dropped, which is written to the directory
+// of the database in the dropped database directory.
+const droppedAtFileName = ".dolt_dropped_at"
+
 // droppedDatabaseManager is responsible for dropping databases and "undropping", or restoring, dropped databases. It
 // is given a Filesys where all database directories can be found. When dropping a database, instead of deleting the
 // database directory, it will move it to a new ".dolt_dropped_databases" directory where databases can be restored.
@@ -96,7 +103,21 @@ func (dd *droppedDatabaseManager) DropDatabase(ctx *sql.Context, name string, dr
 		return err
 	}
 
-	return dd.fs.MoveDir(dropDbLoc, destinationDirectory)
+	if err := dd.fs.MoveDir(dropDbLoc, destinationDirectory); err != nil {
+		return err
+	}
+
+	// The .dolt directory of a root database is held in a directory named after the database
+	heldDirectory := destinationDirectory
+	if isRootDatabase {
+		heldDirectory = filepath.Dir(destinationDirectory)
+	}
+	droppedAt := []byte(time.Now().UTC().Format(time.RFC3339))
+	if err := dd.fs.WriteFile(filepath.Join(heldDirectory, droppedAtFileName), droppedAt, os.ModePerm); err != nil {
+		// The database has already been dropped, so this only affects when it's purged
+		ctx.GetLogger().Warnf("unable to record when database %s was dropped: %s", name, err.Error())
+	}
+	return nil
 }
 
 // UndropDatabase will restore the database named |name| by moving it from the dolt_dropped_database directory, back
@@ -109,6 +130,13 @@ func (dd *droppedDatabaseManager) UndropDatabase(ctx *sql.Context, name string)
 		return nil, "", err
 	}
 
+	droppedAtPath := filepath.Join(sourcePath, droppedAtFileName)
+	if exists, _ := dd.fs.Exists(droppedAtPath); exists {
+		if err = dd.fs.DeleteFile(droppedAtPath); err != nil {
+			return nil, "", err
+		}
+	}
+
 	err = dd.fs.MoveDir(sourcePath, destinationPath)
 	if err != nil {
 		return nil, "", err
@@ -151,6 +179,32 @@ func (dd *droppedDatabaseManager) PurgeAllDroppedDatabases(_ *sql.Context) error
 	return err
 }
 
+// PurgeExpiredDroppedDatabases permanently removes the dropped databases that were dropped before |droppedBefore|,
+// and returns their names.
+func (dd *droppedDatabaseManager) PurgeExpiredDroppedDatabases(ctx *sql.Context, droppedBefore time.Time) ([]string, error) {
+	// If the dropped database holding directory doesn't exist, then there's nothing to purge
+	if exists, _ := dd.fs.Exists(droppedDatabaseDirectoryName); !exists {
+		return nil, nil
+	}
+
+	droppedDbs, err := dd.DroppedDatabases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var purged []string
+	for _, db := range droppedDbs {
+		if !db.DroppedAt.Before(droppedBefore) {
+			continue
+		}
+		if err = dd.fs.Delete(filepath.Join(droppedDatabaseDirectoryName, db.Name), true); err != nil {
+			return purged, err
+		}
+		purged = append(purged, db.Name)
+	}
+	return purged, nil
+}
+
 // initializeDeletedDatabaseDirectory initializes the special directory Dolt uses to store dropped databases until
 // they are fully removed. If the directory is already created and set up correctly, then this method is a no-op.
 // If the directory doesn't exist yet, it will be created. If there are any problems initializing the directory, an
@@ -188,6 +242,43 @@ func (dd *droppedDatabaseManager) ListDroppedDatabases(_ *sql.Context) ([]string
 	return databaseNames, nil
 }
 
+// DroppedDatabases returns the dropped databases which can be restored, in order of name, along with the size of their
+// files and when they were dropped. Databases dropped before the time they were dropped was recorded are reported as
+// being dropped when their directory was last modified.
+func (dd *droppedDatabaseManager) DroppedDatabases(ctx *sql.Context) ([]dsess.DroppedDatabase, error) {
+	names, err := dd.ListDroppedDatabases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	droppedDbs := make([]dsess.DroppedDatabase, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(droppedDatabaseDirectoryName, name)
+
+		var size int64
+		err = dd.fs.Iter(path, true, func(_ string, fileSize int64, isDir bool) (stop bool) {
+			if !isDir {
+				size += fileSize
+			}
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		droppedAt, _ := dd.fs.LastModified(path)
+		if data, err := dd.fs.ReadFile(filepath.Join(path, droppedAtFileName)); err == nil {
+			if t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data))); err == nil {
+				droppedAt = t
+			}
+		}
+
+		droppedDbs = append(droppedDbs, dsess.DroppedDatabase{Name: name, Size: size, DroppedAt: droppedAt})
+	}
+	return droppedDbs, nil
+}
+
 // validateUndropDatabase validates that the database |name| is available to be "undropped" and that no existing
 // database is already being managed that has the same (case-insensitive) name. If any problems are encountered,
 // an error is returned.
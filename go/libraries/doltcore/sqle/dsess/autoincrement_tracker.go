@@ -45,10 +45,25 @@ var (
 type AutoIncrementTracker struct {
 	dbName    string
 	sequences *sync.Map // map[string]uint64
+	blocks    *sync.Map // map[autoIncrementBlockKey]autoIncrementBlock
 	mm        *mutexmap.MutexMap
 	lockMode  LockMode
 }
 
+// autoIncrementBlockKey identifies the block of auto increment values reserved for a table on one branch
+type autoIncrementBlockKey struct {
+	tableName  string
+	workingSet string
+}
+
+// autoIncrementBlock is a range of auto increment values reserved for a table on one branch when
+// dolt_auto_increment_block_size is set, so that rows inserted on different branches get values from different ranges.
+// Values are generated from |next| up to, but not including, |end|.
+type autoIncrementBlock struct {
+	next uint64
+	end  uint64
+}
+
 var _ globalstate.AutoIncrementTracker = &AutoIncrementTracker{}
 
 // NewAutoIncrementTracker returns a new autoincrement tracker for the roots given. All roots sets must be
@@ -59,6 +74,7 @@ func NewAutoIncrementTracker(ctx context.Context, dbName string, roots ...doltdb
 	ait := AutoIncrementTracker{
 		dbName:    dbName,
 		sequences: &sync.Map{},
+		blocks:    &sync.Map{},
 		mm:        mutexmap.NewMutexMap(),
 	}
 
@@ -114,8 +130,9 @@ func (a AutoIncrementTracker) Current(tableName string) uint64 {
 }
 
 // Next returns the next auto increment value for the table named using the provided value from an insert (which may
-// be null or 0, in which case it will be generated from the sequence).
-func (a AutoIncrementTracker) Next(tbl string, insertVal interface{}) (uint64, error) {
+// be null or 0, in which case it will be generated from the sequence). When dolt_auto_increment_block_size is set,
+// generated values come from a block of values reserved for the table on the branch of the working set |ws|.
+func (a AutoIncrementTracker) Next(tbl string, ws ref.WorkingSetRef, insertVal interface{}) (uint64, error) {
 	tbl = strings.ToLower(tbl)
 
 	given, err := CoerceAutoIncrementValue(insertVal)
@@ -129,11 +146,24 @@ func (a AutoIncrementTracker) Next(tbl string, insertVal interface{}) (uint64, e
 	}
 
 	curr := loadAutoIncValue(a.sequences, tbl)
+	blockSize := autoIncrementBlockSize()
+	key := autoIncrementBlockKey{tableName: tbl, workingSet: ws.String()}
 
 	if given == 0 {
 		// |given| is 0 or NULL
-		a.sequences.Store(tbl, curr+1)
-		return curr, nil
+		if blockSize == 0 {
+			a.sequences.Store(tbl, curr+1)
+			return curr, nil
+		}
+
+		block, ok := a.loadBlock(key)
+		if !ok || block.next >= block.end {
+			// reserve the next block from the sequence shared by every branch
+			block = autoIncrementBlock{next: curr, end: curr + blockSize}
+			a.sequences.Store(tbl, block.end)
+		}
+		a.blocks.Store(key, autoIncrementBlock{next: block.next + 1, end: block.end})
+		return block.next, nil
 	}
 
 	if given >= curr {
@@ -141,10 +171,47 @@ func (a AutoIncrementTracker) Next(tbl string, insertVal interface{}) (uint64, e
 		return given, nil
 	}
 
-	// |given| < curr
+	// |given| < curr, so it may be in the block reserved for this branch, which then continues after it
+	if block, ok := a.loadBlock(key); ok && given >= block.next && given < block.end {
+		a.blocks.Store(key, autoIncrementBlock{next: given + 1, end: block.end})
+	}
 	return given, nil
 }
 
+func (a AutoIncrementTracker) loadBlock(key autoIncrementBlockKey) (autoIncrementBlock, bool) {
+	block, ok := a.blocks.Load(key)
+	if !ok {
+		return autoIncrementBlock{}, false
+	}
+	return block.(autoIncrementBlock), true
+}
+
+// releaseBlocks discards the blocks reserved for the table named, on every branch when |ws| is nil, or otherwise on the
+// branch of |ws|. Generated values come from new blocks after that.
+func (a AutoIncrementTracker) releaseBlocks(tableName string, ws *ref.WorkingSetRef) {
+	a.blocks.Range(func(k, _ any) bool {
+		key := k.(autoIncrementBlockKey)
+		if key.tableName == tableName && (ws == nil || key.workingSet == ws.String()) {
+			a.blocks.Delete(k)
+		}
+		return true
+	})
+}
+
+// autoIncrementBlockSize returns the current value of dolt_auto_increment_block_size, which is 0 when auto increment
+// values aren't reserved in blocks.
+func autoIncrementBlockSize() uint64 {
+	_, val, ok := sql.SystemVariables.GetGlobal(DoltAutoIncrementBlockSize)
+	if !ok {
+		return 0
+	}
+	size, _, err := gmstypes.Uint64.Convert(val)
+	if err != nil {
+		return 0
+	}
+	return size.(uint64)
+}
+
 func (a AutoIncrementTracker) CoerceAutoIncrementValue(val interface{}) (uint64, error) {
 	return CoerceAutoIncrementValue(val)
 }
@@ -178,6 +245,9 @@ func (a AutoIncrementTracker) Set(ctx *sql.Context, tableName string, table *dol
 	release := a.mm.Lock(tableName)
 	defer release()
 
+	// an explicitly set value takes effect on this branch right away, rather than after its block runs out
+	a.releaseBlocks(tableName, &ws)
+
 	existing := loadAutoIncValue(a.sequences, tableName)
 	if newAutoIncVal > existing {
 		a.sequences.Store(tableName, newAutoIncVal)
@@ -386,6 +456,8 @@ func (a AutoIncrementTracker) DropTable(ctx *sql.Context, tableName string, wses
 	release := a.mm.Lock(tableName)
 	defer release()
 
+	a.releaseBlocks(tableName, nil)
+
 	newHighestValue := uint64(1)
 
 	// Get the new highest value from all tables in the working sets given
@@ -15,6 +15,8 @@
 package dsess
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -26,6 +28,10 @@ import (
 	"github.com/dolthub/dolt/go/libraries/utils/concurrentmap"
 )
 
+// ErrReadOnlyRevision is returned for changes to a database revision pinned to a tag or commit, such as `mydb/v1` or
+// `mydb/<commit hash>`. These revisions are immutable snapshots, which clients can query without seeing later changes.
+var ErrReadOnlyRevision = errors.New("database revisions pinned to a tag or commit are read-only; use a branch to make changes")
+
 // InitialDbState is the initial state of a database, as returned by SessionDatabase.InitialDBState. It is used to
 // establish the in memory state of the session for every new transaction.
 type InitialDbState struct {
@@ -150,6 +156,30 @@ func (bs *branchState) RevisionDbName() string {
 	return RevisionDbName(bs.dbState.dbName, bs.head)
 }
 
+// detachedHeadErr returns the error for a change to |dbName| when this branch state has no working set, which names
+// the database if it's a revision pinned to a tag or commit.
+func (bs *branchState) detachedHeadErr(dbName string) error {
+	switch bs.revisionType {
+	case RevisionTypeTag, RevisionTypeCommit:
+		return fmt.Errorf("cannot change %s: %w", dbName, ErrReadOnlyRevision)
+	default:
+		return doltdb.ErrOperationNotSupportedInDetachedHead
+	}
+}
+
+// savepointWorkingSet returns the state of this branch to record in a savepoint. Returns false if this branch state
+// has no working set, e.g. for a detached head, since it can't be changed.
+func (bs *branchState) savepointWorkingSet() (savepointWorkingSet, bool, error) {
+	if bs.workingSet == nil || bs.headCommit == nil {
+		return savepointWorkingSet{}, false, nil
+	}
+	headHash, err := bs.headCommit.HashOf()
+	if err != nil {
+		return savepointWorkingSet{}, false, err
+	}
+	return savepointWorkingSet{workingSet: bs.workingSet, headHash: headHash}, true, nil
+}
+
 func (bs *branchState) WorkingRoot() doltdb.RootValue {
 	return bs.roots().Working
 }
@@ -189,3 +219,20 @@ func (bs *branchState) roots() doltdb.Roots {
 		Staged:  bs.WorkingSet().StagedRoot(),
 	}
 }
+
+// checkBranchProtection returns an error wrapping doltdb.ErrBranchProtected if the dolt_branch_protection rules at the
+// head of this branch forbid the current user from changing it. |isMerge| is whether the change is a merge.
+func (bs *branchState) checkBranchProtection(ctx *sql.Context, isMerge bool) error {
+	if bs.revisionType != RevisionTypeBranch || bs.headCommit == nil {
+		return nil
+	}
+	headRoot, err := bs.headCommit.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	protections, err := doltdb.GetBranchProtections(ctx, headRoot)
+	if err != nil || len(protections) == 0 {
+		return err
+	}
+	return protections.CheckBranchProtection(bs.head, ctx.Session.Client().User, isMerge)
+}
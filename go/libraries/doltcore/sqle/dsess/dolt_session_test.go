@@ -17,6 +17,7 @@ package dsess
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	_ "github.com/dolthub/go-mysql-server/sql/variables"
@@ -267,6 +268,14 @@ func (e emptyRevisionDatabaseProvider) PurgeDroppedDatabases(ctx *sql.Context) e
 	return nil
 }
 
+func (e emptyRevisionDatabaseProvider) DroppedDatabases(ctx *sql.Context) ([]DroppedDatabase, error) {
+	return nil, nil
+}
+
+func (e emptyRevisionDatabaseProvider) PurgeExpiredDroppedDatabases(ctx *sql.Context, droppedBefore time.Time) ([]string, error) {
+	return nil, nil
+}
+
 func (e emptyRevisionDatabaseProvider) BaseDatabase(ctx *sql.Context, dbName string) (SqlDatabase, bool) {
 	return nil, false
 }
@@ -311,6 +320,48 @@ func (e emptyRevisionDatabaseProvider) CloneDatabaseFromRemote(ctx *sql.Context,
 	return nil
 }
 
+func (e emptyRevisionDatabaseProvider) CloneDatabaseFromRemoteAsync(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, depth int, remoteParams map[string]string) error {
+	return nil
+}
+
+func (e emptyRevisionDatabaseProvider) CloneStatuses() []CloneStatus {
+	return nil
+}
+
+func (e emptyRevisionDatabaseProvider) StorageHealth() []StorageHealth {
+	return nil
+}
+
+func (e emptyRevisionDatabaseProvider) EnqueueMerge(ctx *sql.Context, dbName, source, target string, runTests bool) (int64, error) {
+	return 0, nil
+}
+
+func (e emptyRevisionDatabaseProvider) CancelQueuedMerge(ctx *sql.Context, dbName string, id int64) error {
+	return nil
+}
+
+func (e emptyRevisionDatabaseProvider) MergeQueue(dbName string) []MergeQueueEntry {
+	return nil
+}
+
+func (e emptyRevisionDatabaseProvider) AddEphemeralBranch(ctx *sql.Context, dbName, branch string, ttl time.Duration) error {
+	return nil
+}
+
+func (e emptyRevisionDatabaseProvider) EphemeralBranchesEnabled() bool {
+	return false
+}
+
+func (e emptyRevisionDatabaseProvider) EndSessionBranches(sessionID uint32) {}
+
+func (e emptyRevisionDatabaseProvider) VerifyAssertions(ctx *sql.Context, dbName string) error {
+	return nil
+}
+
+func (e emptyRevisionDatabaseProvider) UserHasPrivileges(ctx *sql.Context, operations ...sql.PrivilegedOperation) bool {
+	return true
+}
+
 func (e emptyRevisionDatabaseProvider) CreateDatabase(ctx *sql.Context, dbName string) error {
 	return nil
 }
@@ -318,3 +369,52 @@ func (e emptyRevisionDatabaseProvider) CreateDatabase(ctx *sql.Context, dbName s
 func (e emptyRevisionDatabaseProvider) RevisionDbState(_ *sql.Context, revDB string) (InitialDbState, error) {
 	return InitialDbState{}, sql.ErrDatabaseNotFound.New(revDB)
 }
+
+func TestReadOnlyChecks(t *testing.T) {
+	tests := []struct {
+		Name            string
+		ReadOnlyTx      bool
+		ReadOnlySession bool
+	}{
+		{
+			Name: "read write transaction",
+		},
+		{
+			Name:       "read only transaction",
+			ReadOnlyTx: true,
+		},
+		{
+			Name:            "read only session",
+			ReadOnlySession: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			sess := DefaultSession(emptyDatabaseProvider(), nil)
+			if tt.ReadOnlySession {
+				sess.SetReadOnly()
+			}
+			ctx := sql.NewContext(context.Background(), sql.WithSession(sess))
+			if tt.ReadOnlyTx {
+				ctx.SetTransaction(&DoltTransaction{tCharacteristic: sql.ReadOnly})
+			}
+
+			if tt.ReadOnlyTx || tt.ReadOnlySession {
+				assert.ErrorIs(t, CheckWritableTransaction(ctx), ErrReadOnlyTransaction)
+			} else {
+				assert.NoError(t, CheckWritableTransaction(ctx))
+			}
+
+			// locking reads are allowed in READ ONLY transactions, like in MySQL, but not in read-only sessions
+			var locker *RowLocker
+			if tt.ReadOnlySession {
+				assert.ErrorIs(t, CheckReadOnlySession(ctx), ErrReadOnlyTransaction)
+				assert.ErrorIs(t, locker.LockRowsForRead(ctx, RowLockExclusive, nil), ErrReadOnlyTransaction)
+			} else {
+				assert.NoError(t, CheckReadOnlySession(ctx))
+				assert.NoError(t, locker.LockRowsForRead(ctx, RowLockExclusive, nil))
+			}
+		})
+	}
+}
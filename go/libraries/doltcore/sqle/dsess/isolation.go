@@ -0,0 +1,273 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// IsolationLevel is the isolation level of a transaction, as set with SET TRANSACTION ISOLATION LEVEL or the
+// @@transaction_isolation system variable.
+type IsolationLevel string
+
+const (
+	// IsolationLevelRepeatableRead is the default isolation level. Every statement in the transaction reads from a
+	// snapshot taken when the transaction began, and on commit the transaction's changes are merged with any changes
+	// committed concurrently. The commit fails only if the merge has conflicts.
+	IsolationLevelRepeatableRead IsolationLevel = "REPEATABLE-READ"
+	// IsolationLevelSerializable reads from a snapshot like IsolationLevelRepeatableRead, but the transaction fails
+	// to commit if a transaction which committed after it began wrote a row it also wrote (first committer wins), or
+	// changed a table it read.
+	IsolationLevelSerializable IsolationLevel = "SERIALIZABLE"
+	// IsolationLevelReadCommitted is accepted because many drivers and ORMs set it when they connect, but Dolt doesn't
+	// begin a new snapshot for every statement, so transactions at READ COMMITTED run as IsolationLevelRepeatableRead,
+	// which is a stronger guarantee. Setting it warns that this is the case.
+	IsolationLevelReadCommitted IsolationLevel = "READ-COMMITTED"
+)
+
+// SupportedIsolationLevels are the values @@transaction_isolation may be set to. Dolt can't read uncommitted data, so it
+// rejects READ UNCOMMITTED rather than silently providing a different isolation level.
+var SupportedIsolationLevels = []string{string(IsolationLevelReadCommitted), string(IsolationLevelRepeatableRead), string(IsolationLevelSerializable)}
+
+// warnIfReadCommitted warns that transactions will run at REPEATABLE READ if |key|, which was just set, is an isolation
+// level system variable set to READ COMMITTED.
+func (d *DoltSession) warnIfReadCommitted(ctx *sql.Context, key string) error {
+	key = strings.ToLower(key)
+	if key != "transaction_isolation" && key != "tx_isolation" {
+		return nil
+	}
+	val, err := d.Session.GetSessionVariable(ctx, key)
+	if err != nil {
+		return err
+	}
+	if s, ok := val.(string); ok && strings.EqualFold(s, string(IsolationLevelReadCommitted)) {
+		ctx.Session.Warn(&sql.Warning{
+			Level:   "Warning",
+			Code:    1105,
+			Message: "READ-COMMITTED isn't supported, transactions will run at REPEATABLE-READ",
+		})
+	}
+	return nil
+}
+
+var ErrSerializationFailure = errors.New("this transaction read or wrote data changed by a transaction committed since it began, and can't be serialized")
+
+// sessionIsolationLevel returns the isolation level for a transaction beginning in the session given
+func sessionIsolationLevel(ctx *sql.Context) IsolationLevel {
+	if ctx == nil || ctx.Session == nil {
+		return IsolationLevelRepeatableRead
+	}
+	val, err := ctx.GetSessionVariable(ctx, "transaction_isolation")
+	if err != nil {
+		return IsolationLevelRepeatableRead
+	}
+	if s, ok := val.(string); ok && strings.EqualFold(s, string(IsolationLevelSerializable)) {
+		return IsolationLevelSerializable
+	}
+	return IsolationLevelRepeatableRead
+}
+
+// tableReadSet is the set of tables read by a transaction, keyed by revision qualified database name. Every read of a
+// table is treated as a predicate covering the whole table.
+type tableReadSet struct {
+	mu     sync.Mutex
+	tables map[string]map[string]struct{}
+}
+
+func newTableReadSet() *tableReadSet {
+	return &tableReadSet{tables: make(map[string]map[string]struct{})}
+}
+
+func (r *tableReadSet) add(revisionDbName, tableName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	revisionDbName = strings.ToLower(revisionDbName)
+	tables, ok := r.tables[revisionDbName]
+	if !ok {
+		tables = make(map[string]struct{})
+		r.tables[revisionDbName] = tables
+	}
+	tables[strings.ToLower(tableName)] = struct{}{}
+}
+
+func (r *tableReadSet) contains(revisionDbName, tableName string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.tables[strings.ToLower(revisionDbName)][strings.ToLower(tableName)]
+	return ok
+}
+
+// RecordTableRead records that the current transaction read the table named in the database given, which may or may
+// not be revision qualified. Only SERIALIZABLE transactions keep track of the tables they read.
+func RecordTableRead(ctx *sql.Context, dbName, tableName string) error {
+	tx, ok := ctx.GetTransaction().(*DoltTransaction)
+	if !ok || tx.isolationLevel != IsolationLevelSerializable {
+		return nil
+	}
+
+	sess, ok := ctx.Session.(*DoltSession)
+	if !ok {
+		return nil
+	}
+	branchState, ok, err := sess.lookupDbState(ctx, dbName)
+	if err != nil || !ok {
+		return err
+	}
+
+	tx.reads.add(branchState.RevisionDbName(), tableName)
+	return nil
+}
+
+// checkSerializable returns an error if this SERIALIZABLE transaction, which changed the branch named from
+// |startState| to |workingSet|, can't be committed because of the changes made to the branch by other transactions
+// since it began, which resulted in |existingWs|. The transaction is rolled back if so.
+func (tx *DoltTransaction) checkSerializable(
+	ctx *sql.Context,
+	revisionDbName string,
+	startState *doltdb.WorkingSet,
+	existingWs *doltdb.WorkingSet,
+	workingSet *doltdb.WorkingSet,
+) error {
+	conflict, err := tx.conflictsWithConcurrentChanges(ctx, revisionDbName, startState.WorkingRoot(), existingWs.WorkingRoot(), workingSet.WorkingRoot())
+	if err != nil {
+		return err
+	}
+	if !conflict {
+		return nil
+	}
+
+	if rollbackErr := tx.rollback(ctx); rollbackErr != nil {
+		return rollbackErr
+	}
+	return sql.ErrLockDeadlock.New(ErrSerializationFailure.Error())
+}
+
+// conflictsWithConcurrentChanges returns whether the changes from |startRoot| to |ourRoot| made by this transaction
+// conflict with the changes from |startRoot| to |theirRoot| committed by other transactions. They conflict if this
+// transaction read any table they changed, or if both changed the same row or the schema of the same table.
+func (tx *DoltTransaction) conflictsWithConcurrentChanges(ctx context.Context, revisionDbName string, startRoot, theirRoot, ourRoot doltdb.RootValue) (bool, error) {
+	theirDeltas, err := diff.GetTableDeltas(ctx, startRoot, theirRoot)
+	if err != nil {
+		return false, err
+	}
+	if len(theirDeltas) == 0 {
+		return false, nil
+	}
+
+	for _, delta := range theirDeltas {
+		if tx.reads.contains(revisionDbName, delta.FromName.Name) || tx.reads.contains(revisionDbName, delta.ToName.Name) {
+			return true, nil
+		}
+	}
+
+	ourDeltas, err := diff.GetTableDeltas(ctx, startRoot, ourRoot)
+	if err != nil {
+		return false, err
+	}
+	ours := make(map[string]diff.TableDelta, len(ourDeltas))
+	for _, delta := range ourDeltas {
+		for _, name := range []string{delta.FromName.Name, delta.ToName.Name} {
+			if name != "" {
+				ours[strings.ToLower(name)] = delta
+			}
+		}
+	}
+
+	for _, theirs := range theirDeltas {
+		our, ok := ours[strings.ToLower(theirs.FromName.Name)]
+		if !ok {
+			our, ok = ours[strings.ToLower(theirs.ToName.Name)]
+		}
+		if !ok {
+			continue
+		}
+
+		conflict, err := tableDeltasConflict(ctx, our, theirs)
+		if err != nil || conflict {
+			return conflict, err
+		}
+	}
+
+	return false, nil
+}
+
+// tableDeltasConflict returns whether two changes to the same table made by different transactions conflict: whether
+// either of them created, dropped or renamed it or changed its schema, or both changed the same row.
+func tableDeltasConflict(ctx context.Context, ours, theirs diff.TableDelta) (bool, error) {
+	for _, delta := range []diff.TableDelta{ours, theirs} {
+		if delta.IsAdd() || delta.IsDrop() || delta.IsRename() {
+			return true, nil
+		}
+		schemaChanged, err := delta.HasSchemaChanged(ctx)
+		if err != nil || schemaChanged {
+			return schemaChanged, err
+		}
+	}
+
+	if !types.IsFormat_DOLT(ours.Format()) {
+		// Rows can only be compared in the current storage format, so any concurrent change to the table conflicts
+		return true, nil
+	}
+
+	theirKeys := make(map[string]struct{})
+	err := diffRowKeys(ctx, theirs, func(key tree.Item) error {
+		theirKeys[string(key)] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	err = diffRowKeys(ctx, ours, func(key tree.Item) error {
+		if _, ok := theirKeys[string(key)]; ok {
+			return errRowConflict
+		}
+		return nil
+	})
+	if err == errRowConflict {
+		return true, nil
+	}
+	return false, err
+}
+
+var errRowConflict = errors.New("row changed by both transactions")
+
+// diffRowKeys calls |cb| with the key of every row changed in the table delta given
+func diffRowKeys(ctx context.Context, delta diff.TableDelta, cb func(key tree.Item) error) error {
+	from, to, err := delta.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	err = prolly.DiffMaps(ctx, durable.ProllyMapFromIndex(from), durable.ProllyMapFromIndex(to), false, func(_ context.Context, diff tree.Diff) error {
+		return cb(diff.Key)
+	})
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// The stages of a running query reported in the process list. Names match MySQL's where it has an equivalent stage.
+const (
+	QueryStageExecuting    = "executing"
+	QueryStageRowLockWait  = "Waiting for row lock"
+	QueryStageUserLockWait = "User lock"
+	QueryStageCommitting   = "waiting for handler commit"
+)
+
+// QueryProgressTracker is implemented by process lists which report more about the progress of the queries they run
+// than the per-table progress in sql.Process.
+type QueryProgressTracker interface {
+	// SetQueryStage records that the query with the process id given entered |stage|.
+	SetQueryStage(pid uint64, stage string)
+	// AddRowsExamined adds |rows| to the number of rows read by the query with the process id given.
+	AddRowsExamined(pid uint64, rows uint64)
+}
+
+// SetQueryStage records that the query running in |ctx| entered |stage|, if its process list tracks stages.
+func SetQueryStage(ctx *sql.Context, stage string) {
+	if t, ok := ctx.ProcessList.(QueryProgressTracker); ok {
+		t.SetQueryStage(ctx.Pid(), stage)
+	}
+}
+
+// AddRowsExamined adds |rows| to the number of rows read by the query running in |ctx|, if its process list tracks
+// them. Rows read through tables wrapped by the engine's process tracking are already counted; this is for execution
+// paths which read storage directly.
+func AddRowsExamined(ctx *sql.Context, rows uint64) {
+	if t, ok := ctx.ProcessList.(QueryProgressTracker); ok && rows > 0 {
+		t.AddRowsExamined(ctx.Pid(), rows)
+	}
+}
@@ -0,0 +1,736 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// InnodbLockWaitTimeout is the system variable holding the number of seconds a transaction waits for a row lock held
+// by another transaction before giving up.
+const InnodbLockWaitTimeout = "innodb_lock_wait_timeout"
+
+const defaultLockWaitTimeout = 50 * time.Second
+
+// RowLockMode is the kind of lock taken on the rows read by a locking read.
+type RowLockMode uint8
+
+const (
+	// RowLockNone means rows aren't locked when they're read.
+	RowLockNone RowLockMode = iota
+	// RowLockShared is taken by SELECT ... LOCK IN SHARE MODE. Any number of transactions may hold a shared lock on a
+	// row, but none of them may write it until the others release their locks.
+	RowLockShared
+	// RowLockExclusive is taken by SELECT ... FOR UPDATE, and on the rows written by INSERT, UPDATE, DELETE and
+	// REPLACE. Only one transaction may hold an exclusive lock on a row.
+	RowLockExclusive
+)
+
+// newLockWaitTimeoutError returns the error for a row lock wait which exceeded @@innodb_lock_wait_timeout. As in
+// MySQL, only the statement fails: the transaction isn't rolled back.
+func newLockWaitTimeoutError() error {
+	return mysql.NewSQLError(mysql.ERLockWaitTimeout, mysql.SSUnknownSQLState, "Lock wait timeout exceeded; try restarting transaction")
+}
+
+// erLockNowait is the MySQL error code of a locking read with NOWAIT which found a row locked by another transaction.
+const erLockNowait = 3572
+
+// ErrSkipLockedNotSupported is returned by locking reads with SKIP LOCKED. Rows are locked before the statement reads
+// them, so rows locked by other transactions can't be left out of its results.
+var ErrSkipLockedNotSupported = errors.New("SKIP LOCKED is not supported by locking reads")
+
+// newLockNowaitError returns the error for a locking read with NOWAIT which found a row locked by another transaction.
+func newLockNowaitError() error {
+	return mysql.NewSQLError(erLockNowait, mysql.SSUnknownSQLState, "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.")
+}
+
+const (
+	deadlockMsg     = "deadlock found when trying to get lock"
+	lockedChangeMsg = "rows this transaction waited to lock were changed by the transaction holding the lock"
+)
+
+// rowLockKey identifies a single row. Rows are identified by a hash of their primary key, or of the entire row for
+// keyless tables. Two rows with the same hash share a lock, which can cause unnecessary waits but never lets two
+// transactions lock the same row.
+type rowLockKey struct {
+	table rowLockTable
+	hash  uint64
+}
+
+// rowLockTable identifies a table on a branch, by the database it's stored in and its lower-cased revision qualified
+// database name and table name. Different servers in the same process may serve databases with the same name.
+type rowLockTable struct {
+	ddb            *doltdb.DoltDB
+	revisionDbName string
+	tableName      string
+}
+
+type rowLock struct {
+	holders   map[*DoltSession]struct{}
+	exclusive bool
+}
+
+// rowLockManager holds the row locks taken by every session on this server. Locks are owned by sessions, and are
+// released when the session's transaction ends. A session only has one transaction at a time.
+type rowLockManager struct {
+	mu    sync.Mutex
+	locks map[rowLockKey]*rowLock
+	// held is the keys of the locks held by each session
+	held map[*DoltSession][]rowLockKey
+	// tables is the number of locks held on each table, by any session
+	tables map[rowLockTable]int
+	// waitsFor is the sessions each waiting session is waiting for, used to detect deadlocks
+	waitsFor map[*DoltSession][]*DoltSession
+	// released is closed, and replaced, whenever locks are released
+	released chan struct{}
+	// total is the number of locks held, which can be read without holding |mu|
+	total atomic.Int64
+}
+
+var rowLocks = newRowLockManager()
+
+func newRowLockManager() *rowLockManager {
+	return &rowLockManager{
+		locks:    make(map[rowLockKey]*rowLock),
+		held:     make(map[*DoltSession][]rowLockKey),
+		tables:   make(map[rowLockTable]int),
+		waitsFor: make(map[*DoltSession][]*DoltSession),
+		released: make(chan struct{}),
+	}
+}
+
+// acquire takes a lock on the row given for |sess|, waiting for other sessions holding conflicting locks to release
+// them, unless |nowait| is set. Returns whether it had to wait.
+func (m *rowLockManager) acquire(ctx *sql.Context, sess *DoltSession, key rowLockKey, mode RowLockMode, nowait bool) (bool, error) {
+	var timeout <-chan time.Time
+	waited := false
+
+	m.mu.Lock()
+	for {
+		holders := m.conflicting(sess, key, mode)
+		if len(holders) == 0 {
+			m.grant(sess, key, mode)
+			delete(m.waitsFor, sess)
+			m.mu.Unlock()
+			return waited, nil
+		}
+
+		if nowait {
+			m.mu.Unlock()
+			return waited, newLockNowaitError()
+		}
+
+		m.waitsFor[sess] = holders
+		if m.deadlocked(sess) {
+			delete(m.waitsFor, sess)
+			m.mu.Unlock()
+			return waited, sql.ErrLockDeadlock.New(deadlockMsg)
+		}
+
+		released := m.released
+		m.mu.Unlock()
+
+		if timeout == nil {
+			timer := time.NewTimer(lockWaitTimeout(ctx))
+			defer timer.Stop()
+			timeout = timer.C
+			SetQueryStage(ctx, QueryStageRowLockWait)
+			defer SetQueryStage(ctx, QueryStageExecuting)
+		}
+		waited = true
+
+		select {
+		case <-released:
+		case <-timeout:
+			m.stopWaiting(sess)
+			return waited, newLockWaitTimeoutError()
+		case <-ctx.Done():
+			m.stopWaiting(sess)
+			return waited, ctx.Err()
+		}
+
+		m.mu.Lock()
+	}
+}
+
+func (m *rowLockManager) stopWaiting(sess *DoltSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.waitsFor, sess)
+}
+
+// conflicting returns the sessions holding locks on the row given which prevent |sess| from locking it in the mode
+// given. Must be called with |mu| held.
+func (m *rowLockManager) conflicting(sess *DoltSession, key rowLockKey, mode RowLockMode) []*DoltSession {
+	lock, ok := m.locks[key]
+	if !ok || (!lock.exclusive && mode == RowLockShared) {
+		return nil
+	}
+
+	var holders []*DoltSession
+	for holder := range lock.holders {
+		if holder != sess {
+			holders = append(holders, holder)
+		}
+	}
+	return holders
+}
+
+// grant records that |sess| holds a lock on the row given. Must be called with |mu| held.
+func (m *rowLockManager) grant(sess *DoltSession, key rowLockKey, mode RowLockMode) {
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &rowLock{holders: make(map[*DoltSession]struct{})}
+		m.locks[key] = lock
+		m.tables[key.table]++
+		m.total.Add(1)
+	}
+	if _, ok := lock.holders[sess]; !ok {
+		lock.holders[sess] = struct{}{}
+		m.held[sess] = append(m.held[sess], key)
+	}
+	if mode == RowLockExclusive {
+		lock.exclusive = true
+	}
+}
+
+// deadlocked returns whether |sess| is waiting, directly or indirectly, for a session which is waiting for it. Must
+// be called with |mu| held.
+func (m *rowLockManager) deadlocked(sess *DoltSession) bool {
+	visited := make(map[*DoltSession]struct{})
+	toVisit := append([]*DoltSession(nil), m.waitsFor[sess]...)
+	for len(toVisit) > 0 {
+		next := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		if next == sess {
+			return true
+		}
+		if _, ok := visited[next]; ok {
+			continue
+		}
+		visited[next] = struct{}{}
+		toVisit = append(toVisit, m.waitsFor[next]...)
+	}
+	return false
+}
+
+// releaseAll releases every lock held by |sess|.
+func (m *rowLockManager) releaseAll(sess *DoltSession) {
+	if m.total.Load() == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.releaseLocked(sess)
+}
+
+// releaseLocked is releaseAll with |mu| held.
+func (m *rowLockManager) releaseLocked(sess *DoltSession) {
+	keys, ok := m.held[sess]
+	if !ok {
+		return
+	}
+	for _, key := range keys {
+		lock := m.locks[key]
+		delete(lock.holders, sess)
+		if len(lock.holders) > 0 {
+			continue
+		}
+		delete(m.locks, key)
+		m.total.Add(-1)
+		if m.tables[key.table]--; m.tables[key.table] == 0 {
+			delete(m.tables, key.table)
+		}
+	}
+	delete(m.held, sess)
+
+	close(m.released)
+	m.released = make(chan struct{})
+}
+
+// tableLocked returns whether any session holds a lock on a row of the table given.
+func (m *rowLockManager) tableLocked(table rowLockTable) bool {
+	if m.total.Load() == 0 {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tables[table] > 0
+}
+
+// lockWaitTimeout returns the time to wait for a row lock, from @@innodb_lock_wait_timeout.
+func lockWaitTimeout(ctx *sql.Context) time.Duration {
+	val, err := ctx.GetSessionVariable(ctx, InnodbLockWaitTimeout)
+	if err != nil {
+		return defaultLockWaitTimeout
+	}
+	switch v := val.(type) {
+	case int64:
+		return time.Duration(v) * time.Second
+	case int32:
+		return time.Duration(v) * time.Second
+	case int:
+		return time.Duration(v) * time.Second
+	case uint64:
+		return time.Duration(v) * time.Second
+	default:
+		return defaultLockWaitTimeout
+	}
+}
+
+// lockingRead is the row lock mode of a query, cached in the session
+type lockingRead struct {
+	query string
+	mode  RowLockMode
+	// isSelect is true if the query is a SELECT statement, which can't write to any tables
+	isSelect bool
+	// nowait is true if the query fails rather than waiting for rows locked by other transactions
+	nowait bool
+	// skipLocked is true if the query asks to leave out rows locked by other transactions
+	skipLocked bool
+}
+
+// LockingReadMode returns the mode in which the current query locks the rows it reads: RowLockExclusive for
+// SELECT ... FOR UPDATE, RowLockShared for SELECT ... LOCK IN SHARE MODE, and RowLockNone for any other query.
+func LockingReadMode(ctx *sql.Context) RowLockMode {
+	return lockingReadOf(ctx).mode
+}
+
+func lockingReadOf(ctx *sql.Context) lockingRead {
+	sess, ok := ctx.Session.(*DoltSession)
+	if !ok {
+		return lockingRead{}
+	}
+	query := ctx.Query()
+	if query == "" {
+		return lockingRead{}
+	}
+
+	sess.mu.Lock()
+	cached := sess.lockingRead
+	sess.mu.Unlock()
+	if cached.query == query {
+		return cached
+	}
+
+	read := parseLockingRead(query)
+	sess.mu.Lock()
+	sess.lockingRead = read
+	sess.mu.Unlock()
+	return read
+}
+
+// parseLockingRead returns the row lock mode of the query given. The engine doesn't keep the locking clause of a
+// SELECT in its plan, so the query is parsed to find it, once per query.
+func parseLockingRead(query string) lockingRead {
+	read := lockingRead{query: query}
+	lower := strings.ToLower(query)
+	if !strings.Contains(lower, "update") && !strings.Contains(lower, "share") {
+		return read
+	}
+
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return read
+	}
+	_, read.isSelect = stmt.(*sqlparser.Select)
+
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if sel, ok := node.(*sqlparser.Select); ok && sel.Lock != "" {
+			lock := strings.ToLower(sel.Lock)
+			switch {
+			case strings.Contains(lock, "update"):
+				read.mode = RowLockExclusive
+			case strings.Contains(lock, "share") && read.mode == RowLockNone:
+				read.mode = RowLockShared
+			}
+			read.nowait = read.nowait || strings.Contains(lock, "nowait")
+			read.skipLocked = read.skipLocked || strings.Contains(lock, "skip locked")
+		}
+		return true, nil
+	}, stmt)
+
+	return read
+}
+
+// RowLocker takes row locks on a single table for the transactions of a session.
+type RowLocker struct {
+	sess        *DoltSession
+	table       rowLockTable
+	tableName   string
+	keyOrdinals []int
+}
+
+// NewRowLocker returns a RowLocker for the table named in the database given, which may or may not be revision
+// qualified. Rows given to the RowLocker must have every column of |sch|, in schema order. Returns nil if the session
+// doesn't take row locks, in which case every method of the nil RowLocker does nothing.
+func NewRowLocker(ctx *sql.Context, dbName, tableName string, sch schema.Schema) (*RowLocker, error) {
+	sess, ok := ctx.Session.(*DoltSession)
+	if !ok || TransactionsDisabled(ctx) {
+		return nil, nil
+	}
+	branchState, ok, err := sess.lookupDbState(ctx, dbName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if branchState.WorkingSet() == nil {
+		// read only revisions can't be written, so there's no need to lock their rows
+		return nil, nil
+	}
+
+	var keyOrdinals []int
+	if schema.IsKeyless(sch) {
+		for i, col := range sch.GetAllCols().GetColumns() {
+			if !col.Virtual {
+				keyOrdinals = append(keyOrdinals, i)
+			}
+		}
+	} else {
+		keyOrdinals = sch.GetPkOrdinals()
+	}
+
+	return &RowLocker{
+		sess: sess,
+		table: rowLockTable{
+			ddb:            branchState.dbData.Ddb,
+			revisionDbName: strings.ToLower(branchState.RevisionDbName()),
+			tableName:      strings.ToLower(tableName),
+		},
+		tableName:   tableName,
+		keyOrdinals: keyOrdinals,
+	}, nil
+}
+
+func (l *RowLocker) keyOf(row sql.Row) (rowLockKey, error) {
+	key := make(sql.Row, len(l.keyOrdinals))
+	for i, ord := range l.keyOrdinals {
+		key[i] = row[ord]
+	}
+	h, err := sql.HashOf(key)
+	if err != nil {
+		return rowLockKey{}, err
+	}
+	return rowLockKey{table: l.table, hash: h}, nil
+}
+
+// LockRowsForRead takes locks in the mode given on the rows returned by |scan|, which are the rows a locking read of
+// this table is about to return. The locks are held until the transaction ends.
+//
+// Like MySQL, a locking read returns the latest committed version of the rows it reads, rather than the version in the
+// transaction's snapshot. For a SELECT statement, the transaction is brought up to date with the changes committed
+// to the branch by other transactions before the rows are read, and again after every lock wait, which is why the
+// rows are scanned by a callback. Other statements can't change the snapshot they write to while they're running, so
+// they instead fail with a serialization error if they waited for a lock held by a transaction which then changed
+// the table.
+func (l *RowLocker) LockRowsForRead(ctx *sql.Context, mode RowLockMode, scan func(ctx *sql.Context) (sql.RowIter, error)) error {
+	if mode == RowLockNone {
+		return nil
+	}
+	// locking reads are for changing the rows they read, which read-only sessions can't do. MySQL allows them in READ
+	// ONLY transactions, so those are left alone.
+	if err := CheckReadOnlySession(ctx); err != nil {
+		return err
+	}
+	if l == nil {
+		return nil
+	}
+	tx, ok := ctx.GetTransaction().(*DoltTransaction)
+	if !ok {
+		return nil
+	}
+	read := lockingReadOf(ctx)
+	if read.skipLocked {
+		return ErrSkipLockedNotSupported
+	}
+	refresh := read.isSelect
+
+	for {
+		if refresh {
+			if err := l.sess.readLatest(ctx, tx, l.table.revisionDbName); err != nil {
+				return err
+			}
+		}
+
+		waited, err := l.lockScannedRows(ctx, mode, read.nowait, scan)
+		if err != nil {
+			return l.lockError(ctx, tx, err)
+		}
+		if !waited {
+			return nil
+		}
+		if !refresh {
+			return l.checkUnchanged(ctx, tx)
+		}
+	}
+}
+
+func (l *RowLocker) lockScannedRows(ctx *sql.Context, mode RowLockMode, nowait bool, scan func(ctx *sql.Context) (sql.RowIter, error)) (waited bool, err error) {
+	iter, err := scan(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if cerr := iter.Close(ctx); err == nil {
+			err = cerr
+		}
+	}()
+
+	var keys []rowLockKey
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return false, err
+		}
+		key, err := l.keyOf(row)
+		if err != nil {
+			return false, err
+		}
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		w, err := rowLocks.acquire(ctx, l.sess, key, mode, nowait)
+		waited = waited || w
+		if err != nil {
+			return waited, err
+		}
+	}
+	return waited, nil
+}
+
+// LockRowForWrite takes an exclusive lock on a row about to be written, waiting for any other transaction holding a
+// lock on it. Writes only take locks while some transaction holds locks on the table from a locking read, so that
+// transactions which don't use locking reads keep merging their concurrent changes to the same rows on commit, as
+// they always have. Once taken, the lock is held until the transaction ends.
+func (l *RowLocker) LockRowForWrite(ctx *sql.Context, row sql.Row) error {
+	if l == nil {
+		return nil
+	}
+	tx, ok := ctx.GetTransaction().(*DoltTransaction)
+	if !ok || !rowLocks.tableLocked(l.table) {
+		return nil
+	}
+
+	key, err := l.keyOf(row)
+	if err != nil {
+		return err
+	}
+	waited, err := rowLocks.acquire(ctx, l.sess, key, RowLockExclusive, false)
+	if err != nil {
+		return l.lockError(ctx, tx, err)
+	}
+	if waited {
+		return l.checkUnchanged(ctx, tx)
+	}
+	return nil
+}
+
+// lockError rolls back the transaction if |err| is a deadlock, which MySQL resolves by rolling back the transaction
+// which detected it.
+func (l *RowLocker) lockError(ctx *sql.Context, tx *DoltTransaction, err error) error {
+	if sql.ErrLockDeadlock.Is(err) {
+		if rollbackErr := tx.rollback(ctx); rollbackErr != nil {
+			return rollbackErr
+		}
+	}
+	return err
+}
+
+// checkUnchanged returns a serialization error, and rolls back the transaction, if the table has been changed by
+// another transaction since this transaction last read it. The current statement read the rows it's about to write
+// from that snapshot, so it can't continue if they might have changed.
+func (l *RowLocker) checkUnchanged(ctx *sql.Context, tx *DoltTransaction) error {
+	changed, err := l.sess.tableChangedSinceStart(ctx, tx, l.table.revisionDbName, l.tableName)
+	if err != nil || !changed {
+		return err
+	}
+	if rollbackErr := tx.rollback(ctx); rollbackErr != nil {
+		return rollbackErr
+	}
+	return sql.ErrLockDeadlock.New(lockedChangeMsg)
+}
+
+// tableChangedSinceStart returns whether the table named has been changed on the branch named since this transaction
+// began, or since a locking read last brought the branch up to date.
+func (d *DoltSession) tableChangedSinceStart(ctx *sql.Context, tx *DoltTransaction, revisionDbName, tableName string) (bool, error) {
+	branchState, ok, err := d.lookupDbState(ctx, revisionDbName)
+	if err != nil || !ok {
+		return false, err
+	}
+	startWs, latestWs, _, err := startAndLatestWorkingSets(ctx, tx, branchState)
+	if err != nil || startWs == nil {
+		return false, err
+	}
+
+	tblName := doltdb.TableName{Name: tableName}
+	startHash, _, err := startWs.WorkingRoot().GetTableHash(ctx, tblName)
+	if err != nil {
+		return false, err
+	}
+	latestHash, _, err := latestWs.WorkingRoot().GetTableHash(ctx, tblName)
+	if err != nil {
+		return false, err
+	}
+	return startHash != latestHash, nil
+}
+
+// startAndLatestWorkingSets returns the working set of the branch given as it was when this transaction began, or
+// when a locking read last brought it up to date, and as it is now, along with the noms root it was read from.
+// Returns nil working sets if the database hasn't changed since then, or if either working set doesn't exist.
+func startAndLatestWorkingSets(ctx *sql.Context, tx *DoltTransaction, branchState *branchState) (*doltdb.WorkingSet, *doltdb.WorkingSet, hash.Hash, error) {
+	if branchState.WorkingSet() == nil {
+		return nil, nil, hash.Hash{}, nil
+	}
+	startPoint, ok := tx.startPointFor(branchState)
+	if !ok {
+		return nil, nil, hash.Hash{}, nil
+	}
+	latestRoot, err := startPoint.db.NomsRoot(ctx)
+	if err != nil || latestRoot == startPoint.rootHash {
+		return nil, nil, hash.Hash{}, err
+	}
+
+	wsRef := branchState.WorkingSet().Ref()
+	startWs, err := startPoint.db.ResolveWorkingSetAtRoot(ctx, wsRef, startPoint.rootHash)
+	if err == doltdb.ErrWorkingSetNotFound {
+		return nil, nil, hash.Hash{}, nil
+	} else if err != nil {
+		return nil, nil, hash.Hash{}, err
+	}
+	latestWs, err := startPoint.db.ResolveWorkingSetAtRoot(ctx, wsRef, latestRoot)
+	if err == doltdb.ErrWorkingSetNotFound {
+		return nil, nil, hash.Hash{}, nil
+	} else if err != nil {
+		return nil, nil, hash.Hash{}, err
+	}
+	return startWs, latestWs, latestRoot, nil
+}
+
+// readLatest brings the branch named up to date with the changes committed to it by other transactions since this
+// transaction began, or since it was last brought up to date, by merging them into the session's working set. The
+// savepoints of the transaction are brought up to date as well, so that rolling back to one of them doesn't undo the
+// other transactions' changes.
+func (d *DoltSession) readLatest(ctx *sql.Context, tx *DoltTransaction, revisionDbName string) error {
+	branchState, ok, err := d.lookupDbState(ctx, revisionDbName)
+	if err != nil || !ok {
+		return err
+	}
+	startWs, latestWs, latestRoot, err := startAndLatestWorkingSets(ctx, tx, branchState)
+	if err != nil || startWs == nil {
+		return err
+	}
+
+	if !workingAndStagedEqual(startWs, latestWs) {
+		if err := d.mergeLatest(ctx, tx, branchState, startWs, latestWs, latestRoot); err != nil {
+			return err
+		}
+	}
+
+	tx.branchStartPoints[strings.ToLower(branchState.RevisionDbName())] = latestRoot
+	return nil
+}
+
+// mergeLatest merges the changes made to the branch given by other transactions, from |startWs| to |latestWs|, into
+// the session's working set and the transaction's savepoints. The changes can't include schema changes to existing
+// tables, since the statement being executed has already resolved its tables.
+func (d *DoltSession) mergeLatest(
+	ctx *sql.Context,
+	tx *DoltTransaction,
+	branchState *branchState,
+	startWs *doltdb.WorkingSet,
+	latestWs *doltdb.WorkingSet,
+	latestRoot hash.Hash,
+) error {
+	deltas, err := diff.GetTableDeltas(ctx, startWs.WorkingRoot(), latestWs.WorkingRoot())
+	if err != nil {
+		return err
+	}
+	for _, delta := range deltas {
+		schemaChanged := delta.IsDrop() || delta.IsRename()
+		if !schemaChanged && !delta.IsAdd() {
+			if schemaChanged, err = delta.HasSchemaChanged(ctx); err != nil {
+				return err
+			}
+		}
+		if schemaChanged {
+			if rollbackErr := tx.rollback(ctx); rollbackErr != nil {
+				return rollbackErr
+			}
+			return sql.ErrLockDeadlock.New(fmt.Sprintf("table %s was changed by a concurrent transaction", delta.FromName.Name))
+		}
+	}
+
+	ws := branchState.WorkingSet()
+	if tx.isolationLevel == IsolationLevelSerializable {
+		if err := tx.checkSerializable(ctx, branchState.RevisionDbName(), startWs, latestWs, ws); err != nil {
+			return err
+		}
+	}
+
+	merged, err := tx.mergeLatest(ctx, startWs, latestWs, ws, branchState.EditOpts())
+	if err != nil {
+		return err
+	}
+
+	if branchState.headCommit != nil {
+		headRef, err := ws.Ref().ToHeadRef()
+		if err != nil {
+			return err
+		}
+		latestHead, err := tx.dbStartPoints[strings.ToLower(branchState.dbState.dbName)].db.ResolveCommitRefAtRoot(ctx, headRef, latestRoot)
+		if err != nil {
+			return err
+		}
+		branchState.headCommit = latestHead
+	}
+
+	// Changes made by other transactions don't need to be committed by this one
+	dirty := branchState.dirty
+	if err := d.SetWorkingSet(ctx, branchState.RevisionDbName(), merged); err != nil {
+		return err
+	}
+	branchState.dirty = dirty
+
+	dbName := strings.ToLower(branchState.RevisionDbName())
+	for _, sp := range tx.savepoints {
+		spWs, ok := sp.workingSets[dbName]
+		if !ok {
+			continue
+		}
+		spWs.workingSet, err = tx.mergeLatest(ctx, startWs, latestWs, spWs.workingSet, branchState.EditOpts())
+		if err != nil {
+			return err
+		}
+		sp.workingSets[dbName] = spWs
+	}
+	return nil
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -64,6 +65,19 @@ type DoltSession struct {
 	fs               filesys.Filesys
 	writeSessProv    WriteSessFunc
 
+	// The XA transaction this session is working on, if any
+	xa *xaTransaction
+
+	// Savepoints to carry over into the next transaction. A DOLT_COMMIT inside an explicit transaction commits the
+	// transaction and begins a new one, but the savepoints created before it remain visible to the client.
+	carriedSavepoints []savepoint
+
+	// The row lock mode of the most recent query to read a table, guarded by |mu|
+	lockingRead lockingRead
+
+	// readOnly is true if every transaction of this session is READ ONLY
+	readOnly bool
+
 	// If non-nil, this will be returned from ValidateSession.
 	// Used by sqle/cluster to put a session into a terminal err state.
 	validateErr error
@@ -197,7 +211,19 @@ func (d *DoltSession) lookupDbState(ctx *sql.Context, dbName string) (*branchSta
 		return nil, false, sql.ErrDatabaseNotFound.New(dbName)
 	}
 
-	return dbState.heads[strings.ToLower(database.Revision())], true, nil
+	branchState := dbState.heads[strings.ToLower(database.Revision())]
+	if ctx != nil {
+		// Savepoints created before this branch was loaded must still be able to roll it back
+		if dtx, ok := ctx.GetTransaction().(*DoltTransaction); ok && len(dtx.savepoints) > 0 && branchState != nil {
+			if ws, ok, err := branchState.savepointWorkingSet(); err != nil {
+				return nil, false, err
+			} else if ok {
+				dtx.addToSavepoints(branchState.RevisionDbName(), ws)
+			}
+		}
+	}
+
+	return branchState, true, nil
 }
 
 // RevisionDbName returns the name of the revision db for the base name and revision string given
@@ -321,6 +347,15 @@ func (d *DoltSession) ValidateSession(ctx *sql.Context) error {
 	return d.validateErr
 }
 
+// SetTransaction implements sql.Session. The row locks held by the session belong to its transaction, so they're
+// released when the transaction is replaced or cleared, however the transaction ended.
+func (d *DoltSession) SetTransaction(tx sql.Transaction) {
+	if d.Session.GetTransaction() != tx {
+		rowLocks.releaseAll(d)
+	}
+	d.Session.SetTransaction(tx)
+}
+
 // StartTransaction refreshes the state of this session and starts a new transaction.
 func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.TransactionCharacteristic) (sql.Transaction, error) {
 	// TODO: this is only necessary to support filter-branch, which needs to set a root directly and not have the
@@ -329,6 +364,10 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 		return DisabledTransaction{}, nil
 	}
 
+	if d.readOnly {
+		tCharacteristic = sql.ReadOnly
+	}
+
 	// New transaction, clear all session state
 	d.clear()
 
@@ -369,6 +408,7 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 	if err != nil {
 		return nil, err
 	}
+	tx.savepoints, d.carriedSavepoints = d.carriedSavepoints, nil
 
 	// The engine sets the transaction after this call as well, but since we begin accessing data below, we need to set
 	// this now to avoid seeding the session state with stale data in some cases. The duplication is harmless since the
@@ -392,6 +432,12 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 	return tx, nil
 }
 
+// SetReadOnly makes every transaction this session starts READ ONLY, however it's started, so that no statement run
+// in the session can change any data.
+func (d *DoltSession) SetReadOnly() {
+	d.readOnly = true
+}
+
 // clear clears all DB state for this session
 func (d *DoltSession) clear() {
 	d.mu.Lock()
@@ -448,14 +494,15 @@ func (d *DoltSession) CommitTransaction(ctx *sql.Context, tx sql.Transaction) (e
 		return nil
 	}
 
+	if d.xa != nil {
+		return ErrXaRmfail(string(d.xa.state))
+	}
+
 	dirties := d.dirtyWorkingSets()
 	if len(dirties) == 0 {
 		return nil
 	}
-
-	if len(dirties) > 1 {
-		return ErrDirtyWorkingSets
-	}
+	SetQueryStage(ctx, QueryStageCommitting)
 
 	performDoltCommitVar, err := d.Session.GetSessionVariable(ctx, DoltCommitOnTransactionCommit)
 	if err != nil {
@@ -467,6 +514,15 @@ func (d *DoltSession) CommitTransaction(ctx *sql.Context, tx sql.Transaction) (e
 		return fmt.Errorf(fmt.Sprintf("Unexpected type for var %s: %T", DoltCommitOnTransactionCommit, performDoltCommitVar))
 	}
 
+	if len(dirties) > 1 {
+		// A dolt commit is only ever created on the checked out branch, so a transaction that also changed other
+		// branches or databases can't be committed that way. Otherwise, all the working sets are committed together.
+		if peformDoltCommitInt == 1 {
+			return ErrDirtyWorkingSets
+		}
+		return d.commitWorkingSets(ctx, dirties, tx)
+	}
+
 	dirtyBranchState := dirties[0]
 	if peformDoltCommitInt == 1 {
 		// if the dirty working set doesn't belong to the currently checked out branch, that's an error
@@ -547,7 +603,7 @@ func (d *DoltSession) validateDoltCommit(ctx *sql.Context, dirtyBranchState *bra
 	return nil
 }
 
-var ErrDirtyWorkingSets = errors.New("Cannot commit changes on more than one branch / database")
+var ErrDirtyWorkingSets = errors.New("Cannot commit changes on more than one branch / database with @@dolt_transaction_commit enabled")
 
 // dirtyWorkingSets returns all dirty working sets for this session
 func (d *DoltSession) dirtyWorkingSets() []*branchState {
@@ -563,6 +619,42 @@ func (d *DoltSession) dirtyWorkingSets() []*branchState {
 	return dirtyStates
 }
 
+// MemoryStats describe what a session holds in memory between queries.
+type MemoryStats struct {
+	// DirtyWorkingSets is the number of working sets with changes not yet committed to the database
+	DirtyWorkingSets int
+	// CacheEntries is the number of tables, indexes and other definitions in the session's caches
+	CacheEntries int
+}
+
+// MemoryStats returns what the session holds in memory. It may be called from outside the session's connection.
+func (d *DoltSession) MemoryStats() MemoryStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := MemoryStats{DirtyWorkingSets: len(d.dirtyWorkingSets())}
+	for _, state := range d.dbStates {
+		for _, c := range state.headCache {
+			stats.CacheEntries += c.Len()
+		}
+	}
+	return stats
+}
+
+// ClearCaches removes everything from the session's caches, which are refilled as queries use them. It may be called
+// from outside the session's connection.
+func (d *DoltSession) ClearCaches() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, state := range d.dbStates {
+		for _, c := range state.headCache {
+			c.Clear()
+		}
+	}
+	d.dbCache.Clear()
+}
+
 // CommitWorkingSet commits the working set for the transaction given, without creating a new dolt commit.
 // Clients should typically use CommitTransaction, which performs additional checks, instead of this method.
 func (d *DoltSession) CommitWorkingSet(ctx *sql.Context, dbName string, tx sql.Transaction) error {
@@ -586,6 +678,27 @@ func (d *DoltSession) commitWorkingSet(ctx *sql.Context, branchState *branchStat
 	return err
 }
 
+// commitWorkingSets atomically commits the working sets for all the branch states given, which may belong to different
+// databases, without creating a new dolt commit.
+func (d *DoltSession) commitWorkingSets(ctx *sql.Context, branchStates []*branchState, tx sql.Transaction) error {
+	if err := CheckWritableTransaction(ctx); err != nil {
+		return err
+	}
+	dtx, ok := tx.(*DoltTransaction)
+	if !ok {
+		return fmt.Errorf("expected a DoltTransaction")
+	}
+
+	err := dtx.commitWorkingSets(ctx, branchStates)
+	if err != nil {
+		return err
+	}
+
+	// See comment in |commitBranchState|
+	ctx.SetTransaction(nil)
+	return nil
+}
+
 // DoltCommit commits the working set and a new dolt commit with the properties given.
 // Clients should typically use CommitTransaction, which performs additional checks, instead of this method.
 func (d *DoltSession) DoltCommit(
@@ -607,7 +720,16 @@ func (d *DoltSession) DoltCommit(
 		return ws, commit, err
 	}
 
-	return d.commitCurrentHead(ctx, dbName, tx, commitFunc)
+	newCommit, err := d.commitCurrentHead(ctx, dbName, tx, commitFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inside an explicit transaction, the client's savepoints outlive the transaction committed here
+	if dtx, ok := tx.(*DoltTransaction); ok && ctx.GetIgnoreAutoCommit() {
+		d.carriedSavepoints = dtx.savepoints
+	}
+	return newCommit, nil
 }
 
 // doCommitFunc is a function to write to the database, which involves updating the working set and potentially
@@ -621,6 +743,9 @@ func (d *DoltSession) commitBranchState(
 	tx sql.Transaction,
 	commitFunc doCommitFunc,
 ) (*doltdb.Commit, error) {
+	if err := CheckWritableTransaction(ctx); err != nil {
+		return nil, err
+	}
 	dtx, ok := tx.(*DoltTransaction)
 	if !ok {
 		return nil, fmt.Errorf("expected a DoltTransaction")
@@ -686,7 +811,7 @@ func (d *DoltSession) newPendingCommit(ctx *sql.Context, branchState *branchStat
 	headHash, _ := headCommit.HashOf()
 
 	if branchState.WorkingSet() == nil {
-		return nil, doltdb.ErrOperationNotSupportedInDetachedHead
+		return nil, branchState.detachedHeadErr(branchState.RevisionDbName())
 	}
 
 	var mergeParentCommits []*doltdb.Commit
@@ -740,13 +865,18 @@ func (d *DoltSession) newPendingCommit(ctx *sql.Context, branchState *branchStat
 
 // Rollback rolls the given transaction back
 func (d *DoltSession) Rollback(ctx *sql.Context, tx sql.Transaction) error {
+	if d.xa != nil {
+		return ErrXaRmfail(string(d.xa.state))
+	}
+
 	// Nothing to do here, we just throw away all our work and let a new transaction begin next statement
 	d.clear()
 	return nil
 }
 
 // CreateSavepoint creates a new savepoint for this transaction with the name given. A previously created savepoint
-// with the same name will be overwritten.
+// with the same name will be overwritten. The savepoint records the entire working set of every branch in the session,
+// so that rolling back to it undoes schema changes and staged changes as well as changes to data.
 func (d *DoltSession) CreateSavepoint(ctx *sql.Context, tx sql.Transaction, savepointName string) error {
 	if TransactionsDisabled(ctx) {
 		return nil
@@ -757,25 +887,44 @@ func (d *DoltSession) CreateSavepoint(ctx *sql.Context, tx sql.Transaction, save
 		return fmt.Errorf("expected a DoltTransaction")
 	}
 
-	roots := make(map[string]doltdb.RootValue)
+	// Make sure the checked out branch of every database is loaded, then save the state of every loaded branch
 	for _, db := range d.provider.DoltDatabases() {
-		branchState, ok, err := d.lookupDbState(ctx, db.Name())
+		_, ok, err := d.lookupDbState(ctx, db.Name())
 		if err != nil {
 			return err
 		}
 		if !ok {
 			return fmt.Errorf("session state for database %s not found", db.Name())
 		}
-		baseName, _ := SplitRevisionDbName(db.Name())
-		roots[strings.ToLower(baseName)] = branchState.WorkingSet().WorkingRoot()
 	}
 
-	dtx.CreateSavepoint(savepointName, roots)
+	d.mu.Lock()
+	var branchStates []*branchState
+	for _, dbState := range d.dbStates {
+		for _, bs := range dbState.heads {
+			branchStates = append(branchStates, bs)
+		}
+	}
+	d.mu.Unlock()
+
+	workingSets := make(map[string]savepointWorkingSet)
+	for _, bs := range branchStates {
+		ws, ok, err := bs.savepointWorkingSet()
+		if err != nil {
+			return err
+		}
+		if ok {
+			workingSets[strings.ToLower(bs.RevisionDbName())] = ws
+		}
+	}
+
+	dtx.CreateSavepoint(savepointName, workingSets)
 	return nil
 }
 
-// RollbackToSavepoint sets this session's root to the one saved in the savepoint name. It's an error if no savepoint
-// with that name exists.
+// RollbackToSavepoint sets this session's working sets to the ones saved in the savepoint name. It's an error if no
+// savepoint with that name exists, or if a dolt commit has been created on any of the savepoint's branches since it
+// was created.
 func (d *DoltSession) RollbackToSavepoint(ctx *sql.Context, tx sql.Transaction, savepointName string) error {
 	if TransactionsDisabled(ctx) {
 		return nil
@@ -786,13 +935,43 @@ func (d *DoltSession) RollbackToSavepoint(ctx *sql.Context, tx sql.Transaction,
 		return fmt.Errorf("expected a DoltTransaction")
 	}
 
-	roots := dtx.RollbackToSavepoint(savepointName)
-	if roots == nil {
+	workingSets := dtx.RollbackToSavepoint(savepointName)
+	if workingSets == nil {
 		return sql.ErrSavepointDoesNotExist.New(savepointName)
 	}
 
-	for dbName, root := range roots {
-		err := d.SetWorkingRoot(ctx, dbName, root)
+	// Check every branch before changing any of them, so that a failed rollback leaves the session unchanged
+	branchStates := make(map[string]*branchState, len(workingSets))
+	for dbName, saved := range workingSets {
+		branchState, ok, err := d.lookupDbState(ctx, dbName)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// The database was dropped after the savepoint was created, there's nothing to roll back
+			continue
+		}
+		current, ok, err := branchState.savepointWorkingSet()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if current.headHash != saved.headHash {
+			return fmt.Errorf("%w: a dolt commit was created on %s after SAVEPOINT %s", ErrSavepointBeforeDoltCommit, dbName, savepointName)
+		}
+		if workingSetsEqual(branchState.WorkingSet(), saved.workingSet) {
+			continue
+		}
+		if branchState.readOnly {
+			return fmt.Errorf("cannot set root on read-only session")
+		}
+		branchStates[dbName] = branchState
+	}
+
+	for dbName := range branchStates {
+		err := d.SetWorkingSet(ctx, dbName, workingSets[dbName].workingSet)
 		if err != nil {
 			return err
 		}
@@ -937,7 +1116,7 @@ func (d *DoltSession) SetWorkingRoot(ctx *sql.Context, dbName string, newRoot do
 	}
 
 	if branchState.WorkingSet() == nil {
-		return doltdb.ErrOperationNotSupportedInDetachedHead
+		return branchState.detachedHeadErr(dbName)
 	}
 
 	if rootsEqual(branchState.roots().Working, newRoot) {
@@ -956,13 +1135,13 @@ func (d *DoltSession) SetWorkingRoot(ctx *sql.Context, dbName string, newRoot do
 // via setRoot. This method is for clients that need to update more of the session state, such as the dolt_ functions.
 // Unlike setting the working root, this method always marks the database state dirty.
 func (d *DoltSession) SetRoots(ctx *sql.Context, dbName string, roots doltdb.Roots) error {
-	sessionState, _, err := d.LookupDbState(ctx, dbName)
+	sessionState, _, err := d.lookupDbState(ctx, dbName)
 	if err != nil {
 		return err
 	}
 
 	if sessionState.WorkingSet() == nil {
-		return doltdb.ErrOperationNotSupportedInDetachedHead
+		return sessionState.detachedHeadErr(dbName)
 	}
 
 	workingSet := sessionState.WorkingSet().WithWorkingRoot(roots.Working).WithStagedRoot(roots.Staged)
@@ -983,6 +1162,10 @@ func (d *DoltSession) SetWorkingSet(ctx *sql.Context, dbName string, ws *doltdb.
 		panic("attempted to set a nil working set for the session")
 	}
 
+	if err := CheckWritableTransaction(ctx); err != nil {
+		return err
+	}
+
 	branchState, _, err := d.lookupDbState(ctx, dbName)
 	if err != nil {
 		return err
@@ -1052,12 +1235,12 @@ func (d *DoltSession) SwitchWorkingSet(
 
 func (d *DoltSession) WorkingSet(ctx *sql.Context, dbName string) (*doltdb.WorkingSet, error) {
 	// TODO: need to make sure we use a revision qualified DB name here
-	sessionState, _, err := d.LookupDbState(ctx, dbName)
+	sessionState, _, err := d.lookupDbState(ctx, dbName)
 	if err != nil {
 		return nil, err
 	}
 	if sessionState.WorkingSet() == nil {
-		return nil, doltdb.ErrOperationNotSupportedInDetachedHead
+		return nil, sessionState.detachedHeadErr(dbName)
 	}
 	return sessionState.WorkingSet(), nil
 }
@@ -1075,6 +1258,20 @@ func (d *DoltSession) GetHeadCommit(ctx *sql.Context, dbName string) (*doltdb.Co
 	return branchState.headCommit, nil
 }
 
+// CheckBranchProtection returns an error wrapping doltdb.ErrBranchProtected if the dolt_branch_protection rules forbid
+// the current user from moving the head of the branch checked out for |dbName|. |isMerge| is whether the head is being
+// moved by a merge.
+func (d *DoltSession) CheckBranchProtection(ctx *sql.Context, dbName string, isMerge bool) error {
+	branchState, ok, err := d.lookupDbState(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(dbName)
+	}
+	return branchState.checkBranchProtection(ctx, isMerge)
+}
+
 // SetSessionVariable is defined on sql.Session. We intercept it here to interpret the special semantics of the system
 // vars that we define. Otherwise we pass it on to the base implementation.
 func (d *DoltSession) SetSessionVariable(ctx *sql.Context, key string, value interface{}) error {
@@ -1092,8 +1289,30 @@ func (d *DoltSession) SetSessionVariable(ctx *sql.Context, key string, value int
 	if strings.ToLower(key) == "foreign_key_checks" {
 		return d.setForeignKeyChecksSessionVar(ctx, key, value)
 	}
+	if strings.ToLower(key) == DeferForeignKeyChecks {
+		return d.setDeferForeignKeyChecksSessionVar(ctx, key, value)
+	}
 
-	return d.Session.SetSessionVariable(ctx, key, value)
+	if err := d.Session.SetSessionVariable(ctx, key, value); err != nil {
+		return err
+	}
+	return d.warnIfReadCommitted(ctx, key)
+}
+
+// setDeferForeignKeyChecksSessionVar disables foreign key checks for statements while foreign keys are deferred. They
+// are checked instead when the transaction is committed, see checkDeferredForeignKeys.
+func (d *DoltSession) setDeferForeignKeyChecksSessionVar(ctx *sql.Context, key string, value interface{}) error {
+	if err := d.Session.SetSessionVariable(ctx, key, value); err != nil {
+		return err
+	}
+	deferred, err := d.Session.GetSessionVariable(ctx, DeferForeignKeyChecks)
+	if err != nil {
+		return err
+	}
+	if deferred.(int8) == 1 {
+		return d.setForeignKeyChecksSessionVar(ctx, "foreign_key_checks", int64(0))
+	}
+	return d.setForeignKeyChecksSessionVar(ctx, "foreign_key_checks", int64(1))
 }
 
 func (d *DoltSession) setHeadRefSessionVar(ctx *sql.Context, db, value string) error {
@@ -1287,6 +1506,7 @@ func (d *DoltSession) DropTemporaryTable(ctx *sql.Context, db, name string) {
 	tables := d.tempTables[strings.ToLower(db)]
 	for i, tbl := range d.tempTables[strings.ToLower(db)] {
 		if strings.ToLower(tbl.Name()) == strings.ToLower(name) {
+			releaseTemporaryTable(ctx, tbl)
 			tables = append(tables[:i], tables[i+1:]...)
 			break
 		}
@@ -1294,6 +1514,46 @@ func (d *DoltSession) DropTemporaryTable(ctx *sql.Context, db, name string) {
 	d.tempTables[strings.ToLower(db)] = tables
 }
 
+// ReleasableTemporaryTable is a temporary table with storage that must be released once the table is dropped.
+type ReleasableTemporaryTable interface {
+	sql.Table
+	// ReleaseStorage deletes the table's storage.
+	ReleaseStorage() error
+}
+
+// EndSession cleans up after this session once its client has disconnected, whichever protocol it connected with: its
+// transaction is rolled back, which releases its row locks, and its temporary tables and ephemeral branches are deleted.
+func (d *DoltSession) EndSession(ctx *sql.Context) {
+	if tx := d.GetTransaction(); tx != nil {
+		if err := d.Rollback(ctx, tx); err != nil {
+			ctx.GetLogger().Warnf("failed to roll back the transaction of closed session %d: %s", d.ID(), err.Error())
+		}
+	}
+	d.SetTransaction(nil)
+	rowLocks.releaseAll(d)
+	d.provider.EndSessionBranches(d.ID())
+	d.DropAllTemporaryTables(ctx)
+}
+
+// DropAllTemporaryTables drops every temporary table of this session, releasing their storage. It's called when the
+// session ends.
+func (d *DoltSession) DropAllTemporaryTables(ctx *sql.Context) {
+	for db, tables := range d.tempTables {
+		for _, tbl := range tables {
+			releaseTemporaryTable(ctx, tbl)
+		}
+		delete(d.tempTables, db)
+	}
+}
+
+func releaseTemporaryTable(ctx *sql.Context, tbl sql.Table) {
+	if rt, ok := tbl.(ReleasableTemporaryTable); ok {
+		if err := rt.ReleaseStorage(); err != nil {
+			ctx.GetLogger().Warnf("failed to release the storage of temporary table %s: %s", tbl.Name(), err.Error())
+		}
+	}
+}
+
 func (d *DoltSession) GetTemporaryTable(ctx *sql.Context, db, name string) (sql.Table, bool) {
 	for _, tbl := range d.tempTables[strings.ToLower(db)] {
 		if strings.ToLower(tbl.Name()) == strings.ToLower(name) {
@@ -1349,6 +1609,17 @@ func (d *DoltSession) Email() string {
 	return d.email
 }
 
+// ChangeCursorCommitMeta returns the commit metadata to record changes this session makes to change cursors with.
+// Unlike commits to branches, these are allowed in sessions without a configured user, and are made by the system
+// account.
+func (d *DoltSession) ChangeCursorCommitMeta() (*datas.CommitMeta, error) {
+	name, email := d.username, d.email
+	if name == "" || email == "" {
+		name, email = env.DefaultName, env.DefaultEmail
+	}
+	return datas.NewCommitMeta(name, email, "update change cursors")
+}
+
 // setDbSessionVars updates the three session vars that track the value of the session root hashes
 func (d *DoltSession) setDbSessionVars(ctx *sql.Context, state *branchState, force bool) error {
 	// This check is important even when we are forcing an update, because it updates the idea of staleness
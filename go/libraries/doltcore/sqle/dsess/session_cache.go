@@ -161,6 +161,39 @@ func (c *SessionCache) ClearTableCache() {
 	}
 }
 
+// Len returns the number of tables, indexes, views and other definitions cached.
+func (c *SessionCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	n := len(c.writers) + len(c.strictLookups) + len(c.checks) + len(c.tableMaps) + len(c.triggers)
+	for _, m := range c.indexes {
+		n += len(m)
+	}
+	for _, m := range c.tables {
+		n += len(m)
+	}
+	for _, m := range c.views {
+		n += len(m)
+	}
+	return n
+}
+
+// Clear removes everything cached. Entries are recomputed as they're next used.
+func (c *SessionCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.indexes = nil
+	c.tables = nil
+	c.tableMaps = nil
+	c.views = nil
+	c.triggers = nil
+	c.writers = nil
+	c.strictLookups = nil
+	c.checks = nil
+}
+
 type TableCacheKey struct {
 	Name   string
 	Schema string
@@ -16,6 +16,8 @@ package dsess
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -89,6 +91,38 @@ type DoltDatabaseProvider interface {
 	// (otherwise all branches are cloned), remoteName is the name for the remote created in the new database, and
 	// remoteUrl is a URL (e.g. "file:///dbs/db1") or an <org>/<database> path indicating a database hosted on DoltHub.
 	CloneDatabaseFromRemote(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, depth int, remoteParams map[string]string) error
+	// CloneDatabaseFromRemoteAsync is CloneDatabaseFromRemote, but returns as soon as the clone has been started and
+	// performs the clone in the background. The progress of the clone is reported by CloneStatuses.
+	CloneDatabaseFromRemoteAsync(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, depth int, remoteParams map[string]string) error
+	// CloneStatuses returns the status of every database cloned into this provider since it was started, in the order
+	// the clones were started.
+	CloneStatuses() []CloneStatus
+	// StorageHealth returns the result of the most recent scrub of the storage of each database in this provider, in
+	// order of database name. Databases which haven't been scrubbed are left out.
+	StorageHealth() []StorageHealth
+	// EnqueueMerge adds a merge of the branch |source| into the branch |target| of the database |dbName| to the end of
+	// this provider's merge queue, and returns its id. It's an error if the merge queue isn't running.
+	EnqueueMerge(ctx *sql.Context, dbName, source, target string, runTests bool) (int64, error)
+	// CancelQueuedMerge removes the merge with the id given from the merge queue, if it hasn't been started.
+	CancelQueuedMerge(ctx *sql.Context, dbName string, id int64) error
+	// MergeQueue returns every merge added to the merge queue of the database |dbName| since this provider was started,
+	// in the order they were added.
+	MergeQueue(dbName string) []MergeQueueEntry
+	// AddEphemeralBranch marks the branch |branch| of the database |dbName| as ephemeral, so that it's deleted once
+	// |ttl| has passed or the session |ctx| ends, whichever is first. It's an error if ephemeral branches aren't
+	// being cleaned up.
+	AddEphemeralBranch(ctx *sql.Context, dbName, branch string, ttl time.Duration) error
+	// EphemeralBranchesEnabled returns whether ephemeral branches are being cleaned up, which is only the case in a
+	// running sql-server.
+	EphemeralBranchesEnabled() bool
+	// EndSessionBranches expires the ephemeral branches created by the session with the id given, which has ended.
+	EndSessionBranches(sessionID uint32)
+	// VerifyAssertions returns an error if the working set of the database |dbName| violates any of the assertions in
+	// its dolt_assertions table. Nothing is checked unless an AssertionVerifier has been set.
+	VerifyAssertions(ctx *sql.Context, dbName string) error
+	// UserHasPrivileges returns whether the current user has all the privileges given. Every user has them unless a
+	// privilege checker has been set.
+	UserHasPrivileges(ctx *sql.Context, operations ...sql.PrivilegedOperation) bool
 	// SessionDatabase returns the SessionDatabase for the specified database, which may name a revision of a base
 	// database.
 	SessionDatabase(ctx *sql.Context, dbName string) (SqlDatabase, bool, error)
@@ -108,11 +142,113 @@ type DoltDatabaseProvider interface {
 	// ListDroppedDatabases returns a list of the database names for dropped databases that are still
 	// available on disk and can be restored with dolt_undrop().
 	ListDroppedDatabases(ctx *sql.Context) ([]string, error)
+	// DroppedDatabases returns the dropped databases that are still available on disk and can be restored with
+	// dolt_undrop(), along with how much space they use and when they were dropped.
+	DroppedDatabases(ctx *sql.Context) ([]DroppedDatabase, error)
+	// PurgeExpiredDroppedDatabases permanently deletes the dropped databases that were dropped before |droppedBefore|,
+	// and returns their names.
+	PurgeExpiredDroppedDatabases(ctx *sql.Context, droppedBefore time.Time) ([]string, error)
 	// PurgeDroppedDatabases permanently deletes any dropped databases that are being held in temporary storage
 	// in case they need to be restored. This operation is not reversible, so use with caution!
 	PurgeDroppedDatabases(ctx *sql.Context) error
 }
 
+// CloneStatus describes the progress of a database being cloned from a remote by a DoltDatabaseProvider.
+type CloneStatus struct {
+	// Database is the name of the database being created by the clone.
+	Database string
+	// RemoteUrl is the URL of the remote being cloned.
+	RemoteUrl string
+	// State is one of CloneRunning, CloneCompleted or CloneFailed.
+	State string
+	// ChunksTotal is the number of chunks in the table files being downloaded from the remote, once they are known.
+	ChunksTotal int64
+	// ChunksDownloaded is the number of chunks which have been downloaded so far.
+	ChunksDownloaded int64
+	// Error is the error the clone failed with, if its State is CloneFailed.
+	Error string
+	// StartedAt is the time the clone was started.
+	StartedAt time.Time
+	// FinishedAt is the time the clone completed or failed, or the zero time if it is still running.
+	FinishedAt time.Time
+}
+
+const (
+	CloneRunning   = "running"
+	CloneCompleted = "completed"
+	CloneFailed    = "failed"
+)
+
+// StorageHealth is the result of scrubbing the storage of a database, reading every chunk in its table files to find
+// ones which have been corrupted.
+type StorageHealth struct {
+	// Database is the name of the database which was scrubbed.
+	Database string
+	// ChunksChecked is the number of chunks which were read and verified.
+	ChunksChecked int64
+	// Problems are the problems found, and whether the table files with them were repaired.
+	Problems []doltdb.ScrubProblem
+	// Error is the error the scrub failed with, if it couldn't be completed.
+	Error string
+	// CheckedAt is the time the scrub finished.
+	CheckedAt time.Time
+}
+
+// DroppedDatabase is a dropped database which is being held on disk in case it needs to be restored.
+type DroppedDatabase struct {
+	// Name is the name of the database, which is used to restore it with dolt_undrop().
+	Name string
+	// Size is the number of bytes used by the files of the database.
+	Size int64
+	// DroppedAt is the time the database was dropped.
+	DroppedAt time.Time
+}
+
+// ErrEphemeralBranchesNotRunning is returned when creating an ephemeral branch with a provider which isn't cleaning
+// them up.
+var ErrEphemeralBranchesNotRunning = errors.New("ephemeral branches are only available in a running sql-server")
+
+// MergeQueueEntry is a merge in the merge queue of a DoltDatabaseProvider, which merges branches one at a time so that
+// the merges don't race with each other.
+type MergeQueueEntry struct {
+	// ID identifies the merge, and gives the order merges are done in.
+	ID int64
+	// Database is the name of the database the branches are in.
+	Database string
+	// Source is the branch being merged.
+	Source string
+	// Target is the branch being merged into.
+	Target string
+	// RunTests is whether the tests in dolt_tests must pass on the result of the merge before it's committed.
+	RunTests bool
+	// State is one of MergeQueued, MergeRunning, MergeMerged, MergeFailed or MergeCanceled.
+	State string
+	// User is the user who added the merge to the queue. The merge is done as this user.
+	User string
+	// Host is the address of the client which added the merge to the queue.
+	Host string
+	// Commit is the hash of the commit the target branch was updated to, if the merge was done.
+	Commit string
+	// Error is the reason the merge failed, if its State is MergeFailed.
+	Error string
+	// EnqueuedAt is the time the merge was added to the queue.
+	EnqueuedAt time.Time
+	// FinishedAt is the time the merge was done, failed or was canceled, or the zero time if it hasn't finished.
+	FinishedAt time.Time
+}
+
+const (
+	MergeQueued   = "queued"
+	MergeRunning  = "running"
+	MergeMerged   = "merged"
+	MergeFailed   = "failed"
+	MergeCanceled = "canceled"
+)
+
+// AssertionVerifier returns an error if the working set of the database |dbName| violates any of the assertions in its
+// dolt_assertions table. Checking assertions requires running queries, so it's provided by the SQL engine.
+type AssertionVerifier func(ctx *sql.Context, dbName string) error
+
 type SessionDatabaseBranchSpec struct {
 	RepoState env.RepoStateReadWriter
 	Branch    string
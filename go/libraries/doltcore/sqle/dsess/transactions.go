@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -31,8 +33,10 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
 	"github.com/dolthub/dolt/go/store/prolly"
 )
 
@@ -42,6 +46,12 @@ const (
 
 var ErrRetryTransaction = errors.New("this transaction conflicts with a committed transaction from another client")
 
+var ErrDeferredForeignKeyViolation = errors.New("foreign key constraints are violated by the deferred changes to tables")
+
+var ErrReadOnlyTransaction = errors.New("cannot execute statement in a READ ONLY transaction")
+
+var ErrSavepointBeforeDoltCommit = errors.New("cannot roll back to a savepoint created before a dolt commit")
+
 var ErrUnresolvedConflictsCommit = errors.New("Merge conflict detected, transaction rolled back. Merge conflicts must be resolved using the dolt_conflicts and dolt_schema_conflicts tables before committing a transaction. To commit transactions with merge conflicts, set @@dolt_allow_commit_conflicts = 1")
 
 var ErrUnresolvedConflictsAutoCommit = errors.New("Merge conflict detected, @autocommit transaction rolled back. @autocommit must be disabled so that merge conflicts can be resolved using the dolt_conflicts and dolt_schema_conflicts tables before manually committing the transaction. Alternatively, to commit transactions with merge conflicts, set @@dolt_allow_commit_conflicts = 1")
@@ -83,6 +93,12 @@ type DoltTransaction struct {
 	dbStartPoints   map[string]dbRoot
 	savepoints      []savepoint
 	tCharacteristic sql.TransactionCharacteristic
+	isolationLevel  IsolationLevel
+	// reads records the tables read by this transaction, for SERIALIZABLE transactions
+	reads *tableReadSet
+	// branchStartPoints holds the noms roots at which branches were brought up to date by locking reads, keyed by
+	// lower-cased revision qualified database name. They replace the start point of the branch's database.
+	branchStartPoints map[string]hash.Hash
 }
 
 type dbRoot struct {
@@ -93,8 +109,17 @@ type dbRoot struct {
 
 type savepoint struct {
 	name string
-	// TODO: we need a root value per DB here
-	roots map[string]doltdb.RootValue
+	// workingSets holds the state of every branch loaded in the session when the savepoint was created, keyed by
+	// lower-cased revision qualified database name
+	workingSets map[string]savepointWorkingSet
+}
+
+// savepointWorkingSet is the state of a single branch saved in a savepoint
+type savepointWorkingSet struct {
+	workingSet *doltdb.WorkingSet
+	// headHash is the hash of the branch's HEAD commit when the savepoint was created. A savepoint can't be rolled
+	// back to once a new dolt commit has been created on the branch.
+	headHash hash.Hash
 }
 
 func NewDoltTransaction(
@@ -119,8 +144,11 @@ func NewDoltTransaction(
 	}
 
 	return &DoltTransaction{
-		dbStartPoints:   startPoints,
-		tCharacteristic: tCharacteristic,
+		dbStartPoints:     startPoints,
+		tCharacteristic:   tCharacteristic,
+		isolationLevel:    sessionIsolationLevel(ctx),
+		reads:             newTableReadSet(),
+		branchStartPoints: make(map[string]hash.Hash),
 	}, nil
 }
 
@@ -150,6 +178,29 @@ func (tx DoltTransaction) IsReadOnly() bool {
 	return tx.tCharacteristic == sql.ReadOnly
 }
 
+// CheckWritableTransaction returns ErrReadOnlyTransaction if the transaction of |ctx| is READ ONLY, or would be
+// because its session is read-only. It guards the changes which don't go through a plan node the engine can see,
+// such as the writes of functions and locking reads.
+func CheckWritableTransaction(ctx *sql.Context) error {
+	if tx := ctx.GetTransaction(); tx != nil && tx.IsReadOnly() {
+		return ErrReadOnlyTransaction
+	}
+	if sess, ok := ctx.Session.(*DoltSession); ok && sess.readOnly {
+		return ErrReadOnlyTransaction
+	}
+	return nil
+}
+
+// CheckReadOnlySession returns ErrReadOnlyTransaction if the session of |ctx| is read-only. Unlike
+// CheckWritableTransaction, it allows what MySQL allows in a READ ONLY transaction, such as locking reads and writing
+// files, and only guards the statements a read-only session must not run at all.
+func CheckReadOnlySession(ctx *sql.Context) error {
+	if sess, ok := ctx.Session.(*DoltSession); ok && sess.readOnly {
+		return ErrReadOnlyTransaction
+	}
+	return nil
+}
+
 // GetInitialRoot returns the noms root hash for the db named, established when the transaction began. The dbName here
 // is always the base name of the database, not the revision qualified one.
 func (tx DoltTransaction) GetInitialRoot(dbName string) (hash.Hash, bool) {
@@ -158,6 +209,19 @@ func (tx DoltTransaction) GetInitialRoot(dbName string) (hash.Hash, bool) {
 	return startPoint.rootHash, ok
 }
 
+// startPointFor returns the start point of the transaction for the branch given: the noms root of its database when
+// the transaction began, unless a locking read has since brought the branch up to date.
+func (tx *DoltTransaction) startPointFor(branchState *branchState) (dbRoot, bool) {
+	startPoint, ok := tx.dbStartPoints[strings.ToLower(branchState.dbState.dbName)]
+	if !ok {
+		return dbRoot{}, false
+	}
+	if root, ok := tx.branchStartPoints[strings.ToLower(branchState.RevisionDbName())]; ok {
+		startPoint.rootHash = root
+	}
+	return startPoint, true
+}
+
 var txLock sync.Mutex
 
 // Commit attempts to merge the working set given into the current working set.
@@ -369,7 +433,17 @@ func (tx *DoltTransaction) doCommit(
 	commit *doltdb.PendingCommit,
 	writeFn transactionWrite,
 	dbName string,
-) (*doltdb.WorkingSet, *doltdb.Commit, error) {
+) (_ *doltdb.WorkingSet, _ *doltdb.Commit, err error) {
+	// Commits made under |txLock| don't wait for the chunk journal to be synced. The sync is done once the lock is
+	// released, so that concurrent transactions which commit in the meantime can share it.
+	gcCtx, groupCommit := nbs.WithGroupCommit(ctx)
+	defer func() {
+		if werr := groupCommit.Wait(gcCtx); err == nil {
+			err = werr
+		}
+	}()
+	ctx = ctx.WithContext(gcCtx)
+
 	sess := DSessFromSess(ctx.Session)
 	branchState, ok, err := sess.lookupDbState(ctx, dbName)
 	if err != nil {
@@ -380,8 +454,7 @@ func (tx *DoltTransaction) doCommit(
 	}
 
 	// Load the start state for this working set from the noms root at tx start
-	// Get the base DB name from the db state, not the branch state
-	startPoint, ok := tx.dbStartPoints[strings.ToLower(branchState.dbState.dbName)]
+	startPoint, ok := tx.startPointFor(branchState)
 	if !ok {
 		return nil, nil, fmt.Errorf("database %s unknown to transaction, this is a bug", dbName)
 	}
@@ -393,6 +466,15 @@ func (tx *DoltTransaction) doCommit(
 
 	// TODO: no-op if the working set hasn't changed since the transaction started
 
+	if commit != nil || !workingAndStagedEqual(startState, workingSet) {
+		if err = checkBranchProtection(ctx, branchState, commit); err != nil {
+			return nil, nil, err
+		}
+		if err = checkDeferredForeignKeys(ctx, startState, workingSet); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	mergeOpts := branchState.EditOpts()
 
 	for i := 0; i < maxTxCommitRetries; i++ {
@@ -401,57 +483,17 @@ func (tx *DoltTransaction) doCommit(
 			txLock.Lock()
 			defer txLock.Unlock()
 
-			newWorkingSet := false
-
-			existingWs, err := startPoint.db.ResolveWorkingSet(ctx, workingSet.Ref())
-			if err == doltdb.ErrWorkingSetNotFound {
-				// This is to handle the case where an existing DB pre working sets is committing to this HEAD for the
-				// first time. Can be removed and called an error post 1.0
-				existingWs = doltdb.EmptyWorkingSet(workingSet.Ref())
-				newWorkingSet = true
-			} else if err != nil {
-				return nil, nil, err
-			}
-
-			existingWSHash, err := existingWs.HashOf()
-			if err != nil {
-				return nil, nil, err
-			}
-
-			if newWorkingSet || workingAndStagedEqual(existingWs, startState) {
-				// ff merge
-				err = tx.validateWorkingSetForCommit(ctx, workingSet, isFfMerge)
-				if err != nil {
-					return nil, nil, err
-				}
-
-				var newCommit *doltdb.Commit
-				workingSet, newCommit, err = writeFn(ctx, tx, startPoint.db, startState, commit, workingSet, existingWSHash, mergeOpts)
-				if err == datas.ErrOptimisticLockFailed {
-					// this is effectively a `continue` in the loop
-					return nil, nil, nil
-				} else if err != nil {
-					return nil, nil, err
-				}
-
-				return workingSet, newCommit, nil
-			}
-
-			// otherwise (not a ff), merge the working sets together
-			start := time.Now()
-			mergedWorkingSet, err := tx.mergeRoots(ctx, startState, existingWs, workingSet, mergeOpts)
+			toWrite, existingWs, err := tx.prepareWorkingSet(ctx, branchState.RevisionDbName(), startPoint.db, startState, workingSet, mergeOpts)
 			if err != nil {
 				return nil, nil, err
 			}
-			logrus.Tracef("working set merge took %s", time.Since(start))
 
-			err = tx.validateWorkingSetForCommit(ctx, mergedWorkingSet, notFfMerge)
+			existingWSHash, err := existingWs.HashOf()
 			if err != nil {
 				return nil, nil, err
 			}
 
-			var newCommit *doltdb.Commit
-			mergedWorkingSet, newCommit, err = writeFn(ctx, tx, startPoint.db, startState, commit, mergedWorkingSet, existingWSHash, mergeOpts)
+			updatedWs, newCommit, err := writeFn(ctx, tx, startPoint.db, startState, commit, toWrite, existingWSHash, mergeOpts)
 			if err == datas.ErrOptimisticLockFailed {
 				// this is effectively a `continue` in the loop
 				return nil, nil, nil
@@ -459,7 +501,7 @@ func (tx *DoltTransaction) doCommit(
 				return nil, nil, err
 			}
 
-			return mergedWorkingSet, newCommit, nil
+			return updatedWs, newCommit, nil
 		}()
 
 		if err != nil {
@@ -473,6 +515,293 @@ func (tx *DoltTransaction) doCommit(
 	return nil, nil, datas.ErrOptimisticLockFailed
 }
 
+// prepareWorkingSet returns the working set to write to |db| in order to commit |workingSet|, along with the working
+// set currently in |db| which it must replace. If the working set in |db| has changed since the transaction started
+// at |startState|, the returned working set is the merge of the two, unless this transaction is SERIALIZABLE and
+// conflicts with the changes made since it started. Returns ErrXaBranchPrepared if a prepared XA transaction writes
+// the same working set. Must be called with |txLock| held.
+func (tx *DoltTransaction) prepareWorkingSet(
+	ctx *sql.Context,
+	revisionDbName string,
+	db *doltdb.DoltDB,
+	startState *doltdb.WorkingSet,
+	workingSet *doltdb.WorkingSet,
+	mergeOpts editor.Options,
+) (*doltdb.WorkingSet, *doltdb.WorkingSet, error) {
+	if err := preparedXaTransactions.checkNotReserved(ctx, db, workingSet.Ref()); err != nil {
+		return nil, nil, err
+	}
+
+	newWorkingSet := false
+
+	existingWs, err := db.ResolveWorkingSet(ctx, workingSet.Ref())
+	if err == doltdb.ErrWorkingSetNotFound {
+		// This is to handle the case where an existing DB pre working sets is committing to this HEAD for the
+		// first time. Can be removed and called an error post 1.0
+		existingWs = doltdb.EmptyWorkingSet(workingSet.Ref())
+		newWorkingSet = true
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if newWorkingSet || workingAndStagedEqual(existingWs, startState) {
+		// ff merge
+		err = tx.validateWorkingSetForCommit(ctx, workingSet, isFfMerge)
+		if err != nil {
+			return nil, nil, err
+		}
+		return workingSet, existingWs, nil
+	}
+
+	// otherwise (not a ff), merge the working sets together
+	if tx.isolationLevel == IsolationLevelSerializable {
+		err = tx.checkSerializable(ctx, revisionDbName, startState, existingWs, workingSet)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	start := time.Now()
+	mergedWorkingSet, err := tx.mergeRoots(ctx, startState, existingWs, workingSet, mergeOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	logrus.Tracef("working set merge took %s", time.Since(start))
+
+	err = tx.validateWorkingSetForCommit(ctx, mergedWorkingSet, notFfMerge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mergedWorkingSet, existingWs, nil
+}
+
+// pendingWorkingSet is a working set being committed by commitWorkingSets.
+type pendingWorkingSet struct {
+	revisionDbName string
+	db             *doltdb.DoltDB
+	startState     *doltdb.WorkingSet
+	workingSet     *doltdb.WorkingSet
+	mergeOpts      editor.Options
+
+	// replaced is the working set currently in |db|, which the working set written must replace
+	replaced *doltdb.WorkingSet
+}
+
+// commitWorkingSets commits the working sets of every branch in |branchStates|, which may belong to different
+// databases, atomically: either every working set is written, or none of them are.
+func (tx *DoltTransaction) commitWorkingSets(ctx *sql.Context, branchStates []*branchState) error {
+	pending, err := tx.pendingWorkingSets(ctx, branchStates)
+	if err != nil {
+		return err
+	}
+	return tx.commitPendingWorkingSets(ctx, pending)
+}
+
+// pendingWorkingSets returns the working sets of |branchStates| which are to be committed by this transaction.
+func (tx *DoltTransaction) pendingWorkingSets(ctx *sql.Context, branchStates []*branchState) ([]*pendingWorkingSet, error) {
+	pending := make([]*pendingWorkingSet, len(branchStates))
+	for i, branchState := range branchStates {
+		startPoint, ok := tx.startPointFor(branchState)
+		if !ok {
+			return nil, fmt.Errorf("database %s unknown to transaction, this is a bug", branchState.RevisionDbName())
+		}
+
+		workingSet := branchState.WorkingSet()
+		startState, err := startPoint.db.ResolveWorkingSetAtRoot(ctx, workingSet.Ref(), startPoint.rootHash)
+		if err != nil {
+			return nil, err
+		}
+
+		if !workingAndStagedEqual(startState, workingSet) {
+			if err = checkBranchProtection(ctx, branchState, nil); err != nil {
+				return nil, err
+			}
+			if err = checkDeferredForeignKeys(ctx, startState, workingSet); err != nil {
+				return nil, err
+			}
+		}
+
+		pending[i] = &pendingWorkingSet{
+			revisionDbName: branchState.RevisionDbName(),
+			db:             startPoint.db,
+			startState:     startState,
+			workingSet:     workingSet,
+			mergeOpts:      branchState.EditOpts(),
+		}
+	}
+	return pending, nil
+}
+
+// commitPendingWorkingSets writes all the working sets in |pending| atomically.
+//
+// With |txLock| held, so that no other transaction in this process can commit in the meantime, every working set is
+// first merged with the current working set in its database and validated. A single working set is then simply
+// written. Several working sets, which may belong to different databases, are written with a two-phase commit: see
+// commitTwoPhase.
+func (tx *DoltTransaction) commitPendingWorkingSets(ctx *sql.Context, pending []*pendingWorkingSet) (err error) {
+	gcCtx, groupCommit := nbs.WithGroupCommit(ctx)
+	defer func() {
+		if werr := groupCommit.Wait(gcCtx); err == nil {
+			err = werr
+		}
+	}()
+	ctx = ctx.WithContext(gcCtx)
+
+	for i := 0; i < maxTxCommitRetries; i++ {
+		var rscs []doltdb.ReplicationStatusController
+		committed, err := func() (bool, error) {
+			txLock.Lock()
+			defer txLock.Unlock()
+
+			toWrite, err := tx.preparePendingWorkingSets(ctx, pending)
+			if err != nil {
+				return false, err
+			}
+
+			if len(pending) == 1 {
+				err = tx.writeWorkingSet(ctx, pending[0], toWrite[0], &rscs)
+			} else {
+				err = tx.commitTwoPhase(ctx, pending, toWrite, &rscs)
+			}
+			if err == datas.ErrOptimisticLockFailed {
+				// this is effectively a `continue` in the loop
+				return false, nil
+			}
+			return err == nil, err
+		}()
+
+		for _, rsc := range rscs {
+			WaitForReplicationController(ctx, rsc)
+		}
+
+		if err != nil {
+			return err
+		} else if committed {
+			return nil
+		}
+	}
+
+	return datas.ErrOptimisticLockFailed
+}
+
+// preparePendingWorkingSets merges and validates every working set in |pending|, without writing any of them, and
+// returns the working sets to write. Must be called with |txLock| held.
+func (tx *DoltTransaction) preparePendingWorkingSets(ctx *sql.Context, pending []*pendingWorkingSet) ([]*doltdb.WorkingSet, error) {
+	toWrite := make([]*doltdb.WorkingSet, len(pending))
+	for i, p := range pending {
+		var err error
+		toWrite[i], p.replaced, err = tx.prepareWorkingSet(ctx, p.revisionDbName, p.db, p.startState, p.workingSet, p.mergeOpts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return toWrite, nil
+}
+
+// commitTwoPhase writes the working sets |toWrite| over the ones |pending| replace, with a two-phase commit. Must be
+// called with |txLock| held.
+//
+// In the first phase, each working set is stored in its database along with the working set it replaces, the same
+// way XA PREPARE stores the working sets of an XA transaction, under an XID of its own. Once they have all been
+// stored, the transaction is committed, and in the second phase they are written. If the process stops before they
+// have all been written, the stored working sets are used to finish writing them the next time the databases are
+// loaded. If it stops before they have all been stored, the ones which were are deleted instead. See
+// recoverInterruptedCommits.
+//
+// If the first working set can't be written, for example because another process has written to it, the stored
+// working sets are deleted and the error is returned. If a later one can't be written, the transaction stays prepared,
+// so that it can be finished later, and its branches can't be committed to until it is.
+func (tx *DoltTransaction) commitTwoPhase(ctx *sql.Context, pending []*pendingWorkingSet, toWrite []*doltdb.WorkingSet, rscs *[]doltdb.ReplicationStatusController) error {
+	prepared := &preparedXaTransaction{
+		xid:  newCommitXid(len(pending)),
+		user: ctx.Session.Client().User,
+		busy: true,
+	}
+	for i, p := range pending {
+		prepared.branches = append(prepared.branches, &preparedXaBranch{
+			db:       p.db,
+			wsRef:    p.workingSet.Ref(),
+			base:     p.replaced,
+			prepared: toWrite[i],
+		})
+	}
+
+	// Phase one: store every working set.
+	if err := preparedXaTransactions.add(ctx, prepared); err != nil {
+		return err
+	}
+
+	// Phase two: write them all.
+	written, err := writePreparedXaTransaction(ctx, prepared, rscs)
+	if err != nil && written == 0 {
+		if derr := deleteXaRefs(ctx, prepared.xid, prepared.branches); derr != nil {
+			preparedXaTransactions.release(prepared, false)
+			return fmt.Errorf("%w; additionally, failed to delete the transaction's stored working sets: %s", err, derr.Error())
+		}
+		preparedXaTransactions.release(prepared, true)
+		return err
+	} else if err != nil {
+		preparedXaTransactions.release(prepared, false)
+		return fmt.Errorf("%w; the transaction is prepared as XA transaction %s, which must be committed to finish writing it", err, prepared.xid.String())
+	}
+
+	// The transaction is committed, so failing to delete it only leaves stale working sets behind.
+	if err := deleteXaRefs(ctx, prepared.xid, prepared.branches); err != nil {
+		logrus.Warnf("failed to delete the stored working sets of committed transaction %s: %s", prepared.xid.String(), err.Error())
+	}
+	preparedXaTransactions.release(prepared, true)
+	return nil
+}
+
+// writeWorkingSet writes |toWrite| over the working set |p.replaced|.
+func (tx *DoltTransaction) writeWorkingSet(ctx *sql.Context, p *pendingWorkingSet, toWrite *doltdb.WorkingSet, rscs *[]doltdb.ReplicationStatusController) error {
+	prevHash, err := p.replaced.HashOf()
+	if err != nil {
+		return err
+	}
+
+	var rsc doltdb.ReplicationStatusController
+	err = p.db.UpdateWorkingSet(ctx, toWrite.Ref(), toWrite, prevHash, tx.WorkingSetMeta(ctx), &rsc)
+	if err != nil {
+		return err
+	}
+	*rscs = append(*rscs, rsc)
+	return nil
+}
+
+// checkBranchProtection returns an error if the dolt_branch_protection rules at the head of the branch being written
+// forbid the current user from committing its working set, and |commit| if it is non-nil. Users allowed to merge into
+// a protected branch may change its working set, which resolving merge conflicts requires, but may only commit merges.
+func checkBranchProtection(ctx *sql.Context, branchState *branchState, commit *doltdb.PendingCommit) error {
+	isMerge := commit == nil || len(commit.CommitOptions.Parents) > 0
+	return branchState.checkBranchProtection(ctx, isMerge)
+}
+
+// checkDeferredForeignKeys returns an error if foreign key checks are deferred by @@dolt_defer_foreign_key_checks and
+// the changes made to |workingSet| since |startState| violate any foreign keys. Cascading referential actions are not
+// applied to rows changed while foreign key checks are deferred.
+func checkDeferredForeignKeys(ctx *sql.Context, startState, workingSet *doltdb.WorkingSet) error {
+	deferred, err := ctx.GetSessionVariable(ctx, DeferForeignKeyChecks)
+	if err != nil {
+		return err
+	}
+	if deferred.(int8) != 1 {
+		return nil
+	}
+
+	violated, err := merge.GetForeignKeyViolatedTables(ctx, workingSet.WorkingRoot(), startState.WorkingRoot(), set.NewStrSet(nil))
+	if err != nil {
+		return err
+	}
+	if violated.Size() == 0 {
+		return nil
+	}
+	tables := violated.AsSlice()
+	sort.Strings(tables)
+	return fmt.Errorf("%w: %s", ErrDeferredForeignKeyViolation, strings.Join(tables, ", "))
+}
+
 // mergeRoots merges the roots in the existing working set with the one being committed and returns the resulting
 // working set. Conflicts are automatically resolved with "accept ours" if the session settings dictate it.
 // Currently merges working and staged roots as necessary. HEAD root is only handled by the DoltCommit function.
@@ -519,6 +848,30 @@ func (tx *DoltTransaction) mergeRoots(
 	return workingSet, nil
 }
 
+// mergeLatest merges the changes committed by other transactions, from |startState| to |latestState|, into
+// |workingSet|, and returns the result. Returns an error if they conflict with the changes in |workingSet|.
+func (tx *DoltTransaction) mergeLatest(
+	ctx *sql.Context,
+	startState *doltdb.WorkingSet,
+	latestState *doltdb.WorkingSet,
+	workingSet *doltdb.WorkingSet,
+	mergeOpts editor.Options,
+) (*doltdb.WorkingSet, error) {
+	if workingAndStagedEqual(startState, workingSet) {
+		return workingSet.WithWorkingRoot(latestState.WorkingRoot()).WithStagedRoot(latestState.StagedRoot()), nil
+	}
+
+	merged, err := tx.mergeRoots(ctx, startState, latestState, workingSet, mergeOpts)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.validateWorkingSetForCommit(ctx, merged, notFfMerge)
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
 // rollback attempts a transaction rollback
 func (tx *DoltTransaction) rollback(ctx *sql.Context) error {
 	sess := DSessFromSess(ctx.Session)
@@ -742,14 +1095,26 @@ func (tx *DoltTransaction) validateWorkingSetForCommit(ctx *sql.Context, working
 	return nil
 }
 
-// CreateSavepoint creates a new savepoint with the name and roots given. If a savepoint with the name given
+// CreateSavepoint creates a new savepoint with the name and working sets given. If a savepoint with the name given
 // already exists, it's overwritten.
-func (tx *DoltTransaction) CreateSavepoint(name string, roots map[string]doltdb.RootValue) {
+func (tx *DoltTransaction) CreateSavepoint(name string, workingSets map[string]savepointWorkingSet) {
 	existing := tx.findSavepoint(name)
 	if existing >= 0 {
 		tx.savepoints = append(tx.savepoints[:existing], tx.savepoints[existing+1:]...)
 	}
-	tx.savepoints = append(tx.savepoints, savepoint{name, roots})
+	tx.savepoints = append(tx.savepoints, savepoint{name, workingSets})
+}
+
+// addToSavepoints records the working set given for the branch named in every savepoint that doesn't already have
+// one. This is used for branches first loaded into the session after a savepoint was created, which can't have been
+// changed since then.
+func (tx *DoltTransaction) addToSavepoints(revisionDbName string, ws savepointWorkingSet) {
+	key := strings.ToLower(revisionDbName)
+	for _, s := range tx.savepoints {
+		if _, ok := s.workingSets[key]; !ok {
+			s.workingSets[key] = ws
+		}
+	}
 }
 
 // findSavepoint returns the index of the savepoint with the name given, or -1 if it doesn't exist
@@ -762,14 +1127,14 @@ func (tx *DoltTransaction) findSavepoint(name string) int {
 	return -1
 }
 
-// RollbackToSavepoint returns the root values for all applicable databases associated with the savepoint name given, or nil if no such savepoint can
-// be found. All savepoints created after the one being rolled back to are no longer accessible.
-func (tx *DoltTransaction) RollbackToSavepoint(name string) map[string]doltdb.RootValue {
+// RollbackToSavepoint returns the working sets for all the branches associated with the savepoint name given, or nil if
+// no such savepoint can be found. All savepoints created after the one being rolled back to are no longer accessible.
+func (tx *DoltTransaction) RollbackToSavepoint(name string) map[string]savepointWorkingSet {
 	existing := tx.findSavepoint(name)
 	if existing >= 0 {
 		// Clear out any savepoints past this one
 		tx.savepoints = tx.savepoints[:existing+1]
-		return tx.savepoints[existing].roots
+		return tx.savepoints[existing].workingSets
 	}
 	return nil
 }
@@ -795,6 +1160,69 @@ func (tx DoltTransaction) WorkingSetMeta(ctx *sql.Context) *datas.WorkingSetMeta
 	}
 }
 
+// workingSetsEqual returns whether the two working sets given have the same roots and merge state
+func workingSetsEqual(left, right *doltdb.WorkingSet) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+	return rootsEqual(left.WorkingRoot(), right.WorkingRoot()) &&
+		rootsEqual(left.StagedRoot(), right.StagedRoot()) &&
+		mergeStatesEqual(left.MergeState(), right.MergeState()) &&
+		rebaseStatesEqual(left.RebaseState(), right.RebaseState())
+}
+
+// mergeStatesEqual returns whether the two merge states given are for the same merge, compared by value since a
+// working set's merge state is copied whenever the working set is changed
+func mergeStatesEqual(left, right *doltdb.MergeState) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+	return commitsEqual(left.Commit(), right.Commit()) &&
+		left.CommitSpecStr() == right.CommitSpecStr() &&
+		left.IsCherryPick() == right.IsCherryPick() &&
+		optionalRootsEqual(left.PreMergeWorkingRoot(), right.PreMergeWorkingRoot()) &&
+		slices.Equal(left.TablesWithSchemaConflicts(), right.TablesWithSchemaConflicts()) &&
+		slices.Equal(left.MergedTables(), right.MergedTables())
+}
+
+// rebaseStatesEqual returns whether the two rebase states given are for the same rebase, compared by value
+func rebaseStatesEqual(left, right *doltdb.RebaseState) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+	return left.Branch() == right.Branch() &&
+		commitsEqual(left.OntoCommit(), right.OntoCommit()) &&
+		optionalRootsEqual(left.PreRebaseWorkingRoot(), right.PreRebaseWorkingRoot()) &&
+		left.EmptyCommitHandling() == right.EmptyCommitHandling() &&
+		left.CommitBecomesEmptyHandling() == right.CommitBecomesEmptyHandling()
+}
+
+func commitsEqual(left, right *doltdb.Commit) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+
+	lh, err := left.HashOf()
+	if err != nil {
+		return false
+	}
+
+	rh, err := right.HashOf()
+	if err != nil {
+		return false
+	}
+
+	return lh == rh
+}
+
+// optionalRootsEqual is like rootsEqual, but two missing roots are equal
+func optionalRootsEqual(left, right doltdb.RootValue) bool {
+	if left == nil && right == nil {
+		return true
+	}
+	return rootsEqual(left, right)
+}
+
 func rootsEqual(left, right doltdb.RootValue) bool {
 	if left == nil || right == nil {
 		return false
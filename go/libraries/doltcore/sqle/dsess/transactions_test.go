@@ -0,0 +1,53 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+)
+
+func TestWorkingSetsEqual(t *testing.T) {
+	ctx := sql.NewContext(context.Background(), sql.WithSession(DefaultSession(emptyDatabaseProvider(), nil)))
+	b := newTestXaBranch(t, ctx)
+	head, err := b.db.ResolveCommitRef(ctx, ref.NewBranchRef("main"))
+	require.NoError(t, err)
+
+	assert.True(t, workingSetsEqual(b.base, b.base))
+	assert.False(t, workingSetsEqual(b.base, b.prepared))
+	assert.False(t, workingSetsEqual(b.base, nil))
+
+	// Merge and rebase states are compared by value, so a working set is equal to itself once it's read back
+	merging := b.base.StartMerge(head, "main")
+	assert.True(t, workingSetsEqual(merging, writeTestWorkingSet(t, ctx, b.db, merging)))
+	assert.False(t, workingSetsEqual(merging, b.base))
+	assert.False(t, workingSetsEqual(merging, b.base.StartMerge(head, "HEAD")))
+	assert.False(t, workingSetsEqual(merging, b.base.StartCherryPick(head, "main")))
+
+	rebasing, err := b.base.StartRebase(ctx, head, "main", b.base.WorkingRoot(), doltdb.ErrorOnEmptyCommit, doltdb.DropEmptyCommit)
+	require.NoError(t, err)
+	assert.True(t, workingSetsEqual(rebasing, writeTestWorkingSet(t, ctx, b.db, rebasing)))
+	assert.False(t, workingSetsEqual(rebasing, b.base))
+	other, err := b.base.StartRebase(ctx, head, "main", b.base.WorkingRoot(), doltdb.ErrorOnEmptyCommit, doltdb.KeepEmptyCommit)
+	require.NoError(t, err)
+	assert.False(t, workingSetsEqual(rebasing, other))
+}
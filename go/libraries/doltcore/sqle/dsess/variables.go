@@ -40,6 +40,7 @@ const (
 	CurrentBatchModeKey                  = "batch_mode"
 	DoltOverrideSchema                   = "dolt_override_schema"
 	AllowCommitConflicts                 = "dolt_allow_commit_conflicts"
+	DeferForeignKeyChecks                = "dolt_defer_foreign_key_checks"
 	ReplicateToRemote                    = "dolt_replicate_to_remote"
 	ReadReplicaRemote                    = "dolt_read_replica_remote"
 	ReadReplicaForcePull                 = "dolt_read_replica_force_pull"
@@ -65,6 +66,28 @@ const (
 	DoltStatsAutoRefreshInterval  = "dolt_stats_auto_refresh_interval"
 	DoltStatsMemoryOnly           = "dolt_stats_memory_only"
 	DoltStatsBranches             = "dolt_stats_branches"
+
+	DoltGCRetentionDays = "dolt_gc_retention_days"
+
+	DoltDroppedDatabaseRetentionDays = "dolt_dropped_database_retention_days"
+
+	DoltAutoIncrementBlockSize = "dolt_auto_increment_block_size"
+
+	DoltQueryCacheSize = "dolt_query_cache_size"
+
+	DoltParallelScanWorkers = "dolt_parallel_scan_workers"
+
+	DoltSortBufferSize = "dolt_sort_buffer_size"
+
+	DoltJoinBufferSize = "dolt_join_buffer_size"
+
+	DoltQueryMemoryLimit = "dolt_query_memory_limit"
+
+	DoltWindowWorkers = "dolt_window_workers"
+
+	DoltReadAheadDepth = "dolt_read_ahead_depth"
+
+	DoltStorageScrubInterval = "dolt_storage_scrub_interval"
 )
 
 const URLTemplateDatabasePlaceholder = "{database}"
@@ -0,0 +1,733 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+// XID identifies an XA transaction. It is made up of a global transaction identifier, a branch qualifier and a
+// format identifier, as chosen by the external transaction manager coordinating the transaction.
+type XID struct {
+	Gtrid    string
+	Bqual    string
+	FormatID int64
+}
+
+func (x XID) String() string {
+	return fmt.Sprintf("%q,%q,%d", x.Gtrid, x.Bqual, x.FormatID)
+}
+
+// commitFormatID is the format ID of the XIDs of transactions which write more than one working set, which are
+// committed with a two-phase commit using the same storage as prepared XA transactions. Their gtrid is random, and
+// their bqual is the number of working sets they write. XA transactions can't use it.
+const commitFormatID int64 = 0x646f6c74
+
+// newCommitXid returns a new XID for a two-phase commit of |branches| working sets.
+func newCommitXid(branches int) XID {
+	return XID{Gtrid: uuid.NewString(), Bqual: strconv.Itoa(branches), FormatID: commitFormatID}
+}
+
+// xaState is the state of the XA transaction associated with a session.
+type xaState string
+
+const (
+	xaActive xaState = "ACTIVE"
+	xaIdle   xaState = "IDLE"
+)
+
+// xaTransaction is the XA transaction a session is working on, between XA START and XA PREPARE.
+type xaTransaction struct {
+	xid   XID
+	state xaState
+}
+
+var ErrXaNota = errors.New("XAER_NOTA: Unknown XID")
+
+var ErrXaOutside = errors.New("XAER_OUTSIDE: Some work is done outside global transaction")
+
+var ErrXaDupid = errors.New("XAER_DUPID: The XID already exists")
+
+var ErrXaInval = errors.New("XAER_INVAL: Invalid arguments were given")
+
+// ErrXaBranchPrepared is returned when committing a change to a branch whose working set is about to be written by a
+// prepared XA transaction, which must be committed or rolled back first.
+var ErrXaBranchPrepared = errors.New("the working set of this branch is locked by a prepared XA transaction, which must be committed or rolled back first")
+
+// ErrXaBranchChanged is returned when committing a prepared XA transaction whose branches have been written since it
+// was prepared, which only other processes can do.
+var ErrXaBranchChanged = errors.New("XAER_RMERR: the working set of a branch written by this XA transaction has changed since it was prepared, so it can only be rolled back")
+
+// xaRecoverAdminPrivilege is the privilege needed to list the prepared XA transactions, and to commit or roll back the
+// ones prepared by other users.
+const xaRecoverAdminPrivilege = "xa_recover_admin"
+
+// ErrXaRmfail returns the error for a statement which isn't allowed while the session's XA transaction is in |state|.
+func ErrXaRmfail(state string) error {
+	return fmt.Errorf("XAER_RMFAIL: The command cannot be executed when global transaction is in the  %s state", state)
+}
+
+// preparedXaTransaction is an XA transaction which has been prepared, but not yet committed or rolled back.
+type preparedXaTransaction struct {
+	xid XID
+	// user is the user who prepared the transaction
+	user     string
+	branches []*preparedXaBranch
+	// busy is set while a session commits or rolls back the transaction
+	busy bool
+}
+
+// preparedXaBranch is the working set of a branch which a prepared XA transaction writes when it's committed.
+type preparedXaBranch struct {
+	db    *doltdb.DoltDB
+	wsRef ref.WorkingSetRef
+	// base is the working set of the branch when the transaction was prepared, which it replaces. It's empty if the
+	// branch had no working set.
+	base *doltdb.WorkingSet
+	// prepared is the working set the transaction writes
+	prepared *doltdb.WorkingSet
+}
+
+// xaRefPrefix is the prefix of the names of the working sets which store prepared XA transactions. A branch's
+// prepared working set and the working set it replaces are stored as xa/<xid>/prepared/<branch working set> and
+// xa/<xid>/base/<branch working set>, so that prepared transactions survive restarts.
+const xaRefPrefix = "xa/"
+
+const (
+	xaRefPrepared = "prepared"
+	xaRefBase     = "base"
+)
+
+// xaRefKey returns the encoding of |xid| in the names of the working sets storing it.
+func xaRefKey(xid XID) string {
+	return hex.EncodeToString([]byte(xid.Gtrid)) + "." + hex.EncodeToString([]byte(xid.Bqual)) + "." + strconv.FormatInt(xid.FormatID, 10)
+}
+
+func xaRef(xid XID, kind string, wsRef ref.WorkingSetRef) ref.WorkingSetRef {
+	return ref.NewWorkingSetRef(xaRefPrefix + xaRefKey(xid) + "/" + kind + "/" + wsRef.GetPath())
+}
+
+// parseXaRef returns the XID, the kind and the branch working set of the XA working set |r|.
+func parseXaRef(r ref.WorkingSetRef) (XID, string, ref.WorkingSetRef, error) {
+	parts := strings.SplitN(strings.TrimPrefix(r.GetPath(), xaRefPrefix), "/", 3)
+	if len(parts) != 3 {
+		return XID{}, "", ref.WorkingSetRef{}, fmt.Errorf("invalid XA working set: %s", r.String())
+	}
+	key := strings.Split(parts[0], ".")
+	if len(key) != 3 {
+		return XID{}, "", ref.WorkingSetRef{}, fmt.Errorf("invalid XA working set: %s", r.String())
+	}
+	gtrid, err := hex.DecodeString(key[0])
+	if err != nil {
+		return XID{}, "", ref.WorkingSetRef{}, err
+	}
+	bqual, err := hex.DecodeString(key[1])
+	if err != nil {
+		return XID{}, "", ref.WorkingSetRef{}, err
+	}
+	formatID, err := strconv.ParseInt(key[2], 10, 64)
+	if err != nil {
+		return XID{}, "", ref.WorkingSetRef{}, err
+	}
+	return XID{Gtrid: string(gtrid), Bqual: string(bqual), FormatID: formatID}, parts[1], ref.NewWorkingSetRef(parts[2]), nil
+}
+
+// xaBranchKey identifies the working set of a branch in a database.
+type xaBranchKey struct {
+	db    *doltdb.DoltDB
+	wsRef string
+}
+
+// preparedXaTransactions holds the XA transactions prepared by every session on this server, since a prepared
+// transaction is no longer associated with the session which prepared it and may be committed or rolled back by any
+// session. Prepared transactions are stored in the databases they write, and are loaded from each database the first
+// time it's used.
+var preparedXaTransactions = &xaRegistry{
+	loaded:   make(map[*doltdb.DoltDB]struct{}),
+	txs:      make(map[XID]*preparedXaTransaction),
+	reserved: make(map[xaBranchKey]XID),
+}
+
+type xaRegistry struct {
+	mu     sync.Mutex
+	loaded map[*doltdb.DoltDB]struct{}
+	txs    map[XID]*preparedXaTransaction
+	// reserved maps the working set of every branch written by a prepared transaction to its XID. No other
+	// transaction may commit to these branches until it's committed or rolled back, so that committing it can't fail
+	// because of a conflict.
+	reserved map[xaBranchKey]XID
+}
+
+// loadLocked loads the prepared transactions stored in |db|, unless they've already been loaded. Must be called with
+// |r.mu| held.
+func (r *xaRegistry) loadLocked(ctx *sql.Context, db *doltdb.DoltDB) error {
+	if _, ok := r.loaded[db]; ok {
+		return nil
+	}
+
+	refs, err := db.GetWorkingSetRefsWithPrefix(ctx, xaRefPrefix)
+	if err != nil {
+		return err
+	}
+	for _, wsRef := range refs {
+		xid, kind, branchRef, err := parseXaRef(wsRef)
+		if err != nil {
+			return err
+		}
+		ws, err := db.ResolveWorkingSet(ctx, wsRef)
+		if err != nil {
+			return err
+		}
+
+		tx, ok := r.txs[xid]
+		if !ok {
+			tx = &preparedXaTransaction{xid: xid}
+			r.txs[xid] = tx
+		}
+		var branch *preparedXaBranch
+		for _, b := range tx.branches {
+			if b.db == db && b.wsRef == branchRef {
+				branch = b
+			}
+		}
+		if branch == nil {
+			branch = &preparedXaBranch{db: db, wsRef: branchRef, base: doltdb.EmptyWorkingSet(branchRef)}
+			tx.branches = append(tx.branches, branch)
+			r.reserved[xaBranchKey{db: db, wsRef: branchRef.String()}] = xid
+		}
+
+		switch kind {
+		case xaRefPrepared:
+			branch.prepared = ws
+			tx.user = ws.Meta().Name
+		case xaRefBase:
+			branch.base = ws
+		default:
+			return fmt.Errorf("invalid XA working set: %s", wsRef.String())
+		}
+	}
+
+	r.loaded[db] = struct{}{}
+	return nil
+}
+
+// loadAll loads the prepared transactions stored in every database of |provider|, and then finishes or rolls back
+// any two-phase commits which were interrupted. See recoverInterruptedCommits.
+func (r *xaRegistry) loadAll(ctx *sql.Context, provider DoltDatabaseProvider) error {
+	interrupted, err := func() ([]*preparedXaTransaction, error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, db := range provider.DoltDatabases() {
+			for _, ddb := range db.DoltDatabases() {
+				if err := r.loadLocked(ctx, ddb); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		var interrupted []*preparedXaTransaction
+		for _, tx := range r.txs {
+			if tx.xid.FormatID == commitFormatID && !tx.busy {
+				tx.busy = true
+				interrupted = append(interrupted, tx)
+			}
+		}
+		return interrupted, nil
+	}()
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range interrupted {
+		err := recoverInterruptedCommit(ctx, tx)
+		if err != nil {
+			logrus.Warnf("failed to recover interrupted transaction %s, which stays prepared: %s", tx.xid.String(), err.Error())
+		}
+		r.release(tx, err == nil)
+	}
+	return nil
+}
+
+// RecoverInterruptedCommits finishes or rolls back the two-phase commits of transactions writing more than one working
+// set which were interrupted, for example by a crash, in every database of |provider|. This is also done the first
+// time XA transactions are used, but should be done before serving any queries, since the branches they write can't be
+// committed to until it's done.
+func RecoverInterruptedCommits(ctx *sql.Context, provider DoltDatabaseProvider) error {
+	return preparedXaTransactions.loadAll(ctx, provider)
+}
+
+// recoverInterruptedCommit finishes the interrupted two-phase commit |tx| if every working set it writes was stored
+// before it was interrupted, or if any of them was already written, which only happens once they've all been stored.
+// Otherwise, it was interrupted before it was committed, and the working sets which were stored are deleted.
+func recoverInterruptedCommit(ctx *sql.Context, tx *preparedXaTransaction) (err error) {
+	gcCtx, groupCommit := nbs.WithGroupCommit(ctx)
+	defer func() {
+		if werr := groupCommit.Wait(gcCtx); err == nil {
+			err = werr
+		}
+	}()
+	ctx = ctx.WithContext(gcCtx)
+
+	var rscs []doltdb.ReplicationStatusController
+	defer func() {
+		for _, rsc := range rscs {
+			WaitForReplicationController(ctx, rsc)
+		}
+	}()
+
+	txLock.Lock()
+	defer txLock.Unlock()
+
+	stored := 0
+	for _, b := range tx.branches {
+		if b.prepared != nil {
+			stored++
+		}
+	}
+	committed := tx.xid.Bqual == strconv.Itoa(stored)
+	for _, b := range tx.branches {
+		if committed {
+			break
+		}
+		if b.prepared == nil {
+			// only the working set the branch would replace was stored
+			continue
+		}
+		current, err := resolveXaBranch(ctx, b)
+		if err != nil {
+			return err
+		}
+		committed = sameRoots(current, b.prepared) && !sameRoots(b.base, b.prepared)
+	}
+
+	if committed {
+		if _, err := writePreparedXaTransaction(ctx, tx, &rscs); err != nil {
+			return err
+		}
+		logrus.Infof("finished writing interrupted transaction %s", tx.xid.String())
+	} else {
+		logrus.Infof("rolling back interrupted transaction %s", tx.xid.String())
+	}
+	return deleteXaRefs(ctx, tx.xid, tx.branches)
+}
+
+// checkNotReserved returns ErrXaBranchPrepared if the working set |wsRef| of |db| is written by a prepared XA
+// transaction.
+func (r *xaRegistry) checkNotReserved(ctx *sql.Context, db *doltdb.DoltDB, wsRef ref.WorkingSetRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.loadLocked(ctx, db); err != nil {
+		return err
+	}
+	if _, ok := r.reserved[xaBranchKey{db: db, wsRef: wsRef.String()}]; ok {
+		return ErrXaBranchPrepared
+	}
+	return nil
+}
+
+// add stores |tx| in the databases it writes, and reserves its branches.
+func (r *xaRegistry) add(ctx *sql.Context, tx *preparedXaTransaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.txs[tx.xid]; exists {
+		return ErrXaDupid
+	}
+
+	meta := &datas.WorkingSetMeta{
+		Name:        tx.user,
+		Email:       ctx.Session.Client().Address,
+		Timestamp:   uint64(time.Now().Unix()),
+		Description: "XA PREPARE " + tx.xid.String(),
+	}
+	var written []*preparedXaBranch
+	for _, b := range tx.branches {
+		// The prepared working set is stored last, so that a branch is only ever stored with both.
+		var err error
+		if b.base.WorkingRoot() != nil {
+			err = b.db.UpdateWorkingSet(ctx, xaRef(tx.xid, xaRefBase, b.wsRef), b.base, hash.Hash{}, meta, nil)
+		}
+		if err == nil {
+			err = b.db.UpdateWorkingSet(ctx, xaRef(tx.xid, xaRefPrepared, b.wsRef), b.prepared, hash.Hash{}, meta, nil)
+		}
+		if err != nil {
+			if derr := deleteXaRefs(ctx, tx.xid, append(written, b)); derr != nil {
+				return fmt.Errorf("%w; additionally, failed to delete the XA transaction's stored working sets: %s", err, derr.Error())
+			}
+			return err
+		}
+		written = append(written, b)
+	}
+
+	r.txs[tx.xid] = tx
+	for _, b := range tx.branches {
+		r.reserved[xaBranchKey{db: b.db, wsRef: b.wsRef.String()}] = tx.xid
+	}
+	return nil
+}
+
+// acquire marks the prepared transaction |xid| as being committed or rolled back by the current session, and returns
+// it. It must be released with release.
+func (r *xaRegistry) acquire(ctx *sql.Context, provider DoltDatabaseProvider, xid XID) (*preparedXaTransaction, error) {
+	if err := r.loadAll(ctx, provider); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx, ok := r.txs[xid]
+	if !ok {
+		return nil, ErrXaNota
+	}
+	if tx.user != ctx.Session.Client().User && !provider.UserHasPrivileges(ctx, sql.NewDynamicPrivilegedOperation(xaRecoverAdminPrivilege)) {
+		return nil, sql.ErrPrivilegeCheckFailed.New(ctx.Session.Client().User)
+	}
+	if tx.busy {
+		return nil, ErrXaRmfail("PREPARED")
+	}
+	tx.busy = true
+	return tx, nil
+}
+
+// release ends the commit or rollback of |tx|. If |done|, the transaction is removed, and its branches may be
+// written by other transactions again.
+func (r *xaRegistry) release(tx *preparedXaTransaction, done bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx.busy = false
+	if !done {
+		return
+	}
+	delete(r.txs, tx.xid)
+	for _, b := range tx.branches {
+		delete(r.reserved, xaBranchKey{db: b.db, wsRef: b.wsRef.String()})
+	}
+}
+
+// exists returns whether a transaction with the XID given has been prepared.
+func (r *xaRegistry) exists(ctx *sql.Context, provider DoltDatabaseProvider, xid XID) (bool, error) {
+	if err := r.loadAll(ctx, provider); err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.txs[xid]
+	return ok, nil
+}
+
+// deleteXaRefs deletes the working sets storing the branches |branches| of the prepared transaction |xid|.
+func deleteXaRefs(ctx *sql.Context, xid XID, branches []*preparedXaBranch) error {
+	var errs []error
+	for _, b := range branches {
+		for _, kind := range []string{xaRefPrepared, xaRefBase} {
+			err := b.db.DeleteWorkingSet(ctx, xaRef(xid, kind, b.wsRef))
+			if err != nil && !errors.Is(err, doltdb.ErrWorkingSetNotFound) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// XaStart begins a new XA transaction with the XID given, as XA START does.
+func (d *DoltSession) XaStart(ctx *sql.Context, xid XID) error {
+	if d.xa != nil {
+		return ErrXaRmfail(string(d.xa.state))
+	}
+	if ctx.GetIgnoreAutoCommit() {
+		// An explicit transaction is already in progress
+		return ErrXaOutside
+	}
+	if xid.FormatID == commitFormatID {
+		return ErrXaInval
+	}
+
+	exists, err := preparedXaTransactions.exists(ctx, d.provider, xid)
+	if err != nil {
+		return err
+	} else if exists {
+		return ErrXaDupid
+	}
+
+	if tx := ctx.GetTransaction(); tx != nil {
+		if err := d.CommitTransaction(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	tx, err := d.StartTransaction(ctx, sql.ReadWrite)
+	if err != nil {
+		return err
+	}
+	ctx.SetTransaction(tx)
+	// until this transaction is prepared, committed or rolled back, don't begin or commit any transactions automatically
+	ctx.SetIgnoreAutoCommit(true)
+
+	d.xa = &xaTransaction{xid: xid, state: xaActive}
+	return nil
+}
+
+// XaEnd ends the work done in the XA transaction with the XID given, as XA END does.
+func (d *DoltSession) XaEnd(ctx *sql.Context, xid XID) error {
+	if d.xa == nil || d.xa.xid != xid {
+		return ErrXaNota
+	}
+	if d.xa.state != xaActive {
+		return ErrXaRmfail(string(d.xa.state))
+	}
+	d.xa.state = xaIdle
+	return nil
+}
+
+// XaPrepare prepares the XA transaction with the XID given to be committed, as XA PREPARE does. Once prepared, the
+// transaction is detached from this session, and may be committed or rolled back from any session with XaCommit or
+// XaRollback. If the transaction can't be prepared, it is rolled back.
+//
+// Preparing a transaction merges it with the transactions committed since it started, and stores the resulting
+// working set of each branch it writes in the branch's database, so that it survives restarts. Until it's committed
+// or rolled back, other transactions can't commit to those branches, so that committing it can't fail.
+func (d *DoltSession) XaPrepare(ctx *sql.Context, xid XID) (err error) {
+	if d.xa == nil || d.xa.xid != xid {
+		return ErrXaNota
+	}
+	if d.xa.state != xaIdle {
+		return ErrXaRmfail(string(d.xa.state))
+	}
+
+	// Whether or not it is prepared successfully, the transaction is no longer this session's.
+	tx := ctx.GetTransaction()
+	d.xa = nil
+	defer func() {
+		d.clear()
+		ctx.SetTransaction(nil)
+		ctx.SetIgnoreAutoCommit(false)
+	}()
+
+	dtx, ok := tx.(*DoltTransaction)
+	if !ok {
+		return fmt.Errorf("expected a DoltTransaction")
+	}
+
+	pending, err := dtx.pendingWorkingSets(ctx, d.dirtyWorkingSets())
+	if err != nil {
+		return err
+	}
+
+	txLock.Lock()
+	defer txLock.Unlock()
+	toWrite, err := dtx.preparePendingWorkingSets(ctx, pending)
+	if err != nil {
+		return err
+	}
+
+	prepared := &preparedXaTransaction{xid: xid, user: ctx.Session.Client().User}
+	for i, p := range pending {
+		prepared.branches = append(prepared.branches, &preparedXaBranch{
+			db:       p.db,
+			wsRef:    p.workingSet.Ref(),
+			base:     p.replaced,
+			prepared: toWrite[i],
+		})
+	}
+	return preparedXaTransactions.add(ctx, prepared)
+}
+
+// XaCommit commits the XA transaction with the XID given, as XA COMMIT does. If |onePhase| is true, the transaction
+// must be this session's, and is committed without being prepared first. Otherwise, it must be a prepared transaction.
+// A prepared transaction which fails to commit stays prepared.
+func (d *DoltSession) XaCommit(ctx *sql.Context, xid XID, onePhase bool) error {
+	if onePhase {
+		if d.xa == nil || d.xa.xid != xid {
+			return ErrXaNota
+		}
+		if d.xa.state != xaIdle {
+			return ErrXaRmfail(string(d.xa.state))
+		}
+
+		d.xa = nil
+		tx := ctx.GetTransaction()
+		ctx.SetIgnoreAutoCommit(false)
+		if tx == nil {
+			return nil
+		}
+		err := d.CommitTransaction(ctx, tx)
+		if err != nil {
+			_ = d.Rollback(ctx, tx)
+		}
+		ctx.SetTransaction(nil)
+		return err
+	}
+
+	if d.xa != nil {
+		return ErrXaRmfail(string(d.xa.state))
+	}
+
+	prepared, err := preparedXaTransactions.acquire(ctx, d.provider, xid)
+	if err != nil {
+		return err
+	}
+	err = commitPreparedXaTransaction(ctx, prepared)
+	preparedXaTransactions.release(prepared, err == nil)
+	return err
+}
+
+// commitPreparedXaTransaction writes the working sets of |prepared| over the ones they were prepared to replace, and
+// then deletes the stored transaction.
+func commitPreparedXaTransaction(ctx *sql.Context, prepared *preparedXaTransaction) (err error) {
+	gcCtx, groupCommit := nbs.WithGroupCommit(ctx)
+	defer func() {
+		if werr := groupCommit.Wait(gcCtx); err == nil {
+			err = werr
+		}
+	}()
+	ctx = ctx.WithContext(gcCtx)
+
+	var rscs []doltdb.ReplicationStatusController
+	defer func() {
+		for _, rsc := range rscs {
+			WaitForReplicationController(ctx, rsc)
+		}
+	}()
+
+	txLock.Lock()
+	defer txLock.Unlock()
+
+	if _, err := writePreparedXaTransaction(ctx, prepared, &rscs); err != nil {
+		return err
+	}
+
+	// The transaction is committed, so failing to delete it only leaves stale working sets behind.
+	if err := deleteXaRefs(ctx, prepared.xid, prepared.branches); err != nil {
+		logrus.Warnf("failed to delete the stored working sets of committed XA transaction %s: %s", prepared.xid.String(), err.Error())
+	}
+	return nil
+}
+
+// writePreparedXaTransaction writes the working sets of |prepared| over the ones they were prepared to replace, and
+// returns how many it wrote. Branches whose working sets have already been written, by an earlier attempt which
+// failed or was interrupted, are skipped, so a prepared transaction which fails to be written can be written again
+// later. Must be called with |txLock| held.
+func writePreparedXaTransaction(ctx *sql.Context, prepared *preparedXaTransaction, rscs *[]doltdb.ReplicationStatusController) (int, error) {
+	// No transaction in this process can have written these branches, since they're reserved, but another process
+	// might have.
+	var pending []*pendingWorkingSet
+	for _, b := range prepared.branches {
+		if b.prepared == nil {
+			return 0, fmt.Errorf("the working set prepared for %s by transaction %s is missing", b.wsRef.String(), prepared.xid.String())
+		}
+		current, err := resolveXaBranch(ctx, b)
+		if err != nil {
+			return 0, err
+		}
+		if sameRoots(current, b.prepared) {
+			continue
+		} else if !sameRoots(current, b.base) {
+			return 0, ErrXaBranchChanged
+		}
+		pending = append(pending, &pendingWorkingSet{db: b.db, workingSet: b.prepared, replaced: current})
+	}
+
+	var tx DoltTransaction
+	for i, p := range pending {
+		if err := tx.writeWorkingSet(ctx, p, p.workingSet, rscs); err != nil {
+			return i, err
+		}
+	}
+	return len(pending), nil
+}
+
+// resolveXaBranch returns the current working set of the branch |b|, which is empty if it has none.
+func resolveXaBranch(ctx *sql.Context, b *preparedXaBranch) (*doltdb.WorkingSet, error) {
+	current, err := b.db.ResolveWorkingSet(ctx, b.wsRef)
+	if errors.Is(err, doltdb.ErrWorkingSetNotFound) {
+		return doltdb.EmptyWorkingSet(b.wsRef), nil
+	}
+	return current, err
+}
+
+// sameRoots returns whether |left| and |right| have the same working and staged roots, or are both empty. Working
+// sets stored for XA transactions have the same roots as the working sets they were stored for, but not the same
+// hash, since they're stored with their own metadata.
+func sameRoots(left, right *doltdb.WorkingSet) bool {
+	if left.WorkingRoot() == nil || right.WorkingRoot() == nil {
+		return left.WorkingRoot() == nil && right.WorkingRoot() == nil
+	}
+	return workingAndStagedEqual(left, right)
+}
+
+// XaRollback rolls back the XA transaction with the XID given, as XA ROLLBACK does. The transaction may either be
+// this session's or a prepared transaction.
+func (d *DoltSession) XaRollback(ctx *sql.Context, xid XID) error {
+	if d.xa != nil {
+		if d.xa.xid != xid {
+			return ErrXaNota
+		}
+		if d.xa.state != xaIdle {
+			return ErrXaRmfail(string(d.xa.state))
+		}
+
+		d.xa = nil
+		d.clear()
+		ctx.SetTransaction(nil)
+		ctx.SetIgnoreAutoCommit(false)
+		return nil
+	}
+
+	prepared, err := preparedXaTransactions.acquire(ctx, d.provider, xid)
+	if err != nil {
+		return err
+	}
+	err = deleteXaRefs(ctx, xid, prepared.branches)
+	preparedXaTransactions.release(prepared, err == nil)
+	return err
+}
+
+// XaRecover returns the XIDs of all the prepared XA transactions on this server, as XA RECOVER does. Like MySQL, this
+// requires the XA_RECOVER_ADMIN privilege.
+func (d *DoltSession) XaRecover(ctx *sql.Context) ([]XID, error) {
+	if !d.provider.UserHasPrivileges(ctx, sql.NewDynamicPrivilegedOperation(xaRecoverAdminPrivilege)) {
+		return nil, sql.ErrPrivilegeCheckFailed.New(ctx.Session.Client().User)
+	}
+	if err := preparedXaTransactions.loadAll(ctx, d.provider); err != nil {
+		return nil, err
+	}
+
+	preparedXaTransactions.mu.Lock()
+	defer preparedXaTransactions.mu.Unlock()
+	xids := make([]XID, 0, len(preparedXaTransactions.txs))
+	for xid := range preparedXaTransactions.txs {
+		xids = append(xids, xid)
+	}
+	sort.Slice(xids, func(i, j int) bool {
+		return xids[i].String() < xids[j].String()
+	})
+	return xids, nil
+}
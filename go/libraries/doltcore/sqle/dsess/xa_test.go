@@ -0,0 +1,127 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRecoverInterruptedCommit(t *testing.T) {
+	tests := []struct {
+		name string
+		// stored is the number of branches whose working sets were stored before the commit was interrupted
+		stored int
+		// written is the number of branches whose working sets were written before the commit was interrupted
+		written  int
+		finished bool
+	}{
+		{
+			name:     "interrupted while storing",
+			stored:   1,
+			finished: false,
+		},
+		{
+			name:     "interrupted after storing",
+			stored:   2,
+			finished: true,
+		},
+		{
+			name:     "interrupted while writing",
+			stored:   2,
+			written:  1,
+			finished: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := sql.NewContext(context.Background(), sql.WithSession(DefaultSession(emptyDatabaseProvider(), nil)))
+			tx := &preparedXaTransaction{xid: newCommitXid(2), user: "root", busy: true}
+			for i := 0; i < 2; i++ {
+				tx.branches = append(tx.branches, newTestXaBranch(t, ctx))
+			}
+
+			stored := &preparedXaTransaction{xid: tx.xid, user: tx.user, branches: tx.branches[:test.stored], busy: true}
+			require.NoError(t, preparedXaTransactions.add(ctx, stored))
+			defer preparedXaTransactions.release(stored, true)
+			for _, b := range tx.branches[:test.written] {
+				writeTestWorkingSet(t, ctx, b.db, b.prepared)
+			}
+
+			require.NoError(t, recoverInterruptedCommit(ctx, stored))
+
+			for _, b := range tx.branches {
+				current, err := b.db.ResolveWorkingSet(ctx, b.wsRef)
+				require.NoError(t, err)
+				if test.finished {
+					assert.True(t, sameRoots(current, b.prepared))
+				} else {
+					assert.True(t, sameRoots(current, b.base))
+				}
+
+				refs, err := b.db.GetWorkingSetRefsWithPrefix(ctx, xaRefPrefix)
+				require.NoError(t, err)
+				assert.Empty(t, refs)
+			}
+		})
+	}
+}
+
+// newTestXaBranch returns a branch of a new database, with a working set prepared to change its collation.
+func newTestXaBranch(t *testing.T, ctx *sql.Context) *preparedXaBranch {
+	db, err := doltdb.LoadDoltDB(ctx, types.Format_Default, doltdb.InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	require.NoError(t, db.WriteEmptyRepo(ctx, "main", "test", "test@example.com"))
+
+	roots, err := db.ResolveBranchRoots(ctx, ref.NewBranchRef("main"))
+	require.NoError(t, err)
+	wsRef, err := ref.WorkingSetRefForHead(ref.NewBranchRef("main"))
+	require.NoError(t, err)
+	base := writeTestWorkingSet(t, ctx, db, doltdb.EmptyWorkingSet(wsRef).WithWorkingRoot(roots.Head).WithStagedRoot(roots.Head))
+
+	root, err := roots.Head.SetCollation(ctx, schema.Collation_utf8mb4_general_ci)
+	require.NoError(t, err)
+	return &preparedXaBranch{db: db, wsRef: wsRef, base: base, prepared: base.WithWorkingRoot(root)}
+}
+
+// writeTestWorkingSet writes |ws| to |db| and returns it as written.
+func writeTestWorkingSet(t *testing.T, ctx *sql.Context, db *doltdb.DoltDB, ws *doltdb.WorkingSet) *doltdb.WorkingSet {
+	current, err := db.ResolveWorkingSet(ctx, ws.Ref())
+	var prevHash hash.Hash
+	if err == nil {
+		prevHash, err = current.HashOf()
+		require.NoError(t, err)
+	} else {
+		require.ErrorIs(t, err, doltdb.ErrWorkingSetNotFound)
+	}
+	require.NoError(t, db.UpdateWorkingSet(ctx, ws.Ref(), ws, prevHash, &datas.WorkingSetMeta{Name: "test"}, nil))
+
+	written, err := db.ResolveWorkingSet(ctx, ws.Ref())
+	require.NoError(t, err)
+	return written
+}
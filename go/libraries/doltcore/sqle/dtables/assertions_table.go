@@ -0,0 +1,300 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var DoltAssertionsSqlSchema sql.PrimaryKeySchema
+
+func init() {
+	DoltAssertionsSqlSchema, _ = sqlutil.FromDoltSchema("", doltdb.AssertionsTableName, doltdb.AssertionsSchema)
+}
+
+var _ sql.Table = (*AssertionsTable)(nil)
+var _ sql.UpdatableTable = (*AssertionsTable)(nil)
+var _ sql.DeletableTable = (*AssertionsTable)(nil)
+var _ sql.InsertableTable = (*AssertionsTable)(nil)
+var _ sql.ReplaceableTable = (*AssertionsTable)(nil)
+var _ sql.IndexAddressableTable = (*AssertionsTable)(nil)
+
+// AssertionsTable is the system table that stores the assertions of a database, named queries returning the rows of a
+// table which violate them. Assertions are checked before commits are made, and by the dolt_verify_assertions()
+// procedure.
+type AssertionsTable struct {
+	backingTable VersionableTable
+}
+
+func (dt *AssertionsTable) Name() string {
+	return doltdb.AssertionsTableName
+}
+
+func (dt *AssertionsTable) String() string {
+	return doltdb.AssertionsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the dolt_assertions system table.
+func (dt *AssertionsTable) Schema() sql.Schema {
+	return DoltAssertionsSqlSchema.Schema
+}
+
+func (dt *AssertionsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.
+func (dt *AssertionsTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	if dt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return index.SinglePartitionIterFromNomsMap(nil), nil
+	}
+	return dt.backingTable.Partitions(context)
+}
+
+func (dt *AssertionsTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	if dt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return sql.RowsToRowIter(), nil
+	}
+
+	return dt.backingTable.PartitionRows(context, partition)
+}
+
+// NewAssertionsTable creates a AssertionsTable
+func NewAssertionsTable(_ *sql.Context, backingTable VersionableTable) sql.Table {
+	return &AssertionsTable{backingTable: backingTable}
+}
+
+// NewEmptyAssertionsTable creates a AssertionsTable with no backing table
+func NewEmptyAssertionsTable(_ *sql.Context) sql.Table {
+	return &AssertionsTable{}
+}
+
+// Replacer returns a RowReplacer for this table. The RowReplacer will have Insert and optionally Delete called once
+// for each row, followed by a call to Close() when all rows have been processed.
+func (dt *AssertionsTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	return newAssertionsWriter(dt)
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (dt *AssertionsTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return newAssertionsWriter(dt)
+}
+
+// Inserter returns an Inserter for this table. The Inserter will get one call to Insert() for each row to be
+// inserted, and will end with a call to Close() to finalize the insert operation.
+func (dt *AssertionsTable) Inserter(*sql.Context) sql.RowInserter {
+	return newAssertionsWriter(dt)
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (dt *AssertionsTable) Deleter(*sql.Context) sql.RowDeleter {
+	return newAssertionsWriter(dt)
+}
+
+func (dt *AssertionsTable) LockedToRoot(ctx *sql.Context, root doltdb.RootValue) (sql.IndexAddressableTable, error) {
+	if dt.backingTable == nil {
+		return dt, nil
+	}
+	return dt.backingTable.LockedToRoot(ctx, root)
+}
+
+// IndexedAccess implements IndexAddressableTable, but AssertionsTables have no indexes.
+// Thus, this should never be called.
+func (dt *AssertionsTable) IndexedAccess(lookup sql.IndexLookup) sql.IndexedTable {
+	panic("Unreachable")
+}
+
+// GetIndexes implements IndexAddressableTable, but AssertionsTables have no indexes.
+func (dt *AssertionsTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
+	return nil, nil
+}
+
+func (dt *AssertionsTable) PreciseMatch() bool {
+	return true
+}
+
+var _ sql.RowReplacer = (*assertionsWriter)(nil)
+var _ sql.RowUpdater = (*assertionsWriter)(nil)
+var _ sql.RowInserter = (*assertionsWriter)(nil)
+var _ sql.RowDeleter = (*assertionsWriter)(nil)
+
+type assertionsWriter struct {
+	it                      *AssertionsTable
+	errDuringStatementBegin error
+	prevHash                *hash.Hash
+	tableWriter             dsess.TableWriter
+}
+
+func newAssertionsWriter(it *AssertionsTable) *assertionsWriter {
+	return &assertionsWriter{it, nil, nil, nil}
+}
+
+// Insert inserts the row given, returning an error if it cannot. Insert will be called once for each row to process
+// for the insert operation, which may involve many rows. After all rows in an operation have been processed, Close
+// is called.
+func (iw *assertionsWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	if err := iw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	if err := validateAssertionRow(r); err != nil {
+		return err
+	}
+	return iw.tableWriter.Insert(ctx, r)
+}
+
+// Update the given row. Provides both the old and new rows.
+func (iw *assertionsWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if err := iw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	if err := validateAssertionRow(new); err != nil {
+		return err
+	}
+	return iw.tableWriter.Update(ctx, old, new)
+}
+
+// Delete deletes the given row. Returns ErrDeleteRowNotFound if the row was not found. Delete will be called once for
+// each row to process for the delete operation, which may involve many rows. After all rows have been processed,
+// Close is called.
+func (iw *assertionsWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	if err := iw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return iw.tableWriter.Delete(ctx, r)
+}
+
+// StatementBegin is called before the first operation of a statement. Integrators should mark the state of the data
+// in some way that it may be returned to in the case of an error.
+func (iw *assertionsWriter) StatementBegin(ctx *sql.Context) {
+	dbName := ctx.GetCurrentDatabase()
+	dSess := dsess.DSessFromSess(ctx.Session)
+
+	// TODO: this needs to use a revision qualified name
+	roots, _ := dSess.GetRoots(ctx, dbName)
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		iw.errDuringStatementBegin = err
+		return
+	}
+	if !ok {
+		iw.errDuringStatementBegin = fmt.Errorf("no root value found in session")
+		return
+	}
+
+	prevHash, err := roots.Working.HashOf()
+	if err != nil {
+		iw.errDuringStatementBegin = err
+		return
+	}
+
+	iw.prevHash = &prevHash
+
+	found, err := roots.Working.HasTable(ctx, doltdb.TableName{Name: doltdb.AssertionsTableName})
+
+	if err != nil {
+		iw.errDuringStatementBegin = err
+		return
+	}
+
+	if !found {
+		// underlying table doesn't exist. Record this, then create the table.
+		newRootValue, err := doltdb.CreateEmptyTable(ctx, roots.Working, doltdb.TableName{Name: doltdb.AssertionsTableName}, doltdb.AssertionsSchema)
+		if err != nil {
+			iw.errDuringStatementBegin = err
+			return
+		}
+
+		if dbState.WorkingSet() == nil {
+			iw.errDuringStatementBegin = doltdb.ErrOperationNotSupportedInDetachedHead
+			return
+		}
+
+		// We use WriteSession.SetWorkingSet instead of DoltSession.SetWorkingRoot because we want to avoid modifying the root
+		// until the end of the transaction, but we still want the WriteSession to be able to find the newly
+		// created table.
+
+		if ws := dbState.WriteSession(); ws != nil {
+			err = ws.SetWorkingSet(ctx, dbState.WorkingSet().WithWorkingRoot(newRootValue))
+			if err != nil {
+				iw.errDuringStatementBegin = err
+				return
+			}
+		}
+
+		err = dSess.SetWorkingRoot(ctx, dbName, newRootValue)
+		if err != nil {
+			iw.errDuringStatementBegin = err
+			return
+		}
+	}
+
+	if ws := dbState.WriteSession(); ws != nil {
+		tableWriter, err := ws.GetTableWriter(ctx, doltdb.TableName{Name: doltdb.AssertionsTableName}, dbName, dSess.SetWorkingRoot)
+		if err != nil {
+			iw.errDuringStatementBegin = err
+			return
+		}
+		iw.tableWriter = tableWriter
+		tableWriter.StatementBegin(ctx)
+	}
+}
+
+// DiscardChanges is called if a statement encounters an error, and all current changes since the statement beginning
+// should be discarded.
+func (iw *assertionsWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	if iw.tableWriter != nil {
+		return iw.tableWriter.DiscardChanges(ctx, errorEncountered)
+	}
+	return nil
+}
+
+// StatementComplete is called after the last operation of the statement, indicating that it has successfully completed.
+// The mark set in StatementBegin may be removed, and a new one should be created on the next StatementBegin.
+func (iw *assertionsWriter) StatementComplete(ctx *sql.Context) error {
+	if iw.tableWriter != nil {
+		return iw.tableWriter.StatementComplete(ctx)
+	}
+	return nil
+}
+
+// Close finalizes the delete operation, persisting the result.
+func (iw assertionsWriter) Close(ctx *sql.Context) error {
+	if iw.tableWriter != nil {
+		return iw.tableWriter.Close(ctx)
+	}
+	return nil
+}
+
+// validateAssertionRow returns an error if |r| isn't a valid definition of an assertion.
+func validateAssertionRow(r sql.Row) error {
+	a := doltdb.Assertion{
+		Name:      r[0].(string),
+		TableName: r[1].(string),
+		Query:     r[2].(string),
+	}
+	return a.Validate()
+}
@@ -15,6 +15,7 @@
 package dtables
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
@@ -22,16 +23,19 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/types"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 const branchesDefaultRowCount = 10
 
 var _ sql.Table = (*BranchesTable)(nil)
 var _ sql.StatisticsTable = (*BranchesTable)(nil)
+var _ sql.ProjectedTable = (*BranchesTable)(nil)
 var _ sql.UpdatableTable = (*BranchesTable)(nil)
 var _ sql.DeletableTable = (*BranchesTable)(nil)
 var _ sql.InsertableTable = (*BranchesTable)(nil)
@@ -39,8 +43,9 @@ var _ sql.ReplaceableTable = (*BranchesTable)(nil)
 
 // BranchesTable is the system table that accesses branches
 type BranchesTable struct {
-	db     dsess.SqlDatabase
-	remote bool
+	db          dsess.SqlDatabase
+	remote      bool
+	projections []string
 }
 
 // NewBranchesTable creates a BranchesTable
@@ -50,7 +55,7 @@ func NewBranchesTable(_ *sql.Context, db dsess.SqlDatabase) sql.Table {
 
 // NewRemoteBranchesTable creates a BranchesTable with only remote refs
 func NewRemoteBranchesTable(_ *sql.Context, ddb dsess.SqlDatabase) sql.Table {
-	return &BranchesTable{ddb, true}
+	return &BranchesTable{db: ddb, remote: true}
 }
 
 func (bt *BranchesTable) DataLength(ctx *sql.Context) (uint64, error) {
@@ -86,6 +91,21 @@ func (bt *BranchesTable) String() string {
 
 // Schema is a sql.Table interface function that gets the sql.Schema of the branches system table
 func (bt *BranchesTable) Schema() sql.Schema {
+	sch := bt.fullSchema()
+	if bt.projections == nil {
+		return sch
+	}
+	projected := make(sql.Schema, 0, len(bt.projections))
+	for _, name := range bt.projections {
+		if i := sch.IndexOfColName(name); i >= 0 {
+			projected = append(projected, sch[i])
+		}
+	}
+	return projected
+}
+
+// fullSchema returns the schema of the table with every column, whatever its projections.
+func (bt *BranchesTable) fullSchema() sql.Schema {
 	tableName := doltdb.BranchesTableName
 	if bt.remote {
 		tableName = doltdb.RemoteBranchesTableName
@@ -102,10 +122,25 @@ func (bt *BranchesTable) Schema() sql.Schema {
 	if !bt.remote {
 		columns = append(columns, &sql.Column{Name: "remote", Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: true})
 		columns = append(columns, &sql.Column{Name: "branch", Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: true})
+		columns = append(columns, &sql.Column{Name: "ahead", Type: types.Uint64, Source: tableName, PrimaryKey: false, Nullable: true})
+		columns = append(columns, &sql.Column{Name: "behind", Type: types.Uint64, Source: tableName, PrimaryKey: false, Nullable: true})
 	}
 	return columns
 }
 
+// Projections implements sql.ProjectedTable
+func (bt *BranchesTable) Projections() []string {
+	return bt.projections
+}
+
+// WithProjections implements sql.ProjectedTable. How far each branch is ahead of and behind its upstream is counted by
+// walking their histories, so it's only done when the ahead or behind columns are projected.
+func (bt *BranchesTable) WithProjections(colNames []string) sql.Table {
+	nt := *bt
+	nt.projections = colNames
+	return &nt
+}
+
 // Collation implements the sql.Table interface.
 func (bt *BranchesTable) Collation() sql.CollationID {
 	return sql.Collation_Default
@@ -126,7 +161,12 @@ type BranchItr struct {
 	table    *BranchesTable
 	branches []string
 	commits  []*doltdb.Commit
+	txRoot   hash.Hash
 	idx      int
+	// cols are the indexes in the table's full schema of its projected columns
+	cols []int
+	// aheadBehind is whether the ahead or behind columns are projected
+	aheadBehind bool
 }
 
 // NewBranchItr creates a BranchItr from the current environment.
@@ -173,11 +213,20 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 		commits[i] = commit
 	}
 
+	sch, full := table.Schema(), table.fullSchema()
+	cols := make([]int, len(sch))
+	for i, col := range sch {
+		cols[i] = full.IndexOfColName(col.Name)
+	}
+
 	return &BranchItr{
-		table:    table,
-		branches: branchNames,
-		commits:  commits,
-		idx:      0,
+		table:       table,
+		branches:    branchNames,
+		commits:     commits,
+		txRoot:      txRoot,
+		idx:         0,
+		cols:        cols,
+		aheadBehind: sch.IndexOfColName("ahead") >= 0 || sch.IndexOfColName("behind") >= 0,
 	}, nil
 }
 
@@ -208,7 +257,7 @@ func (itr *BranchItr) Next(ctx *sql.Context) (sql.Row, error) {
 
 	remoteBranches := itr.table.remote
 	if remoteBranches {
-		return sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description), nil
+		return itr.project(sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description)), nil
 	} else {
 		branches, err := itr.table.db.DbData().Rsr.GetBranches()
 
@@ -218,13 +267,52 @@ func (itr *BranchItr) Next(ctx *sql.Context) (sql.Row, error) {
 
 		remoteName := ""
 		branchName := ""
+		var ahead, behind interface{}
 		branch, ok := branches.Get(name)
 		if ok {
 			remoteName = branch.Remote
 			branchName = branch.Merge.Ref.GetPath()
 		}
-		return sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, remoteName, branchName), nil
+		if ok && itr.aheadBehind {
+			ahead, behind, err = itr.upstreamAheadBehind(ctx, cm, ref.NewRemoteRef(remoteName, branchName))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return itr.project(sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, remoteName,
+			branchName, ahead, behind)), nil
+	}
+}
+
+// project returns the projected columns of |row|, which has every column of the table.
+func (itr *BranchItr) project(row sql.Row) sql.Row {
+	if itr.table.projections == nil {
+		return row
+	}
+	projected := make(sql.Row, len(itr.cols))
+	for i, col := range itr.cols {
+		projected[i] = row[col]
+	}
+	return projected
+}
+
+// upstreamAheadBehind returns the number of commits |cm| is ahead of and behind the remote tracking branch |upstream|,
+// or nils if the remote tracking branch hasn't been fetched or shares no history with |cm|.
+func (itr *BranchItr) upstreamAheadBehind(ctx *sql.Context, cm *doltdb.Commit, upstream ref.RemoteRef) (interface{}, interface{}, error) {
+	ddb := itr.table.db.DbData().Ddb
+	upstreamCm, err := ddb.ResolveCommitRefAtRoot(ctx, upstream, itr.txRoot)
+	if errors.Is(err, doltdb.ErrBranchNotFound) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	ahead, behind, err := commitwalk.CountAheadBehind(ctx, ddb, cm, upstreamCm)
+	if errors.Is(err, doltdb.ErrNoCommonAncestor) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
 	}
+	return ahead, behind, nil
 }
 
 // Close closes the iterator.
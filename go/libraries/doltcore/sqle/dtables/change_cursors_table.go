@@ -0,0 +1,75 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+// ChangeCursorsTable is a sql.Table implementation that implements a system table which shows the change cursors of a
+// database. Change cursors are written with the dolt_change_cursor stored procedure, and read with the dolt_changes
+// table function.
+type ChangeCursorsTable struct {
+	dbName string
+	ddb    *doltdb.DoltDB
+}
+
+var _ sql.Table = (*ChangeCursorsTable)(nil)
+
+// NewChangeCursorsTable creates a ChangeCursorsTable
+func NewChangeCursorsTable(dbName string, ddb *doltdb.DoltDB) sql.Table {
+	return &ChangeCursorsTable{dbName: dbName, ddb: ddb}
+}
+
+func (t *ChangeCursorsTable) Name() string {
+	return doltdb.ChangeCursorsTableName
+}
+
+func (t *ChangeCursorsTable) String() string {
+	return doltdb.ChangeCursorsTableName
+}
+
+func (t *ChangeCursorsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "name", Type: types.Text, Source: doltdb.ChangeCursorsTableName, PrimaryKey: true, DatabaseSource: t.dbName},
+		{Name: "branch", Type: types.Text, Source: doltdb.ChangeCursorsTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "commit_hash", Type: types.Text, Source: doltdb.ChangeCursorsTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "updated_at", Type: types.Datetime, Source: doltdb.ChangeCursorsTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+	}
+}
+
+func (t *ChangeCursorsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+func (t *ChangeCursorsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+func (t *ChangeCursorsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	cursors, err := t.ddb.GetChangeCursors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]sql.Row, len(cursors))
+	for i, c := range cursors {
+		rows[i] = sql.NewRow(c.Name, c.Branch, c.Commit.String(), c.UpdatedAt)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+// CloneStatusTable is a sql.Table implementation that implements a system table which shows the progress of the
+// databases cloned into the running server with dolt_clone(). Every database shows the clones for the whole server.
+type CloneStatusTable struct {
+	dbName string
+}
+
+var _ sql.Table = (*CloneStatusTable)(nil)
+
+// NewCloneStatusTable creates a CloneStatusTable
+func NewCloneStatusTable(dbName string) sql.Table {
+	return &CloneStatusTable{dbName: dbName}
+}
+
+func (t *CloneStatusTable) Name() string {
+	return doltdb.CloneStatusTableName
+}
+
+func (t *CloneStatusTable) String() string {
+	return doltdb.CloneStatusTableName
+}
+
+func (t *CloneStatusTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "database_name", Type: types.Text, Source: doltdb.CloneStatusTableName, PrimaryKey: true, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "remote_url", Type: types.Text, Source: doltdb.CloneStatusTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "status", Type: types.Text, Source: doltdb.CloneStatusTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "chunks_total", Type: types.Int64, Source: doltdb.CloneStatusTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "chunks_downloaded", Type: types.Int64, Source: doltdb.CloneStatusTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "started_at", Type: types.Datetime, Source: doltdb.CloneStatusTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "finished_at", Type: types.Datetime, Source: doltdb.CloneStatusTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+		{Name: "error", Type: types.Text, Source: doltdb.CloneStatusTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+	}
+}
+
+func (t *CloneStatusTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+func (t *CloneStatusTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+func (t *CloneStatusTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	statuses := sess.Provider().CloneStatuses()
+
+	rows := make([]sql.Row, len(statuses))
+	for i, s := range statuses {
+		var finishedAt, errStr interface{}
+		if !s.FinishedAt.IsZero() {
+			finishedAt = s.FinishedAt
+		}
+		if s.State == dsess.CloneFailed {
+			errStr = s.Error
+		}
+		rows[i] = sql.NewRow(s.Database, s.RemoteUrl, s.State, s.ChunksTotal, s.ChunksDownloaded, s.StartedAt, finishedAt, errStr)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/commitsign"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
@@ -77,6 +78,8 @@ func (dt *CommitsTable) Schema() sql.Schema {
 		{Name: "email", Type: types.Text, Source: doltdb.CommitsTableName, PrimaryKey: false, DatabaseSource: dt.dbName},
 		{Name: "date", Type: types.Datetime, Source: doltdb.CommitsTableName, PrimaryKey: false, DatabaseSource: dt.dbName},
 		{Name: "message", Type: types.Text, Source: doltdb.CommitsTableName, PrimaryKey: false, DatabaseSource: dt.dbName},
+		{Name: "signature_status", Type: types.Text, Source: doltdb.CommitsTableName, PrimaryKey: false, DatabaseSource: dt.dbName},
+		{Name: "metadata", Type: types.JSON, Source: doltdb.CommitsTableName, PrimaryKey: false, Nullable: true, DatabaseSource: dt.dbName},
 	}
 }
 
@@ -95,7 +98,11 @@ func (dt *CommitsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
 func (dt *CommitsTable) PartitionRows(ctx *sql.Context, p sql.Partition) (sql.RowIter, error) {
 	switch p := p.(type) {
 	case *doltdb.CommitPart:
-		return sql.RowsToRowIter(formatCommitTableRow(p.Hash(), p.Meta())), nil
+		row, err := formatCommitTableRow(ctx, &signatureVerifier{}, p.Hash(), p.Commit(), p.Meta())
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowsToRowIter(row), nil
 	default:
 		return NewCommitsRowItr(ctx, dt.ddb)
 	}
@@ -135,7 +142,8 @@ func (dt *CommitsTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLooku
 
 // CommitsRowItr is a sql.RowItr which iterates over each commit as if it's a row in the table.
 type CommitsRowItr struct {
-	itr doltdb.CommitItr
+	itr      doltdb.CommitItr
+	verifier *signatureVerifier
 }
 
 // NewCommitsRowItr creates a CommitsRowItr from the current environment.
@@ -145,7 +153,7 @@ func NewCommitsRowItr(ctx *sql.Context, ddb *doltdb.DoltDB) (CommitsRowItr, erro
 		return CommitsRowItr{}, err
 	}
 
-	return CommitsRowItr{itr: itr}, nil
+	return CommitsRowItr{itr: itr, verifier: &signatureVerifier{}}, nil
 }
 
 // Next retrieves the next row. It will return io.EOF if it's the last row.
@@ -165,7 +173,7 @@ func (itr CommitsRowItr) Next(ctx *sql.Context) (sql.Row, error) {
 		return nil, err
 	}
 
-	return formatCommitTableRow(h, meta), nil
+	return formatCommitTableRow(ctx, itr.verifier, h, cm, meta)
 }
 
 // Close closes the iterator.
@@ -173,6 +181,41 @@ func (itr CommitsRowItr) Close(*sql.Context) error {
 	return nil
 }
 
-func formatCommitTableRow(h hash.Hash, meta *datas.CommitMeta) sql.Row {
-	return sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description)
+func formatCommitTableRow(ctx *sql.Context, verifier *signatureVerifier, h hash.Hash, cm *doltdb.Commit, meta *datas.CommitMeta) (sql.Row, error) {
+	status, err := verifier.status(ctx, cm, meta)
+	if err != nil {
+		return nil, err
+	}
+	var metadata interface{}
+	if len(meta.Metadata) > 0 {
+		metadata, _, err = types.JSON.Convert(meta.Metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, string(status), metadata), nil
+}
+
+// signatureVerifier verifies the signatures of the commits in a scan of dolt_commits. The signing config is only
+// loaded once a signed commit is found, so that scans of unsigned commits don't read it.
+type signatureVerifier struct {
+	cfg *commitsign.Config
+}
+
+func (v *signatureVerifier) status(ctx *sql.Context, cm *doltdb.Commit, meta *datas.CommitMeta) (commitsign.Status, error) {
+	if meta.Signature == "" {
+		return commitsign.StatusUnsigned, nil
+	}
+	if v.cfg == nil {
+		cfg, err := commitsign.LoadConfig()
+		if err != nil {
+			return "", err
+		}
+		v.cfg = &cfg
+	}
+	ver, err := v.cfg.VerifyCommit(ctx, cm)
+	if err != nil {
+		return "", err
+	}
+	return ver.Status, nil
 }
@@ -132,6 +132,8 @@ type prollyConflictRowIter struct {
 
 	kd                       val.TupleDesc
 	baseVD, oursVD, theirsVD val.TupleDesc
+	// the fields of the value tuples of each version which are read, which leaves out dropped columns
+	baseFields, oursFields, theirsFields []int
 	// offsets for each version
 	b, o, t int
 	n       int
@@ -163,12 +165,16 @@ func newProllyConflictRowIter(ctx *sql.Context, ct ProllyConflictsTable) (*proll
 	oursVD := ct.ourSch.GetValueDescriptor()
 	theirsVD := ct.theirSch.GetValueDescriptor()
 
+	baseFields := valueFields(ct.baseSch)
+	oursFields := valueFields(ct.ourSch)
+	theirsFields := valueFields(ct.theirSch)
+
 	b := 1
 	var o, t, n int
 	if !keyless {
-		o = b + kd.Count() + baseVD.Count()
-		t = o + kd.Count() + oursVD.Count() + 1
-		n = t + kd.Count() + theirsVD.Count() + 2
+		o = b + kd.Count() + len(baseFields)
+		t = o + kd.Count() + len(oursFields) + 1
+		n = t + kd.Count() + len(theirsFields) + 2
 	} else {
 		o = b + baseVD.Count() - 1
 		t = o + oursVD.Count()
@@ -176,21 +182,24 @@ func newProllyConflictRowIter(ctx *sql.Context, ct ProllyConflictsTable) (*proll
 	}
 
 	return &prollyConflictRowIter{
-		itr:      itr,
-		tblName:  ct.tblName,
-		vrw:      ct.tbl.ValueReadWriter(),
-		ns:       ct.tbl.NodeStore(),
-		ourRows:  ourRows,
-		keyless:  keyless,
-		ourSch:   ct.ourSch,
-		kd:       kd,
-		baseVD:   baseVD,
-		oursVD:   oursVD,
-		theirsVD: theirsVD,
-		b:        b,
-		o:        o,
-		t:        t,
-		n:        n,
+		itr:          itr,
+		tblName:      ct.tblName,
+		vrw:          ct.tbl.ValueReadWriter(),
+		ns:           ct.tbl.NodeStore(),
+		ourRows:      ourRows,
+		keyless:      keyless,
+		ourSch:       ct.ourSch,
+		kd:           kd,
+		baseVD:       baseVD,
+		oursVD:       oursVD,
+		theirsVD:     theirsVD,
+		baseFields:   baseFields,
+		oursFields:   oursFields,
+		theirsFields: theirsFields,
+		b:            b,
+		o:            o,
+		t:            t,
+		n:            n,
 	}, nil
 }
 
@@ -237,8 +246,8 @@ func (itr *prollyConflictRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 
 func (itr *prollyConflictRowIter) putConflictRowVals(ctx *sql.Context, c conf, r sql.Row) error {
 	if c.bV != nil {
-		for i := 0; i < itr.baseVD.Count(); i++ {
-			f, err := tree.GetField(ctx, itr.baseVD, i, c.bV, itr.baseRows.NodeStore())
+		for i, field := range itr.baseFields {
+			f, err := tree.GetField(ctx, itr.baseVD, field, c.bV, itr.baseRows.NodeStore())
 			if err != nil {
 				return err
 			}
@@ -247,31 +256,44 @@ func (itr *prollyConflictRowIter) putConflictRowVals(ctx *sql.Context, c conf, r
 	}
 
 	if c.oV != nil {
-		for i := 0; i < itr.oursVD.Count(); i++ {
-			f, err := tree.GetField(ctx, itr.oursVD, i, c.oV, itr.baseRows.NodeStore())
+		for i, field := range itr.oursFields {
+			f, err := tree.GetField(ctx, itr.oursVD, field, c.oV, itr.baseRows.NodeStore())
 			if err != nil {
 				return err
 			}
 			r[itr.o+itr.kd.Count()+i] = f
 		}
 	}
-	r[itr.o+itr.kd.Count()+itr.oursVD.Count()] = getDiffType(c.bV, c.oV)
+	r[itr.o+itr.kd.Count()+len(itr.oursFields)] = getDiffType(c.bV, c.oV)
 
 	if c.tV != nil {
-		for i := 0; i < itr.theirsVD.Count(); i++ {
-			f, err := tree.GetField(ctx, itr.theirsVD, i, c.tV, itr.baseRows.NodeStore())
+		for i, field := range itr.theirsFields {
+			f, err := tree.GetField(ctx, itr.theirsVD, field, c.tV, itr.baseRows.NodeStore())
 			if err != nil {
 				return err
 			}
 			r[itr.t+itr.kd.Count()+i] = f
 		}
 	}
-	r[itr.t+itr.kd.Count()+itr.theirsVD.Count()] = getDiffType(c.bV, c.tV)
-	r[itr.t+itr.kd.Count()+itr.theirsVD.Count()+1] = c.id
+	r[itr.t+itr.kd.Count()+len(itr.theirsFields)] = getDiffType(c.bV, c.tV)
+	r[itr.t+itr.kd.Count()+len(itr.theirsFields)+1] = c.id
 
 	return nil
 }
 
+// valueFields returns the fields of the value tuples of the rows of a keyed table with schema |sch|, leaving out its
+// dropped columns, which aren't part of its rows.
+func valueFields(sch schema.Schema) []int {
+	nonPKCols := sch.GetNonPKCols()
+	fields := make([]int, 0, nonPKCols.StoredSize())
+	for i := 0; i < nonPKCols.StoredSize(); i++ {
+		if !nonPKCols.GetByStoredIndex(i).Dropped {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
+
 func getDiffType(base val.Tuple, other val.Tuple) string {
 	if base == nil {
 		return merge.ConflictDiffTypeAdded
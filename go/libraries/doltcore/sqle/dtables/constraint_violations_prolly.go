@@ -124,11 +124,12 @@ func (cvt *prollyConstraintViolationsTable) PartitionRows(ctx *sql.Context, part
 	vd = vd.WithoutFixedAccess()
 
 	return prollyCVIter{
-		itr: itr,
-		sch: sch,
-		kd:  kd,
-		vd:  vd,
-		ns:  cvt.artM.NodeStore(),
+		itr:    itr,
+		sch:    sch,
+		kd:     kd,
+		vd:     vd,
+		fields: valueFields(sch),
+		ns:     cvt.artM.NodeStore(),
 	}, nil
 }
 
@@ -151,6 +152,8 @@ type prollyCVIter struct {
 	itr    prolly.ArtifactIter
 	sch    schema.Schema
 	kd, vd val.TupleDesc
+	// the fields of value tuples which are read, which leaves out dropped columns
+	fields []int
 	ns     tree.NodeStore
 }
 
@@ -190,13 +193,13 @@ func (itr prollyCVIter) Next(ctx *sql.Context) (sql.Row, error) {
 		}
 		o += itr.kd.Count()
 
-		for i := 0; i < itr.vd.Count(); i++ {
-			r[o+i], err = tree.GetField(ctx, itr.vd, i, meta.Value, itr.ns)
+		for i, field := range itr.fields {
+			r[o+i], err = tree.GetField(ctx, itr.vd, field, meta.Value, itr.ns)
 			if err != nil {
 				return nil, err
 			}
 		}
-		o += itr.vd.Count()
+		o += len(itr.fields)
 	} else {
 		// For a keyless table, we still need a key to uniquely identify the row in the constraint
 		// violation table, so we add in the unique hash for the row.
@@ -0,0 +1,93 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+// DroppedDatabasesTable is a sql.Table implementation that implements a system table which shows the dropped databases
+// that can still be restored with dolt_undrop(), how much space they use, when they were dropped and when they'll be
+// purged according to dolt_dropped_database_retention_days. Every database shows the dropped databases of the whole
+// server.
+type DroppedDatabasesTable struct {
+	dbName string
+}
+
+var _ sql.Table = (*DroppedDatabasesTable)(nil)
+
+// NewDroppedDatabasesTable creates a DroppedDatabasesTable
+func NewDroppedDatabasesTable(dbName string) sql.Table {
+	return &DroppedDatabasesTable{dbName: dbName}
+}
+
+func (t *DroppedDatabasesTable) Name() string {
+	return doltdb.DroppedDatabasesTableName
+}
+
+func (t *DroppedDatabasesTable) String() string {
+	return doltdb.DroppedDatabasesTableName
+}
+
+func (t *DroppedDatabasesTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "database_name", Type: types.Text, Source: doltdb.DroppedDatabasesTableName, PrimaryKey: true, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "size", Type: types.Int64, Source: doltdb.DroppedDatabasesTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "dropped_at", Type: types.Datetime, Source: doltdb.DroppedDatabasesTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "purge_after", Type: types.Datetime, Source: doltdb.DroppedDatabasesTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+	}
+}
+
+func (t *DroppedDatabasesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+func (t *DroppedDatabasesTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+func (t *DroppedDatabasesTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	droppedDbs, err := sess.Provider().DroppedDatabases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var retentionDays int64
+	if _, val, ok := sql.SystemVariables.GetGlobal(dsess.DoltDroppedDatabaseRetentionDays); ok {
+		days, _, err := types.Int64.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+		retentionDays = days.(int64)
+	}
+
+	rows := make([]sql.Row, 0, len(droppedDbs))
+	for _, db := range droppedDbs {
+		var purgeAfter interface{}
+		if retentionDays > 0 {
+			purgeAfter = db.DroppedAt.Add(time.Duration(retentionDays) * 24 * time.Hour)
+		}
+		rows = append(rows, sql.NewRow(db.Name, db.Size, db.DroppedAt, purgeAfter))
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
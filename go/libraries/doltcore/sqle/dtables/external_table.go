@@ -0,0 +1,216 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/sqlconn"
+)
+
+var _ sql.Table = (*ExternalTable)(nil)
+var _ sql.InsertableTable = (*ExternalTable)(nil)
+var _ sql.UpdatableTable = (*ExternalTable)(nil)
+var _ sql.DeletableTable = (*ExternalTable)(nil)
+
+// ExternalTable is a table defined in the dolt_external_tables table, whose rows are read from a table on a MySQL or
+// Postgres server when it's queried. Writes to it are made to the table on the server if it's writable. Its
+// definition is versioned, but its rows aren't: they're always the current rows of the table on the server, and writes
+// to it are made as they happen, rather than when the transaction they're made in commits.
+type ExternalTable struct {
+	def    doltdb.ExternalTable
+	remote *sqlconn.RemoteTable
+	sch    sql.Schema
+}
+
+// NewExternalTable returns the external table defined by |def|. The schema of the table is read from the server, and
+// cached for a while.
+func NewExternalTable(ctx *sql.Context, dbName string, def doltdb.ExternalTable) (*ExternalTable, error) {
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	connURL, err := externalConnectionURL(def)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := sqlconn.OpenRemoteTable(ctx, connURL, def.RemoteTable)
+	if err != nil {
+		return nil, fmt.Errorf("error reading external table '%s': %w", def.Name, err)
+	}
+	sch, err := sqlutil.FromDoltSchema(dbName, def.Name, remote.Schema())
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalTable{def: def, remote: remote, sch: sch.Schema}, nil
+}
+
+// externalConnectionURL returns the URL of the connection of the external table |def|, which is given by the server's
+// DOLT_EXTERNAL_CONNECTION_<NAME> environment variable. The URL, and the credentials in it, are never stored in the
+// database.
+func externalConnectionURL(def doltdb.ExternalTable) (string, error) {
+	envVar := dconfig.EnvExternalConnectionPrefix + strings.ToUpper(def.Connection)
+	connURL, ok := os.LookupEnv(envVar)
+	if !ok || connURL == "" {
+		return "", fmt.Errorf("external table '%s' uses the connection '%s', which isn't defined; set %s to its mysql:// or postgres:// URL in the server's environment", def.Name, def.Connection, envVar)
+	}
+	return connURL, nil
+}
+
+func (t *ExternalTable) Name() string {
+	return t.def.Name
+}
+
+func (t *ExternalTable) String() string {
+	return t.def.Name
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the table, mapped from the table on the server.
+func (t *ExternalTable) Schema() sql.Schema {
+	return t.sch
+}
+
+func (t *ExternalTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.
+func (t *ExternalTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that returns the rows of the table on the server.
+func (t *ExternalTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	rd, err := t.remote.Rows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading external table '%s': %w", t.def.Name, err)
+	}
+	return &externalTableIter{rd: rd, sch: t.sch}, nil
+}
+
+// Inserter returns an Inserter for this table. The Inserter will get one call to Insert() for each row to be
+// inserted, and will end with a call to Close() to finalize the insert operation.
+func (t *ExternalTable) Inserter(*sql.Context) sql.RowInserter {
+	return &externalTableWriter{t}
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (t *ExternalTable) Updater(*sql.Context) sql.RowUpdater {
+	return &externalTableWriter{t}
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (t *ExternalTable) Deleter(*sql.Context) sql.RowDeleter {
+	return &externalTableWriter{t}
+}
+
+type externalTableIter struct {
+	rd  *sqlconn.ConnReader
+	sch sql.Schema
+}
+
+var _ sql.RowIter = (*externalTableIter)(nil)
+
+func (itr *externalTableIter) Next(ctx *sql.Context) (sql.Row, error) {
+	r, err := itr.rd.ReadSqlRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, val := range r {
+		if val == nil {
+			continue
+		}
+		r[i], _, err = itr.sch[i].Type.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (itr *externalTableIter) Close(ctx *sql.Context) error {
+	return itr.rd.Close(ctx)
+}
+
+var _ sql.RowInserter = (*externalTableWriter)(nil)
+var _ sql.RowUpdater = (*externalTableWriter)(nil)
+var _ sql.RowDeleter = (*externalTableWriter)(nil)
+
+// externalTableWriter writes each row to the table on the server as it's given. The writes can't be rolled back if
+// the statement making them fails.
+type externalTableWriter struct {
+	t *ExternalTable
+}
+
+// Insert inserts the row given, returning an error if it cannot. Insert will be called once for each row to process
+// for the insert operation, which may involve many rows. After all rows in an operation have been processed, Close
+// is called.
+func (w *externalTableWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	if err := w.checkWritable(); err != nil {
+		return err
+	}
+	return w.t.remote.Insert(ctx, r)
+}
+
+// Update the given row. Provides both the old and new rows.
+func (w *externalTableWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if err := w.checkWritable(); err != nil {
+		return err
+	}
+	return w.t.remote.Update(ctx, old, new)
+}
+
+// Delete deletes the given row. Delete will be called once for each row to process for the delete operation, which
+// may involve many rows. After all rows have been processed, Close is called.
+func (w *externalTableWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	if err := w.checkWritable(); err != nil {
+		return err
+	}
+	return w.t.remote.Delete(ctx, r)
+}
+
+func (w *externalTableWriter) checkWritable() error {
+	if !w.t.def.Writable {
+		return fmt.Errorf("external table '%s' is read-only; set %s in %s to write to it", w.t.def.Name, doltdb.ExternalTablesWritableCol, doltdb.ExternalTablesTableName)
+	}
+	return nil
+}
+
+// StatementBegin is called before the first operation of a statement.
+func (w *externalTableWriter) StatementBegin(*sql.Context) {}
+
+// DiscardChanges is called if a statement encounters an error. The rows already written to the server stay written.
+func (w *externalTableWriter) DiscardChanges(*sql.Context, error) error {
+	return nil
+}
+
+// StatementComplete is called after the last operation of the statement, indicating that it has successfully completed.
+func (w *externalTableWriter) StatementComplete(*sql.Context) error {
+	return nil
+}
+
+// Close finalizes the write operation.
+func (w *externalTableWriter) Close(*sql.Context) error {
+	return nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var DoltExternalTablesSqlSchema sql.PrimaryKeySchema
+
+func init() {
+	DoltExternalTablesSqlSchema, _ = sqlutil.FromDoltSchema("", doltdb.ExternalTablesTableName, doltdb.ExternalTablesSchema)
+}
+
+var _ sql.Table = (*ExternalTablesTable)(nil)
+var _ sql.UpdatableTable = (*ExternalTablesTable)(nil)
+var _ sql.DeletableTable = (*ExternalTablesTable)(nil)
+var _ sql.InsertableTable = (*ExternalTablesTable)(nil)
+var _ sql.ReplaceableTable = (*ExternalTablesTable)(nil)
+var _ sql.IndexAddressableTable = (*ExternalTablesTable)(nil)
+
+// ExternalTablesTable is the system table that stores the definitions of the external tables of a database, whose
+// reads and writes are made to tables on other servers. External tables are created, changed and dropped by writing to
+// this table, which requires the SUPER privilege.
+type ExternalTablesTable struct {
+	backingTable VersionableTable
+}
+
+func (dt *ExternalTablesTable) Name() string {
+	return doltdb.ExternalTablesTableName
+}
+
+func (dt *ExternalTablesTable) String() string {
+	return doltdb.ExternalTablesTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the dolt_external_tables system table.
+func (dt *ExternalTablesTable) Schema() sql.Schema {
+	return DoltExternalTablesSqlSchema.Schema
+}
+
+func (dt *ExternalTablesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.
+func (dt *ExternalTablesTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	if dt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return index.SinglePartitionIterFromNomsMap(nil), nil
+	}
+	return dt.backingTable.Partitions(context)
+}
+
+func (dt *ExternalTablesTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	if dt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return sql.RowsToRowIter(), nil
+	}
+
+	return dt.backingTable.PartitionRows(context, partition)
+}
+
+// NewExternalTablesTable creates a ExternalTablesTable
+func NewExternalTablesTable(_ *sql.Context, backingTable VersionableTable) sql.Table {
+	return &ExternalTablesTable{backingTable: backingTable}
+}
+
+// NewEmptyExternalTablesTable creates a ExternalTablesTable with no backing table
+func NewEmptyExternalTablesTable(_ *sql.Context) sql.Table {
+	return &ExternalTablesTable{}
+}
+
+// Replacer returns a RowReplacer for this table. The RowReplacer will have Insert and optionally Delete called once
+// for each row, followed by a call to Close() when all rows have been processed.
+func (dt *ExternalTablesTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	return newExternalTablesWriter(dt)
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (dt *ExternalTablesTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return newExternalTablesWriter(dt)
+}
+
+// Inserter returns an Inserter for this table. The Inserter will get one call to Insert() for each row to be
+// inserted, and will end with a call to Close() to finalize the insert operation.
+func (dt *ExternalTablesTable) Inserter(*sql.Context) sql.RowInserter {
+	return newExternalTablesWriter(dt)
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (dt *ExternalTablesTable) Deleter(*sql.Context) sql.RowDeleter {
+	return newExternalTablesWriter(dt)
+}
+
+func (dt *ExternalTablesTable) LockedToRoot(ctx *sql.Context, root doltdb.RootValue) (sql.IndexAddressableTable, error) {
+	if dt.backingTable == nil {
+		return dt, nil
+	}
+	return dt.backingTable.LockedToRoot(ctx, root)
+}
+
+// IndexedAccess implements IndexAddressableTable, but ExternalTablesTables have no indexes.
+// Thus, this should never be called.
+func (dt *ExternalTablesTable) IndexedAccess(lookup sql.IndexLookup) sql.IndexedTable {
+	panic("Unreachable")
+}
+
+// GetIndexes implements IndexAddressableTable, but ExternalTablesTables have no indexes.
+func (dt *ExternalTablesTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
+	return nil, nil
+}
+
+func (dt *ExternalTablesTable) PreciseMatch() bool {
+	return true
+}
+
+var _ sql.RowReplacer = (*externalTablesWriter)(nil)
+var _ sql.RowUpdater = (*externalTablesWriter)(nil)
+var _ sql.RowInserter = (*externalTablesWriter)(nil)
+var _ sql.RowDeleter = (*externalTablesWriter)(nil)
+
+type externalTablesWriter struct {
+	it                      *ExternalTablesTable
+	errDuringStatementBegin error
+	prevHash                *hash.Hash
+	tableWriter             dsess.TableWriter
+}
+
+func newExternalTablesWriter(it *ExternalTablesTable) *externalTablesWriter {
+	return &externalTablesWriter{it, nil, nil, nil}
+}
+
+// Insert inserts the row given, returning an error if it cannot. Insert will be called once for each row to process
+// for the insert operation, which may involve many rows. After all rows in an operation have been processed, Close
+// is called.
+func (iw *externalTablesWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	if err := iw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	if err := validateExternalTableRow(r); err != nil {
+		return err
+	}
+	return iw.tableWriter.Insert(ctx, r)
+}
+
+// Update the given row. Provides both the old and new rows.
+func (iw *externalTablesWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if err := iw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	if err := validateExternalTableRow(new); err != nil {
+		return err
+	}
+	return iw.tableWriter.Update(ctx, old, new)
+}
+
+// Delete deletes the given row. Returns ErrDeleteRowNotFound if the row was not found. Delete will be called once for
+// each row to process for the delete operation, which may involve many rows. After all rows have been processed,
+// Close is called.
+func (iw *externalTablesWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	if err := iw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return iw.tableWriter.Delete(ctx, r)
+}
+
+// StatementBegin is called before the first operation of a statement. Integrators should mark the state of the data
+// in some way that it may be returned to in the case of an error.
+func (iw *externalTablesWriter) StatementBegin(ctx *sql.Context) {
+	dbName := ctx.GetCurrentDatabase()
+	dSess := dsess.DSessFromSess(ctx.Session)
+
+	// external tables make the server query other servers, so only administrators may define them
+	if !dSess.Provider().UserHasPrivileges(ctx, sql.NewPrivilegedOperation(sql.PrivilegeCheckSubject{}, sql.PrivilegeType_Super)) {
+		iw.errDuringStatementBegin = sql.ErrPrivilegeCheckFailed.New(ctx.Session.Client().User)
+		return
+	}
+
+	// TODO: this needs to use a revision qualified name
+	roots, _ := dSess.GetRoots(ctx, dbName)
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		iw.errDuringStatementBegin = err
+		return
+	}
+	if !ok {
+		iw.errDuringStatementBegin = fmt.Errorf("no root value found in session")
+		return
+	}
+
+	prevHash, err := roots.Working.HashOf()
+	if err != nil {
+		iw.errDuringStatementBegin = err
+		return
+	}
+
+	iw.prevHash = &prevHash
+
+	found, err := roots.Working.HasTable(ctx, doltdb.TableName{Name: doltdb.ExternalTablesTableName})
+
+	if err != nil {
+		iw.errDuringStatementBegin = err
+		return
+	}
+
+	if !found {
+		// underlying table doesn't exist. Record this, then create the table.
+		newRootValue, err := doltdb.CreateEmptyTable(ctx, roots.Working, doltdb.TableName{Name: doltdb.ExternalTablesTableName}, doltdb.ExternalTablesSchema)
+		if err != nil {
+			iw.errDuringStatementBegin = err
+			return
+		}
+
+		if dbState.WorkingSet() == nil {
+			iw.errDuringStatementBegin = doltdb.ErrOperationNotSupportedInDetachedHead
+			return
+		}
+
+		// We use WriteSession.SetWorkingSet instead of DoltSession.SetWorkingRoot because we want to avoid modifying the root
+		// until the end of the transaction, but we still want the WriteSession to be able to find the newly
+		// created table.
+
+		if ws := dbState.WriteSession(); ws != nil {
+			err = ws.SetWorkingSet(ctx, dbState.WorkingSet().WithWorkingRoot(newRootValue))
+			if err != nil {
+				iw.errDuringStatementBegin = err
+				return
+			}
+		}
+
+		err = dSess.SetWorkingRoot(ctx, dbName, newRootValue)
+		if err != nil {
+			iw.errDuringStatementBegin = err
+			return
+		}
+	}
+
+	if ws := dbState.WriteSession(); ws != nil {
+		tableWriter, err := ws.GetTableWriter(ctx, doltdb.TableName{Name: doltdb.ExternalTablesTableName}, dbName, dSess.SetWorkingRoot)
+		if err != nil {
+			iw.errDuringStatementBegin = err
+			return
+		}
+		iw.tableWriter = tableWriter
+		tableWriter.StatementBegin(ctx)
+	}
+}
+
+// DiscardChanges is called if a statement encounters an error, and all current changes since the statement beginning
+// should be discarded.
+func (iw *externalTablesWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	if iw.tableWriter != nil {
+		return iw.tableWriter.DiscardChanges(ctx, errorEncountered)
+	}
+	return nil
+}
+
+// StatementComplete is called after the last operation of the statement, indicating that it has successfully completed.
+// The mark set in StatementBegin may be removed, and a new one should be created on the next StatementBegin.
+func (iw *externalTablesWriter) StatementComplete(ctx *sql.Context) error {
+	if iw.tableWriter != nil {
+		return iw.tableWriter.StatementComplete(ctx)
+	}
+	return nil
+}
+
+// Close finalizes the delete operation, persisting the result.
+func (iw externalTablesWriter) Close(ctx *sql.Context) error {
+	if iw.tableWriter != nil {
+		return iw.tableWriter.Close(ctx)
+	}
+	return nil
+}
+
+// validateExternalTableRow returns an error if |r| isn't a valid definition of an external table.
+func validateExternalTableRow(r sql.Row) error {
+	ext := doltdb.ExternalTable{
+		Name:        r[0].(string),
+		Connection:  r[1].(string),
+		RemoteTable: r[2].(string),
+	}
+	return ext.Validate()
+}
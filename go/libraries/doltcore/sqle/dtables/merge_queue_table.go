@@ -0,0 +1,89 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+// MergeQueueTable is a sql.Table implementation that implements a system table which shows the merges added to the
+// merge queue of a database in the running server with dolt_merge_queue(), in the order they're done.
+type MergeQueueTable struct {
+	dbName string
+}
+
+var _ sql.Table = (*MergeQueueTable)(nil)
+
+// NewMergeQueueTable creates a MergeQueueTable
+func NewMergeQueueTable(dbName string) sql.Table {
+	return &MergeQueueTable{dbName: dbName}
+}
+
+func (t *MergeQueueTable) Name() string {
+	return doltdb.MergeQueueTableName
+}
+
+func (t *MergeQueueTable) String() string {
+	return doltdb.MergeQueueTableName
+}
+
+func (t *MergeQueueTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "id", Type: types.Int64, Source: doltdb.MergeQueueTableName, PrimaryKey: true, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "source_branch", Type: types.Text, Source: doltdb.MergeQueueTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "target_branch", Type: types.Text, Source: doltdb.MergeQueueTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "run_tests", Type: types.Boolean, Source: doltdb.MergeQueueTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "status", Type: types.Text, Source: doltdb.MergeQueueTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "commit_hash", Type: types.Text, Source: doltdb.MergeQueueTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+		{Name: "error", Type: types.Text, Source: doltdb.MergeQueueTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+		{Name: "enqueued_by", Type: types.Text, Source: doltdb.MergeQueueTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "enqueued_at", Type: types.Datetime, Source: doltdb.MergeQueueTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "finished_at", Type: types.Datetime, Source: doltdb.MergeQueueTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+	}
+}
+
+func (t *MergeQueueTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+func (t *MergeQueueTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+func (t *MergeQueueTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	baseName, _ := dsess.SplitRevisionDbName(t.dbName)
+	entries := sess.Provider().MergeQueue(baseName)
+
+	rows := make([]sql.Row, len(entries))
+	for i, e := range entries {
+		var commit, errStr, finishedAt interface{}
+		if e.State == dsess.MergeMerged {
+			commit = e.Commit
+		} else if e.State == dsess.MergeFailed {
+			errStr = e.Error
+		}
+		if !e.FinishedAt.IsZero() {
+			finishedAt = e.FinishedAt
+		}
+		rows[i] = sql.NewRow(e.ID, e.Source, e.Target, e.RunTests, e.State, commit, errStr, e.User, e.EnqueuedAt, finishedAt)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+// NotesTable is a sql.Table implementation that implements a system table which shows the notes attached to commits
+// in every notes ref of a database. Notes are written with the dolt_notes stored procedure.
+type NotesTable struct {
+	dbName string
+	ddb    *doltdb.DoltDB
+}
+
+var _ sql.Table = (*NotesTable)(nil)
+
+// NewNotesTable creates a NotesTable
+func NewNotesTable(dbName string, ddb *doltdb.DoltDB) sql.Table {
+	return &NotesTable{dbName: dbName, ddb: ddb}
+}
+
+func (t *NotesTable) Name() string {
+	return doltdb.NotesTableName
+}
+
+func (t *NotesTable) String() string {
+	return doltdb.NotesTableName
+}
+
+func (t *NotesTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "notes_ref", Type: types.Text, Source: doltdb.NotesTableName, PrimaryKey: true, DatabaseSource: t.dbName},
+		{Name: doltdb.NotesCommitCol, Type: types.Text, Source: doltdb.NotesTableName, PrimaryKey: true, DatabaseSource: t.dbName},
+		{Name: doltdb.NotesNoteCol, Type: types.LongText, Source: doltdb.NotesTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+	}
+}
+
+func (t *NotesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+func (t *NotesTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+func (t *NotesTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	notesRefs, err := t.ddb.GetNotesRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(notesRefs, func(i, j int) bool {
+		return notesRefs[i].String() < notesRefs[j].String()
+	})
+
+	var rows []sql.Row
+	for _, dref := range notesRefs {
+		nr := dref.(ref.NotesRef)
+		notes, err := t.ddb.GetNotes(ctx, nr)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range notes {
+			rows = append(rows, sql.NewRow(nr.GetPath(), n.Commit.String(), n.Text))
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
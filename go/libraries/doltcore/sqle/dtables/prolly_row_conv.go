@@ -47,7 +47,7 @@ func NewProllyRowConverter(inSch, outSch schema.Schema, warnFn rowconv.WarnFunct
 	}
 
 	pkTargetTypes := make([]sql.Type, inSch.GetPKCols().Size())
-	nonPkTargetTypes := make([]sql.Type, inSch.GetNonPKCols().Size())
+	nonPkTargetTypes := make([]sql.Type, inSch.GetNonPKCols().StoredSize())
 
 	// Populate pkTargetTypes and nonPkTargetTypes with non-nil sql.Type if we need to do a type conversion
 	for i, j := range keyProj {
@@ -68,14 +68,14 @@ func NewProllyRowConverter(inSch, outSch schema.Schema, warnFn rowconv.WarnFunct
 		if j == -1 {
 			continue
 		}
-		inColType := inSch.GetNonPKCols().GetByIndex(i).TypeInfo.ToSqlType()
-		outColType := outSch.GetNonPKCols().GetByIndex(j).TypeInfo.ToSqlType()
+		inColType := inSch.GetNonPKCols().GetByStoredIndex(i).TypeInfo.ToSqlType()
+		outColType := outSch.GetNonPKCols().GetByStoredIndex(j).TypeInfo.ToSqlType()
 		if !inColType.Equals(outColType) {
 			nonPkTargetTypes[i] = outColType
 		}
 
 		// translate tuple offset to row placement
-		t := outSch.GetNonPKCols().GetByIndex(j).Tag
+		t := outSch.GetNonPKCols().GetByStoredIndex(j).Tag
 		valProj[i] = outSch.GetAllCols().TagToIdx[t]
 	}
 
@@ -0,0 +1,314 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var DoltSequencesSqlSchema sql.PrimaryKeySchema
+
+func init() {
+	DoltSequencesSqlSchema, _ = sqlutil.FromDoltSchema("", doltdb.SequencesTableName, doltdb.SequencesSchema)
+}
+
+var _ sql.Table = (*SequencesTable)(nil)
+var _ sql.UpdatableTable = (*SequencesTable)(nil)
+var _ sql.DeletableTable = (*SequencesTable)(nil)
+var _ sql.InsertableTable = (*SequencesTable)(nil)
+var _ sql.ReplaceableTable = (*SequencesTable)(nil)
+var _ sql.IndexAddressableTable = (*SequencesTable)(nil)
+
+// SequencesTable is the system table that stores the sequences of a database. Sequences are created, changed and dropped
+// by writing to this table, and their values are generated by the nextval() function.
+type SequencesTable struct {
+	backingTable VersionableTable
+}
+
+func (dt *SequencesTable) Name() string {
+	return doltdb.SequencesTableName
+}
+
+func (dt *SequencesTable) String() string {
+	return doltdb.SequencesTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the dolt_sequences system table.
+func (dt *SequencesTable) Schema() sql.Schema {
+	return DoltSequencesSqlSchema.Schema
+}
+
+func (dt *SequencesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.
+func (dt *SequencesTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	if dt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return index.SinglePartitionIterFromNomsMap(nil), nil
+	}
+	return dt.backingTable.Partitions(context)
+}
+
+func (dt *SequencesTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	if dt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return sql.RowsToRowIter(), nil
+	}
+
+	return dt.backingTable.PartitionRows(context, partition)
+}
+
+// NewSequencesTable creates a SequencesTable
+func NewSequencesTable(_ *sql.Context, backingTable VersionableTable) sql.Table {
+	return &SequencesTable{backingTable: backingTable}
+}
+
+// NewEmptySequencesTable creates a SequencesTable with no backing table
+func NewEmptySequencesTable(_ *sql.Context) sql.Table {
+	return &SequencesTable{}
+}
+
+// Replacer returns a RowReplacer for this table. The RowReplacer will have Insert and optionally Delete called once
+// for each row, followed by a call to Close() when all rows have been processed.
+func (dt *SequencesTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	return newSequencesWriter(dt)
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (dt *SequencesTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return newSequencesWriter(dt)
+}
+
+// Inserter returns an Inserter for this table. The Inserter will get one call to Insert() for each row to be
+// inserted, and will end with a call to Close() to finalize the insert operation.
+func (dt *SequencesTable) Inserter(*sql.Context) sql.RowInserter {
+	return newSequencesWriter(dt)
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (dt *SequencesTable) Deleter(*sql.Context) sql.RowDeleter {
+	return newSequencesWriter(dt)
+}
+
+func (dt *SequencesTable) LockedToRoot(ctx *sql.Context, root doltdb.RootValue) (sql.IndexAddressableTable, error) {
+	if dt.backingTable == nil {
+		return dt, nil
+	}
+	return dt.backingTable.LockedToRoot(ctx, root)
+}
+
+// IndexedAccess implements IndexAddressableTable, but SequencesTables have no indexes.
+// Thus, this should never be called.
+func (dt *SequencesTable) IndexedAccess(lookup sql.IndexLookup) sql.IndexedTable {
+	panic("Unreachable")
+}
+
+// GetIndexes implements IndexAddressableTable, but SequencesTables have no indexes.
+func (dt *SequencesTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
+	return nil, nil
+}
+
+func (dt *SequencesTable) PreciseMatch() bool {
+	return true
+}
+
+var _ sql.RowReplacer = (*sequencesWriter)(nil)
+var _ sql.RowUpdater = (*sequencesWriter)(nil)
+var _ sql.RowInserter = (*sequencesWriter)(nil)
+var _ sql.RowDeleter = (*sequencesWriter)(nil)
+
+type sequencesWriter struct {
+	it                      *SequencesTable
+	errDuringStatementBegin error
+	prevHash                *hash.Hash
+	tableWriter             dsess.TableWriter
+}
+
+func newSequencesWriter(it *SequencesTable) *sequencesWriter {
+	return &sequencesWriter{it, nil, nil, nil}
+}
+
+// Insert inserts the row given, returning an error if it cannot. Insert will be called once for each row to process
+// for the insert operation, which may involve many rows. After all rows in an operation have been processed, Close
+// is called.
+func (iw *sequencesWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	if err := iw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	if err := validateSequenceRow(r); err != nil {
+		return err
+	}
+	return iw.tableWriter.Insert(ctx, r)
+}
+
+// Update the given row. Provides both the old and new rows.
+func (iw *sequencesWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if err := iw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	if err := validateSequenceRow(new); err != nil {
+		return err
+	}
+	return iw.tableWriter.Update(ctx, old, new)
+}
+
+// Delete deletes the given row. Returns ErrDeleteRowNotFound if the row was not found. Delete will be called once for
+// each row to process for the delete operation, which may involve many rows. After all rows have been processed,
+// Close is called.
+func (iw *sequencesWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	if err := iw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return iw.tableWriter.Delete(ctx, r)
+}
+
+// StatementBegin is called before the first operation of a statement. Integrators should mark the state of the data
+// in some way that it may be returned to in the case of an error.
+func (iw *sequencesWriter) StatementBegin(ctx *sql.Context) {
+	dbName := ctx.GetCurrentDatabase()
+	dSess := dsess.DSessFromSess(ctx.Session)
+
+	// TODO: this needs to use a revision qualified name
+	roots, _ := dSess.GetRoots(ctx, dbName)
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		iw.errDuringStatementBegin = err
+		return
+	}
+	if !ok {
+		iw.errDuringStatementBegin = fmt.Errorf("no root value found in session")
+		return
+	}
+
+	prevHash, err := roots.Working.HashOf()
+	if err != nil {
+		iw.errDuringStatementBegin = err
+		return
+	}
+
+	iw.prevHash = &prevHash
+
+	found, err := roots.Working.HasTable(ctx, doltdb.TableName{Name: doltdb.SequencesTableName})
+
+	if err != nil {
+		iw.errDuringStatementBegin = err
+		return
+	}
+
+	if !found {
+		// underlying table doesn't exist. Record this, then create the table.
+		newRootValue, err := doltdb.CreateEmptyTable(ctx, roots.Working, doltdb.TableName{Name: doltdb.SequencesTableName}, doltdb.SequencesSchema)
+		if err != nil {
+			iw.errDuringStatementBegin = err
+			return
+		}
+
+		if dbState.WorkingSet() == nil {
+			iw.errDuringStatementBegin = doltdb.ErrOperationNotSupportedInDetachedHead
+			return
+		}
+
+		// We use WriteSession.SetWorkingSet instead of DoltSession.SetWorkingRoot because we want to avoid modifying the root
+		// until the end of the transaction, but we still want the WriteSession to be able to find the newly
+		// created table.
+
+		if ws := dbState.WriteSession(); ws != nil {
+			err = ws.SetWorkingSet(ctx, dbState.WorkingSet().WithWorkingRoot(newRootValue))
+			if err != nil {
+				iw.errDuringStatementBegin = err
+				return
+			}
+		}
+
+		err = dSess.SetWorkingRoot(ctx, dbName, newRootValue)
+		if err != nil {
+			iw.errDuringStatementBegin = err
+			return
+		}
+	}
+
+	if ws := dbState.WriteSession(); ws != nil {
+		tableWriter, err := ws.GetTableWriter(ctx, doltdb.TableName{Name: doltdb.SequencesTableName}, dbName, dSess.SetWorkingRoot)
+		if err != nil {
+			iw.errDuringStatementBegin = err
+			return
+		}
+		iw.tableWriter = tableWriter
+		tableWriter.StatementBegin(ctx)
+	}
+}
+
+// DiscardChanges is called if a statement encounters an error, and all current changes since the statement beginning
+// should be discarded.
+func (iw *sequencesWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	if iw.tableWriter != nil {
+		return iw.tableWriter.DiscardChanges(ctx, errorEncountered)
+	}
+	return nil
+}
+
+// StatementComplete is called after the last operation of the statement, indicating that it has successfully completed.
+// The mark set in StatementBegin may be removed, and a new one should be created on the next StatementBegin.
+func (iw *sequencesWriter) StatementComplete(ctx *sql.Context) error {
+	if iw.tableWriter != nil {
+		return iw.tableWriter.StatementComplete(ctx)
+	}
+	return nil
+}
+
+// Close finalizes the delete operation, persisting the result.
+func (iw sequencesWriter) Close(ctx *sql.Context) error {
+	if iw.tableWriter != nil {
+		return iw.tableWriter.Close(ctx)
+	}
+	return nil
+}
+
+// validateSequenceRow returns an error if |r| isn't a valid definition of a sequence.
+func validateSequenceRow(r sql.Row) error {
+	seq := doltdb.Sequence{
+		Name:       r[0].(string),
+		StartValue: r[1].(int64),
+		Increment:  r[2].(int64),
+		MinValue:   r[3].(int64),
+		MaxValue:   r[4].(int64),
+	}
+	return seq.Validate()
+}
+
+// SequenceRow returns the row of the dolt_sequences table for |seq|.
+func SequenceRow(seq doltdb.Sequence) sql.Row {
+	var cycle int8
+	if seq.Cycle {
+		cycle = 1
+	}
+	var next interface{}
+	if seq.NextValue != nil {
+		next = *seq.NextValue
+	}
+	return sql.Row{seq.Name, seq.StartValue, seq.Increment, seq.MinValue, seq.MaxValue, cycle, next}
+}
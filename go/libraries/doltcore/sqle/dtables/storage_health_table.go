@@ -0,0 +1,105 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+const (
+	// storageHealthy is the status of a database whose most recent scrub found no problems
+	storageHealthy = "ok"
+	// storageCorrupt is the status of a problem which couldn't be repaired
+	storageCorrupt = "corrupt"
+	// storageRepaired is the status of a problem which was repaired from a remote or backup
+	storageRepaired = "repaired"
+	// storageScrubFailed is the status of a database whose most recent scrub couldn't be completed
+	storageScrubFailed = "error"
+)
+
+// StorageHealthTable is a sql.Table implementation that implements a system table which shows the results of the most
+// recent scrub of the storage of each database in the running server, which is enabled by dolt_storage_scrub_interval.
+// A database whose scrub found no problems has a single row with the status "ok", and otherwise there's a row for each
+// problem found. Every database shows the results for the whole server.
+type StorageHealthTable struct {
+	dbName string
+}
+
+var _ sql.Table = (*StorageHealthTable)(nil)
+
+// NewStorageHealthTable creates a StorageHealthTable
+func NewStorageHealthTable(dbName string) sql.Table {
+	return &StorageHealthTable{dbName: dbName}
+}
+
+func (t *StorageHealthTable) Name() string {
+	return doltdb.StorageHealthTableName
+}
+
+func (t *StorageHealthTable) String() string {
+	return doltdb.StorageHealthTableName
+}
+
+func (t *StorageHealthTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "database_name", Type: types.Text, Source: doltdb.StorageHealthTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "status", Type: types.Text, Source: doltdb.StorageHealthTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "store", Type: types.Text, Source: doltdb.StorageHealthTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+		{Name: "table_file", Type: types.Text, Source: doltdb.StorageHealthTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+		{Name: "chunk", Type: types.Text, Source: doltdb.StorageHealthTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+		{Name: "problem", Type: types.Text, Source: doltdb.StorageHealthTableName, PrimaryKey: false, Nullable: true, DatabaseSource: t.dbName},
+		{Name: "chunks_checked", Type: types.Int64, Source: doltdb.StorageHealthTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+		{Name: "checked_at", Type: types.Datetime, Source: doltdb.StorageHealthTableName, PrimaryKey: false, Nullable: false, DatabaseSource: t.dbName},
+	}
+}
+
+func (t *StorageHealthTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+func (t *StorageHealthTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+func (t *StorageHealthTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+
+	var rows []sql.Row
+	for _, h := range sess.Provider().StorageHealth() {
+		if h.Error != "" {
+			rows = append(rows, sql.NewRow(h.Database, storageScrubFailed, nil, nil, nil, h.Error, h.ChunksChecked, h.CheckedAt))
+		} else if len(h.Problems) == 0 {
+			rows = append(rows, sql.NewRow(h.Database, storageHealthy, nil, nil, nil, nil, h.ChunksChecked, h.CheckedAt))
+		}
+
+		for _, p := range h.Problems {
+			status := storageCorrupt
+			if p.Repaired {
+				status = storageRepaired
+			}
+			var chunk interface{}
+			if !p.Chunk.IsEmpty() {
+				chunk = p.Chunk.String()
+			}
+			rows = append(rows, sql.NewRow(h.Database, status, p.Store, p.TableFile.String(), chunk, p.Msg, h.ChunksChecked, h.CheckedAt))
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -15,13 +15,17 @@
 package dtables
 
 import (
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 )
 
@@ -29,15 +33,20 @@ const tagsDefaultRowCount = 10
 
 var _ sql.Table = (*TagsTable)(nil)
 var _ sql.StatisticsTable = (*TagsTable)(nil)
+var _ sql.UpdatableTable = (*TagsTable)(nil)
+var _ sql.DeletableTable = (*TagsTable)(nil)
+var _ sql.InsertableTable = (*TagsTable)(nil)
+var _ sql.ReplaceableTable = (*TagsTable)(nil)
 
-// TagsTable is a sql.Table implementation that implements a system table which shows the dolt tags
+// TagsTable is a sql.Table implementation that implements a system table which shows the dolt tags. Tags can be
+// created, changed and deleted by writing to it.
 type TagsTable struct {
-	ddb *doltdb.DoltDB
+	db dsess.SqlDatabase
 }
 
 // NewTagsTable creates a TagsTable
-func NewTagsTable(_ *sql.Context, ddb *doltdb.DoltDB) sql.Table {
-	return &TagsTable{ddb: ddb}
+func NewTagsTable(_ *sql.Context, db dsess.SqlDatabase) sql.Table {
+	return &TagsTable{db: db}
 }
 
 func (dt *TagsTable) DataLength(ctx *sql.Context) (uint64, error) {
@@ -70,10 +79,11 @@ func (dt *TagsTable) Schema() sql.Schema {
 	return []*sql.Column{
 		{Name: "tag_name", Type: types.Text, Source: doltdb.TagsTableName, PrimaryKey: true},
 		{Name: "tag_hash", Type: types.Text, Source: doltdb.TagsTableName, PrimaryKey: true},
-		{Name: "tagger", Type: types.Text, Source: doltdb.TagsTableName, PrimaryKey: false},
-		{Name: "email", Type: types.Text, Source: doltdb.TagsTableName, PrimaryKey: false},
-		{Name: "date", Type: types.Datetime, Source: doltdb.TagsTableName, PrimaryKey: false},
-		{Name: "message", Type: types.Text, Source: doltdb.TagsTableName, PrimaryKey: false},
+		// the rest are nullable so that they can be left out when inserting tags, and are given defaults
+		{Name: "tagger", Type: types.Text, Source: doltdb.TagsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "email", Type: types.Text, Source: doltdb.TagsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "date", Type: types.Datetime, Source: doltdb.TagsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "message", Type: types.Text, Source: doltdb.TagsTableName, PrimaryKey: false, Nullable: true},
 	}
 }
 
@@ -89,7 +99,7 @@ func (dt *TagsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
 
 // PartitionRows is a sql.Table interface function that gets a row iterator for a partition
 func (dt *TagsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
-	return NewTagsItr(ctx, dt.ddb)
+	return NewTagsItr(ctx, dt.db.DbData().Ddb)
 }
 
 // TagsItr is a sql.RowItr implementation which iterates over each commit as if it's a row in the table.
@@ -127,3 +137,137 @@ func (itr *TagsItr) Next(ctx *sql.Context) (sql.Row, error) {
 func (itr *TagsItr) Close(*sql.Context) error {
 	return nil
 }
+
+// Replacer returns a RowReplacer for this table. The RowReplacer will have Insert and optionally Delete called once
+// for each row, followed by a call to Close() when all rows have been processed.
+func (dt *TagsTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	return tagWriter{dt}
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (dt *TagsTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return tagWriter{dt}
+}
+
+// Inserter returns an Inserter for this table. The Inserter will get one call to Insert() for each row to be
+// inserted, and will end with a call to Close() to finalize the insert operation.
+func (dt *TagsTable) Inserter(*sql.Context) sql.RowInserter {
+	return tagWriter{dt}
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (dt *TagsTable) Deleter(*sql.Context) sql.RowDeleter {
+	return tagWriter{dt}
+}
+
+var _ sql.RowReplacer = tagWriter{nil}
+var _ sql.RowUpdater = tagWriter{nil}
+var _ sql.RowInserter = tagWriter{nil}
+var _ sql.RowDeleter = tagWriter{nil}
+
+// tagWriter creates, changes and deletes tags for writes to the dolt_tags table. Like the dolt_tag stored procedure,
+// the changes are made to the database directly rather than in the working set.
+type tagWriter struct {
+	dt *TagsTable
+}
+
+// Insert creates a tag named by the tag_name column at the commit given by tag_hash, which may be any commit spec
+// such as a commit hash or branch name. The tagger and email default to the current user, the date to the current
+// time, and the message to the empty string.
+func (tWr tagWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	name, startPoint, props, err := tWr.tagFromRow(ctx, r)
+	if err != nil {
+		return err
+	}
+	headRef, err := dsess.DSessFromSess(ctx.Session).CWBHeadRef(ctx, tWr.dt.db.Name())
+	if err != nil {
+		return err
+	}
+	return actions.CreateTagOnDB(ctx, tWr.dt.db.DbData().Ddb, name, startPoint, props, headRef)
+}
+
+// Update the given row. Provides both the old and new rows.
+func (tWr tagWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	oldName, ok := old[0].(string)
+	if !ok {
+		return fmt.Errorf("invalid tag name: %v", old[0])
+	}
+	name, startPoint, props, err := tWr.tagFromRow(ctx, new)
+	if err != nil {
+		return err
+	}
+	headRef, err := dsess.DSessFromSess(ctx.Session).CWBHeadRef(ctx, tWr.dt.db.Name())
+	if err != nil {
+		return err
+	}
+	return actions.UpdateTagOnDB(ctx, tWr.dt.db.DbData().Ddb, oldName, name, startPoint, props, headRef)
+}
+
+// Delete deletes the given row. Returns ErrDeleteRowNotFound if the row was not found. Delete will be called once for
+// each row to process for the delete operation, which may involve many rows. After all rows have been processed,
+// Close is called.
+func (tWr tagWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	name, ok := r[0].(string)
+	if !ok {
+		return fmt.Errorf("invalid tag name: %v", r[0])
+	}
+	err := actions.DeleteTagsOnDB(ctx, tWr.dt.db.DbData().Ddb, name)
+	if err == doltdb.ErrTagNotFound {
+		return sql.ErrDeleteRowNotFound.New()
+	}
+	return err
+}
+
+// tagFromRow returns the name, commit spec and properties of the tag described by a row of the dolt_tags table, with
+// defaults for the columns which are NULL.
+func (tWr tagWriter) tagFromRow(ctx *sql.Context, r sql.Row) (string, string, actions.TagProps, error) {
+	var props actions.TagProps
+	name, ok := r[0].(string)
+	if !ok || len(name) == 0 {
+		return "", "", props, fmt.Errorf("tag_name must be given to create a tag")
+	}
+	startPoint, ok := r[1].(string)
+	if !ok || len(startPoint) == 0 {
+		return "", "", props, fmt.Errorf("tag_hash must be given to create a tag")
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	props.TaggerName = dSess.Username()
+	props.TaggerEmail = dSess.Email()
+	if r[2] != nil {
+		props.TaggerName = r[2].(string)
+	}
+	if r[3] != nil {
+		props.TaggerEmail = r[3].(string)
+	}
+	if r[4] != nil {
+		props.Date, ok = r[4].(time.Time)
+		if !ok {
+			return "", "", props, fmt.Errorf("invalid tag date: %v", r[4])
+		}
+	}
+	if r[5] != nil {
+		props.Description = r[5].(string)
+	}
+	return name, startPoint, props, nil
+}
+
+// StatementBegin implements the interface sql.TableEditor. Currently a no-op.
+func (tWr tagWriter) StatementBegin(ctx *sql.Context) {}
+
+// DiscardChanges implements the interface sql.TableEditor. Currently a no-op.
+func (tWr tagWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	return nil
+}
+
+// StatementComplete implements the interface sql.TableEditor. Currently a no-op.
+func (tWr tagWriter) StatementComplete(ctx *sql.Context) error {
+	return nil
+}
+
+// Close finalizes the delete operation, persisting the result.
+func (tWr tagWriter) Close(*sql.Context) error {
+	return nil
+}
@@ -17,9 +17,11 @@ package enginetest
 import (
 	"github.com/dolthub/go-mysql-server/enginetest/queries"
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
 	"github.com/dolthub/go-mysql-server/sql/types"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 )
 
 // Tests in this file are a grab bag of DDL queries, many of them ported from older parts of the Dolt codebase
@@ -593,6 +595,175 @@ var DropColumnScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "drop column which is not part of an index",
+		SetUpScript: []string{
+			"create table t (pk int primary key, a int, b varchar(20), c int, v int as (c + 1) virtual, key (c));",
+			"insert into t (pk, a, b, c) values (1, 10, 'one', 100), (2, 20, 'two', 200), (3, 30, 'three', 300);",
+			"alter table t add column d int;",
+			"insert into t (pk, a, b, c, d) values (4, 40, 'four', 400, 4000);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:            "alter table t drop column b",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:            "alter table t drop column a",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:            "alter table t drop column v",
+				SkipResultsCheck: true,
+			},
+			{
+				Query: "show create table t",
+				Expected: []sql.Row{{"t", "CREATE TABLE `t` (\n" +
+					"  `pk` int NOT NULL,\n" +
+					"  `c` int,\n" +
+					"  `d` int,\n" +
+					"  PRIMARY KEY (`pk`),\n" +
+					"  KEY `c` (`c`)\n" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+			{
+				Query: "select * from t order by pk",
+				Expected: []sql.Row{
+					{1, 100, nil},
+					{2, 200, nil},
+					{3, 300, nil},
+					{4, 400, 4000},
+				},
+			},
+			{
+				Query:    "select pk, d from t where c = 400",
+				Expected: []sql.Row{{4, 4000}},
+			},
+			{
+				Query:    "alter table t add column a int",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query: "select * from t order by pk",
+				Expected: []sql.Row{
+					{1, 100, nil, nil},
+					{2, 200, nil, nil},
+					{3, 300, nil, nil},
+					{4, 400, 4000, nil},
+				},
+			},
+		},
+	},
+	{
+		Name: "drop and add columns with ALGORITHM=INSTANT",
+		SetUpScript: []string{
+			"create table t (pk int primary key, a int, b varchar(20) default 'b', c int, key (c));",
+			"insert into t values (1, 10, 'one', 100), (2, 20, 'two', 200);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "alter table t drop column b, algorithm=instant",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "alter table t add column b varchar(20), algorithm=instant",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select * from t order by pk",
+				Expected: []sql.Row{{1, 10, 100, nil}, {2, 20, 200, nil}},
+			},
+			{
+				Query:    "update t set b = 'new' where pk = 1",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "insert into t values (3, 30, 300, 'three')",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from t order by pk",
+				Expected: []sql.Row{{1, 10, 100, "new"}, {2, 20, 200, nil}, {3, 30, 300, "three"}},
+			},
+			{
+				Query:    "alter table t drop column a, algorithm=instant",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select * from t order by pk",
+				Expected: []sql.Row{{1, 100, "new"}, {2, 200, nil}, {3, 300, "three"}},
+			},
+			{
+				Query:       "alter table t add column d int first, algorithm=instant",
+				ExpectedErr: sqle.ErrInstantAlterNotSupported,
+			},
+			{
+				Query:       "alter table t drop column c, algorithm=instant",
+				ExpectedErr: sqle.ErrInstantAlterNotSupported,
+			},
+			{
+				Query:       "alter table t modify column c bigint, algorithm=instant",
+				ExpectedErr: sqle.ErrInstantAlterNotSupported,
+			},
+			{
+				Query: "show create table t",
+				Expected: []sql.Row{{"t", "CREATE TABLE `t` (\n" +
+					"  `pk` int NOT NULL,\n" +
+					"  `c` int,\n" +
+					"  `b` varchar(20),\n" +
+					"  PRIMARY KEY (`pk`),\n" +
+					"  KEY `c` (`c`)\n" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+			{
+				Query:    "select pk, b from t where c = 300",
+				Expected: []sql.Row{{3, "three"}},
+			},
+			{
+				Query:    "alter table t add column d varchar(20) default 'algorithm=instant' first",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select pk, d from t order by pk",
+				Expected: []sql.Row{{1, "algorithm=instant"}, {2, "algorithm=instant"}, {3, "algorithm=instant"}},
+			},
+		},
+	},
+	{
+		Name: "merge after dropping a column in place",
+		SetUpScript: []string{
+			"create table t (pk int primary key, a int, b int, c int);",
+			"insert into t values (1, 10, 100, 1000), (2, 20, 200, 2000);",
+			"call dolt_commit('-Am', 'create t');",
+			"call dolt_branch('other');",
+			"alter table t drop column b;",
+			"update t set c = 1001 where pk = 1;",
+			"call dolt_commit('-am', 'drop b');",
+			"call dolt_checkout('other');",
+			"insert into t values (3, 30, 300, 3000);",
+			"update t set a = 21 where pk = 2;",
+			"call dolt_commit('-am', 'change rows');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:            "call dolt_merge('other')",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select * from t order by pk",
+				Expected: []sql.Row{{1, 10, 1001}, {2, 21, 2000}, {3, 30, 3000}},
+			},
+			{
+				Query:    "alter table t add column b int",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select * from t order by pk",
+				Expected: []sql.Row{{1, 10, 1001, nil}, {2, 21, 2000, nil}, {3, 30, 3000, nil}},
+			},
+		},
+	},
 	{
 		Name:        "drop primary key column",
 		SetUpScript: SimpsonsSetup,
@@ -1560,6 +1560,26 @@ func TestSchemaDiffTableFunctionPrepared(t *testing.T) {
 	RunSchemaDiffTableFunctionTestsPrepared(t, harness)
 }
 
+func TestSchemaObjectDiffTableFunction(t *testing.T) {
+	harness := newDoltEnginetestHarness(t)
+	RunSchemaObjectDiffTableFunctionTests(t, harness)
+}
+
+func TestSchemaObjectDiffTableFunctionPrepared(t *testing.T) {
+	harness := newDoltEnginetestHarness(t)
+	RunSchemaObjectDiffTableFunctionTestsPrepared(t, harness)
+}
+
+func TestChangesTableFunction(t *testing.T) {
+	harness := newDoltEnginetestHarness(t)
+	RunChangesTableFunctionTests(t, harness)
+}
+
+func TestChangesTableFunctionPrepared(t *testing.T) {
+	harness := newDoltEnginetestHarness(t)
+	RunChangesTableFunctionTestsPrepared(t, harness)
+}
+
 func TestDoltDatabaseCollationDiffs(t *testing.T) {
 	harness := newDoltEnginetestHarness(t)
 	RunDoltDatabaseCollationDiffsTests(t, harness)
@@ -512,6 +512,15 @@ func RunVersionedViewsTest(t *testing.T, h DoltEnginetestHarness) {
 }
 
 func RunVariableTest(t *testing.T, h DoltEnginetestHarness) {
+	// Dolt rejects the isolation levels it doesn't support, see the Dolt version of this test in DoltSystemVariables
+	tests := make([]queries.ScriptTest, 0, len(queries.VariableQueries))
+	for _, test := range queries.VariableQueries {
+		if test.Name != "set transaction" {
+			tests = append(tests, test)
+		}
+	}
+	queries.VariableQueries = tests
+
 	defer h.Close()
 	enginetest.TestVariables(t, h)
 	for _, script := range DoltSystemVariables {
@@ -607,6 +616,41 @@ func RunTransactionTests(t *testing.T, h DoltEnginetestHarness) {
 			enginetest.TestTransactionScript(t, h, script)
 		}()
 	}
+	for _, script := range DoltIsolationLevelTests {
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestTransactionScript(t, h, script)
+		}()
+	}
+	for _, script := range DoltSavepointTests {
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestTransactionScript(t, h, script)
+		}()
+	}
+	for _, script := range DoltXaTransactionTests {
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestTransactionScript(t, h, script)
+		}()
+	}
+	for _, script := range DoltRowLockTests {
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestTransactionScript(t, h, script)
+		}()
+	}
+	for _, script := range DoltReadOnlyTransactionTests {
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestTransactionScript(t, h, script)
+		}()
+	}
 }
 
 func RunBranchTransactionTest(t *testing.T, h DoltEnginetestHarness) {
@@ -635,6 +679,14 @@ func RunMultiDbTransactionsTest(t *testing.T, h DoltEnginetestHarness) {
 			enginetest.TestTransactionScript(t, h, script)
 		}()
 	}
+
+	for _, script := range MultiDbAtomicCommitTests {
+		func() {
+			h := h.NewHarness(t)
+			defer h.Close()
+			enginetest.TestTransactionScript(t, h, script)
+		}()
+	}
 }
 
 func RunMultiDbTransactionsPreparedTest(t *testing.T, h DoltEnginetestHarness) {
@@ -1370,6 +1422,50 @@ func RunSchemaDiffTableFunctionTestsPrepared(t *testing.T, harness DoltEnginetes
 	}
 }
 
+func RunSchemaObjectDiffTableFunctionTests(t *testing.T, harness DoltEnginetestHarness) {
+	for _, test := range SchemaObjectDiffTableFunctionScriptTests {
+		t.Run(test.Name, func(t *testing.T) {
+			harness = harness.NewHarness(t)
+			defer harness.Close()
+			harness.Setup(setup.MydbData)
+			enginetest.TestScript(t, harness, test)
+		})
+	}
+}
+
+func RunSchemaObjectDiffTableFunctionTestsPrepared(t *testing.T, harness DoltEnginetestHarness) {
+	for _, test := range SchemaObjectDiffTableFunctionScriptTests {
+		t.Run(test.Name, func(t *testing.T) {
+			harness = harness.NewHarness(t)
+			defer harness.Close()
+			harness.Setup(setup.MydbData)
+			enginetest.TestScriptPrepared(t, harness, test)
+		})
+	}
+}
+
+func RunChangesTableFunctionTests(t *testing.T, harness DoltEnginetestHarness) {
+	for _, test := range ChangesTableFunctionScriptTests {
+		t.Run(test.Name, func(t *testing.T) {
+			harness = harness.NewHarness(t)
+			defer harness.Close()
+			harness.Setup(setup.MydbData)
+			enginetest.TestScript(t, harness, test)
+		})
+	}
+}
+
+func RunChangesTableFunctionTestsPrepared(t *testing.T, harness DoltEnginetestHarness) {
+	for _, test := range ChangesTableFunctionScriptTests {
+		t.Run(test.Name, func(t *testing.T) {
+			harness = harness.NewHarness(t)
+			defer harness.Close()
+			harness.Setup(setup.MydbData)
+			enginetest.TestScriptPrepared(t, harness, test)
+		})
+	}
+}
+
 func RunDoltDatabaseCollationDiffsTests(t *testing.T, harness DoltEnginetestHarness) {
 	for _, test := range DoltDatabaseCollationScriptTests {
 		t.Run(test.Name, func(t *testing.T) {
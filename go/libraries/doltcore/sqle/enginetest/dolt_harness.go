@@ -34,8 +34,15 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/assertions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cte"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/explainanalyze"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/kvexec"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/memstats"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/optimizertrace"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/querycache"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/statsnoms"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/statspro"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
@@ -144,10 +151,11 @@ func newDoltHarnessForLocalFilesystem(t *testing.T) *DoltHarness {
 }
 
 var defaultSkippedQueries = []string{
-	"show variables",             // we set extra variables
-	"show create table fk_tbl",   // we create an extra key for the FK that vanilla gms does not
-	"show indexes from",          // we create / expose extra indexes (for foreign keys)
-	"show global variables like", // we set extra variables
+	"show variables",                              // we set extra variables
+	"show create table fk_tbl",                    // we create an extra key for the FK that vanilla gms does not
+	"show indexes from",                           // we create / expose extra indexes (for foreign keys)
+	"show global variables like",                  // we set extra variables
+	"select * from information_schema.partitions", // we return a row for each table, vanilla gms returns none
 }
 
 // Setup sets the setup scripts for this DoltHarness's engine
@@ -252,7 +260,14 @@ func (d *DoltHarness) NewEngine(t *testing.T) (enginetest.QueryEngine, error) {
 		if err != nil {
 			return nil, err
 		}
-		e.Analyzer.ExecBuilder = rowexec.NewOverrideBuilder(kvexec.Builder{})
+		e.Analyzer.ExecBuilder = rowexec.NewOverrideBuilder(querycache.NewBuilder(cte.NewBuilder(explainanalyze.NewBuilder(kvexec.Builder{})), querycache.NewCache()))
+		d.provider.(*sqle.DoltDatabaseProvider).Register(optimizertrace.NewProcedure(e))
+		d.provider.(*sqle.DoltDatabaseProvider).Register(memstats.NewProcedure())
+		d.provider.(*sqle.DoltDatabaseProvider).Register(assertions.NewProcedure(e))
+		d.provider.(*sqle.DoltDatabaseProvider).SetAssertionVerifier(assertions.NewVerifier(e))
+		d.provider.(*sqle.DoltDatabaseProvider).SetPrivilegeChecker(e.Analyzer.Catalog.MySQLDb)
+		d.provider.(*sqle.DoltDatabaseProvider).RegisterFunctions(dfunctions.UserLockFunctions(e.LS)...)
+		e.Analyzer.Catalog.InfoSchema = sqle.NewInformationSchemaDatabase(e.Analyzer.Catalog.InfoSchema)
 		d.engine = e
 
 		ctx := enginetest.NewContext(d)
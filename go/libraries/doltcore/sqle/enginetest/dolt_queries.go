@@ -1429,6 +1429,106 @@ var DoltScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "decimal secondary index range scans",
+		SetUpScript: []string{
+			"create table t (pk int primary key, d decimal(5,2), key (d));",
+			"insert into t values (1, -10.00), (2, -1.00), (3, -0.01), (4, 0.00), (5, 0.01), (6, 1.00), (7, 1.01), (8, 10.00), (9, 999.99), (10, null);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select pk from t where d < 0 order by d;",
+				Expected: []sql.Row{{1}, {2}, {3}},
+			},
+			{
+				Query:    "select pk from t where d >= -1 and d <= 1 order by d;",
+				Expected: []sql.Row{{2}, {3}, {4}, {5}, {6}},
+			},
+			{
+				Query:    "select pk from t where d = 1.01;",
+				Expected: []sql.Row{{7}},
+			},
+			{
+				Query:    "select pk from t where d = -0.00;",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "select pk from t where d > 1.00 and d < 999.99 order by d;",
+				Expected: []sql.Row{{7}, {8}},
+			},
+			{
+				Query:    "select pk from t where d > 1 order by d;",
+				Expected: []sql.Row{{7}, {8}, {9}},
+			},
+		},
+	},
+	{
+		Name: "user-level locks",
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select get_lock('Flyway-Lock', 0)",
+				Expected: []sql.Row{{int8(1)}},
+			},
+			{
+				Query:    "select is_free_lock('flyway-lock'), is_used_lock('FLYWAY-LOCK') is not null",
+				Expected: []sql.Row{{int8(0), true}},
+			},
+			{
+				Query:    "select get_lock('flyway-lock', 1)",
+				Expected: []sql.Row{{int8(1)}},
+			},
+			{
+				Query:    "select release_lock('FLYWAY-lock'), is_free_lock('flyway-lock')",
+				Expected: []sql.Row{{int8(1), int8(0)}},
+			},
+			{
+				Query:    "select release_lock('flyway-lock'), is_free_lock('Flyway-Lock'), is_used_lock('flyway-lock')",
+				Expected: []sql.Row{{int8(1), int8(1), nil}},
+			},
+			{
+				Query:    "select release_lock('flyway-lock')",
+				Expected: []sql.Row{{int8(0)}},
+			},
+			{
+				Query:    "select release_lock('never-taken'), get_lock(null, 0), is_free_lock(null)",
+				Expected: []sql.Row{{nil, nil, nil}},
+			},
+			{
+				Query:          "select get_lock('', 0)",
+				ExpectedErrStr: "Incorrect user-level lock name ''.",
+			},
+			{
+				Query:          "select is_free_lock(repeat('a', 65))",
+				ExpectedErrStr: "Incorrect user-level lock name '" + strings.Repeat("a", 65) + "'.",
+			},
+			{
+				Query:    "select get_lock(repeat('a', 64), 0), release_lock(repeat('A', 64))",
+				Expected: []sql.Row{{int8(1), int8(1)}},
+			},
+		},
+	},
+	{
+		Name: "explain analyze only runs queries",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"insert into t values (1), (2), (3);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:       "explain analyze insert into t values (4);",
+				ExpectedErr: sql.ErrSyntaxError,
+			},
+			{
+				Query:    "select count(*) from t;",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				// the timings vary, so the plan is checked by the bats tests
+				Query:            "explain analyze select * from t where pk > 1;",
+				SkipResultsCheck: true,
+			},
+		},
+	},
 }
 
 func makeLargeInsert(sz int) string {
@@ -1875,6 +1975,128 @@ var DoltUserPrivTests = []queries.UserPrivilegeTest{
 			},
 		},
 	},
+	{
+		Name: "query result cache is invalidated when data changes",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 10), (2, 20);",
+			"set global dolt_query_cache_size = 10;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 10}, {2, 20}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 10}, {2, 20}},
+			},
+			{
+				Query:    "insert into t values (3, 30);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 10}, {2, 20}, {3, 30}},
+			},
+			{
+				Query:    "select * from t where c > 15 order by pk;",
+				Expected: []sql.Row{{2, 20}, {3, 30}},
+			},
+			{
+				Query:    "set @x = 25;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "select * from t where c > @x order by pk;",
+				Expected: []sql.Row{{3, 30}},
+			},
+			{
+				Query:    "set @x = 5;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "select * from t where c > @x order by pk;",
+				Expected: []sql.Row{{1, 10}, {2, 20}, {3, 30}},
+			},
+			{
+				Query:    "set global dolt_query_cache_size = 0;",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
+	{
+		Name: "memory stats flush caches",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 10), (2, 20);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:            "call dolt_memory_stats();",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:            "call dolt_memory_stats('--flush', 'node_cache', 'diff_cache');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:            "call dolt_memory_stats('--flush');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 10}, {2, 20}},
+			},
+			{
+				Query:          "call dolt_memory_stats('--flush', 'runtime');",
+				ExpectedErrStr: "dolt_memory_stats: subsystem runtime can't be flushed",
+			},
+			{
+				Query:          "call dolt_memory_stats('--flush', 'chunk_journal');",
+				ExpectedErrStr: "dolt_memory_stats: unknown subsystem chunk_journal",
+			},
+			{
+				Query:          "call dolt_memory_stats('--purge');",
+				ExpectedErrStr: "dolt_memory_stats: unknown argument --purge, expected --flush",
+			},
+		},
+	},
+	{
+		Name: "stored columns after a virtual column",
+		SetUpScript: []string{
+			"create table t (pk int primary key, v int as (pk + 1) virtual, c int);",
+			"insert into t (pk, c) values (1, 10), (2, 20);",
+			"call dolt_commit('-Am', 'create t');",
+			"alter table t add index c_idx (c);",
+			"update t set c = 30 where pk = 2;",
+			"call dolt_commit('-am', 'index c');",
+			"call dolt_checkout('-b', 'other');",
+			"insert into t (pk, c) values (3, 40);",
+			"call dolt_commit('-am', 'insert on other');",
+			"call dolt_checkout('main');",
+			"update t set c = 11 where pk = 1;",
+			"call dolt_commit('-am', 'update on main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select pk, v, c from t where c = 30;",
+				Expected: []sql.Row{{2, 3, 30}},
+			},
+			{
+				Query:    "select to_pk, to_c, from_c, diff_type from dolt_diff('HEAD~2', 'HEAD~1', 't');",
+				Expected: []sql.Row{{2, 30, 20, "modified"}},
+			},
+			{
+				Query:    "call dolt_merge('other');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select pk, v, c from t where c > 10 order by c;",
+				Expected: []sql.Row{{1, 2, 11}, {2, 3, 30}, {3, 4, 40}},
+			},
+		},
+	},
 }
 
 // HistorySystemTableScriptTests contains working tests for both prepared and non-prepared
@@ -3341,6 +3563,96 @@ var DoltInfoSchemaScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "info_schema partitions has a row for each table",
+		SetUpScript: []string{
+			"create table t (a int primary key, b int);",
+			"insert into t values (1, 1), (2, 2);",
+			"create table u (a int primary key);",
+			"create view v as select * from t;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select table_name, partition_name, partition_method, table_rows from information_schema.partitions where table_schema = 'mydb' order by 1;",
+				Expected: []sql.Row{{"t", nil, nil, uint64(2)}, {"u", nil, nil, uint64(0)}},
+			},
+		},
+	},
+	{
+		Name: "info_schema describes system tables",
+		SetUpScript: []string{
+			"create table t (a int primary key, b int unique);",
+			"create procedure p() select 1;",
+			"call dolt_commit('-Am', 'creating table t');",
+			"set @@dolt_show_system_tables = 1;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select count(*) > 0 from information_schema.tables where table_schema = 'mydb' and table_name = 'dolt_procedures';",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "select count(*) from information_schema.tables where table_schema = 'mydb' and table_name like 'dolt_%_dolt_%';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select constraint_name, column_name from information_schema.key_column_usage where table_schema = 'mydb' and table_name = 'dolt_commit_ancestors';",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select constraint_name, column_name from information_schema.key_column_usage where table_schema = 'mydb' and table_name = 'dolt_log';",
+				Expected: []sql.Row{{"commit_hash", "commit_hash"}},
+			},
+			{
+				Query:    "select constraint_name from information_schema.key_column_usage where table_schema = 'mydb' and table_name = 'dolt_history_t' and constraint_name != 'PRIMARY';",
+				Expected: []sql.Row{},
+			},
+			{
+				Query: "show create table dolt_history_t;",
+				Expected: []sql.Row{{"dolt_history_t", "CREATE TABLE `dolt_history_t` (\n" +
+					"  `a` int NOT NULL,\n" +
+					"  `b` int,\n" +
+					"  `commit_hash` char(32) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,\n" +
+					"  `committer` varchar(1024) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,\n" +
+					"  `commit_date` datetime NOT NULL,\n" +
+					"  PRIMARY KEY (`a`,`commit_hash`),\n" +
+					"  KEY `b` (`b`),\n" +
+					"  KEY `commit_hash` (`commit_hash`)\n" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+		},
+	},
+	{
+		Name: "info_schema describes keys, foreign keys, routines and triggers of committed tables",
+		SetUpScript: []string{
+			"create table parent (id int primary key, v int);",
+			"create table child (id int primary key, parent_id int, constraint fk_parent foreign key (parent_id) references parent (id) on delete cascade on update restrict);",
+			"create procedure p() select 1;",
+			"create trigger trg before insert on child for each row set new.parent_id = new.parent_id;",
+			"call dolt_commit('-Am', 'creating tables');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select constraint_name, column_name, referenced_table_name, referenced_column_name from information_schema.key_column_usage where table_schema = 'mydb' and table_name = 'child' order by 1;",
+				Expected: []sql.Row{
+					{"PRIMARY", "id", nil, nil},
+					{"fk_parent", "parent_id", "parent", "id"},
+				},
+			},
+			{
+				Query:    "select constraint_name, table_name, referenced_table_name, update_rule, delete_rule from information_schema.referential_constraints where constraint_schema = 'mydb';",
+				Expected: []sql.Row{{"fk_parent", "child", "parent", "RESTRICT", "CASCADE"}},
+			},
+			{
+				Query:    "select routine_name, routine_type from information_schema.routines where routine_schema = 'mydb';",
+				Expected: []sql.Row{{"p", "PROCEDURE"}},
+			},
+			{
+				Query:    "select trigger_name, event_manipulation, event_object_table, action_timing from information_schema.triggers where trigger_schema = 'mydb';",
+				Expected: []sql.Row{{"trg", "INSERT", "child", "BEFORE"}},
+			},
+		},
+	},
 }
 
 var DoltBranchScripts = []queries.ScriptTest{
@@ -3378,6 +3690,39 @@ var DoltBranchScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "Commit-qualified database revisions are read-only snapshots",
+		SetUpScript: []string{
+			"create table t (pk int primary key)",
+			"call dolt_commit('-Am', 'create table')",
+			"insert into t values (1)",
+			"call dolt_commit('-am', 'insert a row')",
+			"call dolt_branch('other')",
+			"use `mydb/main~`",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select count(*) from t",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select active_branch()",
+				Expected: []sql.Row{{nil}},
+			},
+			{
+				Query:          "insert into t values (2)",
+				ExpectedErrStr: "Database mydb/main~ is read-only.",
+			},
+			{
+				Query:          "call dolt_merge('other')",
+				ExpectedErrStr: "cannot change mydb/main~: database revisions pinned to a tag or commit are read-only; use a branch to make changes",
+			},
+			{
+				Query:    "select count(*) from `mydb/main`.t",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
 	{
 		Name: "Rename branches with dolt_branch procedure",
 		Assertions: []queries.ScriptTestAssertion{
@@ -3583,6 +3928,93 @@ var DoltBranchScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "Projections of dolt_branches",
+		SetUpScript: []string{
+			"call dolt_branch('other')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select behind, name, ahead from dolt_branches order by name",
+				Expected: []sql.Row{{nil, "main", nil}, {nil, "other", nil}},
+			},
+			{
+				Query:    "select name, remote, branch from dolt_branches where name = 'other'",
+				Expected: []sql.Row{{"other", "", ""}},
+			},
+			{
+				Query:    "select count(*) from dolt_branches",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "select b.name from dolt_branches b join dolt_branches o on b.hash = o.hash where o.name = 'other' order by b.name",
+				Expected: []sql.Row{{"main"}, {"other"}},
+			},
+		},
+	},
+	{
+		Name: "Delete branches merged or not merged into a commit",
+		SetUpScript: []string{
+			"create table t (pk int primary key)",
+			"call dolt_commit('-Am', 'create table')",
+			"call dolt_branch('merged1')",
+			"call dolt_branch('merged2')",
+			"call dolt_checkout('-b', 'unmerged')",
+			"insert into t values (1)",
+			"call dolt_commit('-am', 'insert a row')",
+			"call dolt_checkout('main')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select name from dolt_branches where has_ancestor('main', hash) order by name",
+				Expected: []sql.Row{{"main"}, {"merged1"}, {"merged2"}},
+			},
+			{
+				Query:          "call dolt_branch('--merged', 'main')",
+				ExpectedErrStr: "error: --merged and --no-merged can only be used to delete branches with -d or -D; use has_ancestor() with the dolt_branches system table to list them",
+			},
+			{
+				Query:          "call dolt_branch('-d', '--no-merged', 'main')",
+				ExpectedErrStr: "error: branches which aren't merged can only be deleted with -D",
+			},
+			{
+				Query:          "call dolt_branch('-d', '--merged', 'main', 'merged1')",
+				ExpectedErrStr: "error: branch names can't be given with --merged or --no-merged",
+			},
+			{
+				Query:          "call dolt_branch('-d', 'merged1', '--dry-run')",
+				ExpectedErrStr: "error: --dry-run can only be used with --merged or --no-merged",
+			},
+			{
+				Query:                 "call dolt_branch('-d', '--merged', 'main', '--dry-run')",
+				Expected:              []sql.Row{{0}},
+				ExpectedWarning:       1105,
+				ExpectedWarningsCount: 2,
+			},
+			{
+				Query:    "select count(*) from dolt_branches",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:                 "call dolt_branch('-d', '--merged', 'main')",
+				Expected:              []sql.Row{{0}},
+				ExpectedWarning:       1105,
+				ExpectedWarningsCount: 2,
+			},
+			{
+				Query:    "select name from dolt_branches order by name",
+				Expected: []sql.Row{{"main"}, {"unmerged"}},
+			},
+			{
+				Query:    "call dolt_branch('-D', '--no-merged')",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select name from dolt_branches order by name",
+				Expected: []sql.Row{{"main"}},
+			},
+		},
+	},
 }
 
 var DoltResetTestScripts = []queries.ScriptTest{
@@ -4429,6 +4861,103 @@ var LogTableFunctionScriptTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "key range given",
+		SetUpScript: []string{
+			"create table test (pk int PRIMARY KEY, c1 int)",
+			"create table keyless (c1 int)",
+			"call dolt_add('.')",
+			"call dolt_commit('-m', 'created tables [1]')",
+			"insert into test values (1, 1), (50, 50), (100, 100)",
+			"call dolt_commit('-am', 'inserted 1, 50, 100 [2]')",
+			"update test set c1 = 51 where pk = 50",
+			"call dolt_commit('-am', 'updated 50 [3]')",
+			"delete from test where pk = 100",
+			"call dolt_commit('-am', 'deleted 100 [4]')",
+			"insert into keyless values (1)",
+			"call dolt_commit('-am', 'inserted into keyless [5]')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select message from dolt_log('--tables', 'test', '--from-key', '40', '--to-key', '60');",
+				Expected: []sql.Row{
+					{"updated 50 [3]"},
+					{"inserted 1, 50, 100 [2]"},
+				},
+			},
+			{
+				Query: "select message from dolt_log('--tables', 'test', '--from-key', '60');",
+				Expected: []sql.Row{
+					{"deleted 100 [4]"},
+					{"inserted 1, 50, 100 [2]"},
+				},
+			},
+			{
+				Query: "select message from dolt_log('--tables', 'test', '--to-key', '10');",
+				Expected: []sql.Row{
+					{"inserted 1, 50, 100 [2]"},
+				},
+			},
+			{
+				Query:    "select message from dolt_log('--tables', 'test', '--from-key', '2', '--to-key', '3');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:       "select message from dolt_log('--tables', 'test,keyless', '--from-key', '1');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "select message from dolt_log('--tables', 'test', '--from-key', '[1,2]', '--to-key', '3');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "select message from dolt_log('--tables', 'test', '--from-key', '[1,');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:          "select message from dolt_log('--tables', 'keyless', '--from-key', '1');",
+				ExpectedErrStr: "--from-key and --to-key require a table with a primary key",
+			},
+		},
+	},
+	{
+		Name: "composite key range given",
+		SetUpScript: []string{
+			"create table test (name varchar(20), id int, c1 int, primary key (name, id))",
+			"call dolt_add('.')",
+			"call dolt_commit('-m', 'created table [1]')",
+			"insert into test values ('doe, jane', 1, 1), ('doe, john', 1, 1)",
+			"call dolt_commit('-am', 'inserted does [2]')",
+			"update test set c1 = 2 where name = 'doe, john'",
+			"call dolt_commit('-am', 'updated john [3]')",
+			"insert into test values ('doe, jane', 2, 1)",
+			"call dolt_commit('-am', 'inserted jane 2 [4]')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select message from dolt_log('--tables', 'test', '--from-key', 'doe, jane', '--to-key', 'doe, jane');",
+				Expected: []sql.Row{
+					{"inserted jane 2 [4]"},
+					{"inserted does [2]"},
+				},
+			},
+			{
+				Query: `select message from dolt_log('--tables', 'test', '--from-key', '["doe, jane", 2]', '--to-key', '["doe, john", 1]');`,
+				Expected: []sql.Row{
+					{"inserted jane 2 [4]"},
+					{"updated john [3]"},
+					{"inserted does [2]"},
+				},
+			},
+			{
+				Query: `select message from dolt_log('--tables', 'test', '--from-key', '["doe, john"]');`,
+				Expected: []sql.Row{
+					{"updated john [3]"},
+					{"inserted does [2]"},
+				},
+			},
+		},
+	},
 	{
 		Name: "min parents, merges, show parents, decorate",
 		SetUpScript: []string{
@@ -4607,6 +5136,57 @@ var DoltTagTestScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt-tag: SQL write dolt_tags",
+		SetUpScript: []string{
+			"CREATE TABLE test(pk int primary key);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am','created table test')",
+			"INSERT INTO test VALUES (0),(1),(2);",
+			"CALL DOLT_COMMIT('-am','inserted rows')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "INSERT INTO dolt_tags (tag_name, tag_hash) VALUES ('v1', 'HEAD~1')",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1}}},
+			},
+			{
+				Query:    "INSERT INTO dolt_tags (tag_name, tag_hash, tagger, email, date, message) VALUES ('v2', 'main', 'Jane Doe', 'jane@doe.com', '2022-01-02 03:04:05', 'create tag v2')",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1}}},
+			},
+			{
+				Query: "SELECT tag_name, tag_hash = hashof(tag_name), tagger, email, message from dolt_tags",
+				Expected: []sql.Row{
+					{"v1", true, "billy bob", "bigbillieb@fake.horse", ""},
+					{"v2", true, "Jane Doe", "jane@doe.com", "create tag v2"},
+				},
+			},
+			{
+				Query:    "SELECT cast(date as char) from dolt_tags where tag_name = 'v2'",
+				Expected: []sql.Row{{"2022-01-02 03:04:05"}},
+			},
+			{
+				Query:          "INSERT INTO dolt_tags (tag_name, tag_hash) VALUES ('v1', 'HEAD')",
+				ExpectedErrStr: "already exists",
+			},
+			{
+				Query:    "UPDATE dolt_tags SET tag_hash = hashof('HEAD'), message = 'moved v1' WHERE tag_name = 'v1'",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "SELECT tag_name, tag_hash = hashof('HEAD'), message from dolt_tags",
+				Expected: []sql.Row{{"v1", true, "moved v1"}, {"v2", true, "create tag v2"}},
+			},
+			{
+				Query:    "DELETE FROM dolt_tags WHERE tag_name = 'v2'",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1}}},
+			},
+			{
+				Query:    "SELECT tag_name from dolt_tags",
+				Expected: []sql.Row{{"v1"}},
+			},
+		},
+	},
 	{
 		Name: "dolt-tag: SQL use a tag as a ref for merge",
 		SetUpScript: []string{
@@ -5026,10 +5606,13 @@ var DoltReflogTestScripts = []queries.ScriptTest{
 				Expected: []sql.Row{{0}},
 			},
 			{
-				// Calling dolt_gc() invalidates the session, so we have to ask this assertion to create a new session
+				// Calling dolt_gc() invalidates the session, so we have to ask this assertion to create a new session.
+				// The reflog is archived before the chunk journal is rewritten, so its entries survive.
 				NewSession: true,
 				Query:      "select ref, commit_hash, commit_message from dolt_reflog('main')",
-				Expected:   []sql.Row{},
+				Expected: []sql.Row{
+					{"refs/heads/main", doltCommit, "Initialize data repository"},
+				},
 			},
 		},
 	},
@@ -5059,10 +5642,34 @@ var DoltReflogTestScripts = []queries.ScriptTest{
 				Expected: []sql.Row{{0}},
 			},
 			{
-				// Calling dolt_gc() invalidates the session, so we have to force this test to create a new session
+				// Calling dolt_gc() invalidates the session, so we have to force this test to create a new session.
+				// The reflog is archived before the chunk journal is rewritten, so its entries survive.
 				NewSession: true,
 				Query:      "select ref, commit_hash, commit_message from dolt_reflog('main')",
-				Expected:   []sql.Row{},
+				Expected: []sql.Row{
+					{"refs/heads/main", doltCommit, "inserting row 2"},
+					{"refs/heads/main", doltCommit, "inserting row 1"},
+					{"refs/heads/main", doltCommit, "creating table t1"},
+					{"refs/heads/main", doltCommit, "Initialize data repository"},
+				},
+			},
+			{
+				Query:    "insert into t1 values(3);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "call dolt_commit('-Am', 'inserting row 3');",
+				Expected: []sql.Row{{doltCommit}},
+			},
+			{
+				Query: "select ref, commit_hash, commit_message from dolt_reflog('main')",
+				Expected: []sql.Row{
+					{"refs/heads/main", doltCommit, "inserting row 3"},
+					{"refs/heads/main", doltCommit, "inserting row 2"},
+					{"refs/heads/main", doltCommit, "inserting row 1"},
+					{"refs/heads/main", doltCommit, "creating table t1"},
+					{"refs/heads/main", doltCommit, "Initialize data repository"},
+				},
 			},
 		},
 	},
@@ -7152,6 +7759,69 @@ END`,
 }
 
 var DoltSystemVariables = []queries.ScriptTest{
+	{
+		Name: "set transaction isolation level",
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "set transaction isolation level serializable, read only",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "select @@transaction_isolation, @@transaction_read_only",
+				Expected: []sql.Row{{"SERIALIZABLE", 1}},
+			},
+			{
+				Query:          "set transaction read write, isolation level read uncommitted",
+				ExpectedErrStr: "Variable 'transaction_isolation' can't be set to the value of 'READ-UNCOMMITTED'",
+			},
+			{
+				Query:    "select @@transaction_isolation",
+				Expected: []sql.Row{{"SERIALIZABLE"}},
+			},
+			{
+				Query:                 "set transaction isolation level read committed",
+				Expected:              []sql.Row{{}},
+				ExpectedWarning:       1105,
+				ExpectedWarningsCount: 1,
+			},
+			{
+				Query:                 "set @@transaction_isolation = 'READ-COMMITTED'",
+				Expected:              []sql.Row{{}},
+				ExpectedWarning:       1105,
+				ExpectedWarningsCount: 1,
+			},
+			{
+				Query:                 "set @@tx_isolation = 'read-committed'",
+				Expected:              []sql.Row{{}},
+				ExpectedWarning:       1105,
+				ExpectedWarningsCount: 1,
+			},
+			{
+				Query:    "select @@transaction_isolation, @@tx_isolation",
+				Expected: []sql.Row{{"READ-COMMITTED", "READ-COMMITTED"}},
+			},
+			{
+				Query:    "set transaction read write, isolation level repeatable read",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "select @@transaction_isolation, @@transaction_read_only",
+				Expected: []sql.Row{{"REPEATABLE-READ", 0}},
+			},
+			{
+				Query:    "set global transaction isolation level serializable",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "select @@global.transaction_isolation, @@session.transaction_isolation",
+				Expected: []sql.Row{{"SERIALIZABLE", "REPEATABLE-READ"}},
+			},
+			{
+				Query:    "set global transaction isolation level repeatable read",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
 	{
 		Name: "DOLT_SHOW_SYSTEM_TABLES",
 		SetUpScript: []string{
@@ -6199,3 +6199,188 @@ var QueryDiffTableScriptTests = []queries.ScriptTest{
 		},
 	},
 }
+
+var SchemaObjectDiffTableFunctionScriptTests = []queries.ScriptTest{
+	{
+		Name: "views, triggers, events and procedures",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 int);",
+			"create view v1 as select pk from t;",
+			"create view v2 as select c1 from t;",
+			"create trigger trg1 before insert on t for each row set new.c1 = new.c1 + 1;",
+			"create procedure p1() select 1;",
+			"call dolt_commit('-Am', 'commit 1');",
+
+			"drop view v1;",
+			"create view v1 as select pk, c1 from t;",
+			"drop view v2;",
+			"drop procedure p1;",
+			"create procedure p1() select 2;",
+			"create procedure p2() select 3;",
+			"create event e1 on schedule every 1 day disable do insert into t values (100, 100);",
+			"call dolt_commit('-Am', 'commit 2');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "select * from dolt_schema_object_diff();",
+				ExpectedErrStr: "function 'dolt_schema_object_diff' expected 1 to 3 arguments, 0 received",
+			},
+			{
+				Query:          "select * from dolt_schema_object_diff('HEAD');",
+				ExpectedErrStr: "Invalid argument to dolt_schema_object_diff: There are less than 2 arguments present, and the first does not contain '..'",
+			},
+			{
+				Query: "select object_type, name, diff_type from dolt_schema_object_diff('HEAD~', 'HEAD');",
+				Expected: []sql.Row{
+					{"event", "e1", "added"},
+					{"procedure", "p1", "modified"},
+					{"procedure", "p2", "added"},
+					{"view", "v1", "modified"},
+					{"view", "v2", "removed"},
+				},
+			},
+			{
+				Query: "select from_definition, to_definition, diff from dolt_schema_object_diff('HEAD~..HEAD', 'P1');",
+				Expected: []sql.Row{
+					{"create procedure p1() select 1", "create procedure p1() select 2", "-create procedure p1() select 1\n+create procedure p1() select 2"},
+				},
+			},
+			{
+				Query: "select diff_type, from_definition, to_definition from dolt_schema_object_diff('HEAD~', 'HEAD', 'v2');",
+				Expected: []sql.Row{
+					{"removed", "create view v2 as select c1 from t", nil},
+				},
+			},
+			{
+				Query: "select object_type, name, diff_type from dolt_schema_object_diff('HEAD', 'HEAD~');",
+				Expected: []sql.Row{
+					{"event", "e1", "removed"},
+					{"procedure", "p1", "modified"},
+					{"procedure", "p2", "removed"},
+					{"view", "v1", "modified"},
+					{"view", "v2", "added"},
+				},
+			},
+			{
+				Query:    "select * from dolt_schema_object_diff('HEAD', 'HEAD');",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "working set changes",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'commit 1');",
+			"create trigger trg1 before insert on t for each row set new.pk = new.pk * 2;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select object_type, name, diff_type, to_definition from dolt_schema_object_diff('HEAD', 'WORKING');",
+				Expected: []sql.Row{
+					{"trigger", "trg1", "added", "create trigger trg1 before insert on t for each row set new.pk = new.pk * 2"},
+				},
+			},
+		},
+	},
+}
+
+var ChangesTableFunctionScriptTests = []queries.ScriptTest{
+	{
+		Name: "reading and advancing a change cursor",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(10));",
+			"call dolt_commit('-Am', 'create t');",
+			"call dolt_change_cursor('create', 'warehouse');",
+			"insert into t values (1, 'one'), (2, 'two');",
+			"call dolt_commit('-am', 'insert rows');",
+			"update t set c1 = 'uno' where pk = 1;",
+			"delete from t where pk = 2;",
+			"call dolt_commit('-am', 'update rows');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "select * from dolt_changes();",
+				ExpectedErrStr: "function 'dolt_changes' expected 1 arguments, 0 received",
+			},
+			{
+				Query:          "select * from dolt_changes('missing');",
+				ExpectedErrStr: "change cursor not found: missing",
+			},
+			{
+				Query:    "select name, branch, commit_hash = hashof('HEAD~2') from dolt_change_cursors;",
+				Expected: []sql.Row{{"warehouse", "main", true}},
+			},
+			{
+				Query: "select table_name, diff_type, primary_key, from_row->>'$.c1', to_row->>'$.c1' from dolt_changes('warehouse');",
+				Expected: []sql.Row{
+					{"t", "added", gmstypes.MustJSON(`{"pk": 1}`), nil, "one"},
+					{"t", "added", gmstypes.MustJSON(`{"pk": 2}`), nil, "two"},
+					{"t", "modified", gmstypes.MustJSON(`{"pk": 1}`), "one", "uno"},
+					{"t", "removed", gmstypes.MustJSON(`{"pk": 2}`), "two", nil},
+				},
+			},
+			{
+				Query:    "select count(distinct commit_hash) from dolt_changes('warehouse');",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "call dolt_change_cursor('advance', 'warehouse', 'HEAD~');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select diff_type from dolt_changes('warehouse');",
+				Expected: []sql.Row{{"modified"}, {"removed"}},
+			},
+			{
+				Query:    "call dolt_change_cursor('advance', 'warehouse');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select count(*) from dolt_changes('warehouse');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:          "call dolt_change_cursor('create', 'warehouse');",
+				ExpectedErrStr: "change cursor already exists: warehouse",
+			},
+			{
+				Query:    "call dolt_change_cursor('delete', 'warehouse');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select count(*) from dolt_change_cursors;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "change cursor on another branch",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'create t');",
+			"call dolt_branch('feature');",
+			"call dolt_change_cursor('create', 'feature_changes', '--branch', 'feature');",
+			"call dolt_checkout('feature');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'insert on feature');",
+			"call dolt_checkout('main');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'insert on main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select table_name, diff_type, to_row from dolt_changes('feature_changes');",
+				Expected: []sql.Row{{"t", "added", gmstypes.MustJSON(`{"pk": 1}`)}},
+			},
+			{
+				Query:    "select branch from dolt_change_cursors;",
+				Expected: []sql.Row{{"feature"}},
+			},
+			{
+				Query:          "call dolt_change_cursor('advance', 'feature_changes', '--branch', 'main');",
+				ExpectedErrStr: "error: the branch of a change cursor can't be changed",
+			},
+		},
+	},
+}
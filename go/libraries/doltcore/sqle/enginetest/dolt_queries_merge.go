@@ -340,7 +340,7 @@ var MergeScripts = []queries.ScriptTest{
 		Assertions: []queries.ScriptTestAssertion{
 			{
 				Query:          "CALL DOLT_MERGE('feature-branch')",
-				ExpectedErrStr: "this operation is not supported while in a detached head state",
+				ExpectedErrStr: "cannot change mydb/main~: database revisions pinned to a tag or commit are read-only; use a branch to make changes",
 			},
 		},
 	},
@@ -460,6 +460,44 @@ var SchemaChangeTestsBasicCases = []MergeScriptTest{
 			},
 		},
 	},
+	{
+		Name: "adding indexes to one side, changing rows on the other side",
+		AncSetUpScript: []string{
+			"create table t (pk int primary key, col1 int, col2 varchar(100));",
+			"insert into t values (1, 10, 'one'), (2, 20, 'two'), (3, 30, 'three');",
+		},
+		RightSetUpScript: []string{
+			"alter table t add index idx1 (col1);",
+			"alter table t add unique index idx2 (col2);",
+		},
+		LeftSetUpScript: []string{
+			"update t set col1 = 11 where pk = 1;",
+			"delete from t where pk = 2;",
+			"insert into t values (4, 40, 'four');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0, "merge successful"}},
+			},
+			{
+				Query:    "select pk from t where col1 = 11;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select pk from t where col1 = 20;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select pk from t where col2 = 'four';",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "select pk, col2 from t order by col2;",
+				Expected: []sql.Row{{4, "four"}, {1, "one"}, {3, "three"}},
+			},
+		},
+	},
 	{
 		// TODO: Need another test with a different type for the same column name, and verify it's an error?
 		Name: "dropping and adding a column with the same name",
@@ -813,6 +813,64 @@ var DoltTransactionTests = []queries.TransactionTest{
 			},
 		},
 	},
+	{
+		Name: "index added while other transactions write to the table",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int)",
+			"insert into t values (1, 10), (2, 20), (3, 30)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (4, 40)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ update t set c = 21 where pk = 2",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				// the index is built from the rows client b's transaction started with, and caught up with client a's
+				Query:    "/* client b */ alter table t add index idx_c (c)",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "/* client b */ select pk from t where c = 40",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ delete from t where pk = 3",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ insert into t values (5, 50)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select pk, c from t where c >= 20 order by pk",
+				Expected: []sql.Row{{2, 21}, {4, 40}, {5, 50}},
+			},
+			{
+				Query:    "/* client b */ select pk from t where c = 20 or c = 30",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select pk from t where c = 50",
+				Expected: []sql.Row{{5}},
+			},
+			{
+				Query:    "/* client a */ show create table t",
+				Expected: []sql.Row{{"t", "CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `c` int,\n  PRIMARY KEY (`pk`),\n  KEY `idx_c` (`c`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+		},
+	},
 }
 
 var DoltConflictHandlingTests = []queries.TransactionTest{
@@ -2936,6 +2994,47 @@ var MultiDbTransactionTests = []queries.ScriptTest{
 			"call dolt_branch('b1')",
 			"set autocommit = 0",
 		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "insert into t1 values (1)",
+				Expected: []sql.Row{
+					{types.OkResult{RowsAffected: 1}},
+				},
+			},
+			{
+				Query: "insert into `mydb/b1`.t1 values (2)",
+				Expected: []sql.Row{
+					{types.OkResult{RowsAffected: 1}},
+				},
+			},
+			{
+				Query:    "commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from t1 order by a",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from `mydb/b1`.t1 order by a",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "select * from dolt_status",
+				Expected: []sql.Row{{"t1", false, "modified"}},
+			},
+		},
+	},
+	{
+		Name: "committing to more than one branch at a time with dolt_transaction_commit",
+		SetUpScript: []string{
+			"create table t1 (a int)",
+			"call dolt_add('.')",
+			"call dolt_commit('-am', 'new table')",
+			"call dolt_branch('b1')",
+			"set autocommit = 0",
+			"set dolt_transaction_commit = 1",
+		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
 				Query: "insert into t1 values (1)",
@@ -2951,7 +3050,7 @@ var MultiDbTransactionTests = []queries.ScriptTest{
 			},
 			{
 				Query:          "commit",
-				ExpectedErrStr: "Cannot commit changes on more than one branch / database",
+				ExpectedErrStr: "Cannot commit changes on more than one branch / database with @@dolt_transaction_commit enabled",
 			},
 		},
 	},
@@ -2982,8 +3081,16 @@ var MultiDbTransactionTests = []queries.ScriptTest{
 				},
 			},
 			{
-				Query:          "commit",
-				ExpectedErrStr: "Cannot commit changes on more than one branch / database",
+				Query:    "commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from `mydb/main`.t1 order by a",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from t1 order by a",
+				Expected: []sql.Row{{2}},
 			},
 		},
 	},
@@ -3011,8 +3118,154 @@ var MultiDbTransactionTests = []queries.ScriptTest{
 				},
 			},
 			{
-				Query:          "commit",
-				ExpectedErrStr: "Cannot commit changes on more than one branch / database",
+				Query:    "commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from t1 order by a",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from db2.t1 order by a",
+				Expected: []sql.Row{{2}},
+			},
+		},
+	},
+	{
+		Name: "statement writing to more than one database with autocommit",
+		SetUpScript: []string{
+			"create table t1 (a int primary key)",
+			"create database db2",
+			"create table db2.t2 (a int primary key)",
+			"create trigger copy_t1 after insert on t1 for each row insert into db2.t2 values (new.a)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "insert into t1 values (1), (2)",
+				Expected: []sql.Row{
+					{types.OkResult{RowsAffected: 2}},
+				},
+			},
+			{
+				Query:    "select * from db2.t2 order by a",
+				Expected: []sql.Row{{1}, {2}},
+			},
+		},
+	},
+}
+
+// MultiDbAtomicCommitTests are transactions which change more than one database and must be committed to all of them
+// or to none of them.
+var MultiDbAtomicCommitTests = []queries.TransactionTest{
+	{
+		Name: "concurrent transactions writing to multiple databases",
+		SetUpScript: []string{
+			"create database db1",
+			"create database db2",
+			"create table db1.t (x int primary key, y int)",
+			"insert into db1.t values (1, 1)",
+			"create table db2.t (x int primary key, y int)",
+			"insert into db2.t values (1, 1)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into db1.t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ insert into db2.t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ insert into db1.t values (3, 3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ insert into db2.t values (3, 3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from db1.t order by x",
+				Expected: []sql.Row{{1, 1}, {3, 3}},
+			},
+			{
+				Query:    "/* client b */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from db1.t order by x",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "/* client a */ select * from db2.t order by x",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}},
+			},
+		},
+	},
+	{
+		Name: "conflict in one database rolls back the changes to the others",
+		SetUpScript: []string{
+			"create database db1",
+			"create database db2",
+			"create table db1.t (x int primary key, y int)",
+			"insert into db1.t values (1, 1)",
+			"create table db2.t (x int primary key, y int)",
+			"insert into db2.t values (1, 1)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into db1.t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query: "/* client a */ update db2.t set y = 2 where x = 1",
+				Expected: []sql.Row{{types.OkResult{
+					RowsAffected: 1,
+					Info:         plan.UpdateInfo{Matched: 1, Updated: 1},
+				}}},
+			},
+			{
+				Query: "/* client b */ update db2.t set y = 3 where x = 1",
+				Expected: []sql.Row{{types.OkResult{
+					RowsAffected: 1,
+					Info:         plan.UpdateInfo{Matched: 1, Updated: 1},
+				}}},
+			},
+			{
+				Query:    "/* client b */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "/* client a */ commit",
+				ExpectedErrStr: sql.ErrLockDeadlock.New(dsess.ErrRetryTransaction.Error()).Error(),
+			},
+			{
+				Query:    "/* client b */ select * from db1.t order by x",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client b */ select * from db2.t order by x",
+				Expected: []sql.Row{{1, 3}},
 			},
 		},
 	},
@@ -3366,3 +3619,1039 @@ var MultiDbSavepointTests = []queries.TransactionTest{
 		},
 	},
 }
+
+// DoltIsolationLevelTests test the behavior of transactions at each supported isolation level.
+var DoltIsolationLevelTests = []queries.TransactionTest{
+	{
+		Name: "write skew is allowed under repeatable read",
+		SetUpScript: []string{
+			"create table oncall (name varchar(20) primary key, on_call bool)",
+			"insert into oncall values ('alice', true), ('bob', true)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select count(*) from oncall where on_call",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "/* client b */ select count(*) from oncall where on_call",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "/* client a */ update oncall set on_call = false where name = 'alice'",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client b */ update oncall set on_call = false where name = 'bob'",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select count(*) from oncall where on_call",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "write skew is prevented under serializable",
+		SetUpScript: []string{
+			"create table oncall (name varchar(20) primary key, on_call bool)",
+			"insert into oncall values ('alice', true), ('bob', true)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ set transaction isolation level serializable",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client b */ set transaction isolation level serializable",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select count(*) from oncall where on_call",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "/* client b */ select count(*) from oncall where on_call",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "/* client a */ update oncall set on_call = false where name = 'alice'",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client b */ update oncall set on_call = false where name = 'bob'",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "/* client b */ commit",
+				ExpectedErrStr: sql.ErrLockDeadlock.New(dsess.ErrSerializationFailure.Error()).Error(),
+			},
+			{
+				Query:    "/* client b */ select * from oncall order by name",
+				Expected: []sql.Row{{"alice", 0}, {"bob", 1}},
+			},
+		},
+	},
+	{
+		Name: "first committer wins for blind writes under serializable",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+			"set global transaction isolation level serializable",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (1, 1), (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(2)}},
+			},
+			{
+				Query:    "/* client b */ insert into t values (3, 3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (4, 4)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ insert into t values (4, 4)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "/* client b */ commit",
+				ExpectedErrStr: sql.ErrLockDeadlock.New(dsess.ErrSerializationFailure.Error()).Error(),
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}, {4, 4}},
+			},
+			{
+				Query:    "/* client b */ set global transaction isolation level repeatable read",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
+	{
+		Name: "serializable transactions only conflict with changes to tables they use",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+			"create table u (x int primary key, y int)",
+			"insert into t values (1, 1)",
+			"call dolt_commit('-Am', 'created tables')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ set transaction isolation level serializable",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client a */ insert into t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ insert into u values (1, 1)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t as of 'HEAD'",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client a */ insert into u values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ insert into t values (3, 3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "/* client b */ select * from u order by x",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+		},
+	},
+}
+
+// DoltSavepointTests test rolling back to savepoints in transactions that change schemas, stage changes, or create
+// dolt commits.
+var DoltSavepointTests = []queries.TransactionTest{
+	{
+		Name: "rollback to savepoint undoes schema changes",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+			"insert into t values (1, 1)",
+			"call dolt_commit('-Am', 'created t')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ savepoint s1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ create table t2 (a int primary key)",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "/* client a */ savepoint s2",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ alter table t add column z int",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "/* client a */ insert into t values (2, 2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ rollback to savepoint s2",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client a */ show tables",
+				Expected: []sql.Row{{"t"}, {"t2"}},
+			},
+			{
+				Query:    "/* client a */ rollback to savepoint s1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ show tables",
+				Expected: []sql.Row{{"t"}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from dolt_status",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "rollback to savepoint undoes staged changes",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+			"call dolt_commit('-Am', 'created t')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (1, 1)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ savepoint s1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ call dolt_add('t')",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "/* client a */ select * from dolt_status",
+				Expected: []sql.Row{{"t", true, "modified"}},
+			},
+			{
+				Query:    "/* client a */ rollback to savepoint s1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from dolt_status",
+				Expected: []sql.Row{{"t", false, "modified"}},
+			},
+		},
+	},
+	{
+		Name: "rollback to savepoint undoes changes to branches first used after the savepoint",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+			"call dolt_commit('-Am', 'created t')",
+			"call dolt_branch('b1')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ savepoint s1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into `mydb/b1`.t values (1, 1)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ create table `mydb/b1`.t2 (a int primary key)",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "/* client a */ rollback to savepoint s1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from `mydb/b1`.t",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ show tables from `mydb/b1`",
+				Expected: []sql.Row{{"t"}},
+			},
+		},
+	},
+	{
+		Name: "savepoints and dolt_commit",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+			"call dolt_commit('-Am', 'created t')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (1, 1)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ savepoint s1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:            "/* client a */ call dolt_commit('-am', 'inserted 1')",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "/* client a */ insert into t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ savepoint s2",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ alter table t add column z int",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "/* client a */ rollback to savepoint s2",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:          "/* client a */ rollback to savepoint s1",
+				ExpectedErrStr: "cannot roll back to a savepoint created before a dolt commit: a dolt commit was created on mydb/main after SAVEPOINT s1",
+			},
+			{
+				Query:    "/* client a */ release savepoint s1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:    "/* client b */ select message from dolt_log limit 1",
+				Expected: []sql.Row{{"inserted 1"}},
+			},
+		},
+	},
+}
+
+// DoltXaTransactionTests test the XA transaction statements. The engine test harness doesn't use Dolt's parser, so
+// these call DOLT_XA directly, as the parser would after rewriting the XA statements.
+var DoltXaTransactionTests = []queries.TransactionTest{
+	{
+		Name: "XA transaction prepared by one session and committed by another",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+			"insert into t values (1, 1)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ call dolt_xa('start', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('end', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('prepare', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ call dolt_xa('recover')",
+				Expected: []sql.Row{{int64(1), int64(4), int64(0), "xid1"}},
+			},
+			{
+				Query:    "/* client b */ call dolt_xa('recover convert xid')",
+				Expected: []sql.Row{{int64(1), int64(4), int64(0), "0x78696431"}},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:          "/* client b */ insert into t values (3, 3)",
+				ExpectedErrStr: dsess.ErrXaBranchPrepared.Error(),
+			},
+			{
+				Query:    "/* client b */ call dolt_xa('commit', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ call dolt_xa('recover')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:    "/* client a */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+		},
+	},
+	{
+		Name: "XA transaction can't be prepared while another prepared transaction writes the same branch",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ call dolt_xa('start', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (1, 1)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ call dolt_xa('start', 'xid2')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ insert into t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('end', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('prepare', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ call dolt_xa('end', 'xid2')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "/* client b */ call dolt_xa('prepare', 'xid2')",
+				ExpectedErrStr: dsess.ErrXaBranchPrepared.Error(),
+			},
+			{
+				Query:    "/* client b */ call dolt_xa('recover')",
+				Expected: []sql.Row{{int64(1), int64(4), int64(0), "xid1"}},
+			},
+			{
+				Query:    "/* client b */ call dolt_xa('commit', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client b */ insert into t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+		},
+	},
+	{
+		Name: "XA transaction committed in one phase",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ call dolt_xa('start', 'gtrid', 'bqual', 2)",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (1, 1)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:          "/* client a */ call dolt_xa('commit one phase', 'gtrid', 'bqual', 2)",
+				ExpectedErrStr: dsess.ErrXaRmfail("ACTIVE").Error(),
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('end', 'gtrid', 'bqual', 2)",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "/* client a */ call dolt_xa('commit one phase', 'gtrid')",
+				ExpectedErrStr: dsess.ErrXaNota.Error(),
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('commit one phase', 'gtrid', 'bqual', 2)",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}},
+			},
+		},
+	},
+	{
+		Name: "XA transaction rolled back",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ call dolt_xa('start', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (1, 1)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('end', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('rollback', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t order by x",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('start', 'xid2')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('end', 'xid2')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('prepare', 'xid2')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ call dolt_xa('rollback', 'xid2')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "/* client b */ call dolt_xa('commit', 'xid2')",
+				ExpectedErrStr: dsess.ErrXaNota.Error(),
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "statements not allowed in an XA transaction",
+		SetUpScript: []string{
+			"create table t (x int primary key, y int)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ call dolt_xa('start', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "/* client a */ call dolt_xa('start', 'xid2')",
+				ExpectedErrStr: dsess.ErrXaRmfail("ACTIVE").Error(),
+			},
+			{
+				Query:          "/* client a */ commit",
+				ExpectedErrStr: dsess.ErrXaRmfail("ACTIVE").Error(),
+			},
+			{
+				Query:          "/* client a */ call dolt_xa('prepare', 'xid1')",
+				ExpectedErrStr: dsess.ErrXaRmfail("ACTIVE").Error(),
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('end', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ call dolt_xa('rollback', 'xid1')",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "/* client b */ call dolt_xa('start', 'xid1')",
+				ExpectedErrStr: dsess.ErrXaOutside.Error(),
+			},
+			{
+				Query:    "/* client b */ rollback",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+}
+
+// DoltRowLockTests test locking reads, and the row locks taken by writes in explicit transactions. The clients in
+// these tests run one query at a time, so a query which has to wait for a lock always times out.
+var DoltRowLockTests = []queries.TransactionTest{
+	{
+		Name: "select for update nowait fails instead of waiting, and skip locked is rejected",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int)",
+			"insert into t values (1, 1), (2, 2)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t where pk = 1 for update",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:          "/* client b */ select * from t where pk = 1 for update nowait",
+				ExpectedErrStr: "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set. (errno 3572) (sqlstate HY000)",
+			},
+			{
+				Query:    "/* client b */ select * from t where pk = 2 for update nowait",
+				Expected: []sql.Row{{2, 2}},
+			},
+			{
+				Query:          "/* client b */ select * from t for update skip locked",
+				ExpectedErrStr: dsess.ErrSkipLockedNotSupported.Error(),
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from t where pk = 1 for update nowait",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client b */ commit",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "select for update blocks writes to the locked rows until the transaction ends",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int)",
+			"insert into t values (1, 1), (2, 2)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client b */ set innodb_lock_wait_timeout = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t where pk = 1 for update",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:          "/* client b */ update t set c = 10 where pk = 1",
+				ExpectedErrStr: "Lock wait timeout exceeded; try restarting transaction (errno 1205) (sqlstate HY000)",
+			},
+			{
+				Query:    "/* client b */ update t set c = 20 where pk = 2",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client a */ update t set c = c + 10 where pk = 1",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ update t set c = c + 1 where pk = 1",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client b */ select * from t order by pk",
+				Expected: []sql.Row{{1, 12}, {2, 20}},
+			},
+		},
+	},
+	{
+		Name: "rolling back releases row locks",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int)",
+			"insert into t values (1, 1)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client b */ set innodb_lock_wait_timeout = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t for update",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:          "/* client b */ delete from t where pk = 1",
+				ExpectedErrStr: "Lock wait timeout exceeded; try restarting transaction (errno 1205) (sqlstate HY000)",
+			},
+			{
+				Query:    "/* client a */ rollback",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ delete from t where pk = 1",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+		},
+	},
+	{
+		Name: "locking reads read the latest committed rows",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int)",
+			"insert into t values (1, 1)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client b */ update t set c = 5 where pk = 1",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client a */ select * from t",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client a */ select * from t where pk = 1 for update",
+				Expected: []sql.Row{{1, 5}},
+			},
+			{
+				Query:    "/* client a */ update t set c = c + 1 where pk = 1",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from t",
+				Expected: []sql.Row{{1, 6}},
+			},
+		},
+	},
+	{
+		Name: "shared locks",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int)",
+			"insert into t values (1, 1)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client b */ set innodb_lock_wait_timeout = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t lock in share mode",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client b */ select * from t lock in share mode",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:          "/* client b */ select * from t for update",
+				ExpectedErrStr: "Lock wait timeout exceeded; try restarting transaction (errno 1205) (sqlstate HY000)",
+			},
+			{
+				Query:          "/* client b */ update t set c = 2",
+				ExpectedErrStr: "Lock wait timeout exceeded; try restarting transaction (errno 1205) (sqlstate HY000)",
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ update t set c = 2",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client b */ commit",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "writes lock the rows they write while the table has locked rows",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int)",
+			"insert into t values (1, 1), (2, 2), (4, 4)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client b */ set innodb_lock_wait_timeout = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t where pk = 2 for update",
+				Expected: []sql.Row{{2, 2}},
+			},
+			{
+				Query:    "/* client a */ update t set c = 10 where pk = 1",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client a */ insert into t values (3, 3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:          "/* client b */ select * from t where pk = 1 for update",
+				ExpectedErrStr: "Lock wait timeout exceeded; try restarting transaction (errno 1205) (sqlstate HY000)",
+			},
+			{
+				Query:          "/* client b */ insert into t values (3, 30)",
+				ExpectedErrStr: "Lock wait timeout exceeded; try restarting transaction (errno 1205) (sqlstate HY000)",
+			},
+			{
+				Query:    "/* client b */ select * from t where pk = 4 for update",
+				Expected: []sql.Row{{4, 4}},
+			},
+			{
+				Query:    "/* client b */ select * from t order by pk",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {4, 4}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from t where pk = 1 for update",
+				Expected: []sql.Row{{1, 10}},
+			},
+		},
+	},
+	{
+		Name: "innodb_lock_wait_timeout",
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ select @@innodb_lock_wait_timeout",
+				Expected: []sql.Row{{50}},
+			},
+			{
+				Query:    "/* client a */ set innodb_lock_wait_timeout = 5",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ select @@session.innodb_lock_wait_timeout, @@global.innodb_lock_wait_timeout",
+				Expected: []sql.Row{{5, 50}},
+			},
+			{
+				Query:          "/* client a */ set innodb_lock_wait_timeout = 0",
+				ExpectedErrStr: "Variable 'innodb_lock_wait_timeout' can't be set to the value of '0'",
+			},
+		},
+	},
+}
+
+var DoltReadOnlyTransactionTests = []queries.TransactionTest{
+	{
+		Name: "statements which change data through functions fail in read only transactions, locking reads don't",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int)",
+			"insert into t values (1, 1)",
+			"insert into dolt_sequences (name) values ('s')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ start transaction read only",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "/* client a */ select nextval('s')",
+				ExpectedErrStr: dsess.ErrReadOnlyTransaction.Error(),
+			},
+			{
+				Query:          "/* client a */ select setval('s', 100)",
+				ExpectedErrStr: dsess.ErrReadOnlyTransaction.Error(),
+			},
+			{
+				Query:    "/* client a */ select * from t where pk = 1 for update",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client a */ select * from t where pk = 1 for share",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client a */ select * from t",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select nextval('s')",
+				Expected: []sql.Row{{int64(1)}},
+			},
+			{
+				Query:    "/* client b */ select * from t where pk = 1 for update",
+				Expected: []sql.Row{{1, 1}},
+			},
+		},
+	},
+}
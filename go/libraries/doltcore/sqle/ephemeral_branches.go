@@ -0,0 +1,160 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const ephemeralBranchesThread = "ephemeral_branch_cleanup"
+
+// ephemeralBranchCheckInterval is how often expired ephemeral branches are deleted.
+const ephemeralBranchCheckInterval = time.Second
+
+// ephemeralBranch is a branch which is deleted once it expires.
+type ephemeralBranch struct {
+	database  string
+	branch    string
+	sessionID uint32
+	expiresAt time.Time
+}
+
+// ephemeralBranches tracks the ephemeral branches of the databases in a DoltDatabaseProvider. The branches are only
+// tracked in memory, so ephemeral branches left behind by a server which stops are kept as ordinary branches.
+type ephemeralBranches struct {
+	mu       sync.Mutex
+	started  bool
+	branches map[string]ephemeralBranch
+}
+
+func newEphemeralBranches() *ephemeralBranches {
+	return &ephemeralBranches{branches: make(map[string]ephemeralBranch)}
+}
+
+func ephemeralBranchKey(dbName, branch string) string {
+	return strings.ToLower(dbName) + "/" + strings.ToLower(branch)
+}
+
+// expired removes the branches which have expired from the ones tracked and returns them.
+func (e *ephemeralBranches) expired(now time.Time) []ephemeralBranch {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var ret []ephemeralBranch
+	for key, b := range e.branches {
+		if !now.Before(b.expiresAt) {
+			ret = append(ret, b)
+			delete(e.branches, key)
+		}
+	}
+	return ret
+}
+
+// AddEphemeralBranch implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) AddEphemeralBranch(ctx *sql.Context, dbName, branch string, ttl time.Duration) error {
+	e := p.ephemeralBranches
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.started {
+		return dsess.ErrEphemeralBranchesNotRunning
+	}
+	e.branches[ephemeralBranchKey(dbName, branch)] = ephemeralBranch{
+		database:  dbName,
+		branch:    branch,
+		sessionID: ctx.Session.ID(),
+		expiresAt: time.Now().Add(ttl),
+	}
+	return nil
+}
+
+// EphemeralBranchesEnabled implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) EphemeralBranchesEnabled() bool {
+	e := p.ephemeralBranches
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.started
+}
+
+// EndSessionBranches implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) EndSessionBranches(sessionID uint32) {
+	e := p.ephemeralBranches
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	for key, b := range e.branches {
+		if b.sessionID == sessionID && now.Before(b.expiresAt) {
+			b.expiresAt = now
+			e.branches[key] = b
+		}
+	}
+}
+
+// StartEphemeralBranchCleanup starts a background thread which deletes the ephemeral branches of the databases in this
+// provider once they expire. Ephemeral branches can only be created once it's started.
+func (p *DoltDatabaseProvider) StartEphemeralBranchCleanup(ctxFactory func(context.Context) (*sql.Context, error), bThreads *sql.BackgroundThreads) error {
+	e := p.ephemeralBranches
+	e.mu.Lock()
+	e.started = true
+	e.mu.Unlock()
+
+	return bThreads.Add(ephemeralBranchesThread, func(ctx context.Context) {
+		ticker := time.NewTicker(ephemeralBranchCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			expired := e.expired(time.Now())
+			if len(expired) == 0 {
+				continue
+			}
+			sqlCtx, err := ctxFactory(ctx)
+			if err != nil {
+				continue
+			}
+			for _, b := range expired {
+				p.deleteEphemeralBranch(sqlCtx, b)
+			}
+		}
+	})
+}
+
+// deleteEphemeralBranch deletes the ephemeral branch |b|, even if it's checked out by a session or has changes which
+// haven't been merged. Branches which have already been deleted are ignored.
+func (p *DoltDatabaseProvider) deleteEphemeralBranch(ctx *sql.Context, b ephemeralBranch) {
+	db, ok := p.BaseDatabase(ctx, b.database)
+	if !ok {
+		return
+	}
+	err := actions.DeleteBranch(ctx, db.DbData(), b.branch, actions.DeleteOptions{
+		Force:                      true,
+		AllowDeletingCurrentBranch: true,
+	}, p, nil)
+	if err != nil && !errors.Is(err, doltdb.ErrBranchNotFound) {
+		ctx.GetLogger().Warnf("unable to delete expired ephemeral branch %s of database %s: %s", b.branch, b.database, err.Error())
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package explainanalyze
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/rowexec"
+
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+)
+
+// nodeStats is what the row iterators built for a plan node did. A node's iterator may be built more than once, like
+// the inner side of a lookup join, which is built for every outer row. Time and reads include those of the node's
+// children, since their iterators run inside the node's.
+type nodeStats struct {
+	loops     atomic.Uint64
+	rows      atomic.Uint64
+	time      atomic.Int64
+	nodesRead atomic.Uint64
+	cacheHits atomic.Uint64
+
+	spillFiles atomic.Uint64
+	spillRows  atomic.Uint64
+	spillBytes atomic.Uint64
+}
+
+// spiller is a row iterator which may spill rows to temporary files, like a hash join whose rows don't fit in its join
+// buffer.
+type spiller interface {
+	SpillStats() (files, rows, bytes uint64)
+}
+
+// statsBuilder is a sql.NodeExecBuilder which builds row iterators like the engine's exec builder, with the same
+// override, and records what each of them did.
+type statsBuilder struct {
+	// base is the exec builder which builds each node, consulting this builder first
+	base sql.NodeExecBuilder
+	// override is the exec builder override the engine uses, which may build a node's iterator itself
+	override sql.NodeExecBuilder
+	reads    *tree.ReadStats
+
+	mu    sync.Mutex
+	stats map[uintptr]*nodeStats
+	// building is the number of times each node is being built by |base|, which calls back into this builder for
+	// the node itself before building it
+	building map[uintptr]int
+}
+
+var _ sql.NodeExecBuilder = (*statsBuilder)(nil)
+
+// newStatsBuilder returns a statsBuilder which uses |override| like rowexec.NewOverrideBuilder, and counts the Nodes
+// read by the iterators it builds using |reads|, which must be in the context they're run with.
+func newStatsBuilder(override sql.NodeExecBuilder, reads *tree.ReadStats) *statsBuilder {
+	b := &statsBuilder{
+		override: override,
+		reads:    reads,
+		stats:    make(map[uintptr]*nodeStats),
+		building: make(map[uintptr]int),
+	}
+	b.base = rowexec.NewOverrideBuilder(b)
+	return b
+}
+
+// Build implements sql.NodeExecBuilder
+func (b *statsBuilder) Build(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	key, ok := nodeKey(n)
+	if !ok {
+		return b.buildOverride(ctx, n, r)
+	}
+
+	b.mu.Lock()
+	if b.building[key] > 0 {
+		b.building[key]--
+		b.mu.Unlock()
+		return b.buildOverride(ctx, n, r)
+	}
+	b.building[key]++
+	st, ok := b.stats[key]
+	if !ok {
+		st = &nodeStats{}
+		b.stats[key] = st
+	}
+	b.mu.Unlock()
+
+	iter, err := b.base.Build(ctx, n, r)
+	if err != nil || iter == nil {
+		return iter, err
+	}
+	st.loops.Add(1)
+	return &statsIter{iter: iter, stats: st, reads: b.reads}, nil
+}
+
+func (b *statsBuilder) buildOverride(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	if b.override == nil {
+		return nil, nil
+	}
+	return b.override.Build(ctx, n, r)
+}
+
+// nodeKey returns a key identifying the node |n|, if it's a pointer. Plan nodes are compared by identity, and the
+// few which aren't pointers aren't instrumented.
+func nodeKey(n sql.Node) (uintptr, bool) {
+	v := reflect.ValueOf(n)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return 0, false
+	}
+	return v.Pointer(), true
+}
+
+// statsIter is a row iterator which records what it did in the stats of the node it was built for.
+type statsIter struct {
+	iter  sql.RowIter
+	stats *nodeStats
+	reads *tree.ReadStats
+}
+
+var _ sql.RowIter = (*statsIter)(nil)
+
+func (it *statsIter) Next(ctx *sql.Context) (sql.Row, error) {
+	nodesRead, cacheHits := it.reads.NodesRead.Load(), it.reads.CacheHits.Load()
+	start := time.Now()
+
+	row, err := it.iter.Next(ctx)
+
+	it.stats.time.Add(int64(time.Since(start)))
+	it.stats.nodesRead.Add(it.reads.NodesRead.Load() - nodesRead)
+	it.stats.cacheHits.Add(it.reads.CacheHits.Load() - cacheHits)
+	if err == nil {
+		it.stats.rows.Add(1)
+	}
+	return row, err
+}
+
+func (it *statsIter) Close(ctx *sql.Context) error {
+	err := it.iter.Close(ctx)
+	if s, ok := it.iter.(spiller); ok {
+		files, rows, bytes := s.SpillStats()
+		it.stats.spillFiles.Add(files)
+		it.stats.spillRows.Add(rows)
+		it.stats.spillBytes.Add(bytes)
+	}
+	return err
+}
+
+// describe returns a line for each node in the plan rooted at |n|, indented by its depth, describing what its
+// iterators did.
+func (b *statsBuilder) describe(n sql.Node) []string {
+	var lines []string
+	var walk func(n sql.Node, depth int)
+	walk = func(n sql.Node, depth int) {
+		lines = append(lines, strings.Repeat("    ", depth)+"-> "+nodeLabel(n)+" "+b.describeStats(n))
+		for _, child := range n.Children() {
+			walk(child, depth+1)
+		}
+	}
+	walk(n, 0)
+	return lines
+}
+
+func (b *statsBuilder) describeStats(n sql.Node) string {
+	var st *nodeStats
+	if key, ok := nodeKey(n); ok {
+		b.mu.Lock()
+		st = b.stats[key]
+		b.mu.Unlock()
+	}
+	if st == nil || st.loops.Load() == 0 {
+		return "(never executed)"
+	}
+
+	var spilled string
+	if files := st.spillFiles.Load(); files > 0 {
+		spilled = fmt.Sprintf(" spill_files=%d spilled_rows=%d spilled_bytes=%d", files, st.spillRows.Load(), st.spillBytes.Load())
+	}
+	return fmt.Sprintf("(actual time=%.3fms rows=%d loops=%d chunks_read=%d cache_hits=%d%s)",
+		float64(st.time.Load())/float64(time.Millisecond),
+		st.rows.Load(),
+		st.loops.Load(),
+		st.nodesRead.Load(),
+		st.cacheHits.Load(),
+		spilled)
+}
+
+// nodeLabel returns the first line of the description of |n|, with the name of the table or subquery alias it reads,
+// if the description doesn't include it.
+func nodeLabel(n sql.Node) string {
+	label, _, _ := strings.Cut(n.String(), "\n")
+	label = strings.TrimSpace(label)
+	if nameable, ok := n.(sql.Nameable); ok && nameable.Name() != "" && !strings.Contains(label, nameable.Name()) {
+		label += " " + nameable.Name()
+	}
+	return label
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package explainanalyze
+
+import (
+	"errors"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+)
+
+// ErrNotReadOnly is returned when explaining a statement which may change data, since explaining a statement runs it.
+var ErrNotReadOnly = errors.New("cannot analyze statement that could have side effects")
+
+// Builder is a sql.NodeExecBuilder which runs EXPLAIN ANALYZE statements, the DescribeQuery nodes with Analyze set.
+// The statement explained is run, discarding its results, and a row is returned for each node of its plan describing
+// what it did: the time spent in it and its children, the rows it returned, the number of times it ran, the number of
+// chunks it and its children read and how many of those were already cached, and the rows it spilled to temporary
+// files, if any.
+//
+// The nodes it doesn't handle, including those of the plans it explains, are built with the exec builder override
+// the engine would otherwise use.
+type Builder struct {
+	override sql.NodeExecBuilder
+}
+
+var _ sql.NodeExecBuilder = (*Builder)(nil)
+
+// NewBuilder returns a Builder which uses |override| like rowexec.NewOverrideBuilder.
+func NewBuilder(override sql.NodeExecBuilder) *Builder {
+	return &Builder{override: override}
+}
+
+// Build implements sql.NodeExecBuilder
+func (b *Builder) Build(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	if n, ok := n.(*plan.DescribeQuery); ok && n.Format.Analyze {
+		return b.explainAnalyze(ctx, n)
+	}
+	if b.override == nil {
+		return nil, nil
+	}
+	return b.override.Build(ctx, n, r)
+}
+
+func (b *Builder) explainAnalyze(ctx *sql.Context, n *plan.DescribeQuery) (sql.RowIter, error) {
+	if !n.IsReadOnly() {
+		return nil, ErrNotReadOnly
+	}
+
+	reads := &tree.ReadStats{}
+	ctx = ctx.WithContext(tree.WithReadStats(ctx.Context, reads))
+	sb := newStatsBuilder(b.override, reads)
+	if err := run(ctx, sb, n.Child); err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for _, line := range sb.describe(n.Child) {
+		rows = append(rows, sql.Row{line})
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// run runs the plan |node| with |b|, discarding its results.
+func run(ctx *sql.Context, b *statsBuilder, node sql.Node) (err error) {
+	iter, err := b.Build(ctx, node, nil)
+	if err == nil && iter == nil {
+		// |node| isn't instrumented, but its children may be
+		iter, err = b.base.Build(ctx, node, nil)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := iter.Close(ctx); err == nil {
+			err = cerr
+		}
+	}()
+
+	for {
+		if _, err = iter.Next(ctx); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
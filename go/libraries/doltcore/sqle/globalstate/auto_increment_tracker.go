@@ -26,8 +26,9 @@ import (
 type AutoIncrementTracker interface {
 	// Current returns the current auto increment value for the given table.
 	Current(tableName string) uint64
-	// Next returns the next auto increment value for the given table, and increments the current value.
-	Next(tbl string, insertVal interface{}) (uint64, error)
+	// Next returns the next auto increment value for the given table, and increments the current value. Generated values
+	// may come from a block of values reserved for the table in the working set given.
+	Next(tbl string, ws ref.WorkingSetRef, insertVal interface{}) (uint64, error)
 	// AddNewTable adds a new table to the tracker, initializing the auto increment value to 1.
 	AddNewTable(tableName string)
 	// DropTable removes a table from the tracker.
@@ -75,6 +75,11 @@ func (ht *HistoryTable) PrimaryKeySchema() sql.PrimaryKeySchema {
 		Schema:     make(sql.Schema, len(basePkSch.Schema), len(basePkSch.Schema)+3),
 		PkOrdinals: basePkSch.PkOrdinals,
 	}
+	hasPk := len(basePkSch.PkOrdinals) > 0
+	if hasPk {
+		// A row is identified by its primary key and the commit it was read from
+		newSch.PkOrdinals = append(append([]int{}, basePkSch.PkOrdinals...), len(basePkSch.Schema))
+	}
 
 	// Returning a schema from a single table with multiple table names can confuse parts of the analyzer
 	for i, col := range basePkSch.Schema.Copy() {
@@ -84,9 +89,10 @@ func (ht *HistoryTable) PrimaryKeySchema() sql.PrimaryKeySchema {
 
 	newSch.Schema = append(newSch.Schema,
 		&sql.Column{
-			Name:   CommitHashCol,
-			Source: tableName,
-			Type:   CommitHashColType,
+			Name:       CommitHashCol,
+			Source:     tableName,
+			Type:       CommitHashColType,
+			PrimaryKey: hasPk,
 		},
 		&sql.Column{
 			Name:   CommitterCol,
@@ -189,6 +195,10 @@ func NewHistoryTable(table *DoltTable, ddb *doltdb.DoltDB, head *doltdb.Commit)
 func historyTableSchema(tableName string, table *DoltTable) sql.Schema {
 	baseSch := table.Schema().Copy()
 	newSch := make(sql.Schema, len(baseSch), len(baseSch)+3)
+	hasPk := false
+	for _, col := range baseSch {
+		hasPk = hasPk || col.PrimaryKey
+	}
 
 	for i, col := range baseSch {
 		// Returning a schema from a single table with multiple table names can confuse parts of the analyzer
@@ -198,9 +208,10 @@ func historyTableSchema(tableName string, table *DoltTable) sql.Schema {
 
 	newSch = append(newSch,
 		&sql.Column{
-			Name:   CommitHashCol,
-			Source: tableName,
-			Type:   CommitHashColType,
+			Name:       CommitHashCol,
+			Source:     tableName,
+			Type:       CommitHashColType,
+			PrimaryKey: hasPk,
 		},
 		&sql.Column{
 			Name:   CommitterCol,
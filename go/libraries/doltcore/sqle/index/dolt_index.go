@@ -25,6 +25,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/expression"
 	"github.com/dolthub/go-mysql-server/sql/fulltext"
 	sqltypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
@@ -130,7 +131,7 @@ func DoltDiffIndexesFromTable(ctx context.Context, db, tbl string, t *doltdb.Tab
 		toCols[i].Name = "to_" + col.Name
 	}
 
-	// to_ columns
+	// to_ columns. None of these indexes are unique, since a row with the same key can change in many commits.
 	toIndex := doltIndex{
 		id:                            "PRIMARY",
 		tblName:                       doltdb.DoltDiffTablePrefix + tbl,
@@ -138,7 +139,7 @@ func DoltDiffIndexesFromTable(ctx context.Context, db, tbl string, t *doltdb.Tab
 		columns:                       toCols,
 		indexSch:                      sch,
 		tableSch:                      sch,
-		unique:                        true,
+		unique:                        false,
 		comment:                       "",
 		vrw:                           t.ValueReadWriter(),
 		ns:                            t.NodeStore(),
@@ -158,7 +159,7 @@ func DoltDiffIndexesFromTable(ctx context.Context, db, tbl string, t *doltdb.Tab
 			},
 			indexSch:                      sch,
 			tableSch:                      sch,
-			unique:                        true,
+			unique:                        false,
 			comment:                       "",
 			vrw:                           t.ValueReadWriter(),
 			ns:                            t.NodeStore(),
@@ -174,7 +175,7 @@ func DoltDiffIndexesFromTable(ctx context.Context, db, tbl string, t *doltdb.Tab
 				},
 				indexSch:                      sch,
 				tableSch:                      sch,
-				unique:                        true,
+				unique:                        false,
 				comment:                       "",
 				vrw:                           t.ValueReadWriter(),
 				ns:                            t.NodeStore(),
@@ -194,7 +195,7 @@ func DoltToFromCommitIndex(tbl string) sql.Index {
 			schema.NewColumn(ToCommitIndexId, schema.DiffCommitTag, types.StringKind, false),
 			schema.NewColumn(FromCommitIndexId, schema.DiffCommitTag, types.StringKind, false),
 		},
-		unique:                        true,
+		unique:                        false,
 		comment:                       "",
 		order:                         sql.IndexOrderNone,
 		constrainedToLookupExpression: false,
@@ -319,6 +320,8 @@ func DoltHistoryIndexesFromTable(ctx context.Context, db, tbl string, t *doltdb.
 		// weren't asked for (because the index needed may not exist at all revisions)
 		di.order = sql.IndexOrderNone
 		di.constrainedToLookupExpression = false
+		// A unique key can have a different row at every revision, so no history table index is unique
+		di.unique = false
 		unorderedIndexes[i] = di
 	}
 
@@ -1208,13 +1211,13 @@ func (di *doltIndex) prollyRangesFromSqlRanges(ctx context.Context, ns tree.Node
 		fields := make([]prolly.RangeField, len(rng))
 		onlyPreciseTypes := true
 		for j, expr := range rng {
-			if !(sqltypes.IsInteger(expr.Typ) || sqltypes.IsText(expr.Typ)) {
-				// decimal, float, datetime are imperfectly serialized
+			if !(sqltypes.IsInteger(expr.Typ) || sqltypes.IsText(expr.Typ) || sqltypes.IsDecimal(expr.Typ)) {
+				// float, datetime are imperfectly serialized
 				onlyPreciseTypes = false
 			}
 			if rangeCutIsBinding(expr.LowerBound) {
 				// accumulate bound values in |tb|
-				v, err := getRangeCutValue(expr.LowerBound, rng[j].Typ)
+				v, rounded, err := getRangeCutValue(expr.LowerBound, rng[j].Typ, true)
 				if err != nil {
 					return nil, err
 				}
@@ -1225,7 +1228,7 @@ func (di *doltIndex) prollyRangesFromSqlRanges(ctx context.Context, ns tree.Node
 				bound := expr.LowerBound.TypeAsLowerBound()
 				fields[j].Lo = prolly.Bound{
 					Binding:   true,
-					Inclusive: bound == sql.Closed,
+					Inclusive: bound == sql.Closed || rounded,
 				}
 			} else {
 				fields[j].Lo = prolly.Bound{}
@@ -1241,7 +1244,7 @@ func (di *doltIndex) prollyRangesFromSqlRanges(ctx context.Context, ns tree.Node
 			if rangeCutIsBinding(expr.UpperBound) {
 				bound := expr.UpperBound.TypeAsUpperBound()
 				// accumulate bound values in |tb|
-				v, err := getRangeCutValue(expr.UpperBound, rng[i].Typ)
+				v, rounded, err := getRangeCutValue(expr.UpperBound, rng[i].Typ, false)
 				if err != nil {
 					return nil, err
 				}
@@ -1258,7 +1261,7 @@ func (di *doltIndex) prollyRangesFromSqlRanges(ctx context.Context, ns tree.Node
 
 				fields[i].Hi = prolly.Bound{
 					Binding:   true,
-					Inclusive: bound == sql.Closed || nv != v,
+					Inclusive: bound == sql.Closed || nv != v || rounded,
 				}
 			} else {
 				fields[i].Hi = prolly.Bound{}
@@ -1318,15 +1321,43 @@ func rangeCutIsBinding(c sql.RangeCut) bool {
 	}
 }
 
-func getRangeCutValue(cut sql.RangeCut, typ sql.Type) (interface{}, error) {
+// getRangeCutValue returns the value of |cut| converted to |typ|. If the value had to be rounded to |typ|, it is
+// rounded towards the inside of the range, and |rounded| is true: the bound must then be inclusive to match the same
+// values of |typ| as |cut|. |lower| is true if |cut| is the lower bound of its range.
+func getRangeCutValue(cut sql.RangeCut, typ sql.Type, lower bool) (v interface{}, rounded bool, err error) {
 	if _, ok := cut.(sql.AboveNull); ok {
-		return nil, nil
+		return nil, false, nil
+	}
+	if dt, ok := typ.(sql.DecimalType); ok {
+		return getDecimalRangeCutValue(cut, dt, lower)
 	}
 	ret, oob, err := typ.Convert(sql.GetRangeCutKey(cut))
 	if oob == sql.OutOfRange {
-		return ret, nil
+		return ret, false, nil
+	}
+	return ret, false, err
+}
+
+// getDecimalRangeCutValue returns the value of |cut| with the scale of the DECIMAL type |typ|. The value isn't
+// limited to the precision of |typ|: decimals of any size are ordered correctly against the values of the column.
+func getDecimalRangeCutValue(cut sql.RangeCut, typ sql.DecimalType, lower bool) (interface{}, bool, error) {
+	dec, err := sqltypes.InternalDecimalType.ConvertToNullDecimal(sql.GetRangeCutKey(cut))
+	if err != nil {
+		return nil, false, err
+	} else if !dec.Valid {
+		return nil, false, nil
+	}
+
+	scale := int32(typ.Scale())
+	var v decimal.Decimal
+	if lower {
+		v = dec.Decimal.RoundCeil(scale)
+	} else {
+		v = dec.Decimal.RoundFloor(scale)
 	}
-	return ret, err
+	// values of the column are written with its scale
+	v = v.Round(scale)
+	return v, !v.Equal(dec.Decimal), nil
 }
 
 // DropTrailingAllColumnExprs returns the Range with any |AllColumnExprs| at the end of it removed.
@@ -65,11 +65,14 @@ func NewSecondaryKeyBuilder(ctx *sql.Context, tableName string, sch schema.Schem
 
 				virtualExpressions[i] = expr
 				j = -1
-			} else if keyless {
-				// Skip cardinality column
-				j = b.split + 1 + sch.GetNonPKCols().TagToIdx[tag]
 			} else {
-				j = b.split + sch.GetNonPKCols().TagToIdx[tag]
+				j, _ = sch.GetNonPKCols().StoredIndexByTag(tag)
+				if keyless {
+					// Skip cardinality column
+					j = b.split + 1 + j
+				} else {
+					j = b.split + j
+				}
 			}
 		}
 		b.mapping[i] = j
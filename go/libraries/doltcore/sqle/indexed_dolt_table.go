@@ -64,6 +64,12 @@ func (t *IndexedDoltTable) LookupBuilder(ctx *sql.Context) (index.IndexScanBuild
 }
 
 func (idt *IndexedDoltTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
+	if err := idt.RecordRead(ctx); err != nil {
+		return nil, err
+	}
+	if err := lockIndexedRowsForRead(ctx, idt.DoltTable, idt.idx, lookup, idt.isDoltFormat); err != nil {
+		return nil, err
+	}
 	return index.NewRangePartitionIter(ctx, idt.DoltTable, lookup, idt.isDoltFormat)
 }
 
@@ -148,6 +154,12 @@ func (t *WritableIndexedDoltTable) LookupBuilder(ctx *sql.Context) (index.IndexS
 }
 
 func (t *WritableIndexedDoltTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
+	if err := t.RecordRead(ctx); err != nil {
+		return nil, err
+	}
+	if err := lockIndexedRowsForRead(ctx, t.DoltTable, t.idx, lookup, t.isDoltFormat); err != nil {
+		return nil, err
+	}
 	return index.NewRangePartitionIter(ctx, t.DoltTable, lookup, t.isDoltFormat)
 }
 
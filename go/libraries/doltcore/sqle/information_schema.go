@@ -0,0 +1,131 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/information_schema"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// informationSchemaDatabase is the INFORMATION_SCHEMA database, with the tables go-mysql-server leaves empty replaced
+// by tables describing Dolt databases. The other tables, like KEY_COLUMN_USAGE, REFERENTIAL_CONSTRAINTS, ROUTINES and
+// TRIGGERS, are populated by go-mysql-server from the indexes, foreign keys, stored procedures and triggers Dolt
+// databases and tables report, so they describe system and history tables as long as those report their keys
+// accurately.
+type informationSchemaDatabase struct {
+	sql.Database
+	tables map[string]sql.Table
+}
+
+var _ sql.Database = informationSchemaDatabase{}
+
+// NewInformationSchemaDatabase returns the INFORMATION_SCHEMA database |base| with Dolt's implementations of the
+// tables it leaves empty.
+func NewInformationSchemaDatabase(base sql.Database) sql.Database {
+	partitions, ok, err := base.GetTableInsensitive(sql.NewEmptyContext(), information_schema.PartitionsTableName)
+	if err != nil || !ok {
+		return base
+	}
+
+	return informationSchemaDatabase{
+		Database: base,
+		tables: map[string]sql.Table{
+			information_schema.PartitionsTableName: &information_schema.InformationSchemaTable{
+				TableName:   information_schema.PartitionsTableName,
+				TableSchema: partitions.Schema(),
+				Reader:      partitionsRowIter,
+			},
+		},
+	}
+}
+
+// GetTableInsensitive implements sql.Database
+func (db informationSchemaDatabase) GetTableInsensitive(ctx *sql.Context, tblName string) (sql.Table, bool, error) {
+	if tbl, ok := db.tables[strings.ToLower(tblName)]; ok {
+		return tbl, true, nil
+	}
+	return db.Database.GetTableInsensitive(ctx, tblName)
+}
+
+// partitionsRowIter returns the rows of the PARTITIONS table. Dolt tables aren't partitioned, so like MySQL does for
+// unpartitioned tables, it has a single row for each table, with a NULL partition name.
+func partitionsRowIter(ctx *sql.Context, cat sql.Catalog) (sql.RowIter, error) {
+	databases, err := information_schema.AllDatabases(ctx, cat, true)
+	if err != nil {
+		return nil, err
+	}
+
+	y2k, _, _ := types.Timestamp.Convert("2000-01-01 00:00:00")
+	var rows []sql.Row
+	for _, db := range databases {
+		if db.Database.Name() == sql.InformationSchemaDatabaseName {
+			continue
+		}
+
+		err := sql.DBTableIter(ctx, db.Database, func(t sql.Table) (cont bool, err error) {
+			var tableRows, avgRowLength, dataLength uint64
+			if st, ok := t.(sql.StatisticsTable); ok {
+				tableRows, _, err = st.RowCount(ctx)
+				if err != nil {
+					return false, err
+				}
+				dataLength, err = st.DataLength(ctx)
+				if err != nil {
+					return false, err
+				}
+				if tableRows > 0 {
+					avgRowLength = dataLength / tableRows
+				}
+			}
+
+			rows = append(rows, sql.Row{
+				db.CatalogName, // table_catalog
+				db.SchemaName,  // table_schema
+				t.Name(),       // table_name
+				nil,            // partition_name
+				nil,            // subpartition_name
+				nil,            // partition_ordinal_position
+				nil,            // subpartition_ordinal_position
+				nil,            // partition_method
+				nil,            // subpartition_method
+				nil,            // partition_expression
+				nil,            // subpartition_expression
+				nil,            // partition_description
+				tableRows,      // table_rows
+				avgRowLength,   // avg_row_length
+				dataLength,     // data_length
+				uint64(0),      // max_data_length
+				uint64(0),      // index_length
+				uint64(0),      // data_free
+				y2k,            // create_time
+				nil,            // update_time
+				nil,            // check_time
+				nil,            // checksum
+				"",             // partition_comment
+				nil,            // nodegroup
+				nil,            // tablespace_name
+			})
+			return true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/store/prolly"
 	"github.com/dolthub/dolt/go/store/prolly/tree"
@@ -37,6 +38,16 @@ type Builder struct{}
 var _ sql.NodeExecBuilder = (*Builder)(nil)
 
 func (b Builder) Build(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	if into, ok := n.(*plan.Into); ok && (into.Outfile != "" || into.Dumpfile != "") {
+		// writing a file is a change the engine doesn't prevent in read-only sessions
+		if err := dsess.CheckReadOnlySession(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if dsess.LockingReadMode(ctx) != dsess.RowLockNone {
+		// locking reads lock rows as tables are partitioned, which reading key-value pairs directly would skip
+		return nil, nil
+	}
 	switch n := n.(type) {
 	case *plan.JoinNode:
 		if n.Op.IsLookup() && !n.Op.IsPartial() {
@@ -58,6 +69,14 @@ func (b Builder) Build(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, er
 				}
 			}
 		}
+		if n.Op == plan.JoinTypeHash || n.Op == plan.JoinTypeLeftOuterHash {
+			if bufferSize := joinBufferSize(ctx); bufferSize > 0 && len(r) == 0 {
+				// (1) spilling hash joins are enabled for the session
+				// (2) inner or left outer hash join of tables or itas
+				// (3) the join condition compares columns for equality
+				return newHashJoinIter(ctx, n, bufferSize)
+			}
+		}
 	case *plan.GroupBy:
 		if len(n.GroupByExprs) == 0 && len(n.SelectedExprs) == 1 {
 			if cnt, ok := n.SelectedExprs[0].(*aggregation.Count); ok {
@@ -72,6 +91,28 @@ func (b Builder) Build(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, er
 				}
 			}
 		}
+	case *plan.Window:
+		if workers := windowWorkers(ctx); workers > 0 && len(r) == 0 {
+			// (1) incremental window evaluation is enabled for the session
+			// (2) table or ita as child, with or without a filter
+			// (3) only aggregates of numbers and ranking functions, over
+			//     ROWS frames or default frames
+			return newWindowIter(ctx, n, workers)
+		}
+	case *plan.Sort:
+		if bufferSize := sortBufferSize(ctx); bufferSize > 0 && len(r) == 0 {
+			// (1) external sorts are enabled for the session
+			// (2) table or ita as child, with or without a filter
+			// (3) only sorts by columns whose encodings order like their values
+			return newExternalSortIter(ctx, n, bufferSize)
+		}
+	case *plan.ResolvedTable:
+		if workers := parallelScanWorkers(ctx); workers > 1 && len(r) == 0 {
+			// (1) parallel scans are enabled for the session
+			// (2) a full scan of a large Dolt table
+			// (3) not the inner side of a join, which is rescanned for every outer row
+			return newParallelScanIter(ctx, n, workers)
+		}
 	default:
 	}
 	return nil, nil
@@ -293,6 +334,9 @@ func getSourceKv(ctx *sql.Context, n sql.Node, isSrc bool) (prolly.Map, prolly.M
 			if err != nil {
 				return prolly.Map{}, nil, nil, nil, nil, nil, err
 			}
+			if err = dt.RecordRead(ctx); err != nil {
+				return prolly.Map{}, nil, nil, nil, nil, nil, err
+			}
 		case *sqle.IndexedDoltTable:
 			tags = dt.ProjectedTags()
 			table, err = dt.DoltTable.DoltTable(ctx)
@@ -303,6 +347,9 @@ func getSourceKv(ctx *sql.Context, n sql.Node, isSrc bool) (prolly.Map, prolly.M
 			if err != nil {
 				return prolly.Map{}, nil, nil, nil, nil, nil, err
 			}
+			if err = dt.RecordRead(ctx); err != nil {
+				return prolly.Map{}, nil, nil, nil, nil, nil, err
+			}
 		//case *dtables.DiffTable:
 		// TODO: add interface to include system tables
 		default:
@@ -335,22 +382,25 @@ func getSourceKv(ctx *sql.Context, n sql.Node, isSrc bool) (prolly.Map, prolly.M
 		}
 
 	case *plan.ResolvedTable:
+		var doltTable *sqle.DoltTable
 		switch dt := n.UnderlyingTable().(type) {
 		case *sqle.WritableDoltTable:
-			tags = dt.ProjectedTags()
-			table, err = dt.DoltTable.DoltTable(ctx)
+			doltTable = dt.DoltTable
 		case *sqle.AlterableDoltTable:
-			tags = dt.ProjectedTags()
-			table, err = dt.DoltTable.DoltTable(ctx)
+			doltTable = dt.DoltTable
 		case *sqle.DoltTable:
-			tags = dt.ProjectedTags()
-			table, err = dt.DoltTable(ctx)
+			doltTable = dt
 		default:
 			return prolly.Map{}, nil, nil, nil, nil, nil, nil
 		}
+		tags = doltTable.ProjectedTags()
+		table, err = doltTable.DoltTable(ctx)
 		if err != nil {
 			return prolly.Map{}, nil, nil, nil, nil, nil, err
 		}
+		if err = doltTable.RecordRead(ctx); err != nil {
+			return prolly.Map{}, nil, nil, nil, nil, nil, err
+		}
 
 		sch, err = table.GetSchema(ctx)
 		if err != nil {
@@ -388,3 +438,25 @@ func getSourceKv(ctx *sql.Context, n sql.Node, isSrc bool) (prolly.Map, prolly.M
 
 	return indexMap, srcIter, dstIter, sch, tags, nil, nil
 }
+
+// rowsExaminedBatchSize is how many rows a kv iterator reads before reporting them to the process list.
+const rowsExaminedBatchSize = 1024
+
+// rowsExaminedCounter counts the rows read by a kv iterator, which reads storage directly rather than through the
+// tables the engine tracks the progress of. Rows are reported in batches, to avoid taking the process list's lock for
+// every row.
+type rowsExaminedCounter struct {
+	pending uint64
+}
+
+func (c *rowsExaminedCounter) add(ctx *sql.Context) {
+	c.pending++
+	if c.pending == rowsExaminedBatchSize {
+		c.flush(ctx)
+	}
+}
+
+func (c *rowsExaminedCounter) flush(ctx *sql.Context) {
+	dsess.AddRowsExamined(ctx, c.pending)
+	c.pending = 0
+}
@@ -65,6 +65,7 @@ type countAggKvIter struct {
 	isKeyRef bool
 	idx      int
 	done     bool
+	examined rowsExaminedCounter
 }
 
 func (l *countAggKvIter) Close(_ *sql.Context) error {
@@ -84,6 +85,7 @@ func (l *countAggKvIter) Next(ctx *sql.Context) (sql.Row, error) {
 		} else if err != nil {
 			return nil, err
 		}
+		l.examined.add(ctx)
 		if l.nullable {
 			if l.isKeyRef && k.FieldIsNull(l.idx) ||
 				v.FieldIsNull(l.idx) {
@@ -93,5 +95,6 @@ func (l *countAggKvIter) Next(ctx *sql.Context) (sql.Row, error) {
 		cnt++
 	}
 	l.done = true
+	l.examined.flush(ctx)
 	return sql.Row{cnt}, nil
 }
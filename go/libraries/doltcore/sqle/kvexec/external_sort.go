@@ -0,0 +1,272 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvexec
+
+import (
+	"context"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/pool"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/sort"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// sortMinRunSize is the smallest sorted run, in bytes, an external sort
+// keeps in memory before spilling it to disk. A run must fit any row, and
+// rows stored inline are limited to 64KB.
+var sortMinRunSize = 1024 * 1024
+
+// sortFileMax is the number of sorted runs spilled to disk before they're
+// merged into a larger run. Merging reads every run through its own buffer,
+// so the sort buffer is split between them.
+const sortFileMax = 8
+
+// sortBufferSize returns the number of bytes the session sorts rows in
+// memory with before spilling them to disk, from dolt_sort_buffer_size.
+// Zero disables external sorts.
+func sortBufferSize(ctx *sql.Context) int {
+	v, err := ctx.GetSessionVariable(ctx, dsess.DoltSortBufferSize)
+	if err != nil {
+		return 0
+	}
+	size, _, err := gmstypes.Int64.Convert(v)
+	if err != nil {
+		return 0
+	}
+	return int(size.(int64))
+}
+
+// sortRowDesc describes the tuples an external sort spills, which hold the
+// key and value tuples of a row.
+var sortRowDesc = val.NewTupleDescriptor(
+	val.Type{Enc: val.ByteStringEnc, Nullable: true},
+	val.Type{Enc: val.ByteStringEnc, Nullable: true},
+)
+
+// sortKeyField is a column rows are sorted by, read from either the key or
+// the value tuple of a row.
+type sortKeyField struct {
+	desc       val.TupleDesc
+	idx        int
+	fromKey    bool
+	descending bool
+	nullsFirst bool
+}
+
+func (f sortKeyField) compare(lKey, lVal, rKey, rVal val.Tuple) int {
+	var l, r []byte
+	if f.fromKey {
+		l, r = f.desc.GetField(f.idx, lKey), f.desc.GetField(f.idx, rKey)
+	} else {
+		l, r = f.desc.GetField(f.idx, lVal), f.desc.GetField(f.idx, rVal)
+	}
+	// matches the engine's sorter, which swaps descending values before
+	// placing nulls
+	if f.descending {
+		l, r = r, l
+	}
+	switch {
+	case l == nil && r == nil:
+		return 0
+	case l == nil:
+		if f.nullsFirst {
+			return -1
+		}
+		return 1
+	case r == nil:
+		if f.nullsFirst {
+			return 1
+		}
+		return -1
+	}
+	return f.desc.Comparator().CompareValues(f.idx, l, r, f.desc.Types[f.idx])
+}
+
+// getSortKeyFields maps |sortFields| to the columns of the source rows
+// with |tags| they sort by. Returns false if a sort field isn't a column,
+// or its storage encoding doesn't sort in the same order as its SQL values.
+func getSortKeyFields(m prolly.Map, sch schema.Schema, tags []uint64, sortFields sql.SortFields) ([]sortKeyField, bool) {
+	keylessOff := 0
+	if schema.IsKeyless(sch) {
+		keylessOff = 1
+	}
+	keyDesc, valDesc := m.Descriptors()
+	ret := make([]sortKeyField, len(sortFields))
+	for i, sf := range sortFields {
+		gf, ok := sf.Column.(*expression.GetField)
+		if !ok || gf.Index() >= len(tags) {
+			return nil, false
+		}
+		f := sortKeyField{
+			descending: sf.Order == sql.Descending,
+			nullsFirst: sf.NullOrdering == sql.NullsFirst,
+		}
+		tag := tags[gf.Index()]
+		if idx, ok := sch.GetPKCols().StoredIndexByTag(tag); ok {
+			f.desc, f.idx, f.fromKey = keyDesc, idx, true
+		} else if idx, ok := sch.GetNonPKCols().StoredIndexByTag(tag); ok && !sch.GetNonPKCols().GetByStoredIndex(idx).Virtual {
+			f.desc, f.idx = valDesc, idx+keylessOff
+		} else {
+			return nil, false
+		}
+		switch enc := f.desc.Types[f.idx].Enc; {
+		case val.IsAddrEncoding(enc), enc == val.JSONEnc, enc == val.GeometryEnc, enc == val.ExtendedEnc, enc == val.CellEnc:
+			return nil, false
+		}
+		ret[i] = f
+	}
+	return ret, true
+}
+
+// newExternalSortIter returns an iterator over the rows of |n|'s child
+// sorted by |n|'s sort fields. Rows are sorted in runs of at most
+// |bufferSize| bytes, which are spilled to temporary files and merged, so
+// large sorts don't hold every row in memory. Returns nil if |n| doesn't
+// sort the columns of a Dolt table or index scan.
+func newExternalSortIter(ctx *sql.Context, n *plan.Sort, bufferSize int) (sql.RowIter, error) {
+	srcMap, srcIter, _, srcSchema, srcTags, srcFilter, err := getSourceKv(ctx, n.Child, true)
+	if err != nil || srcSchema == nil {
+		return nil, err
+	}
+	fields, ok := getSortKeyFields(srcMap, srcSchema, srcTags, n.SortFields)
+	if !ok {
+		return nil, nil
+	}
+
+	runSize := bufferSize / sortFileMax
+	if runSize < sortMinRunSize {
+		runSize = sortMinRunSize
+	}
+
+	return &externalSortIter{
+		srcIter:   srcIter,
+		srcFilter: srcFilter,
+		fields:    fields,
+		runSize:   runSize,
+		joiner:    newRowJoiner([]schema.Schema{srcSchema}, nil, srcTags, srcMap.NodeStore()),
+		tb:        val.NewTupleBuilder(sortRowDesc),
+		pool:      srcMap.Pool(),
+	}, nil
+}
+
+type externalSortIter struct {
+	srcIter   prolly.MapIter
+	srcFilter sql.Expression
+	fields    []sortKeyField
+	runSize   int
+	joiner    *prollyToSqlJoiner
+	tb        *val.TupleBuilder
+	pool      pool.BuffPool
+
+	sorted interface {
+		IterAll(context.Context) (sort.KeyIter, error)
+		Close()
+	}
+	iter     sort.KeyIter
+	examined rowsExaminedCounter
+}
+
+var _ sql.RowIter = (*externalSortIter)(nil)
+
+func (s *externalSortIter) less(l, r val.Tuple) bool {
+	lKey, lVal := val.Tuple(sortRowDesc.GetField(0, l)), val.Tuple(sortRowDesc.GetField(1, l))
+	rKey, rVal := val.Tuple(sortRowDesc.GetField(0, r)), val.Tuple(sortRowDesc.GetField(1, r))
+	for _, f := range s.fields {
+		if cmp := f.compare(lKey, lVal, rKey, rVal); cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return false
+}
+
+func (s *externalSortIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if s.iter == nil {
+		if err := s.sort(ctx); err != nil {
+			return nil, err
+		}
+	}
+	tup, err := s.iter.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.joiner.buildRow(ctx, val.Tuple(sortRowDesc.GetField(0, tup)), val.Tuple(sortRowDesc.GetField(1, tup)))
+}
+
+// sort reads every source row into an external sorter, and starts
+// iterating over the sorted rows.
+func (s *externalSortIter) sort(ctx *sql.Context) error {
+	sorter := sort.NewTupleSorter(s.runSize, sortFileMax, s.less, tempfiles.MovableTempFileProvider)
+	// the sorted rows are merged into their own file, so the runs are
+	// removed once they're flushed
+	defer sorter.Close()
+
+	for {
+		k, v, err := s.srcIter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		s.examined.add(ctx)
+
+		if s.srcFilter != nil {
+			row, err := s.joiner.buildRow(ctx, k, v)
+			if err != nil {
+				return err
+			}
+			res, err := sql.EvaluateCondition(ctx, s.srcFilter, row)
+			if err != nil {
+				return err
+			}
+			if !sql.IsTrue(res) {
+				continue
+			}
+		}
+
+		s.tb.PutByteString(0, k)
+		s.tb.PutByteString(1, v)
+		if err = sorter.Insert(ctx, s.tb.Build(s.pool)); err != nil {
+			return err
+		}
+	}
+	s.examined.flush(ctx)
+
+	sorted, err := sorter.Flush(ctx)
+	if err != nil {
+		return err
+	}
+	s.sorted = sorted
+	s.iter, err = sorted.IterAll(ctx)
+	return err
+}
+
+func (s *externalSortIter) Close(_ *sql.Context) error {
+	if s.iter != nil {
+		s.iter.Close()
+	}
+	if s.sorted != nil {
+		s.sorted.Close()
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/planbuilder"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+func TestExternalSort(t *testing.T) {
+	defer func(minRunSize int) {
+		sortMinRunSize = minRunSize
+	}(sortMinRunSize)
+	// small runs spill and compact many files
+	sortMinRunSize = 0
+
+	tests := []struct {
+		name     string
+		query    string
+		external bool
+	}{
+		{
+			name:     "sort by primary key",
+			query:    "select * from xy order by x desc",
+			external: true,
+		},
+		{
+			name:     "sort by nullable column",
+			query:    "select * from xy order by y, x",
+			external: true,
+		},
+		{
+			name:     "sort descending with nulls",
+			query:    "select * from xy order by y desc, z, x",
+			external: true,
+		},
+		{
+			name:     "sort filtered rows",
+			query:    "select * from xy where y > 50 order by z, y desc, x",
+			external: true,
+		},
+		{
+			name:     "sort keyless table",
+			query:    "select * from keyless order by b, a",
+			external: true,
+		},
+		{
+			name:     "reject sort by expression",
+			query:    "select * from xy order by y + 1, x",
+			external: false,
+		},
+	}
+
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := sqle.NewDatabase(context.Background(), "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+
+	engine, ctx, err := sqle.NewTestEngine(dEnv, context.Background(), db)
+	require.NoError(t, err)
+	err = ctx.Session.SetSessionVariable(ctx, sql.AutoCommitSessionVar, false)
+	require.NoError(t, err)
+
+	setup := []string{
+		"create table xy (x int primary key, y int, z varchar(10))",
+		"insert into xy with recursive r(i) as (select 1 union all select i+1 from r where i < 1000) select i, if(i % 10 = 0, null, (i * 7) % 100), concat('z', i % 13) from r",
+		"create table keyless (a int, b int)",
+		"insert into keyless with recursive r(i) as (select 1 union all select i+1 from r where i < 500) select i % 50, i % 7 from r",
+	}
+	for _, q := range setup {
+		_, iter, _, err := engine.Query(ctx, q)
+		require.NoError(t, err)
+		_, err = sql.RowIterToRows(ctx, iter)
+		require.NoError(t, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ctx.Session.SetSessionVariable(ctx, dsess.DoltSortBufferSize, 0)
+			require.NoError(t, err)
+			_, iter, _, err := engine.Query(ctx, tt.query)
+			require.NoError(t, err)
+			expected, err := sql.RowIterToRows(ctx, iter)
+			require.NoError(t, err)
+
+			err = ctx.Session.SetSessionVariable(ctx, dsess.DoltSortBufferSize, 4096)
+			require.NoError(t, err)
+
+			binder := planbuilder.New(ctx, engine.EngineAnalyzer().Catalog, engine.Parser)
+			node, _, _, qFlags, err := binder.Parse(tt.query, false)
+			require.NoError(t, err)
+			node, err = engine.EngineAnalyzer().Analyze(ctx, node, nil, qFlags)
+			require.NoError(t, err)
+
+			s := getSort(node)
+			require.NotNil(t, s)
+
+			iter, err = Builder{}.Build(ctx, s, nil)
+			require.NoError(t, err)
+			_, ok := iter.(*externalSortIter)
+			require.Equalf(t, tt.external, ok, "expected external sort: %t", tt.external)
+			if !ok {
+				return
+			}
+
+			rows, err := sql.RowIterToRows(ctx, iter)
+			require.NoError(t, err)
+			require.Equal(t, expected, rows)
+		})
+	}
+}
+
+func getSort(n sql.Node) *plan.Sort {
+	var ret *plan.Sort
+	transform.Inspect(n, func(n sql.Node) bool {
+		if s, ok := n.(*plan.Sort); ok {
+			ret = s
+		}
+		return ret == nil
+	})
+	return ret
+}
@@ -0,0 +1,767 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvexec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+const (
+	// hashJoinFanout is the number of partitions the rows of a hash join
+	// are spilled to, each time they're partitioned.
+	hashJoinFanout = 16
+	// hashJoinMaxLevel is the number of times a partition that doesn't fit
+	// in the join buffer is partitioned again. Rows with the same join key
+	// always share a partition, so a partition of a skewed key may never fit,
+	// and is joined in memory once it can't be split further.
+	hashJoinMaxLevel = 4
+	// joinRowOverhead estimates the memory a buffered row uses besides its
+	// key and value tuples and the values of its fields.
+	joinRowOverhead = 64
+)
+
+// queryMemory accounts for the memory the joins of every query the server
+// runs buffer rows in, which is limited by dolt_query_memory_limit.
+var queryMemory memoryAccountant
+
+// memoryAccountant tracks memory reserved by concurrent queries.
+type memoryAccountant struct {
+	used atomic.Int64
+}
+
+// reserve reserves |n| bytes, and returns true, unless that would take the
+// memory reserved past |limit|. A limit of zero is no limit.
+func (a *memoryAccountant) reserve(n, limit int64) bool {
+	for {
+		used := a.used.Load()
+		if limit > 0 && used+n > limit {
+			return false
+		}
+		if a.used.CompareAndSwap(used, used+n) {
+			return true
+		}
+	}
+}
+
+func (a *memoryAccountant) release(n int64) {
+	a.used.Add(-n)
+}
+
+// joinBufferSize returns the number of bytes the session buffers the rows
+// of a hash join in before spilling them to disk, from
+// dolt_join_buffer_size. Zero disables spilling hash joins.
+func joinBufferSize(ctx *sql.Context) int64 {
+	v, err := ctx.GetSessionVariable(ctx, dsess.DoltJoinBufferSize)
+	if err != nil {
+		return 0
+	}
+	size, _, err := gmstypes.Int64.Convert(v)
+	if err != nil {
+		return 0
+	}
+	return size.(int64)
+}
+
+// queryMemoryLimit returns the number of bytes the joins of all queries may
+// buffer rows in together, from dolt_query_memory_limit. Zero is no limit.
+func queryMemoryLimit() int64 {
+	_, v, ok := sql.SystemVariables.GetGlobal(dsess.DoltQueryMemoryLimit)
+	if !ok {
+		return 0
+	}
+	limit, _, err := gmstypes.Int64.Convert(v)
+	if err != nil {
+		return 0
+	}
+	return limit.(int64)
+}
+
+// hashJoinKey is a pair of columns a hash join matches rows by.
+type hashJoinKey struct {
+	left, right int
+	// collation is the collation of text columns, which hash equal strings
+	// the same
+	collation sql.CollationID
+	text      bool
+}
+
+// getHashJoinKeys returns the pairs of columns compared for equality by the
+// join condition |filter|, where the columns of the left side of the join
+// come before the |leftLen| columns of the right side. Returns false if the
+// condition doesn't compare any, or if equal values of a pair of columns may
+// hash differently.
+func getHashJoinKeys(filter sql.Expression, leftLen int) ([]hashJoinKey, bool) {
+	if filter == nil {
+		return nil, false
+	}
+	var keys []hashJoinKey
+	for _, e := range expression.SplitConjunction(filter) {
+		eq, ok := e.(*expression.Equals)
+		if !ok {
+			continue
+		}
+		l, lok := eq.Left().(*expression.GetField)
+		r, rok := eq.Right().(*expression.GetField)
+		if !lok || !rok {
+			continue
+		}
+		if l.Index() >= leftLen {
+			l, r = r, l
+		}
+		if l.Index() >= leftLen || r.Index() < leftLen {
+			continue
+		}
+		key := hashJoinKey{left: l.Index(), right: r.Index() - leftLen}
+		lt, rt := l.Type(), r.Type()
+		switch {
+		case gmstypes.IsInteger(lt) && gmstypes.IsInteger(rt):
+		case gmstypes.IsText(lt) && gmstypes.IsText(rt):
+			lc, rc := lt.(sql.StringType).Collation(), rt.(sql.StringType).Collation()
+			if lc != rc {
+				continue
+			}
+			key.text, key.collation = true, lc
+		case gmstypes.IsJSON(lt), gmstypes.IsGeometry(lt), !lt.Equals(rt):
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, len(keys) > 0
+}
+
+// hashJoinSide is one of the tables a hash join reads.
+type hashJoinSide struct {
+	iter   prolly.MapIter
+	filter sql.Expression
+	joiner *prollyToSqlJoiner
+	width  int
+}
+
+// newHashJoinIter returns an iterator over the rows of the hash join |n|,
+// which buffers the rows of its right side in at most |bufferSize| bytes.
+// Rows which don't fit are partitioned by their join keys into temporary
+// files, with the rows of the left side, and the partitions are joined one
+// at a time. Returns nil if either side of |n| isn't a Dolt table or index
+// scan, or its condition doesn't compare columns for equality.
+func newHashJoinIter(ctx *sql.Context, n *plan.JoinNode, bufferSize int64) (sql.RowIter, error) {
+	right := n.Right()
+	if hl, ok := right.(*plan.HashLookup); ok {
+		right = hl.Child
+	}
+	leftMap, leftIter, _, leftSch, leftTags, leftFilter, err := getSourceKv(ctx, n.Left(), true)
+	if err != nil || leftSch == nil {
+		return nil, err
+	}
+	rightMap, rightIter, _, rightSch, rightTags, rightFilter, err := getSourceKv(ctx, right, true)
+	if err != nil || rightSch == nil {
+		return nil, err
+	}
+	if len(leftTags) != len(n.Left().Schema()) || len(rightTags) != len(right.Schema()) {
+		return nil, nil
+	}
+	keys, ok := getHashJoinKeys(n.Filter, len(leftTags))
+	if !ok {
+		return nil, nil
+	}
+
+	return &hashJoinIter{
+		left: hashJoinSide{
+			iter:   leftIter,
+			filter: leftFilter,
+			joiner: newRowJoiner([]schema.Schema{leftSch}, nil, leftTags, leftMap.NodeStore()),
+			width:  len(leftTags),
+		},
+		right: hashJoinSide{
+			iter:   rightIter,
+			filter: rightFilter,
+			joiner: newRowJoiner([]schema.Schema{rightSch}, nil, rightTags, rightMap.NodeStore()),
+			width:  len(rightTags),
+		},
+		keys:       keys,
+		filter:     n.Filter,
+		leftOuter:  n.Op == plan.JoinTypeLeftOuterHash,
+		bufferSize: bufferSize,
+		memLimit:   queryMemoryLimit(),
+	}, nil
+}
+
+// hashJoinRow is a row buffered by a hash join.
+type hashJoinRow struct {
+	key, value val.Tuple
+	row        sql.Row
+}
+
+type hashJoinIter struct {
+	left, right hashJoinSide
+	keys        []hashJoinKey
+	filter      sql.Expression
+	leftOuter   bool
+
+	bufferSize int64
+	memLimit   int64
+	reserved   int64
+
+	// table holds the buffered rows of the right side by the hash of their
+	// join keys
+	table map[uint64][]hashJoinRow
+	// probe returns the rows of the left side matched against |table|
+	probe func(ctx *sql.Context) (val.Tuple, val.Tuple, error)
+	// partitions are the spilled partitions left to join
+	partitions []*hashJoinPartition
+	// probing is the file of the left rows of the partition being joined
+	probing *spillFile
+	level   int
+
+	leftRow  sql.Row
+	matches  []hashJoinRow
+	matched  bool
+	started  bool
+	examined rowsExaminedCounter
+	stats    spillStats
+}
+
+var _ sql.RowIter = (*hashJoinIter)(nil)
+
+// spillStats counts the rows a spilling iterator wrote to temporary files.
+type spillStats struct {
+	files, rows, bytes uint64
+}
+
+// SpillStats returns the number of temporary files the join spilled rows
+// to, the number of rows it spilled, and the number of bytes they used.
+// EXPLAIN ANALYZE reports them.
+func (j *hashJoinIter) SpillStats() (files, rows, bytes uint64) {
+	return j.stats.files, j.stats.rows, j.stats.bytes
+}
+
+func (j *hashJoinIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if !j.started {
+		j.started = true
+		if err := j.build(ctx); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		for len(j.matches) > 0 {
+			m := j.matches[0]
+			j.matches = j.matches[1:]
+			row := append(append(make(sql.Row, 0, j.left.width+j.right.width), j.leftRow...), m.row...)
+			res, err := sql.EvaluateCondition(ctx, j.filter, row)
+			if err != nil {
+				return nil, err
+			}
+			if sql.IsTrue(res) {
+				j.matched = true
+				return row, nil
+			}
+		}
+		if j.leftRow != nil && j.leftOuter && !j.matched {
+			row := append(append(make(sql.Row, 0, j.left.width+j.right.width), j.leftRow...), make(sql.Row, j.right.width)...)
+			j.leftRow = nil
+			return row, nil
+		}
+
+		k, v, err := j.probe(ctx)
+		if err == io.EOF {
+			if err = j.nextPartition(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		row, err := j.left.joiner.buildRow(ctx, k, v)
+		if err != nil {
+			return nil, err
+		}
+		j.leftRow, j.matched, j.matches = nil, false, nil
+		if ok, err := j.left.accept(ctx, row); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		j.leftRow = row
+		h, ok := j.hash(row, true)
+		if ok {
+			j.matches = j.table[h]
+		}
+	}
+}
+
+// build reads the rows of the right side into |table|, and spills them to
+// partitions once they don't fit in the join buffer.
+func (j *hashJoinIter) build(ctx *sql.Context) error {
+	j.table = make(map[uint64][]hashJoinRow)
+	var spilled *hashJoinSpill
+	for {
+		k, v, err := j.right.iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		j.examined.add(ctx)
+		row, err := j.right.joiner.buildRow(ctx, k, v)
+		if err != nil {
+			return err
+		}
+		if ok, err := j.right.accept(ctx, row); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		h, ok := j.hash(row, false)
+		if !ok {
+			// a null key matches no rows
+			continue
+		}
+		if spilled == nil && !j.buffer(h, hashJoinRow{key: k, value: v, row: row}, false) {
+			if spilled, err = j.spillTable(); err != nil {
+				return err
+			}
+		}
+		if spilled != nil {
+			if err = spilled.right[j.partitionOf(h)].write(k, v); err != nil {
+				spilled.close()
+				return err
+			}
+		}
+	}
+	j.examined.flush(ctx)
+
+	if spilled == nil {
+		j.probe = func(ctx *sql.Context) (val.Tuple, val.Tuple, error) {
+			return j.left.iter.Next(ctx)
+		}
+		return nil
+	}
+
+	// the left side is partitioned like the right side, so that the rows of
+	// each partition only match rows of the same partition
+	for {
+		k, v, err := j.left.iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		j.examined.add(ctx)
+		row, err := j.left.joiner.buildRow(ctx, k, v)
+		if err != nil {
+			return err
+		}
+		if ok, err := j.left.accept(ctx, row); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		// rows with null keys match no rows, but are still returned by
+		// left outer joins
+		h, _ := j.hash(row, true)
+		if err = spilled.left[j.partitionOf(h)].write(k, v); err != nil {
+			spilled.close()
+			return err
+		}
+	}
+	j.examined.flush(ctx)
+
+	if err := spilled.finish(&j.stats); err != nil {
+		spilled.close()
+		return err
+	}
+	j.partitions = spilled.split(j.leftOuter)
+	j.probe = func(*sql.Context) (val.Tuple, val.Tuple, error) {
+		return nil, nil, io.EOF
+	}
+	return nil
+}
+
+// buffer adds |r| to |table|, and returns true, if there's room for it in
+// the join buffer and in the memory of all queries, or if |force| is true.
+func (j *hashJoinIter) buffer(h uint64, r hashJoinRow, force bool) bool {
+	size := int64(len(r.key) + len(r.value) + joinRowOverhead + 16*len(r.row))
+	if force {
+		queryMemory.reserve(size, 0)
+	} else if j.reserved+size > j.bufferSize || !queryMemory.reserve(size, j.memLimit) {
+		return false
+	}
+	j.reserved += size
+	j.table[h] = append(j.table[h], r)
+	return true
+}
+
+// spillTable moves the rows of |table| to the partitions of a new spill,
+// and releases the memory they used.
+func (j *hashJoinIter) spillTable() (*hashJoinSpill, error) {
+	spilled, err := newHashJoinSpill(j.level)
+	if err != nil {
+		return nil, err
+	}
+	for h, rows := range j.table {
+		p := spilled.right[j.partitionOf(h)]
+		for _, r := range rows {
+			if err = p.write(r.key, r.value); err != nil {
+				spilled.close()
+				return nil, err
+			}
+		}
+	}
+	j.releaseTable()
+	return spilled, nil
+}
+
+func (j *hashJoinIter) releaseTable() {
+	queryMemory.release(j.reserved)
+	j.reserved = 0
+	j.table = make(map[uint64][]hashJoinRow)
+}
+
+// nextPartition loads the right rows of the next spilled partition into
+// |table|, and starts probing it with the partition's left rows. If the
+// partition doesn't fit in the join buffer, it's partitioned again.
+func (j *hashJoinIter) nextPartition(ctx *sql.Context) error {
+	j.releaseTable()
+	if j.probing != nil {
+		j.probing.close()
+		j.probing = nil
+	}
+	if len(j.partitions) == 0 {
+		return io.EOF
+	}
+	p := j.partitions[0]
+	j.partitions = j.partitions[1:]
+	defer p.right.close()
+
+	j.level = p.level
+	// a partition which can't be split further is joined in memory
+	// regardless of the join buffer
+	force := p.level+1 >= hashJoinMaxLevel
+	var respilled *hashJoinSpill
+	err := p.right.each(func(k, v val.Tuple) error {
+		row, err := j.right.joiner.buildRow(ctx, k, v)
+		if err != nil {
+			return err
+		}
+		h, _ := j.hash(row, false)
+		if respilled == nil && !j.buffer(h, hashJoinRow{key: k, value: v, row: row}, force) {
+			j.level = p.level + 1
+			if respilled, err = j.spillTable(); err != nil {
+				return err
+			}
+		}
+		if respilled != nil {
+			return respilled.right[j.partitionOf(h)].write(k, v)
+		}
+		return nil
+	})
+	if err != nil {
+		if respilled != nil {
+			respilled.close()
+		}
+		p.left.close()
+		return err
+	}
+
+	if respilled != nil {
+		err = p.left.each(func(k, v val.Tuple) error {
+			row, err := j.left.joiner.buildRow(ctx, k, v)
+			if err != nil {
+				return err
+			}
+			h, _ := j.hash(row, true)
+			return respilled.left[j.partitionOf(h)].write(k, v)
+		})
+		p.left.close()
+		if err == nil {
+			err = respilled.finish(&j.stats)
+		}
+		if err != nil {
+			respilled.close()
+			return err
+		}
+		j.partitions = append(respilled.split(j.leftOuter), j.partitions...)
+		return j.nextPartition(ctx)
+	}
+
+	left, err := p.left.reader()
+	if err != nil {
+		p.left.close()
+		return err
+	}
+	j.probing = p.left
+	j.probe = func(*sql.Context) (val.Tuple, val.Tuple, error) {
+		return left.next()
+	}
+	return nil
+}
+
+// hash returns the hash of the join keys of |row|, a row of the left side
+// if |left| is true, or of the right side. Returns false if a key is null.
+func (j *hashJoinIter) hash(row sql.Row, left bool) (uint64, bool) {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, k := range j.keys {
+		v := row[k.right]
+		if left {
+			v = row[k.left]
+		}
+		if v == nil {
+			return 0, false
+		}
+		if s, ok := v.(string); ok && k.text {
+			if sh, err := k.collation.HashToUint(s); err == nil {
+				binary.LittleEndian.PutUint64(buf[:], sh)
+				h.Write(buf[:])
+				continue
+			}
+		}
+		// equal values of columns with the same type, or of integer
+		// columns, format the same way
+		fmt.Fprintf(h, "%v\x00", v)
+	}
+	return h.Sum64(), true
+}
+
+// partitionOf returns the partition of a spill at the current level which
+// the rows with the join key hash |h| belong in.
+func (j *hashJoinIter) partitionOf(h uint64) int {
+	return int(h>>(4*j.level)) % hashJoinFanout
+}
+
+func (j *hashJoinIter) Close(_ *sql.Context) error {
+	j.releaseTable()
+	if j.probing != nil {
+		j.probing.close()
+		j.probing = nil
+	}
+	for _, p := range j.partitions {
+		p.close()
+	}
+	j.partitions = nil
+	return nil
+}
+
+// accept returns whether |row| matches the filter of the side.
+func (s hashJoinSide) accept(ctx *sql.Context, row sql.Row) (bool, error) {
+	if s.filter == nil {
+		return true, nil
+	}
+	res, err := sql.EvaluateCondition(ctx, s.filter, row)
+	if err != nil {
+		return false, err
+	}
+	return sql.IsTrue(res), nil
+}
+
+// hashJoinSpill is the files the rows of a hash join are partitioned into.
+type hashJoinSpill struct {
+	left, right []*spillFile
+	level       int
+}
+
+// hashJoinPartition is a partition of a hash join's rows, spilled to disk.
+type hashJoinPartition struct {
+	left, right *spillFile
+	level       int
+}
+
+func newHashJoinSpill(level int) (*hashJoinSpill, error) {
+	s := &hashJoinSpill{
+		left:  make([]*spillFile, hashJoinFanout),
+		right: make([]*spillFile, hashJoinFanout),
+		level: level,
+	}
+	for i := 0; i < hashJoinFanout; i++ {
+		var err error
+		if s.left[i], err = newSpillFile(); err == nil {
+			s.right[i], err = newSpillFile()
+		}
+		if err != nil {
+			s.close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// finish flushes the files of the spill, and adds what they hold to |stats|.
+func (s *hashJoinSpill) finish(stats *spillStats) error {
+	for _, f := range append(s.left, s.right...) {
+		if err := f.flush(); err != nil {
+			return err
+		}
+		if f.rows > 0 {
+			stats.files++
+			stats.rows += f.rows
+			stats.bytes += f.bytes
+		}
+	}
+	return nil
+}
+
+// split returns the partitions of the spill which may join rows, and
+// removes the files of the others. The left rows of a left outer join are
+// returned whether or not they match any rows.
+func (s *hashJoinSpill) split(leftOuter bool) []*hashJoinPartition {
+	var ret []*hashJoinPartition
+	for i := range s.left {
+		if s.left[i].rows == 0 || (s.right[i].rows == 0 && !leftOuter) {
+			s.left[i].close()
+			s.right[i].close()
+			continue
+		}
+		ret = append(ret, &hashJoinPartition{left: s.left[i], right: s.right[i], level: s.level})
+	}
+	return ret
+}
+
+func (s *hashJoinSpill) close() {
+	for _, f := range append(s.left, s.right...) {
+		if f != nil {
+			f.close()
+		}
+	}
+}
+
+func (p *hashJoinPartition) close() {
+	if p.left != nil {
+		p.left.close()
+	}
+	if p.right != nil {
+		p.right.close()
+	}
+}
+
+// spillFile is a temporary file of key and value tuples, each preceded by
+// its length.
+type spillFile struct {
+	f     *os.File
+	w     *bufio.Writer
+	rows  uint64
+	bytes uint64
+}
+
+func newSpillFile() (*spillFile, error) {
+	f, err := tempfiles.MovableTempFileProvider.NewFile("", "hash_join_")
+	if err != nil {
+		return nil, err
+	}
+	return &spillFile{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+func (f *spillFile) write(k, v val.Tuple) error {
+	var buf [binary.MaxVarintLen64]byte
+	for _, b := range [][]byte{k, v} {
+		n := binary.PutUvarint(buf[:], uint64(len(b)))
+		if _, err := f.w.Write(buf[:n]); err != nil {
+			return err
+		}
+		if _, err := f.w.Write(b); err != nil {
+			return err
+		}
+		f.bytes += uint64(n + len(b))
+	}
+	f.rows++
+	return nil
+}
+
+func (f *spillFile) flush() error {
+	return f.w.Flush()
+}
+
+// spillReader reads the tuples written to a spillFile.
+type spillReader struct {
+	r *bufio.Reader
+}
+
+func (f *spillFile) reader() (*spillReader, error) {
+	if err := f.flush(); err != nil {
+		return nil, err
+	}
+	if _, err := f.f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return &spillReader{r: bufio.NewReader(f.f)}, nil
+}
+
+// each calls |cb| with every pair of tuples in the file.
+func (f *spillFile) each(cb func(k, v val.Tuple) error) error {
+	r, err := f.reader()
+	if err != nil {
+		return err
+	}
+	for {
+		k, v, err := r.next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = cb(k, v); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *spillReader) next() (val.Tuple, val.Tuple, error) {
+	var ret [2]val.Tuple
+	for i := range ret {
+		n, err := binary.ReadUvarint(r.r)
+		if err == io.EOF && i == 0 {
+			return nil, nil, io.EOF
+		} else if err != nil {
+			return nil, nil, unexpectedEOF(err)
+		}
+		ret[i] = make(val.Tuple, n)
+		if _, err = io.ReadFull(r.r, ret[i]); err != nil {
+			return nil, nil, unexpectedEOF(err)
+		}
+	}
+	return ret[0], ret[1], nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// close removes the file.
+func (f *spillFile) close() {
+	if f.f == nil {
+		return
+	}
+	f.f.Close()
+	os.Remove(f.f.Name())
+	f.f = nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/planbuilder"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+func TestHashJoin(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		bufferSize int64
+		spilled    bool
+	}{
+		{
+			name:       "in memory",
+			query:      "select /*+ JOIN_ORDER(xy, uv) HASH_JOIN(xy, uv) */ * from xy join uv on y = u",
+			bufferSize: 1 << 30,
+		},
+		{
+			name:       "spilled",
+			query:      "select /*+ JOIN_ORDER(xy, uv) HASH_JOIN(xy, uv) */ * from xy join uv on y = u",
+			bufferSize: 4096,
+			spilled:    true,
+		},
+		{
+			name:       "spilled and partitioned again",
+			query:      "select /*+ JOIN_ORDER(xy, uv) HASH_JOIN(xy, uv) */ * from xy join uv on y = u",
+			bufferSize: 512,
+			spilled:    true,
+		},
+		{
+			name:       "spilled left outer join",
+			query:      "select /*+ JOIN_ORDER(xy, uv) HASH_JOIN(xy, uv) */ * from xy left join uv on y = u and v < 'v5'",
+			bufferSize: 4096,
+			spilled:    true,
+		},
+		{
+			name:       "spilled join with filters on both sides",
+			query:      "select /*+ JOIN_ORDER(xy, uv) HASH_JOIN(xy, uv) */ * from xy join uv on y = u where x > 100 and v > 'v3'",
+			bufferSize: 4096,
+			spilled:    true,
+		},
+		{
+			name:       "spilled join on collated strings",
+			query:      "select /*+ JOIN_ORDER(xy, ci) HASH_JOIN(xy, ci) */ * from xy join ci on z = s",
+			bufferSize: 512,
+			spilled:    true,
+		},
+	}
+
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := sqle.NewDatabase(context.Background(), "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+
+	engine, ctx, err := sqle.NewTestEngine(dEnv, context.Background(), db)
+	require.NoError(t, err)
+	err = ctx.Session.SetSessionVariable(ctx, sql.AutoCommitSessionVar, false)
+	require.NoError(t, err)
+
+	setup := []string{
+		"create table xy (x int primary key, y int, z varchar(10) collate utf8mb4_0900_ai_ci)",
+		"insert into xy with recursive r(i) as (select 1 union all select i+1 from r where i < 1000) select i, if(i % 10 = 0, null, i % 97), concat('Z', i % 13) from r",
+		"create table uv (u int primary key, v varchar(10))",
+		"insert into uv with recursive r(i) as (select 0 union all select i+1 from r where i < 120) select i, concat('v', i % 7) from r",
+		"create table ci (s varchar(10) collate utf8mb4_0900_ai_ci primary key)",
+		"insert into ci with recursive r(i) as (select 0 union all select i+1 from r where i < 20) select concat('z', i) from r",
+	}
+	for _, q := range setup {
+		_, iter, _, err := engine.Query(ctx, q)
+		require.NoError(t, err)
+		_, err = sql.RowIterToRows(ctx, iter)
+		require.NoError(t, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ctx.Session.SetSessionVariable(ctx, dsess.DoltJoinBufferSize, 0)
+			require.NoError(t, err)
+			_, iter, _, err := engine.Query(ctx, tt.query)
+			require.NoError(t, err)
+			expected, err := sql.RowIterToRows(ctx, iter)
+			require.NoError(t, err)
+			require.NotEmpty(t, expected)
+
+			err = ctx.Session.SetSessionVariable(ctx, dsess.DoltJoinBufferSize, tt.bufferSize)
+			require.NoError(t, err)
+
+			binder := planbuilder.New(ctx, engine.EngineAnalyzer().Catalog, engine.Parser)
+			node, _, _, qFlags, err := binder.Parse(tt.query, false)
+			require.NoError(t, err)
+			node, err = engine.EngineAnalyzer().Analyze(ctx, node, nil, qFlags)
+			require.NoError(t, err)
+
+			j := getHashJoin(node)
+			require.NotNil(t, j)
+
+			iter, err = Builder{}.Build(ctx, j, nil)
+			require.NoError(t, err)
+			hj, ok := iter.(*hashJoinIter)
+			require.True(t, ok)
+
+			rows, err := sql.RowIterToRows(ctx, iter)
+			require.NoError(t, err)
+			require.ElementsMatch(t, expected, rows)
+
+			files, spilledRows, _ := hj.SpillStats()
+			require.Equal(t, tt.spilled, files > 0)
+			require.Equal(t, tt.spilled, spilledRows > 0)
+			require.Equal(t, int64(0), queryMemory.used.Load())
+		})
+	}
+
+	t.Run("query memory limit", func(t *testing.T) {
+		err := ctx.Session.SetSessionVariable(ctx, dsess.DoltJoinBufferSize, 1<<30)
+		require.NoError(t, err)
+		err = sql.SystemVariables.SetGlobal(dsess.DoltQueryMemoryLimit, 4096)
+		require.NoError(t, err)
+		defer sql.SystemVariables.SetGlobal(dsess.DoltQueryMemoryLimit, 0)
+
+		query := "select /*+ JOIN_ORDER(xy, uv) HASH_JOIN(xy, uv) */ * from xy join uv on y = u"
+		binder := planbuilder.New(ctx, engine.EngineAnalyzer().Catalog, engine.Parser)
+		node, _, _, qFlags, err := binder.Parse(query, false)
+		require.NoError(t, err)
+		node, err = engine.EngineAnalyzer().Analyze(ctx, node, nil, qFlags)
+		require.NoError(t, err)
+
+		iter, err := Builder{}.Build(ctx, getHashJoin(node), nil)
+		require.NoError(t, err)
+		hj, ok := iter.(*hashJoinIter)
+		require.True(t, ok)
+		_, err = sql.RowIterToRows(ctx, iter)
+		require.NoError(t, err)
+		files, _, _ := hj.SpillStats()
+		require.Greater(t, files, uint64(0))
+	})
+}
+
+func TestMemoryAccountant(t *testing.T) {
+	var a memoryAccountant
+	require.True(t, a.reserve(100, 150))
+	require.False(t, a.reserve(100, 150))
+	require.True(t, a.reserve(100, 0))
+	a.release(200)
+	require.True(t, a.reserve(150, 150))
+	a.release(150)
+	require.Equal(t, int64(0), a.used.Load())
+}
+
+func getHashJoin(n sql.Node) *plan.JoinNode {
+	var ret *plan.JoinNode
+	transform.Inspect(n, func(n sql.Node) bool {
+		if j, ok := n.(*plan.JoinNode); ok && (j.Op == plan.JoinTypeHash || j.Op == plan.JoinTypeLeftOuterHash) {
+			ret = j
+		}
+		return ret == nil
+	})
+	return ret
+}
@@ -67,9 +67,12 @@ type lookupJoinKvIter struct {
 	excludeNulls bool
 	isLeftJoin   bool
 	returnedARow bool
+
+	examined rowsExaminedCounter
 }
 
-func (l *lookupJoinKvIter) Close(_ *sql.Context) error {
+func (l *lookupJoinKvIter) Close(ctx *sql.Context) error {
+	l.examined.flush(ctx)
 	return nil
 }
 
@@ -123,6 +126,7 @@ func (l *lookupJoinKvIter) Next(ctx *sql.Context) (sql.Row, error) {
 			if l.srcKey == nil {
 				return nil, io.EOF
 			}
+			l.examined.add(ctx)
 
 			l.dstKey, err = l.keyTupleMapper.dstKeyTuple(ctx, l.srcKey, l.srcVal)
 			if err != nil {
@@ -146,6 +150,8 @@ func (l *lookupJoinKvIter) Next(ctx *sql.Context) (sql.Row, error) {
 			if !emitLeftJoinNullRow {
 				continue
 			}
+		} else {
+			l.examined.add(ctx)
 		}
 
 		ret, err := l.joiner.buildRow(ctx, l.srcKey, l.srcVal, dstKey, dstVal)
@@ -235,11 +241,14 @@ func newLookupKeyMapping(ctx context.Context, sourceSch schema.Schema, src proll
 			col := sourceSch.GetAllCols().NameToCol[e.Name()]
 			if col.IsPartOfPK {
 				srcMapping[i] = sourceSch.GetPKCols().TagToIdx[col.Tag]
-			} else if keyless {
-				// Skip cardinality column
-				srcMapping[i] = split + 1 + sourceSch.GetNonPKCols().TagToIdx[col.Tag]
 			} else {
-				srcMapping[i] = split + sourceSch.GetNonPKCols().TagToIdx[col.Tag]
+				j, _ := sourceSch.GetNonPKCols().StoredIndexByTag(col.Tag)
+				if keyless {
+					// Skip cardinality column
+					srcMapping[i] = split + 1 + j
+				} else {
+					srcMapping[i] = split + j
+				}
 			}
 		case *expression.Literal:
 			srcMapping[i] = -1
@@ -0,0 +1,255 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvexec
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// parallelScanMinRows is the number of rows a table needs for scans of it
+// to be split between workers. Smaller tables scan faster on one goroutine.
+var parallelScanMinRows uint64 = 64 * 1024
+
+// parallelScanBatchSize is the number of rows workers send to the exchange
+// at a time.
+const parallelScanBatchSize = 256
+
+// parallelScanWorkers returns the number of workers the session scans
+// tables with, from dolt_parallel_scan_workers.
+func parallelScanWorkers(ctx *sql.Context) int {
+	val, err := ctx.GetSessionVariable(ctx, dsess.DoltParallelScanWorkers)
+	if err != nil {
+		return 1
+	}
+	workers, _, err := gmstypes.Int64.Convert(val)
+	if err != nil {
+		return 1
+	}
+	return int(workers.(int64))
+}
+
+// newParallelScanIter returns an iterator over the rows of the full table
+// scan |rt| which splits the scan into the table's partitions, ordinal
+// subranges of its clustered index, and reads them with |workers|
+// goroutines. The rows of different partitions are interleaved, so rows
+// aren't returned in primary key order. Returns nil if |rt| isn't a Dolt
+// table large enough to split.
+func newParallelScanIter(ctx *sql.Context, rt *plan.ResolvedTable, workers int) (sql.RowIter, error) {
+	var doltTable *sqle.DoltTable
+	switch dt := rt.UnderlyingTable().(type) {
+	case *sqle.WritableDoltTable:
+		doltTable = dt.DoltTable
+	case *sqle.AlterableDoltTable:
+		doltTable = dt.DoltTable
+	case *sqle.DoltTable:
+		doltTable = dt
+	default:
+		return nil, nil
+	}
+
+	table, err := doltTable.DoltTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !types.IsFormat_DOLT(table.Format()) {
+		return nil, nil
+	}
+	rows, _, err := doltTable.RowCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rows < parallelScanMinRows {
+		return nil, nil
+	}
+
+	// Partition iterators are created up front, so workers only read
+	// from the clustered index and never touch session state.
+	partitions, err := rt.Table.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var iters []sql.RowIter
+	for {
+		p, err := partitions.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			closeIters(ctx, iters)
+			return nil, err
+		}
+		iter, err := rt.Table.PartitionRows(ctx, p)
+		if err != nil {
+			closeIters(ctx, iters)
+			return nil, err
+		}
+		iters = append(iters, iter)
+	}
+	if err = partitions.Close(ctx); err != nil {
+		closeIters(ctx, iters)
+		return nil, err
+	}
+	if len(iters) < 2 {
+		closeIters(ctx, iters)
+		return nil, nil
+	}
+
+	return newParallelScanExchange(ctx, iters, workers), nil
+}
+
+func closeIters(ctx *sql.Context, iters []sql.RowIter) error {
+	var firstErr error
+	for _, iter := range iters {
+		if err := iter.Close(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// parallelScanIter is an exchange which returns the rows that its workers
+// read from a table's partitions.
+type parallelScanIter struct {
+	iters   []sql.RowIter
+	batches chan []sql.Row
+	batch   []sql.Row
+
+	eg   *errgroup.Group
+	quit chan struct{}
+	once sync.Once
+}
+
+var _ sql.RowIter = (*parallelScanIter)(nil)
+
+func newParallelScanExchange(ctx *sql.Context, iters []sql.RowIter, workers int) *parallelScanIter {
+	if workers > len(iters) {
+		workers = len(iters)
+	}
+
+	it := &parallelScanIter{
+		iters:   iters,
+		batches: make(chan []sql.Row, workers),
+		quit:    make(chan struct{}),
+	}
+
+	next := make(chan sql.RowIter, len(iters))
+	for _, iter := range iters {
+		next <- iter
+	}
+	close(next)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	for i := 0; i < workers; i++ {
+		eg.Go(func() error {
+			for iter := range next {
+				select {
+				case <-it.quit:
+					return nil
+				default:
+				}
+				if err := it.drain(ctx, egCtx, iter); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	it.eg = eg
+
+	go func() {
+		_ = eg.Wait()
+		close(it.batches)
+	}()
+	return it
+}
+
+// drain sends the rows of |iter| to the exchange, until they run out or
+// the exchange is closed.
+func (it *parallelScanIter) drain(ctx *sql.Context, egCtx context.Context, iter sql.RowIter) error {
+	batch := make([]sql.Row, 0, parallelScanBatchSize)
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			_, err = it.send(egCtx, batch)
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		batch = append(batch, row)
+		if len(batch) < parallelScanBatchSize {
+			continue
+		}
+		if ok, err := it.send(egCtx, batch); !ok || err != nil {
+			return err
+		}
+		batch = make([]sql.Row, 0, parallelScanBatchSize)
+	}
+}
+
+// send sends |batch| to the exchange. Returns false if the exchange was
+// closed before it could be sent.
+func (it *parallelScanIter) send(egCtx context.Context, batch []sql.Row) (bool, error) {
+	if len(batch) == 0 {
+		return true, nil
+	}
+	select {
+	case it.batches <- batch:
+		return true, nil
+	case <-it.quit:
+		return false, nil
+	case <-egCtx.Done():
+		return false, egCtx.Err()
+	}
+}
+
+func (it *parallelScanIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for len(it.batch) == 0 {
+		select {
+		case batch, ok := <-it.batches:
+			if !ok {
+				if err := it.eg.Wait(); err != nil {
+					return nil, err
+				}
+				return nil, io.EOF
+			}
+			it.batch = batch
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	row := it.batch[0]
+	it.batch = it.batch[1:]
+	return row, nil
+}
+
+func (it *parallelScanIter) Close(ctx *sql.Context) error {
+	it.once.Do(func() {
+		close(it.quit)
+	})
+	_ = it.eg.Wait()
+	return closeIters(ctx, it.iters)
+}
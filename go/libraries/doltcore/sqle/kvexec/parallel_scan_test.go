@@ -0,0 +1,135 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvexec
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/planbuilder"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+func TestParallelScan(t *testing.T) {
+	defer func(minRows uint64) {
+		parallelScanMinRows = minRows
+	}(parallelScanMinRows)
+	parallelScanMinRows = 100
+
+	tests := []struct {
+		name     string
+		rows     int
+		workers  int
+		parallel bool
+	}{
+		{
+			name:     "accept large table",
+			rows:     1000,
+			workers:  4,
+			parallel: true,
+		},
+		{
+			name:     "reject one worker",
+			rows:     1000,
+			workers:  1,
+			parallel: false,
+		},
+		{
+			name:     "reject small table",
+			rows:     10,
+			workers:  4,
+			parallel: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			defer dEnv.DoltDB.Close()
+
+			tmpDir, err := dEnv.TempTableFilesDir()
+			require.NoError(t, err)
+
+			opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+			db, err := sqle.NewDatabase(context.Background(), "dolt", dEnv.DbData(), opts)
+			require.NoError(t, err)
+
+			engine, ctx, err := sqle.NewTestEngine(dEnv, context.Background(), db)
+			require.NoError(t, err)
+
+			err = ctx.Session.SetSessionVariable(ctx, sql.AutoCommitSessionVar, false)
+			require.NoError(t, err)
+			err = ctx.Session.SetSessionVariable(ctx, dsess.DoltParallelScanWorkers, tt.workers)
+			require.NoError(t, err)
+
+			setup := []string{
+				"create table xy (x int primary key, y int)",
+				fmt.Sprintf("insert into xy with recursive r(i) as (select 1 union all select i+1 from r where i < %d) select i, i from r", tt.rows),
+			}
+			for _, q := range setup {
+				_, iter, _, err := engine.Query(ctx, q)
+				require.NoError(t, err)
+				_, err = sql.RowIterToRows(ctx, iter)
+				require.NoError(t, err)
+			}
+
+			binder := planbuilder.New(ctx, engine.EngineAnalyzer().Catalog, engine.Parser)
+			node, _, _, qFlags, err := binder.Parse("select x from xy", false)
+			require.NoError(t, err)
+			node, err = engine.EngineAnalyzer().Analyze(ctx, node, nil, qFlags)
+			require.NoError(t, err)
+
+			rt := getResolvedTable(node)
+			require.NotNil(t, rt)
+
+			iter, err := Builder{}.Build(ctx, rt, nil)
+			require.NoError(t, err)
+			_, ok := iter.(*parallelScanIter)
+			require.Equalf(t, tt.parallel, ok, "expected parallel scan: %t", tt.parallel)
+			if !ok {
+				return
+			}
+
+			rows, err := sql.RowIterToRows(ctx, iter)
+			require.NoError(t, err)
+			require.Len(t, rows, tt.rows)
+			seen := make(map[int32]bool)
+			for _, row := range rows {
+				seen[row[0].(int32)] = true
+			}
+			require.Len(t, seen, tt.rows)
+		})
+	}
+}
+
+func getResolvedTable(n sql.Node) *plan.ResolvedTable {
+	var ret *plan.ResolvedTable
+	transform.Inspect(n, func(n sql.Node) bool {
+		if rt, ok := n.(*plan.ResolvedTable); ok {
+			ret = rt
+		}
+		return ret == nil
+	})
+	return ret
+}
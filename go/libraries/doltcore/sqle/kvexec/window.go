@@ -0,0 +1,791 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvexec
+
+import (
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/aggregation"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/prolly"
+)
+
+// windowWorkers returns the number of goroutines the session evaluates the
+// partitions of window functions with, from dolt_window_workers. Zero
+// disables incremental window evaluation.
+func windowWorkers(ctx *sql.Context) int {
+	v, err := ctx.GetSessionVariable(ctx, dsess.DoltWindowWorkers)
+	if err != nil {
+		return 0
+	}
+	workers, _, err := gmstypes.Int64.Convert(v)
+	if err != nil {
+		return 0
+	}
+	return int(workers.(int64))
+}
+
+type windowFuncKind uint8
+
+const (
+	windowSum windowFuncKind = iota
+	windowAvg
+	windowCount
+	windowMin
+	windowMax
+	windowRowNumber
+	windowRank
+	windowDenseRank
+)
+
+// windowFrame is the rows of a partition a window function aggregates,
+// relative to the current row. Both ends of every frame handled here only
+// move forward as the current row does, so aggregates are updated with the
+// rows which enter and leave the frame rather than recomputed.
+type windowFrame struct {
+	// start and end are offsets from the current row, which are unbounded
+	// if math.MinInt64 or math.MaxInt64
+	start, end int64
+	// peers extends the end of the frame to the last row which sorts the
+	// same as the current row, like the default frame of an ordered window
+	peers bool
+}
+
+// windowFunc is a window function of a Window node.
+type windowFunc struct {
+	// idx is the position of the function in the node's select expressions
+	idx   int
+	kind  windowFuncKind
+	arg   sql.Expression
+	typ   sql.Type
+	frame windowFrame
+	// float is true if the function adds floating point values, whose sums
+	// depend on the order they're added in, so they're never subtracted
+	float bool
+}
+
+// windowSpec is the window functions which partition and sort rows the
+// same way.
+type windowSpec struct {
+	partitionBy []sql.Expression
+	orderBy     sql.SortFields
+	funcs       []windowFunc
+}
+
+// windowed is an expression evaluated over a window.
+type windowed interface {
+	Window() *sql.WindowDefinition
+}
+
+// newWindowIter returns an iterator over the rows of the Window node |n|,
+// which evaluates its window functions with |workers| goroutines. Each
+// function's aggregate is updated incrementally as its frame slides over
+// the rows of a partition. Returns nil if |n| doesn't read a Dolt table or
+// index scan, or has window functions or frames that aren't handled: SUM,
+// AVG, COUNT, MIN and MAX of numbers over ROWS frames or the default frame,
+// and ROW_NUMBER, RANK and DENSE_RANK.
+func newWindowIter(ctx *sql.Context, n *plan.Window, workers int) (sql.RowIter, error) {
+	specs, ok, err := getWindowSpecs(ctx, n.SelectExprs)
+	if err != nil || !ok {
+		return nil, err
+	}
+	srcMap, srcIter, _, srcSchema, srcTags, srcFilter, err := getSourceKv(ctx, n.Child, true)
+	if err != nil || srcSchema == nil {
+		return nil, err
+	}
+	if len(srcTags) != len(n.Child.Schema()) {
+		return nil, nil
+	}
+	return &windowIter{
+		srcIter:   srcIter,
+		srcFilter: srcFilter,
+		joiner:    newRowJoiner([]schema.Schema{srcSchema}, nil, srcTags, srcMap.NodeStore()),
+		exprs:     n.SelectExprs,
+		specs:     specs,
+		workers:   workers,
+	}, nil
+}
+
+// getWindowSpecs groups the window functions of |exprs| by the way they
+// partition and sort rows. Returns false if any of them isn't handled.
+func getWindowSpecs(ctx *sql.Context, exprs []sql.Expression) ([]*windowSpec, bool, error) {
+	var specs []*windowSpec
+	byKey := make(map[string]*windowSpec)
+	for i, e := range exprs {
+		if a, ok := e.(*expression.Alias); ok {
+			e = a.Child
+		}
+		f := windowFunc{idx: i, typ: e.Type()}
+		switch e := e.(type) {
+		case *aggregation.Sum:
+			f.kind, f.arg = windowSum, e.Child
+		case *aggregation.Avg:
+			f.kind, f.arg = windowAvg, e.Child
+		case *aggregation.Count:
+			f.kind, f.arg = windowCount, e.Child
+			// COUNT(*) and COUNT of a non-NULL literal count every row,
+			// but COUNT(NULL) counts none
+			switch c := e.Child.(type) {
+			case *expression.Star:
+				f.arg = nil
+			case *expression.Literal:
+				if c.Value() != nil {
+					f.arg = nil
+				}
+			}
+		case *aggregation.Min:
+			f.kind, f.arg = windowMin, e.Child
+		case *aggregation.Max:
+			f.kind, f.arg = windowMax, e.Child
+		case *aggregation.RowNumber:
+			f.kind = windowRowNumber
+		case *aggregation.Rank:
+			f.kind = windowRank
+		case *aggregation.DenseRank:
+			f.kind = windowDenseRank
+		default:
+			// other expressions are evaluated on each row, unless they
+			// contain window functions
+			if transform.InspectExpr(e, func(e sql.Expression) bool {
+				_, ok := e.(windowed)
+				return ok
+			}) {
+				return nil, false, nil
+			}
+			continue
+		}
+		if f.arg != nil {
+			switch {
+			case gmstypes.IsFloat(f.arg.Type()):
+				f.float = f.kind == windowSum || f.kind == windowAvg
+			case gmstypes.IsNumber(f.arg.Type()):
+			case f.kind == windowCount:
+			default:
+				return nil, false, nil
+			}
+		}
+
+		def := e.(windowed).Window()
+		if def == nil {
+			return nil, false, nil
+		}
+		var ok bool
+		var err error
+		if f.frame, ok, err = getWindowFrame(ctx, def); err != nil || !ok {
+			return nil, false, err
+		}
+
+		key := windowSpecKey(def)
+		spec, ok := byKey[key]
+		if !ok {
+			spec = &windowSpec{partitionBy: def.PartitionBy, orderBy: def.OrderBy}
+			byKey[key] = spec
+			specs = append(specs, spec)
+		}
+		spec.funcs = append(spec.funcs, f)
+	}
+	return specs, len(specs) > 0, nil
+}
+
+func windowSpecKey(def *sql.WindowDefinition) string {
+	var sb strings.Builder
+	for _, e := range def.PartitionBy {
+		sb.WriteString(e.String())
+		sb.WriteByte(0)
+	}
+	sb.WriteByte(1)
+	for _, sf := range def.OrderBy {
+		sb.WriteString(sf.Column.String())
+		if sf.Order == sql.Descending {
+			sb.WriteString(" desc")
+		}
+		if sf.NullOrdering == sql.NullsFirst {
+			sb.WriteString(" nulls first")
+		}
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+// getWindowFrame returns the frame of the window |def|. Returns false for
+// RANGE frames other than the defaults, whose ends depend on the values
+// rows are sorted by.
+func getWindowFrame(ctx *sql.Context, def *sql.WindowDefinition) (windowFrame, bool, error) {
+	whole := windowFrame{start: math.MinInt64, end: math.MaxInt64}
+	running := windowFrame{start: math.MinInt64, end: 0, peers: true}
+	fr := def.Frame
+	if fr == nil {
+		if len(def.OrderBy) == 0 {
+			return whole, true, nil
+		}
+		return running, true, nil
+	}
+
+	// the engine's frames are named for their units and bounds, like
+	// RowsNPrecedingToCurrentRowFrame
+	if !strings.HasPrefix(reflect.Indirect(reflect.ValueOf(fr)).Type().Name(), "Rows") {
+		switch {
+		case fr.UnboundedPreceding() && fr.UnboundedFollowing():
+			return whole, true, nil
+		case fr.UnboundedPreceding() && fr.EndCurrentRow():
+			return running, true, nil
+		}
+		return windowFrame{}, false, nil
+	}
+
+	var f windowFrame
+	var err error
+	switch {
+	case fr.UnboundedPreceding():
+		f.start = math.MinInt64
+	case fr.StartCurrentRow():
+		f.start = 0
+	case fr.StartNPreceding() != nil:
+		f.start, err = frameOffset(ctx, fr.StartNPreceding())
+		f.start = -f.start
+	case fr.StartNFollowing() != nil:
+		f.start, err = frameOffset(ctx, fr.StartNFollowing())
+	default:
+		return windowFrame{}, false, nil
+	}
+	if err != nil {
+		return windowFrame{}, false, err
+	}
+	switch {
+	case fr.UnboundedFollowing():
+		f.end = math.MaxInt64
+	case fr.EndCurrentRow():
+		f.end = 0
+	case fr.EndNPreceding() != nil:
+		f.end, err = frameOffset(ctx, fr.EndNPreceding())
+		f.end = -f.end
+	case fr.EndNFollowing() != nil:
+		f.end, err = frameOffset(ctx, fr.EndNFollowing())
+	default:
+		return windowFrame{}, false, nil
+	}
+	if err != nil {
+		return windowFrame{}, false, err
+	}
+	return f, true, nil
+}
+
+func frameOffset(ctx *sql.Context, e sql.Expression) (int64, error) {
+	v, err := e.Eval(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	n, _, err := gmstypes.Int64.Convert(v)
+	if err != nil {
+		return 0, err
+	}
+	return n.(int64), nil
+}
+
+// bounds returns the first and last rows of the frame of row |i| of a
+// partition of |n| rows, where |lastPeer| is the last row which sorts the
+// same as row |i|. The frame is empty if |lo| is greater than |hi|.
+func (f windowFrame) bounds(i, n, lastPeer int) (lo, hi int) {
+	lo, hi = 0, n-1
+	if f.start != math.MinInt64 {
+		lo = max(clampOffset(i, f.start), 0)
+	}
+	if f.peers {
+		hi = lastPeer
+	} else if f.end != math.MaxInt64 {
+		hi = min(clampOffset(i, f.end), n-1)
+	}
+	return lo, hi
+}
+
+func clampOffset(i int, off int64) int {
+	v := int64(i) + off
+	if v > math.MaxInt32 {
+		return math.MaxInt32
+	} else if v < math.MinInt32 {
+		return math.MinInt32
+	}
+	return int(v)
+}
+
+type windowIter struct {
+	srcIter   prolly.MapIter
+	srcFilter sql.Expression
+	joiner    *prollyToSqlJoiner
+	exprs     []sql.Expression
+	specs     []*windowSpec
+	workers   int
+
+	results  []sql.Row
+	pos      int
+	started  bool
+	examined rowsExaminedCounter
+}
+
+var _ sql.RowIter = (*windowIter)(nil)
+
+func (w *windowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if !w.started {
+		w.started = true
+		if err := w.evaluate(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if w.pos >= len(w.results) {
+		return nil, io.EOF
+	}
+	row := w.results[w.pos]
+	w.results[w.pos] = nil
+	w.pos++
+	return row, nil
+}
+
+// evaluate reads every source row, and evaluates the select expressions of
+// each of them. The partitions of each window are evaluated concurrently.
+// Rows are returned in the order they're read, like the engine does.
+func (w *windowIter) evaluate(ctx *sql.Context) error {
+	var rows []sql.Row
+	for {
+		k, v, err := w.srcIter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		w.examined.add(ctx)
+		row, err := w.joiner.buildRow(ctx, k, v)
+		if err != nil {
+			return err
+		}
+		if w.srcFilter != nil {
+			res, err := sql.EvaluateCondition(ctx, w.srcFilter, row)
+			if err != nil {
+				return err
+			}
+			if !sql.IsTrue(res) {
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+	w.examined.flush(ctx)
+
+	funcs := make([]bool, len(w.exprs))
+	for _, spec := range w.specs {
+		for _, f := range spec.funcs {
+			funcs[f.idx] = true
+		}
+	}
+	w.results = make([]sql.Row, len(rows))
+	for i, row := range rows {
+		out := make(sql.Row, len(w.exprs))
+		for j, e := range w.exprs {
+			if funcs[j] {
+				continue
+			}
+			var err error
+			if out[j], err = e.Eval(ctx, row); err != nil {
+				return err
+			}
+		}
+		w.results[i] = out
+	}
+
+	var partitions []*windowPartition
+	for _, spec := range w.specs {
+		p, err := spec.partition(ctx, rows)
+		if err != nil {
+			return err
+		}
+		partitions = append(partitions, p...)
+	}
+	eg := &errgroup.Group{}
+	eg.SetLimit(w.workers)
+	for _, p := range partitions {
+		p := p
+		eg.Go(func() error {
+			// partitions write the values of their own rows
+			return p.evaluate(ctx, rows, w.results)
+		})
+	}
+	return eg.Wait()
+}
+
+func (w *windowIter) Close(_ *sql.Context) error {
+	return nil
+}
+
+// windowPartition is the rows of a partition of a window, in the order the
+// window sorts them.
+type windowPartition struct {
+	spec *windowSpec
+	// rows are the positions of the partition's rows in the source rows
+	rows []int
+	// orderVals are the values the rows are sorted by
+	orderVals [][]interface{}
+}
+
+// partition sorts |rows| by the partition and order expressions of the
+// window, and splits them into its partitions.
+func (s *windowSpec) partition(ctx *sql.Context, rows []sql.Row) ([]*windowPartition, error) {
+	partVals := make([][]interface{}, len(rows))
+	orderVals := make([][]interface{}, len(rows))
+	order := make([]int, len(rows))
+	for i, row := range rows {
+		order[i] = i
+		partVals[i] = make([]interface{}, len(s.partitionBy))
+		for j, e := range s.partitionBy {
+			v, err := e.Eval(ctx, row)
+			if err != nil {
+				return nil, err
+			}
+			partVals[i][j] = v
+		}
+		orderVals[i] = make([]interface{}, len(s.orderBy))
+		for j, sf := range s.orderBy {
+			v, err := sf.Column.Eval(ctx, row)
+			if err != nil {
+				return nil, err
+			}
+			orderVals[i][j] = v
+		}
+	}
+
+	var sortErr error
+	comparePartitions := func(l, r int) int {
+		for j, e := range s.partitionBy {
+			cmp, err := compareWindowValues(e.Type(), partVals[l][j], partVals[r][j], false, true)
+			if err != nil {
+				sortErr = err
+			}
+			if cmp != 0 {
+				return cmp
+			}
+		}
+		return 0
+	}
+	// the engine sorts rows stably, so rows which sort the same keep the
+	// order they're read in
+	sort.SliceStable(order, func(i, j int) bool {
+		l, r := order[i], order[j]
+		if cmp := comparePartitions(l, r); cmp != 0 {
+			return cmp < 0
+		}
+		cmp, err := s.compareOrder(orderVals[l], orderVals[r])
+		if err != nil {
+			sortErr = err
+		}
+		return cmp < 0
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	var ret []*windowPartition
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && comparePartitions(order[start], order[end]) == 0 {
+			end++
+		}
+		p := &windowPartition{spec: s, rows: order[start:end], orderVals: make([][]interface{}, end-start)}
+		for i, r := range p.rows {
+			p.orderVals[i] = orderVals[r]
+		}
+		ret = append(ret, p)
+		start = end
+	}
+	return ret, sortErr
+}
+
+func (s *windowSpec) compareOrder(l, r []interface{}) (int, error) {
+	for j, sf := range s.orderBy {
+		cmp, err := compareWindowValues(sf.Column.Type(), l[j], r[j], sf.Order == sql.Descending, sf.NullOrdering == sql.NullsFirst)
+		if err != nil || cmp != 0 {
+			return cmp, err
+		}
+	}
+	return 0, nil
+}
+
+// compareWindowValues compares |l| and |r| like the engine's sorter, which
+// swaps descending values before placing nulls.
+func compareWindowValues(t sql.Type, l, r interface{}, descending, nullsFirst bool) (int, error) {
+	if descending {
+		l, r = r, l
+	}
+	switch {
+	case l == nil && r == nil:
+		return 0, nil
+	case l == nil:
+		if nullsFirst {
+			return -1, nil
+		}
+		return 1, nil
+	case r == nil:
+		if nullsFirst {
+			return 1, nil
+		}
+		return -1, nil
+	}
+	return t.Compare(l, r)
+}
+
+// evaluate evaluates the window functions of the partition, and sets their
+// values in the |results| of its rows.
+func (p *windowPartition) evaluate(ctx *sql.Context, rows []sql.Row, results []sql.Row) error {
+	n := len(p.rows)
+	lastPeer := make([]int, n)
+	for i := n - 1; i >= 0; i-- {
+		lastPeer[i] = i
+		if i < n-1 {
+			cmp, err := p.spec.compareOrder(p.orderVals[i], p.orderVals[i+1])
+			if err != nil {
+				return err
+			}
+			if cmp == 0 {
+				lastPeer[i] = lastPeer[i+1]
+			}
+		}
+	}
+
+	for _, f := range p.spec.funcs {
+		var values []interface{}
+		var err error
+		switch f.kind {
+		case windowRowNumber, windowRank, windowDenseRank:
+			values = rankWindow(f.kind, lastPeer)
+		default:
+			if values, err = p.aggregate(ctx, f, rows, lastPeer); err != nil {
+				return err
+			}
+		}
+		for i, r := range p.rows {
+			v := values[i]
+			if v != nil && f.kind != windowMin && f.kind != windowMax {
+				if v, _, err = f.typ.Convert(v); err != nil {
+					return err
+				}
+			}
+			results[r][f.idx] = v
+		}
+	}
+	return nil
+}
+
+// rankWindow returns the ROW_NUMBER, RANK or DENSE_RANK of each row of a
+// partition, where |lastPeer| is the last row which sorts the same as each.
+func rankWindow(kind windowFuncKind, lastPeer []int) []interface{} {
+	values := make([]interface{}, len(lastPeer))
+	var rank, dense int64
+	for i := range lastPeer {
+		if i == 0 || lastPeer[i-1] != lastPeer[i] {
+			rank = int64(i) + 1
+			dense++
+		}
+		switch kind {
+		case windowRowNumber:
+			values[i] = int64(i) + 1
+		case windowRank:
+			values[i] = rank
+		case windowDenseRank:
+			values[i] = dense
+		}
+	}
+	return values
+}
+
+// aggregate returns the value of the aggregate window function |f| for
+// each row of the partition. Rows are added to the aggregate as they enter
+// the frame, and removed as they leave it.
+func (p *windowPartition) aggregate(ctx *sql.Context, f windowFunc, rows []sql.Row, lastPeer []int) ([]interface{}, error) {
+	n := len(p.rows)
+	agg := &windowAgg{kind: f.kind, float: f.float}
+	if f.arg != nil {
+		agg.args = make([]interface{}, n)
+		for i, r := range p.rows {
+			v, err := f.arg.Eval(ctx, rows[r])
+			if err != nil {
+				return nil, err
+			}
+			if v != nil {
+				if v, err = agg.convert(f.arg.Type(), v); err != nil {
+					return nil, err
+				}
+			}
+			agg.args[i] = v
+		}
+		agg.argType = f.arg.Type()
+	}
+
+	values := make([]interface{}, n)
+	// a and b are the first row of the frame aggregated and the row after
+	// its last
+	a, b := 0, 0
+	for i := 0; i < n; i++ {
+		lo, hi := f.frame.bounds(i, n, lastPeer[i])
+		newA, newB := lo, max(hi+1, lo)
+		if a < newA && !agg.invertible() {
+			// a frame of floating point values is summed again whenever
+			// rows leave it
+			agg.reset()
+			a, b = newA, newA
+		}
+		for ; a < newA && a < b; a++ {
+			agg.remove(a)
+		}
+		if a < newA {
+			a, b = newA, newA
+		}
+		for ; b < newB && b < n; b++ {
+			if err := agg.add(b); err != nil {
+				return nil, err
+			}
+		}
+		values[i] = agg.result()
+	}
+	return values, nil
+}
+
+// windowAgg is the aggregate of the rows in a frame.
+type windowAgg struct {
+	kind    windowFuncKind
+	float   bool
+	args    []interface{}
+	argType sql.Type
+
+	// count is the number of rows, or of non-null values
+	count int64
+	sum   decimal.Decimal
+	fsum  float64
+	// deque holds the rows whose values may be the MIN or MAX of the frame
+	// once the rows before them leave it, in the order they were added
+	deque []int
+}
+
+func (a *windowAgg) convert(t sql.Type, v interface{}) (interface{}, error) {
+	switch a.kind {
+	case windowSum, windowAvg:
+		if a.float {
+			f, _, err := gmstypes.Float64.Convert(v)
+			return f, err
+		}
+		d, err := gmstypes.InternalDecimalType.ConvertToNullDecimal(v)
+		if err != nil || !d.Valid {
+			return nil, err
+		}
+		return d.Decimal, nil
+	}
+	return v, nil
+}
+
+// invertible returns whether rows can be removed from the aggregate.
+func (a *windowAgg) invertible() bool {
+	return !a.float
+}
+
+func (a *windowAgg) reset() {
+	a.count, a.sum, a.fsum, a.deque = 0, decimal.Zero, 0, a.deque[:0]
+}
+
+func (a *windowAgg) add(i int) error {
+	if a.args == nil {
+		a.count++
+		return nil
+	}
+	v := a.args[i]
+	if v == nil {
+		return nil
+	}
+	a.count++
+	switch a.kind {
+	case windowSum, windowAvg:
+		if a.float {
+			a.fsum += v.(float64)
+		} else {
+			a.sum = a.sum.Add(v.(decimal.Decimal))
+		}
+	case windowMin, windowMax:
+		for len(a.deque) > 0 {
+			cmp, err := a.argType.Compare(a.args[a.deque[len(a.deque)-1]], v)
+			if err != nil {
+				return err
+			}
+			if (a.kind == windowMin && cmp <= 0) || (a.kind == windowMax && cmp >= 0) {
+				break
+			}
+			a.deque = a.deque[:len(a.deque)-1]
+		}
+		a.deque = append(a.deque, i)
+	}
+	return nil
+}
+
+func (a *windowAgg) remove(i int) {
+	if a.args == nil {
+		a.count--
+		return
+	}
+	v := a.args[i]
+	if v == nil {
+		return
+	}
+	a.count--
+	switch a.kind {
+	case windowSum, windowAvg:
+		a.sum = a.sum.Sub(v.(decimal.Decimal))
+	case windowMin, windowMax:
+		if len(a.deque) > 0 && a.deque[0] == i {
+			a.deque = a.deque[1:]
+		}
+	}
+}
+
+func (a *windowAgg) result() interface{} {
+	switch a.kind {
+	case windowCount:
+		return a.count
+	case windowMin, windowMax:
+		if len(a.deque) == 0 {
+			return nil
+		}
+		return a.args[a.deque[0]]
+	}
+	if a.count == 0 {
+		return nil
+	}
+	switch {
+	case a.kind == windowSum && a.float:
+		return a.fsum
+	case a.kind == windowSum:
+		return a.sum
+	case a.float:
+		return a.fsum / float64(a.count)
+	default:
+		return a.sum.Div(decimal.NewFromInt(a.count))
+	}
+}
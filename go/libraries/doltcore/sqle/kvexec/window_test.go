@@ -0,0 +1,197 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvexec
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/planbuilder"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		incremental bool
+	}{
+		{
+			name:        "running sum",
+			query:       "select x, sum(y) over (order by x) from xy",
+			incremental: true,
+		},
+		{
+			name:        "running sum with peers",
+			query:       "select x, sum(y) over (order by z), count(*) over (order by z) from xy",
+			incremental: true,
+		},
+		{
+			name:        "sliding frames",
+			query:       "select x, sum(y) over (order by x rows between 3 preceding and current row), avg(y) over (order by x rows between 2 preceding and 2 following), count(y) over (order by x rows between 1 following and 4 following) from xy",
+			incremental: true,
+		},
+		{
+			name:        "sliding min and max",
+			query:       "select x, min(y) over (partition by z order by x rows between 5 preceding and 1 preceding), max(y) over (partition by z order by x rows between current row and 3 following) from xy",
+			incremental: true,
+		},
+		{
+			name:        "whole partitions",
+			query:       "select x, sum(y) over (partition by z), max(y) over (partition by z), count(*) over () from xy",
+			incremental: true,
+		},
+		{
+			name:        "ranking",
+			query:       "select x, row_number() over (partition by z order by x), rank() over (partition by z order by y desc), dense_rank() over (order by y) from xy",
+			incremental: true,
+		},
+		{
+			name:        "floating point sliding sum",
+			query:       "select x, sum(f) over (order by x rows between 2 preceding and current row), avg(f) over (partition by z) from xy",
+			incremental: true,
+		},
+		{
+			name:        "filtered rows",
+			query:       "select x, sum(y) over (partition by z order by x) from xy where x > 100",
+			incremental: true,
+		},
+		{
+			name:        "count of literals",
+			query:       "select x, count(null) over (), count(1) over (partition by z), count(null) over (order by x rows between 2 preceding and current row), count(y) over (order by x) from xy",
+			incremental: true,
+		},
+		{
+			name:        "reject unhandled window function",
+			query:       "select x, lag(y) over (order by x) from xy",
+			incremental: false,
+		},
+		{
+			name:        "reject range frame",
+			query:       "select x, sum(y) over (order by x range between 2 preceding and current row) from xy",
+			incremental: false,
+		},
+	}
+
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := sqle.NewDatabase(context.Background(), "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+
+	engine, ctx, err := sqle.NewTestEngine(dEnv, context.Background(), db)
+	require.NoError(t, err)
+	err = ctx.Session.SetSessionVariable(ctx, sql.AutoCommitSessionVar, false)
+	require.NoError(t, err)
+
+	setup := []string{
+		"create table xy (x int primary key, y int, z varchar(10), f double)",
+		"insert into xy with recursive r(i) as (select 1 union all select i+1 from r where i < 1000) select i, if(i % 10 = 0, null, (i * 7) % 100), concat('z', i % 13), i / 7.0 from r",
+	}
+	for _, q := range setup {
+		_, iter, _, err := engine.Query(ctx, q)
+		require.NoError(t, err)
+		_, err = sql.RowIterToRows(ctx, iter)
+		require.NoError(t, err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ctx.Session.SetSessionVariable(ctx, dsess.DoltWindowWorkers, 0)
+			require.NoError(t, err)
+			_, iter, _, err := engine.Query(ctx, tt.query)
+			require.NoError(t, err)
+			expected, err := sql.RowIterToRows(ctx, iter)
+			require.NoError(t, err)
+
+			err = ctx.Session.SetSessionVariable(ctx, dsess.DoltWindowWorkers, 4)
+			require.NoError(t, err)
+
+			binder := planbuilder.New(ctx, engine.EngineAnalyzer().Catalog, engine.Parser)
+			node, _, _, qFlags, err := binder.Parse(tt.query, false)
+			require.NoError(t, err)
+			node, err = engine.EngineAnalyzer().Analyze(ctx, node, nil, qFlags)
+			require.NoError(t, err)
+
+			w := getWindow(node)
+			require.NotNil(t, w)
+
+			iter, err = Builder{}.Build(ctx, w, nil)
+			require.NoError(t, err)
+			_, ok := iter.(*windowIter)
+			require.Equalf(t, tt.incremental, ok, "expected incremental window: %t", tt.incremental)
+			if !ok {
+				return
+			}
+
+			rows, err := sql.RowIterToRows(ctx, iter)
+			require.NoError(t, err)
+			require.ElementsMatch(t, expected, rows)
+		})
+	}
+}
+
+func TestWindowFrameBounds(t *testing.T) {
+	unbounded := windowFrame{start: math.MinInt64, end: math.MaxInt64}
+	lo, hi := unbounded.bounds(3, 10, 3)
+	assert.Equal(t, []int{0, 9}, []int{lo, hi})
+
+	running := windowFrame{start: math.MinInt64, peers: true}
+	lo, hi = running.bounds(3, 10, 5)
+	assert.Equal(t, []int{0, 5}, []int{lo, hi})
+
+	sliding := windowFrame{start: -2, end: 1}
+	lo, hi = sliding.bounds(0, 10, 0)
+	assert.Equal(t, []int{0, 1}, []int{lo, hi})
+	lo, hi = sliding.bounds(9, 10, 9)
+	assert.Equal(t, []int{7, 9}, []int{lo, hi})
+
+	// frames past the end of the partition are empty
+	following := windowFrame{start: 2, end: 4}
+	lo, hi = following.bounds(8, 10, 8)
+	assert.Greater(t, lo, hi)
+}
+
+func TestRankWindow(t *testing.T) {
+	lastPeer := []int{1, 1, 2, 5, 5, 5, 6}
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5), int64(6), int64(7)}, rankWindow(windowRowNumber, lastPeer))
+	assert.Equal(t, []interface{}{int64(1), int64(1), int64(3), int64(4), int64(4), int64(4), int64(7)}, rankWindow(windowRank, lastPeer))
+	assert.Equal(t, []interface{}{int64(1), int64(1), int64(2), int64(3), int64(3), int64(3), int64(4)}, rankWindow(windowDenseRank, lastPeer))
+}
+
+func getWindow(n sql.Node) *plan.Window {
+	var ret *plan.Window
+	transform.Inspect(n, func(n sql.Node) bool {
+		if w, ok := n.(*plan.Window); ok {
+			ret = w
+		}
+		return ret == nil
+	})
+	return ret
+}
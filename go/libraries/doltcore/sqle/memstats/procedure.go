@@ -0,0 +1,111 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memstats
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// ProcedureName is the name of the stored procedure which reports memory usage by subsystem.
+const ProcedureName = "dolt_memory_stats"
+
+const flushFlag = "--flush"
+
+var statsSchema = sql.Schema{
+	&sql.Column{Name: "subsystem", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "component", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "bytes", Type: types.Int64, Nullable: true},
+	&sql.Column{Name: "capacity_bytes", Type: types.Int64, Nullable: true},
+	&sql.Column{Name: "entries", Type: types.Int64, Nullable: true},
+}
+
+// NewProcedure returns the DOLT_MEMORY_STATS([--flush [subsystem...]]) stored procedure, which returns a row for each
+// component of each subsystem holding memory: the Go runtime, the chunk caches, the sessions of the server and its
+// query and prepared statement caches. Sizes which a subsystem doesn't track are NULL.
+//
+// With --flush, it first frees what the named subsystems, or all of them, cache, and returns as much memory as it can
+// to the operating system.
+func NewProcedure() sql.ExternalStoredProcedureDetails {
+	return sql.ExternalStoredProcedureDetails{
+		Name:     ProcedureName,
+		Schema:   statsSchema,
+		Function: memoryStats,
+		ReadOnly: true,
+	}
+}
+
+func memoryStats(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	sources := Sources()
+	if len(args) > 0 {
+		if !strings.EqualFold(args[0], flushFlag) {
+			return nil, fmt.Errorf("%s: unknown argument %s, expected %s", ProcedureName, args[0], flushFlag)
+		}
+		if err := flush(ctx, sources, args[1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []sql.Row
+	for _, src := range sources {
+		for _, u := range src.Usage(ctx) {
+			rows = append(rows, sql.Row{src.Name, u.Component, nullable(u.Bytes), nullable(u.Capacity), nullable(u.Entries)})
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// flush flushes the subsystems in |sources| named by |names|, or every one that can be flushed if there are none.
+func flush(ctx *sql.Context, sources []Source, names []string) error {
+	byName := make(map[string]Source, len(sources))
+	for _, src := range sources {
+		byName[src.Name] = src
+	}
+
+	if len(names) == 0 {
+		for _, src := range sources {
+			if src.Flush != nil {
+				names = append(names, src.Name)
+			}
+		}
+	}
+	for _, name := range names {
+		src, ok := byName[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("%s: unknown subsystem %s", ProcedureName, name)
+		}
+		if src.Flush == nil {
+			return fmt.Errorf("%s: subsystem %s can't be flushed", ProcedureName, name)
+		}
+	}
+
+	for _, name := range names {
+		byName[strings.ToLower(name)].Flush(ctx)
+	}
+	// collects garbage and returns freed memory to the OS, so the sizes reported reflect the flush
+	debug.FreeOSMemory()
+	return nil
+}
+
+func nullable(v int64) interface{} {
+	if v == Unknown {
+		return nil
+	}
+	return v
+}
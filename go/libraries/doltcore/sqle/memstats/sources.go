@@ -0,0 +1,181 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memstats
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+)
+
+// Unknown is the value of a Usage field which a subsystem doesn't track.
+const Unknown = -1
+
+// Usage is the memory used by one component of a subsystem.
+type Usage struct {
+	Component string
+	// Bytes is the memory used, and Capacity the memory the component is limited to
+	Bytes, Capacity int64
+	// Entries is the number of items held, eg. cached chunks or results
+	Entries int64
+}
+
+// Source is a subsystem whose memory use is reported by DOLT_MEMORY_STATS.
+type Source struct {
+	// Name identifies the subsystem in reports, and to DOLT_MEMORY_STATS('--flush', name)
+	Name string
+	// Usage returns the memory used by each of the subsystem's components
+	Usage func(ctx *sql.Context) []Usage
+	// Flush frees the memory held by the subsystem which can be recomputed. It's nil for subsystems which can't be
+	// flushed.
+	Flush func(ctx *sql.Context)
+}
+
+const (
+	SourceRuntime   = "runtime"
+	SourceNodeCache = "node_cache"
+	SourceDiffCache = "diff_cache"
+	SourceSessions  = "sessions"
+)
+
+var builtinSources = []Source{
+	{Name: SourceRuntime, Usage: runtimeUsage},
+	{Name: SourceNodeCache, Usage: nodeCacheUsage, Flush: func(*sql.Context) { tree.PurgeNodeCache() }},
+	{Name: SourceDiffCache, Usage: diffCacheUsage, Flush: func(*sql.Context) { tree.PurgeDiffCache() }},
+	{Name: SourceSessions, Usage: sessionsUsage, Flush: flushSessions},
+}
+
+var registered = struct {
+	mu      sync.Mutex
+	sources map[string]Source
+}{sources: make(map[string]Source)}
+
+// Register adds |src| to the subsystems reported by DOLT_MEMORY_STATS, replacing any registered with the same name.
+// Subsystems outside of the storage layer, like the caches of a sql-server, register themselves when they're created.
+func Register(src Source) {
+	registered.mu.Lock()
+	defer registered.mu.Unlock()
+	registered.sources[src.Name] = src
+}
+
+// Sources returns the subsystems reported by DOLT_MEMORY_STATS: the built in ones, followed by registered ones in
+// order of name.
+func Sources() []Source {
+	registered.mu.Lock()
+	defer registered.mu.Unlock()
+
+	sources := append([]Source{}, builtinSources...)
+	names := make([]string, 0, len(registered.sources))
+	for name := range registered.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sources = append(sources, registered.sources[name])
+	}
+	return sources
+}
+
+func runtimeUsage(*sql.Context) []Usage {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return []Usage{
+		{Component: "heap allocated", Bytes: int64(ms.HeapAlloc), Capacity: Unknown, Entries: int64(ms.HeapObjects)},
+		{Component: "heap in use", Bytes: int64(ms.HeapInuse), Capacity: Unknown, Entries: Unknown},
+		{Component: "heap idle", Bytes: int64(ms.HeapIdle - ms.HeapReleased), Capacity: Unknown, Entries: Unknown},
+		{Component: "goroutine stacks", Bytes: int64(ms.StackInuse), Capacity: Unknown, Entries: int64(runtime.NumGoroutine())},
+		{Component: "total from os", Bytes: int64(ms.Sys), Capacity: Unknown, Entries: Unknown},
+	}
+}
+
+func nodeCacheUsage(*sql.Context) []Usage {
+	var usage []Usage
+	for _, s := range tree.NodeCacheStats() {
+		component := s.Tier + " (shared)"
+		if s.Partition != "" {
+			component = fmt.Sprintf("%s (%s)", s.Tier, s.Partition)
+		}
+		usage = append(usage, Usage{
+			Component: component,
+			Bytes:     int64(s.Size),
+			Capacity:  int64(s.Capacity),
+			Entries:   Unknown,
+		})
+	}
+	return usage
+}
+
+func diffCacheUsage(*sql.Context) []Usage {
+	size, capacity := tree.DiffCacheSize()
+	return []Usage{{Component: "diffs", Bytes: int64(size), Capacity: int64(capacity), Entries: Unknown}}
+}
+
+// doltSessions returns the sessions of the running sql-server, or just the session of |ctx| when there isn't one.
+func doltSessions(ctx *sql.Context) ([]*dsess.DoltSession, error) {
+	srv := sqlserver.GetRunningServer()
+	if srv == nil {
+		return []*dsess.DoltSession{dsess.DSessFromSess(ctx.Session)}, nil
+	}
+
+	var sessions []*dsess.DoltSession
+	err := srv.SessionManager().Iter(func(session sql.Session) (bool, error) {
+		if sess, ok := session.(*dsess.DoltSession); ok {
+			sessions = append(sessions, sess)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func sessionsUsage(ctx *sql.Context) []Usage {
+	sessions, err := doltSessions(ctx)
+	if err != nil {
+		ctx.GetLogger().Warnf("unable to list sessions: %s", err.Error())
+		return nil
+	}
+
+	var total dsess.MemoryStats
+	for _, sess := range sessions {
+		stats := sess.MemoryStats()
+		total.DirtyWorkingSets += stats.DirtyWorkingSets
+		total.CacheEntries += stats.CacheEntries
+	}
+	return []Usage{
+		{Component: "connections", Bytes: Unknown, Capacity: Unknown, Entries: int64(len(sessions))},
+		{Component: "dirty working sets", Bytes: Unknown, Capacity: Unknown, Entries: int64(total.DirtyWorkingSets)},
+		{Component: "schema and index caches", Bytes: Unknown, Capacity: Unknown, Entries: int64(total.CacheEntries)},
+	}
+}
+
+func flushSessions(ctx *sql.Context) {
+	sessions, err := doltSessions(ctx)
+	if err != nil {
+		ctx.GetLogger().Warnf("unable to list sessions: %s", err.Error())
+		return
+	}
+	for _, sess := range sessions {
+		sess.ClearCaches()
+	}
+}
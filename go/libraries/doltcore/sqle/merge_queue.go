@@ -0,0 +1,198 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const mergeQueueThread = "merge_queue"
+
+// ErrMergeQueueNotRunning is returned when adding a merge to the merge queue of a provider which isn't processing it.
+var ErrMergeQueueNotRunning = errors.New("the merge queue is only available in a running sql-server")
+
+// MergeQueueMerger does a merge from the merge queue, returning the hash of the commit the target branch was updated
+// to. The target branch must be left unchanged if an error is returned.
+type MergeQueueMerger func(ctx context.Context, entry dsess.MergeQueueEntry) (string, error)
+
+// mergeQueue holds the merges added to the merge queue of a DoltDatabaseProvider, which are done one at a time in the
+// order they were added.
+type mergeQueue struct {
+	mu      sync.Mutex
+	merger  MergeQueueMerger
+	nextID  int64
+	entries []dsess.MergeQueueEntry
+	// wake is signaled when a merge is added to the queue
+	wake chan struct{}
+}
+
+func newMergeQueue() *mergeQueue {
+	return &mergeQueue{nextID: 1, wake: make(chan struct{}, 1)}
+}
+
+// next marks the first queued merge as running and returns it, or returns false if there are none.
+func (q *mergeQueue) next() (dsess.MergeQueueEntry, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i := range q.entries {
+		if q.entries[i].State == dsess.MergeQueued {
+			q.entries[i].State = dsess.MergeRunning
+			return q.entries[i], true
+		}
+	}
+	return dsess.MergeQueueEntry{}, false
+}
+
+// finish records the result of the merge with the id given.
+func (q *mergeQueue) finish(id int64, commit string, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i := range q.entries {
+		if q.entries[i].ID == id {
+			if err != nil {
+				q.entries[i].State = dsess.MergeFailed
+				q.entries[i].Error = err.Error()
+			} else {
+				q.entries[i].State = dsess.MergeMerged
+				q.entries[i].Commit = commit
+			}
+			q.entries[i].FinishedAt = time.Now()
+			return
+		}
+	}
+}
+
+// EnqueueMerge implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) EnqueueMerge(ctx *sql.Context, dbName, source, target string, runTests bool) (int64, error) {
+	db, ok := p.BaseDatabase(ctx, dbName)
+	if !ok {
+		return 0, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	for _, branch := range []*string{&source, &target} {
+		name, ok, err := db.DbData().Ddb.HasBranch(ctx, *branch)
+		if err != nil {
+			return 0, err
+		} else if !ok {
+			return 0, fmt.Errorf("branch not found: %s", *branch)
+		}
+		*branch = name
+	}
+	if source == target {
+		return 0, fmt.Errorf("cannot merge branch %s into itself", source)
+	}
+	// The merge changes |target| rather than the session's current branch, so the user must be able to write to it
+	if err := branch_control.CheckBranchAccess(ctx, target, branch_control.Permissions_Write); err != nil {
+		return 0, err
+	}
+
+	q := p.mergeQueue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.merger == nil {
+		return 0, ErrMergeQueueNotRunning
+	}
+
+	id := q.nextID
+	q.nextID++
+	q.entries = append(q.entries, dsess.MergeQueueEntry{
+		ID:         id,
+		Database:   db.Name(),
+		Source:     source,
+		Target:     target,
+		RunTests:   runTests,
+		State:      dsess.MergeQueued,
+		User:       ctx.Session.Client().User,
+		Host:       ctx.Session.Client().Address,
+		EnqueuedAt: time.Now(),
+	})
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return id, nil
+}
+
+// CancelQueuedMerge implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) CancelQueuedMerge(ctx *sql.Context, dbName string, id int64) error {
+	q := p.mergeQueue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i := range q.entries {
+		e := &q.entries[i]
+		if e.ID != id || !strings.EqualFold(e.Database, dbName) {
+			continue
+		}
+		if e.State != dsess.MergeQueued {
+			return fmt.Errorf("merge %d is %s and can't be canceled", id, e.State)
+		}
+		e.State = dsess.MergeCanceled
+		e.FinishedAt = time.Now()
+		return nil
+	}
+	return fmt.Errorf("no merge with id %d in the merge queue of database %s", id, dbName)
+}
+
+// MergeQueue implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) MergeQueue(dbName string) []dsess.MergeQueueEntry {
+	q := p.mergeQueue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var ret []dsess.MergeQueueEntry
+	for _, e := range q.entries {
+		if strings.EqualFold(e.Database, dbName) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// StartMergeQueue starts a background thread which does the merges added to the merge queue of this provider with
+// |merger|, one at a time in the order they were added. Merges can only be added to the queue once it's started.
+func (p *DoltDatabaseProvider) StartMergeQueue(merger MergeQueueMerger, bThreads *sql.BackgroundThreads) error {
+	q := p.mergeQueue
+	q.mu.Lock()
+	q.merger = merger
+	q.mu.Unlock()
+
+	return bThreads.Add(mergeQueueThread, func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.wake:
+			}
+
+			for ctx.Err() == nil {
+				entry, ok := q.next()
+				if !ok {
+					break
+				}
+				commit, err := merger(ctx, entry)
+				q.finish(entry.ID, commit, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,241 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimizertrace
+
+import (
+	"fmt"
+	"strings"
+
+	gms "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// ProcedureName is the name of the stored procedure which traces the optimizer's choices for a query.
+const ProcedureName = "dolt_optimizer_trace"
+
+const (
+	stepStatistics  = "statistics"
+	stepAccessPaths = "access paths"
+	stepPlan        = "plan"
+)
+
+var traceSchema = sql.Schema{
+	&sql.Column{Name: "step", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "trace", Type: types.LongText, Nullable: false},
+}
+
+// NewProcedure returns the DOLT_OPTIMIZER_TRACE(query) stored procedure, which plans |query| with |e| without running
+// it, and returns rows describing how the plan was chosen:
+//   - statistics: the row count of each table the plan reads, and the cardinality of each of its indexes, with
+//     whether they came from collected histograms or were estimated from the table's row data.
+//   - access paths: the ways each table could be read, a table scan or a lookup on one of its indexes, and which
+//     one the plan uses.
+//   - plan: the chosen plan, with the optimizer's cost and row estimates for each node.
+func NewProcedure(e *gms.Engine) sql.ExternalStoredProcedureDetails {
+	return sql.ExternalStoredProcedureDetails{
+		Name:     ProcedureName,
+		Schema:   traceSchema,
+		Function: optimizerTrace(e),
+		ReadOnly: true,
+	}
+}
+
+func optimizerTrace(e *gms.Engine) func(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	return func(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s expects a single query to trace", ProcedureName)
+		}
+
+		node, err := e.AnalyzeQuery(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		var rows []sql.Row
+		for _, src := range tableSources(node) {
+			lines, err := describeStatistics(ctx, src)
+			if err != nil {
+				return nil, err
+			}
+			for _, line := range lines {
+				rows = append(rows, sql.Row{stepStatistics, line})
+			}
+		}
+		for _, src := range tableSources(node) {
+			lines, err := describeAccessPaths(ctx, src)
+			if err != nil {
+				return nil, err
+			}
+			for _, line := range lines {
+				rows = append(rows, sql.Row{stepAccessPaths, line})
+			}
+		}
+		for _, line := range strings.Split(sql.Describe(node, sql.DescribeOptions{Estimates: true}), "\n") {
+			if line != "" {
+				rows = append(rows, sql.Row{stepPlan, line})
+			}
+		}
+		return sql.RowsToRowIter(rows...), nil
+	}
+}
+
+// tableSource is a table read by a plan.
+type tableSource struct {
+	db    string
+	table sql.Table
+	// index is the index the plan reads the table with, or nil if it scans the table
+	index sql.Index
+}
+
+// tableSources returns the tables read by the plan |node|, in the order they appear in it.
+func tableSources(node sql.Node) []tableSource {
+	var sources []tableSource
+	transform.Inspect(node, func(n sql.Node) bool {
+		switch n := n.(type) {
+		case *plan.IndexedTableAccess:
+			sources = append(sources, tableSource{db: databaseName(n), table: n.UnderlyingTable(), index: n.Index()})
+			return false
+		case *plan.ResolvedTable:
+			sources = append(sources, tableSource{db: databaseName(n), table: n.UnderlyingTable()})
+			return false
+		}
+		return true
+	})
+	return sources
+}
+
+func databaseName(n sql.Node) string {
+	if d, ok := n.(sql.Databaser); ok && d.Database() != nil {
+		return d.Database().Name()
+	}
+	return ""
+}
+
+// indexCardinality is what the optimizer knows about the number of distinct keys in an index.
+type indexCardinality struct {
+	rows      uint64
+	distinct  uint64
+	nulls     uint64
+	buckets   int
+	collected bool
+}
+
+// cardinalities returns the cardinality of each index of |src|, keyed by lower-cased index ID, and its row count.
+// Indexes without collected statistics are estimated from the table's row count, which is exact for unique indexes.
+func cardinalities(ctx *sql.Context, src tableSource) (map[string]indexCardinality, uint64, error) {
+	statsPro := dsess.DSessFromSess(ctx.Session).StatsProvider()
+	if statsPro == nil {
+		return nil, 0, fmt.Errorf("%s requires a statistics provider", ProcedureName)
+	}
+	rowCount, err := statsPro.RowCount(ctx, src.db, src.table)
+	if err != nil {
+		return nil, 0, err
+	}
+	tableStats, err := statsPro.GetTableStats(ctx, src.db, src.table)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ret := make(map[string]indexCardinality)
+	for _, stat := range tableStats {
+		ret[strings.ToLower(stat.Qualifier().Index())] = indexCardinality{
+			rows:      stat.RowCount(),
+			distinct:  stat.DistinctCount(),
+			nulls:     stat.NullCount(),
+			buckets:   len(stat.Histogram()),
+			collected: true,
+		}
+	}
+	for _, idx := range tableIndexes(ctx, src.table) {
+		id := strings.ToLower(idx.ID())
+		if _, ok := ret[id]; ok {
+			continue
+		}
+		card := indexCardinality{rows: rowCount}
+		if idx.IsUnique() || id == "primary" {
+			card.distinct = rowCount
+		}
+		ret[id] = card
+	}
+	return ret, rowCount, nil
+}
+
+func tableIndexes(ctx *sql.Context, table sql.Table) []sql.Index {
+	iat, ok := table.(sql.IndexAddressableTable)
+	if !ok {
+		return nil
+	}
+	indexes, err := iat.GetIndexes(ctx)
+	if err != nil {
+		return nil
+	}
+	return indexes
+}
+
+func describeStatistics(ctx *sql.Context, src tableSource) ([]string, error) {
+	cards, rowCount, err := cardinalities(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{fmt.Sprintf("%s: rows=%d", src.table.Name(), rowCount)}
+	for _, idx := range tableIndexes(ctx, src.table) {
+		card := cards[strings.ToLower(idx.ID())]
+		line := fmt.Sprintf("%s.%s (%s): rows=%d", src.table.Name(), idx.ID(), strings.Join(idx.Expressions(), ","), card.rows)
+		if card.distinct > 0 {
+			line += fmt.Sprintf(" distinct=%d", card.distinct)
+		} else {
+			line += " distinct=unknown"
+		}
+		if card.collected {
+			line += fmt.Sprintf(" nulls=%d histogram buckets=%d", card.nulls, card.buckets)
+		} else {
+			line += " no histogram"
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func describeAccessPaths(ctx *sql.Context, src tableSource) ([]string, error) {
+	cards, rowCount, err := cardinalities(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	chosen := func(ok bool) string {
+		if ok {
+			return " (chosen)"
+		}
+		return ""
+	}
+
+	lines := []string{fmt.Sprintf("%s: table scan, rows=%d%s", src.table.Name(), rowCount, chosen(src.index == nil))}
+	for _, idx := range tableIndexes(ctx, src.table) {
+		card := cards[strings.ToLower(idx.ID())]
+		line := fmt.Sprintf("%s: lookup on %s", src.table.Name(), idx.ID())
+		if card.distinct > 0 {
+			// a lookup of a single key reads the rows matching it, which are rows/distinct on average
+			line += fmt.Sprintf(", rows per key=%.2f", float64(card.rows)/float64(card.distinct))
+		}
+		lines = append(lines, line+chosen(src.index != nil && strings.EqualFold(src.index.ID(), idx.ID())))
+	}
+	return lines, nil
+}
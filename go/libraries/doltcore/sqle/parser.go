@@ -0,0 +1,598 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// Parser is a sql.Parser which supports the Dolt specific statement
+//
+//	CREATE DATABASE <name> FROM REMOTE '<url>'
+//
+// and the XA transaction statements and MariaDB's sequence statements, in addition to everything supported by the
+// parser it wraps. These statements are read with the vitess tokenizer and parsed into the statements which implement
+// them: CREATE DATABASE ... FROM REMOTE is equivalent to CALL DOLT_CLONE('<url>', '<name>'), the XA statements are
+// implemented by DOLT_XA, and CREATE SEQUENCE and DROP SEQUENCE insert into and delete from the dolt_sequences table.
+//
+// MariaDB's NEXT VALUE FOR <name> expression can appear anywhere in a statement, so it can't be parsed on its own.
+// Instead, each one is replaced with the equivalent call NEXTVAL('<name>') before the query is given to the wrapped
+// parser.
+type Parser struct {
+	sql.Parser
+}
+
+var _ sql.Parser = Parser{}
+
+// NewParser returns a Parser which wraps |parser|.
+func NewParser(parser sql.Parser) Parser {
+	return Parser{Parser: parser}
+}
+
+// ParseSimple implements sql.Parser.
+func (p Parser) ParseSimple(query string) (sqlparser.Statement, error) {
+	if stmt, _, _, ok := parseDoltStatement(query, ';', false, sqlparser.ParserOptions{}); ok {
+		return stmt, nil
+	}
+	return p.Parser.ParseSimple(rewriteNextValueFor(query, sqlparser.ParserOptions{}).rewritten)
+}
+
+// Parse implements sql.Parser.
+func (p Parser) Parse(ctx *sql.Context, query string, multi bool) (sqlparser.Statement, string, string, error) {
+	options := sql.LoadSqlMode(ctx).ParserOptions()
+	if stmt, parsed, remainder, ok := parseDoltStatement(query, ';', multi, options); ok {
+		return stmt, parsed, remainder, nil
+	}
+	rw := rewriteNextValueFor(query, options)
+	stmt, parsed, remainder, err := p.Parser.Parse(ctx, rw.rewritten, multi)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return stmt, parsed, rw.remainder(remainder), nil
+}
+
+// ParseWithOptions implements sql.Parser.
+func (p Parser) ParseWithOptions(ctx context.Context, query string, delimiter rune, multi bool, options sqlparser.ParserOptions) (sqlparser.Statement, string, string, error) {
+	if stmt, parsed, remainder, ok := parseDoltStatement(query, delimiter, multi, options); ok {
+		return stmt, parsed, remainder, nil
+	}
+	rw := rewriteNextValueFor(query, options)
+	stmt, parsed, remainder, err := p.Parser.ParseWithOptions(ctx, rw.rewritten, delimiter, multi, options)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return stmt, parsed, rw.remainder(remainder), nil
+}
+
+// ParseOneWithOptions implements sql.Parser.
+func (p Parser) ParseOneWithOptions(ctx context.Context, query string, options sqlparser.ParserOptions) (sqlparser.Statement, int, error) {
+	if stmt, end, ok := parseFirstDoltStatement(query, options); ok {
+		return stmt, end, nil
+	}
+	rw := rewriteNextValueFor(query, options)
+	stmt, end, err := p.Parser.ParseOneWithOptions(ctx, rw.rewritten, options)
+	if err != nil {
+		return nil, 0, err
+	}
+	return stmt, rw.originalIndex(end), nil
+}
+
+// parseDoltStatement parses |query| like sql.MysqlParser.ParseWithOptions, if it begins with a Dolt specific
+// statement. Returns false if it doesn't, or if |multi| is false and the statement is followed by another one, in
+// which case the query is left to the wrapped parser.
+func parseDoltStatement(query string, delimiter rune, multi bool, options sqlparser.ParserOptions) (stmt sqlparser.Statement, parsed, remainder string, ok bool) {
+	s := sql.RemoveSpaceAndDelimiter(query, delimiter)
+	stmt, end, ok := parseFirstDoltStatement(s, options)
+	if !ok {
+		return nil, "", "", false
+	}
+	parsed = s
+	if end < len(s) && strings.TrimSpace(s[end:]) != "" {
+		if !multi {
+			return nil, "", "", false
+		}
+		parsed = sql.RemoveSpaceAndDelimiter(s[:end], delimiter)
+		remainder = s[end:]
+	}
+	return stmt, parsed, remainder, true
+}
+
+// parseFirstDoltStatement parses the Dolt specific statement at the start of |query|, if there is one. Returns the
+// statement and the index of the end of it in |query|, after the semicolon terminating it if there is one, or false if
+// |query| doesn't begin with a Dolt specific statement.
+func parseFirstDoltStatement(query string, options sqlparser.ParserOptions) (sqlparser.Statement, int, bool) {
+	for _, parse := range []func(*tokenReader) (sqlparser.Statement, bool){parseCreateDatabaseFromRemote, parseXa, parseCreateSequence, parseDropSequence} {
+		tokens := newTokenReader(query, options)
+		stmt, ok := parse(tokens)
+		if !ok {
+			continue
+		}
+		// The statement must be followed by the end of the query, or the semicolon ending it
+		switch tokens.typ {
+		case 0:
+			return stmt, len(query), true
+		case ';':
+			// The tokenizer has read one character past the semicolon
+			return stmt, min(tokens.tkn.Position-1, len(query)), true
+		default:
+			return nil, 0, false
+		}
+	}
+	return nil, 0, false
+}
+
+// tokenReader reads the tokens of a query one at a time.
+type tokenReader struct {
+	tkn *sqlparser.Tokenizer
+	// typ and val are the current token
+	typ int
+	val []byte
+}
+
+// newTokenReader returns a tokenReader for |query|, positioned at its first token.
+func newTokenReader(query string, options sqlparser.ParserOptions) *tokenReader {
+	tkn := sqlparser.NewStringTokenizer(query)
+	if options.AnsiQuotes {
+		tkn = sqlparser.NewStringTokenizerForAnsiQuotes(query)
+	}
+	tokens := &tokenReader{tkn: tkn}
+	tokens.next()
+	return tokens
+}
+
+// next reads the next token, skipping comments.
+func (t *tokenReader) next() {
+	for {
+		t.typ, t.val = t.tkn.Scan()
+		if t.typ != sqlparser.COMMENT {
+			return
+		}
+	}
+}
+
+// word returns whether the current token is the keyword or identifier |w|, ignoring case, and reads the next token if
+// it is.
+func (t *tokenReader) word(w string) bool {
+	if t.typ != sqlparser.ID && sqlparser.KeywordString(t.typ) == "" {
+		return false
+	}
+	if !strings.EqualFold(string(t.val), w) {
+		return false
+	}
+	t.next()
+	return true
+}
+
+// literal returns the current token as a string, hex or bit literal, and reads the next token, or returns false if
+// it isn't one.
+func (t *tokenReader) literal() (sqlparser.Expr, bool) {
+	var expr sqlparser.Expr
+	switch t.typ {
+	case sqlparser.STRING:
+		expr = sqlparser.NewStrVal(t.val)
+	case sqlparser.HEX:
+		expr = sqlparser.NewHexVal(t.val)
+	case sqlparser.HEXNUM:
+		expr = sqlparser.NewHexNum(t.val)
+	case sqlparser.BIT_LITERAL:
+		expr = sqlparser.NewBitVal(t.val)
+	default:
+		return nil, false
+	}
+	t.next()
+	return expr, true
+}
+
+// integer returns the current token as an integer literal, which may have a sign, and reads the next token, or
+// returns false if it isn't one.
+func (t *tokenReader) integer() (sqlparser.Expr, bool) {
+	sign := ""
+	if t.typ == '-' || t.typ == '+' {
+		sign = string(rune(t.typ))
+		t.next()
+	}
+	if t.typ != sqlparser.INTEGRAL {
+		return nil, false
+	}
+	expr := sqlparser.NewIntVal([]byte(strings.TrimPrefix(sign, "+") + string(t.val)))
+	t.next()
+	return expr, true
+}
+
+// sequenceName returns the current token as the name of a sequence, and reads the next token, or returns false if it
+// isn't an identifier. Sequences belong to the database whose dolt_sequences table they're in, so their names can't
+// be qualified.
+func (t *tokenReader) sequenceName() (sqlparser.Expr, bool) {
+	if t.typ != sqlparser.ID {
+		return nil, false
+	}
+	name := sqlparser.NewStrVal(t.val)
+	t.next()
+	return name, true
+}
+
+// parseCreateDatabaseFromRemote parses the statement
+//
+//	CREATE {DATABASE | SCHEMA} <name> FROM REMOTE '<url>'
+//
+// into the equivalent call to DOLT_CLONE. Returns false if the tokens aren't this statement.
+func parseCreateDatabaseFromRemote(tokens *tokenReader) (sqlparser.Statement, bool) {
+	if !tokens.word("create") || !(tokens.word("database") || tokens.word("schema")) {
+		return nil, false
+	}
+	// bare and backtick quoted names are both identifiers
+	if tokens.typ != sqlparser.ID {
+		return nil, false
+	}
+	name := sqlparser.NewStrVal(tokens.val)
+	tokens.next()
+
+	if !tokens.word("from") || !tokens.word("remote") || tokens.typ != sqlparser.STRING {
+		return nil, false
+	}
+	url := sqlparser.NewStrVal(tokens.val)
+	tokens.next()
+
+	return &sqlparser.Call{
+		ProcName: sqlparser.ProcedureName{Name: sqlparser.NewColIdent("dolt_clone")},
+		Params:   []sqlparser.Expr{url, name},
+	}, true
+}
+
+// parseXa parses one of the XA statements
+//
+//	XA {START | BEGIN} xid [JOIN | RESUME]
+//	XA END xid [SUSPEND [FOR MIGRATE]]
+//	XA PREPARE xid
+//	XA COMMIT xid [ONE PHASE]
+//	XA ROLLBACK xid
+//	XA RECOVER [CONVERT XID]
+//
+// into the equivalent call to DOLT_XA. An xid is a gtrid, optionally followed by a bqual and a formatID, where the
+// gtrid and bqual are string, hex or bit literals and the formatID is an integer. Returns false if the tokens aren't
+// an XA statement.
+func parseXa(tokens *tokenReader) (sqlparser.Statement, bool) {
+	if !tokens.word("xa") {
+		return nil, false
+	}
+
+	var action string
+	switch {
+	case tokens.word("start"), tokens.word("begin"):
+		action = "start"
+	case tokens.word("end"):
+		action = "end"
+	case tokens.word("prepare"):
+		action = "prepare"
+	case tokens.word("commit"):
+		action = "commit"
+	case tokens.word("rollback"):
+		action = "rollback"
+	case tokens.word("recover"):
+		action = "recover"
+		if tokens.word("convert") {
+			if !tokens.word("xid") {
+				return nil, false
+			}
+			action = "recover convert xid"
+		}
+		return newXaCall(action, nil), true
+	default:
+		return nil, false
+	}
+
+	gtrid, ok := tokens.literal()
+	if !ok {
+		return nil, false
+	}
+	xid := []sqlparser.Expr{gtrid}
+	if tokens.typ == ',' {
+		tokens.next()
+		bqual, ok := tokens.literal()
+		if !ok {
+			return nil, false
+		}
+		xid = append(xid, bqual)
+		if tokens.typ == ',' {
+			tokens.next()
+			if tokens.typ != sqlparser.INTEGRAL {
+				return nil, false
+			}
+			xid = append(xid, sqlparser.NewIntVal(tokens.val))
+			tokens.next()
+		}
+	}
+
+	switch action {
+	case "start":
+		// like MySQL, JOIN and RESUME are accepted but have no effect
+		if !tokens.word("join") {
+			tokens.word("resume")
+		}
+	case "end":
+		// A suspended transaction can't be resumed, so suspending it just ends it
+		if tokens.word("suspend") && tokens.word("for") && !tokens.word("migrate") {
+			return nil, false
+		}
+	case "commit":
+		if tokens.word("one") {
+			if !tokens.word("phase") {
+				return nil, false
+			}
+			action = "commit one phase"
+		}
+	}
+	return newXaCall(action, xid), true
+}
+
+// newXaCall returns a call to DOLT_XA which does |action| with the transaction |xid|.
+func newXaCall(action string, xid []sqlparser.Expr) *sqlparser.Call {
+	return &sqlparser.Call{
+		ProcName: sqlparser.ProcedureName{Name: sqlparser.NewColIdent("dolt_xa")},
+		Params:   append([]sqlparser.Expr{sqlparser.NewStrVal([]byte(action))}, xid...),
+	}
+}
+
+// parseCreateSequence parses MariaDB's statement
+//
+//	CREATE SEQUENCE [IF NOT EXISTS] <name> [<option> ...]
+//
+// into an insert into the dolt_sequences table. The options are START [WITH | =] n, INCREMENT [BY | =] n,
+// MINVALUE [=] n, MAXVALUE [=] n, CACHE [=] n, CYCLE, and NO MINVALUE, NO MAXVALUE, NO CACHE and NO CYCLE, which may
+// also be written without a space, in any order. Like in MariaDB, a sequence starts at its MINVALUE unless START is
+// given. CACHE is accepted, but has no effect, and the table options of the table holding a MariaDB sequence aren't
+// supported. Returns false if the tokens aren't this statement.
+func parseCreateSequence(tokens *tokenReader) (sqlparser.Statement, bool) {
+	if !tokens.word("create") || !tokens.word("sequence") {
+		return nil, false
+	}
+	ifNotExists := false
+	if tokens.word("if") {
+		if !tokens.word("not") || !tokens.word("exists") {
+			return nil, false
+		}
+		ifNotExists = true
+	}
+	name, ok := tokens.sequenceName()
+	if !ok {
+		return nil, false
+	}
+
+	cols := sqlparser.Columns{sqlparser.NewColIdent(doltdb.SequencesNameCol)}
+	vals := sqlparser.ValTuple{name}
+	setOption := func(col string, val sqlparser.Expr) {
+		for i := range cols {
+			if cols[i].EqualString(col) {
+				vals[i] = val
+				return
+			}
+		}
+		cols = append(cols, sqlparser.NewColIdent(col))
+		vals = append(vals, val)
+	}
+	// value reads the value of an option, which may be preceded by an equals sign
+	value := func() (sqlparser.Expr, bool) {
+		if tokens.typ == '=' {
+			tokens.next()
+		}
+		return tokens.integer()
+	}
+
+	var startGiven bool
+	var minValue sqlparser.Expr
+	for tokens.typ != 0 && tokens.typ != ';' {
+		var val sqlparser.Expr
+		switch {
+		case tokens.word("start"):
+			tokens.word("with")
+			if val, ok = value(); !ok {
+				return nil, false
+			}
+			setOption(doltdb.SequencesStartValueCol, val)
+			startGiven = true
+		case tokens.word("increment"):
+			tokens.word("by")
+			if val, ok = value(); !ok {
+				return nil, false
+			}
+			setOption(doltdb.SequencesIncrementCol, val)
+		case tokens.word("minvalue"):
+			if minValue, ok = value(); !ok {
+				return nil, false
+			}
+			setOption(doltdb.SequencesMinValueCol, minValue)
+		case tokens.word("maxvalue"):
+			if val, ok = value(); !ok {
+				return nil, false
+			}
+			setOption(doltdb.SequencesMaxValueCol, val)
+		case tokens.word("cache"):
+			if _, ok = value(); !ok {
+				return nil, false
+			}
+		case tokens.word("cycle"):
+			setOption(doltdb.SequencesCycleCol, sqlparser.NewIntVal([]byte("1")))
+		case tokens.word("nocycle"):
+			setOption(doltdb.SequencesCycleCol, sqlparser.NewIntVal([]byte("0")))
+		case tokens.word("nominvalue"), tokens.word("nomaxvalue"), tokens.word("nocache"):
+		case tokens.word("no"):
+			switch {
+			case tokens.word("cycle"):
+				setOption(doltdb.SequencesCycleCol, sqlparser.NewIntVal([]byte("0")))
+			case tokens.word("minvalue"), tokens.word("maxvalue"), tokens.word("cache"):
+			default:
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	if !startGiven && minValue != nil {
+		setOption(doltdb.SequencesStartValueCol, minValue)
+	}
+
+	insert := &sqlparser.Insert{
+		Action:  sqlparser.InsertStr,
+		Table:   sqlparser.TableName{Name: sqlparser.NewTableIdent(doltdb.SequencesTableName)},
+		Columns: cols,
+		Rows:    &sqlparser.AliasedValues{Values: sqlparser.Values{vals}},
+	}
+	if ifNotExists {
+		nameCol := &sqlparser.ColName{Name: sqlparser.NewColIdent(doltdb.SequencesNameCol)}
+		insert.OnDup = sqlparser.OnDup{&sqlparser.AssignmentExpr{Name: nameCol, Expr: nameCol}}
+	}
+	return insert, true
+}
+
+// parseDropSequence parses MariaDB's statement
+//
+//	DROP SEQUENCE [IF EXISTS] <name> [, <name> ...]
+//
+// into a delete from the dolt_sequences table. Unlike in MariaDB, dropping a sequence which doesn't exist isn't an
+// error, whether or not IF EXISTS is given. Returns false if the tokens aren't this statement.
+func parseDropSequence(tokens *tokenReader) (sqlparser.Statement, bool) {
+	if !tokens.word("drop") || !tokens.word("sequence") {
+		return nil, false
+	}
+	if tokens.word("if") && !tokens.word("exists") {
+		return nil, false
+	}
+	var names sqlparser.ValTuple
+	for {
+		name, ok := tokens.sequenceName()
+		if !ok {
+			return nil, false
+		}
+		names = append(names, name)
+		if tokens.typ != ',' {
+			break
+		}
+		tokens.next()
+	}
+
+	return &sqlparser.Delete{
+		TableExprs: sqlparser.TableExprs{&sqlparser.AliasedTableExpr{
+			Expr: sqlparser.TableName{Name: sqlparser.NewTableIdent(doltdb.SequencesTableName)},
+		}},
+		Where: sqlparser.NewWhere(sqlparser.WhereStr, &sqlparser.ComparisonExpr{
+			Operator: sqlparser.InStr,
+			Left:     &sqlparser.ColName{Name: sqlparser.NewColIdent(doltdb.SequencesNameCol)},
+			Right:    names,
+		}),
+	}, true
+}
+
+// nextValueForRewrite is a query whose NEXT VALUE FOR expressions have been replaced with calls to NEXTVAL.
+type nextValueForRewrite struct {
+	query     string
+	rewritten string
+	// replaced holds the replaced expressions, in the order they appear in |query|
+	replaced []replacedExpr
+}
+
+// replacedExpr is an expression at |query[start:end]| which was replaced by |length| bytes.
+type replacedExpr struct {
+	start, end, length int
+}
+
+// rewriteNextValueFor replaces each NEXT VALUE FOR <name> expression in |query| with the equivalent call
+// NEXTVAL('<name>'). The query is only tokenized if it contains the word NEXT.
+func rewriteNextValueFor(query string, options sqlparser.ParserOptions) nextValueForRewrite {
+	rw := nextValueForRewrite{query: query, rewritten: query}
+	if !containsFold(query, "next") {
+		return rw
+	}
+
+	var sb strings.Builder
+	copied := 0
+	tokens := newTokenReader(query, options)
+	for tokens.typ != 0 && tokens.typ != sqlparser.LEX_ERROR {
+		// The tokenizer has read one character past the end of the token. Tokens in special comments are skipped,
+		// since their positions are relative to the comment.
+		end := min(tokens.tkn.Position-1, len(query))
+		start := end - len(tokens.val)
+		if start < copied || !strings.EqualFold(query[start:end], "next") {
+			tokens.next()
+			continue
+		}
+		tokens.next()
+		if !tokens.word("value") || !tokens.word("for") || tokens.typ != sqlparser.ID {
+			// the current token hasn't been looked at yet
+			continue
+		}
+		end = min(tokens.tkn.Position-1, len(query))
+		name := tokens.val
+		tokens.next()
+		if tokens.typ == '.' {
+			// sequences can't be qualified
+			continue
+		}
+		call := sqlparser.String(&sqlparser.FuncExpr{
+			Name:  sqlparser.NewColIdent("nextval"),
+			Exprs: sqlparser.SelectExprs{&sqlparser.AliasedExpr{Expr: sqlparser.NewStrVal(name)}},
+		})
+		sb.WriteString(query[copied:start])
+		sb.WriteString(call)
+		rw.replaced = append(rw.replaced, replacedExpr{start: start, end: end, length: len(call)})
+		copied = end
+	}
+	if len(rw.replaced) > 0 {
+		sb.WriteString(query[copied:])
+		rw.rewritten = sb.String()
+	}
+	return rw
+}
+
+// originalIndex returns the index in the original query of the index |i| in the rewritten one. An index inside a
+// replaced expression is mapped to the end of the expression.
+func (rw nextValueForRewrite) originalIndex(i int) int {
+	delta := 0
+	for _, r := range rw.replaced {
+		start := r.start + delta
+		if i <= start {
+			break
+		}
+		if i < start+r.length {
+			return r.end
+		}
+		delta += r.length - (r.end - r.start)
+	}
+	return i - delta
+}
+
+// remainder returns the statements of the original query which remain after parsing the rewritten one, given the
+// |remainder| of the rewritten query.
+func (rw nextValueForRewrite) remainder(remainder string) string {
+	if len(rw.replaced) == 0 || remainder == "" {
+		return remainder
+	}
+	return rw.query[rw.originalIndex(len(rw.rewritten)-len(remainder)):]
+}
+
+// containsFold returns whether |s| contains |substr|, ignoring case.
+func containsFold(s, substr string) bool {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,311 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreateDatabaseFromRemote(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+		end      int
+	}{
+		{
+			query:    "CREATE DATABASE db1 FROM REMOTE 'file:///tmp/remote'",
+			expected: "call dolt_clone('file:///tmp/remote', 'db1')",
+			end:      52,
+		},
+		{
+			query:    "  create schema db1\n from remote 'https://doltremoteapi.dolthub.com/org/db';",
+			expected: "call dolt_clone('https://doltremoteapi.dolthub.com/org/db', 'db1')",
+			end:      76,
+		},
+		{
+			query:    "CREATE DATABASE `my``db` FROM REMOTE 'org/db'; select 1",
+			expected: "call dolt_clone('org/db', 'my`db')",
+			end:      46,
+		},
+		{
+			query:    "CREATE DATABASE `it's` FROM REMOTE 'it''s'",
+			expected: "call dolt_clone('it\\'s', 'it\\'s')",
+			end:      42,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, end, ok := parseFirstDoltStatement(test.query, sqlparser.ParserOptions{})
+			require.True(t, ok)
+			assert.Equal(t, test.expected, sqlparser.String(stmt))
+			assert.Equal(t, test.end, end)
+		})
+	}
+
+	for _, query := range []string{
+		"CREATE DATABASE db1",
+		"CREATE DATABASE db1 FROM REMOTE",
+		"CREATE DATABASE db1 FROM REMOTE 'org/db' junk",
+		"select 'CREATE DATABASE db1 FROM REMOTE ''org/db'''",
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, _, ok := parseFirstDoltStatement(query, sqlparser.ParserOptions{})
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestParserCreateDatabaseFromRemote(t *testing.T) {
+	p := NewParser(sql.NewMysqlParser())
+	ctx := context.Background()
+
+	stmt, err := p.ParseSimple("CREATE DATABASE db1 FROM REMOTE 'org/db'")
+	require.NoError(t, err)
+	call, ok := stmt.(*sqlparser.Call)
+	require.True(t, ok)
+	assert.Equal(t, "dolt_clone", call.ProcName.Name.Lowered())
+	assert.Len(t, call.Params, 2)
+
+	query := "CREATE DATABASE db1 FROM REMOTE 'org/db'; select 1"
+	_, ri, err := p.ParseOneWithOptions(ctx, query, sqlparser.ParserOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, " select 1", query[ri:])
+
+	_, parsed, remainder, err := p.ParseWithOptions(ctx, query, ';', true, sqlparser.ParserOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE DATABASE db1 FROM REMOTE 'org/db'", parsed)
+	assert.Equal(t, " select 1", remainder)
+}
+
+func TestParseXa(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+		end      int
+	}{
+		{
+			query:    "XA START 'xid1'",
+			expected: "call dolt_xa('start', 'xid1')",
+			end:      15,
+		},
+		{
+			query:    "xa begin 'gtrid', 'bqual', 3 join;",
+			expected: "call dolt_xa('start', 'gtrid', 'bqual', 3)",
+			end:      34,
+		},
+		{
+			query:    "XA END X'0a0b' SUSPEND FOR MIGRATE",
+			expected: "call dolt_xa('end', X'0a0b')",
+			end:      34,
+		},
+		{
+			query:    "XA PREPARE \"xid1\"",
+			expected: "call dolt_xa('prepare', 'xid1')",
+			end:      17,
+		},
+		{
+			query:    "XA COMMIT 'xid1' ONE PHASE; select 1",
+			expected: "call dolt_xa('commit one phase', 'xid1')",
+			end:      27,
+		},
+		{
+			query:    "/* comment */ XA COMMIT 'xid1',0x0b",
+			expected: "call dolt_xa('commit', 'xid1', 0x0b)",
+			end:      35,
+		},
+		{
+			query:    "XA ROLLBACK 'a;b'",
+			expected: "call dolt_xa('rollback', 'a;b')",
+			end:      17,
+		},
+		{
+			query:    "XA RECOVER",
+			expected: "call dolt_xa('recover')",
+			end:      10,
+		},
+		{
+			query:    "xa recover convert xid;",
+			expected: "call dolt_xa('recover convert xid')",
+			end:      23,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, end, ok := parseFirstDoltStatement(test.query, sqlparser.ParserOptions{})
+			require.True(t, ok)
+			assert.Equal(t, test.expected, sqlparser.String(stmt))
+			assert.Equal(t, test.end, end)
+		})
+	}
+
+	for _, query := range []string{
+		"select 'XA START ''xid1'''",
+		"XA START xid1",
+		"XA START 'xid1' SUSPEND",
+		"XA COMMIT 'xid1' ONE",
+		"XA RECOVER CONVERT",
+		"XA 'xid1'",
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, _, ok := parseFirstDoltStatement(query, sqlparser.ParserOptions{})
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestParserXa(t *testing.T) {
+	p := NewParser(sql.NewMysqlParser())
+	ctx := context.Background()
+
+	query := "XA START 'xid1'; select 1"
+	_, ri, err := p.ParseOneWithOptions(ctx, query, sqlparser.ParserOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, " select 1", query[ri:])
+
+	stmt, parsed, remainder, err := p.ParseWithOptions(ctx, query, ';', true, sqlparser.ParserOptions{})
+	require.NoError(t, err)
+	assert.IsType(t, &sqlparser.Call{}, stmt)
+	assert.Equal(t, "XA START 'xid1'", parsed)
+	assert.Equal(t, " select 1", remainder)
+
+	_, err = p.ParseSimple("XA START 'xid1' JOIN;")
+	require.NoError(t, err)
+}
+
+func TestParseSequences(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+		end      int
+	}{
+		{
+			query:    "CREATE SEQUENCE s",
+			expected: "insert into dolt_sequences(`name`) values ('s')",
+			end:      17,
+		},
+		{
+			query:    "create sequence if not exists `it's` start with 10 increment by 5 maxvalue 100 cycle cache 20; select 1",
+			expected: "insert into dolt_sequences(`name`, start_value, increment, max_value, cycle) values ('it\\'s', 10, 5, 100, 1) on duplicate key update `name` = `name`",
+			end:      94,
+		},
+		{
+			query:    "CREATE SEQUENCE s MINVALUE = -10 NO MAXVALUE INCREMENT = 2 NOCYCLE",
+			expected: "insert into dolt_sequences(`name`, min_value, increment, cycle, start_value) values ('s', -10, 2, 0, -10)",
+			end:      66,
+		},
+		{
+			query:    "CREATE SEQUENCE s START 3 MINVALUE 1 NO CYCLE NOCACHE",
+			expected: "insert into dolt_sequences(`name`, start_value, min_value, cycle) values ('s', 3, 1, 0)",
+			end:      53,
+		},
+		{
+			query:    "DROP SEQUENCE s",
+			expected: "delete from dolt_sequences where `name` in ('s')",
+			end:      15,
+		},
+		{
+			query:    "drop sequence if exists s1, `s,2`;",
+			expected: "delete from dolt_sequences where `name` in ('s1', 's,2')",
+			end:      34,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, end, ok := parseFirstDoltStatement(test.query, sqlparser.ParserOptions{})
+			require.True(t, ok)
+			assert.Equal(t, test.expected, sqlparser.String(stmt))
+			assert.Equal(t, test.end, end)
+		})
+	}
+
+	for _, query := range []string{
+		"CREATE SEQUENCE s START WITH 'a'",
+		"CREATE SEQUENCE db.s",
+		"CREATE SEQUENCE s ENGINE = InnoDB",
+		"CREATE SEQUENCE s NO",
+		"DROP SEQUENCE s1 s2",
+		"DROP SEQUENCE",
+		"select 'CREATE SEQUENCE s'",
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, _, ok := parseFirstDoltStatement(query, sqlparser.ParserOptions{})
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestRewriteNextValueFor(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "select next value for s",
+			expected: "select nextval('s')",
+		},
+		{
+			query:    "SELECT NEXT VALUE FOR `my seq`, next /* c */ value for t2 FROM dual",
+			expected: "SELECT nextval('my seq'), nextval('t2') FROM dual",
+		},
+		{
+			query:    "insert into t values (next value for s, 'next value for s')",
+			expected: "insert into t values (nextval('s'), 'next value for s')",
+		},
+		{
+			query:    "select `next` value for s",
+			expected: "select `next` value for s",
+		},
+		{
+			query:    "select next value for db.s",
+			expected: "select next value for db.s",
+		},
+		{
+			query:    "select next, value from t",
+			expected: "select next, value from t",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.expected, rewriteNextValueFor(test.query, sqlparser.ParserOptions{}).rewritten)
+		})
+	}
+}
+
+func TestParserNextValueFor(t *testing.T) {
+	p := NewParser(sql.NewMysqlParser())
+	ctx := context.Background()
+
+	query := "select next value for s; select 1"
+	stmt, ri, err := p.ParseOneWithOptions(ctx, query, sqlparser.ParserOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "select nextval('s')", sqlparser.String(stmt))
+	assert.Equal(t, " select 1", query[ri:])
+
+	query = "select next value for s; select next value for t"
+	_, parsed, remainder, err := p.ParseWithOptions(ctx, query, ';', true, sqlparser.ParserOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "select nextval('s')", parsed)
+	assert.Equal(t, " select next value for t", remainder)
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perfschema
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// QueryProgress is the progress of a running query, beyond the per-table progress reported in sql.Process.
+type QueryProgress struct {
+	// Stage is the stage the query is in, one of the dsess.QueryStage constants.
+	Stage string
+	// StageStarted is when the query entered its current stage.
+	StageStarted time.Time
+	// RowsExamined is the number of rows the query has read from tables so far.
+	RowsExamined uint64
+}
+
+// processList is a sql.ProcessList which records the statements it runs in a StatementDigests, and tracks the stage
+// and rows examined of each running query.
+type processList struct {
+	sql.ProcessList
+	digests *StatementDigests
+
+	mu       *sync.Mutex
+	progress map[uint64]*QueryProgress
+}
+
+var _ sql.ProcessList = processList{}
+var _ dsess.QueryProgressTracker = processList{}
+
+// NewProcessList returns |pl|, recording the statements it runs in |digests|. The processes it returns report the
+// time their query has been in its current stage, rather than the time since the query began, like MySQL.
+func NewProcessList(pl sql.ProcessList, digests *StatementDigests) sql.ProcessList {
+	return processList{
+		ProcessList: pl,
+		digests:     digests,
+		mu:          &sync.Mutex{},
+		progress:    make(map[uint64]*QueryProgress),
+	}
+}
+
+// Processes implements sql.ProcessList
+func (pl processList) Processes() []sql.Process {
+	processes := pl.ProcessList.Processes()
+
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	for i := range processes {
+		if qp, ok := pl.progress[processes[i].QueryPid]; ok && processes[i].Command == sql.ProcessCommandQuery {
+			processes[i].StartedAt = qp.StageStarted
+		}
+	}
+	return processes
+}
+
+// queryProgress returns the progress of the query with the process id given, if it's running.
+func (pl processList) queryProgress(pid uint64) (QueryProgress, bool) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	qp, ok := pl.progress[pid]
+	if !ok {
+		return QueryProgress{}, false
+	}
+	return *qp, true
+}
+
+// BeginQuery implements sql.ProcessList
+func (pl processList) BeginQuery(ctx *sql.Context, query string) (*sql.Context, error) {
+	newCtx, err := pl.ProcessList.BeginQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	pl.mu.Lock()
+	pl.progress[newCtx.Pid()] = &QueryProgress{Stage: dsess.QueryStageExecuting, StageStarted: time.Now()}
+	pl.mu.Unlock()
+
+	pl.digests.begin(ctx.Session.ID(), ctx.Pid(), query, ctx.GetCurrentDatabase())
+	return newCtx, nil
+}
+
+// EndQuery implements sql.ProcessList
+func (pl processList) EndQuery(ctx *sql.Context) {
+	pl.ProcessList.EndQuery(ctx)
+
+	pl.mu.Lock()
+	var rowsExamined uint64
+	if qp, ok := pl.progress[ctx.Pid()]; ok {
+		rowsExamined = qp.RowsExamined
+		delete(pl.progress, ctx.Pid())
+	}
+	pl.mu.Unlock()
+
+	pl.digests.end(ctx.Session.ID(), ctx.Pid(), rowsExamined)
+}
+
+// RemoveConnection implements sql.ProcessList
+func (pl processList) RemoveConnection(connID uint32) {
+	pl.ProcessList.RemoveConnection(connID)
+	pl.digests.removeConnection(connID)
+}
+
+// UpdatePartitionProgress implements sql.ProcessList. The engine reports every row read from a table partition here,
+// so it's where rows examined are counted.
+func (pl processList) UpdatePartitionProgress(pid uint64, tableName, partitionName string, delta int64) {
+	pl.ProcessList.UpdatePartitionProgress(pid, tableName, partitionName, delta)
+	if delta > 0 {
+		pl.AddRowsExamined(pid, uint64(delta))
+	}
+}
+
+// SetQueryStage implements dsess.QueryProgressTracker
+func (pl processList) SetQueryStage(pid uint64, stage string) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if qp, ok := pl.progress[pid]; ok && qp.Stage != stage {
+		qp.Stage = stage
+		qp.StageStarted = time.Now()
+	}
+}
+
+// AddRowsExamined implements dsess.QueryProgressTracker
+func (pl processList) AddRowsExamined(pid uint64, rows uint64) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if qp, ok := pl.progress[pid]; ok {
+		qp.RowsExamined += rows
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perfschema
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// testProcessList is a sql.ProcessList running a single query, for connection 1.
+type testProcessList struct {
+	sql.ProcessList
+	started time.Time
+}
+
+func (pl testProcessList) Processes() []sql.Process {
+	return []sql.Process{{Connection: 1, QueryPid: 7, Command: sql.ProcessCommandQuery, StartedAt: pl.started}}
+}
+
+func (pl testProcessList) BeginQuery(ctx *sql.Context, query string) (*sql.Context, error) {
+	return ctx, nil
+}
+
+func (pl testProcessList) EndQuery(ctx *sql.Context) {}
+
+func (pl testProcessList) UpdatePartitionProgress(pid uint64, tableName, partitionName string, delta int64) {
+}
+
+func TestProcessListProgress(t *testing.T) {
+	started := time.Now().Add(-time.Hour)
+	digests := NewStatementDigests()
+	pl := NewProcessList(testProcessList{started: started}, digests)
+	ctx := sql.NewContext(context.Background(), sql.WithPid(7), sql.WithSession(sql.NewBaseSession()), sql.WithProcessList(pl))
+
+	ctx, err := pl.BeginQuery(ctx, "select * from t")
+	require.NoError(t, err)
+	qp, ok := queryProgressOf(ctx, pl.Processes()[0])
+	require.True(t, ok)
+	assert.Equal(t, dsess.QueryStageExecuting, qp.Stage)
+	// a process's start is when its query entered the current stage
+	assert.True(t, pl.Processes()[0].StartedAt.After(started))
+
+	pl.UpdatePartitionProgress(7, "t", "p", 1)
+	pl.UpdatePartitionProgress(7, "t", "p", 1)
+	dsess.AddRowsExamined(ctx, 3)
+	dsess.SetQueryStage(ctx, dsess.QueryStageRowLockWait)
+	qp, ok = queryProgressOf(ctx, pl.Processes()[0])
+	require.True(t, ok)
+	assert.Equal(t, dsess.QueryStageRowLockWait, qp.Stage)
+	assert.Equal(t, uint64(5), qp.RowsExamined)
+
+	rows, err := eventsStagesCurrentRows(ctx)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "stage/sql/"+dsess.QueryStageRowLockWait, rows[0][3])
+	assert.Equal(t, uint64(5), rows[0][8])
+
+	pl.EndQuery(ctx)
+	_, ok = queryProgressOf(ctx, pl.Processes()[0])
+	assert.False(t, ok)
+	digests.StatementDone(ctx.Session.ID(), false, 0, 0, 0)
+	require.Len(t, digests.Digests(), 1)
+	assert.Equal(t, uint64(5), digests.Digests()[0].SumRowsExamined)
+}
@@ -0,0 +1,310 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perfschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// MaxStatementDigests is the number of distinct statement digests kept, like MySQL's performance_schema_digests_size.
+// Statements with digests seen after the limit is reached are counted in a single summary with a NULL digest.
+const MaxStatementDigests = 10000
+
+// StatementDigest is the summary of every statement run with the same digest in the same schema.
+type StatementDigest struct {
+	// SchemaName is the current database the statements ran in, or empty if there was none.
+	SchemaName string
+	// Digest is the hash of DigestText. It's empty for the summary of statements that didn't fit in the digest table.
+	Digest string
+	// DigestText is the normalized text of the statements, with their literal values replaced by ?.
+	DigestText string
+
+	Count           uint64
+	SumWait         time.Duration
+	MinWait         time.Duration
+	MaxWait         time.Duration
+	SumErrors       uint64
+	SumWarnings     uint64
+	SumRowsSent     uint64
+	SumRowsAffected uint64
+	SumRowsExamined uint64
+	FirstSeen       time.Time
+	LastSeen        time.Time
+	SampleText      string
+	SampleSeen      time.Time
+	SampleWait      time.Duration
+}
+
+type digestKey struct {
+	schema string
+	digest string
+}
+
+// StatementDigests summarizes the statements run by the server, grouped by digest, for the
+// events_statements_summary_by_digest table.
+//
+// A statement begins when the process list starts running it, and is summarized when it's done: when the server
+// reports the outcome with StatementDone, or otherwise when the next statement on its connection begins or the
+// connection closes. Its wait time runs until the process list ends the query, or until it's done if the query isn't
+// ended first.
+type StatementDigests struct {
+	mu       sync.Mutex
+	digests  map[digestKey]*StatementDigest
+	overflow *StatementDigest
+	running  map[uint32]*runningStatement
+}
+
+// runningStatement is a statement begun on a connection that isn't done yet.
+type runningStatement struct {
+	pid          uint64
+	query        string
+	schema       string
+	started      time.Time
+	ended        time.Time
+	rowsExamined uint64
+}
+
+// NewStatementDigests returns an empty StatementDigests.
+func NewStatementDigests() *StatementDigests {
+	return &StatementDigests{
+		digests: make(map[digestKey]*StatementDigest),
+		running: make(map[uint32]*runningStatement),
+	}
+}
+
+// begin records that the connection given started running |query| in |schema|, with the process id given.
+func (sd *StatementDigests) begin(connID uint32, pid uint64, query, schema string) {
+	sd.mu.Lock()
+	prev := sd.running[connID]
+	sd.running[connID] = &runningStatement{pid: pid, query: query, schema: schema, started: time.Now()}
+	sd.mu.Unlock()
+
+	if prev != nil {
+		sd.summarize(prev, statementOutcome{})
+	}
+}
+
+// end records that the query with the process id given finished running on the connection given, having read
+// |rowsExamined| rows.
+func (sd *StatementDigests) end(connID uint32, pid uint64, rowsExamined uint64) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	if rs, ok := sd.running[connID]; ok && rs.pid == pid && rs.ended.IsZero() {
+		rs.ended = time.Now()
+		rs.rowsExamined = rowsExamined
+	}
+}
+
+// removeConnection summarizes the statement running on the connection given, if any.
+func (sd *StatementDigests) removeConnection(connID uint32) {
+	sd.StatementDone(connID, false, 0, 0, 0)
+}
+
+// StatementDone records the outcome of the last statement begun on the connection given, and adds it to the summary
+// of its digest. It does nothing if there's no statement running on the connection.
+func (sd *StatementDigests) StatementDone(connID uint32, failed bool, rowsSent, rowsAffected, warnings uint64) {
+	sd.mu.Lock()
+	rs, ok := sd.running[connID]
+	delete(sd.running, connID)
+	sd.mu.Unlock()
+
+	if ok {
+		sd.summarize(rs, statementOutcome{failed: failed, rowsSent: rowsSent, rowsAffected: rowsAffected, warnings: warnings})
+	}
+}
+
+// statementOutcome is what a statement did, as reported to StatementDone and by the process list.
+type statementOutcome struct {
+	failed       bool
+	rowsSent     uint64
+	rowsAffected uint64
+	rowsExamined uint64
+	warnings     uint64
+}
+
+// summarize adds the statement |rs|, which is done, to the summary of its digest.
+func (sd *StatementDigests) summarize(rs *runningStatement, outcome statementOutcome) {
+	ended := rs.ended
+	if ended.IsZero() {
+		ended = time.Now()
+	}
+
+	// Normalize the query outside the lock, it's the expensive part
+	text := DigestText(rs.query)
+	sum := sha256.Sum256([]byte(text))
+	outcome.rowsExamined = rs.rowsExamined
+	sd.record(rs.schema, hex.EncodeToString(sum[:]), text, rs.query, rs.started, ended.Sub(rs.started), outcome)
+}
+
+func (sd *StatementDigests) record(schema, digest, text, query string, started time.Time, wait time.Duration, outcome statementOutcome) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	key := digestKey{schema: schema, digest: digest}
+	d, ok := sd.digests[key]
+	if !ok {
+		if len(sd.digests) < MaxStatementDigests {
+			d = &StatementDigest{SchemaName: schema, Digest: digest, DigestText: text, FirstSeen: started}
+			sd.digests[key] = d
+		} else {
+			if sd.overflow == nil {
+				sd.overflow = &StatementDigest{FirstSeen: started}
+			}
+			d = sd.overflow
+		}
+	}
+
+	d.Count++
+	d.SumWait += wait
+	if d.Count == 1 || wait < d.MinWait {
+		d.MinWait = wait
+	}
+	if wait > d.MaxWait {
+		d.MaxWait = wait
+	}
+	if outcome.failed {
+		d.SumErrors++
+	}
+	d.SumWarnings += outcome.warnings
+	d.SumRowsSent += outcome.rowsSent
+	d.SumRowsAffected += outcome.rowsAffected
+	d.SumRowsExamined += outcome.rowsExamined
+	d.LastSeen = started
+	// Like MySQL, keep the slowest statement as the sample
+	if d.Count == 1 || wait >= d.SampleWait {
+		d.SampleText = query
+		d.SampleSeen = started
+		d.SampleWait = wait
+	}
+}
+
+// Digests returns a copy of the current statement summaries, ordered by schema and digest, with the summary of
+// statements that didn't fit in the table last.
+func (sd *StatementDigests) Digests() []StatementDigest {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	ret := make([]StatementDigest, 0, len(sd.digests)+1)
+	for _, d := range sd.digests {
+		ret = append(ret, *d)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].SchemaName != ret[j].SchemaName {
+			return ret[i].SchemaName < ret[j].SchemaName
+		}
+		return ret[i].Digest < ret[j].Digest
+	})
+	if sd.overflow != nil {
+		ret = append(ret, *sd.overflow)
+	}
+	return ret
+}
+
+// DigestText returns the normalized form of |query| used to group statements in events_statements_summary_by_digest:
+// literal values are replaced by ?, lists of literal values by (...), comments are removed, identifiers are quoted,
+// keywords are upper-cased, and tokens are separated by a single space.
+func DigestText(query string) string {
+	tkn := sqlparser.NewStringTokenizer(query)
+	var tokens []string
+	prevEnd := 0
+	for {
+		typ, val := tkn.Scan()
+		if typ == 0 {
+			break
+		}
+
+		// The tokenizer has read one character past the end of the token it returned
+		end := min(max(tkn.Position-1, prevEnd), len(query))
+		text := strings.TrimSpace(query[prevEnd:end])
+		prevEnd = end
+
+		switch typ {
+		case sqlparser.LEX_ERROR:
+			tokens = append(tokens, strings.TrimSpace(query[end:]))
+			return joinDigestTokens(tokens)
+		case sqlparser.COMMENT:
+			continue
+		case sqlparser.STRING, sqlparser.INTEGRAL, sqlparser.FLOAT, sqlparser.HEXNUM, sqlparser.HEX,
+			sqlparser.BIT_LITERAL, sqlparser.VALUE_ARG, sqlparser.LIST_ARG:
+			tokens = append(tokens, "?")
+		case sqlparser.ID:
+			if strings.HasPrefix(string(val), "@") {
+				// user and system variables aren't quoted
+				tokens = append(tokens, string(val))
+			} else {
+				tokens = append(tokens, "`"+strings.ReplaceAll(string(val), "`", "``")+"`")
+			}
+		default:
+			if text == "" {
+				text = string(val)
+			}
+			tokens = append(tokens, strings.ToUpper(text))
+		}
+	}
+
+	for len(tokens) > 0 && tokens[len(tokens)-1] == ";" {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return joinDigestTokens(tokens)
+}
+
+// joinDigestTokens joins the tokens of a digest, collapsing parenthesized lists of more than one value into (...).
+func joinDigestTokens(tokens []string) string {
+	var sb strings.Builder
+	for i := 0; i < len(tokens); i++ {
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		if n := valueListLen(tokens[i:]); n > 0 {
+			sb.WriteString("(...)")
+			i += n - 1
+			continue
+		}
+		sb.WriteString(tokens[i])
+	}
+	return sb.String()
+}
+
+// valueListLen returns the number of tokens in the list of two or more values at the start of |tokens|, like
+// ( ? , ? ), or 0 if it doesn't start with one.
+func valueListLen(tokens []string) int {
+	if len(tokens) < 5 || tokens[0] != "(" {
+		return 0
+	}
+	for i := 1; i < len(tokens); i += 2 {
+		if tokens[i] != "?" || i+1 == len(tokens) {
+			return 0
+		}
+		switch tokens[i+1] {
+		case ",":
+		case ")":
+			if i == 1 {
+				return 0
+			}
+			return i + 2
+		default:
+			return 0
+		}
+	}
+	return 0
+}
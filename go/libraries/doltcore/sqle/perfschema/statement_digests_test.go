@@ -0,0 +1,82 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perfschema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestText(t *testing.T) {
+	tests := []struct {
+		query  string
+		digest string
+	}{
+		{"select 1", "SELECT ?"},
+		{"SELECT a, `b c` FROM t1 WHERE id = 'x';", "SELECT `a` , `b c` FROM `t1` WHERE `id` = ?"},
+		{"select * from t where a in (1, 2.5, 0x1F) and b >= ?", "SELECT * FROM `t` WHERE `a` IN (...) AND `b` >= ?"},
+		{"select * from t where a in (1)", "SELECT * FROM `t` WHERE `a` IN ( ? )"},
+		{"insert into t values (1, 'a')", "INSERT INTO `t` VALUES (...)"},
+		{"select /* comment */ @@autocommit,   @x", "SELECT @@autocommit , @x"},
+		{"select count(*) from t limit 10", "SELECT COUNT ( * ) FROM `t` LIMIT ?"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.digest, DigestText(test.query))
+		})
+	}
+}
+
+func TestStatementDigests(t *testing.T) {
+	sd := NewStatementDigests()
+
+	sd.begin(1, 1, "select 1", "db")
+	sd.end(1, 1, 3)
+	sd.StatementDone(1, false, 1, 0, 0)
+	sd.begin(1, 2, "select 22", "db")
+	sd.StatementDone(1, true, 0, 0, 2)
+	// statements not reported done are summarized when the next statement on the connection begins
+	sd.begin(2, 3, "select 1", "other")
+	sd.begin(2, 4, "select 'a'", "other")
+	sd.removeConnection(2)
+	sd.StatementDone(2, false, 1, 0, 0)
+
+	digests := sd.Digests()
+	require.Len(t, digests, 2)
+	assert.Equal(t, "db", digests[0].SchemaName)
+	assert.Equal(t, "SELECT ?", digests[0].DigestText)
+	assert.Equal(t, uint64(2), digests[0].Count)
+	assert.Equal(t, uint64(1), digests[0].SumErrors)
+	assert.Equal(t, uint64(2), digests[0].SumWarnings)
+	assert.Equal(t, uint64(1), digests[0].SumRowsSent)
+	assert.Equal(t, uint64(3), digests[0].SumRowsExamined)
+	assert.Equal(t, "other", digests[1].SchemaName)
+	assert.Equal(t, digests[0].Digest, digests[1].Digest)
+	assert.Equal(t, uint64(2), digests[1].Count)
+	assert.Equal(t, uint64(0), digests[1].SumRowsSent)
+
+	for i := 0; i < MaxStatementDigests; i++ {
+		sd.record("db", string(rune(i)), "", "", time.Now(), time.Millisecond, statementOutcome{})
+	}
+	digests = sd.Digests()
+	require.Len(t, digests, MaxStatementDigests+1)
+	overflow := digests[len(digests)-1]
+	assert.Empty(t, overflow.Digest)
+	assert.Equal(t, uint64(2), overflow.Count)
+}
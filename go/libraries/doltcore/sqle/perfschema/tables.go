@@ -0,0 +1,380 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perfschema
+
+import (
+	"net"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// table is a performance schema table, whose rows are computed from the state of the server when it's read.
+type table struct {
+	name   string
+	schema sql.Schema
+	rows   func(ctx *sql.Context) ([]sql.Row, error)
+}
+
+var _ sql.Table = table{}
+
+func newTable(name string, schema sql.Schema, rows func(ctx *sql.Context) ([]sql.Row, error)) sql.Table {
+	return table{name: name, schema: schema, rows: rows}
+}
+
+type partition struct{}
+
+func (p *partition) Key() []byte {
+	return []byte("FULL")
+}
+
+func (t table) Name() string {
+	return t.name
+}
+
+func (t table) String() string {
+	return t.name
+}
+
+func (t table) Schema() sql.Schema {
+	return t.schema
+}
+
+func (t table) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+func (t table) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return sql.PartitionsToPartitionIter((*partition)(nil)), nil
+}
+
+func (t table) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	rows, err := t.rows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func varchar(length int64) sql.StringType {
+	return types.MustCreateString(sqltypes.VarChar, length, sql.Collation_utf8mb4_0900_bin)
+}
+
+var yesNo = types.MustCreateEnumType([]string{"YES", "NO"}, sql.Collation_utf8mb4_0900_bin)
+
+var threadsSchema = sql.Schema{
+	{Name: "THREAD_ID", Type: types.Uint64, Source: ThreadsTableName, PrimaryKey: true},
+	{Name: "NAME", Type: varchar(128), Source: ThreadsTableName},
+	{Name: "TYPE", Type: varchar(10), Source: ThreadsTableName},
+	{Name: "PROCESSLIST_ID", Type: types.Uint64, Source: ThreadsTableName, Nullable: true},
+	{Name: "PROCESSLIST_USER", Type: varchar(32), Source: ThreadsTableName, Nullable: true},
+	{Name: "PROCESSLIST_HOST", Type: varchar(255), Source: ThreadsTableName, Nullable: true},
+	{Name: "PROCESSLIST_DB", Type: varchar(64), Source: ThreadsTableName, Nullable: true},
+	{Name: "PROCESSLIST_COMMAND", Type: varchar(16), Source: ThreadsTableName, Nullable: true},
+	{Name: "PROCESSLIST_TIME", Type: types.Int64, Source: ThreadsTableName, Nullable: true},
+	{Name: "PROCESSLIST_STATE", Type: varchar(64), Source: ThreadsTableName, Nullable: true},
+	{Name: "PROCESSLIST_INFO", Type: types.LongText, Source: ThreadsTableName, Nullable: true},
+	{Name: "PARENT_THREAD_ID", Type: types.Uint64, Source: ThreadsTableName, Nullable: true},
+	{Name: "ROLE", Type: varchar(64), Source: ThreadsTableName, Nullable: true},
+	{Name: "INSTRUMENTED", Type: yesNo, Source: ThreadsTableName},
+	{Name: "HISTORY", Type: yesNo, Source: ThreadsTableName},
+	{Name: "CONNECTION_TYPE", Type: varchar(16), Source: ThreadsTableName, Nullable: true},
+	{Name: "THREAD_OS_ID", Type: types.Uint64, Source: ThreadsTableName, Nullable: true},
+	{Name: "RESOURCE_GROUP", Type: varchar(64), Source: ThreadsTableName, Nullable: true},
+}
+
+// threadsRows returns a row for each client connection in the process list. Dolt doesn't expose its background
+// threads, so unlike MySQL there are no BACKGROUND threads, and a thread's id is its connection id. As in MySQL,
+// PROCESSLIST_TIME is the time the thread has been in its current state.
+func threadsRows(ctx *sql.Context) ([]sql.Row, error) {
+	if ctx.ProcessList == nil {
+		return nil, nil
+	}
+
+	var rows []sql.Row
+	for _, p := range ctx.ProcessList.Processes() {
+		var db, state, info interface{}
+		if p.Database != "" {
+			db = p.Database
+		}
+		if p.Command == sql.ProcessCommandQuery {
+			state = dsess.QueryStageExecuting
+			if qp, ok := queryProgressOf(ctx, p); ok {
+				state = qp.Stage
+			}
+			info = p.Query
+		}
+
+		var host, connType interface{} = p.Host, nil
+		if h, _, err := net.SplitHostPort(p.Host); err == nil {
+			host, connType = h, "TCP/IP"
+		}
+
+		rows = append(rows, sql.Row{
+			uint64(p.Connection),        // THREAD_ID
+			"thread/sql/one_connection", // NAME
+			"FOREGROUND",                // TYPE
+			uint64(p.Connection),        // PROCESSLIST_ID
+			p.User,                      // PROCESSLIST_USER
+			host,                        // PROCESSLIST_HOST
+			db,                          // PROCESSLIST_DB
+			string(p.Command),           // PROCESSLIST_COMMAND
+			int64(p.Seconds()),          // PROCESSLIST_TIME
+			state,                       // PROCESSLIST_STATE
+			info,                        // PROCESSLIST_INFO
+			nil,                         // PARENT_THREAD_ID
+			nil,                         // ROLE
+			"YES",                       // INSTRUMENTED
+			"YES",                       // HISTORY
+			connType,                    // CONNECTION_TYPE
+			nil,                         // THREAD_OS_ID
+			nil,                         // RESOURCE_GROUP
+		})
+	}
+	return rows, nil
+}
+
+// queryProgressOf returns the progress of the query |p| is running, if it's running one in a process list returned by
+// NewProcessList.
+func queryProgressOf(ctx *sql.Context, p sql.Process) (QueryProgress, bool) {
+	pl, ok := ctx.ProcessList.(processList)
+	if !ok || p.Command != sql.ProcessCommandQuery {
+		return QueryProgress{}, false
+	}
+	return pl.queryProgress(p.QueryPid)
+}
+
+var processlistSchema = sql.Schema{
+	{Name: "ID", Type: types.Uint64, Source: ProcesslistTableName, PrimaryKey: true},
+	{Name: "USER", Type: varchar(32), Source: ProcesslistTableName, Nullable: true},
+	{Name: "HOST", Type: varchar(261), Source: ProcesslistTableName, Nullable: true},
+	{Name: "DB", Type: varchar(64), Source: ProcesslistTableName, Nullable: true},
+	{Name: "COMMAND", Type: varchar(16), Source: ProcesslistTableName, Nullable: true},
+	{Name: "TIME", Type: types.Int64, Source: ProcesslistTableName, Nullable: true},
+	{Name: "STATE", Type: varchar(64), Source: ProcesslistTableName, Nullable: true},
+	{Name: "INFO", Type: types.LongText, Source: ProcesslistTableName, Nullable: true},
+	{Name: "EXECUTION_ENGINE", Type: types.MustCreateEnumType([]string{"PRIMARY", "SECONDARY"}, sql.Collation_utf8mb4_0900_bin), Source: ProcesslistTableName, Nullable: true},
+}
+
+// processlistRows returns a row for each client connection in the process list, like SHOW PROCESSLIST, but with the
+// stage of each running query as its STATE.
+func processlistRows(ctx *sql.Context) ([]sql.Row, error) {
+	if ctx.ProcessList == nil {
+		return nil, nil
+	}
+
+	var rows []sql.Row
+	for _, p := range ctx.ProcessList.Processes() {
+		var db, state, info interface{}
+		if p.Database != "" {
+			db = p.Database
+		}
+		if p.Command == sql.ProcessCommandQuery {
+			state = dsess.QueryStageExecuting
+			if qp, ok := queryProgressOf(ctx, p); ok {
+				state = qp.Stage
+			}
+			info = p.Query
+		}
+
+		rows = append(rows, sql.Row{
+			uint64(p.Connection), // ID
+			p.User,               // USER
+			p.Host,               // HOST
+			db,                   // DB
+			string(p.Command),    // COMMAND
+			int64(p.Seconds()),   // TIME
+			state,                // STATE
+			info,                 // INFO
+			"PRIMARY",            // EXECUTION_ENGINE
+		})
+	}
+	return rows, nil
+}
+
+var eventsStagesCurrentSchema = sql.Schema{
+	{Name: "THREAD_ID", Type: types.Uint64, Source: EventsStagesCurrentTableName, PrimaryKey: true},
+	{Name: "EVENT_ID", Type: types.Uint64, Source: EventsStagesCurrentTableName, PrimaryKey: true},
+	{Name: "END_EVENT_ID", Type: types.Uint64, Source: EventsStagesCurrentTableName, Nullable: true},
+	{Name: "EVENT_NAME", Type: varchar(128), Source: EventsStagesCurrentTableName},
+	{Name: "SOURCE", Type: varchar(64), Source: EventsStagesCurrentTableName, Nullable: true},
+	{Name: "TIMER_START", Type: types.Uint64, Source: EventsStagesCurrentTableName, Nullable: true},
+	{Name: "TIMER_END", Type: types.Uint64, Source: EventsStagesCurrentTableName, Nullable: true},
+	{Name: "TIMER_WAIT", Type: types.Uint64, Source: EventsStagesCurrentTableName, Nullable: true},
+	{Name: "WORK_COMPLETED", Type: types.Uint64, Source: EventsStagesCurrentTableName, Nullable: true},
+	{Name: "WORK_ESTIMATED", Type: types.Uint64, Source: EventsStagesCurrentTableName, Nullable: true},
+	{Name: "NESTING_EVENT_ID", Type: types.Uint64, Source: EventsStagesCurrentTableName, Nullable: true},
+	{Name: "NESTING_EVENT_TYPE", Type: types.MustCreateEnumType([]string{"TRANSACTION", "STATEMENT", "STAGE", "WAIT"}, sql.Collation_utf8mb4_0900_bin), Source: EventsStagesCurrentTableName, Nullable: true},
+}
+
+// eventsStagesCurrentRows returns the current stage of each running query. Its event id is the query's process id,
+// its TIMER_WAIT is the time it's been in the stage so far, and its WORK_COMPLETED is the number of rows the query has
+// examined, which is how progress is reported for long-running queries. There's no estimate of the work remaining.
+func eventsStagesCurrentRows(ctx *sql.Context) ([]sql.Row, error) {
+	if ctx.ProcessList == nil {
+		return nil, nil
+	}
+
+	var rows []sql.Row
+	for _, p := range ctx.ProcessList.Processes() {
+		qp, ok := queryProgressOf(ctx, p)
+		if !ok {
+			continue
+		}
+
+		rows = append(rows, sql.Row{
+			uint64(p.Connection),                     // THREAD_ID
+			p.QueryPid,                               // EVENT_ID
+			nil,                                      // END_EVENT_ID
+			"stage/sql/" + qp.Stage,                  // EVENT_NAME
+			nil,                                      // SOURCE
+			nil,                                      // TIMER_START
+			nil,                                      // TIMER_END
+			picoseconds(time.Since(qp.StageStarted)), // TIMER_WAIT
+			qp.RowsExamined,                          // WORK_COMPLETED
+			nil,                                      // WORK_ESTIMATED
+			nil,                                      // NESTING_EVENT_ID
+			nil,                                      // NESTING_EVENT_TYPE
+		})
+	}
+	return rows, nil
+}
+
+var statementsSummaryByDigestSchema = sql.Schema{
+	{Name: "SCHEMA_NAME", Type: varchar(64), Source: StatementsSummaryByDigestTableName, Nullable: true},
+	{Name: "DIGEST", Type: varchar(64), Source: StatementsSummaryByDigestTableName, Nullable: true},
+	{Name: "DIGEST_TEXT", Type: types.LongText, Source: StatementsSummaryByDigestTableName, Nullable: true},
+	{Name: "COUNT_STAR", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_TIMER_WAIT", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "MIN_TIMER_WAIT", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "AVG_TIMER_WAIT", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "MAX_TIMER_WAIT", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_LOCK_TIME", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_ERRORS", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_WARNINGS", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_ROWS_AFFECTED", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_ROWS_SENT", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_ROWS_EXAMINED", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_CREATED_TMP_DISK_TABLES", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_CREATED_TMP_TABLES", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_SELECT_FULL_JOIN", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_SELECT_FULL_RANGE_JOIN", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_SELECT_RANGE", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_SELECT_RANGE_CHECK", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_SELECT_SCAN", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_SORT_MERGE_PASSES", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_SORT_RANGE", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_SORT_ROWS", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_SORT_SCAN", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_NO_INDEX_USED", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_NO_GOOD_INDEX_USED", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "SUM_CPU_TIME", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "MAX_CONTROLLED_MEMORY", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "MAX_TOTAL_MEMORY", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "COUNT_SECONDARY", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "FIRST_SEEN", Type: types.TimestampMaxPrecision, Source: StatementsSummaryByDigestTableName},
+	{Name: "LAST_SEEN", Type: types.TimestampMaxPrecision, Source: StatementsSummaryByDigestTableName},
+	{Name: "QUANTILE_95", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "QUANTILE_99", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "QUANTILE_999", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+	{Name: "QUERY_SAMPLE_TEXT", Type: types.LongText, Source: StatementsSummaryByDigestTableName, Nullable: true},
+	{Name: "QUERY_SAMPLE_SEEN", Type: types.TimestampMaxPrecision, Source: StatementsSummaryByDigestTableName},
+	{Name: "QUERY_SAMPLE_TIMER_WAIT", Type: types.Uint64, Source: StatementsSummaryByDigestTableName},
+}
+
+// statementsSummaryByDigestRows returns a row for each statement digest recorded. Timer columns are in picoseconds,
+// like MySQL's. Only the statement counts, wait times, errors, warnings and rows sent, affected and examined are
+// measured; the other counters are always 0.
+func (db database) statementsSummaryByDigestRows(ctx *sql.Context) ([]sql.Row, error) {
+	if db.digests == nil {
+		return nil, nil
+	}
+
+	digests := db.digests.Digests()
+	rows := make([]sql.Row, len(digests))
+	for i, d := range digests {
+		var schemaName, digest, digestText, sampleText interface{}
+		if d.SchemaName != "" {
+			schemaName = d.SchemaName
+		}
+		if d.Digest != "" {
+			digest, digestText, sampleText = d.Digest, d.DigestText, d.SampleText
+		}
+
+		rows[i] = sql.Row{
+			schemaName,             // SCHEMA_NAME
+			digest,                 // DIGEST
+			digestText,             // DIGEST_TEXT
+			d.Count,                // COUNT_STAR
+			picoseconds(d.SumWait), // SUM_TIMER_WAIT
+			picoseconds(d.MinWait), // MIN_TIMER_WAIT
+			picoseconds(d.SumWait / time.Duration(d.Count)), // AVG_TIMER_WAIT
+			picoseconds(d.MaxWait),                          // MAX_TIMER_WAIT
+			uint64(0),                                       // SUM_LOCK_TIME
+			d.SumErrors,                                     // SUM_ERRORS
+			d.SumWarnings,                                   // SUM_WARNINGS
+			d.SumRowsAffected,                               // SUM_ROWS_AFFECTED
+			d.SumRowsSent,                                   // SUM_ROWS_SENT
+			d.SumRowsExamined,                               // SUM_ROWS_EXAMINED
+			uint64(0),                                       // SUM_CREATED_TMP_DISK_TABLES
+			uint64(0),                                       // SUM_CREATED_TMP_TABLES
+			uint64(0),                                       // SUM_SELECT_FULL_JOIN
+			uint64(0),                                       // SUM_SELECT_FULL_RANGE_JOIN
+			uint64(0),                                       // SUM_SELECT_RANGE
+			uint64(0),                                       // SUM_SELECT_RANGE_CHECK
+			uint64(0),                                       // SUM_SELECT_SCAN
+			uint64(0),                                       // SUM_SORT_MERGE_PASSES
+			uint64(0),                                       // SUM_SORT_RANGE
+			uint64(0),                                       // SUM_SORT_ROWS
+			uint64(0),                                       // SUM_SORT_SCAN
+			uint64(0),                                       // SUM_NO_INDEX_USED
+			uint64(0),                                       // SUM_NO_GOOD_INDEX_USED
+			uint64(0),                                       // SUM_CPU_TIME
+			uint64(0),                                       // MAX_CONTROLLED_MEMORY
+			uint64(0),                                       // MAX_TOTAL_MEMORY
+			uint64(0),                                       // COUNT_SECONDARY
+			d.FirstSeen,                                     // FIRST_SEEN
+			d.LastSeen,                                      // LAST_SEEN
+			uint64(0),                                       // QUANTILE_95
+			uint64(0),                                       // QUANTILE_99
+			uint64(0),                                       // QUANTILE_999
+			sampleText,                                      // QUERY_SAMPLE_TEXT
+			d.SampleSeen,                                    // QUERY_SAMPLE_SEEN
+			picoseconds(d.SampleWait),                       // QUERY_SAMPLE_TIMER_WAIT
+		}
+	}
+	return rows, nil
+}
+
+func picoseconds(d time.Duration) uint64 {
+	return uint64(d.Nanoseconds()) * 1000
+}
+
+var sessionConnectAttrsSchema = sql.Schema{
+	{Name: "PROCESSLIST_ID", Type: types.Uint64, Source: SessionConnectAttrsTableName},
+	{Name: "ATTR_NAME", Type: varchar(32), Source: SessionConnectAttrsTableName},
+	{Name: "ATTR_VALUE", Type: varchar(1024), Source: SessionConnectAttrsTableName, Nullable: true},
+	{Name: "ORDINAL_POSITION", Type: types.Int32, Source: SessionConnectAttrsTableName, Nullable: true},
+}
+
+// sessionConnectAttrsRows returns the connection attributes sent by each session's client. The server's protocol layer
+// doesn't keep the attributes clients send during the handshake, so there are never any.
+func sessionConnectAttrsRows(ctx *sql.Context) ([]sql.Row, error) {
+	return nil, nil
+}
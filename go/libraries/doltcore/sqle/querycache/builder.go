@@ -0,0 +1,391 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycache
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/rowexec"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// cacheableQueryRegex matches the start of the statements whose results may be cached. Only queries are cached, and
+// not ones which take locks.
+var cacheableQueryRegex = regexp.MustCompile(`(?is)^\s*(?:select|with|table|\()`)
+var lockingQueryRegex = regexp.MustCompile(`(?is)\bfor\s+(?:update|share)\b|\block\s+in\s+share\s+mode\b`)
+
+// uncacheableFunctions are the functions whose results may differ between runs of the same query over the same data.
+var uncacheableFunctions = map[string]struct{}{
+	"benchmark":         {},
+	"connection_id":     {},
+	"curdate":           {},
+	"current_date":      {},
+	"current_role":      {},
+	"current_time":      {},
+	"current_timestamp": {},
+	"current_user":      {},
+	"curtime":           {},
+	"found_rows":        {},
+	"get_lock":          {},
+	"is_free_lock":      {},
+	"is_used_lock":      {},
+	"last_insert_id":    {},
+	"load_file":         {},
+	"localtime":         {},
+	"localtimestamp":    {},
+	"now":               {},
+	"rand":              {},
+	"random_bytes":      {},
+	"release_all_locks": {},
+	"release_lock":      {},
+	"row_count":         {},
+	"session_user":      {},
+	"sleep":             {},
+	"sysdate":           {},
+	"system_user":       {},
+	"unix_timestamp":    {},
+	"user":              {},
+	"utc_date":          {},
+	"utc_time":          {},
+	"utc_timestamp":     {},
+	"uuid":              {},
+	"uuid_short":        {},
+}
+
+// keySessionVariables are the session variables which may change the results of a query.
+var keySessionVariables = []string{
+	"sql_mode",
+	"time_zone",
+	"collation_connection",
+	"character_set_results",
+	"div_precision_increment",
+	"sql_select_limit",
+}
+
+// Builder is a sql.NodeExecBuilder which returns cached results for queries whose results are in its Cache, and
+// caches the results of the others, when they're cacheable. The nodes it doesn't cache are built with the exec
+// builder override the engine would otherwise use.
+//
+// A query's results are cacheable if it only reads Dolt tables, which can name the root value their data comes from,
+// and doesn't depend on anything else which can change between runs, like the time, user variables or locks.
+type Builder struct {
+	// base builds the nodes whose results this builder caches
+	base     sql.NodeExecBuilder
+	override sql.NodeExecBuilder
+	cache    *Cache
+
+	mu sync.Mutex
+	// roots is the root node of each cacheable query whose QueryProcess is being built, by the session and process
+	// running it. The QueryProcess builds its child before returning, so a root is only kept while it's being built.
+	roots map[queryKey]uintptr
+}
+
+// queryKey identifies the process running a query
+type queryKey struct {
+	session uint32
+	pid     uint64
+}
+
+var _ sql.NodeExecBuilder = (*Builder)(nil)
+
+// NewBuilder returns a Builder which caches results in |cache|, and uses |override| like rowexec.NewOverrideBuilder
+// otherwise.
+func NewBuilder(override sql.NodeExecBuilder, cache *Cache) *Builder {
+	return &Builder{
+		base:     rowexec.NewOverrideBuilder(override),
+		override: override,
+		cache:    cache,
+		roots:    make(map[queryKey]uintptr),
+	}
+}
+
+// Build implements sql.NodeExecBuilder
+func (b *Builder) Build(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	if !b.cache.Enabled() {
+		return b.buildOverride(ctx, n, r)
+	}
+
+	// Every query is run by a QueryProcess, whose child is built next. Its iterator ends the query in the process
+	// list, and a transaction committing node below it commits the query's transaction, so both must run even when
+	// the results are cached.
+	if qp, ok := n.(*plan.QueryProcess); ok {
+		root := qp.Child()
+		if tc, ok := root.(*plan.TransactionCommittingNode); ok {
+			root = tc.Child()
+		}
+		if !isCacheable(ctx, root) {
+			return b.buildOverride(ctx, n, r)
+		}
+		unmark := b.markRoot(ctx, root)
+		defer unmark()
+		return b.buildOverride(ctx, n, r)
+	}
+
+	if !b.takeRoot(ctx, n) {
+		return b.buildOverride(ctx, n, r)
+	}
+
+	key, ok, err := cacheKey(ctx, n)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return b.base.Build(ctx, n, r)
+	}
+
+	if rows, ok := b.cache.Get(key); ok {
+		ret := make([]sql.Row, len(rows))
+		for i := range rows {
+			ret[i] = rows[i].Copy()
+		}
+		return sql.RowsToRowIter(ret...), nil
+	}
+
+	iter, err := b.base.Build(ctx, n, r)
+	if err != nil {
+		return nil, err
+	}
+	return &cachingIter{iter: iter, cache: b.cache, key: key}, nil
+}
+
+func (b *Builder) buildOverride(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	if b.override == nil {
+		return nil, nil
+	}
+	return b.override.Build(ctx, n, r)
+}
+
+// markRoot marks |n| as the root of the cacheable query being run by |ctx|, and returns a function which unmarks it.
+func (b *Builder) markRoot(ctx *sql.Context, n sql.Node) func() {
+	ptr, ok := nodeKey(n)
+	if !ok {
+		return func() {}
+	}
+
+	key := queryKey{session: ctx.Session.ID(), pid: ctx.Pid()}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	prev, hadPrev := b.roots[key]
+	b.roots[key] = ptr
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if hadPrev {
+			b.roots[key] = prev
+		} else {
+			delete(b.roots, key)
+		}
+	}
+}
+
+// takeRoot returns whether |n| is the root of the cacheable query being run by |ctx|, which is now being built.
+func (b *Builder) takeRoot(ctx *sql.Context, n sql.Node) bool {
+	ptr, ok := nodeKey(n)
+	if !ok {
+		return false
+	}
+
+	key := queryKey{session: ctx.Session.ID(), pid: ctx.Pid()}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if root, ok := b.roots[key]; !ok || root != ptr {
+		return false
+	}
+	delete(b.roots, key)
+	return true
+}
+
+// nodeKey returns a key identifying the node |n|, if it's a pointer.
+func nodeKey(n sql.Node) (uintptr, bool) {
+	v := reflect.ValueOf(n)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return 0, false
+	}
+	return v.Pointer(), true
+}
+
+// isCacheable returns whether the results of the query being run by |ctx|, whose plan is |n|, can be cached.
+func isCacheable(ctx *sql.Context, n sql.Node) bool {
+	query := ctx.Query()
+	if !cacheableQueryRegex.MatchString(query) || lockingQueryRegex.MatchString(query) {
+		return false
+	}
+	return isDeterministic(n)
+}
+
+// isDeterministic returns whether the plan |n| only reads Dolt tables, and returns the same results every time it's
+// run over the same data.
+func isDeterministic(n sql.Node) bool {
+	deterministic := true
+	transform.Inspect(n, func(n sql.Node) bool {
+		switch n := n.(type) {
+		case *plan.Into:
+			deterministic = false
+		case *plan.ResolvedTable:
+			deterministic = deterministic && isDoltTable(n.UnderlyingTable())
+		case *plan.IndexedTableAccess:
+			deterministic = deterministic && isDoltTable(n.UnderlyingTable())
+		case sql.Table:
+			// table functions, and any other tables whose data doesn't come from a root value
+			deterministic = false
+		}
+
+		if e, ok := n.(sql.Expressioner); ok {
+			for _, expr := range e.Expressions() {
+				deterministic = deterministic && isDeterministicExpr(expr)
+			}
+		}
+		return deterministic
+	})
+	return deterministic
+}
+
+func isDeterministicExpr(e sql.Expression) bool {
+	deterministic := true
+	transform.InspectExpr(e, func(e sql.Expression) bool {
+		switch e := e.(type) {
+		case *expression.UserVar, *expression.SystemVar, *expression.ProcedureParam:
+			deterministic = false
+		case *plan.Subquery:
+			deterministic = deterministic && isDeterministic(e.Query)
+		case sql.NonDeterministicExpression:
+			deterministic = deterministic && !e.IsNonDeterministic()
+		}
+		if f, ok := e.(sql.FunctionExpression); ok {
+			name := strings.ToLower(f.FunctionName())
+			if _, ok := uncacheableFunctions[name]; ok || strings.HasPrefix(name, "dolt_") {
+				deterministic = false
+			}
+		}
+		return !deterministic
+	})
+	return deterministic
+}
+
+// dataCacheKeyer is a table whose data comes from a root value, like a sqle.DoltTable.
+type dataCacheKeyer interface {
+	DataCacheKey(ctx *sql.Context) (doltdb.DataCacheKey, bool, error)
+}
+
+func isDoltTable(t sql.Table) bool {
+	_, ok := t.(dataCacheKeyer)
+	return ok
+}
+
+// cacheKey returns the key the results of the cacheable query being run by |ctx|, whose plan is |n|, are cached
+// with: the query and its plan, which includes the values of any bind variables, the hashes of the root values of the
+// tables it reads, the current database, and the session variables which affect its results.
+func cacheKey(ctx *sql.Context, n sql.Node) (string, bool, error) {
+	var sb strings.Builder
+	sb.WriteString(ctx.Query())
+	sb.WriteByte(0)
+	sb.WriteString(sql.DebugString(n))
+	sb.WriteByte(0)
+	sb.WriteString(ctx.GetCurrentDatabase())
+
+	for _, t := range readTables(n) {
+		dt, ok := t.(dataCacheKeyer)
+		if !ok {
+			return "", false, nil
+		}
+		key, ok, err := dt.DataCacheKey(ctx)
+		if err != nil || !ok {
+			return "", false, err
+		}
+		sb.WriteByte(0)
+		sb.WriteString(key.String())
+	}
+
+	for _, name := range keySessionVariables {
+		val, err := ctx.GetSessionVariable(ctx, name)
+		if err != nil {
+			return "", false, err
+		}
+		fmt.Fprintf(&sb, "\x00%s=%v", name, val)
+	}
+	return sb.String(), true, nil
+}
+
+// readTables returns the tables read by the plan |n|, including by its subqueries.
+func readTables(n sql.Node) []sql.Table {
+	var tables []sql.Table
+	transform.Inspect(n, func(n sql.Node) bool {
+		switch n := n.(type) {
+		case *plan.ResolvedTable:
+			tables = append(tables, n.UnderlyingTable())
+		case *plan.IndexedTableAccess:
+			tables = append(tables, n.UnderlyingTable())
+		}
+
+		if e, ok := n.(sql.Expressioner); ok {
+			for _, expr := range e.Expressions() {
+				transform.InspectExpr(expr, func(e sql.Expression) bool {
+					if sq, ok := e.(*plan.Subquery); ok {
+						tables = append(tables, readTables(sq.Query)...)
+					}
+					return false
+				})
+			}
+		}
+		return true
+	})
+	return tables
+}
+
+// cachingIter is a row iterator which caches the rows of the query result it returns, once it's returned all of
+// them.
+type cachingIter struct {
+	iter  sql.RowIter
+	cache *Cache
+	key   string
+	rows  []sql.Row
+	// done is whether every row has been returned
+	done bool
+}
+
+var _ sql.RowIter = (*cachingIter)(nil)
+
+func (it *cachingIter) Next(ctx *sql.Context) (sql.Row, error) {
+	row, err := it.iter.Next(ctx)
+	if err == io.EOF {
+		it.done = true
+		return nil, err
+	} else if err != nil {
+		it.rows = nil
+		return nil, err
+	}
+
+	if len(it.rows) <= MaxResultRows {
+		it.rows = append(it.rows, row.Copy())
+	}
+	return row, nil
+}
+
+func (it *cachingIter) Close(ctx *sql.Context) error {
+	err := it.iter.Close(ctx)
+	if err == nil && it.done && len(it.rows) <= MaxResultRows {
+		it.cache.Add(it.key, it.rows)
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycache
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/memstats"
+)
+
+// MaxResultRows is the largest result, in rows, which is cached.
+const MaxResultRows = 10_000
+
+// Cache is a server-wide cache of query results. Results are keyed by the query, the root values of the tables it
+// reads and the session state which affects its results, so a cached result is only returned while the data it was
+// computed from is unchanged, and results computed from older roots age out of the cache.
+//
+// The cache holds at most dolt_query_cache_size results, and is disabled when it's 0.
+type Cache struct {
+	mu      sync.Mutex
+	results *lru.Cache[string, []sql.Row]
+	size    int
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// Enabled returns whether query results are being cached.
+func (c *Cache) Enabled() bool {
+	return c != nil && configuredSize() > 0
+}
+
+// Get returns the result cached for |key|, if there is one.
+func (c *Cache) Get(key string) ([]sql.Row, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.resize() {
+		return nil, false
+	}
+	return c.results.Get(key)
+}
+
+// Add caches |rows| as the result for |key|, unless it's too large to cache.
+func (c *Cache) Add(key string, rows []sql.Row) {
+	if len(rows) > MaxResultRows {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.resize() {
+		c.results.Add(key, rows)
+	}
+}
+
+// Len returns the number of results cached.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.resize() {
+		return 0
+	}
+	return c.results.Len()
+}
+
+// Purge drops every cached result.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.results != nil {
+		c.results.Purge()
+	}
+}
+
+// MemorySource returns the memstats.Source reporting the results held by the cache.
+func (c *Cache) MemorySource() memstats.Source {
+	return memstats.Source{
+		Name: "query_cache",
+		Usage: func(*sql.Context) []memstats.Usage {
+			return []memstats.Usage{{
+				Component: "results",
+				Bytes:     memstats.Unknown,
+				Capacity:  memstats.Unknown,
+				Entries:   int64(c.Len()),
+			}}
+		},
+		Flush: func(*sql.Context) { c.Purge() },
+	}
+}
+
+// resize matches the capacity of the cache to dolt_query_cache_size, dropping every result when it's 0. Returns
+// whether the cache is enabled. Callers must hold |c.mu|.
+func (c *Cache) resize() bool {
+	size := configuredSize()
+	if size == c.size {
+		return size > 0
+	}
+
+	c.size = size
+	if size == 0 {
+		c.results = nil
+		return false
+	}
+	if c.results == nil {
+		// only errors for a non-positive size
+		c.results, _ = lru.New[string, []sql.Row](size)
+	} else {
+		c.results.Resize(size)
+	}
+	return true
+}
+
+func configuredSize() int {
+	_, val, ok := sql.SystemVariables.GetGlobal(dsess.DoltQueryCacheSize)
+	if !ok {
+		return 0
+	}
+	size, _, err := types.Int64.Convert(val)
+	if err != nil {
+		return 0
+	}
+	return int(size.(int64))
+}
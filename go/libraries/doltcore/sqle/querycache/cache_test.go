@@ -0,0 +1,93 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+func setCacheSize(t *testing.T, size int64) {
+	require.NoError(t, sql.SystemVariables.SetGlobal(dsess.DoltQueryCacheSize, size))
+}
+
+func TestCache(t *testing.T) {
+	defer setCacheSize(t, 0)
+
+	c := NewCache()
+	setCacheSize(t, 0)
+	assert.False(t, c.Enabled())
+	c.Add("a", []sql.Row{{1}})
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	setCacheSize(t, 2)
+	assert.True(t, c.Enabled())
+	c.Add("a", []sql.Row{{1}})
+	c.Add("b", []sql.Row{{2}})
+	c.Add("c", []sql.Row{{3}})
+	assert.Equal(t, 2, c.Len())
+	_, ok = c.Get("a")
+	assert.False(t, ok, "least recently used result is evicted")
+	rows, ok := c.Get("c")
+	require.True(t, ok)
+	assert.Equal(t, []sql.Row{{3}}, rows)
+
+	c.Add("big", make([]sql.Row, MaxResultRows+1))
+	_, ok = c.Get("big")
+	assert.False(t, ok, "results larger than MaxResultRows aren't cached")
+
+	setCacheSize(t, 1)
+	_, _ = c.Get("c")
+	assert.Equal(t, 1, c.Len())
+
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+
+	setCacheSize(t, 0)
+	assert.False(t, c.Enabled())
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestBuilderRoots(t *testing.T) {
+	b := NewBuilder(nil, NewCache())
+	root := plan.NewProject(nil, nil)
+	other := plan.NewProject(nil, nil)
+	ctx1 := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()), sql.WithPid(1))
+	ctx2 := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()), sql.WithPid(1))
+
+	// a plan shared by queries of different sessions is only the root of the query marking it
+	unmark := b.markRoot(ctx1, root)
+	assert.False(t, b.takeRoot(ctx2, root))
+	assert.False(t, b.takeRoot(ctx1, other))
+	assert.True(t, b.takeRoot(ctx1, root))
+	assert.False(t, b.takeRoot(ctx1, root))
+	unmark()
+	assert.Empty(t, b.roots)
+
+	// roots which are never built aren't kept
+	unmark = b.markRoot(ctx1, root)
+	unmark()
+	assert.Empty(t, b.roots)
+	assert.False(t, b.takeRoot(ctx1, root))
+}
@@ -18,14 +18,13 @@ import (
 	"fmt"
 	"slices"
 	"strings"
-	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
-	"github.com/dolthub/dolt/go/store/hash"
 )
 
 type ReflogTableFunction struct {
@@ -92,88 +91,46 @@ func (rltf *ReflogTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.Row
 	}
 
 	ddb := sqlDb.DbData().Ddb
-	journal := ddb.ChunkJournal()
-	if journal == nil {
-		return sql.RowsToRowIter(), nil
-	}
-
-	previousCommitsByRef := make(map[string]string)
 	rows := make([]sql.Row, 0)
-	err := journal.IterateRoots(func(root string, timestamp *time.Time) error {
-		hashof := hash.Parse(root)
-		datasets, err := ddb.DatasetsByRootHash(ctx, hashof)
-		if err != nil {
-			return fmt.Errorf("unable to look up references for root hash %s: %s",
-				hashof.String(), err.Error())
-		}
-
-		return datasets.IterAll(ctx, func(id string, addr hash.Hash) error {
-			// Skip working set references (WorkingSetRefs can't always be resolved to commits)
-			if ref.IsWorkingSet(id) {
-				return nil
-			}
-
-			doltRef, err := ref.Parse(id)
-			if err != nil {
-				return err
-			}
+	err := ddb.IterateReflog(ctx, func(entry doltdb.ReflogEntry) error {
+		id := entry.Ref.String()
 
-			// Skip any internal refs
-			if doltRef.GetType() == ref.InternalRefType {
+		// skip workspace refs by default
+		if entry.Ref.GetType() == ref.WorkspaceRefType {
+			if !showAll {
 				return nil
 			}
-			// skip workspace refs by default
-			if doltRef.GetType() == ref.WorkspaceRefType {
-				if !showAll {
-					return nil
-				}
-			}
+		}
 
-			// If a ref expression to filter on was specified, see if we match the current ref
-			if refName != "" {
-				// If the caller has supplied a branch or tag name, without the fully qualified ref path,
-				// take the first match and use that as the canonical ref to filter on
-				if strings.HasSuffix(strings.ToLower(id), "/"+strings.ToLower(refName)) {
-					refName = id
-				}
-
-				// Skip refs that don't match the target we're looking for
-				if strings.ToLower(id) != strings.ToLower(refName) {
-					return nil
-				}
+		// If a ref expression to filter on was specified, see if we match the current ref
+		if refName != "" {
+			// If the caller has supplied a branch or tag name, without the fully qualified ref path,
+			// take the first match and use that as the canonical ref to filter on
+			if strings.HasSuffix(strings.ToLower(id), "/"+strings.ToLower(refName)) {
+				refName = id
 			}
 
-			// Skip ref entries where the commit didn't change from the previous ref entry
-			if prev, ok := previousCommitsByRef[id]; ok && prev == addr.String() {
+			// Skip refs that don't match the target we're looking for
+			if strings.ToLower(id) != strings.ToLower(refName) {
 				return nil
 			}
+		}
 
-			commit, err := ddb.ResolveCommitRefAtRoot(ctx, doltRef, hashof)
-			if err != nil {
-				return err
-			}
-			commitMeta, err := commit.GetCommitMeta(ctx)
-			if err != nil {
-				return err
-			}
-
-			// TODO: We should be able to pass in a nil *time.Time, but it
-			// currently triggers a problem in GMS' Time conversion logic.
-			// Passing a nil any value works correctly though.
-			var ts any = nil
-			if timestamp != nil {
-				ts = *timestamp
-			}
+		// TODO: We should be able to pass in a nil *time.Time, but it
+		// currently triggers a problem in GMS' Time conversion logic.
+		// Passing a nil any value works correctly though.
+		var ts any = nil
+		if entry.Timestamp != nil {
+			ts = *entry.Timestamp
+		}
 
-			rows = append(rows, sql.Row{
-				id,                     // ref
-				ts,                     // ref_timestamp
-				addr.String(),          // commit_hash
-				commitMeta.Description, // commit_message
-			})
-			previousCommitsByRef[id] = addr.String()
-			return nil
+		rows = append(rows, sql.Row{
+			id,                        // ref
+			ts,                        // ref_timestamp
+			entry.CommitHash.String(), // commit_hash
+			entry.CommitMessage,       // commit_message
 		})
+		return nil
 	})
 
 	if err != nil {
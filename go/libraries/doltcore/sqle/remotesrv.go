@@ -16,14 +16,18 @@ package sqle
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 type remotesrvStore struct {
@@ -102,3 +106,93 @@ func WithUserPasswordAuth(args remotesrv.ServerArgs, authnz remotesrv.AccessCont
 	args.Options = append(args.Options, si.Options()...)
 	return args
 }
+
+// BranchProtectionPreReceiveHook returns a remotesrv.PreReceiveHook which enforces the dolt_branch_protection rules of
+// the databases accessible through |ctxFactory|. A push may only update a protected branch if the pushing user is
+// allowed to merge into it and the update is a fast-forward. Protected branches cannot be deleted by a push. The rules
+// for a branch are read from its head before the push.
+func BranchProtectionPreReceiveHook(ctxFactory func(context.Context) (*sql.Context, error)) remotesrv.PreReceiveHook {
+	return func(ctx context.Context, event remotesrv.ReceiveEvent) error {
+		var ddb *doltdb.DoltDB
+		for _, update := range event.Refs {
+			if update.Old == "" || !strings.HasPrefix(update.Ref, "refs/heads/") {
+				continue
+			}
+			if ddb == nil {
+				var err error
+				ddb, err = remotesrvDoltDB(ctx, ctxFactory, event.Database)
+				if err != nil {
+					return err
+				}
+			}
+			if err := checkPushBranchProtection(ctx, ddb, event.User, update); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func remotesrvDoltDB(ctx context.Context, ctxFactory func(context.Context) (*sql.Context, error), database string) (*doltdb.DoltDB, error) {
+	sqlCtx, err := ctxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	db, err := dsess.DSessFromSess(sqlCtx.Session).Provider().Database(sqlCtx, database)
+	if err != nil {
+		return nil, err
+	}
+	sdb, ok := db.(dsess.SqlDatabase)
+	if !ok {
+		return nil, remotesrv.ErrUnimplemented
+	}
+	return sdb.DbData().Ddb, nil
+}
+
+func checkPushBranchProtection(ctx context.Context, ddb *doltdb.DoltDB, user string, update remotesrv.RefUpdate) error {
+	branch, err := ref.Parse(update.Ref)
+	if err != nil {
+		return err
+	}
+	oldHead, err := readPushedCommit(ctx, ddb, update.Old)
+	if err != nil {
+		return err
+	}
+	root, err := oldHead.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	protections, err := doltdb.GetBranchProtections(ctx, root)
+	if err != nil || len(protections) == 0 {
+		return err
+	}
+
+	isFastForward := false
+	if update.New != "" {
+		newHead, err := readPushedCommit(ctx, ddb, update.New)
+		if err != nil {
+			return err
+		}
+		isFastForward, err = oldHead.CanFastForwardTo(ctx, newHead)
+		if err != nil && err != doltdb.ErrUpToDate && err != doltdb.ErrIsAhead {
+			return err
+		}
+	}
+	return protections.CheckBranchProtection(branch.GetPath(), user, isFastForward)
+}
+
+func readPushedCommit(ctx context.Context, ddb *doltdb.DoltDB, addr string) (*doltdb.Commit, error) {
+	h, ok := hash.MaybeParse(addr)
+	if !ok {
+		return nil, fmt.Errorf("invalid commit hash %s", addr)
+	}
+	optCmt, err := ddb.ReadCommit(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	cm, ok := optCmt.ToCommit()
+	if !ok {
+		return nil, doltdb.ErrGhostCommitEncountered
+	}
+	return cm, nil
+}
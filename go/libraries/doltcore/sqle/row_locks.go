@@ -0,0 +1,59 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+// lockRowsForRead takes the row locks needed by a locking read of this table, such as SELECT ... FOR UPDATE, before
+// any of its rows are read. |scan| returns the rows the read will return, read from |all|, a copy of this table which
+// returns every column.
+func (t *DoltTable) lockRowsForRead(ctx *sql.Context, scan func(ctx *sql.Context, all *DoltTable) (sql.RowIter, error)) error {
+	if t.lockedToRoot != nil {
+		return nil
+	}
+	mode := dsess.LockingReadMode(ctx)
+	if mode == dsess.RowLockNone {
+		return nil
+	}
+
+	locker, err := dsess.NewRowLocker(ctx, t.db.RevisionQualifiedName(), t.tableName, t.sch)
+	if err != nil {
+		return err
+	}
+
+	all := *t
+	all.projectedCols = t.sch.GetAllCols().Tags
+	all.projectedSchema = nil
+	all.overriddenSchema = nil
+	return locker.LockRowsForRead(ctx, mode, func(ctx *sql.Context) (sql.RowIter, error) {
+		return scan(ctx, &all)
+	})
+}
+
+// lockIndexedRowsForRead takes the row locks needed by a locking read of |t| using the index lookup given.
+func lockIndexedRowsForRead(ctx *sql.Context, t *DoltTable, idx index.DoltIndex, lookup sql.IndexLookup, isDoltFormat bool) error {
+	return t.lockRowsForRead(ctx, func(ctx *sql.Context, all *DoltTable) (sql.RowIter, error) {
+		partitions, err := index.NewRangePartitionIter(ctx, all, lookup, isDoltFormat)
+		if err != nil {
+			return nil, err
+		}
+		return sql.NewTableRowIter(ctx, NewIndexedDoltTable(all, idx), partitions), nil
+	})
+}
@@ -807,7 +807,7 @@ func TestRenameTableStatements(t *testing.T) {
 
 func TestAlterSystemTables(t *testing.T) {
 	systemTableNames := []string{"dolt_log", "dolt_history_people", "dolt_diff_people", "dolt_commit_diff_people", "dolt_schemas"}
-	reservedTableNames := []string{"dolt_query_catalog", "dolt_docs", "dolt_procedures", "dolt_ignore"}
+	reservedTableNames := []string{"dolt_query_catalog", "dolt_docs", "dolt_procedures", "dolt_ignore", "dolt_tests", "dolt_branch_protection", "dolt_sequences", "dolt_assertions", "dolt_external_tables"}
 
 	var dEnv *env.DoltEnv
 	var err error
@@ -822,7 +822,12 @@ func TestAlterSystemTables(t *testing.T) {
 		ExecuteSetupSQL(context.Background(), `
     CREATE VIEW name as select 2+2 from dual;
 		CREATE PROCEDURE simple_proc2() SELECT 1+1;
-		INSERT INTO dolt_ignore VALUES ('test', 1);`)(t, dEnv)
+		INSERT INTO dolt_ignore VALUES ('test', 1);
+		INSERT INTO dolt_tests VALUES ('test', NULL, 'select 1', 'expected_rows', '==', '1');
+		INSERT INTO dolt_branch_protection VALUES ('release%', 'root');
+		INSERT INTO dolt_sequences (name) VALUES ('seq');
+		INSERT INTO dolt_assertions VALUES ('test', 'people', 'select 1 from dual where false');
+		INSERT INTO dolt_external_tables VALUES ('ext', 'mysql://root@localhost/db', 't', 0);`)(t, dEnv)
 	}
 
 	t.Run("Create", func(t *testing.T) {
@@ -0,0 +1,131 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlfmt
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// GenerateSchemaMigration returns the DDL statements which transform the table schemas of |fromRoot| into those of
+// |toRoot|, in an order which can be executed against a database with the schemas of |fromRoot|. System tables are not
+// included.
+//
+// Foreign keys are what constrain the order: a table can't be dropped while another table references it, and a
+// foreign key can't be added before its parent table and columns exist. So the statements are ordered as:
+//  1. foreign keys which are removed or changed, and those of dropped tables, are dropped
+//  2. dropped tables are dropped
+//  3. the remaining changes to existing tables, including renames, are made
+//  4. new tables are created without their foreign keys
+//  5. foreign keys which are added or changed, including those of new tables, are added
+//
+// Adding every foreign key at the end also handles tables which reference each other.
+func GenerateSchemaMigration(ctx context.Context, fromRoot, toRoot doltdb.RootValue) ([]string, error) {
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return nil, err
+	}
+	toSchemas, err := doltdb.GetAllSchemas(ctx, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var userDeltas []diff.TableDelta
+	for _, td := range deltas {
+		if doltdb.HasDoltPrefix(td.CurName()) || doltdb.HasDoltPrefix(td.FromName.Name) {
+			continue
+		}
+		userDeltas = append(userDeltas, td)
+	}
+	sort.Slice(userDeltas, func(i, j int) bool {
+		return userDeltas[i].CurName() < userDeltas[j].CurName()
+	})
+
+	var dropFks, dropTables, alters, creates, addFks []string
+	for _, td := range userDeltas {
+		switch {
+		case td.IsDrop():
+			for _, fk := range td.FromFks {
+				// self-referential foreign keys are dropped along with their table
+				if !strings.EqualFold(fk.ReferencedTableName, fk.TableName) {
+					dropFks = append(dropFks, AlterTableDropForeignKeyStmt(fk.TableName, fk.Name))
+				}
+			}
+			dropTables = append(dropTables, DropTableStmt(td.FromName.Name))
+
+		case td.IsAdd():
+			stmt, err := GenerateCreateTableStatement(td.ToName.Name, td.ToSch, nil, nil)
+			if err != nil {
+				return nil, err
+			}
+			creates = append(creates, stmt)
+			for _, fk := range td.ToFks {
+				addFks = append(addFks, alterTableAddForeignKeyDefinitionStmt(fk, td.ToSch, toSchemas[fk.ReferencedTableName]))
+			}
+
+		default:
+			for _, fkDiff := range diff.DiffForeignKeys(td.FromFks, td.ToFks) {
+				if fkDiff.DiffType == diff.SchDiffRemoved || fkDiff.DiffType == diff.SchDiffModified {
+					dropFks = append(dropFks, AlterTableDropForeignKeyStmt(fkDiff.From.TableName, fkDiff.From.Name))
+				}
+				if fkDiff.DiffType == diff.SchDiffAdded || fkDiff.DiffType == diff.SchDiffModified {
+					addFks = append(addFks, alterTableAddForeignKeyDefinitionStmt(fkDiff.To, td.ToSch, toSchemas[fkDiff.To.ReferencedTableName]))
+				}
+			}
+
+			if td.IsRename() {
+				alters = append(alters, RenameTableStmt(td.FromName.Name, td.ToName.Name))
+			}
+			// the table is renamed and its foreign keys are changed above, so the remaining statements all refer to
+			// the table by its new name and ignore foreign keys
+			renamed := td
+			renamed.FromName = td.ToName
+			renamed.FromFks, renamed.ToFks = nil, nil
+			renamed.FromFksParentSch, renamed.ToFksParentSch = nil, nil
+			fromSch, toSch, err := td.GetSchemas(ctx)
+			if err != nil {
+				return nil, err
+			}
+			stmts, err := generateNonCreateNonDropTableSqlSchemaDiff(renamed, toSchemas, fromSch, toSch)
+			if err != nil {
+				return nil, err
+			}
+			alters = append(alters, stmts...)
+		}
+	}
+
+	var stmts []string
+	for _, group := range [][]string{dropFks, dropTables, alters, creates, addFks} {
+		stmts = append(stmts, group...)
+	}
+	return stmts, nil
+}
+
+// alterTableAddForeignKeyDefinitionStmt returns an ALTER TABLE statement adding |fk| to its table, including its
+// referential actions.
+func alterTableAddForeignKeyDefinitionStmt(fk doltdb.ForeignKey, sch, parentSch schema.Schema) string {
+	var b strings.Builder
+	b.WriteString("ALTER TABLE ")
+	b.WriteString(QuoteIdentifier(fk.TableName))
+	b.WriteString(" ADD ")
+	b.WriteString(strings.TrimSpace(GenerateCreateTableForeignKeyDefinition(fk, sch, parentSch)))
+	b.WriteRune(';')
+	return b.String()
+}
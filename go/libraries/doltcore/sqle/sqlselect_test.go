@@ -784,6 +784,8 @@ func BasicSelectTests() []SelectTest {
 					"Initialize data repository",
 					"",
 					"",
+					nil,
+					nil,
 				},
 			},
 			ExpectedSqlSchema: sql.Schema{
@@ -795,6 +797,8 @@ func BasicSelectTests() []SelectTest {
 				&sql.Column{Name: "latest_commit_message", Type: gmstypes.Text},
 				&sql.Column{Name: "remote", Type: gmstypes.Text},
 				&sql.Column{Name: "branch", Type: gmstypes.Text},
+				&sql.Column{Name: "ahead", Type: gmstypes.Uint64},
+				&sql.Column{Name: "behind", Type: gmstypes.Uint64},
 			},
 		},
 	}
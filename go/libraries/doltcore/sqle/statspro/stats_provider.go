@@ -281,49 +281,53 @@ func (p *Provider) UpdateStatus(db string, msg string) {
 }
 
 func (p *Provider) RowCount(ctx *sql.Context, db string, table sql.Table) (uint64, error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	if priStats, ok, err := p.primaryStats(ctx, db, table); err != nil {
+		return 0, err
+	} else if ok {
+		return priStats.RowCount(), nil
+	}
 
-	statDb, ok := p.getStatDb(db)
-	if !ok {
-		return 0, sql.ErrDatabaseNotFound.New(db)
+	// Without statistics the optimizer would cost every join as if this table
+	// were empty. A table's row count is cheap to read from its row data, so
+	// fall back to that.
+	if st, ok := table.(sql.StatisticsTable); ok {
+		cnt, _, err := st.RowCount(ctx)
+		return cnt, err
 	}
+	return 0, nil
+}
 
-	dSess := dsess.DSessFromSess(ctx.Session)
-	branch, err := dSess.GetBranch()
-	if err != nil {
+func (p *Provider) DataLength(ctx *sql.Context, db string, table sql.Table) (uint64, error) {
+	if priStats, ok, err := p.primaryStats(ctx, db, table); err != nil {
 		return 0, err
+	} else if ok {
+		return priStats.AvgSize(), nil
 	}
 
-	// TODO: schema name
-	priStats, ok := statDb.GetStat(branch, sql.NewStatQualifier(db, table.Name(), "primary"))
-	if !ok {
-		return 0, nil
+	if st, ok := table.(sql.StatisticsTable); ok {
+		return st.DataLength(ctx)
 	}
-
-	return priStats.RowCount(), nil
+	return 0, nil
 }
 
-func (p *Provider) DataLength(ctx *sql.Context, db string, table sql.Table) (uint64, error) {
+// primaryStats returns the collected statistics for the primary index of
+// |table| on the session's current branch, if there are any.
+func (p *Provider) primaryStats(ctx *sql.Context, db string, table sql.Table) (*DoltStats, bool, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	statDb, ok := p.getStatDb(db)
 	if !ok {
-		return 0, sql.ErrDatabaseNotFound.New(db)
+		return nil, false, nil
 	}
 
 	dSess := dsess.DSessFromSess(ctx.Session)
 	branch, err := dSess.GetBranch()
 	if err != nil {
-		return 0, err
+		return nil, false, err
 	}
 
 	// TODO: schema name
 	priStats, ok := statDb.GetStat(branch, sql.NewStatQualifier(db, table.Name(), "primary"))
-	if !ok {
-		return 0, nil
-	}
-
-	return priStats.AvgSize(), nil
+	return priStats, ok, nil
 }
@@ -0,0 +1,184 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const storageScrubberThread = "storage_scrubber"
+
+// storageScrubCheckInterval is how often the storage scrubber checks whether it's time to scrub again, so that changes
+// to dolt_storage_scrub_interval take effect without restarting the server.
+const storageScrubCheckInterval = time.Second
+
+// storageHealthTracker records the result of the most recent scrub of each database in a DoltDatabaseProvider, so
+// that it can be reported by the dolt_storage_health system table.
+type storageHealthTracker struct {
+	mu     sync.Mutex
+	health map[string]dsess.StorageHealth
+}
+
+func newStorageHealthTracker() *storageHealthTracker {
+	return &storageHealthTracker{health: make(map[string]dsess.StorageHealth)}
+}
+
+func (t *storageHealthTracker) record(h dsess.StorageHealth) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.health[strings.ToLower(h.Database)] = h
+}
+
+// StorageHealth implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) StorageHealth() []dsess.StorageHealth {
+	// DoltDatabases is in order of name, and leaves out databases dropped since they were scrubbed
+	dbs := p.DoltDatabases()
+
+	p.storageHealth.mu.Lock()
+	defer p.storageHealth.mu.Unlock()
+	var ret []dsess.StorageHealth
+	for _, db := range dbs {
+		if h, ok := p.storageHealth.health[strings.ToLower(db.Name())]; ok {
+			ret = append(ret, h)
+		}
+	}
+	return ret
+}
+
+// StartStorageScrubber starts a background thread which scrubs the storage of every database in this provider once
+// every dolt_storage_scrub_interval seconds, and not at all while it's 0. Every chunk in the table files of each
+// database is read and verified against its address, and table files with corrupt chunks are repaired with intact
+// copies of them from the database's remotes and backups where possible. The results are reported by the
+// dolt_storage_health system table.
+func (p *DoltDatabaseProvider) StartStorageScrubber(ctxFactory func(context.Context) (*sql.Context, error), bThreads *sql.BackgroundThreads) error {
+	return bThreads.Add(storageScrubberThread, func(ctx context.Context) {
+		ticker := time.NewTicker(storageScrubCheckInterval)
+		defer ticker.Stop()
+
+		var lastScrub time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			interval := storageScrubInterval()
+			if interval == 0 || time.Since(lastScrub) < interval {
+				continue
+			}
+
+			sqlCtx, err := ctxFactory(ctx)
+			if err != nil {
+				continue
+			}
+			p.ScrubStorage(sqlCtx)
+			lastScrub = time.Now()
+		}
+	})
+}
+
+// storageScrubInterval returns the current value of dolt_storage_scrub_interval.
+func storageScrubInterval() time.Duration {
+	_, val, ok := sql.SystemVariables.GetGlobal(dsess.DoltStorageScrubInterval)
+	if !ok {
+		return 0
+	}
+	secs, _, err := types.Int64.Convert(val)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(secs.(int64)) * time.Second
+}
+
+// ScrubStorage scrubs the storage of every database in this provider that's stored locally, recording the results
+// for the dolt_storage_health system table.
+func (p *DoltDatabaseProvider) ScrubStorage(ctx *sql.Context) {
+	for _, db := range p.DoltDatabases() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		dbData := db.DbData()
+		report, err := dbData.Ddb.Scrub(ctx, func(context.Context) ([]*doltdb.DoltDB, error) {
+			return p.scrubSources(ctx, db.Name(), dbData)
+		})
+		if errors.Is(err, doltdb.ErrScrubNotSupported) {
+			continue
+		}
+
+		health := dsess.StorageHealth{
+			Database:      db.Name(),
+			ChunksChecked: int64(report.ChunksChecked),
+			Problems:      report.Problems,
+			CheckedAt:     time.Now(),
+		}
+		if err != nil {
+			health.Error = err.Error()
+			ctx.GetLogger().Warnf("unable to scrub storage of database %s: %s", db.Name(), err.Error())
+		}
+		for _, problem := range report.Problems {
+			if problem.Repaired {
+				ctx.GetLogger().Warnf("repaired corrupt table file %s in database %s: %s", problem.TableFile.String(), db.Name(), problem.Msg)
+			} else {
+				ctx.GetLogger().Errorf("corrupt table file %s in database %s: %s", problem.TableFile.String(), db.Name(), problem.Msg)
+			}
+		}
+		p.storageHealth.record(health)
+	}
+}
+
+// scrubSources returns the remotes and backups of a database, to fetch intact copies of its corrupt chunks from.
+// Remotes which can't be loaded are left out.
+func (p *DoltDatabaseProvider) scrubSources(ctx *sql.Context, dbName string, dbData env.DbData) ([]*doltdb.DoltDB, error) {
+	remotes, err := dbData.Rsr.GetRemotes()
+	if err != nil {
+		return nil, err
+	}
+	backups, err := dbData.Rsr.GetBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	var srcs []*doltdb.DoltDB
+	for _, rs := range []map[string]env.Remote{remotes.Snapshot(), backups.Snapshot()} {
+		names := make([]string, 0, len(rs))
+		for name := range rs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			srcDB, err := p.GetRemoteDB(ctx, dbData.Ddb.Format(), rs[name], true)
+			if err != nil {
+				ctx.GetLogger().Warnf("unable to load remote %s of database %s to repair corrupt chunks: %s", name, dbName, err.Error())
+				continue
+			}
+			srcs = append(srcs, srcDB)
+		}
+	}
+	return srcs, nil
+}
@@ -21,7 +21,9 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/types"
 	_ "github.com/dolthub/go-mysql-server/sql/variables"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cte"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
 )
 
 // TODO: get rid of me, use an integration point to define new sysvars
@@ -31,6 +33,42 @@ func init() {
 
 func AddDoltSystemVariables() {
 	sql.SystemVariables.AddSystemVariables([]sql.SystemVariable{
+		// These replace the MySQL system variables of the same names, to reject the isolation levels Dolt doesn't support
+		&sql.MysqlSystemVariable{
+			Name:              "transaction_isolation",
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemEnumType("transaction_isolation", dsess.SupportedIsolationLevels...),
+			Default:           string(dsess.IsolationLevelRepeatableRead),
+		},
+		&sql.MysqlSystemVariable{
+			Name:              "tx_isolation",
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemEnumType("tx_isolation", dsess.SupportedIsolationLevels...),
+			Default:           string(dsess.IsolationLevelRepeatableRead),
+		},
+		// This replaces the MySQL system variable, which Dolt didn't implement, now that sessions wait for row locks
+		&sql.MysqlSystemVariable{
+			Name:              dsess.InnodbLockWaitTimeout,
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(dsess.InnodbLockWaitTimeout, 1, 1073741824, false),
+			Default:           int64(50),
+		},
+		// This replaces the MySQL system variable, which Dolt didn't implement, now that recursive common table
+		// expressions are limited to it
+		&sql.MysqlSystemVariable{
+			Name:              cte.MaxRecursionDepthVariable,
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Dynamic:           true,
+			SetVarHintApplies: true,
+			Type:              types.NewSystemIntType(cte.MaxRecursionDepthVariable, 0, 4294967295, false),
+			Default:           int64(1000),
+		},
 		&sql.MysqlSystemVariable{
 			Name:              "log_bin_branch",
 			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Persist),
@@ -159,6 +197,14 @@ func AddDoltSystemVariables() {
 			Type:              types.NewSystemBoolType(dsess.AllowCommitConflicts),
 			Default:           int8(0),
 		},
+		&sql.MysqlSystemVariable{ // If true, foreign keys are only checked when the transaction is committed.
+			Name:              dsess.DeferForeignKeyChecks,
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Session),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.DeferForeignKeyChecks),
+			Default:           int8(0),
+		},
 		&sql.MysqlSystemVariable{
 			Name:              dsess.AwsCredsFile,
 			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Session),
@@ -254,6 +300,95 @@ func AddDoltSystemVariables() {
 			Type:    types.NewSystemStringType(dsess.DoltStatsBranches),
 			Default: "",
 		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltGCRetentionDays,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemIntType(dsess.DoltGCRetentionDays, 0, math.MaxInt, false),
+			Default: int64(0),
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltDroppedDatabaseRetentionDays,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemIntType(dsess.DoltDroppedDatabaseRetentionDays, 0, math.MaxInt, false),
+			Default: int64(0),
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltAutoIncrementBlockSize,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemIntType(dsess.DoltAutoIncrementBlockSize, 0, math.MaxInt, false),
+			Default: int64(0),
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltQueryCacheSize,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemIntType(dsess.DoltQueryCacheSize, 0, math.MaxInt32, false),
+			Default: int64(0),
+		},
+		&sql.MysqlSystemVariable{
+			Name:              dsess.DoltParallelScanWorkers,
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Dynamic:           true,
+			SetVarHintApplies: true,
+			Type:              types.NewSystemIntType(dsess.DoltParallelScanWorkers, 1, 256, false),
+			Default:           int64(1),
+		},
+		&sql.MysqlSystemVariable{
+			Name:              dsess.DoltSortBufferSize,
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Dynamic:           true,
+			SetVarHintApplies: true,
+			Type:              types.NewSystemIntType(dsess.DoltSortBufferSize, 0, math.MaxInt64, false),
+			Default:           int64(0),
+		},
+		&sql.MysqlSystemVariable{
+			Name:              dsess.DoltJoinBufferSize,
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Dynamic:           true,
+			SetVarHintApplies: true,
+			Type:              types.NewSystemIntType(dsess.DoltJoinBufferSize, 0, math.MaxInt64, false),
+			Default:           int64(0),
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltQueryMemoryLimit,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Dynamic: true,
+			Type:    types.NewSystemIntType(dsess.DoltQueryMemoryLimit, 0, math.MaxInt64, false),
+			Default: int64(0),
+		},
+		&sql.MysqlSystemVariable{
+			Name:              dsess.DoltWindowWorkers,
+			Scope:             sql.GetMysqlScope(sql.SystemVariableScope_Both),
+			Dynamic:           true,
+			SetVarHintApplies: true,
+			Type:              types.NewSystemIntType(dsess.DoltWindowWorkers, 0, 256, false),
+			Default:           int64(0),
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltReadAheadDepth,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemIntType(dsess.DoltReadAheadDepth, 0, 64, false),
+			Default: int64(tree.DefaultReadAheadDepth),
+			NotifyChanged: func(_ sql.SystemVariableScope, v sql.SystemVarValue) error {
+				depth, _, err := types.Int64.Convert(v.Val)
+				if err != nil {
+					return err
+				}
+				tree.SetReadAheadDepth(int(depth.(int64)))
+				return nil
+			},
+		},
+		&sql.MysqlSystemVariable{
+			Name:    dsess.DoltStorageScrubInterval,
+			Dynamic: true,
+			Scope:   sql.GetMysqlScope(sql.SystemVariableScope_Global),
+			Type:    types.NewSystemIntType(dsess.DoltStorageScrubInterval, 0, math.MaxInt32, false),
+			Default: int64(0),
+		},
 	})
 }
 
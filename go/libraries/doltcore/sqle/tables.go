@@ -32,10 +32,12 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/expression"
 	"github.com/dolthub/go-mysql-server/sql/fulltext"
 	sqltypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
@@ -544,6 +546,24 @@ func (t *DoltTable) sqlSchema() sql.PrimaryKeySchema {
 
 // Partitions returns the partitions for this table.
 func (t *DoltTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	if err := t.RecordRead(ctx); err != nil {
+		return nil, err
+	}
+	err := t.lockRowsForRead(ctx, func(ctx *sql.Context, all *DoltTable) (sql.RowIter, error) {
+		partitions, err := all.partitions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return sql.NewTableRowIter(ctx, all, partitions), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.partitions(ctx)
+}
+
+func (t *DoltTable) partitions(ctx *sql.Context) (sql.PartitionIter, error) {
 	table, err := t.DoltTable(ctx)
 	if err != nil {
 		return nil, err
@@ -561,6 +581,15 @@ func (t *DoltTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
 	return newDoltTablePartitionIter(rows, partitions...), nil
 }
 
+// RecordRead records that this table was read by the current transaction, which SERIALIZABLE transactions check
+// for conflicts when they commit. Tables locked to a root, as for AS OF queries, can't change and aren't recorded.
+func (t *DoltTable) RecordRead(ctx *sql.Context) error {
+	if t.lockedToRoot != nil {
+		return nil
+	}
+	return dsess.RecordTableRead(ctx, t.db.RevisionQualifiedName(), t.tableName)
+}
+
 func (t *DoltTable) IsTemporary() bool {
 	return false
 }
@@ -1608,6 +1637,10 @@ func (t *AlterableDoltTable) AddColumn(ctx *sql.Context, column *sql.Column, ord
 		return errors.New("adding primary keys is not supported")
 	}
 
+	if requestsInstantAlter(ctx) && !col.Virtual && !addsTrailingStoredColumn(t.sch, order) {
+		return ErrInstantAlterNotSupported.New()
+	}
+
 	nullable := NotNull
 	if col.IsNullable() {
 		nullable = Null
@@ -1639,6 +1672,47 @@ func (t *AlterableDoltTable) AddColumn(ctx *sql.Context, column *sql.Column, ord
 	return t.updateFromRoot(ctx, newRoot)
 }
 
+// requestsInstantAlter returns whether the ALTER TABLE statement being executed asks for ALGORITHM=INSTANT, in which
+// case any change that has to rewrite the table's rows is an error. The grammar accepts the ALGORITHM option but
+// doesn't keep it in the statement it parses, so the option is read from the statement's tokens.
+func requestsInstantAlter(ctx *sql.Context) bool {
+	tokens := newTokenReader(ctx.Query(), sql.LoadSqlMode(ctx).ParserOptions())
+	if !tokens.word("alter") {
+		return false
+	}
+	for tokens.typ != 0 && tokens.typ != ';' && tokens.typ != sqlparser.LEX_ERROR {
+		if tokens.typ != sqlparser.ALGORITHM {
+			tokens.next()
+			continue
+		}
+		tokens.next()
+		if tokens.typ == '=' {
+			tokens.next()
+		}
+		if tokens.typ == sqlparser.INSTANT {
+			return true
+		}
+	}
+	return false
+}
+
+// addsTrailingStoredColumn returns whether adding a column to |sch| at |order| stores it after every other stored
+// column. Rows without the trailing field read it as NULL, so such a column is added without rewriting any rows.
+func addsTrailingStoredColumn(sch schema.Schema, order *sql.ColumnOrder) bool {
+	cols := sch.GetNonPKCols()
+	if order == nil {
+		return true
+	} else if order.First {
+		return cols.StoredSize() == 0
+	}
+	after, ok := cols.GetByNameCaseInsensitive(order.AfterColumn)
+	if !ok {
+		return false
+	}
+	idx, ok := cols.StoredIndexByTag(after.Tag)
+	return ok && idx == cols.StoredSize()-1
+}
+
 func (t *AlterableDoltTable) ShouldRewriteTable(
 	ctx *sql.Context,
 	oldSchema sql.PrimaryKeySchema,
@@ -1648,10 +1722,25 @@ func (t *AlterableDoltTable) ShouldRewriteTable(
 ) bool {
 	return t.isIncompatibleTypeChange(oldColumn, newColumn) ||
 		orderChanged(oldSchema, newSchema, oldColumn, newColumn) ||
-		isColumnDrop(oldSchema, newSchema) ||
+		(isColumnDrop(oldSchema, newSchema) && !t.canDropColumnInPlace(oldColumn)) ||
 		isPrimaryKeyChange(oldSchema, newSchema)
 }
 
+// canDropColumnInPlace returns whether |column| can be dropped by DropColumn, which only changes the table's schema,
+// rather than by rewriting the table. This is the case when the column isn't part of the primary key or any index,
+// since neither the keys of rows nor any secondary index depend on it. Rows keep the dropped column's values until the
+// table is next rewritten.
+func (t *AlterableDoltTable) canDropColumnInPlace(column *sql.Column) bool {
+	if column == nil || column.PrimaryKey || !types.IsFormat_DOLT(t.Format()) || schema.IsKeyless(t.sch) {
+		return false
+	}
+	col, ok := t.sch.GetAllCols().GetByNameCaseInsensitive(column.Name)
+	if !ok {
+		return false
+	}
+	return len(t.sch.Indexes().IndexesWithTag(col.Tag)) == 0
+}
+
 func orderChanged(oldSchema, newSchema sql.PrimaryKeySchema, oldColumn, newColumn *sql.Column) bool {
 	if oldColumn == nil || newColumn == nil {
 		return false
@@ -1717,6 +1806,9 @@ func (t *AlterableDoltTable) RewriteInserter(
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return nil, err
 	}
+	if requestsInstantAlter(ctx) {
+		return nil, ErrInstantAlterNotSupported.New()
+	}
 	err := validateSchemaChange(t.Name(), oldSchema, newSchema, oldColumn, newColumn, idxCols)
 	if err != nil {
 		return nil, err
@@ -2208,69 +2300,38 @@ func (t *AlterableDoltTable) adjustForeignKeysForDroppedPk(ctx *sql.Context, tbl
 	return root, nil
 }
 
-// DropColumn implements sql.AlterableTable
-func (t *AlterableDoltTable) DropColumn(*sql.Context, string) error {
-	return fmt.Errorf("not implemented: AlterableDoltTable.DropColumn()")
-}
-
-// dropColumnData drops values for the specified column from the underlying storage layer
-func (t *AlterableDoltTable) dropColumnData(ctx *sql.Context, updatedTable *doltdb.Table, sch schema.Schema, columnName string) (*doltdb.Table, error) {
-	nomsRowData, err := updatedTable.GetNomsRowData(ctx)
+// DropColumn implements sql.AlterableTable. Columns which are part of the primary key or an index are dropped by
+// |RewriteInserter| instead, see |canDropColumnInPlace|.
+func (t *AlterableDoltTable) DropColumn(ctx *sql.Context, columnName string) error {
+	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+	root, err := t.getRoot(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	column, ok := sch.GetAllCols().GetByName(columnName)
-	if !ok {
-		return nil, sql.ErrColumnNotFound.New(columnName)
+	table, _, err := root.GetTable(ctx, t.TableName())
+	if err != nil {
+		return err
 	}
 
-	mapEditor := nomsRowData.Edit()
-	defer mapEditor.Close(ctx)
-
-	err = nomsRowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
-		if t, ok := value.(types.Tuple); ok {
-			newTuple, err := types.NewTuple(nomsRowData.Format())
-			if err != nil {
-				return true, err
-			}
-
-			idx := uint64(0)
-			for idx < t.Len() {
-				tTag, err := t.Get(idx)
-				if err != nil {
-					return true, err
-				}
-
-				tValue, err := t.Get(idx + 1)
-				if err != nil {
-					return true, err
-				}
-
-				if tTag.Equals(types.Uint(column.Tag)) == false {
-					newTuple, err = newTuple.Append(tTag, tValue)
-					if err != nil {
-						return true, err
-					}
-				}
-
-				idx += 2
-			}
-			mapEditor.Set(key, newTuple)
-		}
+	updatedTable, err := dropColumnFromTable(ctx, table, columnName)
+	if err != nil {
+		return err
+	}
 
-		return false, nil
-	})
+	newRoot, err := root.PutTable(ctx, t.TableName(), updatedTable)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	newMapData, err := mapEditor.Map(ctx)
+	err = t.setRoot(ctx, newRoot)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return updatedTable.UpdateNomsRows(ctx, newMapData)
+	return t.updateFromRoot(ctx, newRoot)
 }
 
 // ModifyColumn implements sql.AlterableTable. ModifyColumn operations are only used for operations that change only
@@ -2583,6 +2644,9 @@ func (t *AlterableDoltTable) createIndex(ctx *sql.Context, idx sql.IndexDef, key
 	if err != nil {
 		return err
 	}
+	if err = t.catchUpIndex(ctx, table, ret); err != nil {
+		return err
+	}
 	root, err := t.getRoot(ctx)
 	if err != nil {
 		return err
@@ -2623,6 +2687,86 @@ func (t *AlterableDoltTable) createIndex(ctx *sql.Context, idx sql.IndexDef, key
 	return t.updateFromRoot(ctx, newRoot)
 }
 
+// onlineIndexCatchUpRounds is the number of times an index is caught up with the rows written since it was built,
+// before its transaction commits.
+const onlineIndexCatchUpRounds = 4
+
+// catchUpIndex catches up the index created by |ret| from the rows of |table| with the rows other transactions have
+// committed to the table since, like MySQL's in place index builds. The index is built from a snapshot of the table,
+// which takes as long as the table is big, without blocking writes to it. Each catch up only applies the rows written
+// since the last one, so they get shorter. When this transaction commits, it's merged with the transactions committed
+// since then, which only catches the index up with the rows written after the last catch up.
+func (t *AlterableDoltTable) catchUpIndex(ctx *sql.Context, table *doltdb.Table, ret *creation.CreateIndexReturn) error {
+	if !types.IsFormat_DOLT(table.Format()) || schema.IsKeyless(ret.Sch) || ret.NewIndex.IsFullText() {
+		return nil
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := sess.GetDoltDB(ctx, t.db.RevisionQualifiedName())
+	if !ok {
+		return nil
+	}
+	ws, err := sess.WorkingSet(ctx, t.db.RevisionQualifiedName())
+	if err != nil {
+		return err
+	}
+
+	rows, err := table.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	idx, err := ret.NewTable.GetIndexRowData(ctx, ret.NewIndex.Name())
+	if err != nil {
+		return err
+	}
+	built := durable.ProllyMapFromIndex(idx)
+	from, secondary := durable.ProllyMapFromIndex(rows), built
+
+	for i := 0; i < onlineIndexCatchUpRounds; i++ {
+		latest, err := ddb.ResolveWorkingSet(ctx, ws.Ref())
+		if errors.Is(err, doltdb.ErrWorkingSetNotFound) {
+			break
+		} else if err != nil {
+			return err
+		}
+		tbl, ok, err := latest.WorkingRoot().GetTable(ctx, t.TableName())
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
+		// the index can only be caught up with rows of the same schema
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return err
+		}
+		if !schema.ColCollsAreEqual(sch.GetAllCols(), ret.Sch.GetAllCols()) || !schema.ColCollsAreEqual(sch.GetPKCols(), ret.Sch.GetPKCols()) {
+			break
+		}
+
+		latestRows, err := tbl.GetRowData(ctx)
+		if err != nil {
+			return err
+		}
+		to := durable.ProllyMapFromIndex(latestRows)
+		if to.HashOf() == from.HashOf() {
+			break
+		}
+
+		caughtUp, ok, err := merge.CatchUpSecondaryIndex(ctx, t.tableName, ret.Sch, ret.NewIndex, secondary, from, to)
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		from, secondary = to, caughtUp
+	}
+
+	merge.CacheCaughtUpIndex(t.tableName, ret.Sch, ret.NewIndex, built, from, secondary)
+	return nil
+}
+
 // createForeignKey creates a doltdb.ForeignKey from a sql.ForeignKeyConstraint
 func (t *WritableDoltTable) createForeignKey(
 	ctx *sql.Context,
@@ -3191,8 +3335,34 @@ func (t *AlterableDoltTable) DropCheck(ctx *sql.Context, chName string) error {
 	return t.updateFromRoot(ctx, newRoot)
 }
 
+// ModifyStoredCollation implements sql.CollationAlterableTable. It converts every character string column to
+// |collation|, rebuilding the indexes ordered by those columns.
 func (t *AlterableDoltTable) ModifyStoredCollation(ctx *sql.Context, collation sql.CollationID) error {
-	return fmt.Errorf("converting the collations of columns is not yet supported")
+	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+	root, err := t.getRoot(ctx)
+	if err != nil {
+		return err
+	}
+	currentTable, _, err := root.GetTable(ctx, t.TableName())
+	if err != nil {
+		return err
+	}
+
+	newTable, err := convertTableCollation(ctx, currentTable, t.Name(), collation)
+	if err != nil {
+		return err
+	}
+	newRoot, err := root.PutTable(ctx, t.TableName(), newTable)
+	if err != nil {
+		return err
+	}
+	err = t.setRoot(ctx, newRoot)
+	if err != nil {
+		return err
+	}
+	return t.updateFromRoot(ctx, newRoot)
 }
 
 func (t *AlterableDoltTable) ModifyDefaultCollation(ctx *sql.Context, collation sql.CollationID) error {
@@ -16,6 +16,7 @@ package sqle
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -42,6 +43,7 @@ type TempTable struct {
 
 	table *doltdb.Table
 	sch   schema.Schema
+	store *tempTableStore
 
 	lookup sql.IndexLookup
 
@@ -59,7 +61,10 @@ var _ sql.CheckTable = &TempTable{}
 var _ sql.CheckAlterableTable = &TempTable{}
 var _ sql.StatisticsTable = &TempTable{}
 var _ sql.AutoIncrementTable = &TempTable{}
+var _ dsess.ReleasableTemporaryTable = &TempTable{}
 
+// NewTempTable creates a temporary table named |name| in the database |db|. The table's data is kept in storage of its
+// own, which spills to disk within |tmpDir| when the table grows large, and which is deleted by ReleaseStorage.
 func NewTempTable(
 	ctx *sql.Context,
 	ddb *doltdb.DoltDB,
@@ -67,6 +72,7 @@ func NewTempTable(
 	name, db string,
 	opts editor.Options,
 	collation sql.CollationID,
+	tmpDir string,
 ) (*TempTable, error) {
 	sess := dsess.DSessFromSess(ctx.Session)
 
@@ -104,24 +110,47 @@ func NewTempTable(
 	if err != nil {
 		return nil, err
 	}
-	vrw := ddb.ValueReadWriter()
-	ns := ddb.NodeStore()
 
-	idx, err := durable.NewEmptyIndex(ctx, vrw, ns, sch)
+	store, err := newTempTableStore(ctx, ddb.Format(), tmpDir)
 	if err != nil {
 		return nil, err
 	}
-	set, err := durable.NewIndexSet(ctx, vrw, ns)
+	tempTable, err := newTempTableInStore(ctx, ws, store, pkSch, sch, name, db, opts)
+	if err != nil {
+		return nil, errors.Join(err, store.Close())
+	}
+	return tempTable, nil
+}
+
+func newTempTableInStore(
+	ctx *sql.Context,
+	ws *doltdb.WorkingSet,
+	store *tempTableStore,
+	pkSch sql.PrimaryKeySchema,
+	sch schema.Schema,
+	name, db string,
+	opts editor.Options,
+) (*TempTable, error) {
+	idx, err := durable.NewEmptyIndex(ctx, store.vrw, store.ns, sch)
+	if err != nil {
+		return nil, err
+	}
+	set, err := durable.NewIndexSet(ctx, store.vrw, store.ns)
 	if err != nil {
 		return nil, err
 	}
 
-	tbl, err := doltdb.NewTable(ctx, vrw, ns, sch, idx, set, nil)
+	tbl, err := doltdb.NewTable(ctx, store.vrw, store.ns, sch, idx, set, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	newRoot, err := ws.WorkingRoot().PutTable(ctx, doltdb.TableName{Name: name}, tbl)
+	// the table is written to a root of its own in the temporary storage, never to the working set's root
+	root, err := doltdb.EmptyRootValue(ctx, store.vrw, store.ns)
+	if err != nil {
+		return nil, err
+	}
+	newRoot, err := root.PutTable(ctx, doltdb.TableName{Name: name}, tbl)
 	if err != nil {
 		return nil, err
 	}
@@ -141,6 +170,7 @@ func NewTempTable(
 		pkSch:     pkSch,
 		table:     tbl,
 		sch:       sch,
+		store:     store,
 		opts:      opts,
 	}
 
@@ -250,6 +280,12 @@ func (t *TempTable) IsTemporary() bool {
 	return true
 }
 
+// ReleaseStorage implements dsess.ReleasableTemporaryTable. It deletes the table's storage, after which the table
+// can't be used.
+func (t *TempTable) ReleaseStorage() error {
+	return t.store.Close()
+}
+
 // DataLength implements the sql.StatisticsTable interface.
 func (t *TempTable) DataLength(ctx *sql.Context) (uint64, error) {
 	idx, err := t.table.GetRowData(ctx)
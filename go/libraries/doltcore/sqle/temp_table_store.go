@@ -0,0 +1,64 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// tempTableMemTableSize is how much chunk data a temporary table buffers in memory before it spills to disk.
+const tempTableMemTableSize = 64 * 1024 * 1024
+
+// tempTableStore is the chunk storage of a temporary table. New chunks are kept in memory until the memory table fills
+// up, and are then written to table files in a directory of their own, so large temporary tables spill to disk. The
+// storage is separate from the database's chunk store, so temporary tables are never written into its history, and is
+// deleted when the table is dropped or its session ends.
+type tempTableStore struct {
+	dir string
+	cs  *nbs.NomsBlockStore
+	vrw types.ValueReadWriter
+	ns  tree.NodeStore
+}
+
+// newTempTableStore creates the storage for a temporary table in a new directory within |parentDir|, or within the
+// system's temporary directory if |parentDir| is empty.
+func newTempTableStore(ctx context.Context, nbf *types.NomsBinFormat, parentDir string) (*tempTableStore, error) {
+	dir, err := os.MkdirTemp(parentDir, "temp_table_")
+	if err != nil {
+		return nil, err
+	}
+	cs, err := nbs.NewLocalStore(ctx, nbf.VersionString(), dir, tempTableMemTableSize, nbs.NewUnlimitedMemQuotaProvider())
+	if err != nil {
+		return nil, errors.Join(err, os.RemoveAll(dir))
+	}
+	return &tempTableStore{
+		dir: dir,
+		cs:  cs,
+		vrw: types.NewValueStore(cs),
+		ns:  tree.NewNodeStore(cs),
+	}, nil
+}
+
+// Close closes the chunk store and deletes its files.
+func (s *tempTableStore) Close() error {
+	err := s.cs.Close()
+	return errors.Join(err, os.RemoveAll(s.dir))
+}
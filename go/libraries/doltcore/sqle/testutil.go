@@ -82,7 +82,7 @@ func ExecuteSql(dEnv *env.DoltEnv, root doltdb.RootValue, statements string) (do
 			return nil, errors.New("Show statements aren't handled")
 		case *sqlparser.Select, *sqlparser.OtherRead:
 			return nil, errors.New("Select statements aren't handled")
-		case *sqlparser.Insert:
+		case *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete:
 			var rowIter sql.RowIter
 			_, rowIter, _, execErr = engine.Query(ctx, query)
 			if execErr == nil {
@@ -19,6 +19,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
@@ -54,6 +55,7 @@ type nomsTableWriter struct {
 	flusher     dsess.WriteSessionFlusher
 
 	autoInc                globalstate.AutoIncrementTracker
+	wsRef                  ref.WorkingSetRef
 	nextAutoIncrementValue map[string]uint64
 
 	setter         dsess.SessionRootSetter
@@ -129,7 +131,7 @@ func (te *nomsTableWriter) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.R
 }
 
 func (te *nomsTableWriter) GetNextAutoIncrementValue(ctx *sql.Context, insertVal interface{}) (uint64, error) {
-	return te.autoInc.Next(te.tableName, insertVal)
+	return te.autoInc.Next(te.tableName, te.wsRef, insertVal)
 }
 
 func (te *nomsTableWriter) SetAutoIncrementValue(ctx *sql.Context, val uint64) error {
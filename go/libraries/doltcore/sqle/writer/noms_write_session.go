@@ -29,6 +29,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/store/prolly"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -50,10 +51,11 @@ var _ dsess.WriteSession = &nomsWriteSession{}
 func NewWriteSession(nbf *types.NomsBinFormat, ws *doltdb.WorkingSet, aiTracker globalstate.AutoIncrementTracker, opts editor.Options) dsess.WriteSession {
 	if types.IsFormat_DOLT(nbf) {
 		return &prollyWriteSession{
-			workingSet: ws,
-			tables:     make(map[doltdb.TableName]*prollyTableWriter),
-			aiTracker:  aiTracker,
-			mut:        &sync.RWMutex{},
+			workingSet:   ws,
+			tables:       make(map[doltdb.TableName]*prollyTableWriter),
+			aiTracker:    aiTracker,
+			mut:          &sync.RWMutex{},
+			pendingLimit: prolly.NewPendingSizeLimit(),
 		}
 	}
 
@@ -109,6 +111,7 @@ func (s *nomsWriteSession) GetTableWriter(ctx *sql.Context, table doltdb.TableNa
 		tableEditor: te,
 		flusher:     s,
 		autoInc:     s.aiTracker,
+		wsRef:       s.workingSet.Ref(),
 		setter:      setter,
 	}, nil
 }
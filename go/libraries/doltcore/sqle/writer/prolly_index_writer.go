@@ -29,7 +29,7 @@ import (
 	"github.com/dolthub/dolt/go/store/val"
 )
 
-func getPrimaryProllyWriter(ctx context.Context, t *doltdb.Table, schState *dsess.WriterState) (prollyIndexWriter, error) {
+func getPrimaryProllyWriter(ctx context.Context, t *doltdb.Table, schState *dsess.WriterState, limit *prolly.PendingSizeLimit) (prollyIndexWriter, error) {
 	idx, err := t.GetRowDataWithDescriptors(ctx, schState.PkKeyDesc, schState.PkValDesc)
 	if err != nil {
 		return prollyIndexWriter{}, err
@@ -40,7 +40,7 @@ func getPrimaryProllyWriter(ctx context.Context, t *doltdb.Table, schState *dses
 	keyDesc, valDesc := m.Descriptors()
 
 	return prollyIndexWriter{
-		mut:    m.Mutate(),
+		mut:    m.Mutate().WithPendingSizeLimit(limit),
 		keyBld: val.NewTupleBuilder(keyDesc),
 		keyMap: schState.PriIndex.KeyMapping,
 		valBld: val.NewTupleBuilder(valDesc),
@@ -48,7 +48,7 @@ func getPrimaryProllyWriter(ctx context.Context, t *doltdb.Table, schState *dses
 	}, nil
 }
 
-func getPrimaryKeylessProllyWriter(ctx context.Context, t *doltdb.Table, schState *dsess.WriterState) (prollyKeylessWriter, error) {
+func getPrimaryKeylessProllyWriter(ctx context.Context, t *doltdb.Table, schState *dsess.WriterState, limit *prolly.PendingSizeLimit) (prollyKeylessWriter, error) {
 	idx, err := t.GetRowData(ctx)
 	if err != nil {
 		return prollyKeylessWriter{}, err
@@ -59,7 +59,7 @@ func getPrimaryKeylessProllyWriter(ctx context.Context, t *doltdb.Table, schStat
 	keyDesc, valDesc := m.Descriptors()
 
 	return prollyKeylessWriter{
-		mut:    m.Mutate(),
+		mut:    m.Mutate().WithPendingSizeLimit(limit),
 		keyBld: val.NewTupleBuilder(keyDesc),
 		valBld: val.NewTupleBuilder(valDesc),
 		valMap: schState.PriIndex.ValMapping,
@@ -139,6 +139,10 @@ func (m prollyIndexWriter) Insert(ctx context.Context, sqlRow sql.Row) error {
 
 	for to := range m.valMap {
 		from := m.valMap.MapOrdinal(to)
+		if from < 0 {
+			// dropped columns are stored as NULL
+			continue
+		}
 		if err := tree.PutField(ctx, m.mut.NodeStore(), m.valBld, to, sqlRow[from]); err != nil {
 			return err
 		}
@@ -188,6 +192,10 @@ func (m prollyIndexWriter) Update(ctx context.Context, oldRow sql.Row, newRow sq
 
 	for to := range m.valMap {
 		from := m.valMap.MapOrdinal(to)
+		if from < 0 {
+			// dropped columns are stored as NULL
+			continue
+		}
 		if err = tree.PutField(ctx, m.mut.NodeStore(), m.valBld, to, newRow[from]); err != nil {
 			return err
 		}
@@ -221,7 +229,7 @@ func (m prollyIndexWriter) errForSecondaryUniqueKeyError(ctx context.Context, er
 // uniqueKeyError builds a sql.UniqueKeyError. It fetches the existing row using
 // |key| and passes it as the |existing| row.
 func (m prollyIndexWriter) uniqueKeyError(ctx context.Context, keyStr string, key val.Tuple, isPk bool) error {
-	existing := make(sql.Row, len(m.keyMap)+len(m.valMap))
+	existing := make(sql.Row, rowLength(m.keyMap, m.valMap))
 
 	_ = m.mut.Get(ctx, key, func(key, value val.Tuple) (err error) {
 		kd := m.keyBld.Desc
@@ -235,6 +243,9 @@ func (m prollyIndexWriter) uniqueKeyError(ctx context.Context, keyStr string, ke
 		vd := m.valBld.Desc
 		for from := range m.valMap {
 			to := m.valMap.MapOrdinal(from)
+			if to < 0 {
+				continue
+			}
 			if existing[to], err = tree.GetField(ctx, vd, from, value, m.mut.NodeStore()); err != nil {
 				return err
 			}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
@@ -46,19 +47,24 @@ type prollyTableWriter struct {
 
 	aiCol                  schema.Column
 	aiTracker              globalstate.AutoIncrementTracker
+	wsRef                  ref.WorkingSetRef
 	nextAutoIncrementValue map[string]uint64
 	setAutoIncrement       bool
 
 	flusher dsess.WriteSessionFlusher
 	setter  dsess.SessionRootSetter
 
+	// locker takes row locks on the rows written, once |lockerLoaded| is set
+	locker       *dsess.RowLocker
+	lockerLoaded bool
+
 	errEncountered error
 }
 
 var _ dsess.TableWriter = &prollyTableWriter{}
 var _ AutoIncrementGetter = &prollyTableWriter{}
 
-func getSecondaryProllyIndexWriters(ctx context.Context, t *doltdb.Table, schState *dsess.WriterState) (map[string]indexWriter, error) {
+func getSecondaryProllyIndexWriters(ctx context.Context, t *doltdb.Table, schState *dsess.WriterState, limit *prolly.PendingSizeLimit) (map[string]indexWriter, error) {
 	s, err := t.GetIndexSet(ctx)
 	if err != nil {
 		return nil, err
@@ -84,7 +90,7 @@ func getSecondaryProllyIndexWriters(ctx context.Context, t *doltdb.Table, schSta
 		// mapping from secondary index key to primary key
 		writers[defName] = prollySecondaryIndexWriter{
 			name:          defName,
-			mut:           idxMap.Mutate(),
+			mut:           idxMap.Mutate().WithPendingSizeLimit(limit),
 			unique:        def.IsUnique,
 			prefixLengths: def.PrefixLengths,
 			idxCols:       def.Count,
@@ -98,7 +104,7 @@ func getSecondaryProllyIndexWriters(ctx context.Context, t *doltdb.Table, schSta
 	return writers, nil
 }
 
-func getSecondaryKeylessProllyWriters(ctx context.Context, t *doltdb.Table, schState *dsess.WriterState, primary prollyKeylessWriter) (map[string]indexWriter, error) {
+func getSecondaryKeylessProllyWriters(ctx context.Context, t *doltdb.Table, schState *dsess.WriterState, primary prollyKeylessWriter, limit *prolly.PendingSizeLimit) (map[string]indexWriter, error) {
 	s, err := t.GetIndexSet(ctx)
 	if err != nil {
 		return nil, err
@@ -122,7 +128,7 @@ func getSecondaryKeylessProllyWriters(ctx context.Context, t *doltdb.Table, schS
 
 		writers[defName] = prollyKeylessSecondaryWriter{
 			name:          defName,
-			mut:           m.Mutate(),
+			mut:           m.Mutate().WithPendingSizeLimit(limit),
 			primary:       primary,
 			unique:        def.IsUnique,
 			spatial:       def.IsSpatial,
@@ -139,6 +145,9 @@ func getSecondaryKeylessProllyWriters(ctx context.Context, t *doltdb.Table, schS
 
 // Insert implements TableWriter.
 func (w *prollyTableWriter) Insert(ctx *sql.Context, sqlRow sql.Row) (err error) {
+	if err = w.lockRow(ctx, sqlRow); err != nil {
+		return err
+	}
 	if err = w.primary.ValidateKeyViolations(ctx, sqlRow); err != nil {
 		return err
 	}
@@ -164,12 +173,15 @@ func (w *prollyTableWriter) Insert(ctx *sql.Context, sqlRow sql.Row) (err error)
 	w.setAutoIncrement = true
 
 	// TODO: need schema name in ai tracker
-	w.aiTracker.Next(w.tableName.Name, sqlRow)
+	w.aiTracker.Next(w.tableName.Name, w.wsRef, sqlRow)
 	return nil
 }
 
 // Delete implements TableWriter.
 func (w *prollyTableWriter) Delete(ctx *sql.Context, sqlRow sql.Row) (err error) {
+	if err = w.lockRow(ctx, sqlRow); err != nil {
+		return err
+	}
 	for _, wr := range w.secondary {
 		if err := wr.Delete(ctx, sqlRow); err != nil {
 			return err
@@ -183,6 +195,12 @@ func (w *prollyTableWriter) Delete(ctx *sql.Context, sqlRow sql.Row) (err error)
 
 // Update implements TableWriter.
 func (w *prollyTableWriter) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.Row) (err error) {
+	if err = w.lockRow(ctx, oldRow); err != nil {
+		return err
+	}
+	if err = w.lockRow(ctx, newRow); err != nil {
+		return err
+	}
 	for _, wr := range w.secondary {
 		if err := wr.Update(ctx, oldRow, newRow); err != nil {
 			if uke, ok := err.(secondaryUniqueKeyError); ok {
@@ -199,9 +217,21 @@ func (w *prollyTableWriter) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.
 	return nil
 }
 
+// lockRow takes a lock on a row about to be written, waiting for any other transaction which holds a lock on it.
+func (w *prollyTableWriter) lockRow(ctx *sql.Context, sqlRow sql.Row) error {
+	if !w.lockerLoaded {
+		locker, err := dsess.NewRowLocker(ctx, w.dbName, w.tableName.Name, w.sch)
+		if err != nil {
+			return err
+		}
+		w.locker, w.lockerLoaded = locker, true
+	}
+	return w.locker.LockRowForWrite(ctx, sqlRow)
+}
+
 // GetNextAutoIncrementValue implements TableWriter.
 func (w *prollyTableWriter) GetNextAutoIncrementValue(ctx *sql.Context, insertVal interface{}) (uint64, error) {
-	return w.aiTracker.Next(w.tableName.Name, insertVal)
+	return w.aiTracker.Next(w.tableName.Name, w.wsRef, insertVal)
 }
 
 // SetAutoIncrementValue implements AutoIncrementSetter.
@@ -300,20 +330,20 @@ func (w *prollyTableWriter) Reset(ctx *sql.Context, sess *prollyWriteSession, tb
 
 	var newSecondaries map[string]indexWriter
 	if schema.IsKeyless(sch) {
-		newPrimary, err = getPrimaryKeylessProllyWriter(ctx, tbl, schState)
+		newPrimary, err = getPrimaryKeylessProllyWriter(ctx, tbl, schState, sess.pendingLimit)
 		if err != nil {
 			return err
 		}
-		newSecondaries, err = getSecondaryKeylessProllyWriters(ctx, tbl, schState, newPrimary.(prollyKeylessWriter))
+		newSecondaries, err = getSecondaryKeylessProllyWriters(ctx, tbl, schState, newPrimary.(prollyKeylessWriter), sess.pendingLimit)
 		if err != nil {
 			return err
 		}
 	} else {
-		newPrimary, err = getPrimaryProllyWriter(ctx, tbl, schState)
+		newPrimary, err = getPrimaryProllyWriter(ctx, tbl, schState, sess.pendingLimit)
 		if err != nil {
 			return err
 		}
-		newSecondaries, err = getSecondaryProllyIndexWriters(ctx, tbl, schState)
+		newSecondaries, err = getSecondaryProllyIndexWriters(ctx, tbl, schState, sess.pendingLimit)
 		if err != nil {
 			return err
 		}
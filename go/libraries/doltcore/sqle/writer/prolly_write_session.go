@@ -25,6 +25,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/store/prolly"
 )
 
 // prollyWriteSession handles all edit operations on a table that may also update other tables.
@@ -34,6 +35,8 @@ type prollyWriteSession struct {
 	tables     map[doltdb.TableName]*prollyTableWriter
 	aiTracker  globalstate.AutoIncrementTracker
 	mut        *sync.RWMutex
+	// pendingLimit limits the memory used by the pending writes to every table and index this session writes
+	pendingLimit *prolly.PendingSizeLimit
 }
 
 var _ dsess.WriteSession = &prollyWriteSession{}
@@ -71,20 +74,20 @@ func (s *prollyWriteSession) GetTableWriter(ctx *sql.Context, tableName doltdb.T
 	var pw indexWriter
 	var sws map[string]indexWriter
 	if schema.IsKeyless(schState.DoltSchema) {
-		pw, err = getPrimaryKeylessProllyWriter(ctx, t, schState)
+		pw, err = getPrimaryKeylessProllyWriter(ctx, t, schState, s.pendingLimit)
 		if err != nil {
 			return nil, err
 		}
-		sws, err = getSecondaryKeylessProllyWriters(ctx, t, schState, pw.(prollyKeylessWriter))
+		sws, err = getSecondaryKeylessProllyWriters(ctx, t, schState, pw.(prollyKeylessWriter), s.pendingLimit)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		pw, err = getPrimaryProllyWriter(ctx, t, schState)
+		pw, err = getPrimaryProllyWriter(ctx, t, schState, s.pendingLimit)
 		if err != nil {
 			return nil, err
 		}
-		sws, err = getSecondaryProllyIndexWriters(ctx, t, schState)
+		sws, err = getSecondaryProllyIndexWriters(ctx, t, schState, s.pendingLimit)
 		if err != nil {
 			return nil, err
 		}
@@ -100,6 +103,7 @@ func (s *prollyWriteSession) GetTableWriter(ctx *sql.Context, tableName doltdb.T
 		sqlSch:    schState.PkSchema.Schema,
 		aiCol:     schState.AutoIncCol,
 		aiTracker: s.aiTracker,
+		wsRef:     s.workingSet.Ref(),
 		flusher:   s,
 		setter:    setter,
 	}
@@ -207,6 +211,8 @@ func (s *prollyWriteSession) flush(ctx *sql.Context, autoIncSet bool, manualAuto
 // setRoot is the inner implementation for SetWorkingRoot that does not acquire any locks
 func (s *prollyWriteSession) setWorkingSet(ctx *sql.Context, ws *doltdb.WorkingSet) error {
 	root := ws.WorkingRoot()
+	// the table writers are all replaced, so the pending writes of their old maps no longer count
+	s.pendingLimit = prolly.NewPendingSizeLimit()
 	for tableName, tableWriter := range s.tables {
 		t, ok, err := root.GetTable(ctx, tableName)
 		if err != nil {
@@ -111,6 +111,11 @@ func makeOrdinalMapping(from sql.Schema, to *schema.ColCollection) (m val.Ordina
 	m = make(val.OrdinalMapping, to.StoredSize())
 	for i := range m {
 		col := to.GetByStoredIndex(i)
+		if col.Dropped {
+			// dropped columns aren't part of |from|, even if it has a column with the same name
+			m[i] = -1
+			continue
+		}
 		name := col.Name
 		colIdx := from.IndexOfColName(name)
 		m[i] = colIdx
@@ -118,6 +123,19 @@ func makeOrdinalMapping(from sql.Schema, to *schema.ColCollection) (m val.Ordina
 	return
 }
 
+// rowLength returns the length of the rows the columns mapped by |mappings| are read into. Dropped columns, which
+// aren't mapped, aren't part of rows.
+func rowLength(mappings ...val.OrdinalMapping) (n int) {
+	for _, m := range mappings {
+		for _, ord := range m {
+			if ord >= n {
+				n = ord + 1
+			}
+		}
+	}
+	return
+}
+
 // NB: only works for primary-key tables/indexes
 func makeIndexToIndexMapping(from, to *schema.ColCollection) (m val.OrdinalMapping) {
 	m = make(val.OrdinalMapping, len(to.GetColumns()))
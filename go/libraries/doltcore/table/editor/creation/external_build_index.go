@@ -39,7 +39,8 @@ const (
 
 // BuildProllyIndexExternal builds unique and non-unique indexes with a
 // single prolly tree materialization by presorting the index keys in an
-// intermediate file format.
+// intermediate file format. The sorted keys are streamed into a new tree,
+// which is built bottom-up rather than by editing an empty index.
 func BuildProllyIndexExternal(
 	ctx *sql.Context,
 	vrw types.ValueReadWriter,
@@ -110,8 +111,10 @@ func BuildProllyIndexExternal(
 	}
 	defer it.Close()
 
+	// |secondary| is empty, so the index is built bottom-up from the sorted keys
 	tupIter := &tupleIterWithCb{iter: it, prefixDesc: prefixDesc, uniqCb: uniqCb}
-	ret, err := prolly.MutateMapWithTupleIter(ctx, secondary, tupIter)
+	keyDesc, valDesc := secondary.Descriptors()
+	ret, err := prolly.NewMapFromTupleIter(ctx, secondary.NodeStore(), keyDesc, valDesc, tupIter)
 	if err != nil {
 		return nil, err
 	}
@@ -18,7 +18,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -26,7 +25,6 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
-	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/store/prolly"
 	"github.com/dolthub/dolt/go/store/prolly/tree"
@@ -213,67 +211,7 @@ func BuildUniqueProllyIndex(
 	primary prolly.Map,
 	cb DupEntryCb,
 ) (durable.Index, error) {
-	empty, err := durable.NewEmptyIndex(ctx, vrw, ns, idx.Schema())
-	if err != nil {
-		return nil, err
-	}
-	secondary := durable.ProllyMapFromIndex(empty)
-	if schema.IsKeyless(sch) {
-		secondary = prolly.ConvertToSecondaryKeylessIndex(secondary)
-	}
-
-	iter, err := primary.IterAll(ctx)
-	if err != nil {
-		return nil, err
-	}
-	p := primary.Pool()
-
-	prefixDesc := secondary.KeyDesc().PrefixDesc(idx.Count())
-	secondaryBld, err := index.NewSecondaryKeyBuilder(ctx, tableName, sch, idx, secondary.KeyDesc(), p, secondary.NodeStore())
-	if err != nil {
-		return nil, err
-	}
-
-	mut := secondary.Mutate()
-	for {
-		var k, v val.Tuple
-		k, v, err = iter.Next(ctx)
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return nil, err
-		}
-
-		idxKey, err := secondaryBld.SecondaryKeyFromRow(ctx, k, v)
-		if err != nil {
-			return nil, err
-		}
-
-		if prefixDesc.HasNulls(idxKey) {
-			continue
-		}
-
-		err = mut.GetPrefix(ctx, idxKey, prefixDesc, func(existingKey, _ val.Tuple) error {
-			// register a constraint violation if |idxKey| collides with |existingKey|
-			if existingKey != nil {
-				return cb(ctx, existingKey, idxKey)
-			}
-			return nil
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		if err = mut.Put(ctx, idxKey, val.EmptyTuple); err != nil {
-			return nil, err
-		}
-	}
-
-	secondary, err = mut.Map(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return durable.IndexFromProllyMap(secondary), nil
+	return BuildProllyIndexExternal(ctx, vrw, ns, sch, tableName, idx, primary, cb)
 }
 
 // PrefixItr iterates all keys of a given prefix |p| and its descriptor |d| in map |m|.
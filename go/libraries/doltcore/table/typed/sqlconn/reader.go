@@ -0,0 +1,307 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlconn
+
+import (
+	"context"
+	gosql "database/sql"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/planbuilder"
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+)
+
+// ConnReader implements TableReader. It runs a query over a connection to a MySQL or Postgres server, and returns the
+// rows of its result.
+type ConnReader struct {
+	// db is closed with the reader, unless it's nil because the reader's rows are read from a shared pool
+	db   *gosql.DB
+	rows *gosql.Rows
+	sch  schema.Schema
+	// bits is set for the columns whose values are read as bytes and written as bit values
+	bits []bool
+	vals []interface{}
+}
+
+var _ table.SqlTableReader = (*ConnReader)(nil)
+
+// OpenConnReader runs |query| on the server at |connURL|, a mysql:// or postgres:// URL. The schema of the reader is
+// mapped from the types of the query's columns, and its primary key is |pks|, or the first column if |pks| is empty.
+// The types in |colTypes| are used instead of the mapped types of the columns they name.
+func OpenConnReader(ctx context.Context, connURL, query string, pks []string, colTypes map[string]typeinfo.TypeInfo) (*ConnReader, error) {
+	db, err := openDB(connURL)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	colTyps, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		db.Close()
+		return nil, err
+	}
+
+	if len(pks) == 0 && len(colTyps) > 0 {
+		pks = []string{colTyps[0].Name()}
+	}
+	sch, err := schemaFromColumnTypes(colTyps, pks, colTypes)
+	if err != nil {
+		rows.Close()
+		db.Close()
+		return nil, err
+	}
+
+	return newConnReader(db, rows, sch), nil
+}
+
+func newConnReader(db *gosql.DB, rows *gosql.Rows, sch schema.Schema) *ConnReader {
+	cols := sch.GetAllCols().GetColumns()
+	bits := make([]bool, len(cols))
+	for i, col := range cols {
+		bits[i] = col.TypeInfo.GetTypeIdentifier() == typeinfo.BitTypeIdentifier
+	}
+	return &ConnReader{db: db, rows: rows, sch: sch, bits: bits, vals: make([]interface{}, len(cols))}
+}
+
+// openDB opens a connection pool for |connURL|, whose scheme names the driver it's opened with.
+func openDB(connURL string) (*gosql.DB, error) {
+	u, err := url.Parse(connURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "mysql":
+		// the MySQL driver takes a DSN rather than a URL, so the URL's parts are copied to a config
+		dsn := fmt.Sprintf("tcp(%s)/%s", u.Host, strings.TrimPrefix(u.EscapedPath(), "/"))
+		if u.RawQuery != "" {
+			dsn += "?" + u.RawQuery
+		}
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+		cfg.User = u.User.Username()
+		cfg.Passwd, _ = u.User.Password()
+		connector, err := mysql.NewConnector(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return gosql.OpenDB(connector), nil
+	case "postgres", "postgresql":
+		return gosql.Open("postgres", connURL)
+	default:
+		return nil, fmt.Errorf("unsupported connection URL scheme '%s'; expected mysql or postgres", u.Scheme)
+	}
+}
+
+// columnInfo is what's known of the type of a column of a result.
+type columnInfo struct {
+	dbType     string
+	length     int64
+	hasLength  bool
+	precision  int64
+	scale      int64
+	hasDecimal bool
+}
+
+// schemaFromColumnTypes returns the schema of a result whose columns have the types |colTyps|. The schema is keyless if
+// |pks| is empty.
+func schemaFromColumnTypes(colTyps []*gosql.ColumnType, pks []string, colTypes map[string]typeinfo.TypeInfo) (schema.Schema, error) {
+	if len(colTyps) == 0 {
+		return nil, fmt.Errorf("the query returned no columns")
+	}
+
+	isPk := make(map[string]bool, len(pks))
+	for _, pk := range pks {
+		isPk[strings.ToLower(pk)] = true
+	}
+
+	cols := make([]schema.Column, len(colTyps))
+	for i, ct := range colTyps {
+		name := ct.Name()
+		partOfPk := isPk[strings.ToLower(name)]
+		delete(isPk, strings.ToLower(name))
+
+		ti, ok := colTypes[name]
+		if !ok {
+			info := columnInfo{dbType: ct.DatabaseTypeName()}
+			info.length, info.hasLength = ct.Length()
+			info.precision, info.scale, info.hasDecimal = ct.DecimalSize()
+
+			sqlType, err := planbuilder.ParseColumnTypeString(sqlTypeString(info, partOfPk))
+			if err != nil {
+				return nil, err
+			}
+			ti, err = typeinfo.FromSqlType(sqlType)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var constraints []schema.ColConstraint
+		if partOfPk {
+			constraints = append(constraints, schema.NotNullConstraint{})
+		}
+		col, err := schema.NewColumnWithTypeInfo(name, uint64(i), ti, partOfPk, "", false, "", constraints...)
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = col
+	}
+
+	for _, pk := range pks {
+		if isPk[strings.ToLower(pk)] {
+			return nil, fmt.Errorf("primary key column %s is not a column of the query", pk)
+		}
+	}
+
+	return schema.SchemaFromCols(schema.NewColCollection(cols...))
+}
+
+// sqlTypeString returns the MySQL type of a column of a MySQL or Postgres result. Lengths that aren't reported by the
+// driver are taken to be the widest, and types which have no equivalent are mapped to text.
+func sqlTypeString(info columnInfo, partOfPk bool) string {
+	dbType := strings.ToUpper(info.dbType)
+	unsigned := ""
+	if rest, ok := strings.CutPrefix(dbType, "UNSIGNED "); ok {
+		dbType, unsigned = rest, " unsigned"
+	}
+
+	switch dbType {
+	case "BOOL", "BOOLEAN":
+		return "boolean"
+	case "TINYINT", "INT1":
+		return "tinyint" + unsigned
+	case "SMALLINT", "INT2":
+		return "smallint" + unsigned
+	case "MEDIUMINT":
+		return "mediumint" + unsigned
+	case "INT", "INTEGER", "INT4":
+		return "int" + unsigned
+	case "BIGINT", "INT8":
+		return "bigint" + unsigned
+	case "FLOAT", "FLOAT4", "REAL":
+		return "float"
+	case "DOUBLE", "FLOAT8":
+		return "double"
+	case "DECIMAL", "NUMERIC":
+		if info.hasDecimal && info.precision > 0 && info.precision <= 65 && info.scale >= 0 && info.scale <= 30 && info.scale <= info.precision {
+			return fmt.Sprintf("decimal(%d,%d)", info.precision, info.scale)
+		}
+		return "decimal(65,30)"
+	case "DATE":
+		return "date"
+	case "DATETIME", "TIMESTAMP", "TIMESTAMPTZ":
+		return "datetime(6)"
+	case "TIME", "TIMETZ":
+		return "time(6)"
+	case "YEAR":
+		return "year"
+	case "BIT":
+		return "bit(64)"
+	case "JSON", "JSONB":
+		return "json"
+	case "UUID":
+		return "char(36)"
+	case "CHAR", "BPCHAR", "VARCHAR":
+		if info.hasLength && info.length > 0 && info.length <= 16383 {
+			return "varchar(" + strconv.FormatInt(info.length, 10) + ")"
+		}
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BYTEA":
+		if partOfPk {
+			return "varbinary(16383)"
+		}
+		return "longblob"
+	}
+
+	if partOfPk {
+		// text types can't be primary keys
+		return "varchar(16383)"
+	}
+	return "longtext"
+}
+
+func (r *ConnReader) ReadRow(ctx context.Context) (row.Row, error) {
+	panic("deprecated")
+}
+
+func (r *ConnReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	dest := make([]interface{}, len(r.vals))
+	for i := range r.vals {
+		r.vals[i] = nil
+		dest[i] = &r.vals[i]
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	sqlRow := make(sql.Row, len(r.vals))
+	for i, val := range r.vals {
+		b, ok := val.([]byte)
+		switch {
+		case !ok:
+			sqlRow[i] = val
+		case r.bits[i] && len(b) <= 8:
+			// bit values are read as big-endian bytes, and imported from strings of their numeric values
+			var buf [8]byte
+			copy(buf[8-len(b):], b)
+			sqlRow[i] = strconv.FormatUint(binary.BigEndian.Uint64(buf[:]), 10)
+		default:
+			sqlRow[i] = string(b)
+		}
+	}
+	return sqlRow, nil
+}
+
+func (r *ConnReader) GetSchema() schema.Schema {
+	return r.sch
+}
+
+// Close should release resources being held
+func (r *ConnReader) Close(ctx context.Context) error {
+	err := r.rows.Close()
+	if r.db != nil {
+		if cerr := r.db.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,307 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlconn
+
+import (
+	"context"
+	gosql "database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+const (
+	// remoteSchemaTTL is how long the schema of a remote table is used before it's read from the server again
+	remoteSchemaTTL = time.Minute
+	// remoteMaxIdleConns is the number of idle connections kept open to each server
+	remoteMaxIdleConns = 2
+	// remoteConnMaxIdleTime is how long an idle connection to a server is kept open
+	remoteConnMaxIdleTime = time.Minute
+)
+
+// remoteConns holds a connection pool for each connection URL remote tables have been opened with, so that each query
+// of a remote table doesn't open a new connection, and the tables opened over it, so that each resolution of a remote
+// table doesn't read its schema from the server. The URLs are those of the connections the server is configured with,
+// so there are few of them, and their pools close the connections they aren't using.
+var remoteConns = struct {
+	mu    sync.Mutex
+	conns map[string]*remoteConn
+}{conns: make(map[string]*remoteConn)}
+
+type remoteConn struct {
+	db       *gosql.DB
+	postgres bool
+	// tables are guarded by remoteConns.mu
+	tables map[string]*RemoteTable
+}
+
+func remoteConnFor(connURL string) (*remoteConn, error) {
+	remoteConns.mu.Lock()
+	defer remoteConns.mu.Unlock()
+	if conn, ok := remoteConns.conns[connURL]; ok {
+		return conn, nil
+	}
+
+	u, err := url.Parse(connURL)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openDB(connURL)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxIdleConns(remoteMaxIdleConns)
+	db.SetConnMaxIdleTime(remoteConnMaxIdleTime)
+
+	conn := &remoteConn{db: db, postgres: strings.ToLower(u.Scheme) != "mysql", tables: make(map[string]*RemoteTable)}
+	remoteConns.conns[connURL] = conn
+	return conn, nil
+}
+
+// RemoteTable is a table on a MySQL or Postgres server, which is read and written with queries made over a pooled
+// connection to the server.
+type RemoteTable struct {
+	db       *gosql.DB
+	postgres bool
+	// name is the quoted name of the table
+	name string
+	sch  schema.Schema
+	// cols are the quoted names of the table's columns
+	cols []string
+	// keyed is true if the table has a primary key
+	keyed bool
+	// match are the indexes of the columns updated and deleted rows are matched on: the columns of the table's primary
+	// key, or if it has none, its columns other than floating point ones, whose values may not match exactly after being
+	// read and written back
+	match []int
+	// loaded is when the schema of the table was read from the server
+	loaded time.Time
+}
+
+// OpenRemoteTable returns the table |tableName|, which may be qualified by its database or schema, on the server at
+// |connURL|. The schema of the table has the table's primary key, and its column types are mapped from those of the
+// table's columns. The schema is read from the server at most once every |remoteSchemaTTL|.
+func OpenRemoteTable(ctx context.Context, connURL, tableName string) (*RemoteTable, error) {
+	conn, err := remoteConnFor(connURL)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteConns.mu.Lock()
+	rt, ok := conn.tables[tableName]
+	remoteConns.mu.Unlock()
+	if ok && time.Since(rt.loaded) < remoteSchemaTTL {
+		return rt, nil
+	}
+
+	rt, err = conn.loadTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	remoteConns.mu.Lock()
+	conn.tables[tableName] = rt
+	remoteConns.mu.Unlock()
+	return rt, nil
+}
+
+func (conn *remoteConn) loadTable(ctx context.Context, tableName string) (*RemoteTable, error) {
+	rt := &RemoteTable{db: conn.db, postgres: conn.postgres, loaded: time.Now()}
+	parts := strings.Split(tableName, ".")
+	quoted := make([]string, len(parts))
+	for i := range parts {
+		quoted[i] = rt.quote(parts[i])
+	}
+	rt.name = strings.Join(quoted, ".")
+
+	pks, err := rt.primaryKey(ctx, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.db.QueryContext(ctx, "SELECT * FROM "+rt.name+" WHERE 1 = 0")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	colTyps, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	rt.sch, err = schemaFromColumnTypes(colTyps, pks, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rt.keyed = len(pks) > 0
+	for i, col := range rt.sch.GetAllCols().GetColumns() {
+		rt.cols = append(rt.cols, rt.quote(col.Name))
+		if col.IsPartOfPK || (!rt.keyed && col.TypeInfo.GetTypeIdentifier() != typeinfo.FloatTypeIdentifier) {
+			rt.match = append(rt.match, i)
+		}
+	}
+	return rt, nil
+}
+
+// primaryKey returns the names of the columns of the primary key of the table whose unquoted name is |parts|, or none
+// if it has no primary key.
+func (rt *RemoteTable) primaryKey(ctx context.Context, parts []string) ([]string, error) {
+	var rows *gosql.Rows
+	var err error
+	if rt.postgres {
+		rows, err = rt.db.QueryContext(ctx, "SELECT a.attname FROM pg_index i "+
+			"JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey) "+
+			"WHERE i.indrelid = $1::regclass AND i.indisprimary", rt.name)
+	} else {
+		query := "SELECT column_name FROM information_schema.key_column_usage " +
+			"WHERE constraint_name = 'PRIMARY' AND table_schema = %s AND table_name = ? ORDER BY ordinal_position"
+		if len(parts) > 1 {
+			rows, err = rt.db.QueryContext(ctx, fmt.Sprintf(query, "?"), parts[len(parts)-2], parts[len(parts)-1])
+		} else {
+			rows, err = rt.db.QueryContext(ctx, fmt.Sprintf(query, "DATABASE()"), parts[0])
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pks []string
+	for rows.Next() {
+		var pk string
+		if err := rows.Scan(&pk); err != nil {
+			return nil, err
+		}
+		pks = append(pks, pk)
+	}
+	return pks, rows.Err()
+}
+
+// Schema returns the schema of the table.
+func (rt *RemoteTable) Schema() schema.Schema {
+	return rt.sch
+}
+
+// Rows returns a reader of the rows of the table.
+func (rt *RemoteTable) Rows(ctx context.Context) (*ConnReader, error) {
+	rows, err := rt.db.QueryContext(ctx, "SELECT "+strings.Join(rt.cols, ", ")+" FROM "+rt.name)
+	if err != nil {
+		return nil, err
+	}
+	return newConnReader(nil, rows, rt.sch), nil
+}
+
+// Insert inserts a row with the values |vals| into the table.
+func (rt *RemoteTable) Insert(ctx context.Context, vals []interface{}) error {
+	placeholders := make([]string, len(vals))
+	for i := range vals {
+		placeholders[i] = rt.placeholder(i)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", rt.name, strings.Join(rt.cols, ", "), strings.Join(placeholders, ", "))
+	_, err := rt.db.ExecContext(ctx, query, driverValues(vals)...)
+	return err
+}
+
+// Update replaces a row of the table whose values are |old| with |new|.
+func (rt *RemoteTable) Update(ctx context.Context, old, new []interface{}) error {
+	sets := make([]string, len(new))
+	for i := range new {
+		sets[i] = rt.cols[i] + " = " + rt.placeholder(i)
+	}
+	where, args, err := rt.where(old, len(new))
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s %s", rt.name, strings.Join(sets, ", "), where)
+	_, err = rt.db.ExecContext(ctx, query, append(driverValues(new), args...)...)
+	return err
+}
+
+// Delete deletes a row of the table whose values are |vals|.
+func (rt *RemoteTable) Delete(ctx context.Context, vals []interface{}) error {
+	where, args, err := rt.where(vals, 0)
+	if err != nil {
+		return err
+	}
+	_, err = rt.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s %s", rt.name, where), args...)
+	return err
+}
+
+// where returns the clauses which match the row whose values are |vals|, and their arguments, whose placeholders are
+// numbered from |offset|. Rows are matched on the columns of |rt.match|. Unless they're matched on a primary key, only
+// one of the rows which match is changed, since a table without a primary key may have duplicate rows.
+func (rt *RemoteTable) where(vals []interface{}, offset int) (string, []interface{}, error) {
+	if len(rt.match) == 0 {
+		return "", nil, fmt.Errorf("rows of %s can't be updated or deleted: it has no primary key, and only floating point columns", rt.name)
+	}
+
+	conds := make([]string, len(rt.match))
+	var args []interface{}
+	for i, idx := range rt.match {
+		if vals[idx] == nil {
+			conds[i] = rt.cols[idx] + " IS NULL"
+			continue
+		}
+		conds[i] = rt.cols[idx] + " = " + rt.placeholder(offset+len(args))
+		args = append(args, vals[idx])
+	}
+	cond := strings.Join(conds, " AND ")
+
+	switch {
+	case rt.keyed:
+		return "WHERE " + cond, driverValues(args), nil
+	case rt.postgres:
+		// Postgres doesn't limit UPDATE and DELETE, so the row is matched by the physical location of the first match
+		return fmt.Sprintf("WHERE ctid = (SELECT ctid FROM %s WHERE %s LIMIT 1)", rt.name, cond), driverValues(args), nil
+	default:
+		return "WHERE " + cond + " LIMIT 1", driverValues(args), nil
+	}
+}
+
+func (rt *RemoteTable) quote(id string) string {
+	if rt.postgres {
+		return `"` + strings.ReplaceAll(id, `"`, `""`) + `"`
+	}
+	return "`" + strings.ReplaceAll(id, "`", "``") + "`"
+}
+
+func (rt *RemoteTable) placeholder(i int) string {
+	if rt.postgres {
+		return "$" + strconv.Itoa(i+1)
+	}
+	return "?"
+}
+
+// driverValues returns |vals| as values the drivers accept. Values of types the drivers don't know, like decimals and
+// JSON documents, are passed as their string forms.
+func driverValues(vals []interface{}) []interface{} {
+	args := make([]interface{}, len(vals))
+	for i, val := range vals {
+		args[i] = val
+		if _, ok := val.(driver.Valuer); ok || driver.IsValue(val) {
+			continue
+		}
+		if s, ok := val.(fmt.Stringer); ok {
+			args[i] = s.String()
+		}
+	}
+	return args
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlconn
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteTableWhere(t *testing.T) {
+	t.Run("keyless", func(t *testing.T) {
+		mysqlTable := &RemoteTable{name: "`products`", cols: []string{"`id`", "`name`", "`price`"}, match: []int{0, 1, 2}}
+		where, args, err := mysqlTable.where([]interface{}{1, nil, decimal.RequireFromString("0.50")}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "WHERE `id` = ? AND `name` IS NULL AND `price` = ? LIMIT 1", where)
+		assert.Equal(t, []interface{}{1, "0.5"}, args)
+
+		pgTable := &RemoteTable{postgres: true, name: `"products"`, cols: []string{`"id"`, `"name"`, `"weight"`}, match: []int{0, 1}}
+		where, args, err = pgTable.where([]interface{}{1, "apple", 0.1}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, `WHERE ctid = (SELECT ctid FROM "products" WHERE "id" = $3 AND "name" = $4 LIMIT 1)`, where)
+		assert.Equal(t, []interface{}{1, "apple"}, args)
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		mysqlTable := &RemoteTable{name: "`products`", cols: []string{"`id`", "`name`"}, keyed: true, match: []int{0}}
+		where, args, err := mysqlTable.where([]interface{}{1, "apple"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "WHERE `id` = ?", where)
+		assert.Equal(t, []interface{}{1}, args)
+
+		pgTable := &RemoteTable{postgres: true, name: `"products"`, cols: []string{`"id"`, `"name"`}, keyed: true, match: []int{0}}
+		where, args, err = pgTable.where([]interface{}{1, "apple"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, `WHERE "id" = $3`, where)
+		assert.Equal(t, []interface{}{1}, args)
+	})
+
+	t.Run("no columns to match", func(t *testing.T) {
+		table := &RemoteTable{name: "`points`", cols: []string{"`x`", "`y`"}}
+		_, _, err := table.where([]interface{}{0.1, 0.2}, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestRemoteTableQuote(t *testing.T) {
+	assert.Equal(t, "`a``b`", (&RemoteTable{}).quote("a`b"))
+	assert.Equal(t, `"a""b"`, (&RemoteTable{postgres: true}).quote(`a"b`))
+}
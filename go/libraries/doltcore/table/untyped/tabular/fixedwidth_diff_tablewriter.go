@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"unicode"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -102,7 +103,7 @@ func (w FixedWidthDiffTableWriter) WriteCombinedRow(ctx context.Context, oldRow,
 		if err != nil {
 			return err
 		}
-		combinedRow[i+1], columnDiffs[i+1], widths[i+1] = w.generateTextDiff(oldRowStrs[i+1], newRowStrs[i+1], mode == diff.ModeInPlace)
+		combinedRow[i+1], columnDiffs[i+1], widths[i+1] = w.generateTextDiff(oldRowStrs[i+1], newRowStrs[i+1], mode)
 		hasNewlines = hasNewlines || (columnDiffs[i+1] && len(widths[i+1].Lines) > 2) || (!columnDiffs[i+1] && len(widths[i+1].Lines) > 1)
 	}
 
@@ -126,7 +127,7 @@ func (w FixedWidthDiffTableWriter) WriteCombinedRow(ctx context.Context, oldRow,
 
 // generateTextDiff returns a new string that represents a diff between the old and new string. The returned string will
 // have color applied to it.
-func (w FixedWidthDiffTableWriter) generateTextDiff(oldStr string, newStr string, inPlace bool) (result string, hasDiff bool, width FixedWidthString) {
+func (w FixedWidthDiffTableWriter) generateTextDiff(oldStr string, newStr string, mode diff.Mode) (result string, hasDiff bool, width FixedWidthString) {
 	// The diff routines will modify the strings, and we should just return the original if there will be no diff
 	if oldStr == newStr {
 		return oldStr, false, NewFixedWidthString(oldStr)
@@ -136,7 +137,8 @@ func (w FixedWidthDiffTableWriter) generateTextDiff(oldStr string, newStr string
 	var coloredStr strings.Builder
 	// uncoloredStr is the string that is measured to determine display width, as the colors interfere with measuring
 	var uncoloredStr strings.Builder
-	if inPlace {
+	switch mode {
+	case diff.ModeInPlace:
 		dmp := diffmatchpatch.New()
 		diffs := dmp.DiffMain(oldStr, newStr, false)
 		for _, diffPart := range diffs {
@@ -157,7 +159,31 @@ func (w FixedWidthDiffTableWriter) generateTextDiff(oldStr string, newStr string
 				}
 			}
 		}
-	} else {
+	case diff.ModeWord:
+		for _, diffPart := range wordDiff(oldStr, newStr) {
+			text := diffPart.Text
+			switch diffPart.Type {
+			case diffmatchpatch.DiffInsert:
+				text = "{+" + text + "+}"
+			case diffmatchpatch.DiffDelete:
+				text = "[-" + text + "-]"
+			}
+			uncoloredStr.WriteString(text)
+			for i, part := range strings.Split(text, "\n") {
+				if i > 0 {
+					coloredStr.WriteRune('\n')
+				}
+				switch diffPart.Type {
+				case diffmatchpatch.DiffEqual:
+					coloredStr.WriteString(part)
+				case diffmatchpatch.DiffInsert:
+					coloredStr.WriteString(colorModifiedNew.Sprint(part))
+				case diffmatchpatch.DiffDelete:
+					coloredStr.WriteString(colorModifiedOld.Sprint(part))
+				}
+			}
+		}
+	default:
 		diffStrs := strings.Split(computeDiff.Diff(oldStr, newStr), "\n")
 		for i, diffStr := range diffStrs {
 			if i > 0 {
@@ -180,6 +206,52 @@ func (w FixedWidthDiffTableWriter) generateTextDiff(oldStr string, newStr string
 	return coloredStr.String(), true, ColoredStringWidth(coloredStr.String(), uncoloredStr.String())
 }
 
+// wordDiff computes the diff between |oldStr| and |newStr| at word granularity. Runs of whitespace are treated as
+// their own tokens, so that changes to spacing are reported without disturbing the surrounding words.
+func wordDiff(oldStr, newStr string) []diffmatchpatch.Diff {
+	tokens := []string{""}
+	tokenIdx := make(map[string]rune)
+	toRunes := func(s string) []rune {
+		var runes []rune
+		for _, tok := range splitWords(s) {
+			r, ok := tokenIdx[tok]
+			if !ok {
+				r = rune(len(tokens))
+				tokenIdx[tok] = r
+				tokens = append(tokens, tok)
+			}
+			runes = append(runes, r)
+		}
+		return runes
+	}
+	oldRunes := toRunes(oldStr)
+	newRunes := toRunes(newStr)
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMainRunes(oldRunes, newRunes, false)
+	return dmp.DiffCharsToLines(diffs, tokens)
+}
+
+// splitWords splits |s| into alternating runs of whitespace and non-whitespace characters. Concatenating the
+// returned tokens yields |s|.
+func splitWords(s string) []string {
+	var tokens []string
+	start := 0
+	inSpace := false
+	for i, r := range s {
+		isSpace := unicode.IsSpace(r)
+		if i > start && isSpace != inSpace {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		inSpace = isSpace
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
 func colorsForDiffTypes(colDiffTypes []diff.ChangeType) []*color.Color {
 	colors := make([]*color.Color, len(colDiffTypes))
 	for i := range colDiffTypes {
@@ -0,0 +1,78 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tabular
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected []string
+	}{
+		{"", nil},
+		{"one", []string{"one"}},
+		{"one two", []string{"one", " ", "two"}},
+		{"  one\n\ttwo ", []string{"  ", "one", "\n\t", "two", " "}},
+		{"héllo wörld", []string{"héllo", " ", "wörld"}},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			actual := splitWords(test.in)
+			assert.Equal(t, test.expected, actual)
+			assert.Equal(t, test.in, strings.Join(actual, ""))
+		})
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	diffs := wordDiff("the quick brown fox", "the slow brown fox jumps")
+	assert.Equal(t, []diffmatchpatch.Diff{
+		{Type: diffmatchpatch.DiffEqual, Text: "the "},
+		{Type: diffmatchpatch.DiffDelete, Text: "quick"},
+		{Type: diffmatchpatch.DiffInsert, Text: "slow"},
+		{Type: diffmatchpatch.DiffEqual, Text: " brown fox"},
+		{Type: diffmatchpatch.DiffInsert, Text: " jumps"},
+	}, diffs)
+}
+
+func TestGenerateWordTextDiff(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = noColor
+	}()
+
+	w := FixedWidthDiffTableWriter{}
+	result, hasDiff, _ := w.generateTextDiff("the quick brown fox", "the slow brown fox", diff.ModeWord)
+	assert.True(t, hasDiff)
+	assert.Equal(t, "the [-quick-]{+slow+} brown fox", result)
+
+	result, hasDiff, _ = w.generateTextDiff("the lazy dog", "the sleepy dog", diff.ModeWord)
+	assert.True(t, hasDiff)
+	assert.Equal(t, "the [-lazy-]{+sleepy+} dog", result)
+
+	result, hasDiff, _ = w.generateTextDiff("unchanged", "unchanged", diff.ModeWord)
+	assert.False(t, hasDiff)
+	assert.Equal(t, "unchanged", result)
+}
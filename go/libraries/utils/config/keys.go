@@ -15,22 +15,33 @@
 package config
 
 var ConfigOptions = map[string]struct{}{
-	UserEmailKey:          {},
-	UserNameKey:           {},
-	UserCreds:             {},
-	DoltEditor:            {},
-	InitBranchName:        {},
-	RemotesApiHostKey:     {},
-	RemotesApiHostPortKey: {},
-	AddCredsUrlKey:        {},
-	DoltLabInsecureKey:    {},
-	MetricsDisabled:       {},
-	MetricsHost:           {},
-	MetricsPort:           {},
-	MetricsInsecure:       {},
-	PushAutoSetupRemote:   {},
-	ProfileKey:            {},
-	VersionCheckDisabled:  {},
+	UserEmailKey:                     {},
+	UserNameKey:                      {},
+	UserCreds:                        {},
+	DoltEditor:                       {},
+	InitBranchName:                   {},
+	RemotesApiHostKey:                {},
+	RemotesApiHostPortKey:            {},
+	AddCredsUrlKey:                   {},
+	DoltLabInsecureKey:               {},
+	MetricsDisabled:                  {},
+	MetricsHost:                      {},
+	MetricsPort:                      {},
+	MetricsInsecure:                  {},
+	PushAutoSetupRemote:              {},
+	PullRebase:                       {},
+	ProfileKey:                       {},
+	VersionCheckDisabled:             {},
+	CredentialHelperKey:              {},
+	RemotesProxyKey:                  {},
+	RemotesMaxConcurrentDownloadsKey: {},
+	RemotesMaxConcurrentUploadsKey:   {},
+	RemotesMaxBytesPerSecondKey:      {},
+	UserSigningKey:                   {},
+	CommitGpgSign:                    {},
+	GpgFormat:                        {},
+	GpgProgram:                       {},
+	GpgSshAllowedSignersFile:         {},
 }
 
 const UserEmailKey = "user.email"
@@ -39,6 +50,16 @@ const UserNameKey = "user.name"
 
 const UserCreds = "user.creds"
 
+const UserSigningKey = "user.signingkey"
+
+const CommitGpgSign = "commit.gpgsign"
+
+const GpgFormat = "gpg.format"
+
+const GpgProgram = "gpg.program"
+
+const GpgSshAllowedSignersFile = "gpg.ssh.allowedsignersfile"
+
 const DoltEditor = "core.editor"
 
 const InitBranchName = "init.defaultbranch"
@@ -47,6 +68,14 @@ const RemotesApiHostKey = "remotes.default_host"
 
 const RemotesApiHostPortKey = "remotes.default_port"
 
+const RemotesProxyKey = "remotes.proxy"
+
+const RemotesMaxConcurrentDownloadsKey = "remotes.max_concurrent_downloads"
+
+const RemotesMaxConcurrentUploadsKey = "remotes.max_concurrent_uploads"
+
+const RemotesMaxBytesPerSecondKey = "remotes.max_bytes_per_second"
+
 const AddCredsUrlKey = "creds.add_url"
 
 const DoltLabInsecureKey = "doltlab.insecure"
@@ -61,6 +90,10 @@ const MetricsInsecure = "metrics.insecure"
 
 const PushAutoSetupRemote = "push.autosetupremote"
 
+const PullRebase = "pull.rebase"
+
 const ProfileKey = "profile"
 
 const VersionCheckDisabled = "versioncheck.disabled"
+
+const CredentialHelperKey = "credential.helper"
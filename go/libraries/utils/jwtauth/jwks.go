@@ -44,6 +44,11 @@ type fetchedJWKS struct {
 	cache         *cachedJWKS
 }
 
+// NewFetchedJWKS returns a KeyProvider which fetches the JSON Web Key Set at |url|.
+func NewFetchedJWKS(url string) (KeyProvider, error) {
+	return newFetchedJWKS(url)
+}
+
 func newJWKS(provider JWTProvider) (*fetchedJWKS, error) {
 	return newFetchedJWKS(provider.URL)
 }
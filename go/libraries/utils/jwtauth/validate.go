@@ -29,7 +29,9 @@ type KeyProvider interface {
 
 var ErrKeyNotFound = errors.New("Key not found")
 
-func ValidateJWT(unparsed string, reqTime time.Time, keyProvider KeyProvider, expectedClaims jwt.Expected) (*Claims, error) {
+// ValidateJWT validates the signature and the expected claims of the token |unparsed|, and returns its claims. Any
+// |customClaims| given are also unmarshalled from the token's claims.
+func ValidateJWT(unparsed string, reqTime time.Time, keyProvider KeyProvider, expectedClaims jwt.Expected, customClaims ...interface{}) (*Claims, error) {
 	parsed, err := jwt.ParseSigned(unparsed)
 	if err != nil {
 		return nil, err
@@ -55,7 +57,7 @@ func ValidateJWT(unparsed string, reqTime time.Time, keyProvider KeyProvider, ex
 	var claims Claims
 	claimsError := fmt.Errorf("ValidateJWT: KeyID: %v. Err: %w", keyID, ErrKeyNotFound)
 	for _, key := range keys {
-		claimsError = parsed.Claims(key.Key, &claims)
+		claimsError = parsed.Claims(key.Key, append([]interface{}{&claims}, customClaims...)...)
 		if claimsError == nil {
 			break
 		}
@@ -106,6 +106,29 @@ func BenchmarkSelectRandomRanges(b *testing.B) {
 	})
 }
 
+func BenchmarkWindowSlidingFrameAggregation(b *testing.B) {
+	benchmarkSysbenchQuery(b, func(int) string {
+		return `SELECT id, sum(k) OVER (ORDER BY id ROWS BETWEEN 100 PRECEDING AND CURRENT ROW),
+				avg(k) OVER (ORDER BY id ROWS BETWEEN 100 PRECEDING AND 100 FOLLOWING)
+				FROM sbtest1`
+	})
+}
+
+func BenchmarkWindowPartitionRanking(b *testing.B) {
+	benchmarkSysbenchQuery(b, func(int) string {
+		return `SELECT id, row_number() OVER (PARTITION BY k % 10 ORDER BY id),
+				rank() OVER (PARTITION BY k % 10 ORDER BY k)
+				FROM sbtest1`
+	})
+}
+
+func BenchmarkHashJoinLargeTables(b *testing.B) {
+	benchmarkSysbenchQuery(b, func(int) string {
+		return `SELECT /*+ JOIN_ORDER(a, b) HASH_JOIN(a, b) */ count(*)
+				FROM sbtest1 a JOIN sbtest1 b ON a.pad = b.pad`
+	})
+}
+
 func benchmarkSysbenchQuery(b *testing.B, getQuery func(int) string) {
 	ctx, eng := setupBenchmark(b, dEnv)
 	for i := 0; i < b.N; i++ {
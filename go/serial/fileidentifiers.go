@@ -30,6 +30,7 @@ const CommitFileID = "DCMT"
 const RootValueFileID = "RTVL"
 const TableFileID = "DTBL"
 const ProllyTreeNodeFileID = "TUPM"
+const ProllyTreeNodeCompressedFileID = "TUPC"
 const AddressMapFileID = "ADRM"
 const CommitClosureFileID = "CMCL"
 const TableSchemaFileID = "DSCH"
@@ -153,7 +153,7 @@ func typeString(value types.Value) string {
 			typeString = "DoltgresRootValue"
 		case serial.TableFileID:
 			typeString = "Table"
-		case serial.ProllyTreeNodeFileID:
+		case serial.ProllyTreeNodeFileID, serial.ProllyTreeNodeCompressedFileID:
 			typeString = "ProllyTreeNode"
 		case serial.AddressMapFileID:
 			typeString = "AddressMap"
@@ -237,7 +237,7 @@ func outputEncodedValue(ctx context.Context, w io.Writer, value types.Value) err
 				return err
 			}
 			return tree.OutputAddressMapNode(w, node)
-		case serial.ProllyTreeNodeFileID:
+		case serial.ProllyTreeNodeFileID, serial.ProllyTreeNodeCompressedFileID:
 			fallthrough
 		case serial.AddressMapFileID:
 			node, err := shim.NodeFromValue(value)
@@ -174,6 +174,20 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	nameoff := builder.CreateString(opts.Meta.Name)
 	emailoff := builder.CreateString(opts.Meta.Email)
 	descoff := builder.CreateString(opts.Meta.Description)
+	var sigoff flatbuffers.UOffsetT
+	if opts.Meta.Signature != "" {
+		sigoff = builder.CreateString(opts.Meta.Signature)
+	}
+	var mdkeysoff, mdvalsoff flatbuffers.UOffsetT
+	if len(opts.Meta.Metadata) > 0 {
+		keys := opts.Meta.MetadataKeys()
+		vals := make([]string, len(keys))
+		for i, k := range keys {
+			vals[i] = opts.Meta.Metadata[k]
+		}
+		mdkeysoff = SerializeStringVector(builder, keys)
+		mdvalsoff = SerializeStringVector(builder, vals)
+	}
 	serial.CommitStart(builder)
 	serial.CommitAddRoot(builder, vaddroff)
 	serial.CommitAddHeight(builder, maxheight+1)
@@ -184,6 +198,14 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	serial.CommitAddDescription(builder, descoff)
 	serial.CommitAddTimestampMillis(builder, opts.Meta.Timestamp)
 	serial.CommitAddUserTimestampMillis(builder, opts.Meta.UserTimestamp)
+	if opts.Meta.Signature != "" {
+		// only signed commits have the field, so that unsigned commits can be read by older clients
+		serial.CommitAddSignature(builder, sigoff)
+	}
+	if len(opts.Meta.Metadata) > 0 {
+		serial.CommitAddMetadataKeys(builder, mdkeysoff)
+		serial.CommitAddMetadataValues(builder, mdvalsoff)
+	}
 
 	bytes := serial.FinishMessage(builder, serial.CommitEnd(builder), []byte(serial.CommitFileID))
 	return bytes, maxheight + 1
@@ -222,6 +244,16 @@ func newCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 		if err != nil {
 			return nil, err
 		}
+		// a signature in the meta given, such as one copied from a commit being cherry-picked, doesn't sign this commit
+		meta := *opts.Meta
+		meta.Signature = ""
+		if opts.Signer != nil {
+			meta.Signature, err = opts.Signer(commitSigningPayload(r.TargetHash(), opts.Parents, &meta))
+			if err != nil {
+				return nil, err
+			}
+		}
+		opts.Meta = &meta
 		bs, height := commit_flatbuffer(r.TargetHash(), opts, heights, parentClosureAddr)
 		v := types.SerialMessage(bs)
 		addr, err := v.Hash(vrw.Format())
@@ -231,6 +263,13 @@ func newCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 		return &Commit{v, addr, height}, nil
 	}
 
+	if opts.Signer != nil {
+		return nil, ErrUnsupportedCommitSigning
+	}
+	if len(opts.Meta.Metadata) > 0 {
+		return nil, ErrUnsupportedCommitMetadata
+	}
+
 	metaSt, err := opts.Meta.toNomsStruct(vrw.Format())
 	if err != nil {
 		return nil, err
@@ -576,13 +615,7 @@ func GetCommitMeta(ctx context.Context, cv types.Value) (*CommitMeta, error) {
 		if err != nil {
 			return nil, err
 		}
-		ret := &CommitMeta{}
-		ret.Name = string(cmsg.Name())
-		ret.Email = string(cmsg.Email())
-		ret.Description = string(cmsg.Description())
-		ret.Timestamp = cmsg.TimestampMillis()
-		ret.UserTimestamp = cmsg.UserTimestampMillis()
-		return ret, nil
+		return commitMetaFromMessage(&cmsg), nil
 	}
 	c, ok := cv.(types.Struct)
 	if !ok {
@@ -605,6 +638,24 @@ func GetCommitMeta(ctx context.Context, cv types.Value) (*CommitMeta, error) {
 	}
 }
 
+// commitMetaFromMessage returns the CommitMeta of a serialized commit
+func commitMetaFromMessage(cmsg *serial.Commit) *CommitMeta {
+	ret := &CommitMeta{}
+	ret.Name = string(cmsg.Name())
+	ret.Email = string(cmsg.Email())
+	ret.Description = string(cmsg.Description())
+	ret.Timestamp = cmsg.TimestampMillis()
+	ret.UserTimestamp = cmsg.UserTimestampMillis()
+	ret.Signature = string(cmsg.Signature())
+	if n := cmsg.MetadataKeysLength(); n > 0 && n == cmsg.MetadataValuesLength() {
+		ret.Metadata = make(map[string]string, n)
+		for i := 0; i < n; i++ {
+			ret.Metadata[string(cmsg.MetadataKeys(i))] = string(cmsg.MetadataValues(i))
+		}
+	}
+	return ret
+}
+
 func GetCommittedValue(ctx context.Context, vr types.ValueReader, cv types.Value) (types.Value, error) {
 	if sm, ok := cv.(types.SerialMessage); ok {
 		data := []byte(sm)
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -44,6 +45,7 @@ const defaultInitialCommitMessage = "Initialize data repository"
 var ErrNameNotConfigured = errors.New("Aborting commit due to empty committer name. Is your config set?")
 var ErrEmailNotConfigured = errors.New("Aborting commit due to empty committer email. Is your config set?")
 var ErrEmptyCommitMessage = errors.New("Aborting commit due to empty commit message.")
+var ErrUnsupportedCommitMetadata = errors.New("commit metadata is not supported by the storage format of this database")
 
 // CommitterDate is the function used to get the committer time when creating commits.
 var CommitterDate = time.Now
@@ -60,6 +62,10 @@ type CommitMeta struct {
 	Timestamp     uint64
 	Description   string
 	UserTimestamp int64
+	// Signature is the armored GPG or SSH signature of a signed commit, and empty for unsigned commits
+	Signature string
+	// Metadata is arbitrary key/value metadata attached to the commit, such as ticket ids
+	Metadata map[string]string
 }
 
 // NewCommitMeta creates a CommitMeta instance from a name, email, and description and uses the current time for the
@@ -118,7 +124,7 @@ func NewCommitMetaWithUserTS(name, email, desc string, userTS time.Time) (*Commi
 	committerDateMillis := uint64(CommitterDate().UnixMilli())
 	authorDateMillis := userTS.UnixMilli()
 
-	return &CommitMeta{n, e, committerDateMillis, d, authorDateMillis}, nil
+	return &CommitMeta{n, e, committerDateMillis, d, authorDateMillis, "", nil}, nil
 }
 
 func getRequiredFromSt(st types.Struct, k string) (types.Value, error) {
@@ -170,6 +176,8 @@ func CommitMetaFromNomsSt(st types.Struct) (*CommitMeta, error) {
 		uint64(ts.(types.Uint)),
 		string(d.(types.String)),
 		int64(userTS.(types.Int)),
+		"",
+		nil,
 	}, nil
 }
 
@@ -186,6 +194,16 @@ func (cm *CommitMeta) toNomsStruct(nbf *types.NomsBinFormat) (types.Struct, erro
 	return types.NewStruct(nbf, commitMetaStName, metadata)
 }
 
+// MetadataKeys returns the keys of the commit's metadata in sorted order
+func (cm *CommitMeta) MetadataKeys() []string {
+	keys := make([]string, 0, len(cm.Metadata))
+	for k := range cm.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // Time returns the time at which the commit occurred
 func (cm *CommitMeta) Time() time.Time {
 	return time.UnixMilli(cm.UserTimestamp)
@@ -33,4 +33,12 @@ type CommitOptions struct {
 	Parents []hash.Hash
 
 	Meta *CommitMeta
+
+	// Signer, if provided, signs the commit. It's called with the payload
+	// returned by CommitSigningPayload for the commit being created, and
+	// returns the armored signature to store with it.
+	Signer CommitSigner
 }
+
+// CommitSigner signs the payload of a commit, returning an armored signature.
+type CommitSigner func(payload []byte) (string, error)
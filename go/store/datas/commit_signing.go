@@ -0,0 +1,80 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrUnsupportedCommitSigning is returned when creating a signed commit in a database with the old storage format.
+var ErrUnsupportedCommitSigning = errors.New("signed commits are not supported by the storage format of this database")
+
+// commitSigningPayload returns the data signed for a commit: its root value, parents and metadata, in a text form
+// modeled on git's commit objects. Everything but the signature which identifies the commit is included, so that a
+// signature can't be moved to a different commit.
+func commitSigningPayload(root hash.Hash, parents []hash.Hash, meta *CommitMeta) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "root %s\n", root.String())
+	for _, p := range parents {
+		fmt.Fprintf(&sb, "parent %s\n", p.String())
+	}
+	fmt.Fprintf(&sb, "author %s <%s> %d\n", meta.Name, meta.Email, meta.UserTimestamp)
+	fmt.Fprintf(&sb, "committer %s <%s> %d\n", meta.Name, meta.Email, meta.Timestamp)
+	for _, k := range meta.MetadataKeys() {
+		fmt.Fprintf(&sb, "metadata %s %q\n", k, meta.Metadata[k])
+	}
+	sb.WriteString("\n")
+	sb.WriteString(meta.Description)
+	sb.WriteString("\n")
+	return []byte(sb.String())
+}
+
+// GetCommitSignature returns the signature of a commit and the payload it was made over. The signature is empty for
+// unsigned commits.
+func GetCommitSignature(cv types.Value) (signature string, payload []byte, err error) {
+	sm, ok := cv.(types.SerialMessage)
+	if !ok {
+		// commits in the old storage format can't be signed
+		return "", nil, nil
+	}
+	data := []byte(sm)
+	if serial.GetFileID(data) != serial.CommitFileID {
+		return "", nil, errors.New("GetCommitSignature: provided value is not a commit.")
+	}
+	var cmsg serial.Commit
+	err = serial.InitCommitRoot(&cmsg, data, serial.MessagePrefixSz)
+	if err != nil {
+		return "", nil, err
+	}
+
+	signature = string(cmsg.Signature())
+	if signature == "" {
+		return "", nil, nil
+	}
+
+	var root hash.Hash
+	copy(root[:], cmsg.RootBytes())
+	parents, err := types.SerialCommitParentAddrs(types.Format_DOLT, sm)
+	if err != nil {
+		return "", nil, err
+	}
+	return signature, commitSigningPayload(root, parents, commitMetaFromMessage(&cmsg)), nil
+}
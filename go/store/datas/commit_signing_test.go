@@ -0,0 +1,79 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestSignedCommit(t *testing.T) {
+	ctx := context.Background()
+	storage := &chunks.TestStorage{}
+	db := NewDatabase(storage.NewViewWithDefaultFormat()).(*database)
+	defer db.Close()
+
+	ds, err := db.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	ds, err = db.Commit(ctx, ds, types.String("one"), CommitOptions{})
+	require.NoError(t, err)
+	parent, parentAddr := mustHead(ds), mustHeadAddr(ds)
+
+	meta, err := NewCommitMeta("Bill Billerson", "bill@billerson.com", "signed commit")
+	require.NoError(t, err)
+	var signed []byte
+	signer := func(payload []byte) (string, error) {
+		signed = payload
+		return "-----BEGIN SIGNATURE-----", nil
+	}
+	ds, err = db.Commit(ctx, ds, types.String("two"), CommitOptions{Meta: meta, Signer: signer})
+	if !db.Format().UsesFlatbuffers() {
+		assert.ErrorIs(t, err, ErrUnsupportedCommitSigning)
+		return
+	}
+	require.NoError(t, err)
+	assert.Empty(t, meta.Signature, "the signer must not modify the meta it was given")
+
+	head := mustHead(ds)
+	headMeta, err := GetCommitMeta(ctx, head)
+	require.NoError(t, err)
+	assert.Equal(t, "-----BEGIN SIGNATURE-----", headMeta.Signature)
+
+	sig, payload, err := GetCommitSignature(head)
+	require.NoError(t, err)
+	assert.Equal(t, "-----BEGIN SIGNATURE-----", sig)
+	assert.Equal(t, signed, payload)
+	assert.Contains(t, string(payload), "parent "+parentAddr.String()+"\n")
+	assert.Contains(t, string(payload), "author Bill Billerson <bill@billerson.com>")
+	assert.Contains(t, string(payload), "\n\nsigned commit\n")
+
+	sig, payload, err = GetCommitSignature(parent)
+	require.NoError(t, err)
+	assert.Empty(t, sig)
+	assert.Nil(t, payload)
+
+	// a signature copied from another commit's meta isn't kept
+	ds, err = db.Commit(ctx, ds, types.String("three"), CommitOptions{Meta: headMeta})
+	require.NoError(t, err)
+	sig, _, err = GetCommitSignature(mustHead(ds))
+	require.NoError(t, err)
+	assert.Empty(t, sig)
+}
@@ -685,3 +685,34 @@ func TestPersistedCommitConsts(t *testing.T) {
 	assert.Equal(t, "meta", commitMetaField)
 	assert.Equal(t, "Commit", commitName)
 }
+
+func TestCommitMetadata(t *testing.T) {
+	ctx := context.Background()
+	storage := &chunks.TestStorage{}
+	db := NewDatabase(storage.NewViewWithDefaultFormat()).(*database)
+	defer db.Close()
+
+	ds, err := db.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	meta, err := NewCommitMeta("Bill Billerson", "bill@billerson.com", "commit with metadata")
+	require.NoError(t, err)
+	meta.Metadata = map[string]string{"ticket": "ABC-1", "pipeline.run": "42"}
+
+	ds, err = db.Commit(ctx, ds, types.String("one"), CommitOptions{Meta: meta})
+	if !db.Format().UsesFlatbuffers() {
+		assert.ErrorIs(t, err, ErrUnsupportedCommitMetadata)
+		return
+	}
+	require.NoError(t, err)
+
+	headMeta, err := GetCommitMeta(ctx, mustHead(ds))
+	require.NoError(t, err)
+	assert.Equal(t, meta.Metadata, headMeta.Metadata)
+	assert.Equal(t, []string{"pipeline.run", "ticket"}, headMeta.MetadataKeys())
+
+	ds, err = db.Commit(ctx, ds, types.String("two"), CommitOptions{})
+	require.NoError(t, err)
+	headMeta, err = GetCommitMeta(ctx, mustHead(ds))
+	require.NoError(t, err)
+	assert.Nil(t, headMeta.Metadata)
+}
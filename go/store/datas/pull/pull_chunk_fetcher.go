@@ -33,6 +33,12 @@ type ChunkFetcherable interface {
 	ChunkFetcher(ctx context.Context) nbs.ChunkFetcher
 }
 
+// DeltaChunkFetcherable is implemented by chunk stores which can fetch chunks
+// as deltas against chunks the caller already has.
+type DeltaChunkFetcherable interface {
+	DeltaChunkFetcher(ctx context.Context, bases nbs.DeltaBases) nbs.ChunkFetcher
+}
+
 func GetChunkFetcher(ctx context.Context, cs GetManyer) nbs.ChunkFetcher {
 	if fable, ok := cs.(ChunkFetcherable); ok {
 		return fable.ChunkFetcher(ctx)
@@ -40,6 +46,15 @@ func GetChunkFetcher(ctx context.Context, cs GetManyer) nbs.ChunkFetcher {
 	return NewPullChunkFetcher(ctx, cs)
 }
 
+// GetDeltaChunkFetcher is like GetChunkFetcher, but uses |bases| to fetch
+// chunks as deltas if |cs| supports it.
+func GetDeltaChunkFetcher(ctx context.Context, cs GetManyer, bases nbs.DeltaBases) nbs.ChunkFetcher {
+	if dfable, ok := cs.(DeltaChunkFetcherable); ok {
+		return dfable.DeltaChunkFetcher(ctx, bases)
+	}
+	return GetChunkFetcher(ctx, cs)
+}
+
 // A PullChunkFetcher is a simple implementation of |ChunkFetcher| based on
 // calling GetManyCompressed.
 //
@@ -56,6 +56,8 @@ type Puller struct {
 	wr *PullTableFileWriter
 	rd nbs.ChunkFetcher
 
+	bases *deltaBases
+
 	pushLog *log.Logger
 
 	statsCh chan Stats
@@ -109,8 +111,6 @@ func NewPuller(
 		DestStore:            sinkCS.(chunks.TableFileStore),
 	})
 
-	rd := GetChunkFetcher(ctx, srcChunkStore)
-
 	var pushLogger *log.Logger
 	if dbg, ok := os.LookupEnv(dconfig.EnvPushLog); ok && strings.ToLower(dbg) == "true" {
 		logFilePath := filepath.Join(tempDir, "push.log")
@@ -127,7 +127,7 @@ func NewPuller(
 		sinkDBCS:      sinkCS,
 		hashes:        hash.NewHashSet(hashes...),
 		wr:            wr,
-		rd:            rd,
+		bases:         newDeltaBases(sinkCS),
 		pushLog:       pushLogger,
 		statsCh:       statsCh,
 		stats: &stats{
@@ -135,6 +135,8 @@ func NewPuller(
 		},
 	}
 
+	p.rd = GetDeltaChunkFetcher(ctx, srcChunkStore, p.bases)
+
 	if lcs, ok := sinkCS.(chunks.LoggingChunkStore); ok {
 		lcs.SetLogger(p)
 	}
@@ -142,6 +144,23 @@ func NewPuller(
 	return p, nil
 }
 
+// SetDeltaBases tells the Puller about earlier versions of the chunks it is
+// pulling which the sink already has. |bases| maps the address of a chunk
+// being pulled, typically a commit, to the address of a chunk in the sink,
+// typically the commit the sink currently has for the same branch. As chunks
+// are pulled, their children are paired with the children of their base
+// chunk in the same position, and if the source supports it, changed chunks
+// are fetched as deltas against the chunks they are paired with.
+//
+// Must be called before |Pull|.
+func (p *Puller) SetDeltaBases(bases map[hash.Hash]hash.Hash) {
+	for h, base := range bases {
+		if h != base {
+			p.bases.add(h, base)
+		}
+	}
+}
+
 func (p *Puller) Logf(fmt string, args ...interface{}) {
 	if p.pushLog != nil {
 		p.pushLog.Printf(fmt, args...)
@@ -291,6 +310,94 @@ func (s *stats) read() Stats {
 	return ret
 }
 
+// baseAddrs returns the addresses referenced by the delta base of the chunk
+// at |h|, if it has one.
+func (p *Puller) baseAddrs(ctx context.Context, h hash.Hash) ([]hash.Hash, error) {
+	base, ok := p.bases.take(h)
+	if !ok {
+		return nil, nil
+	}
+	chnk, err := p.bases.GetDeltaBase(ctx, base)
+	if err != nil || chnk.IsEmpty() {
+		return nil, err
+	}
+	var addrs []hash.Hash
+	err = p.waf(chnk, func(h hash.Hash, _ bool) error {
+		addrs = append(addrs, h)
+		return nil
+	})
+	return addrs, err
+}
+
+// pairAddrs calls |pair| with each address in |addrs| which differs from the
+// address in the corresponding position of |baseAddrs|. The two lists are
+// aligned on their common prefix and suffix, and the changed addresses
+// between them are paired from the front for the first half and from the back
+// for the second half, so that an inserted or removed address only misaligns
+// the addresses near it.
+func pairAddrs(addrs, baseAddrs []hash.Hash, pair func(h, base hash.Hash)) {
+	if len(baseAddrs) == 0 {
+		return
+	}
+	start := 0
+	for start < len(addrs) && start < len(baseAddrs) && addrs[start] == baseAddrs[start] {
+		start++
+	}
+	end, baseEnd := len(addrs), len(baseAddrs)
+	for end > start && baseEnd > start && addrs[end-1] == baseAddrs[baseEnd-1] {
+		end--
+		baseEnd--
+	}
+	mid := start + (end-start+1)/2
+	for i := start; i < end; i++ {
+		j := i
+		if i >= mid {
+			j = baseEnd - (end - i)
+		}
+		if j >= start && j < baseEnd {
+			pair(addrs[i], baseAddrs[j])
+		}
+	}
+}
+
+// deltaBases implements nbs.DeltaBases for a Puller.
+type deltaBases struct {
+	mu    sync.Mutex
+	bases map[hash.Hash]hash.Hash
+	sink  chunks.ChunkStore
+}
+
+var _ nbs.DeltaBases = (*deltaBases)(nil)
+
+func newDeltaBases(sink chunks.ChunkStore) *deltaBases {
+	return &deltaBases{bases: make(map[hash.Hash]hash.Hash), sink: sink}
+}
+
+func (b *deltaBases) add(h, base hash.Hash) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bases[h] = base
+}
+
+func (b *deltaBases) take(h hash.Hash) (hash.Hash, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	base, ok := b.bases[h]
+	delete(b.bases, h)
+	return base, ok
+}
+
+func (b *deltaBases) DeltaBase(h hash.Hash) (hash.Hash, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	base, ok := b.bases[h]
+	return base, ok
+}
+
+func (b *deltaBases) GetDeltaBase(ctx context.Context, h hash.Hash) (chunks.Chunk, error) {
+	return b.sink.Get(ctx, h)
+}
+
 // Pull executes the sync operation
 func (p *Puller) Pull(ctx context.Context) error {
 	if p.statsCh != nil {
@@ -354,13 +461,22 @@ func (p *Puller) Pull(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
+			var addrs []hash.Hash
 			err = p.waf(chnk, func(h hash.Hash, _ bool) error {
-				tracker.Seen(h)
+				addrs = append(addrs, h)
 				return nil
 			})
 			if err != nil {
 				return err
 			}
+			baseAddrs, err := p.baseAddrs(ctx, chnk.Hash())
+			if err != nil {
+				return err
+			}
+			pairAddrs(addrs, baseAddrs, p.bases.add)
+			for _, h := range addrs {
+				tracker.Seen(h)
+			}
 			tracker.TickProcessed()
 
 			err = p.wr.AddCompressedChunk(ctx, cChk)
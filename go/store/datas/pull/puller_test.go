@@ -424,3 +424,133 @@ func mustTuple(val types.Tuple, err error) types.Tuple {
 	d.PanicIfError(err)
 	return val
 }
+
+// deltaRecordingStore records the delta bases the Puller supplies for each
+// chunk it fetches.
+type deltaRecordingStore struct {
+	*nbs.NomsBlockStore
+	deltaBases nbs.DeltaBases
+
+	mu     sync.Mutex
+	bases  map[hash.Hash]hash.Hash
+	nFetch int
+}
+
+func (s *deltaRecordingStore) DeltaChunkFetcher(ctx context.Context, bases nbs.DeltaBases) nbs.ChunkFetcher {
+	s.deltaBases = bases
+	return NewPullChunkFetcher(ctx, s)
+}
+
+func (s *deltaRecordingStore) GetManyCompressed(ctx context.Context, hashes hash.HashSet, found func(context.Context, nbs.CompressedChunk)) error {
+	s.mu.Lock()
+	for h := range hashes {
+		s.nFetch++
+		if base, ok := s.deltaBases.DeltaBase(h); ok {
+			s.bases[h] = base
+		}
+	}
+	s.mu.Unlock()
+	return s.NomsBlockStore.GetManyCompressed(ctx, hashes, found)
+}
+
+func TestPullerDeltaBases(t *testing.T) {
+	ctx := context.Background()
+	makeStore := func() *nbs.NomsBlockStore {
+		dir := filepath.Join(os.TempDir(), uuid.New().String())
+		require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+		st, err := nbs.NewLocalStore(ctx, types.Format_Default.VersionString(), dir, clienttest.DefaultMemTableSize, nbs.NewUnlimitedMemQuotaProvider())
+		require.NoError(t, err)
+		return st
+	}
+
+	srcSt := makeStore()
+	vs := types.NewValueStore(srcSt)
+	db := datas.NewTypesDatabase(vs, tree.NewNodeStore(srcSt))
+	defer db.Close()
+
+	kvs := make([]types.Value, 0, 2*20000)
+	for i := 0; i < 20000; i++ {
+		kvs = append(kvs, types.Int(i), types.String(uuid.New().String()))
+	}
+	rootMap, err := types.NewMap(ctx, vs)
+	require.NoError(t, err)
+	rootMap, err = addTableValues(ctx, vs, rootMap, "t", kvs...)
+	require.NoError(t, err)
+	ds, err := db.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	ds, err = db.Commit(ctx, ds, rootMap, datas.CommitOptions{})
+	require.NoError(t, err)
+	initial, ok := ds.MaybeHeadAddr()
+	require.True(t, ok)
+
+	rootMap, err = addTableValues(ctx, vs, rootMap, "t", types.Int(5), types.String("five"))
+	require.NoError(t, err)
+	ds, err = db.Commit(ctx, ds, rootMap, datas.CommitOptions{Parents: []hash.Hash{initial}})
+	require.NoError(t, err)
+	first, ok := ds.MaybeHeadAddr()
+	require.True(t, ok)
+
+	rootMap, err = addTableValues(ctx, vs, rootMap, "t", types.Int(10), types.String("ten"), types.Int(15000), types.String("fifteen thousand"))
+	require.NoError(t, err)
+	ds, err = db.Commit(ctx, ds, rootMap, datas.CommitOptions{Parents: []hash.Hash{first}})
+	require.NoError(t, err)
+	second, ok := ds.MaybeHeadAddr()
+	require.True(t, ok)
+
+	sinkSt := makeStore()
+	sinkvs := types.NewValueStore(sinkSt)
+	sinkdb := datas.NewTypesDatabase(sinkvs, tree.NewNodeStore(sinkSt))
+	defer sinkdb.Close()
+
+	waf, err := types.WalkAddrsForChunkStore(srcSt)
+	require.NoError(t, err)
+	tmpDir := t.TempDir()
+
+	plr, err := NewPuller(ctx, tmpDir, 128, srcSt, sinkSt, waf, []hash.Hash{first}, nil)
+	require.NoError(t, err)
+	require.NoError(t, plr.Pull(ctx))
+
+	src := &deltaRecordingStore{NomsBlockStore: srcSt, bases: make(map[hash.Hash]hash.Hash)}
+	plr, err = NewPuller(ctx, tmpDir, 128, src, sinkSt, waf, []hash.Hash{second}, nil)
+	require.NoError(t, err)
+	plr.SetDeltaBases(map[hash.Hash]hash.Hash{second: first})
+	require.NoError(t, plr.Pull(ctx))
+
+	// Every changed chunk beneath the new commit should have been paired
+	// with the chunk in the same position beneath the old one, all of which
+	// the sink already has.
+	assert.Equal(t, first, src.bases[second])
+	assert.Greater(t, len(src.bases), 3)
+	assert.Equal(t, src.nFetch, len(src.bases))
+	baseSet := make(hash.HashSet)
+	for _, base := range src.bases {
+		baseSet.Insert(base)
+	}
+	absent, err := sinkSt.HasMany(ctx, baseSet)
+	require.NoError(t, err)
+	assert.Empty(t, absent)
+
+	eq, err := pullerAddrEquality(ctx, second, second, vs, sinkvs)
+	require.NoError(t, err)
+	assert.True(t, eq)
+}
+
+func TestPairAddrs(t *testing.T) {
+	var a, b, c, d, e, x, y hash.Hash
+	for i, h := range []*hash.Hash{&a, &b, &c, &d, &e, &x, &y} {
+		h[0] = byte(i + 1)
+	}
+	pairs := func(addrs, baseAddrs []hash.Hash) map[hash.Hash]hash.Hash {
+		ret := make(map[hash.Hash]hash.Hash)
+		pairAddrs(addrs, baseAddrs, func(h, base hash.Hash) { ret[h] = base })
+		return ret
+	}
+
+	assert.Empty(t, pairs([]hash.Hash{a, b}, nil))
+	assert.Empty(t, pairs([]hash.Hash{a, b}, []hash.Hash{a, b}))
+	assert.Equal(t, map[hash.Hash]hash.Hash{x: b}, pairs([]hash.Hash{a, x, c}, []hash.Hash{a, b, c}))
+	// A split child only misaligns the children around it.
+	assert.Equal(t, map[hash.Hash]hash.Hash{x: b, y: b}, pairs([]hash.Hash{a, x, y, c, d}, []hash.Hash{a, b, c, d}))
+	// A removed child leaves the children after it aligned.
+	assert.Equal(t, map[hash.Hash]hash.Hash{x: c}, pairs([]hash.Hash{a, b, x, e}, []hash.Hash{a, b, c, d, e}))
+}
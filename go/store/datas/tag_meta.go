@@ -119,9 +119,9 @@ func (tm *TagMeta) toNomsStruct(nbf *types.NomsBinFormat) (types.Struct, error)
 	return types.NewStruct(nbf, tagMetaStName, metadata)
 }
 
-// Time returns the time at which the tag occurred
+// Time returns the time at which the tag occurred, which is the date given when it was created if there was one
 func (tm *TagMeta) Time() time.Time {
-	return time.UnixMilli(int64(tm.Timestamp))
+	return time.UnixMilli(tm.UserTimestamp)
 }
 
 // FormatTS takes the internal timestamp and turns it into a human readable string in the time.RubyDate format
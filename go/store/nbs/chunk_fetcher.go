@@ -17,6 +17,7 @@ package nbs
 import (
 	"context"
 
+	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
@@ -50,3 +51,18 @@ type ChunkFetcher interface {
 
 	Close() error
 }
+
+// DeltaBases can be supplied by the caller of a ChunkFetcher when it already
+// has chunks which are likely to be similar to the ones it is fetching, for
+// example the nodes of an earlier version of a tree it is fetching a newer
+// version of. A ChunkFetcher which fetches over the network can use them to
+// fetch chunks as deltas against the chunks the caller already has.
+type DeltaBases interface {
+	// DeltaBase returns the address of a chunk the caller has which is
+	// likely to be similar to the chunk at |h|.
+	DeltaBase(h hash.Hash) (hash.Hash, bool)
+
+	// GetDeltaBase returns the contents of a chunk previously returned
+	// from |DeltaBase|. Returns an empty chunk if it is not available.
+	GetDeltaBase(ctx context.Context, h hash.Hash) (chunks.Chunk, error)
+}
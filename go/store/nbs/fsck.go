@@ -0,0 +1,390 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// quarantineDirName is the name of the directory, inside the store directory, that table files removed by
+// QuarantineTableFiles are moved to.
+const quarantineDirName = "quarantine"
+
+// FsckProblem is a problem with the contents of a NomsBlockStore found by Fsck.
+type FsckProblem struct {
+	// TableFile is the name of the table file, archive or chunk journal which has the problem
+	TableFile hash.Hash
+	// Chunk is the address of the corrupt chunk, or the zero hash if the problem is not with a single chunk
+	Chunk hash.Hash
+	// Msg describes the problem
+	Msg string
+}
+
+func (p FsckProblem) String() string {
+	file := "table file " + p.TableFile.String()
+	if p.IsJournal() {
+		file = "chunk journal"
+	}
+	if p.Chunk.IsEmpty() {
+		return fmt.Sprintf("%s: %s", file, p.Msg)
+	}
+	return fmt.Sprintf("%s: chunk %s: %s", file, p.Chunk.String(), p.Msg)
+}
+
+// IsJournal returns whether the problem was found in the chunk journal.
+func (p FsckProblem) IsJournal() bool {
+	return p.TableFile == journalAddr
+}
+
+// Fsck checks the integrity of every table file in the manifest of this store. It checks that each table file in the
+// manifest has been loaded with the number of chunks the manifest records, and reads every chunk in every table
+// file, archive and chunk journal, checking that its data has not been corrupted. |cb| is called with each problem
+// found. The number of chunks read is returned.
+//
+// Fsck does not check that the chunks referenced by other chunks are present in the store.
+func (nbs *NomsBlockStore) Fsck(ctx context.Context, cb func(FsckProblem) error) (int, error) {
+	nbs.mu.Lock()
+	defer nbs.mu.Unlock()
+
+	css, err := nbs.chunkSourcesByAddr()
+	if err != nil {
+		return 0, err
+	}
+
+	chunkCount := 0
+	for _, spec := range nbs.upstream.specs {
+		cs, ok := css[spec.name]
+		if !ok {
+			if err = cb(FsckProblem{TableFile: spec.name, Msg: "table file in manifest was not loaded"}); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		var n int
+		switch src := cs.(type) {
+		case journalChunkSource:
+			n, err = nbs.fsckJournal(ctx, cb)
+		case archiveChunkSource:
+			n, err = fsckArchive(ctx, src, cb)
+		default:
+			n, err = fsckTableFile(ctx, cs, nbs.stats, cb)
+		}
+		if err != nil {
+			return 0, err
+		}
+		chunkCount += n
+
+		if spec.name == journalAddr {
+			// the chunk count of the journal is not kept up to date in the manifest
+			continue
+		}
+		cnt, err := cs.count()
+		if err != nil {
+			return 0, err
+		}
+		if cnt != spec.chunkCount {
+			msg := fmt.Sprintf("manifest records %d chunks, but table file has %d", spec.chunkCount, cnt)
+			if err = cb(FsckProblem{TableFile: spec.name, Msg: msg}); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return chunkCount, nil
+}
+
+// fsckTableFile reads every chunk in the table file |cs|, calling |cb| with each one that can't be read or whose data
+// doesn't match its address.
+func fsckTableFile(ctx context.Context, cs chunkSource, stats *Stats, cb func(FsckProblem) error) (int, error) {
+	idx, err := cs.index()
+	if err != nil {
+		return 0, err
+	}
+
+	cnt := idx.chunkCount()
+	for i := uint32(0); i < cnt; i++ {
+		var addr hash.Hash
+		if _, err = idx.indexEntry(i, &addr); err != nil {
+			return 0, err
+		}
+		if _, msg := readVerifiedChunk(ctx, cs, addr, stats); msg != "" {
+			if err = cb(FsckProblem{TableFile: cs.hash(), Chunk: addr, Msg: msg}); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return int(cnt), nil
+}
+
+// readVerifiedChunk reads the chunk |addr| from |cs|. If it can't be read or its data doesn't match its address, a
+// description of the problem is returned instead of its data.
+func readVerifiedChunk(ctx context.Context, cs chunkSource, addr hash.Hash, stats *Stats) ([]byte, string) {
+	data, err := cs.get(ctx, addr, stats)
+	if err != nil {
+		return nil, fmt.Sprintf("unable to read chunk: %s", err.Error())
+	} else if data == nil {
+		return nil, "chunk is in the table file index, but could not be found"
+	} else if hash.Of(data) != addr {
+		return nil, "chunk data does not match its address"
+	}
+	return data, ""
+}
+
+// fsckArchive reads every chunk in the archive |acs|, calling |cb| with each one whose data doesn't match its address.
+// If the archive can't be read past some point, a single problem is reported for the archive.
+func fsckArchive(ctx context.Context, acs archiveChunkSource, cb func(FsckProblem) error) (int, error) {
+	n := 0
+	var cbErr error
+	err := acs.iterate(ctx, func(c chunks.Chunk) error {
+		n++
+		if hash.Of(c.Data()) != c.Hash() {
+			cbErr = cb(FsckProblem{TableFile: acs.hash(), Chunk: c.Hash(), Msg: "chunk data does not match its address"})
+			return cbErr
+		}
+		return nil
+	})
+	if cbErr != nil {
+		return 0, cbErr
+	} else if err != nil {
+		msg := fmt.Sprintf("unable to read archive after %d chunks: %s", n, err.Error())
+		if err = cb(FsckProblem{TableFile: acs.hash(), Msg: msg}); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// fsckJournal reads every record in the chunk journal of this store from disk, calling |cb| with each chunk whose
+// data doesn't match its address. The journal is preallocated with zeros, so any non-zero bytes after the last
+// readable record are reported as a problem; they hold records which were corrupted or only partially written.
+func (nbs *NomsBlockStore) fsckJournal(ctx context.Context, cb func(FsckProblem) error) (int, error) {
+	j := nbs.ChunkJournal()
+	if j == nil {
+		return 0, errors.New("store has a chunk journal in its manifest, but is not using a chunk journal")
+	}
+
+	f, err := os.Open(j.path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	n := 0
+	off, err := processJournalRecords(ctx, f, 0, func(o int64, r journalRec) error {
+		if r.kind != chunkJournalRecKind {
+			return nil
+		}
+		n++
+		addr := hash.Hash(r.address)
+		msg := ""
+		cc, err := NewCompressedChunk(addr, r.payload)
+		if err == nil {
+			var c chunks.Chunk
+			if c, err = cc.ToChunk(); err == nil && hash.Of(c.Data()) != addr {
+				msg = "chunk data does not match its address"
+			}
+		}
+		if err != nil {
+			msg = fmt.Sprintf("unable to read chunk at offset %d: %s", o, err.Error())
+		}
+		if msg != "" {
+			return cb(FsckProblem{TableFile: journalAddr, Chunk: addr, Msg: msg})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	unreadable, err := countNonZeroBytes(f)
+	if err != nil {
+		return 0, err
+	}
+	if unreadable > 0 {
+		msg := fmt.Sprintf("journal has %d bytes of unreadable data after offset %d", unreadable, off)
+		if err = cb(FsckProblem{TableFile: journalAddr, Msg: msg}); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// countNonZeroBytes returns the number of non-zero bytes between the current offset of |r| and its end.
+func countNonZeroBytes(r io.Reader) (int, error) {
+	buf := make([]byte, 64*1024)
+	cnt := 0
+	for {
+		n, err := r.Read(buf)
+		cnt += n - bytes.Count(buf[:n], []byte{0})
+		if err == io.EOF {
+			return cnt, nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// QuarantineTableFiles removes the table files and archives |names| from this store, moving them to a quarantine
+// directory inside the store directory. The chunks in them which can still be read and verified are first copied to
+// a new table file, which replaces them in the manifest. It returns the number of chunks which could not be salvaged.
+//
+// The chunk journal cannot be quarantined, and neither can the table files of a store with a manifest appendix.
+func (nbs *NomsBlockStore) QuarantineTableFiles(ctx context.Context, names hash.HashSet) (int, error) {
+	if names.Size() == 0 {
+		return 0, nil
+	}
+	if names.Has(journalAddr) {
+		return 0, errors.New("the chunk journal cannot be quarantined")
+	}
+	dir, ok := nbs.Path()
+	if !ok {
+		return 0, errors.New("table files can only be quarantined in a local store")
+	}
+
+	mt, lost, specs, err := nbs.salvageTableFiles(ctx, names)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(mt.chunks) > 0 {
+		// salvaged chunks are always written to a table file, even if the store has a chunk journal
+		p := nbs.p
+		if j, ok := p.(*ChunkJournal); ok {
+			p = j.persister
+		}
+		cs, err := p.Persist(ctx, mt, nil, nbs.stats)
+		if err != nil {
+			return 0, err
+		}
+		cnt, err := cs.count()
+		if err != nil {
+			return 0, err
+		}
+		specs = append(specs, tableSpec{name: cs.hash(), chunkCount: cnt})
+		if err = cs.close(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = nbs.swapTables(ctx, specs); err != nil {
+		return 0, err
+	}
+
+	quarantineDir := filepath.Join(dir, quarantineDirName)
+	if err = os.MkdirAll(quarantineDir, os.ModePerm); err != nil {
+		return 0, err
+	}
+	for name := range names {
+		for _, fileName := range []string{name.String(), name.String() + archiveFileSuffix} {
+			err = os.Rename(filepath.Join(dir, fileName), filepath.Join(quarantineDir, fileName))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return 0, err
+			}
+		}
+	}
+
+	return lost, nil
+}
+
+// salvageTableFiles reads every chunk which can be read and verified in the table files |names| into a memTable. It
+// returns the memTable, the number of chunks which could not be salvaged, and the specs of the manifest without
+// |names|.
+func (nbs *NomsBlockStore) salvageTableFiles(ctx context.Context, names hash.HashSet) (*memTable, int, []tableSpec, error) {
+	nbs.mu.Lock()
+	defer nbs.mu.Unlock()
+
+	if len(nbs.upstream.appendix) > 0 {
+		return nil, 0, nil, errors.New("table files cannot be quarantined in a store with a manifest appendix")
+	}
+
+	css, err := nbs.chunkSourcesByAddr()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var specs []tableSpec
+	var salvaged []chunks.Chunk
+	size, lost := uint64(0), 0
+	for _, spec := range nbs.upstream.specs {
+		if !names.Has(spec.name) {
+			specs = append(specs, spec)
+			continue
+		}
+		cs, ok := css[spec.name]
+		if !ok {
+			return nil, 0, nil, fmt.Errorf("table file %s is not in the manifest", spec.name.String())
+		}
+
+		add := func(c chunks.Chunk) {
+			salvaged = append(salvaged, c)
+			size += uint64(len(c.Data()))
+		}
+		var n int
+		if acs, ok := cs.(archiveChunkSource); ok {
+			err = acs.iterate(ctx, func(c chunks.Chunk) error {
+				n++
+				if hash.Of(c.Data()) == c.Hash() {
+					add(c)
+				} else {
+					lost++
+				}
+				return nil
+			})
+			// everything after the point the archive can't be read from is lost
+			if err != nil {
+				cnt, err := acs.count()
+				if err != nil {
+					return nil, 0, nil, err
+				}
+				lost += int(cnt) - n
+			}
+		} else {
+			idx, err := cs.index()
+			if err != nil {
+				return nil, 0, nil, err
+			}
+			for i := uint32(0); i < idx.chunkCount(); i++ {
+				var addr hash.Hash
+				if _, err = idx.indexEntry(i, &addr); err != nil {
+					return nil, 0, nil, err
+				}
+				data, msg := readVerifiedChunk(ctx, cs, addr, nbs.stats)
+				if msg != "" {
+					lost++
+					continue
+				}
+				add(chunks.NewChunkWithHash(addr, data))
+			}
+		}
+	}
+
+	mt := newMemTable(size + 1)
+	for _, c := range salvaged {
+		if res := mt.addChunk(c.Hash(), c.Data()); res == chunkNotAdded {
+			return nil, 0, nil, errors.New("unable to salvage chunks: table file too large")
+		}
+	}
+	return mt, lost, specs, nil
+}
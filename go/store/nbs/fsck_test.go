@@ -0,0 +1,89 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestFsckAndQuarantine(t *testing.T) {
+	ctx := context.Background()
+	st, nomsDir, _ := makeTestLocalStore(t, defaultMaxTables)
+	defer st.Close()
+	populateLocalStore(t, st, 4)
+
+	fsck := func() ([]FsckProblem, int) {
+		var problems []FsckProblem
+		n, err := st.Fsck(ctx, func(p FsckProblem) error {
+			problems = append(problems, p)
+			return nil
+		})
+		require.NoError(t, err)
+		return problems, n
+	}
+
+	problems, n := fsck()
+	assert.Empty(t, problems)
+	assert.Equal(t, 1+2+3+4, n)
+
+	_, sources, _, err := st.Sources(ctx)
+	require.NoError(t, err)
+	var corrupted string
+	for _, src := range sources {
+		if src.NumChunks() == 4 {
+			corrupted = src.FileID()
+		}
+	}
+	require.NotEmpty(t, corrupted)
+
+	// the data of the first chunk in the table file starts at offset 0
+	f, err := os.OpenFile(filepath.Join(nomsDir, corrupted), os.O_RDWR, 0)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, 0)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	problems, _ = fsck()
+	require.Len(t, problems, 1)
+	assert.Equal(t, corrupted, problems[0].TableFile.String())
+	assert.False(t, problems[0].Chunk.IsEmpty())
+	assert.False(t, problems[0].IsJournal())
+	corruptChunk := problems[0].Chunk
+
+	lost, err := st.QuarantineTableFiles(ctx, hash.NewHashSet(problems[0].TableFile))
+	require.NoError(t, err)
+	assert.Equal(t, 1, lost)
+
+	problems, n = fsck()
+	assert.Empty(t, problems)
+	// the three intact chunks were salvaged to a new table file
+	assert.Equal(t, 1+2+3+3, n)
+	ok, err := st.Has(ctx, corruptChunk)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = os.Stat(filepath.Join(nomsDir, quarantineDirName, corrupted))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(nomsDir, corrupted))
+	assert.True(t, os.IsNotExist(err))
+}
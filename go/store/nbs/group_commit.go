@@ -0,0 +1,114 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"runtime/trace"
+	"sync"
+)
+
+type groupCommitKey struct{}
+
+// GroupCommit collects the chunk journal syncs which were deferred by commits made with a context returned from
+// WithGroupCommit.
+//
+// Committing a new root hash to a chunk journal normally syncs the journal file before the commit returns, while
+// holding the locks of the store. Under a group commit, the root hash record is written to the journal file without
+// being synced, and the sync is done in Wait, after the caller has released its own locks. Concurrent committers
+// which reach Wait together share a single sync of the journal: whichever syncs first makes durable every record
+// written before it, and the others return without syncing again.
+//
+// A root hash committed under a group commit becomes the root of the store as soon as it is written, before Wait
+// syncs it, so that the commits which follow can be built on it without waiting for the sync. Until Wait returns,
+// readers of the store can see a root which is not yet durable: if the process or the machine crashes first, the
+// root they saw is lost, along with every root committed after it. The commit itself hasn't been reported as durable
+// yet, since its caller is still waiting, and neither has any commit built on top of it, since those are written
+// later in the same journal and can't be made durable without it. If Wait fails, the root stays visible but its
+// durability is unknown, and the caller must report its commit as failed.
+type GroupCommit struct {
+	mu      sync.Mutex
+	pending map[*journalWriter]int64
+}
+
+// WithGroupCommit returns a context under which commits to chunk journals defer syncing the journal to the returned
+// GroupCommit. Callers must call Wait before reporting the commits as durable.
+func WithGroupCommit(ctx context.Context) (context.Context, *GroupCommit) {
+	gc := &GroupCommit{pending: make(map[*journalWriter]int64)}
+	return context.WithValue(ctx, groupCommitKey{}, gc), gc
+}
+
+func groupCommitFromContext(ctx context.Context) *GroupCommit {
+	gc, _ := ctx.Value(groupCommitKey{}).(*GroupCommit)
+	return gc
+}
+
+// deferSync records that |wr| must be synced through offset |off|.
+func (gc *GroupCommit) deferSync(wr *journalWriter, off int64) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if off > gc.pending[wr] {
+		gc.pending[wr] = off
+	}
+}
+
+// Wait syncs every chunk journal written to under this GroupCommit, returning once the commits made under it are
+// durable.
+func (gc *GroupCommit) Wait(ctx context.Context) error {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	for wr, off := range gc.pending {
+		if err := wr.syncTo(ctx, off); err != nil {
+			return err
+		}
+		delete(gc.pending, wr)
+	}
+	return nil
+}
+
+// syncTo syncs the journal file, if it has not already been synced through |off|. Only one sync runs at a time;
+// callers which queue behind it find their records already synced.
+func (wr *journalWriter) syncTo(ctx context.Context, off int64) error {
+	wr.syncLock.Lock()
+	defer wr.syncLock.Unlock()
+	if wr.synced.Load() >= off {
+		return nil
+	}
+
+	wr.lock.RLock()
+	journal, end := wr.journal, wr.off
+	wr.lock.RUnlock()
+	if journal == nil {
+		// the journal is synced when it is closed
+		return nil
+	}
+
+	defer trace.StartRegion(ctx, "sync").End()
+	if err := journal.Sync(); err != nil {
+		return err
+	}
+	wr.advanceSynced(end)
+	return nil
+}
+
+// advanceSynced records that the journal file is synced through |off|.
+func (wr *journalWriter) advanceSynced(off int64) {
+	for {
+		synced := wr.synced.Load()
+		if synced >= off || wr.synced.CompareAndSwap(synced, off) {
+			return
+		}
+	}
+}
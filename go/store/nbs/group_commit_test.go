@@ -0,0 +1,158 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/file"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestGroupCommit(t *testing.T) {
+	cacheOnce.Do(makeGlobalCaches)
+	ctx := context.Background()
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { file.RemoveAll(dir) })
+
+	nbf := types.Format_Default.VersionString()
+	st, err := NewLocalJournalingStore(ctx, nbf, dir, NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+
+	t.Run("sync is deferred until Wait", func(t *testing.T) {
+		gcCtx, gc := WithGroupCommit(ctx)
+		last, err := st.Root(ctx)
+		require.NoError(t, err)
+		var root hash.Hash
+		for h, c := range makeChunkSet(8, 64) {
+			require.NoError(t, st.Put(gcCtx, c, noopGetAddrs))
+			root = h
+		}
+		ok, err := st.Commit(gcCtx, root, last)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		wr := st.ChunkJournal().wr
+		off := wr.currentSize()
+		assert.Less(t, wr.synced.Load(), off)
+		require.NoError(t, gc.Wait(ctx))
+		assert.Equal(t, off, wr.synced.Load())
+	})
+
+	t.Run("readers see the root before it is synced", func(t *testing.T) {
+		gcCtx, gc := WithGroupCommit(ctx)
+		last, err := st.Root(ctx)
+		require.NoError(t, err)
+		var root hash.Hash
+		for h, c := range makeChunkSet(8, 64) {
+			require.NoError(t, st.Put(gcCtx, c, noopGetAddrs))
+			root = h
+		}
+		ok, err := st.Commit(gcCtx, root, last)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// the root isn't durable until Wait returns, but it is already the root of the store
+		wr := st.ChunkJournal().wr
+		assert.Less(t, wr.synced.Load(), wr.currentSize())
+		actual, err := st.Root(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, root, actual)
+		has, err := st.Has(ctx, root)
+		require.NoError(t, err)
+		assert.True(t, has)
+		require.NoError(t, gc.Wait(ctx))
+	})
+
+	t.Run("commits outside a group commit are synced", func(t *testing.T) {
+		last, err := st.Root(ctx)
+		require.NoError(t, err)
+		var root hash.Hash
+		for h, c := range makeChunkSet(8, 64) {
+			require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+			root = h
+		}
+		ok, err := st.Commit(ctx, root, last)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		wr := st.ChunkJournal().wr
+		assert.Equal(t, wr.currentSize(), wr.synced.Load())
+	})
+
+	t.Run("concurrent commits", func(t *testing.T) {
+		// |mu| serializes commits, as transactions do
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		errs := make([]error, 16)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				gcCtx, gc := WithGroupCommit(ctx)
+				errs[i] = func() error {
+					mu.Lock()
+					defer mu.Unlock()
+					last, err := st.Root(gcCtx)
+					if err != nil {
+						return err
+					}
+					var root hash.Hash
+					for h, c := range makeChunkSet(4, 64) {
+						if err = st.Put(gcCtx, c, noopGetAddrs); err != nil {
+							return err
+						}
+						root = h
+					}
+					ok, err := st.Commit(gcCtx, root, last)
+					if err != nil {
+						return err
+					} else if !ok {
+						return assert.AnError
+					}
+					return nil
+				}()
+				if errs[i] == nil {
+					errs[i] = gc.Wait(ctx)
+				}
+			}(i)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		wr := st.ChunkJournal().wr
+		assert.Equal(t, wr.currentSize(), wr.synced.Load())
+	})
+
+	root, err := st.Root(ctx)
+	require.NoError(t, err)
+	require.NoError(t, st.Close())
+
+	st, err = NewLocalJournalingStore(ctx, nbf, dir, NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	defer st.Close()
+	actual, err := st.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, root, actual)
+}
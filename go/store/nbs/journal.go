@@ -66,6 +66,9 @@ type ChunkJournal struct {
 	// reflogRingBuffer holds the most recent roots written to the chunk journal so that they can be
 	// quickly loaded for reflog queries without having to re-read the journal file from disk.
 	reflogRingBuffer *reflogRingBuffer
+
+	// compaction tracks the background compaction of the journal once it grows past |journalMaxSize|.
+	compaction journalCompaction
 }
 
 var _ tablePersister = &ChunkJournal{}
@@ -82,6 +85,7 @@ func newChunkJournal(ctx context.Context, nbfVers, dir string, m *journalManifes
 
 	j := &ChunkJournal{path: path, backing: m, persister: p}
 	j.contents.nbfVers = nbfVers
+	j.compaction.ctx, j.compaction.cancel = context.WithCancel(context.Background())
 	j.reflogRingBuffer = newReflogRingBuffer(reflogBufferSize())
 
 	ok, err := fileExists(path)
@@ -355,7 +359,13 @@ func (j *ChunkJournal) Update(ctx context.Context, lastLock hash.Hash, next mani
 		}
 	}
 
-	if err := j.wr.commitRootHash(ctx, next.root); err != nil {
+	if gc := groupCommitFromContext(ctx); gc != nil {
+		off, err := j.wr.writeRootHash(ctx, next.root)
+		if err != nil {
+			return manifestContents{}, err
+		}
+		gc.deferSync(j.wr, off)
+	} else if err := j.wr.commitRootHash(ctx, next.root); err != nil {
 		return manifestContents{}, err
 	}
 	j.contents = next
@@ -464,6 +474,7 @@ func (j *ChunkJournal) maybeInit(ctx context.Context) (err error) {
 
 // Close implements io.Closer
 func (j *ChunkJournal) Close() (err error) {
+	j.compaction.stop()
 	if j.wr != nil {
 		err = j.wr.Close()
 		// flush the latest root to the backing manifest
@@ -173,7 +173,7 @@ func (s journalChunkSource) reader(ctx context.Context) (io.ReadCloser, uint64,
 
 func (s journalChunkSource) getRecordRanges(ctx context.Context, requests []getRecord) (map[hash.Hash]Range, error) {
 	ranges := make(map[hash.Hash]Range, len(requests))
-	for _, req := range requests {
+	for i, req := range requests {
 		if req.found {
 			continue
 		}
@@ -183,7 +183,7 @@ func (s journalChunkSource) getRecordRanges(ctx context.Context, requests []getR
 		} else if !ok {
 			continue
 		}
-		req.found = true // update |requests|
+		requests[i].found = true // update |requests|
 		ranges[hash.Hash(*req.a)] = rng
 	}
 	return ranges, nil
@@ -0,0 +1,376 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// journalMaxSize is the size, in bytes, that the chunk journal can grow to before it is rotated. When a commit leaves
+// the journal larger than this, the journal is compacted in the background: the chunks it holds are copied to a new
+// table file, and the journal is replaced with one holding only the records written since compaction began. A value
+// of 0 disables rotation. This is controlled by the DOLT_JOURNAL_MAX_SIZE env var and is ONLY written to during
+// initialization.
+var journalMaxSize int64 = 0
+
+// rotatedJournalSuffix is the suffix of the file a rotated chunk journal is written to before it replaces the
+// current journal.
+const rotatedJournalSuffix = ".rotate"
+
+func init() {
+	if v := os.Getenv(dconfig.EnvJournalMaxSize); v != "" {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || i < 0 {
+			logrus.Warnf("unable to parse a size in bytes for %s from %s", dconfig.EnvJournalMaxSize, v)
+		} else {
+			journalMaxSize = i
+		}
+	}
+}
+
+// journalCompaction is the state of the background compaction of a ChunkJournal.
+type journalCompaction struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex // protects the following state
+	running bool
+	closed  bool
+	count   uint64
+	last    time.Time
+}
+
+// stop cancels any running compaction and waits for it to finish. No compactions are started after stop is called.
+func (c *journalCompaction) stop() {
+	c.mu.Lock()
+	c.closed = true
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.mu.Unlock()
+	c.wg.Wait()
+}
+
+// JournalStats describes the size of a chunk journal and the progress of its background compaction.
+type JournalStats struct {
+	// Size is the number of bytes of records in the journal
+	Size int64
+	// MaxSize is the size the journal is rotated at, or 0 if rotation is disabled
+	MaxSize int64
+	// CompactionLag is the number of bytes by which the journal exceeds MaxSize, which compaction has yet to
+	// remove
+	CompactionLag int64
+	// Compacting is true while a compaction is running
+	Compacting bool
+	// Compactions is the number of compactions which have completed since the journal was opened
+	Compactions uint64
+	// LastCompaction is the time the last compaction completed, or the zero time if there hasn't been one
+	LastCompaction time.Time
+}
+
+// JournalStats returns the current JournalStats of this ChunkJournal.
+func (j *ChunkJournal) JournalStats() JournalStats {
+	j.compaction.mu.Lock()
+	defer j.compaction.mu.Unlock()
+
+	stats := JournalStats{
+		MaxSize:        journalMaxSize,
+		Compacting:     j.compaction.running,
+		Compactions:    j.compaction.count,
+		LastCompaction: j.compaction.last,
+	}
+	if wr := j.wr; wr != nil {
+		stats.Size = wr.currentSize()
+	}
+	if stats.MaxSize > 0 && stats.Size > stats.MaxSize {
+		stats.CompactionLag = stats.Size - stats.MaxSize
+	}
+	return stats
+}
+
+// JournalStats returns the JournalStats of the chunk journal of this store, if it has one.
+func (nbs *NomsBlockStore) JournalStats() (JournalStats, bool) {
+	nbs.mu.RLock()
+	defer nbs.mu.RUnlock()
+	j := nbs.ChunkJournal()
+	if j == nil {
+		return JournalStats{}, false
+	}
+	return j.JournalStats(), true
+}
+
+// maybeCompactJournal starts a background compaction of the chunk journal if it has grown larger than
+// |journalMaxSize| and one is not already running. Callers must hold |nbs.mu|.
+func (nbs *NomsBlockStore) maybeCompactJournal() {
+	j := nbs.ChunkJournal()
+	if j == nil || j.wr == nil || journalMaxSize <= 0 || nbs.gcInProgress {
+		return
+	}
+	if j.wr.currentSize() <= journalMaxSize {
+		return
+	}
+
+	j.compaction.mu.Lock()
+	defer j.compaction.mu.Unlock()
+	if j.compaction.running || j.compaction.closed {
+		return
+	}
+	j.compaction.running = true
+	j.compaction.wg.Add(1)
+
+	go func() {
+		defer j.compaction.wg.Done()
+		err := nbs.compactJournal(j.compaction.ctx, j)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logrus.Warnf("failed to compact chunk journal %s: %s", j.path, err.Error())
+		}
+
+		j.compaction.mu.Lock()
+		defer j.compaction.mu.Unlock()
+		j.compaction.running = false
+		if err == nil {
+			j.compaction.count++
+			j.compaction.last = time.Now()
+		}
+	}()
+}
+
+// compactJournal rotates the chunk journal |j| of this store. Every chunk in the journal is copied to a new table file
+// without holding any locks, and the table file is then added to the manifest. The journal is replaced with one
+// holding the records written since the copy began, along with the most recent root hash records so that the reflog
+// is preserved. If a garbage collection runs while the chunks are being copied, the compaction is abandoned.
+func (nbs *NomsBlockStore) compactJournal(ctx context.Context, j *ChunkJournal) error {
+	nbs.mu.Lock()
+	wr, gcGen := j.wr, nbs.upstream.gcGen
+	nbs.mu.Unlock()
+	if wr == nil {
+		return nil
+	}
+
+	end, err := wr.flushedOffset(ctx)
+	if err != nil {
+		return err
+	}
+	specs, roots, err := copyJournalChunks(ctx, wr.path, end, j.persister)
+	if err != nil {
+		return err
+	} else if len(specs) == 0 {
+		return nil
+	}
+
+	nbs.mu.Lock()
+	defer nbs.mu.Unlock()
+
+	// the table file is left behind to be pruned by the next garbage collection
+	if nbs.gcInProgress || nbs.upstream.gcGen != gcGen || j.wr != wr {
+		return nil
+	} else if len(nbs.upstream.appendix) > 0 {
+		return errors.New("chunk journal cannot be compacted in a store with a manifest appendix")
+	}
+
+	rotated := wr.path + rotatedJournalSuffix
+	if err = wr.writeRotatedJournal(ctx, rotated, roots, end); err != nil {
+		_ = os.Remove(rotated)
+		return err
+	}
+
+	// the manifest includes both the new table file and the current journal until the journal is replaced
+	contents := nbs.upstream
+	contents.specs = append(append([]tableSpec{}, contents.specs...), specs...)
+	contents.lock = generateLockHash(contents.root, contents.specs, contents.appendix)
+	if err = j.flushToBackingManifest(ctx, contents, nbs.stats); err != nil {
+		_ = os.Remove(rotated)
+		return err
+	}
+	j.contents, nbs.upstream = contents, contents
+
+	if err = j.replaceJournal(ctx, rotated); err != nil {
+		return err
+	}
+	nbs.upstream = j.contents
+
+	upstream := make(chunkSourceSet, len(nbs.tables.upstream)+len(specs))
+	for a, cs := range nbs.tables.upstream {
+		upstream[a] = cs
+	}
+	novel := make(chunkSourceSet, len(nbs.tables.novel))
+	for a, cs := range nbs.tables.novel {
+		novel[a] = cs
+	}
+	for _, css := range []chunkSourceSet{upstream, novel} {
+		if _, ok := css[journalAddr]; ok {
+			css[journalAddr] = journalChunkSource{journal: j.wr}
+		}
+	}
+	for _, spec := range specs {
+		cs, err := j.persister.Open(ctx, spec.name, spec.chunkCount, nbs.stats)
+		if err != nil {
+			return err
+		}
+		upstream[spec.name] = cs
+	}
+	nbs.tables = tableSet{
+		novel:    novel,
+		upstream: upstream,
+		p:        nbs.tables.p,
+		q:        nbs.tables.q,
+		rl:       nbs.tables.rl,
+	}
+	return nil
+}
+
+// copyJournalChunks copies every chunk in the first |end| bytes of the chunk journal at |path| to a new table file
+// persisted by |p|. It returns the specs of the table files written, and the most recent root hash records read from
+// the journal.
+func copyJournalChunks(ctx context.Context, path string, end int64, p tableFilePersister) ([]tableSpec, [][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	gcc, err := newGarbageCollectionCopier()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keepRoots := reflogBufferSize()
+	if keepRoots < 1 {
+		keepRoots = 1
+	}
+	var roots [][]byte
+	copied := hash.NewHashSet()
+	off, err := processJournalRecords(ctx, io.NewSectionReader(f, 0, end), 0, func(o int64, r journalRec) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		switch r.kind {
+		case chunkJournalRecKind:
+			if copied.Has(r.address) {
+				return nil
+			}
+			cc, err := NewCompressedChunk(r.address, append([]byte{}, r.payload...))
+			if err != nil {
+				return err
+			}
+			copied.Insert(r.address)
+			return gcc.addChunk(ctx, cc)
+		case rootHashJournalRecKind:
+			rec := make([]byte, r.length)
+			if _, err := f.ReadAt(rec, o); err != nil {
+				return err
+			}
+			roots = append(roots, rec)
+			if len(roots) > 2*keepRoots {
+				roots = append([][]byte{}, roots[len(roots)-keepRoots:]...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	} else if off != end {
+		return nil, nil, fmt.Errorf("unable to read chunk journal past offset %d", off)
+	}
+	if len(roots) > keepRoots {
+		roots = roots[len(roots)-keepRoots:]
+	}
+
+	specs, err := gcc.copyTablesToDir(ctx, p)
+	if err != nil {
+		return nil, nil, err
+	}
+	return specs, roots, nil
+}
+
+// flushedOffset flushes any buffered records to the journal file, and returns the offset of the end of the last
+// record.
+func (wr *journalWriter) flushedOffset(ctx context.Context) (int64, error) {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	if err := wr.flush(ctx); err != nil {
+		return 0, err
+	}
+	return wr.off, nil
+}
+
+// writeRotatedJournal writes a new journal file at |path| holding the root hash records |roots| followed by every
+// record written to this journal after |start|.
+func (wr *journalWriter) writeRotatedJournal(ctx context.Context, path string, roots [][]byte, start int64) (err error) {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	if err = wr.flush(ctx); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	for _, rec := range roots {
+		if _, err = f.Write(rec); err != nil {
+			return err
+		}
+	}
+	if _, err = io.Copy(f, io.NewSectionReader(wr.journal, start, wr.off-start)); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// replaceJournal closes the current journal and replaces it with the journal file at |path|, which is then opened
+// and bootstrapped in its place.
+func (j *ChunkJournal) replaceJournal(ctx context.Context, path string) error {
+	if err := j.wr.Close(); err != nil {
+		return err
+	}
+	j.wr = nil
+
+	// the journal index holds offsets into the current journal
+	err := os.Remove(filepath.Join(filepath.Dir(j.path), journalIndexFileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err = os.Rename(path, j.path); err != nil {
+		// reopen the current journal
+		if berr := j.bootstrapJournalWriter(ctx); berr != nil {
+			return fmt.Errorf("%w; unable to reopen chunk journal: %s", err, berr.Error())
+		}
+		return err
+	}
+
+	j.reflogRingBuffer.Truncate()
+	return j.bootstrapJournalWriter(ctx)
+}
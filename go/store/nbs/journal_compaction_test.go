@@ -0,0 +1,105 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/file"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestChunkJournalCompaction(t *testing.T) {
+	cacheOnce.Do(makeGlobalCaches)
+	ctx := context.Background()
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { file.RemoveAll(dir) })
+
+	prev := journalMaxSize
+	journalMaxSize = 8 * 1024
+	t.Cleanup(func() { journalMaxSize = prev })
+
+	nbf := types.Format_Default.VersionString()
+	st, err := NewLocalJournalingStore(ctx, nbf, dir, NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+
+	waitForCompaction := func() JournalStats {
+		for {
+			stats, ok := st.JournalStats()
+			require.True(t, ok)
+			if !stats.Compacting {
+				return stats
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	all := make(map[hash.Hash]chunks.Chunk)
+	var roots []hash.Hash
+	last := hash.Hash{}
+	for i := 0; i < 8; i++ {
+		var root hash.Hash
+		for h, c := range makeChunkSet(16, 256) {
+			require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+			all[h] = c
+			root = h
+		}
+		ok, err := st.Commit(ctx, root, last)
+		require.NoError(t, err)
+		require.True(t, ok)
+		roots = append(roots, root)
+		last = root
+		waitForCompaction()
+	}
+
+	stats := waitForCompaction()
+	assert.Greater(t, stats.Compactions, uint64(0))
+	assert.Less(t, stats.Size, int64(2*journalMaxSize))
+
+	// every root committed since the journal was created is still in the reflog
+	var reflog []hash.Hash
+	require.NoError(t, st.ChunkJournal().IterateRoots(func(root string, _ *time.Time) error {
+		reflog = append(reflog, hash.Parse(root))
+		return nil
+	}))
+	assert.Equal(t, roots, reflog[len(reflog)-len(roots):])
+
+	verify := func(st *NomsBlockStore) {
+		for h, c := range all {
+			out, err := st.Get(ctx, h)
+			require.NoError(t, err)
+			assert.Equal(t, c, out)
+		}
+		root, err := st.Root(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, last, root)
+	}
+	verify(st)
+	require.NoError(t, st.Close())
+
+	st, err = NewLocalJournalingStore(ctx, nbf, dir, NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	defer st.Close()
+	verify(st)
+}
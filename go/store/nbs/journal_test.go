@@ -110,6 +110,14 @@ func TestReadRecordRanges(t *testing.T) {
 
 	ranges, err := jcs.getRecordRanges(ctx, gets)
 	require.NoError(t, err)
+	assert.Len(t, ranges, len(gets))
+	for _, get := range gets {
+		assert.True(t, get.found)
+	}
+	// records that were found aren't looked up again
+	again, err := jcs.getRecordRanges(ctx, gets)
+	require.NoError(t, err)
+	assert.Empty(t, again)
 
 	for h, rng := range ranges {
 		b, err := jcs.get(ctx, h, &Stats{})
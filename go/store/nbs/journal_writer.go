@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"runtime/trace"
 	"sync"
+	"sync/atomic"
 
 	"github.com/dolthub/swiss"
 	"github.com/sirupsen/logrus"
@@ -169,6 +170,11 @@ type journalWriter struct {
 	maxNovel    int
 
 	lock sync.RWMutex
+
+	// syncLock serializes syncs of the journal file made by syncTo, and |synced| is the offset through which the
+	// file is known to be synced, by syncTo or by commitRootHash
+	syncLock sync.Mutex
+	synced   atomic.Int64
 }
 
 var _ io.Closer = &journalWriter{}
@@ -484,6 +490,7 @@ func (wr *journalWriter) commitRootHashUnlocked(ctx context.Context, root hash.H
 	}
 
 	wr.unsyncd = 0
+	wr.advanceSynced(wr.off)
 	if wr.ranges.novelCount() > wr.maxNovel {
 		o := wr.offset() - int64(n) // pre-commit journal offset
 		if err := wr.flushIndexRecord(ctx, root, o); err != nil {
@@ -493,6 +500,29 @@ func (wr *journalWriter) commitRootHashUnlocked(ctx context.Context, root hash.H
 	return nil
 }
 
+// writeRootHash writes |root| to the journal without syncing the file, and returns the offset through which the
+// file must be synced for the write to be durable. If the journal index is due to be extended, the root hash is
+// committed and synced as by commitRootHash instead, and 0 is returned.
+func (wr *journalWriter) writeRootHash(ctx context.Context, root hash.Hash) (int64, error) {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+
+	if wr.ranges.novelCount() > wr.maxNovel {
+		return 0, wr.commitRootHashUnlocked(ctx, root)
+	}
+
+	buf, err := wr.getBytes(ctx, rootHashRecordSize())
+	if err != nil {
+		return 0, err
+	}
+	wr.currentRoot = root
+	_ = writeRootHashRecord(buf, root)
+	if err = wr.flush(ctx); err != nil {
+		return 0, err
+	}
+	return wr.off, nil
+}
+
 // flushIndexRecord writes metadata for a range of index lookups to the
 // out-of-band journal index file. Index records accelerate journal
 // bootstrapping by reducing the amount of the journal that must be processed.
@@ -627,6 +657,8 @@ func (wr *journalWriter) recordCount() uint32 {
 }
 
 func (wr *journalWriter) Close() (err error) {
+	wr.syncLock.Lock()
+	defer wr.syncLock.Unlock()
 	wr.lock.Lock()
 	defer wr.lock.Unlock()
 
@@ -0,0 +1,119 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// reflogArchiveName is the name of the file, next to the chunk journal, which holds reflog records that have been
+// archived before the roots they were read from were removed from the journal.
+const reflogArchiveName = "reflog"
+
+// ArchiveReflog durably appends |records| to the reflog archive of this ChunkJournal. The root hashes recorded in
+// the chunk journal are dropped when the journal is rewritten by garbage collection, so callers which want to keep
+// the history of their refs must archive it first. Records are opaque to the ChunkJournal, but must not contain
+// newlines. If the reflog has been disabled, nothing is written.
+//
+// If a previous write was interrupted, the partial record it left behind is terminated before |records| are
+// written, and will be returned by IterateReflogArchive as a record of its own.
+func (j *ChunkJournal) ArchiveReflog(records [][]byte) (err error) {
+	if reflogDisabled || len(records) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, r := range records {
+		if bytes.IndexByte(r, '\n') >= 0 {
+			return fmt.Errorf("invalid reflog archive record: records cannot contain newlines")
+		}
+		buf.Write(r)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(j.reflogArchivePath(), os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := f.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err = f.ReadAt(last, info.Size()-1); err != nil {
+			return err
+		}
+		if last[0] != '\n' {
+			if _, err = f.Write([]byte{'\n'}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err = f.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// IterateReflogArchive calls |f| with each record in the reflog archive of this ChunkJournal, from oldest to newest.
+// A partially written record at the end of the archive is ignored. If |f| returns an error, iteration is stopped and
+// the error is returned.
+func (j *ChunkJournal) IterateReflogArchive(f func(record []byte) error) error {
+	if reflogDisabled {
+		return nil
+	}
+
+	file, err := os.Open(j.reflogArchivePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rd := bufio.NewReader(file)
+	for {
+		line, err := rd.ReadBytes('\n')
+		if err == io.EOF {
+			// any trailing bytes are from an interrupted write
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = f(line[:len(line)-1]); err != nil {
+			return err
+		}
+	}
+}
+
+func (j *ChunkJournal) reflogArchivePath() string {
+	return filepath.Join(filepath.Dir(j.path), reflogArchiveName)
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReflogArchive(t *testing.T) {
+	j := makeTestChunkJournal(t)
+
+	readAll := func() []string {
+		var records []string
+		err := j.IterateReflogArchive(func(record []byte) error {
+			records = append(records, string(record))
+			return nil
+		})
+		require.NoError(t, err)
+		return records
+	}
+
+	assert.Empty(t, readAll())
+
+	require.NoError(t, j.ArchiveReflog([][]byte{[]byte("one"), []byte("two")}))
+	require.NoError(t, j.ArchiveReflog(nil))
+	require.NoError(t, j.ArchiveReflog([][]byte{[]byte("three")}))
+	assert.Equal(t, []string{"one", "two", "three"}, readAll())
+
+	err := j.ArchiveReflog([][]byte{[]byte("four\nfive")})
+	require.Error(t, err)
+	assert.Equal(t, []string{"one", "two", "three"}, readAll())
+
+	// simulate an interrupted write
+	f, err := os.OpenFile(j.reflogArchivePath(), os.O_APPEND|os.O_WRONLY, 0666)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("partial"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, []string{"one", "two", "three"}, readAll())
+
+	require.NoError(t, j.ArchiveReflog([][]byte{[]byte("four")}))
+	assert.Equal(t, []string{"one", "two", "three", "partial", "four"}, readAll())
+}
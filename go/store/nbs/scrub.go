@@ -0,0 +1,88 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+)
+
+// Scrub reads every chunk in the table files and archives of this store, calling |cb| with each one which can't be
+// read or whose data doesn't match its address. The number of chunks read is returned.
+//
+// Unlike Fsck, Scrub only holds the store's lock while it opens its own readers of the table files, so it can run in
+// the background while the store is being written to. Table files added after it starts are not read, and the chunk
+// journal, which is still being appended to, is skipped.
+func (nbs *NomsBlockStore) Scrub(ctx context.Context, cb func(FsckProblem) error) (int, error) {
+	sources, err := nbs.cloneTableFiles()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		for _, cs := range sources {
+			cs.close()
+		}
+	}()
+
+	chunkCount := 0
+	for _, cs := range sources {
+		if err = ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		var n int
+		if acs, ok := cs.(archiveChunkSource); ok {
+			n, err = fsckArchive(ctx, acs, cb)
+		} else {
+			n, err = fsckTableFile(ctx, cs, nbs.stats, cb)
+		}
+		if err != nil {
+			return 0, err
+		}
+		chunkCount += n
+	}
+	return chunkCount, nil
+}
+
+// cloneTableFiles returns a new reader of each table file and archive in the manifest of this store, other than the
+// chunk journal. The readers must be closed by the caller.
+func (nbs *NomsBlockStore) cloneTableFiles() ([]chunkSource, error) {
+	nbs.mu.Lock()
+	defer nbs.mu.Unlock()
+
+	css, err := nbs.chunkSourcesByAddr()
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []chunkSource
+	for _, spec := range nbs.upstream.specs {
+		if spec.name == journalAddr {
+			continue
+		}
+		cs, ok := css[spec.name]
+		if !ok {
+			continue
+		}
+		cl, err := cs.clone()
+		if err != nil {
+			for _, s := range sources {
+				s.close()
+			}
+			return nil, err
+		}
+		sources = append(sources, cl)
+	}
+	return sources, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestScrub(t *testing.T) {
+	ctx := context.Background()
+	st, nomsDir, _ := makeTestLocalStore(t, defaultMaxTables)
+	defer st.Close()
+	populateLocalStore(t, st, 3)
+
+	scrub := func() ([]FsckProblem, int) {
+		var problems []FsckProblem
+		n, err := st.Scrub(ctx, func(p FsckProblem) error {
+			problems = append(problems, p)
+			return nil
+		})
+		require.NoError(t, err)
+		return problems, n
+	}
+
+	problems, n := scrub()
+	assert.Empty(t, problems)
+	assert.Equal(t, 1+2+3, n)
+
+	_, sources, _, err := st.Sources(ctx)
+	require.NoError(t, err)
+	var corrupted string
+	for _, src := range sources {
+		if src.NumChunks() == 3 {
+			corrupted = src.FileID()
+		}
+	}
+	require.NotEmpty(t, corrupted)
+
+	f, err := os.OpenFile(filepath.Join(nomsDir, corrupted), os.O_RDWR, 0)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, 0)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	problems, _ = scrub()
+	require.Len(t, problems, 1)
+	assert.Equal(t, corrupted, problems[0].TableFile.String())
+	assert.False(t, problems[0].Chunk.IsEmpty())
+
+	// the store can be written to between scrubs, and the chunks written are read by the next one
+	c := chunks.NewChunk([]byte("written after scrubbing"))
+	require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+	root, err := st.Root(ctx)
+	require.NoError(t, err)
+	ok, err := st.Commit(ctx, root, root)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	problems, n = scrub()
+	assert.Len(t, problems, 1)
+	assert.Equal(t, 1+2+3+1, n)
+
+	// once the corrupt table file is quarantined, the next scrub finds no problems
+	lost, err := st.QuarantineTableFiles(ctx, hash.NewHashSet(problems[0].TableFile))
+	require.NoError(t, err)
+	assert.Equal(t, 1, lost)
+	problems, _ = scrub()
+	assert.Empty(t, problems)
+}
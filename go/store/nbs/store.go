@@ -1173,6 +1173,7 @@ func (nbs *NomsBlockStore) commit(ctx context.Context, current, last hash.Hash,
 
 	for {
 		if err := nbs.updateManifest(ctx, current, last, checker); err == nil {
+			nbs.maybeCompactJournal()
 			return true, nil
 		} else if err == errOptimisticLockFailedRoot || err == errLastRootMismatch {
 			return false, nil
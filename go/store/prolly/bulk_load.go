@@ -0,0 +1,132 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prolly
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/dolt/go/store/prolly/sort"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+const (
+	// bulkLoadBatchSize is the number of bytes of pairs sorted in memory
+	// before they are spilled to a temporary file.
+	bulkLoadBatchSize = 32 * 1024 * 1024 // 32MB
+	// bulkLoadFileMax is the number of temporary files after which sorted
+	// runs are merged together.
+	bulkLoadFileMax = 128
+)
+
+// BulkLoadMap creates a prolly Tree Map from the pairs of |iter|, which may
+// be in any order. Rather than applying each pair to the tree as an edit, the
+// pairs are first sorted, spilling to temporary files from |tmpProv| as
+// needed, and the sorted pairs are then streamed into a chunker which builds
+// the tree bottom-up: each leaf is written once it is full, and the internal
+// levels are built from the leaves as they are written. No node is written
+// more than once, and memory use is bounded by the sort buffer rather than the
+// size of the map.
+//
+// An error is returned if |iter| produces the same key more than once.
+func BulkLoadMap(ctx context.Context, ns tree.NodeStore, keyDesc, valDesc val.TupleDesc, iter TupleIter, tmpProv tempfiles.TempFileProvider) (Map, error) {
+	sorter := sort.NewTupleSorter(bulkLoadBatchSize, bulkLoadFileMax, func(l, r val.Tuple) bool {
+		lk, _ := unpackPair(l)
+		rk, _ := unpackPair(r)
+		return keyDesc.Compare(lk, rk) < 0
+	}, tmpProv)
+	defer sorter.Close()
+
+	for {
+		k, v := iter.Next(ctx)
+		if k == nil {
+			break
+		}
+		if err := sorter.Insert(ctx, packPair(k, v)); err != nil {
+			return Map{}, err
+		}
+	}
+
+	sorted, err := sorter.Flush(ctx)
+	if err != nil {
+		return Map{}, err
+	}
+	defer sorted.Close()
+
+	it, err := sorted.IterAll(ctx)
+	if err != nil {
+		return Map{}, err
+	}
+	defer it.Close()
+
+	pairs := &sortedPairIter{iter: it, keyDesc: keyDesc}
+	m, err := NewMapFromTupleIter(ctx, ns, keyDesc, valDesc, pairs)
+	if err != nil {
+		return Map{}, err
+	}
+	if pairs.err != nil {
+		return Map{}, pairs.err
+	}
+	return m, nil
+}
+
+// packPair encodes the pair |k|, |v| as a single tuple to be sorted: the key,
+// followed by the value, followed by the length of the key.
+func packPair(k, v val.Tuple) val.Tuple {
+	buf := make([]byte, len(k)+len(v)+4)
+	copy(buf, k)
+	copy(buf[len(k):], v)
+	binary.LittleEndian.PutUint32(buf[len(k)+len(v):], uint32(len(k)))
+	return buf
+}
+
+// unpackPair decodes a pair encoded by packPair.
+func unpackPair(p val.Tuple) (k, v val.Tuple) {
+	n := len(p) - 4
+	kl := binary.LittleEndian.Uint32(p[n:])
+	return p[:kl], p[kl:n]
+}
+
+// sortedPairIter is a TupleIter over packed pairs read from a sort.KeyIter.
+type sortedPairIter struct {
+	iter    sort.KeyIter
+	keyDesc val.TupleDesc
+	lastKey val.Tuple
+	err     error
+}
+
+var _ TupleIter = (*sortedPairIter)(nil)
+
+func (s *sortedPairIter) Next(ctx context.Context) (k, v val.Tuple) {
+	p, err := s.iter.Next(ctx)
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			s.err = err
+		}
+		return nil, nil
+	}
+	k, v = unpackPair(p)
+	if s.lastKey != nil && s.keyDesc.Compare(s.lastKey, k) == 0 {
+		s.err = fmt.Errorf("duplicate key %s", s.keyDesc.Format(k))
+		return nil, nil
+	}
+	s.lastKey = k
+	return k, v
+}
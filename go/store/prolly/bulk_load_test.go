@@ -0,0 +1,68 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prolly
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+func TestBulkLoadMap(t *testing.T) {
+	ctx := context.Background()
+	kd := val.NewTupleDescriptor(
+		val.Type{Enc: val.Uint32Enc, Nullable: false},
+	)
+	vd := val.NewTupleDescriptor(
+		val.Type{Enc: val.Uint32Enc, Nullable: true},
+		val.Type{Enc: val.Uint32Enc, Nullable: true},
+		val.Type{Enc: val.Uint32Enc, Nullable: true},
+	)
+	ns := tree.NewTestNodeStore()
+
+	for _, s := range []int{0, 20, 2000, 20_000} {
+		t.Run("scale "+strconv.Itoa(s), func(t *testing.T) {
+			tuples := tree.RandomTuplePairs(s, kd, vd, ns)
+			expected := mustProllyMapFromTuples(t, kd, vd, tuples)
+
+			shuffled := make([][2]val.Tuple, len(tuples))
+			copy(shuffled, tuples)
+			rand.Shuffle(len(shuffled), func(i, j int) {
+				shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+			})
+
+			actual, err := BulkLoadMap(ctx, ns, kd, vd, &testTupleIter{tuples: shuffled}, tempfiles.MovableTempFileProvider)
+			require.NoError(t, err)
+			assert.Equal(t, expected.HashOf(), actual.HashOf())
+			testIterAll(t, actual, tuples)
+		})
+	}
+
+	t.Run("duplicate keys", func(t *testing.T) {
+		tuples := tree.RandomTuplePairs(200, kd, vd, ns)
+		tuples = append(tuples, [2]val.Tuple{tuples[100][0], tuples[50][1]})
+		_, err := BulkLoadMap(ctx, ns, kd, vd, &testTupleIter{tuples: tuples}, tempfiles.MovableTempFileProvider)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate key")
+	})
+}
@@ -37,10 +37,19 @@ type ItemAccess struct {
 	// offset buffer (offStart is zero), then
 	// Items have a fixed width equal to itemWidth.
 	itemWidth uint16
+
+	// items, if non-nil, is the buffer Items are read
+	// from instead of the serial.Message. It holds the
+	// keys of a node whose keys are prefix compressed,
+	// expanded when the node was unpacked.
+	items []byte
 }
 
 // GetItem returns the ith Item from the buffer.
 func (acc ItemAccess) GetItem(i int, msg serial.Message) []byte {
+	if acc.items != nil {
+		msg = acc.items
+	}
 	buf := msg[acc.bufStart : acc.bufStart+acc.bufLen]
 	off := msg[acc.offStart : acc.offStart+acc.offLen]
 	if acc.offStart != 0 {
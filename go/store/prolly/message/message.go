@@ -39,6 +39,8 @@ func UnpackFields(msg serial.Message) (keys, values ItemAccess, level, count uin
 	switch serial.GetFileID(msg) {
 	case serial.ProllyTreeNodeFileID:
 		return getProllyMapKeysAndValues(msg)
+	case serial.ProllyTreeNodeCompressedFileID:
+		return expandProllyMapKeys(msg)
 	case serial.AddressMapFileID:
 		keys, err = getAddressMapKeys(msg)
 		if err != nil {
@@ -94,7 +96,7 @@ func UnpackFields(msg serial.Message) (keys, values ItemAccess, level, count uin
 func WalkAddresses(ctx context.Context, msg serial.Message, cb func(ctx context.Context, addr hash.Hash) error) error {
 	id := serial.GetFileID(msg)
 	switch id {
-	case serial.ProllyTreeNodeFileID:
+	case serial.ProllyTreeNodeFileID, serial.ProllyTreeNodeCompressedFileID:
 		return walkProllyMapAddresses(ctx, msg, cb)
 	case serial.AddressMapFileID:
 		return walkAddressMapAddresses(ctx, msg, cb)
@@ -112,7 +114,7 @@ func WalkAddresses(ctx context.Context, msg serial.Message, cb func(ctx context.
 func GetTreeCount(msg serial.Message) (int, error) {
 	id := serial.GetFileID(msg)
 	switch id {
-	case serial.ProllyTreeNodeFileID:
+	case serial.ProllyTreeNodeFileID, serial.ProllyTreeNodeCompressedFileID:
 		return getProllyMapTreeCount(msg)
 	case serial.AddressMapFileID:
 		return getAddressMapTreeCount(msg)
@@ -130,7 +132,7 @@ func GetTreeCount(msg serial.Message) (int, error) {
 func GetSubtrees(msg serial.Message) ([]uint64, error) {
 	id := serial.GetFileID(msg)
 	switch id {
-	case serial.ProllyTreeNodeFileID:
+	case serial.ProllyTreeNodeFileID, serial.ProllyTreeNodeCompressedFileID:
 		return getProllyMapSubtrees(msg)
 	case serial.AddressMapFileID:
 		return getAddressMapSubtrees(msg)
@@ -15,13 +15,17 @@
 package message
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"fmt"
+	"os"
+	"unicode/utf8"
 
 	fb "github.com/dolthub/flatbuffers/v23/go"
 
 	"github.com/dolthub/dolt/go/gen/fb/serial"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/pool"
 	"github.com/dolthub/dolt/go/store/val"
@@ -38,34 +42,88 @@ const (
 	prollyMapAddressArrayBytesVOffset fb.VOffsetT = 18
 )
 
+// minSharedKeyPrefix is the average number of bytes that the keys of a leaf
+// node must share with its first key for the keys to be prefix compressed.
+const minSharedKeyPrefix = 8
+
 var prollyMapFileID = []byte(serial.ProllyTreeNodeFileID)
 
+var prollyMapCompressedFileID = []byte(serial.ProllyTreeNodeCompressedFileID)
+
+func init() {
+	if v := os.Getenv(dconfig.EnvIndexKeyPrefixCompression); v != "" {
+		enableKeyPrefixCompression = true
+	}
+}
+
+// enableKeyPrefixCompression enables prefix compression of the keys of
+// secondary index leaf nodes. See |ProllyMapSerializer.WithKeyPrefixCompression|.
+var enableKeyPrefixCompression = false
+
 func NewProllyMapSerializer(valueDesc val.TupleDesc, pool pool.BuffPool) ProllyMapSerializer {
 	return ProllyMapSerializer{valDesc: valueDesc, pool: pool}
 }
 
 type ProllyMapSerializer struct {
-	valDesc val.TupleDesc
-	pool    pool.BuffPool
+	keyDesc      val.TupleDesc
+	valDesc      val.TupleDesc
+	pool         pool.BuffPool
+	compressKeys bool
 }
 
 var _ Serializer = ProllyMapSerializer{}
 
+// WithKeyPrefixCompression returns a serializer that prefix compresses the keys,
+// described by |keyDesc|, of the leaf nodes of maps without values, such as
+// secondary indexes. Keys are compressed if compression is enabled with
+// dconfig.EnvIndexKeyPrefixCompression, or if |compressed| is true because the
+// map written is already compressed (see IsKeyPrefixCompressed), so that a map
+// stays compressed once it is.
+//
+// Every node written by a compressing serializer has its own file identifier,
+// whether its keys are compressed or not, so that the root node of a map tells
+// whether any of its nodes may be. Clients that can't expand compressed keys
+// fail to read these nodes rather than reading the wrong keys, and roots with
+// compressed maps are written with a feature version which keeps those clients
+// from reading them at all.
+func (s ProllyMapSerializer) WithKeyPrefixCompression(keyDesc val.TupleDesc, compressed bool) ProllyMapSerializer {
+	s.keyDesc = keyDesc
+	s.compressKeys = (enableKeyPrefixCompression || compressed) && s.valDesc.Count() == 0
+	return s
+}
+
+// IsKeyPrefixCompressed returns true if |msg| is a node of a prolly map whose
+// leaf nodes may have prefix compressed keys.
+func IsKeyPrefixCompressed(msg serial.Message) bool {
+	return serial.GetFileID(msg) == serial.ProllyTreeNodeCompressedFileID
+}
+
 func (s ProllyMapSerializer) Serialize(keys, values [][]byte, subtrees []uint64, level int) serial.Message {
 	var (
 		keyTups, keyOffs fb.UOffsetT
+		keyPrefixes      fb.UOffsetT
 		valTups, valOffs fb.UOffsetT
 		valAddrOffs      fb.UOffsetT
 		refArr, cardArr  fb.UOffsetT
 	)
 
+	var prefixes []uint16
+	if level == 0 && s.compressKeys {
+		keys, prefixes = compressKeyPrefixes(s.keyDesc, keys)
+	}
+
 	keySz, valSz, bufSz := estimateProllyMapSize(keys, values, subtrees, s.valDesc.AddressFieldCount())
+	bufSz += len(prefixes) * uint16Size
 	b := getFlatbufferBuilder(s.pool, bufSz)
 
 	// serialize keys and offStart
 	keyTups = writeItemBytes(b, keys, keySz)
 	serial.ProllyTreeNodeStartKeyOffsetsVector(b, len(keys)+1)
 	keyOffs = writeItemOffsets(b, keys, keySz)
+	if prefixes != nil {
+		serial.ProllyTreeNodeStartKeyPrefixLengthsVector(b, len(prefixes))
+		keyPrefixes = writePrefixLengths(b, prefixes)
+	}
 
 	if level == 0 {
 		// serialize value tuples for leaf nodes
@@ -100,10 +158,90 @@ func (s ProllyMapSerializer) Serialize(keys, values [][]byte, subtrees []uint64,
 	serial.ProllyTreeNodeAddKeyType(b, serial.ItemTypeTupleFormatAlpha)
 	serial.ProllyTreeNodeAddValueType(b, serial.ItemTypeTupleFormatAlpha)
 	serial.ProllyTreeNodeAddTreeLevel(b, uint8(level))
+	if prefixes != nil {
+		serial.ProllyTreeNodeAddKeyPrefixLengths(b, keyPrefixes)
+	}
+	if s.compressKeys {
+		return serial.FinishMessage(b, serial.ProllyTreeNodeEnd(b), prollyMapCompressedFileID)
+	}
 
 	return serial.FinishMessage(b, serial.ProllyTreeNodeEnd(b), prollyMapFileID)
 }
 
+// compressKeyPrefixes computes the length of the prefix each of |keys| shares
+// with the first key. If the keys share enough of their prefix to be worth
+// compressing, it returns the first key followed by the suffixes of the other
+// keys, along with their prefix lengths. Otherwise, it returns |keys| and nil.
+func compressKeyPrefixes(desc val.TupleDesc, keys [][]byte) ([][]byte, []uint16) {
+	if len(keys) < 2 {
+		return keys, nil
+	}
+	first := val.Tuple(keys[0])
+	prefixes := make([]uint16, len(keys))
+	var shared int
+	for i := 1; i < len(keys); i++ {
+		n := sharedKeyPrefix(desc, first, val.Tuple(keys[i]))
+		prefixes[i] = uint16(n)
+		shared += n
+	}
+	if shared < minSharedKeyPrefix*(len(keys)-1) {
+		return keys, nil
+	}
+
+	compressed := make([][]byte, len(keys))
+	compressed[0] = first
+	for i := 1; i < len(keys); i++ {
+		compressed[i] = keys[i][prefixes[i]:]
+	}
+	return compressed, prefixes
+}
+
+// sharedKeyPrefix returns the number of leading bytes |key| shares with |first|.
+// The values of tuple fields are stored contiguously from the front of a tuple,
+// so the prefix covers the leading fields whose values are equal, and then the
+// common prefix of the first field whose values differ if it's a string or a
+// byte string. A string field's prefix always ends on a character boundary, so
+// that a prefix never splits a character that its collation compares as a whole.
+func sharedKeyPrefix(desc val.TupleDesc, first, key val.Tuple) (n int) {
+	cnt := min(first.Count(), key.Count(), desc.Count())
+	for i := 0; i < cnt; i++ {
+		l, r := first.GetField(i), key.GetField(i)
+		if bytes.Equal(l, r) {
+			n += len(l)
+			continue
+		}
+		switch desc.Types[i].Enc {
+		case val.StringEnc:
+			p := commonPrefixLength(l, r)
+			for p > 0 && p < len(l) && !utf8.RuneStart(l[p]) {
+				p--
+			}
+			n += p
+		case val.ByteStringEnc:
+			n += commonPrefixLength(l, r)
+		}
+		break
+	}
+	return n
+}
+
+func commonPrefixLength(l, r []byte) (n int) {
+	if len(r) < len(l) {
+		l, r = r, l
+	}
+	for n < len(l) && l[n] == r[n] {
+		n++
+	}
+	return
+}
+
+func writePrefixLengths(b *fb.Builder, prefixes []uint16) fb.UOffsetT {
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		b.PrependUint16(prefixes[i])
+	}
+	return b.EndVector(len(prefixes))
+}
+
 func getProllyMapKeysAndValues(msg serial.Message) (keys, values ItemAccess, level, count uint16, err error) {
 	var pm serial.ProllyTreeNode
 	err = serial.InitProllyTreeNodeRoot(&pm, msg, serial.MessagePrefixSz)
@@ -132,6 +270,65 @@ func getProllyMapKeysAndValues(msg serial.Message) (keys, values ItemAccess, lev
 	return
 }
 
+// expandProllyMapKeys unpacks a prolly map message whose nodes may have prefix
+// compressed keys. If the keys of |msg| are compressed, they are expanded into
+// a buffer of their own, which |keys| reads them from. Keys are expanded once,
+// when the message is unpacked, and reading them doesn't allocate.
+func expandProllyMapKeys(msg serial.Message) (keys, values ItemAccess, level, count uint16, err error) {
+	var stored ItemAccess
+	stored, values, level, count, err = getProllyMapKeysAndValues(msg)
+	if err != nil {
+		return
+	}
+	var pm serial.ProllyTreeNode
+	err = serial.InitProllyTreeNodeRoot(&pm, msg, serial.MessagePrefixSz)
+	if err != nil {
+		return
+	}
+	if pm.KeyPrefixLengthsLength() == 0 {
+		return stored, values, level, count, nil
+	}
+	if pm.KeyPrefixLengthsLength() != int(count) {
+		err = fmt.Errorf("prolly map message has %d key prefix lengths for %d keys", pm.KeyPrefixLengthsLength(), count)
+		return
+	}
+
+	// the expanded keys are followed by their offsets, which must fit in a uint16
+	bufLen := 0
+	for i := 0; i < int(count); i++ {
+		bufLen += int(pm.KeyPrefixLengths(i)) + len(stored.GetItem(i, msg))
+	}
+	offLen := (int(count) + 1) * uint16Size
+	if bufLen == 0 {
+		err = fmt.Errorf("prolly map message has %d empty keys", count)
+		return
+	} else if bufLen+offLen > maxChunkSz {
+		err = fmt.Errorf("expanded keys of prolly map message exceed size limit ( %d > %d )", bufLen+offLen, maxChunkSz)
+		return
+	}
+
+	keys.items = make([]byte, bufLen+offLen)
+	keys.bufLen = uint16(bufLen)
+	keys.offStart = uint16(bufLen)
+	keys.offLen = uint16(offLen)
+
+	items := keys.items[:bufLen]
+	offs := keys.items[bufLen:]
+	first := stored.GetItem(0, msg)
+	pos := 0
+	for i := 0; i < int(count); i++ {
+		n := int(pm.KeyPrefixLengths(i))
+		if n > len(first) {
+			err = fmt.Errorf("prolly map message has a key prefix length longer than its first key ( %d > %d )", n, len(first))
+			return
+		}
+		pos += copy(items[pos:], first[:n])
+		pos += copy(items[pos:], stored.GetItem(i, msg))
+		val.WriteUint16(offs[(i+1)*uint16Size:(i+2)*uint16Size], uint16(pos))
+	}
+	return
+}
+
 func walkProllyMapAddresses(ctx context.Context, msg serial.Message, cb func(ctx context.Context, addr hash.Hash) error) error {
 	var pm serial.ProllyTreeNode
 	err := serial.InitProllyTreeNodeRoot(&pm, msg, serial.MessagePrefixSz)
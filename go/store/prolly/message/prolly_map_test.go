@@ -15,12 +15,15 @@
 package message
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"unsafe"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/gen/fb/serial"
 	"github.com/dolthub/dolt/go/store/pool"
 	"github.com/dolthub/dolt/go/store/val"
 )
@@ -46,9 +49,202 @@ func TestGetKeyValueOffsetsVectors(t *testing.T) {
 	}
 }
 
+func TestKeyPrefixCompression(t *testing.T) {
+	kd := val.NewTupleDescriptor(val.Type{Enc: val.StringEnc}, val.Type{Enc: val.Int64Enc})
+	s := ProllyMapSerializer{keyDesc: kd, valDesc: val.TupleDesc{}, pool: sharedPool, compressKeys: true}
+
+	t.Run("keys with a common prefix are compressed", func(t *testing.T) {
+		keys := stringKeys(kd, 100, "https://www.example.com/products/%04d")
+		values := make([][]byte, len(keys))
+		msg := s.Serialize(keys, values, nil, 0)
+		assert.Equal(t, serial.ProllyTreeNodeCompressedFileID, serial.GetFileID(msg))
+		assert.Less(t, len(msg), int(sumSize(keys)))
+
+		keyBuf, _, _, cnt, err := UnpackFields(msg)
+		require.NoError(t, err)
+		require.Equal(t, len(keys), int(cnt))
+		for i := range keys {
+			assert.Equal(t, keys[i], keyBuf.GetItem(i, msg))
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			keyBuf.GetItem(int(cnt)-1, msg)
+		})
+		assert.Zero(t, allocs)
+
+		// the expanded keys don't share the message's buffer
+		grown := append(msg, make([]byte, 4096)...)
+		for i := range grown[len(msg):] {
+			grown[len(msg)+i] = 0xff
+		}
+		for i := range keys {
+			assert.Equal(t, keys[i], keyBuf.GetItem(i, grown))
+		}
+	})
+
+	t.Run("keys without a common prefix are not compressed", func(t *testing.T) {
+		keys := stringKeys(kd, 100, "%04d")
+		values := make([][]byte, len(keys))
+		msg := s.Serialize(keys, values, nil, 0)
+		assert.True(t, IsKeyPrefixCompressed(msg))
+		assert.Equal(t, int(sumSize(keys)), len(getProllyMapKeyItems(t, msg)))
+
+		keyBuf, _, _, cnt, err := UnpackFields(msg)
+		require.NoError(t, err)
+		require.Equal(t, len(keys), int(cnt))
+		for i := range keys {
+			assert.Equal(t, keys[i], keyBuf.GetItem(i, msg))
+		}
+	})
+
+	t.Run("internal nodes are not compressed", func(t *testing.T) {
+		keys := stringKeys(kd, 100, "https://www.example.com/products/%04d")
+		values := make([][]byte, len(keys))
+		subtrees := make([]uint64, len(keys))
+		for i := range values {
+			values[i] = make([]byte, 20)
+			subtrees[i] = 1
+		}
+		msg := s.Serialize(keys, values, subtrees, 1)
+		assert.True(t, IsKeyPrefixCompressed(msg))
+		assert.Equal(t, int(sumSize(keys)), len(getProllyMapKeyItems(t, msg)))
+	})
+
+	t.Run("maps which aren't compressed keep their file identifier", func(t *testing.T) {
+		keys := stringKeys(kd, 100, "https://www.example.com/products/%04d")
+		values := make([][]byte, len(keys))
+		msg := ProllyMapSerializer{keyDesc: kd, valDesc: val.TupleDesc{}, pool: sharedPool}.Serialize(keys, values, nil, 0)
+		assert.Equal(t, serial.ProllyTreeNodeFileID, serial.GetFileID(msg))
+		assert.False(t, IsKeyPrefixCompressed(msg))
+	})
+
+	t.Run("expanded keys must fit in a message", func(t *testing.T) {
+		keys := stringKeys(kd, 200, strings.Repeat("a", 1000)+"%04d")
+		values := make([][]byte, len(keys))
+		msg := s.Serialize(keys, values, nil, 0)
+		_, _, _, _, err := UnpackFields(msg)
+		assert.ErrorContains(t, err, "exceed size limit")
+	})
+
+	t.Run("compression must be enabled", func(t *testing.T) {
+		vd := val.NewTupleDescriptor(val.Type{Enc: val.Int32Enc})
+		assert.False(t, NewProllyMapSerializer(val.TupleDesc{}, sharedPool).WithKeyPrefixCompression(kd, false).compressKeys)
+		// maps which are already compressed stay compressed
+		assert.True(t, NewProllyMapSerializer(val.TupleDesc{}, sharedPool).WithKeyPrefixCompression(kd, true).compressKeys)
+
+		enableKeyPrefixCompression = true
+		defer func() { enableKeyPrefixCompression = false }()
+		assert.True(t, NewProllyMapSerializer(val.TupleDesc{}, sharedPool).WithKeyPrefixCompression(kd, false).compressKeys)
+		assert.False(t, NewProllyMapSerializer(vd, sharedPool).WithKeyPrefixCompression(kd, false).compressKeys)
+	})
+}
+
+// getProllyMapKeyItems returns the key items of |msg| as they are stored.
+func getProllyMapKeyItems(t *testing.T, msg serial.Message) []byte {
+	var pm serial.ProllyTreeNode
+	require.NoError(t, serial.InitProllyTreeNodeRoot(&pm, msg, serial.MessagePrefixSz))
+	return pm.KeyItemsBytes()
+}
+
+func TestSharedKeyPrefix(t *testing.T) {
+	kd := val.NewTupleDescriptor(val.Type{Enc: val.StringEnc}, val.Type{Enc: val.StringEnc})
+	tuple := func(a, b string) val.Tuple {
+		tb := val.NewTupleBuilder(kd)
+		require.NoError(t, tb.PutString(0, a))
+		require.NoError(t, tb.PutString(1, b))
+		return tb.Build(sharedPool)
+	}
+
+	tests := []struct {
+		l, r     val.Tuple
+		expected int
+	}{
+		{tuple("abc", "x"), tuple("abd", "x"), 2},
+		// equal leading fields are shared along with the prefix of the next field
+		{tuple("abc", "xyz"), tuple("abc", "xyw"), 4 + 2},
+		{tuple("abc", "xyz"), tuple("abc", "xyz"), 4 + 4},
+		// prefixes end on a character boundary: é is c3 a9 and è is c3 a8
+		{tuple("café", "x"), tuple("cafè", "x"), 3},
+		{tuple("abc", "x"), tuple("xyz", "x"), 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, sharedKeyPrefix(kd, tt.l, tt.r), "%s %s", kd.Format(tt.l), kd.Format(tt.r))
+	}
+
+	// fields that aren't strings share only whole values
+	id := val.NewTupleDescriptor(val.Type{Enc: val.Int64Enc})
+	ib := val.NewTupleBuilder(id)
+	ib.PutInt64(0, 1<<8)
+	l := ib.Build(sharedPool)
+	ib.PutInt64(0, 2<<8)
+	r := ib.Build(sharedPool)
+	assert.Equal(t, 0, sharedKeyPrefix(id, l, r))
+}
+
 func TestItemAccessSize(t *testing.T) {
 	sz := unsafe.Sizeof(ItemAccess{})
-	assert.Equal(t, 10, int(sz))
+	assert.Equal(t, 40, int(sz))
+}
+
+func BenchmarkGetItem(b *testing.B) {
+	kd := val.NewTupleDescriptor(val.Type{Enc: val.StringEnc}, val.Type{Enc: val.Int64Enc})
+	keys := stringKeys(kd, 100, "https://www.example.com/products/%04d")
+	values := make([][]byte, len(keys))
+	for _, compress := range []bool{false, true} {
+		s := ProllyMapSerializer{keyDesc: kd, valDesc: val.TupleDesc{}, pool: sharedPool, compressKeys: compress}
+		msg := s.Serialize(keys, values, nil, 0)
+		keyBuf, _, _, cnt, err := UnpackFields(msg)
+		require.NoError(b, err)
+		b.Run(fmt.Sprintf("compressed=%t", compress), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				keyBuf.GetItem(i%int(cnt), msg)
+			}
+		})
+	}
+}
+
+func BenchmarkUnpackFields(b *testing.B) {
+	kd := val.NewTupleDescriptor(val.Type{Enc: val.StringEnc}, val.Type{Enc: val.Int64Enc})
+	keys := stringKeys(kd, 100, "https://www.example.com/products/%04d")
+	values := make([][]byte, len(keys))
+	for _, compress := range []bool{false, true} {
+		s := ProllyMapSerializer{keyDesc: kd, valDesc: val.TupleDesc{}, pool: sharedPool, compressKeys: compress}
+		msg := s.Serialize(keys, values, nil, 0)
+		b.Run(fmt.Sprintf("compressed=%t", compress), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(msg)))
+			for i := 0; i < b.N; i++ {
+				_, _, _, _, _ = UnpackFields(msg)
+			}
+		})
+	}
+}
+
+func BenchmarkSerialize(b *testing.B) {
+	kd := val.NewTupleDescriptor(val.Type{Enc: val.StringEnc}, val.Type{Enc: val.Int64Enc})
+	keys := stringKeys(kd, 100, "https://www.example.com/products/%04d")
+	values := make([][]byte, len(keys))
+	for _, compress := range []bool{false, true} {
+		s := ProllyMapSerializer{keyDesc: kd, valDesc: val.TupleDesc{}, pool: sharedPool, compressKeys: compress}
+		b.Run(fmt.Sprintf("compressed=%t", compress), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s.Serialize(keys, values, nil, 0)
+			}
+		})
+	}
+}
+
+// stringKeys returns |count| sorted tuples of |kd| whose first field is |format| applied to their index.
+func stringKeys(kd val.TupleDesc, count int, format string) [][]byte {
+	tb := val.NewTupleBuilder(kd)
+	keys := make([][]byte, count)
+	for i := range keys {
+		_ = tb.PutString(0, fmt.Sprintf(format, i))
+		tb.PutInt64(1, int64(i))
+		keys[i] = tb.Build(sharedPool)
+	}
+	return keys
 }
 
 func randomByteSlices(t *testing.T, count int) (keys, values [][]byte) {
@@ -72,6 +72,15 @@ func TestMutableMapWrites(t *testing.T) {
 	t.Run("test internal node splits", func(t *testing.T) {
 		testInternalNodeSplits(t)
 	})
+	t.Run("test flushing at the pending size limit", func(t *testing.T) {
+		testPendingSizeFlushes(t)
+	})
+	t.Run("test pending size of rewritten keys and deletes", func(t *testing.T) {
+		testPendingSizeAccounting(t)
+	})
+	t.Run("test shared pending size limit", func(t *testing.T) {
+		testSharedPendingSizeLimit(t)
+	})
 }
 
 func testPointUpdates(t *testing.T, mapCount int) {
@@ -459,6 +468,96 @@ func testBulkInserts(t *testing.T, size int) {
 	}
 }
 
+func testPendingSizeFlushes(t *testing.T) {
+	ctx := context.Background()
+	orig := ascendingIntMap(t, 100)
+	mut := orig.Mutate()
+	// flush after every few writes
+	mut.maxPendingSize = 4 * pendingEditOverhead
+
+	for i := 100; i < 200; i++ {
+		k, v := makePut(int64(i), int64(i))
+		require.NoError(t, mut.Put(ctx, k, v))
+		assert.LessOrEqual(t, mut.pendingSize, mut.maxPendingSize)
+	}
+	require.NoError(t, mut.Checkpoint(ctx))
+	for i := 200; i < 300; i++ {
+		k, v := makePut(int64(i), int64(i))
+		require.NoError(t, mut.Put(ctx, k, v))
+	}
+	for i := 0; i < 50; i++ {
+		require.NoError(t, mut.Delete(ctx, makeDelete(int64(i))))
+	}
+	assert.NotNil(t, mut.stash)
+
+	mut.Revert(ctx)
+	m := materializeMap(t, mut)
+	c, err := m.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 200, c)
+	for i := 0; i < 200; i++ {
+		ok, err := m.Has(ctx, makeDelete(int64(i)))
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+}
+
+func testPendingSizeAccounting(t *testing.T) {
+	ctx := context.Background()
+	orig := ascendingIntMap(t, 100)
+	mut := orig.Mutate()
+
+	// writing the same key again only counts the change in its value
+	k, v := makePut(int64(1000), int64(1))
+	require.NoError(t, mut.Put(ctx, k, v))
+	size := mut.pendingSize
+	for i := 0; i < 100; i++ {
+		k, v = makePut(int64(1000), int64(i))
+		require.NoError(t, mut.Put(ctx, k, v))
+	}
+	assert.Equal(t, size, mut.pendingSize)
+	require.NoError(t, mut.Delete(ctx, makeDelete(int64(1000))))
+	assert.Equal(t, size-len(v), mut.pendingSize)
+
+	// deletes flush at the limit too
+	mut.maxPendingSize = 4 * pendingEditOverhead
+	for i := 0; i < 100; i++ {
+		require.NoError(t, mut.Delete(ctx, makeDelete(int64(i))))
+		assert.LessOrEqual(t, mut.pendingSize, mut.maxPendingSize)
+	}
+	m := materializeMap(t, mut)
+	c, err := m.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, c)
+}
+
+func testSharedPendingSizeLimit(t *testing.T) {
+	ctx := context.Background()
+	limit := &PendingSizeLimit{max: 8 * pendingEditOverhead}
+	a := ascendingIntMap(t, 10).Mutate().WithPendingSizeLimit(limit)
+	b := ascendingIntMap(t, 10).Mutate().WithPendingSizeLimit(limit)
+	require.NoError(t, a.Checkpoint(ctx))
+	require.NoError(t, b.Checkpoint(ctx))
+
+	for i := 100; i < 200; i++ {
+		k, v := makePut(int64(i), int64(i))
+		require.NoError(t, a.Put(ctx, k, v))
+		require.NoError(t, b.Put(ctx, k, v))
+		assert.LessOrEqual(t, limit.size.Load(), limit.max)
+		assert.Equal(t, int64(a.pendingSize+b.pendingSize), limit.size.Load())
+	}
+
+	a.Revert(ctx)
+	b.Revert(ctx)
+	assert.Equal(t, int64(a.pendingSize+b.pendingSize), limit.size.Load())
+	for _, mut := range []*MutableMap{a, b} {
+		m := materializeMap(t, mut)
+		c, err := m.Count()
+		require.NoError(t, err)
+		assert.Equal(t, 10, c)
+	}
+}
+
 func testInternalNodeSplits(t *testing.T) {
 	const n = 100_000
 	var err error
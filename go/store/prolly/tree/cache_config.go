@@ -0,0 +1,210 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/store/chunks"
+)
+
+const (
+	// CacheTierMemory and CacheTierDisk name the tiers of a node cache in its CacheStats.
+	CacheTierMemory = "memory"
+	CacheTierDisk   = "disk"
+
+	defaultDiskCacheSize = 4 * 1024 * 1024 * 1024
+)
+
+// nodeCacheConfig configures the caches of NodeStores. It's read from the
+// environment during initialization:
+//
+//   - DOLT_NODE_CACHE_SIZE is the size in bytes of the memory cache shared
+//     by databases.
+//   - DOLT_NODE_CACHE_PARTITION_SIZE, if set, gives each database its own
+//     memory cache of this size instead, so that on servers with many
+//     databases, one database's scans can't evict another's working set.
+//   - DOLT_NODE_CACHE_DISK_DIR, if set, is a directory in which databases
+//     backed by remote storage cache the chunks they read, after they're
+//     evicted from memory. Each database's chunks are limited to
+//     DOLT_NODE_CACHE_DISK_SIZE bytes, 4GB by default.
+type nodeCacheConfig struct {
+	memorySize    int
+	partitionSize int
+	diskDir       string
+	diskSize      int64
+}
+
+var cacheConfig = loadNodeCacheConfig()
+
+func loadNodeCacheConfig() nodeCacheConfig {
+	cfg := nodeCacheConfig{
+		memorySize: cacheSize,
+		diskDir:    os.Getenv(dconfig.EnvNodeCacheDiskDir),
+		diskSize:   defaultDiskCacheSize,
+	}
+	if v, ok := sizeFromEnv(dconfig.EnvNodeCacheSize); ok {
+		cfg.memorySize = int(v)
+	}
+	if v, ok := sizeFromEnv(dconfig.EnvNodeCachePartitionSize); ok {
+		cfg.partitionSize = int(v)
+	}
+	if v, ok := sizeFromEnv(dconfig.EnvNodeCacheDiskSize); ok {
+		cfg.diskSize = v
+	}
+	return cfg
+}
+
+func sizeFromEnv(name string) (int64, bool) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, false
+	}
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || i < 0 {
+		logrus.Warnf("unable to parse a size in bytes for %s from %s", name, v)
+		return 0, false
+	}
+	return i, true
+}
+
+// cachePartition is the cache of the Nodes of one database.
+type cachePartition struct {
+	memory nodeCache
+	disk   *diskCache
+}
+
+var partitions = struct {
+	mu     sync.Mutex
+	byName map[string]*cachePartition
+}{byName: make(map[string]*cachePartition)}
+
+// NewNodeStoreForDatabase makes a new NodeStore for the database stored at
+// |location|, using the database's own cache partition if partitioning is
+// configured and the shared cache otherwise. Databases backed by remote
+// storage, |remote|, also cache chunks on local disk if a disk cache is
+// configured.
+func NewNodeStoreForDatabase(cs chunks.ChunkStore, location string, remote bool) NodeStore {
+	usePartition := cacheConfig.partitionSize > 0
+	useDisk := remote && cacheConfig.diskDir != ""
+	if !usePartition && !useDisk {
+		return NewNodeStore(cs)
+	}
+
+	partitions.mu.Lock()
+	defer partitions.mu.Unlock()
+	p, ok := partitions.byName[location]
+	if !ok {
+		p = &cachePartition{memory: sharedCache}
+		if usePartition {
+			p.memory = newChunkCache(cacheConfig.partitionSize)
+		}
+		if useDisk {
+			dir := filepath.Join(cacheConfig.diskDir, url.PathEscape(location))
+			dc, err := newDiskCache(dir, cacheConfig.diskSize)
+			if err != nil {
+				logrus.Warnf("unable to create a disk cache for %s in %s: %s", location, dir, err.Error())
+			} else {
+				p.disk = dc
+			}
+		}
+		partitions.byName[location] = p
+	}
+
+	ns := NewNodeStore(cs).(nodeStore)
+	ns.cache = p.memory
+	ns.disk = p.disk
+	return ns
+}
+
+// CacheStats are the metrics of one tier of a node cache.
+type CacheStats struct {
+	// Partition is the location of the database whose Nodes the cache
+	// holds, or "" for the memory cache shared by databases.
+	Partition string
+	// Tier is CacheTierMemory or CacheTierDisk.
+	Tier                    string
+	Hits, Misses, Evictions uint64
+	// Size is the total size in bytes of the cached Nodes, and Capacity
+	// the size the cache is limited to.
+	Size, Capacity uint64
+}
+
+// NodeCacheStats returns the metrics of every node cache, ordered by
+// partition and tier.
+func NodeCacheStats() []CacheStats {
+	stats := []CacheStats{memoryCacheStats("", sharedCache)}
+
+	partitions.mu.Lock()
+	defer partitions.mu.Unlock()
+	for name, p := range partitions.byName {
+		if cacheConfig.partitionSize > 0 {
+			stats = append(stats, memoryCacheStats(name, p.memory))
+		}
+		if p.disk != nil {
+			size, capacity := p.disk.sizes()
+			stats = append(stats, CacheStats{
+				Partition: name,
+				Tier:      CacheTierDisk,
+				Hits:      p.disk.metrics.hits.Load(),
+				Misses:    p.disk.metrics.misses.Load(),
+				Evictions: p.disk.metrics.evictions.Load(),
+				Size:      size,
+				Capacity:  capacity,
+			})
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Partition != stats[j].Partition {
+			return stats[i].Partition < stats[j].Partition
+		}
+		return stats[i].Tier > stats[j].Tier
+	})
+	return stats
+}
+
+// PurgeNodeCache drops the Nodes held in every memory cache, shared and
+// partitioned. Disk caches are kept, since they don't use memory.
+func PurgeNodeCache() {
+	sharedCache.purge()
+
+	partitions.mu.Lock()
+	defer partitions.mu.Unlock()
+	for _, p := range partitions.byName {
+		p.memory.purge()
+	}
+}
+
+func memoryCacheStats(partition string, c nodeCache) CacheStats {
+	size, capacity := c.sizes()
+	return CacheStats{
+		Partition: partition,
+		Tier:      CacheTierMemory,
+		Hits:      c.metrics.hits.Load(),
+		Misses:    c.metrics.misses.Load(),
+		Evictions: c.metrics.evictions.Load(),
+		Size:      size,
+		Capacity:  capacity,
+	}
+}
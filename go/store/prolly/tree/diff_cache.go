@@ -0,0 +1,106 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/util/sizecache"
+)
+
+const (
+	// diffCacheSize is the total size of the Diffs held by the diff cache.
+	diffCacheSize = 64 * 1024 * 1024
+	// maxCachedDiffSize is the size of the largest set of Diffs between
+	// two trees that is cached. Larger diffs are recomputed each time.
+	maxCachedDiffSize = diffCacheSize / 16
+	// diffOverhead approximates the memory used by a Diff, excluding the
+	// contents of its Items.
+	diffOverhead = 80
+)
+
+// sharedDiffCache holds the complete set of Diffs between pairs of trees
+// that have been diffed recently. Trees are identified by the hashes of their
+// root Nodes, so the same pair of table rows compared from different refs,
+// eg. main and a feature branch in a pull request, share an entry.
+var sharedDiffCache = newDiffCache(diffCacheSize)
+
+type diffCacheKey struct {
+	from, to                hash.Hash
+	considerAllRowsModified bool
+}
+
+type diffCache struct {
+	cache *sizecache.SizeCache
+}
+
+func newDiffCache(maxSize uint64) diffCache {
+	return diffCache{cache: sizecache.New(maxSize)}
+}
+
+func (c diffCache) get(key diffCacheKey) ([]Diff, bool) {
+	v, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return v.([]Diff), true
+}
+
+func (c diffCache) put(key diffCacheKey, diffs []Diff, size int) {
+	c.cache.Add(key, uint64(size), diffs)
+}
+
+// PurgeDiffCache drops every cached Diff. It is called after garbage
+// collection, which may remove the chunks of trees held in the cache.
+func PurgeDiffCache() {
+	sharedDiffCache.cache.Purge()
+}
+
+// DiffCacheSize returns the approximate memory in bytes used by cached
+// Diffs, and the size the cache is limited to.
+func DiffCacheSize() (size, capacity uint64) {
+	return sharedDiffCache.cache.Size(), diffCacheSize
+}
+
+// diffRecorder copies the Diffs between two trees as they are computed, until
+// they exceed |maxCachedDiffSize|.
+type diffRecorder struct {
+	diffs []Diff
+	size  int
+	full  bool
+}
+
+func (r *diffRecorder) record(d Diff) {
+	if r.full {
+		return
+	}
+	r.size += len(d.Key) + len(d.From) + len(d.To) + diffOverhead
+	if r.size > maxCachedDiffSize {
+		r.diffs, r.full = nil, true
+		return
+	}
+	r.diffs = append(r.diffs, Diff{
+		Key:  copyItem(d.Key),
+		From: copyItem(d.From),
+		To:   copyItem(d.To),
+		Type: d.Type,
+	})
+}
+
+func copyItem(i Item) Item {
+	if i == nil {
+		return nil
+	}
+	return append(Item{}, i...)
+}
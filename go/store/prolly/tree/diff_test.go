@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/store/val"
 )
@@ -57,3 +58,55 @@ func TestDifferFromRoots(t *testing.T) {
 	dif, err = dfr.Next(ctx)
 	assert.Equal(t, io.EOF, err)
 }
+
+func TestDiffOrderedTreesCache(t *testing.T) {
+	ctx := context.Background()
+	ns := NewTestNodeStore()
+
+	fromTups, desc := AscendingUintTuples(1234)
+	toTups := make([][2]val.Tuple, len(fromTups))
+	copy(toTups, fromTups)
+	bld := val.NewTupleBuilder(desc)
+	for _, i := range []int{23, 500, 1000} {
+		bld.PutUint32(0, uint32(i*7))
+		toTups[i][1] = bld.Build(sharedPool)
+	}
+	from := StaticMap[val.Tuple, val.Tuple, val.TupleDesc]{Root: makeTree(t, fromTups), NodeStore: ns, Order: desc}
+	to := StaticMap[val.Tuple, val.Tuple, val.TupleDesc]{Root: makeTree(t, toTups), NodeStore: ns, Order: desc}
+	key := diffCacheKey{from: from.Root.HashOf(), to: to.Root.HashOf()}
+
+	collect := func() (diffs []Diff) {
+		err := DiffOrderedTrees(ctx, from, to, false, func(_ context.Context, d Diff) error {
+			diffs = append(diffs, d)
+			return nil
+		})
+		assert.Equal(t, io.EOF, err)
+		return
+	}
+
+	PurgeDiffCache()
+	t.Run("incomplete diffs are not cached", func(t *testing.T) {
+		err := DiffOrderedTrees(ctx, from, to, false, func(_ context.Context, d Diff) error {
+			return io.EOF
+		})
+		assert.Equal(t, io.EOF, err)
+		_, ok := sharedDiffCache.get(key)
+		assert.False(t, ok)
+	})
+
+	t.Run("complete diffs are cached", func(t *testing.T) {
+		expected := collect()
+		assert.Len(t, expected, 3)
+		cached, ok := sharedDiffCache.get(key)
+		require.True(t, ok)
+		assert.Equal(t, expected, cached)
+		assert.Equal(t, expected, collect())
+	})
+
+	t.Run("purge drops cached diffs", func(t *testing.T) {
+		PurgeDiffCache()
+		_, ok := sharedDiffCache.get(key)
+		assert.False(t, ok)
+		assert.Len(t, collect(), 3)
+	})
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"container/list"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// diskCacheTempPrefix is the prefix of the files chunks are written to
+// before they're renamed into the cache.
+const diskCacheTempPrefix = "tmp-"
+
+// diskCache is a cache of chunks in a local directory, the second tier of
+// the node cache of databases backed by remote storage. Each chunk is a
+// file named by its address, and the least recently used chunks are
+// removed once the directory holds more than |maxSize| bytes of them.
+//
+// A nil *diskCache caches nothing.
+type diskCache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	entries map[hash.Hash]*list.Element
+	// lru holds the diskEntry of each cached chunk, most recently used first
+	lru  *list.List
+	size int64
+
+	metrics cacheMetrics
+}
+
+type diskEntry struct {
+	addr hash.Hash
+	size int64
+}
+
+// newDiskCache returns a diskCache in |dir|, which keeps the chunks
+// cached there by earlier processes.
+func newDiskCache(dir string, maxSize int64) (*diskCache, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dc := &diskCache{
+		dir:     dir,
+		maxSize: maxSize,
+		entries: make(map[hash.Hash]*list.Element),
+		lru:     list.New(),
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(f.Name(), diskCacheTempPrefix) {
+			// left by a process which exited while writing it
+			_ = os.Remove(filepath.Join(dir, f.Name()))
+			continue
+		}
+		addr, ok := hash.MaybeParse(f.Name())
+		if !ok {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		dc.entries[addr] = dc.lru.PushBack(diskEntry{addr: addr, size: info.Size()})
+		dc.size += info.Size()
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.shrinkToMaxSize()
+	return dc, nil
+}
+
+func (dc *diskCache) path(addr hash.Hash) string {
+	return filepath.Join(dc.dir, addr.String())
+}
+
+// get returns the contents of the chunk |addr|, if it's cached.
+func (dc *diskCache) get(addr hash.Hash) ([]byte, bool) {
+	if dc == nil {
+		return nil, false
+	}
+
+	dc.mu.Lock()
+	e, ok := dc.entries[addr]
+	if ok {
+		dc.lru.MoveToFront(e)
+	}
+	dc.mu.Unlock()
+
+	if ok {
+		data, err := os.ReadFile(dc.path(addr))
+		if err == nil && hash.Of(data) == addr {
+			dc.metrics.hits.Add(1)
+			return data, true
+		}
+		// the file was removed or corrupted by something else
+		dc.remove(addr)
+	}
+	dc.metrics.misses.Add(1)
+	return nil, false
+}
+
+// put caches |data| as the contents of the chunk |addr|. Errors writing
+// it are ignored, leaving the chunk uncached.
+func (dc *diskCache) put(addr hash.Hash, data []byte) {
+	if dc == nil || int64(len(data)) > dc.maxSize {
+		return
+	}
+	dc.mu.Lock()
+	_, ok := dc.entries[addr]
+	dc.mu.Unlock()
+	if ok {
+		return
+	}
+
+	// chunks are renamed into place, so they're never read partially written
+	f, err := os.CreateTemp(dc.dir, diskCacheTempPrefix+"*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), dc.path(addr))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if _, ok = dc.entries[addr]; ok {
+		return
+	}
+	dc.entries[addr] = dc.lru.PushFront(diskEntry{addr: addr, size: int64(len(data))})
+	dc.size += int64(len(data))
+	dc.shrinkToMaxSize()
+}
+
+func (dc *diskCache) remove(addr hash.Hash) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if e, ok := dc.entries[addr]; ok {
+		dc.removeEntry(e)
+	}
+}
+
+// shrinkToMaxSize removes the least recently used chunks until the cache
+// is within its size. Callers must hold |dc.mu|.
+func (dc *diskCache) shrinkToMaxSize() {
+	for dc.size > dc.maxSize {
+		dc.removeEntry(dc.lru.Back())
+		dc.metrics.evictions.Add(1)
+	}
+}
+
+func (dc *diskCache) removeEntry(e *list.Element) {
+	de := dc.lru.Remove(e).(diskEntry)
+	delete(dc.entries, de.addr)
+	dc.size -= de.size
+	_ = os.Remove(dc.path(de.addr))
+}
+
+// sizes returns the total size of the cached chunks, and the size the
+// cache is limited to.
+func (dc *diskCache) sizes() (size, capacity uint64) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	return uint64(dc.size), uint64(dc.maxSize)
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestDiskCache(t *testing.T) {
+	chunk := func(i byte) ([]byte, hash.Hash) {
+		data := make([]byte, 100)
+		data[0] = i
+		return data, hash.Of(data)
+	}
+
+	t.Run("put and get", func(t *testing.T) {
+		dc, err := newDiskCache(t.TempDir(), 1000)
+		require.NoError(t, err)
+
+		data, addr := chunk(1)
+		_, ok := dc.get(addr)
+		assert.False(t, ok)
+		dc.put(addr, data)
+		got, ok := dc.get(addr)
+		require.True(t, ok)
+		assert.Equal(t, data, got)
+
+		assert.Equal(t, uint64(1), dc.metrics.hits.Load())
+		assert.Equal(t, uint64(1), dc.metrics.misses.Load())
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		dc, err := newDiskCache(t.TempDir(), 300)
+		require.NoError(t, err)
+
+		addrs := make([]hash.Hash, 4)
+		for i := range addrs {
+			var data []byte
+			data, addrs[i] = chunk(byte(i))
+			dc.put(addrs[i], data)
+			if i == 2 {
+				// chunk 0 is used more recently than chunk 1
+				_, ok := dc.get(addrs[0])
+				require.True(t, ok)
+			}
+		}
+
+		_, ok := dc.get(addrs[1])
+		assert.False(t, ok)
+		for _, i := range []int{0, 2, 3} {
+			_, ok = dc.get(addrs[i])
+			assert.True(t, ok)
+		}
+		assert.Equal(t, uint64(1), dc.metrics.evictions.Load())
+		size, capacity := dc.sizes()
+		assert.Equal(t, uint64(300), size)
+		assert.Equal(t, uint64(300), capacity)
+	})
+
+	t.Run("keeps chunks across restarts", func(t *testing.T) {
+		dir := t.TempDir()
+		dc, err := newDiskCache(dir, 1000)
+		require.NoError(t, err)
+		data, addr := chunk(1)
+		dc.put(addr, data)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, diskCacheTempPrefix+"partial"), []byte("x"), 0644))
+		dc, err = newDiskCache(dir, 1000)
+		require.NoError(t, err)
+		got, ok := dc.get(addr)
+		require.True(t, ok)
+		assert.Equal(t, data, got)
+		_, err = os.Stat(filepath.Join(dir, diskCacheTempPrefix+"partial"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("drops corrupted chunks", func(t *testing.T) {
+		dc, err := newDiskCache(t.TempDir(), 1000)
+		require.NoError(t, err)
+		data, addr := chunk(1)
+		dc.put(addr, data)
+
+		require.NoError(t, os.WriteFile(dc.path(addr), []byte("corrupted"), 0644))
+		_, ok := dc.get(addr)
+		assert.False(t, ok)
+		size, _ := dc.sizes()
+		assert.Equal(t, uint64(0), size)
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		var dc *diskCache
+		data, addr := chunk(1)
+		dc.put(addr, data)
+		_, ok := dc.get(addr)
+		assert.False(t, ok)
+	})
+}
@@ -15,11 +15,13 @@
 package tree
 
 import (
+	"bytes"
 	"context"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -536,22 +538,176 @@ func (i IndexedJsonDocument) replaceIntoCursor(ctx context.Context, keyPath json
 	return NewIndexedJsonDocument(ctx, newRoot, i.m.NodeStore), true, nil
 }
 
-// ArrayInsert is not yet implemented, so we call it on a types.JSONDocument instead.
-func (i IndexedJsonDocument) ArrayInsert(path string, val sql.JSONWrapper) (types.MutableJSON, bool, error) {
-	v, err := i.ToInterface()
+// ArrayInsert implements types.MutableJSON
+func (i IndexedJsonDocument) ArrayInsert(path string, val sql.JSONWrapper) (result types.MutableJSON, changed bool, err error) {
+	err = tryWithFallback(
+		i.ctx,
+		i,
+		func() error {
+			result, changed, err = i.tryArrayInsert(i.ctx, path, val)
+			return err
+		},
+		func(jsonDocument types.JSONDocument) error {
+			result, changed, err = jsonDocument.ArrayInsert(path, val)
+			return err
+		})
+	return result, changed, err
+}
+
+// tryArrayInsert inserts |val| into an array at the index given by the last element of |path|. Only inserting past the
+// end of a non-empty array is done in place: inserting before an existing element renumbers every element after it, so
+// this returns unsupportedPathError and the caller falls back on rewriting the document.
+func (i IndexedJsonDocument) tryArrayInsert(ctx context.Context, path string, val sql.JSONWrapper) (types.MutableJSON, bool, error) {
+	keyPath, err := jsonPathElementsFromMySQLJsonPath([]byte(path))
 	if err != nil {
 		return nil, false, err
 	}
-	return types.JSONDocument{Val: v}.ArrayInsert(path, val)
+	if keyPath.size() == 0 || !keyPath.getLastPathElement().isArrayIndex {
+		return nil, false, unsupportedPathError
+	}
+	index := keyPath.getLastPathElement().getArrayIndex()
+	keyPath.pop()
+
+	jsonCursor, nextIndex, ok, err := i.cursorAtEndOfArray(ctx, keyPath)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok || index < nextIndex {
+		return nil, false, unsupportedPathError
+	}
+
+	keyPath.appendArrayIndex(nextIndex)
+	return i.insertIntoCursor(ctx, keyPath, jsonCursor, val)
 }
 
-// ArrayAppend is not yet implemented, so we call it on a types.JSONDocument instead.
-func (i IndexedJsonDocument) ArrayAppend(path string, val sql.JSONWrapper) (types.MutableJSON, bool, error) {
-	v, err := i.ToInterface()
+// cursorAtEndOfArray returns a cursor pointing to the end of the last element of the array at |arrayPath|, along with
+// the index that a value appended to the array would have. If there is no non-empty array at |arrayPath|, it returns
+// false.
+func (i IndexedJsonDocument) cursorAtEndOfArray(ctx context.Context, arrayPath jsonLocation) (*JsonCursor, uint64, bool, error) {
+	// No element has an index this large, so the cursor stops at the end of the array's last element, if it has one.
+	endPath := arrayPath.Clone()
+	endPath.appendArrayIndex(math.MaxUint64)
+	jsonCursor, _, err := newJsonCursor(ctx, i.m.NodeStore, i.m.Root, endPath, false)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	cursorPath := jsonCursor.GetCurrentPath()
+	if cursorPath.size() != endPath.size() || cursorPath.getScannerState() != endOfValue {
+		return nil, 0, false, nil
+	}
+	lastPathElement := cursorPath.getLastPathElement()
+	if !lastPathElement.isArrayIndex {
+		return nil, 0, false, nil
+	}
+	parentPath := cursorPath.Clone()
+	parentPath.pop()
+	parentPath.setScannerState(startOfValue)
+	checkPath := arrayPath.Clone()
+	checkPath.setScannerState(startOfValue)
+	if compareJsonLocations(parentPath, checkPath) != 0 {
+		return nil, 0, false, nil
+	}
+	return jsonCursor, lastPathElement.getArrayIndex() + 1, true, nil
+}
+
+// ArrayAppend implements types.MutableJSON
+func (i IndexedJsonDocument) ArrayAppend(path string, val sql.JSONWrapper) (result types.MutableJSON, changed bool, err error) {
+	err = tryWithFallback(
+		i.ctx,
+		i,
+		func() error {
+			result, changed, err = i.tryArrayAppend(i.ctx, path, val)
+			return err
+		},
+		func(jsonDocument types.JSONDocument) error {
+			result, changed, err = jsonDocument.ArrayAppend(path, val)
+			return err
+		})
+	return result, changed, err
+}
+
+// tryArrayAppend appends |val| to the array at |path|, or wraps the scalar or object at |path| in an array ending with
+// |val|. Appending to a non-empty array only rewrites the chunk holding the end of the array; the rest of the document
+// is shared with the original.
+func (i IndexedJsonDocument) tryArrayAppend(ctx context.Context, path string, val sql.JSONWrapper) (types.MutableJSON, bool, error) {
+	keyPath, err := jsonPathElementsFromMySQLJsonPath([]byte(path))
+	if err != nil {
+		return nil, false, err
+	}
+
+	jsonCursor, found, err := newJsonCursor(ctx, i.m.NodeStore, i.m.Root, keyPath, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The supplied path may be 0-indexing into a scalar, which is the same as referencing the scalar. Remove
+	// the index and try again.
+	for !found && keyPath.size() > jsonCursor.jsonScanner.currentPath.size() {
+		lastKeyPathElement := keyPath.getLastPathElement()
+		if !lastKeyPathElement.isArrayIndex || lastKeyPathElement.getArrayIndex() != 0 {
+			// The key does not exist in the document.
+			return i, false, nil
+		}
+
+		keyPath.pop()
+		found = compareJsonLocations(keyPath, jsonCursor.jsonScanner.currentPath) == 0
+	}
+
+	if !found {
+		// The key does not exist in the document.
+		return i, false, nil
+	}
+
+	// Appending to a non-empty array only writes the new element after the last one.
+	endCursor, nextIndex, ok, err := i.cursorAtEndOfArray(ctx, keyPath)
 	if err != nil {
 		return nil, false, err
 	}
-	return types.JSONDocument{Val: v}.ArrayAppend(path, val)
+	if ok {
+		keyPath.appendArrayIndex(nextIndex)
+		return i.insertIntoCursor(ctx, keyPath, endCursor, val)
+	}
+
+	// Otherwise the value is an empty array, a scalar or an object, and is rewritten.
+	// The cursor is now pointing to the start of the value being appended to.
+	jsonChunker, err := newJsonChunker(ctx, jsonCursor, i.m.NodeStore)
+	if err != nil {
+		return IndexedJsonDocument{}, false, err
+	}
+
+	originalValue, err := jsonCursor.NextValue(ctx)
+	if err != nil {
+		return IndexedJsonDocument{}, false, err
+	}
+
+	insertedValueBytes, err := types.MarshallJson(val)
+	if err != nil {
+		return IndexedJsonDocument{}, false, err
+	}
+
+	originalValue = bytes.TrimSpace(originalValue)
+	if len(originalValue) > 0 && originalValue[0] == '[' {
+		elements := bytes.TrimSpace(originalValue[1 : len(originalValue)-1])
+		if len(elements) == 0 {
+			jsonChunker.appendJsonToBuffer([]byte(fmt.Sprintf("[%s]", insertedValueBytes)))
+		} else {
+			jsonChunker.appendJsonToBuffer([]byte(fmt.Sprintf("[%s,%s]", elements, insertedValueBytes)))
+		}
+	} else {
+		jsonChunker.appendJsonToBuffer([]byte(fmt.Sprintf("[%s,%s]", originalValue, insertedValueBytes)))
+	}
+	err = jsonChunker.processBuffer(ctx)
+	if err != nil {
+		return IndexedJsonDocument{}, false, err
+	}
+
+	newRoot, err := jsonChunker.Done(ctx)
+	if err != nil {
+		return IndexedJsonDocument{}, false, err
+	}
+
+	return NewIndexedJsonDocument(ctx, newRoot, i.m.NodeStore), true, nil
 }
 
 // Value implements driver.Valuer for interoperability with other go libraries
@@ -29,6 +29,8 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/types"
 	typetests "github.com/dolthub/go-mysql-server/sql/types/jsontests"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 // newIndexedJsonDocumentFromValue creates an IndexedJsonDocument from a provided value.
@@ -282,6 +284,129 @@ func TestIndexedJsonDocument_Set(t *testing.T) {
 	jsontests.RunJsonTests(t, testCases)
 }
 
+func TestIndexedJsonDocument_ArrayAppend(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	ns := NewTestNodeStore()
+
+	testCases := []struct {
+		doc      string
+		path     string
+		value    string
+		expected string
+		changed  bool
+	}{
+		{doc: `[1, 2]`, path: "$", value: `3`, expected: `[1, 2, 3]`, changed: true},
+		{doc: `[]`, path: "$", value: `"a"`, expected: `["a"]`, changed: true},
+		{doc: `{"a": [1], "b": 2}`, path: "$.a", value: `{"c": 3}`, expected: `{"a": [1, {"c": 3}], "b": 2}`, changed: true},
+		{doc: `{"a": [1], "b": 2}`, path: "$.b", value: `3`, expected: `{"a": [1], "b": [2, 3]}`, changed: true},
+		{doc: `{"a": 1}`, path: "$", value: `2`, expected: `[{"a": 1}, 2]`, changed: true},
+		{doc: `{"a": 1}`, path: "$.a[0]", value: `2`, expected: `{"a": [1, 2]}`, changed: true},
+		{doc: `[[1], [2]]`, path: "$[1]", value: `[3]`, expected: `[[1], [2, [3]]]`, changed: true},
+		{doc: `{"a": 1}`, path: "$.b", value: `2`, expected: `{"a": 1}`, changed: false},
+		{doc: `{"a": 1}`, path: "$.a[1]", value: `2`, expected: `{"a": 1}`, changed: false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %s %s", tc.doc, tc.path, tc.value), func(t *testing.T) {
+			doc := newIndexedJsonDocumentFromValue(t, ctx, ns, tc.doc)
+			newDoc, changed, err := doc.ArrayAppend(tc.path, types.MustJSON(tc.value))
+			require.NoError(t, err)
+			require.Equal(t, tc.changed, changed)
+
+			cmp, err := types.JSON.Compare(newDoc, types.MustJSON(tc.expected))
+			require.NoError(t, err)
+			require.Equal(t, 0, cmp)
+		})
+	}
+
+	t.Run("large document appends", func(t *testing.T) {
+		largeDoc := createLargeDocumentForTesting(t, ctx, ns)
+		for _, path := range []string{"$", "$[6].children", "$[8].children[6].children[4].children[3].children[0]"} {
+			t.Run(path, func(t *testing.T) {
+				newDoc, changed, err := largeDoc.ArrayAppend(path, types.MustJSON(`{"a": 1}`))
+				require.NoError(t, err)
+				require.True(t, changed)
+				require.IsType(t, IndexedJsonDocument{}, newDoc)
+
+				// the result is the same as appending to the unindexed document
+				v, err := largeDoc.Clone(ctx).ToInterface()
+				require.NoError(t, err)
+				expected, _, err := types.JSONDocument{Val: v}.ArrayAppend(path, types.MustJSON(`{"a": 1}`))
+				require.NoError(t, err)
+				cmp, err := types.JSON.Compare(newDoc, expected)
+				require.NoError(t, err)
+				require.Equal(t, 0, cmp)
+
+				// only the chunk holding the end of the array is rewritten
+				require.LessOrEqual(t, countNewLeafChunks(t, ctx, largeDoc, newDoc.(IndexedJsonDocument)), 2)
+			})
+		}
+	})
+}
+
+func TestIndexedJsonDocument_ArrayInsert(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	ns := NewTestNodeStore()
+
+	testCases := []struct {
+		doc      string
+		path     string
+		value    string
+		expected string
+		changed  bool
+	}{
+		{doc: `[1, 2]`, path: "$[2]", value: `3`, expected: `[1, 2, 3]`, changed: true},
+		{doc: `[1, 2]`, path: "$[5]", value: `3`, expected: `[1, 2, 3]`, changed: true},
+		{doc: `[1, 2]`, path: "$[0]", value: `3`, expected: `[3, 1, 2]`, changed: true},
+		{doc: `[]`, path: "$[0]", value: `1`, expected: `[1]`, changed: true},
+		{doc: `{"a": [1], "b": [2]}`, path: "$.a[1]", value: `3`, expected: `{"a": [1, 3], "b": [2]}`, changed: true},
+		{doc: `[[1], [2]]`, path: "$[0][4]", value: `[3]`, expected: `[[1, [3]], [2]]`, changed: true},
+		{doc: `{"a": 1}`, path: "$.a[1]", value: `2`, expected: `{"a": 1}`, changed: false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %s %s", tc.doc, tc.path, tc.value), func(t *testing.T) {
+			doc := newIndexedJsonDocumentFromValue(t, ctx, ns, tc.doc)
+			newDoc, changed, err := doc.ArrayInsert(tc.path, types.MustJSON(tc.value))
+			require.NoError(t, err)
+			require.Equal(t, tc.changed, changed)
+
+			cmp, err := types.JSON.Compare(newDoc, types.MustJSON(tc.expected))
+			require.NoError(t, err)
+			require.Equal(t, 0, cmp)
+		})
+	}
+
+	t.Run("large document inserts past the end of an array", func(t *testing.T) {
+		largeDoc := createLargeDocumentForTesting(t, ctx, ns)
+		newDoc, changed, err := largeDoc.ArrayInsert("$[6].children[100]", types.MustJSON(`{"a": 1}`))
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.IsType(t, IndexedJsonDocument{}, newDoc)
+		require.LessOrEqual(t, countNewLeafChunks(t, ctx, largeDoc, newDoc.(IndexedJsonDocument)), 2)
+	})
+}
+
+// countNewLeafChunks returns the number of leaf chunks of |newDoc| that aren't chunks of |oldDoc|.
+func countNewLeafChunks(t *testing.T, ctx context.Context, oldDoc, newDoc IndexedJsonDocument) int {
+	oldChunks := make(map[hash.Hash]struct{})
+	err := oldDoc.m.WalkNodes(ctx, func(ctx context.Context, nd Node) error {
+		if nd.IsLeaf() {
+			oldChunks[nd.HashOf()] = struct{}{}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	newChunks := 0
+	err = newDoc.m.WalkNodes(ctx, func(ctx context.Context, nd Node) error {
+		if _, ok := oldChunks[nd.HashOf()]; nd.IsLeaf() && !ok {
+			newChunks++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return newChunks
+}
+
 func TestIndexedJsonDocument_Value(t *testing.T) {
 	ctx := context.Background()
 	ns := NewTestNodeStore()
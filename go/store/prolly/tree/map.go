@@ -40,22 +40,43 @@ type StaticMap[K, V ~[]byte, O Ordering[K]] struct {
 // DiffOrderedTrees invokes `cb` for each difference between `from` and `to. If `considerAllRowsModified`
 // is true, then a key that exists in both trees will be considered a modification even if the bytes are the same.
 // This is used when `from` and `to` have different schemas.
+//
+// Diffs between trees that are compared repeatedly are served from a bounded cache keyed by the trees' root hashes.
 func DiffOrderedTrees[K, V ~[]byte, O Ordering[K]](
 	ctx context.Context,
 	from, to StaticMap[K, V, O],
 	considerAllRowsModified bool,
 	cb DiffFn,
 ) error {
+	key := diffCacheKey{
+		from:                    from.Root.HashOf(),
+		to:                      to.Root.HashOf(),
+		considerAllRowsModified: considerAllRowsModified,
+	}
+	if diffs, ok := sharedDiffCache.get(key); ok {
+		for _, diff := range diffs {
+			if err := cb(ctx, diff); err != nil {
+				return err
+			}
+		}
+		return io.EOF
+	}
+
 	differ, err := DifferFromRoots[K](ctx, from.NodeStore, to.NodeStore, from.Root, to.Root, from.Order, considerAllRowsModified)
 	if err != nil {
 		return err
 	}
 
+	var rec diffRecorder
 	for {
 		var diff Diff
 		if diff, err = differ.Next(ctx); err != nil {
+			if err == io.EOF && !rec.full && key.from != key.to {
+				sharedDiffCache.put(key, rec.diffs, rec.size+diffOverhead)
+			}
 			break
 		}
+		rec.record(diff)
 
 		if err = cb(ctx, diff); err != nil {
 			break
@@ -199,6 +199,11 @@ func (nd Node) bytes() []byte {
 	return nd.msg
 }
 
+// KeyPrefixCompressed returns true if |nd| is a node of a prolly map whose leaf nodes may have prefix compressed keys.
+func (nd Node) KeyPrefixCompressed() bool {
+	return message.IsKeyPrefixCompressed(nd.msg)
+}
+
 func walkAddresses(ctx context.Context, nd Node, cb AddressCb) (err error) {
 	return message.WalkAddresses(ctx, nd.msg, cb)
 }
@@ -17,6 +17,7 @@ package tree
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/dolthub/dolt/go/store/hash"
 )
@@ -28,19 +29,65 @@ const (
 
 func newChunkCache(maxSize int) (c nodeCache) {
 	sz := maxSize / numStripes
+	c.metrics = &cacheMetrics{}
 	for i := range c.stripes {
-		c.stripes[i] = newStripe(sz)
+		c.stripes[i] = newStripe(sz, c.metrics)
 	}
 	return
 }
 
 type nodeCache struct {
 	stripes [numStripes]*stripe
+	metrics *cacheMetrics
+}
+
+// cacheMetrics count the lookups and evictions of a cache.
+type cacheMetrics struct {
+	hits, misses, evictions atomic.Uint64
 }
 
 func (c nodeCache) get(addr hash.Hash) (Node, bool) {
 	s := c.stripes[addr[0]&stripeMask]
-	return s.get(addr)
+	n, ok := s.get(addr)
+	if ok {
+		c.metrics.hits.Add(1)
+	} else {
+		c.metrics.misses.Add(1)
+	}
+	return n, ok
+}
+
+// has returns whether the Node |addr| is cached, without counting
+// a lookup or marking it as recently used.
+func (c nodeCache) has(addr hash.Hash) bool {
+	s := c.stripes[addr[0]&stripeMask]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.chunks[addr]
+	return ok
+}
+
+// sizes returns the total size of the cached Nodes, and the size the
+// cache is limited to.
+func (c nodeCache) sizes() (size, capacity uint64) {
+	for _, s := range c.stripes {
+		s.mu.Lock()
+		size += uint64(s.sz)
+		capacity += uint64(s.maxSz)
+		s.mu.Unlock()
+	}
+	return
+}
+
+// purge drops every cached Node.
+func (c nodeCache) purge() {
+	for _, s := range c.stripes {
+		s.mu.Lock()
+		s.chunks = make(map[hash.Hash]*centry)
+		s.head = nil
+		s.sz = 0
+		s.mu.Unlock()
+	}
 }
 
 func (c nodeCache) insert(addr hash.Hash, node Node) {
@@ -63,9 +110,11 @@ type stripe struct {
 	sz     int
 	maxSz  int
 	rev    int
+
+	metrics *cacheMetrics
 }
 
-func newStripe(maxSize int) *stripe {
+func newStripe(maxSize int, metrics *cacheMetrics) *stripe {
 	return &stripe{
 		&sync.Mutex{},
 		make(map[hash.Hash]*centry),
@@ -73,6 +122,7 @@ func newStripe(maxSize int) *stripe {
 		0,
 		maxSize,
 		0,
+		metrics,
 	}
 }
 
@@ -137,6 +187,7 @@ func (s *stripe) shrinkToMaxSz() {
 			}
 			delete(s.chunks, t.a)
 			s.sz -= t.n.Size()
+			s.metrics.evictions.Add(1)
 		} else {
 			panic("cache is empty but cache Size is > than max Size")
 		}
@@ -35,6 +35,10 @@ type cursor struct {
 	idx    int
 	parent *cursor
 	nrw    NodeStore
+
+	// aheadFrom and aheadTo are the range of indexes
+	// in the parent Node that have been read ahead.
+	aheadFrom, aheadTo int
 }
 
 type SearchFn func(nd Node) (idx int)
@@ -515,6 +519,9 @@ func (cur *cursor) advance(ctx context.Context) error {
 	}
 
 	cur.skipToNodeStart()
+	if cur.isLeaf() {
+		cur.readAhead()
+	}
 	return nil
 }
 
@@ -555,8 +562,13 @@ func (cur *cursor) retreat(ctx context.Context) error {
 
 // fetchNode loads the Node that the cursor index points to.
 // It's called whenever the cursor advances/retreats to a different chunk.
+// It's also where long scans notice that their context was
+// canceled, since cached nodes are read without checking it.
 func (cur *cursor) fetchNode(ctx context.Context) (err error) {
 	assertTrue(cur.parent != nil, "cannot fetch node for cursor with nil parent")
+	if err = ctx.Err(); err != nil {
+		return err
+	}
 	cur.nd, err = fetchChild(ctx, cur.nrw, cur.parent.currentRef())
 	cur.idx = -1 // caller must set
 	return err
@@ -599,6 +611,7 @@ func (cur *cursor) copy(other *cursor) {
 	cur.nd = other.nd
 	cur.idx = other.idx
 	cur.nrw = other.nrw
+	cur.aheadFrom, cur.aheadTo = 0, 0
 
 	if cur.parent != nil {
 		assertTrue(other.parent != nil, "cursors must be of equal height to call copy()")
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/prolly/message"
 	"github.com/dolthub/dolt/go/store/val"
 )
@@ -69,6 +70,81 @@ func TestNodeCursor(t *testing.T) {
 		}
 		assert.Equal(t, 10_000/2, i)
 	})
+
+	t.Run("advance stops at chunk boundary when canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		root, items, ns := randomTree(t, 10_000)
+		cur, err := newCursorAtStart(ctx, ns, root)
+		require.NoError(t, err)
+
+		cancel()
+		i := 1
+		for ; i < len(items); i++ {
+			if err = cur.advance(ctx); err != nil {
+				break
+			}
+		}
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, i, len(items))
+	})
+
+	t.Run("reads are counted in read stats", func(t *testing.T) {
+		var stats ReadStats
+		ctx := WithReadStats(context.Background(), &stats)
+		root, items, ns := randomTree(t, 10_000)
+		cur, err := newCursorAtStart(ctx, ns, root)
+		require.NoError(t, err)
+		for i := 1; i < len(items); i++ {
+			require.NoError(t, cur.advance(ctx))
+		}
+
+		assert.Greater(t, stats.NodesRead.Load(), uint64(1))
+		// every node was cached when the tree was written
+		assert.Equal(t, stats.NodesRead.Load(), stats.CacheHits.Load())
+	})
+
+	t.Run("forward scans read ahead", func(t *testing.T) {
+		defer SetReadAheadDepth(ReadAheadDepth())
+		ctx := context.Background()
+		root, items, ns := randomTree(t, 10_000)
+
+		SetReadAheadDepth(4)
+		rec := &readAheadRecorder{NodeStore: ns}
+		cur, err := newCursorAtStart(ctx, rec, root)
+		require.NoError(t, err)
+		for i := 1; i < len(items); i++ {
+			atEnd := cur.atNodeEnd()
+			require.NoError(t, cur.advance(ctx))
+			if atEnd && cur.parent.idx > 1 {
+				// leaves are read ahead from the second leaf a scan reaches under each parent
+				assert.Contains(t, rec.refs, cur.parent.currentRef())
+			}
+		}
+		require.NotEmpty(t, rec.refs)
+		seen := make(map[hash.Hash]bool)
+		for _, r := range rec.refs {
+			assert.False(t, seen[r], "leaves are read ahead once")
+			seen[r] = true
+		}
+
+		SetReadAheadDepth(0)
+		rec = &readAheadRecorder{NodeStore: ns}
+		cur, err = newCursorAtStart(ctx, rec, root)
+		require.NoError(t, err)
+		for i := 1; i < len(items); i++ {
+			require.NoError(t, cur.advance(ctx))
+		}
+		assert.Empty(t, rec.refs)
+	})
+}
+
+type readAheadRecorder struct {
+	NodeStore
+	refs hash.HashSlice
+}
+
+func (r *readAheadRecorder) readAhead(refs hash.HashSlice) {
+	r.refs = append(r.refs, refs...)
 }
 
 func testNewCursorAtItem(t *testing.T, count int) {
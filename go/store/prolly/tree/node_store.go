@@ -54,13 +54,14 @@ type NodeStore interface {
 type nodeStore struct {
 	store chunks.ChunkStore
 	cache nodeCache
+	disk  *diskCache
 	bp    pool.BuffPool
 	bbp   *sync.Pool
 }
 
 var _ NodeStore = nodeStore{}
 
-var sharedCache = newChunkCache(cacheSize)
+var sharedCache = newChunkCache(cacheConfig.memorySize)
 
 var sharedPool = pool.NewBuffPool()
 
@@ -84,8 +85,17 @@ func NewNodeStore(cs chunks.ChunkStore) NodeStore {
 func (ns nodeStore) Read(ctx context.Context, ref hash.Hash) (Node, error) {
 	n, ok := ns.cache.get(ref)
 	if ok {
+		recordReads(ctx, 1, 1)
 		return n, nil
 	}
+	recordReads(ctx, 1, 0)
+
+	if data, ok := ns.disk.get(ref); ok {
+		if nd, err := NodeFromBytes(data); err == nil {
+			ns.cache.insert(ref, nd)
+			return nd, nil
+		}
+	}
 
 	c, err := ns.store.Get(ctx, ref)
 	if err != nil {
@@ -98,6 +108,7 @@ func (ns nodeStore) Read(ctx context.Context, ref hash.Hash) (Node, error) {
 		return Node{}, err
 	}
 	ns.cache.insert(ref, n)
+	ns.disk.put(ref, c.Data())
 
 	return n, nil
 }
@@ -115,21 +126,14 @@ func (ns nodeStore) ReadMany(ctx context.Context, addrs hash.HashSlice) ([]Node,
 			gets.Insert(r)
 		}
 	}
+	recordReads(ctx, len(addrs), len(found))
 
-	var nerr error
 	mu := new(sync.Mutex)
-	err := ns.store.GetMany(ctx, gets, func(ctx context.Context, chunk *chunks.Chunk) {
-		n, err := NodeFromBytes(chunk.Data())
-		if err != nil {
-			nerr = err
-		}
+	err := ns.readUncached(ctx, gets, func(addr hash.Hash, n Node) {
 		mu.Lock()
-		found[chunk.Hash()] = n
+		found[addr] = n
 		mu.Unlock()
 	})
-	if err == nil {
-		err = nerr
-	}
 	if err != nil {
 		return nil, err
 	}
@@ -145,6 +149,47 @@ func (ns nodeStore) ReadMany(ctx context.Context, addrs hash.HashSlice) ([]Node,
 	return nodes, nil
 }
 
+// readUncached reads the Nodes |addrs|, which aren't in the memory cache,
+// from the disk cache or else the ChunkStore, adding the Nodes read from
+// the ChunkStore to the disk cache. |cb| is called with each Node read,
+// possibly concurrently.
+func (ns nodeStore) readUncached(ctx context.Context, addrs hash.HashSet, cb func(addr hash.Hash, n Node)) error {
+	if ns.disk != nil {
+		remaining := make(hash.HashSet, len(addrs))
+		for addr := range addrs {
+			if data, ok := ns.disk.get(addr); ok {
+				if n, err := NodeFromBytes(data); err == nil {
+					cb(addr, n)
+					continue
+				}
+			}
+			remaining.Insert(addr)
+		}
+		addrs = remaining
+	}
+	if len(addrs) == 0 {
+		return nil
+	}
+
+	var nerr error
+	mu := new(sync.Mutex)
+	err := ns.store.GetMany(ctx, addrs, func(ctx context.Context, chunk *chunks.Chunk) {
+		n, err := NodeFromBytes(chunk.Data())
+		if err != nil {
+			mu.Lock()
+			nerr = err
+			mu.Unlock()
+			return
+		}
+		ns.disk.put(chunk.Hash(), chunk.Data())
+		cb(chunk.Hash(), n)
+	})
+	if err == nil {
+		err = nerr
+	}
+	return err
+}
+
 // Write implements NodeStore.
 func (ns nodeStore) Write(ctx context.Context, nd Node) (hash.Hash, error) {
 	c := chunks.NewChunk(nd.bytes())
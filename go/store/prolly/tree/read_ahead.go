@@ -0,0 +1,137 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// DefaultReadAheadDepth is the number of leaf Nodes forward scans read
+// ahead of their cursor, unless changed with SetReadAheadDepth.
+const DefaultReadAheadDepth = 4
+
+// maxReadAheads bounds the number of read-aheads in flight. Scans skip
+// reading ahead while it's reached, rather than waiting.
+const maxReadAheads = 32
+
+var readAheadDepth atomic.Int32
+
+var readAheadSem = make(chan struct{}, maxReadAheads)
+
+func init() {
+	readAheadDepth.Store(DefaultReadAheadDepth)
+}
+
+// ReadAheadDepth returns the number of leaf Nodes forward scans read ahead
+// of their cursor.
+func ReadAheadDepth() int {
+	return int(readAheadDepth.Load())
+}
+
+// SetReadAheadDepth sets the number of leaf Nodes forward scans read ahead
+// of their cursor. Read-ahead is disabled when |depth| is 0.
+func SetReadAheadDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	readAheadDepth.Store(int32(depth))
+}
+
+// readAheader is a NodeStore which can read Nodes into its cache in the
+// background.
+type readAheader interface {
+	readAhead(refs hash.HashSlice)
+}
+
+var _ readAheader = nodeStore{}
+
+// readAhead reads the Nodes |refs| which aren't cached into the cache,
+// without waiting for them. Read-aheads aren't counted in ReadStats; the
+// scan which issued them counts the Nodes when it reaches them.
+func (ns nodeStore) readAhead(refs hash.HashSlice) {
+	gets := hash.HashSet{}
+	for _, r := range refs {
+		if !ns.cache.has(r) {
+			gets.Insert(r)
+		}
+	}
+	if len(gets) == 0 {
+		return
+	}
+
+	select {
+	case readAheadSem <- struct{}{}:
+	default:
+		return
+	}
+	go func() {
+		defer func() { <-readAheadSem }()
+		// errors are left for the scan to find when it reads the Nodes
+		_ = ns.readUncached(context.Background(), gets, func(addr hash.Hash, n Node) {
+			ns.cache.insert(addr, n)
+		})
+	}()
+}
+
+// readAhead starts reading the leaf Nodes after this leaf cursor's Node,
+// its next siblings under its parent, so they're cached by the time a
+// forward scan reaches them. Reads are issued in batches, once the scan
+// is halfway through the Nodes read ahead of it.
+func (cur *cursor) readAhead() {
+	depth := ReadAheadDepth()
+	if depth <= 0 || cur.parent == nil {
+		return
+	}
+	ra, ok := cur.nrw.(readAheader)
+	if !ok {
+		return
+	}
+
+	p := cur.parent
+	next := p.idx + 1
+	if next <= cur.aheadFrom {
+		// the parent cursor moved to a new Node
+		cur.aheadFrom, cur.aheadTo = 0, 0
+	}
+	if cur.aheadTo-next > depth/2 {
+		return
+	}
+
+	start := next
+	if cur.aheadTo > start {
+		start = cur.aheadTo
+	}
+	end := next + depth
+	if end > p.nd.Count() {
+		end = p.nd.Count()
+	}
+	if start >= end {
+		return
+	}
+
+	refs := make(hash.HashSlice, 0, end-start)
+	for i := start; i < end; i++ {
+		refs = append(refs, p.nd.getAddress(i))
+	}
+	ra.readAhead(refs)
+
+	if cur.aheadTo == 0 {
+		cur.aheadFrom = start
+	}
+	cur.aheadTo = end
+}
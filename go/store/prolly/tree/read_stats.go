@@ -0,0 +1,46 @@
+// Copyright 2024 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ReadStats counts the Nodes read by NodeStores with a context carrying it.
+type ReadStats struct {
+	// NodesRead is the number of Nodes read, whether from the cache or the ChunkStore.
+	NodesRead atomic.Uint64
+	// CacheHits is the number of Nodes read from the cache.
+	CacheHits atomic.Uint64
+}
+
+type readStatsKey struct{}
+
+// WithReadStats returns a context which counts the Nodes read with it in |stats|.
+func WithReadStats(ctx context.Context, stats *ReadStats) context.Context {
+	return context.WithValue(ctx, readStatsKey{}, stats)
+}
+
+// recordReads adds |read| Nodes, |hits| of which were cached, to the ReadStats of |ctx|, if it has any.
+func recordReads(ctx context.Context, read, hits int) {
+	if ctx == nil {
+		return
+	}
+	if stats, ok := ctx.Value(readStatsKey{}).(*ReadStats); ok {
+		stats.NodesRead.Add(uint64(read))
+		stats.CacheHits.Add(uint64(hits))
+	}
+}
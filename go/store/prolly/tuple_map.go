@@ -63,7 +63,7 @@ type TupleIter interface {
 }
 
 func NewMapFromTupleIter(ctx context.Context, ns tree.NodeStore, keyDesc, valDesc val.TupleDesc, iter TupleIter) (Map, error) {
-	serializer := message.NewProllyMapSerializer(valDesc, ns.Pool())
+	serializer := message.NewProllyMapSerializer(valDesc, ns.Pool()).WithKeyPrefixCompression(keyDesc, false)
 	ch, err := tree.NewEmptyChunker(ctx, ns, serializer)
 	if err != nil {
 		return Map{}, err
@@ -93,7 +93,7 @@ func NewMapFromTupleIter(ctx context.Context, ns tree.NodeStore, keyDesc, valDes
 
 func MutateMapWithTupleIter(ctx context.Context, m Map, iter TupleIter) (Map, error) {
 	fn := tree.ApplyMutations[val.Tuple, val.TupleDesc, message.ProllyMapSerializer]
-	s := message.NewProllyMapSerializer(m.valDesc, m.tuples.NodeStore.Pool())
+	s := message.NewProllyMapSerializer(m.valDesc, m.tuples.NodeStore.Pool()).WithKeyPrefixCompression(m.keyDesc, m.KeyPrefixCompressed())
 
 	root, err := fn(ctx, m.tuples.NodeStore, m.tuples.Root, m.keyDesc, s, mutationIter{iter: iter})
 	if err != nil {
@@ -167,7 +167,8 @@ func makeDiffCallBack(from, to Map, innerCb tree.DiffFn) tree.DiffFn {
 }
 
 func MergeMaps(ctx context.Context, left, right, base Map, cb tree.CollisionFn) (Map, tree.MergeStats, error) {
-	serializer := message.NewProllyMapSerializer(left.valDesc, base.NodeStore().Pool())
+	compressed := left.KeyPrefixCompressed() || right.KeyPrefixCompressed() || base.KeyPrefixCompressed()
+	serializer := message.NewProllyMapSerializer(left.valDesc, base.NodeStore().Pool()).WithKeyPrefixCompression(left.keyDesc, compressed)
 	// TODO: MergeMaps does not properly detect merge conflicts when one side adds a NULL to the end of its tuple.
 	// To fix this, accurate values of `leftSchemaChanged` and `rightSchemaChanged` must be computed.
 	// However, since `MergeMaps` is not currently called, fixing this is not a priority.
@@ -232,6 +233,12 @@ func (m Map) HashOf() hash.Hash {
 	return m.tuples.HashOf()
 }
 
+// KeyPrefixCompressed returns true if the keys of this Map's leaf nodes may be prefix compressed, in which case it
+// stays compressed as it is changed.
+func (m Map) KeyPrefixCompressed() bool {
+	return m.tuples.Root.KeyPrefixCompressed()
+}
+
 // Format returns the NomsBinFormat of this Map.
 func (m Map) Format() *types.NomsBinFormat {
 	return m.tuples.NodeStore.Format()
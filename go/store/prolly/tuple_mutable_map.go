@@ -19,6 +19,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/dolthub/dolt/go/store/prolly/message"
 	"github.com/dolthub/dolt/go/store/prolly/tree"
@@ -26,19 +27,43 @@ import (
 )
 
 const (
-	defaultMaxPending = 64 * 1024
+	// defaultMaxPendingSize is the size, in bytes, of the pending writes
+	// a MutableMap, or the maps sharing a PendingSizeLimit, accumulate in
+	// memory before flushing them to their trees.
+	defaultMaxPendingSize = 32 * 1024 * 1024
+
+	// pendingEditOverhead approximates the memory used by a pending write
+	// in addition to its key and value, for its skip.List node.
+	pendingEditOverhead = 96
 )
 
+// PendingSizeLimit limits the combined size of the pending writes of the
+// MutableMaps sharing it, like the maps of every table and index written by
+// a session, so that their memory use is bounded however many maps there are.
+// Once the limit is exceeded, each map flushes its pending writes the next
+// time it's written.
+type PendingSizeLimit struct {
+	max  int64
+	size atomic.Int64
+}
+
+// NewPendingSizeLimit returns a new PendingSizeLimit of the default size.
+func NewPendingSizeLimit() *PendingSizeLimit {
+	return &PendingSizeLimit{max: defaultMaxPendingSize}
+}
+
 // MutableMap is an ordered collection of val.Tuple backed by a Prolly Tree.
 // Writes to the map are queued in a skip.List and periodically flushed when
-// the maximum number of pending writes is exceeded.
+// the pending writes exceed the map's memory threshold, or its maximum number
+// of pending writes, if it has one. Large writes, like multi-row INSERTs,
+// are flushed in a few large batches rather than many small ones.
 type MutableMap struct {
 	// tuples contains the primary Prolly Tree and skip.List for this map.
 	tuples tree.MutableMap[val.Tuple, val.Tuple, val.TupleDesc]
 
 	// stash, if not nil, contains a previous checkpoint of this map.
 	// stashes are created when a MutableMap has been check-pointed, but
-	// the number of in-memory pending writes exceeds, maxPending.
+	// the in-memory pending writes exceed the map's buffer limits.
 	// In this case we stash a copy MutableMap containing the checkpoint,
 	// flush the pending writes and continue accumulating
 	stash *tree.MutableMap[val.Tuple, val.Tuple, val.TupleDesc]
@@ -46,17 +71,27 @@ type MutableMap struct {
 	// keyDesc and valDesc are tuples descriptors for the map.
 	keyDesc, valDesc val.TupleDesc
 
-	// buffer size
-	maxPending int
+	// buffer limits, disabled when 0. maxPending is a number
+	// of writes, and maxPendingSize a number of bytes, which
+	// is replaced by |sharedLimit| if it's set.
+	maxPending     int
+	maxPendingSize int
+	sharedLimit    *PendingSizeLimit
+
+	// pendingSize is the approximate size of the pending writes,
+	// counting each key once however many times it's written,
+	// and checkpointSize its value at the last checkpoint.
+	pendingSize    int
+	checkpointSize int
 }
 
 // newMutableMap returns a new MutableMap.
 func newMutableMap(m Map) *MutableMap {
 	return &MutableMap{
-		tuples:     m.tuples.Mutate(),
-		keyDesc:    m.keyDesc,
-		valDesc:    m.valDesc,
-		maxPending: defaultMaxPending,
+		tuples:         m.tuples.Mutate(),
+		keyDesc:        m.keyDesc,
+		valDesc:        m.valDesc,
+		maxPendingSize: defaultMaxPendingSize,
 	}
 }
 
@@ -64,16 +99,16 @@ func newMutableMap(m Map) *MutableMap {
 // values specified in |kd| and |vd|. This is useful if you are rewriting the data in a map to change its schema.
 func newMutableMapWithDescriptors(m Map, kd, vd val.TupleDesc) *MutableMap {
 	return &MutableMap{
-		tuples:     m.tuples.Mutate(),
-		keyDesc:    kd,
-		valDesc:    vd,
-		maxPending: defaultMaxPending,
+		tuples:         m.tuples.Mutate(),
+		keyDesc:        kd,
+		valDesc:        vd,
+		maxPendingSize: defaultMaxPendingSize,
 	}
 }
 
 // Map materializes all pending and applied mutations in the MutableMap.
 func (mut *MutableMap) Map(ctx context.Context) (Map, error) {
-	s := message.NewProllyMapSerializer(mut.valDesc, mut.NodeStore().Pool())
+	s := message.NewProllyMapSerializer(mut.valDesc, mut.NodeStore().Pool()).WithKeyPrefixCompression(mut.keyDesc, mut.tuples.Static.Root.KeyPrefixCompressed())
 	return mut.flushWithSerializer(ctx, s)
 }
 
@@ -104,6 +139,17 @@ func (mut *MutableMap) WithMaxPending(max int) *MutableMap {
 	return &ret
 }
 
+// WithPendingSizeLimit returns a MutableMap whose pending writes count
+// towards |limit|, which it shares with other maps, instead of its own limit.
+func (mut *MutableMap) WithPendingSizeLimit(limit *PendingSizeLimit) *MutableMap {
+	ret := *mut
+	ret.sharedLimit = limit
+	if limit != nil {
+		limit.size.Add(int64(ret.pendingSize))
+	}
+	return &ret
+}
+
 // NodeStore returns the map's NodeStore
 func (mut *MutableMap) NodeStore() tree.NodeStore {
 	return mut.tuples.Static.NodeStore
@@ -111,18 +157,57 @@ func (mut *MutableMap) NodeStore() tree.NodeStore {
 
 // Put adds the Tuple pair |key|, |value| to the MutableMap.
 func (mut *MutableMap) Put(ctx context.Context, key, value val.Tuple) error {
+	delta := mut.editSize(key, value)
 	if err := mut.tuples.Put(ctx, key, value); err != nil {
 		return err
 	}
-	if mut.tuples.Edits.Count() > mut.maxPending {
+	mut.addPendingSize(delta)
+	if mut.overPendingLimit() {
 		return mut.flushPending(ctx)
 	}
 	return nil
 }
 
+// editSize returns how much writing |value| to |key| changes the size of
+// the pending writes. Writing a key which already has a pending write
+// only changes it by the difference in the size of the values.
+func (mut *MutableMap) editSize(key, value val.Tuple) int {
+	if prev, ok := mut.tuples.Edits.Get(key); ok {
+		return len(value) - len(prev)
+	}
+	return len(key) + len(value) + pendingEditOverhead
+}
+
+// addPendingSize adds |delta| to the size of the pending writes.
+func (mut *MutableMap) addPendingSize(delta int) {
+	mut.pendingSize += delta
+	if mut.sharedLimit != nil {
+		mut.sharedLimit.size.Add(int64(delta))
+	}
+}
+
+// overPendingLimit returns whether the pending writes should be flushed.
+func (mut *MutableMap) overPendingLimit() bool {
+	if mut.maxPending > 0 && mut.tuples.Edits.Count() > mut.maxPending {
+		return true
+	}
+	if mut.sharedLimit != nil {
+		return mut.pendingSize > 0 && mut.sharedLimit.size.Load() > mut.sharedLimit.max
+	}
+	return mut.maxPendingSize > 0 && mut.pendingSize > mut.maxPendingSize
+}
+
 // Delete deletes the pair keyed by |key| from the MutableMap.
 func (mut *MutableMap) Delete(ctx context.Context, key val.Tuple) error {
-	return mut.tuples.Delete(ctx, key)
+	delta := mut.editSize(key, nil)
+	if err := mut.tuples.Delete(ctx, key); err != nil {
+		return err
+	}
+	mut.addPendingSize(delta)
+	if mut.overPendingLimit() {
+		return mut.flushPending(ctx)
+	}
+	return nil
 }
 
 // Get fetches the Tuple pair keyed by |key|, if it exists, and passes it to |cb|.
@@ -150,6 +235,7 @@ func (mut *MutableMap) Checkpoint(context.Context) error {
 	// discard previous stash, if one exists
 	mut.stash = nil
 	mut.tuples.Edits.Checkpoint()
+	mut.checkpointSize = mut.pendingSize
 	return nil
 }
 
@@ -158,6 +244,7 @@ func (mut *MutableMap) Revert(context.Context) {
 	// if we've accumulated a large number of writes
 	// since we check-pointed, our last checkpoint
 	// may be stashed in a separate tree.MutableMap
+	mut.addPendingSize(mut.checkpointSize - mut.pendingSize)
 	if mut.stash != nil {
 		mut.tuples = *mut.stash
 		return
@@ -180,6 +267,7 @@ func (mut *MutableMap) flushPending(ctx context.Context) error {
 	}
 	mut.tuples.Static = sm.tuples
 	mut.tuples.Edits.Truncate() // reuse skip list
+	mut.addPendingSize(-mut.pendingSize)
 	mut.stash = stash
 	return nil
 }
@@ -40,6 +40,16 @@ func LesserRange(stop val.Tuple, desc val.TupleDesc) Range {
 	return lesserRange(stop, desc)
 }
 
+// ClosedRange defines an inclusive Range of Tuples [start, stop].
+func ClosedRange(start, stop val.Tuple, desc val.TupleDesc) Range {
+	return closedRange(start, stop, desc)
+}
+
+// LesserOrEqualRange defines a Range of Tuples less than or equal to |stop|.
+func LesserOrEqualRange(stop val.Tuple, desc val.TupleDesc) Range {
+	return lesserOrEqualRange(stop, desc)
+}
+
 // PrefixRange constructs a Range for Tuples with a prefix of |prefix|.
 func PrefixRange(prefix val.Tuple, desc val.TupleDesc) Range {
 	return closedRange(prefix, prefix, desc)
@@ -333,7 +343,8 @@ func IncrementTuple(start val.Tuple, n int, desc val.TupleDesc, pool pool.BuffPo
 		if !ok {
 			return nil, false
 		}
-		tb.PutDecimal(n, v.Add(decimal.New(1, 0)))
+		// increment the finest precision of |v|, which has the scale of its column
+		tb.PutDecimal(n, v.Add(decimal.New(1, v.Exponent())))
 	default:
 		return nil, false
 	}
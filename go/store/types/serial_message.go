@@ -332,7 +332,7 @@ func (sm SerialMessage) HumanReadableStringAtIndentationLevel(level int) string
 		level -= 1
 		printWithIndendationLevel(level, ret, "}\n")
 		return ret.String()
-	case serial.ProllyTreeNodeFileID:
+	case serial.ProllyTreeNodeFileID, serial.ProllyTreeNodeCompressedFileID:
 		ret := &strings.Builder{}
 		printWithIndendationLevel(level, ret, "{\n")
 		level++
@@ -691,7 +691,7 @@ func (sm SerialMessage) WalkAddrs(nbf *NomsBinFormat, cb func(addr hash.Hash) er
 	case serial.TableSchemaFileID, serial.ForeignKeyCollectionFileID:
 		// no further references from these file types
 		return nil
-	case serial.ProllyTreeNodeFileID, serial.AddressMapFileID, serial.MergeArtifactsFileID, serial.BlobFileID, serial.CommitClosureFileID:
+	case serial.ProllyTreeNodeFileID, serial.ProllyTreeNodeCompressedFileID, serial.AddressMapFileID, serial.MergeArtifactsFileID, serial.BlobFileID, serial.CommitClosureFileID:
 		return message.WalkAddresses(context.TODO(), serial.Message(sm), func(ctx context.Context, addr hash.Hash) error {
 			return cb(addr)
 		})
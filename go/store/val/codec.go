@@ -445,6 +445,42 @@ func compareDecimal(l, r decimal.Decimal) int {
 	return l.Cmp(r)
 }
 
+// compareDecimalBytes compares two encoded decimals. Decimals are written
+// with the scale of their column, so the decimals of an index usually share
+// an exponent and can be compared by their coefficients without decoding them.
+func compareDecimalBytes(l, r []byte) int {
+	const coeffOff = int32Size + int8Size
+	lc := bytes.TrimLeft(l[coeffOff:], "\x00")
+	rc := bytes.TrimLeft(r[coeffOff:], "\x00")
+
+	// a zero coefficient is zero, regardless of its sign
+	ls, rs := int8(0), int8(0)
+	if len(lc) > 0 {
+		ls = readInt8(l[int32Size:coeffOff])
+	}
+	if len(rc) > 0 {
+		rs = readInt8(r[int32Size:coeffOff])
+	}
+	if ls != rs {
+		return compareInt8(ls, rs)
+	} else if ls == 0 {
+		return 0
+	}
+
+	if readInt32(l[:int32Size]) != readInt32(r[:int32Size]) {
+		return compareDecimal(readDecimal(l), readDecimal(r))
+	}
+
+	cmp := compareInt64(int64(len(lc)), int64(len(rc)))
+	if cmp == 0 {
+		cmp = bytes.Compare(lc, rc)
+	}
+	if ls < 0 {
+		cmp = -cmp
+	}
+	return cmp
+}
+
 const minYear int16 = 1901
 const maxYear int16 = 2155
 const zeroToken uint8 = 255
@@ -16,6 +16,8 @@ package val
 
 import (
 	"math"
+	"math/big"
+	"math/rand"
 	"testing"
 	"time"
 
@@ -110,6 +112,31 @@ func TestCompare(t *testing.T) {
 			l:   encDecimal(decimalFromString("2634193746329327479.32030573792e-19")), r: encDecimal(decimalFromString("5.5729136e3")),
 			cmp: -1,
 		},
+		{
+			typ: Type{Enc: DecimalEnc},
+			l:   encDecimal(decimalFromString("-1.00")), r: encDecimal(decimalFromString("-10.00")),
+			cmp: 1,
+		},
+		{
+			typ: Type{Enc: DecimalEnc},
+			l:   encDecimal(decimalFromString("-0.01")), r: encDecimal(decimalFromString("0.00")),
+			cmp: -1,
+		},
+		{
+			typ: Type{Enc: DecimalEnc},
+			l:   encDecimal(decimalFromString("184467440737095516.16")), r: encDecimal(decimalFromString("1.00")),
+			cmp: 1,
+		},
+		{
+			typ: Type{Enc: DecimalEnc},
+			l:   encDecimal(decimalFromString("1.00")), r: encDecimal(decimalFromString("1")),
+			cmp: 0,
+		},
+		{
+			typ: Type{Enc: DecimalEnc},
+			l:   encNegativeZeroDecimal(), r: encDecimal(decimalFromString("0.00")),
+			cmp: 0,
+		},
 		// year
 		{
 			typ: Type{Enc: YearEnc},
@@ -258,6 +285,34 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestCompareDecimal(t *testing.T) {
+	// decimals with the same scale, as in an index, and with mixed scales
+	for _, scale := range []int32{0, 2, 10, -1} {
+		for i := 0; i < 1000; i++ {
+			l, r := randomDecimal(scale), randomDecimal(scale)
+			expected := l.Cmp(r)
+			assert.Equal(t, expected, compareDecimalBytes(encDecimal(l), encDecimal(r)),
+				"expected %s %s %s", l.String(), fmtComparator(expected), r.String())
+		}
+	}
+}
+
+// randomDecimal returns a random decimal with |scale| digits after the decimal
+// point, or a random scale if |scale| is negative.
+func randomDecimal(scale int32) decimal.Decimal {
+	if scale < 0 {
+		scale = rand.Int31n(12)
+	}
+	coeff := big.NewInt(rand.Int63n(1000))
+	for i := rand.Intn(4); i > 0; i-- {
+		coeff.Mul(coeff, big.NewInt(rand.Int63()))
+	}
+	if rand.Intn(2) == 0 {
+		coeff.Neg(coeff)
+	}
+	return decimal.NewFromBigInt(coeff, -scale)
+}
+
 func fmtComparator(c int) string {
 	if c == 0 {
 		return "="
@@ -292,6 +347,13 @@ func encBit(u uint64) []byte {
 	return buf
 }
 
+// encNegativeZeroDecimal encodes a zero decimal with a negative sign.
+func encNegativeZeroDecimal() []byte {
+	buf := encDecimal(decimalFromString("0.00"))
+	writeInt8(buf[int32Size:int32Size+int8Size], -1)
+	return buf
+}
+
 func encDecimal(d decimal.Decimal) []byte {
 	buf := make([]byte, sizeOfDecimal(d))
 	writeDecimal(buf, d)
@@ -116,7 +116,7 @@ func compare(typ Type, left, right []byte) int {
 	case Bit64Enc:
 		return compareBit64(readBit64(left), readBit64(right))
 	case DecimalEnc:
-		return compareDecimal(readDecimal(left), readDecimal(right))
+		return compareDecimalBytes(left, right)
 	case YearEnc:
 		return compareYear(readYear(left), readYear(right))
 	case DateEnc:
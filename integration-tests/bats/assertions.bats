@@ -0,0 +1,74 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+create table accounts (id int primary key, owner varchar(20), balance int);
+insert into accounts values (1, 'alice', 10), (2, 'bob', 20), (3, 'alice', 5);
+insert into dolt_assertions values ('no_negative_totals', 'accounts',
+  'select id from accounts where owner in (select owner from accounts group by owner having sum(balance) < 0)');
+SQL
+    dolt commit -Am "add accounts and assertions"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "assertions: assertions are versioned in dolt_assertions" {
+    run dolt sql -r csv -q "select name, table_name from dolt_assertions"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "no_negative_totals,accounts" ]
+
+    run dolt sql -q "insert into dolt_assertions values ('bad', 'accounts', 'delete from accounts')"
+    [ "$status" -eq 1 ]
+
+    run dolt sql -r csv -q "select count(*) from dolt_assertions as of 'HEAD'"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "assertions: commits which violate assertions fail" {
+    dolt sql -q "update accounts set balance = -20 where id = 3"
+
+    run dolt commit -am "overdrawn"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "the working set violates assertions: 'no_negative_totals' on table accounts" ]] || false
+
+    run dolt sql -q "call dolt_commit('-am', 'overdrawn')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "violates assertions" ]] || false
+
+    dolt sql -q "update accounts set balance = 15 where id = 1"
+    dolt commit -am "still in credit"
+}
+
+@test "assertions: --force commits despite violated assertions" {
+    dolt sql -q "update accounts set balance = -20 where id = 3"
+    dolt commit --force -am "overdrawn"
+
+    run dolt sql -r csv -q "select balance from accounts as of 'HEAD' where id = 3"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "-20" ]
+}
+
+@test "assertions: dolt_verify_assertions records the violating rows" {
+    dolt sql -q "update accounts set balance = -20 where id = 3"
+
+    run dolt sql -r csv -q "set @@dolt_force_transaction_commit=1; call dolt_verify_assertions();"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "no_negative_totals,accounts,2" ]] || false
+
+    run dolt sql -r csv -q "select violation_type, id, owner from dolt_constraint_violations_accounts order by id"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ "${lines[1]}" = "check constraint,1,alice" ]
+    [ "${lines[2]}" = "check constraint,3,alice" ]
+
+    run dolt sql -r csv -q "select json_extract(violation_info, '$.Name') from dolt_constraint_violations_accounts limit 1"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "no_negative_totals" ]
+}
@@ -777,6 +777,38 @@ SQL
     [[ "$output" =~ "6,6" ]] || false    
 }
 
+@test "auto_increment: values are allocated to branches in blocks" {
+    dolt sql  <<SQL
+call dolt_commit('-Am', 'empty table');
+call dolt_branch('branch1');
+set @@global.dolt_auto_increment_block_size = 10;
+
+insert into test (c0) values (1);
+call dolt_checkout('branch1');
+insert into test (c0) values (2);
+call dolt_checkout('main');
+insert into test (c0) values (3);
+call dolt_checkout('branch1');
+insert into test (c0) values (4);
+call dolt_commit('-am', 'branch1 values');
+
+call dolt_checkout('main');
+insert into test values (5, 5);
+insert into test (c0) values (6);
+call dolt_commit('-am', 'main values');
+call dolt_merge('branch1');
+SQL
+
+    run dolt sql -q 'select * from test order by pk' -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "1,1" ]
+    [ "${lines[2]}" = "2,3" ]
+    [ "${lines[3]}" = "5,5" ]
+    [ "${lines[4]}" = "6,6" ]
+    [ "${lines[5]}" = "11,2" ]
+    [ "${lines[6]}" = "12,4" ]
+}
+
 @test "auto_increment: newly cloned database" {
     dolt sql  <<SQL
 call dolt_add('.');
@@ -0,0 +1,139 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+load $BATS_TEST_DIRNAME/helper/query-server-common.bash
+
+setup() {
+    setup_common
+    dolt sql -q "CREATE TABLE t (pk int primary key);"
+    dolt commit -Am "create t"
+    dolt branch feature
+}
+
+teardown() {
+    assert_feature_version
+    stop_sql_server
+    teardown_common
+}
+
+@test "branch-protection: rules take effect once committed" {
+    dolt sql -q "INSERT INTO dolt_branch_protection VALUES ('main', 'alice')"
+    run dolt sql -q "SELECT * FROM dolt_branch_protection" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "main,alice" ]] || false
+
+    dolt commit -Am "protect main"
+
+    run dolt sql -q "INSERT INTO t VALUES (1)"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "branch is protected: user 'root' is not allowed to change branch 'main'" ]] || false
+
+    dolt checkout feature
+    dolt sql -q "INSERT INTO t VALUES (1)"
+    dolt commit -am "unprotected branches accept commits"
+}
+
+@test "branch-protection: allowed users can only merge into protected branches" {
+    dolt sql -q "INSERT INTO dolt_branch_protection VALUES ('ma%', 'alice, root')"
+    dolt commit -Am "protect main"
+
+    dolt sql -q "INSERT INTO t VALUES (1)"
+    run dolt commit -am "direct commit"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "branch 'main' only accepts merges" ]] || false
+    dolt reset --hard
+
+    dolt checkout feature
+    dolt sql -q "INSERT INTO t VALUES (2)"
+    dolt commit -am "feature commit"
+    dolt checkout main
+    dolt merge feature
+
+    dolt branch other HEAD~1
+    dolt checkout other
+    dolt sql -q "INSERT INTO t VALUES (3)"
+    dolt commit -am "other commit"
+    dolt checkout main
+    dolt merge other -m "merge other"
+
+    run dolt sql -q "SELECT count(*) FROM t" -r csv
+    [[ "$output" =~ "2" ]] || false
+
+    run dolt reset --hard HEAD~1
+    [ $status -eq 1 ]
+    [[ "$output" =~ "branch 'main' only accepts merges" ]] || false
+}
+
+@test "branch-protection: pushes to protected branches through sql-server remotesapi" {
+    mkdir remote
+    cd remote
+    dolt init
+    dolt sql -q "CREATE TABLE t (pk int primary key);"
+    dolt sql -q "INSERT INTO dolt_branch_protection VALUES ('main', 'alice')"
+    dolt commit -Am "protect main"
+    dolt sql -q "CREATE USER alice@'%' IDENTIFIED BY 'pass'; GRANT ALL ON *.* TO alice@'%' WITH GRANT OPTION;"
+    dolt sql -q "CREATE USER eve@'%' IDENTIFIED BY 'pass'; GRANT ALL ON *.* TO eve@'%' WITH GRANT OPTION;"
+
+    APIPORT=$( definePORT )
+    export DOLT_REMOTE_PASSWORD="pass"
+    start_sql_server_with_args --remotesapi-port $APIPORT
+
+    cd ../
+    dolt clone http://localhost:$APIPORT/remote cloned_db -u alice
+    cd cloned_db
+    dolt checkout -b change
+    dolt sql -q "INSERT INTO t VALUES (1)"
+    dolt commit -am "add 1"
+
+    run dolt push origin --user eve change:main
+    [ $status -eq 1 ]
+    [[ "$output" =~ "user 'eve' is not allowed to change branch 'main'" ]] || false
+
+    run dolt push origin --user eve change:other
+    [ $status -eq 0 ]
+
+    run dolt push origin --user alice change:main
+    [ $status -eq 0 ]
+
+    dolt reset --hard HEAD~1
+    dolt commit --allow-empty -m "rewrite"
+    run dolt push origin --force --user alice change:main
+    [ $status -eq 1 ]
+    [[ "$output" =~ "branch 'main' only accepts merges" ]] || false
+}
+
+@test "branch-protection: protected branches can't be force moved, deleted or renamed" {
+    dolt sql -q "INSERT INTO dolt_branch_protection VALUES ('ma%', 'root')"
+    dolt commit -Am "protect main"
+    dolt branch protected_copy_source
+
+    run dolt sql -q "call dolt_branch('-f', 'main', 'feature')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "branch 'main' only accepts merges" ]] || false
+
+    run dolt sql -q "call dolt_branch('-c', '-f', 'feature', 'main')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "branch 'main' only accepts merges" ]] || false
+
+    dolt sql -q "call dolt_branch('mainline')"
+    run dolt sql -q "call dolt_checkout('feature'); call dolt_branch('-D', 'mainline')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "branch 'mainline' only accepts merges" ]] || false
+
+    run dolt sql -q "call dolt_checkout('feature'); call dolt_branch('-m', '-f', 'mainline', 'renamed')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "branch 'mainline' only accepts merges" ]] || false
+
+    run dolt sql -q "call dolt_checkout('feature'); call dolt_branch('-m', '-f', 'feature', 'mainline')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "branch 'mainline' only accepts merges" ]] || false
+
+    run dolt sql -r csv -q "select name from dolt_branches order by name"
+    [ "${lines[1]}" = "feature" ]
+    [ "${lines[2]}" = "main" ]
+    [ "${lines[3]}" = "mainline" ]
+
+    # branches which aren't protected can still be moved, deleted and renamed
+    dolt sql -q "call dolt_branch('-f', 'feature', 'main')"
+    dolt sql -q "call dolt_branch('-m', 'protected_copy_source', 'renamed')"
+    dolt sql -q "call dolt_branch('-D', 'renamed')"
+}
@@ -300,3 +300,83 @@ teardown() {
     [ $status -eq "1" ]
     [[ "$output" =~ "is an invalid branch name" ]] || false
 }
+
+@test "branch: --result-format json" {
+    dolt branch other
+
+    run dolt branch --result-format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"branches": [' ]] || false
+    [[ "$output" =~ '"name": "main"' ]] || false
+    [[ "$output" =~ '"name": "other"' ]] || false
+    [[ "$output" =~ '"current": true' ]] || false
+
+    run dolt branch --show-current --result-format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"branch": "main"' ]] || false
+
+    run dolt branch new-branch --result-format json
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "only supported when listing branches" ]] || false
+}
+
+@test "branch: --merged and --no-merged list branches" {
+    dolt branch merged
+    dolt checkout -b unmerged
+    dolt commit --allow-empty -m "not on main"
+    dolt checkout main
+
+    run dolt branch --merged
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "main" ]] || false
+    [[ "$output" =~ "merged" ]] || false
+    [[ ! "$output" =~ "unmerged" ]] || false
+
+    run dolt branch --no-merged main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "unmerged" ]] || false
+    [ "${#lines[@]}" -eq 1 ]
+
+    run dolt branch --merged unmerged
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+}
+
+@test "branch: delete merged branches in bulk" {
+    dolt branch merged1
+    dolt branch merged2
+    dolt checkout -b unmerged
+    dolt commit --allow-empty -m "not on main"
+    dolt checkout main
+
+    run dolt branch -d --merged main --dry-run
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "would delete branch 'merged1'" ]] || false
+    [[ "$output" =~ "would delete branch 'merged2'" ]] || false
+    [ "${#lines[@]}" -eq 2 ]
+    run dolt branch
+    [[ "$output" =~ "merged1" ]] || false
+
+    run dolt branch -d --merged main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "deleted branch 'merged1'" ]] || false
+    [[ "$output" =~ "deleted branch 'merged2'" ]] || false
+
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "merged1" ]] || false
+    [[ ! "$output" =~ "merged2" ]] || false
+    [[ "$output" =~ "unmerged" ]] || false
+
+    run dolt branch -d --no-merged
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "can only be deleted with -D" ]] || false
+
+    run dolt branch -D --no-merged
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "deleted branch 'unmerged'" ]] || false
+
+    run dolt branch
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ "main" ]] || false
+}
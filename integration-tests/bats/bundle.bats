@@ -0,0 +1,84 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "create table t (pk int primary key, c varchar(20));"
+    dolt sql -q "insert into t values (1, 'one');"
+    dolt commit -Am "first commit"
+    dolt tag v1
+    dolt branch other
+    dolt sql -q "insert into t values (2, 'two');"
+    dolt commit -am "second commit"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "bundle: create, extract and clone" {
+    run dolt bundle create test.bundle
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Bundled 3 refs into test.bundle" ]] || false
+
+    run dolt bundle list-heads test.bundle
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "refs/heads/main" ]] || false
+    [[ "$output" =~ "refs/heads/other" ]] || false
+    [[ "$output" =~ "refs/tags/v1" ]] || false
+
+    run dolt bundle extract test.bundle "$BATS_TMPDIR/bundle-extract-$$"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Extracted 3 refs" ]] || false
+
+    cd "$BATS_TMPDIR"
+    rm -rf "bundle-clone-$$"
+    dolt clone "file://$BATS_TMPDIR/bundle-extract-$$" "bundle-clone-$$"
+    cd "bundle-clone-$$"
+
+    run dolt sql -q "select count(*) from t" -r csv
+    [[ "$output" =~ "2" ]] || false
+    run dolt tag
+    [[ "$output" =~ "v1" ]] || false
+    run dolt branch -a
+    [[ "$output" =~ "remotes/origin/other" ]] || false
+
+    cd ..
+    rm -rf "bundle-clone-$$" "bundle-extract-$$"
+}
+
+@test "bundle: create with selected refs" {
+    run dolt bundle create test.bundle other
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Bundled 1 refs into test.bundle" ]] || false
+
+    run dolt bundle list-heads test.bundle
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ "refs/heads/other" ]] || false
+
+    run dolt bundle create test2.bundle doesnotexist
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "'doesnotexist' is not a branch or tag" ]] || false
+}
+
+@test "bundle: extract errors" {
+    dolt bundle create test.bundle
+    mkdir existing
+
+    run dolt bundle extract test.bundle existing
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "'existing' already exists" ]] || false
+
+    echo "not a bundle" > bad.bundle
+    run dolt bundle extract bad.bundle out
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "not a dolt bundle" ]] || false
+    [ ! -d out ]
+
+    run dolt bundle list-heads bad.bundle
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "not a dolt bundle" ]] || false
+}
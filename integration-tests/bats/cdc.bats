@@ -0,0 +1,52 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE test(pk BIGINT PRIMARY KEY, v varchar(10))"
+    dolt commit -Am "Created table"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "cdc: kafka requires brokers and a cursor" {
+    run dolt cdc kafka --cursor stream
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--brokers must be given" ]] || false
+
+    run dolt cdc kafka --brokers localhost:9092
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--cursor must be given" ]] || false
+}
+
+@test "cdc: kafka with a cursor which doesn't exist" {
+    run dolt cdc kafka --brokers localhost:1 --cursor stream --once
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "change cursor not found" ]] || false
+}
+
+@test "cdc: kafka with no changes to produce" {
+    dolt sql -q "call dolt_change_cursor('create', 'stream')"
+
+    # no broker is connected to when the cursor is already at the head of its branch
+    run dolt cdc kafka --brokers localhost:1 --cursor stream --once
+    [ "$status" -eq 0 ]
+}
+
+@test "cdc: kafka doesn't move the cursor when brokers can't be reached" {
+    dolt sql -q "call dolt_change_cursor('create', 'stream')"
+    dolt sql -q "INSERT INTO test VALUES (1, 'one')"
+    dolt commit -am "Added a row"
+
+    run dolt cdc kafka --brokers localhost:1 --cursor stream --timeout-millis 100 --once
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no broker could be reached" ]] || false
+
+    run dolt sql -r csv -q "select count(*) from dolt_changes('stream')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
@@ -35,3 +35,29 @@ teardown() {
     [ -s ".dolt/noms/vvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvv" ]
     [ -s ".dolt/noms/journal.idx" ]
 }
+
+@test "chunk-journal: journal is compacted into table files once it exceeds DOLT_JOURNAL_MAX_SIZE" {
+    export DOLT_JOURNAL_MAX_SIZE=20000
+    dolt sql -q "create table t (pk int primary key, c0 text);"
+    dolt commit -Am "new table t"
+    for i in {1..30}
+    do
+        dolt sql -q "insert into t values ($i, repeat('$i', 1000));"
+        dolt commit -am "commit $i"
+    done
+
+    table_files=$(ls .dolt/noms | grep -E '^[0-9a-v]{32}$' | grep -v vvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvv | wc -l)
+    [ "$table_files" -gt 0 ]
+
+    run dolt sql -q "select count(*) from t;" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "30" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 32 ]
+
+    run dolt fsck
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "no problems found" ]] || false
+}
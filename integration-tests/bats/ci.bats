@@ -0,0 +1,76 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt sql <<SQL
+CREATE TABLE t (pk int primary key, v varchar(10));
+INSERT INTO t VALUES (1, 'a'), (2, 'b');
+INSERT INTO dolt_tests VALUES
+    ('row count', 'counts', 'SELECT * FROM t', 'expected_rows', '==', '2'),
+    ('column count', 'counts', 'SELECT * FROM t', 'expected_columns', '==', '2'),
+    ('first value', NULL, 'SELECT v FROM t WHERE pk = 1', 'expected_single_value', '==', 'a');
+SQL
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "ci: run passes when all assertions hold" {
+    run dolt ci run
+    [ $status -eq 0 ]
+    [[ "$output" =~ "PASS  counts/row count" ]] || false
+    [[ "$output" =~ "PASS  first value" ]] || false
+    [[ "$output" =~ "3 passed, 0 failed" ]] || false
+}
+
+@test "ci: run fails when an assertion does not hold" {
+    dolt sql -q "INSERT INTO t VALUES (3, 'c')"
+    run dolt ci run
+    [ $status -eq 1 ]
+    [[ "$output" =~ "FAIL  counts/row count: expected row count == 2, got 3" ]] || false
+    [[ "$output" =~ "2 passed, 1 failed" ]] || false
+}
+
+@test "ci: run reports queries which error" {
+    dolt sql -q "INSERT INTO dolt_tests VALUES ('broken', NULL, 'SELECT * FROM missing', 'expected_rows', '==', '0')"
+    run dolt ci run broken
+    [ $status -eq 1 ]
+    [[ "$output" =~ "ERROR broken" ]] || false
+}
+
+@test "ci: run selects tests by name and group" {
+    run dolt ci run "first value"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1 passed, 0 failed" ]] || false
+
+    run dolt ci run --group counts
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2 passed, 0 failed" ]] || false
+    [[ ! "$output" =~ "first value" ]] || false
+
+    run dolt ci run missing
+    [ $status -eq 1 ]
+    [[ "$output" =~ "test 'missing' not found" ]] || false
+}
+
+@test "ci: run against a ref uses the committed tests and data" {
+    dolt commit -Am "add tests"
+    dolt sql -q "INSERT INTO t VALUES (3, 'c')"
+
+    run dolt ci run --ref main
+    [ $status -eq 0 ]
+    [[ "$output" =~ "3 passed, 0 failed" ]] || false
+
+    run dolt ci run
+    [ $status -eq 1 ]
+}
+
+@test "ci: run produces json results" {
+    run dolt ci run -r json --group counts
+    [ $status -eq 0 ]
+    [[ "$output" =~ '"passed":2' ]] || false
+    [[ "$output" =~ '"test_name":"row count","test_group":"counts","test_query":"SELECT * FROM t","status":"PASS"' ]] || false
+}
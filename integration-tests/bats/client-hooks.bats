@@ -0,0 +1,74 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt sql -q "CREATE TABLE t (pk int primary key);"
+    mkdir -p .dolt/hooks
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "client-hooks: pre-commit hook receives staged changes and can abort the commit" {
+    cat > .dolt/hooks/pre-commit <<'EOF'
+#!/bin/sh
+cat
+exit 1
+EOF
+    chmod +x .dolt/hooks/pre-commit
+
+    run dolt commit -Am "create t"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "t	added	false	true" ]] || false
+    [[ "$output" =~ "pre-commit hook failed" ]] || false
+
+    run dolt log --oneline
+    [[ ! "$output" =~ "create t" ]] || false
+
+    dolt commit --no-verify -Am "create t"
+    run dolt log --oneline
+    [[ "$output" =~ "create t" ]] || false
+}
+
+@test "client-hooks: pre-commit hook which succeeds allows the commit" {
+    printf '#!/bin/sh\nexit 0\n' > .dolt/hooks/pre-commit
+    chmod +x .dolt/hooks/pre-commit
+
+    dolt commit -Am "create t"
+    run dolt log --oneline
+    [[ "$output" =~ "create t" ]] || false
+}
+
+@test "client-hooks: hooks which are not executable are ignored" {
+    printf '#!/bin/sh\nexit 1\n' > .dolt/hooks/pre-commit
+
+    dolt commit -Am "create t"
+}
+
+@test "client-hooks: pre-push hook receives the pushed refs and can abort the push" {
+    dolt commit -Am "create t"
+    mkdir remote
+    dolt remote add origin file://remote
+
+    cat > .dolt/hooks/pre-push <<'EOF'
+#!/bin/sh
+echo "args: $@"
+cat
+exit 1
+EOF
+    chmod +x .dolt/hooks/pre-push
+
+    head=$(dolt sql -q "select hashof('main')" -r csv | tail -n 1)
+    run dolt push origin main
+    [ $status -eq 1 ]
+    [[ "$output" =~ "args: origin main" ]] || false
+    [[ "$output" =~ "main $head" ]] || false
+    [[ "$output" =~ "pre-push hook failed" ]] || false
+
+    dolt push --no-verify origin main
+    run dolt branch -r
+    [[ "$output" =~ "origin/main" ]] || false
+}
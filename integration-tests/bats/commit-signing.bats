@@ -0,0 +1,143 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    KEYS_DIR="$BATS_TMPDIR/signing-keys-$$"
+    mkdir -p "$KEYS_DIR"
+
+    dolt sql -q "create table t (pk int primary key);"
+    dolt add .
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+    rm -rf "$KEYS_DIR"
+}
+
+# setup_ssh_signing creates an SSH key and configures dolt to sign commits with it, and to trust it if an argument is given
+setup_ssh_signing() {
+    if ! command -v ssh-keygen >/dev/null; then
+        skip "ssh-keygen is not installed"
+    fi
+    ssh-keygen -q -t ed25519 -N "" -C "bats@dolthub.com" -f "$KEYS_DIR/id_ed25519"
+    dolt config --global --add gpg.format ssh
+    dolt config --global --add user.signingkey "$KEYS_DIR/id_ed25519"
+    if [ -n "$1" ]; then
+        echo "bats@dolthub.com $(cat "$KEYS_DIR/id_ed25519.pub")" > "$KEYS_DIR/allowed_signers"
+        dolt config --global --add gpg.ssh.allowedsignersfile "$KEYS_DIR/allowed_signers"
+    fi
+}
+
+@test "commit-signing: commits are unsigned by default" {
+    dolt commit -m "unsigned"
+
+    run dolt verify-commit
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unsigned" ]] || false
+
+    run dolt sql -r csv -q "select distinct signature_status from dolt_commits"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "unsigned" ]
+}
+
+@test "commit-signing: sign a commit with an SSH key" {
+    setup_ssh_signing trusted
+    dolt commit -S -m "signed"
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "good signature from bats@dolthub.com" ]] || false
+
+    run dolt verify-commit HEAD HEAD~1
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "good signature" ]] || false
+    [[ "$output" =~ "unsigned" ]] || false
+
+    run dolt sql -r csv -q "select message, signature_status from dolt_commits order by date desc limit 2"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "signed,good" ]
+    [ "${lines[2]}" = "Initialize data repository,unsigned" ]
+
+    head=$(dolt sql -r csv -q "select hashof('HEAD')" | tail -n 1)
+    run dolt sql -r csv -q "select signature_status from dolt_commits where commit_hash = '$head'"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "good" ]
+}
+
+@test "commit-signing: signatures by untrusted SSH keys are unknown" {
+    setup_ssh_signing
+    dolt commit -S -m "signed"
+
+    run dolt verify-commit
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown signature" ]] || false
+    [[ "$output" =~ "gpg.ssh.allowedsignersfile" ]] || false
+
+    run dolt sql -r csv -q "select signature_status from dolt_commits where message = 'signed'"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "unknown" ]
+}
+
+@test "commit-signing: commit.gpgsign signs commits by default" {
+    setup_ssh_signing trusted
+    dolt config --global --add commit.gpgsign true
+
+    dolt commit -m "signed by default"
+    run dolt verify-commit
+    [ "$status" -eq 0 ]
+
+    dolt sql -q "insert into t values (1)"
+    dolt commit -am "not signed" --no-gpg-sign
+    run dolt verify-commit
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unsigned" ]] || false
+
+    dolt sql -q "insert into t values (2)"
+    dolt sql -q "call dolt_commit('-am', 'signed from sql')"
+    run dolt sql -r csv -q "select signature_status from dolt_commits where message = 'signed from sql'"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "good" ]
+}
+
+@test "commit-signing: signing with an SSH key requires user.signingkey" {
+    dolt config --global --add gpg.format ssh
+
+    run dolt commit -S -m "signed"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "user.signingkey" ]] || false
+
+    run dolt commit -S --no-gpg-sign -m "signed"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --gpg-sign and --no-gpg-sign" ]] || false
+}
+
+@test "commit-signing: sign a commit with GPG" {
+    if ! command -v gpg >/dev/null; then
+        skip "gpg is not installed"
+    fi
+    export GNUPGHOME="$KEYS_DIR/gnupg"
+    mkdir -m 700 "$GNUPGHOME"
+    gpg --batch --pinentry-mode loopback --passphrase "" --quick-gen-key "Bats Tester <bats@dolthub.com>" ed25519 sign never
+
+    dolt config --global --add user.signingkey "bats@dolthub.com"
+    dolt commit -S -m "signed"
+
+    run dolt verify-commit
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "good signature from Bats Tester <bats@dolthub.com>" ]] || false
+
+    run dolt sql -r csv -q "select signature_status from dolt_commits where message = 'signed'"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "good" ]
+
+    # without the key, the signature can't be checked
+    export GNUPGHOME="$KEYS_DIR/empty-gnupg"
+    mkdir -m 700 "$GNUPGHOME"
+    run dolt verify-commit
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown signature" ]] || false
+}
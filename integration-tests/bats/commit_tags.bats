@@ -162,3 +162,55 @@ teardown() {
     [ $status -eq 0 ]
     [[ "$output" =~ "1.0.0" ]] || false
 }
+
+@test "commit_tags: create a tag with date arg given" {
+    dolt tag v1 HEAD^ -m "first release" --date "2022-01-02T03:04:05Z"
+    run dolt tag -v
+    [ $status -eq 0 ]
+    [[ "$output" =~ "first release" ]] || false
+    [[ "$output" =~ "2022" ]] || false
+
+    run dolt tag -d v1 --date "2022-01-02T03:04:05Z"
+    [ $status -ne 0 ]
+}
+
+@test "commit_tags: push and fetch all tags" {
+    mkdir remote
+    dolt remote add origin file://./remote
+    dolt push origin main
+
+    dolt checkout -b other
+    dolt sql -q "insert into test values (10)"
+    dolt commit -am "commit not on main"
+    dolt tag v1 HEAD~2
+    dolt tag v2 other -m "tag not on main"
+    dolt checkout main
+
+    run dolt push --tags origin
+    [ $status -eq 0 ]
+    [[ "$output" =~ "[new tag]" ]] || false
+
+    run dolt push --tags --all origin
+    [ $status -ne 0 ]
+    [[ "$output" =~ "--all and --tags are incompatible" ]] || false
+
+    dolt clone file://./remote clone
+    cd clone
+    # only tags on main are fetched with it
+    run dolt tag
+    [ $status -eq 0 ]
+    [[ "$output" =~ "v1" ]] || false
+    [[ ! "$output" =~ "v2" ]] || false
+    dolt tag -d v1
+
+    dolt fetch --tags
+    run dolt tag -v
+    [ $status -eq 0 ]
+    [[ "$output" =~ "v1" ]] || false
+    [[ "$output" =~ "v2" ]] || false
+    [[ "$output" =~ "tag not on main" ]] || false
+
+    run dolt sql -q "select * from test as of 'v2' where pk = 10"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "10" ]] || false
+}
@@ -139,3 +139,27 @@ SQL
     [ "$status" -ne "0" ]
     [[ "$output" =~ "$database_name/$commit is read-only" ]] || false
 }
+
+@test "db-revision-specifiers: connections to commit-qualified database revisions are pinned to the commit" {
+    commit=$(dolt sql -q "SELECT hashof('HEAD~2');" -r=csv | tail -1)
+
+    run dolt --use-db "$database_name/$commit" sql -r=csv -q "select database(), active_branch(); select count(*) from test;"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "$database_name/$commit," ]] || false
+    [ "${lines[3]}" = "2" ]
+
+    # later commits aren't seen
+    dolt sql -q "INSERT INTO test VALUES (10, 'red');"
+    dolt commit -am "Inserted 10, red"
+    run dolt --use-db "$database_name/$commit" sql -r=csv -q "select count(*) from test;"
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "2" ]
+
+    run dolt --use-db "$database_name/$commit" sql -q "call dolt_merge('branch1');"
+    [ "$status" -ne "0" ]
+    [[ "$output" =~ "cannot change $database_name/$commit: database revisions pinned to a tag or commit are read-only" ]] || false
+
+    run dolt --use-db "$database_name/$commit" sql -q "update test set color = 'red';"
+    [ "$status" -ne "0" ]
+    [[ "$output" =~ "$database_name/$commit is read-only" ]] || false
+}
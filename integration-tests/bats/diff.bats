@@ -1811,3 +1811,31 @@ SQL
     [ $status -eq 1 ]
     [[ $output =~ "invalid Arguments" ]] || false
 }
+
+@test "diff: --diff-mode=word highlights changed words in long text" {
+    dolt sql <<SQL
+create table docs (pk int primary key, body text);
+insert into docs values (1, 'the quick brown fox jumps over the lazy dog');
+SQL
+    dolt add .
+    dolt commit -m "added docs"
+
+    dolt sql -q "update docs set body = 'the slow brown fox jumps over the sleepy dog' where pk = 1"
+
+    run dolt diff --diff-mode=word docs
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| * | 1  | the [-quick-]{+slow+} brown fox jumps over the [-lazy-]{+sleepy+} dog |" ]] || false
+
+    run dolt diff --stat docs
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1 Row Modified (100.00%)" ]] || false
+}
+
+@test "diff: --summary --result-format json" {
+    dolt sql -q "insert into test values (0, 0, 0, 0, 0, 0)"
+
+    run dolt diff --summary -r json
+    [ $status -eq 0 ]
+    [[ "$output" =~ '"table_name": "test"' ]] || false
+    [[ "$output" =~ '"data_change": true' ]] || false
+}
@@ -474,6 +474,66 @@ SQL
     [ ! -f dumpfile.sql ]
 }
 
+@test "dump: --parallel dumps each table to its own file with a manifest" {
+    dolt sql -q "CREATE TABLE new_table(pk int primary key);"
+    dolt sql -q "INSERT INTO new_table VALUES (1);"
+    dolt sql -q "CREATE TABLE warehouse(warehouse_id int primary key, warehouse_name longtext);"
+    dolt sql -q "INSERT into warehouse VALUES (1, 'UPS'), (2, 'TV'), (3, 'Table');"
+    dolt sql -q "CREATE VIEW warehouse_names AS SELECT warehouse_name FROM warehouse;"
+
+    run dolt dump --parallel 2 --directory dumps --no-create-db
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully exported data." ]] || false
+    [ -f dumps/new_table.sql ]
+    [ -f dumps/warehouse.sql ]
+    [ -f dumps/dolt_schema_elements.sql ]
+    [ -f dumps/manifest.json ]
+
+    run grep -c INSERT dumps/warehouse.sql
+    [ "$output" -eq 1 ]
+    run grep "CREATE VIEW" dumps/dolt_schema_elements.sql
+    [ "$status" -eq 0 ]
+
+    root_hash=$(dolt sql -q "select dolt_hashof_db()" -r csv | tail -n 1)
+    run cat dumps/manifest.json
+    [[ "$output" =~ "\"root_hash\": \"$root_hash\"" ]] || false
+    [[ "$output" =~ "\"file\": \"warehouse.sql\"" ]] || false
+    [[ "$output" =~ "\"rows\": 3" ]] || false
+
+    run dolt dump --parallel 2 --directory dumps
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "already exists" ]] || false
+
+    mkdir roundtrip
+    cd roundtrip
+    dolt init
+    dolt sql < ../dumps/new_table.sql
+    dolt sql < ../dumps/warehouse.sql
+    dolt sql < ../dumps/dolt_schema_elements.sql
+    run dolt sql -q "select count(*) from warehouse_names" -r csv
+    [[ "$output" =~ "3" ]] || false
+    cd ..
+
+    run dolt dump -f -r csv --parallel 4 --directory dumps
+    [ "$status" -eq 0 ]
+    [ -f dumps/new_table.csv ]
+    [ -f dumps/warehouse.csv ]
+    run cat dumps/manifest.json
+    [[ "$output" =~ "\"format\": \"csv\"" ]] || false
+    [[ ! "$output" =~ "schema_elements" ]] || false
+}
+
+@test "dump: --parallel with invalid arguments" {
+    dolt sql -q "CREATE TABLE new_table(pk int primary key);"
+    run dolt dump --parallel 0
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "parallel must be at least 1" ]] || false
+
+    run dolt dump --parallel 2 --file-name dumpfile.sql
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "file-name is not supported with parallel" ]] || false
+}
+
 @test "dump: SQL type - with both filename and directory name given" {
     dolt sql -q "CREATE TABLE new_table(pk int primary key);"
     run dolt dump --file-name dumpfile.sql --directory dumps
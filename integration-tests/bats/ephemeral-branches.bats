@@ -0,0 +1,88 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+load $BATS_TEST_DIRNAME/helper/query-server-common.bash
+
+setup() {
+    skiponwindows "tests are flaky on Windows"
+    if [ "$SQL_ENGINE" = "remote-engine" ]; then
+      skip "This test tests remote connections directly, SQL_ENGINE is not needed."
+    fi
+    setup_common
+
+    dolt sql -q "create table t (pk int primary key);"
+    dolt commit -Am "first commit"
+}
+
+teardown() {
+    stop_sql_server 1 && sleep 0.5
+    assert_feature_version
+    teardown_common
+}
+
+# wait_for_branch_deleted polls dolt_branches until the branch given has been deleted
+wait_for_branch_deleted() {
+    for i in $(seq 1 50); do
+        run dolt sql -r csv -q "select count(*) from dolt_branches where name = '$1'"
+        [ "${lines[1]}" = "1" ] || break
+        sleep 0.1
+    done
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "ephemeral-branches: need a running sql-server" {
+    run dolt sql -q "call dolt_checkout('-b', 'tmp/one', '--ttl', '1h')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "ephemeral branches are only available in a running sql-server" ]] || false
+
+    run dolt branch
+    [[ ! "$output" =~ "tmp/one" ]] || false
+}
+
+@test "ephemeral-branches: deleted when they expire" {
+    start_sql_server
+
+    run dolt sql -r csv <<SQL
+call dolt_checkout('-b', 'tmp/one', '--ttl', '1s');
+insert into t values (1);
+call dolt_commit('-am', 'ephemeral change');
+call dolt_checkout('main');
+select count(*) from dolt_branches where name = 'tmp/one';
+select sleep(3);
+select count(*) from dolt_branches where name = 'tmp/one';
+SQL
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "count(*)"$'\n'"1" ]] || false
+    [[ "$output" =~ "count(*)"$'\n'"0" ]] || false
+
+    run dolt sql -r csv -q "select count(*) from t"
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "ephemeral-branches: deleted when the session that created them ends" {
+    start_sql_server
+
+    dolt sql -q "call dolt_branch('kept'); call dolt_checkout('-b', 'tmp/one', '--ttl', '1h'); call dolt_checkout('main');"
+    wait_for_branch_deleted "tmp/one"
+
+    run dolt sql -r csv -q "select name from dolt_branches order by name"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "kept" ]
+    [ "${lines[2]}" = "main" ]
+    [ "${#lines[@]}" -eq 3 ]
+}
+
+@test "ephemeral-branches: bad arguments" {
+    start_sql_server
+
+    run dolt sql -q "call dolt_checkout('main', '--ttl', '1h')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--ttl can only be used when creating a branch with -b or -B" ]] || false
+
+    run dolt sql -q "call dolt_checkout('-b', 'tmp/one', '--ttl', 'soon')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid --ttl 'soon'" ]] || false
+
+    run dolt sql -q "call dolt_checkout('-b', 'tmp/one', '--ttl', '-1h')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid --ttl '-1h'" ]] || false
+}
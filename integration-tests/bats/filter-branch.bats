@@ -678,4 +678,36 @@ SQL
     [[ "$output" =~ "1,1," ]] || false
     [[ "$output" =~ "2,2," ]] || false
     [[ "$output" =~ "3,3," ]] || false
-}
\ No newline at end of file
+}
+@test "filter-branch: rewritten refs are listed" {
+    dolt branch other
+    dolt tag v1
+    dolt sql -q "INSERT INTO test VALUES (7,7);"
+    dolt commit -am "added a row"
+
+    run dolt filter-branch --all -q "DELETE FROM test WHERE pk = 7;"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Ref 'refs/heads/main' was rewritten" ]] || false
+    [[ ! "$output" =~ "refs/heads/other" ]] || false
+    [[ ! "$output" =~ "refs/tags/v1" ]] || false
+}
+
+@test "filter-branch: --commit-map writes a mapping of rewritten commits" {
+    dolt sql -q "INSERT INTO test VALUES (7,7);"
+    dolt commit -am "added a row"
+    dolt sql -q "INSERT INTO test VALUES (8,8);"
+    dolt commit -am "added another row"
+    first=$(dolt sql -r csv -q "select hashof('HEAD~1')" | tail -n 1)
+    second=$(dolt sql -r csv -q "select hashof('HEAD')" | tail -n 1)
+    unchanged=$(dolt sql -r csv -q "select hashof('HEAD~2')" | tail -n 1)
+
+    dolt filter-branch --commit-map map.csv -q "DELETE FROM test WHERE pk = 7;"
+
+    run cat map.csv
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ "${lines[0]}" = "original_commit,rewritten_commit" ]
+    [ "${lines[1]}" = "$first,$(dolt sql -r csv -q "select hashof('HEAD~1')" | tail -n 1)" ]
+    [ "${lines[2]}" = "$second,$(dolt sql -r csv -q "select hashof('HEAD')" | tail -n 1)" ]
+    [[ ! "$output" =~ "$unchanged" ]] || false
+}
@@ -1290,6 +1290,99 @@ SQL
     [[ "$output" =~ "child,1" ]] || false
 }
 
+@test "foreign-keys: Merge cascades deletes onto rows added to the child" {
+    dolt sql <<SQL
+ALTER TABLE child ADD CONSTRAINT fk_name FOREIGN KEY (v1) REFERENCES parent(v1) ON DELETE CASCADE;
+INSERT INTO parent VALUES (1, 1, 1), (2, 2, 2);
+SQL
+    dolt add -A
+    dolt commit -m "initial commit"
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO child VALUES (1, 1, 1), (2, 2, 2);"
+    dolt add -A
+    dolt commit -m "added children"
+    dolt checkout main
+    dolt sql -q "DELETE FROM parent WHERE id = 1;"
+    dolt add -A
+    dolt commit -m "deleted parent"
+    dolt merge other -m "merge other"
+
+    run dolt sql -q "SELECT * FROM child ORDER BY id ASC" -r=csv
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "2,2,2" ]
+    [[ "${#lines[@]}" = "2" ]] || false
+    run dolt sql -q "SELECT count(*) FROM dolt_constraint_violations" -r=csv
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "foreign-keys: Merge cascades updates and sets nulls onto rows added to the child" {
+    dolt sql <<SQL
+ALTER TABLE child ADD CONSTRAINT fk_name FOREIGN KEY (v1) REFERENCES parent(v1) ON DELETE SET NULL ON UPDATE CASCADE;
+INSERT INTO parent VALUES (1, 1, 1), (2, 2, 2), (3, 3, 3);
+SQL
+    dolt add -A
+    dolt commit -m "initial commit"
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO child VALUES (1, 1, 1), (2, 2, 2), (3, 3, 3);"
+    dolt add -A
+    dolt commit -m "added children"
+    dolt checkout main
+    dolt sql <<SQL
+UPDATE parent SET v1 = 10 WHERE id = 1;
+DELETE FROM parent WHERE id = 2;
+SQL
+    dolt add -A
+    dolt commit -m "changed parents"
+    dolt merge other -m "merge other"
+
+    run dolt sql -q "SELECT * FROM child ORDER BY id ASC" -r=csv
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "1,10,1" ]
+    [ "${lines[2]}" = "2,,2" ]
+    [ "${lines[3]}" = "3,3,3" ]
+    run dolt sql -q "SELECT count(*) FROM dolt_constraint_violations" -r=csv
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "foreign-keys: deferred foreign key checks are done when the transaction is committed" {
+    dolt sql -q "ALTER TABLE child ADD CONSTRAINT fk_name FOREIGN KEY (v1) REFERENCES parent(v1);"
+    dolt add -A
+    dolt commit -m "initial commit"
+
+    dolt sql <<SQL
+SET @@dolt_defer_foreign_key_checks = 1;
+START TRANSACTION;
+INSERT INTO child VALUES (1, 1, 1);
+INSERT INTO parent VALUES (1, 1, 1);
+COMMIT;
+SQL
+
+    run dolt sql <<SQL
+SET @@dolt_defer_foreign_key_checks = 1;
+START TRANSACTION;
+INSERT INTO child VALUES (2, 2, 2);
+COMMIT;
+SQL
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "foreign key constraints are violated by the deferred changes to tables: child" ]] || false
+
+    run dolt sql -q "SELECT * FROM child ORDER BY id ASC" -r=csv
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "1,1,1" ]
+    [[ "${#lines[@]}" = "2" ]] || false
+
+    # statements are checked again once foreign key checks aren't deferred
+    run dolt sql <<SQL
+SET @@dolt_defer_foreign_key_checks = 1;
+SET @@dolt_defer_foreign_key_checks = 0;
+INSERT INTO child VALUES (2, 2, 2);
+SQL
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "Foreign key violation" ]] || false
+}
+
 @test "foreign-keys: different foreign keys with same name is schema conflict" {
     dolt commit -Am "initial commit"
 
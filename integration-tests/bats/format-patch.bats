@@ -0,0 +1,99 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "create table t (pk int primary key, c varchar(100));"
+    dolt commit -Am "create t"
+    dolt branch base
+
+    dolt sql -q "insert into t values (1, 'it''s a -- test; ok'), (2, 'b');"
+    dolt commit -am "add rows"
+    dolt sql -q "alter table t add column d int; update t set d = 5 where pk = 2;"
+    dolt commit -am "add column d"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "format-patch: writes one patch per commit" {
+    run dolt format-patch base -o patches
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "patches/0001-add-rows.sql" ]
+    [ "${lines[1]}" = "patches/0002-add-column-d.sql" ]
+
+    run cat patches/0001-add-rows.sql
+    [[ "$output" =~ "-- dolt format-patch" ]] || false
+    [[ "$output" =~ "-- add rows" ]] || false
+    [[ "$output" =~ "INSERT INTO \`t\`" ]] || false
+
+    run cat patches/0002-add-column-d.sql
+    [[ "$output" =~ "ALTER TABLE \`t\` ADD \`d\` int;" ]] || false
+}
+
+@test "format-patch: --stdout and explicit ranges" {
+    run dolt format-patch --stdout base..HEAD~1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "-- add rows" ]] || false
+    [[ ! "$output" =~ "add column d" ]] || false
+    [ ! -d patches ]
+
+    run dolt format-patch --stdout -o patches base
+    [ "$status" -eq 1 ]
+
+    run dolt format-patch base...HEAD
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "symmetric revision ranges are not supported" ]] || false
+}
+
+@test "format-patch: apply recreates the commits on another branch" {
+    dolt format-patch base -o patches
+    dolt checkout base
+
+    run dolt apply patches/0001-add-rows.sql patches/0002-add-column-d.sql
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Applied patches/0001-add-rows.sql: add rows" ]] || false
+    [[ "$output" =~ "Applied patches/0002-add-column-d.sql: add column d" ]] || false
+
+    run dolt log --oneline -n 2
+    [[ "${lines[0]}" =~ "add column d" ]] || false
+    [[ "${lines[1]}" =~ "add rows" ]] || false
+
+    run dolt diff main base
+    [ "$status" -eq 0 ]
+    [ "$output" = "" ]
+}
+
+@test "format-patch: apply --no-commit leaves changes in the working set" {
+    dolt format-patch base -o patches
+    dolt checkout base
+
+    run dolt apply --no-commit patches/0001-add-rows.sql
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "select c from t where pk = 1" -r csv
+    [[ "$output" =~ "it's a -- test; ok" ]] || false
+
+    run dolt log --oneline -n 1
+    [[ "$output" =~ "create t" ]] || false
+
+    run dolt apply patches/0002-add-column-d.sql
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "your local changes would be committed along with the patches" ]] || false
+}
+
+@test "format-patch: apply fails on a conflicting patch" {
+    dolt format-patch base -o patches
+
+    run dolt apply patches/0001-add-rows.sql
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "failed to apply patch 'patches/0001-add-rows.sql'" ]] || false
+
+    echo "insert into t values (3, 'c');" > not-a-patch.sql
+    run dolt apply not-a-patch.sql
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "not a dolt patch file" ]] || false
+}
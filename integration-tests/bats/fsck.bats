@@ -0,0 +1,84 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "create table t (pk int primary key, c varchar(20));"
+    dolt sql -q "insert into t values (1, 'one'), (2, 'two');"
+    dolt commit -Am "first commit"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+# corrupt_oldgen_table_file overwrites the start of the first chunk in the only table file in oldgen
+corrupt_oldgen_table_file() {
+    table_file=$(ls .dolt/noms/oldgen | grep -E '^[0-9a-v]{32}$' | head -n 1)
+    printf '\xff\xff\xff' | dd of=".dolt/noms/oldgen/$table_file" bs=1 seek=5 conv=notrunc 2>/dev/null
+    echo "$table_file"
+}
+
+@test "fsck: no problems in a healthy database" {
+    run dolt fsck
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "no problems found" ]] || false
+
+    dolt gc
+    dolt sql -q "insert into t values (3, 'three');"
+    dolt commit -am "second commit"
+
+    run dolt fsck
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "no problems found" ]] || false
+}
+
+@test "fsck: reports corrupt chunks" {
+    dolt gc
+    table_file=$(corrupt_oldgen_table_file)
+
+    run dolt fsck
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "oldgen: table file $table_file: chunk" ]] || false
+    [[ "$output" =~ "checksum error" ]] || false
+    [[ "$output" =~ "found 2 problems" ]] || false
+
+    # nothing was changed
+    [ -f ".dolt/noms/oldgen/$table_file" ]
+}
+
+@test "fsck: --quarantine moves corrupt table files out of the database" {
+    dolt gc
+    table_file=$(corrupt_oldgen_table_file)
+
+    run dolt fsck --quarantine
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "quarantined table files: $table_file" ]] || false
+    [[ "$output" =~ "1 corrupt chunks could not be salvaged" ]] || false
+    [[ "$output" =~ "is missing" ]] || false
+
+    [ ! -f ".dolt/noms/oldgen/$table_file" ]
+    [ -f ".dolt/noms/oldgen/quarantine/$table_file" ]
+
+    # the chunk which was lost is still reported as missing, but nothing is corrupt
+    run dolt fsck
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "is missing" ]] || false
+    [[ ! "$output" =~ "checksum error" ]] || false
+    [[ "$output" =~ "found 1 problems" ]] || false
+}
+
+@test "fsck: reports unreadable data in the chunk journal" {
+    printf '\x01\x02' | dd of=.dolt/noms/vvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvv bs=1 seek=900000 conv=notrunc 2>/dev/null
+
+    run dolt fsck
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "chunk journal: journal has 2 bytes of unreadable data" ]] || false
+}
+
+@test "fsck: too many arguments" {
+    run dolt fsck foo
+    [ "$status" -ne 0 ]
+}
@@ -404,3 +404,59 @@ SQL
     echo "$AFTER"
     [ "$BEFORE" -gt "$AFTER" ]
 }
+
+@test "garbage_collection: gc retains recently deleted branch heads with --prune-older-than" {
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY);"
+    dolt commit -Am "create table"
+    dolt checkout -b temp
+    dolt sql -q "INSERT INTO test VALUES (1),(2),(3);"
+    dolt commit -Am "temp commit"
+    temp_head=$(dolt sql -q "select hashof('HEAD')" -r csv | tail -n 1)
+    dolt checkout main
+    dolt branch -D temp
+
+    dolt gc --prune-older-than 7
+
+    run dolt show "$temp_head"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "temp commit" ]] || false
+}
+
+@test "garbage_collection: gc without retention collects deleted branch heads" {
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY);"
+    dolt commit -Am "create table"
+    dolt checkout -b temp
+    dolt sql -q "INSERT INTO test VALUES (1),(2),(3);"
+    dolt commit -Am "temp commit"
+    temp_head=$(dolt sql -q "select hashof('HEAD')" -r csv | tail -n 1)
+    dolt checkout main
+    dolt branch -D temp
+
+    dolt gc --prune-older-than 0
+
+    run dolt show "$temp_head"
+    [ "$status" -eq 1 ]
+}
+
+@test "garbage_collection: dolt_gc_retention_days sets the default retention" {
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY);"
+    dolt commit -Am "create table"
+    dolt checkout -b temp
+    dolt sql -q "INSERT INTO test VALUES (1),(2),(3);"
+    dolt commit -Am "temp commit"
+    temp_head=$(dolt sql -q "select hashof('HEAD')" -r csv | tail -n 1)
+    dolt checkout main
+    dolt branch -D temp
+
+    dolt sql -q "set @@global.dolt_gc_retention_days = 7; call dolt_gc();"
+
+    run dolt show "$temp_head"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "temp commit" ]] || false
+}
+
+@test "garbage_collection: --prune-older-than cannot be used with --shallow" {
+    run dolt gc --shallow --prune-older-than 7
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "cannot be used with --shallow" ]] || false
+}
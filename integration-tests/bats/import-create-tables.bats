@@ -916,3 +916,86 @@ DELIM
     [ "$status" -eq 1 ]
     [[ "$output" =~ "parameters all-text and schema are mutually exclusive" ]] || false
 }
+
+@test "import-create-tables: schema inference reports the type of each column" {
+    cat <<CSV > prices.csv
+id,price,amount,big,note
+1,1.25,12345678901234567.25,18446744073709551615,
+2,10.5,1.5,18446744073709551614,two
+3,0.125,7,18446744073709551613,three
+CSV
+    run dolt table import -c --pk=id prices prices.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Inferred the schema of prices from 3 of 3 rows:" ]] || false
+    [[ "$output" =~ "price: float" ]] || false
+    [[ "$output" =~ "amount: decimal(19,2) (holds values of types" ]] || false
+    [[ "$output" =~ "big: bigint unsigned" ]] || false
+    [[ "$output" =~ "note: varchar(1023) (has empty values)" ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+
+    run dolt sql -q "select sum(price) from prices" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "11.875" ]] || false
+
+    run dolt table import -c --pk=id --quiet quiet_prices prices.csv
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "Inferred the schema" ]] || false
+}
+
+@test "import-create-tables: --sample-rows infers the schema from the first rows" {
+    cat <<CSV > sampled.csv
+id,val
+1,1
+2,2
+3,three
+CSV
+    run dolt table import -c --pk=id --sample-rows 2 sampled sampled.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "from 2 of 2 rows" ]] || false
+
+    run dolt table import -c --pk=id --sample-rows 0 sampled sampled.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--sample-rows must be positive" ]] || false
+
+    run dolt table import -u --sample-rows 2 sampled sampled.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--sample-rows is only supported for create operations" ]] || false
+}
+
+@test "import-create-tables: --column-types and --column-types-file override inferred types" {
+    cat <<CSV > typed.csv
+id,price,code
+1,1.5,007
+2,2.25,010
+CSV
+    run dolt table import -c --pk=id --column-types "price=decimal(10,2),code=varchar(3)" typed typed.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "price: decimal(10,2) (given type)" ]] || false
+
+    run dolt sql -q "describe typed" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "price,\"decimal(10,2)\"" ]] || false
+    [[ "$output" =~ "code,varchar(3)" ]] || false
+
+    cat <<JSON > types.json
+{"id": "bigint", "code": "char(3)"}
+JSON
+    run dolt table import -c --pk=id --column-types-file types.json typed2 typed.csv
+    [ "$status" -eq 0 ]
+    run dolt sql -q "describe typed2" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "id,bigint" ]] || false
+    [[ "$output" =~ "code,char(3)" ]] || false
+
+    run dolt table import -c --pk=id --column-types "nope=int" typed3 typed.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "a type was given for column nope, which isn't in the file" ]] || false
+
+    run dolt table import -c --pk=id --column-types "price=notatype" typed3 typed.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid type 'notatype' for column price" ]] || false
+
+    run dolt table import -c --pk=id --all-text --column-types "price=int" typed3 typed.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--column-types is only supported when the schema is inferred" ]] || false
+}
@@ -1486,3 +1486,76 @@ DELIM
     [ "$status" -eq 1 ]
     [[ "$output" =~ "fatal: --all-text is only supported for create operations" ]] || false
 }
+
+@test "import-update-tables: --sync deletes the rows which aren't in the file" {
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY, c1 int, c2 varchar(10) DEFAULT 'keep')"
+    dolt sql -q "INSERT INTO test VALUES (0, 0, 'a'), (1, 1, 'b'), (2, 2, 'c')"
+    dolt commit -Am "add rows"
+
+    cat <<DELIM > sync.csv
+pk,c1
+1,10
+2,2
+3,3
+DELIM
+
+    run dolt table import -u --sync test sync.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Rows Processed: 3, Additions: 1, Modifications: 1, Had No Effect: 1, Deletions: 1" ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+
+    run dolt sql -r csv -q "SELECT * FROM test ORDER BY pk"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,10,b" ]
+    [ "${lines[2]}" = "2,2,c" ]
+    [ "${lines[3]}" = "3,3,keep" ]
+    [ "${#lines[@]}" -eq 4 ]
+}
+
+@test "import-update-tables: --commit-message commits the import" {
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY, c1 int)"
+    dolt sql -q "INSERT INTO test VALUES (0, 0), (1, 1)"
+    dolt commit -Am "add rows"
+
+    cat <<DELIM > sync.csv
+pk,c1
+1,10
+DELIM
+
+    run dolt table import -u --sync --commit-message "sync test" test sync.csv
+    [ "$status" -eq 0 ]
+
+    run dolt log -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "sync test" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt sql -r csv -q "SELECT diff_type, from_pk, to_pk FROM dolt_diff_test WHERE to_commit = HASHOF('HEAD') ORDER BY from_pk"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "removed,0," ]
+    [ "${lines[2]}" = "modified,1,1" ]
+}
+
+@test "import-update-tables: --sync argument validation" {
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY, c1 int)"
+    dolt sql -q "CREATE TABLE keyless (c1 int)"
+    cat <<DELIM > sync.csv
+pk,c1
+1,10
+DELIM
+
+    run dolt table import -r --sync test sync.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--sync is only supported for update operations" ]] || false
+
+    run dolt table import -u --sync --continue test sync.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "mutually exclusive" ]] || false
+
+    run dolt table import -u --sync keyless sync.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "must have a primary key" ]] || false
+}
@@ -2722,3 +2722,50 @@ SQL
     # column should still exist
     [[ "$output" =~ '`v1` int' ]] || false
 }
+
+@test "index: prefix compressed secondary index keys" {
+    export DOLT_INDEX_KEY_PREFIX_COMPRESSION=1
+    dolt sql <<SQL
+CREATE TABLE pages (id int PRIMARY KEY, url varchar(200) COLLATE utf8mb4_0900_ai_ci, KEY url_idx (url));
+INSERT INTO pages
+  WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 2000)
+  SELECT i, concat('https://www.example.com/products/café/', lpad(i, 5, '0')) FROM n;
+SQL
+    dolt commit -Am "add pages"
+
+    # older clients can't read compressed keys
+    export EXPECTED_FEATURE_VERSION=9
+    run dolt version --feature
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "feature version: 9" ]] || false
+
+    run dolt sql -q "SELECT id FROM pages WHERE url = 'https://www.example.com/products/café/01234'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1234" ]
+
+    # the index uses the column's collation
+    run dolt sql -q "SELECT id FROM pages WHERE url = 'HTTPS://WWW.EXAMPLE.COM/PRODUCTS/CAFE/01234'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1234" ]
+
+    run dolt sql -q "SELECT count(*), min(id), max(id) FROM pages WHERE url >= 'https://www.example.com/products/café/00100' AND url < 'https://www.example.com/products/café/00200'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "100,100,199" ]
+
+    dolt sql -q "UPDATE pages SET url = concat(url, '/v2') WHERE id % 3 = 0; DELETE FROM pages WHERE id % 5 = 0"
+    run dolt sql -q "SELECT count(*) FROM pages WHERE url LIKE 'https://www.example.com/products/café/%/v2'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "533" ]
+
+    # maps stay compressed once they are, and roots keep the feature version
+    unset DOLT_INDEX_KEY_PREFIX_COMPRESSION
+    dolt sql -q "INSERT INTO pages VALUES (2001, 'https://www.example.com/products/café/02001/v2')"
+    run dolt sql -q "SELECT count(*) FROM pages WHERE url LIKE 'https://www.example.com/products/café/%/v2'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "534" ]
+
+    dolt sql -q "ALTER TABLE pages DROP INDEX url_idx; ALTER TABLE pages ADD INDEX url_idx (url)"
+    run dolt sql -q "SELECT count(*) FROM pages WHERE url LIKE 'https://www.example.com/products/café/%/v2'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "534" ]
+}
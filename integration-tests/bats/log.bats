@@ -1043,4 +1043,32 @@ export NO_COLOR=1
     [[  "${lines[18]}" =~ "|/" ]] || false                               # |/
     [[  "${lines[19]}" =~ "* commit" ]] || false                         # *  commit Initialize data repository
 
-}
\ No newline at end of file
+}
+@test "log: --result-format json" {
+    dolt sql -q "create table testtable (pk int PRIMARY KEY)"
+    dolt add .
+    dolt commit -m "commit 1"
+    dolt tag v1
+
+    run dolt log --result-format json -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"commits": [' ]] || false
+    [[ "$output" =~ '"message": "commit 1"' ]] || false
+    [[ "$output" =~ '"HEAD"' ]] || false
+    [[ "$output" =~ '"refs/heads/main"' ]] || false
+    [[ "$output" =~ '"refs/tags/v1"' ]] || false
+    [[ ! "$output" =~ "Initialize data repository" ]] || false
+
+    run dolt log -r json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"message": "Initialize data repository"' ]] || false
+    [[ "$output" =~ '"parents": []' ]] || false
+
+    run dolt log -r json --graph
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--graph is not supported with --result-format json" ]] || false
+
+    run dolt log -r xml
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid --result-format: xml" ]] || false
+}
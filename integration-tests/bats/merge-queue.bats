@@ -0,0 +1,158 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+load $BATS_TEST_DIRNAME/helper/query-server-common.bash
+
+setup() {
+    skiponwindows "tests are flaky on Windows"
+    if [ "$SQL_ENGINE" = "remote-engine" ]; then
+      skip "This test tests remote connections directly, SQL_ENGINE is not needed."
+    fi
+    setup_common
+
+    dolt sql -q "create table t (pk int primary key, c int);"
+    dolt sql -q "insert into t values (1, 1);"
+    dolt commit -Am "first commit"
+    dolt branch feature
+}
+
+teardown() {
+    stop_sql_server 1 && sleep 0.5
+    assert_feature_version
+    teardown_common
+}
+
+# wait_for_merge polls dolt_merge_queue until the merge with the id given is no longer queued or running
+wait_for_merge() {
+    for i in $(seq 1 50); do
+        run dolt sql -r csv -q "select status from dolt_merge_queue where id = $1"
+        [[ "${lines[1]}" =~ ^(queued|running)$ ]] || break
+        sleep 0.1
+    done
+    [[ ! "${lines[1]}" =~ ^(queued|running)$ ]] || false
+}
+
+@test "merge-queue: queued merges need a running sql-server" {
+    run dolt sql -q "call dolt_merge_queue('add', 'feature')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "the merge queue is only available in a running sql-server" ]] || false
+}
+
+@test "merge-queue: merge a branch into main" {
+    dolt checkout feature
+    dolt sql -q "insert into t values (2, 2);"
+    dolt commit -am "add a row on feature"
+    dolt checkout main
+    dolt sql -q "insert into t values (3, 3);"
+    dolt commit -am "add a row on main"
+
+    start_sql_server
+    run dolt sql -r csv -q "call dolt_merge_queue('add', 'feature')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+    wait_for_merge 1
+
+    run dolt sql -r csv -q "select source_branch, target_branch, status, error, enqueued_by from dolt_merge_queue"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "feature,main,merged,,root" ]
+
+    run dolt sql -r csv -q "select commit_hash = hashof('main') from dolt_merge_queue where id = 1"
+    [ "${lines[1]}" = "true" ]
+
+    run dolt sql -r csv -q "select message from dolt_log limit 1"
+    [ "${lines[1]}" = "Merge branch 'feature' into main" ]
+
+    run dolt sql -r csv -q "select pk from t order by pk"
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "2" ]
+    [ "${lines[3]}" = "3" ]
+}
+
+@test "merge-queue: merges with conflicts fail and leave the target branch alone" {
+    dolt checkout feature
+    dolt sql -q "update t set c = 2 where pk = 1;"
+    dolt commit -am "change on feature"
+    dolt checkout main
+    dolt sql -q "update t set c = 3 where pk = 1;"
+    dolt commit -am "change on main"
+    head=$(dolt sql -r csv -q "select hashof('main')" | tail -n 1)
+
+    start_sql_server
+    dolt sql -q "call dolt_merge_queue('add', 'feature', '--into', 'main')"
+    wait_for_merge 1
+
+    run dolt sql -r csv -q "select status, error from dolt_merge_queue where id = 1"
+    [ "$status" -eq 0 ]
+    [[ "${lines[1]}" =~ "failed,merging feature into main produced conflicts" ]] || false
+
+    run dolt sql -r csv -q "select hashof('main')"
+    [ "${lines[1]}" = "$head" ]
+}
+
+@test "merge-queue: merges fail when tests fail with --run-tests" {
+    dolt sql -q "insert into dolt_tests values ('one row', NULL, 'select * from t', 'expected_rows', '==', '1');"
+    dolt commit -Am "add test"
+    dolt checkout feature
+    dolt sql -q "insert into t values (2, 2);"
+    dolt commit -am "add a row on feature"
+    dolt checkout main
+    head=$(dolt sql -r csv -q "select hashof('main')" | tail -n 1)
+
+    start_sql_server
+    dolt sql -q "call dolt_merge_queue('add', 'feature', '--run-tests')"
+    wait_for_merge 1
+
+    run dolt sql -r csv -q "select run_tests, status, error from dolt_merge_queue where id = 1"
+    [ "$status" -eq 0 ]
+    [[ "${lines[1]}" =~ "true,failed,1 of 1 tests failed: one row" ]] || false
+
+    run dolt sql -r csv -q "select hashof('main')"
+    [ "${lines[1]}" = "$head" ]
+
+    # without --run-tests the same merge goes through
+    dolt sql -q "call dolt_merge_queue('add', 'feature')"
+    wait_for_merge 2
+    run dolt sql -r csv -q "select status from dolt_merge_queue where id = 2"
+    [ "${lines[1]}" = "merged" ]
+}
+
+@test "merge-queue: bad arguments" {
+    start_sql_server
+
+    run dolt sql -q "call dolt_merge_queue('add', 'missing')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "branch not found: missing" ]] || false
+
+    run dolt sql -q "call dolt_merge_queue('add', 'main')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot merge branch main into itself" ]] || false
+
+    run dolt sql -q "call dolt_merge_queue('cancel', '100')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no merge with id 100" ]] || false
+
+    dolt sql -q "call dolt_merge_queue('add', 'feature')"
+    wait_for_merge 1
+    run dolt sql -q "call dolt_merge_queue('cancel', '1')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "merge 1 is merged and can't be canceled" ]] || false
+}
+
+@test "merge-queue: merges need write access to the target branch" {
+    dolt sql -q "create user test"
+    dolt sql -q "grant all on *.* to test"
+    dolt sql -q "delete from dolt_branch_control where user='%'"
+    dolt sql -q "insert into dolt_branch_control values ('dolt-repo-$$', 'feature', 'test', '%', 'write')"
+    head=$(dolt sql -r csv -q "select hashof('main')" | tail -n 1)
+
+    start_sql_server
+    dolt -u test sql -q "call dolt_checkout('feature'); insert into t values (2, 2); call dolt_commit('-am', 'add a row on feature');"
+
+    run dolt -u test sql -q "call dolt_checkout('feature'); call dolt_merge_queue('add', 'feature', '--into', 'main')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "does not have the correct permissions" ]] || false
+
+    run dolt sql -r csv -q "select count(*) from dolt_merge_queue"
+    [ "${lines[1]}" = "0" ]
+    run dolt sql -r csv -q "select hashof('main')"
+    [ "${lines[1]}" = "$head" ]
+}
@@ -1360,3 +1360,27 @@ SQL
     run dolt merge b1
     log_status_eq 0
 }
+
+@test "merge: --result-format json" {
+    dolt checkout -b merge_branch
+    dolt sql -q "insert into test1 values (1, 1, 1)"
+    dolt commit -am "add row on merge_branch"
+    dolt checkout main
+    dolt sql -q "insert into test2 values (1, 1, 1)"
+    dolt commit -am "add row on main"
+
+    run dolt merge merge_branch --no-edit --result-format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"fast_forward": false' ]] || false
+    [[ "$output" =~ '"conflicts": false' ]] || false
+    [[ "$output" =~ '"table": "test1"' ]] || false
+    [[ "$output" =~ '"rows_added": 1' ]] || false
+
+    run dolt merge merge_branch --result-format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"up_to_date": true' ]] || false
+
+    run dolt merge --abort --result-format json
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--abort is not supported with --result-format json" ]] || false
+}
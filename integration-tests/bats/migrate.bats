@@ -347,3 +347,56 @@ SQL
    [[ $output =~ "CONSTRAINT \`j_chk\` CHECK ((\`j\` = 0))" ]] || false
    [[ $output =~ ") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci" ]] || false
 }
+
+@test "migrate: logs table progress and verifies branch heads" {
+    dolt sql <<SQL
+CREATE TABLE test (pk int primary key, c0 varchar(20));
+INSERT INTO test VALUES (0,'zero'),(1,'one');
+CALL dolt_add('-A');
+CALL dolt_commit('-am', 'added table test');
+SQL
+
+    run dolt migrate
+    [ $status -eq 0 ]
+    [[ "$output" =~ "migrated table test (2 rows)" ]] || false
+    [[ "$output" =~ "verified table test on branch main (2 rows" ]] || false
+    [ ! -d .dolt/migration ]
+}
+
+@test "migrate: only the given branches" {
+    dolt sql <<SQL
+CREATE TABLE test (pk int primary key, c0 int);
+INSERT INTO test VALUES (0,0);
+CALL dolt_add('-A');
+CALL dolt_commit('-am', 'added table test');
+CALL dolt_tag('on_main', 'head');
+CALL dolt_branch('keep');
+CALL dolt_branch('drop');
+CALL dolt_checkout('drop');
+INSERT INTO test VALUES (1,1);
+CALL dolt_commit('-am', 'row on drop');
+CALL dolt_tag('on_drop', 'head');
+SQL
+
+    run dolt migrate keep
+    [ $status -ne 0 ]
+    [[ "$output" =~ "checked out branch main must be migrated" ]] || false
+
+    run dolt migrate main missing
+    [ $status -ne 0 ]
+    [[ "$output" =~ "branch not found: missing" ]] || false
+
+    run dolt migrate main keep
+    [ $status -eq 0 ]
+    [[ "$output" =~ "skipping refs/tags/on_drop" ]] || false
+    [[ $(cat ./.dolt/noms/manifest | cut -f 2 -d :) = "$TARGET_NBF" ]] || false
+
+    run dolt branch
+    [[ "$output" =~ "main" ]] || false
+    [[ "$output" =~ "keep" ]] || false
+    [[ ! "$output" =~ "drop" ]] || false
+
+    run dolt tag
+    [[ "$output" =~ "on_main" ]] || false
+    [[ ! "$output" =~ "on_drop" ]] || false
+}
@@ -0,0 +1,162 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "create table test (pk int primary key);"
+    dolt add .
+    dolt commit -m "created table test"
+    dolt sql -q "insert into test values (1);"
+    dolt commit -am "added a row"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "notes: add, show, append and remove notes" {
+    head=$(dolt sql -r csv -q "select hashof('HEAD')" | tail -n 1)
+    parent=$(dolt sql -r csv -q "select hashof('HEAD~1')" | tail -n 1)
+
+    run dolt notes
+    [ $status -eq 0 ]
+    [ "$output" = "" ]
+
+    dolt notes add -m "looks good"
+    dolt notes add -m "first commit" HEAD~1
+
+    run dolt notes show
+    [ $status -eq 0 ]
+    [ "$output" = "looks good" ]
+
+    run dolt notes list
+    [ $status -eq 0 ]
+    [[ "$output" =~ "$head" ]] || false
+    [[ "$output" =~ "$parent" ]] || false
+
+    run dolt notes add -m "again"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "use '-f' to overwrite existing notes" ]] || false
+
+    dolt notes add -f -m "replaced"
+    dolt notes append -m "appended"
+    run dolt sql -r csv -q "select note from dolt_notes where commit_hash = '$head'"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "replaced" ]] || false
+    [[ "$output" =~ "appended" ]] || false
+
+    dolt notes remove
+    run dolt notes show
+    [ $status -ne 0 ]
+    [[ "$output" =~ "no note found for commit $head" ]] || false
+
+    run dolt notes remove
+    [ $status -ne 0 ]
+    [[ "$output" =~ "no note found" ]] || false
+
+    run dolt notes list
+    [ $status -eq 0 ]
+    [ "$output" = "$parent" ]
+}
+
+@test "notes: notes don't change commits" {
+    head=$(dolt sql -r csv -q "select hashof('HEAD')" | tail -n 1)
+    dolt notes add -m "a note"
+
+    [ "$(dolt sql -r csv -q "select hashof('HEAD')" | tail -n 1)" = "$head" ]
+    run dolt sql -r csv -q "select count(*) from dolt_log"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+
+    run dolt status
+    [ $status -eq 0 ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+}
+
+@test "notes: notes refs are separate namespaces" {
+    head=$(dolt sql -r csv -q "select hashof('HEAD')" | tail -n 1)
+    dolt notes add -m "default"
+    dolt notes --ref reviews add -m "approved by alice"
+    dolt sql -q "call dolt_notes('add', '--ref', 'refs/notes/quality', '-m', '0.95')"
+
+    run dolt notes --ref reviews show
+    [ $status -eq 0 ]
+    [ "$output" = "approved by alice" ]
+
+    run dolt sql -r csv -q "select notes_ref, commit_hash = '$head', note from dolt_notes order by notes_ref"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "commits,true,default" ]
+    [ "${lines[2]}" = "quality,true,0.95" ]
+    [ "${lines[3]}" = "reviews,true,approved by alice" ]
+
+    run dolt notes --ref refs/heads/main add -m "bad ref"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "invalid notes ref" ]] || false
+
+    run dolt branch
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "reviews" ]] || false
+}
+
+@test "notes: dolt_notes procedure" {
+    dolt sql -q "call dolt_notes('add', '-m', 'from sql', 'HEAD~1')"
+    run dolt notes show HEAD~1
+    [ $status -eq 0 ]
+    [ "$output" = "from sql" ]
+
+    run dolt sql -q "call dolt_notes('add', '-m', 'again', 'HEAD~1')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "found existing notes" ]] || false
+
+    run dolt sql -q "call dolt_notes('add')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "must be given with -m" ]] || false
+
+    run dolt sql -q "call dolt_notes('edit', '-m', 'x')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "unknown subcommand" ]] || false
+
+    dolt sql -q "call dolt_notes('remove', 'HEAD~1')"
+    run dolt sql -r csv -q "select count(*) from dolt_notes"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "notes: push and fetch notes" {
+    mkdir remote
+    dolt remote add origin file://./remote
+    dolt push origin main
+    dolt notes add -m "reviewed"
+
+    run dolt push origin refs/notes/commits
+    [ $status -eq 0 ]
+    [[ "$output" =~ "refs/notes/commits -> refs/notes/commits" ]] || false
+
+    dolt clone file://./remote clone
+    cd clone
+    dolt fetch --notes
+    run dolt notes show
+    [ $status -eq 0 ]
+    [ "$output" = "reviewed" ]
+
+    # a notes ref is only pushed if it's a fast-forward
+    dolt notes append -m "from the clone"
+    dolt push origin refs/notes/commits
+    cd ..
+    dolt notes add -f -m "diverged"
+    run dolt push origin refs/notes/commits
+    [ $status -ne 0 ]
+    [[ "$output" =~ "rejected" ]] || false
+
+    run dolt fetch --notes
+    [ $status -ne 0 ]
+    [[ "$output" =~ "diverged" ]] || false
+
+    dolt push -f origin refs/notes/commits
+    cd clone
+    run dolt fetch --notes
+    [ $status -ne 0 ]
+    [[ "$output" =~ "diverged" ]] || false
+}
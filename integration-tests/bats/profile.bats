@@ -394,3 +394,111 @@ teardown() {
     dolt profile
     chmod 755 .
 }
+
+@test "profile: profiles inherit values from the profiles they include" {
+    dolt profile add --use-db defaultDB base
+    dolt profile add --user dolt --password "" --include base child
+
+    run dolt --profile child sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "defaultDB_tbl" ]] || false
+
+    # values set by the profile take precedence over included values
+    dolt profile add --use-db altDB --include base override
+    run dolt --profile override sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "altDB_tbl" ]] || false
+
+    run dolt profile -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "include: base" ]] || false
+}
+
+@test "profile: profiles can't include themselves" {
+    run dolt profile add --include selfTest selfTest
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "profile selfTest cannot include itself" ]] || false
+
+    dolt profile add --include b --use-db defaultDB a
+    dolt profile add --include a b
+    run dolt --profile a sql -q "show tables"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "profile a includes itself" ]] || false
+
+    dolt profile add --include nonExistent missingInclude
+    run dolt --profile missingInclude sql -q "show tables"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "profile nonExistent not found" ]] || false
+}
+
+@test "profile: environment variables in profiles are expanded" {
+    dolt profile add --use-db '$PROFILE_DB' envTest
+
+    PROFILE_DB=altDB run dolt --profile envTest sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "altDB_tbl" ]] || false
+
+    PROFILE_DB=defaultDB run dolt --profile envTest sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "defaultDB_tbl" ]] || false
+
+    # the variable is saved, not its value
+    run dolt profile -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ 'use-db: $PROFILE_DB' ]] || false
+}
+
+@test "profile: repository profiles take precedence over global profiles" {
+    dolt profile add --use-db defaultDB shared
+
+    run dolt profile add --local --use-db altDB shared
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "can only be used in a dolt repository" ]] || false
+
+    cd altDB
+    dolt profile add --local --use-db altDB shared
+    run dolt --profile shared sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "altDB_tbl" ]] || false
+    cd ..
+
+    run dolt --profile shared sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "defaultDB_tbl" ]] || false
+
+    cd altDB
+    dolt profile remove --local shared
+    run dolt profile
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "shared" ]] || false
+}
+
+@test "profile: profiles apply saved command arguments" {
+    dolt profile add --use-db defaultDB --args "sql --result-format=csv" csvTest
+
+    run dolt --profile csvTest sql -q "select 1 as a"
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "a" ]
+    [ "${lines[1]}" = "1" ]
+
+    # arguments on the command line take precedence
+    run dolt --profile csvTest sql -r json -q "select 1 as a"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '{"rows": [{"a":1}]}' ]] || false
+
+    run dolt profile -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "args: sql --result-format=csv" ]] || false
+}
+
+@test "profile: invalid saved command arguments are reported" {
+    dolt profile add --args "sql-server --no-such-option" badArgs
+    run dolt --profile badArgs sql-server
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid profile arguments for sql-server" ]] || false
+
+    dolt profile add --args "sql positional" positionalArgs
+    run dolt --profile positionalArgs sql -q "select 1"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "profile arguments for sql" ]] || false
+}
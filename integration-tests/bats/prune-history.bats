@@ -0,0 +1,133 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "create table t (pk int primary key, c varchar(20));"
+    dolt sql -q "insert into t values (1, 'secret');"
+    dolt commit -Am "add secret"
+    dolt sql -q "update t set c = 'redacted' where pk = 1;"
+    dolt commit -am "redact secret"
+    dolt sql -q "insert into t values (2, 'two');"
+    dolt commit -am "add two"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "prune-history: prune the commits before a commit" {
+    secret=$(dolt sql -r csv -q "select commit_hash from dolt_log where message = 'add secret'" | tail -n 1)
+
+    run dolt prune-history HEAD~1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Pruned the history of refs/heads/main" ]] || false
+
+    run dolt sql -r csv -q "select message from dolt_log"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ "${lines[1]}" = "add two" ]
+    [ "${lines[2]}" = "redact secret" ]
+
+    run dolt sql -r csv -q "select * from t order by pk"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,redacted" ]
+    [ "${lines[2]}" = "2,two" ]
+
+    run dolt sql -r csv -q "select count(*) from dolt_history_t where c = 'secret'"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+
+    # the pruned commits were garbage collected
+    run dolt show $secret
+    [ "$status" -eq 1 ]
+}
+
+@test "prune-history: prune the commits made before a date" {
+    dolt sql -q "insert into t values (3, 'three');"
+    dolt commit -am "add three" --date "2099-01-01T00:00:00"
+
+    run dolt prune-history --before 2050-01-01
+    [ "$status" -eq 0 ]
+
+    run dolt sql -r csv -q "select message from dolt_log"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "add three" ]
+
+    run dolt sql -r csv -q "select count(*) from t"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+}
+
+@test "prune-history: branches and tags are rewritten, and their heads are kept" {
+    dolt tag v1 HEAD~2
+    dolt branch feature HEAD~1
+    dolt checkout feature
+    dolt sql -q "insert into t values (10, 'feature');"
+    dolt commit -am "feature commit"
+    dolt checkout main
+
+    dolt prune-history HEAD~1
+
+    # the tag is on a pruned commit, but is kept as a root commit
+    run dolt sql -r csv -q "select count(*) from dolt_log('v1')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+    run dolt sql -r csv -q "select * from t as of 'v1'"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,secret" ]
+
+    run dolt sql -r csv -q "select message from dolt_log('feature')"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ "${lines[1]}" = "feature commit" ]
+    [ "${lines[2]}" = "redact secret" ]
+
+    # main and feature still share history
+    run dolt merge-base main feature
+    [ "$status" -eq 0 ]
+    [ "$output" = "$(dolt sql -r csv -q "select hashof('main~1')" | tail -n 1)" ]
+}
+
+@test "prune-history: uncommitted changes are kept" {
+    dolt sql -q "insert into t values (3, 'three');"
+    dolt sql -q "insert into t values (4, 'four');"
+    dolt add t
+    dolt sql -q "delete from t where pk = 4;"
+
+    dolt prune-history --no-gc HEAD~1
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Changes to be committed" ]] || false
+    [[ "$output" =~ "Changes not staged for commit" ]] || false
+
+    run dolt sql -r csv -q "select count(*) from t"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+}
+
+@test "prune-history: errors" {
+    run dolt prune-history
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "exactly one of a commit or --before must be given" ]] || false
+
+    run dolt prune-history --before 2000-01-01 HEAD
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "exactly one of a commit or --before must be given" ]] || false
+
+    run dolt prune-history --before notadate
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid date" ]] || false
+
+    run dolt prune-history doesnotexist
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unable to resolve commit 'doesnotexist'" ]] || false
+
+    run dolt prune-history --before 2000-01-01
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Nothing to prune" ]] || false
+}
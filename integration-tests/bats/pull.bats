@@ -541,4 +541,152 @@ SQL
     run dolt ls
     [ "$status" -eq 0 ]
     [[ "$output" =~ "testTable" ]] || false
-}
\ No newline at end of file
+}
+# setup_diverged_main makes a commit on main in repo1 which is pushed, and a commit on main in repo2 which isn't, so
+# that the two have diverged. The commits conflict if an argument is given.
+setup_diverged_main() {
+    cd $TESTDIRS/repo2
+    dolt pull origin main
+
+    cd $TESTDIRS/repo1
+    if [ -n "$1" ]; then
+        dolt sql -q "update t1 set b = 1 where a = 0"
+    else
+        dolt sql -q "insert into t1 values (1, 1)"
+    fi
+    dolt commit -am "remote commit"
+    dolt push origin main
+
+    cd $TESTDIRS/repo2
+    if [ -n "$1" ]; then
+        dolt sql -q "update t1 set b = 2 where a = 0"
+    else
+        dolt sql -q "insert into t1 values (2, 2)"
+    fi
+    dolt commit -am "local commit"
+}
+
+@test "pull: pull --rebase replays local commits on top of the remote branch" {
+    setup_diverged_main
+
+    run dolt pull --rebase origin main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/main" ]] || false
+
+    run dolt log --oneline -n 3
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "local commit" ]] || false
+    [[ "${lines[1]}" =~ "remote commit" ]] || false
+    [[ "${lines[2]}" =~ "Second commit" ]] || false
+    ! [[ "$output" =~ "Merge branch" ]] || false
+
+    run dolt sql -q "select * from t1 order by a" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0,0" ]
+    [ "${lines[2]}" = "1,1" ]
+    [ "${lines[3]}" = "2,2" ]
+
+    run dolt branch
+    [ "$status" -eq 0 ]
+    ! [[ "$output" =~ "dolt_rebase_main" ]] || false
+
+    run dolt pull --rebase --squash origin main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--rebase can't be used with --squash" ]] || false
+
+    run dolt pull --rebase --no-rebase origin main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot be used together" ]] || false
+}
+
+@test "pull: pull.rebase config rebases by default" {
+    setup_diverged_main
+    dolt config --local --add pull.rebase true
+
+    run dolt pull origin main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/main" ]] || false
+
+    run dolt log --oneline -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "local commit" ]] || false
+
+    cd $TESTDIRS/repo1
+    dolt sql -q "insert into t1 values (3, 3)"
+    dolt commit -am "another remote commit"
+    dolt push origin main
+
+    cd $TESTDIRS/repo2
+    dolt sql -q "insert into t1 values (4, 4)"
+    dolt commit -am "another local commit"
+    run dolt pull --no-rebase origin main
+    [ "$status" -eq 0 ]
+
+    run dolt log --oneline -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Merge branch 'main'" ]] || false
+}
+
+@test "pull: pull --rebase stops on conflicts until they're resolved" {
+    setup_diverged_main conflict
+
+    run dolt pull --rebase origin main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "merge conflict detected while rebasing commit" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "On branch dolt_rebase_main" ]] || false
+
+    run dolt rebase --continue
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "must be resolved first" ]] || false
+
+    dolt conflicts resolve --theirs t1
+    dolt add t1
+    run dolt rebase --continue
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/main" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "On branch main" ]] || false
+
+    run dolt log --oneline -n 2
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "local commit" ]] || false
+    [[ "${lines[1]}" =~ "remote commit" ]] || false
+
+    run dolt sql -q "select b from t1 where a = 0" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+
+    run dolt branch
+    [ "$status" -eq 0 ]
+    ! [[ "$output" =~ "dolt_rebase_main" ]] || false
+}
+
+@test "pull: abort a pull --rebase stopped by conflicts" {
+    setup_diverged_main conflict
+
+    run dolt pull --rebase origin main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "merge conflict detected while rebasing commit" ]] || false
+
+    run dolt rebase --abort
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Interactive rebase aborted" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "On branch main" ]] || false
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt log --oneline -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "local commit" ]] || false
+
+    run dolt sql -q "select b from t1 where a = 0" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+}
@@ -188,9 +188,12 @@ SQL
 
     dolt gc
 
+    # the reflog is archived before garbage collection rewrites the chunk journal
     run dolt reflog
     [ "$status" -eq 0 ]
-    [ "${#lines[@]}" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    out=$(echo "$output" | sed -E 's/\x1b\[[0-9;]*m//g') # remove special characters for color
+    [[ "$out" =~ "(HEAD -> main) Initialize data repository" ]] || false
 }
 
 @test "reflog: garbage collection with newgen data" {
@@ -217,9 +220,23 @@ SQL
 
     dolt gc
 
+    # the reflog is archived before garbage collection rewrites the chunk journal
     run dolt reflog main
     [ "$status" -eq 0 ]
-    [ "${#lines[@]}" -eq 0 ]
+    [ "${#lines[@]}" -eq 4 ]
+    out=$(echo "$output" | sed -E 's/\x1b\[[0-9;]*m//g') # remove special characters for color
+    [[ "$out" =~ "(HEAD -> main) inserting row 2" ]] || false
+    [[ "$out" =~ "inserting row 1" ]] || false
+    [[ "$out" =~ "creating table t1" ]] || false
+    [[ "$out" =~ "Initialize data repository" ]] || false
+
+    # new entries are added after the archived ones
+    dolt commit --allow-empty -m "after gc"
+    run dolt reflog main
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 5 ]
+    out=$(echo "$output" | sed -E 's/\x1b\[[0-9;]*m//g') # remove special characters for color
+    [[ "${lines[0]}" =~ "after gc" ]] || false
 }
 
 @test "reflog: too many arguments given" {
@@ -258,3 +275,110 @@ SQL
     [[ "$line2" =~ "Initialize data repository" ]] || false
     [[ ! "$line2" =~ "HEAD" ]] || false
 }
+
+@test "reflog: restore a deleted branch" {
+    setup_common
+
+    dolt sql -q "create table t (i int primary key);"
+    dolt commit -Am "create table"
+    dolt checkout -b feature
+    dolt sql -q "insert into t values (1);"
+    dolt commit -Am "feature commit 1"
+    dolt sql -q "insert into t values (2);"
+    dolt commit -Am "feature commit 2"
+    dolt checkout main
+    dolt branch -D feature
+
+    run dolt reflog restore feature
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Restored branch 'feature'" ]] || false
+
+    run dolt log feature --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "feature commit 2" ]] || false
+
+    run dolt reflog restore 'feature@{1}'
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "already exists" ]] || false
+
+    run dolt reflog restore -f 'feature@{1}'
+    [ "$status" -eq 0 ]
+    run dolt log feature --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "feature commit 1" ]] || false
+
+    run dolt reflog restore -b other 'feature@{0}'
+    [ "$status" -eq 0 ]
+    run dolt log other --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "feature commit 1" ]] || false
+}
+
+@test "reflog: restore a deleted tag" {
+    setup_common
+
+    dolt commit --allow-empty -m "tagged commit"
+    dolt tag v1
+    dolt commit --allow-empty -m "untagged commit"
+    dolt tag -d v1
+
+    run dolt reflog restore v1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Restored tag 'v1'" ]] || false
+
+    run dolt log v1 --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "tagged commit" ]] || false
+}
+
+@test "reflog: restore a deleted branch after garbage collection" {
+    setup_common
+
+    dolt checkout -b feature
+    dolt commit --allow-empty -m "feature commit"
+    dolt checkout main
+    dolt branch -D feature
+
+    dolt gc --prune-older-than 7
+
+    run dolt reflog restore feature
+    [ "$status" -eq 0 ]
+    run dolt log feature --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "feature commit" ]] || false
+}
+
+@test "reflog: restore fails once garbage collection removes the commit" {
+    setup_common
+
+    dolt checkout -b feature
+    dolt commit --allow-empty -m "feature commit"
+    dolt checkout main
+    dolt branch -D feature
+
+    dolt gc
+
+    run dolt reflog feature
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "feature commit" ]] || false
+
+    run dolt reflog restore feature
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no longer exists" ]] || false
+}
+
+@test "reflog: restore errors" {
+    setup_common
+
+    run dolt reflog restore doesNotExist
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no reflog entries found" ]] || false
+
+    run dolt reflog restore 'main@{5}'
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "only has 1 reflog entries" ]] || false
+
+    run dolt reflog restore 'main@{x}'
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid reflog entry" ]] || false
+}
@@ -75,3 +75,17 @@ teardown() {
         [[ "$output" =~ "only valid for aws remotes" ]] || false
     fi
 }
+
+@test "remote-cmd: list remotes as json" {
+    dolt remote add origin http://customhost/org/db
+
+    run dolt remote --result-format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"name": "origin"' ]] || false
+    [[ "$output" =~ '"url": "http://customhost/org/db"' ]] || false
+    [[ "$output" =~ '"params": {}' ]] || false
+
+    run dolt remote -r json remove origin
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "only supported when listing remotes" ]] || false
+}
@@ -207,6 +207,81 @@ SQL
     [[ "$output" =~ "There is no tracking information for the current branch." ]] || false
 }
 
+@test "remotes: dolt branch --set-upstream-to sets the upstream of a branch" {
+    mkdir remote
+    mkdir repo1
+
+    cd repo1
+    dolt init
+    dolt remote add origin file://../remote
+    dolt push origin main
+    dolt branch other
+    dolt push origin other
+
+    run dolt branch --set-upstream-to origin/other
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "branch 'main' set up to track 'origin/other'." ]] || false
+
+    run dolt branch -u origin/main other
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "branch 'other' set up to track 'origin/main'." ]] || false
+
+    run dolt sql -r csv -q "select name, remote, branch from dolt_branches order by name"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "main,origin,other" ]
+    [ "${lines[2]}" = "other,origin,main" ]
+
+    run dolt branch -u origin/missing
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "the requested upstream branch 'origin/missing' does not exist" ]] || false
+
+    run dolt branch -u origin/main missing
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "branch 'missing' does not exist" ]] || false
+}
+
+@test "remotes: dolt_branches shows how far branches are ahead of and behind their upstreams" {
+    mkdir remote
+    mkdir repo1
+
+    cd repo1
+    dolt init
+    dolt sql -q "create table t (pk int primary key)"
+    dolt commit -Am "create table"
+    dolt remote add origin file://../remote
+    dolt push --set-upstream origin main
+    dolt branch local-only
+
+    cd ..
+    dolt clone file://./remote repo2
+    cd repo2
+    dolt sql -q "insert into t values (1)"
+    dolt commit -am "remote commit"
+    dolt push origin main
+
+    cd ../repo1
+    dolt sql -q "insert into t values (2)"
+    dolt commit -am "local commit 1"
+    dolt sql -q "insert into t values (3)"
+    dolt commit -am "local commit 2"
+
+    # behind isn't known until the remote branch is fetched
+    run dolt sql -r csv -q "select name, ahead, behind from dolt_branches order by name"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "local-only,," ]
+    [ "${lines[2]}" = "main,2,0" ]
+
+    dolt fetch
+    run dolt sql -r csv -q "select name, ahead, behind from dolt_branches order by name"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "local-only,," ]
+    [ "${lines[2]}" = "main,2,1" ]
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "have diverged" ]] || false
+}
+
 @test "remotes: add a remote using dolt remote" {
     run dolt remote add test-remote http://localhost:50051/test-org/test-repo
     [ "$status" -eq 0 ]
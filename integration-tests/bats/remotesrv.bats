@@ -163,3 +163,38 @@ stop_remotesrv() {
     cd ../cloned
     dolt clone http://localhost:1234/test-org/test-repo repo1
 }
+
+@test "remotesrv: fetch sends changed chunks as deltas" {
+    mkdir remote
+    mkdir cloned
+    cd remote
+    dolt init
+    dolt sql -q 'create table vals (i int primary key, s varchar(64));'
+    dolt sql -q "insert into vals with recursive c(n) as (select 1 union all select n+1 from c where n < 5000) select n, concat('value-', n, '-padding-padding') from c;"
+    dolt add vals
+    dolt commit -m 'initial vals.'
+
+    remotesrv --http-port 1234 --repo-mode > ../remotesrv.log 2>&1 &
+    remotesrv_pid=$!
+
+    cd ../cloned
+    dolt clone http://localhost:50051/test-org/test-repo repo1
+
+    stop_remotesrv
+    cd ../remote
+    dolt sql -q "update vals set s = 'changed' where i in (10, 2500, 4990);"
+    dolt commit -am 'change some vals'
+
+    remotesrv --http-port 1234 --repo-mode > ../remotesrv.log 2>&1 &
+    remotesrv_pid=$!
+
+    cd ../cloned/repo1
+    dolt fetch
+    run dolt sql -q "select count(*) from vals as of 'origin/main' where s = 'changed';"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    run grep 'method=GetChunkDeltas' ../../remotesrv.log
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "num_deltas=" ]] || false
+}
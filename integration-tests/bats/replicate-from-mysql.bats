@@ -0,0 +1,96 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    # a stand-in for mysqldump, which records its arguments and writes a dump taken with --set-gtid-purged=ON
+    cat <<'SQL' > dump.sql
+-- MySQL dump 10.13  Distrib 8.0.33, for Linux (x86_64)
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!50503 SET NAMES utf8mb4 */;
+SET @MYSQLDUMP_TEMP_LOG_BIN = @@SESSION.SQL_LOG_BIN;
+SET @@SESSION.SQL_LOG_BIN= 0;
+SET @@GLOBAL.GTID_PURGED=/*!80000 '+'*/ '3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5';
+
+DROP TABLE IF EXISTS `items`;
+CREATE TABLE `items` (
+  `id` int NOT NULL,
+  `name` varchar(20) DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+LOCK TABLES `items` WRITE;
+INSERT INTO `items` VALUES (1,'one'),(2,'two');
+UNLOCK TABLES;
+SET @@SESSION.SQL_LOG_BIN = @MYSQLDUMP_TEMP_LOG_BIN;
+-- Dump completed on 2024-01-01 12:00:00
+SQL
+    cat <<EOF2 > fake-mysqldump
+#!/bin/sh
+echo "\$@" > "$PWD/mysqldump-args"
+echo "\$MYSQL_PWD" > "$PWD/mysqldump-password"
+cat "$PWD/dump.sql"
+EOF2
+    chmod +x fake-mysqldump
+    DB_NAME=$(basename "$PWD")
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "replicate-from-mysql: imports a snapshot and configures replication from it" {
+    MYSQL_PWD=secret run dolt replicate-from-mysql --mysqldump ./fake-mysqldump --host mysql.example.com --port 3307 --user repl --server-id 7
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Imported $DB_NAME as of GTIDs 3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5" ]] || false
+
+    run cat mysqldump-args
+    [[ "$output" =~ "--single-transaction" ]] || false
+    [[ "$output" =~ "--set-gtid-purged=ON" ]] || false
+    [[ "$output" =~ "--host=mysql.example.com --port=3307 --user=repl $DB_NAME" ]] || false
+    run cat mysqldump-password
+    [ "$output" = "secret" ]
+
+    run dolt sql -q "select id, name from items order by id" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,one" ]] || false
+    [[ "$output" =~ "2,two" ]] || false
+
+    run dolt log --oneline
+    [[ "$output" =~ "Import $DB_NAME from mysql.example.com:3307" ]] || false
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    # replication starts from the snapshot's GTIDs when the server next starts
+    [ -f .doltcfg/replica-running ]
+    run dolt sql -q "select @@server_id, @@gtid_purged" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "7,3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5" ]] || false
+}
+
+@test "replicate-from-mysql: errors" {
+    run dolt replicate-from-mysql --user repl
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--host must be given" ]] || false
+
+    run dolt replicate-from-mysql --host localhost
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--user must be given" ]] || false
+
+    run dolt replicate-from-mysql --mysqldump ./fake-mysqldump --host localhost --user repl shop
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "must have the same name as the MySQL database shop" ]] || false
+
+    run dolt replicate-from-mysql --mysqldump ./missing-mysqldump --host localhost --user repl
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "failed to take a snapshot" ]] || false
+
+    grep -v GTID_PURGED dump.sql > no-gtids.sql
+    mv no-gtids.sql dump.sql
+    run dolt replicate-from-mysql --mysqldump ./fake-mysqldump --host localhost --user repl
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "GTIDs must be enabled" ]] || false
+    [ ! -f .doltcfg/replica-running ]
+}
@@ -0,0 +1,90 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+create table parent (id int primary key, name varchar(20));
+create table child (id int primary key, parent_id int, constraint fk_child foreign key (parent_id) references parent(id));
+create table other (id int primary key, v int);
+SQL
+    dolt commit -Am "initial schema"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "schema-diff: shows changes to table schemas" {
+    dolt sql -q "alter table other add column v2 varchar(10)"
+
+    run dolt schema diff
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "other" ]] || false
+    [[ "$output" =~ "+  \`v2\` varchar" ]] || false
+    [[ ! "$output" =~ "parent" ]] || false
+
+    dolt commit -am "add v2"
+    run dolt schema diff HEAD~ HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "+  \`v2\` varchar" ]] || false
+
+    run dolt schema diff HEAD HEAD~
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "-  \`v2\` varchar" ]] || false
+
+    run dolt schema diff
+    [ "$status" -eq 0 ]
+    [ "$output" = "" ]
+
+    run dolt schema diff doesnotexist
+    [ "$status" -eq 1 ]
+}
+
+@test "schema-diff: migration statements are ordered by foreign key dependencies" {
+    dolt sql <<SQL
+drop table child;
+alter table parent add column created date;
+create table b (id int primary key);
+create table a (id int primary key, b_id int, constraint fk_a foreign key (b_id) references b(id) on delete cascade);
+SQL
+
+    run dolt schema diff --migration
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = 'ALTER TABLE `child` DROP FOREIGN KEY `fk_child`;' ]
+    [ "${lines[1]}" = 'DROP TABLE `child`;' ]
+    [[ "${lines[2]}" == 'ALTER TABLE `parent` ADD `created` date'* ]] || false
+    [ "${lines[3]}" = 'CREATE TABLE `a` (' ]
+    # new tables are created without their foreign keys, which are added once every table exists
+    [[ ! "$output" =~ "FOREIGN KEY.*CREATE TABLE" ]] || false
+    [ "${lines[-1]}" = 'ALTER TABLE `a` ADD CONSTRAINT `fk_a` FOREIGN KEY (`b_id`) REFERENCES `b` (`id`) ON DELETE CASCADE;' ]
+}
+
+@test "schema-diff: migration scripts round trip" {
+    dolt branch before
+    dolt sql <<SQL
+alter table child drop foreign key fk_child;
+rename table other to renamed;
+alter table renamed add index idx_v (v);
+create table c1 (id int primary key, c2_id int);
+create table c2 (id int primary key, c1_id int);
+alter table c1 add constraint fk_c1 foreign key (c2_id) references c2(id);
+alter table c2 add constraint fk_c2 foreign key (c1_id) references c1(id);
+alter table child add constraint fk_child2 foreign key (parent_id) references parent(id) on update cascade;
+SQL
+    dolt commit -Am "change schemas"
+
+    dolt schema diff --migration before main > migration.sql
+    dolt checkout before
+    dolt sql < migration.sql
+
+    run dolt schema diff WORKING main
+    [ "$status" -eq 0 ]
+    [ "$output" = "" ]
+
+    run dolt schema diff --migration WORKING main
+    [ "$status" -eq 0 ]
+    [ "$output" = "" ]
+}
@@ -0,0 +1,218 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+load $BATS_TEST_DIRNAME/helper/query-server-common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "create table t (id bigint primary key, v int);"
+    dolt commit -Am "first commit"
+}
+
+teardown() {
+    assert_feature_version
+    stop_sql_server
+    teardown_common
+}
+
+@test "sequences: generate values from a sequence" {
+    dolt sql -q "insert into dolt_sequences (name) values ('seq')"
+
+    run dolt sql -r csv -q "select nextval('seq')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    dolt sql -q "insert into t values (nextval('seq'), 1), (nextval('seq'), 2)"
+    run dolt sql -r csv -q "select * from t order by id"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "2,1" ]
+    [ "${lines[2]}" = "3,2" ]
+
+    run dolt sql -r csv -q "select name, next_value from dolt_sequences"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "seq,4" ]
+
+    run dolt sql -q "select nextval('missing')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Unknown SEQUENCE: 'missing'" ]] || false
+}
+
+@test "sequences: sequence options" {
+    dolt sql -q "insert into dolt_sequences (name, start_value, increment, min_value, max_value, cycle) values ('cycling', 5, 5, 1, 15, 1), ('limited', 1, 1, 1, 2, 0)"
+
+    run dolt sql -r csv -q "select nextval('cycling'), nextval('cycling'), nextval('cycling'), nextval('cycling')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "5,10,15,1" ]
+
+    dolt sql -q "select nextval('limited'), nextval('limited')"
+    run dolt sql -q "select nextval('limited')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "sequence has run out" ]] || false
+
+    run dolt sql -q "insert into dolt_sequences (name, increment) values ('backwards', -1)"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "increment must be positive" ]] || false
+
+    run dolt sql -q "insert into dolt_sequences (name, start_value, min_value) values ('too_low', 1, 10)"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "start_value must be between min_value and max_value" ]] || false
+}
+
+@test "sequences: setval" {
+    dolt sql -q "insert into dolt_sequences (name) values ('seq')"
+
+    run dolt sql -r csv -q "select setval('seq', 100)"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "100" ]
+
+    run dolt sql -r csv -q "select nextval('seq')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "101" ]
+
+    # values the sequence is already past are ignored
+    run dolt sql -r csv -q "select setval('seq', 50) is null, nextval('seq')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "true,102" ]
+}
+
+@test "sequences: sequences used on different branches merge" {
+    dolt sql -q "insert into dolt_sequences (name) values ('seq')"
+    dolt commit -Am "add sequence"
+    dolt branch other
+
+    dolt sql -q "select nextval('seq'), nextval('seq')"
+    dolt commit -am "use sequence on main"
+
+    # values used on other branches are skipped, even by a new server
+    dolt checkout other
+    run dolt sql -r csv -q "select nextval('seq')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+    dolt sql -q "update dolt_sequences set max_value = 1000 where name = 'seq'"
+    dolt commit -am "use and change sequence on other"
+
+    dolt checkout main
+    run dolt merge other -m "merge other"
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+
+    # the merged sequence continues after the values used on both branches
+    run dolt sql -r csv -q "select next_value, max_value from dolt_sequences"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "4,1000" ]
+}
+
+@test "sequences: branches in the same server get distinct values" {
+    dolt sql -q "insert into dolt_sequences (name) values ('seq')"
+    dolt commit -Am "add sequence"
+    dolt branch other
+
+    dolt sql <<SQL
+insert into t values (nextval('seq'), 1);
+call dolt_commit('-am', 'main values');
+call dolt_checkout('other');
+insert into t values (nextval('seq'), 2);
+call dolt_commit('-am', 'other values');
+call dolt_checkout('main');
+call dolt_merge('other');
+SQL
+
+    run dolt sql -r csv -q "select * from t order by id"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,1" ]
+    [ "${lines[2]}" = "2,2" ]
+
+    run dolt sql -r csv -q "select nextval('seq')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+}
+
+@test "sequences: sequence names are case-insensitive" {
+    dolt sql -q "insert into dolt_sequences (name) values ('Seq')"
+
+    run dolt sql -r csv -q "select nextval('seq'), nextval('SEQ')"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,2" ]
+
+    run dolt sql -r csv -q "select name, next_value from dolt_sequences"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "Seq,3" ]
+
+    run dolt sql -q "insert into dolt_sequences (name) values ('SEQ')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "duplicate primary key" ]] || false
+}
+
+@test "sequences: create and drop sequences" {
+    dolt sql -q "create sequence Seq start with 10 increment by 5 maxvalue 20 cycle"
+    run dolt sql -r csv -q "select name, start_value, increment, min_value, max_value, cycle from dolt_sequences"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "Seq,10,5,1,20,1" ]
+
+    # NEXT VALUE FOR is the same as NEXTVAL, and like NEXTVAL it matches names case-insensitively
+    run dolt sql -r csv -q "select next value for seq, nextval('SEQ'), next value for \`SEQ\`"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "10,15,20" ]
+
+    dolt sql -q "insert into t values (next value for seq, 1)"
+    run dolt sql -r csv -q "select * from t"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,1" ]
+
+    run dolt sql -q "create sequence SEQ"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "duplicate primary key" ]] || false
+    dolt sql -q "create sequence if not exists SEQ"
+    run dolt sql -r csv -q "select name, start_value from dolt_sequences"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "Seq,10" ]
+
+    run dolt sql -q "create sequence bad increment by 0"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "increment must be positive" ]] || false
+
+    dolt sql -q "create sequence other minvalue 100"
+    run dolt sql -r csv -q "select next value for other"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "100" ]
+
+    dolt sql -q "drop sequence SEQ, other"
+    dolt sql -q "drop sequence if exists seq"
+    run dolt sql -r csv -q "select count(*) from dolt_sequences"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "sequences: changing a sequence requires write access" {
+    dolt sql -q "insert into dolt_sequences (name) values ('seq')"
+    dolt commit -Am "add sequence"
+    dolt sql -q "create user reader"
+    dolt sql -q "grant select on *.* to reader"
+    dolt sql -q "create user writer"
+    dolt sql -q "grant all on *.* to writer"
+    dolt sql -q "delete from dolt_branch_control where user = '%'"
+    dolt sql -q "insert into dolt_branch_control values ('%', 'other', 'writer', '%', 'write')"
+    dolt branch other
+
+    start_sql_server
+
+    run dolt -u reader sql -q "select nextval('seq')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "command denied" ]] || false
+
+    run dolt -u reader sql -q "select setval('seq', 100)"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "command denied" ]] || false
+
+    run dolt -u writer sql -q "select nextval('seq')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "does not have the correct permissions" ]] || false
+
+    run dolt -u writer sql -r csv -q "call dolt_checkout('other'); select nextval('seq')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+
+    run dolt sql -r csv -q "select next_value from dolt_sequences"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "" ]
+}
@@ -122,3 +122,86 @@ teardown() {
     [[ $output =~ "schon" ]] || false
     [[ $output =~ "schön" ]] || false
 }
+
+@test "sql-charsets-collations: convert a table to a character set" {
+    dolt sql -q "create table t (pk varchar(20) collate utf8mb4_0900_bin primary key, c varchar(20) collate utf8mb4_0900_bin, n int, index (c))"
+    dolt sql -q "insert into t values ('b', 'B', 1), ('A', 'a', 2), ('C', 'c', 3)"
+
+    dolt sql -q "alter table t convert to character set utf8mb4 collate utf8mb4_0900_ai_ci"
+    run dolt sql -q "show create table t"
+    [ $status -eq 0 ]
+    [[ ! $output =~ "utf8mb4_0900_bin" ]] || false
+
+    # the primary key and secondary index are ordered by the new collation
+    run dolt sql -r csv -q "select pk from t order by pk"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "A" ]
+    [ "${lines[2]}" = "b" ]
+    [ "${lines[3]}" = "C" ]
+
+    run dolt sql -r csv -q "select n from t where c = 'A'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+
+    run dolt sql -r csv -q "select n from t where c > 'A' order by c"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "3" ]
+}
+
+@test "sql-charsets-collations: converting a table to a character set errors on duplicate keys" {
+    dolt sql -q "create table t (pk varchar(20) collate utf8mb4_0900_bin primary key)"
+    dolt sql -q "insert into t values ('a'), ('A')"
+    run dolt sql -q "alter table t convert to character set utf8mb4 collate utf8mb4_0900_ai_ci"
+    [ $status -eq 1 ]
+    [[ $output =~ "duplicate" ]] || false
+
+    dolt sql -q "create table u (pk int primary key, c varchar(20) collate utf8mb4_0900_bin, unique key (c))"
+    dolt sql -q "insert into u values (1, 'ä'), (2, 'a')"
+    run dolt sql -q "alter table u convert to character set utf8mb4 collate utf8mb4_0900_ai_ci"
+    [ $status -eq 1 ]
+    [[ $output =~ "duplicate" ]] || false
+
+    # the tables are unchanged
+    run dolt sql -r csv -q "select count(*) from t where pk = 'a'"
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "sql-charsets-collations: convert a table to utf8mb4_unicode_520_ci and accent insensitive collations" {
+    dolt sql -q "create table t (pk varchar(20) collate utf8mb4_0900_bin primary key, n int)"
+    dolt sql -q "insert into t values ('b', 1), ('A', 2), ('C', 3)"
+
+    dolt sql -q "alter table t convert to character set utf8mb4 collate utf8mb4_unicode_520_ci"
+    run dolt sql -q "show create table t"
+    [ $status -eq 0 ]
+    [[ $output =~ "utf8mb4_unicode_520_ci" ]] || false
+    run dolt sql -r csv -q "select pk from t order by pk"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "A" ]
+    [ "${lines[2]}" = "b" ]
+    [ "${lines[3]}" = "C" ]
+    run dolt sql -r csv -q "select n from t where pk = 'á'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+
+    dolt sql -q "alter table t convert to character set utf8mb4 collate utf8mb4_es_0900_ai_ci"
+    run dolt sql -q "show create table t"
+    [ $status -eq 0 ]
+    [[ $output =~ "utf8mb4_es_0900_ai_ci" ]] || false
+    run dolt sql -q "insert into t values ('á', 4)"
+    [ $status -eq 1 ]
+    [[ $output =~ "duplicate" ]] || false
+}
+
+@test "sql-charsets-collations: converting a table to an unimplemented collation errors" {
+    dolt sql -q "create table t (pk int primary key)"
+    run dolt sql -q "alter table t convert to character set utf8mb4 collate utf8mb4_nb_0900_ai_ci"
+    [ $status -eq 1 ]
+    [[ $output =~ "utf8mb4_nb_0900_ai_ci" ]] || false
+    [[ $output =~ "has not yet been implemented" ]] || false
+
+    run dolt sql -q "show create table t"
+    [ $status -eq 0 ]
+    [[ ! $output =~ "utf8mb4_nb_0900_ai_ci" ]] || false
+}
@@ -278,3 +278,50 @@ SQL
   dolt sql -q "CALL DOLT_COMMIT('--skip-empty', '-m', 'commit message');"
   [ $new_head = $(get_head_commit) ]
 }
+
+@test "sql-commit: DOLT_COMMIT with --metadata" {
+    run dolt sql -q "call dolt_commit('-m', 'with metadata', '--metadata', 'ticket=ABC-1', 'pipeline.run=42')"
+    [ $status -eq 0 ]
+
+    run dolt sql -r csv -q "select json_unquote(json_extract(metadata, '$.ticket')), json_unquote(json_extract(metadata, '$.\"pipeline.run\"')) from dolt_commits where message = 'with metadata'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "ABC-1,42" ]
+
+    run dolt sql -r csv -q "select message from dolt_commits where json_unquote(json_extract(metadata, '$.ticket')) = 'ABC-1'"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "with metadata" ]
+
+    run dolt sql -r csv -q "select count(*) from dolt_commits where metadata is null"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    # amending keeps the metadata unless new metadata is given
+    dolt sql -q "call dolt_commit('--amend', '-m', 'amended')"
+    run dolt sql -r csv -q "select json_unquote(json_extract(metadata, '$.ticket')) from dolt_commits where message = 'amended'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "ABC-1" ]
+
+    dolt sql -q "call dolt_commit('--amend', '--metadata', 'ticket=ABC-2')"
+    run dolt sql -r csv -q "select json_length(metadata), json_unquote(json_extract(metadata, '$.ticket')) from dolt_commits where message = 'amended'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "1,ABC-2" ]
+}
+
+@test "sql-commit: dolt commit with --metadata" {
+    dolt commit -m "with metadata" --metadata ticket=ABC-1 tickets=ABC-2,ABC-3
+
+    run dolt sql -r csv -q "select json_unquote(json_extract(metadata, '$.ticket')), json_unquote(json_extract(metadata, '$.tickets')) from dolt_commits where message = 'with metadata'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = 'ABC-1,"ABC-2,ABC-3"' ]
+}
+
+@test "sql-commit: DOLT_COMMIT with invalid --metadata" {
+    run dolt sql -q "call dolt_commit('-m', 'with metadata', '--metadata', 'ticket')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "expected key=value" ]] || false
+
+    run dolt sql -q "call dolt_commit('-m', 'with metadata', '--metadata', 'ticket id=ABC-1')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "invalid commit metadata key" ]] || false
+}
@@ -768,6 +768,26 @@ SQL
     [[ "$output" =~ "table not found: mytemptable" ]] || false
 }
 
+@test "sql-create-tables: temporary table storage is deleted when the table is dropped" {
+    run dolt sql -r csv <<SQL
+CREATE TEMPORARY TABLE tmp (pk int PRIMARY KEY, val varchar(200), INDEX (val));
+INSERT INTO tmp WITH RECURSIVE n (i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 1000) SELECT i, concat(repeat('x', 100), i) FROM n;
+UPDATE tmp SET val = 'updated' WHERE pk <= 10;
+SELECT count(*) FROM tmp WHERE val = 'updated';
+DROP TEMPORARY TABLE tmp;
+SQL
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "10" ]] || false
+
+    # temporary tables are kept in storage of their own, which is deleted with the table
+    run ls .dolt/temptf
+    [[ ! "$output" =~ "temp_table_" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+}
+
 @test "sql-create-tables: BINARY attributes" {
     dolt sql <<SQL
 CREATE TABLE budgets(id CHAR(36) CHARACTER SET utf8mb4 BINARY);
@@ -501,3 +501,62 @@ SQL
 }
 
 
+
+@test "sql-pull: dolt_pull --rebase" {
+    cd repo2
+    dolt sql -q "call dolt_pull('origin')"
+
+    cd ../repo1
+    dolt sql -q "insert into t1 values (1, 1)"
+    dolt commit -am "remote commit"
+    dolt push origin main
+
+    cd ../repo2
+    dolt sql -q "insert into t1 values (2, 2)"
+    dolt commit -am "local commit"
+
+    run dolt sql -r csv -q "call dolt_pull('--rebase', 'origin', 'main')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "0,0,Successfully rebased and updated refs/heads/main" ]] || false
+
+    run dolt sql -r csv -q "select message from dolt_log limit 2"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "local commit" ]
+    [ "${lines[2]}" = "remote commit" ]
+}
+
+@test "sql-pull: dolt_pull --rebase pauses on conflicts" {
+    cd repo2
+    dolt sql -q "call dolt_pull('origin')"
+
+    cd ../repo1
+    dolt sql -q "update t1 set b = 1 where a = 0"
+    dolt commit -am "remote commit"
+    dolt push origin main
+
+    cd ../repo2
+    dolt sql -q "update t1 set b = 2 where a = 0"
+    dolt commit -am "local commit"
+
+    run dolt sql -r csv <<SQL
+set @@dolt_allow_commit_conflicts = 1;
+call dolt_pull('--rebase', 'origin', 'main');
+select active_branch();
+call dolt_conflicts_resolve('--ours', 't1');
+call dolt_add('t1');
+call dolt_rebase('--continue');
+SQL
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "merge conflict detected while rebasing commit" ]] || false
+    [[ "$output" =~ "dolt_rebase_main" ]] || false
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/main" ]] || false
+
+    # resolving the conflicts with the remote changes leaves the local commit empty, so it's dropped
+    run dolt sql -r csv -q "select b from t1 where a = 0"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    run dolt sql -r csv -q "select message from dolt_log limit 1"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "remote commit" ]
+}
@@ -398,6 +398,45 @@ call dolt_commit('-am', 'add one val');"
     [[ "$output" =~ "dave" ]] || false
 }
 
+@test "sql-server-remotesrv: clone and push with credentials from credential.helper" {
+    mkdir remote
+    cd remote
+    dolt init
+    dolt sql -q 'create table names (name varchar(10) primary key);'
+    dolt commit -Am 'create names.'
+
+    APIPORT=$( definePORT )
+    start_sql_server_with_args -u root -p rootpass --remotesapi-port $APIPORT
+    unset DOLT_REMOTE_PASSWORD
+
+    cd ../
+    cat > dolt-credential-test <<EOF
+#!/bin/sh
+cat > "$(pwd)/request"
+echo username=root
+echo password=rootpass
+EOF
+    chmod +x dolt-credential-test
+    dolt config --global --add credential.helper "$(pwd)/dolt-credential-test"
+
+    dolt clone http://localhost:$APIPORT/remote cloned_db
+    run cat request
+    [[ "$output" =~ "protocol=http" ]] || false
+    [[ "$output" =~ "host=localhost" ]] || false
+
+    cd cloned_db
+    dolt sql -q 'insert into names values ("abe");'
+    dolt commit -am 'add abe'
+    dolt push origin --user root main:main
+    run cat ../request
+    [[ "$output" =~ "username=root" ]] || false
+
+    dolt config --global --add credential.helper "!echo password=wrong; true"
+    run dolt push origin --user root main:other
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "Access denied for user 'root'" ]] || false
+}
+
 @test "sql-server-remotesrv: push to dirty workspace as super user" {
     mkdir remote
     cd remote
@@ -77,6 +77,44 @@ EOF
   [[ $output =~ "| barbie    | barbie@plastic.com | committing as barbie |" ]] || false
 }
 
+@test "sql-server: prepared XA transactions survive restarts and lock their branches" {
+  cd repo1
+  start_sql_server
+  dolt sql -q "create table xa_t (pk int primary key);"
+  dolt sql -q "create user user1@'%';"
+  dolt sql -q "grant select, insert on *.* to user1@'%';"
+
+  dolt -u user1 sql -q "xa start 'xid1'; insert into xa_t values (1); xa end 'xid1'; xa prepare 'xid1';"
+
+  # listing prepared transactions needs XA_RECOVER_ADMIN
+  run dolt -u user1 sql -q "xa recover"
+  [ $status -ne 0 ]
+  [[ $output =~ "command denied to user" ]] || false
+
+  stop_sql_server 1
+  start_sql_server
+
+  run dolt sql -r csv -q "xa recover"
+  [ $status -eq 0 ]
+  [[ $output =~ "1,4,0,xid1" ]] || false
+
+  # nothing else can commit to the branch until the prepared transaction is committed or rolled back
+  run dolt sql -q "insert into xa_t values (2);"
+  [ $status -ne 0 ]
+  [[ $output =~ "locked by a prepared XA transaction" ]] || false
+
+  dolt sql -q "xa commit 'xid1'"
+  dolt sql -q "insert into xa_t values (2);"
+  run dolt sql -r csv -q "select pk from xa_t order by pk"
+  [ $status -eq 0 ]
+  [ "${lines[1]}" = "1" ]
+  [ "${lines[2]}" = "2" ]
+
+  run dolt sql -r csv -q "xa recover"
+  [ $status -eq 0 ]
+  [ "${#lines[@]}" -eq 1 ]
+}
+
 @test "sql-server: can create savepoint when no database is selected" {
     skiponwindows "Missing dependencies"
 
@@ -1809,6 +1847,51 @@ behavior:
     [[ "$output" =~ "42" ]] || false
 }
 
+@test "sql-server: create database from remote and async dolt_clone" {
+    mkdir rem1
+    cd repo1
+    dolt sql -q "CREATE TABLE test (pk INT PRIMARY KEY);"
+    dolt sql -q "INSERT INTO test VALUES (1), (2), (3);"
+    dolt add -A
+    dolt commit -m "initial commit"
+    dolt remote add remote1 file://../rem1
+    dolt push remote1 main
+
+    cd ..
+    start_sql_server
+
+    dolt sql -q "CREATE DATABASE repo3 FROM REMOTE 'file://./rem1';"
+    run dolt --use-db repo3 sql -q "SELECT COUNT(*) FROM test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    dolt sql -q "call dolt_clone('--async', 'file://./rem1', 'repo4');"
+    for i in $(seq 1 50); do
+        run dolt sql -q "SELECT status FROM dolt_clone_status WHERE database_name = 'repo4'" -r csv
+        [[ "$output" =~ "running" ]] || break
+        sleep 0.1
+    done
+    [[ "$output" =~ "completed" ]] || false
+
+    run dolt sql -q "SELECT database_name, status, chunks_total = chunks_downloaded FROM dolt_clone_status ORDER BY 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "repo3,completed,true" ]] || false
+    [[ "$output" =~ "repo4,completed,true" ]] || false
+
+    run dolt --use-db repo4 sql -q "SELECT COUNT(*) FROM test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    dolt sql -q "call dolt_clone('--async', 'file://./missing', 'repo5');"
+    for i in $(seq 1 50); do
+        run dolt sql -q "SELECT status FROM dolt_clone_status WHERE database_name = 'repo5'" -r csv
+        [[ "$output" =~ "running" ]] || break
+        sleep 0.1
+    done
+    [[ "$output" =~ "failed" ]] || false
+    [ ! -d repo5 ]
+}
+
 @test "sql-server: locks made in session should be released on session end" {
     start_sql_server
     EXPECTED=$(echo -e "\"GET_LOCK('mylock', 1000)\"\n1\nIS_FREE_LOCK('mylock')\n0")
@@ -1961,3 +2044,441 @@ behavior:
     [[ "$output" =~ "Detected that a Dolt sql-server is running from this directory." ]] || false
     [[ "$output" =~ "Stop the sql-server before initializing this directory as a Dolt database." ]] || false
 }
+
+@test "sql-server: performance_schema reports threads and statement digests" {
+    cd repo1
+    start_sql_server
+
+    run dolt sql -r csv -q "select @@performance_schema"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+
+    dolt sql -q "create table t (pk int primary key)"
+    dolt sql -q "select * from t where pk = 1"
+    dolt sql -q "select * from t where pk = 2"
+
+    run dolt sql -r csv -q "select digest_text, count_star from performance_schema.events_statements_summary_by_digest where digest_text like 'SELECT * FROM %'"
+    [ $status -eq 0 ]
+    [[ "$output" =~ 'SELECT * FROM `t` WHERE `pk` = ?,2' ]] || false
+
+    run dolt sql -r csv -q "select processlist_command, processlist_info from performance_schema.threads where processlist_id = connection_id()"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Query,select processlist_command" ]] || false
+
+    run dolt sql -q "insert into performance_schema.threads (thread_id) values (1)"
+    [ $status -ne 0 ]
+}
+
+@test "sql-server: performance_schema reports query stages and rows examined" {
+    cd repo1
+    start_sql_server
+
+    dolt sql -q "create table t (pk int primary key); insert into t values (1), (2), (3)"
+
+    run dolt sql -r csv -q "select command, state, info from performance_schema.processlist where id = connection_id()"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Query,executing,select command" ]] || false
+
+    run dolt sql -r csv -q "select event_name from performance_schema.events_stages_current where thread_id = connection_id()"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "stage/sql/executing" ]] || false
+
+    dolt sql -q "select * from t where pk > 0"
+    run dolt sql -r csv -q "select sum_rows_examined from performance_schema.events_statements_summary_by_digest where digest_text = 'SELECT * FROM \`t\` WHERE \`pk\` > ?'"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+}
+
+@test "sql-server: reload config on SIGHUP and with dolt_reload_config()" {
+    skiponwindows "Missing dependencies"
+
+    cd repo1
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+log_level: info
+
+listener:
+  host: 0.0.0.0
+  port: $PORT
+
+system_variables:
+  dolt_transaction_commit: 0
+YAML
+    dolt sql-server --config server.yaml &
+    SERVER_PID=$!
+    wait_for_connection $PORT 8500
+
+    run dolt sql -r csv -q "select @@dolt_log_level, @@dolt_transaction_commit"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "info,0" ]] || false
+
+    sed -i.bak -e 's/log_level: info/log_level: debug/' -e 's/dolt_transaction_commit: 0/dolt_transaction_commit: 1/' server.yaml
+    kill -HUP $SERVER_PID
+    sleep 1
+
+    run dolt sql -r csv -q "select @@dolt_log_level, @@dolt_transaction_commit"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "debug,1" ]] || false
+
+    sed -i.bak -e 's/log_level: debug/log_level: warning/' server.yaml
+    dolt sql -q "call dolt_reload_config()"
+
+    run dolt sql -r csv -q "select @@dolt_log_level"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "warning" ]] || false
+
+    # an invalid config is rejected, and the running config is kept
+    sed -i.bak -e 's/log_level: warning/log_level: loud/' server.yaml
+    run dolt sql -q "call dolt_reload_config()"
+    [ $status -ne 0 ]
+
+    run dolt sql -r csv -q "select @@dolt_log_level"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "warning" ]] || false
+}
+
+@test "sql-server: serve the X Protocol on listener.x_port" {
+    skiponwindows "Missing dependencies"
+    python3 -c 'import mysqlx' || skip "mysqlx python module not installed"
+
+    cd repo1
+    dolt sql -q "create table test (pk int primary key, c1 varchar(20))"
+    PORT=$( definePORT )
+    XPORT=$( definePORT )
+    cat > server.yaml <<YAML
+user:
+  name: dolt
+
+listener:
+  host: 0.0.0.0
+  port: $PORT
+  x_port: $XPORT
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 8500
+
+    run python3 -c '
+import mysqlx
+session = mysqlx.get_session({
+    "host": "127.0.0.1",
+    "port": '"$XPORT"',
+    "user": "dolt",
+    "password": "",
+    "schema": "repo1",
+    "ssl-mode": mysqlx.SSLMode.DISABLED,
+    "auth": mysqlx.Auth.MYSQL41,
+})
+table = session.get_schema("repo1").get_table("test")
+table.insert("pk", "c1").values(1, "one").values(2, "two").values(3, "three").execute()
+table.update().set("c1", "TWO").where("pk = :pk").bind("pk", 2).execute()
+table.delete().where("pk > 2").execute()
+for row in table.select("pk", "c1").order_by("pk").execute().fetch_all():
+    print("row: %d,%s" % (row[0], row[1]))
+print("count: %d" % session.sql("select count(*) from test where pk > ?").bind(0).execute().fetch_one()[0])
+session.close()
+'
+    [ $status -eq 0 ]
+    [[ "$output" =~ "row: 1,one" ]] || false
+    [[ "$output" =~ "row: 2,TWO" ]] || false
+    [[ ! "$output" =~ "row: 3" ]] || false
+    [[ "$output" =~ "count: 1" ]] || false
+
+    run dolt sql -r csv -q "select * from test order by pk"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2,TWO" ]] || false
+}
+
+@test "sql-server: serve the PostgreSQL protocol on listener.postgres_port" {
+    skiponwindows "Missing dependencies"
+    python3 -c 'import psycopg2' || skip "psycopg2 python module not installed"
+
+    cd repo1
+    dolt sql -q "create table test (pk int primary key, c1 varchar(20))"
+    PORT=$( definePORT )
+    PGPORT=$( definePORT )
+    cat > server.yaml <<YAML
+user:
+  name: dolt
+  password: password
+
+listener:
+  host: 0.0.0.0
+  port: $PORT
+  postgres_port: $PGPORT
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 8500
+
+    # passwords aren't sent in cleartext over connections without SSL unless allow_cleartext_passwords is set
+    run python3 -c '
+import psycopg2
+try:
+    psycopg2.connect(host="127.0.0.1", port='"$PGPORT"', user="dolt", password="password", dbname="repo1", sslmode="disable")
+except psycopg2.OperationalError as e:
+    print("error: %s" % e)
+'
+    [ $status -eq 0 ]
+    [[ "$output" =~ "connection requires SSL" ]] || false
+
+    stop_sql_server 1
+    cat >> server.yaml <<YAML
+  allow_cleartext_passwords: true
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 8500
+
+    run python3 -c '
+import psycopg2
+conn = psycopg2.connect(host="127.0.0.1", port='"$PGPORT"', user="dolt", password="password", dbname="repo1", sslmode="disable")
+cur = conn.cursor()
+cur.execute("select version()")
+print("version: %s" % cur.fetchone()[0])
+cur.execute("insert into \"test\" (pk, c1) values (%s, %s), (%s, %s)", (1, "one", 2, "it'"'"'s"))
+conn.commit()
+cur.execute("select \"pk\", \"c1\" from \"test\" where pk > %s order by pk", (0,))
+for row in cur.fetchall():
+    print("row: %d,%s" % row)
+try:
+    cur.execute("select 1::int")
+except psycopg2.Error as e:
+    print("error: %s" % e.pgcode)
+conn.close()
+'
+    [ $status -eq 0 ]
+    [[ "$output" =~ "version: PostgreSQL" ]] || false
+    [[ "$output" =~ "row: 1,one" ]] || false
+    [[ "$output" =~ "row: 2,it's" ]] || false
+    [[ "$output" =~ "error: 0A000" ]] || false
+
+    run dolt sql -r csv -q "select * from test order by pk"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2,it's" ]] || false
+
+    # the transaction and locks of a client which disconnects are released
+    run python3 -c '
+import psycopg2
+conn = psycopg2.connect(host="127.0.0.1", port='"$PGPORT"', user="dolt", password="password", dbname="repo1", sslmode="disable")
+cur = conn.cursor()
+cur.execute("select get_lock(%s, 0)", ("l",))
+cur.execute("select * from \"test\" where pk = 1 for update")
+cur.execute("insert into \"test\" (pk, c1) values (3, %s)", ("three",))
+conn.close()
+conn = psycopg2.connect(host="127.0.0.1", port='"$PGPORT"', user="dolt", password="password", dbname="repo1", sslmode="disable")
+cur = conn.cursor()
+cur.execute("select get_lock(%s, 10)", ("l",))
+print("lock: %d" % cur.fetchone()[0])
+cur.execute("update \"test\" set c1 = %s where pk = 1", ("uno",))
+conn.commit()
+cur.execute("select count(*) from \"test\" where pk = 3")
+print("count: %d" % cur.fetchone()[0])
+conn.close()
+'
+    [ $status -eq 0 ]
+    [[ "$output" =~ "lock: 1" ]] || false
+    [[ "$output" =~ "count: 0" ]] || false
+}
+
+@test "sql-server: run read only queries with the HTTP API" {
+    skiponwindows "Missing dependencies"
+
+    cd repo1
+    dolt sql -q "create table test (pk int primary key, c1 varchar(20))"
+    dolt sql -q "insert into test values (1, 'one')"
+    dolt commit -Am "add test"
+    dolt branch other
+    dolt sql -q "insert into test values (2, 'two')"
+    dolt sql -q "insert into dolt_sequences (name) values ('s')"
+    PORT=$( definePORT )
+    HTTPPORT=$( definePORT )
+    cat > server.yaml <<YAML
+user:
+  name: dolt
+  password: password
+
+listener:
+  host: 0.0.0.0
+  port: $PORT
+
+http_api:
+  port: $HTTPPORT
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 8500
+
+    run python3 -c '
+import base64, json, urllib.error, urllib.parse, urllib.request
+def get(params, password="password"):
+    req = urllib.request.Request("http://127.0.0.1:'"$HTTPPORT"'/api/sql?" + urllib.parse.urlencode(params))
+    req.add_header("Authorization", "Basic " + base64.b64encode(("dolt:" + password).encode()).decode())
+    try:
+        with urllib.request.urlopen(req) as res:
+            return res.status, res.read().decode()
+    except urllib.error.HTTPError as e:
+        return e.code, e.read().decode()
+status, body = get({"query": "select * from test order by pk", "database": "repo1"})
+print("json: %d %s" % (status, [r["c1"] for r in json.loads(body)["rows"]]))
+status, body = get({"query": "select * from test order by pk", "database": "repo1", "ref": "other", "format": "csv"})
+print("csv: %d %s" % (status, body.replace("\n", "|")))
+status, body = get({"query": "select * from test where pk > 5", "database": "repo1"})
+print("empty: %d %s" % (status, body))
+status, body = get({"query": "insert into test values (3, \"three\")", "database": "repo1"})
+print("write: %d %s" % (status, json.loads(body)["error"]))
+for name, query in [
+    ("nextval", "select nextval(\"s\")"),
+    ("setval", "select setval(\"s\", 100)"),
+    ("for update", "select * from test where pk = 1 for update"),
+    ("outfile", "select * from test into outfile \"http_api_out.csv\""),
+    ("call", "call dolt_commit(\"--allow-empty\", \"-m\", \"empty\")"),
+]:
+    status, body = get({"query": query, "database": "repo1"})
+    print("%s: %d %s" % (name, status, json.loads(body).get("error")))
+status, body = get({"query": "select 1"}, password="wrong")
+print("auth: %d" % status)
+'
+    [ $status -eq 0 ]
+    [[ "$output" =~ "json: 200 ['one', 'two']" ]] || false
+    [[ "$output" =~ "csv: 200 pk,c1|1,one|" ]] || false
+    [[ "$output" =~ 'empty: 200 {"rows": []}' ]] || false
+    [[ "$output" =~ "write: 400 cannot execute statement in a READ ONLY transaction" ]] || false
+    [[ "$output" =~ "nextval: 400 cannot execute statement in a READ ONLY transaction" ]] || false
+    [[ "$output" =~ "setval: 400 cannot execute statement in a READ ONLY transaction" ]] || false
+    [[ "$output" =~ "for update: 400 cannot execute statement in a READ ONLY transaction" ]] || false
+    [[ "$output" =~ "outfile: 400 cannot execute statement in a READ ONLY transaction" ]] || false
+    [[ "$output" =~ "call: 400" ]] || false
+    [[ "$output" =~ "auth: 401" ]] || false
+
+    [ ! -f http_api_out.csv ]
+
+    run dolt sql -r csv -q "select count(*) from test"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    run dolt sql -r csv -q "select next_value from dolt_sequences where name = 's'"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+    [[ ! "$output" =~ "100" ]] || false
+
+    run dolt log --oneline
+    [[ ! "$output" =~ "empty" ]] || false
+}
+
+@test "sql-server: fetch results with Arrow Flight SQL on listener.flight_sql_port" {
+    skiponwindows "Missing dependencies"
+    python3 -c 'import adbc_driver_flightsql.dbapi' || skip "adbc_driver_flightsql python module not installed"
+
+    cd repo1
+    dolt sql -q "create table test (pk int primary key, c1 varchar(20), c2 decimal(10,2))"
+    dolt sql -q "insert into test values (1, 'one', 1.50), (2, null, -0.01)"
+    PORT=$( definePORT )
+    FLIGHTPORT=$( definePORT )
+    cat > server.yaml <<YAML
+user:
+  name: dolt
+  password: password
+
+listener:
+  host: 0.0.0.0
+  port: $PORT
+  flight_sql_port: $FLIGHTPORT
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 8500
+
+    run python3 -c '
+import adbc_driver_flightsql.dbapi as flightsql
+conn = flightsql.connect("grpc://127.0.0.1:'"$FLIGHTPORT"'", db_kwargs={
+    "username": "dolt",
+    "password": "password",
+    "adbc.flight.sql.rpc.call_header.database": "repo1",
+}, autocommit=True)
+cur = conn.cursor()
+cur.execute("select * from test order by pk")
+table = cur.fetch_arrow_table()
+print("schema: %s" % ",".join(str(f.type) for f in table.schema))
+print("rows: %s" % table.to_pylist())
+try:
+    cur.execute("insert into test values (3, \"three\", 3)")
+except flightsql.Error as e:
+    print("write error")
+conn.close()
+'
+    [ $status -eq 0 ]
+    [[ "$output" =~ "schema: int32,string,decimal128(10, 2)" ]] || false
+    [[ "$output" =~ "rows: [{'pk': 1, 'c1': 'one', 'c2': Decimal('1.50')}, {'pk': 2, 'c1': None, 'c2': Decimal('-0.01')}]" ]] || false
+    [[ "$output" =~ "write error" ]] || false
+
+    run dolt sql -r csv -q "select count(*) from test"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+}
+
+@test "sql-server: deliver committed changes to a change_data_capture webhook" {
+    skiponwindows "Missing dependencies"
+
+    cd repo1
+    dolt sql -q "create table test (pk int primary key, c1 varchar(20))"
+    dolt commit -Am "add test"
+    dolt sql -q "call dolt_change_cursor('create', 'hook')"
+    dolt sql -q "insert into test values (1, 'one'), (2, 'two')"
+    dolt commit -am "add rows"
+
+    HOOKPORT=$( definePORT )
+    python3 -c '
+import http.server
+class Handler(http.server.BaseHTTPRequestHandler):
+    def do_POST(self):
+        body = self.rfile.read(int(self.headers["Content-Length"]))
+        with open("hook.log", "a") as f:
+            f.write(body.decode() + "\n")
+        self.send_response(200)
+        self.end_headers()
+    def log_message(self, *args):
+        pass
+http.server.HTTPServer(("127.0.0.1", '"$HOOKPORT"'), Handler).serve_forever()
+' &
+    HOOK_PID=$!
+
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+listener:
+  host: 0.0.0.0
+  port: $PORT
+
+change_data_capture:
+  webhooks:
+  - database: repo1
+    cursor: hook
+    url: http://127.0.0.1:$HOOKPORT/changes
+    poll_interval_millis: 100
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 8500
+
+    dolt --use-db repo1 sql -q "update test set c1 = 'uno' where pk = 1; call dolt_commit('-am', 'update row')"
+
+    for i in $(seq 1 50); do
+        if [ "$(dolt --use-db repo1 sql -r csv -q "select count(*) from dolt_changes('hook')" | tail -n 1)" = "0" ]; then
+            break
+        fi
+        sleep 0.1
+    done
+    kill $HOOK_PID
+
+    run python3 -c '
+import json
+changes = [c for line in open("hook.log") for c in json.loads(line)["changes"]]
+for c in changes:
+    print("%s %s %s" % (c["diff_type"], c["primary_key"]["pk"], c["to_row"]["c1"]))
+'
+    [ $status -eq 0 ]
+    [[ "$output" =~ "added 1 one" ]] || false
+    [[ "$output" =~ "added 2 two" ]] || false
+    [[ "$output" =~ "modified 1 uno" ]] || false
+    [ "${#lines[@]}" -eq 3 ]
+}
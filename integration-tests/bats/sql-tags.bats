@@ -86,3 +86,68 @@ teardown() {
     [[ "$output" =~ "8" ]] || false
     [[ "$output" =~ "9" ]] || false
 }
+
+@test "sql-tags: DOLT_TAG works with date arg defined" {
+    dolt sql -q "CALL DOLT_TAG('v1', '-m', 'first release', '--date', '2022-01-02T03:04:05')"
+    run dolt sql -r csv -q "select tag_name, date, message from dolt_tags"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "v1,2022-01-02 03:04:05,first release" ]
+
+    run dolt sql -q "CALL DOLT_TAG('v2', '--date', 'not a date')"
+    [ $status -ne 0 ]
+}
+
+@test "sql-tags: create a tag by inserting into dolt_tags" {
+    head=$(dolt sql -r csv -q "select hashof('HEAD')" | tail -n 1)
+    parent=$(dolt sql -r csv -q "select hashof('HEAD^')" | tail -n 1)
+
+    dolt sql -q "insert into dolt_tags (tag_name, tag_hash) values ('v1', 'HEAD^')"
+    dolt sql -q "insert into dolt_tags values ('v2', '$head', 'Jane Doe', 'jane@doe.com', '2022-01-02 03:04:05', 'second release')"
+
+    run dolt sql -r csv -q "select tag_name, tag_hash, message from dolt_tags order by tag_name"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "v1,$parent," ]
+    [ "${lines[2]}" = "v2,$head,second release" ]
+
+    run dolt tag -v
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Tagger: Bats Tests <bats@email.fake>" ]] || false
+    [[ "$output" =~ "Tagger: Jane Doe <jane@doe.com>" ]] || false
+
+    run dolt sql -q "insert into dolt_tags (tag_name, tag_hash) values ('v1', 'HEAD')"
+    [ $status -ne 0 ]
+    run dolt sql -q "insert into dolt_tags (tag_name, tag_hash) values ('v3', 'nosuchbranch')"
+    [ $status -ne 0 ]
+    run dolt sql -q "insert into dolt_tags (tag_name) values ('v3')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "tag_hash" ]] || false
+}
+
+@test "sql-tags: update and delete tags in dolt_tags" {
+    head=$(dolt sql -r csv -q "select hashof('HEAD')" | tail -n 1)
+    dolt sql -q "CALL DOLT_TAG('-m', 'first release', 'v1', 'HEAD^')"
+    dolt sql -q "CALL DOLT_TAG('v2')"
+
+    dolt sql -q "update dolt_tags set tag_hash = '$head', message = 'moved' where tag_name = 'v1'"
+    run dolt sql -r csv -q "select tag_name, tag_hash, message from dolt_tags where tag_name = 'v1'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "v1,$head,moved" ]
+
+    dolt sql -q "update dolt_tags set tag_name = 'v1.0' where tag_name = 'v1'"
+    run dolt sql -r csv -q "select tag_name, tag_hash, message from dolt_tags order by tag_name"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ "${lines[1]}" = "v1.0,$head,moved" ]
+
+    # a tag can't be renamed to the name of another tag
+    run dolt sql -q "update dolt_tags set tag_name = 'v2' where tag_name = 'v1.0'"
+    [ $status -ne 0 ]
+    run dolt sql -r csv -q "select count(*) from dolt_tags"
+    [ "${lines[1]}" = "2" ]
+
+    dolt sql -q "delete from dolt_tags where tag_name = 'v2'"
+    run dolt sql -r csv -q "select tag_name from dolt_tags"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "v1.0" ]
+}
@@ -1459,7 +1459,7 @@ SQL
 
     run dolt sql -r csv -q "set dolt_show_branch_databases = 1; show databases"
     [ "$status" -eq 0 ]
-    [ "${#lines[@]}" -eq 11 ] # 2 base dbs, 3 branch dbs each, 2 mysql dbs, 1 header line
+    [ "${#lines[@]}" -eq 12 ] # 2 base dbs, 3 branch dbs each, 3 mysql dbs, 1 header line
     [[ "$output" =~ "db1/b1" ]] || false
     [[ "$output" =~ "db1/b2" ]] || false
     [[ "$output" =~ "db1/main" ]] || false
@@ -1476,13 +1476,13 @@ SQL
     dolt sql -q "set @@persist.dolt_show_branch_databases = 1"
     run dolt sql -r csv -q "show databases"
     [ "$status" -eq 0 ]
-    [ "${#lines[@]}" -eq 11 ]
+    [ "${#lines[@]}" -eq 12 ]
 
     # make sure we aren't double-counting revision dbs
     run dolt sql -r csv -q 'use `db1/main`; show databases'
     [ "$status" -eq 0 ]
     [[ "$output" =~ "Database changed" ]] || false
-    [ "${#lines[@]}" -eq 12 ] # one line for above output, 11 dbs
+    [ "${#lines[@]}" -eq 13 ] # one line for above output, 12 dbs
 }
 
 @test "sql: run outside a dolt directory" {
@@ -2735,6 +2735,40 @@ SQL
     [ "$status" -eq 1 ]
 }
 
+@test "sql: --file with --transaction-size and --on-error" {
+    dolt sql -q "create table t (a int primary key)"
+    cat > script.sql <<SQL
+    insert into t values (1);
+    insert into t values (2);
+    insert into t values (2);
+    insert into t values (3);
+SQL
+
+    run dolt sql --file script.sql --on-error continue
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Processed 100.0% of the file (4 statements, 1 errors)" ]] || false
+    [[ "$output" =~ "Duplicate entry" ]] || false
+    run dolt sql -q "select count(*) from t" -r csv
+    [[ "$output" =~ "3" ]] || false
+
+    dolt sql -q "delete from t"
+    run dolt sql --file script.sql --on-error rollback
+    [ "$status" -eq 1 ]
+    run dolt sql -q "select count(*) from t" -r csv
+    [[ "$output" =~ "0" ]] || false
+
+    run dolt sql --file script.sql --on-error rollback --transaction-size 2
+    [ "$status" -eq 1 ]
+    run dolt sql -q "select a from t order by a" -r csv
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "2" ]
+    [ "${#lines[@]}" -eq 3 ]
+
+    run dolt sql --file script.sql --on-error skip
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid value for --on-error: skip" ]] || false
+}
+
 @test "sql: server with no dbs yet should be able to describe dolt stored procedures" {
     # make directories outside of the existing init'ed dolt repos
     tempDir=$(mktemp -d)
@@ -2908,3 +2942,104 @@ SQL
     dolt sql < $BATS_TEST_DIRNAME/helper/with_utf16be_bom.sql
     dolt table rm t1
 }
+
+@test "sql: explain analyze reports what each operator did" {
+    run dolt sql -r csv -q "explain analyze select * from one_pk where c1 > 10"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "plan" ]] || false
+    [[ "$output" =~ "rows=2 loops=1" ]] || false
+    [[ "$output" =~ "chunks_read=" ]] || false
+
+    run dolt sql -q "explain analyze insert into one_pk (pk) values (10)"
+    [ "$status" -ne 0 ]
+    run dolt sql -r csv -q "select count(*) from one_pk"
+    [[ "$output" =~ "4" ]] || false
+}
+
+@test "sql: hash joins spill to disk when they don't fit in the join buffer" {
+    dolt sql <<SQL
+create table big_l (id int primary key, k int);
+create table big_r (id int primary key, k int, v varchar(20));
+insert into big_l with recursive r(i) as (select 1 union all select i+1 from r where i < 2000) select i, i % 500 from r;
+insert into big_r with recursive r(i) as (select 1 union all select i+1 from r where i < 1000) select i, i % 500, concat('value ', i) from r;
+SQL
+    query="select /*+ JOIN_ORDER(big_l, big_r) HASH_JOIN(big_l, big_r) */ count(*) from big_l join big_r on big_l.k = big_r.k"
+
+    run dolt sql -r csv -q "$query"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "4000" ]
+
+    run dolt sql -r csv -q "set dolt_join_buffer_size = 4096; $query"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "4000" ]] || false
+
+    run dolt sql -r csv -q "set dolt_join_buffer_size = 4096; explain analyze $query"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "spill_files=" ]] || false
+    [[ "$output" =~ "spilled_rows=" ]] || false
+
+    run dolt sql -r csv -q "explain analyze $query"
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "spill_files=" ]] || false
+}
+
+@test "sql: window functions are evaluated incrementally with dolt_window_workers" {
+    dolt sql <<SQL
+create table readings (id int primary key, sensor int, val int);
+insert into readings with recursive r(i) as (select 1 union all select i+1 from r where i < 1000) select i, i % 4, i % 10 from r;
+SQL
+    query="select id, sum(val) over (partition by sensor order by id rows between 3 preceding and current row) as s, row_number() over (partition by sensor order by id) as rn from readings order by id limit 3 offset 996"
+
+    run dolt sql -r csv -q "$query"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "997,24,250" ]] || false
+    [[ "$output" =~ "998,18,250" ]] || false
+    [[ "$output" =~ "999,22,250" ]] || false
+
+    run dolt sql -r csv -q "set dolt_window_workers = 4; $query"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "997,24,250" ]] || false
+    [[ "$output" =~ "998,18,250" ]] || false
+    [[ "$output" =~ "999,22,250" ]] || false
+
+    run dolt sql -r csv -q "set dolt_window_workers = 4; select count(null) over () as n, count(1) over () as c from readings limit 1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "0,1000" ]] || false
+}
+
+@test "sql: dolt_optimizer_trace reports statistics and access paths" {
+    run dolt sql -r csv -q "call dolt_optimizer_trace('select * from one_pk join two_pk on one_pk.pk = two_pk.pk1')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "statistics,one_pk: rows=4" ]] || false
+    [[ "$output" =~ "statistics,two_pk: rows=4" ]] || false
+    [[ "$output" =~ "access paths,one_pk: table scan" ]] || false
+    [[ "$output" =~ "access paths,two_pk: lookup on PRIMARY" ]] || false
+    [[ "$output" =~ "(chosen)" ]] || false
+    [[ "$output" =~ "plan," ]] || false
+
+    run dolt sql -q "call dolt_optimizer_trace('select * from missing')"
+    [ "$status" -ne 0 ]
+}
+
+@test "sql: recursive CTE depth limit and CTE materialization hints" {
+    run dolt sql -q "set cte_max_recursion_depth = 10; with recursive n (i) as (select 1 union all select i + 1 from n where i < 100) select count(*) from n"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "cte_max_recursion_depth" ]] || false
+
+    run dolt sql -r csv -q "with recursive n (i) as (select 1 union all select i + 1 from n where i < 100) select /*+ NO_MERGE(n) */ count(*) from n"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "100" ]
+
+    run dolt sql -r csv -q "set cte_max_recursion_depth = 200; with recursive n (i) as (select 1 union all select i + 1 from n where i < 150) select count(*) from n"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "150" ]
+
+    # a common table expression read twice is materialized, unless it's hinted MERGE
+    run dolt sql -r csv -q "with t as (select 1 as a union all select 2) select count(*) from t t1 join t t2 on t1.a <= t2.a"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+
+    run dolt sql -r csv -q "with t as (select 1 as a union all select 2) select /*+ MERGE(t) */ count(*) from t t1 join t t2 on t1.a <= t2.a"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+}
@@ -245,6 +245,24 @@ teardown() {
     [ "${lines[1]}" = "1,0" ]
 }
 
+@test "stats: dolt_optimizer_trace reports collected histograms" {
+    cd repo2
+
+    dolt sql -q "insert into xy values (0,0), (1,0), (2,0), (3,1)"
+
+    run dolt sql -r csv -q "call dolt_optimizer_trace('select * from xy join ab on y = b')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "statistics,xy: rows=4" ]] || false
+    [[ "$output" =~ "no histogram" ]] || false
+    [[ ! "$output" =~ "histogram buckets=" ]] || false
+
+    dolt sql -q "analyze table xy"
+
+    run dolt sql -r csv -q "call dolt_optimizer_trace('select * from xy join ab on y = b')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "histogram buckets=" ]] || false
+}
+
 @test "stats: multi db" {
     cd repo1
 
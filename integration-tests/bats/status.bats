@@ -552,3 +552,22 @@ SQL
     [[ "${lines[1]}" = "Your branch is ahead of 'origin/main' by 1 commit." ]] || false
     [[ "${lines[2]}" = "  (use \"dolt push\" to publish your local commits)" ]] || false
 }
+
+@test "status: --result-format json" {
+    dolt sql -q "create table t1 (pk int primary key)"
+    dolt sql -q "create table t2 (pk int primary key)"
+    dolt add t1
+
+    run dolt status --result-format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"branch": "main"' ]] || false
+    [[ "$output" =~ '"staged": [' ]] || false
+    [[ "$output" =~ '"table": "t1"' ]] || false
+    [[ "$output" =~ '"table": "t2"' ]] || false
+    [[ "$output" =~ '"status": "new table"' ]] || false
+    [[ ! "$output" =~ "Changes to be committed" ]] || false
+
+    run dolt status -r xml
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid --result-format: xml" ]] || false
+}
@@ -0,0 +1,102 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+load $BATS_TEST_DIRNAME/helper/query-server-common.bash
+
+setup() {
+    skiponwindows "tests are flaky on Windows"
+    if [ "$SQL_ENGINE" = "remote-engine" ]; then
+      skip "This test tests remote connections directly, SQL_ENGINE is not needed."
+    fi
+    setup_common
+
+    dolt sql -q "create table t (pk int primary key, c varchar(20));"
+    dolt sql -q "insert into t values (1, 'one'), (2, 'two');"
+    dolt commit -Am "first commit"
+}
+
+teardown() {
+    stop_sql_server 1 && sleep 0.5
+    assert_feature_version
+    teardown_common
+}
+
+# corrupt_oldgen_table_file overwrites the start of the first chunk in the only table file in oldgen
+corrupt_oldgen_table_file() {
+    table_file=$(ls .dolt/noms/oldgen | grep -E '^[0-9a-v]{32}$' | head -n 1)
+    printf '\xff\xff\xff' | dd of=".dolt/noms/oldgen/$table_file" bs=1 seek=5 conv=notrunc 2>/dev/null
+    echo "$table_file"
+}
+
+# wait_for_scrub polls dolt_storage_health until the storage of the database has been scrubbed
+wait_for_scrub() {
+    for i in $(seq 1 50); do
+        run dolt sql -r csv -q "select count(*) from dolt_storage_health"
+        [ "${lines[1]}" = "0" ] || break
+        sleep 0.1
+    done
+    [ "${lines[1]}" != "0" ] || false
+}
+
+@test "storage-health: nothing is scrubbed by default" {
+    start_sql_server
+    sleep 2
+
+    run dolt sql -r csv -q "select count(*) from dolt_storage_health"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "storage-health: healthy database" {
+    dolt gc
+    dolt sql -q "set @@persist.dolt_storage_scrub_interval = 60"
+    start_sql_server
+    wait_for_scrub
+
+    run dolt sql -r csv -q "select status, table_file, chunks_checked > 0 from dolt_storage_health"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "ok,,true" ]
+}
+
+@test "storage-health: reports corrupt chunks which can't be repaired" {
+    dolt gc
+    table_file=$(corrupt_oldgen_table_file)
+    dolt sql -q "set @@persist.dolt_storage_scrub_interval = 60"
+    start_sql_server
+    wait_for_scrub
+
+    run dolt sql -r csv -q "select status, store, table_file, problem from dolt_storage_health"
+    [ "$status" -eq 0 ]
+    [[ "${lines[1]}" =~ "corrupt,oldgen,$table_file,unable to read chunk" ]] || false
+
+    # nothing was changed
+    [ -f ".dolt/noms/oldgen/$table_file" ]
+}
+
+@test "storage-health: repairs corrupt chunks from a remote" {
+    mkdir rem1
+    dolt remote add origin file://./rem1
+    dolt push origin main
+
+    dolt gc
+    table_file=$(corrupt_oldgen_table_file)
+    dolt sql -q "set @@persist.dolt_storage_scrub_interval = 60"
+    start_sql_server
+    wait_for_scrub
+
+    run dolt sql -r csv -q "select status, store, table_file from dolt_storage_health"
+    [ "$status" -eq 0 ]
+    [[ "${lines[1]}" =~ "repaired,oldgen,$table_file" ]] || false
+
+    [ ! -f ".dolt/noms/oldgen/$table_file" ]
+    [ -f ".dolt/noms/oldgen/quarantine/$table_file" ]
+
+    stop_sql_server 1
+    run dolt fsck
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "no problems found" ]] || false
+
+    run dolt sql -r csv -q "select * from t order by pk"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,one" ]
+    [ "${lines[2]}" = "2,two" ]
+}
@@ -0,0 +1,62 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "ulid: generate ULIDs" {
+    run dolt sql -r csv -q "select length(ulid()), ulid() regexp '^[0-7][0-9A-HJKMNP-TV-Z]{25}$', ulid() < ulid()"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "26,true,true" ]
+
+    # ULIDs generated in the same statement are still distinct and ordered
+    run dolt sql -r csv -q "with recursive r(n) as (select 1 union all select n + 1 from r where n < 1000) select count(distinct id), sum(prev >= id) from (select id, lag(id) over (order by n) as prev from (select n, ulid() as id from r) t) x"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1000,0" ]
+}
+
+@test "ulid: convert ULIDs to and from binary" {
+    run dolt sql -r csv -q "select hex(ulid_to_bin('01ARZ3NDEKTSV4RRFFQ69G5FAV')), bin_to_ulid(ulid_to_bin('01arz3ndektsv4rrffq69g5fav'))"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "01563E3AB5D3D6764C61EFB99302BD5B,01ARZ3NDEKTSV4RRFFQ69G5FAV" ]
+
+    run dolt sql -q "select ulid_to_bin('not a ulid')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid ULID" ]] || false
+
+    run dolt sql -q "select bin_to_ulid(0x0102)"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid binary ULID" ]] || false
+}
+
+@test "ulid: ULID and UUID primary keys merge across branches" {
+    dolt sql -q "create table u (id binary(16) primary key default (ulid_to_bin(ulid())), v int)"
+    dolt sql -q "create table w (id binary(16) primary key default (uuid_to_bin(uuid(), 1)), v int)"
+    dolt commit -Am "create tables"
+    dolt branch other
+
+    dolt sql -q "insert into u (v) values (1), (2); insert into w (v) values (1), (2)"
+    dolt commit -am "main rows"
+    dolt checkout other
+    dolt sql -q "insert into u (v) values (3); insert into w (v) values (3)"
+    dolt commit -am "other rows"
+
+    dolt checkout main
+    dolt merge other -m "merge other"
+
+    run dolt sql -r csv -q "select v from u order by id"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "2" ]
+    [ "${lines[3]}" = "3" ]
+
+    run dolt sql -r csv -q "select count(*), sum(is_uuid(bin_to_uuid(id, 1))) from w"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "3,3" ]
+}
@@ -327,3 +327,54 @@ EOF
   [[ ! $output =~ "purgeme" ]] || false
   [[ $output =~ "keepme" ]] || false
 }
+
+@test "undrop: dolt_dropped_databases lists the databases which can be undropped" {
+  setup_remote_server
+  dolt sql -q "create database dropper;"
+  dolt sql -q "create table dropper.t (pk int primary key);"
+  dolt sql -q "drop database dropper;"
+
+  run dolt sql -r csv -q "select database_name, size > 0, dropped_at is not null, purge_after is null from dolt_dropped_databases;"
+  [ $status -eq 0 ]
+  [ "${#lines[@]}" -eq 2 ]
+  [ "${lines[1]}" = "dropper,true,true,true" ]
+
+  dolt sql -q "set @@persist.dolt_dropped_database_retention_days = 7;"
+  run dolt sql -r csv -q "select purge_after = date_add(dropped_at, interval 7 day) from dolt_dropped_databases;"
+  [ $status -eq 0 ]
+  [ "${lines[1]}" = "true" ]
+
+  dolt sql -q "call dolt_undrop('dropper');"
+  run dolt sql -r csv -q "select count(*) from dolt_dropped_databases;"
+  [ $status -eq 0 ]
+  [ "${lines[1]}" = "0" ]
+  [ ! -f dropper/.dolt_dropped_at ]
+}
+
+@test "undrop: dolt gc purges expired dropped databases" {
+  if [ "$SQL_ENGINE" = "remote-engine" ]; then
+    skip "dolt gc ends the connections to a running server"
+  fi
+  dolt sql -q "create database expired;"
+  dolt sql -q "create database recent;"
+  dolt sql -q "drop database expired;"
+  dolt sql -q "drop database recent;"
+  echo "2000-01-01T00:00:00Z" > .dolt_dropped_databases/expired/.dolt_dropped_at
+
+  # dropped databases are kept until they're purged explicitly by default
+  dolt gc
+  run dolt sql -r csv -q "select database_name from dolt_dropped_databases order by database_name;"
+  [ $status -eq 0 ]
+  [ "${#lines[@]}" -eq 3 ]
+
+  dolt sql -q "set @@persist.dolt_dropped_database_retention_days = 30;"
+  dolt gc
+  run dolt sql -r csv -q "select database_name from dolt_dropped_databases;"
+  [ $status -eq 0 ]
+  [ "${#lines[@]}" -eq 2 ]
+  [ "${lines[1]}" = "recent" ]
+
+  run dolt sql -q "call dolt_undrop('expired');"
+  [ $status -eq 1 ]
+  [[ $output =~ "no database named 'expired' found to undrop" ]] || false
+}
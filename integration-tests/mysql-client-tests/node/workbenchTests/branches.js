@@ -58,6 +58,8 @@ export const branchTests = [
         latest_commit_message: "Initialize data repository",
         remote: "",
         branch: "",
+        ahead: null,
+        behind: null,
       },
       {
         name: "mybranch",
@@ -68,6 +70,8 @@ export const branchTests = [
         latest_commit_message: "Create table test",
         remote: "",
         branch: "",
+        ahead: null,
+        behind: null,
       },
     ],
     matcher: branchesMatcher,